@@ -12,6 +12,7 @@ import (
 
 	"github.com/ivankudzin/tgapp/adminpanel/backend/login/internal/config"
 	"github.com/ivankudzin/tgapp/adminpanel/backend/login/internal/httpapi"
+	"github.com/ivankudzin/tgapp/adminpanel/backend/login/internal/notify"
 	"github.com/ivankudzin/tgapp/adminpanel/backend/login/internal/repo"
 	"github.com/ivankudzin/tgapp/adminpanel/backend/login/internal/security"
 	"github.com/ivankudzin/tgapp/adminpanel/backend/login/internal/service"
@@ -34,6 +35,7 @@ func main() {
 	challenges := repo.NewChallengeRepo(pool)
 	setupTokens := repo.NewTOTPSetupTokenRepo(pool)
 	sessions := repo.NewSessionRepo(pool)
+	audit := repo.NewAdminAuditRepo(pool)
 	tokenManager := security.NewTokenManager(cfg.JWTSecret, cfg.JWTTTL)
 	secretCipher, err := security.NewSecretCipher(cfg.TOTPSecretKey)
 	if err != nil {
@@ -44,8 +46,18 @@ func main() {
 		challenges,
 		setupTokens,
 		sessions,
+		audit,
+		repo.NewPasskeyRepo(pool),
+		repo.NewRecoveryCodeRepo(pool),
+		repo.NewLoginEventRepo(pool),
 		tokenManager,
 		secretCipher,
+		security.WebAuthn{
+			RPID:    cfg.WebAuthnRPID,
+			RPName:  cfg.WebAuthnRPName,
+			Origins: cfg.WebAuthnOrigins,
+		},
+		notify.NewTelegram(cfg.TelegramBotToken),
 		cfg.TelegramBotToken,
 		cfg.TelegramAuthMaxAge,
 		cfg.ChallengeTTL,
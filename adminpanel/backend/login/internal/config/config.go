@@ -25,6 +25,10 @@ type Config struct {
 	Issuer             string
 	BootstrapKey       string
 	DevMode            bool
+	// WebAuthn passkeys are enabled when both the RP ID and at least one origin are set.
+	WebAuthnRPID    string
+	WebAuthnRPName  string
+	WebAuthnOrigins []string
 }
 
 func Load() (Config, error) {
@@ -46,7 +50,10 @@ func Load() (Config, error) {
 		Issuer:             getString("LOGIN_2FA_ISSUER", "Heartbeat Admin"),
 		BootstrapKey:       strings.TrimSpace(os.Getenv("LOGIN_BOOTSTRAP_KEY")),
 		DevMode:            getBool("LOGIN_DEV_MODE", false),
+		WebAuthnRPID:       strings.TrimSpace(os.Getenv("LOGIN_WEBAUTHN_RP_ID")),
+		WebAuthnOrigins:    getList("LOGIN_WEBAUTHN_ORIGINS"),
 	}
+	cfg.WebAuthnRPName = getString("LOGIN_WEBAUTHN_RP_NAME", cfg.Issuer)
 
 	var missing []string
 	if cfg.PostgresDSN == "" {
@@ -92,6 +99,16 @@ func getString(key, fallback string) string {
 	return v
 }
 
+func getList(key string) []string {
+	var out []string
+	for _, part := range strings.Split(os.Getenv(key), ",") {
+		if v := strings.TrimSpace(part); v != "" {
+			out = append(out, v)
+		}
+	}
+	return out
+}
+
 func getDuration(key string, fallback time.Duration) time.Duration {
 	v := strings.TrimSpace(os.Getenv(key))
 	if v == "" {
@@ -0,0 +1,201 @@
+package httpapi
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/go-chi/chi/v5"
+
+	"github.com/ivankudzin/tgapp/adminpanel/backend/login/internal/service"
+)
+
+type ownerContextKey struct{}
+
+type inviteAdminRequest struct {
+	TelegramID  int64  `json:"telegram_id"`
+	Username    string `json:"username"`
+	DisplayName string `json:"display_name"`
+	Role        string `json:"role"`
+}
+
+type setAdminRoleRequest struct {
+	Role string `json:"role"`
+}
+
+func (s *Server) registerAdminUserRoutes(r chi.Router) {
+	r.Use(s.requireOwner)
+
+	r.Get("/", s.handleListAdminUsers)
+	r.Post("/", s.handleInviteAdminUser)
+	r.Get("/{id}", s.handleGetAdminUser)
+	r.Post("/{id}/role", s.handleSetAdminRole)
+	r.Post("/{id}/deactivate", s.handleSetAdminActive(false))
+	r.Post("/{id}/reactivate", s.handleSetAdminActive(true))
+	r.Post("/{id}/password-reset", s.handleForcePasswordReset)
+	r.Post("/{id}/2fa-reset", s.handleResetAdminTOTP)
+	r.Post("/{id}/unlock", s.handleUnlockAdminUser)
+	r.Get("/{id}/sessions", s.handleListAdminSessions)
+	r.Delete("/{id}/sessions", s.handleRevokeAllAdminSessions)
+	r.Delete("/{id}/sessions/{sid}", s.handleRevokeAdminSession)
+	r.Get("/{id}/login-history", s.handleAdminLoginHistory)
+}
+
+// requireOwner admits active OWNERs only and stores their admin user id in the request context.
+func (s *Server) requireOwner(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		token := extractBearerToken(r.Header.Get("Authorization"))
+		if token == "" {
+			writeError(w, http.StatusUnauthorized, "unauthorized", "Missing Bearer token")
+			return
+		}
+		owner, err := s.svc.AuthorizeOwner(r.Context(), token)
+		if err != nil {
+			s.handleServiceError(w, err)
+			return
+		}
+		next.ServeHTTP(w, r.WithContext(context.WithValue(r.Context(), ownerContextKey{}, owner.ID)))
+	})
+}
+
+func (s *Server) handleListAdminUsers(w http.ResponseWriter, r *http.Request) {
+	users, err := s.svc.ListAdminUsers(r.Context())
+	if err != nil {
+		s.handleServiceError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, map[string]any{"items": users})
+}
+
+func (s *Server) handleInviteAdminUser(w http.ResponseWriter, r *http.Request) {
+	var req inviteAdminRequest
+	if err := decodeJSON(r, &req); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid_json", "Invalid JSON body")
+		return
+	}
+
+	result, err := s.svc.InviteAdminUser(r.Context(), ownerAuditMeta(r), service.InviteAdminInput{
+		TelegramID:  req.TelegramID,
+		Username:    req.Username,
+		DisplayName: req.DisplayName,
+		Role:        req.Role,
+	})
+	if err != nil {
+		s.handleServiceError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusCreated, result)
+}
+
+func (s *Server) handleGetAdminUser(w http.ResponseWriter, r *http.Request) {
+	userID, ok := adminUserIDParam(w, r)
+	if !ok {
+		return
+	}
+	user, err := s.svc.GetAdminUser(r.Context(), userID)
+	if err != nil {
+		s.handleServiceError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, user)
+}
+
+func (s *Server) handleSetAdminRole(w http.ResponseWriter, r *http.Request) {
+	userID, ok := adminUserIDParam(w, r)
+	if !ok {
+		return
+	}
+	var req setAdminRoleRequest
+	if err := decodeJSON(r, &req); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid_json", "Invalid JSON body")
+		return
+	}
+
+	user, err := s.svc.SetAdminUserRole(r.Context(), ownerAuditMeta(r), userID, req.Role)
+	if err != nil {
+		s.handleServiceError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, user)
+}
+
+func (s *Server) handleSetAdminActive(active bool) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		userID, ok := adminUserIDParam(w, r)
+		if !ok {
+			return
+		}
+		user, err := s.svc.SetAdminUserActive(r.Context(), ownerAuditMeta(r), userID, active)
+		if err != nil {
+			s.handleServiceError(w, err)
+			return
+		}
+		writeJSON(w, http.StatusOK, user)
+	}
+}
+
+func (s *Server) handleForcePasswordReset(w http.ResponseWriter, r *http.Request) {
+	userID, ok := adminUserIDParam(w, r)
+	if !ok {
+		return
+	}
+	result, err := s.svc.ForcePasswordReset(r.Context(), ownerAuditMeta(r), userID)
+	if err != nil {
+		s.handleServiceError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, result)
+}
+
+func (s *Server) handleResetAdminTOTP(w http.ResponseWriter, r *http.Request) {
+	userID, ok := adminUserIDParam(w, r)
+	if !ok {
+		return
+	}
+	user, err := s.svc.ResetAdminUserTOTP(r.Context(), ownerAuditMeta(r), userID)
+	if err != nil {
+		s.handleServiceError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, user)
+}
+
+func (s *Server) handleUnlockAdminUser(w http.ResponseWriter, r *http.Request) {
+	userID, ok := adminUserIDParam(w, r)
+	if !ok {
+		return
+	}
+	user, err := s.svc.UnlockAdminUser(r.Context(), ownerAuditMeta(r), userID)
+	if err != nil {
+		s.handleServiceError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, user)
+}
+
+func adminUserIDParam(w http.ResponseWriter, r *http.Request) (int64, bool) {
+	userID, err := strconv.ParseInt(strings.TrimSpace(chi.URLParam(r, "id")), 10, 64)
+	if err != nil || userID <= 0 {
+		writeError(w, http.StatusBadRequest, "invalid_input", "Invalid admin user id")
+		return 0, false
+	}
+	return userID, true
+}
+
+func ownerIDFromContext(ctx context.Context) int64 {
+	ownerID, _ := ctx.Value(ownerContextKey{}).(int64)
+	return ownerID
+}
+
+func ownerAuditMeta(r *http.Request) service.AuditMeta {
+	return auditMeta(r, ownerIDFromContext(r.Context()))
+}
+
+func auditMeta(r *http.Request, actorID int64) service.AuditMeta {
+	return service.AuditMeta{
+		ActorID:   actorID,
+		IP:        clientIP(r),
+		UserAgent: r.UserAgent(),
+	}
+}
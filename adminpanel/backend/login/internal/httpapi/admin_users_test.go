@@ -0,0 +1,200 @@
+package httpapi
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+
+	"github.com/ivankudzin/tgapp/adminpanel/backend/login/internal/repo"
+	"github.com/ivankudzin/tgapp/adminpanel/backend/login/internal/security"
+	"github.com/ivankudzin/tgapp/adminpanel/backend/login/internal/service"
+)
+
+const testBootstrapKey = "bootstrap-key"
+
+type fakeAdminUsers struct {
+	service.AdminUserStore
+	byID map[int64]repo.AdminUser
+}
+
+func (f *fakeAdminUsers) FindByID(_ context.Context, userID int64) (repo.AdminUser, error) {
+	user, ok := f.byID[userID]
+	if !ok {
+		return repo.AdminUser{}, repo.ErrNotFound
+	}
+	return user, nil
+}
+
+func (f *fakeAdminUsers) HasOwner(context.Context) (bool, error) {
+	for _, user := range f.byID {
+		if strings.EqualFold(user.Role, repo.RoleOwner) {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func (f *fakeAdminUsers) SetActive(_ context.Context, userID int64, active bool) error {
+	user, ok := f.byID[userID]
+	if !ok {
+		return repo.ErrNotFound
+	}
+	if !active && strings.EqualFold(user.Role, repo.RoleOwner) {
+		for id, other := range f.byID {
+			if id != userID && strings.EqualFold(other.Role, repo.RoleOwner) && other.IsActive {
+				user.IsActive = active
+				f.byID[userID] = user
+				return nil
+			}
+		}
+		return repo.ErrLastOwner
+	}
+	user.IsActive = active
+	f.byID[userID] = user
+	return nil
+}
+
+type fakeSessions struct {
+	service.SessionStore
+	active map[uuid.UUID]int64
+}
+
+func (f *fakeSessions) Touch(_ context.Context, sessionID uuid.UUID, adminUserID int64, _ time.Duration) error {
+	if f.active[sessionID] != adminUserID {
+		return repo.ErrNotFound
+	}
+	return nil
+}
+
+func (f *fakeSessions) Revoke(_ context.Context, sessionID uuid.UUID, adminUserID int64) error {
+	if f.active[sessionID] != adminUserID {
+		return repo.ErrNotFound
+	}
+	delete(f.active, sessionID)
+	return nil
+}
+
+type testServer struct {
+	handler http.Handler
+	users   *fakeAdminUsers
+	tokens  *security.TokenManager
+	session *fakeSessions
+}
+
+func newTestServer(users ...repo.AdminUser) *testServer {
+	ts := &testServer{
+		users:   &fakeAdminUsers{byID: make(map[int64]repo.AdminUser)},
+		tokens:  security.NewTokenManager("test-secret", time.Hour),
+		session: &fakeSessions{active: make(map[uuid.UUID]int64)},
+	}
+	for _, user := range users {
+		ts.users.byID[user.ID] = user
+	}
+	svc := service.NewService(
+		ts.users, nil, nil, ts.session, nil, nil, nil, nil,
+		ts.tokens, nil, security.WebAuthn{}, nil,
+		"", time.Minute, time.Minute, time.Minute, time.Hour, 12*time.Hour, 5, 15*time.Minute, "test", false,
+	)
+	s := &Server{svc: svc, bootstrapKey: testBootstrapKey}
+	r := chi.NewRouter()
+	s.registerRoutes(r)
+	ts.handler = r
+	return ts
+}
+
+func (ts *testServer) token(t *testing.T, userID int64) string {
+	t.Helper()
+	user := ts.users.byID[userID]
+	sessionID := uuid.New()
+	ts.session.active[sessionID] = userID
+	token, _, err := ts.tokens.Issue(user.ID, user.TelegramID, user.Role, user.Username, sessionID.String())
+	if err != nil {
+		t.Fatalf("issue token: %v", err)
+	}
+	return token
+}
+
+// sessionOf returns the session id of a token issued by the test server.
+func (ts *testServer) sessionOf(t *testing.T, token string) string {
+	t.Helper()
+	claims, err := ts.tokens.Parse(token)
+	if err != nil {
+		t.Fatalf("parse token: %v", err)
+	}
+	return claims.SID
+}
+
+func (ts *testServer) do(method, path string, header http.Header, body string) (int, string) {
+	req := httptest.NewRequest(method, path, strings.NewReader(body))
+	for key, values := range header {
+		req.Header[key] = values
+	}
+	rec := httptest.NewRecorder()
+	ts.handler.ServeHTTP(rec, req)
+
+	var payload struct {
+		Error struct {
+			Code string `json:"code"`
+		} `json:"error"`
+	}
+	_ = json.Unmarshal(rec.Body.Bytes(), &payload)
+	return rec.Code, payload.Error.Code
+}
+
+func bearer(token string) http.Header {
+	return http.Header{"Authorization": {"Bearer " + token}}
+}
+
+func testAdmin(id int64, role string) repo.AdminUser {
+	return repo.AdminUser{ID: id, TelegramID: 1000 + id, Role: role, IsActive: true, TOTPEnabled: true}
+}
+
+func TestAdminUserRoutesRequireOwner(t *testing.T) {
+	ts := newTestServer(testAdmin(1, repo.RoleOwner), testAdmin(2, "ADMIN"))
+
+	if code, errCode := ts.do(http.MethodPost, "/v1/admin/users/1/deactivate", bearer(ts.token(t, 2)), ""); code != http.StatusForbidden || errCode != "forbidden" {
+		t.Fatalf("expected 403 forbidden for a non-owner, got %d %s", code, errCode)
+	}
+	if code, _ := ts.do(http.MethodPost, "/v1/admin/users/1/deactivate", nil, ""); code != http.StatusUnauthorized {
+		t.Fatalf("expected 401 without a token, got %d", code)
+	}
+	if ts.users.byID[1].IsActive != true {
+		t.Fatalf("a refused request must not change the owner")
+	}
+}
+
+func TestLastOwnerDeactivationConflicts(t *testing.T) {
+	ts := newTestServer(testAdmin(1, repo.RoleOwner))
+
+	if code, errCode := ts.do(http.MethodPost, "/v1/admin/users/1/deactivate", bearer(ts.token(t, 1)), ""); code != http.StatusConflict || errCode != "last_owner" {
+		t.Fatalf("expected 409 last_owner, got %d %s", code, errCode)
+	}
+}
+
+func TestBootstrapKeyClosesOnceOwnerExists(t *testing.T) {
+	ts := newTestServer(testAdmin(2, "ADMIN"))
+	withKey := func(key string) http.Header { return http.Header{"X-Bootstrap-Key": {key}} }
+
+	if code, errCode := ts.do(http.MethodPost, "/v1/admin/2fa/setup/start", withKey("wrong"), "{}"); code != http.StatusForbidden || errCode != "forbidden" {
+		t.Fatalf("expected 403 for a wrong key, got %d %s", code, errCode)
+	}
+	// The key passes the gate while there is no owner: the handler itself rejects the body.
+	if code, errCode := ts.do(http.MethodPost, "/v1/admin/2fa/setup/start", withKey(testBootstrapKey), "not json"); code != http.StatusBadRequest || errCode != "invalid_json" {
+		t.Fatalf("expected the bootstrap key to be accepted, got %d %s", code, errCode)
+	}
+
+	dormant := testAdmin(1, repo.RoleOwner)
+	dormant.IsActive = false
+	dormant.TOTPEnabled = false
+	ts.users.byID[1] = dormant
+	if code, errCode := ts.do(http.MethodPost, "/v1/admin/2fa/setup/start", withKey(testBootstrapKey), "not json"); code != http.StatusForbidden || errCode != "bootstrap_closed" {
+		t.Fatalf("expected 403 bootstrap_closed once an owner exists, got %d %s", code, errCode)
+	}
+}
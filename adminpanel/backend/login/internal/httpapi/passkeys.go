@@ -0,0 +1,146 @@
+package httpapi
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/go-chi/chi/v5"
+
+	"github.com/ivankudzin/tgapp/adminpanel/backend/login/internal/service"
+)
+
+type adminContextKey struct{}
+
+type adminPrincipal struct {
+	ID        int64
+	SessionID string
+}
+
+type passkeyLoginOptionsRequest struct {
+	ChallengeID string `json:"challenge_id"`
+}
+
+type passkeyLoginVerifyRequest struct {
+	ChallengeID string                    `json:"challenge_id"`
+	Credential  service.PasskeyCredential `json:"credential"`
+}
+
+type finishPasskeyRegistrationRequest struct {
+	RegistrationID string                    `json:"registration_id"`
+	Name           string                    `json:"name"`
+	Credential     service.PasskeyCredential `json:"credential"`
+}
+
+func (s *Server) registerPasskeyRoutes(r chi.Router) {
+	r.Use(s.requireAdmin)
+
+	r.Get("/", s.handleListPasskeys)
+	r.Post("/register/start", s.handleStartPasskeyRegistration)
+	r.Post("/register/finish", s.handleFinishPasskeyRegistration)
+	r.Delete("/{id}", s.handleDeletePasskey)
+}
+
+// requireAdmin admits any active admin and stores their admin user id and session id in the request context.
+func (s *Server) requireAdmin(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		token := extractBearerToken(r.Header.Get("Authorization"))
+		if token == "" {
+			writeError(w, http.StatusUnauthorized, "unauthorized", "Missing Bearer token")
+			return
+		}
+		principal, err := s.svc.AuthorizeAdmin(r.Context(), token)
+		if err != nil {
+			s.handleServiceError(w, err)
+			return
+		}
+		admin := adminPrincipal{ID: principal.User.ID, SessionID: principal.SessionID}
+		next.ServeHTTP(w, r.WithContext(context.WithValue(r.Context(), adminContextKey{}, admin)))
+	})
+}
+
+func (s *Server) handlePasskeyLoginOptions(w http.ResponseWriter, r *http.Request) {
+	var req passkeyLoginOptionsRequest
+	if err := decodeJSON(r, &req); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid_json", "Invalid JSON body")
+		return
+	}
+	options, err := s.svc.StartPasskeyLogin(r.Context(), req.ChallengeID)
+	if err != nil {
+		s.handleServiceError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, map[string]any{
+		"challenge_id": req.ChallengeID,
+		"public_key":   options,
+	})
+}
+
+func (s *Server) handlePasskeyLoginVerify(w http.ResponseWriter, r *http.Request) {
+	var req passkeyLoginVerifyRequest
+	if err := decodeJSON(r, &req); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid_json", "Invalid JSON body")
+		return
+	}
+	result, err := s.svc.VerifyPasskeyLogin(r.Context(), req.ChallengeID, req.Credential, auditMeta(r, 0))
+	if err != nil {
+		s.handleServiceError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, result)
+}
+
+func (s *Server) handleListPasskeys(w http.ResponseWriter, r *http.Request) {
+	passkeys, err := s.svc.ListPasskeys(r.Context(), adminIDFromContext(r.Context()))
+	if err != nil {
+		s.handleServiceError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, map[string]any{"items": passkeys})
+}
+
+func (s *Server) handleStartPasskeyRegistration(w http.ResponseWriter, r *http.Request) {
+	result, err := s.svc.StartPasskeyRegistration(r.Context(), adminIDFromContext(r.Context()))
+	if err != nil {
+		s.handleServiceError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, result)
+}
+
+func (s *Server) handleFinishPasskeyRegistration(w http.ResponseWriter, r *http.Request) {
+	var req finishPasskeyRegistrationRequest
+	if err := decodeJSON(r, &req); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid_json", "Invalid JSON body")
+		return
+	}
+	passkey, err := s.svc.FinishPasskeyRegistration(r.Context(), auditMeta(r, adminIDFromContext(r.Context())), req.RegistrationID, req.Name, req.Credential)
+	if err != nil {
+		s.handleServiceError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusCreated, passkey)
+}
+
+func (s *Server) handleDeletePasskey(w http.ResponseWriter, r *http.Request) {
+	passkeyID, err := strconv.ParseInt(strings.TrimSpace(chi.URLParam(r, "id")), 10, 64)
+	if err != nil || passkeyID <= 0 {
+		writeError(w, http.StatusBadRequest, "invalid_input", "Invalid passkey id")
+		return
+	}
+	if err := s.svc.DeletePasskey(r.Context(), auditMeta(r, adminIDFromContext(r.Context())), passkeyID); err != nil {
+		s.handleServiceError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, map[string]any{"status": "ok"})
+}
+
+func adminIDFromContext(ctx context.Context) int64 {
+	return adminFromContext(ctx).ID
+}
+
+func adminFromContext(ctx context.Context) adminPrincipal {
+	admin, _ := ctx.Value(adminContextKey{}).(adminPrincipal)
+	return admin
+}
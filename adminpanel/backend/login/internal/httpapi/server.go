@@ -2,6 +2,7 @@ package httpapi
 
 import (
 	"context"
+	"crypto/subtle"
 	"encoding/json"
 	"errors"
 	"fmt"
@@ -60,8 +61,17 @@ func (s *Server) registerRoutes(r chi.Router) {
 		r.Get("/auth/me", s.handleMe)
 		r.Post("/auth/logout", s.handleLogout)
 
-		r.Post("/admin/2fa/setup/start", s.withBootstrapKey(s.handleStartTOTPSetup))
-		r.Post("/admin/2fa/setup/confirm", s.withBootstrapKey(s.handleConfirmTOTPSetup))
+		r.Post("/admin/2fa/setup/start", s.withOwnerOrBootstrapKey(s.handleStartTOTPSetup))
+		r.Post("/admin/2fa/setup/confirm", s.withOwnerOrBootstrapKey(s.handleConfirmTOTPSetup))
+
+		r.Post("/auth/passkey/options", s.handlePasskeyLoginOptions)
+		r.Post("/auth/passkey/verify", s.handlePasskeyLoginVerify)
+		r.Route("/auth/passkeys", s.registerPasskeyRoutes)
+		r.Group(s.registerSelfServiceTOTPRoutes)
+		r.Route("/auth/sessions", s.registerSessionRoutes)
+		r.With(s.requireAdmin).Get("/auth/login-history", s.handleOwnLoginHistory)
+
+		r.Route("/admin/users", s.registerAdminUserRoutes)
 	})
 }
 
@@ -77,6 +87,7 @@ type verify2FARequest struct {
 type verifyPasswordRequest struct {
 	ChallengeID string `json:"challenge_id"`
 	Password    string `json:"password"`
+	NewPassword string `json:"new_password,omitempty"`
 }
 
 type startTOTPSetupRequest struct {
@@ -114,7 +125,7 @@ func (s *Server) handle2FAVerify(w http.ResponseWriter, r *http.Request) {
 		writeError(w, http.StatusBadRequest, "invalid_json", "Invalid JSON body")
 		return
 	}
-	result, err := s.svc.VerifyTOTP(r.Context(), req.ChallengeID, req.Code)
+	result, err := s.svc.VerifyTOTP(r.Context(), req.ChallengeID, req.Code, auditMeta(r, 0))
 	if err != nil {
 		s.handleServiceError(w, err)
 		return
@@ -128,7 +139,7 @@ func (s *Server) handlePasswordVerify(w http.ResponseWriter, r *http.Request) {
 		writeError(w, http.StatusBadRequest, "invalid_json", "Invalid JSON body")
 		return
 	}
-	result, err := s.svc.VerifyPassword(r.Context(), req.ChallengeID, req.Password)
+	result, err := s.svc.VerifyPassword(r.Context(), req.ChallengeID, req.Password, req.NewPassword, auditMeta(r, 0))
 	if err != nil {
 		s.handleServiceError(w, err)
 		return
@@ -158,11 +169,15 @@ func (s *Server) handleConfirmTOTPSetup(w http.ResponseWriter, r *http.Request)
 		return
 	}
 
-	if err := s.svc.ConfirmTOTPSetup(r.Context(), req.SetupID, req.Code); err != nil {
+	result, err := s.svc.ConfirmTOTPSetup(r.Context(), req.SetupID, req.Code, auditMeta(r, ownerIDFromContext(r.Context())))
+	if err != nil {
 		s.handleServiceError(w, err)
 		return
 	}
-	writeJSON(w, http.StatusOK, map[string]any{"status": "ok"})
+	writeJSON(w, http.StatusOK, map[string]any{
+		"status":         "ok",
+		"recovery_codes": result.RecoveryCodes,
+	})
 }
 
 func (s *Server) handleMe(w http.ResponseWriter, r *http.Request) {
@@ -197,17 +212,32 @@ func (s *Server) handleLogout(w http.ResponseWriter, r *http.Request) {
 	writeJSON(w, http.StatusOK, map[string]any{"status": "ok"})
 }
 
-func (s *Server) withBootstrapKey(next http.HandlerFunc) http.HandlerFunc {
+// withOwnerOrBootstrapKey lets an OWNER in with a Bearer token; the bootstrap key is accepted only
+// while no OWNER exists.
+func (s *Server) withOwnerOrBootstrapKey(next http.HandlerFunc) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
+		if extractBearerToken(r.Header.Get("Authorization")) != "" {
+			s.requireOwner(next).ServeHTTP(w, r)
+			return
+		}
 		if strings.TrimSpace(s.bootstrapKey) == "" {
 			writeError(w, http.StatusNotImplemented, "bootstrap_disabled", "Bootstrap key is not configured")
 			return
 		}
 		provided := strings.TrimSpace(r.Header.Get("X-Bootstrap-Key"))
-		if provided == "" || provided != s.bootstrapKey {
+		if provided == "" || subtle.ConstantTimeCompare([]byte(provided), []byte(s.bootstrapKey)) != 1 {
 			writeError(w, http.StatusForbidden, "forbidden", "Invalid bootstrap key")
 			return
 		}
+		allowed, err := s.svc.BootstrapAllowed(r.Context())
+		if err != nil {
+			s.handleServiceError(w, err)
+			return
+		}
+		if !allowed {
+			writeError(w, http.StatusForbidden, "bootstrap_closed", "Bootstrap key is disabled once an owner exists")
+			return
+		}
 		next(w, r)
 	}
 }
@@ -230,6 +260,24 @@ func (s *Server) handleServiceError(w http.ResponseWriter, err error) {
 		writeError(w, http.StatusPreconditionRequired, "totp_not_configured", "2FA is not configured")
 	case errors.Is(err, service.ErrSessionExpired):
 		writeError(w, http.StatusUnauthorized, "session_expired", "Session expired. Sign in again")
+	case errors.Is(err, service.ErrPasswordResetRequired):
+		writeError(w, http.StatusPreconditionRequired, "password_reset_required", "Set a new password to continue")
+	case errors.Is(err, service.ErrNotFound):
+		writeError(w, http.StatusNotFound, "not_found", "Admin user not found")
+	case errors.Is(err, service.ErrConflict):
+		writeError(w, http.StatusConflict, "already_exists", "Admin user already exists")
+	case errors.Is(err, service.ErrLastOwner):
+		writeError(w, http.StatusConflict, "last_owner", "The last active owner cannot be demoted or deactivated")
+	case errors.Is(err, service.ErrSessionNotFound):
+		writeError(w, http.StatusNotFound, "session_not_found", "Session not found")
+	case errors.Is(err, service.ErrInvalidCode):
+		writeError(w, http.StatusUnprocessableEntity, "invalid_code", "Invalid 2FA code")
+	case errors.Is(err, service.ErrPasskeysDisabled):
+		writeError(w, http.StatusNotImplemented, "passkeys_disabled", "Passkeys are not configured")
+	case errors.Is(err, service.ErrPasskeyNotFound):
+		writeError(w, http.StatusNotFound, "passkey_not_found", "Passkey not found")
+	case errors.Is(err, service.ErrLastSecondFactor):
+		writeError(w, http.StatusConflict, "last_second_factor", "Enable 2FA or add another passkey before removing this one")
 	default:
 		writeError(w, http.StatusInternalServerError, "internal_error", "Internal server error")
 	}
@@ -276,7 +324,7 @@ func clientIP(r *http.Request) string {
 		parts := strings.Split(xff, ",")
 		if len(parts) > 0 {
 			candidate := strings.TrimSpace(parts[0])
-			if candidate != "" {
+			if net.ParseIP(candidate) != nil {
 				return candidate
 			}
 		}
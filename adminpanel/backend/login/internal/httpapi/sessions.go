@@ -0,0 +1,111 @@
+package httpapi
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/go-chi/chi/v5"
+)
+
+func (s *Server) registerSessionRoutes(r chi.Router) {
+	r.Use(s.requireAdmin)
+
+	r.Get("/", s.handleListOwnSessions)
+	r.Delete("/{sid}", s.handleRevokeOwnSession)
+}
+
+func (s *Server) handleListOwnSessions(w http.ResponseWriter, r *http.Request) {
+	admin := adminFromContext(r.Context())
+	sessions, err := s.svc.ListSessions(r.Context(), admin.ID, admin.SessionID)
+	if err != nil {
+		s.handleServiceError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, map[string]any{"items": sessions})
+}
+
+func (s *Server) handleRevokeOwnSession(w http.ResponseWriter, r *http.Request) {
+	admin := adminFromContext(r.Context())
+	if err := s.svc.RevokeSession(r.Context(), auditMeta(r, admin.ID), admin.ID, chi.URLParam(r, "sid")); err != nil {
+		s.handleServiceError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, map[string]any{"status": "ok"})
+}
+
+func (s *Server) handleOwnLoginHistory(w http.ResponseWriter, r *http.Request) {
+	events, err := s.svc.LoginHistory(r.Context(), adminIDFromContext(r.Context()), limitParam(r))
+	if err != nil {
+		s.handleServiceError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, map[string]any{"items": events})
+}
+
+func (s *Server) handleListAdminSessions(w http.ResponseWriter, r *http.Request) {
+	userID, ok := adminUserIDParam(w, r)
+	if !ok {
+		return
+	}
+	if _, err := s.svc.GetAdminUser(r.Context(), userID); err != nil {
+		s.handleServiceError(w, err)
+		return
+	}
+	sessions, err := s.svc.ListSessions(r.Context(), userID, "")
+	if err != nil {
+		s.handleServiceError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, map[string]any{"items": sessions})
+}
+
+func (s *Server) handleRevokeAdminSession(w http.ResponseWriter, r *http.Request) {
+	userID, ok := adminUserIDParam(w, r)
+	if !ok {
+		return
+	}
+	if err := s.svc.RevokeSession(r.Context(), ownerAuditMeta(r), userID, chi.URLParam(r, "sid")); err != nil {
+		s.handleServiceError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, map[string]any{"status": "ok"})
+}
+
+func (s *Server) handleRevokeAllAdminSessions(w http.ResponseWriter, r *http.Request) {
+	userID, ok := adminUserIDParam(w, r)
+	if !ok {
+		return
+	}
+	if err := s.svc.RevokeAllSessions(r.Context(), ownerAuditMeta(r), userID); err != nil {
+		s.handleServiceError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, map[string]any{"status": "ok"})
+}
+
+func (s *Server) handleAdminLoginHistory(w http.ResponseWriter, r *http.Request) {
+	userID, ok := adminUserIDParam(w, r)
+	if !ok {
+		return
+	}
+	if _, err := s.svc.GetAdminUser(r.Context(), userID); err != nil {
+		s.handleServiceError(w, err)
+		return
+	}
+	events, err := s.svc.LoginHistory(r.Context(), userID, limitParam(r))
+	if err != nil {
+		s.handleServiceError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, map[string]any{"items": events})
+}
+
+// limitParam reads ?limit=; the service clamps it, so a bad value just falls back to the default.
+func limitParam(r *http.Request) int {
+	limit, err := strconv.Atoi(strings.TrimSpace(r.URL.Query().Get("limit")))
+	if err != nil {
+		return 0
+	}
+	return limit
+}
@@ -0,0 +1,30 @@
+package httpapi
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/ivankudzin/tgapp/adminpanel/backend/login/internal/repo"
+)
+
+func TestRevokeOwnSessionRoute(t *testing.T) {
+	ts := newTestServer(testAdmin(1, repo.RoleOwner), testAdmin(2, "ADMIN"), testAdmin(3, "ADMIN"))
+	token := ts.token(t, 2)
+	spare := ts.token(t, 2)
+	other := ts.token(t, 3)
+
+	path := "/v1/auth/sessions/" + ts.sessionOf(t, other)
+	if code, errCode := ts.do(http.MethodDelete, path, bearer(token), ""); code != http.StatusNotFound || errCode != "session_not_found" {
+		t.Fatalf("expected 404 for another admin's session, got %d %s", code, errCode)
+	}
+	if code, _ := ts.do(http.MethodGet, "/v1/auth/me", bearer(other), ""); code != http.StatusOK {
+		t.Fatalf("another admin's session must stay valid, got %d", code)
+	}
+
+	if code, _ := ts.do(http.MethodDelete, "/v1/auth/sessions/"+ts.sessionOf(t, spare), bearer(token), ""); code != http.StatusOK {
+		t.Fatalf("expected own session to be revoked, got %d", code)
+	}
+	if code, errCode := ts.do(http.MethodGet, "/v1/auth/me", bearer(spare), ""); code != http.StatusUnauthorized || errCode != "session_expired" {
+		t.Fatalf("expected the revoked token to stop working, got %d %s", code, errCode)
+	}
+}
@@ -0,0 +1,71 @@
+package httpapi
+
+import (
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+)
+
+type totpCodeRequest struct {
+	Code string `json:"code"`
+}
+
+func (s *Server) registerSelfServiceTOTPRoutes(r chi.Router) {
+	r.Use(s.requireAdmin)
+
+	r.Get("/auth/2fa/recovery-codes", s.handleRecoveryCodesStatus)
+	r.Post("/auth/2fa/recovery-codes/regenerate", s.handleRegenerateRecoveryCodes)
+	r.Post("/auth/2fa/rotate/start", s.handleStartTOTPRotation)
+	r.Post("/auth/2fa/rotate/confirm", s.handleConfirmTOTPRotation)
+}
+
+func (s *Server) handleRecoveryCodesStatus(w http.ResponseWriter, r *http.Request) {
+	status, err := s.svc.RecoveryCodesStatus(r.Context(), adminIDFromContext(r.Context()))
+	if err != nil {
+		s.handleServiceError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, status)
+}
+
+func (s *Server) handleRegenerateRecoveryCodes(w http.ResponseWriter, r *http.Request) {
+	var req totpCodeRequest
+	if err := decodeJSON(r, &req); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid_json", "Invalid JSON body")
+		return
+	}
+	result, err := s.svc.RegenerateRecoveryCodes(r.Context(), auditMeta(r, adminIDFromContext(r.Context())), req.Code)
+	if err != nil {
+		s.handleServiceError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, result)
+}
+
+func (s *Server) handleStartTOTPRotation(w http.ResponseWriter, r *http.Request) {
+	var req totpCodeRequest
+	if err := decodeJSON(r, &req); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid_json", "Invalid JSON body")
+		return
+	}
+	result, err := s.svc.StartTOTPRotation(r.Context(), auditMeta(r, adminIDFromContext(r.Context())), req.Code)
+	if err != nil {
+		s.handleServiceError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, result)
+}
+
+func (s *Server) handleConfirmTOTPRotation(w http.ResponseWriter, r *http.Request) {
+	var req confirmTOTPSetupRequest
+	if err := decodeJSON(r, &req); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid_json", "Invalid JSON body")
+		return
+	}
+	result, err := s.svc.ConfirmTOTPRotation(r.Context(), auditMeta(r, adminIDFromContext(r.Context())), req.SetupID, req.Code)
+	if err != nil {
+		s.handleServiceError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, result)
+}
@@ -0,0 +1,72 @@
+package notify
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"strings"
+	"time"
+)
+
+const telegramAPIBase = "https://api.telegram.org"
+
+// Telegram sends security alerts to admins through the login bot. Admins receive them in the private chat
+// with the bot, so the chat id is their Telegram user id.
+type Telegram struct {
+	botToken string
+	client   *http.Client
+}
+
+func NewTelegram(botToken string) *Telegram {
+	return &Telegram{
+		botToken: strings.TrimSpace(botToken),
+		client:   &http.Client{Timeout: 10 * time.Second},
+	}
+}
+
+func (t *Telegram) Send(ctx context.Context, chatID int64, text string) error {
+	if t == nil || t.botToken == "" {
+		log.Printf("telegram alert skipped (no bot token): chat_id=%d text=%q", chatID, text)
+		return nil
+	}
+
+	payload, err := json.Marshal(map[string]any{
+		"chat_id":                  chatID,
+		"text":                     text,
+		"disable_web_page_preview": true,
+	})
+	if err != nil {
+		return fmt.Errorf("marshal telegram message: %w", err)
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, telegramAPIBase+"/bot"+t.botToken+"/sendMessage", bytes.NewReader(payload))
+	if err != nil {
+		return fmt.Errorf("build telegram request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := t.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("send telegram message: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return fmt.Errorf("send telegram message: status %d: %s", resp.StatusCode, strings.TrimSpace(string(body)))
+	}
+	return nil
+}
+
+// SendAsync delivers an alert without holding up the request that triggered it.
+func (t *Telegram) SendAsync(chatID int64, text string) {
+	go func() {
+		ctx, cancel := context.WithTimeout(context.Background(), 15*time.Second)
+		defer cancel()
+		if err := t.Send(ctx, chatID, text); err != nil {
+			log.Printf("telegram alert failed: chat_id=%d: %v", chatID, err)
+		}
+	}()
+}
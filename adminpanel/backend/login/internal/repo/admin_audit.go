@@ -0,0 +1,46 @@
+package repo
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+type AdminAuditEntry struct {
+	// ActorID is 0 for bootstrap-key requests made before any OWNER existed.
+	ActorID   int64
+	TargetID  int64
+	Action    string
+	Details   map[string]any
+	IP        string
+	UserAgent string
+}
+
+type AdminAuditRepo struct {
+	pool *pgxpool.Pool
+}
+
+func NewAdminAuditRepo(pool *pgxpool.Pool) *AdminAuditRepo {
+	return &AdminAuditRepo{pool: pool}
+}
+
+func (r *AdminAuditRepo) Insert(ctx context.Context, entry AdminAuditEntry) error {
+	const query = `
+INSERT INTO admin_user_audit (actor_admin_user_id, target_admin_user_id, action, details, ip_address, user_agent)
+VALUES (NULLIF($1, 0), NULLIF($2, 0), $3, $4, NULLIF($5, ''), NULLIF($6, ''))
+`
+	details := entry.Details
+	if details == nil {
+		details = map[string]any{}
+	}
+	payload, err := json.Marshal(details)
+	if err != nil {
+		return fmt.Errorf("marshal admin audit details: %w", err)
+	}
+	if _, err := r.pool.Exec(ctx, query, entry.ActorID, entry.TargetID, entry.Action, payload, entry.IP, entry.UserAgent); err != nil {
+		return fmt.Errorf("insert admin audit: %w", err)
+	}
+	return nil
+}
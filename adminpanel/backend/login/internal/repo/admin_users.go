@@ -7,10 +7,35 @@ import (
 	"time"
 
 	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
 	"github.com/jackc/pgx/v5/pgxpool"
 )
 
-var ErrNotFound = errors.New("not found")
+var (
+	ErrNotFound  = errors.New("not found")
+	ErrConflict  = errors.New("already exists")
+	ErrLastOwner = errors.New("last active owner")
+)
+
+const RoleOwner = "OWNER"
+
+const adminUserColumns = `
+id, telegram_id, COALESCE(username, ''), COALESCE(display_name, ''), role,
+       password_hash, COALESCE(totp_secret, ''), totp_enabled, is_active,
+       failed_login_attempts, locked_until, password_reset_required, created_at, updated_at
+`
+
+// keepsActiveOwner guards role and status changes of user $1 so at least one active OWNER remains.
+const keepsActiveOwner = `
+  AND (
+      UPPER(role) <> 'OWNER'
+      OR NOT is_active
+      OR EXISTS (
+          SELECT 1 FROM admin_users AS o
+          WHERE o.id <> $1 AND UPPER(o.role) = 'OWNER' AND o.is_active
+      )
+  )
+`
 
 type AdminUser struct {
 	ID             int64
@@ -24,6 +49,10 @@ type AdminUser struct {
 	IsActive       bool
 	FailedAttempts int
 	LockedUntil    *time.Time
+
+	PasswordResetRequired bool
+	CreatedAt             time.Time
+	UpdatedAt             time.Time
 }
 
 type AdminUserRepo struct {
@@ -35,10 +64,7 @@ func NewAdminUserRepo(pool *pgxpool.Pool) *AdminUserRepo {
 }
 
 func (r *AdminUserRepo) FindByTelegramID(ctx context.Context, telegramID int64) (AdminUser, error) {
-	const query = `
-SELECT id, telegram_id, COALESCE(username, ''), COALESCE(display_name, ''), role,
-       password_hash, COALESCE(totp_secret, ''), totp_enabled, is_active,
-       failed_login_attempts, locked_until
+	query := `SELECT ` + adminUserColumns + `
 FROM admin_users
 WHERE telegram_id = $1
 `
@@ -46,20 +72,192 @@ WHERE telegram_id = $1
 }
 
 func (r *AdminUserRepo) FindByID(ctx context.Context, userID int64) (AdminUser, error) {
-	const query = `
-SELECT id, telegram_id, COALESCE(username, ''), COALESCE(display_name, ''), role,
-       password_hash, COALESCE(totp_secret, ''), totp_enabled, is_active,
-       failed_login_attempts, locked_until
+	query := `SELECT ` + adminUserColumns + `
 FROM admin_users
 WHERE id = $1
 `
 	return r.scanOne(ctx, query, userID)
 }
 
+func (r *AdminUserRepo) List(ctx context.Context) ([]AdminUser, error) {
+	query := `SELECT ` + adminUserColumns + `
+FROM admin_users
+ORDER BY is_active DESC, id ASC
+`
+	rows, err := r.pool.Query(ctx, query)
+	if err != nil {
+		return nil, fmt.Errorf("list admin users: %w", err)
+	}
+	defer rows.Close()
+
+	var users []AdminUser
+	for rows.Next() {
+		user, err := scanAdminUser(rows)
+		if err != nil {
+			return nil, fmt.Errorf("scan admin user: %w", err)
+		}
+		users = append(users, user)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterate admin users: %w", err)
+	}
+	return users, nil
+}
+
+// HasOwner reports whether any OWNER row exists, active or not, with or without 2FA.
+func (r *AdminUserRepo) HasOwner(ctx context.Context) (bool, error) {
+	const query = `SELECT EXISTS (SELECT 1 FROM admin_users WHERE UPPER(role) = 'OWNER')`
+	var exists bool
+	if err := r.pool.QueryRow(ctx, query).Scan(&exists); err != nil {
+		return false, fmt.Errorf("check owner exists: %w", err)
+	}
+	return exists, nil
+}
+
+func (r *AdminUserRepo) RoleExists(ctx context.Context, role string) (bool, error) {
+	const query = `SELECT EXISTS (SELECT 1 FROM admin_roles WHERE name = $1)`
+	var exists bool
+	if err := r.pool.QueryRow(ctx, query, role).Scan(&exists); err != nil {
+		return false, fmt.Errorf("check admin role exists: %w", err)
+	}
+	return exists, nil
+}
+
+// Create inserts an invited admin who must replace passwordHash on first sign-in.
+func (r *AdminUserRepo) Create(ctx context.Context, user AdminUser, invitedBy int64) (AdminUser, error) {
+	query := `
+INSERT INTO admin_users (telegram_id, username, display_name, role, password_hash, password_reset_required, invited_by)
+VALUES ($1, NULLIF($2, ''), NULLIF($3, ''), $4, $5, TRUE, NULLIF($6, 0))
+RETURNING ` + adminUserColumns
+	created, err := scanAdminUser(r.pool.QueryRow(ctx, query,
+		user.TelegramID,
+		user.Username,
+		user.DisplayName,
+		user.Role,
+		user.PasswordHash,
+		invitedBy,
+	))
+	if err != nil {
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) && pgErr.Code == "23505" {
+			return AdminUser{}, ErrConflict
+		}
+		return AdminUser{}, fmt.Errorf("create admin user: %w", err)
+	}
+	return created, nil
+}
+
+func (r *AdminUserRepo) SetRole(ctx context.Context, userID int64, role string) error {
+	query := `
+UPDATE admin_users
+SET role = $2,
+    updated_at = NOW()
+WHERE id = $1
+` + keepsActiveOwner + `
+  OR (id = $1 AND UPPER($2) = 'OWNER')
+`
+	return r.execGuarded(ctx, "set admin role", query, userID, role)
+}
+
+func (r *AdminUserRepo) SetActive(ctx context.Context, userID int64, active bool) error {
+	query := `
+UPDATE admin_users
+SET is_active = $2,
+    updated_at = NOW()
+WHERE id = $1
+` + keepsActiveOwner + `
+  OR (id = $1 AND $2)
+`
+	return r.execGuarded(ctx, "set admin active", query, userID, active)
+}
+
+// RequirePasswordReset replaces the password with a temporary one that must be changed on next sign-in.
+func (r *AdminUserRepo) RequirePasswordReset(ctx context.Context, userID int64, passwordHash string) error {
+	const query = `
+UPDATE admin_users
+SET password_hash = $2,
+    password_reset_required = TRUE,
+    updated_at = NOW()
+WHERE id = $1
+`
+	return r.exec(ctx, "require password reset", query, userID, passwordHash)
+}
+
+func (r *AdminUserRepo) SetPassword(ctx context.Context, userID int64, passwordHash string) error {
+	const query = `
+UPDATE admin_users
+SET password_hash = $2,
+    password_reset_required = FALSE,
+    updated_at = NOW()
+WHERE id = $1
+`
+	return r.exec(ctx, "set password", query, userID, passwordHash)
+}
+
+func (r *AdminUserRepo) DisableTOTP(ctx context.Context, userID int64) error {
+	const query = `
+UPDATE admin_users
+SET totp_secret = NULL,
+    totp_enabled = FALSE,
+    updated_at = NOW()
+WHERE id = $1
+`
+	return r.exec(ctx, "disable totp", query, userID)
+}
+
+func (r *AdminUserRepo) exec(ctx context.Context, op string, query string, args ...any) error {
+	res, err := r.pool.Exec(ctx, query, args...)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	if res.RowsAffected() == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+// execGuarded runs a keepsActiveOwner update; no affected row means either a missing user or the last owner.
+// The active OWNER rows are locked first so concurrent demotions are serialized and each one re-checks the guard.
+func (r *AdminUserRepo) execGuarded(ctx context.Context, op string, query string, userID int64, arg any) error {
+	tx, err := r.pool.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("%s: begin: %w", op, err)
+	}
+	defer func() { _ = tx.Rollback(ctx) }()
+
+	if _, err := tx.Exec(ctx, `SELECT id FROM admin_users WHERE UPPER(role) = 'OWNER' AND is_active FOR UPDATE`); err != nil {
+		return fmt.Errorf("%s: lock owners: %w", op, err)
+	}
+	res, err := tx.Exec(ctx, query, userID, arg)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	if res.RowsAffected() == 0 {
+		if _, findErr := r.FindByID(ctx, userID); findErr != nil {
+			return findErr
+		}
+		return ErrLastOwner
+	}
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("%s: commit: %w", op, err)
+	}
+	return nil
+}
+
 func (r *AdminUserRepo) scanOne(ctx context.Context, query string, arg any) (AdminUser, error) {
+	user, err := scanAdminUser(r.pool.QueryRow(ctx, query, arg))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return AdminUser{}, ErrNotFound
+		}
+		return AdminUser{}, fmt.Errorf("query admin user: %w", err)
+	}
+	return user, nil
+}
+
+func scanAdminUser(row pgx.Row) (AdminUser, error) {
 	var user AdminUser
-	var lockedUntil *time.Time
-	err := r.pool.QueryRow(ctx, query, arg).Scan(
+	err := row.Scan(
 		&user.ID,
 		&user.TelegramID,
 		&user.Username,
@@ -70,16 +268,12 @@ func (r *AdminUserRepo) scanOne(ctx context.Context, query string, arg any) (Adm
 		&user.TOTPEnabled,
 		&user.IsActive,
 		&user.FailedAttempts,
-		&lockedUntil,
+		&user.LockedUntil,
+		&user.PasswordResetRequired,
+		&user.CreatedAt,
+		&user.UpdatedAt,
 	)
-	if err != nil {
-		if errors.Is(err, pgx.ErrNoRows) {
-			return AdminUser{}, ErrNotFound
-		}
-		return AdminUser{}, fmt.Errorf("query admin user: %w", err)
-	}
-	user.LockedUntil = lockedUntil
-	return user, nil
+	return user, err
 }
 
 func (r *AdminUserRepo) ResetFailures(ctx context.Context, userID int64) error {
@@ -97,7 +291,8 @@ WHERE id = $1
 	return nil
 }
 
-func (r *AdminUserRepo) MarkFailure(ctx context.Context, userID int64, maxAttempts int, lockUntil time.Time) (bool, error) {
+// MarkFailure counts a failed login step and returns the new attempt count and whether the account is now locked.
+func (r *AdminUserRepo) MarkFailure(ctx context.Context, userID int64, maxAttempts int, lockUntil time.Time) (int, bool, error) {
 	const query = `
 UPDATE admin_users
 SET failed_login_attempts = failed_login_attempts + 1,
@@ -107,14 +302,15 @@ SET failed_login_attempts = failed_login_attempts + 1,
     END,
     updated_at = NOW()
 WHERE id = $1
-RETURNING locked_until
+RETURNING failed_login_attempts, locked_until
 `
+	var attempts int
 	var storedLock *time.Time
-	err := r.pool.QueryRow(ctx, query, userID, maxAttempts, lockUntil).Scan(&storedLock)
+	err := r.pool.QueryRow(ctx, query, userID, maxAttempts, lockUntil).Scan(&attempts, &storedLock)
 	if err != nil {
-		return false, fmt.Errorf("mark login failure: %w", err)
+		return 0, false, fmt.Errorf("mark login failure: %w", err)
 	}
-	return storedLock != nil && storedLock.After(time.Now().UTC()), nil
+	return attempts, storedLock != nil && storedLock.After(time.Now().UTC()), nil
 }
 
 func (r *AdminUserRepo) EnableTOTP(ctx context.Context, userID int64, secret string) error {
@@ -16,6 +16,7 @@ type ChallengeStatus string
 const (
 	ChallengeTelegramVerified ChallengeStatus = "telegram_verified"
 	ChallengeTOTPVerified     ChallengeStatus = "totp_verified"
+	ChallengePasskeyVerified  ChallengeStatus = "passkey_verified"
 	ChallengeCompleted        ChallengeStatus = "completed"
 )
 
@@ -24,6 +25,8 @@ type LoginChallenge struct {
 	AdminUserID int64
 	Status      ChallengeStatus
 	ExpiresAt   time.Time
+	// WebAuthnChallenge is the passkey assertion challenge issued for this login, if any.
+	WebAuthnChallenge []byte
 }
 
 type ChallengeRepo struct {
@@ -60,13 +63,13 @@ RETURNING id, admin_user_id, status, expires_at
 
 func (r *ChallengeRepo) GetActive(ctx context.Context, id uuid.UUID) (LoginChallenge, error) {
 	const query = `
-SELECT id, admin_user_id, status, expires_at
+SELECT id, admin_user_id, status, expires_at, webauthn_challenge
 FROM admin_login_challenges
 WHERE id = $1
   AND expires_at > NOW()
 `
 	var out LoginChallenge
-	err := r.pool.QueryRow(ctx, query, id).Scan(&out.ID, &out.AdminUserID, &out.Status, &out.ExpiresAt)
+	err := r.pool.QueryRow(ctx, query, id).Scan(&out.ID, &out.AdminUserID, &out.Status, &out.ExpiresAt, &out.WebAuthnChallenge)
 	if err != nil {
 		if errors.Is(err, pgx.ErrNoRows) {
 			return LoginChallenge{}, ErrNotFound
@@ -94,6 +97,24 @@ WHERE id = $1
 	return nil
 }
 
+func (r *ChallengeRepo) SetWebAuthnChallenge(ctx context.Context, id uuid.UUID, challenge []byte) error {
+	const query = `
+UPDATE admin_login_challenges
+SET webauthn_challenge = $2
+WHERE id = $1
+  AND status = $3
+  AND expires_at > NOW()
+`
+	res, err := r.pool.Exec(ctx, query, id, challenge, string(ChallengeTelegramVerified))
+	if err != nil {
+		return fmt.Errorf("set webauthn challenge: %w", err)
+	}
+	if res.RowsAffected() == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
 func (r *ChallengeRepo) Expire(ctx context.Context, id uuid.UUID) error {
 	const query = `
 UPDATE admin_login_challenges
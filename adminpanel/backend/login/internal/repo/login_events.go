@@ -0,0 +1,107 @@
+package repo
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+const (
+	LoginEventSuccess = "success"
+	LoginEventFailure = "failure"
+	LoginEventLocked  = "locked"
+)
+
+type LoginEvent struct {
+	ID          int64
+	AdminUserID int64
+	Event       string
+	Step        string
+	SessionID   *uuid.UUID
+	IP          string
+	UserAgent   string
+	CreatedAt   time.Time
+}
+
+// KnownLoginSource tells whether the admin has signed in successfully before, and from this IP / user agent.
+type KnownLoginSource struct {
+	HasHistory bool
+	KnownIP    bool
+	KnownAgent bool
+}
+
+type LoginEventRepo struct {
+	pool *pgxpool.Pool
+}
+
+func NewLoginEventRepo(pool *pgxpool.Pool) *LoginEventRepo {
+	return &LoginEventRepo{pool: pool}
+}
+
+func (r *LoginEventRepo) Insert(ctx context.Context, event LoginEvent) error {
+	const query = `
+INSERT INTO admin_login_events (admin_user_id, event, step, session_id, ip_address, user_agent)
+VALUES ($1, $2, $3, $4, NULLIF($5, ''), NULLIF($6, ''))
+`
+	if _, err := r.pool.Exec(ctx, query, event.AdminUserID, event.Event, event.Step, event.SessionID, event.IP, event.UserAgent); err != nil {
+		return fmt.Errorf("insert login event: %w", err)
+	}
+	return nil
+}
+
+func (r *LoginEventRepo) ListByUser(ctx context.Context, adminUserID int64, limit int) ([]LoginEvent, error) {
+	const query = `
+SELECT id, admin_user_id, event, step, session_id, COALESCE(HOST(ip_address), ''), COALESCE(user_agent, ''), created_at
+FROM admin_login_events
+WHERE admin_user_id = $1
+ORDER BY created_at DESC, id DESC
+LIMIT $2
+`
+	rows, err := r.pool.Query(ctx, query, adminUserID, limit)
+	if err != nil {
+		return nil, fmt.Errorf("list login events: %w", err)
+	}
+	defer rows.Close()
+
+	var out []LoginEvent
+	for rows.Next() {
+		var event LoginEvent
+		if err := rows.Scan(
+			&event.ID,
+			&event.AdminUserID,
+			&event.Event,
+			&event.Step,
+			&event.SessionID,
+			&event.IP,
+			&event.UserAgent,
+			&event.CreatedAt,
+		); err != nil {
+			return nil, fmt.Errorf("scan login event: %w", err)
+		}
+		out = append(out, event)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterate login events: %w", err)
+	}
+	return out, nil
+}
+
+func (r *LoginEventRepo) KnownSource(ctx context.Context, adminUserID int64, ip, userAgent string) (KnownLoginSource, error) {
+	const query = `
+SELECT
+    COUNT(*) > 0,
+    COALESCE(BOOL_OR(ip_address IS NOT DISTINCT FROM NULLIF($2, '')::INET), FALSE),
+    COALESCE(BOOL_OR(user_agent IS NOT DISTINCT FROM NULLIF($3, '')), FALSE)
+FROM admin_login_events
+WHERE admin_user_id = $1
+  AND event = 'success'
+`
+	var out KnownLoginSource
+	if err := r.pool.QueryRow(ctx, query, adminUserID, ip, userAgent).Scan(&out.HasHistory, &out.KnownIP, &out.KnownAgent); err != nil {
+		return KnownLoginSource{}, fmt.Errorf("check known login source: %w", err)
+	}
+	return out, nil
+}
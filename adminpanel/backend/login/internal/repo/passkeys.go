@@ -0,0 +1,220 @@
+package repo
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+type Passkey struct {
+	ID           int64
+	AdminUserID  int64
+	CredentialID []byte
+	PublicKey    []byte
+	Algorithm    int
+	SignCount    uint32
+	Transports   []string
+	Name         string
+	CreatedAt    time.Time
+	LastUsedAt   *time.Time
+}
+
+type PasskeyRegistration struct {
+	ID          uuid.UUID
+	AdminUserID int64
+	Challenge   []byte
+	ExpiresAt   time.Time
+}
+
+type PasskeyRepo struct {
+	pool *pgxpool.Pool
+}
+
+func NewPasskeyRepo(pool *pgxpool.Pool) *PasskeyRepo {
+	return &PasskeyRepo{pool: pool}
+}
+
+const passkeyColumns = `
+id, admin_user_id, credential_id, public_key, algorithm, sign_count, transports, name, created_at, last_used_at
+`
+
+func (r *PasskeyRepo) Create(ctx context.Context, passkey Passkey) (Passkey, error) {
+	query := `
+INSERT INTO admin_webauthn_credentials (admin_user_id, credential_id, public_key, algorithm, sign_count, transports, name)
+VALUES ($1, $2, $3, $4, $5, $6, $7)
+RETURNING ` + passkeyColumns
+	transports := passkey.Transports
+	if transports == nil {
+		transports = []string{}
+	}
+	created, err := scanPasskey(r.pool.QueryRow(ctx, query,
+		passkey.AdminUserID,
+		passkey.CredentialID,
+		passkey.PublicKey,
+		passkey.Algorithm,
+		int64(passkey.SignCount),
+		transports,
+		passkey.Name,
+	))
+	if err != nil {
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) && pgErr.Code == "23505" {
+			return Passkey{}, ErrConflict
+		}
+		return Passkey{}, fmt.Errorf("create passkey: %w", err)
+	}
+	return created, nil
+}
+
+func (r *PasskeyRepo) ListByUser(ctx context.Context, adminUserID int64) ([]Passkey, error) {
+	query := `SELECT ` + passkeyColumns + `
+FROM admin_webauthn_credentials
+WHERE admin_user_id = $1
+ORDER BY id ASC
+`
+	rows, err := r.pool.Query(ctx, query, adminUserID)
+	if err != nil {
+		return nil, fmt.Errorf("list passkeys: %w", err)
+	}
+	defer rows.Close()
+
+	var out []Passkey
+	for rows.Next() {
+		passkey, err := scanPasskey(rows)
+		if err != nil {
+			return nil, fmt.Errorf("scan passkey: %w", err)
+		}
+		out = append(out, passkey)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterate passkeys: %w", err)
+	}
+	return out, nil
+}
+
+func (r *PasskeyRepo) FindByCredentialID(ctx context.Context, adminUserID int64, credentialID []byte) (Passkey, error) {
+	query := `SELECT ` + passkeyColumns + `
+FROM admin_webauthn_credentials
+WHERE admin_user_id = $1
+  AND credential_id = $2
+`
+	passkey, err := scanPasskey(r.pool.QueryRow(ctx, query, adminUserID, credentialID))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return Passkey{}, ErrNotFound
+		}
+		return Passkey{}, fmt.Errorf("find passkey: %w", err)
+	}
+	return passkey, nil
+}
+
+// UpdateSignCount stores the counter only if it still matches the value the assertion was verified against,
+// so two concurrent assertions with the same counter cannot both succeed.
+func (r *PasskeyRepo) UpdateSignCount(ctx context.Context, id int64, previous, next uint32) error {
+	const query = `
+UPDATE admin_webauthn_credentials
+SET sign_count = $3,
+    last_used_at = NOW()
+WHERE id = $1
+  AND sign_count = $2
+`
+	res, err := r.pool.Exec(ctx, query, id, int64(previous), int64(next))
+	if err != nil {
+		return fmt.Errorf("update passkey sign count: %w", err)
+	}
+	if res.RowsAffected() == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+func (r *PasskeyRepo) Delete(ctx context.Context, id int64, adminUserID int64) error {
+	const query = `DELETE FROM admin_webauthn_credentials WHERE id = $1 AND admin_user_id = $2`
+	res, err := r.pool.Exec(ctx, query, id, adminUserID)
+	if err != nil {
+		return fmt.Errorf("delete passkey: %w", err)
+	}
+	if res.RowsAffected() == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+func (r *PasskeyRepo) CountByUser(ctx context.Context, adminUserID int64) (int, error) {
+	const query = `SELECT COUNT(*) FROM admin_webauthn_credentials WHERE admin_user_id = $1`
+	var count int
+	if err := r.pool.QueryRow(ctx, query, adminUserID).Scan(&count); err != nil {
+		return 0, fmt.Errorf("count passkeys: %w", err)
+	}
+	return count, nil
+}
+
+func (r *PasskeyRepo) CreateRegistration(ctx context.Context, adminUserID int64, challenge []byte, ttl time.Duration) (PasskeyRegistration, error) {
+	const query = `
+INSERT INTO admin_webauthn_registrations (id, admin_user_id, challenge, expires_at)
+VALUES ($1, $2, $3, NOW() + ($4 * INTERVAL '1 second'))
+RETURNING id, admin_user_id, challenge, expires_at
+`
+	seconds := secondsOrDefault(ttl, 300)
+	var out PasskeyRegistration
+	err := r.pool.QueryRow(ctx, query, uuid.New(), adminUserID, challenge, seconds).Scan(
+		&out.ID,
+		&out.AdminUserID,
+		&out.Challenge,
+		&out.ExpiresAt,
+	)
+	if err != nil {
+		return PasskeyRegistration{}, fmt.Errorf("create passkey registration: %w", err)
+	}
+	return out, nil
+}
+
+// TakeRegistration returns and deletes a live registration, so each challenge is usable once.
+func (r *PasskeyRepo) TakeRegistration(ctx context.Context, id uuid.UUID, adminUserID int64) (PasskeyRegistration, error) {
+	const query = `
+DELETE FROM admin_webauthn_registrations
+WHERE id = $1
+  AND admin_user_id = $2
+  AND expires_at > NOW()
+RETURNING id, admin_user_id, challenge, expires_at
+`
+	var out PasskeyRegistration
+	err := r.pool.QueryRow(ctx, query, id, adminUserID).Scan(
+		&out.ID,
+		&out.AdminUserID,
+		&out.Challenge,
+		&out.ExpiresAt,
+	)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return PasskeyRegistration{}, ErrNotFound
+		}
+		return PasskeyRegistration{}, fmt.Errorf("take passkey registration: %w", err)
+	}
+	return out, nil
+}
+
+func scanPasskey(row pgx.Row) (Passkey, error) {
+	var passkey Passkey
+	var signCount int64
+	err := row.Scan(
+		&passkey.ID,
+		&passkey.AdminUserID,
+		&passkey.CredentialID,
+		&passkey.PublicKey,
+		&passkey.Algorithm,
+		&signCount,
+		&passkey.Transports,
+		&passkey.Name,
+		&passkey.CreatedAt,
+		&passkey.LastUsedAt,
+	)
+	passkey.SignCount = uint32(signCount)
+	return passkey, err
+}
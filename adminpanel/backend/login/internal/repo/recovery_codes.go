@@ -0,0 +1,68 @@
+package repo
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+type RecoveryCodeRepo struct {
+	pool *pgxpool.Pool
+}
+
+func NewRecoveryCodeRepo(pool *pgxpool.Pool) *RecoveryCodeRepo {
+	return &RecoveryCodeRepo{pool: pool}
+}
+
+// Replace drops every code of the admin, used or not, and stores the new hashes in one statement.
+func (r *RecoveryCodeRepo) Replace(ctx context.Context, adminUserID int64, hashes []string) error {
+	const query = `
+WITH removed AS (
+    DELETE FROM admin_recovery_codes WHERE admin_user_id = $1
+)
+INSERT INTO admin_recovery_codes (admin_user_id, code_hash)
+SELECT $1, code_hash FROM UNNEST($2::TEXT[]) AS code_hash
+`
+	if _, err := r.pool.Exec(ctx, query, adminUserID, hashes); err != nil {
+		return fmt.Errorf("replace recovery codes: %w", err)
+	}
+	return nil
+}
+
+// Consume marks an unused code as used; a second attempt with the same code gets ErrNotFound.
+func (r *RecoveryCodeRepo) Consume(ctx context.Context, adminUserID int64, hash, ip string) error {
+	const query = `
+UPDATE admin_recovery_codes
+SET used_at = NOW(),
+    used_ip = NULLIF($3, '')::INET
+WHERE admin_user_id = $1
+  AND code_hash = $2
+  AND used_at IS NULL
+`
+	res, err := r.pool.Exec(ctx, query, adminUserID, hash, ip)
+	if err != nil {
+		return fmt.Errorf("consume recovery code: %w", err)
+	}
+	if res.RowsAffected() == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+func (r *RecoveryCodeRepo) CountUnused(ctx context.Context, adminUserID int64) (int, error) {
+	const query = `SELECT COUNT(*) FROM admin_recovery_codes WHERE admin_user_id = $1 AND used_at IS NULL`
+	var count int
+	if err := r.pool.QueryRow(ctx, query, adminUserID).Scan(&count); err != nil {
+		return 0, fmt.Errorf("count recovery codes: %w", err)
+	}
+	return count, nil
+}
+
+func (r *RecoveryCodeRepo) DeleteForUser(ctx context.Context, adminUserID int64) error {
+	const query = `DELETE FROM admin_recovery_codes WHERE admin_user_id = $1`
+	if _, err := r.pool.Exec(ctx, query, adminUserID); err != nil {
+		return fmt.Errorf("delete recovery codes: %w", err)
+	}
+	return nil
+}
@@ -9,6 +9,17 @@ import (
 	"github.com/jackc/pgx/v5/pgxpool"
 )
 
+type AdminSession struct {
+	ID            uuid.UUID
+	AdminUserID   int64
+	IP            string
+	UserAgent     string
+	CreatedAt     time.Time
+	LastSeenAt    time.Time
+	IdleExpiresAt time.Time
+	ExpiresAt     time.Time
+}
+
 type SessionRepo struct {
 	pool *pgxpool.Pool
 }
@@ -70,6 +81,60 @@ WHERE id = $1
 	return nil
 }
 
+// ListActive returns sessions that are neither revoked nor expired, most recently used first.
+func (r *SessionRepo) ListActive(ctx context.Context, adminUserID int64) ([]AdminSession, error) {
+	const query = `
+SELECT id, admin_user_id, COALESCE(HOST(ip_address), ''), COALESCE(user_agent, ''),
+       created_at, last_seen_at, idle_expires_at, expires_at
+FROM admin_sessions
+WHERE admin_user_id = $1
+  AND revoked_at IS NULL
+  AND expires_at > NOW()
+  AND idle_expires_at > NOW()
+ORDER BY last_seen_at DESC
+`
+	rows, err := r.pool.Query(ctx, query, adminUserID)
+	if err != nil {
+		return nil, fmt.Errorf("list admin sessions: %w", err)
+	}
+	defer rows.Close()
+
+	var out []AdminSession
+	for rows.Next() {
+		var session AdminSession
+		if err := rows.Scan(
+			&session.ID,
+			&session.AdminUserID,
+			&session.IP,
+			&session.UserAgent,
+			&session.CreatedAt,
+			&session.LastSeenAt,
+			&session.IdleExpiresAt,
+			&session.ExpiresAt,
+		); err != nil {
+			return nil, fmt.Errorf("scan admin session: %w", err)
+		}
+		out = append(out, session)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterate admin sessions: %w", err)
+	}
+	return out, nil
+}
+
+func (r *SessionRepo) RevokeAllForUser(ctx context.Context, adminUserID int64) error {
+	const query = `
+UPDATE admin_sessions
+SET revoked_at = NOW()
+WHERE admin_user_id = $1
+  AND revoked_at IS NULL
+`
+	if _, err := r.pool.Exec(ctx, query, adminUserID); err != nil {
+		return fmt.Errorf("revoke admin sessions: %w", err)
+	}
+	return nil
+}
+
 func secondsOrDefault(d time.Duration, fallback int64) int64 {
 	seconds := int64(d.Seconds())
 	if seconds <= 0 {
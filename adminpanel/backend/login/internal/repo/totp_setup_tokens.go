@@ -81,3 +81,12 @@ func (r *TOTPSetupTokenRepo) Delete(ctx context.Context, id uuid.UUID) error {
 	}
 	return nil
 }
+
+func (r *TOTPSetupTokenRepo) DeleteForUser(ctx context.Context, adminUserID int64) error {
+	const query = `DELETE FROM admin_totp_setup_tokens WHERE admin_user_id = $1`
+	_, err := r.pool.Exec(ctx, query, adminUserID)
+	if err != nil {
+		return fmt.Errorf("delete totp setup tokens for user: %w", err)
+	}
+	return nil
+}
@@ -1,7 +1,10 @@
 package security
 
 import (
+	"crypto/rand"
+	"encoding/base32"
 	"errors"
+	"strings"
 
 	"golang.org/x/crypto/bcrypt"
 )
@@ -16,6 +19,15 @@ func HashPassword(password string) (string, error) {
 	return string(hash), nil
 }
 
+// GenerateTemporaryPassword returns a random one-time password for invites and forced resets.
+func GenerateTemporaryPassword() (string, error) {
+	buf := make([]byte, 15)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+	return strings.ToLower(base32.StdEncoding.WithPadding(base32.NoPadding).EncodeToString(buf)), nil
+}
+
 func CheckPassword(hash, password string) error {
 	if err := bcrypt.CompareHashAndPassword([]byte(hash), []byte(password)); err != nil {
 		return ErrInvalidPassword
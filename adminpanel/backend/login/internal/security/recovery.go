@@ -0,0 +1,55 @@
+package security
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base32"
+	"encoding/hex"
+	"strings"
+)
+
+const (
+	RecoveryCodeCount = 10
+
+	recoveryCodeLength = 10
+	recoveryAlphabet   = "abcdefghijklmnopqrstuvwxyz234567"
+)
+
+var recoveryEncoding = base32.StdEncoding.WithPadding(base32.NoPadding)
+
+// GenerateRecoveryCodes returns one-time codes formatted as "xxxxx-xxxxx".
+func GenerateRecoveryCodes(count int) ([]string, error) {
+	codes := make([]string, 0, count)
+	buf := make([]byte, 7)
+	for len(codes) < count {
+		if _, err := rand.Read(buf); err != nil {
+			return nil, err
+		}
+		code := strings.ToLower(recoveryEncoding.EncodeToString(buf))[:recoveryCodeLength]
+		codes = append(codes, code[:5]+"-"+code[5:])
+	}
+	return codes, nil
+}
+
+// NormalizeRecoveryCode drops separators and case; it returns "" for anything that cannot be a recovery code,
+// so six-digit TOTP codes never reach the recovery path.
+func NormalizeRecoveryCode(code string) string {
+	code = strings.ToLower(strings.TrimSpace(code))
+	code = strings.NewReplacer("-", "", " ", "").Replace(code)
+	if len(code) != recoveryCodeLength {
+		return ""
+	}
+	for _, r := range code {
+		if !strings.ContainsRune(recoveryAlphabet, r) {
+			return ""
+		}
+	}
+	return code
+}
+
+// HashRecoveryCode hashes a normalized code. Codes carry 50 random bits, so a plain SHA-256 is enough
+// and keeps lookups by hash possible.
+func HashRecoveryCode(normalized string) string {
+	sum := sha256.Sum256([]byte(normalized))
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,35 @@
+package security
+
+import "testing"
+
+func TestRecoveryCodesNormalizeAndHash(t *testing.T) {
+	codes, err := GenerateRecoveryCodes(RecoveryCodeCount)
+	if err != nil {
+		t.Fatalf("generate: %v", err)
+	}
+	if len(codes) != RecoveryCodeCount {
+		t.Fatalf("expected %d codes, got %d", RecoveryCodeCount, len(codes))
+	}
+
+	seen := make(map[string]bool, len(codes))
+	for _, code := range codes {
+		normalized := NormalizeRecoveryCode(code)
+		if normalized == "" {
+			t.Fatalf("generated code %q does not normalize", code)
+		}
+		if NormalizeRecoveryCode(" "+code[:5]+" "+code[6:]+" ") != normalized {
+			t.Fatalf("separators should not matter for %q", code)
+		}
+		hash := HashRecoveryCode(normalized)
+		if seen[hash] {
+			t.Fatalf("duplicate code %q", code)
+		}
+		seen[hash] = true
+	}
+
+	for _, code := range []string{"123456", "", "abcde-fghi", "abcde-fgh1!"} {
+		if NormalizeRecoveryCode(code) != "" {
+			t.Fatalf("expected %q to be rejected", code)
+		}
+	}
+}
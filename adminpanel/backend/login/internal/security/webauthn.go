@@ -0,0 +1,244 @@
+package security
+
+import (
+	"bytes"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/subtle"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// COSE algorithm identifiers accepted for passkeys.
+const (
+	COSEAlgES256 = -7
+	COSEAlgEdDSA = -8
+	COSEAlgRS256 = -257
+)
+
+const (
+	authDataFlagUserPresent  = 0x01
+	authDataFlagUserVerified = 0x04
+	authDataFlagAttestedData = 0x40
+
+	authDataMinLength = 37
+)
+
+var (
+	ErrWebAuthnInvalid     = errors.New("invalid webauthn response")
+	ErrWebAuthnSignature   = errors.New("invalid webauthn signature")
+	ErrWebAuthnSignCounter = errors.New("webauthn sign counter did not increase")
+)
+
+// WebAuthn verifies registration and assertion ceremonies for one relying party.
+// Registration expects attestation "none" and the SPKI public key from AuthenticatorAttestationResponse.getPublicKey(),
+// so no CBOR parsing is needed.
+type WebAuthn struct {
+	RPID    string
+	RPName  string
+	Origins []string
+}
+
+type AuthenticatorData struct {
+	RPIDHash     []byte
+	Flags        byte
+	SignCount    uint32
+	CredentialID []byte
+}
+
+func (d AuthenticatorData) UserVerified() bool {
+	return d.Flags&authDataFlagUserVerified != 0
+}
+
+type clientData struct {
+	Type      string `json:"type"`
+	Challenge string `json:"challenge"`
+	Origin    string `json:"origin"`
+}
+
+// RegisteredCredential is what gets stored after a successful registration ceremony.
+type RegisteredCredential struct {
+	ID        []byte
+	PublicKey []byte
+	Algorithm int
+	SignCount uint32
+}
+
+func (w WebAuthn) Enabled() bool {
+	return strings.TrimSpace(w.RPID) != "" && len(w.Origins) > 0
+}
+
+func NewWebAuthnChallenge() ([]byte, error) {
+	challenge := make([]byte, 32)
+	if _, err := rand.Read(challenge); err != nil {
+		return nil, err
+	}
+	return challenge, nil
+}
+
+// VerifyRegistration checks a navigator.credentials.create() response against the issued challenge.
+func (w WebAuthn) VerifyRegistration(challenge, clientDataJSON, authenticatorData, publicKeyDER []byte, algorithm int) (RegisteredCredential, error) {
+	if err := w.verifyClientData(clientDataJSON, "webauthn.create", challenge); err != nil {
+		return RegisteredCredential{}, err
+	}
+	authData, err := w.parseAuthenticatorData(authenticatorData)
+	if err != nil {
+		return RegisteredCredential{}, err
+	}
+	if authData.Flags&authDataFlagAttestedData == 0 || len(authData.CredentialID) == 0 {
+		return RegisteredCredential{}, fmt.Errorf("%w: no attested credential", ErrWebAuthnInvalid)
+	}
+	if _, err := parseWebAuthnPublicKey(publicKeyDER, algorithm); err != nil {
+		return RegisteredCredential{}, err
+	}
+
+	return RegisteredCredential{
+		ID:        authData.CredentialID,
+		PublicKey: publicKeyDER,
+		Algorithm: algorithm,
+		SignCount: authData.SignCount,
+	}, nil
+}
+
+// VerifyAssertion checks a navigator.credentials.get() response and returns the new sign counter.
+// A counter that does not grow past storedSignCount means a cloned authenticator, unless both are 0
+// (authenticators without counters).
+func (w WebAuthn) VerifyAssertion(challenge, clientDataJSON, authenticatorData, signature, publicKeyDER []byte, algorithm int, storedSignCount uint32) (uint32, error) {
+	if err := w.verifyClientData(clientDataJSON, "webauthn.get", challenge); err != nil {
+		return 0, err
+	}
+	authData, err := w.parseAuthenticatorData(authenticatorData)
+	if err != nil {
+		return 0, err
+	}
+	publicKey, err := parseWebAuthnPublicKey(publicKeyDER, algorithm)
+	if err != nil {
+		return 0, err
+	}
+
+	clientDataHash := sha256.Sum256(clientDataJSON)
+	signed := append(append([]byte{}, authenticatorData...), clientDataHash[:]...)
+	if !verifyWebAuthnSignature(publicKey, signed, signature) {
+		return 0, ErrWebAuthnSignature
+	}
+
+	if (authData.SignCount != 0 || storedSignCount != 0) && authData.SignCount <= storedSignCount {
+		return 0, ErrWebAuthnSignCounter
+	}
+	return authData.SignCount, nil
+}
+
+func (w WebAuthn) verifyClientData(raw []byte, wantType string, challenge []byte) error {
+	var data clientData
+	if err := json.Unmarshal(raw, &data); err != nil {
+		return fmt.Errorf("%w: client data: %v", ErrWebAuthnInvalid, err)
+	}
+	if data.Type != wantType {
+		return fmt.Errorf("%w: unexpected type %q", ErrWebAuthnInvalid, data.Type)
+	}
+	got, err := DecodeBase64URL(data.Challenge)
+	if err != nil || len(challenge) == 0 || subtle.ConstantTimeCompare(got, challenge) != 1 {
+		return fmt.Errorf("%w: challenge mismatch", ErrWebAuthnInvalid)
+	}
+	for _, origin := range w.Origins {
+		if data.Origin == origin {
+			return nil
+		}
+	}
+	return fmt.Errorf("%w: origin %q is not allowed", ErrWebAuthnInvalid, data.Origin)
+}
+
+func (w WebAuthn) parseAuthenticatorData(raw []byte) (AuthenticatorData, error) {
+	authData, err := ParseAuthenticatorData(raw)
+	if err != nil {
+		return AuthenticatorData{}, err
+	}
+	rpIDHash := sha256.Sum256([]byte(w.RPID))
+	if !bytes.Equal(authData.RPIDHash, rpIDHash[:]) {
+		return AuthenticatorData{}, fmt.Errorf("%w: rp id mismatch", ErrWebAuthnInvalid)
+	}
+	if authData.Flags&authDataFlagUserPresent == 0 {
+		return AuthenticatorData{}, fmt.Errorf("%w: user not present", ErrWebAuthnInvalid)
+	}
+	return authData, nil
+}
+
+// ParseAuthenticatorData reads rpIdHash, flags, signCount and, when attested, the credential id.
+func ParseAuthenticatorData(raw []byte) (AuthenticatorData, error) {
+	if len(raw) < authDataMinLength {
+		return AuthenticatorData{}, fmt.Errorf("%w: authenticator data too short", ErrWebAuthnInvalid)
+	}
+	out := AuthenticatorData{
+		RPIDHash:  raw[:32],
+		Flags:     raw[32],
+		SignCount: binary.BigEndian.Uint32(raw[33:37]),
+	}
+	if out.Flags&authDataFlagAttestedData == 0 {
+		return out, nil
+	}
+
+	// aaguid (16) | credentialIdLength (2) | credentialId | credentialPublicKey (CBOR, unused)
+	rest := raw[authDataMinLength:]
+	if len(rest) < 18 {
+		return AuthenticatorData{}, fmt.Errorf("%w: attested data too short", ErrWebAuthnInvalid)
+	}
+	idLength := int(binary.BigEndian.Uint16(rest[16:18]))
+	if idLength == 0 || len(rest) < 18+idLength {
+		return AuthenticatorData{}, fmt.Errorf("%w: bad credential id length", ErrWebAuthnInvalid)
+	}
+	out.CredentialID = rest[18 : 18+idLength]
+	return out, nil
+}
+
+func parseWebAuthnPublicKey(der []byte, algorithm int) (crypto.PublicKey, error) {
+	key, err := x509.ParsePKIXPublicKey(der)
+	if err != nil {
+		return nil, fmt.Errorf("%w: public key: %v", ErrWebAuthnInvalid, err)
+	}
+	ok := false
+	switch algorithm {
+	case COSEAlgES256:
+		_, ok = key.(*ecdsa.PublicKey)
+	case COSEAlgEdDSA:
+		_, ok = key.(ed25519.PublicKey)
+	case COSEAlgRS256:
+		_, ok = key.(*rsa.PublicKey)
+	}
+	if !ok {
+		return nil, fmt.Errorf("%w: unsupported algorithm %d", ErrWebAuthnInvalid, algorithm)
+	}
+	return key, nil
+}
+
+func verifyWebAuthnSignature(publicKey crypto.PublicKey, signed, signature []byte) bool {
+	switch key := publicKey.(type) {
+	case *ecdsa.PublicKey:
+		digest := sha256.Sum256(signed)
+		return ecdsa.VerifyASN1(key, digest[:], signature)
+	case ed25519.PublicKey:
+		return ed25519.Verify(key, signed, signature)
+	case *rsa.PublicKey:
+		digest := sha256.Sum256(signed)
+		return rsa.VerifyPKCS1v15(key, crypto.SHA256, digest[:], signature) == nil
+	default:
+		return false
+	}
+}
+
+// DecodeBase64URL accepts the unpadded base64url WebAuthn uses, tolerating padding.
+func DecodeBase64URL(value string) ([]byte, error) {
+	return base64.RawURLEncoding.DecodeString(strings.TrimRight(strings.TrimSpace(value), "="))
+}
+
+func EncodeBase64URL(value []byte) string {
+	return base64.RawURLEncoding.EncodeToString(value)
+}
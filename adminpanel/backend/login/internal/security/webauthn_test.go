@@ -0,0 +1,153 @@
+package security
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"testing"
+)
+
+const testOrigin = "https://admin.example.com"
+
+// softAuthenticator is an in-memory ES256 authenticator producing the same bytes a browser would.
+type softAuthenticator struct {
+	t            *testing.T
+	rpID         string
+	key          *ecdsa.PrivateKey
+	credentialID []byte
+	signCount    uint32
+}
+
+func newSoftAuthenticator(t *testing.T, rpID string) *softAuthenticator {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatalf("generate key: %v", err)
+	}
+	return &softAuthenticator{t: t, rpID: rpID, key: key, credentialID: []byte("soft-credential-1")}
+}
+
+func (a *softAuthenticator) publicKeyDER() []byte {
+	der, err := x509.MarshalPKIXPublicKey(&a.key.PublicKey)
+	if err != nil {
+		a.t.Fatalf("marshal public key: %v", err)
+	}
+	return der
+}
+
+func (a *softAuthenticator) clientData(kind string, challenge []byte, origin string) []byte {
+	raw, err := json.Marshal(map[string]any{
+		"type":      kind,
+		"challenge": EncodeBase64URL(challenge),
+		"origin":    origin,
+	})
+	if err != nil {
+		a.t.Fatalf("marshal client data: %v", err)
+	}
+	return raw
+}
+
+func (a *softAuthenticator) authData(flags byte, attested bool) []byte {
+	rpIDHash := sha256.Sum256([]byte(a.rpID))
+	out := append([]byte{}, rpIDHash[:]...)
+	out = append(out, flags)
+	out = binary.BigEndian.AppendUint32(out, a.signCount)
+	if attested {
+		out = append(out, make([]byte, 16)...)
+		out = binary.BigEndian.AppendUint16(out, uint16(len(a.credentialID)))
+		out = append(out, a.credentialID...)
+		out = append(out, 0xa0) // empty CBOR map in place of the COSE key
+	}
+	return out
+}
+
+func (a *softAuthenticator) create(challenge []byte) (clientDataJSON, authData []byte) {
+	return a.clientData("webauthn.create", challenge, testOrigin), a.authData(authDataFlagUserPresent|authDataFlagUserVerified|authDataFlagAttestedData, true)
+}
+
+func (a *softAuthenticator) get(challenge []byte, origin string) (clientDataJSON, authData, signature []byte) {
+	a.signCount++
+	clientDataJSON = a.clientData("webauthn.get", challenge, origin)
+	authData = a.authData(authDataFlagUserPresent|authDataFlagUserVerified, false)
+	clientDataHash := sha256.Sum256(clientDataJSON)
+	digest := sha256.Sum256(append(append([]byte{}, authData...), clientDataHash[:]...))
+	signature, err := ecdsa.SignASN1(rand.Reader, a.key, digest[:])
+	if err != nil {
+		a.t.Fatalf("sign assertion: %v", err)
+	}
+	return clientDataJSON, authData, signature
+}
+
+func TestWebAuthnRegistrationAndAssertion(t *testing.T) {
+	rp := WebAuthn{RPID: "admin.example.com", Origins: []string{testOrigin}}
+	authenticator := newSoftAuthenticator(t, rp.RPID)
+
+	challenge, err := NewWebAuthnChallenge()
+	if err != nil {
+		t.Fatalf("challenge: %v", err)
+	}
+	clientDataJSON, authData := authenticator.create(challenge)
+	credential, err := rp.VerifyRegistration(challenge, clientDataJSON, authData, authenticator.publicKeyDER(), COSEAlgES256)
+	if err != nil {
+		t.Fatalf("verify registration: %v", err)
+	}
+	if string(credential.ID) != string(authenticator.credentialID) {
+		t.Fatalf("unexpected credential id %q", credential.ID)
+	}
+
+	challenge, _ = NewWebAuthnChallenge()
+	clientDataJSON, authData, signature := authenticator.get(challenge, testOrigin)
+	signCount, err := rp.VerifyAssertion(challenge, clientDataJSON, authData, signature, credential.PublicKey, credential.Algorithm, credential.SignCount)
+	if err != nil {
+		t.Fatalf("verify assertion: %v", err)
+	}
+	if signCount != 1 {
+		t.Fatalf("expected sign count 1, got %d", signCount)
+	}
+
+	// A replayed counter means the credential was cloned.
+	challenge, _ = NewWebAuthnChallenge()
+	clientDataJSON, authData, signature = authenticator.get(challenge, testOrigin)
+	if _, err := rp.VerifyAssertion(challenge, clientDataJSON, authData, signature, credential.PublicKey, credential.Algorithm, 5); !errors.Is(err, ErrWebAuthnSignCounter) {
+		t.Fatalf("expected sign counter error, got %v", err)
+	}
+}
+
+func TestWebAuthnAssertionRejectsTampering(t *testing.T) {
+	rp := WebAuthn{RPID: "admin.example.com", Origins: []string{testOrigin}}
+	authenticator := newSoftAuthenticator(t, rp.RPID)
+	publicKey := authenticator.publicKeyDER()
+	challenge, _ := NewWebAuthnChallenge()
+
+	clientDataJSON, authData, signature := authenticator.get(challenge, "https://evil.example.com")
+	if _, err := rp.VerifyAssertion(challenge, clientDataJSON, authData, signature, publicKey, COSEAlgES256, 0); !errors.Is(err, ErrWebAuthnInvalid) {
+		t.Fatalf("expected origin rejection, got %v", err)
+	}
+
+	otherChallenge, _ := NewWebAuthnChallenge()
+	clientDataJSON, authData, signature = authenticator.get(otherChallenge, testOrigin)
+	if _, err := rp.VerifyAssertion(challenge, clientDataJSON, authData, signature, publicKey, COSEAlgES256, 0); !errors.Is(err, ErrWebAuthnInvalid) {
+		t.Fatalf("expected challenge rejection, got %v", err)
+	}
+
+	clientDataJSON, authData, signature = authenticator.get(challenge, testOrigin)
+	signature[len(signature)-1] ^= 0xff
+	if _, err := rp.VerifyAssertion(challenge, clientDataJSON, authData, signature, publicKey, COSEAlgES256, 0); !errors.Is(err, ErrWebAuthnSignature) {
+		t.Fatalf("expected signature rejection, got %v", err)
+	}
+
+	otherRP := WebAuthn{RPID: "other.example.com", Origins: []string{testOrigin}}
+	clientDataJSON, authData, signature = authenticator.get(challenge, testOrigin)
+	if _, err := otherRP.VerifyAssertion(challenge, clientDataJSON, authData, signature, publicKey, COSEAlgES256, 0); !errors.Is(err, ErrWebAuthnInvalid) {
+		t.Fatalf("expected rp id rejection, got %v", err)
+	}
+
+	if _, err := rp.VerifyRegistration(challenge, authenticator.clientData("webauthn.create", challenge, testOrigin), authData, publicKey, COSEAlgEdDSA); !errors.Is(err, ErrWebAuthnInvalid) {
+		t.Fatalf("expected registration without attested data to fail, got %v", err)
+	}
+}
@@ -0,0 +1,364 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/ivankudzin/tgapp/adminpanel/backend/login/internal/repo"
+	"github.com/ivankudzin/tgapp/adminpanel/backend/login/internal/security"
+)
+
+const (
+	AuditAdminInvited       = "ADMIN_INVITED"
+	AuditAdminRoleChanged   = "ADMIN_ROLE_CHANGED"
+	AuditAdminDeactivated   = "ADMIN_DEACTIVATED"
+	AuditAdminReactivated   = "ADMIN_REACTIVATED"
+	AuditPasswordResetForce = "ADMIN_PASSWORD_RESET_FORCED"
+	AuditPasswordChanged    = "ADMIN_PASSWORD_CHANGED"
+	AuditTOTPReset          = "ADMIN_2FA_RESET"
+	AuditTOTPEnabled        = "ADMIN_2FA_ENABLED"
+	AuditAdminUnlocked      = "ADMIN_UNLOCKED"
+)
+
+var roleNamePattern = regexp.MustCompile(`^[A-Z][A-Z0-9_]{1,31}$`)
+
+// AuditMeta identifies who made a change; ActorID is 0 for bootstrap-key requests.
+type AuditMeta struct {
+	ActorID   int64
+	IP        string
+	UserAgent string
+}
+
+type AdminPrincipal struct {
+	User      repo.AdminUser
+	SessionID string
+}
+
+type AdminUserView struct {
+	ID                    int64      `json:"id"`
+	TelegramID            int64      `json:"telegram_id"`
+	Username              string     `json:"username,omitempty"`
+	DisplayName           string     `json:"display_name,omitempty"`
+	Role                  string     `json:"role"`
+	IsActive              bool       `json:"is_active"`
+	TOTPEnabled           bool       `json:"totp_enabled"`
+	PasswordResetRequired bool       `json:"password_reset_required"`
+	FailedLoginAttempts   int        `json:"failed_login_attempts"`
+	LockedUntil           *time.Time `json:"locked_until,omitempty"`
+	CreatedAt             time.Time  `json:"created_at"`
+	UpdatedAt             time.Time  `json:"updated_at"`
+}
+
+type InviteAdminInput struct {
+	TelegramID  int64
+	Username    string
+	DisplayName string
+	Role        string
+}
+
+// TemporaryPasswordResult carries a one-time password that is shown to the OWNER exactly once.
+type TemporaryPasswordResult struct {
+	User              AdminUserView `json:"user"`
+	TemporaryPassword string        `json:"temporary_password"`
+}
+
+// AuthorizeOwner validates the access token and requires the caller to be an active OWNER right now,
+// so a demotion takes effect without waiting for the token to expire.
+func (s *Service) AuthorizeOwner(ctx context.Context, token string) (repo.AdminUser, error) {
+	principal, err := s.AuthorizeAdmin(ctx, token)
+	if err != nil {
+		return repo.AdminUser{}, err
+	}
+	if !isOwner(principal.User.Role) {
+		return repo.AdminUser{}, ErrForbidden
+	}
+	return principal.User, nil
+}
+
+// AuthorizeAdmin resolves a session token to an active admin user of any role and the session it belongs to.
+func (s *Service) AuthorizeAdmin(ctx context.Context, token string) (AdminPrincipal, error) {
+	claims, err := s.ValidateAccessToken(ctx, token)
+	if err != nil {
+		return AdminPrincipal{}, err
+	}
+	user, err := s.users.FindByID(ctx, claims.UserID)
+	if err != nil {
+		if errors.Is(err, repo.ErrNotFound) {
+			return AdminPrincipal{}, ErrUnauthorized
+		}
+		return AdminPrincipal{}, fmt.Errorf("find admin user: %w", err)
+	}
+	if !user.IsActive {
+		return AdminPrincipal{}, ErrForbidden
+	}
+	return AdminPrincipal{User: user, SessionID: claims.SID}, nil
+}
+
+// BootstrapAllowed reports whether the bootstrap key may still be used: only while no OWNER exists at all,
+// so a deactivated owner or a 2FA reset cannot reopen it.
+func (s *Service) BootstrapAllowed(ctx context.Context) (bool, error) {
+	hasOwner, err := s.users.HasOwner(ctx)
+	if err != nil {
+		return false, err
+	}
+	return !hasOwner, nil
+}
+
+func (s *Service) ListAdminUsers(ctx context.Context) ([]AdminUserView, error) {
+	users, err := s.users.List(ctx)
+	if err != nil {
+		return nil, err
+	}
+	out := make([]AdminUserView, 0, len(users))
+	for _, user := range users {
+		out = append(out, toAdminUserView(user))
+	}
+	return out, nil
+}
+
+func (s *Service) GetAdminUser(ctx context.Context, userID int64) (AdminUserView, error) {
+	user, err := s.findAdminUser(ctx, userID)
+	if err != nil {
+		return AdminUserView{}, err
+	}
+	return toAdminUserView(user), nil
+}
+
+func (s *Service) InviteAdminUser(ctx context.Context, meta AuditMeta, in InviteAdminInput) (TemporaryPasswordResult, error) {
+	if in.TelegramID <= 0 {
+		return TemporaryPasswordResult{}, ErrInvalidInput
+	}
+	role, err := s.normalizeRole(ctx, in.Role)
+	if err != nil {
+		return TemporaryPasswordResult{}, err
+	}
+
+	password, hash, err := newTemporaryPassword()
+	if err != nil {
+		return TemporaryPasswordResult{}, err
+	}
+	user, err := s.users.Create(ctx, repo.AdminUser{
+		TelegramID:   in.TelegramID,
+		Username:     strings.TrimPrefix(strings.TrimSpace(in.Username), "@"),
+		DisplayName:  strings.TrimSpace(in.DisplayName),
+		Role:         role,
+		PasswordHash: hash,
+	}, meta.ActorID)
+	if err != nil {
+		if errors.Is(err, repo.ErrConflict) {
+			return TemporaryPasswordResult{}, ErrConflict
+		}
+		return TemporaryPasswordResult{}, err
+	}
+
+	s.writeAudit(ctx, meta, user.ID, AuditAdminInvited, map[string]any{
+		"telegram_id": user.TelegramID,
+		"role":        user.Role,
+	})
+	return TemporaryPasswordResult{User: toAdminUserView(user), TemporaryPassword: password}, nil
+}
+
+func (s *Service) SetAdminUserRole(ctx context.Context, meta AuditMeta, userID int64, role string) (AdminUserView, error) {
+	user, err := s.findAdminUser(ctx, userID)
+	if err != nil {
+		return AdminUserView{}, err
+	}
+	role, err = s.normalizeRole(ctx, role)
+	if err != nil {
+		return AdminUserView{}, err
+	}
+	if strings.EqualFold(user.Role, role) {
+		return toAdminUserView(user), nil
+	}
+
+	if err := s.users.SetRole(ctx, userID, role); err != nil {
+		return AdminUserView{}, mapAdminUserError(err)
+	}
+	if err := s.sessions.RevokeAllForUser(ctx, userID); err != nil {
+		return AdminUserView{}, err
+	}
+	s.writeAudit(ctx, meta, userID, AuditAdminRoleChanged, map[string]any{
+		"from": user.Role,
+		"to":   role,
+	})
+	return s.GetAdminUser(ctx, userID)
+}
+
+// SetAdminUserActive deactivates or reactivates an admin; deactivation also ends their sessions.
+func (s *Service) SetAdminUserActive(ctx context.Context, meta AuditMeta, userID int64, active bool) (AdminUserView, error) {
+	user, err := s.findAdminUser(ctx, userID)
+	if err != nil {
+		return AdminUserView{}, err
+	}
+	if user.IsActive == active {
+		return toAdminUserView(user), nil
+	}
+
+	if err := s.users.SetActive(ctx, userID, active); err != nil {
+		return AdminUserView{}, mapAdminUserError(err)
+	}
+	action := AuditAdminReactivated
+	if !active {
+		action = AuditAdminDeactivated
+		if err := s.sessions.RevokeAllForUser(ctx, userID); err != nil {
+			return AdminUserView{}, err
+		}
+	}
+	s.writeAudit(ctx, meta, userID, action, nil)
+	return s.GetAdminUser(ctx, userID)
+}
+
+func (s *Service) ForcePasswordReset(ctx context.Context, meta AuditMeta, userID int64) (TemporaryPasswordResult, error) {
+	if _, err := s.findAdminUser(ctx, userID); err != nil {
+		return TemporaryPasswordResult{}, err
+	}
+
+	password, hash, err := newTemporaryPassword()
+	if err != nil {
+		return TemporaryPasswordResult{}, err
+	}
+	if err := s.users.RequirePasswordReset(ctx, userID, hash); err != nil {
+		return TemporaryPasswordResult{}, mapAdminUserError(err)
+	}
+	if err := s.sessions.RevokeAllForUser(ctx, userID); err != nil {
+		return TemporaryPasswordResult{}, err
+	}
+	s.writeAudit(ctx, meta, userID, AuditPasswordResetForce, nil)
+
+	user, err := s.GetAdminUser(ctx, userID)
+	if err != nil {
+		return TemporaryPasswordResult{}, err
+	}
+	return TemporaryPasswordResult{User: user, TemporaryPassword: password}, nil
+}
+
+// ResetAdminUserTOTP clears the TOTP secret; TOTP sign-in stays unavailable until an OWNER runs 2FA setup again.
+func (s *Service) ResetAdminUserTOTP(ctx context.Context, meta AuditMeta, userID int64) (AdminUserView, error) {
+	if _, err := s.findAdminUser(ctx, userID); err != nil {
+		return AdminUserView{}, err
+	}
+
+	if err := s.users.DisableTOTP(ctx, userID); err != nil {
+		return AdminUserView{}, mapAdminUserError(err)
+	}
+	if err := s.setupTokens.DeleteForUser(ctx, userID); err != nil {
+		return AdminUserView{}, err
+	}
+	if err := s.recoveryCodes.DeleteForUser(ctx, userID); err != nil {
+		return AdminUserView{}, err
+	}
+	if err := s.sessions.RevokeAllForUser(ctx, userID); err != nil {
+		return AdminUserView{}, err
+	}
+	s.writeAudit(ctx, meta, userID, AuditTOTPReset, nil)
+	return s.GetAdminUser(ctx, userID)
+}
+
+func (s *Service) UnlockAdminUser(ctx context.Context, meta AuditMeta, userID int64) (AdminUserView, error) {
+	user, err := s.findAdminUser(ctx, userID)
+	if err != nil {
+		return AdminUserView{}, err
+	}
+
+	if err := s.users.ResetFailures(ctx, userID); err != nil {
+		return AdminUserView{}, err
+	}
+	s.writeAudit(ctx, meta, userID, AuditAdminUnlocked, map[string]any{
+		"failed_login_attempts": user.FailedAttempts,
+		"was_locked":            isLocked(user.LockedUntil),
+	})
+	return s.GetAdminUser(ctx, userID)
+}
+
+func (s *Service) findAdminUser(ctx context.Context, userID int64) (repo.AdminUser, error) {
+	if userID <= 0 {
+		return repo.AdminUser{}, ErrInvalidInput
+	}
+	user, err := s.users.FindByID(ctx, userID)
+	if err != nil {
+		return repo.AdminUser{}, mapAdminUserError(err)
+	}
+	return user, nil
+}
+
+// normalizeRole upper-cases the role and requires it to exist in admin_roles, shared with the main backend.
+func (s *Service) normalizeRole(ctx context.Context, raw string) (string, error) {
+	role := strings.ToUpper(strings.TrimSpace(raw))
+	if !roleNamePattern.MatchString(role) {
+		return "", ErrInvalidInput
+	}
+	exists, err := s.users.RoleExists(ctx, role)
+	if err != nil {
+		return "", err
+	}
+	if !exists {
+		return "", fmt.Errorf("%w: unknown role %s", ErrInvalidInput, role)
+	}
+	return role, nil
+}
+
+// writeAudit never fails the change it records: the change is already committed.
+func (s *Service) writeAudit(ctx context.Context, meta AuditMeta, targetID int64, action string, details map[string]any) {
+	if s.audit == nil {
+		return
+	}
+	if err := s.audit.Insert(ctx, repo.AdminAuditEntry{
+		ActorID:   meta.ActorID,
+		TargetID:  targetID,
+		Action:    action,
+		Details:   details,
+		IP:        meta.IP,
+		UserAgent: meta.UserAgent,
+	}); err != nil {
+		log.Printf("write admin audit %s for admin %d: %v", action, targetID, err)
+	}
+}
+
+func newTemporaryPassword() (string, string, error) {
+	password, err := security.GenerateTemporaryPassword()
+	if err != nil {
+		return "", "", fmt.Errorf("generate temporary password: %w", err)
+	}
+	hash, err := security.HashPassword(password)
+	if err != nil {
+		return "", "", fmt.Errorf("hash temporary password: %w", err)
+	}
+	return password, hash, nil
+}
+
+func mapAdminUserError(err error) error {
+	switch {
+	case errors.Is(err, repo.ErrNotFound):
+		return ErrNotFound
+	case errors.Is(err, repo.ErrLastOwner):
+		return ErrLastOwner
+	default:
+		return err
+	}
+}
+
+func isOwner(role string) bool {
+	return strings.EqualFold(strings.TrimSpace(role), repo.RoleOwner)
+}
+
+func toAdminUserView(user repo.AdminUser) AdminUserView {
+	return AdminUserView{
+		ID:                    user.ID,
+		TelegramID:            user.TelegramID,
+		Username:              user.Username,
+		DisplayName:           user.DisplayName,
+		Role:                  user.Role,
+		IsActive:              user.IsActive,
+		TOTPEnabled:           user.TOTPEnabled,
+		PasswordResetRequired: user.PasswordResetRequired,
+		FailedLoginAttempts:   user.FailedAttempts,
+		LockedUntil:           user.LockedUntil,
+		CreatedAt:             user.CreatedAt,
+		UpdatedAt:             user.UpdatedAt,
+	}
+}
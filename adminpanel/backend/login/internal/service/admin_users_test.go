@@ -0,0 +1,359 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/ivankudzin/tgapp/adminpanel/backend/login/internal/repo"
+	"github.com/ivankudzin/tgapp/adminpanel/backend/login/internal/security"
+)
+
+type fakeAdminUsers struct {
+	AdminUserStore
+	byID map[int64]repo.AdminUser
+}
+
+func newFakeAdminUsers(users ...repo.AdminUser) *fakeAdminUsers {
+	f := &fakeAdminUsers{byID: make(map[int64]repo.AdminUser, len(users))}
+	for _, user := range users {
+		f.byID[user.ID] = user
+	}
+	return f
+}
+
+func (f *fakeAdminUsers) FindByID(_ context.Context, userID int64) (repo.AdminUser, error) {
+	user, ok := f.byID[userID]
+	if !ok {
+		return repo.AdminUser{}, repo.ErrNotFound
+	}
+	return user, nil
+}
+
+func (f *fakeAdminUsers) HasOwner(context.Context) (bool, error) {
+	for _, user := range f.byID {
+		if isOwner(user.Role) {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func (f *fakeAdminUsers) RoleExists(_ context.Context, role string) (bool, error) {
+	return role == repo.RoleOwner || role == "ADMIN", nil
+}
+
+// keepsActiveOwner mirrors the SQL guard of the postgres repo.
+func (f *fakeAdminUsers) keepsActiveOwner(user repo.AdminUser) bool {
+	if !isOwner(user.Role) || !user.IsActive {
+		return true
+	}
+	for id, other := range f.byID {
+		if id != user.ID && isOwner(other.Role) && other.IsActive {
+			return true
+		}
+	}
+	return false
+}
+
+func (f *fakeAdminUsers) SetRole(_ context.Context, userID int64, role string) error {
+	user, ok := f.byID[userID]
+	if !ok {
+		return repo.ErrNotFound
+	}
+	if !isOwner(role) && !f.keepsActiveOwner(user) {
+		return repo.ErrLastOwner
+	}
+	user.Role = role
+	f.byID[userID] = user
+	return nil
+}
+
+func (f *fakeAdminUsers) SetActive(_ context.Context, userID int64, active bool) error {
+	user, ok := f.byID[userID]
+	if !ok {
+		return repo.ErrNotFound
+	}
+	if !active && !f.keepsActiveOwner(user) {
+		return repo.ErrLastOwner
+	}
+	user.IsActive = active
+	f.byID[userID] = user
+	return nil
+}
+
+func (f *fakeAdminUsers) DisableTOTP(_ context.Context, userID int64) error {
+	user, ok := f.byID[userID]
+	if !ok {
+		return repo.ErrNotFound
+	}
+	user.TOTPEnabled = false
+	user.TOTPSecret = ""
+	f.byID[userID] = user
+	return nil
+}
+
+type fakeSessions struct {
+	SessionStore
+	active     map[uuid.UUID]repo.AdminSession
+	revokedFor []int64
+}
+
+func newFakeSessions() *fakeSessions {
+	return &fakeSessions{active: make(map[uuid.UUID]repo.AdminSession)}
+}
+
+func (f *fakeSessions) add(adminUserID int64) uuid.UUID {
+	id := uuid.New()
+	f.active[id] = repo.AdminSession{ID: id, AdminUserID: adminUserID}
+	return id
+}
+
+func (f *fakeSessions) Touch(_ context.Context, sessionID uuid.UUID, adminUserID int64, _ time.Duration) error {
+	session, ok := f.active[sessionID]
+	if !ok || session.AdminUserID != adminUserID {
+		return repo.ErrNotFound
+	}
+	return nil
+}
+
+func (f *fakeSessions) Revoke(_ context.Context, sessionID uuid.UUID, adminUserID int64) error {
+	session, ok := f.active[sessionID]
+	if !ok || session.AdminUserID != adminUserID {
+		return repo.ErrNotFound
+	}
+	delete(f.active, sessionID)
+	return nil
+}
+
+func (f *fakeSessions) ListActive(_ context.Context, adminUserID int64) ([]repo.AdminSession, error) {
+	var out []repo.AdminSession
+	for _, session := range f.active {
+		if session.AdminUserID == adminUserID {
+			out = append(out, session)
+		}
+	}
+	return out, nil
+}
+
+func (f *fakeSessions) RevokeAllForUser(_ context.Context, adminUserID int64) error {
+	f.revokedFor = append(f.revokedFor, adminUserID)
+	for id, session := range f.active {
+		if session.AdminUserID == adminUserID {
+			delete(f.active, id)
+		}
+	}
+	return nil
+}
+
+type fakeAudit struct {
+	entries []repo.AdminAuditEntry
+}
+
+func (f *fakeAudit) Insert(_ context.Context, entry repo.AdminAuditEntry) error {
+	f.entries = append(f.entries, entry)
+	return nil
+}
+
+func (f *fakeAudit) actions() string {
+	actions := make([]string, 0, len(f.entries))
+	for _, entry := range f.entries {
+		actions = append(actions, entry.Action)
+	}
+	return strings.Join(actions, ",")
+}
+
+type fakeSetupTokens struct {
+	TOTPSetupTokenStore
+	deletedFor []int64
+}
+
+func (f *fakeSetupTokens) DeleteForUser(_ context.Context, adminUserID int64) error {
+	f.deletedFor = append(f.deletedFor, adminUserID)
+	return nil
+}
+
+type fakeRecoveryCodes struct {
+	RecoveryCodeStore
+	deletedFor []int64
+}
+
+func (f *fakeRecoveryCodes) DeleteForUser(_ context.Context, adminUserID int64) error {
+	f.deletedFor = append(f.deletedFor, adminUserID)
+	return nil
+}
+
+type testEnv struct {
+	svc           *Service
+	users         *fakeAdminUsers
+	sessions      *fakeSessions
+	audit         *fakeAudit
+	setupTokens   *fakeSetupTokens
+	recoveryCodes *fakeRecoveryCodes
+	tokens        *security.TokenManager
+}
+
+func newTestEnv(users ...repo.AdminUser) *testEnv {
+	env := &testEnv{
+		users:         newFakeAdminUsers(users...),
+		sessions:      newFakeSessions(),
+		audit:         &fakeAudit{},
+		setupTokens:   &fakeSetupTokens{},
+		recoveryCodes: &fakeRecoveryCodes{},
+		tokens:        security.NewTokenManager("test-secret", time.Hour),
+	}
+	env.svc = NewService(
+		env.users, nil, env.setupTokens, env.sessions, env.audit, nil, env.recoveryCodes, nil,
+		env.tokens, nil, security.WebAuthn{}, nil,
+		"", time.Minute, time.Minute, time.Minute, time.Hour, 12*time.Hour, 5, 15*time.Minute, "test", false,
+	)
+	return env
+}
+
+// signIn opens a session for the user and returns its access token.
+func (e *testEnv) signIn(t *testing.T, userID int64) string {
+	t.Helper()
+	user := e.users.byID[userID]
+	sessionID := e.sessions.add(userID)
+	token, _, err := e.tokens.Issue(user.ID, user.TelegramID, user.Role, user.Username, sessionID.String())
+	if err != nil {
+		t.Fatalf("issue token: %v", err)
+	}
+	return token
+}
+
+func owner(id int64) repo.AdminUser {
+	return repo.AdminUser{ID: id, TelegramID: 1000 + id, Role: repo.RoleOwner, IsActive: true, TOTPEnabled: true}
+}
+
+func admin(id int64) repo.AdminUser {
+	return repo.AdminUser{ID: id, TelegramID: 1000 + id, Role: "ADMIN", IsActive: true, TOTPEnabled: true}
+}
+
+func TestAuthorizeOwnerRejectsOtherRoles(t *testing.T) {
+	env := newTestEnv(owner(1), admin(2))
+
+	if _, err := env.svc.AuthorizeOwner(context.Background(), env.signIn(t, 2)); !errors.Is(err, ErrForbidden) {
+		t.Fatalf("expected ErrForbidden for a non-owner, got %v", err)
+	}
+	user, err := env.svc.AuthorizeOwner(context.Background(), env.signIn(t, 1))
+	if err != nil || user.ID != 1 {
+		t.Fatalf("expected the owner to pass, got %+v, %v", user, err)
+	}
+
+	// A demotion applies to tokens issued before it.
+	token := env.signIn(t, 1)
+	env.users.byID[1] = admin(1)
+	if _, err := env.svc.AuthorizeOwner(context.Background(), token); !errors.Is(err, ErrForbidden) {
+		t.Fatalf("expected ErrForbidden after demotion, got %v", err)
+	}
+}
+
+func TestBootstrapAllowedOnlyWithoutOwner(t *testing.T) {
+	env := newTestEnv(admin(2))
+	allowed, err := env.svc.BootstrapAllowed(context.Background())
+	if err != nil || !allowed {
+		t.Fatalf("expected bootstrap to be open without an owner, got %v, %v", allowed, err)
+	}
+
+	// An owner that is deactivated and has no 2FA still closes the bootstrap key.
+	dormant := owner(1)
+	dormant.IsActive = false
+	dormant.TOTPEnabled = false
+	env.users.byID[1] = dormant
+	allowed, err = env.svc.BootstrapAllowed(context.Background())
+	if err != nil || allowed {
+		t.Fatalf("expected bootstrap to be closed once an owner exists, got %v, %v", allowed, err)
+	}
+}
+
+func TestLastActiveOwnerCannotBeDemotedOrDeactivated(t *testing.T) {
+	env := newTestEnv(owner(1), admin(2))
+	meta := AuditMeta{ActorID: 1}
+
+	if _, err := env.svc.SetAdminUserRole(context.Background(), meta, 1, "ADMIN"); !errors.Is(err, ErrLastOwner) {
+		t.Fatalf("expected ErrLastOwner on demotion, got %v", err)
+	}
+	if _, err := env.svc.SetAdminUserActive(context.Background(), meta, 1, false); !errors.Is(err, ErrLastOwner) {
+		t.Fatalf("expected ErrLastOwner on deactivation, got %v", err)
+	}
+	if len(env.sessions.revokedFor) != 0 || len(env.audit.entries) != 0 {
+		t.Fatalf("a refused change must not revoke sessions or write audit: %v, %q", env.sessions.revokedFor, env.audit.actions())
+	}
+
+	// With a second active owner the first one can step down.
+	if _, err := env.svc.SetAdminUserRole(context.Background(), meta, 2, repo.RoleOwner); err != nil {
+		t.Fatalf("promote second owner: %v", err)
+	}
+	view, err := env.svc.SetAdminUserRole(context.Background(), meta, 1, "ADMIN")
+	if err != nil || view.Role != "ADMIN" {
+		t.Fatalf("expected demotion to succeed, got %+v, %v", view, err)
+	}
+}
+
+func TestDeactivateAndTOTPResetRevokeSessions(t *testing.T) {
+	env := newTestEnv(owner(1), admin(2), admin(3))
+	meta := AuditMeta{ActorID: 1, IP: "10.0.0.1", UserAgent: "test"}
+	env.sessions.add(2)
+	env.sessions.add(3)
+
+	view, err := env.svc.SetAdminUserActive(context.Background(), meta, 2, false)
+	if err != nil || view.IsActive {
+		t.Fatalf("deactivate: %+v, %v", view, err)
+	}
+	view, err = env.svc.ResetAdminUserTOTP(context.Background(), meta, 3)
+	if err != nil || view.TOTPEnabled {
+		t.Fatalf("reset 2fa: %+v, %v", view, err)
+	}
+
+	if got := env.sessions.revokedFor; len(got) != 2 || got[0] != 2 || got[1] != 3 {
+		t.Fatalf("expected sessions of admins 2 and 3 to be revoked, got %v", got)
+	}
+	for _, userID := range []int64{2, 3} {
+		if sessions, _ := env.sessions.ListActive(context.Background(), userID); len(sessions) != 0 {
+			t.Fatalf("admin %d still has %d sessions", userID, len(sessions))
+		}
+	}
+	if len(env.setupTokens.deletedFor) != 1 || len(env.recoveryCodes.deletedFor) != 1 {
+		t.Fatalf("2fa reset must drop setup tokens and recovery codes: %v, %v", env.setupTokens.deletedFor, env.recoveryCodes.deletedFor)
+	}
+
+	// Reactivation does not touch sessions.
+	if _, err := env.svc.SetAdminUserActive(context.Background(), meta, 2, true); err != nil {
+		t.Fatalf("reactivate: %v", err)
+	}
+	if len(env.sessions.revokedFor) != 2 {
+		t.Fatalf("reactivation must not revoke sessions, got %v", env.sessions.revokedFor)
+	}
+}
+
+func TestAdminUserChangesWriteAudit(t *testing.T) {
+	env := newTestEnv(owner(1), admin(2))
+	meta := AuditMeta{ActorID: 1, IP: "10.0.0.1", UserAgent: "test"}
+
+	if _, err := env.svc.SetAdminUserRole(context.Background(), meta, 2, repo.RoleOwner); err != nil {
+		t.Fatalf("set role: %v", err)
+	}
+	if _, err := env.svc.SetAdminUserRole(context.Background(), meta, 2, repo.RoleOwner); err != nil {
+		t.Fatalf("repeat set role: %v", err)
+	}
+	if _, err := env.svc.SetAdminUserActive(context.Background(), meta, 2, false); err != nil {
+		t.Fatalf("deactivate: %v", err)
+	}
+	if _, err := env.svc.ResetAdminUserTOTP(context.Background(), meta, 2); err != nil {
+		t.Fatalf("reset 2fa: %v", err)
+	}
+
+	want := strings.Join([]string{AuditAdminRoleChanged, AuditAdminDeactivated, AuditTOTPReset}, ",")
+	if got := env.audit.actions(); got != want {
+		t.Fatalf("expected audit %q, got %q", want, got)
+	}
+	first := env.audit.entries[0]
+	if first.ActorID != 1 || first.TargetID != 2 || first.IP != "10.0.0.1" || first.Details["to"] != repo.RoleOwner {
+		t.Fatalf("unexpected audit entry: %+v", first)
+	}
+}
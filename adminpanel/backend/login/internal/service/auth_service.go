@@ -22,15 +22,28 @@ var (
 	ErrChallengeStep     = errors.New("invalid challenge step")
 	ErrTOTPNotConfigured = errors.New("2fa is not configured")
 	ErrSessionExpired    = errors.New("session expired")
+	ErrNotFound          = errors.New("not found")
+	ErrConflict          = errors.New("already exists")
+	ErrLastOwner         = errors.New("last active owner")
+	// ErrPasswordResetRequired asks the client to repeat the password step with new_password.
+	ErrPasswordResetRequired = errors.New("password reset required")
 )
 
+const minPasswordLength = 12
+
 type Service struct {
-	users            *repo.AdminUserRepo
-	challenges       *repo.ChallengeRepo
-	setupTokens      *repo.TOTPSetupTokenRepo
-	sessions         *repo.SessionRepo
+	users            AdminUserStore
+	challenges       ChallengeStore
+	setupTokens      TOTPSetupTokenStore
+	sessions         SessionStore
+	audit            AuditStore
+	passkeys         PasskeyStore
+	recoveryCodes    RecoveryCodeStore
+	loginEvents      LoginEventStore
 	tokens           *security.TokenManager
 	secretCipher     *security.SecretCipher
+	webauthn         security.WebAuthn
+	alerts           Alerter
 	telegramBotToken string
 	telegramMaxAge   time.Duration
 	challengeTTL     time.Duration
@@ -53,11 +66,15 @@ type TelegramStartResult struct {
 	ChallengeID string `json:"challenge_id"`
 	NextStep    string `json:"next_step"`
 	Username    string `json:"username,omitempty"`
+	// Methods lists the second factors the admin can use: "totp" and/or "passkey".
+	Methods []string `json:"methods"`
 }
 
 type VerifyTOTPResult struct {
 	ChallengeID string `json:"challenge_id"`
 	NextStep    string `json:"next_step"`
+	// RecoveryCodesLeft is set when a recovery code was used instead of a TOTP code.
+	RecoveryCodesLeft *int `json:"recovery_codes_left,omitempty"`
 }
 
 type VerifyPasswordResult struct {
@@ -75,6 +92,10 @@ type AdminInfo struct {
 	Role        string `json:"role"`
 }
 
+type TOTPSetupConfirmResult struct {
+	RecoveryCodes []string `json:"recovery_codes"`
+}
+
 type TOTPSetupStartResult struct {
 	SetupID       string    `json:"setup_id"`
 	TelegramID    int64     `json:"telegram_id"`
@@ -85,12 +106,18 @@ type TOTPSetupStartResult struct {
 }
 
 func NewService(
-	users *repo.AdminUserRepo,
-	challenges *repo.ChallengeRepo,
-	setupTokens *repo.TOTPSetupTokenRepo,
-	sessions *repo.SessionRepo,
+	users AdminUserStore,
+	challenges ChallengeStore,
+	setupTokens TOTPSetupTokenStore,
+	sessions SessionStore,
+	audit AuditStore,
+	passkeys PasskeyStore,
+	recoveryCodes RecoveryCodeStore,
+	loginEvents LoginEventStore,
 	tokens *security.TokenManager,
 	secretCipher *security.SecretCipher,
+	webauthn security.WebAuthn,
+	alerts Alerter,
 	telegramBotToken string,
 	telegramMaxAge time.Duration,
 	challengeTTL time.Duration,
@@ -107,8 +134,14 @@ func NewService(
 		challenges:       challenges,
 		setupTokens:      setupTokens,
 		sessions:         sessions,
+		audit:            audit,
+		passkeys:         passkeys,
+		recoveryCodes:    recoveryCodes,
+		loginEvents:      loginEvents,
 		tokens:           tokens,
 		secretCipher:     secretCipher,
+		webauthn:         webauthn,
+		alerts:           alerts,
 		telegramBotToken: telegramBotToken,
 		telegramMaxAge:   telegramMaxAge,
 		challengeTTL:     challengeTTL,
@@ -141,7 +174,11 @@ func (s *Service) TelegramStart(ctx context.Context, in TelegramStartInput) (Tel
 	if isLocked(user.LockedUntil) {
 		return TelegramStartResult{}, ErrAccountLocked
 	}
-	if !user.TOTPEnabled || strings.TrimSpace(user.TOTPSecret) == "" {
+	methods, err := s.secondFactorMethods(ctx, user)
+	if err != nil {
+		return TelegramStartResult{}, err
+	}
+	if len(methods) == 0 {
 		return TelegramStartResult{}, ErrTOTPNotConfigured
 	}
 
@@ -159,10 +196,12 @@ func (s *Service) TelegramStart(ctx context.Context, in TelegramStartInput) (Tel
 		ChallengeID: challenge.ID.String(),
 		NextStep:    "2fa",
 		Username:    username,
+		Methods:     methods,
 	}, nil
 }
 
-func (s *Service) VerifyTOTP(ctx context.Context, challengeID, code string) (VerifyTOTPResult, error) {
+// VerifyTOTP accepts either a TOTP code or a one-time recovery code.
+func (s *Service) VerifyTOTP(ctx context.Context, challengeID, code string, meta AuditMeta) (VerifyTOTPResult, error) {
 	id, err := uuid.Parse(strings.TrimSpace(challengeID))
 	if err != nil {
 		return VerifyTOTPResult{}, ErrInvalidInput
@@ -191,16 +230,25 @@ func (s *Service) VerifyTOTP(ctx context.Context, challengeID, code string) (Ver
 	if !user.TOTPEnabled || strings.TrimSpace(user.TOTPSecret) == "" {
 		return VerifyTOTPResult{}, ErrTOTPNotConfigured
 	}
-	totpSecret, err := s.decryptTOTPSecret(user.TOTPSecret)
-	if err != nil {
-		return VerifyTOTPResult{}, fmt.Errorf("decrypt totp secret: %w", err)
-	}
 
-	if !security.ValidateTOTP(totpSecret, code, time.Now().UTC()) {
-		if err := s.applyFailure(ctx, user.ID); err != nil {
+	var recoveryCodesLeft *int
+	if recoveryCode := security.NormalizeRecoveryCode(code); recoveryCode != "" {
+		left, err := s.consumeRecoveryCode(ctx, user, recoveryCode, meta)
+		if err != nil {
+			return VerifyTOTPResult{}, err
+		}
+		recoveryCodesLeft = &left
+	} else {
+		ok, err := s.checkTOTP(user, code)
+		if err != nil {
 			return VerifyTOTPResult{}, err
 		}
-		return VerifyTOTPResult{}, ErrUnauthorized
+		if !ok {
+			if err := s.applyFailure(ctx, user, LoginStepTOTP, meta); err != nil {
+				return VerifyTOTPResult{}, err
+			}
+			return VerifyTOTPResult{}, ErrUnauthorized
+		}
 	}
 
 	if err := s.challenges.AdvanceStatus(ctx, challenge.ID, repo.ChallengeTelegramVerified, repo.ChallengeTOTPVerified); err != nil {
@@ -211,12 +259,15 @@ func (s *Service) VerifyTOTP(ctx context.Context, challengeID, code string) (Ver
 	}
 
 	return VerifyTOTPResult{
-		ChallengeID: challenge.ID.String(),
-		NextStep:    "password",
+		ChallengeID:       challenge.ID.String(),
+		NextStep:          "password",
+		RecoveryCodesLeft: recoveryCodesLeft,
 	}, nil
 }
 
-func (s *Service) VerifyPassword(ctx context.Context, challengeID, password string) (VerifyPasswordResult, error) {
+// VerifyPassword finishes sign-in. Accounts with a temporary password must send newPassword,
+// which replaces it before the session is issued.
+func (s *Service) VerifyPassword(ctx context.Context, challengeID, password, newPassword string, meta AuditMeta) (VerifyPasswordResult, error) {
 	id, err := uuid.Parse(strings.TrimSpace(challengeID))
 	if err != nil {
 		return VerifyPasswordResult{}, ErrInvalidInput
@@ -233,7 +284,7 @@ func (s *Service) VerifyPassword(ctx context.Context, challengeID, password stri
 		}
 		return VerifyPasswordResult{}, fmt.Errorf("get challenge: %w", err)
 	}
-	if challenge.Status != repo.ChallengeTOTPVerified {
+	if challenge.Status != repo.ChallengeTOTPVerified && challenge.Status != repo.ChallengePasskeyVerified {
 		return VerifyPasswordResult{}, ErrChallengeStep
 	}
 
@@ -249,12 +300,31 @@ func (s *Service) VerifyPassword(ctx context.Context, challengeID, password stri
 	}
 
 	if err := security.CheckPassword(user.PasswordHash, password); err != nil {
-		if err := s.applyFailure(ctx, user.ID); err != nil {
+		if err := s.applyFailure(ctx, user, LoginStepPassword, meta); err != nil {
 			return VerifyPasswordResult{}, err
 		}
 		return VerifyPasswordResult{}, ErrUnauthorized
 	}
 
+	if user.PasswordResetRequired {
+		newPassword = strings.TrimSpace(newPassword)
+		if newPassword == "" {
+			return VerifyPasswordResult{}, ErrPasswordResetRequired
+		}
+		if len(newPassword) < minPasswordLength || newPassword == password {
+			return VerifyPasswordResult{}, ErrInvalidInput
+		}
+		hash, err := security.HashPassword(newPassword)
+		if err != nil {
+			return VerifyPasswordResult{}, fmt.Errorf("hash password: %w", err)
+		}
+		if err := s.users.SetPassword(ctx, user.ID, hash); err != nil {
+			return VerifyPasswordResult{}, fmt.Errorf("set password: %w", err)
+		}
+		meta.ActorID = user.ID
+		s.writeAudit(ctx, meta, user.ID, AuditPasswordChanged, nil)
+	}
+
 	if err := s.users.ResetFailures(ctx, user.ID); err != nil {
 		return VerifyPasswordResult{}, fmt.Errorf("reset failures: %w", err)
 	}
@@ -262,16 +332,21 @@ func (s *Service) VerifyPassword(ctx context.Context, challengeID, password stri
 	now := time.Now().UTC()
 	sessionID := uuid.New()
 	sessionExpiresAt := now.Add(s.sessionMaxTTL)
-	if err := s.sessions.Create(ctx, sessionID, user.ID, sessionExpiresAt, s.sessionIdleTTL, "", ""); err != nil {
+	if err := s.sessions.Create(ctx, sessionID, user.ID, sessionExpiresAt, s.sessionIdleTTL, meta.IP, meta.UserAgent); err != nil {
 		return VerifyPasswordResult{}, fmt.Errorf("create session: %w", err)
 	}
+	secondFactor := LoginStepTOTP
+	if challenge.Status == repo.ChallengePasskeyVerified {
+		secondFactor = LoginStepPasskey
+	}
+	s.recordLoginSuccess(ctx, user, sessionID, secondFactor, meta)
 
 	token, expiresAt, err := s.tokens.Issue(user.ID, user.TelegramID, user.Role, user.Username, sessionID.String())
 	if err != nil {
 		return VerifyPasswordResult{}, fmt.Errorf("issue jwt: %w", err)
 	}
 
-	if err := s.challenges.AdvanceStatus(ctx, challenge.ID, repo.ChallengeTOTPVerified, repo.ChallengeCompleted); err != nil {
+	if err := s.challenges.AdvanceStatus(ctx, challenge.ID, challenge.Status, repo.ChallengeCompleted); err != nil {
 		if errors.Is(err, repo.ErrNotFound) {
 			return VerifyPasswordResult{}, ErrChallengeExpired
 		}
@@ -309,7 +384,10 @@ func (s *Service) StartTOTPSetup(ctx context.Context, telegramID int64, accountN
 	if !user.IsActive {
 		return TOTPSetupStartResult{}, ErrForbidden
 	}
+	return s.newTOTPSetup(ctx, user, accountName)
+}
 
+func (s *Service) newTOTPSetup(ctx context.Context, user repo.AdminUser, accountName string) (TOTPSetupStartResult, error) {
 	accountName = strings.TrimSpace(accountName)
 	if accountName == "" {
 		if user.Username != "" {
@@ -344,40 +422,56 @@ func (s *Service) StartTOTPSetup(ctx context.Context, telegramID int64, accountN
 	}, nil
 }
 
-func (s *Service) ConfirmTOTPSetup(ctx context.Context, setupID, code string) error {
-	id, err := uuid.Parse(strings.TrimSpace(setupID))
+// ConfirmTOTPSetup enables TOTP and issues a fresh set of recovery codes, shown to the admin only once.
+func (s *Service) ConfirmTOTPSetup(ctx context.Context, setupID, code string, meta AuditMeta) (TOTPSetupConfirmResult, error) {
+	token, err := s.getSetupToken(ctx, setupID)
+	if err != nil {
+		return TOTPSetupConfirmResult{}, err
+	}
+	codes, err := s.completeTOTPSetup(ctx, token, code)
 	if err != nil {
-		return ErrInvalidInput
+		return TOTPSetupConfirmResult{}, err
 	}
+	s.writeAudit(ctx, meta, token.AdminUserID, AuditTOTPEnabled, map[string]any{"bootstrap": meta.ActorID == 0})
+	return TOTPSetupConfirmResult{RecoveryCodes: codes}, nil
+}
 
+func (s *Service) getSetupToken(ctx context.Context, setupID string) (repo.TOTPSetupToken, error) {
+	id, err := uuid.Parse(strings.TrimSpace(setupID))
+	if err != nil {
+		return repo.TOTPSetupToken{}, ErrInvalidInput
+	}
 	token, err := s.setupTokens.Get(ctx, id)
 	if err != nil {
 		if errors.Is(err, repo.ErrNotFound) {
-			return ErrChallengeExpired
+			return repo.TOTPSetupToken{}, ErrChallengeExpired
 		}
-		return fmt.Errorf("get totp setup token: %w", err)
+		return repo.TOTPSetupToken{}, fmt.Errorf("get totp setup token: %w", err)
 	}
+	return token, nil
+}
 
+func (s *Service) completeTOTPSetup(ctx context.Context, token repo.TOTPSetupToken, code string) ([]string, error) {
 	if !security.ValidateTOTP(token.Secret, code, time.Now().UTC()) {
-		return ErrUnauthorized
+		return nil, ErrUnauthorized
 	}
 
 	encryptedSecret, err := s.secretCipher.Encrypt(token.Secret)
 	if err != nil {
-		return fmt.Errorf("encrypt totp secret: %w", err)
+		return nil, fmt.Errorf("encrypt totp secret: %w", err)
 	}
 
 	if err := s.users.EnableTOTP(ctx, token.AdminUserID, encryptedSecret); err != nil {
 		if errors.Is(err, repo.ErrNotFound) {
-			return ErrUnauthorized
+			return nil, ErrUnauthorized
 		}
-		return fmt.Errorf("enable totp: %w", err)
+		return nil, fmt.Errorf("enable totp: %w", err)
 	}
 
 	if err := s.setupTokens.Delete(ctx, token.ID); err != nil {
-		return fmt.Errorf("delete setup token: %w", err)
+		return nil, fmt.Errorf("delete setup token: %w", err)
 	}
-	return nil
+	return s.issueRecoveryCodes(ctx, token.AdminUserID)
 }
 
 func (s *Service) ValidateAccessToken(ctx context.Context, token string) (security.AdminClaims, error) {
@@ -420,11 +514,27 @@ func (s *Service) Logout(ctx context.Context, token string) error {
 	return nil
 }
 
-func (s *Service) applyFailure(ctx context.Context, userID int64) error {
-	locked, err := s.users.MarkFailure(ctx, userID, s.maxAttempts, time.Now().UTC().Add(s.lockDuration))
+// applyFailure counts a failed step towards the lockout, records it in the login history and alerts the admin
+// when failures pile up.
+func (s *Service) applyFailure(ctx context.Context, user repo.AdminUser, step string, meta AuditMeta) error {
+	attempts, locked, err := s.users.MarkFailure(ctx, user.ID, s.maxAttempts, time.Now().UTC().Add(s.lockDuration))
 	if err != nil {
 		return fmt.Errorf("mark login failure: %w", err)
 	}
+
+	event := repo.LoginEventFailure
+	if locked {
+		event = repo.LoginEventLocked
+	}
+	s.recordLoginEvent(ctx, repo.LoginEvent{
+		AdminUserID: user.ID,
+		Event:       event,
+		Step:        step,
+		IP:          meta.IP,
+		UserAgent:   meta.UserAgent,
+	})
+	s.alertLoginFailures(user, step, attempts, locked, meta)
+
 	if locked {
 		return ErrAccountLocked
 	}
@@ -435,6 +545,18 @@ func isLocked(lockedUntil *time.Time) bool {
 	return lockedUntil != nil && lockedUntil.After(time.Now().UTC())
 }
 
+// checkTOTP validates a code against the admin's current secret without touching failure counters.
+func (s *Service) checkTOTP(user repo.AdminUser, code string) (bool, error) {
+	if !user.TOTPEnabled || strings.TrimSpace(user.TOTPSecret) == "" {
+		return false, ErrTOTPNotConfigured
+	}
+	totpSecret, err := s.decryptTOTPSecret(user.TOTPSecret)
+	if err != nil {
+		return false, fmt.Errorf("decrypt totp secret: %w", err)
+	}
+	return security.ValidateTOTP(totpSecret, code, time.Now().UTC()), nil
+}
+
 func (s *Service) decryptTOTPSecret(stored string) (string, error) {
 	if s.secretCipher == nil {
 		return "", fmt.Errorf("secret cipher is not configured")
@@ -0,0 +1,460 @@
+package service
+
+import (
+	"context"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/ivankudzin/tgapp/adminpanel/backend/login/internal/repo"
+	"github.com/ivankudzin/tgapp/adminpanel/backend/login/internal/security"
+)
+
+const (
+	AuditPasskeyAdded   = "ADMIN_PASSKEY_ADDED"
+	AuditPasskeyRemoved = "ADMIN_PASSKEY_REMOVED"
+
+	passkeyCeremonyTimeout = 5 * time.Minute
+	maxPasskeyNameLength   = 64
+)
+
+var (
+	ErrPasskeysDisabled = errors.New("passkeys are not configured")
+	ErrPasskeyNotFound  = errors.New("passkey not found")
+	// ErrLastSecondFactor protects admins without TOTP from removing their only passkey.
+	ErrLastSecondFactor = errors.New("last second factor")
+)
+
+// PasskeyCredential is a PublicKeyCredential serialized by the browser with base64url fields.
+// Registration fills PublicKey/PublicKeyAlgorithm (getPublicKey(), getPublicKeyAlgorithm()) and Transports;
+// assertion fills Signature.
+type PasskeyCredential struct {
+	RawID              string   `json:"raw_id"`
+	ClientDataJSON     string   `json:"client_data_json"`
+	AuthenticatorData  string   `json:"authenticator_data"`
+	Signature          string   `json:"signature,omitempty"`
+	PublicKey          string   `json:"public_key,omitempty"`
+	PublicKeyAlgorithm int      `json:"public_key_algorithm,omitempty"`
+	Transports         []string `json:"transports,omitempty"`
+}
+
+type PasskeyDescriptor struct {
+	Type       string   `json:"type"`
+	ID         string   `json:"id"`
+	Transports []string `json:"transports,omitempty"`
+}
+
+type PasskeyAssertionOptions struct {
+	Challenge        string              `json:"challenge"`
+	Timeout          int64               `json:"timeout"`
+	RPID             string              `json:"rpId"`
+	AllowCredentials []PasskeyDescriptor `json:"allowCredentials"`
+	UserVerification string              `json:"userVerification"`
+}
+
+type PasskeyCreationOptions struct {
+	RP struct {
+		ID   string `json:"id"`
+		Name string `json:"name"`
+	} `json:"rp"`
+	User struct {
+		ID          string `json:"id"`
+		Name        string `json:"name"`
+		DisplayName string `json:"displayName"`
+	} `json:"user"`
+	Challenge          string              `json:"challenge"`
+	PubKeyCredParams   []map[string]any    `json:"pubKeyCredParams"`
+	Timeout            int64               `json:"timeout"`
+	ExcludeCredentials []PasskeyDescriptor `json:"excludeCredentials"`
+	AuthenticatorSel   map[string]string   `json:"authenticatorSelection"`
+	Attestation        string              `json:"attestation"`
+}
+
+type PasskeyRegistrationStart struct {
+	RegistrationID string                 `json:"registration_id"`
+	ExpiresAt      time.Time              `json:"expires_at"`
+	Options        PasskeyCreationOptions `json:"options"`
+}
+
+type PasskeyView struct {
+	ID         int64      `json:"id"`
+	Name       string     `json:"name"`
+	Algorithm  int        `json:"algorithm"`
+	Transports []string   `json:"transports,omitempty"`
+	CreatedAt  time.Time  `json:"created_at"`
+	LastUsedAt *time.Time `json:"last_used_at,omitempty"`
+}
+
+// StartPasskeyLogin issues a WebAuthn assertion challenge as the second factor of a login challenge.
+func (s *Service) StartPasskeyLogin(ctx context.Context, challengeID string) (PasskeyAssertionOptions, error) {
+	if !s.webauthn.Enabled() {
+		return PasskeyAssertionOptions{}, ErrPasskeysDisabled
+	}
+	challenge, err := s.loginChallengeAt(ctx, challengeID, repo.ChallengeTelegramVerified)
+	if err != nil {
+		return PasskeyAssertionOptions{}, err
+	}
+
+	passkeys, err := s.passkeys.ListByUser(ctx, challenge.AdminUserID)
+	if err != nil {
+		return PasskeyAssertionOptions{}, err
+	}
+	if len(passkeys) == 0 {
+		return PasskeyAssertionOptions{}, ErrTOTPNotConfigured
+	}
+
+	webauthnChallenge, err := security.NewWebAuthnChallenge()
+	if err != nil {
+		return PasskeyAssertionOptions{}, fmt.Errorf("generate webauthn challenge: %w", err)
+	}
+	if err := s.challenges.SetWebAuthnChallenge(ctx, challenge.ID, webauthnChallenge); err != nil {
+		if errors.Is(err, repo.ErrNotFound) {
+			return PasskeyAssertionOptions{}, ErrChallengeExpired
+		}
+		return PasskeyAssertionOptions{}, err
+	}
+
+	return PasskeyAssertionOptions{
+		Challenge:        security.EncodeBase64URL(webauthnChallenge),
+		Timeout:          passkeyCeremonyTimeout.Milliseconds(),
+		RPID:             s.webauthn.RPID,
+		AllowCredentials: passkeyDescriptors(passkeys),
+		UserVerification: "preferred",
+	}, nil
+}
+
+// VerifyPasskeyLogin accepts a passkey assertion in place of a TOTP code; failures count towards the lockout.
+func (s *Service) VerifyPasskeyLogin(ctx context.Context, challengeID string, credential PasskeyCredential, meta AuditMeta) (VerifyTOTPResult, error) {
+	if !s.webauthn.Enabled() {
+		return VerifyTOTPResult{}, ErrPasskeysDisabled
+	}
+	challenge, err := s.loginChallengeAt(ctx, challengeID, repo.ChallengeTelegramVerified)
+	if err != nil {
+		return VerifyTOTPResult{}, err
+	}
+	if len(challenge.WebAuthnChallenge) == 0 {
+		return VerifyTOTPResult{}, ErrChallengeStep
+	}
+
+	user, err := s.users.FindByID(ctx, challenge.AdminUserID)
+	if err != nil {
+		if errors.Is(err, repo.ErrNotFound) {
+			return VerifyTOTPResult{}, ErrUnauthorized
+		}
+		return VerifyTOTPResult{}, fmt.Errorf("find admin user: %w", err)
+	}
+	if isLocked(user.LockedUntil) {
+		return VerifyTOTPResult{}, ErrAccountLocked
+	}
+
+	raw, err := decodePasskeyCredential(credential, false)
+	if err != nil {
+		return VerifyTOTPResult{}, err
+	}
+	passkey, err := s.passkeys.FindByCredentialID(ctx, user.ID, raw.id)
+	if err != nil {
+		if !errors.Is(err, repo.ErrNotFound) {
+			return VerifyTOTPResult{}, err
+		}
+		if err := s.applyFailure(ctx, user, LoginStepPasskey, meta); err != nil {
+			return VerifyTOTPResult{}, err
+		}
+		return VerifyTOTPResult{}, ErrUnauthorized
+	}
+
+	signCount, err := s.webauthn.VerifyAssertion(
+		challenge.WebAuthnChallenge,
+		raw.clientDataJSON,
+		raw.authenticatorData,
+		raw.signature,
+		passkey.PublicKey,
+		passkey.Algorithm,
+		passkey.SignCount,
+	)
+	if err != nil {
+		if errors.Is(err, security.ErrWebAuthnSignCounter) {
+			meta.ActorID = user.ID
+			s.writeAudit(ctx, meta, user.ID, "ADMIN_PASSKEY_COUNTER_REGRESSION", map[string]any{"passkey_id": passkey.ID})
+		}
+		if err := s.applyFailure(ctx, user, LoginStepPasskey, meta); err != nil {
+			return VerifyTOTPResult{}, err
+		}
+		return VerifyTOTPResult{}, ErrUnauthorized
+	}
+	if err := s.passkeys.UpdateSignCount(ctx, passkey.ID, passkey.SignCount, signCount); err != nil {
+		if errors.Is(err, repo.ErrNotFound) {
+			return VerifyTOTPResult{}, ErrUnauthorized
+		}
+		return VerifyTOTPResult{}, err
+	}
+
+	if err := s.challenges.AdvanceStatus(ctx, challenge.ID, repo.ChallengeTelegramVerified, repo.ChallengePasskeyVerified); err != nil {
+		if errors.Is(err, repo.ErrNotFound) {
+			return VerifyTOTPResult{}, ErrChallengeExpired
+		}
+		return VerifyTOTPResult{}, fmt.Errorf("advance challenge step: %w", err)
+	}
+	return VerifyTOTPResult{
+		ChallengeID: challenge.ID.String(),
+		NextStep:    "password",
+	}, nil
+}
+
+func (s *Service) StartPasskeyRegistration(ctx context.Context, adminUserID int64) (PasskeyRegistrationStart, error) {
+	if !s.webauthn.Enabled() {
+		return PasskeyRegistrationStart{}, ErrPasskeysDisabled
+	}
+	user, err := s.findAdminUser(ctx, adminUserID)
+	if err != nil {
+		return PasskeyRegistrationStart{}, err
+	}
+	existing, err := s.passkeys.ListByUser(ctx, user.ID)
+	if err != nil {
+		return PasskeyRegistrationStart{}, err
+	}
+
+	challenge, err := security.NewWebAuthnChallenge()
+	if err != nil {
+		return PasskeyRegistrationStart{}, fmt.Errorf("generate webauthn challenge: %w", err)
+	}
+	registration, err := s.passkeys.CreateRegistration(ctx, user.ID, challenge, passkeyCeremonyTimeout)
+	if err != nil {
+		return PasskeyRegistrationStart{}, err
+	}
+
+	name := user.Username
+	if name == "" {
+		name = fmt.Sprintf("telegram_%d", user.TelegramID)
+	}
+	displayName := user.DisplayName
+	if displayName == "" {
+		displayName = name
+	}
+
+	options := PasskeyCreationOptions{
+		Challenge: security.EncodeBase64URL(challenge),
+		PubKeyCredParams: []map[string]any{
+			{"type": "public-key", "alg": security.COSEAlgES256},
+			{"type": "public-key", "alg": security.COSEAlgEdDSA},
+			{"type": "public-key", "alg": security.COSEAlgRS256},
+		},
+		Timeout:            passkeyCeremonyTimeout.Milliseconds(),
+		ExcludeCredentials: passkeyDescriptors(existing),
+		AuthenticatorSel: map[string]string{
+			"residentKey":      "preferred",
+			"userVerification": "preferred",
+		},
+		Attestation: "none",
+	}
+	options.RP.ID = s.webauthn.RPID
+	options.RP.Name = s.webauthn.RPName
+	options.User.ID = security.EncodeBase64URL(binary.BigEndian.AppendUint64(nil, uint64(user.ID)))
+	options.User.Name = name
+	options.User.DisplayName = displayName
+
+	return PasskeyRegistrationStart{
+		RegistrationID: registration.ID.String(),
+		ExpiresAt:      registration.ExpiresAt,
+		Options:        options,
+	}, nil
+}
+
+func (s *Service) FinishPasskeyRegistration(ctx context.Context, meta AuditMeta, registrationID string, name string, credential PasskeyCredential) (PasskeyView, error) {
+	if !s.webauthn.Enabled() {
+		return PasskeyView{}, ErrPasskeysDisabled
+	}
+	id, err := uuid.Parse(strings.TrimSpace(registrationID))
+	if err != nil {
+		return PasskeyView{}, ErrInvalidInput
+	}
+	name = strings.TrimSpace(name)
+	if len([]rune(name)) > maxPasskeyNameLength {
+		return PasskeyView{}, ErrInvalidInput
+	}
+
+	registration, err := s.passkeys.TakeRegistration(ctx, id, meta.ActorID)
+	if err != nil {
+		if errors.Is(err, repo.ErrNotFound) {
+			return PasskeyView{}, ErrChallengeExpired
+		}
+		return PasskeyView{}, err
+	}
+
+	raw, err := decodePasskeyCredential(credential, true)
+	if err != nil {
+		return PasskeyView{}, err
+	}
+	registered, err := s.webauthn.VerifyRegistration(
+		registration.Challenge,
+		raw.clientDataJSON,
+		raw.authenticatorData,
+		raw.publicKey,
+		credential.PublicKeyAlgorithm,
+	)
+	if err != nil {
+		return PasskeyView{}, fmt.Errorf("%w: %v", ErrInvalidInput, err)
+	}
+
+	if name == "" {
+		name = fmt.Sprintf("Passkey %s", time.Now().UTC().Format("2006-01-02"))
+	}
+	passkey, err := s.passkeys.Create(ctx, repo.Passkey{
+		AdminUserID:  registration.AdminUserID,
+		CredentialID: registered.ID,
+		PublicKey:    registered.PublicKey,
+		Algorithm:    registered.Algorithm,
+		SignCount:    registered.SignCount,
+		Transports:   credential.Transports,
+		Name:         name,
+	})
+	if err != nil {
+		if errors.Is(err, repo.ErrConflict) {
+			return PasskeyView{}, ErrConflict
+		}
+		return PasskeyView{}, err
+	}
+
+	s.writeAudit(ctx, meta, registration.AdminUserID, AuditPasskeyAdded, map[string]any{
+		"passkey_id": passkey.ID,
+		"name":       passkey.Name,
+	})
+	return toPasskeyView(passkey), nil
+}
+
+func (s *Service) ListPasskeys(ctx context.Context, adminUserID int64) ([]PasskeyView, error) {
+	passkeys, err := s.passkeys.ListByUser(ctx, adminUserID)
+	if err != nil {
+		return nil, err
+	}
+	out := make([]PasskeyView, 0, len(passkeys))
+	for _, passkey := range passkeys {
+		out = append(out, toPasskeyView(passkey))
+	}
+	return out, nil
+}
+
+func (s *Service) DeletePasskey(ctx context.Context, meta AuditMeta, passkeyID int64) error {
+	user, err := s.findAdminUser(ctx, meta.ActorID)
+	if err != nil {
+		return err
+	}
+	if !user.TOTPEnabled {
+		count, err := s.passkeys.CountByUser(ctx, user.ID)
+		if err != nil {
+			return err
+		}
+		if count <= 1 {
+			return ErrLastSecondFactor
+		}
+	}
+
+	if err := s.passkeys.Delete(ctx, passkeyID, user.ID); err != nil {
+		if errors.Is(err, repo.ErrNotFound) {
+			return ErrPasskeyNotFound
+		}
+		return err
+	}
+	s.writeAudit(ctx, meta, user.ID, AuditPasskeyRemoved, map[string]any{"passkey_id": passkeyID})
+	return nil
+}
+
+func (s *Service) secondFactorMethods(ctx context.Context, user repo.AdminUser) ([]string, error) {
+	methods := make([]string, 0, 2)
+	if user.TOTPEnabled && strings.TrimSpace(user.TOTPSecret) != "" {
+		methods = append(methods, "totp")
+	}
+	if s.webauthn.Enabled() && s.passkeys != nil {
+		count, err := s.passkeys.CountByUser(ctx, user.ID)
+		if err != nil {
+			return nil, err
+		}
+		if count > 0 {
+			methods = append(methods, "passkey")
+		}
+	}
+	return methods, nil
+}
+
+func (s *Service) loginChallengeAt(ctx context.Context, challengeID string, status repo.ChallengeStatus) (repo.LoginChallenge, error) {
+	id, err := uuid.Parse(strings.TrimSpace(challengeID))
+	if err != nil {
+		return repo.LoginChallenge{}, ErrInvalidInput
+	}
+	challenge, err := s.challenges.GetActive(ctx, id)
+	if err != nil {
+		if errors.Is(err, repo.ErrNotFound) {
+			return repo.LoginChallenge{}, ErrChallengeExpired
+		}
+		return repo.LoginChallenge{}, fmt.Errorf("get challenge: %w", err)
+	}
+	if challenge.Status != status {
+		return repo.LoginChallenge{}, ErrChallengeStep
+	}
+	return challenge, nil
+}
+
+type decodedPasskeyCredential struct {
+	id                []byte
+	clientDataJSON    []byte
+	authenticatorData []byte
+	signature         []byte
+	publicKey         []byte
+}
+
+func decodePasskeyCredential(credential PasskeyCredential, registration bool) (decodedPasskeyCredential, error) {
+	var out decodedPasskeyCredential
+	fields := []struct {
+		value string
+		dst   *[]byte
+	}{
+		{credential.RawID, &out.id},
+		{credential.ClientDataJSON, &out.clientDataJSON},
+		{credential.AuthenticatorData, &out.authenticatorData},
+	}
+	if registration {
+		fields = append(fields, struct {
+			value string
+			dst   *[]byte
+		}{credential.PublicKey, &out.publicKey})
+	} else {
+		fields = append(fields, struct {
+			value string
+			dst   *[]byte
+		}{credential.Signature, &out.signature})
+	}
+	for _, field := range fields {
+		decoded, err := security.DecodeBase64URL(field.value)
+		if err != nil || len(decoded) == 0 {
+			return decodedPasskeyCredential{}, ErrInvalidInput
+		}
+		*field.dst = decoded
+	}
+	return out, nil
+}
+
+func passkeyDescriptors(passkeys []repo.Passkey) []PasskeyDescriptor {
+	out := make([]PasskeyDescriptor, 0, len(passkeys))
+	for _, passkey := range passkeys {
+		out = append(out, PasskeyDescriptor{
+			Type:       "public-key",
+			ID:         security.EncodeBase64URL(passkey.CredentialID),
+			Transports: passkey.Transports,
+		})
+	}
+	return out
+}
+
+func toPasskeyView(passkey repo.Passkey) PasskeyView {
+	return PasskeyView{
+		ID:         passkey.ID,
+		Name:       passkey.Name,
+		Algorithm:  passkey.Algorithm,
+		Transports: passkey.Transports,
+		CreatedAt:  passkey.CreatedAt,
+		LastUsedAt: passkey.LastUsedAt,
+	}
+}
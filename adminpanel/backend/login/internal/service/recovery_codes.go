@@ -0,0 +1,139 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/ivankudzin/tgapp/adminpanel/backend/login/internal/repo"
+	"github.com/ivankudzin/tgapp/adminpanel/backend/login/internal/security"
+)
+
+const (
+	AuditRecoveryCodeUsed         = "ADMIN_RECOVERY_CODE_USED"
+	AuditRecoveryCodesRegenerated = "ADMIN_RECOVERY_CODES_REGENERATED"
+	AuditTOTPRotated              = "ADMIN_2FA_ROTATED"
+)
+
+// ErrInvalidCode is returned to signed-in admins who fail to re-confirm their current TOTP code.
+var ErrInvalidCode = errors.New("invalid 2fa code")
+
+type RecoveryCodesStatus struct {
+	Remaining int `json:"remaining"`
+}
+
+// StartTOTPRotation re-verifies the current TOTP code and opens a setup for a new secret.
+// The old secret keeps working until ConfirmTOTPRotation succeeds.
+func (s *Service) StartTOTPRotation(ctx context.Context, meta AuditMeta, code string) (TOTPSetupStartResult, error) {
+	user, err := s.reverifyTOTP(ctx, meta, code)
+	if err != nil {
+		return TOTPSetupStartResult{}, err
+	}
+	return s.newTOTPSetup(ctx, user, "")
+}
+
+func (s *Service) ConfirmTOTPRotation(ctx context.Context, meta AuditMeta, setupID, code string) (TOTPSetupConfirmResult, error) {
+	token, err := s.getSetupToken(ctx, setupID)
+	if err != nil {
+		return TOTPSetupConfirmResult{}, err
+	}
+	if token.AdminUserID != meta.ActorID {
+		return TOTPSetupConfirmResult{}, ErrChallengeExpired
+	}
+	codes, err := s.completeTOTPSetup(ctx, token, code)
+	if err != nil {
+		if errors.Is(err, ErrUnauthorized) {
+			return TOTPSetupConfirmResult{}, ErrInvalidCode
+		}
+		return TOTPSetupConfirmResult{}, err
+	}
+	s.writeAudit(ctx, meta, meta.ActorID, AuditTOTPRotated, nil)
+	return TOTPSetupConfirmResult{RecoveryCodes: codes}, nil
+}
+
+// RegenerateRecoveryCodes invalidates every previous code, used or not.
+func (s *Service) RegenerateRecoveryCodes(ctx context.Context, meta AuditMeta, code string) (TOTPSetupConfirmResult, error) {
+	if _, err := s.reverifyTOTP(ctx, meta, code); err != nil {
+		return TOTPSetupConfirmResult{}, err
+	}
+	codes, err := s.issueRecoveryCodes(ctx, meta.ActorID)
+	if err != nil {
+		return TOTPSetupConfirmResult{}, err
+	}
+	s.writeAudit(ctx, meta, meta.ActorID, AuditRecoveryCodesRegenerated, nil)
+	return TOTPSetupConfirmResult{RecoveryCodes: codes}, nil
+}
+
+func (s *Service) RecoveryCodesStatus(ctx context.Context, adminUserID int64) (RecoveryCodesStatus, error) {
+	remaining, err := s.recoveryCodes.CountUnused(ctx, adminUserID)
+	if err != nil {
+		return RecoveryCodesStatus{}, err
+	}
+	return RecoveryCodesStatus{Remaining: remaining}, nil
+}
+
+// reverifyTOTP checks the current code of a signed-in admin; misses count towards the lockout like at sign-in.
+func (s *Service) reverifyTOTP(ctx context.Context, meta AuditMeta, code string) (repo.AdminUser, error) {
+	user, err := s.findAdminUser(ctx, meta.ActorID)
+	if err != nil {
+		return repo.AdminUser{}, err
+	}
+	if isLocked(user.LockedUntil) {
+		return repo.AdminUser{}, ErrAccountLocked
+	}
+	ok, err := s.checkTOTP(user, code)
+	if err != nil {
+		return repo.AdminUser{}, err
+	}
+	if !ok {
+		if err := s.applyFailure(ctx, user, LoginStepReverify, meta); err != nil {
+			return repo.AdminUser{}, err
+		}
+		return repo.AdminUser{}, ErrInvalidCode
+	}
+	return user, nil
+}
+
+func (s *Service) issueRecoveryCodes(ctx context.Context, adminUserID int64) ([]string, error) {
+	codes, err := security.GenerateRecoveryCodes(security.RecoveryCodeCount)
+	if err != nil {
+		return nil, fmt.Errorf("generate recovery codes: %w", err)
+	}
+	hashes := make([]string, 0, len(codes))
+	for _, code := range codes {
+		hashes = append(hashes, security.HashRecoveryCode(security.NormalizeRecoveryCode(code)))
+	}
+	if err := s.recoveryCodes.Replace(ctx, adminUserID, hashes); err != nil {
+		return nil, err
+	}
+	return codes, nil
+}
+
+// consumeRecoveryCode burns a recovery code for the login and alerts the admin in Telegram.
+func (s *Service) consumeRecoveryCode(ctx context.Context, user repo.AdminUser, normalized string, meta AuditMeta) (int, error) {
+	if err := s.recoveryCodes.Consume(ctx, user.ID, security.HashRecoveryCode(normalized), meta.IP); err != nil {
+		if !errors.Is(err, repo.ErrNotFound) {
+			return 0, err
+		}
+		if err := s.applyFailure(ctx, user, LoginStepRecoveryCode, meta); err != nil {
+			return 0, err
+		}
+		return 0, ErrUnauthorized
+	}
+
+	left, err := s.recoveryCodes.CountUnused(ctx, user.ID)
+	if err != nil {
+		return 0, err
+	}
+	meta.ActorID = user.ID
+	s.writeAudit(ctx, meta, user.ID, AuditRecoveryCodeUsed, map[string]any{"remaining": left})
+
+	s.alerts.SendAsync(user.TelegramID, fmt.Sprintf(
+		"Вход в админку по резервному коду.\nВремя: %s\nIP: %s\nОсталось кодов: %d\n\nЕсли это были не вы, срочно свяжитесь с владельцем.",
+		time.Now().UTC().Format("2006-01-02 15:04 MST"),
+		orUnknown(meta.IP),
+		left,
+	))
+	return left, nil
+}
@@ -0,0 +1,206 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/ivankudzin/tgapp/adminpanel/backend/login/internal/repo"
+)
+
+const (
+	LoginStepTOTP         = "totp"
+	LoginStepRecoveryCode = "recovery_code"
+	LoginStepPasskey      = "passkey"
+	LoginStepPassword     = "password"
+	LoginStepReverify     = "reverify"
+
+	AuditSessionRevoked     = "ADMIN_SESSION_REVOKED"
+	AuditSessionsRevokedAll = "ADMIN_SESSIONS_REVOKED_ALL"
+
+	defaultLoginHistoryLimit = 50
+	maxLoginHistoryLimit     = 200
+)
+
+var ErrSessionNotFound = errors.New("session not found")
+
+type SessionView struct {
+	ID            string    `json:"id"`
+	IP            string    `json:"ip,omitempty"`
+	UserAgent     string    `json:"user_agent,omitempty"`
+	CreatedAt     time.Time `json:"created_at"`
+	LastSeenAt    time.Time `json:"last_seen_at"`
+	IdleExpiresAt time.Time `json:"idle_expires_at"`
+	ExpiresAt     time.Time `json:"expires_at"`
+	Current       bool      `json:"current"`
+}
+
+type LoginEventView struct {
+	Event     string    `json:"event"`
+	Step      string    `json:"step"`
+	SessionID string    `json:"session_id,omitempty"`
+	IP        string    `json:"ip,omitempty"`
+	UserAgent string    `json:"user_agent,omitempty"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// ListSessions returns the live sessions of an admin; currentSessionID marks the caller's own session.
+func (s *Service) ListSessions(ctx context.Context, adminUserID int64, currentSessionID string) ([]SessionView, error) {
+	sessions, err := s.sessions.ListActive(ctx, adminUserID)
+	if err != nil {
+		return nil, err
+	}
+	out := make([]SessionView, 0, len(sessions))
+	for _, session := range sessions {
+		out = append(out, SessionView{
+			ID:            session.ID.String(),
+			IP:            session.IP,
+			UserAgent:     session.UserAgent,
+			CreatedAt:     session.CreatedAt,
+			LastSeenAt:    session.LastSeenAt,
+			IdleExpiresAt: session.IdleExpiresAt,
+			ExpiresAt:     session.ExpiresAt,
+			Current:       session.ID.String() == currentSessionID,
+		})
+	}
+	return out, nil
+}
+
+// RevokeSession ends one session of adminUserID; meta.ActorID is the admin themselves or an OWNER.
+func (s *Service) RevokeSession(ctx context.Context, meta AuditMeta, adminUserID int64, sessionID string) error {
+	id, err := uuid.Parse(strings.TrimSpace(sessionID))
+	if err != nil {
+		return ErrInvalidInput
+	}
+	if err := s.sessions.Revoke(ctx, id, adminUserID); err != nil {
+		if errors.Is(err, repo.ErrNotFound) {
+			return ErrSessionNotFound
+		}
+		return err
+	}
+	s.writeAudit(ctx, meta, adminUserID, AuditSessionRevoked, map[string]any{"session_id": id.String()})
+	return nil
+}
+
+func (s *Service) RevokeAllSessions(ctx context.Context, meta AuditMeta, adminUserID int64) error {
+	if _, err := s.findAdminUser(ctx, adminUserID); err != nil {
+		return err
+	}
+	if err := s.sessions.RevokeAllForUser(ctx, adminUserID); err != nil {
+		return err
+	}
+	s.writeAudit(ctx, meta, adminUserID, AuditSessionsRevokedAll, nil)
+	return nil
+}
+
+func (s *Service) LoginHistory(ctx context.Context, adminUserID int64, limit int) ([]LoginEventView, error) {
+	if limit <= 0 {
+		limit = defaultLoginHistoryLimit
+	}
+	if limit > maxLoginHistoryLimit {
+		limit = maxLoginHistoryLimit
+	}
+	events, err := s.loginEvents.ListByUser(ctx, adminUserID, limit)
+	if err != nil {
+		return nil, err
+	}
+	out := make([]LoginEventView, 0, len(events))
+	for _, event := range events {
+		view := LoginEventView{
+			Event:     event.Event,
+			Step:      event.Step,
+			IP:        event.IP,
+			UserAgent: event.UserAgent,
+			CreatedAt: event.CreatedAt,
+		}
+		if event.SessionID != nil {
+			view.SessionID = event.SessionID.String()
+		}
+		out = append(out, view)
+	}
+	return out, nil
+}
+
+// recordLoginSuccess stores the login and alerts the admin if it came from an IP or user agent
+// never seen in a previous successful login. The very first login is not alerted.
+func (s *Service) recordLoginSuccess(ctx context.Context, user repo.AdminUser, sessionID uuid.UUID, step string, meta AuditMeta) {
+	if s.loginEvents == nil {
+		return
+	}
+	known, err := s.loginEvents.KnownSource(ctx, user.ID, meta.IP, meta.UserAgent)
+	if err != nil {
+		log.Printf("check login source for admin %d: %v", user.ID, err)
+	}
+	s.recordLoginEvent(ctx, repo.LoginEvent{
+		AdminUserID: user.ID,
+		Event:       repo.LoginEventSuccess,
+		Step:        step,
+		SessionID:   &sessionID,
+		IP:          meta.IP,
+		UserAgent:   meta.UserAgent,
+	})
+	if err != nil || !known.HasHistory || (known.KnownIP && known.KnownAgent) {
+		return
+	}
+
+	var changed []string
+	if !known.KnownIP {
+		changed = append(changed, "новый IP")
+	}
+	if !known.KnownAgent {
+		changed = append(changed, "новое устройство или браузер")
+	}
+	s.alerts.SendAsync(user.TelegramID, fmt.Sprintf(
+		"Вход в админку: %s.\nВремя: %s\nIP: %s\nUser-Agent: %s\n\nЕсли это были не вы, завершите сессию в разделе «Сессии» и свяжитесь с владельцем.",
+		strings.Join(changed, ", "),
+		time.Now().UTC().Format("2006-01-02 15:04 MST"),
+		orUnknown(meta.IP),
+		orUnknown(meta.UserAgent),
+	))
+}
+
+// alertLoginFailures warns the admin one attempt before the lockout and once more when it happens.
+func (s *Service) alertLoginFailures(user repo.AdminUser, step string, attempts int, locked bool, meta AuditMeta) {
+	var text string
+	switch {
+	case locked:
+		text = fmt.Sprintf(
+			"Аккаунт админки заблокирован на %s после %d неудачных попыток входа (шаг: %s).\nПоследний IP: %s",
+			s.lockDuration,
+			attempts,
+			step,
+			orUnknown(meta.IP),
+		)
+	case attempts >= 2 && attempts == s.maxAttempts-1:
+		text = fmt.Sprintf(
+			"%d неудачных попыток входа в админку подряд (шаг: %s).\nIP: %s\nЕщё одна ошибка — и аккаунт будет заблокирован.",
+			attempts,
+			step,
+			orUnknown(meta.IP),
+		)
+	default:
+		return
+	}
+	s.alerts.SendAsync(user.TelegramID, text)
+}
+
+func (s *Service) recordLoginEvent(ctx context.Context, event repo.LoginEvent) {
+	if s.loginEvents == nil {
+		return
+	}
+	if err := s.loginEvents.Insert(ctx, event); err != nil {
+		log.Printf("record login event %s for admin %d: %v", event.Event, event.AdminUserID, err)
+	}
+}
+
+func orUnknown(value string) string {
+	if strings.TrimSpace(value) == "" {
+		return "неизвестно"
+	}
+	return value
+}
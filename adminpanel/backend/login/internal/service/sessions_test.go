@@ -0,0 +1,181 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/ivankudzin/tgapp/adminpanel/backend/login/internal/repo"
+)
+
+type fakeLoginEvents struct {
+	events    []repo.LoginEvent
+	known     repo.KnownLoginSource
+	knownErr  error
+	lastLimit int
+}
+
+func (f *fakeLoginEvents) Insert(_ context.Context, event repo.LoginEvent) error {
+	f.events = append(f.events, event)
+	return nil
+}
+
+func (f *fakeLoginEvents) ListByUser(_ context.Context, adminUserID int64, limit int) ([]repo.LoginEvent, error) {
+	f.lastLimit = limit
+	var out []repo.LoginEvent
+	for i := len(f.events) - 1; i >= 0 && len(out) < limit; i-- {
+		if f.events[i].AdminUserID == adminUserID {
+			out = append(out, f.events[i])
+		}
+	}
+	return out, nil
+}
+
+func (f *fakeLoginEvents) KnownSource(context.Context, int64, string, string) (repo.KnownLoginSource, error) {
+	return f.known, f.knownErr
+}
+
+type fakeAlerter struct {
+	sent map[int64][]string
+}
+
+func (f *fakeAlerter) SendAsync(chatID int64, text string) {
+	if f.sent == nil {
+		f.sent = make(map[int64][]string)
+	}
+	f.sent[chatID] = append(f.sent[chatID], text)
+}
+
+func TestRevokeSessionOwnAndOther(t *testing.T) {
+	env := newTestEnv(owner(1), admin(2), admin(3))
+	own := env.sessions.add(2)
+	other := env.sessions.add(3)
+
+	// An admin's own route passes their id, so another admin's session is not found.
+	err := env.svc.RevokeSession(context.Background(), AuditMeta{ActorID: 2}, 2, other.String())
+	if !errors.Is(err, ErrSessionNotFound) {
+		t.Fatalf("expected ErrSessionNotFound for someone else's session, got %v", err)
+	}
+	if _, ok := env.sessions.active[other]; !ok {
+		t.Fatalf("another admin's session must survive")
+	}
+
+	if err := env.svc.RevokeSession(context.Background(), AuditMeta{ActorID: 2}, 2, own.String()); err != nil {
+		t.Fatalf("revoke own session: %v", err)
+	}
+	// An OWNER revokes another admin's session through the admin route.
+	if err := env.svc.RevokeSession(context.Background(), AuditMeta{ActorID: 1}, 3, other.String()); err != nil {
+		t.Fatalf("owner revokes admin session: %v", err)
+	}
+	if len(env.sessions.active) != 0 {
+		t.Fatalf("expected both sessions to be revoked, %d left", len(env.sessions.active))
+	}
+
+	if len(env.audit.entries) != 2 {
+		t.Fatalf("expected two audit entries, got %q", env.audit.actions())
+	}
+	self, byOwner := env.audit.entries[0], env.audit.entries[1]
+	if self.ActorID != 2 || self.TargetID != 2 || self.Details["session_id"] != own.String() {
+		t.Fatalf("unexpected own revoke audit: %+v", self)
+	}
+	if byOwner.ActorID != 1 || byOwner.TargetID != 3 || byOwner.Action != AuditSessionRevoked {
+		t.Fatalf("unexpected owner revoke audit: %+v", byOwner)
+	}
+
+	if err := env.svc.RevokeSession(context.Background(), AuditMeta{ActorID: 2}, 2, "not-a-uuid"); !errors.Is(err, ErrInvalidInput) {
+		t.Fatalf("expected ErrInvalidInput for a malformed id, got %v", err)
+	}
+}
+
+func TestLoginSuccessAlertsOnNewSource(t *testing.T) {
+	user := admin(2)
+	meta := AuditMeta{ActorID: 2, IP: "10.0.0.1", UserAgent: "Firefox"}
+
+	cases := []struct {
+		name     string
+		known    repo.KnownLoginSource
+		knownErr error
+		want     []string
+	}{
+		{name: "first login", known: repo.KnownLoginSource{}},
+		{name: "known ip and agent", known: repo.KnownLoginSource{HasHistory: true, KnownIP: true, KnownAgent: true}},
+		{name: "lookup failed", knownErr: errors.New("db down")},
+		{name: "new ip", known: repo.KnownLoginSource{HasHistory: true, KnownAgent: true}, want: []string{"новый IP"}},
+		{name: "new agent", known: repo.KnownLoginSource{HasHistory: true, KnownIP: true}, want: []string{"новое устройство"}},
+		{name: "new ip and agent", known: repo.KnownLoginSource{HasHistory: true}, want: []string{"новый IP", "новое устройство"}},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			env := newTestEnv(user)
+			events := &fakeLoginEvents{known: tc.known, knownErr: tc.knownErr}
+			alerts := &fakeAlerter{}
+			env.svc.loginEvents = events
+			env.svc.alerts = alerts
+
+			env.svc.recordLoginSuccess(context.Background(), user, uuid.New(), LoginStepPassword, meta)
+
+			if len(events.events) != 1 || events.events[0].Event != repo.LoginEventSuccess {
+				t.Fatalf("the login must be recorded either way, got %+v", events.events)
+			}
+			sent := alerts.sent[user.TelegramID]
+			if len(tc.want) == 0 {
+				if len(sent) != 0 {
+					t.Fatalf("expected no alert, got %q", sent)
+				}
+				return
+			}
+			if len(sent) != 1 {
+				t.Fatalf("expected one alert, got %q", sent)
+			}
+			for _, fragment := range tc.want {
+				if !strings.Contains(sent[0], fragment) {
+					t.Fatalf("alert %q does not mention %q", sent[0], fragment)
+				}
+			}
+			if !strings.Contains(sent[0], meta.IP) || !strings.Contains(sent[0], meta.UserAgent) {
+				t.Fatalf("alert must include the source: %q", sent[0])
+			}
+		})
+	}
+}
+
+func TestLoginHistoryLimit(t *testing.T) {
+	env := newTestEnv(admin(2), admin(3))
+	events := &fakeLoginEvents{}
+	env.svc.loginEvents = events
+
+	sessionID := uuid.New()
+	start := time.Date(2026, 10, 19, 9, 0, 0, 0, time.UTC)
+	for i := 0; i < 250; i++ {
+		event := repo.LoginEvent{AdminUserID: 2, Event: repo.LoginEventSuccess, Step: LoginStepPassword, CreatedAt: start.Add(time.Duration(i) * time.Minute)}
+		if i == 249 {
+			event.SessionID = &sessionID
+		}
+		events.events = append(events.events, event)
+	}
+	events.events = append(events.events, repo.LoginEvent{AdminUserID: 3, Event: repo.LoginEventSuccess})
+
+	for _, tc := range []struct {
+		limit, want int
+	}{
+		{limit: 0, want: defaultLoginHistoryLimit},
+		{limit: -5, want: defaultLoginHistoryLimit},
+		{limit: 10, want: 10},
+		{limit: 1000, want: maxLoginHistoryLimit},
+	} {
+		history, err := env.svc.LoginHistory(context.Background(), 2, tc.limit)
+		if err != nil {
+			t.Fatalf("login history: %v", err)
+		}
+		if events.lastLimit != tc.want || len(history) != tc.want {
+			t.Fatalf("limit %d: expected %d events, asked for %d and got %d", tc.limit, tc.want, events.lastLimit, len(history))
+		}
+		if history[0].SessionID != sessionID.String() || !history[0].CreatedAt.After(history[len(history)-1].CreatedAt) {
+			t.Fatalf("limit %d: expected newest first with the session id, got %+v", tc.limit, history[0])
+		}
+	}
+}
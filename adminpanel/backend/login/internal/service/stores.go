@@ -0,0 +1,85 @@
+package service
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/ivankudzin/tgapp/adminpanel/backend/login/internal/repo"
+)
+
+// The stores below are implemented by the postgres repos in internal/repo.
+
+type AdminUserStore interface {
+	FindByTelegramID(ctx context.Context, telegramID int64) (repo.AdminUser, error)
+	FindByID(ctx context.Context, userID int64) (repo.AdminUser, error)
+	List(ctx context.Context) ([]repo.AdminUser, error)
+	HasOwner(ctx context.Context) (bool, error)
+	RoleExists(ctx context.Context, role string) (bool, error)
+	Create(ctx context.Context, user repo.AdminUser, invitedBy int64) (repo.AdminUser, error)
+	SetRole(ctx context.Context, userID int64, role string) error
+	SetActive(ctx context.Context, userID int64, active bool) error
+	RequirePasswordReset(ctx context.Context, userID int64, passwordHash string) error
+	SetPassword(ctx context.Context, userID int64, passwordHash string) error
+	DisableTOTP(ctx context.Context, userID int64) error
+	ResetFailures(ctx context.Context, userID int64) error
+	MarkFailure(ctx context.Context, userID int64, maxAttempts int, lockUntil time.Time) (int, bool, error)
+	EnableTOTP(ctx context.Context, userID int64, secret string) error
+}
+
+type ChallengeStore interface {
+	Create(ctx context.Context, adminUserID int64, ttl time.Duration, ip, userAgent string) (repo.LoginChallenge, error)
+	GetActive(ctx context.Context, id uuid.UUID) (repo.LoginChallenge, error)
+	AdvanceStatus(ctx context.Context, id uuid.UUID, from, to repo.ChallengeStatus) error
+	SetWebAuthnChallenge(ctx context.Context, id uuid.UUID, challenge []byte) error
+	Expire(ctx context.Context, id uuid.UUID) error
+}
+
+type TOTPSetupTokenStore interface {
+	Create(ctx context.Context, adminUserID int64, secret string, ttl time.Duration) (repo.TOTPSetupToken, error)
+	Get(ctx context.Context, id uuid.UUID) (repo.TOTPSetupToken, error)
+	Delete(ctx context.Context, id uuid.UUID) error
+	DeleteForUser(ctx context.Context, adminUserID int64) error
+}
+
+type SessionStore interface {
+	Create(ctx context.Context, sessionID uuid.UUID, adminUserID int64, expiresAt time.Time, idleTimeout time.Duration, ip, userAgent string) error
+	Touch(ctx context.Context, sessionID uuid.UUID, adminUserID int64, idleTimeout time.Duration) error
+	Revoke(ctx context.Context, sessionID uuid.UUID, adminUserID int64) error
+	ListActive(ctx context.Context, adminUserID int64) ([]repo.AdminSession, error)
+	RevokeAllForUser(ctx context.Context, adminUserID int64) error
+}
+
+type AuditStore interface {
+	Insert(ctx context.Context, entry repo.AdminAuditEntry) error
+}
+
+type PasskeyStore interface {
+	Create(ctx context.Context, passkey repo.Passkey) (repo.Passkey, error)
+	ListByUser(ctx context.Context, adminUserID int64) ([]repo.Passkey, error)
+	FindByCredentialID(ctx context.Context, adminUserID int64, credentialID []byte) (repo.Passkey, error)
+	UpdateSignCount(ctx context.Context, id int64, previous, next uint32) error
+	Delete(ctx context.Context, id int64, adminUserID int64) error
+	CountByUser(ctx context.Context, adminUserID int64) (int, error)
+	CreateRegistration(ctx context.Context, adminUserID int64, challenge []byte, ttl time.Duration) (repo.PasskeyRegistration, error)
+	TakeRegistration(ctx context.Context, id uuid.UUID, adminUserID int64) (repo.PasskeyRegistration, error)
+}
+
+type RecoveryCodeStore interface {
+	Replace(ctx context.Context, adminUserID int64, hashes []string) error
+	Consume(ctx context.Context, adminUserID int64, hash, ip string) error
+	CountUnused(ctx context.Context, adminUserID int64) (int, error)
+	DeleteForUser(ctx context.Context, adminUserID int64) error
+}
+
+type LoginEventStore interface {
+	Insert(ctx context.Context, event repo.LoginEvent) error
+	ListByUser(ctx context.Context, adminUserID int64, limit int) ([]repo.LoginEvent, error)
+	KnownSource(ctx context.Context, adminUserID int64, ip, userAgent string) (repo.KnownLoginSource, error)
+}
+
+// Alerter delivers security alerts to an admin's Telegram chat.
+type Alerter interface {
+	SendAsync(chatID int64, text string)
+}
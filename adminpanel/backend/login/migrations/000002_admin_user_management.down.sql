@@ -0,0 +1,5 @@
+DROP TABLE IF EXISTS admin_user_audit;
+
+ALTER TABLE admin_users
+    DROP COLUMN IF EXISTS invited_by,
+    DROP COLUMN IF EXISTS password_reset_required;
@@ -0,0 +1,17 @@
+ALTER TABLE admin_users
+    ADD COLUMN IF NOT EXISTS password_reset_required BOOLEAN NOT NULL DEFAULT FALSE,
+    ADD COLUMN IF NOT EXISTS invited_by BIGINT REFERENCES admin_users(id) ON DELETE SET NULL;
+
+CREATE TABLE IF NOT EXISTS admin_user_audit (
+    id BIGSERIAL PRIMARY KEY,
+    actor_admin_user_id BIGINT REFERENCES admin_users(id) ON DELETE SET NULL,
+    target_admin_user_id BIGINT REFERENCES admin_users(id) ON DELETE SET NULL,
+    action TEXT NOT NULL,
+    details JSONB NOT NULL DEFAULT '{}'::jsonb,
+    ip_address INET,
+    user_agent TEXT,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_admin_user_audit_target_created_at ON admin_user_audit(target_admin_user_id, created_at DESC);
+CREATE INDEX IF NOT EXISTS idx_admin_user_audit_created_at ON admin_user_audit(created_at DESC);
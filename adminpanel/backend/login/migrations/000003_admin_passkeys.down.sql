@@ -0,0 +1,13 @@
+DELETE FROM admin_login_challenges WHERE status = 'passkey_verified';
+
+ALTER TABLE admin_login_challenges
+    DROP CONSTRAINT IF EXISTS admin_login_challenges_status_check;
+ALTER TABLE admin_login_challenges
+    ADD CONSTRAINT admin_login_challenges_status_check
+    CHECK (status IN ('telegram_verified', 'totp_verified', 'completed'));
+
+ALTER TABLE admin_login_challenges
+    DROP COLUMN IF EXISTS webauthn_challenge;
+
+DROP TABLE IF EXISTS admin_webauthn_registrations;
+DROP TABLE IF EXISTS admin_webauthn_credentials;
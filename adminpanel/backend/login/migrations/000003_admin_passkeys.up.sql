@@ -0,0 +1,33 @@
+CREATE TABLE IF NOT EXISTS admin_webauthn_credentials (
+    id BIGSERIAL PRIMARY KEY,
+    admin_user_id BIGINT NOT NULL REFERENCES admin_users(id) ON DELETE CASCADE,
+    credential_id BYTEA NOT NULL UNIQUE,
+    public_key BYTEA NOT NULL,
+    algorithm INTEGER NOT NULL,
+    sign_count BIGINT NOT NULL DEFAULT 0,
+    transports TEXT[] NOT NULL DEFAULT '{}',
+    name TEXT NOT NULL DEFAULT '',
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    last_used_at TIMESTAMPTZ
+);
+
+CREATE INDEX IF NOT EXISTS idx_admin_webauthn_credentials_admin_user_id ON admin_webauthn_credentials(admin_user_id);
+
+CREATE TABLE IF NOT EXISTS admin_webauthn_registrations (
+    id UUID PRIMARY KEY,
+    admin_user_id BIGINT NOT NULL REFERENCES admin_users(id) ON DELETE CASCADE,
+    challenge BYTEA NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    expires_at TIMESTAMPTZ NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS idx_admin_webauthn_registrations_expires_at ON admin_webauthn_registrations(expires_at);
+
+ALTER TABLE admin_login_challenges
+    ADD COLUMN IF NOT EXISTS webauthn_challenge BYTEA;
+
+ALTER TABLE admin_login_challenges
+    DROP CONSTRAINT IF EXISTS admin_login_challenges_status_check;
+ALTER TABLE admin_login_challenges
+    ADD CONSTRAINT admin_login_challenges_status_check
+    CHECK (status IN ('telegram_verified', 'totp_verified', 'passkey_verified', 'completed'));
@@ -0,0 +1,11 @@
+CREATE TABLE IF NOT EXISTS admin_recovery_codes (
+    id BIGSERIAL PRIMARY KEY,
+    admin_user_id BIGINT NOT NULL REFERENCES admin_users(id) ON DELETE CASCADE,
+    code_hash TEXT NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    used_at TIMESTAMPTZ,
+    used_ip INET,
+    UNIQUE (admin_user_id, code_hash)
+);
+
+CREATE INDEX IF NOT EXISTS idx_admin_recovery_codes_admin_user_id ON admin_recovery_codes(admin_user_id);
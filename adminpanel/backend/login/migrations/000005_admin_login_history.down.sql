@@ -0,0 +1 @@
+DROP TABLE IF EXISTS admin_login_events;
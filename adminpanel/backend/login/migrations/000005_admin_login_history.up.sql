@@ -0,0 +1,12 @@
+CREATE TABLE IF NOT EXISTS admin_login_events (
+    id BIGSERIAL PRIMARY KEY,
+    admin_user_id BIGINT NOT NULL REFERENCES admin_users(id) ON DELETE CASCADE,
+    event TEXT NOT NULL CHECK (event IN ('success', 'failure', 'locked')),
+    step TEXT NOT NULL,
+    session_id UUID,
+    ip_address INET,
+    user_agent TEXT,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_admin_login_events_user_created ON admin_login_events(admin_user_id, created_at DESC);
@@ -0,0 +1,2 @@
+-- admin_roles is shared with the backend, which owns dropping it.
+SELECT 1;
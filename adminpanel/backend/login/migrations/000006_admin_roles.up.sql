@@ -0,0 +1,26 @@
+-- admin_roles is shared with the backend (migration 000020); keep both definitions and seeds identical.
+CREATE TABLE IF NOT EXISTS admin_roles (
+    name TEXT PRIMARY KEY,
+    permissions TEXT[] NOT NULL DEFAULT '{}',
+    is_system BOOLEAN NOT NULL DEFAULT FALSE,
+    updated_by_tg_id BIGINT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    CONSTRAINT admin_roles_name_check CHECK (name ~ '^[A-Z][A-Z0-9_]{1,31}$')
+);
+
+INSERT INTO admin_roles (name, permissions, is_system)
+VALUES
+    ('OWNER', ARRAY[
+        'moderation.decide', 'moderation.qa', 'moderation.reasons',
+        'users.ban', 'users.view_private',
+        'system.toggle_registration', 'stats.view', 'audit.view',
+        'payments.refund', 'access.manage'
+    ], TRUE),
+    ('ADMIN', ARRAY[
+        'moderation.decide', 'moderation.qa', 'moderation.reasons',
+        'users.ban', 'users.view_private', 'access.manage'
+    ], TRUE),
+    ('SUPPORT', ARRAY['users.view_private', 'stats.view', 'payments.refund'], TRUE),
+    ('MODERATOR', ARRAY['moderation.decide'], TRUE)
+ON CONFLICT (name) DO NOTHING;
@@ -20,20 +20,26 @@ import (
 	adssvc "github.com/ivankudzin/tgapp/backend/internal/services/ads"
 	analyticsvc "github.com/ivankudzin/tgapp/backend/internal/services/analytics"
 	antiabusesvc "github.com/ivankudzin/tgapp/backend/internal/services/antiabuse"
+	auditsvc "github.com/ivankudzin/tgapp/backend/internal/services/audit"
 	authsvc "github.com/ivankudzin/tgapp/backend/internal/services/auth"
+	balancesvc "github.com/ivankudzin/tgapp/backend/internal/services/balance"
 	entsvc "github.com/ivankudzin/tgapp/backend/internal/services/entitlements"
 	feedsvc "github.com/ivankudzin/tgapp/backend/internal/services/feed"
+	flagssvc "github.com/ivankudzin/tgapp/backend/internal/services/flags"
 	geosvc "github.com/ivankudzin/tgapp/backend/internal/services/geo"
 	likessvc "github.com/ivankudzin/tgapp/backend/internal/services/likes"
 	matchessvc "github.com/ivankudzin/tgapp/backend/internal/services/matches"
 	mediasvc "github.com/ivankudzin/tgapp/backend/internal/services/media"
 	modsvc "github.com/ivankudzin/tgapp/backend/internal/services/moderation"
 	paymentsvc "github.com/ivankudzin/tgapp/backend/internal/services/payments"
+	permissionssvc "github.com/ivankudzin/tgapp/backend/internal/services/permissions"
 	profilesvc "github.com/ivankudzin/tgapp/backend/internal/services/profiles"
 	ratesvc "github.com/ivankudzin/tgapp/backend/internal/services/rate"
+	remotecfgsvc "github.com/ivankudzin/tgapp/backend/internal/services/remoteconfig"
 	supportsvc "github.com/ivankudzin/tgapp/backend/internal/services/support"
 	swipesvc "github.com/ivankudzin/tgapp/backend/internal/services/swipes"
 	userssvc "github.com/ivankudzin/tgapp/backend/internal/services/users"
+	waitlistsvc "github.com/ivankudzin/tgapp/backend/internal/services/waitlist"
 )
 
 type App struct {
@@ -135,12 +141,7 @@ func New(ctx context.Context, cfg config.Config, log *zap.Logger) (*App, error)
 		cfg.Remote.AntiAbuse.LikeMax10Sec,
 		cfg.Remote.AntiAbuse.LikeMaxPerMin,
 	)
-	likeService := likessvc.NewService(quotaRepo, entitlementRepo, rateLimiter, likessvc.Config{
-		FreeLikesPerDay: cfg.Remote.Limits.FreeLikesPerDay,
-		DefaultTimezone: cfg.Remote.MeDefaults.Timezone,
-		DefaultIsPlus:   cfg.Remote.MeDefaults.IsPlus,
-		PlusUnlimitedUI: cfg.Remote.Limits.PlusUnlimitedUI,
-	})
+	likeService := likessvc.NewService(quotaRepo, entitlementRepo, rateLimiter, likeServiceConfig(cfg.Remote))
 	likeService.AttachIncoming(pool, likeRepo, entitlementRepo)
 	matchesService := matchessvc.NewService(matchessvc.Dependencies{
 		Pool:              pool,
@@ -162,17 +163,7 @@ func New(ctx context.Context, cfg config.Config, log *zap.Logger) (*App, error)
 		QuotaView:    likeService,
 		AntiAbuse:    antiAbuseService,
 		Telemetry:    analyticsService,
-	}, swipesvc.Config{
-		FreeLikesPerDay:      cfg.Remote.Limits.FreeLikesPerDay,
-		FreeRewindsPerDay:    1,
-		PlusRewindsPerDay:    cfg.Remote.Limits.PlusRewindsPerDay,
-		DefaultTimezone:      cfg.Remote.MeDefaults.Timezone,
-		DefaultIsPlus:        cfg.Remote.MeDefaults.IsPlus,
-		MinCardViewMS:        cfg.Remote.AntiAbuse.MinCardViewMS,
-		SuspectLikeThreshold: cfg.Remote.AntiAbuse.SuspectLikeThreshold,
-		NewDeviceRiskWeight:  cfg.Remote.AntiAbuse.NewDeviceRiskWeight,
-		NewDeviceCooldownSec: firstCooldownStep(cfg.Remote.AntiAbuse.CooldownStepsSec),
-	})
+	}, swipeServiceConfig(cfg.Remote))
 	swipeService.AttachDevices(userDeviceRepo)
 	swipeService.AttachDailyMetrics(dailyMetricsRepo)
 
@@ -202,28 +193,86 @@ func New(ctx context.Context, cfg config.Config, log *zap.Logger) (*App, error)
 	moderationService := modsvc.NewService(moderationRepo, profileRepo, mediaRepo, mediaStorage)
 	supportService := supportsvc.NewService(supportRepo)
 	moderationService.AttachDailyMetrics(dailyMetricsRepo)
+	mediaService.AttachHashIndex(mediaRepo)
+	mediaService.AttachVerificationInvalidator(profileRepo)
+	moderationService.AttachPhotoMatcher(mediaService)
+	moderationService.AttachReviews(pgrepo.NewModerationReviewRepo(pool))
+	moderationService.AttachWorkload(pgrepo.NewModeratorWorkloadRepo(pool))
+	moderationService.AttachRejectReasons(pgrepo.NewRejectReasonRepo(pool))
 	userService := userssvc.NewService(pool, mediaRepo, mediaStorage)
+	userService.AttachSessions(sessionRepo)
+	userService.AttachRisk(antiAbuseService)
+	authService.AttachBans(authBanStoreAdapter{users: userService})
+	auditService := auditsvc.NewService(pgrepo.NewAuditRepo(pool))
+	permissionsService := permissionssvc.NewService(pgrepo.NewAdminRoleRepo(pool))
+
+	flagService := flagssvc.NewService(nil)
+	waitlistService := waitlistsvc.NewService(nil, flagService)
+	balanceService := balancesvc.NewService(nil, cfg.Remote.Balance)
+	if pool != nil {
+		appFlagRepo := pgrepo.NewAppFlagRepo(pool)
+		flagService = flagssvc.NewService(appFlagRepo)
+		flagService.AttachSubjects(appFlagRepo)
+		waitlistService = waitlistsvc.NewService(pgrepo.NewWaitlistRepo(pool), flagService)
+		balanceService = balancesvc.NewService(pgrepo.NewSupplyBalanceRepo(pool), cfg.Remote.Balance)
+		likeService.AttachLikeLimiter(balanceService)
+		swipeService.AttachLikeLimiter(balanceService)
+	}
+
+	// Published remote config versions hot-reload quotas and like rate limits; sections
+	// wired into other services at construction time still need a restart (see README).
+	remoteConfigService := remotecfgsvc.NewService(cfg.Remote)
+	remoteConfigService.OnChange(func(snapshot remotecfgsvc.Snapshot) {
+		likeService.Reconfigure(likeServiceConfig(snapshot.Config))
+		swipeService.Reconfigure(swipeServiceConfig(snapshot.Config))
+		balanceService.Reconfigure(snapshot.Config.Balance)
+		rateLimiter.SetLikeLimits(
+			snapshot.Config.AntiAbuse.LikeMaxPerSec,
+			snapshot.Config.AntiAbuse.LikeMax10Sec,
+			snapshot.Config.AntiAbuse.LikeMaxPerMin,
+		)
+		log.Info("remote config applied", zap.Int64("version", snapshot.Version), zap.String("checksum", snapshot.Checksum))
+	})
+	if pool != nil {
+		remoteConfigService.AttachStore(pgrepo.NewRemoteConfigRepo(pool))
+		remoteConfigService.AttachBus(redrepo.NewRemoteConfigBus(redisClient))
+		if err := remoteConfigService.Bootstrap(ctx); err != nil {
+			log.Warn("remote config bootstrap failed, serving config file", zap.Error(err))
+		}
+		go remoteConfigService.Listen(ctx, func(err error) {
+			log.Warn("remote config reload failed", zap.Error(err))
+		})
+		go balanceService.Run(ctx, func(err error) {
+			log.Warn("supply balance recompute failed", zap.Error(err))
+		})
+	}
 
 	RegisterRoutes(r, Dependencies{
 		AdsService:         adsService,
 		AntiAbuseService:   antiAbuseService,
 		AntiAbuseDashboard: antiAbuseDashboardRepo,
 		AnalyticsService:   analyticsService,
+		AuditService:       auditService,
 		EntitlementService: entitlementService,
 		AuthService:        authService,
+		BalanceService:     balanceService,
 		AdminWebAuth:       adminWebAuthService,
 		DailyMetricsRepo:   dailyMetricsRepo,
 		FeedService:        feedService,
+		FlagService:        flagService,
 		GeoService:         geoService,
 		LikeService:        likeService,
 		MatchService:       matchesService,
 		MediaService:       mediaService,
 		ModerationService:  moderationService,
 		PaymentService:     paymentService,
+		Permissions:        permissionsService,
 		ProfileService:     profileService,
+		RemoteConfig:       remoteConfigService,
 		SupportService:     supportService,
 		SwipeService:       swipeService,
 		UserService:        userService,
+		WaitlistService:    waitlistService,
 		Logger:             log,
 		Config:             cfg,
 	})
@@ -270,6 +319,18 @@ func (a *App) Handler() http.Handler {
 	return a.httpRouter
 }
 
+type authBanStoreAdapter struct {
+	users *userssvc.Service
+}
+
+func (a authBanStoreAdapter) IsBanned(ctx context.Context, userID int64) (bool, error) {
+	state, err := a.users.GetBanState(ctx, userID)
+	if err != nil {
+		return false, err
+	}
+	return state.Banned, nil
+}
+
 type authUserStoreAdapter struct {
 	repo *pgrepo.UserRepo
 }
@@ -293,6 +354,29 @@ func (a authUserStoreAdapter) GetOrCreateByTelegramID(ctx context.Context, teleg
 	}, nil
 }
 
+func likeServiceConfig(remote config.RemoteConfig) likessvc.Config {
+	return likessvc.Config{
+		FreeLikesPerDay: remote.Limits.FreeLikesPerDay,
+		DefaultTimezone: remote.MeDefaults.Timezone,
+		DefaultIsPlus:   remote.MeDefaults.IsPlus,
+		PlusUnlimitedUI: remote.Limits.PlusUnlimitedUI,
+	}
+}
+
+func swipeServiceConfig(remote config.RemoteConfig) swipesvc.Config {
+	return swipesvc.Config{
+		FreeLikesPerDay:      remote.Limits.FreeLikesPerDay,
+		FreeRewindsPerDay:    1,
+		PlusRewindsPerDay:    remote.Limits.PlusRewindsPerDay,
+		DefaultTimezone:      remote.MeDefaults.Timezone,
+		DefaultIsPlus:        remote.MeDefaults.IsPlus,
+		MinCardViewMS:        remote.AntiAbuse.MinCardViewMS,
+		SuspectLikeThreshold: remote.AntiAbuse.SuspectLikeThreshold,
+		NewDeviceRiskWeight:  remote.AntiAbuse.NewDeviceRiskWeight,
+		NewDeviceCooldownSec: firstCooldownStep(remote.AntiAbuse.CooldownStepsSec),
+	}
+}
+
 func firstCooldownStep(steps []int) int {
 	if len(steps) == 0 {
 		return 30
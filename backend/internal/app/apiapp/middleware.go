@@ -14,6 +14,8 @@ import (
 	"github.com/ivankudzin/tgapp/backend/internal/config"
 	adminauthsvc "github.com/ivankudzin/tgapp/backend/internal/services/adminauth"
 	authsvc "github.com/ivankudzin/tgapp/backend/internal/services/auth"
+	permissionssvc "github.com/ivankudzin/tgapp/backend/internal/services/permissions"
+	waitlistsvc "github.com/ivankudzin/tgapp/backend/internal/services/waitlist"
 	httperrors "github.com/ivankudzin/tgapp/backend/internal/transport/http/errors"
 )
 
@@ -119,6 +121,10 @@ func AdminWebAuthMiddleware(adminAuthService *adminauthsvc.Service, log *zap.Log
 				SID:    claims.SID,
 				Role:   claims.Role,
 			})
+			// Web moderators lock queue items under their Telegram ID, the same key the bot uses.
+			if claims.TelegramID != 0 {
+				ctx = authsvc.WithActorTGID(ctx, claims.TelegramID)
+			}
 			next.ServeHTTP(w, r.WithContext(ctx))
 		})
 	}
@@ -211,6 +217,87 @@ func RequireRole(roles ...string) func(http.Handler) http.Handler {
 	}
 }
 
+// RequireAdmitted keeps waitlisted users out of matching endpoints. Onboarding (profile, media)
+// stays open so admins can admit by city and gender. A waitlist lookup failure rejects the request.
+func RequireAdmitted(waitlist *waitlistsvc.Service) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			identity, ok := authsvc.IdentityFromContext(r.Context())
+			if !ok || waitlist == nil {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			waiting, err := waitlist.IsWaiting(r.Context(), identity.UserID)
+			if err != nil {
+				httperrors.Write(w, http.StatusServiceUnavailable, httperrors.APIError{
+					Code:    "WAITLIST_UNAVAILABLE",
+					Message: "registration status is temporarily unavailable",
+				})
+				return
+			}
+			if waiting {
+				httperrors.Write(w, http.StatusForbidden, httperrors.APIError{
+					Code:    "WAITLISTED",
+					Message: "registration is closed; you are on the waitlist",
+				})
+				return
+			}
+
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// RequirePermission lets the request through when the admin role (web identity or bot actor) holds
+// at least one of the listed permissions.
+func RequirePermission(perms *permissionssvc.Service, anyOf ...string) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			role := adminRoleFromContext(r)
+			if role == "" {
+				httperrors.Write(w, http.StatusUnauthorized, httperrors.APIError{
+					Code:    "UNAUTHORIZED",
+					Message: "authentication required",
+				})
+				return
+			}
+
+			for _, permission := range anyOf {
+				granted, err := perms.Has(r.Context(), role, permission)
+				if err != nil {
+					httperrors.Write(w, http.StatusInternalServerError, httperrors.APIError{
+						Code:    "PERMISSIONS_UNAVAILABLE",
+						Message: "permissions are unavailable",
+					})
+					return
+				}
+				if granted {
+					next.ServeHTTP(w, r)
+					return
+				}
+			}
+
+			httperrors.Write(w, http.StatusForbidden, httperrors.APIError{
+				Code:    "FORBIDDEN",
+				Message: "insufficient permission",
+			})
+		})
+	}
+}
+
+func adminRoleFromContext(r *http.Request) string {
+	if isBot, ok := authsvc.ActorIsBotFromContext(r.Context()); ok && isBot {
+		role, _ := authsvc.ActorRoleFromContext(r.Context())
+		return normalizeRole(role)
+	}
+	identity, ok := authsvc.IdentityFromContext(r.Context())
+	if !ok {
+		return ""
+	}
+	return normalizeRole(identity.Role)
+}
+
 func AdminBotAuthMiddleware(cfg config.AdminConfig, log *zap.Logger) func(http.Handler) http.Handler {
 	expectedToken := strings.TrimSpace(cfg.BotToken)
 	role := strings.TrimSpace(cfg.BotRole)
@@ -247,8 +334,14 @@ func AdminBotAuthMiddleware(cfg config.AdminConfig, log *zap.Logger) func(http.H
 				return
 			}
 
+			// The bot resolves the actor's role itself; the configured role covers older bots.
+			actorRole := normalizeRole(r.Header.Get("X-Actor-Role"))
+			if actorRole == "" {
+				actorRole = role
+			}
+
 			ctx := authsvc.WithActorIsBot(r.Context(), true)
-			ctx = authsvc.WithActorRole(ctx, role)
+			ctx = authsvc.WithActorRole(ctx, actorRole)
 			ctx = authsvc.WithActorTGID(ctx, actorTGID)
 			next.ServeHTTP(w, r.WithContext(ctx))
 		})
@@ -10,6 +10,7 @@ import (
 
 	"github.com/ivankudzin/tgapp/backend/internal/config"
 	authsvc "github.com/ivankudzin/tgapp/backend/internal/services/auth"
+	permissionssvc "github.com/ivankudzin/tgapp/backend/internal/services/permissions"
 )
 
 func TestRequireRoleAllowsCaseInsensitiveMatch(t *testing.T) {
@@ -123,3 +124,64 @@ func TestAdminBotAuthMiddlewareSetsActorContext(t *testing.T) {
 		t.Fatalf("unexpected status: got %d want %d", rr.Code, http.StatusNoContent)
 	}
 }
+
+func TestAdminBotAuthMiddlewarePrefersActorRoleHeader(t *testing.T) {
+	mw := AdminBotAuthMiddleware(config.AdminConfig{
+		BotToken: "secret-token",
+		BotRole:  "MODERATOR",
+	}, zap.NewNop())
+
+	req := httptest.NewRequest(http.MethodGet, "/admin/bot/queue", nil)
+	req.Header.Set("X-Admin-Bot-Token", "secret-token")
+	req.Header.Set("X-Actor-Tg-Id", "987654321")
+	req.Header.Set("X-Actor-Role", "support")
+	rr := httptest.NewRecorder()
+
+	mw(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		role, ok := authsvc.ActorRoleFromContext(r.Context())
+		if !ok || role != "SUPPORT" {
+			t.Fatalf("actor_role mismatch: %q", role)
+		}
+		w.WriteHeader(http.StatusNoContent)
+	})).ServeHTTP(rr, req)
+
+	if rr.Code != http.StatusNoContent {
+		t.Fatalf("unexpected status: got %d want %d", rr.Code, http.StatusNoContent)
+	}
+}
+
+func TestRequirePermissionChecksWebAndBotRolesAlike(t *testing.T) {
+	mw := RequirePermission(permissionssvc.NewService(nil), permissionssvc.UsersBan)
+	handler := mw(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusNoContent)
+	}))
+
+	webCtx := func(role string) context.Context {
+		return authsvc.WithIdentity(context.Background(), authsvc.Identity{UserID: 1, SID: "sid-1", Role: role})
+	}
+	botCtx := func(role string) context.Context {
+		ctx := authsvc.WithActorIsBot(context.Background(), true)
+		ctx = authsvc.WithActorTGID(ctx, 777)
+		return authsvc.WithActorRole(ctx, role)
+	}
+
+	cases := []struct {
+		name string
+		ctx  context.Context
+		want int
+	}{
+		{name: "web admin", ctx: webCtx("admin"), want: http.StatusNoContent},
+		{name: "bot admin", ctx: botCtx("ADMIN"), want: http.StatusNoContent},
+		{name: "web moderator", ctx: webCtx("MODERATOR"), want: http.StatusForbidden},
+		{name: "bot moderator", ctx: botCtx("MODERATOR"), want: http.StatusForbidden},
+		{name: "anonymous", ctx: context.Background(), want: http.StatusUnauthorized},
+	}
+	for _, tc := range cases {
+		req := httptest.NewRequest(http.MethodPost, "/admin/bot/users/1/ban", nil).WithContext(tc.ctx)
+		rr := httptest.NewRecorder()
+		handler.ServeHTTP(rr, req)
+		if rr.Code != tc.want {
+			t.Fatalf("%s: unexpected status: got %d want %d", tc.name, rr.Code, tc.want)
+		}
+	}
+}
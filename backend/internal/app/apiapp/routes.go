@@ -13,19 +13,25 @@ import (
 	adssvc "github.com/ivankudzin/tgapp/backend/internal/services/ads"
 	analyticsvc "github.com/ivankudzin/tgapp/backend/internal/services/analytics"
 	antiabusesvc "github.com/ivankudzin/tgapp/backend/internal/services/antiabuse"
+	auditsvc "github.com/ivankudzin/tgapp/backend/internal/services/audit"
 	authsvc "github.com/ivankudzin/tgapp/backend/internal/services/auth"
+	balancesvc "github.com/ivankudzin/tgapp/backend/internal/services/balance"
 	entsvc "github.com/ivankudzin/tgapp/backend/internal/services/entitlements"
 	feedsvc "github.com/ivankudzin/tgapp/backend/internal/services/feed"
+	flagssvc "github.com/ivankudzin/tgapp/backend/internal/services/flags"
 	geosvc "github.com/ivankudzin/tgapp/backend/internal/services/geo"
 	likessvc "github.com/ivankudzin/tgapp/backend/internal/services/likes"
 	matchessvc "github.com/ivankudzin/tgapp/backend/internal/services/matches"
 	mediasvc "github.com/ivankudzin/tgapp/backend/internal/services/media"
 	modsvc "github.com/ivankudzin/tgapp/backend/internal/services/moderation"
 	paymentsvc "github.com/ivankudzin/tgapp/backend/internal/services/payments"
+	permissionssvc "github.com/ivankudzin/tgapp/backend/internal/services/permissions"
 	profilesvc "github.com/ivankudzin/tgapp/backend/internal/services/profiles"
+	remotecfgsvc "github.com/ivankudzin/tgapp/backend/internal/services/remoteconfig"
 	supportsvc "github.com/ivankudzin/tgapp/backend/internal/services/support"
 	swipesvc "github.com/ivankudzin/tgapp/backend/internal/services/swipes"
 	userssvc "github.com/ivankudzin/tgapp/backend/internal/services/users"
+	waitlistsvc "github.com/ivankudzin/tgapp/backend/internal/services/waitlist"
 	httperrors "github.com/ivankudzin/tgapp/backend/internal/transport/http/errors"
 	"github.com/ivankudzin/tgapp/backend/internal/transport/http/handlers"
 )
@@ -35,21 +41,27 @@ type Dependencies struct {
 	AntiAbuseService   *antiabusesvc.Service
 	AntiAbuseDashboard *redrepo.AntiAbuseDashboardRepo
 	AnalyticsService   *analyticsvc.Service
+	AuditService       *auditsvc.Service
 	EntitlementService *entsvc.Service
 	AuthService        *authsvc.Service
+	BalanceService     *balancesvc.Service
 	AdminWebAuth       *adminauthsvc.Service
 	DailyMetricsRepo   *pgrepo.DailyMetricsRepo
 	FeedService        *feedsvc.Service
+	FlagService        *flagssvc.Service
 	GeoService         *geosvc.Service
 	LikeService        *likessvc.Service
 	MatchService       *matchessvc.Service
 	MediaService       *mediasvc.Service
 	ModerationService  *modsvc.Service
 	PaymentService     *paymentsvc.Service
+	Permissions        *permissionssvc.Service
 	ProfileService     *profilesvc.Service
+	RemoteConfig       *remotecfgsvc.Service
 	SupportService     *supportsvc.Service
 	SwipeService       *swipesvc.Service
 	UserService        *userssvc.Service
+	WaitlistService    *waitlistsvc.Service
 	Logger             *zap.Logger
 	Config             config.Config
 }
@@ -57,9 +69,17 @@ type Dependencies struct {
 func RegisterRoutes(r chi.Router, deps Dependencies) {
 	adsHandler := handlers.NewAdsHandler(deps.AdsService)
 	authHandler := handlers.NewAuthHandler(deps.AuthService)
+	authHandler.AttachWaitlist(deps.WaitlistService)
 	healthHandler := handlers.NewHealthHandler()
 	meHandler := handlers.NewMeHandler(deps.Config.Remote, deps.AntiAbuseService)
 	configHandler := handlers.NewConfigHandler(deps.Config.Remote)
+	if deps.RemoteConfig != nil {
+		meHandler.AttachRemoteConfig(deps.RemoteConfig)
+		configHandler.AttachRemoteConfig(deps.RemoteConfig)
+	}
+	meHandler.AttachFlags(deps.FlagService)
+	meHandler.AttachWaitlist(deps.WaitlistService)
+	waitlistHandler := handlers.NewWaitlistHandler(deps.WaitlistService)
 	locationHandler := handlers.NewLocationHandler(deps.GeoService)
 	profileHandler := handlers.NewProfileHandler(deps.ProfileService)
 	mediaHandler := handlers.NewMediaHandler(deps.MediaService)
@@ -81,15 +101,46 @@ func RegisterRoutes(r chi.Router, deps Dependencies) {
 	adminHandler := handlers.NewAdminHandler(deps.UserService, deps.AnalyticsService)
 	adminHandler.AttachDailyMetrics(deps.DailyMetricsRepo)
 	adminHandler.AttachAntiAbuseDashboard(deps.AntiAbuseDashboard)
+	adminHandler.AttachAudit(deps.AuditService)
+	if deps.ModerationService != nil {
+		adminHandler.AttachModerationSLA(deps.ModerationService)
+	}
 	adminBotModerationHandler := handlers.NewAdminBotModerationHandler(deps.ModerationService, deps.AnalyticsService)
 	adminBotUsersHandler := handlers.NewAdminBotUsersHandler(deps.UserService, deps.AnalyticsService)
 	adminBotSupportHandler := handlers.NewAdminBotSupportHandler(deps.SupportService, deps.AnalyticsService)
+	adminAuditHandler := handlers.NewAdminAuditHandler(deps.AuditService)
+	adminRejectReasonsHandler := handlers.NewAdminRejectReasonsHandler(deps.ModerationService, deps.AuditService)
+	adminModerationHandler := handlers.NewAdminModerationHandler(deps.ModerationService, deps.AuditService)
+	adminUsersHandler := handlers.NewAdminUsersHandler(deps.UserService, deps.AuditService)
+	adminConfigHandler := handlers.NewAdminConfigHandler(deps.RemoteConfig, deps.AuditService)
+	adminBotAccessHandler := handlers.NewAdminBotAccessHandler(deps.Permissions, deps.AuditService)
+	adminFlagsHandler := handlers.NewAdminFlagsHandler(deps.FlagService, deps.AuditService)
+	adminWaitlistHandler := handlers.NewAdminWaitlistHandler(deps.WaitlistService, deps.AuditService)
+	adminBalanceHandler := handlers.NewAdminBalanceHandler(deps.BalanceService, deps.AuditService)
 	authMW := AuthMiddleware(deps.AuthService, deps.Logger)
+	admittedMW := RequireAdmitted(deps.WaitlistService)
 	adminWebAuthMW := AdminWebAuthMiddleware(deps.AdminWebAuth, deps.Logger)
-	adminHealthRoleMW := RequireRole("OWNER", "SUPPORT", "MODERATOR")
-	adminPrivateRoleMW := RequireRole("OWNER", "SUPPORT")
-	adminMetricsRoleMW := RequireRole("OWNER", "SUPPORT")
+	perms := deps.Permissions
+	if perms == nil {
+		perms = permissionssvc.NewService(nil)
+	}
+	anyAdminMW := RequirePermission(perms, permissionssvc.Known()...)
+	moderationDecideMW := RequirePermission(perms, permissionssvc.ModerationDecide)
+	rejectReasonsViewMW := RequirePermission(perms, permissionssvc.ModerationDecide, permissionssvc.ModerationReasons, permissionssvc.StatsView)
+	rejectReasonsEditMW := RequirePermission(perms, permissionssvc.ModerationReasons)
+	usersViewPrivateMW := RequirePermission(perms, permissionssvc.UsersViewPrivate)
+	usersBanMW := RequirePermission(perms, permissionssvc.UsersBan)
+	statsViewMW := RequirePermission(perms, permissionssvc.StatsView)
+	queueStatsViewMW := RequirePermission(perms, permissionssvc.ModerationDecide, permissionssvc.StatsView)
+	auditViewMW := RequirePermission(perms, permissionssvc.AuditView)
+	accessManageMW := RequirePermission(perms, permissionssvc.AccessManage)
+	workloadViewMW := RequirePermission(perms, permissionssvc.StatsView, permissionssvc.AccessManage)
 	devPayRoleMW := RequireRole("OWNER")
+	configPublishMW := RequirePermission(perms, permissionssvc.ConfigPublish)
+	balanceRecomputeMW := RequirePermission(perms, permissionssvc.BalanceRecompute)
+	systemRegistrationMW := RequirePermission(perms, permissionssvc.SystemToggleRegistration)
+	flagsViewMW := RequirePermission(perms, permissionssvc.SystemToggleRegistration, permissionssvc.FlagsManage)
+	flagsManageMW := RequirePermission(perms, permissionssvc.FlagsManage)
 	adminBotAuthMW := AdminBotAuthMiddleware(deps.Config.Admin, deps.Logger)
 	adminBotNotImplemented := http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
 		httperrors.Write(w, http.StatusNotImplemented, httperrors.APIError{
@@ -101,14 +152,43 @@ func RegisterRoutes(r chi.Router, deps Dependencies) {
 	r.Get("/healthz", healthHandler.Get)
 	r.Route("/admin/bot", func(r chi.Router) {
 		r.Use(adminBotAuthMW)
-		r.Get("/mod/reject-reasons", adminBotModerationHandler.RejectReasons)
-		r.Post("/mod/queue/acquire", adminBotModerationHandler.QueueAcquire)
-		r.Post("/mod/items/{id}/approve", adminBotModerationHandler.Approve)
-		r.Post("/mod/items/{id}/reject", adminBotModerationHandler.Reject)
-		r.Get("/lookup/user", adminBotUsersHandler.LookupUser)
-		r.Post("/users/{id}/ban", adminBotUsersHandler.BanUser)
-		r.Post("/users/{id}/unban", adminBotUsersHandler.UnbanUser)
-		r.Post("/users/{id}/force-review", adminBotUsersHandler.ForceReview)
+		r.With(rejectReasonsViewMW).Get("/mod/reject-reasons", adminBotModerationHandler.RejectReasons)
+		r.With(rejectReasonsEditMW).Put("/mod/reject-reasons/{code}", adminBotModerationHandler.UpsertRejectReason)
+		r.With(statsViewMW).Get("/mod/sla", adminBotModerationHandler.SLA)
+		r.With(moderationDecideMW).Post("/mod/reviews/acquire", adminBotModerationHandler.ReviewAcquire)
+		r.With(moderationDecideMW).Get("/mod/reviews/{id}", adminBotModerationHandler.ReviewGet)
+		r.With(moderationDecideMW).Post("/mod/reviews/{id}/resolve", adminBotModerationHandler.ReviewResolve)
+		r.With(statsViewMW).Get("/mod/reviews/stats", adminBotModerationHandler.ReviewStats)
+		r.With(moderationDecideMW).Post("/mod/queue/acquire", adminBotModerationHandler.QueueAcquire)
+		r.With(moderationDecideMW).Post("/mod/queue/acquire-batch", adminBotModerationHandler.QueueAcquireBatch)
+		r.With(moderationDecideMW).Post("/mod/batch/decide", adminBotModerationHandler.DecideBatch)
+		r.With(workloadViewMW).Get("/mod/workload", adminBotModerationHandler.Workload)
+		r.With(accessManageMW).Put("/mod/workload/{tg_id}", adminBotModerationHandler.SaveModeratorWorkload)
+		r.With(accessManageMW).Post("/mod/workload/{tg_id}/shifts", adminBotModerationHandler.AddModeratorShift)
+		r.With(accessManageMW).Delete("/mod/shifts/{id}", adminBotModerationHandler.DeleteModeratorShift)
+		r.With(accessManageMW).Post("/mod/workload/reassign-idle", adminBotModerationHandler.ReassignIdleLocks)
+		r.With(statsViewMW).Get("/mod/shifts/throughput", adminBotModerationHandler.ShiftThroughput)
+		r.With(moderationDecideMW).Post("/mod/items/{id}/approve", adminBotModerationHandler.Approve)
+		r.With(moderationDecideMW).Post("/mod/items/{id}/approve-verified", adminBotModerationHandler.ApproveVerified)
+		r.With(moderationDecideMW).Post("/mod/items/{id}/reject", adminBotModerationHandler.Reject)
+		r.With(moderationDecideMW).Post("/mod/banned-images", adminBotModerationHandler.BanImage)
+		r.With(usersViewPrivateMW).Get("/lookup/user", adminBotUsersHandler.LookupUser)
+		r.With(usersViewPrivateMW).Get("/lookup/dossier", adminBotUsersHandler.Dossier)
+		r.With(usersBanMW).Post("/users/{id}/ban", adminBotUsersHandler.BanUser)
+		r.With(usersBanMW).Post("/users/{id}/unban", adminBotUsersHandler.UnbanUser)
+		r.With(usersViewPrivateMW).Get("/bans/state", adminBotUsersHandler.BanState)
+		r.With(moderationDecideMW).Post("/users/{id}/force-review", adminBotUsersHandler.ForceReview)
+		r.Post("/audit", adminAuditHandler.BotAppend)
+		r.With(auditViewMW).Get("/audit", adminAuditHandler.BotSearch)
+		r.With(auditViewMW).Get("/audit/recent", adminAuditHandler.BotRecent)
+		r.With(auditViewMW).Get("/audit/verify", adminAuditHandler.BotVerify)
+		r.With(accessManageMW).Get("/access/roles", adminBotAccessHandler.ListRoles)
+		r.With(accessManageMW).Put("/access/roles/{name}", adminBotAccessHandler.UpsertRole)
+		r.With(flagsViewMW).Get("/flags", adminFlagsHandler.BotList)
+		r.With(flagsManageMW).Put("/flags/{key}", adminFlagsHandler.BotUpsert)
+		r.With(flagsManageMW).Delete("/flags/{key}", adminFlagsHandler.BotDelete)
+		r.With(systemRegistrationMW).Get("/waitlist", adminWaitlistHandler.BotStats)
+		r.With(systemRegistrationMW).Post("/waitlist/admit", adminWaitlistHandler.BotAdmit)
 		r.Route("/support", func(r chi.Router) {
 			r.Post("/incoming", adminBotSupportHandler.Incoming)
 			r.Get("/conversations", adminBotSupportHandler.ListConversations)
@@ -124,11 +204,46 @@ func RegisterRoutes(r chi.Router, deps Dependencies) {
 		r.Handle("/*", adminBotNotImplemented)
 	})
 	r.Route("/admin", func(r chi.Router) {
-		r.With(adminWebAuthMW, adminHealthRoleMW).Get("/health", adminHandler.Health)
-		r.With(adminWebAuthMW, adminPrivateRoleMW).Get("/users/{id}/private", adminHandler.UserPrivate)
-		r.With(adminWebAuthMW, adminMetricsRoleMW).Get("/metrics/daily", adminHandler.MetricsDaily)
-		r.With(adminWebAuthMW, adminMetricsRoleMW).Get("/antiabuse/summary", adminHandler.AntiAbuseSummary)
-		r.With(adminWebAuthMW, adminMetricsRoleMW).Get("/antiabuse/top", adminHandler.AntiAbuseTop)
+		r.With(adminWebAuthMW, anyAdminMW).Get("/health", adminHandler.Health)
+		r.With(adminWebAuthMW, usersViewPrivateMW).Get("/users", adminUsersHandler.Search)
+		r.With(adminWebAuthMW, usersViewPrivateMW).Get("/users/{id}", adminUsersHandler.Detail)
+		r.With(adminWebAuthMW, usersViewPrivateMW).Post("/users/{id}/private", adminHandler.UserPrivate)
+		r.With(adminWebAuthMW, usersBanMW).Post("/users/{id}/ban", adminUsersHandler.Ban)
+		r.With(adminWebAuthMW, usersBanMW).Post("/users/{id}/unban", adminUsersHandler.Unban)
+		r.With(adminWebAuthMW, moderationDecideMW).Post("/users/{id}/force-review", adminUsersHandler.ForceReview)
+		r.With(adminWebAuthMW, statsViewMW).Get("/metrics/daily", adminHandler.MetricsDaily)
+		r.With(adminWebAuthMW, statsViewMW).Get("/antiabuse/summary", adminHandler.AntiAbuseSummary)
+		r.With(adminWebAuthMW, statsViewMW).Get("/antiabuse/top", adminHandler.AntiAbuseTop)
+		r.With(adminWebAuthMW, statsViewMW).Get("/moderation/sla", adminHandler.ModerationSLA)
+		r.With(adminWebAuthMW, rejectReasonsViewMW).Get("/moderation/reject-reasons", adminRejectReasonsHandler.List)
+		r.With(adminWebAuthMW, rejectReasonsEditMW).Put("/moderation/reject-reasons/{code}", adminRejectReasonsHandler.Upsert)
+		r.With(adminWebAuthMW, moderationDecideMW).Post("/moderation/queue/acquire", adminModerationHandler.QueueAcquire)
+		r.With(adminWebAuthMW, queueStatsViewMW).Get("/moderation/queue/stats", adminModerationHandler.QueueStats)
+		r.With(adminWebAuthMW, moderationDecideMW).Get("/moderation/items/{id}", adminModerationHandler.Item)
+		r.With(adminWebAuthMW, moderationDecideMW).Post("/moderation/items/{id}/approve", adminModerationHandler.Approve)
+		r.With(adminWebAuthMW, moderationDecideMW).Post("/moderation/items/{id}/approve-verified", adminModerationHandler.ApproveVerified)
+		r.With(adminWebAuthMW, moderationDecideMW).Post("/moderation/items/{id}/reject", adminModerationHandler.Reject)
+		r.With(adminWebAuthMW, auditViewMW).Get("/audit", adminAuditHandler.List)
+		r.With(adminWebAuthMW, auditViewMW).Get("/audit/export", adminAuditHandler.Export)
+		r.With(adminWebAuthMW, auditViewMW).Get("/audit/verify", adminAuditHandler.Verify)
+		r.With(adminWebAuthMW, anyAdminMW).Get("/config", adminConfigHandler.Current)
+		r.With(adminWebAuthMW, anyAdminMW).Get("/config/versions", adminConfigHandler.ListVersions)
+		r.With(adminWebAuthMW, anyAdminMW).Get("/config/versions/{version}", adminConfigHandler.GetVersion)
+		r.With(adminWebAuthMW, anyAdminMW).Get("/config/diff", adminConfigHandler.Diff)
+		r.With(adminWebAuthMW, configPublishMW).Post("/config/publish", adminConfigHandler.Publish)
+		r.With(adminWebAuthMW, configPublishMW).Post("/config/rollback", adminConfigHandler.Rollback)
+		r.With(adminWebAuthMW, anyAdminMW).Get("/flags", adminFlagsHandler.List)
+		r.With(adminWebAuthMW, anyAdminMW).Get("/flags/{key}", adminFlagsHandler.Get)
+		r.With(adminWebAuthMW, anyAdminMW).Get("/flags/{key}/evaluate", adminFlagsHandler.Evaluate)
+		r.With(adminWebAuthMW, flagsManageMW).Put("/flags/{key}", adminFlagsHandler.Upsert)
+		r.With(adminWebAuthMW, flagsManageMW).Delete("/flags/{key}", adminFlagsHandler.Delete)
+		r.With(adminWebAuthMW, anyAdminMW).Get("/waitlist", adminWaitlistHandler.Stats)
+		r.With(adminWebAuthMW, systemRegistrationMW).Post("/waitlist/admit", adminWaitlistHandler.Admit)
+		r.With(adminWebAuthMW, systemRegistrationMW).Get("/waitlist/invites", adminWaitlistHandler.ListInvites)
+		r.With(adminWebAuthMW, systemRegistrationMW).Post("/waitlist/invites", adminWaitlistHandler.CreateInvites)
+		r.With(adminWebAuthMW, systemRegistrationMW).Post("/waitlist/invites/{code}/disable", adminWaitlistHandler.DisableInvite)
+		r.With(adminWebAuthMW, statsViewMW).Get("/balance", adminBalanceHandler.Report)
+		r.With(adminWebAuthMW, balanceRecomputeMW).Post("/balance/recompute", adminBalanceHandler.Recompute)
 	})
 	r.Get("/config", configHandler.Handle)
 	r.With(authMW).Post("/profile/location", locationHandler.Handle)
@@ -137,11 +252,11 @@ func RegisterRoutes(r chi.Router, deps Dependencies) {
 	r.With(authMW).Get("/media/photos", mediaHandler.PhotosList)
 	r.With(authMW).Get("/moderation/status", moderationHandler.Handle)
 	r.With(authMW).Get("/quota", quotaHandler.Handle)
-	r.With(authMW).Post("/swipe", swipeHandler.Handle)
-	r.With(authMW).Get("/feed", feedHandler.Handle)
-	r.With(authMW).Post("/rewind", rewindHandler.Handle)
-	r.With(authMW).Get("/likes/incoming", likesHandler.Incoming)
-	r.With(authMW).Post("/likes/reveal_one", likesHandler.RevealOne)
+	r.With(authMW, admittedMW).Post("/swipe", swipeHandler.Handle)
+	r.With(authMW, admittedMW).Get("/feed", feedHandler.Handle)
+	r.With(authMW, admittedMW).Post("/rewind", rewindHandler.Handle)
+	r.With(authMW, admittedMW).Get("/likes/incoming", likesHandler.Incoming)
+	r.With(authMW, admittedMW).Post("/likes/reveal_one", likesHandler.RevealOne)
 	r.With(authMW).Get("/matches", matchesHandler.Handle)
 	r.With(authMW).Post("/unmatch", matchesHandler.Unmatch)
 	r.With(authMW).Post("/block", matchesHandler.Block)
@@ -171,6 +286,8 @@ func RegisterRoutes(r chi.Router, deps Dependencies) {
 
 	r.Route("/v1", func(r chi.Router) {
 		r.With(authMW).Get("/me", meHandler.Handle)
+		r.With(authMW).Get("/waitlist", waitlistHandler.Status)
+		r.With(authMW).Post("/waitlist/invite", waitlistHandler.Invite)
 		r.Get("/config", configHandler.Handle)
 		r.With(authMW).Post("/location", locationHandler.Handle)
 		r.With(authMW).Post("/profile/location", locationHandler.Handle)
@@ -181,16 +298,17 @@ func RegisterRoutes(r chi.Router, deps Dependencies) {
 		r.With(authMW).Post("/media/photo", mediaHandler.PhotoUpload)
 		r.With(authMW).Get("/media/photos", mediaHandler.PhotosList)
 		r.With(authMW).Get("/moderation/status", moderationHandler.Handle)
+		r.With(authMW).Post("/moderation/appeal", moderationHandler.Appeal)
 		r.With(authMW).Get("/quota", quotaHandler.Handle)
-		r.With(authMW).Get("/feed", feedHandler.Handle)
-		r.With(authMW).Get("/candidates/{user_id}/profile", candidateHandler.Profile)
-		r.With(authMW).Get("/candidates/{user_id}/media/photos", candidateHandler.Photos)
-		r.With(authMW).Post("/swipes", swipeHandler.Handle)
-		r.With(authMW).Post("/rewind", rewindHandler.Handle)
+		r.With(authMW, admittedMW).Get("/feed", feedHandler.Handle)
+		r.With(authMW, admittedMW).Get("/candidates/{user_id}/profile", candidateHandler.Profile)
+		r.With(authMW, admittedMW).Get("/candidates/{user_id}/media/photos", candidateHandler.Photos)
+		r.With(authMW, admittedMW).Post("/swipes", swipeHandler.Handle)
+		r.With(authMW, admittedMW).Post("/rewind", rewindHandler.Handle)
 		r.Post("/boost", boostHandler.Handle)
-		r.With(authMW).Get("/likes", likesHandler.Handle)
-		r.With(authMW).Get("/likes/incoming", likesHandler.Incoming)
-		r.With(authMW).Post("/likes/reveal_one", likesHandler.RevealOne)
+		r.With(authMW, admittedMW).Get("/likes", likesHandler.Handle)
+		r.With(authMW, admittedMW).Get("/likes/incoming", likesHandler.Incoming)
+		r.With(authMW, admittedMW).Post("/likes/reveal_one", likesHandler.RevealOne)
 		r.With(authMW).Get("/matches", matchesHandler.Handle)
 		r.With(authMW).Post("/unmatch", matchesHandler.Unmatch)
 		r.With(authMW).Post("/block", matchesHandler.Block)
@@ -20,12 +20,17 @@ import (
 	pgrepo "github.com/ivankudzin/tgapp/backend/internal/repo/postgres"
 	mediasvc "github.com/ivankudzin/tgapp/backend/internal/services/media"
 	modsvc "github.com/ivankudzin/tgapp/backend/internal/services/moderation"
+	waitlistsvc "github.com/ivankudzin/tgapp/backend/internal/services/waitlist"
 )
 
 const (
 	missingSetupInstruction = "Сначала открой Mini App, заверши onboarding и укажи username в Telegram, затем отправь кружок снова."
 	uploadedInstruction     = "Кружок получен и отправлен на модерацию."
 	queueEmptyInstruction   = "Очередь модерации пуста."
+	waitlistInstruction     = "Регистрация сейчас закрыта, ты в листе ожидания (место %d, перед тобой %d). Мы напишем, когда откроем доступ."
+	admittedInstruction     = "Доступ открыт! Заходи в Mini App и заверши анкету."
+
+	waitlistNotifyInterval = time.Minute
 )
 
 type rejectState struct {
@@ -47,6 +52,7 @@ type App struct {
 	moderationRepo    *pgrepo.ModerationRepo
 	profileRepo       *pgrepo.ProfileRepo
 	moderationService *modsvc.Service
+	waitlistService   *waitlistsvc.Service
 	cleanupJob        *cleanup.Job
 
 	rejectMu     sync.Mutex
@@ -80,6 +86,10 @@ func New(ctx context.Context, cfg config.Config, logger *zap.Logger) (*App, erro
 	moderationRepo := pgrepo.NewModerationRepo(pool)
 	profileRepo := pgrepo.NewProfileRepo(pool)
 	moderationService := modsvc.NewService(moderationRepo, profileRepo, mediaRepo, storage)
+	moderationService.AttachRejectReasons(pgrepo.NewRejectReasonRepo(pool))
+	// The bot only reads entries and sends admission messages, so it doesn't need the
+	// registration flag.
+	waitlistService := waitlistsvc.NewService(pgrepo.NewWaitlistRepo(pool), nil)
 	cleanupJob := cleanup.NewCircleCleanupJob(mediaRepo, moderationRepo, storage, cfg.Bot.CircleRetention, logger)
 	cleanupJob.AttachExactGeoCleanup(profileRepo, time.Duration(cfg.Geo.ExactRetentionHours)*time.Hour)
 
@@ -106,6 +116,7 @@ func New(ctx context.Context, cfg config.Config, logger *zap.Logger) (*App, erro
 		moderationRepo:    moderationRepo,
 		profileRepo:       profileRepo,
 		moderationService: moderationService,
+		waitlistService:   waitlistService,
 		cleanupJob:        cleanupJob,
 		rejectByChat:      make(map[int64]rejectState),
 	}, nil
@@ -114,12 +125,15 @@ func New(ctx context.Context, cfg config.Config, logger *zap.Logger) (*App, erro
 func (a *App) Run(ctx context.Context) error {
 	a.logger.Info("bot app started")
 
-	errCh := make(chan error, 2)
+	errCh := make(chan error, 3)
 	go func() {
 		errCh <- a.runCleanupLoop(ctx)
 	}()
 
 	if a.bot != nil {
+		go func() {
+			errCh <- a.runWaitlistNotifyLoop(ctx)
+		}()
 		go func() {
 			errCh <- a.bot.Listen(ctx, tginfra.Handlers{
 				OnVideoNote: a.handleVideoNote,
@@ -173,6 +187,36 @@ func (a *App) runCleanupLoop(ctx context.Context) error {
 	}
 }
 
+// runWaitlistNotifyLoop tells batch-admitted users that they can finish onboarding. Delivery
+// errors are retried by the waitlist service; store errors are only logged so a database blip
+// doesn't stop the bot.
+func (a *App) runWaitlistNotifyLoop(ctx context.Context) error {
+	if a.waitlistService == nil {
+		return nil
+	}
+
+	ticker := time.NewTicker(waitlistNotifyInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+			sent, err := a.waitlistService.NotifyAdmitted(ctx, func(ctx context.Context, n waitlistsvc.Notification) error {
+				return a.bot.SendText(ctx, n.TelegramID, admittedInstruction)
+			})
+			if err != nil {
+				a.logger.Warn("waitlist notify failed", zap.Error(err))
+				continue
+			}
+			if sent > 0 {
+				a.logger.Info("waitlist admission notifications sent", zap.Int("sent", sent))
+			}
+		}
+	}
+}
+
 func (a *App) handleVideoNote(ctx context.Context, update tginfra.VideoNoteUpdate) error {
 	if a.bot == nil {
 		return nil
@@ -190,6 +234,16 @@ func (a *App) handleVideoNote(ctx context.Context, update tginfra.VideoNoteUpdat
 		return a.bot.SendText(ctx, update.ChatID, missingSetupInstruction)
 	}
 
+	// Waitlisted users don't reach moderation until they are admitted.
+	if a.waitlistService != nil {
+		status, err := a.waitlistService.Status(ctx, user.ID)
+		if err != nil {
+			a.logger.Warn("failed to load waitlist status", zap.Error(err), zap.Int64("user_id", user.ID))
+		} else if status.Status == waitlistsvc.StatusWaiting {
+			return a.bot.SendText(ctx, update.ChatID, fmt.Sprintf(waitlistInstruction, status.Position, status.Ahead))
+		}
+	}
+
 	if strings.TrimSpace(user.Username) == "" && strings.TrimSpace(update.Username) != "" {
 		if err := a.userRepo.UpdateUsername(ctx, user.ID, update.Username); err != nil {
 			a.logger.Warn("failed to persist telegram username", zap.Error(err), zap.Int64("user_id", user.ID))
@@ -229,6 +283,9 @@ func (a *App) handleVideoNote(ctx context.Context, update tginfra.VideoNoteUpdat
 	if err := a.profileRepo.SetModerationStatus(ctx, user.ID, "PENDING"); err != nil {
 		return err
 	}
+	if err := a.profileRepo.MarkVerificationPending(ctx, user.ID); err != nil {
+		return err
+	}
 
 	return a.bot.SendText(ctx, update.ChatID, uploadedInstruction)
 }
@@ -271,6 +328,26 @@ func (a *App) handleCallback(ctx context.Context, update tginfra.CallbackUpdate)
 			return err
 		}
 		return a.bot.SendText(ctx, update.ChatID, "Анкета одобрена.")
+	case "verify":
+		if err := a.moderationService.ApproveAsVerified(ctx, itemID, update.UserID); err != nil {
+			if errors.Is(err, modsvc.ErrCircleMissing) {
+				return a.bot.AnswerCallback(ctx, update.CallbackID, "No circle to verify")
+			}
+			return a.bot.AnswerCallback(ctx, update.CallbackID, "Approve failed")
+		}
+		if err := a.bot.AnswerCallback(ctx, update.CallbackID, "Approved as verified"); err != nil {
+			return err
+		}
+		return a.bot.SendText(ctx, update.ChatID, "Анкета одобрена, пользователь верифицирован.")
+	case "circle_mismatch", "circle_failed":
+		reasonCode := strings.ToUpper(action)
+		if err := a.moderationService.RejectWithTemplate(ctx, itemID, update.UserID, reasonCode); err != nil {
+			return a.bot.AnswerCallback(ctx, update.CallbackID, "Reject failed")
+		}
+		if err := a.bot.AnswerCallback(ctx, update.CallbackID, "Rejected"); err != nil {
+			return err
+		}
+		return a.bot.SendText(ctx, update.ChatID, fmt.Sprintf("Анкета отклонена (%s).", reasonCode))
 	case "reject":
 		a.rejectMu.Lock()
 		a.rejectByChat[update.ChatID] = rejectState{
@@ -360,6 +437,7 @@ func formatQueueMessage(item modsvc.QueueItem) string {
 		fmt.Sprintf("- occupation: %s", defaultString(item.Profile.Occupation, "-")),
 		fmt.Sprintf("- education: %s", defaultString(item.Profile.Education, "-")),
 		fmt.Sprintf("- goals: %s", strings.Join(item.Profile.Goals, ", ")),
+		fmt.Sprintf("- verification: %s", defaultString(item.Profile.VerificationStatus, "-")),
 		"",
 		"Photos:",
 	}
@@ -86,6 +86,7 @@ type RemoteConfig struct {
 	Boost      BoostConfig      `yaml:"boost"`
 	Cities     []CityConfig     `yaml:"cities"`
 	MeDefaults MeDefaultsConfig `yaml:"me_defaults"`
+	Balance    BalanceConfig    `yaml:"balance"`
 }
 
 type AntiAbuseConfig struct {
@@ -133,6 +134,26 @@ type CityConfig struct {
 	Lon  float64 `yaml:"lon"`
 }
 
+// BalanceConfig drives the supply balance controller. Shares are the part of women among active
+// approved profiles; cities without a target use TargetFemaleShare. A like factor of 0 means 1.
+type BalanceConfig struct {
+	Enabled              bool                  `yaml:"enabled"`
+	ActiveWindow         time.Duration         `yaml:"active_window"`
+	MinActive            int                   `yaml:"min_active"`
+	TargetFemaleShare    float64               `yaml:"target_female_share"`
+	Tolerance            float64               `yaml:"tolerance"`
+	Targets              []BalanceTargetConfig `yaml:"targets"`
+	ThrottleAdmissions   bool                  `yaml:"throttle_admissions"`
+	PrioritizeModeration bool                  `yaml:"prioritize_moderation"`
+	OverLikeFactor       float64               `yaml:"over_like_factor"`
+	UnderLikeFactor      float64               `yaml:"under_like_factor"`
+}
+
+type BalanceTargetConfig struct {
+	CityID      string  `yaml:"city_id"`
+	FemaleShare float64 `yaml:"female_share"`
+}
+
 type MeDefaultsConfig struct {
 	UsernamePrefix       string                 `yaml:"username_prefix"`
 	CityID               string                 `yaml:"city_id"`
@@ -254,6 +275,18 @@ func Default() Config {
 					IncognitoDuration:     0,
 				},
 			},
+			Balance: BalanceConfig{
+				Enabled:              false,
+				ActiveWindow:         7 * 24 * time.Hour,
+				MinActive:            50,
+				TargetFemaleShare:    0.5,
+				Tolerance:            0.1,
+				Targets:              []BalanceTargetConfig{},
+				ThrottleAdmissions:   true,
+				PrioritizeModeration: true,
+				OverLikeFactor:       1,
+				UnderLikeFactor:      1,
+			},
 		},
 	}
 }
@@ -180,15 +180,22 @@ func (b *Bot) SendModerationQueue(ctx context.Context, chatID int64, text string
 		return fmt.Errorf("telegram bot is not initialized")
 	}
 
-	approveData := "mod:approve:" + strconv.FormatInt(itemID, 10)
-	rejectData := "mod:reject:" + strconv.FormatInt(itemID, 10)
+	id := strconv.FormatInt(itemID, 10)
+	approveData := "mod:approve:" + id
+	verifyData := "mod:verify:" + id
+	rejectData := "mod:reject:" + id
 
 	msg := tgbotapi.NewMessage(chatID, text)
 	msg.ReplyMarkup = tgbotapi.NewInlineKeyboardMarkup(
 		tgbotapi.NewInlineKeyboardRow(
 			tgbotapi.NewInlineKeyboardButtonData("Approve", approveData),
+			tgbotapi.NewInlineKeyboardButtonData("Approve + Verified", verifyData),
 			tgbotapi.NewInlineKeyboardButtonData("Reject", rejectData),
 		),
+		tgbotapi.NewInlineKeyboardRow(
+			tgbotapi.NewInlineKeyboardButtonData("Circle mismatch", "mod:circle_mismatch:"+id),
+			tgbotapi.NewInlineKeyboardButtonData("Circle failed", "mod:circle_failed:"+id),
+		),
 	)
 
 	if _, err := b.api.Send(msg); err != nil {
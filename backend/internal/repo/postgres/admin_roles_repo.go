@@ -0,0 +1,111 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+type AdminRoleRepo struct {
+	pool *pgxpool.Pool
+}
+
+type AdminRoleRecord struct {
+	Name          string
+	Permissions   []string
+	IsSystem      bool
+	UpdatedByTGID *int64
+	UpdatedAt     time.Time
+}
+
+const adminRoleColumns = `name, permissions, is_system, updated_by_tg_id, updated_at`
+
+func NewAdminRoleRepo(pool *pgxpool.Pool) *AdminRoleRepo {
+	return &AdminRoleRepo{pool: pool}
+}
+
+func (r *AdminRoleRepo) ListAdminRoles(ctx context.Context) ([]AdminRoleRecord, error) {
+	if r.pool == nil {
+		return nil, fmt.Errorf("postgres pool is nil")
+	}
+
+	rows, err := r.pool.Query(ctx, `
+SELECT `+adminRoleColumns+`
+FROM admin_roles
+ORDER BY is_system DESC, name ASC
+`)
+	if err != nil {
+		return nil, fmt.Errorf("list admin roles: %w", err)
+	}
+	defer rows.Close()
+
+	items := make([]AdminRoleRecord, 0, 8)
+	for rows.Next() {
+		item, err := scanAdminRole(rows)
+		if err != nil {
+			return nil, err
+		}
+		items = append(items, item)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterate admin roles: %w", err)
+	}
+	return items, nil
+}
+
+// UpsertCustomAdminRole never touches system roles: the WHERE on the conflict branch turns it into a no-op.
+func (r *AdminRoleRepo) UpsertCustomAdminRole(ctx context.Context, in AdminRoleRecord) (AdminRoleRecord, bool, error) {
+	if r.pool == nil {
+		return AdminRoleRecord{}, false, fmt.Errorf("postgres pool is nil")
+	}
+
+	rows, err := r.pool.Query(ctx, `
+INSERT INTO admin_roles (name, permissions, is_system, updated_by_tg_id, updated_at)
+VALUES ($1, $2::text[], FALSE, $3, NOW())
+ON CONFLICT (name) DO UPDATE SET
+	permissions = EXCLUDED.permissions,
+	updated_by_tg_id = EXCLUDED.updated_by_tg_id,
+	updated_at = NOW()
+WHERE admin_roles.is_system = FALSE
+RETURNING `+adminRoleColumns,
+		in.Name,
+		in.Permissions,
+		in.UpdatedByTGID,
+	)
+	if err != nil {
+		return AdminRoleRecord{}, false, fmt.Errorf("upsert admin role: %w", err)
+	}
+	defer rows.Close()
+
+	if !rows.Next() {
+		if err := rows.Err(); err != nil {
+			return AdminRoleRecord{}, false, fmt.Errorf("upsert admin role: %w", err)
+		}
+		return AdminRoleRecord{}, false, nil
+	}
+	item, err := scanAdminRole(rows)
+	if err != nil {
+		return AdminRoleRecord{}, false, err
+	}
+	return item, true, nil
+}
+
+func scanAdminRole(rows pgx.Rows) (AdminRoleRecord, error) {
+	var item AdminRoleRecord
+	if err := rows.Scan(
+		&item.Name,
+		&item.Permissions,
+		&item.IsSystem,
+		&item.UpdatedByTGID,
+		&item.UpdatedAt,
+	); err != nil {
+		return AdminRoleRecord{}, fmt.Errorf("scan admin role: %w", err)
+	}
+	if item.Permissions == nil {
+		item.Permissions = []string{}
+	}
+	return item, nil
+}
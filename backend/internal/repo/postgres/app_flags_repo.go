@@ -0,0 +1,182 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+var ErrAppFlagNotFound = errors.New("app flag not found")
+
+type AppFlagRepo struct {
+	pool *pgxpool.Pool
+}
+
+type AppFlagRecord struct {
+	Key           string
+	ValueType     string
+	ValueBool     bool
+	Value         []byte
+	Rules         []byte
+	Description   string
+	ClientVisible bool
+	UpdatedByTGID *int64
+	UpdatedAt     time.Time
+}
+
+type AppFlagSubjectRecord struct {
+	UserID int64
+	CityID string
+	Gender string
+	IsPlus bool
+}
+
+const appFlagColumns = `key, value_type, value_bool, value, rules, description, client_visible, updated_by_tg_id, updated_at`
+
+func NewAppFlagRepo(pool *pgxpool.Pool) *AppFlagRepo {
+	return &AppFlagRepo{pool: pool}
+}
+
+func (r *AppFlagRepo) ListAppFlags(ctx context.Context) ([]AppFlagRecord, error) {
+	if r.pool == nil {
+		return nil, fmt.Errorf("postgres pool is nil")
+	}
+
+	rows, err := r.pool.Query(ctx, `
+SELECT `+appFlagColumns+`
+FROM app_flags
+ORDER BY key ASC
+`)
+	if err != nil {
+		return nil, fmt.Errorf("list app flags: %w", err)
+	}
+	defer rows.Close()
+
+	items := make([]AppFlagRecord, 0, 8)
+	for rows.Next() {
+		item, err := scanAppFlag(rows)
+		if err != nil {
+			return nil, err
+		}
+		items = append(items, item)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterate app flags: %w", err)
+	}
+	return items, nil
+}
+
+func (r *AppFlagRepo) UpsertAppFlag(ctx context.Context, in AppFlagRecord) (AppFlagRecord, error) {
+	if r.pool == nil {
+		return AppFlagRecord{}, fmt.Errorf("postgres pool is nil")
+	}
+
+	var value any
+	if len(in.Value) > 0 {
+		value = string(in.Value)
+	}
+	rules := "[]"
+	if len(in.Rules) > 0 {
+		rules = string(in.Rules)
+	}
+
+	rows, err := r.pool.Query(ctx, `
+INSERT INTO app_flags (
+	key, value_type, value_bool, value, rules, description, client_visible, updated_by_tg_id, updated_at
+)
+VALUES ($1, $2, $3, $4::jsonb, $5::jsonb, $6, $7, $8, NOW())
+ON CONFLICT (key) DO UPDATE SET
+	value_type = EXCLUDED.value_type,
+	value_bool = EXCLUDED.value_bool,
+	value = EXCLUDED.value,
+	rules = EXCLUDED.rules,
+	description = EXCLUDED.description,
+	client_visible = EXCLUDED.client_visible,
+	updated_by_tg_id = EXCLUDED.updated_by_tg_id,
+	updated_at = NOW()
+RETURNING `+appFlagColumns,
+		in.Key,
+		in.ValueType,
+		in.ValueBool,
+		value,
+		rules,
+		in.Description,
+		in.ClientVisible,
+		in.UpdatedByTGID,
+	)
+	if err != nil {
+		return AppFlagRecord{}, fmt.Errorf("upsert app flag: %w", err)
+	}
+	defer rows.Close()
+
+	if !rows.Next() {
+		if err := rows.Err(); err != nil {
+			return AppFlagRecord{}, fmt.Errorf("upsert app flag: %w", err)
+		}
+		return AppFlagRecord{}, fmt.Errorf("upsert app flag: no row returned")
+	}
+	return scanAppFlag(rows)
+}
+
+func (r *AppFlagRepo) DeleteAppFlag(ctx context.Context, key string) error {
+	if r.pool == nil {
+		return fmt.Errorf("postgres pool is nil")
+	}
+
+	tag, err := r.pool.Exec(ctx, `DELETE FROM app_flags WHERE key = $1`, key)
+	if err != nil {
+		return fmt.Errorf("delete app flag: %w", err)
+	}
+	if tag.RowsAffected() == 0 {
+		return ErrAppFlagNotFound
+	}
+	return nil
+}
+
+// AppFlagSubject loads the user attributes flag rules can target.
+func (r *AppFlagRepo) AppFlagSubject(ctx context.Context, userID int64) (AppFlagSubjectRecord, error) {
+	if r.pool == nil {
+		return AppFlagSubjectRecord{}, fmt.Errorf("postgres pool is nil")
+	}
+
+	out := AppFlagSubjectRecord{UserID: userID}
+	err := r.pool.QueryRow(ctx, `
+SELECT
+	COALESCE(p.city_id, ''),
+	COALESCE(p.gender, ''),
+	COALESCE(e.plus_expires_at > NOW(), FALSE)
+FROM users u
+LEFT JOIN profiles p ON p.user_id = u.id
+LEFT JOIN entitlements e ON e.user_id = u.id
+WHERE u.id = $1
+`, userID).Scan(&out.CityID, &out.Gender, &out.IsPlus)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return out, nil
+	}
+	if err != nil {
+		return AppFlagSubjectRecord{}, fmt.Errorf("load app flag subject: %w", err)
+	}
+	return out, nil
+}
+
+func scanAppFlag(rows pgx.Rows) (AppFlagRecord, error) {
+	var item AppFlagRecord
+	if err := rows.Scan(
+		&item.Key,
+		&item.ValueType,
+		&item.ValueBool,
+		&item.Value,
+		&item.Rules,
+		&item.Description,
+		&item.ClientVisible,
+		&item.UpdatedByTGID,
+		&item.UpdatedAt,
+	); err != nil {
+		return AppFlagRecord{}, fmt.Errorf("scan app flag: %w", err)
+	}
+	return item, nil
+}
@@ -0,0 +1,266 @@
+package postgres
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// auditChainLockKey serializes chain appends; bot_moderator takes the same advisory lock.
+const auditChainLockKey = "bot_audit_chain"
+
+type AuditRepo struct {
+	pool *pgxpool.Pool
+}
+
+type AuditRecord struct {
+	ID        string
+	Seq       int64
+	ActorTGID int64
+	Action    string
+	Payload   json.RawMessage
+	PrevHash  string
+	Hash      string
+	CreatedAt time.Time
+}
+
+type AuditFilter struct {
+	ActorTGID    int64
+	TargetUserID int64
+	Action       string
+	From         *time.Time
+	To           *time.Time
+	Limit        int
+	Offset       int
+}
+
+type AuditChainReport struct {
+	Checked  int
+	OK       bool
+	BrokenID string
+}
+
+func NewAuditRepo(pool *pgxpool.Pool) *AuditRepo {
+	return &AuditRepo{pool: pool}
+}
+
+func (r *AuditRepo) Append(ctx context.Context, in AuditRecord) (AuditRecord, error) {
+	if r.pool == nil {
+		return AuditRecord{}, fmt.Errorf("postgres pool is nil")
+	}
+	if len(bytes.TrimSpace(in.Payload)) == 0 {
+		in.Payload = json.RawMessage(`{}`)
+	}
+	if in.CreatedAt.IsZero() {
+		in.CreatedAt = time.Now()
+	}
+	in.CreatedAt = in.CreatedAt.UTC().Truncate(time.Microsecond)
+
+	tx, err := r.pool.Begin(ctx)
+	if err != nil {
+		return AuditRecord{}, fmt.Errorf("begin audit append tx: %w", err)
+	}
+	defer func() {
+		_ = tx.Rollback(ctx)
+	}()
+
+	if _, err := tx.Exec(ctx, `SELECT pg_advisory_xact_lock(hashtext($1))`, auditChainLockKey); err != nil {
+		return AuditRecord{}, fmt.Errorf("lock audit chain: %w", err)
+	}
+
+	var prevHash string
+	err = tx.QueryRow(ctx, `
+SELECT hash
+FROM bot_audit
+WHERE hash IS NOT NULL
+ORDER BY seq DESC
+LIMIT 1
+`).Scan(&prevHash)
+	if err != nil && !errors.Is(err, pgx.ErrNoRows) {
+		return AuditRecord{}, fmt.Errorf("load audit chain head: %w", err)
+	}
+
+	in.PrevHash = prevHash
+	in.Hash = AuditChainHash(prevHash, in)
+	if err := tx.QueryRow(ctx, `
+INSERT INTO bot_audit (actor_tg_id, action, payload, created_at, prev_hash, hash)
+VALUES ($1, $2, $3::jsonb, $4, $5, $6)
+RETURNING id::text, seq
+`, in.ActorTGID, in.Action, string(in.Payload), in.CreatedAt, in.PrevHash, in.Hash).Scan(&in.ID, &in.Seq); err != nil {
+		return AuditRecord{}, fmt.Errorf("insert audit record: %w", err)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return AuditRecord{}, fmt.Errorf("commit audit append tx: %w", err)
+	}
+	return in, nil
+}
+
+func (r *AuditRepo) Search(ctx context.Context, filter AuditFilter) ([]AuditRecord, error) {
+	if r.pool == nil {
+		return nil, fmt.Errorf("postgres pool is nil")
+	}
+	if filter.Limit <= 0 {
+		filter.Limit = 50
+	}
+	if filter.Offset < 0 {
+		filter.Offset = 0
+	}
+
+	conditions := make([]string, 0, 5)
+	args := make([]any, 0, 7)
+	if filter.ActorTGID != 0 {
+		args = append(args, filter.ActorTGID)
+		conditions = append(conditions, fmt.Sprintf("actor_tg_id = $%d", len(args)))
+	}
+	if filter.TargetUserID != 0 {
+		args = append(args, strconv.FormatInt(filter.TargetUserID, 10))
+		conditions = append(conditions, fmt.Sprintf("payload->>'target_user_id' = $%d", len(args)))
+	}
+	if action := strings.TrimSpace(filter.Action); action != "" {
+		args = append(args, action)
+		conditions = append(conditions, fmt.Sprintf("action = $%d", len(args)))
+	}
+	if filter.From != nil {
+		args = append(args, filter.From.UTC())
+		conditions = append(conditions, fmt.Sprintf("created_at >= $%d", len(args)))
+	}
+	if filter.To != nil {
+		args = append(args, filter.To.UTC())
+		conditions = append(conditions, fmt.Sprintf("created_at < $%d", len(args)))
+	}
+
+	where := ""
+	if len(conditions) > 0 {
+		where = "WHERE " + strings.Join(conditions, " AND ")
+	}
+	args = append(args, filter.Limit, filter.Offset)
+
+	rows, err := r.pool.Query(ctx, fmt.Sprintf(`
+SELECT id::text, seq, actor_tg_id, action, payload, COALESCE(prev_hash, ''), COALESCE(hash, ''), created_at
+FROM bot_audit
+%s
+ORDER BY created_at DESC, seq DESC
+LIMIT $%d OFFSET $%d
+`, where, len(args)-1, len(args)), args...)
+	if err != nil {
+		return nil, fmt.Errorf("search audit records: %w", err)
+	}
+	defer rows.Close()
+
+	out := make([]AuditRecord, 0, filter.Limit)
+	for rows.Next() {
+		record, err := scanAuditRecord(rows)
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, record)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterate audit records: %w", err)
+	}
+	return out, nil
+}
+
+// VerifyChain walks the chain from its first hashed record; rows written before the chain existed are skipped.
+func (r *AuditRepo) VerifyChain(ctx context.Context) (AuditChainReport, error) {
+	if r.pool == nil {
+		return AuditChainReport{}, fmt.Errorf("postgres pool is nil")
+	}
+
+	rows, err := r.pool.Query(ctx, `
+SELECT id::text, seq, actor_tg_id, action, payload, COALESCE(prev_hash, ''), COALESCE(hash, ''), created_at
+FROM bot_audit
+WHERE seq >= (SELECT MIN(seq) FROM bot_audit WHERE hash IS NOT NULL)
+ORDER BY seq ASC
+`)
+	if err != nil {
+		return AuditChainReport{}, fmt.Errorf("load audit chain: %w", err)
+	}
+	defer rows.Close()
+
+	report := AuditChainReport{OK: true}
+	prevHash := ""
+	for rows.Next() {
+		record, err := scanAuditRecord(rows)
+		if err != nil {
+			return AuditChainReport{}, err
+		}
+		report.Checked++
+		if record.Hash == "" || record.PrevHash != prevHash || AuditChainHash(prevHash, record) != record.Hash {
+			report.OK = false
+			report.BrokenID = record.ID
+			return report, nil
+		}
+		prevHash = record.Hash
+	}
+	if err := rows.Err(); err != nil {
+		return AuditChainReport{}, fmt.Errorf("iterate audit chain: %w", err)
+	}
+	return report, nil
+}
+
+// AuditChainHash must stay byte-for-byte identical to bot_moderator's model.AuditChainHash.
+// The payload is canonicalized because jsonb does not preserve key order or whitespace.
+func AuditChainHash(prevHash string, record AuditRecord) string {
+	var b strings.Builder
+	b.WriteString(prevHash)
+	b.WriteByte('|')
+	b.WriteString(strconv.FormatInt(record.ActorTGID, 10))
+	b.WriteByte('|')
+	b.WriteString(record.Action)
+	b.WriteByte('|')
+	b.Write(canonicalAuditPayload(record.Payload))
+	b.WriteByte('|')
+	b.WriteString(record.CreatedAt.UTC().Truncate(time.Microsecond).Format(time.RFC3339Nano))
+
+	sum := sha256.Sum256([]byte(b.String()))
+	return hex.EncodeToString(sum[:])
+}
+
+func canonicalAuditPayload(payload json.RawMessage) []byte {
+	if len(bytes.TrimSpace(payload)) == 0 {
+		return []byte(`{}`)
+	}
+	decoder := json.NewDecoder(bytes.NewReader(payload))
+	decoder.UseNumber()
+	var value any
+	if err := decoder.Decode(&value); err != nil {
+		return bytes.TrimSpace(payload)
+	}
+	canonical, err := json.Marshal(value)
+	if err != nil {
+		return bytes.TrimSpace(payload)
+	}
+	return canonical
+}
+
+func scanAuditRecord(rows pgx.Rows) (AuditRecord, error) {
+	var record AuditRecord
+	var payload []byte
+	if err := rows.Scan(
+		&record.ID,
+		&record.Seq,
+		&record.ActorTGID,
+		&record.Action,
+		&payload,
+		&record.PrevHash,
+		&record.Hash,
+		&record.CreatedAt,
+	); err != nil {
+		return AuditRecord{}, fmt.Errorf("scan audit record: %w", err)
+	}
+	record.Payload = json.RawMessage(payload)
+	record.CreatedAt = record.CreatedAt.UTC()
+	return record, nil
+}
@@ -47,6 +47,7 @@ type FeedQuery struct {
 	RadiusKM         int
 	ViewerLat        *float64
 	ViewerLon        *float64
+	VerifiedOnly     bool
 	HasCursor        bool
 	CursorPriority   int
 	CursorCreatedAt  time.Time
@@ -77,6 +78,7 @@ type CandidateProfileRecord struct {
 	Languages   []string
 	Goals       []string
 	IsPlus      bool
+	IsVerified  bool
 }
 
 type FeedCandidate struct {
@@ -92,6 +94,7 @@ type FeedCandidate struct {
 	GoalsPriority int
 	RankScore     *float64
 	DistanceKM    *float64
+	IsVerified    bool
 	CreatedAt     time.Time
 }
 
@@ -196,6 +199,7 @@ SELECT
 		)))
 		ELSE NULL
 	END AS distance_km,
+	p.verification_status = 'VERIFIED' AS is_verified,
 	p.created_at
 FROM profiles p
 LEFT JOIN LATERAL (
@@ -220,6 +224,9 @@ WHERE
 		OR LOWER(p.looking_for) = LOWER($8)
 	)
 	AND DATE_PART('year', AGE($2::timestamptz, p.birthdate::timestamp))::int BETWEEN $9 AND $10
+	AND ($22::boolean = FALSE OR p.verification_status = 'VERIFIED')
+	AND NOT `+ActiveBanExists("p.user_id")+`
+	AND NOT `+WaitlistedExists("p.user_id")+`
 	AND NOT EXISTS (
 		SELECT 1
 		FROM blocks b
@@ -296,6 +303,7 @@ LIMIT $21
 		cursorCreatedAt,          // $19
 		q.CursorUserID,           // $20
 		q.Limit,                  // $21
+		q.VerifiedOnly,           // $22
 	)
 	if err != nil {
 		return nil, fmt.Errorf("list feed candidates: %w", err)
@@ -319,6 +327,7 @@ LIMIT $21
 			&item.GoalsPriority,
 			&rankScore,
 			&item.DistanceKM,
+			&item.IsVerified,
 			&item.CreatedAt,
 		); err != nil {
 			return nil, fmt.Errorf("scan feed candidate: %w", err)
@@ -378,19 +387,17 @@ SELECT
 	COALESCE(p.eye_color, ''),
 	COALESCE(p.languages, '{}'::text[]),
 	COALESCE(p.goals, '{}'::text[]),
-	COALESCE(e.plus_expires_at > $3::timestamptz, FALSE) AS is_plus
+	COALESCE(e.plus_expires_at > $3::timestamptz, FALSE) AS is_plus,
+	p.verification_status = 'VERIFIED' AS is_verified
 FROM profiles p
 LEFT JOIN profiles vp ON vp.user_id = $1
 LEFT JOIN entitlements e ON e.user_id = p.user_id
-LEFT JOIN user_bans ub ON ub.user_id = (
-	'00000000-0000-0000-0000-' ||
-	LPAD(TO_HEX((p.user_id & 281474976710655)::bigint), 12, '0')
-)::uuid
 WHERE
 	p.user_id = $2
 	AND p.approved = TRUE
 	AND p.birthdate IS NOT NULL
-	AND COALESCE(ub.banned, FALSE) = FALSE
+	AND NOT `+ActiveBanExists("p.user_id")+`
+	AND NOT `+WaitlistedExists("p.user_id")+`
 	AND NOT EXISTS (
 		SELECT 1
 		FROM blocks b
@@ -426,6 +433,7 @@ LIMIT 1
 		&record.Languages,
 		&record.Goals,
 		&record.IsPlus,
+		&record.IsVerified,
 	)
 	if err != nil {
 		if errors.Is(err, pgx.ErrNoRows) {
@@ -116,6 +116,8 @@ JOIN profiles p ON p.user_id = l.from_user_id
 WHERE
 	l.to_user_id = $1
 	AND p.approved = TRUE
+	AND NOT `+ActiveBanExists("l.from_user_id")+`
+	AND NOT `+WaitlistedExists("l.from_user_id")+`
 	AND NOT EXISTS (
 		SELECT 1
 		FROM blocks b
@@ -153,6 +155,8 @@ JOIN profiles p ON p.user_id = l.from_user_id
 WHERE
 	l.to_user_id = $1
 	AND p.approved = TRUE
+	AND NOT `+ActiveBanExists("l.from_user_id")+`
+	AND NOT `+WaitlistedExists("l.from_user_id")+`
 	AND NOT EXISTS (
 		SELECT 1
 		FROM blocks b
@@ -212,6 +216,8 @@ JOIN profiles p ON p.user_id = l.from_user_id
 WHERE
 	l.to_user_id = $1
 	AND p.approved = TRUE
+	AND NOT `+ActiveBanExists("l.from_user_id")+`
+	AND NOT `+WaitlistedExists("l.from_user_id")+`
 	AND NOT EXISTS (
 		SELECT 1
 		FROM blocks b
@@ -12,6 +12,8 @@ import (
 	mediasvc "github.com/ivankudzin/tgapp/backend/internal/services/media"
 )
 
+var ErrMediaNotFound = errors.New("media not found")
+
 type MediaRepo struct {
 	pool *pgxpool.Pool
 }
@@ -33,7 +35,7 @@ func NewMediaRepo(pool *pgxpool.Pool) *MediaRepo {
 	return &MediaRepo{pool: pool}
 }
 
-func (r *MediaRepo) CreatePhoto(ctx context.Context, userID int64, objectKey string) (mediasvc.PhotoRecord, error) {
+func (r *MediaRepo) CreatePhoto(ctx context.Context, userID int64, objectKey string, hashes mediasvc.ImageHashes) (mediasvc.PhotoRecord, error) {
 	if r.pool == nil {
 		return mediasvc.PhotoRecord{}, fmt.Errorf("postgres pool is nil")
 	}
@@ -77,15 +79,24 @@ FOR UPDATE
 		return mediasvc.PhotoRecord{}, mediasvc.ErrPhotoLimitReached
 	}
 
+	var phash, dhash *int64
+	var bands []int32
+	if !hashes.IsZero() {
+		p, d := int64(hashes.PHash), int64(hashes.DHash)
+		phash, dhash = &p, &d
+		bands = mediasvc.HashBands(hashes.PHash)
+	}
+
 	var record mediasvc.PhotoRecord
 	err = tx.QueryRow(ctx, `
-INSERT INTO media (user_id, kind, s3_key, position, status, created_at, updated_at)
-VALUES ($1, 'photo', $2, $3, 'active', NOW(), NOW())
+INSERT INTO media (user_id, kind, s3_key, position, status, phash, dhash, phash_bands, created_at, updated_at)
+VALUES ($1, 'photo', $2, $3, 'active', $4, $5, $6, NOW(), NOW())
 RETURNING id, position, s3_key, created_at
-`, userID, objectKey, position).Scan(&record.ID, &record.Position, &record.ObjectKey, &record.CreatedAt)
+`, userID, objectKey, position, phash, dhash, bands).Scan(&record.ID, &record.Position, &record.ObjectKey, &record.CreatedAt)
 	if err != nil {
 		return mediasvc.PhotoRecord{}, fmt.Errorf("insert media photo: %w", err)
 	}
+	record.Hashes = hashes
 
 	if err := tx.Commit(ctx); err != nil {
 		return mediasvc.PhotoRecord{}, fmt.Errorf("commit transaction: %w", err)
@@ -100,7 +111,7 @@ func (r *MediaRepo) ListActivePhotos(ctx context.Context, userID int64) ([]media
 	}
 
 	rows, err := r.pool.Query(ctx, `
-SELECT id, position, s3_key, created_at
+SELECT id, position, s3_key, COALESCE(phash, 0), COALESCE(dhash, 0), created_at
 FROM media
 WHERE user_id = $1 AND kind = 'photo' AND status = 'active'
 ORDER BY position ASC, created_at ASC
@@ -113,9 +124,11 @@ ORDER BY position ASC, created_at ASC
 	photos := make([]mediasvc.PhotoRecord, 0)
 	for rows.Next() {
 		var record mediasvc.PhotoRecord
-		if err := rows.Scan(&record.ID, &record.Position, &record.ObjectKey, &record.CreatedAt); err != nil {
+		var phash, dhash int64
+		if err := rows.Scan(&record.ID, &record.Position, &record.ObjectKey, &phash, &dhash, &record.CreatedAt); err != nil {
 			return nil, fmt.Errorf("scan active photo: %w", err)
 		}
+		record.Hashes = mediasvc.ImageHashes{PHash: uint64(phash), DHash: uint64(dhash)}
 		photos = append(photos, record)
 	}
 
@@ -126,6 +139,127 @@ ORDER BY position ASC, created_at ASC
 	return photos, nil
 }
 
+func (r *MediaRepo) FindHashCandidates(ctx context.Context, userID int64, bands []int32, limit int) ([]mediasvc.HashCandidate, error) {
+	if r.pool == nil {
+		return nil, fmt.Errorf("postgres pool is nil")
+	}
+	if len(bands) == 0 {
+		return []mediasvc.HashCandidate{}, nil
+	}
+	if limit <= 0 {
+		limit = 50
+	}
+
+	rows, err := r.pool.Query(ctx, `
+(
+	SELECT 'USER' AS source, m.id, m.user_id, 0::BIGINT AS banned_id, '' AS label, m.phash, COALESCE(m.dhash, 0)
+	FROM media m
+	WHERE m.kind = 'photo'
+	  AND m.status = 'active'
+	  AND m.user_id <> $1
+	  AND m.phash_bands && $2::INTEGER[]
+	ORDER BY m.created_at DESC
+	LIMIT $3
+)
+UNION ALL
+(
+	SELECT 'BANNED' AS source, COALESCE(b.source_media_id, 0), 0::BIGINT, b.id, b.label, b.phash, COALESCE(b.dhash, 0)
+	FROM banned_image_hashes b
+	WHERE b.phash_bands && $2::INTEGER[]
+	ORDER BY b.created_at DESC
+	LIMIT $3
+)
+`, userID, bands, limit)
+	if err != nil {
+		return nil, fmt.Errorf("find hash candidates: %w", err)
+	}
+	defer rows.Close()
+
+	candidates := make([]mediasvc.HashCandidate, 0)
+	for rows.Next() {
+		var candidate mediasvc.HashCandidate
+		var phash, dhash int64
+		if err := rows.Scan(
+			&candidate.Source,
+			&candidate.MediaID,
+			&candidate.UserID,
+			&candidate.BannedImageID,
+			&candidate.Label,
+			&phash,
+			&dhash,
+		); err != nil {
+			return nil, fmt.Errorf("scan hash candidate: %w", err)
+		}
+		candidate.Hashes = mediasvc.ImageHashes{PHash: uint64(phash), DHash: uint64(dhash)}
+		candidates = append(candidates, candidate)
+	}
+	if rows.Err() != nil {
+		return nil, fmt.Errorf("iterate hash candidates: %w", rows.Err())
+	}
+
+	return candidates, nil
+}
+
+func (r *MediaRepo) ReplacePhotoMatches(ctx context.Context, mediaID int64, matches []mediasvc.PhotoMatch) error {
+	if r.pool == nil {
+		return fmt.Errorf("postgres pool is nil")
+	}
+
+	tx, err := r.pool.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("begin transaction: %w", err)
+	}
+	defer func() {
+		_ = tx.Rollback(ctx)
+	}()
+
+	if _, err := tx.Exec(ctx, `DELETE FROM media_hash_matches WHERE media_id = $1`, mediaID); err != nil {
+		return fmt.Errorf("delete photo matches: %w", err)
+	}
+
+	for _, match := range matches {
+		_, err := tx.Exec(ctx, `
+INSERT INTO media_hash_matches (media_id, source, matched_media_id, matched_user_id, banned_image_id, distance, created_at)
+VALUES ($1, $2, NULLIF($3, 0), NULLIF($4, 0), NULLIF($5, 0), $6, NOW())
+`, mediaID, match.Source, match.MatchedMediaID, match.MatchedUserID, match.BannedImageID, match.Distance)
+		if err != nil {
+			return fmt.Errorf("insert photo match: %w", err)
+		}
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("commit transaction: %w", err)
+	}
+	return nil
+}
+
+func (r *MediaRepo) BanPhotoHash(ctx context.Context, mediaID int64, label string, actorTGID int64) (int64, error) {
+	if r.pool == nil {
+		return 0, fmt.Errorf("postgres pool is nil")
+	}
+
+	var createdByTGID *int64
+	if actorTGID != 0 {
+		createdByTGID = &actorTGID
+	}
+
+	var id int64
+	err := r.pool.QueryRow(ctx, `
+INSERT INTO banned_image_hashes (phash, dhash, phash_bands, label, source_media_id, created_by_tg_id, created_at)
+SELECT phash, dhash, phash_bands, $2, id, $3, NOW()
+FROM media
+WHERE id = $1 AND kind = 'photo' AND phash IS NOT NULL
+RETURNING id
+`, mediaID, label, createdByTGID).Scan(&id)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return 0, ErrMediaNotFound
+		}
+		return 0, fmt.Errorf("insert banned image hash: %w", err)
+	}
+	return id, nil
+}
+
 func (r *MediaRepo) CreateCircle(ctx context.Context, userID int64, objectKey string) (CircleRecord, error) {
 	if r.pool == nil {
 		return CircleRecord{}, fmt.Errorf("postgres pool is nil")
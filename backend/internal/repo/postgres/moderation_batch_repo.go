@@ -0,0 +1,289 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+)
+
+const MaxModerationBatchSize = 10
+
+const (
+	ModerationBatchApprove         = "APPROVE"
+	ModerationBatchApproveVerified = "APPROVE_VERIFIED"
+	ModerationBatchReject          = "REJECT"
+)
+
+type ModerationBatchDecision struct {
+	ItemID          int64
+	Decision        string
+	ReasonCode      string
+	ReasonText      string
+	RequiredFixStep string
+	// Verification is the profile verification status to set alongside the decision, if any.
+	// A reject without one puts a PENDING verification back to its previous status.
+	Verification string
+}
+
+// AcquirePendingBatch locks up to limit pending items for the actor under one lock deadline.
+// Live locks the actor already holds count towards the batch and are extended with it.
+func (r *ModerationRepo) AcquirePendingBatch(ctx context.Context, actorTGID int64, limit int, lockDuration time.Duration) ([]ModerationItemRecord, error) {
+	if r.pool == nil {
+		return nil, fmt.Errorf("postgres pool is nil")
+	}
+	if actorTGID == 0 {
+		return nil, fmt.Errorf("invalid actor tg id")
+	}
+	if limit <= 0 || limit > MaxModerationBatchSize {
+		return nil, fmt.Errorf("invalid moderation batch size")
+	}
+	if lockDuration <= 0 {
+		lockDuration = 10 * time.Minute
+	}
+	seconds := int64(lockDuration / time.Second)
+
+	tx, err := r.pool.Begin(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("begin batch acquire transaction: %w", err)
+	}
+	defer func() {
+		_ = tx.Rollback(ctx)
+	}()
+
+	skills := append([]string(nil), ModeratorSkills...)
+	err = tx.QueryRow(ctx, `
+SELECT skills
+FROM moderator_workload
+WHERE moderator_tg_id = $1
+`, actorTGID).Scan(&skills)
+	if err != nil && !errors.Is(err, pgx.ErrNoRows) {
+		return nil, fmt.Errorf("load moderator workload: %w", err)
+	}
+
+	rows, err := tx.Query(ctx, `
+UPDATE moderation_items
+SET
+	locked_at = CASE WHEN assigned_at IS NOT NULL THEN NOW() ELSE locked_at END,
+	locked_until = NOW() + make_interval(secs => $2),
+	assigned_at = NULL,
+	updated_at = NOW()
+WHERE id IN (
+	SELECT id
+	FROM moderation_items
+	WHERE locked_by_tg_id = $1
+	  AND locked_until > NOW()
+	  AND UPPER(status) = 'PENDING'
+	ORDER BY locked_at ASC, id ASC
+	LIMIT $3
+	FOR UPDATE
+)
+RETURNING id, user_id, status, reason_text, required_fix_step, eta_bucket, locked_by_tg_id, locked_until, locked_at, created_at, updated_at
+`, actorTGID, seconds, limit)
+	if err != nil {
+		return nil, fmt.Errorf("extend held moderation locks: %w", err)
+	}
+	items, err := scanAcquiredModerationItems(rows)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(items) < limit {
+		rows, err = tx.Query(ctx, `
+WITH candidate AS (`+moderationQueueCandidatesSQL+`), expired AS (
+	INSERT INTO moderation_lock_expiries (moderation_item_id, moderator_tg_id, locked_at, expired_at)
+	SELECT id, locked_by_tg_id, locked_at, locked_until
+	FROM candidate
+	WHERE locked_by_tg_id IS NOT NULL
+	  AND locked_until IS NOT NULL
+)
+UPDATE moderation_items mi
+SET
+	locked_by_tg_id = $1,
+	locked_at = NOW(),
+	locked_until = NOW() + make_interval(secs => $2),
+	assigned_at = NULL,
+	updated_at = NOW()
+FROM candidate
+WHERE mi.id = candidate.id
+RETURNING mi.id, mi.user_id, mi.status, mi.reason_text, mi.required_fix_step, mi.eta_bucket, mi.locked_by_tg_id, mi.locked_until, mi.locked_at, mi.created_at, mi.updated_at
+`, actorTGID, seconds, skills, int64(ModerationQueueAgingThreshold/time.Second), limit-len(items))
+		if err != nil {
+			return nil, fmt.Errorf("acquire pending moderation batch: %w", err)
+		}
+		acquired, err := scanAcquiredModerationItems(rows)
+		if err != nil {
+			return nil, err
+		}
+		items = append(items, acquired...)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, fmt.Errorf("commit batch acquire transaction: %w", err)
+	}
+
+	sort.SliceStable(items, func(i, j int) bool {
+		if !items[i].CreatedAt.Equal(items[j].CreatedAt) {
+			return items[i].CreatedAt.Before(items[j].CreatedAt)
+		}
+		return items[i].ID < items[j].ID
+	})
+	return items, nil
+}
+
+// DecideBatch applies every decision in one transaction: if any item is no longer pending or is
+// held by another moderator, nothing is written. LockedAt of the returned records is the review start.
+func (r *ModerationRepo) DecideBatch(ctx context.Context, moderatorTGID int64, decisions []ModerationBatchDecision, etaBucket string) ([]ModerationItemRecord, error) {
+	if r.pool == nil {
+		return nil, fmt.Errorf("postgres pool is nil")
+	}
+	if moderatorTGID == 0 {
+		return nil, fmt.Errorf("invalid actor tg id")
+	}
+	if len(decisions) == 0 || len(decisions) > MaxModerationBatchSize {
+		return nil, fmt.Errorf("invalid moderation batch size")
+	}
+
+	tx, err := r.pool.Begin(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("begin batch decision transaction: %w", err)
+	}
+	defer func() {
+		_ = tx.Rollback(ctx)
+	}()
+
+	out := make([]ModerationItemRecord, 0, len(decisions))
+	for _, decision := range decisions {
+		status := "APPROVED"
+		approved := true
+		if decision.Decision == ModerationBatchReject {
+			status = "REJECTED"
+			approved = false
+		}
+
+		item := ModerationItemRecord{}
+		err := tx.QueryRow(ctx, `
+UPDATE moderation_items
+SET
+	status = $3,
+	moderator_tg_id = $2,
+	reason_code = NULLIF($4, ''),
+	reason_text = NULLIF($5, ''),
+	required_fix_step = NULLIF($6, ''),
+	review_started_at = locked_at,
+	locked_by_tg_id = NULL,
+	locked_at = NULL,
+	locked_until = NULL,
+	decided_at = NOW(),
+	eta_bucket = COALESCE(NULLIF($7, ''), eta_bucket),
+	updated_at = NOW()
+WHERE id = $1
+  AND UPPER(status) = 'PENDING'
+  AND (locked_by_tg_id IS NULL OR locked_by_tg_id = $2 OR locked_until < NOW())
+RETURNING id, user_id, status, review_started_at, created_at, updated_at
+`,
+			decision.ItemID,
+			moderatorTGID,
+			status,
+			strings.TrimSpace(decision.ReasonCode),
+			strings.TrimSpace(decision.ReasonText),
+			strings.TrimSpace(decision.RequiredFixStep),
+			etaBucket,
+		).Scan(&item.ID, &item.UserID, &item.Status, &item.LockedAt, &item.CreatedAt, &item.UpdatedAt)
+		if err != nil {
+			if errors.Is(err, pgx.ErrNoRows) {
+				var exists bool
+				if err := tx.QueryRow(ctx, `SELECT EXISTS (SELECT 1 FROM moderation_items WHERE id = $1)`, decision.ItemID).Scan(&exists); err != nil {
+					return nil, fmt.Errorf("check moderation item: %w", err)
+				}
+				if !exists {
+					return nil, fmt.Errorf("moderation item %d: %w", decision.ItemID, ErrModerationItemNotFound)
+				}
+				return nil, fmt.Errorf("moderation item %d: %w", decision.ItemID, ErrModerationItemNotPending)
+			}
+			return nil, fmt.Errorf("apply batch moderation decision: %w", err)
+		}
+
+		if _, err := tx.Exec(ctx, `
+INSERT INTO profiles (
+	user_id,
+	display_name,
+	moderation_status,
+	approved,
+	updated_at
+) VALUES ($1, '', $2, $3, NOW())
+ON CONFLICT (user_id) DO UPDATE SET
+	moderation_status = EXCLUDED.moderation_status,
+	approved = EXCLUDED.approved,
+	updated_at = NOW()
+`, item.UserID, status, approved); err != nil {
+			return nil, fmt.Errorf("apply moderation decision: %w", err)
+		}
+		if decision.Verification != "" {
+			if _, err := tx.Exec(ctx, `
+UPDATE profiles
+SET
+	verification_status = $2,
+	verified_at = CASE WHEN $2 = 'VERIFIED' THEN NOW() ELSE NULL END,
+	updated_at = NOW()
+WHERE user_id = $1
+`, item.UserID, decision.Verification); err != nil {
+				return nil, fmt.Errorf("set profile verification status: %w", err)
+			}
+		} else if decision.Decision == ModerationBatchReject {
+			if _, err := tx.Exec(ctx, restorePendingVerificationQuery, item.UserID); err != nil {
+				return nil, fmt.Errorf("restore profile verification: %w", err)
+			}
+		}
+		out = append(out, item)
+	}
+
+	// Deciding part of a batch counts as activity on the rest, so the idle reassigner leaves it alone.
+	if _, err := tx.Exec(ctx, `
+UPDATE moderation_items
+SET locked_at = NOW(), updated_at = NOW()
+WHERE locked_by_tg_id = $1
+  AND locked_until > NOW()
+  AND UPPER(status) = 'PENDING'
+`, moderatorTGID); err != nil {
+		return nil, fmt.Errorf("touch held moderation locks: %w", err)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, fmt.Errorf("commit batch decision transaction: %w", err)
+	}
+	return out, nil
+}
+
+func scanAcquiredModerationItems(rows pgx.Rows) ([]ModerationItemRecord, error) {
+	defer rows.Close()
+
+	items := make([]ModerationItemRecord, 0, MaxModerationBatchSize)
+	for rows.Next() {
+		item := ModerationItemRecord{}
+		if err := rows.Scan(
+			&item.ID,
+			&item.UserID,
+			&item.Status,
+			&item.ReasonText,
+			&item.RequiredFixStep,
+			&item.ETABucket,
+			&item.LockedByTGID,
+			&item.LockedUntil,
+			&item.LockedAt,
+			&item.CreatedAt,
+			&item.UpdatedAt,
+		); err != nil {
+			return nil, fmt.Errorf("scan acquired moderation item: %w", err)
+		}
+		items = append(items, item)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterate acquired moderation items: %w", err)
+	}
+	return items, nil
+}
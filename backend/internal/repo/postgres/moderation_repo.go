@@ -12,6 +12,8 @@ import (
 )
 
 var ErrModerationItemNotFound = errors.New("moderation item not found")
+var ErrModerationItemNotPending = errors.New("moderation item is not pending")
+var ErrModerationItemLocked = errors.New("moderation item is locked by another moderator")
 
 type ModerationRepo struct {
 	pool *pgxpool.Pool
@@ -25,11 +27,14 @@ type ModerationItemRecord struct {
 	ReasonText      *string
 	RequiredFixStep *string
 	ETABucket       string
-	LockedByTGID    *int64
-	LockedUntil     *time.Time
-	LockedAt        *time.Time
-	CreatedAt       time.Time
-	UpdatedAt       time.Time
+	ModeratorTGID   *int64
+	// ModeratorAdminUserID is set when the decision was made from the web admin panel.
+	ModeratorAdminUserID *int64
+	LockedByTGID         *int64
+	LockedUntil          *time.Time
+	LockedAt             *time.Time
+	CreatedAt            time.Time
+	UpdatedAt            time.Time
 }
 
 func NewModerationRepo(pool *pgxpool.Pool) *ModerationRepo {
@@ -70,7 +75,7 @@ func (r *ModerationRepo) GetLatestByUser(ctx context.Context, userID int64) (Mod
 	}
 
 	item, err := r.queryOne(ctx, `
-SELECT id, user_id, status, reason_text, required_fix_step, eta_bucket, created_at, updated_at
+SELECT id, user_id, status, reason_text, required_fix_step, reason_code, moderator_tg_id, eta_bucket, created_at, updated_at
 FROM moderation_items
 WHERE user_id = $1
 ORDER BY created_at DESC, id DESC
@@ -100,6 +105,9 @@ WHERE UPPER(status) = 'PENDING'
 	return count, nil
 }
 
+// AcquireNextPending hands the actor their next item. A card auto-assigned to the actor, or
+// one they already hold once they are at their cap, is resumed; otherwise the highest
+// priority unlocked item matching the actor's skills is locked.
 func (r *ModerationRepo) AcquireNextPending(ctx context.Context, actorTGID int64, lockDuration time.Duration) (ModerationItemRecord, error) {
 	if r.pool == nil {
 		return ModerationItemRecord{}, fmt.Errorf("postgres pool is nil")
@@ -124,27 +132,74 @@ func (r *ModerationRepo) AcquireNextPending(ctx context.Context, actorTGID int64
 		seconds = int64((10 * time.Minute) / time.Second)
 	}
 
-	item := ModerationItemRecord{}
+	maxActive := DefaultModeratorMaxActiveItems
+	skills := append([]string(nil), ModeratorSkills...)
 	err = tx.QueryRow(ctx, `
-WITH candidate AS (
-	SELECT id
+SELECT max_active_items, skills
+FROM moderator_workload
+WHERE moderator_tg_id = $1
+`, actorTGID).Scan(&maxActive, &skills)
+	if err != nil && !errors.Is(err, pgx.ErrNoRows) {
+		return ModerationItemRecord{}, fmt.Errorf("load moderator workload: %w", err)
+	}
+
+	var heldID int64
+	var heldAssigned bool
+	var heldCount int
+	err = tx.QueryRow(ctx, `
+WITH held AS (
+	SELECT id, assigned_at, locked_at
 	FROM moderation_items
-	WHERE UPPER(status) = 'PENDING'
-	  AND (locked_until IS NULL OR locked_until < NOW())
-	ORDER BY created_at ASC, id ASC
-	FOR UPDATE SKIP LOCKED
-	LIMIT 1
+	WHERE locked_by_tg_id = $1
+	  AND locked_until > NOW()
+	  AND UPPER(status) = 'PENDING'
+	FOR UPDATE
+)
+SELECT id, assigned_at IS NOT NULL, (SELECT COUNT(*) FROM held)
+FROM held
+ORDER BY assigned_at IS NOT NULL DESC, locked_at ASC, id ASC
+LIMIT 1
+`, actorTGID).Scan(&heldID, &heldAssigned, &heldCount)
+	if err != nil && !errors.Is(err, pgx.ErrNoRows) {
+		return ModerationItemRecord{}, fmt.Errorf("load held moderation locks: %w", err)
+	}
+
+	var row pgx.Row
+	if heldID > 0 && (heldAssigned || heldCount >= maxActive) {
+		row = tx.QueryRow(ctx, `
+UPDATE moderation_items
+SET
+	locked_at = CASE WHEN assigned_at IS NOT NULL THEN NOW() ELSE locked_at END,
+	locked_until = NOW() + make_interval(secs => $2),
+	assigned_at = NULL,
+	updated_at = NOW()
+WHERE id = $1
+RETURNING id, user_id, status, reason_text, required_fix_step, eta_bucket, locked_by_tg_id, locked_until, locked_at, created_at, updated_at
+`, heldID, seconds)
+	} else {
+		row = tx.QueryRow(ctx, `
+WITH candidate AS (`+moderationQueueCandidatesSQL+`), expired AS (
+	INSERT INTO moderation_lock_expiries (moderation_item_id, moderator_tg_id, locked_at, expired_at)
+	SELECT id, locked_by_tg_id, locked_at, locked_until
+	FROM candidate
+	WHERE locked_by_tg_id IS NOT NULL
+	  AND locked_until IS NOT NULL
 )
 UPDATE moderation_items mi
 SET
 	locked_by_tg_id = $1,
 	locked_at = NOW(),
 	locked_until = NOW() + make_interval(secs => $2),
+	assigned_at = NULL,
 	updated_at = NOW()
 FROM candidate
 WHERE mi.id = candidate.id
 RETURNING mi.id, mi.user_id, mi.status, mi.reason_text, mi.required_fix_step, mi.eta_bucket, mi.locked_by_tg_id, mi.locked_until, mi.locked_at, mi.created_at, mi.updated_at
-`, actorTGID, seconds).Scan(
+`, actorTGID, seconds, skills, int64(ModerationQueueAgingThreshold/time.Second), 1)
+	}
+
+	item := ModerationItemRecord{}
+	err = row.Scan(
 		&item.ID,
 		&item.UserID,
 		&item.Status,
@@ -177,7 +232,7 @@ func (r *ModerationRepo) GetNextPending(ctx context.Context) (ModerationItemReco
 	}
 
 	item, err := r.queryOne(ctx, `
-SELECT id, user_id, status, reason_text, required_fix_step, eta_bucket, created_at, updated_at
+SELECT id, user_id, status, reason_text, required_fix_step, reason_code, moderator_tg_id, eta_bucket, created_at, updated_at
 FROM moderation_items
 WHERE UPPER(status) = 'PENDING'
   AND (locked_until IS NULL OR locked_until < NOW())
@@ -200,7 +255,7 @@ func (r *ModerationRepo) GetByID(ctx context.Context, itemID int64) (ModerationI
 	}
 
 	item, err := r.queryOne(ctx, `
-SELECT id, user_id, status, reason_text, required_fix_step, eta_bucket, created_at, updated_at
+SELECT id, user_id, status, reason_text, required_fix_step, reason_code, moderator_tg_id, eta_bucket, created_at, updated_at
 FROM moderation_items
 WHERE id = $1
 LIMIT 1
@@ -231,7 +286,9 @@ WHERE id = $1
 	return nil
 }
 
-func (r *ModerationRepo) MarkApproved(ctx context.Context, itemID int64, moderatorTGID int64, etaBucket string) error {
+// MarkApproved decides a pending item unless another moderator holds a live lock on it.
+// moderatorAdminUserID is zero for decisions made from the bot.
+func (r *ModerationRepo) MarkApproved(ctx context.Context, itemID int64, moderatorTGID, moderatorAdminUserID int64, etaBucket string) error {
 	if r.pool == nil {
 		return fmt.Errorf("postgres pool is nil")
 	}
@@ -239,14 +296,16 @@ func (r *ModerationRepo) MarkApproved(ctx context.Context, itemID int64, moderat
 		return fmt.Errorf("invalid moderation item id")
 	}
 
-	if _, err := r.pool.Exec(ctx, `
+	tag, err := r.pool.Exec(ctx, `
 UPDATE moderation_items
 SET
 	status = 'APPROVED',
 	moderator_tg_id = $2,
+	moderator_admin_user_id = NULLIF($4, 0),
 	reason_code = NULL,
 	reason_text = NULL,
 	required_fix_step = NULL,
+	review_started_at = locked_at,
 	locked_by_tg_id = NULL,
 	locked_at = NULL,
 	locked_until = NULL,
@@ -254,9 +313,15 @@ SET
 	eta_bucket = COALESCE(NULLIF($3, ''), eta_bucket),
 	updated_at = NOW()
 WHERE id = $1
-`, itemID, moderatorTGID, etaBucket); err != nil {
+  AND UPPER(status) = 'PENDING'
+  AND (locked_by_tg_id IS NULL OR locked_by_tg_id = $2 OR locked_until < NOW())
+`, itemID, moderatorTGID, etaBucket, moderatorAdminUserID)
+	if err != nil {
 		return fmt.Errorf("mark moderation approved: %w", err)
 	}
+	if tag.RowsAffected() == 0 {
+		return r.decisionConflict(ctx, itemID)
+	}
 
 	return nil
 }
@@ -264,7 +329,7 @@ WHERE id = $1
 func (r *ModerationRepo) MarkRejected(
 	ctx context.Context,
 	itemID int64,
-	moderatorTGID int64,
+	moderatorTGID, moderatorAdminUserID int64,
 	reasonCode, reasonText, requiredFixStep, etaBucket string,
 ) error {
 	if r.pool == nil {
@@ -274,14 +339,16 @@ func (r *ModerationRepo) MarkRejected(
 		return fmt.Errorf("invalid moderation item id")
 	}
 
-	if _, err := r.pool.Exec(ctx, `
+	tag, err := r.pool.Exec(ctx, `
 UPDATE moderation_items
 SET
 	status = 'REJECTED',
 	moderator_tg_id = $2,
+	moderator_admin_user_id = NULLIF($7, 0),
 	reason_code = NULLIF($3, ''),
 	reason_text = $4,
 	required_fix_step = $5,
+	review_started_at = locked_at,
 	locked_by_tg_id = NULL,
 	locked_at = NULL,
 	locked_until = NULL,
@@ -289,13 +356,152 @@ SET
 	eta_bucket = COALESCE(NULLIF($6, ''), eta_bucket),
 	updated_at = NOW()
 WHERE id = $1
-`, itemID, moderatorTGID, strings.TrimSpace(reasonCode), strings.TrimSpace(reasonText), strings.TrimSpace(requiredFixStep), etaBucket); err != nil {
+  AND UPPER(status) = 'PENDING'
+  AND (locked_by_tg_id IS NULL OR locked_by_tg_id = $2 OR locked_until < NOW())
+`, itemID, moderatorTGID, strings.TrimSpace(reasonCode), strings.TrimSpace(reasonText), strings.TrimSpace(requiredFixStep), etaBucket, moderatorAdminUserID)
+	if err != nil {
 		return fmt.Errorf("mark moderation rejected: %w", err)
 	}
+	if tag.RowsAffected() == 0 {
+		return r.decisionConflict(ctx, itemID)
+	}
 
 	return nil
 }
 
+// ModerationActionRecord is one row of bot_moderation_actions, the per-decision log the
+// moderator bot keeps for work stats and second-review exclusion.
+type ModerationActionRecord struct {
+	ActorTGID        int64
+	ActorRole        string
+	TargetUserID     int64
+	ModerationItemID int64
+	Decision         string
+	ReasonCode       string
+	DurationSec      *int
+	CreatedAt        time.Time
+}
+
+// InsertModerationAction stores ids as the same pseudo UUIDs the moderator bot writes.
+func (r *ModerationRepo) InsertModerationAction(ctx context.Context, action ModerationActionRecord) error {
+	if r.pool == nil {
+		return fmt.Errorf("postgres pool is nil")
+	}
+
+	_, err := r.pool.Exec(ctx, `
+INSERT INTO bot_moderation_actions (
+	actor_tg_id, actor_role, target_user_id, moderation_item_id, decision, reason_code, duration_sec, created_at
+) VALUES (
+	$1, $2,
+	('00000000-0000-0000-0000-' || LPAD(TO_HEX(($3::bigint & 281474976710655)::bigint), 12, '0'))::uuid,
+	('00000000-0000-0000-0000-' || LPAD(TO_HEX(($4::bigint & 281474976710655)::bigint), 12, '0'))::uuid,
+	$5, NULLIF($6, ''), $7, $8
+)
+`, action.ActorTGID, action.ActorRole, action.TargetUserID, action.ModerationItemID,
+		action.Decision, strings.TrimSpace(action.ReasonCode), action.DurationSec, action.CreatedAt)
+	if err != nil {
+		return fmt.Errorf("insert moderation action: %w", err)
+	}
+	return nil
+}
+
+// decisionConflict explains why a guarded decision update matched no row.
+func (r *ModerationRepo) decisionConflict(ctx context.Context, itemID int64) error {
+	var pending, locked bool
+	err := r.pool.QueryRow(ctx, `
+SELECT
+	UPPER(status) = 'PENDING',
+	locked_by_tg_id IS NOT NULL AND locked_until >= NOW()
+FROM moderation_items
+WHERE id = $1
+`, itemID).Scan(&pending, &locked)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return ErrModerationItemNotFound
+		}
+		return fmt.Errorf("check moderation item: %w", err)
+	}
+	if pending && locked {
+		return ErrModerationItemLocked
+	}
+	return ErrModerationItemNotPending
+}
+
+// GetForReview loads an item together with its current lock holder for the web review screen.
+func (r *ModerationRepo) GetForReview(ctx context.Context, itemID int64) (ModerationItemRecord, error) {
+	if r.pool == nil {
+		return ModerationItemRecord{}, fmt.Errorf("postgres pool is nil")
+	}
+	if itemID <= 0 {
+		return ModerationItemRecord{}, fmt.Errorf("invalid moderation item id")
+	}
+
+	var item ModerationItemRecord
+	err := r.pool.QueryRow(ctx, `
+SELECT
+	id,
+	user_id,
+	status,
+	eta_bucket,
+	moderator_tg_id,
+	moderator_admin_user_id,
+	CASE WHEN locked_until >= NOW() THEN locked_by_tg_id END,
+	CASE WHEN locked_until >= NOW() THEN locked_at END,
+	CASE WHEN locked_until >= NOW() THEN locked_until END,
+	created_at,
+	updated_at
+FROM moderation_items
+WHERE id = $1
+`, itemID).Scan(
+		&item.ID,
+		&item.UserID,
+		&item.Status,
+		&item.ETABucket,
+		&item.ModeratorTGID,
+		&item.ModeratorAdminUserID,
+		&item.LockedByTGID,
+		&item.LockedAt,
+		&item.LockedUntil,
+		&item.CreatedAt,
+		&item.UpdatedAt,
+	)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return ModerationItemRecord{}, ErrModerationItemNotFound
+		}
+		return ModerationItemRecord{}, fmt.Errorf("get moderation item for review: %w", err)
+	}
+	return item, nil
+}
+
+type ModerationQueueStats struct {
+	PendingCount     int
+	LockedCount      int
+	HeldByActorCount int
+	OldestPendingAt  *time.Time
+}
+
+// GetQueueStats counts the pending queue; only live locks count as locked.
+func (r *ModerationRepo) GetQueueStats(ctx context.Context, actorTGID int64) (ModerationQueueStats, error) {
+	if r.pool == nil {
+		return ModerationQueueStats{}, fmt.Errorf("postgres pool is nil")
+	}
+
+	var stats ModerationQueueStats
+	if err := r.pool.QueryRow(ctx, `
+SELECT
+	COUNT(*),
+	COUNT(*) FILTER (WHERE locked_by_tg_id IS NOT NULL AND locked_until >= NOW()),
+	COUNT(*) FILTER (WHERE locked_by_tg_id = $1 AND locked_until >= NOW()),
+	MIN(created_at)
+FROM moderation_items
+WHERE UPPER(status) = 'PENDING'
+`, actorTGID).Scan(&stats.PendingCount, &stats.LockedCount, &stats.HeldByActorCount, &stats.OldestPendingAt); err != nil {
+		return ModerationQueueStats{}, fmt.Errorf("get moderation queue stats: %w", err)
+	}
+	return stats, nil
+}
+
 func (r *ModerationRepo) DeleteByMediaID(ctx context.Context, mediaID int64) error {
 	if r.pool == nil {
 		return fmt.Errorf("postgres pool is nil")
@@ -322,6 +528,8 @@ func (r *ModerationRepo) queryOne(ctx context.Context, query string, args ...int
 		&item.Status,
 		&item.ReasonText,
 		&item.RequiredFixStep,
+		&item.ReasonCode,
+		&item.ModeratorTGID,
 		&item.ETABucket,
 		&item.CreatedAt,
 		&item.UpdatedAt,
@@ -334,3 +542,164 @@ func (r *ModerationRepo) queryOne(ctx context.Context, query string, args ...int
 	}
 	return item, nil
 }
+
+type ModerationSLAStats struct {
+	DecidedCount      int
+	ApprovedCount     int
+	RejectedCount     int
+	WaitP50Sec        float64
+	WaitP90Sec        float64
+	WaitP99Sec        float64
+	PendingCount      int
+	OldestPendingSec  float64
+	StaleLockCount    int
+	LockExpiredCount  int
+	ModeratorLatency  []ModeratorLatencyRecord
+	RejectReasonCount []RejectReasonCountRecord
+}
+
+type ModeratorLatencyRecord struct {
+	ModeratorTGID  int64
+	Decisions      int
+	Approved       int
+	Rejected       int
+	AvgDecisionSec float64
+	P90DecisionSec float64
+}
+
+type RejectReasonCountRecord struct {
+	ReasonCode string
+	Count      int
+}
+
+func (r *ModerationRepo) CountDecidedSince(ctx context.Context, since time.Time) (int, error) {
+	if r.pool == nil {
+		return 0, fmt.Errorf("postgres pool is nil")
+	}
+
+	var count int
+	if err := r.pool.QueryRow(ctx, `
+SELECT COUNT(*)
+FROM moderation_items
+WHERE decided_at >= $1
+`, since.UTC()).Scan(&count); err != nil {
+		return 0, fmt.Errorf("count decided moderation items: %w", err)
+	}
+
+	return count, nil
+}
+
+func (r *ModerationRepo) GetSLAStats(ctx context.Context, since time.Time) (ModerationSLAStats, error) {
+	if r.pool == nil {
+		return ModerationSLAStats{}, fmt.Errorf("postgres pool is nil")
+	}
+
+	since = since.UTC()
+	stats := ModerationSLAStats{
+		ModeratorLatency:  []ModeratorLatencyRecord{},
+		RejectReasonCount: []RejectReasonCountRecord{},
+	}
+
+	if err := r.pool.QueryRow(ctx, `
+SELECT
+	COUNT(*),
+	COUNT(*) FILTER (WHERE UPPER(status) = 'APPROVED'),
+	COUNT(*) FILTER (WHERE UPPER(status) = 'REJECTED'),
+	COALESCE(percentile_cont(0.5) WITHIN GROUP (ORDER BY EXTRACT(EPOCH FROM decided_at - created_at)), 0),
+	COALESCE(percentile_cont(0.9) WITHIN GROUP (ORDER BY EXTRACT(EPOCH FROM decided_at - created_at)), 0),
+	COALESCE(percentile_cont(0.99) WITHIN GROUP (ORDER BY EXTRACT(EPOCH FROM decided_at - created_at)), 0)
+FROM moderation_items
+WHERE decided_at >= $1
+`, since).Scan(
+		&stats.DecidedCount,
+		&stats.ApprovedCount,
+		&stats.RejectedCount,
+		&stats.WaitP50Sec,
+		&stats.WaitP90Sec,
+		&stats.WaitP99Sec,
+	); err != nil {
+		return ModerationSLAStats{}, fmt.Errorf("aggregate moderation wait times: %w", err)
+	}
+
+	if err := r.pool.QueryRow(ctx, `
+SELECT
+	COUNT(*),
+	COALESCE(MAX(EXTRACT(EPOCH FROM NOW() - created_at)), 0),
+	COUNT(*) FILTER (WHERE locked_by_tg_id IS NOT NULL AND locked_until < NOW())
+FROM moderation_items
+WHERE UPPER(status) = 'PENDING'
+`).Scan(&stats.PendingCount, &stats.OldestPendingSec, &stats.StaleLockCount); err != nil {
+		return ModerationSLAStats{}, fmt.Errorf("aggregate pending moderation items: %w", err)
+	}
+
+	if err := r.pool.QueryRow(ctx, `
+SELECT COUNT(*)
+FROM moderation_lock_expiries
+WHERE expired_at >= $1
+`, since).Scan(&stats.LockExpiredCount); err != nil {
+		return ModerationSLAStats{}, fmt.Errorf("count moderation lock expiries: %w", err)
+	}
+
+	rows, err := r.pool.Query(ctx, `
+SELECT
+	moderator_tg_id,
+	COUNT(*),
+	COUNT(*) FILTER (WHERE UPPER(status) = 'APPROVED'),
+	COUNT(*) FILTER (WHERE UPPER(status) = 'REJECTED'),
+	COALESCE(AVG(EXTRACT(EPOCH FROM decided_at - review_started_at)), 0),
+	COALESCE(percentile_cont(0.9) WITHIN GROUP (ORDER BY EXTRACT(EPOCH FROM decided_at - review_started_at)), 0)
+FROM moderation_items
+WHERE decided_at >= $1
+  AND moderator_tg_id IS NOT NULL
+GROUP BY moderator_tg_id
+ORDER BY COUNT(*) DESC, moderator_tg_id ASC
+`, since)
+	if err != nil {
+		return ModerationSLAStats{}, fmt.Errorf("aggregate moderator latency: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var rec ModeratorLatencyRecord
+		if err := rows.Scan(
+			&rec.ModeratorTGID,
+			&rec.Decisions,
+			&rec.Approved,
+			&rec.Rejected,
+			&rec.AvgDecisionSec,
+			&rec.P90DecisionSec,
+		); err != nil {
+			return ModerationSLAStats{}, fmt.Errorf("scan moderator latency: %w", err)
+		}
+		stats.ModeratorLatency = append(stats.ModeratorLatency, rec)
+	}
+	if err := rows.Err(); err != nil {
+		return ModerationSLAStats{}, fmt.Errorf("iterate moderator latency: %w", err)
+	}
+
+	reasonRows, err := r.pool.Query(ctx, `
+SELECT COALESCE(NULLIF(UPPER(reason_code), ''), 'OTHER') AS code, COUNT(*)
+FROM moderation_items
+WHERE decided_at >= $1
+  AND UPPER(status) = 'REJECTED'
+GROUP BY code
+ORDER BY COUNT(*) DESC, code ASC
+`, since)
+	if err != nil {
+		return ModerationSLAStats{}, fmt.Errorf("aggregate reject reasons: %w", err)
+	}
+	defer reasonRows.Close()
+
+	for reasonRows.Next() {
+		var rec RejectReasonCountRecord
+		if err := reasonRows.Scan(&rec.ReasonCode, &rec.Count); err != nil {
+			return ModerationSLAStats{}, fmt.Errorf("scan reject reason count: %w", err)
+		}
+		stats.RejectReasonCount = append(stats.RejectReasonCount, rec)
+	}
+	if err := reasonRows.Err(); err != nil {
+		return ModerationSLAStats{}, fmt.Errorf("iterate reject reason counts: %w", err)
+	}
+
+	return stats, nil
+}
@@ -0,0 +1,474 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+var ErrModerationReviewNotFound = errors.New("moderation review not found")
+var ErrModerationReviewExists = errors.New("moderation review already exists")
+var ErrModerationReviewNotPending = errors.New("moderation review is not pending")
+var ErrModerationReviewLockConflict = errors.New("moderation review is not locked by the reviewer")
+
+type ModerationReviewRepo struct {
+	pool *pgxpool.Pool
+}
+
+type ModerationReviewRecord struct {
+	ID                    int64
+	ModerationItemID      int64
+	UserID                int64
+	Kind                  string
+	Status                string
+	OriginalDecision      string
+	OriginalReasonCode    *string
+	OriginalModeratorTGID *int64
+	AppealMessage         *string
+	ReviewerTGID          *int64
+	ReviewNote            *string
+	LockedByTGID          *int64
+	LockedAt              *time.Time
+	LockedUntil           *time.Time
+	DecidedAt             *time.Time
+	CreatedAt             time.Time
+	UpdatedAt             time.Time
+}
+
+type ModerationReviewCreate struct {
+	ModerationItemID      int64
+	UserID                int64
+	Kind                  string
+	OriginalDecision      string
+	OriginalReasonCode    *string
+	OriginalModeratorTGID *int64
+	AppealMessage         *string
+}
+
+// ModerationReviewResolution is a second opinion on a review. Override is set when the review
+// overturns the original decision.
+type ModerationReviewResolution struct {
+	ReviewID     int64
+	ReviewerTGID int64
+	Status       string
+	Note         string
+	Override     *ModerationReviewOverride
+}
+
+type ModerationReviewOverride struct {
+	Status             string
+	ReasonCode         string
+	ReasonText         string
+	RequiredFixStep    string
+	Approved           bool
+	RejectVerification bool
+}
+
+type ModerationReviewKindStats struct {
+	Kind       string
+	Pending    int
+	Decided    int
+	Upheld     int
+	Overturned int
+}
+
+type ModerationReviewModeratorStats struct {
+	ModeratorTGID int64
+	Reviewed      int
+	Upheld        int
+	Overturned    int
+}
+
+type ModerationReviewStats struct {
+	Kinds      []ModerationReviewKindStats
+	Moderators []ModerationReviewModeratorStats
+}
+
+// moderationReviewActionItemUUID matches the pseudo UUID the moderator bot stores in
+// bot_moderation_actions.moderation_item_id for backend moderation items.
+const moderationReviewActionItemUUID = `('00000000-0000-0000-0000-' || lpad(to_hex(mr.moderation_item_id), 12, '0'))::uuid`
+
+const moderationReviewColumns = `id, moderation_item_id, user_id, kind, status, original_decision, original_reason_code,
+	original_moderator_tg_id, appeal_message, reviewer_tg_id, review_note, locked_by_tg_id, locked_at, locked_until,
+	decided_at, created_at, updated_at`
+
+func NewModerationReviewRepo(pool *pgxpool.Pool) *ModerationReviewRepo {
+	return &ModerationReviewRepo{pool: pool}
+}
+
+func (r *ModerationReviewRepo) Create(ctx context.Context, in ModerationReviewCreate) (ModerationReviewRecord, error) {
+	if r.pool == nil {
+		return ModerationReviewRecord{}, fmt.Errorf("postgres pool is nil")
+	}
+	if in.ModerationItemID <= 0 || in.UserID <= 0 || strings.TrimSpace(in.Kind) == "" {
+		return ModerationReviewRecord{}, fmt.Errorf("invalid moderation review payload")
+	}
+
+	record, err := r.queryOne(ctx, `
+INSERT INTO moderation_reviews (
+	moderation_item_id,
+	user_id,
+	kind,
+	original_decision,
+	original_reason_code,
+	original_moderator_tg_id,
+	appeal_message
+)
+VALUES ($1, $2, $3, $4, $5, $6, $7)
+ON CONFLICT (moderation_item_id, kind) DO NOTHING
+RETURNING `+moderationReviewColumns,
+		in.ModerationItemID,
+		in.UserID,
+		strings.ToUpper(strings.TrimSpace(in.Kind)),
+		strings.ToUpper(strings.TrimSpace(in.OriginalDecision)),
+		in.OriginalReasonCode,
+		in.OriginalModeratorTGID,
+		in.AppealMessage,
+	)
+	if errors.Is(err, ErrModerationReviewNotFound) {
+		return ModerationReviewRecord{}, ErrModerationReviewExists
+	}
+	if err != nil {
+		return ModerationReviewRecord{}, fmt.Errorf("create moderation review: %w", err)
+	}
+
+	return record, nil
+}
+
+func (r *ModerationReviewRepo) GetByID(ctx context.Context, reviewID int64) (ModerationReviewRecord, error) {
+	if r.pool == nil {
+		return ModerationReviewRecord{}, fmt.Errorf("postgres pool is nil")
+	}
+	if reviewID <= 0 {
+		return ModerationReviewRecord{}, fmt.Errorf("invalid moderation review id")
+	}
+
+	return r.queryOne(ctx, `
+SELECT `+moderationReviewColumns+`
+FROM moderation_reviews
+WHERE id = $1
+LIMIT 1
+`, reviewID)
+}
+
+func (r *ModerationReviewRepo) GetForItem(ctx context.Context, itemID int64, kind string) (ModerationReviewRecord, error) {
+	if r.pool == nil {
+		return ModerationReviewRecord{}, fmt.Errorf("postgres pool is nil")
+	}
+	if itemID <= 0 {
+		return ModerationReviewRecord{}, fmt.Errorf("invalid moderation item id")
+	}
+
+	return r.queryOne(ctx, `
+SELECT `+moderationReviewColumns+`
+FROM moderation_reviews
+WHERE moderation_item_id = $1
+  AND kind = $2
+LIMIT 1
+`, itemID, strings.ToUpper(strings.TrimSpace(kind)))
+}
+
+// AcquireNextPending locks the oldest pending review of the given kind that the reviewer
+// neither decided originally nor acted on in the moderator bot.
+func (r *ModerationReviewRepo) AcquireNextPending(ctx context.Context, kind string, reviewerTGID int64, lockDuration time.Duration) (ModerationReviewRecord, error) {
+	if r.pool == nil {
+		return ModerationReviewRecord{}, fmt.Errorf("postgres pool is nil")
+	}
+	if reviewerTGID == 0 {
+		return ModerationReviewRecord{}, fmt.Errorf("invalid reviewer tg id")
+	}
+	if lockDuration <= 0 {
+		lockDuration = 10 * time.Minute
+	}
+
+	return r.queryOne(ctx, `
+WITH candidate AS (
+	SELECT mr.id
+	FROM moderation_reviews mr
+	JOIN moderation_items mi ON mi.id = mr.moderation_item_id
+	WHERE mr.kind = $1
+	  AND mr.status = 'PENDING'
+	  AND (mr.locked_until IS NULL OR mr.locked_until < NOW())
+	  AND COALESCE(mr.original_moderator_tg_id, 0) <> $2
+	  AND COALESCE(mi.moderator_tg_id, 0) <> $2
+	  AND NOT EXISTS (
+		SELECT 1
+		FROM bot_moderation_actions bma
+		WHERE bma.moderation_item_id = `+moderationReviewActionItemUUID+`
+		  AND bma.actor_tg_id = $2
+	  )
+	ORDER BY mr.created_at ASC, mr.id ASC
+	FOR UPDATE OF mr SKIP LOCKED
+	LIMIT 1
+)
+UPDATE moderation_reviews mr
+SET
+	locked_by_tg_id = $2,
+	locked_at = NOW(),
+	locked_until = NOW() + make_interval(secs => $3),
+	updated_at = NOW()
+FROM candidate
+WHERE mr.id = candidate.id
+RETURNING `+prefixColumns("mr.", moderationReviewColumns),
+		strings.ToUpper(strings.TrimSpace(kind)),
+		reviewerTGID,
+		int64(lockDuration/time.Second),
+	)
+}
+
+// Resolve records the second opinion and, for an overturn, rewrites the moderation item and the
+// profile in the same transaction. Only the reviewer holding an unexpired lock may resolve. The
+// profile is left alone when the user has a newer moderation item: that decision is the current one.
+func (r *ModerationReviewRepo) Resolve(ctx context.Context, in ModerationReviewResolution) (ModerationReviewRecord, error) {
+	if r.pool == nil {
+		return ModerationReviewRecord{}, fmt.Errorf("postgres pool is nil")
+	}
+	if in.ReviewID <= 0 || in.ReviewerTGID == 0 {
+		return ModerationReviewRecord{}, fmt.Errorf("invalid moderation review payload")
+	}
+
+	var record ModerationReviewRecord
+	err := WithTx(ctx, r.pool, func(txCtx context.Context, tx pgx.Tx) error {
+		var err error
+		record, err = scanModerationReview(tx.QueryRow(txCtx, `
+UPDATE moderation_reviews
+SET
+	status = $3,
+	reviewer_tg_id = $2,
+	review_note = NULLIF($4, ''),
+	locked_by_tg_id = NULL,
+	locked_at = NULL,
+	locked_until = NULL,
+	decided_at = NOW(),
+	updated_at = NOW()
+WHERE id = $1
+  AND status = 'PENDING'
+  AND COALESCE(original_moderator_tg_id, 0) <> $2
+  AND locked_by_tg_id = $2
+  AND locked_until > NOW()
+RETURNING `+moderationReviewColumns,
+			in.ReviewID,
+			in.ReviewerTGID,
+			strings.ToUpper(strings.TrimSpace(in.Status)),
+			strings.TrimSpace(in.Note),
+		))
+		if errors.Is(err, ErrModerationReviewNotFound) {
+			return resolveConflict(txCtx, tx, in.ReviewID)
+		}
+		if err != nil {
+			return fmt.Errorf("resolve moderation review: %w", err)
+		}
+		if in.Override == nil {
+			return nil
+		}
+
+		return overrideReviewedDecision(txCtx, tx, record, in.ReviewerTGID, *in.Override)
+	})
+	if err != nil {
+		return ModerationReviewRecord{}, err
+	}
+
+	return record, nil
+}
+
+// resolveConflict explains why the resolve update matched no row.
+func resolveConflict(ctx context.Context, tx pgx.Tx, reviewID int64) error {
+	var status string
+	err := tx.QueryRow(ctx, `SELECT status FROM moderation_reviews WHERE id = $1`, reviewID).Scan(&status)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return ErrModerationReviewNotFound
+	}
+	if err != nil {
+		return fmt.Errorf("load moderation review status: %w", err)
+	}
+	if status != "PENDING" {
+		return ErrModerationReviewNotPending
+	}
+	return ErrModerationReviewLockConflict
+}
+
+// overrideReviewedDecision rewrites the outcome of the reviewed item, keeping the original
+// decided_at so SLA wait times stay intact.
+func overrideReviewedDecision(ctx context.Context, tx pgx.Tx, review ModerationReviewRecord, reviewerTGID int64, in ModerationReviewOverride) error {
+	status := strings.ToUpper(strings.TrimSpace(in.Status))
+	tag, err := tx.Exec(ctx, `
+UPDATE moderation_items
+SET
+	status = $3,
+	moderator_tg_id = $2,
+	reason_code = NULLIF($4, ''),
+	reason_text = NULLIF($5, ''),
+	required_fix_step = NULLIF($6, ''),
+	updated_at = NOW()
+WHERE id = $1
+  AND UPPER(status) IN ('APPROVED', 'REJECTED')
+`, review.ModerationItemID, reviewerTGID, status, strings.TrimSpace(in.ReasonCode), strings.TrimSpace(in.ReasonText), strings.TrimSpace(in.RequiredFixStep))
+	if err != nil {
+		return fmt.Errorf("override moderation decision: %w", err)
+	}
+	if tag.RowsAffected() == 0 {
+		return ErrModerationItemNotFound
+	}
+
+	var superseded bool
+	if err := tx.QueryRow(ctx, `
+SELECT EXISTS (
+	SELECT 1
+	FROM moderation_items
+	WHERE user_id = $1
+	  AND id > $2
+)
+`, review.UserID, review.ModerationItemID).Scan(&superseded); err != nil {
+		return fmt.Errorf("check newer moderation items: %w", err)
+	}
+	if superseded {
+		return nil
+	}
+
+	if _, err := tx.Exec(ctx, `
+INSERT INTO profiles (
+	user_id,
+	display_name,
+	moderation_status,
+	approved,
+	updated_at
+) VALUES ($1, '', $2, $3, NOW())
+ON CONFLICT (user_id) DO UPDATE SET
+	moderation_status = EXCLUDED.moderation_status,
+	approved = EXCLUDED.approved,
+	updated_at = NOW()
+`, review.UserID, status, in.Approved); err != nil {
+		return fmt.Errorf("apply moderation decision: %w", err)
+	}
+
+	if in.RejectVerification {
+		if _, err := tx.Exec(ctx, `
+UPDATE profiles
+SET
+	verification_status = 'REJECTED',
+	verified_at = NULL,
+	updated_at = NOW()
+WHERE user_id = $1
+`, review.UserID); err != nil {
+			return fmt.Errorf("reject profile verification: %w", err)
+		}
+	}
+
+	return nil
+}
+
+func (r *ModerationReviewRepo) Stats(ctx context.Context, since time.Time) (ModerationReviewStats, error) {
+	if r.pool == nil {
+		return ModerationReviewStats{}, fmt.Errorf("postgres pool is nil")
+	}
+
+	stats := ModerationReviewStats{
+		Kinds:      []ModerationReviewKindStats{},
+		Moderators: []ModerationReviewModeratorStats{},
+	}
+
+	rows, err := r.pool.Query(ctx, `
+SELECT
+	kind,
+	COUNT(*) FILTER (WHERE status = 'PENDING'),
+	COUNT(*) FILTER (WHERE status <> 'PENDING' AND decided_at >= $1),
+	COUNT(*) FILTER (WHERE status = 'UPHELD' AND decided_at >= $1),
+	COUNT(*) FILTER (WHERE status = 'OVERTURNED' AND decided_at >= $1)
+FROM moderation_reviews
+GROUP BY kind
+ORDER BY kind ASC
+`, since.UTC())
+	if err != nil {
+		return ModerationReviewStats{}, fmt.Errorf("aggregate moderation reviews: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var item ModerationReviewKindStats
+		if err := rows.Scan(&item.Kind, &item.Pending, &item.Decided, &item.Upheld, &item.Overturned); err != nil {
+			return ModerationReviewStats{}, fmt.Errorf("scan moderation review stats: %w", err)
+		}
+		stats.Kinds = append(stats.Kinds, item)
+	}
+	if err := rows.Err(); err != nil {
+		return ModerationReviewStats{}, fmt.Errorf("iterate moderation review stats: %w", err)
+	}
+
+	moderatorRows, err := r.pool.Query(ctx, `
+SELECT
+	original_moderator_tg_id,
+	COUNT(*),
+	COUNT(*) FILTER (WHERE status = 'UPHELD'),
+	COUNT(*) FILTER (WHERE status = 'OVERTURNED')
+FROM moderation_reviews
+WHERE status <> 'PENDING'
+  AND decided_at >= $1
+  AND original_moderator_tg_id IS NOT NULL
+GROUP BY original_moderator_tg_id
+ORDER BY COUNT(*) DESC, original_moderator_tg_id ASC
+`, since.UTC())
+	if err != nil {
+		return ModerationReviewStats{}, fmt.Errorf("aggregate moderation review agreement: %w", err)
+	}
+	defer moderatorRows.Close()
+
+	for moderatorRows.Next() {
+		var item ModerationReviewModeratorStats
+		if err := moderatorRows.Scan(&item.ModeratorTGID, &item.Reviewed, &item.Upheld, &item.Overturned); err != nil {
+			return ModerationReviewStats{}, fmt.Errorf("scan moderation review agreement: %w", err)
+		}
+		stats.Moderators = append(stats.Moderators, item)
+	}
+	if err := moderatorRows.Err(); err != nil {
+		return ModerationReviewStats{}, fmt.Errorf("iterate moderation review agreement: %w", err)
+	}
+
+	return stats, nil
+}
+
+func (r *ModerationReviewRepo) queryOne(ctx context.Context, query string, args ...interface{}) (ModerationReviewRecord, error) {
+	return scanModerationReview(r.pool.QueryRow(ctx, query, args...))
+}
+
+func scanModerationReview(row pgx.Row) (ModerationReviewRecord, error) {
+	var record ModerationReviewRecord
+	err := row.Scan(
+		&record.ID,
+		&record.ModerationItemID,
+		&record.UserID,
+		&record.Kind,
+		&record.Status,
+		&record.OriginalDecision,
+		&record.OriginalReasonCode,
+		&record.OriginalModeratorTGID,
+		&record.AppealMessage,
+		&record.ReviewerTGID,
+		&record.ReviewNote,
+		&record.LockedByTGID,
+		&record.LockedAt,
+		&record.LockedUntil,
+		&record.DecidedAt,
+		&record.CreatedAt,
+		&record.UpdatedAt,
+	)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return ModerationReviewRecord{}, ErrModerationReviewNotFound
+		}
+		return ModerationReviewRecord{}, fmt.Errorf("query moderation review: %w", err)
+	}
+	return record, nil
+}
+
+func prefixColumns(prefix string, columns string) string {
+	parts := strings.Split(columns, ",")
+	for i, part := range parts {
+		parts[i] = prefix + strings.TrimSpace(part)
+	}
+	return strings.Join(parts, ", ")
+}
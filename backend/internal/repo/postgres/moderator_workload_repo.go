@@ -0,0 +1,525 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+var ErrModeratorShiftNotFound = errors.New("moderator shift not found")
+
+const DefaultModeratorMaxActiveItems = 1
+
+// ModerationQueueAgingThreshold lifts items that waited this long above every priority tier,
+// so a steady stream of re-reviews and reports cannot starve ordinary submissions.
+const ModerationQueueAgingThreshold = 2 * time.Hour
+
+var ModeratorSkills = []string{"PHOTO", "CIRCLE", "REPORTS"}
+
+// moderationItemSkillSQL classifies a queue item (aliased mi, with media m and the lateral
+// rep.reported flag joined) into the skill needed to review it.
+const moderationItemSkillSQL = `CASE WHEN rep.reported THEN 'REPORTS' WHEN m.kind = 'circle' THEN 'CIRCLE' ELSE 'PHOTO' END`
+
+// moderationQueueCandidatesSQL selects up to $5 unlocked pending items for skills $3 in queue
+// priority order: items older than $4 seconds, re-reviews, reported users, segments the supply
+// balance controller marks as under-represented, Plus users, then FIFO.
+const moderationQueueCandidatesSQL = `
+	SELECT mi.id, mi.locked_by_tg_id, mi.locked_at, mi.locked_until
+	FROM moderation_items mi
+	LEFT JOIN media m ON mi.target_type = 'media' AND m.id = mi.target_id
+	LEFT JOIN user_entitlements ue ON ue.user_id = mi.user_id
+	CROSS JOIN LATERAL (
+		SELECT EXISTS (
+			SELECT 1 FROM reports rp
+			WHERE rp.target_user_id = mi.user_id
+			  AND rp.created_at > NOW() - INTERVAL '7 days'
+		) AS reported
+	) rep
+	WHERE UPPER(mi.status) = 'PENDING'
+	  AND (mi.locked_until IS NULL OR mi.locked_until < NOW())
+	  AND (` + moderationItemSkillSQL + `) = ANY($3::TEXT[])
+	ORDER BY
+		mi.created_at < NOW() - make_interval(secs => $4) DESC,
+		(mi.target_type = 'profile' OR EXISTS (
+			SELECT 1 FROM moderation_items prev
+			WHERE prev.user_id = mi.user_id
+			  AND prev.id <> mi.id
+			  AND UPPER(prev.status) = 'REJECTED'
+		)) DESC,
+		rep.reported DESC,
+		EXISTS (
+			SELECT 1
+			FROM profiles bp
+			JOIN supply_balance_decisions sb ON sb.city_id = TRIM(bp.city_id)
+			WHERE bp.user_id = mi.user_id
+			  AND sb.prioritize_moderation
+			  AND sb.under_gender = LOWER(TRIM(bp.gender))
+		) DESC,
+		COALESCE(ue.plus_active_until > NOW(), FALSE) DESC,
+		mi.created_at ASC,
+		mi.id ASC
+	FOR UPDATE OF mi SKIP LOCKED
+	LIMIT $5
+`
+
+// moderatorShiftActiveSQL matches shifts (aliased s) that cover the current Minsk wall-clock
+// time, including the tail of yesterday's overnight shift.
+const moderatorShiftActiveSQL = `(
+	(s.weekday = EXTRACT(ISODOW FROM (NOW() AT TIME ZONE 'Europe/Minsk'))
+	 AND (NOW() AT TIME ZONE 'Europe/Minsk')::time >= s.starts_at
+	 AND ((NOW() AT TIME ZONE 'Europe/Minsk')::time < s.ends_at OR s.ends_at <= s.starts_at))
+	OR
+	(s.ends_at <= s.starts_at
+	 AND s.weekday = EXTRACT(ISODOW FROM (NOW() AT TIME ZONE 'Europe/Minsk') - INTERVAL '1 day')
+	 AND (NOW() AT TIME ZONE 'Europe/Minsk')::time < s.ends_at)
+)`
+
+const activeLocksByModeratorSQL = `(
+	SELECT COUNT(*)
+	FROM moderation_items al
+	WHERE al.locked_by_tg_id = %s
+	  AND al.locked_until > NOW()
+	  AND UPPER(al.status) = 'PENDING'
+)`
+
+type ModeratorWorkloadRepo struct {
+	pool *pgxpool.Pool
+}
+
+type ModeratorShiftRecord struct {
+	ID            int64
+	ModeratorTGID int64
+	Weekday       int
+	StartsAt      string
+	EndsAt        string
+	CreatedByTGID *int64
+	CreatedAt     time.Time
+}
+
+type ModeratorWorkloadRecord struct {
+	ModeratorTGID  int64
+	MaxActiveItems int
+	Skills         []string
+	ActiveItems    int
+	OnShift        bool
+	Shifts         []ModeratorShiftRecord
+	UpdatedByTGID  *int64
+	UpdatedAt      *time.Time
+}
+
+type LockReassignmentRecord struct {
+	ModerationItemID int64
+	UserID           int64
+	Skill            string
+	FromTGID         int64
+	ToTGID           int64
+	IdleSec          float64
+}
+
+type ShiftThroughputRecord struct {
+	ShiftID       int64
+	ModeratorTGID int64
+	StartsAt      time.Time
+	EndsAt        time.Time
+	Decisions     int
+	Approved      int
+	Rejected      int
+}
+
+func NewModeratorWorkloadRepo(pool *pgxpool.Pool) *ModeratorWorkloadRepo {
+	return &ModeratorWorkloadRepo{pool: pool}
+}
+
+// ListModeratorWorkload returns everyone with settings, a shift or a live lock, with defaults
+// filled in for moderators that were never configured.
+func (r *ModeratorWorkloadRepo) ListModeratorWorkload(ctx context.Context) ([]ModeratorWorkloadRecord, error) {
+	if r.pool == nil {
+		return nil, fmt.Errorf("postgres pool is nil")
+	}
+
+	rows, err := r.pool.Query(ctx, `
+WITH staff AS (
+	SELECT moderator_tg_id FROM moderator_workload
+	UNION
+	SELECT moderator_tg_id FROM moderator_shifts
+	UNION
+	SELECT locked_by_tg_id FROM moderation_items
+	WHERE locked_by_tg_id IS NOT NULL
+	  AND locked_until > NOW()
+	  AND UPPER(status) = 'PENDING'
+)
+SELECT
+	st.moderator_tg_id,
+	COALESCE(w.max_active_items, $1),
+	COALESCE(w.skills, $2::TEXT[]),
+	w.updated_by_tg_id,
+	w.updated_at,
+	`+fmt.Sprintf(activeLocksByModeratorSQL, "st.moderator_tg_id")+`,
+	EXISTS (
+		SELECT 1 FROM moderator_shifts s
+		WHERE s.moderator_tg_id = st.moderator_tg_id
+		  AND `+moderatorShiftActiveSQL+`
+	)
+FROM staff st
+LEFT JOIN moderator_workload w ON w.moderator_tg_id = st.moderator_tg_id
+ORDER BY st.moderator_tg_id ASC
+`, DefaultModeratorMaxActiveItems, ModeratorSkills)
+	if err != nil {
+		return nil, fmt.Errorf("list moderator workload: %w", err)
+	}
+	defer rows.Close()
+
+	items := make([]ModeratorWorkloadRecord, 0, 8)
+	index := make(map[int64]int)
+	for rows.Next() {
+		var item ModeratorWorkloadRecord
+		if err := rows.Scan(
+			&item.ModeratorTGID,
+			&item.MaxActiveItems,
+			&item.Skills,
+			&item.UpdatedByTGID,
+			&item.UpdatedAt,
+			&item.ActiveItems,
+			&item.OnShift,
+		); err != nil {
+			return nil, fmt.Errorf("scan moderator workload: %w", err)
+		}
+		item.Shifts = []ModeratorShiftRecord{}
+		index[item.ModeratorTGID] = len(items)
+		items = append(items, item)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterate moderator workload: %w", err)
+	}
+
+	shifts, err := r.listShifts(ctx)
+	if err != nil {
+		return nil, err
+	}
+	for _, shift := range shifts {
+		if i, ok := index[shift.ModeratorTGID]; ok {
+			items[i].Shifts = append(items[i].Shifts, shift)
+		}
+	}
+	return items, nil
+}
+
+func (r *ModeratorWorkloadRepo) listShifts(ctx context.Context) ([]ModeratorShiftRecord, error) {
+	rows, err := r.pool.Query(ctx, `
+SELECT id, moderator_tg_id, weekday, to_char(starts_at, 'HH24:MI'), to_char(ends_at, 'HH24:MI'), created_by_tg_id, created_at
+FROM moderator_shifts
+ORDER BY moderator_tg_id ASC, weekday ASC, starts_at ASC, id ASC
+`)
+	if err != nil {
+		return nil, fmt.Errorf("list moderator shifts: %w", err)
+	}
+	defer rows.Close()
+
+	items := make([]ModeratorShiftRecord, 0, 16)
+	for rows.Next() {
+		item, err := scanModeratorShift(rows)
+		if err != nil {
+			return nil, fmt.Errorf("scan moderator shift: %w", err)
+		}
+		items = append(items, item)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterate moderator shifts: %w", err)
+	}
+	return items, nil
+}
+
+func (r *ModeratorWorkloadRepo) UpsertModeratorWorkload(ctx context.Context, in ModeratorWorkloadRecord) (ModeratorWorkloadRecord, error) {
+	if r.pool == nil {
+		return ModeratorWorkloadRecord{}, fmt.Errorf("postgres pool is nil")
+	}
+
+	out := ModeratorWorkloadRecord{}
+	err := r.pool.QueryRow(ctx, `
+INSERT INTO moderator_workload (moderator_tg_id, max_active_items, skills, updated_by_tg_id, updated_at)
+VALUES ($1, $2, $3::TEXT[], $4, NOW())
+ON CONFLICT (moderator_tg_id) DO UPDATE SET
+	max_active_items = EXCLUDED.max_active_items,
+	skills = EXCLUDED.skills,
+	updated_by_tg_id = EXCLUDED.updated_by_tg_id,
+	updated_at = NOW()
+RETURNING moderator_tg_id, max_active_items, skills, updated_by_tg_id, updated_at
+`, in.ModeratorTGID, in.MaxActiveItems, in.Skills, in.UpdatedByTGID).Scan(
+		&out.ModeratorTGID,
+		&out.MaxActiveItems,
+		&out.Skills,
+		&out.UpdatedByTGID,
+		&out.UpdatedAt,
+	)
+	if err != nil {
+		return ModeratorWorkloadRecord{}, fmt.Errorf("upsert moderator workload: %w", err)
+	}
+	out.Shifts = []ModeratorShiftRecord{}
+	return out, nil
+}
+
+func (r *ModeratorWorkloadRepo) AddModeratorShift(ctx context.Context, in ModeratorShiftRecord) (ModeratorShiftRecord, error) {
+	if r.pool == nil {
+		return ModeratorShiftRecord{}, fmt.Errorf("postgres pool is nil")
+	}
+
+	out, err := scanModeratorShift(r.pool.QueryRow(ctx, `
+INSERT INTO moderator_shifts (moderator_tg_id, weekday, starts_at, ends_at, created_by_tg_id, created_at)
+VALUES ($1, $2, $3::TIME, $4::TIME, $5, NOW())
+RETURNING id, moderator_tg_id, weekday, to_char(starts_at, 'HH24:MI'), to_char(ends_at, 'HH24:MI'), created_by_tg_id, created_at
+`, in.ModeratorTGID, in.Weekday, in.StartsAt, in.EndsAt, in.CreatedByTGID))
+	if err != nil {
+		return ModeratorShiftRecord{}, fmt.Errorf("insert moderator shift: %w", err)
+	}
+	return out, nil
+}
+
+func (r *ModeratorWorkloadRepo) DeleteModeratorShift(ctx context.Context, shiftID int64) (ModeratorShiftRecord, error) {
+	if r.pool == nil {
+		return ModeratorShiftRecord{}, fmt.Errorf("postgres pool is nil")
+	}
+
+	out, err := scanModeratorShift(r.pool.QueryRow(ctx, `
+DELETE FROM moderator_shifts
+WHERE id = $1
+RETURNING id, moderator_tg_id, weekday, to_char(starts_at, 'HH24:MI'), to_char(ends_at, 'HH24:MI'), created_by_tg_id, created_at
+`, shiftID))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return ModeratorShiftRecord{}, ErrModeratorShiftNotFound
+		}
+		return ModeratorShiftRecord{}, fmt.Errorf("delete moderator shift: %w", err)
+	}
+	return out, nil
+}
+
+type reassignCandidate struct {
+	tgID      int64
+	maxActive int
+	active    int
+	skills    []string
+}
+
+// ReassignIdleLocks moves cards whose holder sat on them longer than idle to the least loaded
+// on-shift moderator with spare capacity and the matching skill. Cards nobody can take keep
+// their lock until it expires normally.
+func (r *ModeratorWorkloadRepo) ReassignIdleLocks(ctx context.Context, idle, lockDuration time.Duration) ([]LockReassignmentRecord, error) {
+	if r.pool == nil {
+		return nil, fmt.Errorf("postgres pool is nil")
+	}
+	if idle <= 0 || lockDuration <= 0 {
+		return nil, fmt.Errorf("invalid idle lock window")
+	}
+
+	tx, err := r.pool.Begin(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("begin reassign transaction: %w", err)
+	}
+	defer func() {
+		_ = tx.Rollback(ctx)
+	}()
+
+	rows, err := tx.Query(ctx, `
+SELECT mi.id, mi.user_id, mi.locked_by_tg_id, EXTRACT(EPOCH FROM NOW() - mi.locked_at)::FLOAT8, `+moderationItemSkillSQL+`
+FROM moderation_items mi
+LEFT JOIN media m ON mi.target_type = 'media' AND m.id = mi.target_id
+CROSS JOIN LATERAL (
+	SELECT EXISTS (
+		SELECT 1 FROM reports rp
+		WHERE rp.target_user_id = mi.user_id
+		  AND rp.created_at > NOW() - INTERVAL '7 days'
+	) AS reported
+) rep
+WHERE UPPER(mi.status) = 'PENDING'
+  AND mi.locked_by_tg_id IS NOT NULL
+  AND mi.locked_until > NOW()
+  AND mi.locked_at < NOW() - make_interval(secs => $1)
+ORDER BY mi.locked_at ASC, mi.id ASC
+LIMIT 50
+FOR UPDATE OF mi SKIP LOCKED
+`, int64(idle/time.Second))
+	if err != nil {
+		return nil, fmt.Errorf("list idle moderation locks: %w", err)
+	}
+	idleItems := make([]LockReassignmentRecord, 0, 8)
+	for rows.Next() {
+		var item LockReassignmentRecord
+		if err := rows.Scan(&item.ModerationItemID, &item.UserID, &item.FromTGID, &item.IdleSec, &item.Skill); err != nil {
+			rows.Close()
+			return nil, fmt.Errorf("scan idle moderation lock: %w", err)
+		}
+		idleItems = append(idleItems, item)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterate idle moderation locks: %w", err)
+	}
+	if len(idleItems) == 0 {
+		return []LockReassignmentRecord{}, nil
+	}
+
+	rows, err = tx.Query(ctx, `
+SELECT DISTINCT
+	s.moderator_tg_id,
+	COALESCE(w.max_active_items, $1),
+	COALESCE(w.skills, $2::TEXT[]),
+	`+fmt.Sprintf(activeLocksByModeratorSQL, "s.moderator_tg_id")+`
+FROM moderator_shifts s
+LEFT JOIN moderator_workload w ON w.moderator_tg_id = s.moderator_tg_id
+WHERE `+moderatorShiftActiveSQL+`
+`, DefaultModeratorMaxActiveItems, ModeratorSkills)
+	if err != nil {
+		return nil, fmt.Errorf("list on-shift moderators: %w", err)
+	}
+	candidates := make([]*reassignCandidate, 0, 8)
+	for rows.Next() {
+		candidate := &reassignCandidate{}
+		if err := rows.Scan(&candidate.tgID, &candidate.maxActive, &candidate.skills, &candidate.active); err != nil {
+			rows.Close()
+			return nil, fmt.Errorf("scan on-shift moderator: %w", err)
+		}
+		candidates = append(candidates, candidate)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterate on-shift moderators: %w", err)
+	}
+
+	seconds := int64(lockDuration / time.Second)
+	moved := make([]LockReassignmentRecord, 0, len(idleItems))
+	for _, item := range idleItems {
+		target := pickReassignCandidate(candidates, item.FromTGID, item.Skill)
+		if target == nil {
+			continue
+		}
+
+		if _, err := tx.Exec(ctx, `
+WITH expired AS (
+	INSERT INTO moderation_lock_expiries (moderation_item_id, moderator_tg_id, locked_at, expired_at)
+	SELECT id, locked_by_tg_id, locked_at, NOW()
+	FROM moderation_items
+	WHERE id = $1
+)
+UPDATE moderation_items
+SET
+	locked_by_tg_id = $2,
+	locked_at = NOW(),
+	locked_until = NOW() + make_interval(secs => $3),
+	assigned_at = NOW(),
+	updated_at = NOW()
+WHERE id = $1
+`, item.ModerationItemID, target.tgID, seconds); err != nil {
+			return nil, fmt.Errorf("reassign moderation item %d: %w", item.ModerationItemID, err)
+		}
+
+		target.active++
+		for _, candidate := range candidates {
+			if candidate.tgID == item.FromTGID && candidate.active > 0 {
+				candidate.active--
+			}
+		}
+		item.ToTGID = target.tgID
+		moved = append(moved, item)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, fmt.Errorf("commit reassign transaction: %w", err)
+	}
+	return moved, nil
+}
+
+func pickReassignCandidate(candidates []*reassignCandidate, holderTGID int64, skill string) *reassignCandidate {
+	var best *reassignCandidate
+	for _, candidate := range candidates {
+		if candidate.tgID == holderTGID || candidate.active >= candidate.maxActive || !containsString(candidate.skills, skill) {
+			continue
+		}
+		if best == nil || candidate.active < best.active || (candidate.active == best.active && candidate.tgID < best.tgID) {
+			best = candidate
+		}
+	}
+	return best
+}
+
+// ShiftThroughput counts decisions made inside every scheduled shift occurrence that overlaps [from, to).
+func (r *ModeratorWorkloadRepo) ShiftThroughput(ctx context.Context, from, to time.Time) ([]ShiftThroughputRecord, error) {
+	if r.pool == nil {
+		return nil, fmt.Errorf("postgres pool is nil")
+	}
+
+	rows, err := r.pool.Query(ctx, `
+WITH days AS (
+	SELECT d::DATE AS day
+	FROM generate_series(
+		(($1::TIMESTAMPTZ AT TIME ZONE 'Europe/Minsk')::DATE - 1)::TIMESTAMP,
+		($2::TIMESTAMPTZ AT TIME ZONE 'Europe/Minsk')::DATE::TIMESTAMP,
+		INTERVAL '1 day'
+	) d
+), occurrences AS (
+	SELECT
+		s.id,
+		s.moderator_tg_id,
+		(days.day + s.starts_at) AT TIME ZONE 'Europe/Minsk' AS starts_at,
+		(days.day + s.ends_at + CASE WHEN s.ends_at <= s.starts_at THEN INTERVAL '1 day' ELSE INTERVAL '0' END) AT TIME ZONE 'Europe/Minsk' AS ends_at
+	FROM moderator_shifts s
+	JOIN days ON EXTRACT(ISODOW FROM days.day) = s.weekday
+)
+SELECT
+	o.id,
+	o.moderator_tg_id,
+	o.starts_at,
+	o.ends_at,
+	COUNT(mi.id),
+	COUNT(mi.id) FILTER (WHERE UPPER(mi.status) = 'APPROVED'),
+	COUNT(mi.id) FILTER (WHERE UPPER(mi.status) = 'REJECTED')
+FROM occurrences o
+LEFT JOIN moderation_items mi
+	ON mi.moderator_tg_id = o.moderator_tg_id
+	AND mi.decided_at >= o.starts_at
+	AND mi.decided_at < o.ends_at
+WHERE o.starts_at < $2
+  AND o.ends_at > $1
+GROUP BY o.id, o.moderator_tg_id, o.starts_at, o.ends_at
+ORDER BY o.starts_at ASC, o.moderator_tg_id ASC
+`, from.UTC(), to.UTC())
+	if err != nil {
+		return nil, fmt.Errorf("query shift throughput: %w", err)
+	}
+	defer rows.Close()
+
+	items := make([]ShiftThroughputRecord, 0, 16)
+	for rows.Next() {
+		var item ShiftThroughputRecord
+		if err := rows.Scan(&item.ShiftID, &item.ModeratorTGID, &item.StartsAt, &item.EndsAt, &item.Decisions, &item.Approved, &item.Rejected); err != nil {
+			return nil, fmt.Errorf("scan shift throughput: %w", err)
+		}
+		items = append(items, item)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterate shift throughput: %w", err)
+	}
+	return items, nil
+}
+
+func scanModeratorShift(row pgx.Row) (ModeratorShiftRecord, error) {
+	var item ModeratorShiftRecord
+	var weekday int16
+	if err := row.Scan(&item.ID, &item.ModeratorTGID, &weekday, &item.StartsAt, &item.EndsAt, &item.CreatedByTGID, &item.CreatedAt); err != nil {
+		return ModeratorShiftRecord{}, err
+	}
+	item.Weekday = int(weekday)
+	return item, nil
+}
+
+func containsString(items []string, value string) bool {
+	for _, item := range items {
+		if item == value {
+			return true
+		}
+	}
+	return false
+}
@@ -22,15 +22,16 @@ type ProfileModerationSnapshot struct {
 }
 
 type ProfileQueueSummary struct {
-	UserID      int64
-	DisplayName string
-	CityID      string
-	Gender      string
-	LookingFor  string
-	Goals       []string
-	Birthdate   *time.Time
-	Occupation  string
-	Education   string
+	UserID             int64
+	DisplayName        string
+	CityID             string
+	Gender             string
+	LookingFor         string
+	Goals              []string
+	Birthdate          *time.Time
+	Occupation         string
+	Education          string
+	VerificationStatus string
 }
 
 func NewProfileRepo(pool *pgxpool.Pool) *ProfileRepo {
@@ -198,6 +199,109 @@ ON CONFLICT (user_id) DO UPDATE SET
 	return nil
 }
 
+func (r *ProfileRepo) SetVerificationStatus(ctx context.Context, userID int64, status string) error {
+	if r.pool == nil {
+		return nil
+	}
+	if userID <= 0 || status == "" {
+		return fmt.Errorf("invalid verification status payload")
+	}
+
+	const query = `
+UPDATE profiles
+SET
+	verification_status = $2,
+	verified_at = CASE WHEN $2 = 'VERIFIED' THEN NOW() ELSE NULL END,
+	updated_at = NOW()
+WHERE user_id = $1
+`
+
+	if _, err := r.pool.Exec(ctx, query, userID, status); err != nil {
+		return fmt.Errorf("set profile verification status: %w", err)
+	}
+
+	return nil
+}
+
+// MarkVerificationPending puts a freshly recorded circle into review and remembers the status
+// it replaces; the verified_at of a verified profile is kept so the badge can be restored.
+func (r *ProfileRepo) MarkVerificationPending(ctx context.Context, userID int64) error {
+	if r.pool == nil {
+		return nil
+	}
+	if userID <= 0 {
+		return fmt.Errorf("invalid user id")
+	}
+
+	if _, err := r.pool.Exec(ctx, `
+UPDATE profiles
+SET
+	verification_previous_status = CASE
+		WHEN verification_status = 'PENDING' THEN verification_previous_status
+		ELSE verification_status
+	END,
+	verification_status = 'PENDING',
+	updated_at = NOW()
+WHERE user_id = $1
+`, userID); err != nil {
+		return fmt.Errorf("mark profile verification pending: %w", err)
+	}
+
+	return nil
+}
+
+// restorePendingVerificationQuery puts a PENDING profile back to the status it had before its
+// circle went to review. It runs on rejects for reasons that say nothing about the circle.
+const restorePendingVerificationQuery = `
+UPDATE profiles
+SET
+	verification_status = COALESCE(verification_previous_status, 'NONE'),
+	verified_at = CASE WHEN verification_previous_status = 'VERIFIED' THEN verified_at ELSE NULL END,
+	verification_previous_status = NULL,
+	updated_at = NOW()
+WHERE user_id = $1
+	AND verification_status = 'PENDING'
+`
+
+func (r *ProfileRepo) RestorePendingVerification(ctx context.Context, userID int64) error {
+	if r.pool == nil {
+		return nil
+	}
+	if userID <= 0 {
+		return fmt.Errorf("invalid user id")
+	}
+
+	if _, err := r.pool.Exec(ctx, restorePendingVerificationQuery, userID); err != nil {
+		return fmt.Errorf("restore profile verification: %w", err)
+	}
+
+	return nil
+}
+
+// InvalidateVerification drops an existing verified badge so the user has to record a new circle.
+func (r *ProfileRepo) InvalidateVerification(ctx context.Context, userID int64) error {
+	if r.pool == nil {
+		return nil
+	}
+	if userID <= 0 {
+		return fmt.Errorf("invalid user id")
+	}
+
+	if _, err := r.pool.Exec(ctx, `
+UPDATE profiles
+SET
+	verification_status = 'REVERIFY_REQUIRED',
+	verified_at = NULL,
+	updated_at = NOW()
+WHERE user_id = $1
+	AND verification_status = 'VERIFIED'
+`, userID); err != nil {
+		return fmt.Errorf("invalidate profile verification: %w", err)
+	}
+
+	return nil
+}
+
 func (r *ProfileRepo) GetModerationSnapshot(ctx context.Context, userID int64) (ProfileModerationSnapshot, error) {
 	if r.pool == nil {
 		return ProfileModerationSnapshot{}, fmt.Errorf("postgres pool is nil")
@@ -233,7 +337,7 @@ func (r *ProfileRepo) GetQueueSummary(ctx context.Context, userID int64) (Profil
 
 	var summary ProfileQueueSummary
 	err := r.pool.QueryRow(ctx, `
-SELECT user_id, display_name, city_id, gender, looking_for, goals, birthdate, occupation, education
+SELECT user_id, display_name, city_id, gender, looking_for, goals, birthdate, occupation, education, verification_status
 FROM profiles
 WHERE user_id = $1
 LIMIT 1
@@ -247,6 +351,7 @@ LIMIT 1
 		&summary.Birthdate,
 		&summary.Occupation,
 		&summary.Education,
+		&summary.VerificationStatus,
 	)
 	if err != nil {
 		if errors.Is(err, pgx.ErrNoRows) {
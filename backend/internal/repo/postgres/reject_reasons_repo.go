@@ -0,0 +1,144 @@
+package postgres
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+type RejectReasonRepo struct {
+	pool *pgxpool.Pool
+}
+
+type RejectReasonRecord struct {
+	Code            string
+	Labels          map[string]string
+	ReasonText      string
+	RequiredFixStep string
+	Severity        string
+	IsActive        bool
+	SortOrder       int
+	UpdatedByTGID   *int64
+	UpdatedAt       time.Time
+}
+
+const rejectReasonColumns = `code, labels, reason_text, required_fix_step, severity, is_active, sort_order, updated_by_tg_id, updated_at`
+
+func NewRejectReasonRepo(pool *pgxpool.Pool) *RejectReasonRepo {
+	return &RejectReasonRepo{pool: pool}
+}
+
+func (r *RejectReasonRepo) ListRejectReasons(ctx context.Context, includeInactive bool) ([]RejectReasonRecord, error) {
+	if r.pool == nil {
+		return nil, fmt.Errorf("postgres pool is nil")
+	}
+
+	rows, err := r.pool.Query(ctx, `
+SELECT `+rejectReasonColumns+`
+FROM moderation_reject_reasons
+WHERE $1 OR is_active
+ORDER BY sort_order ASC, code ASC
+`, includeInactive)
+	if err != nil {
+		return nil, fmt.Errorf("list reject reasons: %w", err)
+	}
+	defer rows.Close()
+
+	items := make([]RejectReasonRecord, 0, 16)
+	for rows.Next() {
+		item, err := scanRejectReason(rows)
+		if err != nil {
+			return nil, err
+		}
+		items = append(items, item)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterate reject reasons: %w", err)
+	}
+	return items, nil
+}
+
+func (r *RejectReasonRepo) UpsertRejectReason(ctx context.Context, in RejectReasonRecord) (RejectReasonRecord, error) {
+	if r.pool == nil {
+		return RejectReasonRecord{}, fmt.Errorf("postgres pool is nil")
+	}
+	code := strings.ToUpper(strings.TrimSpace(in.Code))
+	if code == "" {
+		return RejectReasonRecord{}, fmt.Errorf("invalid reject reason code")
+	}
+
+	labels, err := json.Marshal(in.Labels)
+	if err != nil {
+		return RejectReasonRecord{}, fmt.Errorf("marshal reject reason labels: %w", err)
+	}
+
+	rows, err := r.pool.Query(ctx, `
+INSERT INTO moderation_reject_reasons (
+	code, labels, reason_text, required_fix_step, severity, is_active, sort_order, updated_by_tg_id, updated_at
+)
+VALUES ($1, $2::jsonb, $3, $4, $5, $6, $7, $8, NOW())
+ON CONFLICT (code) DO UPDATE SET
+	labels = EXCLUDED.labels,
+	reason_text = EXCLUDED.reason_text,
+	required_fix_step = EXCLUDED.required_fix_step,
+	severity = EXCLUDED.severity,
+	is_active = EXCLUDED.is_active,
+	sort_order = EXCLUDED.sort_order,
+	updated_by_tg_id = EXCLUDED.updated_by_tg_id,
+	updated_at = NOW()
+RETURNING `+rejectReasonColumns,
+		code,
+		string(labels),
+		strings.TrimSpace(in.ReasonText),
+		strings.TrimSpace(in.RequiredFixStep),
+		strings.ToUpper(strings.TrimSpace(in.Severity)),
+		in.IsActive,
+		in.SortOrder,
+		in.UpdatedByTGID,
+	)
+	if err != nil {
+		return RejectReasonRecord{}, fmt.Errorf("upsert reject reason: %w", err)
+	}
+	defer rows.Close()
+
+	if !rows.Next() {
+		if err := rows.Err(); err != nil {
+			return RejectReasonRecord{}, fmt.Errorf("upsert reject reason: %w", err)
+		}
+		return RejectReasonRecord{}, fmt.Errorf("upsert reject reason: no row returned")
+	}
+	return scanRejectReason(rows)
+}
+
+func scanRejectReason(rows pgx.Rows) (RejectReasonRecord, error) {
+	var (
+		item   RejectReasonRecord
+		labels []byte
+	)
+	if err := rows.Scan(
+		&item.Code,
+		&labels,
+		&item.ReasonText,
+		&item.RequiredFixStep,
+		&item.Severity,
+		&item.IsActive,
+		&item.SortOrder,
+		&item.UpdatedByTGID,
+		&item.UpdatedAt,
+	); err != nil {
+		return RejectReasonRecord{}, fmt.Errorf("scan reject reason: %w", err)
+	}
+
+	item.Labels = map[string]string{}
+	if len(labels) > 0 {
+		if err := json.Unmarshal(labels, &item.Labels); err != nil {
+			return RejectReasonRecord{}, fmt.Errorf("decode reject reason labels: %w", err)
+		}
+	}
+	return item, nil
+}
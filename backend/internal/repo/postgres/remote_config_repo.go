@@ -0,0 +1,186 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+var (
+	ErrRemoteConfigNotFound = errors.New("remote config version not found")
+	ErrRemoteConfigConflict = errors.New("remote config was published concurrently")
+)
+
+// remoteConfigLockKey serializes publishes across API instances.
+const remoteConfigLockKey = "remote_config_versions"
+
+type RemoteConfigRepo struct {
+	pool *pgxpool.Pool
+}
+
+type RemoteConfigVersionRecord struct {
+	Version                int64
+	Document               []byte
+	Checksum               string
+	Comment                string
+	RollbackOf             *int64
+	PublishedByAdminUserID *int64
+	PublishedByTGID        *int64
+	PublishedAt            time.Time
+}
+
+const remoteConfigColumns = `version, document, checksum, comment, rollback_of, published_by_admin_user_id, published_by_tg_id, published_at`
+
+func NewRemoteConfigRepo(pool *pgxpool.Pool) *RemoteConfigRepo {
+	return &RemoteConfigRepo{pool: pool}
+}
+
+func (r *RemoteConfigRepo) LatestRemoteConfig(ctx context.Context) (RemoteConfigVersionRecord, error) {
+	if r.pool == nil {
+		return RemoteConfigVersionRecord{}, fmt.Errorf("postgres pool is nil")
+	}
+
+	rows, err := r.pool.Query(ctx, `
+SELECT `+remoteConfigColumns+`
+FROM remote_config_versions
+ORDER BY version DESC
+LIMIT 1
+`)
+	if err != nil {
+		return RemoteConfigVersionRecord{}, fmt.Errorf("get latest remote config: %w", err)
+	}
+	defer rows.Close()
+
+	return scanSingleRemoteConfig(rows, "get latest remote config")
+}
+
+func (r *RemoteConfigRepo) GetRemoteConfig(ctx context.Context, version int64) (RemoteConfigVersionRecord, error) {
+	if r.pool == nil {
+		return RemoteConfigVersionRecord{}, fmt.Errorf("postgres pool is nil")
+	}
+
+	rows, err := r.pool.Query(ctx, `
+SELECT `+remoteConfigColumns+`
+FROM remote_config_versions
+WHERE version = $1
+`, version)
+	if err != nil {
+		return RemoteConfigVersionRecord{}, fmt.Errorf("get remote config: %w", err)
+	}
+	defer rows.Close()
+
+	return scanSingleRemoteConfig(rows, "get remote config")
+}
+
+// ListRemoteConfigVersions returns version metadata newest first; documents are not loaded.
+func (r *RemoteConfigRepo) ListRemoteConfigVersions(ctx context.Context, limit, offset int) ([]RemoteConfigVersionRecord, error) {
+	if r.pool == nil {
+		return nil, fmt.Errorf("postgres pool is nil")
+	}
+
+	rows, err := r.pool.Query(ctx, `
+SELECT version, NULL::jsonb, checksum, comment, rollback_of, published_by_admin_user_id, published_by_tg_id, published_at
+FROM remote_config_versions
+ORDER BY version DESC
+LIMIT $1 OFFSET $2
+`, limit, offset)
+	if err != nil {
+		return nil, fmt.Errorf("list remote config versions: %w", err)
+	}
+	defer rows.Close()
+
+	items := make([]RemoteConfigVersionRecord, 0, limit)
+	for rows.Next() {
+		item, err := scanRemoteConfig(rows)
+		if err != nil {
+			return nil, err
+		}
+		items = append(items, item)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterate remote config versions: %w", err)
+	}
+	return items, nil
+}
+
+// InsertRemoteConfig appends a new version. When expectedLatest is set the insert only
+// happens if the current latest version still matches it (0 meaning an empty table). The
+// check and the insert run under remoteConfigLockKey so two publishers cannot both pass it.
+func (r *RemoteConfigRepo) InsertRemoteConfig(ctx context.Context, in RemoteConfigVersionRecord, expectedLatest *int64) (RemoteConfigVersionRecord, error) {
+	if r.pool == nil {
+		return RemoteConfigVersionRecord{}, fmt.Errorf("postgres pool is nil")
+	}
+
+	var record RemoteConfigVersionRecord
+	err := WithTx(ctx, r.pool, func(txCtx context.Context, tx pgx.Tx) error {
+		if _, err := tx.Exec(txCtx, `SELECT pg_advisory_xact_lock(hashtext($1))`, remoteConfigLockKey); err != nil {
+			return fmt.Errorf("lock remote config: %w", err)
+		}
+
+		if expectedLatest != nil {
+			var latest int64
+			if err := tx.QueryRow(txCtx, `SELECT COALESCE(MAX(version), 0) FROM remote_config_versions`).Scan(&latest); err != nil {
+				return fmt.Errorf("load latest remote config version: %w", err)
+			}
+			if latest != *expectedLatest {
+				return ErrRemoteConfigConflict
+			}
+		}
+
+		rows, err := tx.Query(txCtx, `
+INSERT INTO remote_config_versions (
+	document, checksum, comment, rollback_of, published_by_admin_user_id, published_by_tg_id
+)
+VALUES ($1::jsonb, $2, $3, $4, $5, $6)
+RETURNING `+remoteConfigColumns,
+			string(in.Document),
+			in.Checksum,
+			in.Comment,
+			in.RollbackOf,
+			in.PublishedByAdminUserID,
+			in.PublishedByTGID,
+		)
+		if err != nil {
+			return fmt.Errorf("insert remote config: %w", err)
+		}
+		defer rows.Close()
+
+		record, err = scanSingleRemoteConfig(rows, "insert remote config")
+		return err
+	})
+	if err != nil {
+		return RemoteConfigVersionRecord{}, err
+	}
+	return record, nil
+}
+
+func scanSingleRemoteConfig(rows pgx.Rows, op string) (RemoteConfigVersionRecord, error) {
+	if !rows.Next() {
+		if err := rows.Err(); err != nil {
+			return RemoteConfigVersionRecord{}, fmt.Errorf("%s: %w", op, err)
+		}
+		return RemoteConfigVersionRecord{}, ErrRemoteConfigNotFound
+	}
+	return scanRemoteConfig(rows)
+}
+
+func scanRemoteConfig(rows pgx.Rows) (RemoteConfigVersionRecord, error) {
+	var item RemoteConfigVersionRecord
+	if err := rows.Scan(
+		&item.Version,
+		&item.Document,
+		&item.Checksum,
+		&item.Comment,
+		&item.RollbackOf,
+		&item.PublishedByAdminUserID,
+		&item.PublishedByTGID,
+		&item.PublishedAt,
+	); err != nil {
+		return RemoteConfigVersionRecord{}, fmt.Errorf("scan remote config: %w", err)
+	}
+	return item, nil
+}
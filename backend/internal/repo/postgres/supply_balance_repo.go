@@ -0,0 +1,282 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+var ErrProfileSegmentNotFound = errors.New("profile segment not found")
+
+type SupplyBalanceRepo struct {
+	pool *pgxpool.Pool
+}
+
+// SupplySegmentRecord is one city and gender: approved profiles seen since the window start,
+// likes they sent according to daily_metrics and users of the segment still on the waitlist.
+type SupplySegmentRecord struct {
+	CityID  string
+	Gender  string
+	Active  int
+	Likes   int
+	Waiting int
+}
+
+type SupplyBalanceRecord struct {
+	CityID               string
+	ActiveFemale         int
+	ActiveMale           int
+	FemaleShare          float64
+	TargetShare          float64
+	State                string
+	UnderGender          *string
+	OverGender           *string
+	LikesFemale          int
+	LikesMale            int
+	WaitingFemale        int
+	WaitingMale          int
+	ThrottleAdmissions   bool
+	PrioritizeModeration bool
+	OverLikeFactor       float64
+	UnderLikeFactor      float64
+	ComputedAt           time.Time
+}
+
+func NewSupplyBalanceRepo(pool *pgxpool.Pool) *SupplyBalanceRepo {
+	return &SupplyBalanceRepo{pool: pool}
+}
+
+// ListSupplySegments reads the inputs of the balance controller. Profiles without a city or with
+// an unknown gender are left out: they cannot be steered per segment.
+func (r *SupplyBalanceRepo) ListSupplySegments(ctx context.Context, since time.Time) ([]SupplySegmentRecord, error) {
+	if r.pool == nil {
+		return nil, fmt.Errorf("postgres pool is nil")
+	}
+
+	segments := make(map[[2]string]*SupplySegmentRecord)
+	order := make([][2]string, 0)
+	collect := func(query, label string, apply func(*SupplySegmentRecord, int), args ...any) error {
+		rows, err := r.pool.Query(ctx, query, args...)
+		if err != nil {
+			return fmt.Errorf("load %s: %w", label, err)
+		}
+		defer rows.Close()
+		for rows.Next() {
+			var cityID, gender string
+			var value int
+			if err := rows.Scan(&cityID, &gender, &value); err != nil {
+				return fmt.Errorf("scan %s: %w", label, err)
+			}
+			key := [2]string{cityID, gender}
+			segment, ok := segments[key]
+			if !ok {
+				segment = &SupplySegmentRecord{CityID: cityID, Gender: gender}
+				segments[key] = segment
+				order = append(order, key)
+			}
+			apply(segment, value)
+		}
+		if err := rows.Err(); err != nil {
+			return fmt.Errorf("iterate %s: %w", label, err)
+		}
+		return nil
+	}
+
+	if err := collect(`
+SELECT TRIM(p.city_id), LOWER(TRIM(p.gender)), COUNT(*)::int
+FROM profiles p
+WHERE p.approved = TRUE
+  AND COALESCE(TRIM(p.city_id), '') <> ''
+  AND LOWER(TRIM(p.gender)) IN ('female', 'male')
+  AND NOT `+ActiveBanExists("p.user_id")+`
+  AND EXISTS (
+	SELECT 1
+	FROM user_devices d
+	WHERE d.user_id = p.user_id
+	  AND d.last_seen_at >= $1
+  )
+GROUP BY 1, 2
+ORDER BY 1, 2
+`, "active supply", func(s *SupplySegmentRecord, v int) { s.Active = v }, since); err != nil {
+		return nil, err
+	}
+
+	if err := collect(`
+SELECT city_id, gender, COALESCE(SUM(likes + superlikes), 0)::int
+FROM daily_metrics
+WHERE day_key >= $1::date
+  AND city_id <> 'unknown'
+  AND gender IN ('female', 'male')
+GROUP BY 1, 2
+ORDER BY 1, 2
+`, "segment likes", func(s *SupplySegmentRecord, v int) { s.Likes = v }, since.UTC()); err != nil {
+		return nil, err
+	}
+
+	if err := collect(`
+SELECT TRIM(p.city_id), LOWER(TRIM(p.gender)), COUNT(*)::int
+FROM registration_waitlist w
+JOIN profiles p ON p.user_id = w.user_id
+WHERE w.status = 'WAITING'
+  AND COALESCE(TRIM(p.city_id), '') <> ''
+  AND LOWER(TRIM(p.gender)) IN ('female', 'male')
+GROUP BY 1, 2
+ORDER BY 1, 2
+`, "waiting supply", func(s *SupplySegmentRecord, v int) { s.Waiting = v }); err != nil {
+		return nil, err
+	}
+
+	out := make([]SupplySegmentRecord, 0, len(order))
+	for _, key := range order {
+		out = append(out, *segments[key])
+	}
+	return out, nil
+}
+
+// ReplaceSupplyBalance swaps the stored decisions for items in one transaction so readers never
+// see a half-written set.
+func (r *SupplyBalanceRepo) ReplaceSupplyBalance(ctx context.Context, items []SupplyBalanceRecord) error {
+	if r.pool == nil {
+		return fmt.Errorf("postgres pool is nil")
+	}
+
+	return WithTx(ctx, r.pool, func(txCtx context.Context, tx pgx.Tx) error {
+		if _, err := tx.Exec(txCtx, `DELETE FROM supply_balance_decisions`); err != nil {
+			return fmt.Errorf("clear supply balance decisions: %w", err)
+		}
+		for _, item := range items {
+			if _, err := tx.Exec(txCtx, `
+INSERT INTO supply_balance_decisions (
+	city_id,
+	active_female,
+	active_male,
+	female_share,
+	target_share,
+	state,
+	under_gender,
+	over_gender,
+	likes_female,
+	likes_male,
+	waiting_female,
+	waiting_male,
+	throttle_admissions,
+	prioritize_moderation,
+	over_like_factor,
+	under_like_factor,
+	computed_at
+)
+VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17)
+`,
+				item.CityID,
+				item.ActiveFemale,
+				item.ActiveMale,
+				item.FemaleShare,
+				item.TargetShare,
+				item.State,
+				item.UnderGender,
+				item.OverGender,
+				item.LikesFemale,
+				item.LikesMale,
+				item.WaitingFemale,
+				item.WaitingMale,
+				item.ThrottleAdmissions,
+				item.PrioritizeModeration,
+				item.OverLikeFactor,
+				item.UnderLikeFactor,
+				item.ComputedAt,
+			); err != nil {
+				return fmt.Errorf("insert supply balance decision: %w", err)
+			}
+		}
+		return nil
+	})
+}
+
+func (r *SupplyBalanceRepo) ListSupplyBalance(ctx context.Context) ([]SupplyBalanceRecord, error) {
+	if r.pool == nil {
+		return nil, fmt.Errorf("postgres pool is nil")
+	}
+
+	rows, err := r.pool.Query(ctx, `
+SELECT
+	city_id,
+	active_female,
+	active_male,
+	female_share,
+	target_share,
+	state,
+	under_gender,
+	over_gender,
+	likes_female,
+	likes_male,
+	waiting_female,
+	waiting_male,
+	throttle_admissions,
+	prioritize_moderation,
+	over_like_factor,
+	under_like_factor,
+	computed_at
+FROM supply_balance_decisions
+ORDER BY active_female + active_male DESC, city_id ASC
+`)
+	if err != nil {
+		return nil, fmt.Errorf("list supply balance decisions: %w", err)
+	}
+	defer rows.Close()
+
+	items := make([]SupplyBalanceRecord, 0)
+	for rows.Next() {
+		var item SupplyBalanceRecord
+		if err := rows.Scan(
+			&item.CityID,
+			&item.ActiveFemale,
+			&item.ActiveMale,
+			&item.FemaleShare,
+			&item.TargetShare,
+			&item.State,
+			&item.UnderGender,
+			&item.OverGender,
+			&item.LikesFemale,
+			&item.LikesMale,
+			&item.WaitingFemale,
+			&item.WaitingMale,
+			&item.ThrottleAdmissions,
+			&item.PrioritizeModeration,
+			&item.OverLikeFactor,
+			&item.UnderLikeFactor,
+			&item.ComputedAt,
+		); err != nil {
+			return nil, fmt.Errorf("scan supply balance decision: %w", err)
+		}
+		items = append(items, item)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterate supply balance decisions: %w", err)
+	}
+	return items, nil
+}
+
+// ProfileSegment returns the city and lowercased gender used to look up a user's decision.
+func (r *SupplyBalanceRepo) ProfileSegment(ctx context.Context, userID int64) (string, string, error) {
+	if r.pool == nil {
+		return "", "", fmt.Errorf("postgres pool is nil")
+	}
+
+	var cityID, gender string
+	err := r.pool.QueryRow(ctx, `
+SELECT COALESCE(TRIM(city_id), ''), COALESCE(LOWER(TRIM(gender)), '')
+FROM profiles
+WHERE user_id = $1
+`, userID).Scan(&cityID, &gender)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return "", "", ErrProfileSegmentNotFound
+		}
+		return "", "", fmt.Errorf("load profile segment: %w", err)
+	}
+	return cityID, gender, nil
+}
@@ -0,0 +1,17 @@
+package postgres
+
+// ActiveBanExists matches an in-force ban for the BIGINT user id expression; expired
+// temporary bans stop matching on their own, so no sweeper is needed.
+// user_bans is keyed by the same pseudo UUID the users service derives from the numeric id.
+func ActiveBanExists(userIDExpr string) string {
+	return `EXISTS (
+		SELECT 1
+		FROM user_bans ub_active
+		WHERE ub_active.user_id = (
+			'00000000-0000-0000-0000-' ||
+			LPAD(TO_HEX((` + userIDExpr + ` & 281474976710655)::bigint), 12, '0')
+		)::uuid
+			AND ub_active.banned = TRUE
+			AND (ub_active.expires_at IS NULL OR ub_active.expires_at > NOW())
+	)`
+}
@@ -0,0 +1,589 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// WaitlistedExists matches a user still waiting for admission for the BIGINT user id expression.
+// Feed and like candidate queries exclude such users next to ActiveBanExists.
+func WaitlistedExists(userIDExpr string) string {
+	return `EXISTS (
+		SELECT 1
+		FROM registration_waitlist rw_waiting
+		WHERE rw_waiting.user_id = (` + userIDExpr + `)
+			AND rw_waiting.status = 'WAITING'
+	)`
+}
+
+var (
+	ErrWaitlistEntryNotFound = errors.New("waitlist entry not found")
+	ErrInviteCodeNotFound    = errors.New("invite code not found")
+	ErrInviteCodeUnusable    = errors.New("invite code is disabled, expired or used up")
+)
+
+const (
+	WaitlistStatusWaiting  = "WAITING"
+	WaitlistStatusAdmitted = "ADMITTED"
+
+	WaitlistAdmittedViaBatch  = "batch"
+	WaitlistAdmittedViaInvite = "invite"
+	WaitlistAdmittedViaOpen   = "open"
+)
+
+type WaitlistRepo struct {
+	pool *pgxpool.Pool
+}
+
+type WaitlistEntryRecord struct {
+	UserID         int64
+	Position       int64
+	Status         string
+	InviteCode     *string
+	AdmittedVia    *string
+	JoinedAt       time.Time
+	AdmittedAt     *time.Time
+	AdmittedByTGID *int64
+	NotifiedAt     *time.Time
+	AheadInQueue   int64
+	WaitingTotal   int64
+}
+
+type WaitlistAdmitInput struct {
+	Limit   int
+	CityID  string
+	Gender  string
+	Balance bool
+	// IgnoreBalance admits users of segments the supply balance controller is throttling.
+	IgnoreBalance bool
+	ActorTGID     int64
+}
+
+type WaitlistAdmittedRecord struct {
+	UserID   int64
+	Position int64
+	CityID   string
+	Gender   string
+}
+
+type WaitlistBucketRecord struct {
+	Key   string
+	Count int64
+}
+
+type WaitlistStatsRecord struct {
+	Waiting       int64
+	AdmittedToday int64
+	PendingNotify int64
+	ByCity        []WaitlistBucketRecord
+	ByGender      []WaitlistBucketRecord
+}
+
+type WaitlistNotificationRecord struct {
+	UserID      int64
+	TelegramID  int64
+	Position    int64
+	AdmittedVia string
+}
+
+type InviteCodeRecord struct {
+	Code          string
+	MaxUses       int
+	UsedCount     int
+	ExpiresAt     *time.Time
+	Comment       string
+	CreatedByTGID *int64
+	CreatedAt     time.Time
+	DisabledAt    *time.Time
+}
+
+const waitlistEntryColumns = `
+	w.user_id,
+	w.position,
+	w.status,
+	w.invite_code,
+	w.admitted_via,
+	w.joined_at,
+	w.admitted_at,
+	w.admitted_by_tg_id,
+	w.notified_at,
+	(SELECT COUNT(*) FROM registration_waitlist a WHERE a.status = 'WAITING' AND a.position < w.position),
+	(SELECT COUNT(*) FROM registration_waitlist a WHERE a.status = 'WAITING')`
+
+const inviteCodeColumns = `code, max_uses, used_count, expires_at, comment, created_by_tg_id, created_at, disabled_at`
+
+func NewWaitlistRepo(pool *pgxpool.Pool) *WaitlistRepo {
+	return &WaitlistRepo{pool: pool}
+}
+
+func (r *WaitlistRepo) GetWaitlistEntry(ctx context.Context, userID int64) (WaitlistEntryRecord, error) {
+	if r.pool == nil {
+		return WaitlistEntryRecord{}, fmt.Errorf("postgres pool is nil")
+	}
+	return getWaitlistEntry(ctx, r.pool, userID)
+}
+
+// UserHasProfile reports whether the user got past onboarding before the waitlist existed;
+// such accounts are never queued.
+func (r *WaitlistRepo) UserHasProfile(ctx context.Context, userID int64) (bool, error) {
+	if r.pool == nil {
+		return false, fmt.Errorf("postgres pool is nil")
+	}
+
+	var exists bool
+	if err := r.pool.QueryRow(ctx, `
+SELECT EXISTS (SELECT 1 FROM profiles WHERE user_id = $1)
+`, userID).Scan(&exists); err != nil {
+		return false, fmt.Errorf("check user profile: %w", err)
+	}
+	return exists, nil
+}
+
+func (r *WaitlistRepo) JoinWaitlist(ctx context.Context, userID int64) (WaitlistEntryRecord, error) {
+	if r.pool == nil {
+		return WaitlistEntryRecord{}, fmt.Errorf("postgres pool is nil")
+	}
+
+	if _, err := r.pool.Exec(ctx, `
+INSERT INTO registration_waitlist (user_id)
+VALUES ($1)
+ON CONFLICT (user_id) DO NOTHING
+`, userID); err != nil {
+		return WaitlistEntryRecord{}, fmt.Errorf("join waitlist: %w", err)
+	}
+	return getWaitlistEntry(ctx, r.pool, userID)
+}
+
+// AdmitWaitlistUser admits a single user outside of a batch (used when registration is open
+// again). Such users are already in the app, so they are marked as notified.
+func (r *WaitlistRepo) AdmitWaitlistUser(ctx context.Context, userID int64, via string) (WaitlistEntryRecord, error) {
+	if r.pool == nil {
+		return WaitlistEntryRecord{}, fmt.Errorf("postgres pool is nil")
+	}
+
+	if _, err := r.pool.Exec(ctx, `
+UPDATE registration_waitlist
+SET status = 'ADMITTED',
+	admitted_via = $2,
+	admitted_at = NOW(),
+	notified_at = NOW()
+WHERE user_id = $1
+  AND status = 'WAITING'
+`, userID, via); err != nil {
+		return WaitlistEntryRecord{}, fmt.Errorf("admit waitlist user: %w", err)
+	}
+	return getWaitlistEntry(ctx, r.pool, userID)
+}
+
+// RedeemInvite consumes one use of code and admits the user in the same transaction. A user who
+// is already admitted keeps the code unused.
+func (r *WaitlistRepo) RedeemInvite(ctx context.Context, userID int64, code string) (WaitlistEntryRecord, error) {
+	var out WaitlistEntryRecord
+	err := WithTx(ctx, r.pool, func(ctx context.Context, tx pgx.Tx) error {
+		var status string
+		err := tx.QueryRow(ctx, `
+SELECT status
+FROM registration_waitlist
+WHERE user_id = $1
+FOR UPDATE
+`, userID).Scan(&status)
+		if err != nil && !errors.Is(err, pgx.ErrNoRows) {
+			return fmt.Errorf("lock waitlist entry: %w", err)
+		}
+		if status == WaitlistStatusAdmitted {
+			out, err = getWaitlistEntry(ctx, tx, userID)
+			return err
+		}
+
+		tag, err := tx.Exec(ctx, `
+UPDATE invite_codes
+SET used_count = used_count + 1
+WHERE code = $1
+  AND disabled_at IS NULL
+  AND (expires_at IS NULL OR expires_at > NOW())
+  AND (max_uses = 0 OR used_count < max_uses)
+`, code)
+		if err != nil {
+			return fmt.Errorf("consume invite code: %w", err)
+		}
+		if tag.RowsAffected() == 0 {
+			return ErrInviteCodeUnusable
+		}
+
+		if _, err := tx.Exec(ctx, `
+INSERT INTO registration_waitlist (user_id, status, invite_code, admitted_via, admitted_at, notified_at)
+VALUES ($1, 'ADMITTED', $2, 'invite', NOW(), NOW())
+ON CONFLICT (user_id) DO UPDATE SET
+	status = 'ADMITTED',
+	invite_code = EXCLUDED.invite_code,
+	admitted_via = 'invite',
+	admitted_at = NOW(),
+	notified_at = NOW()
+`, userID, code); err != nil {
+			return fmt.Errorf("admit invited user: %w", err)
+		}
+
+		out, err = getWaitlistEntry(ctx, tx, userID)
+		return err
+	})
+	if err != nil {
+		return WaitlistEntryRecord{}, err
+	}
+	return out, nil
+}
+
+// AdmitWaitlist admits up to Limit waiting users in queue order, optionally restricted to a city
+// or gender. Balance interleaves genders (first of each gender, then second of each, ...) so a
+// batch doesn't tip the ratio towards whoever queued first.
+func (r *WaitlistRepo) AdmitWaitlist(ctx context.Context, in WaitlistAdmitInput) ([]WaitlistAdmittedRecord, error) {
+	if r.pool == nil {
+		return nil, fmt.Errorf("postgres pool is nil")
+	}
+	if in.Limit <= 0 {
+		return []WaitlistAdmittedRecord{}, nil
+	}
+
+	var actor *int64
+	if in.ActorTGID != 0 {
+		actor = &in.ActorTGID
+	}
+
+	rows, err := r.pool.Query(ctx, `
+WITH candidates AS (
+	SELECT
+		w.user_id,
+		w.position,
+		ROW_NUMBER() OVER (PARTITION BY COALESCE(p.gender, 'unknown') ORDER BY w.position) AS rn
+	FROM registration_waitlist w
+	LEFT JOIN profiles p ON p.user_id = w.user_id
+	WHERE w.status = 'WAITING'
+	  AND ($2 = '' OR LOWER(COALESCE(p.city_id, '')) = LOWER($2))
+	  AND ($3 = '' OR LOWER(COALESCE(p.gender, '')) = LOWER($3))
+	  AND ($6::boolean OR NOT EXISTS (
+		SELECT 1
+		FROM supply_balance_decisions sb
+		WHERE sb.throttle_admissions
+		  AND sb.city_id = TRIM(p.city_id)
+		  AND sb.over_gender = LOWER(TRIM(p.gender))
+	  ))
+),
+picked AS (
+	SELECT user_id
+	FROM candidates
+	ORDER BY CASE WHEN $4::boolean THEN rn ELSE 0 END ASC, position ASC
+	LIMIT $1
+)
+UPDATE registration_waitlist w
+SET status = 'ADMITTED',
+	admitted_via = 'batch',
+	admitted_at = NOW(),
+	admitted_by_tg_id = $5
+FROM picked
+LEFT JOIN profiles p ON p.user_id = picked.user_id
+WHERE w.user_id = picked.user_id
+  AND w.status = 'WAITING'
+RETURNING w.user_id, w.position, COALESCE(p.city_id, ''), COALESCE(p.gender, '')
+`, in.Limit, strings.TrimSpace(in.CityID), strings.TrimSpace(in.Gender), in.Balance, actor, in.IgnoreBalance)
+	if err != nil {
+		return nil, fmt.Errorf("admit waitlist: %w", err)
+	}
+	defer rows.Close()
+
+	items := make([]WaitlistAdmittedRecord, 0, in.Limit)
+	for rows.Next() {
+		var item WaitlistAdmittedRecord
+		if err := rows.Scan(&item.UserID, &item.Position, &item.CityID, &item.Gender); err != nil {
+			return nil, fmt.Errorf("scan admitted user: %w", err)
+		}
+		items = append(items, item)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterate admitted users: %w", err)
+	}
+	return items, nil
+}
+
+func (r *WaitlistRepo) WaitlistStats(ctx context.Context, topN int) (WaitlistStatsRecord, error) {
+	if r.pool == nil {
+		return WaitlistStatsRecord{}, fmt.Errorf("postgres pool is nil")
+	}
+	if topN <= 0 {
+		topN = 10
+	}
+
+	var out WaitlistStatsRecord
+	if err := r.pool.QueryRow(ctx, `
+SELECT
+	COUNT(*) FILTER (WHERE status = 'WAITING'),
+	COUNT(*) FILTER (WHERE status = 'ADMITTED' AND admitted_at >= date_trunc('day', NOW())),
+	COUNT(*) FILTER (WHERE status = 'ADMITTED' AND notified_at IS NULL)
+FROM registration_waitlist
+`).Scan(&out.Waiting, &out.AdmittedToday, &out.PendingNotify); err != nil {
+		return WaitlistStatsRecord{}, fmt.Errorf("load waitlist stats: %w", err)
+	}
+
+	var err error
+	out.ByCity, err = r.waitingBuckets(ctx, `COALESCE(NULLIF(p.city_id, ''), 'unknown')`, topN)
+	if err != nil {
+		return WaitlistStatsRecord{}, err
+	}
+	out.ByGender, err = r.waitingBuckets(ctx, `COALESCE(NULLIF(p.gender, ''), 'unknown')`, topN)
+	if err != nil {
+		return WaitlistStatsRecord{}, err
+	}
+	return out, nil
+}
+
+func (r *WaitlistRepo) waitingBuckets(ctx context.Context, keyExpr string, limit int) ([]WaitlistBucketRecord, error) {
+	rows, err := r.pool.Query(ctx, `
+SELECT `+keyExpr+` AS bucket, COUNT(*)
+FROM registration_waitlist w
+LEFT JOIN profiles p ON p.user_id = w.user_id
+WHERE w.status = 'WAITING'
+GROUP BY bucket
+ORDER BY COUNT(*) DESC, bucket ASC
+LIMIT $1
+`, limit)
+	if err != nil {
+		return nil, fmt.Errorf("load waitlist buckets: %w", err)
+	}
+	defer rows.Close()
+
+	items := make([]WaitlistBucketRecord, 0, limit)
+	for rows.Next() {
+		var item WaitlistBucketRecord
+		if err := rows.Scan(&item.Key, &item.Count); err != nil {
+			return nil, fmt.Errorf("scan waitlist bucket: %w", err)
+		}
+		items = append(items, item)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterate waitlist buckets: %w", err)
+	}
+	return items, nil
+}
+
+// AcquireWaitlistNotifications claims batch-admitted users that still need a bot message. A claim
+// expires after claimTTL so a crashed sender doesn't block a user forever.
+func (r *WaitlistRepo) AcquireWaitlistNotifications(
+	ctx context.Context,
+	limit int,
+	maxAttempts int,
+	claimTTL time.Duration,
+) ([]WaitlistNotificationRecord, error) {
+	if r.pool == nil {
+		return nil, fmt.Errorf("postgres pool is nil")
+	}
+	if limit <= 0 {
+		return []WaitlistNotificationRecord{}, nil
+	}
+
+	rows, err := r.pool.Query(ctx, `
+WITH claimed AS (
+	SELECT user_id
+	FROM registration_waitlist
+	WHERE status = 'ADMITTED'
+	  AND notified_at IS NULL
+	  AND notify_attempts < $2
+	  AND (notify_attempted_at IS NULL OR notify_attempted_at < NOW() - make_interval(secs => $3))
+	ORDER BY admitted_at ASC
+	LIMIT $1
+	FOR UPDATE SKIP LOCKED
+)
+UPDATE registration_waitlist w
+SET notify_attempts = w.notify_attempts + 1,
+	notify_attempted_at = NOW()
+FROM claimed
+JOIN users u ON u.id = claimed.user_id
+WHERE w.user_id = claimed.user_id
+RETURNING w.user_id, u.telegram_id, w.position, COALESCE(w.admitted_via, '')
+`, limit, maxAttempts, claimTTL.Seconds())
+	if err != nil {
+		return nil, fmt.Errorf("acquire waitlist notifications: %w", err)
+	}
+	defer rows.Close()
+
+	items := make([]WaitlistNotificationRecord, 0, limit)
+	for rows.Next() {
+		var item WaitlistNotificationRecord
+		if err := rows.Scan(&item.UserID, &item.TelegramID, &item.Position, &item.AdmittedVia); err != nil {
+			return nil, fmt.Errorf("scan waitlist notification: %w", err)
+		}
+		items = append(items, item)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterate waitlist notifications: %w", err)
+	}
+	return items, nil
+}
+
+func (r *WaitlistRepo) MarkWaitlistNotified(ctx context.Context, userID int64) error {
+	if r.pool == nil {
+		return fmt.Errorf("postgres pool is nil")
+	}
+
+	if _, err := r.pool.Exec(ctx, `
+UPDATE registration_waitlist
+SET notified_at = NOW()
+WHERE user_id = $1
+`, userID); err != nil {
+		return fmt.Errorf("mark waitlist notified: %w", err)
+	}
+	return nil
+}
+
+func (r *WaitlistRepo) CreateInviteCodes(ctx context.Context, items []InviteCodeRecord) ([]InviteCodeRecord, error) {
+	out := make([]InviteCodeRecord, 0, len(items))
+	err := WithTx(ctx, r.pool, func(ctx context.Context, tx pgx.Tx) error {
+		for _, item := range items {
+			rows, err := tx.Query(ctx, `
+INSERT INTO invite_codes (code, max_uses, expires_at, comment, created_by_tg_id)
+VALUES ($1, $2, $3, $4, $5)
+RETURNING `+inviteCodeColumns,
+				item.Code,
+				item.MaxUses,
+				item.ExpiresAt,
+				item.Comment,
+				item.CreatedByTGID,
+			)
+			if err != nil {
+				return fmt.Errorf("insert invite code: %w", err)
+			}
+			created, err := scanSingleInviteCode(rows)
+			if err != nil {
+				return err
+			}
+			out = append(out, created)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (r *WaitlistRepo) ListInviteCodes(ctx context.Context, limit, offset int) ([]InviteCodeRecord, error) {
+	if r.pool == nil {
+		return nil, fmt.Errorf("postgres pool is nil")
+	}
+
+	rows, err := r.pool.Query(ctx, `
+SELECT `+inviteCodeColumns+`
+FROM invite_codes
+ORDER BY created_at DESC, code ASC
+LIMIT $1 OFFSET $2
+`, limit, offset)
+	if err != nil {
+		return nil, fmt.Errorf("list invite codes: %w", err)
+	}
+	defer rows.Close()
+
+	items := make([]InviteCodeRecord, 0, limit)
+	for rows.Next() {
+		item, err := scanInviteCode(rows)
+		if err != nil {
+			return nil, err
+		}
+		items = append(items, item)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterate invite codes: %w", err)
+	}
+	return items, nil
+}
+
+func (r *WaitlistRepo) DisableInviteCode(ctx context.Context, code string) (InviteCodeRecord, error) {
+	if r.pool == nil {
+		return InviteCodeRecord{}, fmt.Errorf("postgres pool is nil")
+	}
+
+	rows, err := r.pool.Query(ctx, `
+UPDATE invite_codes
+SET disabled_at = COALESCE(disabled_at, NOW())
+WHERE code = $1
+RETURNING `+inviteCodeColumns, code)
+	if err != nil {
+		return InviteCodeRecord{}, fmt.Errorf("disable invite code: %w", err)
+	}
+	item, err := scanSingleInviteCode(rows)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return InviteCodeRecord{}, ErrInviteCodeNotFound
+	}
+	return item, err
+}
+
+type waitlistQuerier interface {
+	QueryRow(ctx context.Context, sql string, args ...any) pgx.Row
+}
+
+func getWaitlistEntry(ctx context.Context, q waitlistQuerier, userID int64) (WaitlistEntryRecord, error) {
+	var item WaitlistEntryRecord
+	err := q.QueryRow(ctx, `
+SELECT `+waitlistEntryColumns+`
+FROM registration_waitlist w
+WHERE w.user_id = $1
+`, userID).Scan(
+		&item.UserID,
+		&item.Position,
+		&item.Status,
+		&item.InviteCode,
+		&item.AdmittedVia,
+		&item.JoinedAt,
+		&item.AdmittedAt,
+		&item.AdmittedByTGID,
+		&item.NotifiedAt,
+		&item.AheadInQueue,
+		&item.WaitingTotal,
+	)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return WaitlistEntryRecord{}, ErrWaitlistEntryNotFound
+	}
+	if err != nil {
+		return WaitlistEntryRecord{}, fmt.Errorf("load waitlist entry: %w", err)
+	}
+	return item, nil
+}
+
+// scanSingleInviteCode reads exactly one row and closes rows; no row yields pgx.ErrNoRows.
+func scanSingleInviteCode(rows pgx.Rows) (InviteCodeRecord, error) {
+	defer rows.Close()
+	if !rows.Next() {
+		if err := rows.Err(); err != nil {
+			return InviteCodeRecord{}, fmt.Errorf("read invite code: %w", err)
+		}
+		return InviteCodeRecord{}, pgx.ErrNoRows
+	}
+	item, err := scanInviteCode(rows)
+	if err != nil {
+		return InviteCodeRecord{}, err
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return InviteCodeRecord{}, fmt.Errorf("read invite code: %w", err)
+	}
+	return item, nil
+}
+
+func scanInviteCode(rows pgx.Rows) (InviteCodeRecord, error) {
+	var item InviteCodeRecord
+	if err := rows.Scan(
+		&item.Code,
+		&item.MaxUses,
+		&item.UsedCount,
+		&item.ExpiresAt,
+		&item.Comment,
+		&item.CreatedByTGID,
+		&item.CreatedAt,
+		&item.DisabledAt,
+	); err != nil {
+		return InviteCodeRecord{}, fmt.Errorf("scan invite code: %w", err)
+	}
+	return item, nil
+}
@@ -0,0 +1,62 @@
+package redis
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/redis/go-redis/v9"
+)
+
+const remoteConfigChannel = "remote_config:published"
+
+// RemoteConfigBus fans out "version N was published" notifications to every API instance.
+type RemoteConfigBus struct {
+	client *redis.Client
+}
+
+func NewRemoteConfigBus(client *redis.Client) *RemoteConfigBus {
+	return &RemoteConfigBus{client: client}
+}
+
+func (b *RemoteConfigBus) PublishRemoteConfig(ctx context.Context, version int64) error {
+	if b.client == nil {
+		return fmt.Errorf("redis client is nil")
+	}
+	if err := b.client.Publish(ctx, remoteConfigChannel, strconv.FormatInt(version, 10)).Err(); err != nil {
+		return fmt.Errorf("publish remote config version: %w", err)
+	}
+	return nil
+}
+
+// SubscribeRemoteConfig blocks until ctx is cancelled, calling fn for every published version.
+func (b *RemoteConfigBus) SubscribeRemoteConfig(ctx context.Context, fn func(version int64)) error {
+	if b.client == nil {
+		return fmt.Errorf("redis client is nil")
+	}
+
+	sub := b.client.Subscribe(ctx, remoteConfigChannel)
+	defer sub.Close()
+
+	if _, err := sub.Receive(ctx); err != nil {
+		return fmt.Errorf("subscribe remote config: %w", err)
+	}
+
+	ch := sub.Channel()
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case msg, ok := <-ch:
+			if !ok {
+				return nil
+			}
+			version, err := strconv.ParseInt(strings.TrimSpace(msg.Payload), 10, 64)
+			if err != nil || version <= 0 {
+				continue
+			}
+			fn(version)
+		}
+	}
+}
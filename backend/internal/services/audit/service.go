@@ -0,0 +1,187 @@
+package audit
+
+import (
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	pgrepo "github.com/ivankudzin/tgapp/backend/internal/repo/postgres"
+)
+
+var ErrInvalidAction = errors.New("invalid audit action")
+var ErrInvalidPayload = errors.New("audit payload must be a json object")
+var ErrUnsupportedFormat = errors.New("unsupported audit export format")
+
+const (
+	DefaultPageSize = 50
+	MaxPageSize     = 200
+	ExportLimit     = 10000
+
+	FormatCSV  = "csv"
+	FormatJSON = "json"
+)
+
+type Repo interface {
+	Append(ctx context.Context, in pgrepo.AuditRecord) (pgrepo.AuditRecord, error)
+	Search(ctx context.Context, filter pgrepo.AuditFilter) ([]pgrepo.AuditRecord, error)
+	VerifyChain(ctx context.Context) (pgrepo.AuditChainReport, error)
+}
+
+type Page struct {
+	Items   []pgrepo.AuditRecord
+	Offset  int
+	Limit   int
+	HasMore bool
+}
+
+type ExportItem struct {
+	ID        string          `json:"id"`
+	Seq       int64           `json:"seq"`
+	ActorTGID int64           `json:"actor_tg_id"`
+	Action    string          `json:"action"`
+	Payload   json.RawMessage `json:"payload"`
+	PrevHash  string          `json:"prev_hash"`
+	Hash      string          `json:"hash"`
+	CreatedAt time.Time       `json:"created_at"`
+}
+
+type Service struct {
+	repo Repo
+	now  func() time.Time
+}
+
+func NewService(repo Repo) *Service {
+	return &Service{repo: repo, now: time.Now}
+}
+
+// Append stamps the record with the server clock; callers cannot backdate entries in the chain.
+func (s *Service) Append(ctx context.Context, actorTGID int64, action string, payload json.RawMessage) (pgrepo.AuditRecord, error) {
+	action = strings.ToUpper(strings.TrimSpace(action))
+	if action == "" {
+		return pgrepo.AuditRecord{}, ErrInvalidAction
+	}
+	if strings.TrimSpace(string(payload)) == "" {
+		payload = json.RawMessage(`{}`)
+	}
+	var object map[string]any
+	if err := json.Unmarshal(payload, &object); err != nil {
+		return pgrepo.AuditRecord{}, ErrInvalidPayload
+	}
+
+	return s.repo.Append(ctx, pgrepo.AuditRecord{
+		ActorTGID: actorTGID,
+		Action:    action,
+		Payload:   payload,
+		CreatedAt: s.now().UTC(),
+	})
+}
+
+// Search returns one page; an extra row is fetched to know whether a next page exists.
+func (s *Service) Search(ctx context.Context, filter pgrepo.AuditFilter) (Page, error) {
+	filter = normalizeFilter(filter)
+	if filter.Limit <= 0 {
+		filter.Limit = DefaultPageSize
+	}
+	if filter.Limit > MaxPageSize {
+		filter.Limit = MaxPageSize
+	}
+
+	query := filter
+	query.Limit = filter.Limit + 1
+	items, err := s.repo.Search(ctx, query)
+	if err != nil {
+		return Page{}, err
+	}
+
+	page := Page{Items: items, Offset: filter.Offset, Limit: filter.Limit}
+	if len(items) > filter.Limit {
+		page.HasMore = true
+		page.Items = items[:filter.Limit]
+	}
+	return page, nil
+}
+
+// Export writes up to ExportLimit records of the filtered range and returns how many were written.
+func (s *Service) Export(ctx context.Context, filter pgrepo.AuditFilter, format string, w io.Writer) (int, error) {
+	format = strings.ToLower(strings.TrimSpace(format))
+	if format != FormatCSV && format != FormatJSON {
+		return 0, ErrUnsupportedFormat
+	}
+
+	filter = normalizeFilter(filter)
+	filter.Limit = ExportLimit
+	filter.Offset = 0
+	items, err := s.repo.Search(ctx, filter)
+	if err != nil {
+		return 0, err
+	}
+
+	if format == FormatJSON {
+		out := make([]ExportItem, 0, len(items))
+		for _, item := range items {
+			out = append(out, ToExportItem(item))
+		}
+		encoder := json.NewEncoder(w)
+		encoder.SetIndent("", "  ")
+		return len(items), encoder.Encode(out)
+	}
+
+	writer := csv.NewWriter(w)
+	if err := writer.Write([]string{"id", "seq", "created_at", "actor_tg_id", "action", "payload", "prev_hash", "hash"}); err != nil {
+		return 0, err
+	}
+	for _, item := range items {
+		if err := writer.Write([]string{
+			item.ID,
+			strconv.FormatInt(item.Seq, 10),
+			item.CreatedAt.UTC().Format(time.RFC3339Nano),
+			strconv.FormatInt(item.ActorTGID, 10),
+			item.Action,
+			payloadString(item.Payload),
+			item.PrevHash,
+			item.Hash,
+		}); err != nil {
+			return 0, err
+		}
+	}
+	writer.Flush()
+	return len(items), writer.Error()
+}
+
+func (s *Service) VerifyChain(ctx context.Context) (pgrepo.AuditChainReport, error) {
+	return s.repo.VerifyChain(ctx)
+}
+
+func ToExportItem(item pgrepo.AuditRecord) ExportItem {
+	return ExportItem{
+		ID:        item.ID,
+		Seq:       item.Seq,
+		ActorTGID: item.ActorTGID,
+		Action:    item.Action,
+		Payload:   json.RawMessage(payloadString(item.Payload)),
+		PrevHash:  item.PrevHash,
+		Hash:      item.Hash,
+		CreatedAt: item.CreatedAt,
+	}
+}
+
+func normalizeFilter(filter pgrepo.AuditFilter) pgrepo.AuditFilter {
+	filter.Action = strings.ToUpper(strings.TrimSpace(filter.Action))
+	if filter.Offset < 0 {
+		filter.Offset = 0
+	}
+	return filter
+}
+
+func payloadString(payload json.RawMessage) string {
+	trimmed := strings.TrimSpace(string(payload))
+	if trimmed == "" {
+		return "{}"
+	}
+	return trimmed
+}
@@ -0,0 +1,142 @@
+package audit
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	pgrepo "github.com/ivankudzin/tgapp/backend/internal/repo/postgres"
+)
+
+type fakeAuditRepo struct {
+	appended []pgrepo.AuditRecord
+	items    []pgrepo.AuditRecord
+	filters  []pgrepo.AuditFilter
+}
+
+func (f *fakeAuditRepo) Append(_ context.Context, in pgrepo.AuditRecord) (pgrepo.AuditRecord, error) {
+	f.appended = append(f.appended, in)
+	return in, nil
+}
+
+func (f *fakeAuditRepo) Search(_ context.Context, filter pgrepo.AuditFilter) ([]pgrepo.AuditRecord, error) {
+	f.filters = append(f.filters, filter)
+	end := filter.Offset + filter.Limit
+	if end > len(f.items) {
+		end = len(f.items)
+	}
+	if filter.Offset >= end {
+		return []pgrepo.AuditRecord{}, nil
+	}
+	return f.items[filter.Offset:end], nil
+}
+
+func (f *fakeAuditRepo) VerifyChain(context.Context) (pgrepo.AuditChainReport, error) {
+	return pgrepo.AuditChainReport{OK: true}, nil
+}
+
+func TestAppendValidatesInput(t *testing.T) {
+	repo := &fakeAuditRepo{}
+	svc := NewService(repo)
+	svc.now = func() time.Time { return time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC) }
+
+	if _, err := svc.Append(context.Background(), 1, " ", nil); !errors.Is(err, ErrInvalidAction) {
+		t.Fatalf("expected invalid action, got %v", err)
+	}
+	if _, err := svc.Append(context.Background(), 1, "BAN_USER", json.RawMessage(`[1]`)); !errors.Is(err, ErrInvalidPayload) {
+		t.Fatalf("expected invalid payload, got %v", err)
+	}
+
+	record, err := svc.Append(context.Background(), 1, "ban_user", nil)
+	if err != nil {
+		t.Fatalf("append: %v", err)
+	}
+	if record.Action != "BAN_USER" || string(record.Payload) != `{}` || !record.CreatedAt.Equal(svc.now()) {
+		t.Fatalf("unexpected appended record %+v", record)
+	}
+}
+
+func TestSearchPagination(t *testing.T) {
+	repo := &fakeAuditRepo{}
+	for i := 0; i < 5; i++ {
+		repo.items = append(repo.items, pgrepo.AuditRecord{ID: fmt.Sprint(i)})
+	}
+	svc := NewService(repo)
+
+	page, err := svc.Search(context.Background(), pgrepo.AuditFilter{Limit: 3, Action: " ban_user "})
+	if err != nil {
+		t.Fatalf("search: %v", err)
+	}
+	if len(page.Items) != 3 || !page.HasMore || repo.filters[0].Limit != 4 || repo.filters[0].Action != "BAN_USER" {
+		t.Fatalf("unexpected page %+v filter %+v", page, repo.filters[0])
+	}
+
+	page, err = svc.Search(context.Background(), pgrepo.AuditFilter{Limit: 3, Offset: 3})
+	if err != nil {
+		t.Fatalf("search: %v", err)
+	}
+	if len(page.Items) != 2 || page.HasMore {
+		t.Fatalf("unexpected last page %+v", page)
+	}
+}
+
+func TestExportFormats(t *testing.T) {
+	repo := &fakeAuditRepo{items: []pgrepo.AuditRecord{{
+		ID:        "a1",
+		Seq:       7,
+		ActorTGID: 5,
+		Action:    "UNBAN_USER",
+		Payload:   json.RawMessage(`{"target_user_id": 9}`),
+		Hash:      "h1",
+		CreatedAt: time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC),
+	}}}
+	svc := NewService(repo)
+
+	var csvOut bytes.Buffer
+	count, err := svc.Export(context.Background(), pgrepo.AuditFilter{}, "csv", &csvOut)
+	if err != nil || count != 1 {
+		t.Fatalf("export csv: count=%d err=%v", count, err)
+	}
+	lines := strings.Split(strings.TrimSpace(csvOut.String()), "\n")
+	if len(lines) != 2 || lines[1] != `a1,7,2026-01-02T03:04:05Z,5,UNBAN_USER,"{""target_user_id"": 9}",,h1` {
+		t.Fatalf("unexpected csv:\n%s", csvOut.String())
+	}
+	if repo.filters[0].Limit != ExportLimit {
+		t.Fatalf("expected export limit, got %+v", repo.filters[0])
+	}
+
+	var jsonOut bytes.Buffer
+	if _, err := svc.Export(context.Background(), pgrepo.AuditFilter{}, "JSON", &jsonOut); err != nil {
+		t.Fatalf("export json: %v", err)
+	}
+	var decoded []ExportItem
+	if err := json.Unmarshal(jsonOut.Bytes(), &decoded); err != nil || len(decoded) != 1 || decoded[0].Seq != 7 {
+		t.Fatalf("unexpected json export %s (err=%v)", jsonOut.String(), err)
+	}
+
+	if _, err := svc.Export(context.Background(), pgrepo.AuditFilter{}, "xml", &bytes.Buffer{}); !errors.Is(err, ErrUnsupportedFormat) {
+		t.Fatalf("expected unsupported format, got %v", err)
+	}
+}
+
+// bot_moderator pins the same value in its audit tests; both sides must agree to share one chain.
+func TestAuditChainHashMatchesBot(t *testing.T) {
+	record := pgrepo.AuditRecord{
+		ActorTGID: 700001,
+		Action:    "BAN_USER",
+		Payload:   json.RawMessage(`{"reason":"SPAM","target_user_id":42}`),
+		CreatedAt: time.Date(2026, 1, 2, 6, 4, 5, 678901000, time.FixedZone("MSK", 3*3600)),
+	}
+	genesis := pgrepo.AuditChainHash("", record)
+	if genesis != "dd85f6f100a82ea2b1d7fee61b084733c0609faca6a3ad39eeb48eac02131b01" {
+		t.Fatalf("unexpected genesis hash %s", genesis)
+	}
+	if next := pgrepo.AuditChainHash(genesis, record); next != "c06df4dd40c33001e72d0bbe14470acb8e58becab0919e1e260a076a5ba84f8b" {
+		t.Fatalf("unexpected chained hash %s", next)
+	}
+}
@@ -32,6 +32,11 @@ type UserStore interface {
 	GetOrCreateByTelegramID(ctx context.Context, telegramID int64) (UserRecord, error)
 }
 
+// BanStore reports whether a user is currently banned; expired temporary bans count as lifted.
+type BanStore interface {
+	IsBanned(ctx context.Context, userID int64) (bool, error)
+}
+
 type UserRecord struct {
 	UserID int64
 	Role   string
@@ -42,6 +47,7 @@ type Service struct {
 	sessions   SessionStore
 	users      UserStore
 	devices    DeviceStore
+	bans       BanStore
 	refreshTTL time.Duration
 	now        func() time.Time
 }
@@ -70,6 +76,10 @@ func (s *Service) AttachUsers(users UserStore) {
 	s.users = users
 }
 
+func (s *Service) AttachBans(bans BanStore) {
+	s.bans = bans
+}
+
 func (s *Service) LoginTelegram(ctx context.Context, initData, deviceID string) (AuthResult, error) {
 	if err := ValidateTelegramInitData(initData); err != nil {
 		return AuthResult{}, err
@@ -100,6 +110,10 @@ func (s *Service) LoginTelegram(ctx context.Context, initData, deviceID string)
 		}
 	}
 
+	if err := s.ensureNotBanned(ctx, userID); err != nil {
+		return AuthResult{}, err
+	}
+
 	result, err := s.issueForUser(ctx, userID, role)
 	if err != nil {
 		return AuthResult{}, err
@@ -129,6 +143,9 @@ func (s *Service) Refresh(ctx context.Context, refreshToken string) (AuthResult,
 	if s.now().After(session.ExpiresAt) {
 		return AuthResult{}, ErrUnauthorized
 	}
+	if err := s.ensureNotBanned(ctx, session.UserID); err != nil {
+		return AuthResult{}, err
+	}
 
 	newRefreshToken, err := NewRefreshToken()
 	if err != nil {
@@ -159,6 +176,20 @@ func (s *Service) Refresh(ctx context.Context, refreshToken string) (AuthResult,
 	}, nil
 }
 
+func (s *Service) ensureNotBanned(ctx context.Context, userID int64) error {
+	if s.bans == nil {
+		return nil
+	}
+	banned, err := s.bans.IsBanned(ctx, userID)
+	if err != nil {
+		return fmt.Errorf("check user ban: %w", err)
+	}
+	if banned {
+		return ErrBanned
+	}
+	return nil
+}
+
 func (s *Service) Logout(ctx context.Context, sid string) error {
 	if strings.TrimSpace(sid) == "" {
 		return ErrInvalidInput
@@ -99,6 +99,32 @@ func TestLoginTelegramUsesRoleFromUserStore(t *testing.T) {
 	}
 }
 
+func TestBannedUserCannotLoginOrRefresh(t *testing.T) {
+	svc, cleanup := newAuthServiceForTest(t)
+	defer cleanup()
+
+	ctx := context.Background()
+	loginRes, err := svc.LoginTelegram(ctx, "user_id=4004", "0b8f6a52-8a0f-4bb4-9f4e-1f6c2d0b9a11")
+	if err != nil {
+		t.Fatalf("login telegram: %v", err)
+	}
+
+	bans := &fakeBanStore{banned: map[int64]bool{4004: true}}
+	svc.AttachBans(bans)
+
+	if _, err := svc.LoginTelegram(ctx, "user_id=4004", "0b8f6a52-8a0f-4bb4-9f4e-1f6c2d0b9a11"); !errors.Is(err, authsvc.ErrBanned) {
+		t.Fatalf("expected banned login to fail, got err=%v", err)
+	}
+	if _, err := svc.Refresh(ctx, loginRes.RefreshToken); !errors.Is(err, authsvc.ErrBanned) {
+		t.Fatalf("expected banned refresh to fail, got err=%v", err)
+	}
+
+	bans.banned[4004] = false
+	if _, err := svc.Refresh(ctx, loginRes.RefreshToken); err != nil {
+		t.Fatalf("refresh after unban: %v", err)
+	}
+}
+
 func newAuthServiceForTest(t *testing.T) (*authsvc.Service, func()) {
 	t.Helper()
 
@@ -131,3 +157,11 @@ func (f fakeUserStore) GetOrCreateByTelegramID(context.Context, int64) (authsvc.
 		Role:   f.role,
 	}, nil
 }
+
+type fakeBanStore struct {
+	banned map[int64]bool
+}
+
+func (f *fakeBanStore) IsBanned(_ context.Context, userID int64) (bool, error) {
+	return f.banned[userID], nil
+}
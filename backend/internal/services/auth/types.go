@@ -10,6 +10,7 @@ var (
 	ErrUnauthorized    = errors.New("unauthorized")
 	ErrSessionNotFound = errors.New("session not found")
 	ErrRefreshNotFound = errors.New("refresh token not found")
+	ErrBanned          = errors.New("user is banned")
 )
 
 type SessionRecord struct {
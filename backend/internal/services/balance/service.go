@@ -0,0 +1,392 @@
+package balance
+
+import (
+	"context"
+	"errors"
+	"math"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/ivankudzin/tgapp/backend/internal/config"
+	pgrepo "github.com/ivankudzin/tgapp/backend/internal/repo/postgres"
+)
+
+const (
+	GenderFemale = "female"
+	GenderMale   = "male"
+
+	StateBalanced    = "BALANCED"
+	StateFemaleShort = "FEMALE_SHORT"
+	StateMaleShort   = "MALE_SHORT"
+	StateLowSample   = "LOW_SAMPLE"
+
+	// RecomputeInterval is how often Run refreshes the decisions; supply moves over days, so
+	// this only needs to be frequent enough to pick up a config publish.
+	RecomputeInterval = 10 * time.Minute
+
+	defaultActiveWindow = 7 * 24 * time.Hour
+	defaultTargetShare  = 0.5
+	maxLikeFactor       = 3
+)
+
+var ErrUnavailable = errors.New("supply balance store is not configured")
+
+type Store interface {
+	ListSupplySegments(ctx context.Context, since time.Time) ([]pgrepo.SupplySegmentRecord, error)
+	ReplaceSupplyBalance(ctx context.Context, items []pgrepo.SupplyBalanceRecord) error
+	ListSupplyBalance(ctx context.Context) ([]pgrepo.SupplyBalanceRecord, error)
+	ProfileSegment(ctx context.Context, userID int64) (string, string, error)
+}
+
+// Decision is the controller's view of one city. UnderGender and OverGender are empty unless
+// the female share is outside target ± tolerance.
+type Decision struct {
+	CityID               string
+	ActiveFemale         int
+	ActiveMale           int
+	FemaleShare          float64
+	TargetShare          float64
+	State                string
+	UnderGender          string
+	OverGender           string
+	LikesFemale          int
+	LikesMale            int
+	WaitingFemale        int
+	WaitingMale          int
+	ThrottleAdmissions   bool
+	PrioritizeModeration bool
+	OverLikeFactor       float64
+	UnderLikeFactor      float64
+}
+
+// LikeFactor is the multiplier applied to the free daily likes of gender in this city.
+func (d Decision) LikeFactor(gender string) float64 {
+	switch {
+	case d.OverGender != "" && gender == d.OverGender:
+		return d.OverLikeFactor
+	case d.UnderGender != "" && gender == d.UnderGender:
+		return d.UnderLikeFactor
+	default:
+		return 1
+	}
+}
+
+type Report struct {
+	Enabled      bool
+	ActiveWindow time.Duration
+	Tolerance    float64
+	ComputedAt   *time.Time
+	Cities       []Decision
+}
+
+type Service struct {
+	store Store
+	now   func() time.Time
+
+	cfgMu sync.RWMutex
+	cfg   config.BalanceConfig
+
+	mu     sync.RWMutex
+	report Report
+	loaded bool
+}
+
+func NewService(store Store, cfg config.BalanceConfig) *Service {
+	return &Service{store: store, cfg: cfg, now: time.Now}
+}
+
+// Reconfigure swaps the targets after a remote config publish; decisions follow on the next
+// recompute.
+func (s *Service) Reconfigure(cfg config.BalanceConfig) {
+	s.cfgMu.Lock()
+	s.cfg = cfg
+	s.cfgMu.Unlock()
+}
+
+func (s *Service) config() config.BalanceConfig {
+	s.cfgMu.RLock()
+	defer s.cfgMu.RUnlock()
+	return s.cfg
+}
+
+// Run recomputes the decisions right away and then every RecomputeInterval. Every API instance
+// runs it; the result only depends on the database, so concurrent runs write the same rows.
+func (s *Service) Run(ctx context.Context, onError func(error)) {
+	if s.store == nil {
+		return
+	}
+
+	ticker := time.NewTicker(RecomputeInterval)
+	defer ticker.Stop()
+	for {
+		if _, err := s.Recompute(ctx); err != nil && ctx.Err() == nil && onError != nil {
+			onError(err)
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (s *Service) Recompute(ctx context.Context) (Report, error) {
+	if s.store == nil {
+		return Report{}, ErrUnavailable
+	}
+
+	cfg := s.config()
+	now := s.now().UTC()
+	segments, err := s.store.ListSupplySegments(ctx, now.Add(-activeWindow(cfg)))
+	if err != nil {
+		return Report{}, err
+	}
+
+	decisions := Decide(cfg, segments)
+	records := make([]pgrepo.SupplyBalanceRecord, 0, len(decisions))
+	for _, decision := range decisions {
+		records = append(records, toRecord(decision, now))
+	}
+	if err := s.store.ReplaceSupplyBalance(ctx, records); err != nil {
+		return Report{}, err
+	}
+
+	report := newReport(cfg, decisions, &now)
+	s.mu.Lock()
+	s.report = report
+	s.loaded = true
+	s.mu.Unlock()
+	return report, nil
+}
+
+// Report returns the decisions this instance last computed, falling back to the stored ones
+// before its first run.
+func (s *Service) Report(ctx context.Context) (Report, error) {
+	s.mu.RLock()
+	report, loaded := s.report, s.loaded
+	s.mu.RUnlock()
+	if loaded {
+		return report, nil
+	}
+	if s.store == nil {
+		return newReport(s.config(), []Decision{}, nil), nil
+	}
+
+	records, err := s.store.ListSupplyBalance(ctx)
+	if err != nil {
+		return Report{}, err
+	}
+	decisions := make([]Decision, 0, len(records))
+	var computedAt *time.Time
+	for _, record := range records {
+		decisions = append(decisions, fromRecord(record))
+		if computedAt == nil || record.ComputedAt.After(*computedAt) {
+			at := record.ComputedAt
+			computedAt = &at
+		}
+	}
+	return newReport(s.config(), decisions, computedAt), nil
+}
+
+// FreeLikesLimit scales base for the user's segment. It fails open: without a profile, a
+// decision or a working store the base limit applies.
+func (s *Service) FreeLikesLimit(ctx context.Context, userID int64, base int) int {
+	if s.store == nil || userID <= 0 || base <= 0 {
+		return base
+	}
+
+	s.mu.RLock()
+	adjusting := false
+	for _, decision := range s.report.Cities {
+		if decision.OverLikeFactor != 1 || decision.UnderLikeFactor != 1 {
+			adjusting = true
+			break
+		}
+	}
+	cities := s.report.Cities
+	s.mu.RUnlock()
+	if !adjusting {
+		return base
+	}
+
+	cityID, gender, err := s.store.ProfileSegment(ctx, userID)
+	if err != nil {
+		return base
+	}
+	for _, decision := range cities {
+		if decision.CityID != cityID {
+			continue
+		}
+		limit := int(math.Round(float64(base) * decision.LikeFactor(gender)))
+		if limit < 1 {
+			limit = 1
+		}
+		return limit
+	}
+	return base
+}
+
+// Decide turns per-segment supply into per-city decisions. Actions are only set while the
+// controller is enabled; the ratios are reported either way.
+func Decide(cfg config.BalanceConfig, segments []pgrepo.SupplySegmentRecord) []Decision {
+	byCity := make(map[string]*Decision)
+	for _, segment := range segments {
+		cityID := strings.TrimSpace(segment.CityID)
+		if cityID == "" {
+			continue
+		}
+		decision, ok := byCity[cityID]
+		if !ok {
+			decision = &Decision{CityID: cityID}
+			byCity[cityID] = decision
+		}
+		switch strings.ToLower(strings.TrimSpace(segment.Gender)) {
+		case GenderFemale:
+			decision.ActiveFemale += segment.Active
+			decision.LikesFemale += segment.Likes
+			decision.WaitingFemale += segment.Waiting
+		case GenderMale:
+			decision.ActiveMale += segment.Active
+			decision.LikesMale += segment.Likes
+			decision.WaitingMale += segment.Waiting
+		}
+	}
+
+	targets := make(map[string]float64, len(cfg.Targets))
+	for _, target := range cfg.Targets {
+		targets[strings.TrimSpace(target.CityID)] = target.FemaleShare
+	}
+	defaultTarget := cfg.TargetFemaleShare
+	if defaultTarget <= 0 || defaultTarget >= 1 {
+		defaultTarget = defaultTargetShare
+	}
+
+	out := make([]Decision, 0, len(byCity))
+	for _, decision := range byCity {
+		decision.TargetShare = defaultTarget
+		if target, ok := targets[decision.CityID]; ok {
+			decision.TargetShare = target
+		}
+		decision.OverLikeFactor = 1
+		decision.UnderLikeFactor = 1
+
+		total := decision.ActiveFemale + decision.ActiveMale
+		if total > 0 {
+			decision.FemaleShare = float64(decision.ActiveFemale) / float64(total)
+		}
+		switch {
+		case total == 0 || total < cfg.MinActive:
+			decision.State = StateLowSample
+		case decision.FemaleShare < decision.TargetShare-cfg.Tolerance:
+			decision.State = StateFemaleShort
+			decision.UnderGender, decision.OverGender = GenderFemale, GenderMale
+		case decision.FemaleShare > decision.TargetShare+cfg.Tolerance:
+			decision.State = StateMaleShort
+			decision.UnderGender, decision.OverGender = GenderMale, GenderFemale
+		default:
+			decision.State = StateBalanced
+		}
+
+		if cfg.Enabled && decision.UnderGender != "" {
+			decision.ThrottleAdmissions = cfg.ThrottleAdmissions
+			decision.PrioritizeModeration = cfg.PrioritizeModeration
+			decision.OverLikeFactor = likeFactor(cfg.OverLikeFactor)
+			decision.UnderLikeFactor = likeFactor(cfg.UnderLikeFactor)
+		}
+		out = append(out, *decision)
+	}
+
+	sort.Slice(out, func(i, j int) bool {
+		left := out[i].ActiveFemale + out[i].ActiveMale
+		right := out[j].ActiveFemale + out[j].ActiveMale
+		if left != right {
+			return left > right
+		}
+		return out[i].CityID < out[j].CityID
+	})
+	return out
+}
+
+func likeFactor(v float64) float64 {
+	if v <= 0 {
+		return 1
+	}
+	if v > maxLikeFactor {
+		return maxLikeFactor
+	}
+	return v
+}
+
+func activeWindow(cfg config.BalanceConfig) time.Duration {
+	if cfg.ActiveWindow <= 0 {
+		return defaultActiveWindow
+	}
+	return cfg.ActiveWindow
+}
+
+func newReport(cfg config.BalanceConfig, decisions []Decision, computedAt *time.Time) Report {
+	return Report{
+		Enabled:      cfg.Enabled,
+		ActiveWindow: activeWindow(cfg),
+		Tolerance:    cfg.Tolerance,
+		ComputedAt:   computedAt,
+		Cities:       decisions,
+	}
+}
+
+func toRecord(decision Decision, at time.Time) pgrepo.SupplyBalanceRecord {
+	return pgrepo.SupplyBalanceRecord{
+		CityID:               decision.CityID,
+		ActiveFemale:         decision.ActiveFemale,
+		ActiveMale:           decision.ActiveMale,
+		FemaleShare:          decision.FemaleShare,
+		TargetShare:          decision.TargetShare,
+		State:                decision.State,
+		UnderGender:          optionalString(decision.UnderGender),
+		OverGender:           optionalString(decision.OverGender),
+		LikesFemale:          decision.LikesFemale,
+		LikesMale:            decision.LikesMale,
+		WaitingFemale:        decision.WaitingFemale,
+		WaitingMale:          decision.WaitingMale,
+		ThrottleAdmissions:   decision.ThrottleAdmissions,
+		PrioritizeModeration: decision.PrioritizeModeration,
+		OverLikeFactor:       decision.OverLikeFactor,
+		UnderLikeFactor:      decision.UnderLikeFactor,
+		ComputedAt:           at,
+	}
+}
+
+func fromRecord(record pgrepo.SupplyBalanceRecord) Decision {
+	decision := Decision{
+		CityID:               record.CityID,
+		ActiveFemale:         record.ActiveFemale,
+		ActiveMale:           record.ActiveMale,
+		FemaleShare:          record.FemaleShare,
+		TargetShare:          record.TargetShare,
+		State:                record.State,
+		LikesFemale:          record.LikesFemale,
+		LikesMale:            record.LikesMale,
+		WaitingFemale:        record.WaitingFemale,
+		WaitingMale:          record.WaitingMale,
+		ThrottleAdmissions:   record.ThrottleAdmissions,
+		PrioritizeModeration: record.PrioritizeModeration,
+		OverLikeFactor:       record.OverLikeFactor,
+		UnderLikeFactor:      record.UnderLikeFactor,
+	}
+	if record.UnderGender != nil {
+		decision.UnderGender = *record.UnderGender
+	}
+	if record.OverGender != nil {
+		decision.OverGender = *record.OverGender
+	}
+	return decision
+}
+
+func optionalString(v string) *string {
+	if v == "" {
+		return nil
+	}
+	return &v
+}
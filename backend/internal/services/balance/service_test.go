@@ -0,0 +1,165 @@
+package balance
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/ivankudzin/tgapp/backend/internal/config"
+	pgrepo "github.com/ivankudzin/tgapp/backend/internal/repo/postgres"
+)
+
+type fakeStore struct {
+	segments []pgrepo.SupplySegmentRecord
+	since    time.Time
+	saved    []pgrepo.SupplyBalanceRecord
+	profiles map[int64][2]string
+}
+
+func (f *fakeStore) ListSupplySegments(_ context.Context, since time.Time) ([]pgrepo.SupplySegmentRecord, error) {
+	f.since = since
+	return f.segments, nil
+}
+
+func (f *fakeStore) ReplaceSupplyBalance(_ context.Context, items []pgrepo.SupplyBalanceRecord) error {
+	f.saved = items
+	return nil
+}
+
+func (f *fakeStore) ListSupplyBalance(context.Context) ([]pgrepo.SupplyBalanceRecord, error) {
+	return f.saved, nil
+}
+
+func (f *fakeStore) ProfileSegment(_ context.Context, userID int64) (string, string, error) {
+	segment, ok := f.profiles[userID]
+	if !ok {
+		return "", "", pgrepo.ErrProfileSegmentNotFound
+	}
+	return segment[0], segment[1], nil
+}
+
+func testConfig() config.BalanceConfig {
+	cfg := config.Default().Remote.Balance
+	cfg.Enabled = true
+	cfg.MinActive = 10
+	cfg.OverLikeFactor = 0.5
+	cfg.UnderLikeFactor = 1.5
+	cfg.Targets = []config.BalanceTargetConfig{{CityID: "brest", FemaleShare: 0.4}}
+	return cfg
+}
+
+func testSegments() []pgrepo.SupplySegmentRecord {
+	return []pgrepo.SupplySegmentRecord{
+		{CityID: "minsk", Gender: "female", Active: 30, Likes: 100, Waiting: 2},
+		{CityID: "minsk", Gender: "male", Active: 70, Likes: 400, Waiting: 40},
+		{CityID: "brest", Gender: "female", Active: 9, Waiting: 1},
+		{CityID: "brest", Gender: "male", Active: 11},
+		{CityID: "gomel", Gender: "female", Active: 3},
+		{CityID: "gomel", Gender: "male", Active: 2},
+	}
+}
+
+func TestDecideFlagsUnderRepresentedSegments(t *testing.T) {
+	decisions := Decide(testConfig(), testSegments())
+	if len(decisions) != 3 {
+		t.Fatalf("expected 3 cities, got %+v", decisions)
+	}
+
+	minsk := decisions[0]
+	if minsk.CityID != "minsk" || minsk.State != StateFemaleShort || minsk.UnderGender != GenderFemale || minsk.OverGender != GenderMale {
+		t.Fatalf("unexpected minsk decision: %+v", minsk)
+	}
+	if !minsk.ThrottleAdmissions || !minsk.PrioritizeModeration || minsk.FemaleShare != 0.3 || minsk.WaitingMale != 40 {
+		t.Fatalf("expected minsk actions and ratios, got %+v", minsk)
+	}
+	if minsk.LikeFactor(GenderMale) != 0.5 || minsk.LikeFactor(GenderFemale) != 1.5 {
+		t.Fatalf("unexpected like factors: %+v", minsk)
+	}
+
+	brest := decisions[1]
+	if brest.CityID != "brest" || brest.State != StateBalanced || brest.TargetShare != 0.4 || brest.ThrottleAdmissions {
+		t.Fatalf("expected brest to be balanced against its own target, got %+v", brest)
+	}
+
+	gomel := decisions[2]
+	if gomel.State != StateLowSample || gomel.UnderGender != "" || gomel.LikeFactor(GenderMale) != 1 {
+		t.Fatalf("expected gomel to be left alone, got %+v", gomel)
+	}
+}
+
+func TestDecideReportsWithoutActingWhileDisabled(t *testing.T) {
+	cfg := testConfig()
+	cfg.Enabled = false
+
+	minsk := Decide(cfg, testSegments())[0]
+	if minsk.State != StateFemaleShort {
+		t.Fatalf("expected the ratio to be reported, got %+v", minsk)
+	}
+	if minsk.ThrottleAdmissions || minsk.PrioritizeModeration || minsk.OverLikeFactor != 1 || minsk.UnderLikeFactor != 1 {
+		t.Fatalf("expected no actions while disabled, got %+v", minsk)
+	}
+}
+
+func TestRecomputePersistsAndScalesLikes(t *testing.T) {
+	store := &fakeStore{
+		segments: testSegments(),
+		profiles: map[int64][2]string{
+			1: {"minsk", "male"},
+			2: {"minsk", "female"},
+			3: {"brest", "male"},
+		},
+	}
+	service := NewService(store, testConfig())
+	now := time.Date(2026, 10, 19, 12, 0, 0, 0, time.UTC)
+	service.now = func() time.Time { return now }
+	ctx := context.Background()
+
+	if got := service.FreeLikesLimit(ctx, 1, 35); got != 35 {
+		t.Fatalf("expected base limit before the first recompute, got %d", got)
+	}
+
+	report, err := service.Recompute(ctx)
+	if err != nil {
+		t.Fatalf("recompute: %v", err)
+	}
+	if !store.since.Equal(now.Add(-7 * 24 * time.Hour)) {
+		t.Fatalf("expected a 7 day window, got %s", store.since)
+	}
+	if len(store.saved) != 3 || store.saved[0].OverGender == nil || *store.saved[0].OverGender != GenderMale {
+		t.Fatalf("unexpected persisted decisions: %+v", store.saved)
+	}
+	if report.ComputedAt == nil || !report.ComputedAt.Equal(now) {
+		t.Fatalf("unexpected report time: %+v", report.ComputedAt)
+	}
+
+	for userID, want := range map[int64]int{1: 18, 2: 53, 3: 35, 99: 35} {
+		if got := service.FreeLikesLimit(ctx, userID, 35); got != want {
+			t.Fatalf("user %d: expected %d free likes, got %d", userID, want, got)
+		}
+	}
+}
+
+func TestReportFallsBackToStoredDecisions(t *testing.T) {
+	under, over := GenderFemale, GenderMale
+	computedAt := time.Date(2026, 10, 19, 9, 0, 0, 0, time.UTC)
+	store := &fakeStore{saved: []pgrepo.SupplyBalanceRecord{{
+		CityID:      "minsk",
+		State:       StateFemaleShort,
+		UnderGender: &under,
+		OverGender:  &over,
+		ComputedAt:  computedAt,
+	}}}
+
+	report, err := NewService(store, testConfig()).Report(context.Background())
+	if err != nil {
+		t.Fatalf("report: %v", err)
+	}
+	if len(report.Cities) != 1 || report.Cities[0].UnderGender != GenderFemale || report.ComputedAt == nil || !report.ComputedAt.Equal(computedAt) {
+		t.Fatalf("unexpected report: %+v", report)
+	}
+
+	if _, err := NewService(nil, testConfig()).Recompute(context.Background()); !errors.Is(err, ErrUnavailable) {
+		t.Fatalf("expected ErrUnavailable, got %v", err)
+	}
+}
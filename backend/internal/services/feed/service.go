@@ -13,6 +13,7 @@ import (
 	"github.com/ivankudzin/tgapp/backend/internal/domain/rules"
 	pgrepo "github.com/ivankudzin/tgapp/backend/internal/repo/postgres"
 	antiabusesvc "github.com/ivankudzin/tgapp/backend/internal/services/antiabuse"
+	mediasvc "github.com/ivankudzin/tgapp/backend/internal/services/media"
 )
 
 const (
@@ -25,6 +26,7 @@ var (
 	ErrValidation    = errors.New("validation error")
 	ErrInvalidCursor = errors.New("invalid cursor")
 	ErrNotFound      = errors.New("not found")
+	ErrPlusRequired  = errors.New("plus subscription required")
 )
 
 type Repository interface {
@@ -94,6 +96,12 @@ type Item struct {
 	PrimaryPhotoURL *string
 	Age             int
 	DistanceKM      *float64
+	IsVerified      bool
+}
+
+type Filters struct {
+	// VerifiedOnly keeps only candidates with an approved verification circle; Plus only.
+	VerifiedOnly bool
 }
 
 type Result struct {
@@ -102,7 +110,8 @@ type Result struct {
 }
 
 type CandidateBadges struct {
-	IsPlus bool
+	IsPlus   bool
+	Verified bool
 }
 
 type CandidateProfile struct {
@@ -187,6 +196,10 @@ func (s *Service) AttachPhotoSigner(signer PhotoURLSigner) {
 }
 
 func (s *Service) Get(ctx context.Context, userID int64, cursor string, limit int) (Result, error) {
+	return s.GetFiltered(ctx, userID, cursor, limit, Filters{})
+}
+
+func (s *Service) GetFiltered(ctx context.Context, userID int64, cursor string, limit int, filters Filters) (Result, error) {
 	if userID <= 0 {
 		return Result{}, ErrValidation
 	}
@@ -217,6 +230,16 @@ func (s *Service) Get(ctx context.Context, userID int64, cursor string, limit in
 		return Result{Items: []Item{}}, nil
 	}
 
+	if filters.VerifiedOnly {
+		isPlus, err := s.resolvePlus(ctx, userID, s.now().UTC())
+		if err != nil {
+			return Result{}, err
+		}
+		if !isPlus {
+			return Result{}, ErrPlusRequired
+		}
+	}
+
 	ageMin, ageMax := normalizeAgeRange(viewer.AgeMin, viewer.AgeMax, s.cfg.DefaultAgeMin, s.cfg.DefaultAgeMax)
 	radius := normalizeRadius(viewer.RadiusKM, s.cfg.DefaultRadiusKM, s.cfg.MaxRadiusKM)
 	query := pgrepo.FeedQuery{
@@ -230,6 +253,7 @@ func (s *Service) Get(ctx context.Context, userID int64, cursor string, limit in
 		RadiusKM:         radius,
 		ViewerLat:        viewer.LastLat,
 		ViewerLon:        viewer.LastLon,
+		VerifiedOnly:     filters.VerifiedOnly,
 		HasCursor:        hasCursor,
 		Limit:            limit,
 		Now:              s.now().UTC(),
@@ -261,9 +285,10 @@ func (s *Service) Get(ctx context.Context, userID int64, cursor string, limit in
 			City:            candidate.City,
 			Zodiac:          zodiac,
 			PrimaryGoal:     strings.TrimSpace(candidate.PrimaryGoal),
-			PrimaryPhotoURL: s.buildPhotoURL(ctx, candidate.PrimaryPhoto),
+			PrimaryPhotoURL: s.buildPhotoURL(ctx, mediasvc.VariantKey(candidate.PrimaryPhoto, mediasvc.VariantCard)),
 			Age:             candidate.Age,
 			DistanceKM:      candidate.DistanceKM,
+			IsVerified:      candidate.IsVerified,
 		})
 	}
 
@@ -324,7 +349,8 @@ func (s *Service) GetCandidateProfile(ctx context.Context, viewerUserID, candida
 		IsTravel:    false,
 		TravelCity:  nil,
 		Badges: CandidateBadges{
-			IsPlus: record.IsPlus,
+			IsPlus:   record.IsPlus,
+			Verified: record.IsVerified,
 		},
 	}, nil
 }
@@ -395,7 +421,7 @@ func (s *Service) resolvePlus(ctx context.Context, userID int64, at time.Time) (
 
 	isPlus, _, err := s.plusStore.IsPlusActive(ctx, userID, at)
 	if err != nil {
-		return false, fmt.Errorf("resolve plus for ads: %w", err)
+		return false, fmt.Errorf("resolve plus entitlement: %w", err)
 	}
 	if isPlus {
 		return true, nil
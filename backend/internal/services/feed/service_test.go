@@ -227,6 +227,35 @@ func TestGetInjectsAdsByInterval(t *testing.T) {
 	}
 }
 
+func TestGetFilteredVerifiedOnlyRequiresPlus(t *testing.T) {
+	repo := &feedRepoStub{
+		viewer: pgrepo.FeedViewerContext{UserID: 10, CityID: "minsk"},
+		items: []pgrepo.FeedCandidate{
+			{UserID: 1, DisplayName: "u1", CityID: "minsk", City: "Minsk", Age: 21, IsVerified: true, CreatedAt: time.Date(2026, 2, 8, 12, 0, 0, 0, time.UTC)},
+		},
+	}
+	plusStore := &feedPlusStoreStub{isPlus: false}
+	service := NewService(repo, Config{})
+	service.AttachAds(nil, plusStore, AdsConfig{})
+
+	_, err := service.GetFiltered(context.Background(), 10, "", 20, Filters{VerifiedOnly: true})
+	if !errors.Is(err, ErrPlusRequired) {
+		t.Fatalf("expected ErrPlusRequired, got %v", err)
+	}
+
+	plusStore.isPlus = true
+	result, err := service.GetFiltered(context.Background(), 10, "", 20, Filters{VerifiedOnly: true})
+	if err != nil {
+		t.Fatalf("get verified feed: %v", err)
+	}
+	if !repo.lastQuery.VerifiedOnly {
+		t.Fatalf("expected verified only flag in repo query")
+	}
+	if len(result.Items) != 1 || !result.Items[0].IsVerified {
+		t.Fatalf("expected verified candidate in result: %+v", result.Items)
+	}
+}
+
 func TestGetAppliesShadowRankMultiplierAndKeepsCursorOrder(t *testing.T) {
 	repo := &feedRepoStub{
 		viewer: pgrepo.FeedViewerContext{
@@ -366,6 +395,7 @@ func TestGetCandidateProfileReturnsMappedData(t *testing.T) {
 			Languages:   []string{"ru", "en"},
 			Goals:       []string{"relationship"},
 			IsPlus:      true,
+			IsVerified:  true,
 		},
 	}
 	service := NewService(repo, Config{})
@@ -380,6 +410,9 @@ func TestGetCandidateProfileReturnsMappedData(t *testing.T) {
 	if !got.Badges.IsPlus {
 		t.Fatalf("expected plus badge to be true")
 	}
+	if !got.Badges.Verified {
+		t.Fatalf("expected verified badge to be true")
+	}
 }
 
 func TestGetCandidateProfileNotFound(t *testing.T) {
@@ -0,0 +1,242 @@
+package flags
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"hash/fnv"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var ErrInvalidFlag = errors.New("invalid flag")
+
+type ValueType string
+
+const (
+	TypeBool   ValueType = "bool"
+	TypeInt    ValueType = "int"
+	TypeString ValueType = "string"
+)
+
+const (
+	maxRules            = 20
+	maxRuleUserIDs      = 1000
+	maxStringValueLen   = 256
+	maxDescriptionLen   = 200
+	percentageBucketCap = 100
+)
+
+var keyPattern = regexp.MustCompile(`^[a-z][a-z0-9_]{1,63}$`)
+
+// Rule targets a subset of users. Every condition that is set must match; a rule without
+// conditions matches everyone. Percentage buckets are stable per flag and user.
+type Rule struct {
+	CityIDs    []string        `json:"city_ids,omitempty"`
+	Genders    []string        `json:"genders,omitempty"`
+	Plus       *bool           `json:"plus,omitempty"`
+	UserIDs    []int64         `json:"user_ids,omitempty"`
+	Percentage *int            `json:"percentage,omitempty"`
+	Value      json.RawMessage `json:"value"`
+}
+
+type Flag struct {
+	Key           string
+	Type          ValueType
+	Value         json.RawMessage
+	Rules         []Rule
+	Description   string
+	ClientVisible bool
+	UpdatedByTGID *int64
+	UpdatedAt     time.Time
+}
+
+// Subject is who a flag is evaluated for. UserID 0 is an anonymous subject: it never falls
+// into allowlists or percentage buckets.
+type Subject struct {
+	UserID int64
+	CityID string
+	Gender string
+	IsPlus bool
+}
+
+// Evaluate returns the value of the first matching rule, or the flag default.
+func (f Flag) Evaluate(subject Subject) json.RawMessage {
+	for _, rule := range f.Rules {
+		if rule.matches(f.Key, subject) {
+			return rule.Value
+		}
+	}
+	return f.Value
+}
+
+func (r Rule) matches(key string, subject Subject) bool {
+	if len(r.CityIDs) > 0 && !containsFold(r.CityIDs, subject.CityID) {
+		return false
+	}
+	if len(r.Genders) > 0 && !containsFold(r.Genders, subject.Gender) {
+		return false
+	}
+	if r.Plus != nil && *r.Plus != subject.IsPlus {
+		return false
+	}
+	if len(r.UserIDs) > 0 {
+		found := false
+		for _, id := range r.UserIDs {
+			if id == subject.UserID && id > 0 {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	if r.Percentage != nil {
+		if subject.UserID <= 0 || Bucket(key, subject.UserID) >= *r.Percentage {
+			return false
+		}
+	}
+	return true
+}
+
+// Bucket maps a user to 0..99 for a flag. Salting with the key keeps rollouts of different
+// flags independent, so the same 10% of users don't get every experiment.
+func Bucket(key string, userID int64) int {
+	h := fnv.New32a()
+	_, _ = h.Write([]byte(key))
+	_, _ = h.Write([]byte{':'})
+	_, _ = h.Write([]byte(strconv.FormatInt(userID, 10)))
+	return int(h.Sum32() % percentageBucketCap)
+}
+
+// Normalize validates f and returns it with trimmed, lowercased keys and canonical values.
+func Normalize(f Flag) (Flag, error) {
+	f.Key = strings.ToLower(strings.TrimSpace(f.Key))
+	if !keyPattern.MatchString(f.Key) {
+		return Flag{}, fmt.Errorf("%w: key must match %s", ErrInvalidFlag, keyPattern.String())
+	}
+
+	f.Type = ValueType(strings.ToLower(strings.TrimSpace(string(f.Type))))
+	if f.Type == "" {
+		f.Type = TypeBool
+	}
+	if f.Type != TypeBool && f.Type != TypeInt && f.Type != TypeString {
+		return Flag{}, fmt.Errorf("%w: unsupported type %q", ErrInvalidFlag, f.Type)
+	}
+
+	value, err := normalizeValue(f.Type, f.Value)
+	if err != nil {
+		return Flag{}, fmt.Errorf("%w: value: %v", ErrInvalidFlag, err)
+	}
+	f.Value = value
+
+	f.Description = strings.TrimSpace(f.Description)
+	if len([]rune(f.Description)) > maxDescriptionLen {
+		return Flag{}, fmt.Errorf("%w: description is too long", ErrInvalidFlag)
+	}
+
+	if len(f.Rules) > maxRules {
+		return Flag{}, fmt.Errorf("%w: at most %d rules", ErrInvalidFlag, maxRules)
+	}
+	rules := make([]Rule, 0, len(f.Rules))
+	for i, rule := range f.Rules {
+		normalized, err := normalizeRule(f.Type, rule)
+		if err != nil {
+			return Flag{}, fmt.Errorf("%w: rules[%d]: %v", ErrInvalidFlag, i, err)
+		}
+		rules = append(rules, normalized)
+	}
+	f.Rules = rules
+	return f, nil
+}
+
+func normalizeRule(valueType ValueType, rule Rule) (Rule, error) {
+	rule.CityIDs = normalizeTokens(rule.CityIDs)
+	rule.Genders = normalizeTokens(rule.Genders)
+
+	if len(rule.UserIDs) > maxRuleUserIDs {
+		return Rule{}, fmt.Errorf("at most %d user ids", maxRuleUserIDs)
+	}
+	for _, id := range rule.UserIDs {
+		if id <= 0 {
+			return Rule{}, fmt.Errorf("user ids must be positive")
+		}
+	}
+	if rule.Percentage != nil && (*rule.Percentage < 0 || *rule.Percentage > 100) {
+		return Rule{}, fmt.Errorf("percentage must be between 0 and 100")
+	}
+
+	value, err := normalizeValue(valueType, rule.Value)
+	if err != nil {
+		return Rule{}, fmt.Errorf("value: %v", err)
+	}
+	rule.Value = value
+	return rule, nil
+}
+
+func normalizeValue(valueType ValueType, raw json.RawMessage) (json.RawMessage, error) {
+	raw = bytes.TrimSpace(raw)
+	if len(raw) == 0 || bytes.Equal(raw, []byte("null")) {
+		return nil, fmt.Errorf("is required")
+	}
+
+	var out any
+	switch valueType {
+	case TypeBool:
+		var v bool
+		if err := json.Unmarshal(raw, &v); err != nil {
+			return nil, fmt.Errorf("must be a boolean")
+		}
+		out = v
+	case TypeInt:
+		var v int64
+		if err := json.Unmarshal(raw, &v); err != nil {
+			return nil, fmt.Errorf("must be an integer")
+		}
+		out = v
+	case TypeString:
+		var v string
+		if err := json.Unmarshal(raw, &v); err != nil {
+			return nil, fmt.Errorf("must be a string")
+		}
+		if len([]rune(v)) > maxStringValueLen {
+			return nil, fmt.Errorf("is too long")
+		}
+		out = v
+	}
+	return json.Marshal(out)
+}
+
+func normalizeTokens(values []string) []string {
+	if len(values) == 0 {
+		return nil
+	}
+	out := make([]string, 0, len(values))
+	seen := make(map[string]struct{}, len(values))
+	for _, value := range values {
+		value = strings.ToLower(strings.TrimSpace(value))
+		if value == "" {
+			continue
+		}
+		if _, ok := seen[value]; ok {
+			continue
+		}
+		seen[value] = struct{}{}
+		out = append(out, value)
+	}
+	return out
+}
+
+func containsFold(values []string, target string) bool {
+	target = strings.TrimSpace(target)
+	for _, value := range values {
+		if strings.EqualFold(value, target) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,164 @@
+package flags
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+
+	pgrepo "github.com/ivankudzin/tgapp/backend/internal/repo/postgres"
+)
+
+func TestEvaluateFirstMatchingRuleWins(t *testing.T) {
+	plus := true
+	flag, err := Normalize(Flag{
+		Key:   "travel_mode",
+		Type:  TypeBool,
+		Value: json.RawMessage(`false`),
+		Rules: []Rule{
+			{UserIDs: []int64{42}, Value: json.RawMessage(`true`)},
+			{CityIDs: []string{" Minsk "}, Plus: &plus, Value: json.RawMessage(`true`)},
+		},
+	})
+	if err != nil {
+		t.Fatalf("normalize: %v", err)
+	}
+
+	cases := []struct {
+		name    string
+		subject Subject
+		want    string
+	}{
+		{name: "allowlisted", subject: Subject{UserID: 42, CityID: "brest"}, want: "true"},
+		{name: "plus in city", subject: Subject{UserID: 7, CityID: "minsk", IsPlus: true}, want: "true"},
+		{name: "free in city", subject: Subject{UserID: 7, CityID: "minsk"}, want: "false"},
+		{name: "other city", subject: Subject{UserID: 7, CityID: "gomel", IsPlus: true}, want: "false"},
+	}
+	for _, tc := range cases {
+		if got := string(flag.Evaluate(tc.subject)); got != tc.want {
+			t.Fatalf("%s: got %s want %s", tc.name, got, tc.want)
+		}
+	}
+}
+
+func TestPercentageRolloutIsStableAndProportional(t *testing.T) {
+	pct := 20
+	flag := Flag{Key: "new_feed", Type: TypeBool, Value: json.RawMessage(`false`), Rules: []Rule{{Percentage: &pct, Value: json.RawMessage(`true`)}}}
+
+	enabled := 0
+	for id := int64(1); id <= 10000; id++ {
+		first := string(flag.Evaluate(Subject{UserID: id}))
+		if first != string(flag.Evaluate(Subject{UserID: id})) {
+			t.Fatalf("bucket is not stable for user %d", id)
+		}
+		if first == "true" {
+			enabled++
+		}
+	}
+	if enabled < 1700 || enabled > 2300 {
+		t.Fatalf("expected ~20%% rollout, got %d/10000", enabled)
+	}
+	if string(flag.Evaluate(Subject{})) != "false" {
+		t.Fatalf("anonymous subject must not fall into a percentage bucket")
+	}
+	if Bucket("a", 1) == Bucket("b", 1) && Bucket("a", 2) == Bucket("b", 2) && Bucket("a", 3) == Bucket("b", 3) {
+		t.Fatalf("buckets should be salted by flag key")
+	}
+}
+
+func TestNormalizeRejectsTypeMismatch(t *testing.T) {
+	cases := []Flag{
+		{Key: "Bad Key", Type: TypeBool, Value: json.RawMessage(`true`)},
+		{Key: "limit", Type: TypeInt, Value: json.RawMessage(`"ten"`)},
+		{Key: "limit", Type: TypeInt, Value: json.RawMessage(`10`), Rules: []Rule{{Value: json.RawMessage(`1.5`)}}},
+		{Key: "mode", Type: "json", Value: json.RawMessage(`{}`)},
+		{Key: "flag", Type: TypeBool},
+	}
+	for i, in := range cases {
+		if _, err := Normalize(in); !errors.Is(err, ErrInvalidFlag) {
+			t.Fatalf("case %d: expected ErrInvalidFlag, got %v", i, err)
+		}
+	}
+}
+
+func TestServiceSaveKeepsBoolDefaultInValueBool(t *testing.T) {
+	store := &flagStoreStub{}
+	svc := NewService(store)
+	ctx := context.Background()
+
+	saved, err := svc.Save(ctx, Flag{Key: RegistrationEnabled, Type: TypeBool, Value: json.RawMessage(`false`)}, 99)
+	if err != nil {
+		t.Fatalf("save: %v", err)
+	}
+	if store.records[RegistrationEnabled].ValueBool || store.records[RegistrationEnabled].Value != nil {
+		t.Fatalf("bool default must be stored in value_bool: %+v", store.records[RegistrationEnabled])
+	}
+	if string(saved.Value) != "false" || *saved.UpdatedByTGID != 99 {
+		t.Fatalf("unexpected saved flag: %+v", saved)
+	}
+	if svc.Bool(ctx, RegistrationEnabled, Subject{}, true) {
+		t.Fatalf("expected registration to be disabled")
+	}
+
+	if _, err := svc.Save(ctx, Flag{Key: RegistrationEnabled, Type: TypeInt, Value: json.RawMessage(`1`)}, 99); !errors.Is(err, ErrBuiltinFlag) {
+		t.Fatalf("expected ErrBuiltinFlag, got %v", err)
+	}
+	if err := svc.Delete(ctx, RegistrationEnabled); !errors.Is(err, ErrBuiltinFlag) {
+		t.Fatalf("expected ErrBuiltinFlag, got %v", err)
+	}
+	if err := svc.Delete(ctx, "missing"); !errors.Is(err, ErrFlagNotFound) {
+		t.Fatalf("expected ErrFlagNotFound, got %v", err)
+	}
+}
+
+func TestServiceClientValuesOnlyExposesVisibleFlags(t *testing.T) {
+	svc := NewService(&flagStoreStub{})
+	ctx := context.Background()
+
+	if _, err := svc.Save(ctx, Flag{Key: "feed_limit", Type: TypeInt, Value: json.RawMessage(`30`), ClientVisible: true,
+		Rules: []Rule{{CityIDs: []string{"brest"}, Value: json.RawMessage(`50`)}}}, 1); err != nil {
+		t.Fatalf("save: %v", err)
+	}
+	if _, err := svc.Save(ctx, Flag{Key: "internal_only", Type: TypeString, Value: json.RawMessage(`"x"`)}, 1); err != nil {
+		t.Fatalf("save: %v", err)
+	}
+
+	values := svc.ClientValues(ctx, Subject{UserID: 5, CityID: "brest"})
+	if len(values) != 1 || string(values["feed_limit"]) != "50" {
+		t.Fatalf("unexpected client values: %v", values)
+	}
+	if got := svc.Int(ctx, "feed_limit", Subject{CityID: "minsk"}, 0); got != 30 {
+		t.Fatalf("unexpected default int: %d", got)
+	}
+	if got := svc.String(ctx, "unknown", Subject{}, "fallback"); got != "fallback" {
+		t.Fatalf("unexpected fallback: %s", got)
+	}
+}
+
+type flagStoreStub struct {
+	records map[string]pgrepo.AppFlagRecord
+}
+
+func (s *flagStoreStub) ListAppFlags(context.Context) ([]pgrepo.AppFlagRecord, error) {
+	out := make([]pgrepo.AppFlagRecord, 0, len(s.records))
+	for _, record := range s.records {
+		out = append(out, record)
+	}
+	return out, nil
+}
+
+func (s *flagStoreStub) UpsertAppFlag(_ context.Context, in pgrepo.AppFlagRecord) (pgrepo.AppFlagRecord, error) {
+	if s.records == nil {
+		s.records = map[string]pgrepo.AppFlagRecord{}
+	}
+	s.records[in.Key] = in
+	return in, nil
+}
+
+func (s *flagStoreStub) DeleteAppFlag(_ context.Context, key string) error {
+	if _, ok := s.records[key]; !ok {
+		return pgrepo.ErrAppFlagNotFound
+	}
+	delete(s.records, key)
+	return nil
+}
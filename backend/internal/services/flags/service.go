@@ -0,0 +1,286 @@
+package flags
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	pgrepo "github.com/ivankudzin/tgapp/backend/internal/repo/postgres"
+)
+
+const (
+	// RegistrationEnabled is toggled from the bot's System menu; it can't be deleted or retyped.
+	RegistrationEnabled = "registration_enabled"
+
+	flagCacheTTL = 30 * time.Second
+)
+
+var (
+	ErrFlagNotFound  = errors.New("flag not found")
+	ErrBuiltinFlag   = errors.New("built-in flag cannot be deleted or retyped")
+	ErrFlagsReadOnly = errors.New("flag store is not configured")
+)
+
+type Store interface {
+	ListAppFlags(ctx context.Context) ([]pgrepo.AppFlagRecord, error)
+	UpsertAppFlag(ctx context.Context, in pgrepo.AppFlagRecord) (pgrepo.AppFlagRecord, error)
+	DeleteAppFlag(ctx context.Context, key string) error
+}
+
+type SubjectStore interface {
+	AppFlagSubject(ctx context.Context, userID int64) (pgrepo.AppFlagSubjectRecord, error)
+}
+
+type Service struct {
+	subjects SubjectStore
+
+	mu       sync.Mutex
+	store    Store
+	items    []Flag
+	loadedAt time.Time
+}
+
+func NewService(store Store) *Service {
+	return &Service{store: store}
+}
+
+func (s *Service) AttachSubjects(subjects SubjectStore) {
+	s.subjects = subjects
+}
+
+func (s *Service) List(ctx context.Context) ([]Flag, error) {
+	items, err := s.load(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return append([]Flag(nil), items...), nil
+}
+
+func (s *Service) Get(ctx context.Context, key string) (Flag, error) {
+	items, err := s.load(ctx)
+	if err != nil {
+		return Flag{}, err
+	}
+	for _, item := range items {
+		if item.Key == key {
+			return item, nil
+		}
+	}
+	return Flag{}, ErrFlagNotFound
+}
+
+func (s *Service) Save(ctx context.Context, in Flag, actorTGID int64) (Flag, error) {
+	flag, err := Normalize(in)
+	if err != nil {
+		return Flag{}, err
+	}
+	if flag.Key == RegistrationEnabled && flag.Type != TypeBool {
+		return Flag{}, ErrBuiltinFlag
+	}
+
+	record, err := flagToRecord(flag)
+	if err != nil {
+		return Flag{}, err
+	}
+	if actorTGID != 0 {
+		record.UpdatedByTGID = &actorTGID
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.store == nil {
+		return Flag{}, ErrFlagsReadOnly
+	}
+
+	saved, err := s.store.UpsertAppFlag(ctx, record)
+	if err != nil {
+		return Flag{}, err
+	}
+	s.items = nil
+	s.loadedAt = time.Time{}
+	return flagFromRecord(saved)
+}
+
+func (s *Service) Delete(ctx context.Context, key string) error {
+	if key == RegistrationEnabled {
+		return ErrBuiltinFlag
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.store == nil {
+		return ErrFlagsReadOnly
+	}
+
+	if err := s.store.DeleteAppFlag(ctx, key); err != nil {
+		if errors.Is(err, pgrepo.ErrAppFlagNotFound) {
+			return ErrFlagNotFound
+		}
+		return err
+	}
+	s.items = nil
+	s.loadedAt = time.Time{}
+	return nil
+}
+
+// Subject loads the targeting attributes of a user; lookup failures yield an ID-only subject.
+func (s *Service) Subject(ctx context.Context, userID int64) Subject {
+	subject := Subject{UserID: userID}
+	if s.subjects == nil || userID <= 0 {
+		return subject
+	}
+	record, err := s.subjects.AppFlagSubject(ctx, userID)
+	if err != nil {
+		return subject
+	}
+	subject.CityID = record.CityID
+	subject.Gender = record.Gender
+	subject.IsPlus = record.IsPlus
+	return subject
+}
+
+// Value evaluates key for subject; unknown flags and store failures return ok=false.
+func (s *Service) Value(ctx context.Context, key string, subject Subject) (json.RawMessage, bool) {
+	flag, err := s.Get(ctx, key)
+	if err != nil {
+		return nil, false
+	}
+	return flag.Evaluate(subject), true
+}
+
+func (s *Service) Bool(ctx context.Context, key string, subject Subject, fallback bool) bool {
+	raw, ok := s.Value(ctx, key, subject)
+	if !ok {
+		return fallback
+	}
+	var out bool
+	if err := json.Unmarshal(raw, &out); err != nil {
+		return fallback
+	}
+	return out
+}
+
+func (s *Service) Int(ctx context.Context, key string, subject Subject, fallback int64) int64 {
+	raw, ok := s.Value(ctx, key, subject)
+	if !ok {
+		return fallback
+	}
+	var out int64
+	if err := json.Unmarshal(raw, &out); err != nil {
+		return fallback
+	}
+	return out
+}
+
+func (s *Service) String(ctx context.Context, key string, subject Subject, fallback string) string {
+	raw, ok := s.Value(ctx, key, subject)
+	if !ok {
+		return fallback
+	}
+	var out string
+	if err := json.Unmarshal(raw, &out); err != nil {
+		return fallback
+	}
+	return out
+}
+
+// ClientValues evaluates every client-visible flag for subject.
+func (s *Service) ClientValues(ctx context.Context, subject Subject) map[string]json.RawMessage {
+	out := map[string]json.RawMessage{}
+	items, err := s.load(ctx)
+	if err != nil {
+		return out
+	}
+	for _, item := range items {
+		if item.ClientVisible {
+			out[item.Key] = item.Evaluate(subject)
+		}
+	}
+	return out
+}
+
+// load serves a short-lived cache so other API instances pick up edits without a restart;
+// a failed refresh keeps serving the last good snapshot. Without a store there are no flags,
+// so every lookup falls back to the caller's default.
+func (s *Service) load(ctx context.Context) ([]Flag, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.store == nil {
+		return nil, nil
+	}
+	if s.items != nil && time.Since(s.loadedAt) < flagCacheTTL {
+		return s.items, nil
+	}
+
+	records, err := s.store.ListAppFlags(ctx)
+	if err != nil {
+		if s.items != nil {
+			return s.items, nil
+		}
+		return nil, err
+	}
+
+	items := make([]Flag, 0, len(records))
+	for _, record := range records {
+		item, err := flagFromRecord(record)
+		if err != nil {
+			// A row edited by hand into an invalid shape is skipped rather than failing every flag.
+			continue
+		}
+		items = append(items, item)
+	}
+	s.items = items
+	s.loadedAt = time.Now()
+	return items, nil
+}
+
+func flagFromRecord(record pgrepo.AppFlagRecord) (Flag, error) {
+	flag := Flag{
+		Key:           record.Key,
+		Type:          ValueType(record.ValueType),
+		Value:         record.Value,
+		Description:   record.Description,
+		ClientVisible: record.ClientVisible,
+		UpdatedByTGID: record.UpdatedByTGID,
+		UpdatedAt:     record.UpdatedAt,
+	}
+	if flag.Type == TypeBool || flag.Type == "" {
+		flag.Type = TypeBool
+		flag.Value, _ = json.Marshal(record.ValueBool)
+	}
+	if len(record.Rules) > 0 {
+		if err := json.Unmarshal(record.Rules, &flag.Rules); err != nil {
+			return Flag{}, fmt.Errorf("decode flag %s rules: %w", record.Key, err)
+		}
+	}
+	return Normalize(flag)
+}
+
+func flagToRecord(flag Flag) (pgrepo.AppFlagRecord, error) {
+	rules := flag.Rules
+	if rules == nil {
+		rules = []Rule{}
+	}
+	rawRules, err := json.Marshal(rules)
+	if err != nil {
+		return pgrepo.AppFlagRecord{}, fmt.Errorf("encode flag rules: %w", err)
+	}
+
+	record := pgrepo.AppFlagRecord{
+		Key:           flag.Key,
+		ValueType:     string(flag.Type),
+		Rules:         rawRules,
+		Description:   flag.Description,
+		ClientVisible: flag.ClientVisible,
+	}
+	if flag.Type == TypeBool {
+		_ = json.Unmarshal(flag.Value, &record.ValueBool)
+	} else {
+		record.Value = flag.Value
+	}
+	return record, nil
+}
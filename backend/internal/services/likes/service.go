@@ -5,6 +5,7 @@ import (
 	"errors"
 	"fmt"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/jackc/pgx/v5"
@@ -67,6 +68,11 @@ type RevealCreditStore interface {
 	ConsumeRevealCredit(ctx context.Context, tx pgx.Tx, userID int64) error
 }
 
+// LikeLimiter adjusts the free daily likes per user, e.g. by supply balance segment.
+type LikeLimiter interface {
+	FreeLikesLimit(ctx context.Context, userID int64, base int) int
+}
+
 type Config struct {
 	FreeLikesPerDay int
 	DefaultTimezone string
@@ -88,6 +94,8 @@ type Service struct {
 	pool        *pgxpool.Pool
 	incoming    IncomingStore
 	reveal      RevealCreditStore
+	limiter     LikeLimiter
+	cfgMu       sync.RWMutex
 	cfg         Config
 	now         func() time.Time
 }
@@ -114,28 +122,57 @@ type IncomingResult struct {
 }
 
 func NewService(quotaStore QuotaStore, plusStore EntitlementStore, rateLimiter *ratesvc.Limiter, cfg Config) *Service {
-	if cfg.FreeLikesPerDay <= 0 {
-		cfg.FreeLikesPerDay = rules.FreeLikesPerDay
-	}
-	if strings.TrimSpace(cfg.DefaultTimezone) == "" {
-		cfg.DefaultTimezone = "UTC"
-	}
-
 	return &Service{
 		quotaStore:  quotaStore,
 		plusStore:   plusStore,
 		rateLimiter: rateLimiter,
-		cfg:         cfg,
+		cfg:         normalizeConfig(cfg),
 		now:         time.Now,
 	}
 }
 
+// Reconfigure swaps the limits after a remote config publish; requests already running keep the old values.
+func (s *Service) Reconfigure(cfg Config) {
+	cfg = normalizeConfig(cfg)
+	s.cfgMu.Lock()
+	s.cfg = cfg
+	s.cfgMu.Unlock()
+}
+
+func (s *Service) config() Config {
+	s.cfgMu.RLock()
+	defer s.cfgMu.RUnlock()
+	return s.cfg
+}
+
+func normalizeConfig(cfg Config) Config {
+	if cfg.FreeLikesPerDay <= 0 {
+		cfg.FreeLikesPerDay = rules.FreeLikesPerDay
+	}
+	if strings.TrimSpace(cfg.DefaultTimezone) == "" {
+		cfg.DefaultTimezone = "UTC"
+	}
+	return cfg
+}
+
 func (s *Service) AttachIncoming(pool *pgxpool.Pool, incoming IncomingStore, reveal RevealCreditStore) {
 	s.pool = pool
 	s.incoming = incoming
 	s.reveal = reveal
 }
 
+func (s *Service) AttachLikeLimiter(limiter LikeLimiter) {
+	s.limiter = limiter
+}
+
+func (s *Service) freeLikesPerDay(ctx context.Context, userID int64) int {
+	base := s.config().FreeLikesPerDay
+	if s.limiter == nil {
+		return base
+	}
+	return s.limiter.FreeLikesLimit(ctx, userID, base)
+}
+
 func (s *Service) GetSnapshot(ctx context.Context, userID int64, timezone string) (Snapshot, error) {
 	if userID <= 0 {
 		return Snapshot{}, ErrValidation
@@ -154,20 +191,21 @@ func (s *Service) GetSnapshot(ctx context.Context, userID int64, timezone string
 		return Snapshot{}, err
 	}
 
+	freeLikes := s.freeLikesPerDay(ctx, userID)
 	snapshot := Snapshot{
-		LikesLeft: s.cfg.FreeLikesPerDay,
+		LikesLeft: freeLikes,
 		ResetAt:   resetAt,
 		IsPlus:    isPlus,
 	}
 
-	if isPlus && s.cfg.PlusUnlimitedUI {
+	if isPlus && s.config().PlusUnlimitedUI {
 		snapshot.LikesLeft = -1
 	} else {
 		used, err := s.quotaStore.GetLikesUsed(ctx, userID, dayKey)
 		if err != nil {
 			return Snapshot{}, fmt.Errorf("read daily quota: %w", err)
 		}
-		left := s.cfg.FreeLikesPerDay - used
+		left := freeLikes - used
 		if left < 0 {
 			left = 0
 		}
@@ -227,7 +265,8 @@ func (s *Service) ConsumeLike(ctx context.Context, userID int64, timezone string
 	if err != nil {
 		return Snapshot{}, fmt.Errorf("read daily quota: %w", err)
 	}
-	if used >= s.cfg.FreeLikesPerDay {
+	freeLikes := s.freeLikesPerDay(ctx, userID)
+	if used >= freeLikes {
 		return Snapshot{}, ErrDailyLimit
 	}
 
@@ -236,7 +275,7 @@ func (s *Service) ConsumeLike(ctx context.Context, userID int64, timezone string
 		return Snapshot{}, fmt.Errorf("update daily quota: %w", err)
 	}
 
-	left := s.cfg.FreeLikesPerDay - updatedUsed
+	left := freeLikes - updatedUsed
 	if left < 0 {
 		left = 0
 	}
@@ -342,7 +381,7 @@ func (s *Service) RevealOne(ctx context.Context, userID int64) (IncomingProfile,
 
 func (s *Service) resolvePlus(ctx context.Context, userID int64, at time.Time) (bool, error) {
 	if s.plusStore == nil {
-		return s.cfg.DefaultIsPlus, nil
+		return s.config().DefaultIsPlus, nil
 	}
 
 	isPlus, _, err := s.plusStore.IsPlusActive(ctx, userID, at)
@@ -353,13 +392,13 @@ func (s *Service) resolvePlus(ctx context.Context, userID int64, at time.Time) (
 		return true, nil
 	}
 
-	return s.cfg.DefaultIsPlus, nil
+	return s.config().DefaultIsPlus, nil
 }
 
 func (s *Service) resolveTimezone(explicit string) (*time.Location, string) {
 	candidate := strings.TrimSpace(explicit)
 	if candidate == "" {
-		candidate = strings.TrimSpace(s.cfg.DefaultTimezone)
+		candidate = strings.TrimSpace(s.config().DefaultTimezone)
 	}
 	if candidate == "" {
 		candidate = "UTC"
@@ -71,3 +71,38 @@ func TestConsumeLikeResetsOnLocalMidnight(t *testing.T) {
 		t.Fatalf("unexpected likes_left after reset: got %d want %d", snapshot.LikesLeft, 34)
 	}
 }
+
+type fixedLikeLimiter struct {
+	limits map[int64]int
+}
+
+func (l fixedLikeLimiter) FreeLikesLimit(_ context.Context, userID int64, base int) int {
+	if limit, ok := l.limits[userID]; ok {
+		return limit
+	}
+	return base
+}
+
+func TestConsumeLikeUsesLimiterPerUser(t *testing.T) {
+	store := newMemoryQuotaStore()
+	service := NewService(store, nil, nil, Config{FreeLikesPerDay: 35, DefaultTimezone: "UTC"})
+	service.AttachLikeLimiter(fixedLikeLimiter{limits: map[int64]int{7: 2}})
+	ctx := context.Background()
+
+	for i := 0; i < 2; i++ {
+		if _, err := service.ConsumeLike(ctx, 7, ""); err != nil {
+			t.Fatalf("consume like #%d: %v", i+1, err)
+		}
+	}
+	if _, err := service.ConsumeLike(ctx, 7, ""); !errors.Is(err, ErrDailyLimit) {
+		t.Fatalf("expected ErrDailyLimit at the adjusted limit, got %v", err)
+	}
+
+	snapshot, err := service.GetSnapshot(ctx, 8, "")
+	if err != nil {
+		t.Fatalf("snapshot: %v", err)
+	}
+	if snapshot.LikesLeft != 35 {
+		t.Fatalf("expected other users to keep the base limit, got %d", snapshot.LikesLeft)
+	}
+}
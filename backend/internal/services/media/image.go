@@ -0,0 +1,410 @@
+package media
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"image"
+	"image/color"
+	"image/jpeg"
+	"image/png"
+	"math"
+	"math/bits"
+	"sort"
+	"strings"
+
+	"golang.org/x/image/draw"
+	"golang.org/x/image/webp"
+)
+
+var (
+	ErrUnsupportedImage = errors.New("unsupported image format")
+	ErrImageTooSmall    = errors.New("image is too small")
+	ErrImageTooLarge    = errors.New("image is too large")
+)
+
+const (
+	ImageFormatJPEG = "jpeg"
+	ImageFormatPNG  = "png"
+	ImageFormatWebP = "webp"
+
+	maxImageBytes    = 20 << 20 // 20 MiB
+	minImageSide     = 320
+	maxImageSide     = 10000
+	maxImagePixels   = 40_000_000
+	variantJPEGQual  = 85
+	variantKeyExt    = ".jpg"
+	perceptualSize   = 32
+	perceptualBlock  = 8
+	exifOrientTag    = 0x0112
+	exifHeaderLength = 6
+)
+
+type Variant string
+
+const (
+	VariantThumb Variant = "thumb"
+	VariantCard  Variant = "card"
+	VariantFull  Variant = "full"
+)
+
+var photoVariants = []struct {
+	Variant Variant
+	MaxSide int
+}{
+	{Variant: VariantThumb, MaxSide: 320},
+	{Variant: VariantCard, MaxSide: 800},
+	{Variant: VariantFull, MaxSide: 1600},
+}
+
+type EncodedVariant struct {
+	Variant Variant
+	Width   int
+	Height  int
+	Data    []byte
+}
+
+type ProcessedImage struct {
+	Format   string
+	Width    int
+	Height   int
+	Hashes   ImageHashes
+	Variants []EncodedVariant
+}
+
+// ProcessImage validates an uploaded photo and re-encodes it into JPEG variants.
+// Re-encoding from decoded pixels drops every metadata segment (EXIF, GPS, XMP),
+// so the EXIF orientation is applied to the pixels beforehand.
+func ProcessImage(data []byte) (ProcessedImage, error) {
+	if len(data) == 0 {
+		return ProcessedImage{}, ErrValidation
+	}
+	if len(data) > maxImageBytes {
+		return ProcessedImage{}, ErrImageTooLarge
+	}
+
+	format := sniffImageFormat(data)
+	if format == "" {
+		return ProcessedImage{}, ErrUnsupportedImage
+	}
+
+	cfg, err := decodeImageConfig(format, data)
+	if err != nil {
+		return ProcessedImage{}, ErrUnsupportedImage
+	}
+	if cfg.Width > maxImageSide || cfg.Height > maxImageSide || cfg.Width*cfg.Height > maxImagePixels {
+		return ProcessedImage{}, ErrImageTooLarge
+	}
+	if cfg.Width < minImageSide || cfg.Height < minImageSide {
+		return ProcessedImage{}, ErrImageTooSmall
+	}
+
+	src, err := decodeImage(format, data)
+	if err != nil {
+		return ProcessedImage{}, ErrUnsupportedImage
+	}
+
+	normalized := flattenImage(src)
+	if format == ImageFormatJPEG {
+		normalized = applyOrientation(normalized, jpegOrientation(data))
+	}
+
+	bounds := normalized.Bounds()
+	result := ProcessedImage{
+		Format: format,
+		Width:  bounds.Dx(),
+		Height: bounds.Dy(),
+		Hashes: ImageHashes{
+			PHash: PerceptualHash(normalized),
+			DHash: DifferenceHash(normalized),
+		},
+		Variants: make([]EncodedVariant, 0, len(photoVariants)),
+	}
+
+	for _, spec := range photoVariants {
+		resized := fitWithin(normalized, spec.MaxSide)
+		var buf bytes.Buffer
+		if err := jpeg.Encode(&buf, resized, &jpeg.Options{Quality: variantJPEGQual}); err != nil {
+			return ProcessedImage{}, fmt.Errorf("encode %s variant: %w", spec.Variant, err)
+		}
+		result.Variants = append(result.Variants, EncodedVariant{
+			Variant: spec.Variant,
+			Width:   resized.Bounds().Dx(),
+			Height:  resized.Bounds().Dy(),
+			Data:    buf.Bytes(),
+		})
+	}
+
+	return result, nil
+}
+
+// VariantKey derives the object key of a photo variant from the stored full-size key.
+// Keys that predate variant processing are returned unchanged.
+func VariantKey(key string, variant Variant) string {
+	trimmed := strings.TrimSpace(key)
+	fullSuffix := "_" + string(VariantFull) + variantKeyExt
+	if !strings.HasSuffix(trimmed, fullSuffix) {
+		return trimmed
+	}
+	switch variant {
+	case VariantThumb, VariantCard, VariantFull:
+	default:
+		return trimmed
+	}
+	return strings.TrimSuffix(trimmed, fullSuffix) + "_" + string(variant) + variantKeyExt
+}
+
+func HammingDistance(a, b uint64) int {
+	return bits.OnesCount64(a ^ b)
+}
+
+// PerceptualHash computes a 64-bit DCT hash: the image is reduced to 32x32 grayscale,
+// transformed, and the 63 AC coefficients of the low-frequency 8x8 block are thresholded
+// against their median. The DC term only tracks overall brightness, so its bit stays zero.
+func PerceptualHash(img image.Image) uint64 {
+	small := image.NewGray(image.Rect(0, 0, perceptualSize, perceptualSize))
+	draw.CatmullRom.Scale(small, small.Bounds(), img, img.Bounds(), draw.Src, nil)
+
+	pixels := make([][]float64, perceptualSize)
+	for y := 0; y < perceptualSize; y++ {
+		pixels[y] = make([]float64, perceptualSize)
+		for x := 0; x < perceptualSize; x++ {
+			pixels[y][x] = float64(small.GrayAt(x, y).Y)
+		}
+	}
+
+	coeffs := dct2D(pixels)
+	block := make([]float64, 0, perceptualBlock*perceptualBlock)
+	for y := 0; y < perceptualBlock; y++ {
+		for x := 0; x < perceptualBlock; x++ {
+			block = append(block, coeffs[y][x])
+		}
+	}
+
+	sorted := append([]float64(nil), block[1:]...)
+	sort.Float64s(sorted)
+	// 63 AC coefficients: the median is the middle element.
+	median := sorted[len(sorted)/2]
+
+	var hash uint64
+	for i := 1; i < len(block); i++ {
+		if block[i] > median {
+			hash |= 1 << uint(i)
+		}
+	}
+	return hash
+}
+
+func dct2D(input [][]float64) [][]float64 {
+	n := len(input)
+	cosTable := make([][]float64, n)
+	for k := 0; k < n; k++ {
+		cosTable[k] = make([]float64, n)
+		for i := 0; i < n; i++ {
+			cosTable[k][i] = math.Cos(math.Pi / float64(n) * (float64(i) + 0.5) * float64(k))
+		}
+	}
+
+	rows := make([][]float64, n)
+	for y := 0; y < n; y++ {
+		rows[y] = make([]float64, n)
+		for k := 0; k < n; k++ {
+			sum := 0.0
+			for i := 0; i < n; i++ {
+				sum += input[y][i] * cosTable[k][i]
+			}
+			rows[y][k] = sum
+		}
+	}
+
+	out := make([][]float64, n)
+	for k := 0; k < n; k++ {
+		out[k] = make([]float64, n)
+	}
+	for x := 0; x < n; x++ {
+		for k := 0; k < n; k++ {
+			sum := 0.0
+			for i := 0; i < n; i++ {
+				sum += rows[i][x] * cosTable[k][i]
+			}
+			out[k][x] = sum
+		}
+	}
+	return out
+}
+
+func sniffImageFormat(data []byte) string {
+	switch {
+	case len(data) >= 3 && data[0] == 0xFF && data[1] == 0xD8 && data[2] == 0xFF:
+		return ImageFormatJPEG
+	case len(data) >= 8 && bytes.Equal(data[:8], []byte{0x89, 'P', 'N', 'G', 0x0D, 0x0A, 0x1A, 0x0A}):
+		return ImageFormatPNG
+	case len(data) >= 12 && string(data[:4]) == "RIFF" && string(data[8:12]) == "WEBP":
+		return ImageFormatWebP
+	default:
+		return ""
+	}
+}
+
+func decodeImageConfig(format string, data []byte) (image.Config, error) {
+	reader := bytes.NewReader(data)
+	switch format {
+	case ImageFormatJPEG:
+		return jpeg.DecodeConfig(reader)
+	case ImageFormatPNG:
+		return png.DecodeConfig(reader)
+	case ImageFormatWebP:
+		return webp.DecodeConfig(reader)
+	default:
+		return image.Config{}, ErrUnsupportedImage
+	}
+}
+
+func decodeImage(format string, data []byte) (image.Image, error) {
+	reader := bytes.NewReader(data)
+	switch format {
+	case ImageFormatJPEG:
+		return jpeg.Decode(reader)
+	case ImageFormatPNG:
+		return png.Decode(reader)
+	case ImageFormatWebP:
+		return webp.Decode(reader)
+	default:
+		return nil, ErrUnsupportedImage
+	}
+}
+
+// flattenImage composites the source over white so transparent PNG/WebP areas survive JPEG encoding.
+func flattenImage(src image.Image) *image.RGBA {
+	bounds := src.Bounds()
+	dst := image.NewRGBA(image.Rect(0, 0, bounds.Dx(), bounds.Dy()))
+	draw.Draw(dst, dst.Bounds(), &image.Uniform{C: color.White}, image.Point{}, draw.Src)
+	draw.Draw(dst, dst.Bounds(), src, bounds.Min, draw.Over)
+	return dst
+}
+
+func fitWithin(src *image.RGBA, maxSide int) *image.RGBA {
+	width := src.Bounds().Dx()
+	height := src.Bounds().Dy()
+	if width <= maxSide && height <= maxSide {
+		return src
+	}
+
+	if width >= height {
+		height = int(math.Round(float64(height) * float64(maxSide) / float64(width)))
+		width = maxSide
+	} else {
+		width = int(math.Round(float64(width) * float64(maxSide) / float64(height)))
+		height = maxSide
+	}
+	if width < 1 {
+		width = 1
+	}
+	if height < 1 {
+		height = 1
+	}
+
+	dst := image.NewRGBA(image.Rect(0, 0, width, height))
+	draw.CatmullRom.Scale(dst, dst.Bounds(), src, src.Bounds(), draw.Src, nil)
+	return dst
+}
+
+func applyOrientation(src *image.RGBA, orientation int) *image.RGBA {
+	if orientation < 2 || orientation > 8 {
+		return src
+	}
+
+	width := src.Bounds().Dx()
+	height := src.Bounds().Dy()
+	dstWidth, dstHeight := width, height
+	if orientation >= 5 {
+		dstWidth, dstHeight = height, width
+	}
+
+	dst := image.NewRGBA(image.Rect(0, 0, dstWidth, dstHeight))
+	for y := 0; y < height; y++ {
+		for x := 0; x < width; x++ {
+			var dx, dy int
+			switch orientation {
+			case 2:
+				dx, dy = width-1-x, y
+			case 3:
+				dx, dy = width-1-x, height-1-y
+			case 4:
+				dx, dy = x, height-1-y
+			case 5:
+				dx, dy = y, x
+			case 6:
+				dx, dy = height-1-y, x
+			case 7:
+				dx, dy = height-1-y, width-1-x
+			case 8:
+				dx, dy = y, width-1-x
+			}
+			dst.SetRGBA(dx, dy, src.RGBAAt(x, y))
+		}
+	}
+	return dst
+}
+
+// jpegOrientation reads the EXIF orientation tag from the APP1 segment; 1 means "as stored".
+func jpegOrientation(data []byte) int {
+	offset := 2
+	for offset+4 <= len(data) {
+		if data[offset] != 0xFF {
+			return 1
+		}
+		marker := data[offset+1]
+		if marker == 0xDA || marker == 0xD9 {
+			return 1
+		}
+		segmentLength := int(binary.BigEndian.Uint16(data[offset+2 : offset+4]))
+		if segmentLength < 2 || offset+2+segmentLength > len(data) {
+			return 1
+		}
+		segment := data[offset+4 : offset+2+segmentLength]
+		if marker == 0xE1 && len(segment) > exifHeaderLength && string(segment[:exifHeaderLength]) == "Exif\x00\x00" {
+			return exifOrientation(segment[exifHeaderLength:])
+		}
+		offset += 2 + segmentLength
+	}
+	return 1
+}
+
+func exifOrientation(tiff []byte) int {
+	if len(tiff) < 8 {
+		return 1
+	}
+
+	var order binary.ByteOrder
+	switch string(tiff[:2]) {
+	case "II":
+		order = binary.LittleEndian
+	case "MM":
+		order = binary.BigEndian
+	default:
+		return 1
+	}
+
+	ifdOffset := int(order.Uint32(tiff[4:8]))
+	if ifdOffset < 8 || ifdOffset+2 > len(tiff) {
+		return 1
+	}
+	entries := int(order.Uint16(tiff[ifdOffset : ifdOffset+2]))
+	for i := 0; i < entries; i++ {
+		entry := ifdOffset + 2 + i*12
+		if entry+12 > len(tiff) {
+			return 1
+		}
+		if order.Uint16(tiff[entry:entry+2]) != exifOrientTag {
+			continue
+		}
+		value := int(order.Uint16(tiff[entry+8 : entry+10]))
+		if value < 1 || value > 8 {
+			return 1
+		}
+		return value
+	}
+	return 1
+}
@@ -0,0 +1,245 @@
+package media
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"image"
+	"image/color"
+	"image/jpeg"
+	"image/png"
+	"math"
+	"testing"
+)
+
+func TestProcessImageBuildsVariants(t *testing.T) {
+	processed, err := ProcessImage(encodeTestJPEG(t, 2400, 1200))
+	if err != nil {
+		t.Fatalf("process image: %v", err)
+	}
+	if processed.Format != ImageFormatJPEG || processed.Width != 2400 || processed.Height != 1200 {
+		t.Fatalf("unexpected processed image: %s %dx%d", processed.Format, processed.Width, processed.Height)
+	}
+
+	expected := map[Variant][2]int{
+		VariantThumb: {320, 160},
+		VariantCard:  {800, 400},
+		VariantFull:  {1600, 800},
+	}
+	if len(processed.Variants) != len(expected) {
+		t.Fatalf("unexpected variants count: %d", len(processed.Variants))
+	}
+	for _, variant := range processed.Variants {
+		size, ok := expected[variant.Variant]
+		if !ok {
+			t.Fatalf("unexpected variant %q", variant.Variant)
+		}
+		if variant.Width != size[0] || variant.Height != size[1] {
+			t.Fatalf("variant %s: got %dx%d want %dx%d", variant.Variant, variant.Width, variant.Height, size[0], size[1])
+		}
+		cfg, err := jpeg.DecodeConfig(bytes.NewReader(variant.Data))
+		if err != nil {
+			t.Fatalf("variant %s is not a jpeg: %v", variant.Variant, err)
+		}
+		if cfg.Width != size[0] || cfg.Height != size[1] {
+			t.Fatalf("variant %s encoded as %dx%d", variant.Variant, cfg.Width, cfg.Height)
+		}
+	}
+}
+
+func TestProcessImageDoesNotUpscale(t *testing.T) {
+	processed, err := ProcessImage(encodeTestPNG(t, 400, 500))
+	if err != nil {
+		t.Fatalf("process image: %v", err)
+	}
+	for _, variant := range processed.Variants {
+		if variant.Variant == VariantThumb {
+			if variant.Width != 256 || variant.Height != 320 {
+				t.Fatalf("unexpected thumb size: %dx%d", variant.Width, variant.Height)
+			}
+			continue
+		}
+		if variant.Width != 400 || variant.Height != 500 {
+			t.Fatalf("variant %s should keep source size, got %dx%d", variant.Variant, variant.Width, variant.Height)
+		}
+	}
+}
+
+func TestProcessImageStripsExifAndAppliesOrientation(t *testing.T) {
+	source := encodeTestJPEG(t, 800, 400)
+	withExif := insertExifSegment(t, source, 6, "GPS 53.9006N 27.5590E")
+
+	processed, err := ProcessImage(withExif)
+	if err != nil {
+		t.Fatalf("process image: %v", err)
+	}
+	if processed.Width != 400 || processed.Height != 800 {
+		t.Fatalf("expected orientation 6 to rotate to 400x800, got %dx%d", processed.Width, processed.Height)
+	}
+
+	for _, variant := range processed.Variants {
+		if bytes.Contains(variant.Data, []byte("Exif")) || bytes.Contains(variant.Data, []byte("GPS")) {
+			t.Fatalf("variant %s still contains exif metadata", variant.Variant)
+		}
+	}
+}
+
+func TestProcessImageValidation(t *testing.T) {
+	testCases := []struct {
+		name string
+		data []byte
+		want error
+	}{
+		{name: "garbage", data: []byte("definitely not an image"), want: ErrUnsupportedImage},
+		{name: "gif", data: []byte("GIF89a\x01\x00\x01\x00"), want: ErrUnsupportedImage},
+		{name: "truncated_jpeg", data: []byte{0xFF, 0xD8, 0xFF, 0xE0}, want: ErrUnsupportedImage},
+		{name: "too_small", data: encodeTestJPEG(t, 200, 600), want: ErrImageTooSmall},
+		{name: "too_wide", data: encodeTestPNG(t, maxImageSide+1, minImageSide), want: ErrImageTooLarge},
+		{name: "too_many_bytes", data: make([]byte, maxImageBytes+1), want: ErrImageTooLarge},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := ProcessImage(tc.data)
+			if !errors.Is(err, tc.want) {
+				t.Fatalf("expected %v, got %v", tc.want, err)
+			}
+		})
+	}
+}
+
+func TestSniffImageFormat(t *testing.T) {
+	webpHeader := append([]byte("RIFF\x00\x00\x00\x00WEBP"), []byte("VP8 ")...)
+	if got := sniffImageFormat(webpHeader); got != ImageFormatWebP {
+		t.Fatalf("expected webp, got %q", got)
+	}
+	if got := sniffImageFormat(encodeTestPNG(t, 1, 1)); got != ImageFormatPNG {
+		t.Fatalf("expected png, got %q", got)
+	}
+	if got := sniffImageFormat(encodeTestJPEG(t, 8, 8)); got != ImageFormatJPEG {
+		t.Fatalf("expected jpeg, got %q", got)
+	}
+}
+
+func TestPerceptualHashSimilarity(t *testing.T) {
+	original := testPattern(1200, 900, 0)
+	downscaled := fitWithin(flattenImage(original), 300)
+	different := testPattern(1200, 900, 1)
+
+	originalHash := PerceptualHash(original)
+	if distance := HammingDistance(originalHash, PerceptualHash(downscaled)); distance > 6 {
+		t.Fatalf("expected resized image to keep hash, distance=%d", distance)
+	}
+	if distance := HammingDistance(originalHash, PerceptualHash(different)); distance < 16 {
+		t.Fatalf("expected different images to diverge, distance=%d", distance)
+	}
+	if distance := HammingDistance(originalHash, PerceptualHash(brighten(original, 15))); distance > 2 {
+		t.Fatalf("expected a brightness shift to keep hash, distance=%d", distance)
+	}
+	if originalHash&1 != 0 {
+		t.Fatalf("the DC bit must not be set")
+	}
+}
+
+func brighten(img image.Image, delta uint8) image.Image {
+	bounds := img.Bounds()
+	out := image.NewRGBA(bounds)
+	for y := bounds.Min.Y; y < bounds.Max.Y; y++ {
+		for x := bounds.Min.X; x < bounds.Max.X; x++ {
+			r, _, _, _ := img.At(x, y).RGBA()
+			value := uint8(r>>8) + delta
+			out.SetRGBA(x, y, color.RGBA{R: value, G: value, B: value, A: 255})
+		}
+	}
+	return out
+}
+
+func TestVariantKey(t *testing.T) {
+	key := "users/1/photos/20260101T000000_abcd_full.jpg"
+	if got := VariantKey(key, VariantThumb); got != "users/1/photos/20260101T000000_abcd_thumb.jpg" {
+		t.Fatalf("unexpected thumb key: %q", got)
+	}
+	if got := VariantKey(key, VariantCard); got != "users/1/photos/20260101T000000_abcd_card.jpg" {
+		t.Fatalf("unexpected card key: %q", got)
+	}
+	if got := VariantKey(key, VariantFull); got != key {
+		t.Fatalf("unexpected full key: %q", got)
+	}
+
+	legacy := "users/1/photos/20250101T000000_abcd.png"
+	if got := VariantKey(legacy, VariantCard); got != legacy {
+		t.Fatalf("legacy key should be unchanged, got %q", got)
+	}
+}
+
+func testPattern(width, height, seed int) image.Image {
+	img := image.NewRGBA(image.Rect(0, 0, width, height))
+	for y := 0; y < height; y++ {
+		for x := 0; x < width; x++ {
+			var value uint8
+			if seed == 0 {
+				fx := float64(x) / float64(width)
+				fy := float64(y) / float64(height)
+				value = uint8(128 + 60*math.Sin(5*fx+1) + 50*math.Cos(7*fy*fx+2*fy))
+			} else {
+				if (x/(width/4)+y/(height/4))%2 == 0 {
+					value = 230
+				} else {
+					value = 20
+				}
+			}
+			img.SetRGBA(x, y, color.RGBA{R: value, G: value, B: value, A: 255})
+		}
+	}
+	return img
+}
+
+func encodeTestJPEG(t *testing.T, width, height int) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	if err := jpeg.Encode(&buf, testPattern(width, height, 0), &jpeg.Options{Quality: 90}); err != nil {
+		t.Fatalf("encode jpeg: %v", err)
+	}
+	return buf.Bytes()
+}
+
+func encodeTestPNG(t *testing.T, width, height int) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	img := image.NewNRGBA(image.Rect(0, 0, width, height))
+	for y := 0; y < height; y++ {
+		for x := 0; x < width; x++ {
+			img.SetNRGBA(x, y, color.NRGBA{R: 200, G: 80, B: 40, A: uint8(x % 256)})
+		}
+	}
+	if err := png.Encode(&buf, img); err != nil {
+		t.Fatalf("encode png: %v", err)
+	}
+	return buf.Bytes()
+}
+
+func insertExifSegment(t *testing.T, jpegData []byte, orientation uint16, comment string) []byte {
+	t.Helper()
+
+	tiff := &bytes.Buffer{}
+	tiff.WriteString("MM")
+	_ = binary.Write(tiff, binary.BigEndian, uint16(42))
+	_ = binary.Write(tiff, binary.BigEndian, uint32(8))
+	_ = binary.Write(tiff, binary.BigEndian, uint16(1))
+	_ = binary.Write(tiff, binary.BigEndian, uint16(exifOrientTag))
+	_ = binary.Write(tiff, binary.BigEndian, uint16(3))
+	_ = binary.Write(tiff, binary.BigEndian, uint32(1))
+	_ = binary.Write(tiff, binary.BigEndian, orientation)
+	_ = binary.Write(tiff, binary.BigEndian, uint16(0))
+	_ = binary.Write(tiff, binary.BigEndian, uint32(0))
+	tiff.WriteString(comment)
+
+	payload := append([]byte("Exif\x00\x00"), tiff.Bytes()...)
+	segment := []byte{0xFF, 0xE1, 0, 0}
+	binary.BigEndian.PutUint16(segment[2:], uint16(len(payload)+2))
+	segment = append(segment, payload...)
+
+	out := append([]byte{}, jpegData[:2]...)
+	out = append(out, segment...)
+	return append(out, jpegData[2:]...)
+}
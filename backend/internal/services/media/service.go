@@ -1,13 +1,14 @@
 package media
 
 import (
+	"bytes"
 	"context"
 	"crypto/rand"
 	"encoding/hex"
 	"errors"
 	"fmt"
 	"io"
-	"path"
+	"log"
 	"strings"
 	"time"
 )
@@ -22,13 +23,24 @@ const (
 	photoStatus     = "active"
 	signedURLTTL    = 5 * time.Minute
 	maxActivePhotos = 3
+	primaryPosition = 1
 )
 
 type Store interface {
-	CreatePhoto(ctx context.Context, userID int64, objectKey string) (PhotoRecord, error)
+	CreatePhoto(ctx context.Context, userID int64, objectKey string, hashes ImageHashes) (PhotoRecord, error)
 	ListActivePhotos(ctx context.Context, userID int64) ([]PhotoRecord, error)
 }
 
+type HashIndex interface {
+	FindHashCandidates(ctx context.Context, userID int64, bands []int32, limit int) ([]HashCandidate, error)
+	ReplacePhotoMatches(ctx context.Context, mediaID int64, matches []PhotoMatch) error
+	BanPhotoHash(ctx context.Context, mediaID int64, label string, actorTGID int64) (int64, error)
+}
+
+type VerificationInvalidator interface {
+	InvalidateVerification(ctx context.Context, userID int64) error
+}
+
 type ObjectStorage interface {
 	EnsureBucket(ctx context.Context) error
 	PutPhoto(ctx context.Context, key string, body io.Reader, size int64, contentType string) error
@@ -37,15 +49,18 @@ type ObjectStorage interface {
 }
 
 type Service struct {
-	store   Store
-	storage ObjectStorage
-	now     func() time.Time
+	store     Store
+	storage   ObjectStorage
+	hashIndex HashIndex
+	verifier  VerificationInvalidator
+	now       func() time.Time
 }
 
 type PhotoRecord struct {
 	ID        int64
 	Position  int
 	ObjectKey string
+	Hashes    ImageHashes
 	CreatedAt time.Time
 }
 
@@ -64,41 +79,74 @@ func NewService(store Store, storage ObjectStorage) *Service {
 	}
 }
 
-func (s *Service) UploadPhoto(ctx context.Context, userID int64, fileName, contentType string, body io.Reader, size int64) (Photo, error) {
+func (s *Service) AttachHashIndex(index HashIndex) {
+	s.hashIndex = index
+}
+
+func (s *Service) AttachVerificationInvalidator(verifier VerificationInvalidator) {
+	s.verifier = verifier
+}
+
+func (s *Service) UploadPhoto(ctx context.Context, userID int64, body io.Reader, size int64) (Photo, error) {
 	if userID <= 0 || body == nil || size <= 0 {
 		return Photo{}, ErrValidation
 	}
+	if size > maxImageBytes {
+		return Photo{}, ErrImageTooLarge
+	}
 	if s.store == nil || s.storage == nil {
 		return Photo{}, fmt.Errorf("media dependencies are not configured")
 	}
 
+	data, err := io.ReadAll(io.LimitReader(body, maxImageBytes+1))
+	if err != nil {
+		return Photo{}, fmt.Errorf("read photo body: %w", err)
+	}
+
+	processed, err := ProcessImage(data)
+	if err != nil {
+		return Photo{}, err
+	}
+
 	if err := s.storage.EnsureBucket(ctx); err != nil {
 		return Photo{}, fmt.Errorf("ensure bucket: %w", err)
 	}
 
-	objectKey, err := buildPhotoObjectKey(userID, fileName)
+	objectKey, err := buildPhotoObjectKey(userID)
 	if err != nil {
 		return Photo{}, fmt.Errorf("build object key: %w", err)
 	}
 
-	if strings.TrimSpace(contentType) == "" {
-		contentType = "application/octet-stream"
-	}
-
-	if err := s.storage.PutPhoto(ctx, objectKey, body, size, contentType); err != nil {
-		return Photo{}, fmt.Errorf("put object: %w", err)
+	storedKeys := make([]string, 0, len(processed.Variants))
+	for _, variant := range processed.Variants {
+		key := VariantKey(objectKey, variant.Variant)
+		if err := s.storage.PutPhoto(ctx, key, bytes.NewReader(variant.Data), int64(len(variant.Data)), "image/jpeg"); err != nil {
+			s.deleteObjects(ctx, storedKeys)
+			return Photo{}, fmt.Errorf("put %s variant: %w", variant.Variant, err)
+		}
+		storedKeys = append(storedKeys, key)
 	}
 
-	record, err := s.store.CreatePhoto(ctx, userID, objectKey)
+	record, err := s.store.CreatePhoto(ctx, userID, objectKey, processed.Hashes)
 	if err != nil {
-		_ = s.storage.Delete(ctx, objectKey)
+		s.deleteObjects(ctx, storedKeys)
 		if errors.Is(err, ErrPhotoLimitReached) {
 			return Photo{}, ErrPhotoLimitReached
 		}
 		return Photo{}, fmt.Errorf("create photo record: %w", err)
 	}
 
-	url, err := s.storage.PresignGet(ctx, record.ObjectKey, signedURLTTL)
+	if _, err := s.flagPhotoMatches(ctx, userID, record); err != nil {
+		log.Printf("warning: photo hash matching failed for media %d: %v", record.ID, err)
+	}
+	// Only a new primary photo changes what the circle was matched against.
+	if s.verifier != nil && record.Position == primaryPosition {
+		if err := s.verifier.InvalidateVerification(ctx, userID); err != nil {
+			log.Printf("warning: verification reset failed for user %d: %v", userID, err)
+		}
+	}
+
+	url, err := s.storage.PresignGet(ctx, VariantKey(record.ObjectKey, VariantFull), signedURLTTL)
 	if err != nil {
 		return Photo{}, fmt.Errorf("presign photo url: %w", err)
 	}
@@ -112,6 +160,10 @@ func (s *Service) UploadPhoto(ctx context.Context, userID int64, fileName, conte
 }
 
 func (s *Service) ListPhotos(ctx context.Context, userID int64) ([]Photo, error) {
+	return s.ListPhotoVariants(ctx, userID, VariantFull)
+}
+
+func (s *Service) ListPhotoVariants(ctx context.Context, userID int64, variant Variant) ([]Photo, error) {
 	if userID <= 0 {
 		return nil, ErrValidation
 	}
@@ -126,7 +178,7 @@ func (s *Service) ListPhotos(ctx context.Context, userID int64) ([]Photo, error)
 
 	photos := make([]Photo, 0, len(records))
 	for _, rec := range records {
-		url, err := s.storage.PresignGet(ctx, rec.ObjectKey, signedURLTTL)
+		url, err := s.storage.PresignGet(ctx, VariantKey(rec.ObjectKey, variant), signedURLTTL)
 		if err != nil {
 			return nil, fmt.Errorf("presign photo url: %w", err)
 		}
@@ -141,19 +193,76 @@ func (s *Service) ListPhotos(ctx context.Context, userID int64) ([]Photo, error)
 	return photos, nil
 }
 
-func buildPhotoObjectKey(userID int64, fileName string) (string, error) {
+// FindPhotoMatches re-checks every active photo of the user against other users' photos
+// and the banned-image list, refreshing the stored flags.
+func (s *Service) FindPhotoMatches(ctx context.Context, userID int64) ([]PhotoMatch, error) {
+	if userID <= 0 {
+		return nil, ErrValidation
+	}
+	if s.store == nil {
+		return nil, fmt.Errorf("media dependencies are not configured")
+	}
+	if s.hashIndex == nil {
+		return []PhotoMatch{}, nil
+	}
+
+	records, err := s.store.ListActivePhotos(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("list media records: %w", err)
+	}
+
+	matches := make([]PhotoMatch, 0)
+	for _, record := range records {
+		recordMatches, err := s.flagPhotoMatches(ctx, userID, record)
+		if err != nil {
+			return nil, err
+		}
+		matches = append(matches, recordMatches...)
+	}
+	return matches, nil
+}
+
+func (s *Service) BanPhotoHash(ctx context.Context, mediaID int64, label string, actorTGID int64) (int64, error) {
+	if mediaID <= 0 {
+		return 0, ErrValidation
+	}
+	if s.hashIndex == nil {
+		return 0, fmt.Errorf("media hash index is not configured")
+	}
+	return s.hashIndex.BanPhotoHash(ctx, mediaID, strings.TrimSpace(label), actorTGID)
+}
+
+func (s *Service) flagPhotoMatches(ctx context.Context, userID int64, record PhotoRecord) ([]PhotoMatch, error) {
+	if s.hashIndex == nil || record.Hashes.IsZero() {
+		return []PhotoMatch{}, nil
+	}
+
+	candidates, err := s.hashIndex.FindHashCandidates(ctx, userID, HashBands(record.Hashes.PHash), hashCandidateLimit)
+	if err != nil {
+		return nil, fmt.Errorf("find hash candidates: %w", err)
+	}
+
+	matches := matchCandidates(record, candidates)
+	if err := s.hashIndex.ReplacePhotoMatches(ctx, record.ID, matches); err != nil {
+		return nil, fmt.Errorf("store photo matches: %w", err)
+	}
+	return matches, nil
+}
+
+func (s *Service) deleteObjects(ctx context.Context, keys []string) {
+	for _, key := range keys {
+		_ = s.storage.Delete(ctx, key)
+	}
+}
+
+func buildPhotoObjectKey(userID int64) (string, error) {
 	rnd := make([]byte, 8)
 	if _, err := rand.Read(rnd); err != nil {
 		return "", err
 	}
 
-	ext := strings.ToLower(path.Ext(strings.TrimSpace(fileName)))
-	if ext == "" {
-		ext = ".bin"
-	}
-
 	stamp := time.Now().UTC().Format("20060102T150405")
-	return fmt.Sprintf("users/%d/photos/%s_%s%s", userID, stamp, hex.EncodeToString(rnd), ext), nil
+	return fmt.Sprintf("users/%d/photos/%s_%s_%s%s", userID, stamp, hex.EncodeToString(rnd), VariantFull, variantKeyExt), nil
 }
 
 func MaxActivePhotos() int {
@@ -1,6 +1,7 @@
 package media
 
 import (
+	"bytes"
 	"context"
 	"errors"
 	"io"
@@ -14,7 +15,7 @@ type fakeStore struct {
 	nextID  int64
 }
 
-func (f *fakeStore) CreatePhoto(_ context.Context, _ int64, objectKey string) (PhotoRecord, error) {
+func (f *fakeStore) CreatePhoto(_ context.Context, _ int64, objectKey string, hashes ImageHashes) (PhotoRecord, error) {
 	if len(f.records) >= MaxActivePhotos() {
 		return PhotoRecord{}, ErrPhotoLimitReached
 	}
@@ -24,6 +25,7 @@ func (f *fakeStore) CreatePhoto(_ context.Context, _ int64, objectKey string) (P
 		ID:        f.nextID,
 		Position:  len(f.records) + 1,
 		ObjectKey: objectKey,
+		Hashes:    hashes,
 		CreatedAt: time.Now().UTC(),
 	}
 	f.records = append(f.records, rec)
@@ -38,13 +40,15 @@ func (f *fakeStore) ListActivePhotos(_ context.Context, _ int64) ([]PhotoRecord,
 
 type fakeStorage struct {
 	deleteCalls int
+	putKeys     []string
 }
 
 func (f *fakeStorage) EnsureBucket(_ context.Context) error {
 	return nil
 }
 
-func (f *fakeStorage) PutPhoto(_ context.Context, _ string, _ io.Reader, _ int64, _ string) error {
+func (f *fakeStorage) PutPhoto(_ context.Context, key string, _ io.Reader, _ int64, _ string) error {
+	f.putKeys = append(f.putKeys, key)
 	return nil
 }
 
@@ -61,9 +65,10 @@ func TestUploadPhotoLimitThree(t *testing.T) {
 	store := &fakeStore{}
 	storage := &fakeStorage{}
 	svc := NewService(store, storage)
+	body := encodeTestJPEG(t, 640, 480)
 
 	for i := 1; i <= MaxActivePhotos(); i++ {
-		photo, err := svc.UploadPhoto(context.Background(), 1, "photo.jpg", "image/jpeg", strings.NewReader("abc"), 3)
+		photo, err := svc.UploadPhoto(context.Background(), 1, bytes.NewReader(body), int64(len(body)))
 		if err != nil {
 			t.Fatalf("upload photo #%d: %v", i, err)
 		}
@@ -72,11 +77,100 @@ func TestUploadPhotoLimitThree(t *testing.T) {
 		}
 	}
 
-	_, err := svc.UploadPhoto(context.Background(), 1, "photo4.jpg", "image/jpeg", strings.NewReader("abc"), 3)
+	_, err := svc.UploadPhoto(context.Background(), 1, bytes.NewReader(body), int64(len(body)))
 	if !errors.Is(err, ErrPhotoLimitReached) {
 		t.Fatalf("expected ErrPhotoLimitReached, got %v", err)
 	}
-	if storage.deleteCalls != 1 {
-		t.Fatalf("expected cleanup delete call after limit reached, got %d", storage.deleteCalls)
+	if storage.deleteCalls != len(photoVariants) {
+		t.Fatalf("expected cleanup delete of every variant after limit reached, got %d", storage.deleteCalls)
+	}
+}
+
+func TestUploadPhotoStoresVariants(t *testing.T) {
+	store := &fakeStore{}
+	storage := &fakeStorage{}
+	svc := NewService(store, storage)
+	body := encodeTestJPEG(t, 2000, 1000)
+
+	photo, err := svc.UploadPhoto(context.Background(), 7, bytes.NewReader(body), int64(len(body)))
+	if err != nil {
+		t.Fatalf("upload photo: %v", err)
+	}
+
+	fullKey := store.records[0].ObjectKey
+	if !strings.HasSuffix(fullKey, "_full.jpg") {
+		t.Fatalf("unexpected stored object key: %q", fullKey)
+	}
+	if photo.URL != "https://signed.local/"+fullKey {
+		t.Fatalf("unexpected photo url: %q", photo.URL)
+	}
+
+	expected := []string{VariantKey(fullKey, VariantThumb), VariantKey(fullKey, VariantCard), fullKey}
+	if len(storage.putKeys) != len(expected) {
+		t.Fatalf("unexpected put keys: %v", storage.putKeys)
+	}
+	for i, key := range expected {
+		if storage.putKeys[i] != key {
+			t.Fatalf("unexpected put key #%d: got %q want %q", i, storage.putKeys[i], key)
+		}
+	}
+
+	cards, err := svc.ListPhotoVariants(context.Background(), 7, VariantCard)
+	if err != nil {
+		t.Fatalf("list card variants: %v", err)
+	}
+	if len(cards) != 1 || !strings.HasSuffix(cards[0].URL, "_card.jpg") {
+		t.Fatalf("unexpected card variants: %+v", cards)
+	}
+}
+
+type fakeVerifier struct {
+	invalidated []int64
+}
+
+func (f *fakeVerifier) InvalidateVerification(_ context.Context, userID int64) error {
+	f.invalidated = append(f.invalidated, userID)
+	return nil
+}
+
+func TestUploadPhotoInvalidatesVerification(t *testing.T) {
+	verifier := &fakeVerifier{}
+	svc := NewService(&fakeStore{}, &fakeStorage{})
+	svc.AttachVerificationInvalidator(verifier)
+	body := encodeTestJPEG(t, 640, 480)
+
+	if _, err := svc.UploadPhoto(context.Background(), 9, bytes.NewReader(body), int64(len(body))); err != nil {
+		t.Fatalf("upload photo: %v", err)
+	}
+	if len(verifier.invalidated) != 1 || verifier.invalidated[0] != 9 {
+		t.Fatalf("expected verification reset for user 9, got %v", verifier.invalidated)
+	}
+
+	if _, err := svc.UploadPhoto(context.Background(), 9, bytes.NewReader(body), int64(len(body))); err != nil {
+		t.Fatalf("upload second photo: %v", err)
+	}
+	if len(verifier.invalidated) != 1 {
+		t.Fatalf("a non-primary photo must not reset verification, got %v", verifier.invalidated)
+	}
+
+	_, err := svc.UploadPhoto(context.Background(), 9, strings.NewReader("abc"), 3)
+	if !errors.Is(err, ErrUnsupportedImage) {
+		t.Fatalf("expected ErrUnsupportedImage, got %v", err)
+	}
+	if len(verifier.invalidated) != 1 {
+		t.Fatalf("rejected upload must not reset verification, got %v", verifier.invalidated)
+	}
+}
+
+func TestUploadPhotoRejectsInvalidImage(t *testing.T) {
+	storage := &fakeStorage{}
+	svc := NewService(&fakeStore{}, storage)
+
+	_, err := svc.UploadPhoto(context.Background(), 1, strings.NewReader("abc"), 3)
+	if !errors.Is(err, ErrUnsupportedImage) {
+		t.Fatalf("expected ErrUnsupportedImage, got %v", err)
+	}
+	if len(storage.putKeys) != 0 {
+		t.Fatalf("expected no stored objects, got %v", storage.putKeys)
 	}
 }
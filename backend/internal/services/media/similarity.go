@@ -0,0 +1,123 @@
+package media
+
+import (
+	"image"
+	"sort"
+
+	"golang.org/x/image/draw"
+)
+
+const (
+	MatchSourceUser   = "USER"
+	MatchSourceBanned = "BANNED"
+
+	// Eight 8-bit bands guarantee that any pair within 7 bits of pHash distance
+	// shares at least one band, so band overlap is a lossless candidate filter.
+	hashBandCount      = 8
+	hashBandBits       = 8
+	PHashMatchDistance = hashBandCount - 1
+	DHashMatchDistance = 12
+	hashCandidateLimit = 50
+)
+
+type ImageHashes struct {
+	PHash uint64
+	DHash uint64
+}
+
+func (h ImageHashes) IsZero() bool {
+	return h.PHash == 0 && h.DHash == 0
+}
+
+type HashCandidate struct {
+	Source        string
+	MediaID       int64
+	UserID        int64
+	BannedImageID int64
+	Label         string
+	Hashes        ImageHashes
+}
+
+type PhotoMatch struct {
+	MediaID        int64
+	Position       int
+	Source         string
+	MatchedMediaID int64
+	MatchedUserID  int64
+	BannedImageID  int64
+	Label          string
+	Distance       int
+}
+
+// DifferenceHash computes a 64-bit gradient hash over a 9x8 grayscale thumbnail.
+func DifferenceHash(img image.Image) uint64 {
+	small := image.NewGray(image.Rect(0, 0, 9, 8))
+	draw.CatmullRom.Scale(small, small.Bounds(), img, img.Bounds(), draw.Src, nil)
+
+	var hash uint64
+	bit := 0
+	for y := 0; y < 8; y++ {
+		for x := 0; x < 8; x++ {
+			if small.GrayAt(x, y).Y > small.GrayAt(x+1, y).Y {
+				hash |= 1 << uint(bit)
+			}
+			bit++
+		}
+	}
+	return hash
+}
+
+// HashBands splits a hash into position-tagged bands for the GIN overlap index.
+func HashBands(hash uint64) []int32 {
+	bands := make([]int32, 0, hashBandCount)
+	mask := uint64(1<<hashBandBits - 1)
+	for i := 0; i < hashBandCount; i++ {
+		value := (hash >> (uint(i) * hashBandBits)) & mask
+		bands = append(bands, int32(i<<hashBandBits)|int32(value))
+	}
+	return bands
+}
+
+// MatchDistance reports the pHash distance and whether both hashes agree closely enough
+// to treat the images as the same picture.
+func MatchDistance(a, b ImageHashes) (int, bool) {
+	distance := HammingDistance(a.PHash, b.PHash)
+	if distance > PHashMatchDistance {
+		return distance, false
+	}
+	if a.DHash != 0 && b.DHash != 0 && HammingDistance(a.DHash, b.DHash) > DHashMatchDistance {
+		return distance, false
+	}
+	return distance, true
+}
+
+func matchCandidates(record PhotoRecord, candidates []HashCandidate) []PhotoMatch {
+	matches := make([]PhotoMatch, 0)
+	for _, candidate := range candidates {
+		if candidate.Source == MatchSourceUser && candidate.MediaID == record.ID {
+			continue
+		}
+		distance, ok := MatchDistance(record.Hashes, candidate.Hashes)
+		if !ok {
+			continue
+		}
+		matches = append(matches, PhotoMatch{
+			MediaID:        record.ID,
+			Position:       record.Position,
+			Source:         candidate.Source,
+			MatchedMediaID: candidate.MediaID,
+			MatchedUserID:  candidate.UserID,
+			BannedImageID:  candidate.BannedImageID,
+			Label:          candidate.Label,
+			Distance:       distance,
+		})
+	}
+
+	sort.SliceStable(matches, func(i, j int) bool {
+		if matches[i].Distance != matches[j].Distance {
+			return matches[i].Distance < matches[j].Distance
+		}
+		return matches[i].Source == MatchSourceBanned && matches[j].Source != MatchSourceBanned
+	})
+	return matches
+}
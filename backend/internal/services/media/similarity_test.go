@@ -0,0 +1,124 @@
+package media
+
+import (
+	"bytes"
+	"context"
+	"testing"
+)
+
+type fakeHashIndex struct {
+	candidates []HashCandidate
+	lastBands  []int32
+	stored     map[int64][]PhotoMatch
+}
+
+func (f *fakeHashIndex) FindHashCandidates(_ context.Context, _ int64, bands []int32, _ int) ([]HashCandidate, error) {
+	f.lastBands = bands
+	overlapping := make([]HashCandidate, 0, len(f.candidates))
+	for _, candidate := range f.candidates {
+		if bandsOverlap(bands, HashBands(candidate.Hashes.PHash)) {
+			overlapping = append(overlapping, candidate)
+		}
+	}
+	return overlapping, nil
+}
+
+func (f *fakeHashIndex) ReplacePhotoMatches(_ context.Context, mediaID int64, matches []PhotoMatch) error {
+	if f.stored == nil {
+		f.stored = map[int64][]PhotoMatch{}
+	}
+	f.stored[mediaID] = matches
+	return nil
+}
+
+func (f *fakeHashIndex) BanPhotoHash(_ context.Context, _ int64, _ string, _ int64) (int64, error) {
+	return 1, nil
+}
+
+func bandsOverlap(a, b []int32) bool {
+	for _, left := range a {
+		for _, right := range b {
+			if left == right {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func TestHashBandsCatchNearDuplicates(t *testing.T) {
+	base := uint64(0xF0F0_1234_ABCD_0F0F)
+	for flips := 0; flips <= PHashMatchDistance; flips++ {
+		changed := base
+		for bit := 0; bit < flips; bit++ {
+			changed ^= 1 << uint(bit*9)
+		}
+		if !bandsOverlap(HashBands(base), HashBands(changed)) {
+			t.Fatalf("expected band overlap with %d flipped bits", flips)
+		}
+	}
+
+	if bandsOverlap(HashBands(0), HashBands(^uint64(0))) {
+		t.Fatal("expected no band overlap for inverted hash")
+	}
+}
+
+func TestMatchDistance(t *testing.T) {
+	a := ImageHashes{PHash: 0xAAAA_AAAA_AAAA_AAAA, DHash: 0x1111_1111_1111_1111}
+
+	if distance, ok := MatchDistance(a, ImageHashes{PHash: a.PHash ^ 0b111, DHash: a.DHash}); !ok || distance != 3 {
+		t.Fatalf("expected match at distance 3, got %d ok=%v", distance, ok)
+	}
+	if _, ok := MatchDistance(a, ImageHashes{PHash: a.PHash ^ 0xFF, DHash: a.DHash}); ok {
+		t.Fatal("expected pHash distance 8 to be rejected")
+	}
+	if _, ok := MatchDistance(a, ImageHashes{PHash: a.PHash, DHash: ^a.DHash}); ok {
+		t.Fatal("expected diverging dHash to veto the match")
+	}
+}
+
+func TestUploadPhotoFlagsReusedAndBannedImages(t *testing.T) {
+	body := encodeTestJPEG(t, 900, 700)
+	processed, err := ProcessImage(body)
+	if err != nil {
+		t.Fatalf("process image: %v", err)
+	}
+
+	index := &fakeHashIndex{
+		candidates: []HashCandidate{
+			{Source: MatchSourceUser, MediaID: 501, UserID: 42, Hashes: processed.Hashes},
+			{Source: MatchSourceBanned, BannedImageID: 9, Label: "stock model", Hashes: ImageHashes{PHash: processed.Hashes.PHash ^ 0b11}},
+			{Source: MatchSourceUser, MediaID: 502, UserID: 43, Hashes: ImageHashes{PHash: ^processed.Hashes.PHash}},
+		},
+	}
+	store := &fakeStore{}
+	svc := NewService(store, &fakeStorage{})
+	svc.AttachHashIndex(index)
+
+	photo, err := svc.UploadPhoto(context.Background(), 7, bytes.NewReader(body), int64(len(body)))
+	if err != nil {
+		t.Fatalf("upload photo: %v", err)
+	}
+	if store.records[0].Hashes != processed.Hashes {
+		t.Fatalf("expected hashes to be persisted, got %+v", store.records[0].Hashes)
+	}
+
+	stored := index.stored[photo.ID]
+	if len(stored) != 2 {
+		t.Fatalf("expected two flagged matches, got %+v", stored)
+	}
+	if stored[0].Source != MatchSourceUser || stored[0].MatchedUserID != 42 || stored[0].Distance != 0 {
+		t.Fatalf("unexpected first match: %+v", stored[0])
+	}
+	if stored[1].Source != MatchSourceBanned || stored[1].BannedImageID != 9 || stored[1].Distance != 2 {
+		t.Fatalf("unexpected second match: %+v", stored[1])
+	}
+
+	matches, err := svc.FindPhotoMatches(context.Background(), 7)
+	if err != nil {
+		t.Fatalf("find photo matches: %v", err)
+	}
+	if len(matches) != 2 || matches[0].Position != 1 {
+		t.Fatalf("unexpected queue-time matches: %+v", matches)
+	}
+}
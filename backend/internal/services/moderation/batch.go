@@ -0,0 +1,161 @@
+package moderation
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	pgrepo "github.com/ivankudzin/tgapp/backend/internal/repo/postgres"
+)
+
+var ErrInvalidBatch = errors.New("invalid moderation batch")
+
+type BatchDecision struct {
+	ItemID          int64
+	Decision        string
+	ReasonCode      string
+	ReasonText      string
+	RequiredFixStep string
+}
+
+// AcquireQueueBatch locks up to limit queue items for the actor and loads their cards.
+func (s *Service) AcquireQueueBatch(ctx context.Context, actorTGID int64, limit int) ([]QueueItem, error) {
+	if limit < 1 || limit > pgrepo.MaxModerationBatchSize {
+		return nil, fmt.Errorf("%w: size must be between 1 and %d", ErrInvalidBatch, pgrepo.MaxModerationBatchSize)
+	}
+	if s.moderationRepo == nil || s.profileRepo == nil || s.mediaRepo == nil {
+		return nil, fmt.Errorf("moderation service dependencies are not configured")
+	}
+	if actorTGID == 0 {
+		return nil, fmt.Errorf("invalid actor tg id")
+	}
+
+	items, err := s.moderationRepo.AcquirePendingBatch(ctx, actorTGID, limit, queueLockTTL)
+	if err != nil {
+		return nil, err
+	}
+	if len(items) == 0 {
+		return nil, ErrQueueEmpty
+	}
+
+	queueSize, err := s.moderationRepo.CountPending(ctx)
+	if err != nil {
+		return nil, err
+	}
+	etaBucket := s.estimateETABucket(ctx, queueSize)
+
+	out := make([]QueueItem, 0, len(items))
+	for _, item := range items {
+		queueItem, err := s.buildQueueItem(ctx, item, queueSize, etaBucket)
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, queueItem)
+	}
+	return out, nil
+}
+
+// DecideBatch commits all decisions at once; a single stale item rolls back the whole batch.
+// Rejects without a text fall back to the catalog template for their reason code.
+func (s *Service) DecideBatch(ctx context.Context, moderatorTGID int64, decisions []BatchDecision) ([]pgrepo.ModerationItemRecord, error) {
+	normalized, err := s.normalizeBatchDecisions(ctx, decisions)
+	if err != nil {
+		return nil, err
+	}
+	if s.moderationRepo == nil || s.profileRepo == nil || s.mediaRepo == nil {
+		return nil, fmt.Errorf("moderation service dependencies are not configured")
+	}
+
+	for _, decision := range normalized {
+		if decision.Decision != pgrepo.ModerationBatchApproveVerified {
+			continue
+		}
+		item, err := s.moderationRepo.GetByID(ctx, decision.ItemID)
+		if err != nil {
+			return nil, err
+		}
+		circle, err := s.mediaRepo.GetLatestCircle(ctx, item.UserID)
+		if err != nil {
+			return nil, err
+		}
+		if circle == nil {
+			return nil, fmt.Errorf("moderation item %d: %w", decision.ItemID, ErrCircleMissing)
+		}
+	}
+
+	queueSize, err := s.moderationRepo.CountPending(ctx)
+	if err != nil {
+		return nil, err
+	}
+	etaBucket := s.estimateETABucket(ctx, queueSize)
+
+	decided, err := s.moderationRepo.DecideBatch(ctx, moderatorTGID, normalized, etaBucket)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, item := range decided {
+		if !strings.EqualFold(item.Status, "APPROVED") {
+			continue
+		}
+		if s.dailyMetrics != nil {
+			if err := s.dailyMetrics.Increment(ctx, item.UserID, time.Now().UTC(), pgrepo.DailyMetricsDelta{Approved: 1}); err != nil {
+				log.Printf("warning: increment daily metrics failed for batch approve: %v", err)
+			}
+		}
+		s.sampleApprovalForQA(ctx, item, moderatorTGID)
+	}
+	return decided, nil
+}
+
+func (s *Service) normalizeBatchDecisions(ctx context.Context, decisions []BatchDecision) ([]pgrepo.ModerationBatchDecision, error) {
+	if len(decisions) == 0 || len(decisions) > pgrepo.MaxModerationBatchSize {
+		return nil, fmt.Errorf("%w: size must be between 1 and %d", ErrInvalidBatch, pgrepo.MaxModerationBatchSize)
+	}
+
+	seen := make(map[int64]struct{}, len(decisions))
+	out := make([]pgrepo.ModerationBatchDecision, 0, len(decisions))
+	for _, in := range decisions {
+		if in.ItemID <= 0 {
+			return nil, fmt.Errorf("%w: invalid moderation item id", ErrInvalidBatch)
+		}
+		if _, ok := seen[in.ItemID]; ok {
+			return nil, fmt.Errorf("%w: duplicate moderation item %d", ErrInvalidBatch, in.ItemID)
+		}
+		seen[in.ItemID] = struct{}{}
+
+		decision := pgrepo.ModerationBatchDecision{
+			ItemID:   in.ItemID,
+			Decision: strings.ToUpper(strings.TrimSpace(in.Decision)),
+		}
+		switch decision.Decision {
+		case pgrepo.ModerationBatchApprove:
+		case pgrepo.ModerationBatchApproveVerified:
+			decision.Verification = VerificationVerified
+		case pgrepo.ModerationBatchReject:
+			reason, err := s.LookupRejectReason(ctx, in.ReasonCode)
+			if err != nil {
+				return nil, err
+			}
+			decision.ReasonCode = reason.ReasonCode
+			decision.ReasonText = strings.TrimSpace(in.ReasonText)
+			decision.RequiredFixStep = strings.TrimSpace(in.RequiredFixStep)
+			if decision.ReasonText == "" {
+				decision.ReasonText = reason.ReasonText
+			}
+			if decision.RequiredFixStep == "" {
+				decision.RequiredFixStep = reason.RequiredFixStep
+			}
+			if IsCircleRejectReason(reason.ReasonCode) {
+				decision.Verification = VerificationRejected
+			}
+		default:
+			return nil, fmt.Errorf("%w: unknown decision %q", ErrInvalidBatch, in.Decision)
+		}
+		out = append(out, decision)
+	}
+	return out, nil
+}
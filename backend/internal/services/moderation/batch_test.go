@@ -0,0 +1,61 @@
+package moderation
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	pgrepo "github.com/ivankudzin/tgapp/backend/internal/repo/postgres"
+)
+
+func TestNormalizeBatchDecisions(t *testing.T) {
+	svc := NewService(nil, nil, nil, nil)
+	svc.AttachRejectReasons(testRejectReasons())
+	ctx := context.Background()
+
+	got, err := svc.normalizeBatchDecisions(ctx, []BatchDecision{
+		{ItemID: 1, Decision: "approve"},
+		{ItemID: 2, Decision: "APPROVE_VERIFIED"},
+		{ItemID: 3, Decision: "reject", ReasonCode: "circle_mismatch"},
+		{ItemID: 4, Decision: "REJECT", ReasonCode: "OTHER", ReasonText: "text", RequiredFixStep: "step"},
+	})
+	if err != nil {
+		t.Fatalf("normalize batch: %v", err)
+	}
+	if got[0].Verification != "" || got[1].Verification != VerificationVerified {
+		t.Fatalf("unexpected approve verification: %+v", got[:2])
+	}
+	if got[2].ReasonCode != "CIRCLE_MISMATCH" || got[2].ReasonText == "" || got[2].Verification != VerificationRejected {
+		t.Fatalf("expected templated circle reject, got %+v", got[2])
+	}
+	if got[3].ReasonText != "text" || got[3].RequiredFixStep != "step" || got[3].Verification != "" {
+		t.Fatalf("unexpected custom reject: %+v", got[3])
+	}
+}
+
+func TestNormalizeBatchDecisionsRejectsInvalidBatches(t *testing.T) {
+	svc := NewService(nil, nil, nil, nil)
+	svc.AttachRejectReasons(testRejectReasons())
+	ctx := context.Background()
+
+	oversized := make([]BatchDecision, pgrepo.MaxModerationBatchSize+1)
+	for i := range oversized {
+		oversized[i] = BatchDecision{ItemID: int64(i + 1), Decision: "APPROVE"}
+	}
+	cases := [][]BatchDecision{
+		nil,
+		oversized,
+		{{ItemID: 1, Decision: "APPROVE"}, {ItemID: 1, Decision: "REJECT", ReasonCode: "OTHER"}},
+		{{ItemID: 1, Decision: "SKIP"}},
+		{{ItemID: 0, Decision: "APPROVE"}},
+	}
+	for _, decisions := range cases {
+		if _, err := svc.normalizeBatchDecisions(ctx, decisions); !errors.Is(err, ErrInvalidBatch) {
+			t.Fatalf("expected ErrInvalidBatch for %+v, got %v", decisions, err)
+		}
+	}
+
+	if _, err := svc.normalizeBatchDecisions(ctx, []BatchDecision{{ItemID: 1, Decision: "REJECT", ReasonCode: "NOPE"}}); !errors.Is(err, ErrInvalidReasonCode) {
+		t.Fatalf("expected ErrInvalidReasonCode, got %v", err)
+	}
+}
@@ -1,100 +1,243 @@
 package moderation
 
 import (
+	"context"
+	"errors"
+	"fmt"
+	"regexp"
 	"sort"
 	"strings"
+	"sync"
+	"time"
+
+	pgrepo "github.com/ivankudzin/tgapp/backend/internal/repo/postgres"
+)
+
+var ErrInvalidRejectReason = errors.New("invalid reject reason")
+var ErrRejectReasonsReadOnly = errors.New("reject reason catalog is read-only")
+
+const (
+	RejectReasonSeverityLow    = "LOW"
+	RejectReasonSeverityMedium = "MEDIUM"
+	RejectReasonSeverityHigh   = "HIGH"
+
+	DefaultRejectReasonLanguage = "ru"
+
+	rejectReasonCacheTTL = 30 * time.Second
 )
 
+var rejectReasonCodePattern = regexp.MustCompile(`^[A-Z0-9_]{2,32}$`)
+
+type RejectReasonStore interface {
+	ListRejectReasons(ctx context.Context, includeInactive bool) ([]pgrepo.RejectReasonRecord, error)
+	UpsertRejectReason(ctx context.Context, in pgrepo.RejectReasonRecord) (pgrepo.RejectReasonRecord, error)
+}
+
 type RejectReasonItem struct {
 	ReasonCode      string
 	Label           string
+	Labels          map[string]string
 	ReasonText      string
 	RequiredFixStep string
+	Severity        string
+	IsActive        bool
+	SortOrder       int
+	UpdatedByTGID   *int64
+	UpdatedAt       time.Time
 }
 
-type rejectReasonTemplate struct {
-	Label           string
-	ReasonText      string
-	RequiredFixStep string
+type rejectReasonCatalog struct {
+	mu       sync.Mutex
+	store    RejectReasonStore
+	items    []RejectReasonItem
+	loadedAt time.Time
+}
+
+func (s *Service) AttachRejectReasons(store RejectReasonStore) {
+	s.rejectReasons.mu.Lock()
+	defer s.rejectReasons.mu.Unlock()
+	s.rejectReasons.store = store
+	s.rejectReasons.items = nil
+	s.rejectReasons.loadedAt = time.Time{}
+}
+
+// RejectWithTemplate rejects using the canned reason and fix step for the given code.
+func (s *Service) RejectWithTemplate(ctx context.Context, itemID int64, moderatorTGID int64, reasonCode string) error {
+	reason, err := s.LookupRejectReason(ctx, reasonCode)
+	if err != nil {
+		return err
+	}
+	return s.Reject(ctx, itemID, moderatorTGID, reason.ReasonCode, reason.ReasonText, reason.RequiredFixStep)
+}
+
+func (s *Service) ListRejectReasons(ctx context.Context, includeInactive bool) ([]RejectReasonItem, error) {
+	all, err := s.loadRejectReasons(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	items := make([]RejectReasonItem, 0, len(all))
+	for _, item := range all {
+		if !includeInactive && !item.IsActive {
+			continue
+		}
+		items = append(items, item)
+	}
+	return items, nil
+}
+
+// LookupRejectReason returns an active catalog entry; unknown and disabled codes are ErrInvalidReasonCode.
+func (s *Service) LookupRejectReason(ctx context.Context, reasonCode string) (RejectReasonItem, error) {
+	normalized := strings.ToUpper(strings.TrimSpace(reasonCode))
+	items, err := s.loadRejectReasons(ctx)
+	if err != nil {
+		return RejectReasonItem{}, err
+	}
+	for _, item := range items {
+		if item.ReasonCode == normalized && item.IsActive {
+			return item, nil
+		}
+	}
+	return RejectReasonItem{}, ErrInvalidReasonCode
+}
+
+func (s *Service) UpsertRejectReason(ctx context.Context, actorTGID int64, in RejectReasonItem) (RejectReasonItem, error) {
+	record, err := normalizeRejectReason(in)
+	if err != nil {
+		return RejectReasonItem{}, err
+	}
+	if actorTGID != 0 {
+		record.UpdatedByTGID = &actorTGID
+	}
+
+	s.rejectReasons.mu.Lock()
+	defer s.rejectReasons.mu.Unlock()
+	if s.rejectReasons.store == nil {
+		return RejectReasonItem{}, ErrRejectReasonsReadOnly
+	}
+
+	saved, err := s.rejectReasons.store.UpsertRejectReason(ctx, record)
+	if err != nil {
+		return RejectReasonItem{}, err
+	}
+	s.rejectReasons.items = nil
+	s.rejectReasons.loadedAt = time.Time{}
+	return rejectReasonItemFromRecord(saved), nil
 }
 
-// Templates are copied 1:1 from tgbots/bot_moderator rejectTemplates (MVP sync).
-var rejectReasonTemplates = map[string]rejectReasonTemplate{
-	"PHOTO_NO_FACE": {
-		Label:           "Фото: не видно лица",
-		ReasonText:      "На фото не видно лица.",
-		RequiredFixStep: "Загрузите фото, где лицо хорошо различимо.",
-	},
-	"PHOTO_FAKE_NOT_YOU": {
-		Label:           "Фото: не вы",
-		ReasonText:      "Фото не соответствует владельцу анкеты.",
-		RequiredFixStep: "Загрузите ваши реальные фотографии без чужих изображений.",
-	},
-	"PHOTO_PROHIBITED": {
-		Label:           "Фото: запрещенный контент",
-		ReasonText:      "Обнаружен запрещенный фото-контент.",
-		RequiredFixStep: "Удалите запрещённый контент и загрузите новые фото.",
-	},
-	"CIRCLE_MISMATCH": {
-		Label:           "Кружок: не совпадает",
-		ReasonText:      "Кружок не совпадает с фотографиями анкеты.",
-		RequiredFixStep: "Перезапишите кружок, чтобы внешность совпадала с фото.",
-	},
-	"CIRCLE_FAILED": {
-		Label:           "Кружок: не прошел проверку",
-		ReasonText:      "Кружок не прошел проверку качества.",
-		RequiredFixStep: "Перезапишите кружок при хорошем освещении и стабильной связи.",
-	},
-	"PROFILE_INCOMPLETE": {
-		Label:           "Профиль: не заполнен",
-		ReasonText:      "Профиль заполнен не полностью.",
-		RequiredFixStep: "Заполните обязательные поля профиля и отправьте на модерацию повторно.",
-	},
-	"SPAM_ADS_LINKS": {
-		Label:           "Спам/реклама/ссылки",
-		ReasonText:      "Обнаружены признаки спама, рекламы или внешних ссылок.",
-		RequiredFixStep: "Удалите спам/рекламу/ссылки из профиля и отправьте на модерацию повторно.",
-	},
-	"BOT_SUSPECT": {
-		Label:           "Подозрение на бота",
-		ReasonText:      "Профиль помечен как подозрительный на автоматизацию.",
-		RequiredFixStep: "Обновите анкету и пройдите повторную модерацию вручную.",
-	},
-	"OTHER": {
-		Label:           "Другое",
-		ReasonText:      "Требуется корректировка анкеты.",
-		RequiredFixStep: "Обновите анкету по замечанию модератора и отправьте на модерацию повторно.",
-	},
+// loadRejectReasons serves a short-lived cache so other API instances pick up edits without a restart;
+// a failed refresh keeps serving the last good snapshot.
+func (s *Service) loadRejectReasons(ctx context.Context) ([]RejectReasonItem, error) {
+	s.rejectReasons.mu.Lock()
+	defer s.rejectReasons.mu.Unlock()
+
+	// The catalog lives in reject_reasons only; without a store every code is unknown.
+	if s.rejectReasons.store == nil {
+		return []RejectReasonItem{}, nil
+	}
+
+	if s.rejectReasons.items != nil && time.Since(s.rejectReasons.loadedAt) < rejectReasonCacheTTL {
+		return s.rejectReasons.items, nil
+	}
+
+	records, err := s.rejectReasons.store.ListRejectReasons(ctx, true)
+	if err != nil {
+		if s.rejectReasons.items != nil {
+			return s.rejectReasons.items, nil
+		}
+		return nil, err
+	}
+
+	items := make([]RejectReasonItem, 0, len(records))
+	for _, record := range records {
+		items = append(items, rejectReasonItemFromRecord(record))
+	}
+	sort.SliceStable(items, func(i, j int) bool {
+		if items[i].SortOrder != items[j].SortOrder {
+			return items[i].SortOrder < items[j].SortOrder
+		}
+		return items[i].ReasonCode < items[j].ReasonCode
+	})
+
+	s.rejectReasons.items = items
+	s.rejectReasons.loadedAt = time.Now()
+	return items, nil
 }
 
-func (s *Service) ListRejectReasons() []RejectReasonItem {
-	codes := make([]string, 0, len(allowedRejectReasonCodes))
-	for code := range allowedRejectReasonCodes {
-		codes = append(codes, code)
-	}
-	sort.Strings(codes)
-
-	items := make([]RejectReasonItem, 0, len(codes))
-	for _, code := range codes {
-		template, ok := rejectReasonTemplates[code]
-		if !ok {
-			items = append(items, RejectReasonItem{
-				ReasonCode:      code,
-				Label:           defaultRejectReasonLabel(code),
-				ReasonText:      "",
-				RequiredFixStep: "",
-			})
+func normalizeRejectReason(in RejectReasonItem) (pgrepo.RejectReasonRecord, error) {
+	code := strings.ToUpper(strings.TrimSpace(in.ReasonCode))
+	if !rejectReasonCodePattern.MatchString(code) {
+		return pgrepo.RejectReasonRecord{}, fmt.Errorf("%w: code must match [A-Z0-9_]{2,32}", ErrInvalidRejectReason)
+	}
+
+	labels := make(map[string]string, len(in.Labels)+1)
+	for lang, label := range in.Labels {
+		lang = strings.ToLower(strings.TrimSpace(lang))
+		label = strings.TrimSpace(label)
+		if lang == "" || label == "" {
 			continue
 		}
-		items = append(items, RejectReasonItem{
-			ReasonCode:      code,
-			Label:           strings.TrimSpace(template.Label),
-			ReasonText:      strings.TrimSpace(template.ReasonText),
-			RequiredFixStep: strings.TrimSpace(template.RequiredFixStep),
-		})
+		labels[lang] = label
+	}
+	if label := strings.TrimSpace(in.Label); label != "" {
+		if _, ok := labels[DefaultRejectReasonLanguage]; !ok {
+			labels[DefaultRejectReasonLanguage] = label
+		}
+	}
+	if labels[DefaultRejectReasonLanguage] == "" {
+		return pgrepo.RejectReasonRecord{}, fmt.Errorf("%w: %s label is required", ErrInvalidRejectReason, DefaultRejectReasonLanguage)
+	}
+
+	reasonText := strings.TrimSpace(in.ReasonText)
+	requiredFixStep := strings.TrimSpace(in.RequiredFixStep)
+	if reasonText == "" || requiredFixStep == "" {
+		return pgrepo.RejectReasonRecord{}, fmt.Errorf("%w: reason_text and required_fix_step are required", ErrInvalidRejectReason)
+	}
+
+	severity := strings.ToUpper(strings.TrimSpace(in.Severity))
+	switch severity {
+	case "":
+		severity = RejectReasonSeverityMedium
+	case RejectReasonSeverityLow, RejectReasonSeverityMedium, RejectReasonSeverityHigh:
+	default:
+		return pgrepo.RejectReasonRecord{}, fmt.Errorf("%w: severity must be LOW, MEDIUM or HIGH", ErrInvalidRejectReason)
+	}
+
+	return pgrepo.RejectReasonRecord{
+		Code:            code,
+		Labels:          labels,
+		ReasonText:      reasonText,
+		RequiredFixStep: requiredFixStep,
+		Severity:        severity,
+		IsActive:        in.IsActive,
+		SortOrder:       in.SortOrder,
+	}, nil
+}
+
+func rejectReasonItemFromRecord(record pgrepo.RejectReasonRecord) RejectReasonItem {
+	code := strings.ToUpper(strings.TrimSpace(record.Code))
+	return RejectReasonItem{
+		ReasonCode:      code,
+		Label:           rejectReasonLabel(code, record.Labels),
+		Labels:          record.Labels,
+		ReasonText:      strings.TrimSpace(record.ReasonText),
+		RequiredFixStep: strings.TrimSpace(record.RequiredFixStep),
+		Severity:        strings.ToUpper(strings.TrimSpace(record.Severity)),
+		IsActive:        record.IsActive,
+		SortOrder:       record.SortOrder,
+		UpdatedByTGID:   record.UpdatedByTGID,
+		UpdatedAt:       record.UpdatedAt,
 	}
+}
 
-	return items
+func rejectReasonLabel(code string, labels map[string]string) string {
+	if label := strings.TrimSpace(labels[DefaultRejectReasonLanguage]); label != "" {
+		return label
+	}
+	if label := strings.TrimSpace(labels["en"]); label != "" {
+		return label
+	}
+	return defaultRejectReasonLabel(code)
 }
 
 func defaultRejectReasonLabel(reasonCode string) string {
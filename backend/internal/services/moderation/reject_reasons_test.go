@@ -1,36 +1,116 @@
 package moderation
 
-import "testing"
+import (
+	"context"
+	"errors"
+	"testing"
 
-func TestListRejectReasonsCoversAllowedCodes(t *testing.T) {
+	pgrepo "github.com/ivankudzin/tgapp/backend/internal/repo/postgres"
+)
+
+func TestRejectReasonCatalogIsEmptyWithoutStore(t *testing.T) {
 	svc := NewService(nil, nil, nil, nil)
-	items := svc.ListRejectReasons()
+	ctx := context.Background()
 
-	if len(items) != len(allowedRejectReasonCodes) {
-		t.Fatalf("unexpected reject reasons count: got=%d want=%d", len(items), len(allowedRejectReasonCodes))
+	items, err := svc.ListRejectReasons(ctx, true)
+	if err != nil {
+		t.Fatalf("list reject reasons: %v", err)
+	}
+	if items == nil || len(items) != 0 {
+		t.Fatalf("expected an empty catalog without a store, got %+v", items)
 	}
+	if _, err := svc.LookupRejectReason(ctx, "OTHER"); !errors.Is(err, ErrInvalidReasonCode) {
+		t.Fatalf("expected ErrInvalidReasonCode without a store, got %v", err)
+	}
+}
 
-	byCode := make(map[string]RejectReasonItem, len(items))
-	for _, item := range items {
-		if _, exists := byCode[item.ReasonCode]; exists {
-			t.Fatalf("duplicate reason code: %s", item.ReasonCode)
-		}
-		byCode[item.ReasonCode] = item
+func TestRejectReasonCatalogHidesInactiveAndReloadsAfterUpsert(t *testing.T) {
+	store := &rejectReasonStoreStub{records: []pgrepo.RejectReasonRecord{
+		{Code: "OTHER", Labels: map[string]string{"ru": "Другое"}, ReasonText: "text", RequiredFixStep: "step", Severity: "MEDIUM", IsActive: true, SortOrder: 100},
+		{Code: "LEGACY", Labels: map[string]string{"en": "Legacy"}, ReasonText: "text", RequiredFixStep: "step", Severity: "LOW", IsActive: false, SortOrder: 1},
+	}}
+	svc := NewService(nil, nil, nil, nil)
+	svc.AttachRejectReasons(store)
+	ctx := context.Background()
+
+	active, err := svc.ListRejectReasons(ctx, false)
+	if err != nil {
+		t.Fatalf("list reject reasons: %v", err)
+	}
+	if len(active) != 1 || active[0].ReasonCode != "OTHER" {
+		t.Fatalf("unexpected active reasons: %+v", active)
+	}
+	all, _ := svc.ListRejectReasons(ctx, true)
+	if len(all) != 2 || all[0].ReasonCode != "LEGACY" || all[0].Label != "Legacy" {
+		t.Fatalf("unexpected full catalog: %+v", all)
+	}
+	if _, err := svc.LookupRejectReason(ctx, "legacy"); !errors.Is(err, ErrInvalidReasonCode) {
+		t.Fatalf("expected inactive reason to be rejected, got %v", err)
 	}
 
-	for code := range allowedRejectReasonCodes {
-		item, ok := byCode[code]
-		if !ok {
-			t.Fatalf("missing reason code: %s", code)
-		}
-		if item.Label == "" {
-			t.Fatalf("empty label for reason code: %s", code)
-		}
-		if item.ReasonText == "" {
-			t.Fatalf("empty reason_text for reason code: %s", code)
+	if _, err := svc.UpsertRejectReason(ctx, 700, RejectReasonItem{
+		ReasonCode:      "legacy",
+		Label:           "Старое",
+		ReasonText:      "text",
+		RequiredFixStep: "step",
+		IsActive:        true,
+	}); err != nil {
+		t.Fatalf("upsert reject reason: %v", err)
+	}
+	if store.saved.UpdatedByTGID == nil || *store.saved.UpdatedByTGID != 700 || store.saved.Severity != RejectReasonSeverityMedium {
+		t.Fatalf("unexpected saved record: %+v", store.saved)
+	}
+	if _, err := svc.LookupRejectReason(ctx, "LEGACY"); err != nil {
+		t.Fatalf("expected reactivated reason after upsert, got %v", err)
+	}
+}
+
+func TestUpsertRejectReasonValidates(t *testing.T) {
+	svc := NewService(nil, nil, nil, nil)
+	ctx := context.Background()
+
+	if _, err := svc.UpsertRejectReason(ctx, 1, RejectReasonItem{ReasonCode: "bad code", Label: "x", ReasonText: "t", RequiredFixStep: "s"}); !errors.Is(err, ErrInvalidRejectReason) {
+		t.Fatalf("expected ErrInvalidRejectReason for bad code, got %v", err)
+	}
+	if _, err := svc.UpsertRejectReason(ctx, 1, RejectReasonItem{ReasonCode: "OK_CODE", Label: "x", ReasonText: "t", RequiredFixStep: "s", Severity: "CRITICAL"}); !errors.Is(err, ErrInvalidRejectReason) {
+		t.Fatalf("expected ErrInvalidRejectReason for bad severity, got %v", err)
+	}
+	if _, err := svc.UpsertRejectReason(ctx, 1, RejectReasonItem{ReasonCode: "OK_CODE", Label: "x", ReasonText: "t", RequiredFixStep: "s"}); !errors.Is(err, ErrRejectReasonsReadOnly) {
+		t.Fatalf("expected ErrRejectReasonsReadOnly without store, got %v", err)
+	}
+}
+
+// testRejectReasons is a small catalog for tests that reject with templated reasons.
+func testRejectReasons() *rejectReasonStoreStub {
+	return &rejectReasonStoreStub{records: []pgrepo.RejectReasonRecord{
+		{Code: "CIRCLE_MISMATCH", Labels: map[string]string{"ru": "Кружок: не совпадает"}, ReasonText: "Кружок не совпадает с фотографиями анкеты.", RequiredFixStep: "Перезапишите кружок.", Severity: "MEDIUM", IsActive: true, SortOrder: 40},
+		{Code: "OTHER", Labels: map[string]string{"ru": "Другое"}, ReasonText: "Требуется корректировка анкеты.", RequiredFixStep: "Обновите анкету.", Severity: "MEDIUM", IsActive: true, SortOrder: 1000},
+	}}
+}
+
+type rejectReasonStoreStub struct {
+	records []pgrepo.RejectReasonRecord
+	saved   pgrepo.RejectReasonRecord
+}
+
+func (s *rejectReasonStoreStub) ListRejectReasons(_ context.Context, includeInactive bool) ([]pgrepo.RejectReasonRecord, error) {
+	items := make([]pgrepo.RejectReasonRecord, 0, len(s.records))
+	for _, record := range s.records {
+		if includeInactive || record.IsActive {
+			items = append(items, record)
 		}
-		if item.RequiredFixStep == "" {
-			t.Fatalf("empty required_fix_step for reason code: %s", code)
+	}
+	return items, nil
+}
+
+func (s *rejectReasonStoreStub) UpsertRejectReason(_ context.Context, in pgrepo.RejectReasonRecord) (pgrepo.RejectReasonRecord, error) {
+	s.saved = in
+	for i, record := range s.records {
+		if record.Code == in.Code {
+			s.records[i] = in
+			return in, nil
 		}
 	}
+	s.records = append(s.records, in)
+	return in, nil
 }
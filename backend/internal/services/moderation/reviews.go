@@ -0,0 +1,341 @@
+package moderation
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"math/rand"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	pgrepo "github.com/ivankudzin/tgapp/backend/internal/repo/postgres"
+)
+
+const (
+	ReviewKindAppeal = "APPEAL"
+	ReviewKindQA     = "QA"
+
+	ReviewStatusPending    = "PENDING"
+	ReviewStatusUpheld     = "UPHELD"
+	ReviewStatusOverturned = "OVERTURNED"
+
+	ReviewOutcomeUphold   = "UPHOLD"
+	ReviewOutcomeOverturn = "OVERTURN"
+)
+
+const (
+	maxAppealMessageRunes = 500
+	qaSampleRate          = 0.05
+	reviewLockTTL         = 10 * time.Minute
+)
+
+var ErrAppealNotAllowed = errors.New("only rejected moderation decisions can be appealed")
+var ErrAppealExists = errors.New("moderation decision has already been appealed")
+var ErrInvalidAppealMessage = errors.New("invalid appeal message")
+var ErrReviewQueueEmpty = errors.New("review queue is empty")
+var ErrInvalidReviewKind = errors.New("invalid review kind")
+var ErrInvalidReviewOutcome = errors.New("invalid review outcome")
+var ErrSameReviewer = errors.New("second review must be done by a different moderator")
+
+type ReviewStore interface {
+	Create(ctx context.Context, in pgrepo.ModerationReviewCreate) (pgrepo.ModerationReviewRecord, error)
+	GetByID(ctx context.Context, reviewID int64) (pgrepo.ModerationReviewRecord, error)
+	GetForItem(ctx context.Context, itemID int64, kind string) (pgrepo.ModerationReviewRecord, error)
+	AcquireNextPending(ctx context.Context, kind string, reviewerTGID int64, lockDuration time.Duration) (pgrepo.ModerationReviewRecord, error)
+	Resolve(ctx context.Context, in pgrepo.ModerationReviewResolution) (pgrepo.ModerationReviewRecord, error)
+	Stats(ctx context.Context, since time.Time) (pgrepo.ModerationReviewStats, error)
+}
+
+type ReviewQueueItem struct {
+	Review          pgrepo.ModerationReviewRecord
+	ReasonText      *string
+	RequiredFixStep *string
+	Profile         pgrepo.ProfileQueueSummary
+	PhotoURLs       []string
+	CircleURL       *string
+}
+
+type ResolveReviewInput struct {
+	ReviewID     int64
+	ReviewerTGID int64
+	Outcome      string
+	ReasonCode   string
+	Note         string
+}
+
+type ReviewKindStats struct {
+	Kind          string
+	Pending       int
+	Decided       int
+	Upheld        int
+	Overturned    int
+	AgreementRate float64
+}
+
+type ReviewModeratorStats struct {
+	ModeratorTGID int64
+	Reviewed      int
+	Upheld        int
+	Overturned    int
+	AgreementRate float64
+}
+
+type ReviewStats struct {
+	Window     time.Duration
+	Kinds      []ReviewKindStats
+	Moderators []ReviewModeratorStats
+}
+
+func (s *Service) AttachReviews(store ReviewStore) {
+	s.reviews = store
+}
+
+// SubmitAppeal queues the user's latest rejection for a second review.
+func (s *Service) SubmitAppeal(ctx context.Context, userID int64, message string) (pgrepo.ModerationReviewRecord, error) {
+	if userID <= 0 {
+		return pgrepo.ModerationReviewRecord{}, fmt.Errorf("invalid user id")
+	}
+	message = strings.TrimSpace(message)
+	if message == "" || utf8.RuneCountInString(message) > maxAppealMessageRunes {
+		return pgrepo.ModerationReviewRecord{}, ErrInvalidAppealMessage
+	}
+	if s.moderationRepo == nil || s.reviews == nil {
+		return pgrepo.ModerationReviewRecord{}, fmt.Errorf("moderation service dependencies are not configured")
+	}
+
+	item, err := s.moderationRepo.GetLatestByUser(ctx, userID)
+	if err != nil {
+		if errors.Is(err, pgrepo.ErrModerationItemNotFound) {
+			return pgrepo.ModerationReviewRecord{}, ErrAppealNotAllowed
+		}
+		return pgrepo.ModerationReviewRecord{}, err
+	}
+	if strings.ToUpper(strings.TrimSpace(item.Status)) != "REJECTED" {
+		return pgrepo.ModerationReviewRecord{}, ErrAppealNotAllowed
+	}
+
+	review, err := s.reviews.Create(ctx, pgrepo.ModerationReviewCreate{
+		ModerationItemID:      item.ID,
+		UserID:                userID,
+		Kind:                  ReviewKindAppeal,
+		OriginalDecision:      "REJECTED",
+		OriginalReasonCode:    item.ReasonCode,
+		OriginalModeratorTGID: item.ModeratorTGID,
+		AppealMessage:         &message,
+	})
+	if errors.Is(err, pgrepo.ErrModerationReviewExists) {
+		return pgrepo.ModerationReviewRecord{}, ErrAppealExists
+	}
+	if err != nil {
+		return pgrepo.ModerationReviewRecord{}, err
+	}
+
+	return review, nil
+}
+
+func (s *Service) GetNextReview(ctx context.Context, kind string, reviewerTGID int64) (ReviewQueueItem, error) {
+	kind, ok := normalizeReviewKind(kind)
+	if !ok {
+		return ReviewQueueItem{}, ErrInvalidReviewKind
+	}
+	if reviewerTGID == 0 {
+		return ReviewQueueItem{}, fmt.Errorf("invalid reviewer tg id")
+	}
+	if s.moderationRepo == nil || s.profileRepo == nil || s.mediaRepo == nil || s.reviews == nil {
+		return ReviewQueueItem{}, fmt.Errorf("moderation service dependencies are not configured")
+	}
+
+	review, err := s.reviews.AcquireNextPending(ctx, kind, reviewerTGID, reviewLockTTL)
+	if err != nil {
+		if errors.Is(err, pgrepo.ErrModerationReviewNotFound) {
+			return ReviewQueueItem{}, ErrReviewQueueEmpty
+		}
+		return ReviewQueueItem{}, err
+	}
+
+	item, err := s.moderationRepo.GetByID(ctx, review.ModerationItemID)
+	if err != nil {
+		return ReviewQueueItem{}, err
+	}
+
+	profile, photoURLs, circleURL, err := s.loadProfileMedia(ctx, review.UserID)
+	if err != nil {
+		return ReviewQueueItem{}, err
+	}
+
+	return ReviewQueueItem{
+		Review:          review,
+		ReasonText:      item.ReasonText,
+		RequiredFixStep: item.RequiredFixStep,
+		Profile:         profile,
+		PhotoURLs:       photoURLs,
+		CircleURL:       circleURL,
+	}, nil
+}
+
+// GetReview loads a review so callers can authorize against its stored kind.
+func (s *Service) GetReview(ctx context.Context, reviewID int64) (pgrepo.ModerationReviewRecord, error) {
+	if reviewID <= 0 {
+		return pgrepo.ModerationReviewRecord{}, fmt.Errorf("invalid review id")
+	}
+	if s.reviews == nil {
+		return pgrepo.ModerationReviewRecord{}, fmt.Errorf("moderation service dependencies are not configured")
+	}
+	return s.reviews.GetByID(ctx, reviewID)
+}
+
+// ResolveReview records the second opinion. Overturning an appeal approves the profile;
+// overturning a QA sample rejects it with the given reason code. The store applies the review
+// and the override atomically.
+func (s *Service) ResolveReview(ctx context.Context, in ResolveReviewInput) (pgrepo.ModerationReviewRecord, error) {
+	if in.ReviewID <= 0 {
+		return pgrepo.ModerationReviewRecord{}, fmt.Errorf("invalid review id")
+	}
+	if in.ReviewerTGID == 0 {
+		return pgrepo.ModerationReviewRecord{}, fmt.Errorf("invalid reviewer tg id")
+	}
+	outcome := strings.ToUpper(strings.TrimSpace(in.Outcome))
+	if outcome != ReviewOutcomeUphold && outcome != ReviewOutcomeOverturn {
+		return pgrepo.ModerationReviewRecord{}, ErrInvalidReviewOutcome
+	}
+	if s.reviews == nil {
+		return pgrepo.ModerationReviewRecord{}, fmt.Errorf("moderation service dependencies are not configured")
+	}
+
+	review, err := s.reviews.GetByID(ctx, in.ReviewID)
+	if err != nil {
+		return pgrepo.ModerationReviewRecord{}, err
+	}
+	if review.OriginalModeratorTGID != nil && *review.OriginalModeratorTGID == in.ReviewerTGID {
+		return pgrepo.ModerationReviewRecord{}, ErrSameReviewer
+	}
+
+	reasonCode := strings.ToUpper(strings.TrimSpace(in.ReasonCode))
+	var template RejectReasonItem
+	if outcome == ReviewOutcomeOverturn && review.Kind == ReviewKindQA {
+		template, err = s.LookupRejectReason(ctx, reasonCode)
+		if err != nil {
+			return pgrepo.ModerationReviewRecord{}, err
+		}
+	}
+
+	resolution := pgrepo.ModerationReviewResolution{
+		ReviewID:     review.ID,
+		ReviewerTGID: in.ReviewerTGID,
+		Status:       ReviewStatusUpheld,
+		Note:         in.Note,
+	}
+	if outcome == ReviewOutcomeOverturn {
+		resolution.Status = ReviewStatusOverturned
+		switch review.Kind {
+		case ReviewKindAppeal:
+			resolution.Override = &pgrepo.ModerationReviewOverride{Status: "APPROVED", Approved: true}
+		case ReviewKindQA:
+			resolution.Override = &pgrepo.ModerationReviewOverride{
+				Status:             "REJECTED",
+				ReasonCode:         reasonCode,
+				ReasonText:         template.ReasonText,
+				RequiredFixStep:    template.RequiredFixStep,
+				RejectVerification: IsCircleRejectReason(reasonCode),
+			}
+		}
+	}
+
+	return s.reviews.Resolve(ctx, resolution)
+}
+
+func (s *Service) GetReviewStats(ctx context.Context, window time.Duration) (ReviewStats, error) {
+	if s.reviews == nil {
+		return ReviewStats{}, fmt.Errorf("moderation service dependencies are not configured")
+	}
+	if window <= 0 {
+		window = defaultSLAWindow
+	}
+	if window > maxSLAWindow {
+		window = maxSLAWindow
+	}
+
+	stats, err := s.reviews.Stats(ctx, time.Now().UTC().Add(-window))
+	if err != nil {
+		return ReviewStats{}, err
+	}
+
+	out := ReviewStats{
+		Window:     window,
+		Kinds:      make([]ReviewKindStats, 0, len(stats.Kinds)),
+		Moderators: make([]ReviewModeratorStats, 0, len(stats.Moderators)),
+	}
+	for _, item := range stats.Kinds {
+		out.Kinds = append(out.Kinds, ReviewKindStats{
+			Kind:          item.Kind,
+			Pending:       item.Pending,
+			Decided:       item.Decided,
+			Upheld:        item.Upheld,
+			Overturned:    item.Overturned,
+			AgreementRate: ratio(item.Upheld, item.Decided),
+		})
+	}
+	for _, item := range stats.Moderators {
+		out.Moderators = append(out.Moderators, ReviewModeratorStats{
+			ModeratorTGID: item.ModeratorTGID,
+			Reviewed:      item.Reviewed,
+			Upheld:        item.Upheld,
+			Overturned:    item.Overturned,
+			AgreementRate: ratio(item.Upheld, item.Reviewed),
+		})
+	}
+
+	return out, nil
+}
+
+func (s *Service) sampleApprovalForQA(ctx context.Context, item pgrepo.ModerationItemRecord, moderatorTGID int64) {
+	if s.reviews == nil || s.qaSample == nil || !s.qaSample() {
+		return
+	}
+
+	var moderator *int64
+	if moderatorTGID != 0 {
+		moderator = &moderatorTGID
+	}
+	_, err := s.reviews.Create(ctx, pgrepo.ModerationReviewCreate{
+		ModerationItemID:      item.ID,
+		UserID:                item.UserID,
+		Kind:                  ReviewKindQA,
+		OriginalDecision:      "APPROVED",
+		OriginalModeratorTGID: moderator,
+	})
+	if err != nil && !errors.Is(err, pgrepo.ErrModerationReviewExists) {
+		log.Printf("warning: enqueue qa review failed for moderation item %d: %v", item.ID, err)
+	}
+}
+
+func (s *Service) appealStatus(ctx context.Context, itemID int64, status string) string {
+	if s.reviews == nil || (status != "REJECTED" && status != "APPROVED") {
+		return ""
+	}
+
+	review, err := s.reviews.GetForItem(ctx, itemID, ReviewKindAppeal)
+	if err != nil {
+		if !errors.Is(err, pgrepo.ErrModerationReviewNotFound) {
+			log.Printf("warning: appeal lookup failed for moderation item %d: %v", itemID, err)
+		}
+		return ""
+	}
+	return review.Status
+}
+
+func normalizeReviewKind(raw string) (string, bool) {
+	kind := strings.ToUpper(strings.TrimSpace(raw))
+	switch kind {
+	case ReviewKindAppeal, ReviewKindQA:
+		return kind, true
+	default:
+		return "", false
+	}
+}
+
+func defaultQASample() bool {
+	return rand.Float64() < qaSampleRate
+}
@@ -0,0 +1,162 @@
+package moderation
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	pgrepo "github.com/ivankudzin/tgapp/backend/internal/repo/postgres"
+)
+
+type fakeReviewStore struct {
+	created    []pgrepo.ModerationReviewCreate
+	review     pgrepo.ModerationReviewRecord
+	resolved   int
+	resolution pgrepo.ModerationReviewResolution
+}
+
+func (f *fakeReviewStore) Create(_ context.Context, in pgrepo.ModerationReviewCreate) (pgrepo.ModerationReviewRecord, error) {
+	f.created = append(f.created, in)
+	return pgrepo.ModerationReviewRecord{ID: int64(len(f.created)), Kind: in.Kind, Status: ReviewStatusPending}, nil
+}
+
+func (f *fakeReviewStore) GetByID(_ context.Context, reviewID int64) (pgrepo.ModerationReviewRecord, error) {
+	if f.review.ID != reviewID {
+		return pgrepo.ModerationReviewRecord{}, pgrepo.ErrModerationReviewNotFound
+	}
+	return f.review, nil
+}
+
+func (f *fakeReviewStore) GetForItem(context.Context, int64, string) (pgrepo.ModerationReviewRecord, error) {
+	return pgrepo.ModerationReviewRecord{}, pgrepo.ErrModerationReviewNotFound
+}
+
+func (f *fakeReviewStore) AcquireNextPending(context.Context, string, int64, time.Duration) (pgrepo.ModerationReviewRecord, error) {
+	return pgrepo.ModerationReviewRecord{}, pgrepo.ErrModerationReviewNotFound
+}
+
+func (f *fakeReviewStore) Resolve(_ context.Context, in pgrepo.ModerationReviewResolution) (pgrepo.ModerationReviewRecord, error) {
+	f.resolved++
+	f.resolution = in
+	out := f.review
+	out.Status = in.Status
+	out.ReviewerTGID = &in.ReviewerTGID
+	return out, nil
+}
+
+func (f *fakeReviewStore) Stats(context.Context, time.Time) (pgrepo.ModerationReviewStats, error) {
+	return pgrepo.ModerationReviewStats{
+		Kinds: []pgrepo.ModerationReviewKindStats{{Kind: ReviewKindQA, Decided: 4, Upheld: 3, Overturned: 1}},
+	}, nil
+}
+
+func TestSubmitAppealValidatesMessage(t *testing.T) {
+	svc := NewService(nil, nil, nil, nil)
+	svc.AttachReviews(&fakeReviewStore{})
+
+	for _, message := range []string{"", "   ", strings.Repeat("я", maxAppealMessageRunes+1)} {
+		if _, err := svc.SubmitAppeal(context.Background(), 1, message); !errors.Is(err, ErrInvalidAppealMessage) {
+			t.Fatalf("expected ErrInvalidAppealMessage for %d runes, got %v", len([]rune(message)), err)
+		}
+	}
+}
+
+func TestResolveReviewRequiresDifferentModerator(t *testing.T) {
+	original := int64(500)
+	store := &fakeReviewStore{review: pgrepo.ModerationReviewRecord{
+		ID:                    7,
+		Kind:                  ReviewKindAppeal,
+		Status:                ReviewStatusPending,
+		OriginalModeratorTGID: &original,
+	}}
+	svc := NewService(nil, nil, nil, nil)
+	svc.AttachReviews(store)
+
+	_, err := svc.ResolveReview(context.Background(), ResolveReviewInput{ReviewID: 7, ReviewerTGID: 500, Outcome: ReviewOutcomeOverturn})
+	if !errors.Is(err, ErrSameReviewer) {
+		t.Fatalf("expected ErrSameReviewer, got %v", err)
+	}
+
+	resolved, err := svc.ResolveReview(context.Background(), ResolveReviewInput{ReviewID: 7, ReviewerTGID: 501, Outcome: ReviewOutcomeUphold})
+	if err != nil {
+		t.Fatalf("resolve review: %v", err)
+	}
+	if resolved.Status != ReviewStatusUpheld || store.resolved != 1 {
+		t.Fatalf("unexpected resolve result: %+v (calls=%d)", resolved, store.resolved)
+	}
+}
+
+func TestResolveAppealOverturnApprovesProfile(t *testing.T) {
+	store := &fakeReviewStore{review: pgrepo.ModerationReviewRecord{ID: 9, Kind: ReviewKindAppeal, Status: ReviewStatusPending}}
+	svc := NewService(nil, nil, nil, nil)
+	svc.AttachReviews(store)
+
+	resolved, err := svc.ResolveReview(context.Background(), ResolveReviewInput{ReviewID: 9, ReviewerTGID: 2, Outcome: ReviewOutcomeOverturn})
+	if err != nil {
+		t.Fatalf("resolve review: %v", err)
+	}
+	if resolved.Status != ReviewStatusOverturned {
+		t.Fatalf("unexpected status: %s", resolved.Status)
+	}
+	override := store.resolution.Override
+	if override == nil || override.Status != "APPROVED" || !override.Approved || override.RejectVerification {
+		t.Fatalf("unexpected override: %+v", override)
+	}
+}
+
+func TestResolveQAOverturnRequiresReasonCode(t *testing.T) {
+	store := &fakeReviewStore{review: pgrepo.ModerationReviewRecord{ID: 3, Kind: ReviewKindQA, Status: ReviewStatusPending}}
+	svc := NewService(nil, nil, nil, nil)
+	svc.AttachReviews(store)
+
+	_, err := svc.ResolveReview(context.Background(), ResolveReviewInput{ReviewID: 3, ReviewerTGID: 1, Outcome: ReviewOutcomeOverturn, ReasonCode: "NOPE"})
+	if !errors.Is(err, ErrInvalidReasonCode) {
+		t.Fatalf("expected ErrInvalidReasonCode, got %v", err)
+	}
+	if store.resolved != 0 {
+		t.Fatalf("invalid overturn must not resolve the review")
+	}
+
+	_, err = svc.ResolveReview(context.Background(), ResolveReviewInput{ReviewID: 3, ReviewerTGID: 1, Outcome: "MAYBE"})
+	if !errors.Is(err, ErrInvalidReviewOutcome) {
+		t.Fatalf("expected ErrInvalidReviewOutcome, got %v", err)
+	}
+}
+
+func TestSampleApprovalForQA(t *testing.T) {
+	store := &fakeReviewStore{}
+	svc := NewService(nil, nil, nil, nil)
+	svc.AttachReviews(store)
+	svc.qaSample = func() bool { return true }
+
+	svc.sampleApprovalForQA(context.Background(), pgrepo.ModerationItemRecord{ID: 11, UserID: 22}, 33)
+
+	if len(store.created) != 1 {
+		t.Fatalf("expected one qa review, got %d", len(store.created))
+	}
+	created := store.created[0]
+	if created.Kind != ReviewKindQA || created.ModerationItemID != 11 || created.OriginalModeratorTGID == nil || *created.OriginalModeratorTGID != 33 {
+		t.Fatalf("unexpected qa review payload: %+v", created)
+	}
+
+	svc.qaSample = func() bool { return false }
+	svc.sampleApprovalForQA(context.Background(), pgrepo.ModerationItemRecord{ID: 12, UserID: 22}, 33)
+	if len(store.created) != 1 {
+		t.Fatalf("unsampled approval must not create a qa review")
+	}
+}
+
+func TestGetReviewStatsAgreementRate(t *testing.T) {
+	svc := NewService(nil, nil, nil, nil)
+	svc.AttachReviews(&fakeReviewStore{})
+
+	stats, err := svc.GetReviewStats(context.Background(), 0)
+	if err != nil {
+		t.Fatalf("get review stats: %v", err)
+	}
+	if len(stats.Kinds) != 1 || stats.Kinds[0].AgreementRate != 0.75 {
+		t.Fatalf("unexpected review stats: %+v", stats)
+	}
+}
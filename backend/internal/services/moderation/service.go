@@ -9,6 +9,7 @@ import (
 	"time"
 
 	pgrepo "github.com/ivankudzin/tgapp/backend/internal/repo/postgres"
+	mediasvc "github.com/ivankudzin/tgapp/backend/internal/services/media"
 )
 
 const signedURLTTL = 5 * time.Minute
@@ -16,17 +17,20 @@ const queueLockTTL = 10 * time.Minute
 
 var ErrQueueEmpty = errors.New("moderation queue is empty")
 var ErrInvalidReasonCode = errors.New("invalid reject reason code")
+var ErrCircleMissing = errors.New("verification circle is missing")
+var ErrInvalidRejectPayload = errors.New("reason_text and required_fix_step are required")
+
+const (
+	VerificationNone             = "NONE"
+	VerificationPending          = "PENDING"
+	VerificationVerified         = "VERIFIED"
+	VerificationRejected         = "REJECTED"
+	VerificationReverifyRequired = "REVERIFY_REQUIRED"
+)
 
-var allowedRejectReasonCodes = map[string]struct{}{
-	"PHOTO_NO_FACE":      {},
-	"PHOTO_FAKE_NOT_YOU": {},
-	"PHOTO_PROHIBITED":   {},
-	"CIRCLE_MISMATCH":    {},
-	"CIRCLE_FAILED":      {},
-	"PROFILE_INCOMPLETE": {},
-	"SPAM_ADS_LINKS":     {},
-	"BOT_SUSPECT":        {},
-	"OTHER":              {},
+var circleRejectReasonCodes = map[string]struct{}{
+	"CIRCLE_MISMATCH": {},
+	"CIRCLE_FAILED":   {},
 }
 
 type URLSigner interface {
@@ -37,12 +41,22 @@ type DailyMetricsStore interface {
 	Increment(ctx context.Context, userID int64, at time.Time, delta pgrepo.DailyMetricsDelta) error
 }
 
+type PhotoMatcher interface {
+	FindPhotoMatches(ctx context.Context, userID int64) ([]mediasvc.PhotoMatch, error)
+	BanPhotoHash(ctx context.Context, mediaID int64, label string, actorTGID int64) (int64, error)
+}
+
 type Service struct {
 	moderationRepo *pgrepo.ModerationRepo
 	profileRepo    *pgrepo.ProfileRepo
 	mediaRepo      *pgrepo.MediaRepo
 	signer         URLSigner
 	dailyMetrics   DailyMetricsStore
+	photoMatcher   PhotoMatcher
+	reviews        ReviewStore
+	qaSample       func() bool
+	rejectReasons  rejectReasonCatalog
+	workload       WorkloadStore
 }
 
 type UserStatus struct {
@@ -50,6 +64,7 @@ type UserStatus struct {
 	ReasonText      *string
 	RequiredFixStep *string
 	ETABucket       string
+	AppealStatus    string
 }
 
 type QueueItem struct {
@@ -64,6 +79,7 @@ type QueueItem struct {
 	Profile      pgrepo.ProfileQueueSummary
 	PhotoURLs    []string
 	CircleURL    *string
+	PhotoMatches []mediasvc.PhotoMatch
 	CreatedAt    time.Time
 }
 
@@ -73,6 +89,7 @@ func NewService(moderationRepo *pgrepo.ModerationRepo, profileRepo *pgrepo.Profi
 		profileRepo:    profileRepo,
 		mediaRepo:      mediaRepo,
 		signer:         signer,
+		qaSample:       defaultQASample,
 	}
 }
 
@@ -80,6 +97,10 @@ func (s *Service) AttachDailyMetrics(store DailyMetricsStore) {
 	s.dailyMetrics = store
 }
 
+func (s *Service) AttachPhotoMatcher(matcher PhotoMatcher) {
+	s.photoMatcher = matcher
+}
+
 func (s *Service) GetUserStatus(ctx context.Context, userID int64) (UserStatus, error) {
 	if userID <= 0 {
 		return UserStatus{}, fmt.Errorf("invalid user id")
@@ -108,7 +129,7 @@ func (s *Service) GetUserStatus(ctx context.Context, userID int64) (UserStatus,
 		if countErr != nil {
 			return UserStatus{}, countErr
 		}
-		eta := ETABucketFromQueueSize(pendingCount)
+		eta := s.estimateETABucket(ctx, pendingCount)
 
 		return UserStatus{Status: status, ETABucket: eta}, nil
 	}
@@ -124,7 +145,7 @@ func (s *Service) GetUserStatus(ctx context.Context, userID int64) (UserStatus,
 		if countErr != nil {
 			return UserStatus{}, countErr
 		}
-		eta = ETABucketFromQueueSize(pendingCount)
+		eta = s.estimateETABucket(ctx, pendingCount)
 		_ = s.moderationRepo.UpdateETABucket(ctx, item.ID, eta)
 	}
 
@@ -137,6 +158,7 @@ func (s *Service) GetUserStatus(ctx context.Context, userID int64) (UserStatus,
 		ReasonText:      item.ReasonText,
 		RequiredFixStep: item.RequiredFixStep,
 		ETABucket:       eta,
+		AppealStatus:    s.appealStatus(ctx, item.ID, status),
 	}, nil
 }
 
@@ -161,39 +183,76 @@ func (s *Service) GetNextQueueItem(ctx context.Context, actorTGID int64) (QueueI
 		return QueueItem{}, err
 	}
 
-	etaBucket := ETABucketFromQueueSize(queueSize)
-	_ = s.moderationRepo.UpdateETABucket(ctx, item.ID, etaBucket)
+	etaBucket := s.estimateETABucket(ctx, queueSize)
+	return s.buildQueueItem(ctx, item, queueSize, etaBucket)
+}
 
-	profile, err := s.profileRepo.GetQueueSummary(ctx, item.UserID)
+// GetQueueItem loads one item with signed media for review without taking its lock.
+func (s *Service) GetQueueItem(ctx context.Context, itemID int64) (QueueItem, error) {
+	if s.moderationRepo == nil || s.profileRepo == nil || s.mediaRepo == nil {
+		return QueueItem{}, fmt.Errorf("moderation service dependencies are not configured")
+	}
+
+	item, err := s.moderationRepo.GetForReview(ctx, itemID)
 	if err != nil {
 		return QueueItem{}, err
 	}
 
-	photos, err := s.mediaRepo.ListUserPhotos(ctx, item.UserID, 3)
+	queueSize, err := s.moderationRepo.CountPending(ctx)
 	if err != nil {
 		return QueueItem{}, err
 	}
 
-	photoURLs := make([]string, 0, len(photos))
-	for _, photo := range photos {
-		url, signErr := s.signKey(ctx, photo.ObjectKey)
-		if signErr != nil {
-			return QueueItem{}, signErr
-		}
-		photoURLs = append(photoURLs, url)
+	etaBucket := item.ETABucket
+	if strings.EqualFold(item.Status, "PENDING") {
+		etaBucket = s.estimateETABucket(ctx, queueSize)
+	}
+	return s.buildQueueItem(ctx, item, queueSize, etaBucket)
+}
+
+type QueueStats struct {
+	PendingCount     int
+	LockedCount      int
+	HeldByActorCount int
+	OldestPendingAt  *time.Time
+	ETABucket        string
+}
+
+func (s *Service) GetQueueStats(ctx context.Context, actorTGID int64) (QueueStats, error) {
+	if s.moderationRepo == nil {
+		return QueueStats{}, fmt.Errorf("moderation service dependencies are not configured")
 	}
 
-	var circleURL *string
-	circle, err := s.mediaRepo.GetLatestCircle(ctx, item.UserID)
+	stats, err := s.moderationRepo.GetQueueStats(ctx, actorTGID)
+	if err != nil {
+		return QueueStats{}, err
+	}
+
+	return QueueStats{
+		PendingCount:     stats.PendingCount,
+		LockedCount:      stats.LockedCount,
+		HeldByActorCount: stats.HeldByActorCount,
+		OldestPendingAt:  stats.OldestPendingAt,
+		ETABucket:        s.estimateETABucket(ctx, stats.PendingCount),
+	}, nil
+}
+
+func (s *Service) buildQueueItem(ctx context.Context, item pgrepo.ModerationItemRecord, queueSize int, etaBucket string) (QueueItem, error) {
+	_ = s.moderationRepo.UpdateETABucket(ctx, item.ID, etaBucket)
+
+	profile, photoURLs, circleURL, err := s.loadProfileMedia(ctx, item.UserID)
 	if err != nil {
 		return QueueItem{}, err
 	}
-	if circle != nil {
-		url, signErr := s.signKey(ctx, circle.ObjectKey)
-		if signErr != nil {
-			return QueueItem{}, signErr
+
+	photoMatches := []mediasvc.PhotoMatch{}
+	if s.photoMatcher != nil {
+		matches, matchErr := s.photoMatcher.FindPhotoMatches(ctx, item.UserID)
+		if matchErr != nil {
+			log.Printf("warning: photo match lookup failed for moderation item %d: %v", item.ID, matchErr)
+		} else {
+			photoMatches = matches
 		}
-		circleURL = &url
 	}
 
 	return QueueItem{
@@ -208,11 +267,66 @@ func (s *Service) GetNextQueueItem(ctx context.Context, actorTGID int64) (QueueI
 		Profile:      profile,
 		PhotoURLs:    photoURLs,
 		CircleURL:    circleURL,
+		PhotoMatches: photoMatches,
 		CreatedAt:    item.CreatedAt,
 	}, nil
 }
 
+func (s *Service) loadProfileMedia(ctx context.Context, userID int64) (pgrepo.ProfileQueueSummary, []string, *string, error) {
+	profile, err := s.profileRepo.GetQueueSummary(ctx, userID)
+	if err != nil {
+		return pgrepo.ProfileQueueSummary{}, nil, nil, err
+	}
+
+	photos, err := s.mediaRepo.ListUserPhotos(ctx, userID, 3)
+	if err != nil {
+		return pgrepo.ProfileQueueSummary{}, nil, nil, err
+	}
+
+	photoURLs := make([]string, 0, len(photos))
+	for _, photo := range photos {
+		url, signErr := s.signKey(ctx, photo.ObjectKey)
+		if signErr != nil {
+			return pgrepo.ProfileQueueSummary{}, nil, nil, signErr
+		}
+		photoURLs = append(photoURLs, url)
+	}
+
+	var circleURL *string
+	circle, err := s.mediaRepo.GetLatestCircle(ctx, userID)
+	if err != nil {
+		return pgrepo.ProfileQueueSummary{}, nil, nil, err
+	}
+	if circle != nil {
+		url, signErr := s.signKey(ctx, circle.ObjectKey)
+		if signErr != nil {
+			return pgrepo.ProfileQueueSummary{}, nil, nil, signErr
+		}
+		circleURL = &url
+	}
+
+	return profile, photoURLs, circleURL, nil
+}
+
+// Moderator identifies who decides an item. TGID holds the queue lock, so bot and web
+// moderators share AcquireNextPending; AdminUserID attributes web decisions to the admin user.
+type Moderator struct {
+	TGID        int64
+	AdminUserID int64
+	Role        string
+}
+
 func (s *Service) Approve(ctx context.Context, itemID int64, moderatorTGID int64) error {
+	return s.ApproveBy(ctx, itemID, Moderator{TGID: moderatorTGID}, false)
+}
+
+// ApproveAsVerified approves the profile and grants the verified badge after the moderator
+// has matched the circle against the profile photos.
+func (s *Service) ApproveAsVerified(ctx context.Context, itemID int64, moderatorTGID int64) error {
+	return s.ApproveBy(ctx, itemID, Moderator{TGID: moderatorTGID}, true)
+}
+
+func (s *Service) ApproveBy(ctx context.Context, itemID int64, moderator Moderator, verified bool) error {
 	if itemID <= 0 {
 		return fmt.Errorf("invalid moderation item id")
 	}
@@ -224,39 +338,62 @@ func (s *Service) Approve(ctx context.Context, itemID int64, moderatorTGID int64
 	if err != nil {
 		return err
 	}
+	if verified {
+		if s.mediaRepo == nil {
+			return fmt.Errorf("moderation service dependencies are not configured")
+		}
+		circle, err := s.mediaRepo.GetLatestCircle(ctx, item.UserID)
+		if err != nil {
+			return err
+		}
+		if circle == nil {
+			return ErrCircleMissing
+		}
+	}
 
 	queueSize, err := s.moderationRepo.CountPending(ctx)
 	if err != nil {
 		return err
 	}
-	etaBucket := ETABucketFromQueueSize(queueSize)
+	etaBucket := s.estimateETABucket(ctx, queueSize)
 
-	if err := s.moderationRepo.MarkApproved(ctx, itemID, moderatorTGID, etaBucket); err != nil {
+	if err := s.moderationRepo.MarkApproved(ctx, itemID, moderator.TGID, moderator.AdminUserID, etaBucket); err != nil {
 		return err
 	}
 
 	if err := s.profileRepo.ApplyModerationDecision(ctx, item.UserID, "APPROVED", true); err != nil {
 		return err
 	}
+	if verified {
+		if err := s.profileRepo.SetVerificationStatus(ctx, item.UserID, VerificationVerified); err != nil {
+			return err
+		}
+	}
 	if s.dailyMetrics != nil {
 		if err := s.dailyMetrics.Increment(ctx, item.UserID, time.Now().UTC(), pgrepo.DailyMetricsDelta{Approved: 1}); err != nil {
 			log.Printf("warning: increment daily metrics failed for moderation approve: %v", err)
 		}
 	}
+	s.sampleApprovalForQA(ctx, item, moderator.TGID)
+	s.recordWebAction(ctx, item, moderator, "APPROVE", "")
 
 	return nil
 }
 
 func (s *Service) Reject(ctx context.Context, itemID int64, moderatorTGID int64, reasonCode, reasonText, requiredFixStep string) error {
+	return s.RejectBy(ctx, itemID, Moderator{TGID: moderatorTGID}, reasonCode, reasonText, requiredFixStep)
+}
+
+func (s *Service) RejectBy(ctx context.Context, itemID int64, moderator Moderator, reasonCode, reasonText, requiredFixStep string) error {
 	if itemID <= 0 {
 		return fmt.Errorf("invalid moderation item id")
 	}
 	if strings.TrimSpace(reasonText) == "" || strings.TrimSpace(requiredFixStep) == "" {
-		return fmt.Errorf("reason_text and required_fix_step are required")
+		return ErrInvalidRejectPayload
 	}
 	normalizedReasonCode := strings.ToUpper(strings.TrimSpace(reasonCode))
-	if _, ok := allowedRejectReasonCodes[normalizedReasonCode]; !ok {
-		return ErrInvalidReasonCode
+	if _, err := s.LookupRejectReason(ctx, normalizedReasonCode); err != nil {
+		return err
 	}
 	if s.moderationRepo == nil || s.profileRepo == nil {
 		return fmt.Errorf("moderation service dependencies are not configured")
@@ -271,36 +408,98 @@ func (s *Service) Reject(ctx context.Context, itemID int64, moderatorTGID int64,
 	if err != nil {
 		return err
 	}
-	etaBucket := ETABucketFromQueueSize(queueSize)
+	etaBucket := s.estimateETABucket(ctx, queueSize)
 
-	if err := s.moderationRepo.MarkRejected(ctx, itemID, moderatorTGID, normalizedReasonCode, reasonText, requiredFixStep, etaBucket); err != nil {
+	if err := s.moderationRepo.MarkRejected(ctx, itemID, moderator.TGID, moderator.AdminUserID, normalizedReasonCode, reasonText, requiredFixStep, etaBucket); err != nil {
 		return err
 	}
 
 	if err := s.profileRepo.ApplyModerationDecision(ctx, item.UserID, "REJECTED", false); err != nil {
 		return err
 	}
+	if IsCircleRejectReason(normalizedReasonCode) {
+		if err := s.profileRepo.SetVerificationStatus(ctx, item.UserID, VerificationRejected); err != nil {
+			return err
+		}
+	} else if err := s.profileRepo.RestorePendingVerification(ctx, item.UserID); err != nil {
+		return err
+	}
+	s.recordWebAction(ctx, item, moderator, "REJECT", normalizedReasonCode)
 
 	return nil
 }
 
+// recordWebAction logs a web panel decision to bot_moderation_actions. Bot decisions are
+// recorded by the bot itself, so only decisions carrying an admin user id are written here.
+func (s *Service) recordWebAction(ctx context.Context, item pgrepo.ModerationItemRecord, moderator Moderator, decision, reasonCode string) {
+	if moderator.AdminUserID <= 0 {
+		return
+	}
+
+	now := time.Now().UTC()
+	var durationSec *int
+	if item.LockedAt != nil && !item.LockedAt.IsZero() {
+		seconds := int(now.Sub(*item.LockedAt).Seconds())
+		if seconds < 0 {
+			seconds = 0
+		}
+		durationSec = &seconds
+	}
+	err := s.moderationRepo.InsertModerationAction(ctx, pgrepo.ModerationActionRecord{
+		ActorTGID:        moderator.TGID,
+		ActorRole:        moderator.Role,
+		TargetUserID:     item.UserID,
+		ModerationItemID: item.ID,
+		Decision:         decision,
+		ReasonCode:       reasonCode,
+		DurationSec:      durationSec,
+		CreatedAt:        now,
+	})
+	if err != nil {
+		log.Printf("warning: record web moderation action failed for moderation item %d: %v", item.ID, err)
+	}
+}
+
+func (s *Service) BanPhotoHash(ctx context.Context, mediaID int64, label string, actorTGID int64) (int64, error) {
+	if mediaID <= 0 {
+		return 0, fmt.Errorf("invalid media id")
+	}
+	if s.photoMatcher == nil {
+		return 0, fmt.Errorf("moderation photo matcher is not configured")
+	}
+	return s.photoMatcher.BanPhotoHash(ctx, mediaID, label, actorTGID)
+}
+
+func IsCircleRejectReason(reasonCode string) bool {
+	_, ok := circleRejectReasonCodes[strings.ToUpper(strings.TrimSpace(reasonCode))]
+	return ok
+}
+
+// ETABucketFromQueueSize assumes roughly one decision per minute.
 func ETABucketFromQueueSize(queueSize int) string {
-	if queueSize >= 50 {
+	return etaBucketFromMinutes(queueSize)
+}
+
+func etaBucketFromMinutes(minutes int) string {
+	if minutes > 60 {
 		return "more_than_hour"
 	}
-	if queueSize <= 10 {
+	if minutes <= 10 {
 		return "up_to_10"
 	}
-	if queueSize <= 20 {
+	if minutes <= 20 {
 		return "up_to_20"
 	}
-	if queueSize <= 30 {
+	if minutes <= 30 {
 		return "up_to_30"
 	}
-	if queueSize <= 40 {
+	if minutes <= 40 {
 		return "up_to_40"
 	}
-	return "up_to_50"
+	if minutes <= 50 {
+		return "up_to_50"
+	}
+	return "up_to_60"
 }
 
 func (s *Service) signKey(ctx context.Context, key string) (string, error) {
@@ -22,7 +22,8 @@ func TestETABucketFromQueueSize(t *testing.T) {
 		{queueSize: 40, want: "up_to_40"},
 		{queueSize: 41, want: "up_to_50"},
 		{queueSize: 49, want: "up_to_50"},
-		{queueSize: 50, want: "more_than_hour"},
+		{queueSize: 50, want: "up_to_50"},
+		{queueSize: 55, want: "up_to_60"},
 		{queueSize: 200, want: "more_than_hour"},
 	}
 
@@ -36,8 +37,26 @@ func TestETABucketFromQueueSize(t *testing.T) {
 	}
 }
 
+func TestETABucketFromMinutesHourBoundary(t *testing.T) {
+	tests := []struct {
+		minutes int
+		want    string
+	}{
+		{minutes: 59, want: "up_to_60"},
+		{minutes: 60, want: "up_to_60"},
+		{minutes: 61, want: "more_than_hour"},
+	}
+
+	for _, tt := range tests {
+		if got := etaBucketFromMinutes(tt.minutes); got != tt.want {
+			t.Fatalf("unexpected bucket for %d minutes: got %s want %s", tt.minutes, got, tt.want)
+		}
+	}
+}
+
 func TestRejectReasonCodeValidation(t *testing.T) {
 	svc := NewService(nil, nil, nil, nil)
+	svc.AttachRejectReasons(testRejectReasons())
 
 	err := svc.Reject(context.Background(), 1, 1, "BAD_REASON", "text", "step")
 	if !errors.Is(err, ErrInvalidReasonCode) {
@@ -49,3 +68,27 @@ func TestRejectReasonCodeValidation(t *testing.T) {
 		t.Fatalf("did not expect ErrInvalidReasonCode for OTHER")
 	}
 }
+
+func TestETABucketFromThroughput(t *testing.T) {
+	tests := []struct {
+		name            string
+		queueSize       int
+		decidedLastHour int
+		want            string
+	}{
+		{name: "no throughput falls back to queue size", queueSize: 25, decidedLastHour: 0, want: "up_to_30"},
+		{name: "empty queue", queueSize: 0, decidedLastHour: 30, want: "up_to_10"},
+		{name: "fast team", queueSize: 100, decidedLastHour: 600, want: "up_to_10"},
+		{name: "half speed", queueSize: 15, decidedLastHour: 30, want: "up_to_30"},
+		{name: "slow team", queueSize: 10, decidedLastHour: 6, want: "more_than_hour"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := ETABucketFromThroughput(tt.queueSize, tt.decidedLastHour)
+			if got != tt.want {
+				t.Fatalf("unexpected bucket for queue=%d decided=%d: got %s want %s", tt.queueSize, tt.decidedLastHour, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,144 @@
+package moderation
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"math"
+	"time"
+)
+
+const (
+	defaultSLAWindow = 24 * time.Hour
+	maxSLAWindow     = 30 * 24 * time.Hour
+	throughputWindow = time.Hour
+)
+
+type SLAReport struct {
+	Window           time.Duration
+	GeneratedAt      time.Time
+	DecidedCount     int
+	ApprovedCount    int
+	RejectedCount    int
+	ApproveRatio     float64
+	WaitP50Sec       float64
+	WaitP90Sec       float64
+	WaitP99Sec       float64
+	PendingCount     int
+	OldestPendingSec float64
+	DecidedLastHour  int
+	ETABucket        string
+	LockExpiredCount int
+	StaleLockCount   int
+	Moderators       []ModeratorSLA
+	RejectReasons    []RejectReasonSLA
+}
+
+type ModeratorSLA struct {
+	ModeratorTGID  int64
+	Decisions      int
+	Approved       int
+	Rejected       int
+	AvgDecisionSec float64
+	P90DecisionSec float64
+}
+
+type RejectReasonSLA struct {
+	ReasonCode    string
+	Count         int
+	ShareOfReject float64
+	ShareOfTotal  float64
+}
+
+func (s *Service) GetSLA(ctx context.Context, window time.Duration) (SLAReport, error) {
+	if s.moderationRepo == nil {
+		return SLAReport{}, fmt.Errorf("moderation service dependencies are not configured")
+	}
+	if window <= 0 {
+		window = defaultSLAWindow
+	}
+	if window > maxSLAWindow {
+		window = maxSLAWindow
+	}
+
+	now := time.Now().UTC()
+	stats, err := s.moderationRepo.GetSLAStats(ctx, now.Add(-window))
+	if err != nil {
+		return SLAReport{}, err
+	}
+	decidedLastHour, err := s.moderationRepo.CountDecidedSince(ctx, now.Add(-throughputWindow))
+	if err != nil {
+		return SLAReport{}, err
+	}
+
+	report := SLAReport{
+		Window:           window,
+		GeneratedAt:      now,
+		DecidedCount:     stats.DecidedCount,
+		ApprovedCount:    stats.ApprovedCount,
+		RejectedCount:    stats.RejectedCount,
+		ApproveRatio:     ratio(stats.ApprovedCount, stats.DecidedCount),
+		WaitP50Sec:       stats.WaitP50Sec,
+		WaitP90Sec:       stats.WaitP90Sec,
+		WaitP99Sec:       stats.WaitP99Sec,
+		PendingCount:     stats.PendingCount,
+		OldestPendingSec: stats.OldestPendingSec,
+		DecidedLastHour:  decidedLastHour,
+		ETABucket:        ETABucketFromThroughput(stats.PendingCount, decidedLastHour),
+		LockExpiredCount: stats.LockExpiredCount,
+		StaleLockCount:   stats.StaleLockCount,
+		Moderators:       make([]ModeratorSLA, 0, len(stats.ModeratorLatency)),
+		RejectReasons:    make([]RejectReasonSLA, 0, len(stats.RejectReasonCount)),
+	}
+	for _, rec := range stats.ModeratorLatency {
+		report.Moderators = append(report.Moderators, ModeratorSLA{
+			ModeratorTGID:  rec.ModeratorTGID,
+			Decisions:      rec.Decisions,
+			Approved:       rec.Approved,
+			Rejected:       rec.Rejected,
+			AvgDecisionSec: rec.AvgDecisionSec,
+			P90DecisionSec: rec.P90DecisionSec,
+		})
+	}
+	for _, rec := range stats.RejectReasonCount {
+		report.RejectReasons = append(report.RejectReasons, RejectReasonSLA{
+			ReasonCode:    rec.ReasonCode,
+			Count:         rec.Count,
+			ShareOfReject: ratio(rec.Count, stats.RejectedCount),
+			ShareOfTotal:  ratio(rec.Count, stats.DecidedCount),
+		})
+	}
+
+	return report, nil
+}
+
+// estimateETABucket prefers observed throughput over the last hour and falls back to
+// the queue-size heuristic when nothing was decided recently.
+func (s *Service) estimateETABucket(ctx context.Context, queueSize int) string {
+	decided, err := s.moderationRepo.CountDecidedSince(ctx, time.Now().UTC().Add(-throughputWindow))
+	if err != nil {
+		log.Printf("warning: moderation throughput lookup failed: %v", err)
+		return ETABucketFromQueueSize(queueSize)
+	}
+	return ETABucketFromThroughput(queueSize, decided)
+}
+
+// ETABucketFromThroughput converts the pending queue into minutes of work at the
+// observed decisions-per-hour rate.
+func ETABucketFromThroughput(queueSize int, decidedLastHour int) string {
+	if decidedLastHour <= 0 {
+		return ETABucketFromQueueSize(queueSize)
+	}
+	if queueSize <= 0 {
+		return etaBucketFromMinutes(0)
+	}
+	minutes := int(math.Ceil(float64(queueSize) * 60 / float64(decidedLastHour)))
+	return etaBucketFromMinutes(minutes)
+}
+
+func ratio(part, total int) float64 {
+	if total <= 0 {
+		return 0
+	}
+	return float64(part) / float64(total)
+}
@@ -0,0 +1,190 @@
+package moderation
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	pgrepo "github.com/ivankudzin/tgapp/backend/internal/repo/postgres"
+)
+
+var ErrWorkloadUnavailable = errors.New("moderator workload store is not configured")
+var ErrInvalidWorkload = errors.New("invalid moderator workload")
+
+const (
+	maxModeratorActiveItems = 20
+	maxThroughputWindow     = 31 * 24 * time.Hour
+	defaultThroughputWindow = 24 * time.Hour
+)
+
+type WorkloadStore interface {
+	ListModeratorWorkload(ctx context.Context) ([]pgrepo.ModeratorWorkloadRecord, error)
+	UpsertModeratorWorkload(ctx context.Context, in pgrepo.ModeratorWorkloadRecord) (pgrepo.ModeratorWorkloadRecord, error)
+	AddModeratorShift(ctx context.Context, in pgrepo.ModeratorShiftRecord) (pgrepo.ModeratorShiftRecord, error)
+	DeleteModeratorShift(ctx context.Context, shiftID int64) (pgrepo.ModeratorShiftRecord, error)
+	ReassignIdleLocks(ctx context.Context, idle, lockDuration time.Duration) ([]pgrepo.LockReassignmentRecord, error)
+	ShiftThroughput(ctx context.Context, from, to time.Time) ([]pgrepo.ShiftThroughputRecord, error)
+}
+
+type Workload struct {
+	Pending    int
+	Moderators []pgrepo.ModeratorWorkloadRecord
+}
+
+func (s *Service) AttachWorkload(store WorkloadStore) {
+	s.workload = store
+}
+
+func (s *Service) GetWorkload(ctx context.Context) (Workload, error) {
+	if s.workload == nil || s.moderationRepo == nil {
+		return Workload{}, ErrWorkloadUnavailable
+	}
+
+	pending, err := s.moderationRepo.CountPending(ctx)
+	if err != nil {
+		return Workload{}, err
+	}
+	moderators, err := s.workload.ListModeratorWorkload(ctx)
+	if err != nil {
+		return Workload{}, err
+	}
+	return Workload{Pending: pending, Moderators: moderators}, nil
+}
+
+func (s *Service) SaveModeratorWorkload(ctx context.Context, moderatorTGID int64, maxActiveItems int, skills []string, actorTGID int64) (pgrepo.ModeratorWorkloadRecord, error) {
+	if s.workload == nil {
+		return pgrepo.ModeratorWorkloadRecord{}, ErrWorkloadUnavailable
+	}
+	if moderatorTGID == 0 {
+		return pgrepo.ModeratorWorkloadRecord{}, fmt.Errorf("%w: moderator tg id is required", ErrInvalidWorkload)
+	}
+	if maxActiveItems < 1 || maxActiveItems > maxModeratorActiveItems {
+		return pgrepo.ModeratorWorkloadRecord{}, fmt.Errorf("%w: max_active_items must be between 1 and %d", ErrInvalidWorkload, maxModeratorActiveItems)
+	}
+	normalized, err := NormalizeModeratorSkills(skills)
+	if err != nil {
+		return pgrepo.ModeratorWorkloadRecord{}, err
+	}
+
+	record := pgrepo.ModeratorWorkloadRecord{
+		ModeratorTGID:  moderatorTGID,
+		MaxActiveItems: maxActiveItems,
+		Skills:         normalized,
+	}
+	if actorTGID != 0 {
+		record.UpdatedByTGID = &actorTGID
+	}
+	return s.workload.UpsertModeratorWorkload(ctx, record)
+}
+
+func (s *Service) AddModeratorShift(ctx context.Context, moderatorTGID int64, weekday int, startsAt, endsAt string, actorTGID int64) (pgrepo.ModeratorShiftRecord, error) {
+	if s.workload == nil {
+		return pgrepo.ModeratorShiftRecord{}, ErrWorkloadUnavailable
+	}
+	if moderatorTGID == 0 {
+		return pgrepo.ModeratorShiftRecord{}, fmt.Errorf("%w: moderator tg id is required", ErrInvalidWorkload)
+	}
+	if weekday < 1 || weekday > 7 {
+		return pgrepo.ModeratorShiftRecord{}, fmt.Errorf("%w: weekday must be 1 (Mon) .. 7 (Sun)", ErrInvalidWorkload)
+	}
+	start, okStart := normalizeShiftClock(startsAt)
+	end, okEnd := normalizeShiftClock(endsAt)
+	if !okStart || !okEnd {
+		return pgrepo.ModeratorShiftRecord{}, fmt.Errorf("%w: shift times must be HH:MM", ErrInvalidWorkload)
+	}
+	if start == end {
+		return pgrepo.ModeratorShiftRecord{}, fmt.Errorf("%w: shift must not start and end at the same time", ErrInvalidWorkload)
+	}
+
+	record := pgrepo.ModeratorShiftRecord{
+		ModeratorTGID: moderatorTGID,
+		Weekday:       weekday,
+		StartsAt:      start,
+		EndsAt:        end,
+	}
+	if actorTGID != 0 {
+		record.CreatedByTGID = &actorTGID
+	}
+	return s.workload.AddModeratorShift(ctx, record)
+}
+
+func (s *Service) DeleteModeratorShift(ctx context.Context, shiftID int64) (pgrepo.ModeratorShiftRecord, error) {
+	if s.workload == nil {
+		return pgrepo.ModeratorShiftRecord{}, ErrWorkloadUnavailable
+	}
+	if shiftID <= 0 {
+		return pgrepo.ModeratorShiftRecord{}, fmt.Errorf("%w: invalid shift id", ErrInvalidWorkload)
+	}
+	return s.workload.DeleteModeratorShift(ctx, shiftID)
+}
+
+// ReassignIdleLocks hands cards held longer than idle to another on-shift moderator with a fresh queue lock.
+func (s *Service) ReassignIdleLocks(ctx context.Context, idle time.Duration) ([]pgrepo.LockReassignmentRecord, error) {
+	if s.workload == nil {
+		return nil, ErrWorkloadUnavailable
+	}
+	if idle <= 0 || idle >= queueLockTTL {
+		return nil, fmt.Errorf("%w: idle window must be below the %s queue lock", ErrInvalidWorkload, queueLockTTL)
+	}
+	return s.workload.ReassignIdleLocks(ctx, idle, queueLockTTL)
+}
+
+func (s *Service) ShiftThroughput(ctx context.Context, from, to time.Time) ([]pgrepo.ShiftThroughputRecord, error) {
+	if s.workload == nil {
+		return nil, ErrWorkloadUnavailable
+	}
+	if to.IsZero() {
+		to = time.Now().UTC()
+	}
+	if from.IsZero() {
+		from = to.Add(-defaultThroughputWindow)
+	}
+	if !from.Before(to) || to.Sub(from) > maxThroughputWindow {
+		return nil, fmt.Errorf("%w: throughput window must be positive and at most 31 days", ErrInvalidWorkload)
+	}
+	return s.workload.ShiftThroughput(ctx, from, to)
+}
+
+func NormalizeModeratorSkills(skills []string) ([]string, error) {
+	seen := make(map[string]struct{}, len(skills))
+	for _, raw := range skills {
+		skill := strings.ToUpper(strings.TrimSpace(raw))
+		if skill == "" {
+			continue
+		}
+		if !isModeratorSkill(skill) {
+			return nil, fmt.Errorf("%w: unknown skill %q", ErrInvalidWorkload, raw)
+		}
+		seen[skill] = struct{}{}
+	}
+	if len(seen) == 0 {
+		return nil, fmt.Errorf("%w: at least one skill is required", ErrInvalidWorkload)
+	}
+
+	out := make([]string, 0, len(seen))
+	for _, skill := range pgrepo.ModeratorSkills {
+		if _, ok := seen[skill]; ok {
+			out = append(out, skill)
+		}
+	}
+	return out, nil
+}
+
+func isModeratorSkill(skill string) bool {
+	for _, known := range pgrepo.ModeratorSkills {
+		if known == skill {
+			return true
+		}
+	}
+	return false
+}
+
+func normalizeShiftClock(raw string) (string, bool) {
+	parsed, err := time.Parse("15:04", strings.TrimSpace(raw))
+	if err != nil {
+		return "", false
+	}
+	return parsed.Format("15:04"), true
+}
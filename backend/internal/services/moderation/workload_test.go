@@ -0,0 +1,99 @@
+package moderation
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+
+	pgrepo "github.com/ivankudzin/tgapp/backend/internal/repo/postgres"
+)
+
+func TestNormalizeModeratorSkills(t *testing.T) {
+	got, err := NormalizeModeratorSkills([]string{" reports", "photo", "PHOTO", ""})
+	if err != nil {
+		t.Fatalf("normalize skills: %v", err)
+	}
+	if want := []string{"PHOTO", "REPORTS"}; !reflect.DeepEqual(got, want) {
+		t.Fatalf("unexpected skills: got %v want %v", got, want)
+	}
+
+	if _, err := NormalizeModeratorSkills([]string{"video"}); !errors.Is(err, ErrInvalidWorkload) {
+		t.Fatalf("expected invalid workload for unknown skill, got %v", err)
+	}
+	if _, err := NormalizeModeratorSkills(nil); !errors.Is(err, ErrInvalidWorkload) {
+		t.Fatalf("expected invalid workload for empty skills, got %v", err)
+	}
+}
+
+func TestAddModeratorShiftValidation(t *testing.T) {
+	store := &workloadStoreStub{}
+	svc := NewService(nil, nil, nil, nil)
+	svc.AttachWorkload(store)
+	ctx := context.Background()
+
+	if _, err := svc.AddModeratorShift(ctx, 7, 0, "09:00", "18:00", 1); !errors.Is(err, ErrInvalidWorkload) {
+		t.Fatalf("expected invalid weekday error, got %v", err)
+	}
+	if _, err := svc.AddModeratorShift(ctx, 7, 1, "25:00", "18:00", 1); !errors.Is(err, ErrInvalidWorkload) {
+		t.Fatalf("expected invalid clock error, got %v", err)
+	}
+	if _, err := svc.AddModeratorShift(ctx, 7, 1, "09:00", "9:00", 1); !errors.Is(err, ErrInvalidWorkload) {
+		t.Fatalf("expected empty shift error, got %v", err)
+	}
+
+	shift, err := svc.AddModeratorShift(ctx, 7, 5, "22:00", "6:00", 1)
+	if err != nil {
+		t.Fatalf("add overnight shift: %v", err)
+	}
+	if shift.StartsAt != "22:00" || shift.EndsAt != "06:00" || shift.CreatedByTGID == nil || *shift.CreatedByTGID != 1 {
+		t.Fatalf("unexpected stored shift: %+v", shift)
+	}
+}
+
+func TestReassignIdleLocksRequiresIdleBelowLockTTL(t *testing.T) {
+	store := &workloadStoreStub{}
+	svc := NewService(nil, nil, nil, nil)
+	svc.AttachWorkload(store)
+
+	if _, err := svc.ReassignIdleLocks(context.Background(), queueLockTTL); !errors.Is(err, ErrInvalidWorkload) {
+		t.Fatalf("expected idle window error, got %v", err)
+	}
+	if _, err := svc.ReassignIdleLocks(context.Background(), 5*time.Minute); err != nil {
+		t.Fatalf("reassign idle locks: %v", err)
+	}
+	if store.lockDuration != queueLockTTL {
+		t.Fatalf("expected reassigned cards to get the queue lock ttl, got %s", store.lockDuration)
+	}
+}
+
+type workloadStoreStub struct {
+	lockDuration time.Duration
+}
+
+func (s *workloadStoreStub) ListModeratorWorkload(context.Context) ([]pgrepo.ModeratorWorkloadRecord, error) {
+	return nil, nil
+}
+
+func (s *workloadStoreStub) UpsertModeratorWorkload(_ context.Context, in pgrepo.ModeratorWorkloadRecord) (pgrepo.ModeratorWorkloadRecord, error) {
+	return in, nil
+}
+
+func (s *workloadStoreStub) AddModeratorShift(_ context.Context, in pgrepo.ModeratorShiftRecord) (pgrepo.ModeratorShiftRecord, error) {
+	in.ID = 1
+	return in, nil
+}
+
+func (s *workloadStoreStub) DeleteModeratorShift(context.Context, int64) (pgrepo.ModeratorShiftRecord, error) {
+	return pgrepo.ModeratorShiftRecord{}, pgrepo.ErrModeratorShiftNotFound
+}
+
+func (s *workloadStoreStub) ReassignIdleLocks(_ context.Context, _ time.Duration, lockDuration time.Duration) ([]pgrepo.LockReassignmentRecord, error) {
+	s.lockDuration = lockDuration
+	return []pgrepo.LockReassignmentRecord{}, nil
+}
+
+func (s *workloadStoreStub) ShiftThroughput(context.Context, time.Time, time.Time) ([]pgrepo.ShiftThroughputRecord, error) {
+	return nil, nil
+}
@@ -0,0 +1,278 @@
+package permissions
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	pgrepo "github.com/ivankudzin/tgapp/backend/internal/repo/postgres"
+)
+
+const (
+	ModerationDecide         = "moderation.decide"
+	ModerationQA             = "moderation.qa"
+	ModerationReasons        = "moderation.reasons"
+	UsersBan                 = "users.ban"
+	UsersViewPrivate         = "users.view_private"
+	SystemToggleRegistration = "system.toggle_registration"
+	StatsView                = "stats.view"
+	AuditView                = "audit.view"
+	PaymentsRefund           = "payments.refund"
+	AccessManage             = "access.manage"
+	ConfigPublish            = "config.publish"
+	BalanceRecompute         = "balance.recompute"
+	FlagsManage              = "flags.manage"
+)
+
+const (
+	RoleOwner = "OWNER"
+
+	roleCacheTTL = 30 * time.Second
+)
+
+var (
+	ErrInvalidRole   = errors.New("invalid role")
+	ErrSystemRole    = errors.New("system roles are read-only")
+	ErrRolesReadOnly = errors.New("role catalog is read-only")
+)
+
+var rolePattern = regexp.MustCompile(`^[A-Z][A-Z0-9_]{1,31}$`)
+
+var knownPermissions = []string{
+	ModerationDecide,
+	ModerationQA,
+	ModerationReasons,
+	UsersBan,
+	UsersViewPrivate,
+	SystemToggleRegistration,
+	StatsView,
+	AuditView,
+	PaymentsRefund,
+	AccessManage,
+	ConfigPublish,
+	BalanceRecompute,
+	FlagsManage,
+}
+
+// defaultRoles mirrors the seed of migrations 000020, 000028 and 000029 and is served until a role store is attached.
+var defaultRoles = []Role{
+	{Name: RoleOwner, Permissions: append([]string(nil), knownPermissions...), IsSystem: true},
+	{Name: "ADMIN", Permissions: []string{ModerationDecide, ModerationQA, ModerationReasons, UsersBan, UsersViewPrivate, AccessManage}, IsSystem: true},
+	{Name: "SUPPORT", Permissions: []string{UsersViewPrivate, StatsView, PaymentsRefund}, IsSystem: true},
+	{Name: "MODERATOR", Permissions: []string{ModerationDecide}, IsSystem: true},
+}
+
+type RoleStore interface {
+	ListAdminRoles(ctx context.Context) ([]pgrepo.AdminRoleRecord, error)
+	UpsertCustomAdminRole(ctx context.Context, in pgrepo.AdminRoleRecord) (pgrepo.AdminRoleRecord, bool, error)
+}
+
+type Role struct {
+	Name          string
+	Permissions   []string
+	IsSystem      bool
+	UpdatedByTGID *int64
+	UpdatedAt     time.Time
+}
+
+func (r Role) Has(permission string) bool {
+	for _, granted := range r.Permissions {
+		if granted == permission {
+			return true
+		}
+	}
+	return false
+}
+
+// Service resolves role names to permission sets. Roles are shared by the web admin, the admin bot
+// and the moderator bot; OWNER always holds every permission so a bad edit can't lock everyone out.
+type Service struct {
+	mu       sync.Mutex
+	store    RoleStore
+	roles    map[string]Role
+	loadedAt time.Time
+	now      func() time.Time
+}
+
+func NewService(store RoleStore) *Service {
+	return &Service{store: store, now: time.Now}
+}
+
+func Known() []string {
+	return append([]string(nil), knownPermissions...)
+}
+
+func IsKnown(permission string) bool {
+	for _, known := range knownPermissions {
+		if known == permission {
+			return true
+		}
+	}
+	return false
+}
+
+func NormalizeRoleName(raw string) string {
+	return strings.ToUpper(strings.TrimSpace(raw))
+}
+
+func (s *Service) Has(ctx context.Context, roleName string, permission string) (bool, error) {
+	role, ok, err := s.Role(ctx, roleName)
+	if err != nil || !ok {
+		return false, err
+	}
+	return role.Has(permission), nil
+}
+
+func (s *Service) Role(ctx context.Context, roleName string) (Role, bool, error) {
+	name := NormalizeRoleName(roleName)
+	if name == RoleOwner {
+		return Role{Name: RoleOwner, Permissions: Known(), IsSystem: true}, true, nil
+	}
+
+	roles, err := s.load(ctx)
+	if err != nil {
+		return Role{}, false, err
+	}
+	role, ok := roles[name]
+	return role, ok, nil
+}
+
+func (s *Service) ListRoles(ctx context.Context) ([]Role, error) {
+	roles, err := s.load(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	items := make([]Role, 0, len(roles))
+	for _, role := range roles {
+		items = append(items, role)
+	}
+	sort.Slice(items, func(i, j int) bool {
+		if items[i].IsSystem != items[j].IsSystem {
+			return items[i].IsSystem
+		}
+		return items[i].Name < items[j].Name
+	})
+	return items, nil
+}
+
+// SaveCustomRole creates or replaces a custom role; system roles and unknown permissions are rejected.
+func (s *Service) SaveCustomRole(ctx context.Context, in Role, actorTGID int64) (Role, error) {
+	name := NormalizeRoleName(in.Name)
+	if !rolePattern.MatchString(name) {
+		return Role{}, fmt.Errorf("%w: name must match %s", ErrInvalidRole, rolePattern.String())
+	}
+	perms, err := normalizePermissions(in.Permissions)
+	if err != nil {
+		return Role{}, err
+	}
+	for _, role := range defaultRoles {
+		if role.Name == name {
+			return Role{}, ErrSystemRole
+		}
+	}
+	if s.store == nil {
+		return Role{}, ErrRolesReadOnly
+	}
+
+	var updatedBy *int64
+	if actorTGID != 0 {
+		updatedBy = &actorTGID
+	}
+	record, saved, err := s.store.UpsertCustomAdminRole(ctx, pgrepo.AdminRoleRecord{
+		Name:          name,
+		Permissions:   perms,
+		UpdatedByTGID: updatedBy,
+	})
+	if err != nil {
+		return Role{}, err
+	}
+	if !saved {
+		return Role{}, ErrSystemRole
+	}
+
+	s.invalidate()
+	return fromRecord(record), nil
+}
+
+func (s *Service) load(ctx context.Context) (map[string]Role, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.roles != nil && s.now().Sub(s.loadedAt) < roleCacheTTL {
+		return s.roles, nil
+	}
+	if s.store == nil {
+		s.roles = rolesByName(defaultRoles)
+		s.loadedAt = s.now()
+		return s.roles, nil
+	}
+
+	records, err := s.store.ListAdminRoles(ctx)
+	if err != nil {
+		if s.roles != nil {
+			return s.roles, nil
+		}
+		return nil, err
+	}
+	roles := make([]Role, 0, len(records))
+	for _, record := range records {
+		roles = append(roles, fromRecord(record))
+	}
+	s.roles = rolesByName(roles)
+	s.loadedAt = s.now()
+	return s.roles, nil
+}
+
+func (s *Service) invalidate() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.roles = nil
+}
+
+func normalizePermissions(raw []string) ([]string, error) {
+	seen := make(map[string]struct{}, len(raw))
+	perms := make([]string, 0, len(raw))
+	for _, value := range raw {
+		permission := strings.ToLower(strings.TrimSpace(value))
+		if permission == "" {
+			continue
+		}
+		if !IsKnown(permission) {
+			return nil, fmt.Errorf("%w: unknown permission %q", ErrInvalidRole, permission)
+		}
+		if _, ok := seen[permission]; ok {
+			continue
+		}
+		seen[permission] = struct{}{}
+		perms = append(perms, permission)
+	}
+	if len(perms) == 0 {
+		return nil, fmt.Errorf("%w: at least one permission is required", ErrInvalidRole)
+	}
+	sort.Strings(perms)
+	return perms, nil
+}
+
+func rolesByName(roles []Role) map[string]Role {
+	byName := make(map[string]Role, len(roles))
+	for _, role := range roles {
+		byName[NormalizeRoleName(role.Name)] = role
+	}
+	return byName
+}
+
+func fromRecord(record pgrepo.AdminRoleRecord) Role {
+	return Role{
+		Name:          NormalizeRoleName(record.Name),
+		Permissions:   append([]string(nil), record.Permissions...),
+		IsSystem:      record.IsSystem,
+		UpdatedByTGID: record.UpdatedByTGID,
+		UpdatedAt:     record.UpdatedAt,
+	}
+}
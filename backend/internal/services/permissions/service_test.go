@@ -0,0 +1,109 @@
+package permissions
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	pgrepo "github.com/ivankudzin/tgapp/backend/internal/repo/postgres"
+)
+
+func TestDefaultRolesMatchSeed(t *testing.T) {
+	svc := NewService(nil)
+	ctx := context.Background()
+
+	cases := []struct {
+		role       string
+		permission string
+		want       bool
+	}{
+		{role: "owner", permission: PaymentsRefund, want: true},
+		{role: "ADMIN", permission: UsersBan, want: true},
+		{role: "ADMIN", permission: AuditView, want: false},
+		{role: "SUPPORT", permission: StatsView, want: true},
+		{role: "SUPPORT", permission: ModerationDecide, want: false},
+		{role: "MODERATOR", permission: ModerationDecide, want: true},
+		{role: "MODERATOR", permission: UsersViewPrivate, want: false},
+		{role: "USER", permission: ModerationDecide, want: false},
+	}
+	for _, tc := range cases {
+		got, err := svc.Has(ctx, tc.role, tc.permission)
+		if err != nil {
+			t.Fatalf("has %s/%s: %v", tc.role, tc.permission, err)
+		}
+		if got != tc.want {
+			t.Fatalf("unexpected permission %s/%s: got=%v want=%v", tc.role, tc.permission, got, tc.want)
+		}
+	}
+
+	if _, err := svc.SaveCustomRole(ctx, Role{Name: "QA", Permissions: []string{ModerationQA}}, 1); !errors.Is(err, ErrRolesReadOnly) {
+		t.Fatalf("expected read-only error, got %v", err)
+	}
+}
+
+func TestSaveCustomRoleValidatesAndReloads(t *testing.T) {
+	store := &roleStoreStub{records: []pgrepo.AdminRoleRecord{
+		{Name: "OWNER", Permissions: []string{}, IsSystem: true},
+		{Name: "MODERATOR", Permissions: []string{ModerationDecide}, IsSystem: true},
+	}}
+	svc := NewService(store)
+	ctx := context.Background()
+
+	if ok, _ := svc.Has(ctx, "OWNER", AccessManage); !ok {
+		t.Fatalf("owner must keep every permission regardless of the stored set")
+	}
+	if _, err := svc.SaveCustomRole(ctx, Role{Name: "moderator", Permissions: []string{StatsView}}, 1); !errors.Is(err, ErrSystemRole) {
+		t.Fatalf("expected system role error, got %v", err)
+	}
+	if _, err := svc.SaveCustomRole(ctx, Role{Name: "QA", Permissions: []string{"chats.read"}}, 1); !errors.Is(err, ErrInvalidRole) {
+		t.Fatalf("expected invalid role for unknown permission, got %v", err)
+	}
+	if _, err := svc.SaveCustomRole(ctx, Role{Name: "q", Permissions: []string{StatsView}}, 1); !errors.Is(err, ErrInvalidRole) {
+		t.Fatalf("expected invalid role for short name, got %v", err)
+	}
+
+	if ok, _ := svc.Has(ctx, "QA_LEAD", ModerationQA); ok {
+		t.Fatalf("unknown role must not have permissions")
+	}
+	saved, err := svc.SaveCustomRole(ctx, Role{Name: " qa_lead ", Permissions: []string{" Moderation.QA ", StatsView, StatsView}}, 42)
+	if err != nil {
+		t.Fatalf("save custom role: %v", err)
+	}
+	if saved.Name != "QA_LEAD" || len(saved.Permissions) != 2 || saved.UpdatedByTGID == nil || *saved.UpdatedByTGID != 42 {
+		t.Fatalf("unexpected saved role: %+v", saved)
+	}
+	if ok, _ := svc.Has(ctx, "qa_lead", ModerationQA); !ok {
+		t.Fatalf("saved role must be visible without waiting for the cache ttl")
+	}
+
+	roles, err := svc.ListRoles(ctx)
+	if err != nil {
+		t.Fatalf("list roles: %v", err)
+	}
+	if len(roles) != 3 || roles[2].Name != "QA_LEAD" || roles[2].IsSystem {
+		t.Fatalf("custom roles must follow system roles: %+v", roles)
+	}
+}
+
+type roleStoreStub struct {
+	records []pgrepo.AdminRoleRecord
+}
+
+func (s *roleStoreStub) ListAdminRoles(context.Context) ([]pgrepo.AdminRoleRecord, error) {
+	return append([]pgrepo.AdminRoleRecord(nil), s.records...), nil
+}
+
+func (s *roleStoreStub) UpsertCustomAdminRole(_ context.Context, in pgrepo.AdminRoleRecord) (pgrepo.AdminRoleRecord, bool, error) {
+	for i, record := range s.records {
+		if record.Name != in.Name {
+			continue
+		}
+		if record.IsSystem {
+			return pgrepo.AdminRoleRecord{}, false, nil
+		}
+		s.records[i] = in
+		return in, true, nil
+	}
+	s.records = append(s.records, in)
+	return in, true, nil
+}
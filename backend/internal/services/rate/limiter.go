@@ -6,6 +6,7 @@ import (
 	"log"
 	"strconv"
 	"strings"
+	"sync"
 	"time"
 )
 
@@ -92,7 +93,12 @@ type Store interface {
 }
 
 type Limiter struct {
-	store        Store
+	store  Store
+	mu     sync.RWMutex
+	limits likeLimits
+}
+
+type likeLimits struct {
 	perSec       int
 	per10Sec     int
 	perMinute    int
@@ -103,6 +109,27 @@ type Limiter struct {
 }
 
 func NewLimiter(store Store, perSec, per10Sec, perMinute int) *Limiter {
+	return &Limiter{
+		store:  store,
+		limits: newLikeLimits(perSec, per10Sec, perMinute),
+	}
+}
+
+// SetLikeLimits replaces the like windows, e.g. after a remote config publish.
+func (l *Limiter) SetLikeLimits(perSec, per10Sec, perMinute int) {
+	limits := newLikeLimits(perSec, per10Sec, perMinute)
+	l.mu.Lock()
+	l.limits = limits
+	l.mu.Unlock()
+}
+
+func (l *Limiter) likeLimits() likeLimits {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+	return l.limits
+}
+
+func newLikeLimits(perSec, per10Sec, perMinute int) likeLimits {
 	if perSec < 0 {
 		perSec = 0
 	}
@@ -113,8 +140,7 @@ func NewLimiter(store Store, perSec, per10Sec, perMinute int) *Limiter {
 		perMinute = 0
 	}
 
-	return &Limiter{
-		store:        store,
+	return likeLimits{
 		perSec:       perSec,
 		per10Sec:     per10Sec,
 		perMinute:    perMinute,
@@ -132,16 +158,17 @@ func (l *Limiter) CheckLikeRate(ctx context.Context, userID int64, sid, ip, devi
 		return false, 1, "invalid_input"
 	}
 	_ = ip
+	limits := l.likeLimits()
 
-	sidLimit := l.sidPerMinute
+	sidLimit := limits.sidPerMinute
 	normalizedSID := strings.TrimSpace(sid)
 	if normalizedSID == "" {
 		sidLimit = 0
 		normalizedSID = "_"
 	}
-	devicePerSec := l.devicePerSec
-	devicePer10 := l.devicePer10
-	devicePerMin := l.devicePerMin
+	devicePerSec := limits.devicePerSec
+	devicePer10 := limits.devicePer10
+	devicePerMin := limits.devicePerMin
 	normalizedDeviceID := strings.TrimSpace(deviceID)
 	if normalizedDeviceID == "" {
 		devicePerSec = 0
@@ -162,11 +189,11 @@ func (l *Limiter) CheckLikeRate(ctx context.Context, userID int64, sid, ip, devi
 			deviceMinuteKey(normalizedDeviceID),
 			sidMinuteKey(normalizedSID),
 		},
-		l.perSec,
+		limits.perSec,
 		int64(likes1SecWindow/time.Millisecond),
-		l.per10Sec,
+		limits.per10Sec,
 		int64(likes10SecWindow/time.Millisecond),
-		l.perMinute,
+		limits.perMinute,
 		int64(likesMinuteWindow/time.Millisecond),
 		devicePerSec,
 		int64(likes1SecWindow/time.Millisecond),
@@ -215,33 +242,34 @@ func (l *Limiter) RetryAfterLike(ctx context.Context, userID int64) (int64, erro
 	}
 
 	retryAfterSec := int64(0)
+	limits := l.likeLimits()
 
-	if l.perSec > 0 {
+	if limits.perSec > 0 {
 		count, ttl, err := l.store.WindowState(ctx, user1SecKey(userID))
 		if err != nil {
 			return 0, err
 		}
-		if count > int64(l.perSec) {
+		if count > int64(limits.perSec) {
 			retryAfterSec = maxInt64(retryAfterSec, ceilSeconds(ttl))
 		}
 	}
 
-	if l.per10Sec > 0 {
+	if limits.per10Sec > 0 {
 		count, ttl, err := l.store.WindowState(ctx, user10SecKey(userID))
 		if err != nil {
 			return 0, err
 		}
-		if count > int64(l.per10Sec) {
+		if count > int64(limits.per10Sec) {
 			retryAfterSec = maxInt64(retryAfterSec, ceilSeconds(ttl))
 		}
 	}
 
-	if l.perMinute > 0 {
+	if limits.perMinute > 0 {
 		count, ttl, err := l.store.WindowState(ctx, userMinuteKey(userID))
 		if err != nil {
 			return 0, err
 		}
-		if count > int64(l.perMinute) {
+		if count > int64(limits.perMinute) {
 			retryAfterSec = maxInt64(retryAfterSec, ceilSeconds(ttl))
 		}
 	}
@@ -0,0 +1,248 @@
+package remoteconfig
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/ivankudzin/tgapp/backend/internal/config"
+)
+
+var ErrInvalidDocument = errors.New("invalid remote config document")
+
+// DecodeDocument parses a full remote config document. JSON is accepted as a YAML subset so
+// the same keys as the `remote:` section of the config file apply; unknown keys are rejected
+// so a typo never publishes silently.
+func DecodeDocument(raw []byte) (config.RemoteConfig, error) {
+	if len(bytes.TrimSpace(raw)) == 0 {
+		return config.RemoteConfig{}, fmt.Errorf("%w: document is empty", ErrInvalidDocument)
+	}
+
+	decoder := yaml.NewDecoder(bytes.NewReader(raw))
+	decoder.KnownFields(true)
+
+	var cfg config.RemoteConfig
+	if err := decoder.Decode(&cfg); err != nil {
+		return config.RemoteConfig{}, fmt.Errorf("%w: %v", ErrInvalidDocument, err)
+	}
+	if err := Validate(cfg); err != nil {
+		return config.RemoteConfig{}, err
+	}
+	return cfg, nil
+}
+
+// EncodeDocument renders cfg as canonical JSON: yaml keys, sorted, durations as strings.
+// The checksum of this form identifies a config regardless of how it was submitted.
+func EncodeDocument(cfg config.RemoteConfig) ([]byte, error) {
+	raw, err := yaml.Marshal(cfg)
+	if err != nil {
+		return nil, fmt.Errorf("encode remote config: %w", err)
+	}
+
+	var tree map[string]any
+	if err := yaml.Unmarshal(raw, &tree); err != nil {
+		return nil, fmt.Errorf("encode remote config: %w", err)
+	}
+
+	out, err := json.Marshal(tree)
+	if err != nil {
+		return nil, fmt.Errorf("encode remote config: %w", err)
+	}
+	return out, nil
+}
+
+func Checksum(document []byte) string {
+	sum := sha256.Sum256(document)
+	return hex.EncodeToString(sum[:])
+}
+
+func Validate(cfg config.RemoteConfig) error {
+	var problems []string
+	check := func(ok bool, format string, args ...any) {
+		if !ok {
+			problems = append(problems, fmt.Sprintf(format, args...))
+		}
+	}
+
+	limits := cfg.Limits
+	check(limits.FreeLikesPerDay > 0, "limits.free_likes_per_day must be positive")
+	check(limits.PlusRatePerMinute >= 0, "limits.plus_rate_per_minute must not be negative")
+	check(limits.PlusRatePer10Seconds >= 0, "limits.plus_rate_per_10sec must not be negative")
+	check(limits.PlusRewindsPerDay >= 0, "limits.plus_rewinds_per_day must not be negative")
+
+	abuse := cfg.AntiAbuse
+	check(abuse.LikeMaxPerSec > 0, "antiabuse.like_max_per_sec must be positive")
+	check(abuse.LikeMax10Sec >= abuse.LikeMaxPerSec, "antiabuse.like_max_10s must be at least like_max_per_sec")
+	check(abuse.LikeMaxPerMin >= abuse.LikeMax10Sec, "antiabuse.like_max_min must be at least like_max_10s")
+	check(abuse.ReportMaxPer10Min > 0, "antiabuse.report_max_10m must be positive")
+	check(abuse.MinCardViewMS >= 0, "antiabuse.min_card_view_ms must not be negative")
+	check(abuse.RiskDecayHours > 0, "antiabuse.risk_decay_hours must be positive")
+	check(len(abuse.CooldownStepsSec) > 0, "antiabuse.cooldown_steps_sec must not be empty")
+	for i, step := range abuse.CooldownStepsSec {
+		check(step > 0, "antiabuse.cooldown_steps_sec[%d] must be positive", i)
+		if i > 0 {
+			check(step > abuse.CooldownStepsSec[i-1], "antiabuse.cooldown_steps_sec must be strictly increasing")
+		}
+	}
+	check(abuse.ShadowThreshold > 0, "antiabuse.shadow_threshold must be positive")
+	check(abuse.ShadowRankMultiplier > 0 && abuse.ShadowRankMultiplier <= 1, "antiabuse.shadow_rank_multiplier must be in (0, 1]")
+	check(abuse.SuspectLikeThreshold >= 0, "antiabuse.suspect_like_threshold must not be negative")
+	check(abuse.NewDeviceRiskWeight >= 0, "antiabuse.new_device_risk_weight must not be negative")
+
+	check(cfg.AdsInject.FreeEvery >= 0, "ads_inject.free must not be negative")
+	check(cfg.AdsInject.PlusEvery >= 0, "ads_inject.plus must not be negative")
+
+	filters := cfg.Filters
+	check(filters.AgeMin >= 18, "filters.age_min must be at least 18")
+	check(filters.AgeMax >= filters.AgeMin && filters.AgeMax <= 100, "filters.age_max must be between age_min and 100")
+	check(filters.RadiusDefaultKM > 0, "filters.radius_default_km must be positive")
+	check(filters.RadiusMaxKM >= filters.RadiusDefaultKM, "filters.radius_max_km must be at least radius_default_km")
+
+	check(strings.TrimSpace(cfg.GoalsMode) != "", "goals_mode is required")
+	check(cfg.Boost.Duration > 0, "boost.duration must be positive")
+
+	check(len(cfg.Cities) > 0, "cities must not be empty")
+	cityIDs := make(map[string]struct{}, len(cfg.Cities))
+	for i, city := range cfg.Cities {
+		id := strings.TrimSpace(city.ID)
+		check(id != "", "cities[%d].id is required", i)
+		check(strings.TrimSpace(city.Name) != "", "cities[%d].name is required", i)
+		check(city.Lat >= -90 && city.Lat <= 90, "cities[%d].lat is out of range", i)
+		check(city.Lon >= -180 && city.Lon <= 180, "cities[%d].lon is out of range", i)
+		if _, dup := cityIDs[id]; dup && id != "" {
+			check(false, "cities[%d].id %q is duplicated", i, id)
+		}
+		cityIDs[id] = struct{}{}
+	}
+
+	me := cfg.MeDefaults
+	if _, ok := cityIDs[strings.TrimSpace(me.CityID)]; !ok {
+		check(false, "me_defaults.city_id %q is not listed in cities", me.CityID)
+	}
+	check(strings.TrimSpace(me.ModerationStatus) != "", "me_defaults.moderation_status is required")
+	if _, err := time.LoadLocation(strings.TrimSpace(me.Timezone)); err != nil || strings.TrimSpace(me.Timezone) == "" {
+		check(false, "me_defaults.timezone %q is not a valid IANA zone", me.Timezone)
+	}
+	check(me.PlusDuration >= 0, "me_defaults.plus_duration must not be negative")
+	check(me.TooFastRetryAfterSec >= 0, "me_defaults.too_fast_retry_after_sec must not be negative")
+	ent := me.Entitlements
+	check(ent.SuperLikeCredits >= 0 && ent.BoostCredits >= 0 && ent.RevealCredits >= 0 && ent.MessageWoMatchCredits >= 0,
+		"me_defaults.entitlements credits must not be negative")
+	check(ent.IncognitoDuration >= 0, "me_defaults.entitlements.incognito_duration must not be negative")
+
+	// Documents published before the balance section existed decode to its zero value, so the
+	// stricter checks only apply once the controller is enabled.
+	balance := cfg.Balance
+	check(balance.ActiveWindow >= 0, "balance.active_window must not be negative")
+	check(balance.MinActive >= 0, "balance.min_active must not be negative")
+	check(balance.TargetFemaleShare >= 0 && balance.TargetFemaleShare < 1, "balance.target_female_share must be in [0, 1)")
+	check(balance.Tolerance >= 0 && balance.Tolerance < 0.5, "balance.tolerance must be in [0, 0.5)")
+	check(balance.OverLikeFactor >= 0 && balance.OverLikeFactor <= 3, "balance.over_like_factor must be between 0 and 3")
+	check(balance.UnderLikeFactor >= 0 && balance.UnderLikeFactor <= 3, "balance.under_like_factor must be between 0 and 3")
+	if balance.Enabled {
+		check(balance.ActiveWindow > 0, "balance.active_window must be positive")
+		check(balance.TargetFemaleShare > 0, "balance.target_female_share must be positive")
+	}
+	targetCities := make(map[string]struct{}, len(balance.Targets))
+	for i, target := range balance.Targets {
+		id := strings.TrimSpace(target.CityID)
+		if _, ok := cityIDs[id]; !ok {
+			check(false, "balance.targets[%d].city_id %q is not listed in cities", i, target.CityID)
+		}
+		if _, dup := targetCities[id]; dup {
+			check(false, "balance.targets[%d].city_id %q is duplicated", i, target.CityID)
+		}
+		targetCities[id] = struct{}{}
+		check(target.FemaleShare > 0 && target.FemaleShare < 1, "balance.targets[%d].female_share must be in (0, 1)", i)
+	}
+
+	if len(problems) > 0 {
+		return fmt.Errorf("%w: %s", ErrInvalidDocument, strings.Join(problems, "; "))
+	}
+	return nil
+}
+
+// Change is one leaf that differs between two documents. Lists are compared as a whole.
+type Change struct {
+	Path string
+	From json.RawMessage
+	To   json.RawMessage
+}
+
+func Diff(from, to []byte) ([]Change, error) {
+	left, err := flattenDocument(from)
+	if err != nil {
+		return nil, err
+	}
+	right, err := flattenDocument(to)
+	if err != nil {
+		return nil, err
+	}
+
+	paths := make([]string, 0, len(left)+len(right))
+	for path := range left {
+		paths = append(paths, path)
+	}
+	for path := range right {
+		if _, ok := left[path]; !ok {
+			paths = append(paths, path)
+		}
+	}
+	sort.Strings(paths)
+
+	changes := make([]Change, 0)
+	for _, path := range paths {
+		before, after := left[path], right[path]
+		if bytes.Equal(before, after) {
+			continue
+		}
+		changes = append(changes, Change{Path: path, From: before, To: after})
+	}
+	return changes, nil
+}
+
+func flattenDocument(document []byte) (map[string]json.RawMessage, error) {
+	out := make(map[string]json.RawMessage)
+	if len(bytes.TrimSpace(document)) == 0 {
+		return out, nil
+	}
+
+	var tree map[string]any
+	if err := json.Unmarshal(document, &tree); err != nil {
+		return nil, fmt.Errorf("decode remote config document: %w", err)
+	}
+	if err := flattenInto(out, "", tree); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func flattenInto(out map[string]json.RawMessage, prefix string, value any) error {
+	if nested, ok := value.(map[string]any); ok && len(nested) > 0 {
+		for key, child := range nested {
+			path := key
+			if prefix != "" {
+				path = prefix + "." + key
+			}
+			if err := flattenInto(out, path, child); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	raw, err := json.Marshal(value)
+	if err != nil {
+		return fmt.Errorf("encode remote config value %s: %w", prefix, err)
+	}
+	out[prefix] = raw
+	return nil
+}
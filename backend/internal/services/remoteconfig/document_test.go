@@ -0,0 +1,131 @@
+package remoteconfig
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ivankudzin/tgapp/backend/internal/config"
+)
+
+func TestEncodeDecodeDocumentRoundTrip(t *testing.T) {
+	cfg := config.Default().Remote
+
+	document, err := EncodeDocument(cfg)
+	if err != nil {
+		t.Fatalf("encode: %v", err)
+	}
+	if !strings.Contains(string(document), `"free_likes_per_day":35`) || !strings.Contains(string(document), `"duration":"30m0s"`) {
+		t.Fatalf("unexpected canonical document: %s", document)
+	}
+
+	decoded, err := DecodeDocument(document)
+	if err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+	if decoded.Boost.Duration != 30*time.Minute || len(decoded.Cities) != len(cfg.Cities) || decoded.MeDefaults.Timezone != cfg.MeDefaults.Timezone {
+		t.Fatalf("round trip mismatch: %+v", decoded)
+	}
+
+	again, err := EncodeDocument(decoded)
+	if err != nil {
+		t.Fatalf("encode again: %v", err)
+	}
+	if Checksum(again) != Checksum(document) {
+		t.Fatalf("checksum is not stable across round trip")
+	}
+}
+
+func TestDecodeDocumentRejectsUnknownFields(t *testing.T) {
+	document, err := EncodeDocument(config.Default().Remote)
+	if err != nil {
+		t.Fatalf("encode: %v", err)
+	}
+	typo := strings.Replace(string(document), `"free_likes_per_day"`, `"free_like_per_day"`, 1)
+
+	if _, err := DecodeDocument([]byte(typo)); !errors.Is(err, ErrInvalidDocument) {
+		t.Fatalf("expected ErrInvalidDocument for unknown key, got %v", err)
+	}
+	if _, err := DecodeDocument([]byte("  ")); !errors.Is(err, ErrInvalidDocument) {
+		t.Fatalf("expected ErrInvalidDocument for empty document, got %v", err)
+	}
+}
+
+func TestValidateReportsAllProblems(t *testing.T) {
+	cfg := config.Default().Remote
+	cfg.Limits.FreeLikesPerDay = 0
+	cfg.Filters.AgeMin = 40
+	cfg.Filters.AgeMax = 30
+	cfg.AntiAbuse.CooldownStepsSec = []int{60, 30}
+	cfg.MeDefaults.CityID = "paris"
+
+	err := Validate(cfg)
+	if !errors.Is(err, ErrInvalidDocument) {
+		t.Fatalf("expected ErrInvalidDocument, got %v", err)
+	}
+	for _, want := range []string{"free_likes_per_day", "age_max", "cooldown_steps_sec", "me_defaults.city_id"} {
+		if !strings.Contains(err.Error(), want) {
+			t.Fatalf("expected %q in %v", want, err)
+		}
+	}
+
+	if err := Validate(config.Default().Remote); err != nil {
+		t.Fatalf("default remote config must be valid: %v", err)
+	}
+}
+
+func TestValidateBalanceSection(t *testing.T) {
+	cfg := config.Default().Remote
+	cfg.Balance = config.BalanceConfig{}
+	if err := Validate(cfg); err != nil {
+		t.Fatalf("documents without a balance section must stay valid: %v", err)
+	}
+
+	cfg.Balance = config.Default().Remote.Balance
+	cfg.Balance.Enabled = true
+	cfg.Balance.Tolerance = 0.6
+	cfg.Balance.Targets = []config.BalanceTargetConfig{
+		{CityID: "minsk", FemaleShare: 0.45},
+		{CityID: "paris", FemaleShare: 0.5},
+		{CityID: "minsk", FemaleShare: 1},
+	}
+	err := Validate(cfg)
+	if !errors.Is(err, ErrInvalidDocument) {
+		t.Fatalf("expected ErrInvalidDocument, got %v", err)
+	}
+	for _, want := range []string{"balance.tolerance", `"paris" is not listed`, "is duplicated", "targets[2].female_share"} {
+		if !strings.Contains(err.Error(), want) {
+			t.Fatalf("expected %q in %v", want, err)
+		}
+	}
+}
+
+func TestDiffReportsLeafChanges(t *testing.T) {
+	base := config.Default().Remote
+	next := base
+	next.Limits.FreeLikesPerDay = 50
+	next.AntiAbuse.CooldownStepsSec = []int{30, 60}
+
+	from, _ := EncodeDocument(base)
+	to, _ := EncodeDocument(next)
+
+	changes, err := Diff(from, to)
+	if err != nil {
+		t.Fatalf("diff: %v", err)
+	}
+	if len(changes) != 2 {
+		t.Fatalf("expected 2 changes, got %+v", changes)
+	}
+	if changes[0].Path != "antiabuse.cooldown_steps_sec" || string(changes[0].To) != "[30,60]" {
+		t.Fatalf("unexpected first change: %+v", changes[0])
+	}
+	if changes[1].Path != "limits.free_likes_per_day" || string(changes[1].From) != "35" || string(changes[1].To) != "50" {
+		t.Fatalf("unexpected second change: %+v", changes[1])
+	}
+
+	same, err := Diff(from, from)
+	if err != nil || len(same) != 0 {
+		t.Fatalf("expected no changes, got %+v err=%v", same, err)
+	}
+}
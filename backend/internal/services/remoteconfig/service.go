@@ -0,0 +1,477 @@
+package remoteconfig
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/ivankudzin/tgapp/backend/internal/config"
+	pgrepo "github.com/ivankudzin/tgapp/backend/internal/repo/postgres"
+)
+
+var (
+	ErrUnavailable     = errors.New("remote config store is not configured")
+	ErrVersionNotFound = errors.New("remote config version not found")
+	ErrVersionConflict = errors.New("remote config was changed since the base version")
+	ErrNoChanges       = errors.New("remote config document has no changes")
+)
+
+const (
+	defaultListLimit = 20
+	maxListLimit     = 100
+	maxCommentLen    = 500
+
+	// reloadInterval is a safety net for instances that missed a pub/sub notification.
+	reloadInterval   = time.Minute
+	resubscribeDelay = 5 * time.Second
+
+	seedComment = "seeded from config file"
+)
+
+type Store interface {
+	LatestRemoteConfig(ctx context.Context) (pgrepo.RemoteConfigVersionRecord, error)
+	GetRemoteConfig(ctx context.Context, version int64) (pgrepo.RemoteConfigVersionRecord, error)
+	ListRemoteConfigVersions(ctx context.Context, limit, offset int) ([]pgrepo.RemoteConfigVersionRecord, error)
+	InsertRemoteConfig(ctx context.Context, in pgrepo.RemoteConfigVersionRecord, expectedLatest *int64) (pgrepo.RemoteConfigVersionRecord, error)
+}
+
+type Bus interface {
+	PublishRemoteConfig(ctx context.Context, version int64) error
+	SubscribeRemoteConfig(ctx context.Context, fn func(version int64)) error
+}
+
+// Snapshot is the config an instance is currently serving. Version 0 means the config file
+// is served as is because no published version has been loaded.
+type Snapshot struct {
+	Version     int64
+	Checksum    string
+	Document    []byte
+	Config      config.RemoteConfig
+	PublishedAt time.Time
+}
+
+func (s Snapshot) ETag() string {
+	checksum := s.Checksum
+	if len(checksum) > 16 {
+		checksum = checksum[:16]
+	}
+	return fmt.Sprintf(`"%d-%s"`, s.Version, checksum)
+}
+
+type Version struct {
+	Version                int64
+	Checksum               string
+	Comment                string
+	RollbackOf             *int64
+	PublishedByAdminUserID *int64
+	PublishedByTGID        *int64
+	PublishedAt            time.Time
+	Document               json.RawMessage
+}
+
+type Publisher struct {
+	AdminUserID int64
+	TGID        int64
+}
+
+type PublishInput struct {
+	Document json.RawMessage
+	Comment  string
+	// BaseVersion, when set, makes the publish fail with ErrVersionConflict if someone
+	// else published in the meantime.
+	BaseVersion *int64
+	DryRun      bool
+	By          Publisher
+}
+
+type PublishResult struct {
+	Version Version
+	Changes []Change
+	DryRun  bool
+}
+
+type Service struct {
+	store Store
+	bus   Bus
+
+	fallback config.RemoteConfig
+
+	mu        sync.RWMutex
+	current   Snapshot
+	listeners []func(Snapshot)
+}
+
+func NewService(fallback config.RemoteConfig) *Service {
+	return &Service{
+		fallback: fallback,
+		current:  StaticSnapshot(fallback),
+	}
+}
+
+// StaticSnapshot wraps a config that is not backed by a stored version.
+func StaticSnapshot(cfg config.RemoteConfig) Snapshot {
+	snapshot := Snapshot{Config: cfg}
+	if document, err := EncodeDocument(cfg); err == nil {
+		snapshot.Document = document
+		snapshot.Checksum = Checksum(document)
+	}
+	return snapshot
+}
+
+func (s *Service) AttachStore(store Store) {
+	s.store = store
+}
+
+func (s *Service) AttachBus(bus Bus) {
+	s.bus = bus
+}
+
+func (s *Service) Current() Snapshot {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.current
+}
+
+// OnChange registers fn to be called with every newly applied snapshot.
+func (s *Service) OnChange(fn func(Snapshot)) {
+	if fn == nil {
+		return
+	}
+	s.mu.Lock()
+	s.listeners = append(s.listeners, fn)
+	s.mu.Unlock()
+}
+
+// Bootstrap loads the latest published version. An empty table is seeded with the config
+// file as version 1 so the history always starts from what was actually running.
+func (s *Service) Bootstrap(ctx context.Context) error {
+	if s.store == nil {
+		return ErrUnavailable
+	}
+
+	record, err := s.store.LatestRemoteConfig(ctx)
+	if errors.Is(err, pgrepo.ErrRemoteConfigNotFound) {
+		document, encodeErr := EncodeDocument(s.fallback)
+		if encodeErr != nil {
+			return encodeErr
+		}
+		empty := int64(0)
+		record, err = s.store.InsertRemoteConfig(ctx, pgrepo.RemoteConfigVersionRecord{
+			Document: document,
+			Checksum: Checksum(document),
+			Comment:  seedComment,
+		}, &empty)
+		if errors.Is(err, pgrepo.ErrRemoteConfigConflict) {
+			// Another instance seeded first.
+			record, err = s.store.LatestRemoteConfig(ctx)
+		}
+	}
+	if err != nil {
+		return err
+	}
+
+	snapshot, err := snapshotFromRecord(record)
+	if err != nil {
+		return err
+	}
+	s.apply(snapshot)
+	return nil
+}
+
+// Reload applies the latest stored version if it is newer than the one being served.
+func (s *Service) Reload(ctx context.Context) error {
+	if s.store == nil {
+		return ErrUnavailable
+	}
+
+	record, err := s.store.LatestRemoteConfig(ctx)
+	if errors.Is(err, pgrepo.ErrRemoteConfigNotFound) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	if record.Version <= s.Current().Version {
+		return nil
+	}
+
+	snapshot, err := snapshotFromRecord(record)
+	if err != nil {
+		return err
+	}
+	s.apply(snapshot)
+	return nil
+}
+
+// Listen keeps the instance in sync until ctx is cancelled: it reloads on every bus
+// notification and periodically in case a notification was missed.
+func (s *Service) Listen(ctx context.Context, onError func(error)) {
+	if s.store == nil {
+		return
+	}
+	report := func(err error) {
+		if err != nil && onError != nil && ctx.Err() == nil {
+			onError(err)
+		}
+	}
+
+	if s.bus != nil {
+		go func() {
+			for ctx.Err() == nil {
+				err := s.bus.SubscribeRemoteConfig(ctx, func(version int64) {
+					if version > s.Current().Version {
+						report(s.Reload(ctx))
+					}
+				})
+				report(err)
+
+				select {
+				case <-ctx.Done():
+				case <-time.After(resubscribeDelay):
+				}
+			}
+		}()
+	}
+
+	ticker := time.NewTicker(reloadInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			report(s.Reload(ctx))
+		}
+	}
+}
+
+func (s *Service) ListVersions(ctx context.Context, limit, offset int) ([]Version, error) {
+	if s.store == nil {
+		return nil, ErrUnavailable
+	}
+	if limit <= 0 {
+		limit = defaultListLimit
+	}
+	if limit > maxListLimit {
+		limit = maxListLimit
+	}
+	if offset < 0 {
+		offset = 0
+	}
+
+	records, err := s.store.ListRemoteConfigVersions(ctx, limit, offset)
+	if err != nil {
+		return nil, err
+	}
+
+	items := make([]Version, 0, len(records))
+	for _, record := range records {
+		items = append(items, versionFromRecord(record))
+	}
+	return items, nil
+}
+
+func (s *Service) GetVersion(ctx context.Context, version int64) (Version, error) {
+	record, err := s.getRecord(ctx, version)
+	if err != nil {
+		return Version{}, err
+	}
+	return versionFromRecord(record), nil
+}
+
+// Diff compares two stored versions; to = 0 compares against the version being served.
+func (s *Service) Diff(ctx context.Context, from, to int64) ([]Change, error) {
+	left, err := s.getRecord(ctx, from)
+	if err != nil {
+		return nil, err
+	}
+
+	right := s.Current().Document
+	if to > 0 {
+		record, err := s.getRecord(ctx, to)
+		if err != nil {
+			return nil, err
+		}
+		right = record.Document
+	}
+	return Diff(left.Document, right)
+}
+
+func (s *Service) Publish(ctx context.Context, in PublishInput) (PublishResult, error) {
+	cfg, err := DecodeDocument(in.Document)
+	if err != nil {
+		return PublishResult{}, err
+	}
+	return s.publish(ctx, cfg, in.Comment, nil, in.BaseVersion, in.DryRun, in.By)
+}
+
+// Rollback re-publishes the document of an older version as a new version.
+func (s *Service) Rollback(ctx context.Context, version int64, comment string, by Publisher) (PublishResult, error) {
+	record, err := s.getRecord(ctx, version)
+	if err != nil {
+		return PublishResult{}, err
+	}
+	cfg, err := DecodeDocument(record.Document)
+	if err != nil {
+		return PublishResult{}, err
+	}
+
+	if strings.TrimSpace(comment) == "" {
+		comment = fmt.Sprintf("rollback to v%d", version)
+	}
+	return s.publish(ctx, cfg, comment, &version, nil, false, by)
+}
+
+func (s *Service) publish(
+	ctx context.Context,
+	cfg config.RemoteConfig,
+	comment string,
+	rollbackOf *int64,
+	baseVersion *int64,
+	dryRun bool,
+	by Publisher,
+) (PublishResult, error) {
+	if s.store == nil {
+		return PublishResult{}, ErrUnavailable
+	}
+
+	comment = strings.TrimSpace(comment)
+	if len([]rune(comment)) > maxCommentLen {
+		return PublishResult{}, fmt.Errorf("%w: comment is too long", ErrInvalidDocument)
+	}
+
+	document, err := EncodeDocument(cfg)
+	if err != nil {
+		return PublishResult{}, err
+	}
+
+	// Diff against what is stored, not what this instance serves: it may not have reloaded yet.
+	latest := s.Current().Document
+	record, err := s.store.LatestRemoteConfig(ctx)
+	switch {
+	case err == nil:
+		latest = record.Document
+	case !errors.Is(err, pgrepo.ErrRemoteConfigNotFound):
+		return PublishResult{}, err
+	}
+	changes, err := Diff(latest, document)
+	if err != nil {
+		return PublishResult{}, err
+	}
+	if len(changes) == 0 {
+		return PublishResult{}, ErrNoChanges
+	}
+
+	result := PublishResult{
+		Version: Version{
+			Checksum:   Checksum(document),
+			Comment:    comment,
+			RollbackOf: rollbackOf,
+			Document:   document,
+		},
+		Changes: changes,
+		DryRun:  dryRun,
+	}
+	if dryRun {
+		return result, nil
+	}
+
+	record, err = s.store.InsertRemoteConfig(ctx, pgrepo.RemoteConfigVersionRecord{
+		Document:               document,
+		Checksum:               result.Version.Checksum,
+		Comment:                comment,
+		RollbackOf:             rollbackOf,
+		PublishedByAdminUserID: optionalID(by.AdminUserID),
+		PublishedByTGID:        optionalID(by.TGID),
+	}, baseVersion)
+	if errors.Is(err, pgrepo.ErrRemoteConfigConflict) {
+		return PublishResult{}, ErrVersionConflict
+	}
+	if err != nil {
+		return PublishResult{}, err
+	}
+
+	s.apply(Snapshot{
+		Version:     record.Version,
+		Checksum:    record.Checksum,
+		Document:    document,
+		Config:      cfg,
+		PublishedAt: record.PublishedAt,
+	})
+	if s.bus != nil {
+		// Other instances also poll every reloadInterval, so a lost notification only delays them.
+		_ = s.bus.PublishRemoteConfig(ctx, record.Version)
+	}
+
+	result.Version = versionFromRecord(record)
+	result.Version.Document = document
+	return result, nil
+}
+
+func (s *Service) getRecord(ctx context.Context, version int64) (pgrepo.RemoteConfigVersionRecord, error) {
+	if s.store == nil {
+		return pgrepo.RemoteConfigVersionRecord{}, ErrUnavailable
+	}
+	if version <= 0 {
+		return pgrepo.RemoteConfigVersionRecord{}, ErrVersionNotFound
+	}
+
+	record, err := s.store.GetRemoteConfig(ctx, version)
+	if errors.Is(err, pgrepo.ErrRemoteConfigNotFound) {
+		return pgrepo.RemoteConfigVersionRecord{}, ErrVersionNotFound
+	}
+	return record, err
+}
+
+func (s *Service) apply(snapshot Snapshot) {
+	s.mu.Lock()
+	if snapshot.Version <= s.current.Version {
+		s.mu.Unlock()
+		return
+	}
+	s.current = snapshot
+	listeners := append([]func(Snapshot){}, s.listeners...)
+	s.mu.Unlock()
+
+	for _, fn := range listeners {
+		fn(snapshot)
+	}
+}
+
+func snapshotFromRecord(record pgrepo.RemoteConfigVersionRecord) (Snapshot, error) {
+	cfg, err := DecodeDocument(record.Document)
+	if err != nil {
+		return Snapshot{}, fmt.Errorf("load remote config v%d: %w", record.Version, err)
+	}
+	return Snapshot{
+		Version:     record.Version,
+		Checksum:    record.Checksum,
+		Document:    record.Document,
+		Config:      cfg,
+		PublishedAt: record.PublishedAt,
+	}, nil
+}
+
+func versionFromRecord(record pgrepo.RemoteConfigVersionRecord) Version {
+	return Version{
+		Version:                record.Version,
+		Checksum:               record.Checksum,
+		Comment:                record.Comment,
+		RollbackOf:             record.RollbackOf,
+		PublishedByAdminUserID: record.PublishedByAdminUserID,
+		PublishedByTGID:        record.PublishedByTGID,
+		PublishedAt:            record.PublishedAt,
+		Document:               record.Document,
+	}
+}
+
+func optionalID(id int64) *int64 {
+	if id <= 0 {
+		return nil
+	}
+	return &id
+}
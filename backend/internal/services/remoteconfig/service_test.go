@@ -0,0 +1,236 @@
+package remoteconfig
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ivankudzin/tgapp/backend/internal/config"
+	pgrepo "github.com/ivankudzin/tgapp/backend/internal/repo/postgres"
+)
+
+func TestBootstrapSeedsConfigFileAsFirstVersion(t *testing.T) {
+	store := &remoteConfigStoreStub{}
+	svc := NewService(config.Default().Remote)
+	svc.AttachStore(store)
+
+	if err := svc.Bootstrap(context.Background()); err != nil {
+		t.Fatalf("bootstrap: %v", err)
+	}
+	if len(store.records) != 1 || store.records[0].Comment != seedComment {
+		t.Fatalf("expected seeded version, got %+v", store.records)
+	}
+	current := svc.Current()
+	if current.Version != 1 || current.Checksum != store.records[0].Checksum {
+		t.Fatalf("unexpected current snapshot: %+v", current)
+	}
+}
+
+func TestPublishAppliesNotifiesAndBroadcasts(t *testing.T) {
+	store := &remoteConfigStoreStub{}
+	bus := &remoteConfigBusStub{}
+	svc := NewService(config.Default().Remote)
+	svc.AttachStore(store)
+	svc.AttachBus(bus)
+	ctx := context.Background()
+	if err := svc.Bootstrap(ctx); err != nil {
+		t.Fatalf("bootstrap: %v", err)
+	}
+
+	var notified []int64
+	svc.OnChange(func(s Snapshot) { notified = append(notified, s.Version) })
+
+	next := config.Default().Remote
+	next.Limits.FreeLikesPerDay = 50
+	document := mustEncode(t, next)
+
+	dry, err := svc.Publish(ctx, PublishInput{Document: document, DryRun: true})
+	if err != nil {
+		t.Fatalf("dry run: %v", err)
+	}
+	if !dry.DryRun || len(dry.Changes) != 1 || len(store.records) != 1 || len(notified) != 0 {
+		t.Fatalf("dry run must not persist: %+v records=%d", dry, len(store.records))
+	}
+
+	stale := int64(0)
+	if _, err := svc.Publish(ctx, PublishInput{Document: document, BaseVersion: &stale}); !errors.Is(err, ErrVersionConflict) {
+		t.Fatalf("expected ErrVersionConflict, got %v", err)
+	}
+
+	result, err := svc.Publish(ctx, PublishInput{Document: document, Comment: "more likes", By: Publisher{AdminUserID: 7}})
+	if err != nil {
+		t.Fatalf("publish: %v", err)
+	}
+	if result.Version.Version != 2 || *store.records[1].PublishedByAdminUserID != 7 {
+		t.Fatalf("unexpected publish result: %+v", result.Version)
+	}
+	if svc.Current().Config.Limits.FreeLikesPerDay != 50 {
+		t.Fatalf("published config was not applied")
+	}
+	if len(notified) != 1 || notified[0] != 2 || len(bus.published) != 1 || bus.published[0] != 2 {
+		t.Fatalf("unexpected notifications: listeners=%v bus=%v", notified, bus.published)
+	}
+
+	if _, err := svc.Publish(ctx, PublishInput{Document: document}); !errors.Is(err, ErrNoChanges) {
+		t.Fatalf("expected ErrNoChanges, got %v", err)
+	}
+}
+
+func TestRollbackPublishesOldDocumentAsNewVersion(t *testing.T) {
+	store := &remoteConfigStoreStub{}
+	svc := NewService(config.Default().Remote)
+	svc.AttachStore(store)
+	ctx := context.Background()
+	if err := svc.Bootstrap(ctx); err != nil {
+		t.Fatalf("bootstrap: %v", err)
+	}
+
+	next := config.Default().Remote
+	next.Filters.AgeMax = 45
+	if _, err := svc.Publish(ctx, PublishInput{Document: mustEncode(t, next)}); err != nil {
+		t.Fatalf("publish: %v", err)
+	}
+
+	result, err := svc.Rollback(ctx, 1, "", Publisher{TGID: 42})
+	if err != nil {
+		t.Fatalf("rollback: %v", err)
+	}
+	if result.Version.Version != 3 || result.Version.RollbackOf == nil || *result.Version.RollbackOf != 1 || result.Version.Comment != "rollback to v1" {
+		t.Fatalf("unexpected rollback version: %+v", result.Version)
+	}
+	if svc.Current().Config.Filters.AgeMax != 30 {
+		t.Fatalf("rollback was not applied")
+	}
+
+	if _, err := svc.Rollback(ctx, 99, "", Publisher{}); !errors.Is(err, ErrVersionNotFound) {
+		t.Fatalf("expected ErrVersionNotFound, got %v", err)
+	}
+}
+
+func TestReloadPicksUpVersionsPublishedElsewhere(t *testing.T) {
+	store := &remoteConfigStoreStub{}
+	svc := NewService(config.Default().Remote)
+	svc.AttachStore(store)
+	ctx := context.Background()
+	if err := svc.Bootstrap(ctx); err != nil {
+		t.Fatalf("bootstrap: %v", err)
+	}
+
+	other := NewService(config.Default().Remote)
+	other.AttachStore(store)
+	if err := other.Bootstrap(ctx); err != nil {
+		t.Fatalf("bootstrap other: %v", err)
+	}
+	next := config.Default().Remote
+	next.AdsInject.FreeEvery = 9
+	if _, err := other.Publish(ctx, PublishInput{Document: mustEncode(t, next)}); err != nil {
+		t.Fatalf("publish: %v", err)
+	}
+
+	if err := svc.Reload(ctx); err != nil {
+		t.Fatalf("reload: %v", err)
+	}
+	if current := svc.Current(); current.Version != 2 || current.Config.AdsInject.FreeEvery != 9 {
+		t.Fatalf("unexpected snapshot after reload: version=%d", current.Version)
+	}
+}
+
+func TestPublishDiffsAgainstLatestStoredVersion(t *testing.T) {
+	store := &remoteConfigStoreStub{}
+	svc := NewService(config.Default().Remote)
+	svc.AttachStore(store)
+	ctx := context.Background()
+	if err := svc.Bootstrap(ctx); err != nil {
+		t.Fatalf("bootstrap: %v", err)
+	}
+
+	other := NewService(config.Default().Remote)
+	other.AttachStore(store)
+	if err := other.Bootstrap(ctx); err != nil {
+		t.Fatalf("bootstrap other: %v", err)
+	}
+	next := config.Default().Remote
+	next.AdsInject.FreeEvery = 9
+	if _, err := other.Publish(ctx, PublishInput{Document: mustEncode(t, next)}); err != nil {
+		t.Fatalf("publish: %v", err)
+	}
+
+	// svc still serves v1, but v2 already holds this document.
+	if _, err := svc.Publish(ctx, PublishInput{Document: mustEncode(t, next), DryRun: true}); !errors.Is(err, ErrNoChanges) {
+		t.Fatalf("expected ErrNoChanges against v2, got %v", err)
+	}
+
+	next.Limits.FreeLikesPerDay = 50
+	dry, err := svc.Publish(ctx, PublishInput{Document: mustEncode(t, next), DryRun: true})
+	if err != nil {
+		t.Fatalf("dry run: %v", err)
+	}
+	if len(dry.Changes) != 1 || !strings.Contains(dry.Changes[0].Path, "free_likes_per_day") {
+		t.Fatalf("expected only the likes change against v2, got %+v", dry.Changes)
+	}
+}
+
+func mustEncode(t *testing.T, cfg config.RemoteConfig) json.RawMessage {
+	t.Helper()
+	document, err := EncodeDocument(cfg)
+	if err != nil {
+		t.Fatalf("encode: %v", err)
+	}
+	return document
+}
+
+type remoteConfigStoreStub struct {
+	records []pgrepo.RemoteConfigVersionRecord
+}
+
+func (s *remoteConfigStoreStub) LatestRemoteConfig(context.Context) (pgrepo.RemoteConfigVersionRecord, error) {
+	if len(s.records) == 0 {
+		return pgrepo.RemoteConfigVersionRecord{}, pgrepo.ErrRemoteConfigNotFound
+	}
+	return s.records[len(s.records)-1], nil
+}
+
+func (s *remoteConfigStoreStub) GetRemoteConfig(_ context.Context, version int64) (pgrepo.RemoteConfigVersionRecord, error) {
+	for _, record := range s.records {
+		if record.Version == version {
+			return record, nil
+		}
+	}
+	return pgrepo.RemoteConfigVersionRecord{}, pgrepo.ErrRemoteConfigNotFound
+}
+
+func (s *remoteConfigStoreStub) ListRemoteConfigVersions(_ context.Context, limit, offset int) ([]pgrepo.RemoteConfigVersionRecord, error) {
+	out := make([]pgrepo.RemoteConfigVersionRecord, 0, len(s.records))
+	for i := len(s.records) - 1 - offset; i >= 0 && len(out) < limit; i-- {
+		out = append(out, s.records[i])
+	}
+	return out, nil
+}
+
+func (s *remoteConfigStoreStub) InsertRemoteConfig(_ context.Context, in pgrepo.RemoteConfigVersionRecord, expectedLatest *int64) (pgrepo.RemoteConfigVersionRecord, error) {
+	latest := int64(len(s.records))
+	if expectedLatest != nil && *expectedLatest != latest {
+		return pgrepo.RemoteConfigVersionRecord{}, pgrepo.ErrRemoteConfigConflict
+	}
+	in.Version = latest + 1
+	in.PublishedAt = time.Now().UTC()
+	s.records = append(s.records, in)
+	return in, nil
+}
+
+type remoteConfigBusStub struct {
+	published []int64
+}
+
+func (b *remoteConfigBusStub) PublishRemoteConfig(_ context.Context, version int64) error {
+	b.published = append(b.published, version)
+	return nil
+}
+
+func (b *remoteConfigBusStub) SubscribeRemoteConfig(ctx context.Context, _ func(version int64)) error {
+	<-ctx.Done()
+	return nil
+}
@@ -6,6 +6,7 @@ import (
 	"fmt"
 	"log"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/jackc/pgx/v5"
@@ -163,6 +164,10 @@ type DailyMetricsStore interface {
 	Increment(ctx context.Context, userID int64, at time.Time, delta pgrepo.DailyMetricsDelta) error
 }
 
+type LikeLimiter interface {
+	FreeLikesLimit(ctx context.Context, userID int64, base int) int
+}
+
 type SwipeClientTelemetry struct {
 	CardViewMS    int
 	SwipeVelocity *float64
@@ -205,6 +210,8 @@ type Service struct {
 	telemetry    TelemetryService
 	devices      DeviceRegistry
 	dailyMetrics DailyMetricsStore
+	likeLimiter  LikeLimiter
+	cfgMu        sync.RWMutex
 	cfg          Config
 	now          func() time.Time
 }
@@ -223,6 +230,37 @@ type Dependencies struct {
 }
 
 func NewService(deps Dependencies, cfg Config) *Service {
+	return &Service{
+		pool:         deps.Pool,
+		swipeStore:   deps.SwipeStore,
+		likeStore:    deps.LikeStore,
+		matchStore:   deps.MatchStore,
+		quotaStore:   deps.QuotaStore,
+		entitlements: deps.Entitlements,
+		rateLimiter:  deps.RateLimiter,
+		quotaView:    deps.QuotaView,
+		antiAbuse:    deps.AntiAbuse,
+		telemetry:    deps.Telemetry,
+		cfg:          normalizeConfig(cfg),
+		now:          time.Now,
+	}
+}
+
+// Reconfigure swaps limits and anti-abuse thresholds after a remote config publish.
+func (s *Service) Reconfigure(cfg Config) {
+	cfg = normalizeConfig(cfg)
+	s.cfgMu.Lock()
+	s.cfg = cfg
+	s.cfgMu.Unlock()
+}
+
+func (s *Service) config() Config {
+	s.cfgMu.RLock()
+	defer s.cfgMu.RUnlock()
+	return s.cfg
+}
+
+func normalizeConfig(cfg Config) Config {
 	if cfg.FreeLikesPerDay <= 0 {
 		cfg.FreeLikesPerDay = rules.FreeLikesPerDay
 	}
@@ -247,21 +285,7 @@ func NewService(deps Dependencies, cfg Config) *Service {
 	if cfg.NewDeviceCooldownSec <= 0 {
 		cfg.NewDeviceCooldownSec = 30
 	}
-
-	return &Service{
-		pool:         deps.Pool,
-		swipeStore:   deps.SwipeStore,
-		likeStore:    deps.LikeStore,
-		matchStore:   deps.MatchStore,
-		quotaStore:   deps.QuotaStore,
-		entitlements: deps.Entitlements,
-		rateLimiter:  deps.RateLimiter,
-		quotaView:    deps.QuotaView,
-		antiAbuse:    deps.AntiAbuse,
-		telemetry:    deps.Telemetry,
-		cfg:          cfg,
-		now:          time.Now,
-	}
+	return cfg
 }
 
 func (s *Service) AttachDevices(devices DeviceRegistry) {
@@ -272,6 +296,12 @@ func (s *Service) AttachDailyMetrics(store DailyMetricsStore) {
 	s.dailyMetrics = store
 }
 
+// AttachLikeLimiter scales the free daily likes per user; it should be the one attached to the
+// likes service so quota snapshots and consumption agree.
+func (s *Service) AttachLikeLimiter(limiter LikeLimiter) {
+	s.likeLimiter = limiter
+}
+
 func (s *Service) Swipe(ctx context.Context, userID, targetID int64, action, timezone, sid, ip, deviceID string, client SwipeClientTelemetry) (SwipeResult, error) {
 	if userID <= 0 || targetID <= 0 || userID == targetID {
 		return SwipeResult{}, ErrValidation
@@ -310,6 +340,11 @@ func (s *Service) Swipe(ctx context.Context, userID, targetID int64, action, tim
 		return SwipeResult{}, err
 	}
 
+	freeLikes := s.config().FreeLikesPerDay
+	if !isPlus && s.likeLimiter != nil && normalizedAction != actionDislike {
+		freeLikes = s.likeLimiter.FreeLikesLimit(ctx, userID, freeLikes)
+	}
+
 	matchCreated := false
 	if err := pgrepo.WithTx(ctx, s.pool, func(txCtx context.Context, tx pgx.Tx) error {
 		switch normalizedAction {
@@ -324,7 +359,7 @@ func (s *Service) Swipe(ctx context.Context, userID, targetID int64, action, tim
 			}
 
 			if !isPlus {
-				if _, err := s.quotaStore.ConsumeLikeWithLimit(txCtx, tx, userID, dayKey, tzName, s.cfg.FreeLikesPerDay); err != nil {
+				if _, err := s.quotaStore.ConsumeLikeWithLimit(txCtx, tx, userID, dayKey, tzName, freeLikes); err != nil {
 					if errors.Is(err, pgrepo.ErrLikesLimitReached) {
 						return likessvc.ErrDailyLimit
 					}
@@ -415,9 +450,9 @@ func (s *Service) Rewind(ctx context.Context, userID int64, timezone string) (Re
 		return RewindResult{}, err
 	}
 
-	rewindLimit := s.cfg.FreeRewindsPerDay
+	rewindLimit := s.config().FreeRewindsPerDay
 	if isPlus {
-		rewindLimit = s.cfg.PlusRewindsPerDay
+		rewindLimit = s.config().PlusRewindsPerDay
 	}
 
 	var undone pgrepo.SwipeRecord
@@ -515,13 +550,13 @@ func (s *Service) resolvePlus(ctx context.Context, userID int64, at time.Time) (
 	if isPlus {
 		return true, nil
 	}
-	return s.cfg.DefaultIsPlus, nil
+	return s.config().DefaultIsPlus, nil
 }
 
 func (s *Service) resolveTimezone(explicit string) (*time.Location, string) {
 	candidate := strings.TrimSpace(explicit)
 	if candidate == "" {
-		candidate = strings.TrimSpace(s.cfg.DefaultTimezone)
+		candidate = strings.TrimSpace(s.config().DefaultTimezone)
 	}
 	if candidate == "" {
 		candidate = "UTC"
@@ -538,7 +573,7 @@ func (s *Service) applyLowCardViewViolation(ctx context.Context, userID, targetI
 	if action != actionLike && action != actionSuperLike {
 		return
 	}
-	if client.CardViewMS <= 0 || client.CardViewMS >= s.cfg.MinCardViewMS {
+	if client.CardViewMS <= 0 || client.CardViewMS >= s.config().MinCardViewMS {
 		return
 	}
 
@@ -551,7 +586,7 @@ func (s *Service) applyLowCardViewViolation(ctx context.Context, userID, targetI
 			"action":           action,
 			"target_id":        targetID,
 			"card_view_ms":     client.CardViewMS,
-			"min_card_view_ms": s.cfg.MinCardViewMS,
+			"min_card_view_ms": s.config().MinCardViewMS,
 		}
 		if client.SwipeVelocity != nil {
 			props["swipe_velocity"] = *client.SwipeVelocity
@@ -638,7 +673,7 @@ func (s *Service) handleDeviceRegistration(ctx context.Context, userID int64, de
 
 	state := antiabusesvc.State{}
 	if s.antiAbuse != nil {
-		state, err = s.antiAbuse.ApplyViolationWithCooldown(ctx, userID, s.cfg.NewDeviceRiskWeight, s.cfg.NewDeviceCooldownSec, now)
+		state, err = s.antiAbuse.ApplyViolationWithCooldown(ctx, userID, s.config().NewDeviceRiskWeight, s.config().NewDeviceCooldownSec, now)
 		if err != nil {
 			return antiabusesvc.State{}, err
 		}
@@ -665,8 +700,8 @@ func (s *Service) logNewDeviceEvent(ctx context.Context, userID int64, deviceID
 			TS:   now.UTC().UnixMilli(),
 			Props: map[string]any{
 				"device_id":               deviceID,
-				"new_device_risk_weight":  s.cfg.NewDeviceRiskWeight,
-				"new_device_cooldown_sec": s.cfg.NewDeviceCooldownSec,
+				"new_device_risk_weight":  s.config().NewDeviceRiskWeight,
+				"new_device_cooldown_sec": s.config().NewDeviceCooldownSec,
 				"risk_score":              state.RiskScore,
 				"cooldown_until_ts":       cooldownUntilTS,
 			},
@@ -675,10 +710,10 @@ func (s *Service) logNewDeviceEvent(ctx context.Context, userID int64, deviceID
 }
 
 func (s *Service) shouldMarkLikeAsSuspect(state antiabusesvc.State) bool {
-	if s.cfg.SuspectLikeThreshold <= 0 {
+	if s.config().SuspectLikeThreshold <= 0 {
 		return false
 	}
-	return state.RiskScore >= s.cfg.SuspectLikeThreshold
+	return state.RiskScore >= s.config().SuspectLikeThreshold
 }
 
 func (s *Service) logSuspectLikeEvent(ctx context.Context, userID, targetID int64, action string, isSuspectLike bool, riskScore int, now time.Time) {
@@ -693,7 +728,7 @@ func (s *Service) logSuspectLikeEvent(ctx context.Context, userID, targetID int6
 		"action":                 action,
 		"target_id":              targetID,
 		"risk_score":             riskScore,
-		"suspect_like_threshold": s.cfg.SuspectLikeThreshold,
+		"suspect_like_threshold": s.config().SuspectLikeThreshold,
 	}
 
 	uid := userID
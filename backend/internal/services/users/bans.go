@@ -0,0 +1,391 @@
+package users
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+
+	pgrepo "github.com/ivankudzin/tgapp/backend/internal/repo/postgres"
+)
+
+const (
+	BanReasonSpam        = "SPAM"
+	BanReasonScam        = "SCAM"
+	BanReasonHarassment  = "HARASSMENT"
+	BanReasonFakeProfile = "FAKE_PROFILE"
+	BanReasonUnderage    = "UNDERAGE"
+	BanReasonExplicit    = "EXPLICIT_CONTENT"
+	BanReasonBanEvasion  = "BAN_EVASION"
+	BanReasonOther       = "OTHER"
+)
+
+const (
+	BanDuration24h       = "24h"
+	BanDuration7d        = "7d"
+	BanDuration30d       = "30d"
+	BanDurationPermanent = "permanent"
+)
+
+const (
+	EvasionSignalDevice = "DEVICE"
+	EvasionSignalPhone  = "PHONE"
+	EvasionSignalPhoto  = "PHOTO"
+)
+
+const (
+	banEvasionMatchLimit  = 20
+	banUnmatchedStatus    = "unmatched_ban"
+	banReasonOtherDefault = "BANNED_BY_ADMIN"
+)
+
+// linkedAccountsCTE yields (linked_user_id, signal) rows for accounts sharing a device,
+// phone hash or near-duplicate photo with $1.
+const linkedAccountsCTE = `
+WITH linked AS (
+	SELECT other.user_id AS linked_user_id, '` + EvasionSignalDevice + `' AS signal
+	FROM user_devices mine
+	JOIN user_devices other ON other.device_id = mine.device_id AND other.user_id <> mine.user_id
+	WHERE mine.user_id = $1
+	UNION
+	SELECT other.user_id, '` + EvasionSignalPhone + `'
+	FROM user_private mine
+	JOIN user_private other ON other.phone_hash = mine.phone_hash AND other.user_id <> mine.user_id
+	WHERE mine.user_id = $1
+	  AND mine.phone_hash <> ''
+	UNION
+	SELECT hm.matched_user_id, '` + EvasionSignalPhoto + `'
+	FROM media_hash_matches hm
+	JOIN media m ON m.id = hm.media_id
+	WHERE m.user_id = $1
+	  AND hm.source = 'USER'
+	  AND hm.matched_user_id IS NOT NULL
+	  AND hm.matched_user_id <> $1
+	UNION
+	SELECT m.user_id, '` + EvasionSignalPhoto + `'
+	FROM media_hash_matches hm
+	JOIN media m ON m.id = hm.media_id
+	WHERE hm.matched_user_id = $1
+	  AND hm.source = 'USER'
+	  AND m.user_id <> $1
+)`
+
+var banReasonCodes = []string{
+	BanReasonSpam,
+	BanReasonScam,
+	BanReasonHarassment,
+	BanReasonFakeProfile,
+	BanReasonUnderage,
+	BanReasonExplicit,
+	BanReasonBanEvasion,
+	BanReasonOther,
+}
+
+var banDurations = map[string]time.Duration{
+	BanDuration24h:       24 * time.Hour,
+	BanDuration7d:        7 * 24 * time.Hour,
+	BanDuration30d:       30 * 24 * time.Hour,
+	BanDurationPermanent: 0,
+}
+
+type SessionRevoker interface {
+	DeleteAllForUser(ctx context.Context, userID int64) error
+}
+
+type BanInput struct {
+	UserID     int64
+	ReasonCode string
+	Reason     string
+	Duration   string
+	ActorTGID  int64
+}
+
+type BanState struct {
+	Banned     bool
+	ReasonCode string
+	Reason     string
+	BannedAt   *time.Time
+	ExpiresAt  *time.Time
+}
+
+type BanResult struct {
+	BanState
+	UnmatchedCount  int
+	SessionsRevoked bool
+}
+
+type EvasionMatch struct {
+	BannedUserID int64
+	BannedTGID   int64
+	Signals      []string
+}
+
+func BanReasonCodes() []string {
+	return append([]string(nil), banReasonCodes...)
+}
+
+func NormalizeBanReasonCode(raw string) (string, error) {
+	code := strings.ToUpper(strings.TrimSpace(raw))
+	if code == "" {
+		return BanReasonOther, nil
+	}
+	for _, known := range banReasonCodes {
+		if code == known {
+			return code, nil
+		}
+	}
+	return "", fmt.Errorf("%w: unknown ban reason code %q", ErrValidation, raw)
+}
+
+// ParseBanDuration maps the bot/admin duration presets to a TTL; zero means permanent.
+func ParseBanDuration(raw string) (time.Duration, error) {
+	key := strings.ToLower(strings.TrimSpace(raw))
+	if key == "" {
+		return 0, nil
+	}
+	ttl, ok := banDurations[key]
+	if !ok {
+		return 0, fmt.Errorf("%w: unknown ban duration %q", ErrValidation, raw)
+	}
+	return ttl, nil
+}
+
+func (s *Service) AttachSessions(sessions SessionRevoker) {
+	s.sessions = sessions
+}
+
+// Ban records the ban, ends the user's active matches and revokes app sessions.
+// Feeds and likes exclude the user through the in-force ban predicate until expires_at.
+func (s *Service) Ban(ctx context.Context, in BanInput) (BanResult, error) {
+	if in.UserID <= 0 || in.ActorTGID == 0 {
+		return BanResult{}, ErrValidation
+	}
+	code, err := NormalizeBanReasonCode(in.ReasonCode)
+	if err != nil {
+		return BanResult{}, err
+	}
+	ttl, err := ParseBanDuration(in.Duration)
+	if err != nil {
+		return BanResult{}, err
+	}
+	reason := strings.TrimSpace(in.Reason)
+	if reason == "" && code == BanReasonOther {
+		reason = banReasonOtherDefault
+	}
+
+	now := s.now().UTC()
+	result := BanResult{BanState: BanState{
+		Banned:     true,
+		ReasonCode: code,
+		Reason:     reason,
+		BannedAt:   &now,
+	}}
+	if ttl > 0 {
+		expiresAt := now.Add(ttl)
+		result.ExpiresAt = &expiresAt
+	}
+	if s.pool == nil {
+		return result, nil
+	}
+
+	exists, err := s.userExists(ctx, in.UserID)
+	if err != nil {
+		return BanResult{}, err
+	}
+	if !exists {
+		return BanResult{}, ErrNotFound
+	}
+
+	tx, err := s.pool.Begin(ctx)
+	if err != nil {
+		return BanResult{}, fmt.Errorf("begin ban transaction: %w", err)
+	}
+	defer func() {
+		_ = tx.Rollback(ctx)
+	}()
+
+	if _, err := tx.Exec(ctx, `
+INSERT INTO user_bans (user_id, banned, reason_code, reason, banned_at, expires_at, updated_by_tg_id, updated_at)
+VALUES ($1::uuid, TRUE, $2, NULLIF($3, ''), $4, $5, $6, $4)
+ON CONFLICT (user_id) DO UPDATE SET
+	banned = TRUE,
+	reason_code = EXCLUDED.reason_code,
+	reason = EXCLUDED.reason,
+	banned_at = EXCLUDED.banned_at,
+	expires_at = EXCLUDED.expires_at,
+	updated_by_tg_id = EXCLUDED.updated_by_tg_id,
+	updated_at = EXCLUDED.updated_at
+`, pseudoUUID(in.UserID), code, reason, now, result.ExpiresAt, in.ActorTGID); err != nil {
+		return BanResult{}, fmt.Errorf("upsert user ban: %w", err)
+	}
+
+	tag, err := tx.Exec(ctx, `
+UPDATE matches
+SET status = $2
+WHERE (user_a_id = $1 OR user_b_id = $1)
+  AND COALESCE(status, 'active') = 'active'
+`, in.UserID, banUnmatchedStatus)
+	if err != nil {
+		return BanResult{}, fmt.Errorf("unmatch banned user: %w", err)
+	}
+	result.UnmatchedCount = int(tag.RowsAffected())
+
+	if err := tx.Commit(ctx); err != nil {
+		return BanResult{}, fmt.Errorf("commit ban transaction: %w", err)
+	}
+
+	// The ban is already committed; a failed revoke is only reported so the caller can retry the ban.
+	if s.sessions != nil {
+		result.SessionsRevoked = s.sessions.DeleteAllForUser(ctx, in.UserID) == nil
+	}
+	return result, nil
+}
+
+func (s *Service) Unban(ctx context.Context, userID int64, updatedByTGID int64) error {
+	if s.pool == nil {
+		return nil
+	}
+	if userID <= 0 || updatedByTGID == 0 {
+		return ErrValidation
+	}
+	exists, err := s.userExists(ctx, userID)
+	if err != nil {
+		return err
+	}
+	if !exists {
+		return ErrNotFound
+	}
+
+	if _, err := s.pool.Exec(ctx, `
+INSERT INTO user_bans (user_id, banned, reason_code, reason, banned_at, expires_at, updated_by_tg_id, updated_at)
+VALUES ($1::uuid, FALSE, NULL, NULL, NULL, NULL, $2, NOW())
+ON CONFLICT (user_id) DO UPDATE SET
+	banned = FALSE,
+	reason_code = NULL,
+	reason = NULL,
+	banned_at = NULL,
+	expires_at = NULL,
+	updated_by_tg_id = EXCLUDED.updated_by_tg_id,
+	updated_at = EXCLUDED.updated_at
+`, pseudoUUID(userID), updatedByTGID); err != nil {
+		return fmt.Errorf("clear user ban: %w", err)
+	}
+	return nil
+}
+
+// GetBanState reports an expired temporary ban as not banned.
+func (s *Service) GetBanState(ctx context.Context, userID int64) (BanState, error) {
+	if s.pool == nil || userID <= 0 {
+		return BanState{}, nil
+	}
+
+	var (
+		banned     bool
+		reasonCode sql.NullString
+		reason     sql.NullString
+		bannedAt   *time.Time
+		expiresAt  *time.Time
+	)
+	err := s.pool.QueryRow(ctx, `
+SELECT banned, reason_code, reason, banned_at, expires_at
+FROM user_bans
+WHERE user_id = $1::uuid
+LIMIT 1
+`, pseudoUUID(userID)).Scan(&banned, &reasonCode, &reason, &bannedAt, &expiresAt)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return BanState{}, nil
+		}
+		return BanState{}, fmt.Errorf("lookup user ban state: %w", err)
+	}
+
+	return resolveBanState(banned, reasonCode.String, reason.String, bannedAt, expiresAt, s.now().UTC()), nil
+}
+
+func resolveBanState(banned bool, reasonCode, reason string, bannedAt, expiresAt *time.Time, now time.Time) BanState {
+	if !banned || (expiresAt != nil && !expiresAt.After(now)) {
+		return BanState{}
+	}
+	state := BanState{
+		Banned:     true,
+		ReasonCode: strings.TrimSpace(reasonCode),
+		Reason:     strings.TrimSpace(reason),
+	}
+	if bannedAt != nil {
+		value := bannedAt.UTC()
+		state.BannedAt = &value
+	}
+	if expiresAt != nil {
+		value := expiresAt.UTC()
+		state.ExpiresAt = &value
+	}
+	return state
+}
+
+// FindBanEvasion lists currently banned accounts older than userID that share a device,
+// phone hash or near-duplicate photo with it.
+func (s *Service) FindBanEvasion(ctx context.Context, userID int64) ([]EvasionMatch, error) {
+	if s.pool == nil || userID <= 0 {
+		return nil, nil
+	}
+
+	rows, err := s.pool.Query(ctx, linkedAccountsCTE+`
+SELECT l.linked_user_id, banned_user.telegram_id, l.signal
+FROM linked l
+JOIN users banned_user ON banned_user.id = l.linked_user_id
+JOIN users suspect ON suspect.id = $1
+WHERE banned_user.created_at < suspect.created_at
+  AND `+pgrepo.ActiveBanExists("l.linked_user_id")+`
+ORDER BY l.linked_user_id ASC, l.signal ASC
+`, userID)
+	if err != nil {
+		return nil, fmt.Errorf("find ban evasion links: %w", err)
+	}
+	defer rows.Close()
+
+	byUser := make(map[int64]*EvasionMatch)
+	for rows.Next() {
+		var (
+			bannedUserID int64
+			bannedTGID   int64
+			signal       string
+		)
+		if err := rows.Scan(&bannedUserID, &bannedTGID, &signal); err != nil {
+			return nil, fmt.Errorf("scan ban evasion link: %w", err)
+		}
+		match, ok := byUser[bannedUserID]
+		if !ok {
+			match = &EvasionMatch{BannedUserID: bannedUserID, BannedTGID: bannedTGID}
+			byUser[bannedUserID] = match
+		}
+		match.Signals = append(match.Signals, signal)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterate ban evasion links: %w", err)
+	}
+
+	return collectEvasionMatches(byUser), nil
+}
+
+// collectEvasionMatches orders the strongest links (most shared signals) first.
+func collectEvasionMatches(byUser map[int64]*EvasionMatch) []EvasionMatch {
+	items := make([]EvasionMatch, 0, len(byUser))
+	for _, match := range byUser {
+		items = append(items, *match)
+	}
+	sort.Slice(items, func(i, j int) bool {
+		if len(items[i].Signals) != len(items[j].Signals) {
+			return len(items[i].Signals) > len(items[j].Signals)
+		}
+		return items[i].BannedUserID < items[j].BannedUserID
+	})
+	if len(items) > banEvasionMatchLimit {
+		items = items[:banEvasionMatchLimit]
+	}
+	return items
+}
@@ -0,0 +1,94 @@
+package users
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestParseBanDurationPresets(t *testing.T) {
+	cases := map[string]time.Duration{
+		"":          0,
+		"24h":       24 * time.Hour,
+		"7D":        7 * 24 * time.Hour,
+		"30d":       30 * 24 * time.Hour,
+		"permanent": 0,
+	}
+	for raw, want := range cases {
+		got, err := ParseBanDuration(raw)
+		if err != nil {
+			t.Fatalf("parse %q: %v", raw, err)
+		}
+		if got != want {
+			t.Fatalf("parse %q: got=%s want=%s", raw, got, want)
+		}
+	}
+	if _, err := ParseBanDuration("1y"); !errors.Is(err, ErrValidation) {
+		t.Fatalf("expected ErrValidation for unknown duration, got %v", err)
+	}
+}
+
+func TestNormalizeBanReasonCode(t *testing.T) {
+	if code, err := NormalizeBanReasonCode(" spam "); err != nil || code != BanReasonSpam {
+		t.Fatalf("unexpected normalized code: %q %v", code, err)
+	}
+	if code, err := NormalizeBanReasonCode(""); err != nil || code != BanReasonOther {
+		t.Fatalf("expected OTHER for empty code, got %q %v", code, err)
+	}
+	if _, err := NormalizeBanReasonCode("RUDE"); !errors.Is(err, ErrValidation) {
+		t.Fatalf("expected ErrValidation for unknown code, got %v", err)
+	}
+}
+
+func TestBanComputesExpiryWithoutPool(t *testing.T) {
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	svc := NewService(nil, nil, nil)
+	svc.now = func() time.Time { return now }
+
+	result, err := svc.Ban(context.Background(), BanInput{UserID: 10, ReasonCode: "scam", Duration: "7d", ActorTGID: 1})
+	if err != nil {
+		t.Fatalf("ban: %v", err)
+	}
+	if !result.Banned || result.ReasonCode != BanReasonScam {
+		t.Fatalf("unexpected ban result: %+v", result)
+	}
+	if result.ExpiresAt == nil || !result.ExpiresAt.Equal(now.Add(7*24*time.Hour)) {
+		t.Fatalf("unexpected expires_at: %v", result.ExpiresAt)
+	}
+
+	permanent, err := svc.Ban(context.Background(), BanInput{UserID: 10, Duration: "permanent", ActorTGID: 1})
+	if err != nil {
+		t.Fatalf("permanent ban: %v", err)
+	}
+	if permanent.ExpiresAt != nil || permanent.ReasonCode != BanReasonOther || permanent.Reason == "" {
+		t.Fatalf("unexpected permanent ban: %+v", permanent)
+	}
+}
+
+func TestResolveBanStateTreatsExpiredBanAsLifted(t *testing.T) {
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	past := now.Add(-time.Minute)
+	future := now.Add(time.Hour)
+
+	if state := resolveBanState(true, "SPAM", "", nil, &past, now); state.Banned {
+		t.Fatalf("expected expired ban to be lifted: %+v", state)
+	}
+	if state := resolveBanState(true, "SPAM", " spam bot ", nil, &future, now); !state.Banned || state.Reason != "spam bot" || state.ExpiresAt == nil {
+		t.Fatalf("expected active temporary ban: %+v", state)
+	}
+	if state := resolveBanState(true, "OTHER", "", nil, nil, now); !state.Banned || state.ExpiresAt != nil {
+		t.Fatalf("expected active permanent ban: %+v", state)
+	}
+}
+
+func TestCollectEvasionMatchesOrdersBySignalCount(t *testing.T) {
+	items := collectEvasionMatches(map[int64]*EvasionMatch{
+		3: {BannedUserID: 3, Signals: []string{EvasionSignalPhoto}},
+		7: {BannedUserID: 7, Signals: []string{EvasionSignalDevice, EvasionSignalPhone}},
+		5: {BannedUserID: 5, Signals: []string{EvasionSignalDevice}},
+	})
+	if len(items) != 3 || items[0].BannedUserID != 7 || items[1].BannedUserID != 3 || items[2].BannedUserID != 5 {
+		t.Fatalf("unexpected evasion order: %+v", items)
+	}
+}
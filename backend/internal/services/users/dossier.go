@@ -0,0 +1,659 @@
+package users
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	antiabusesvc "github.com/ivankudzin/tgapp/backend/internal/services/antiabuse"
+)
+
+const (
+	DossierSectionOverview   = "overview"
+	DossierSectionReports    = "reports"
+	DossierSectionDevices    = "devices"
+	DossierSectionPurchases  = "purchases"
+	DossierSectionModeration = "moderation"
+	DossierSectionRisk       = "risk"
+	DossierSectionSupport    = "support"
+)
+
+const (
+	defaultDossierLimit = 10
+	maxDossierLimit     = 50
+	dossierActivityDays = 7
+)
+
+var dossierSections = []string{
+	DossierSectionOverview,
+	DossierSectionReports,
+	DossierSectionDevices,
+	DossierSectionPurchases,
+	DossierSectionModeration,
+	DossierSectionRisk,
+	DossierSectionSupport,
+}
+
+// riskEventNames are the anti-abuse telemetry events that make up a user's cooldown history;
+// Redis only keeps the current risk state.
+var riskEventNames = []string{
+	"antiabuse_cooldown_applied",
+	"antiabuse_too_fast",
+	"antiabuse_shadow_enabled",
+	"antiabuse_suspect_like",
+	"antiabuse_new_device",
+	"antiabuse_low_card_view",
+}
+
+type RiskSource interface {
+	GetState(ctx context.Context, userID int64) (antiabusesvc.State, error)
+}
+
+// SupportHistory lists support conversations opened by a user, newest first, with the total count.
+type SupportHistory interface {
+	ListUserConversations(ctx context.Context, userID int64, limit, offset int) ([]DossierSupportConversation, int, error)
+}
+
+type DossierQuery struct {
+	UserID  int64
+	Section string
+	Limit   int
+	Offset  int
+}
+
+// Dossier holds the sections requested by DossierQuery; sections that were not requested stay nil.
+type Dossier struct {
+	UserID     int64
+	Section    string
+	Limit      int
+	Offset     int
+	Risk       *DossierRisk
+	Activity   *DossierActivity
+	Reports    *DossierReports
+	Devices    *DossierDevices
+	Purchases  *DossierPurchases
+	Moderation *DossierModeration
+	Support    *DossierSupport
+}
+
+type DossierRisk struct {
+	Available       bool
+	RiskScore       int
+	CooldownUntil   *time.Time
+	LastViolationAt *time.Time
+	ShadowEnabled   bool
+	Events          []DossierRiskEvent
+	EventsTotal     int
+}
+
+type DossierRiskEvent struct {
+	Name       string
+	OccurredAt time.Time
+	Payload    map[string]any
+}
+
+type DossierActivity struct {
+	Days             int
+	Likes            int
+	Superlikes       int
+	Dislikes         int
+	LikeRatio        float64
+	SegmentLikeRatio float64
+	LikesReceived    int
+	Swipes1h         int
+	Swipes24h        int
+	TooFastHits      int
+}
+
+type DossierReports struct {
+	Received      []DossierReport
+	ReceivedTotal int
+	Filed         []DossierReport
+	FiledTotal    int
+}
+
+type DossierReport struct {
+	ID                int64
+	CounterpartUserID int64
+	Reason            string
+	Details           string
+	Status            string
+	CreatedAt         time.Time
+}
+
+type DossierDevices struct {
+	Devices        []DossierDevice
+	Total          int
+	LinkedAccounts []DossierLinkedAccount
+}
+
+type DossierDevice struct {
+	DeviceID    string
+	FirstSeenAt time.Time
+	LastSeenAt  time.Time
+	IsBlocked   bool
+	SharedWith  int
+}
+
+type DossierLinkedAccount struct {
+	UserID  int64
+	TGID    int64
+	Signals []string
+	Banned  bool
+}
+
+type DossierPurchases struct {
+	Items []DossierPurchase
+	Total int
+}
+
+type DossierPurchase struct {
+	ID          int64
+	SKU         string
+	Provider    string
+	Status      string
+	AmountMinor int64
+	Currency    string
+	CreatedAt   time.Time
+}
+
+type DossierModeration struct {
+	Items []DossierModerationDecision
+	Total int
+}
+
+type DossierModerationDecision struct {
+	ModerationItemID int64
+	TargetType       string
+	Status           string
+	ReasonCode       string
+	ModeratorTGID    *int64
+	CreatedAt        time.Time
+	DecidedAt        *time.Time
+	ReviewKind       string
+	ReviewStatus     string
+}
+
+type DossierSupport struct {
+	Available bool
+	Items     []DossierSupportConversation
+	Total     int
+}
+
+type DossierSupportConversation struct {
+	ID            int64
+	Status        string
+	MessagesCount int
+	LastMessage   string
+	CreatedAt     time.Time
+	UpdatedAt     time.Time
+}
+
+func (s *Service) AttachRisk(risk RiskSource) {
+	s.risk = risk
+}
+
+func (s *Service) AttachSupportHistory(support SupportHistory) {
+	s.support = support
+}
+
+func DossierSections() []string {
+	return append([]string(nil), dossierSections...)
+}
+
+func normalizeDossierQuery(q DossierQuery) (DossierQuery, error) {
+	if q.UserID <= 0 {
+		return DossierQuery{}, ErrValidation
+	}
+	q.Section = strings.ToLower(strings.TrimSpace(q.Section))
+	if q.Section != "" {
+		known := false
+		for _, section := range dossierSections {
+			if q.Section == section {
+				known = true
+				break
+			}
+		}
+		if !known {
+			return DossierQuery{}, fmt.Errorf("%w: unknown dossier section %q", ErrValidation, q.Section)
+		}
+	}
+	if q.Limit <= 0 {
+		q.Limit = defaultDossierLimit
+	}
+	if q.Limit > maxDossierLimit {
+		q.Limit = maxDossierLimit
+	}
+	if q.Offset < 0 {
+		q.Offset = 0
+	}
+	return q, nil
+}
+
+// Dossier loads one section (or all when Section is empty); Limit/Offset page every list in it.
+func (s *Service) Dossier(ctx context.Context, q DossierQuery) (Dossier, error) {
+	q, err := normalizeDossierQuery(q)
+	if err != nil {
+		return Dossier{}, err
+	}
+	if s.pool == nil {
+		return Dossier{}, ErrNotFound
+	}
+	exists, err := s.userExists(ctx, q.UserID)
+	if err != nil {
+		return Dossier{}, err
+	}
+	if !exists {
+		return Dossier{}, ErrNotFound
+	}
+
+	out := Dossier{UserID: q.UserID, Section: q.Section, Limit: q.Limit, Offset: q.Offset}
+	want := func(section string) bool {
+		return q.Section == "" || q.Section == section
+	}
+
+	if want(DossierSectionOverview) || want(DossierSectionRisk) {
+		risk := s.dossierRiskState(ctx, q.UserID)
+		if want(DossierSectionRisk) {
+			risk.Events, risk.EventsTotal, err = s.dossierRiskEvents(ctx, q)
+			if err != nil {
+				return Dossier{}, err
+			}
+		}
+		out.Risk = &risk
+	}
+	if want(DossierSectionOverview) {
+		activity, err := s.dossierActivity(ctx, q.UserID)
+		if err != nil {
+			return Dossier{}, err
+		}
+		out.Activity = &activity
+	}
+	if want(DossierSectionReports) {
+		reports, err := s.dossierReports(ctx, q)
+		if err != nil {
+			return Dossier{}, err
+		}
+		out.Reports = &reports
+	}
+	if want(DossierSectionDevices) {
+		devices, err := s.dossierDevices(ctx, q)
+		if err != nil {
+			return Dossier{}, err
+		}
+		out.Devices = &devices
+	}
+	if want(DossierSectionPurchases) {
+		purchases, err := s.dossierPurchases(ctx, q)
+		if err != nil {
+			return Dossier{}, err
+		}
+		out.Purchases = &purchases
+	}
+	if want(DossierSectionModeration) {
+		moderation, err := s.dossierModeration(ctx, q)
+		if err != nil {
+			return Dossier{}, err
+		}
+		out.Moderation = &moderation
+	}
+	if want(DossierSectionSupport) {
+		support := DossierSupport{}
+		if s.support != nil {
+			support.Items, support.Total, err = s.support.ListUserConversations(ctx, q.UserID, q.Limit, q.Offset)
+			if err != nil {
+				return Dossier{}, fmt.Errorf("list support conversations: %w", err)
+			}
+			support.Available = true
+		}
+		out.Support = &support
+	}
+
+	return out, nil
+}
+
+// dossierRiskState degrades to Available=false when Redis is unreachable so the rest of the dossier still loads.
+func (s *Service) dossierRiskState(ctx context.Context, userID int64) DossierRisk {
+	if s.risk == nil {
+		return DossierRisk{}
+	}
+	state, err := s.risk.GetState(ctx, userID)
+	if err != nil {
+		return DossierRisk{}
+	}
+	return DossierRisk{
+		Available:       true,
+		RiskScore:       state.RiskScore,
+		CooldownUntil:   state.CooldownUntil,
+		LastViolationAt: state.LastViolationAt,
+		ShadowEnabled:   state.ShadowEnabled,
+	}
+}
+
+func (s *Service) dossierRiskEvents(ctx context.Context, q DossierQuery) ([]DossierRiskEvent, int, error) {
+	rows, err := s.pool.Query(ctx, `
+SELECT name, occurred_at, payload, COUNT(*) OVER ()
+FROM events
+WHERE user_id = $1
+  AND name = ANY($2::text[])
+ORDER BY occurred_at DESC, id DESC
+LIMIT $3 OFFSET $4
+`, q.UserID, riskEventNames, q.Limit, q.Offset)
+	if err != nil {
+		return nil, 0, fmt.Errorf("list dossier risk events: %w", err)
+	}
+	defer rows.Close()
+
+	items := make([]DossierRiskEvent, 0, q.Limit)
+	total := 0
+	for rows.Next() {
+		var (
+			item    DossierRiskEvent
+			payload []byte
+		)
+		if err := rows.Scan(&item.Name, &item.OccurredAt, &payload, &total); err != nil {
+			return nil, 0, fmt.Errorf("scan dossier risk event: %w", err)
+		}
+		item.OccurredAt = item.OccurredAt.UTC()
+		if len(payload) > 0 {
+			_ = json.Unmarshal(payload, &item.Payload)
+		}
+		items = append(items, item)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, 0, fmt.Errorf("iterate dossier risk events: %w", err)
+	}
+	return items, total, nil
+}
+
+// dossierActivity compares the user's own like ratio with their segment's ratio in daily_metrics.
+func (s *Service) dossierActivity(ctx context.Context, userID int64) (DossierActivity, error) {
+	now := s.now().UTC()
+	since := now.AddDate(0, 0, -dossierActivityDays)
+	activity := DossierActivity{Days: dossierActivityDays}
+
+	err := s.pool.QueryRow(ctx, `
+SELECT
+	COUNT(*) FILTER (WHERE s.actor_user_id = $1 AND s.action = 'LIKE' AND s.created_at >= $2),
+	COUNT(*) FILTER (WHERE s.actor_user_id = $1 AND s.action = 'SUPERLIKE' AND s.created_at >= $2),
+	COUNT(*) FILTER (WHERE s.actor_user_id = $1 AND s.action = 'DISLIKE' AND s.created_at >= $2),
+	COUNT(*) FILTER (WHERE s.target_user_id = $1 AND s.action IN ('LIKE', 'SUPERLIKE') AND s.created_at >= $2),
+	COUNT(*) FILTER (WHERE s.actor_user_id = $1 AND s.created_at >= $3),
+	COUNT(*) FILTER (WHERE s.actor_user_id = $1 AND s.created_at >= $4)
+FROM swipes s
+WHERE (s.actor_user_id = $1 OR s.target_user_id = $1)
+  AND s.created_at >= $2
+`, userID, since, now.Add(-time.Hour), now.Add(-24*time.Hour)).Scan(
+		&activity.Likes,
+		&activity.Superlikes,
+		&activity.Dislikes,
+		&activity.LikesReceived,
+		&activity.Swipes1h,
+		&activity.Swipes24h,
+	)
+	if err != nil {
+		return DossierActivity{}, fmt.Errorf("load dossier swipe activity: %w", err)
+	}
+	activity.LikeRatio = likeRatio(activity.Likes+activity.Superlikes, activity.Dislikes)
+
+	if err := s.pool.QueryRow(ctx, `
+SELECT COALESCE(SUM(too_fast_hits), 0)
+FROM quotas_daily
+WHERE user_id = $1
+  AND day_key >= $2::date
+`, userID, since).Scan(&activity.TooFastHits); err != nil {
+		return DossierActivity{}, fmt.Errorf("load dossier too-fast hits: %w", err)
+	}
+
+	var segmentLikes, segmentDislikes int64
+	if err := s.pool.QueryRow(ctx, `
+SELECT COALESCE(SUM(dm.likes + dm.superlikes), 0), COALESCE(SUM(dm.dislikes), 0)
+FROM profiles p
+JOIN daily_metrics dm
+  ON dm.city_id = COALESCE(NULLIF(TRIM(p.city_id), ''), 'unknown')
+ AND dm.gender = COALESCE(NULLIF(LOWER(TRIM(p.gender)), ''), 'unknown')
+ AND dm.looking_for = COALESCE(NULLIF(LOWER(TRIM(p.looking_for)), ''), 'unknown')
+WHERE p.user_id = $1
+  AND dm.day_key >= $2::date
+`, userID, since).Scan(&segmentLikes, &segmentDislikes); err != nil {
+		return DossierActivity{}, fmt.Errorf("load dossier segment metrics: %w", err)
+	}
+	activity.SegmentLikeRatio = likeRatio(int(segmentLikes), int(segmentDislikes))
+
+	return activity, nil
+}
+
+func likeRatio(likes, dislikes int) float64 {
+	total := likes + dislikes
+	if total <= 0 {
+		return 0
+	}
+	return float64(likes) / float64(total)
+}
+
+func (s *Service) dossierReports(ctx context.Context, q DossierQuery) (DossierReports, error) {
+	received, receivedTotal, err := s.listDossierReports(ctx, q, "target_user_id", "reporter_user_id")
+	if err != nil {
+		return DossierReports{}, err
+	}
+	filed, filedTotal, err := s.listDossierReports(ctx, q, "reporter_user_id", "target_user_id")
+	if err != nil {
+		return DossierReports{}, err
+	}
+	return DossierReports{
+		Received:      received,
+		ReceivedTotal: receivedTotal,
+		Filed:         filed,
+		FiledTotal:    filedTotal,
+	}, nil
+}
+
+func (s *Service) listDossierReports(ctx context.Context, q DossierQuery, ownColumn, counterpartColumn string) ([]DossierReport, int, error) {
+	rows, err := s.pool.Query(ctx, fmt.Sprintf(`
+SELECT id, %s, reason, COALESCE(details, ''), status, created_at, COUNT(*) OVER ()
+FROM reports
+WHERE %s = $1
+ORDER BY created_at DESC, id DESC
+LIMIT $2 OFFSET $3
+`, counterpartColumn, ownColumn), q.UserID, q.Limit, q.Offset)
+	if err != nil {
+		return nil, 0, fmt.Errorf("list dossier reports: %w", err)
+	}
+	defer rows.Close()
+
+	items := make([]DossierReport, 0, q.Limit)
+	total := 0
+	for rows.Next() {
+		var item DossierReport
+		if err := rows.Scan(&item.ID, &item.CounterpartUserID, &item.Reason, &item.Details, &item.Status, &item.CreatedAt, &total); err != nil {
+			return nil, 0, fmt.Errorf("scan dossier report: %w", err)
+		}
+		item.CreatedAt = item.CreatedAt.UTC()
+		items = append(items, item)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, 0, fmt.Errorf("iterate dossier reports: %w", err)
+	}
+	return items, total, nil
+}
+
+func (s *Service) dossierDevices(ctx context.Context, q DossierQuery) (DossierDevices, error) {
+	rows, err := s.pool.Query(ctx, `
+SELECT
+	d.device_id,
+	d.first_seen_at,
+	d.last_seen_at,
+	d.is_blocked,
+	(SELECT COUNT(*) FROM user_devices o WHERE o.device_id = d.device_id AND o.user_id <> d.user_id),
+	COUNT(*) OVER ()
+FROM user_devices d
+WHERE d.user_id = $1
+ORDER BY d.last_seen_at DESC, d.device_id ASC
+LIMIT $2 OFFSET $3
+`, q.UserID, q.Limit, q.Offset)
+	if err != nil {
+		return DossierDevices{}, fmt.Errorf("list dossier devices: %w", err)
+	}
+	defer rows.Close()
+
+	out := DossierDevices{Devices: make([]DossierDevice, 0, q.Limit)}
+	for rows.Next() {
+		var item DossierDevice
+		if err := rows.Scan(&item.DeviceID, &item.FirstSeenAt, &item.LastSeenAt, &item.IsBlocked, &item.SharedWith, &out.Total); err != nil {
+			return DossierDevices{}, fmt.Errorf("scan dossier device: %w", err)
+		}
+		item.FirstSeenAt = item.FirstSeenAt.UTC()
+		item.LastSeenAt = item.LastSeenAt.UTC()
+		out.Devices = append(out.Devices, item)
+	}
+	if err := rows.Err(); err != nil {
+		return DossierDevices{}, fmt.Errorf("iterate dossier devices: %w", err)
+	}
+
+	out.LinkedAccounts, err = s.linkedAccounts(ctx, q.UserID)
+	if err != nil {
+		return DossierDevices{}, err
+	}
+	return out, nil
+}
+
+// linkedAccounts lists every account sharing a signal with userID, banned or not.
+func (s *Service) linkedAccounts(ctx context.Context, userID int64) ([]DossierLinkedAccount, error) {
+	rows, err := s.pool.Query(ctx, linkedAccountsCTE+`
+SELECT l.linked_user_id, u.telegram_id, l.signal
+FROM linked l
+JOIN users u ON u.id = l.linked_user_id
+ORDER BY l.linked_user_id ASC, l.signal ASC
+`, userID)
+	if err != nil {
+		return nil, fmt.Errorf("list linked accounts: %w", err)
+	}
+	defer rows.Close()
+
+	byUser := make(map[int64]*EvasionMatch)
+	for rows.Next() {
+		var (
+			linkedUserID int64
+			linkedTGID   int64
+			signal       string
+		)
+		if err := rows.Scan(&linkedUserID, &linkedTGID, &signal); err != nil {
+			return nil, fmt.Errorf("scan linked account: %w", err)
+		}
+		match, ok := byUser[linkedUserID]
+		if !ok {
+			match = &EvasionMatch{BannedUserID: linkedUserID, BannedTGID: linkedTGID}
+			byUser[linkedUserID] = match
+		}
+		match.Signals = append(match.Signals, signal)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterate linked accounts: %w", err)
+	}
+
+	matches := collectEvasionMatches(byUser)
+	items := make([]DossierLinkedAccount, 0, len(matches))
+	for _, match := range matches {
+		ban, err := s.GetBanState(ctx, match.BannedUserID)
+		if err != nil {
+			return nil, err
+		}
+		items = append(items, DossierLinkedAccount{
+			UserID:  match.BannedUserID,
+			TGID:    match.BannedTGID,
+			Signals: match.Signals,
+			Banned:  ban.Banned,
+		})
+	}
+	return items, nil
+}
+
+func (s *Service) dossierPurchases(ctx context.Context, q DossierQuery) (DossierPurchases, error) {
+	rows, err := s.pool.Query(ctx, `
+SELECT id, sku, provider, status, COALESCE(amount_minor, 0), COALESCE(currency, ''), created_at, COUNT(*) OVER ()
+FROM purchases
+WHERE user_id = $1
+ORDER BY created_at DESC, id DESC
+LIMIT $2 OFFSET $3
+`, q.UserID, q.Limit, q.Offset)
+	if err != nil {
+		return DossierPurchases{}, fmt.Errorf("list dossier purchases: %w", err)
+	}
+	defer rows.Close()
+
+	out := DossierPurchases{Items: make([]DossierPurchase, 0, q.Limit)}
+	for rows.Next() {
+		var item DossierPurchase
+		if err := rows.Scan(&item.ID, &item.SKU, &item.Provider, &item.Status, &item.AmountMinor, &item.Currency, &item.CreatedAt, &out.Total); err != nil {
+			return DossierPurchases{}, fmt.Errorf("scan dossier purchase: %w", err)
+		}
+		item.CreatedAt = item.CreatedAt.UTC()
+		out.Items = append(out.Items, item)
+	}
+	if err := rows.Err(); err != nil {
+		return DossierPurchases{}, fmt.Errorf("iterate dossier purchases: %w", err)
+	}
+	return out, nil
+}
+
+func (s *Service) dossierModeration(ctx context.Context, q DossierQuery) (DossierModeration, error) {
+	rows, err := s.pool.Query(ctx, `
+SELECT
+	mi.id,
+	mi.target_type,
+	mi.status,
+	COALESCE(mi.reason_code, ''),
+	mi.moderator_tg_id,
+	mi.created_at,
+	mi.decided_at,
+	COALESCE(mr.kind, ''),
+	COALESCE(mr.status, ''),
+	COUNT(*) OVER ()
+FROM moderation_items mi
+LEFT JOIN LATERAL (
+	SELECT r.kind, r.status
+	FROM moderation_reviews r
+	WHERE r.moderation_item_id = mi.id
+	ORDER BY r.created_at DESC, r.id DESC
+	LIMIT 1
+) mr ON TRUE
+WHERE mi.user_id = $1
+ORDER BY mi.created_at DESC, mi.id DESC
+LIMIT $2 OFFSET $3
+`, q.UserID, q.Limit, q.Offset)
+	if err != nil {
+		return DossierModeration{}, fmt.Errorf("list dossier moderation decisions: %w", err)
+	}
+	defer rows.Close()
+
+	out := DossierModeration{Items: make([]DossierModerationDecision, 0, q.Limit)}
+	for rows.Next() {
+		var item DossierModerationDecision
+		if err := rows.Scan(
+			&item.ModerationItemID,
+			&item.TargetType,
+			&item.Status,
+			&item.ReasonCode,
+			&item.ModeratorTGID,
+			&item.CreatedAt,
+			&item.DecidedAt,
+			&item.ReviewKind,
+			&item.ReviewStatus,
+			&out.Total,
+		); err != nil {
+			return DossierModeration{}, fmt.Errorf("scan dossier moderation decision: %w", err)
+		}
+		item.CreatedAt = item.CreatedAt.UTC()
+		if item.DecidedAt != nil {
+			decidedAt := item.DecidedAt.UTC()
+			item.DecidedAt = &decidedAt
+		}
+		out.Items = append(out.Items, item)
+	}
+	if err := rows.Err(); err != nil {
+		return DossierModeration{}, fmt.Errorf("iterate dossier moderation decisions: %w", err)
+	}
+	return out, nil
+}
@@ -0,0 +1,69 @@
+package users
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	antiabusesvc "github.com/ivankudzin/tgapp/backend/internal/services/antiabuse"
+)
+
+type fakeRiskSource struct {
+	state antiabusesvc.State
+	err   error
+}
+
+func (f fakeRiskSource) GetState(context.Context, int64) (antiabusesvc.State, error) {
+	return f.state, f.err
+}
+
+func TestNormalizeDossierQuery(t *testing.T) {
+	q, err := normalizeDossierQuery(DossierQuery{UserID: 7, Section: " Reports ", Limit: 500, Offset: -3})
+	if err != nil {
+		t.Fatalf("normalize: %v", err)
+	}
+	if q.Section != DossierSectionReports || q.Limit != maxDossierLimit || q.Offset != 0 {
+		t.Fatalf("unexpected normalized query: %+v", q)
+	}
+
+	q, err = normalizeDossierQuery(DossierQuery{UserID: 7})
+	if err != nil || q.Limit != defaultDossierLimit || q.Section != "" {
+		t.Fatalf("unexpected default query: %+v %v", q, err)
+	}
+
+	if _, err := normalizeDossierQuery(DossierQuery{UserID: 7, Section: "chats"}); !errors.Is(err, ErrValidation) {
+		t.Fatalf("expected ErrValidation for unknown section, got %v", err)
+	}
+	if _, err := normalizeDossierQuery(DossierQuery{}); !errors.Is(err, ErrValidation) {
+		t.Fatalf("expected ErrValidation for missing user id, got %v", err)
+	}
+}
+
+func TestLikeRatio(t *testing.T) {
+	if got := likeRatio(0, 0); got != 0 {
+		t.Fatalf("expected zero ratio without swipes, got %v", got)
+	}
+	if got := likeRatio(3, 1); got != 0.75 {
+		t.Fatalf("unexpected ratio: %v", got)
+	}
+}
+
+func TestDossierRiskStateDegradesWithoutRedis(t *testing.T) {
+	svc := NewService(nil, nil, nil)
+	if risk := svc.dossierRiskState(context.Background(), 1); risk.Available {
+		t.Fatalf("expected unavailable risk without source: %+v", risk)
+	}
+
+	svc.AttachRisk(fakeRiskSource{err: errors.New("redis down")})
+	if risk := svc.dossierRiskState(context.Background(), 1); risk.Available {
+		t.Fatalf("expected unavailable risk on redis error: %+v", risk)
+	}
+
+	cooldown := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	svc.AttachRisk(fakeRiskSource{state: antiabusesvc.State{RiskScore: 42, CooldownUntil: &cooldown, ShadowEnabled: true, Exists: true}})
+	risk := svc.dossierRiskState(context.Background(), 1)
+	if !risk.Available || risk.RiskScore != 42 || !risk.ShadowEnabled || risk.CooldownUntil == nil || !risk.CooldownUntil.Equal(cooldown) {
+		t.Fatalf("unexpected risk state: %+v", risk)
+	}
+}
@@ -0,0 +1,214 @@
+package users
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	pgrepo "github.com/ivankudzin/tgapp/backend/internal/repo/postgres"
+)
+
+const (
+	defaultSearchLimit = 20
+	maxSearchLimit     = 100
+)
+
+// SearchQuery filters the admin user list; zero values leave a filter off.
+// Query matches a numeric user id or Telegram ID exactly, otherwise a username or display name prefix.
+type SearchQuery struct {
+	Query       string
+	CityID      string
+	Status      string
+	Banned      *bool
+	CreatedFrom *time.Time
+	CreatedTo   *time.Time
+	MinReports  int
+	Limit       int
+	Offset      int
+}
+
+type UserSummary struct {
+	UserID           int64
+	TGID             int64
+	Username         string
+	DisplayName      string
+	CityID           string
+	Gender           string
+	ModerationStatus string
+	Approved         bool
+	IsBanned         bool
+	ReportsCount     int
+	CreatedAt        time.Time
+}
+
+type SearchResult struct {
+	Items []UserSummary
+	// Total counts every match, also when Offset is past the last one.
+	Total  int
+	Limit  int
+	Offset int
+}
+
+func normalizeSearchQuery(q SearchQuery) (SearchQuery, error) {
+	q.Query = strings.TrimSpace(strings.TrimPrefix(strings.TrimSpace(q.Query), "@"))
+	q.CityID = strings.TrimSpace(q.CityID)
+	q.Status = strings.ToLower(strings.TrimSpace(q.Status))
+	if q.CreatedFrom != nil && q.CreatedTo != nil && q.CreatedTo.Before(*q.CreatedFrom) {
+		return SearchQuery{}, fmt.Errorf("%w: created_to is before created_from", ErrValidation)
+	}
+	if q.MinReports < 0 {
+		return SearchQuery{}, fmt.Errorf("%w: min_reports must not be negative", ErrValidation)
+	}
+	if q.Limit <= 0 {
+		q.Limit = defaultSearchLimit
+	}
+	if q.Limit > maxSearchLimit {
+		q.Limit = maxSearchLimit
+	}
+	if q.Offset < 0 {
+		q.Offset = 0
+	}
+	return q, nil
+}
+
+// Search lists users newest first; reports count covers every report filed against the user.
+func (s *Service) Search(ctx context.Context, q SearchQuery) (SearchResult, error) {
+	q, err := normalizeSearchQuery(q)
+	if err != nil {
+		return SearchResult{}, err
+	}
+	if s.pool == nil {
+		return SearchResult{Items: []UserSummary{}, Limit: q.Limit, Offset: q.Offset}, nil
+	}
+
+	conditions := make([]string, 0, 8)
+	args := make([]any, 0, 10)
+	arg := func(value any) string {
+		args = append(args, value)
+		return "$" + strconv.Itoa(len(args))
+	}
+
+	if q.Query != "" {
+		if numeric, err := strconv.ParseInt(q.Query, 10, 64); err == nil {
+			placeholder := arg(numeric)
+			conditions = append(conditions, fmt.Sprintf("(u.id = %s OR u.telegram_id = %s)", placeholder, placeholder))
+		} else {
+			placeholder := arg(escapeLike(strings.ToLower(q.Query)) + "%")
+			conditions = append(conditions, fmt.Sprintf(
+				`(LOWER(u.username) LIKE %s ESCAPE '\' OR LOWER(COALESCE(p.display_name, '')) LIKE %s ESCAPE '\')`,
+				placeholder,
+				placeholder,
+			))
+		}
+	}
+	if q.CityID != "" {
+		conditions = append(conditions, "p.city_id = "+arg(q.CityID))
+	}
+	if q.Status != "" {
+		conditions = append(conditions, "LOWER(COALESCE(p.moderation_status, '')) = "+arg(q.Status))
+	}
+	if q.Banned != nil {
+		if *q.Banned {
+			conditions = append(conditions, pgrepo.ActiveBanExists("u.id"))
+		} else {
+			conditions = append(conditions, "NOT "+pgrepo.ActiveBanExists("u.id"))
+		}
+	}
+	if q.CreatedFrom != nil {
+		conditions = append(conditions, "u.created_at >= "+arg(q.CreatedFrom.UTC()))
+	}
+	if q.CreatedTo != nil {
+		conditions = append(conditions, "u.created_at < "+arg(q.CreatedTo.UTC()))
+	}
+	if q.MinReports > 0 {
+		conditions = append(conditions, "rc.reports_count >= "+arg(q.MinReports))
+	}
+
+	where := ""
+	if len(conditions) > 0 {
+		where = "WHERE " + strings.Join(conditions, "\n  AND ")
+	}
+	from := `
+FROM users u
+LEFT JOIN profiles p ON p.user_id = u.id
+LEFT JOIN LATERAL (
+	SELECT COUNT(*)::int AS reports_count
+	FROM reports r
+	WHERE r.target_user_id = u.id
+) rc ON TRUE
+` + where
+	filterArgs := len(args)
+	limit := arg(q.Limit)
+	offset := arg(q.Offset)
+
+	rows, err := s.pool.Query(ctx, fmt.Sprintf(`
+SELECT u.id,
+       u.telegram_id,
+       COALESCE(u.username, ''),
+       COALESCE(p.display_name, ''),
+       COALESCE(p.city_id, ''),
+       COALESCE(p.gender, ''),
+       COALESCE(p.moderation_status, ''),
+       COALESCE(p.approved, FALSE),
+       %s,
+       rc.reports_count,
+       u.created_at,
+       COUNT(*) OVER ()
+%s
+ORDER BY u.created_at DESC, u.id DESC
+LIMIT %s OFFSET %s
+`, pgrepo.ActiveBanExists("u.id"), from, limit, offset), args...)
+	if err != nil {
+		return SearchResult{}, fmt.Errorf("search users: %w", err)
+	}
+	defer rows.Close()
+
+	result := SearchResult{Items: make([]UserSummary, 0, q.Limit), Limit: q.Limit, Offset: q.Offset}
+	for rows.Next() {
+		var item UserSummary
+		if err := rows.Scan(
+			&item.UserID,
+			&item.TGID,
+			&item.Username,
+			&item.DisplayName,
+			&item.CityID,
+			&item.Gender,
+			&item.ModerationStatus,
+			&item.Approved,
+			&item.IsBanned,
+			&item.ReportsCount,
+			&item.CreatedAt,
+			&result.Total,
+		); err != nil {
+			return SearchResult{}, fmt.Errorf("scan user search row: %w", err)
+		}
+		item.CreatedAt = item.CreatedAt.UTC()
+		result.Items = append(result.Items, item)
+	}
+	if err := rows.Err(); err != nil {
+		return SearchResult{}, fmt.Errorf("iterate user search rows: %w", err)
+	}
+
+	// COUNT(*) OVER () comes with the rows, so a page past the end has to count on its own.
+	if len(result.Items) == 0 && q.Offset > 0 {
+		if err := s.pool.QueryRow(ctx, "SELECT COUNT(*)"+from, args[:filterArgs]...).Scan(&result.Total); err != nil {
+			return SearchResult{}, fmt.Errorf("count user search rows: %w", err)
+		}
+	}
+	return result, nil
+}
+
+// escapeLike makes s match literally inside a LIKE pattern with ESCAPE '\'.
+func escapeLike(s string) string {
+	return strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`).Replace(s)
+}
+
+// GetUser loads the same card the bot lookup returns, by numeric user id only.
+func (s *Service) GetUser(ctx context.Context, userID int64) (LookupUser, error) {
+	if s.pool == nil {
+		return LookupUser{}, ErrNotFound
+	}
+	return s.findByUserID(ctx, userID)
+}
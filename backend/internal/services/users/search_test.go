@@ -0,0 +1,44 @@
+package users
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestNormalizeSearchQuery(t *testing.T) {
+	q, err := normalizeSearchQuery(SearchQuery{Query: " @Alice ", Status: " Approved ", Limit: 500, Offset: -1})
+	if err != nil {
+		t.Fatalf("normalize: %v", err)
+	}
+	if q.Query != "Alice" || q.Status != "approved" || q.Limit != maxSearchLimit || q.Offset != 0 {
+		t.Fatalf("unexpected normalized query: %+v", q)
+	}
+
+	q, err = normalizeSearchQuery(SearchQuery{})
+	if err != nil || q.Limit != defaultSearchLimit {
+		t.Fatalf("unexpected default query: %+v %v", q, err)
+	}
+
+	from := time.Date(2026, 3, 10, 0, 0, 0, 0, time.UTC)
+	to := from.Add(-time.Hour)
+	if _, err := normalizeSearchQuery(SearchQuery{CreatedFrom: &from, CreatedTo: &to}); !errors.Is(err, ErrValidation) {
+		t.Fatalf("expected ErrValidation for inverted range, got %v", err)
+	}
+	if _, err := normalizeSearchQuery(SearchQuery{MinReports: -1}); !errors.Is(err, ErrValidation) {
+		t.Fatalf("expected ErrValidation for negative min_reports, got %v", err)
+	}
+}
+
+func TestEscapeLike(t *testing.T) {
+	for in, want := range map[string]string{
+		"alice":      "alice",
+		"100%":       `100\%`,
+		"a_b":        `a\_b`,
+		`back\slash`: `back\\slash`,
+	} {
+		if got := escapeLike(in); got != want {
+			t.Fatalf("escapeLike(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
@@ -52,6 +52,9 @@ type LookupUser struct {
 	LikeTokens       int
 	IsBanned         bool
 	BanReason        string
+	BanReasonCode    string
+	BanExpiresAt     *time.Time
+	EvasionMatches   []EvasionMatch
 }
 
 type PrivateUser struct {
@@ -66,6 +69,9 @@ type Service struct {
 	pool      *pgxpool.Pool
 	media     *pgrepo.MediaRepo
 	urlSigner URLSigner
+	sessions  SessionRevoker
+	risk      RiskSource
+	support   SupportHistory
 	now       func() time.Time
 }
 
@@ -114,35 +120,6 @@ func (s *Service) LookupUser(ctx context.Context, query string) (LookupUser, err
 	return s.findByUsername(ctx, cleanQuery)
 }
 
-func (s *Service) SetBan(ctx context.Context, userID int64, banned bool, reason string, updatedByTGID int64) error {
-	if s.pool == nil {
-		return nil
-	}
-	if userID <= 0 || updatedByTGID == 0 {
-		return ErrValidation
-	}
-	exists, err := s.userExists(ctx, userID)
-	if err != nil {
-		return err
-	}
-	if !exists {
-		return ErrNotFound
-	}
-
-	if _, err := s.pool.Exec(ctx, `
-INSERT INTO user_bans (user_id, banned, reason, updated_by_tg_id, updated_at)
-VALUES ($1::uuid, $2, NULLIF($3, ''), $4, NOW())
-ON CONFLICT (user_id) DO UPDATE SET
-	banned = EXCLUDED.banned,
-	reason = EXCLUDED.reason,
-	updated_by_tg_id = EXCLUDED.updated_by_tg_id,
-	updated_at = EXCLUDED.updated_at
-`, pseudoUUID(userID), banned, strings.TrimSpace(reason), updatedByTGID); err != nil {
-		return fmt.Errorf("upsert user ban: %w", err)
-	}
-	return nil
-}
-
 func (s *Service) ForceReview(ctx context.Context, userID int64) error {
 	if s.pool == nil {
 		return nil
@@ -375,12 +352,18 @@ LIMIT 1
 	if err := s.attachMedia(ctx, &user); err != nil {
 		return LookupUser{}, err
 	}
-	banned, reason, err := s.banState(ctx, user.UserID)
+	ban, err := s.GetBanState(ctx, user.UserID)
+	if err != nil {
+		return LookupUser{}, err
+	}
+	user.IsBanned = ban.Banned
+	user.BanReason = ban.Reason
+	user.BanReasonCode = ban.ReasonCode
+	user.BanExpiresAt = ban.ExpiresAt
+	user.EvasionMatches, err = s.FindBanEvasion(ctx, user.UserID)
 	if err != nil {
 		return LookupUser{}, err
 	}
-	user.IsBanned = banned
-	user.BanReason = reason
 
 	return user, nil
 }
@@ -446,28 +429,6 @@ func (s *Service) signKey(ctx context.Context, key string) (string, error) {
 	return url, nil
 }
 
-func (s *Service) banState(ctx context.Context, userID int64) (bool, string, error) {
-	if s.pool == nil || userID <= 0 {
-		return false, "", nil
-	}
-
-	var banned bool
-	var reason sql.NullString
-	err := s.pool.QueryRow(ctx, `
-SELECT banned, reason
-FROM user_bans
-WHERE user_id = $1::uuid
-LIMIT 1
-`, pseudoUUID(userID)).Scan(&banned, &reason)
-	if err != nil {
-		if errors.Is(err, pgx.ErrNoRows) {
-			return false, "", nil
-		}
-		return false, "", fmt.Errorf("lookup user ban state: %w", err)
-	}
-	return banned, strings.TrimSpace(reason.String), nil
-}
-
 func (s *Service) userExists(ctx context.Context, userID int64) (bool, error) {
 	if s.pool == nil {
 		return false, nil
@@ -0,0 +1,404 @@
+package waitlist
+
+import (
+	"context"
+	"crypto/rand"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	pgrepo "github.com/ivankudzin/tgapp/backend/internal/repo/postgres"
+	flagssvc "github.com/ivankudzin/tgapp/backend/internal/services/flags"
+)
+
+const (
+	// StatusOpen means the user is not queued: registration was open when they arrived or the
+	// account predates the waitlist.
+	StatusOpen     = "OPEN"
+	StatusWaiting  = pgrepo.WaitlistStatusWaiting
+	StatusAdmitted = pgrepo.WaitlistStatusAdmitted
+
+	MaxAdmitBatch      = 500
+	MaxInviteBatch     = 100
+	maxInviteCodeLen   = 32
+	inviteCodeLen      = 8
+	maxCommentLen      = 200
+	notifyBatchSize    = 50
+	notifyMaxAttempts  = 3
+	notifyClaimTimeout = 5 * time.Minute
+)
+
+// inviteAlphabet leaves out 0/O and 1/I so codes survive being read out or retyped.
+const inviteAlphabet = "ABCDEFGHJKLMNPQRSTUVWXYZ23456789"
+
+var (
+	ErrInvalidInput        = errors.New("invalid waitlist input")
+	ErrInviteCodeInvalid   = errors.New("invite code is invalid")
+	ErrInviteCodeNotFound  = errors.New("invite code not found")
+	ErrWaitlistUnavailable = errors.New("waitlist store is not configured")
+	ErrNotOnWaitlist       = errors.New("user is not on the waitlist")
+)
+
+type Store interface {
+	GetWaitlistEntry(ctx context.Context, userID int64) (pgrepo.WaitlistEntryRecord, error)
+	UserHasProfile(ctx context.Context, userID int64) (bool, error)
+	JoinWaitlist(ctx context.Context, userID int64) (pgrepo.WaitlistEntryRecord, error)
+	AdmitWaitlistUser(ctx context.Context, userID int64, via string) (pgrepo.WaitlistEntryRecord, error)
+	RedeemInvite(ctx context.Context, userID int64, code string) (pgrepo.WaitlistEntryRecord, error)
+	AdmitWaitlist(ctx context.Context, in pgrepo.WaitlistAdmitInput) ([]pgrepo.WaitlistAdmittedRecord, error)
+	WaitlistStats(ctx context.Context, topN int) (pgrepo.WaitlistStatsRecord, error)
+	AcquireWaitlistNotifications(ctx context.Context, limit int, maxAttempts int, claimTTL time.Duration) ([]pgrepo.WaitlistNotificationRecord, error)
+	MarkWaitlistNotified(ctx context.Context, userID int64) error
+	CreateInviteCodes(ctx context.Context, items []pgrepo.InviteCodeRecord) ([]pgrepo.InviteCodeRecord, error)
+	ListInviteCodes(ctx context.Context, limit, offset int) ([]pgrepo.InviteCodeRecord, error)
+	DisableInviteCode(ctx context.Context, code string) (pgrepo.InviteCodeRecord, error)
+}
+
+type Status struct {
+	Status      string
+	Position    int64
+	Ahead       int64
+	AdmittedVia string
+	JoinedAt    *time.Time
+	AdmittedAt  *time.Time
+	// InviteRejected is set when a code was sent but could not be redeemed; the user keeps
+	// their place in the queue.
+	InviteRejected bool
+}
+
+type AdmitInput struct {
+	Limit   int
+	CityID  string
+	Gender  string
+	Balance bool
+	// IgnoreBalance also admits segments held back by the supply balance controller.
+	IgnoreBalance bool
+	ActorTGID     int64
+}
+
+type AdmitResult struct {
+	Admitted []pgrepo.WaitlistAdmittedRecord
+	Waiting  int64
+}
+
+type InviteInput struct {
+	Count     int
+	MaxUses   int
+	ExpiresAt *time.Time
+	Comment   string
+	ActorTGID int64
+}
+
+type Notification struct {
+	UserID     int64
+	TelegramID int64
+	Position   int64
+}
+
+type Service struct {
+	store Store
+	flags *flagssvc.Service
+	now   func() time.Time
+}
+
+// NewService wires the waitlist to the registration_enabled flag. A nil store disables the
+// waitlist entirely: every user is treated as admitted.
+func NewService(store Store, flags *flagssvc.Service) *Service {
+	return &Service{store: store, flags: flags, now: time.Now}
+}
+
+// Register runs on every Telegram login. An existing entry always wins so a user who was
+// admitted stays admitted after registration closes again.
+func (s *Service) Register(ctx context.Context, userID int64, inviteCode string) (Status, error) {
+	if s.store == nil || userID <= 0 {
+		return Status{Status: StatusOpen}, nil
+	}
+	inviteCode = NormalizeInviteCode(inviteCode)
+
+	entry, err := s.store.GetWaitlistEntry(ctx, userID)
+	switch {
+	case err == nil:
+		if entry.Status == StatusAdmitted {
+			return statusFromEntry(entry), nil
+		}
+		if s.registrationOpen(ctx, userID) {
+			admitted, err := s.store.AdmitWaitlistUser(ctx, userID, pgrepo.WaitlistAdmittedViaOpen)
+			if err != nil {
+				return Status{}, err
+			}
+			return statusFromEntry(admitted), nil
+		}
+		return s.tryInvite(ctx, userID, inviteCode, entry)
+	case !errors.Is(err, pgrepo.ErrWaitlistEntryNotFound):
+		return Status{}, err
+	}
+
+	hasProfile, err := s.store.UserHasProfile(ctx, userID)
+	if err != nil {
+		return Status{}, err
+	}
+	if hasProfile || s.registrationOpen(ctx, userID) {
+		return Status{Status: StatusOpen}, nil
+	}
+
+	if inviteCode != "" {
+		admitted, err := s.store.RedeemInvite(ctx, userID, inviteCode)
+		if err == nil {
+			return statusFromEntry(admitted), nil
+		}
+		if !errors.Is(err, pgrepo.ErrInviteCodeUnusable) {
+			return Status{}, err
+		}
+	}
+
+	joined, err := s.store.JoinWaitlist(ctx, userID)
+	if err != nil {
+		return Status{}, err
+	}
+	out := statusFromEntry(joined)
+	out.InviteRejected = inviteCode != ""
+	return out, nil
+}
+
+// RedeemInvite lets a user who is already queued apply a code from inside the app.
+func (s *Service) RedeemInvite(ctx context.Context, userID int64, inviteCode string) (Status, error) {
+	if s.store == nil {
+		return Status{}, ErrWaitlistUnavailable
+	}
+	inviteCode = NormalizeInviteCode(inviteCode)
+	if inviteCode == "" {
+		return Status{}, fmt.Errorf("%w: invite code is required", ErrInvalidInput)
+	}
+
+	entry, err := s.store.GetWaitlistEntry(ctx, userID)
+	if errors.Is(err, pgrepo.ErrWaitlistEntryNotFound) {
+		return Status{}, ErrNotOnWaitlist
+	}
+	if err != nil {
+		return Status{}, err
+	}
+	if entry.Status == StatusAdmitted {
+		return statusFromEntry(entry), nil
+	}
+
+	status, err := s.tryInvite(ctx, userID, inviteCode, entry)
+	if err != nil {
+		return Status{}, err
+	}
+	if status.InviteRejected {
+		return Status{}, ErrInviteCodeInvalid
+	}
+	return status, nil
+}
+
+func (s *Service) Status(ctx context.Context, userID int64) (Status, error) {
+	if s.store == nil {
+		return Status{Status: StatusOpen}, nil
+	}
+	entry, err := s.store.GetWaitlistEntry(ctx, userID)
+	if errors.Is(err, pgrepo.ErrWaitlistEntryNotFound) {
+		return Status{Status: StatusOpen}, nil
+	}
+	if err != nil {
+		return Status{}, err
+	}
+	return statusFromEntry(entry), nil
+}
+
+// IsWaiting is the request-path check for gated endpoints.
+func (s *Service) IsWaiting(ctx context.Context, userID int64) (bool, error) {
+	status, err := s.Status(ctx, userID)
+	if err != nil {
+		return false, err
+	}
+	return status.Status == StatusWaiting, nil
+}
+
+func (s *Service) Admit(ctx context.Context, in AdmitInput) (AdmitResult, error) {
+	if s.store == nil {
+		return AdmitResult{}, ErrWaitlistUnavailable
+	}
+	if in.Limit <= 0 || in.Limit > MaxAdmitBatch {
+		return AdmitResult{}, fmt.Errorf("%w: limit must be between 1 and %d", ErrInvalidInput, MaxAdmitBatch)
+	}
+	gender := strings.ToLower(strings.TrimSpace(in.Gender))
+	if in.Balance && gender != "" {
+		return AdmitResult{}, fmt.Errorf("%w: balance and gender are mutually exclusive", ErrInvalidInput)
+	}
+
+	admitted, err := s.store.AdmitWaitlist(ctx, pgrepo.WaitlistAdmitInput{
+		Limit:         in.Limit,
+		CityID:        strings.TrimSpace(in.CityID),
+		Gender:        gender,
+		Balance:       in.Balance,
+		IgnoreBalance: in.IgnoreBalance,
+		ActorTGID:     in.ActorTGID,
+	})
+	if err != nil {
+		return AdmitResult{}, err
+	}
+
+	out := AdmitResult{Admitted: admitted}
+	if stats, err := s.store.WaitlistStats(ctx, 1); err == nil {
+		out.Waiting = stats.Waiting
+	}
+	return out, nil
+}
+
+func (s *Service) Stats(ctx context.Context, topN int) (pgrepo.WaitlistStatsRecord, error) {
+	if s.store == nil {
+		return pgrepo.WaitlistStatsRecord{ByCity: []pgrepo.WaitlistBucketRecord{}, ByGender: []pgrepo.WaitlistBucketRecord{}}, nil
+	}
+	return s.store.WaitlistStats(ctx, topN)
+}
+
+func (s *Service) CreateInvites(ctx context.Context, in InviteInput) ([]pgrepo.InviteCodeRecord, error) {
+	if s.store == nil {
+		return nil, ErrWaitlistUnavailable
+	}
+	if in.Count <= 0 || in.Count > MaxInviteBatch {
+		return nil, fmt.Errorf("%w: count must be between 1 and %d", ErrInvalidInput, MaxInviteBatch)
+	}
+	if in.MaxUses < 0 {
+		return nil, fmt.Errorf("%w: max_uses must be >= 0", ErrInvalidInput)
+	}
+	if in.ExpiresAt != nil && !in.ExpiresAt.After(s.now()) {
+		return nil, fmt.Errorf("%w: expires_at must be in the future", ErrInvalidInput)
+	}
+	comment := strings.TrimSpace(in.Comment)
+	if len([]rune(comment)) > maxCommentLen {
+		return nil, fmt.Errorf("%w: comment is too long", ErrInvalidInput)
+	}
+
+	var createdBy *int64
+	if in.ActorTGID != 0 {
+		actor := in.ActorTGID
+		createdBy = &actor
+	}
+	items := make([]pgrepo.InviteCodeRecord, 0, in.Count)
+	for i := 0; i < in.Count; i++ {
+		code, err := NewInviteCode()
+		if err != nil {
+			return nil, err
+		}
+		items = append(items, pgrepo.InviteCodeRecord{
+			Code:          code,
+			MaxUses:       in.MaxUses,
+			ExpiresAt:     in.ExpiresAt,
+			Comment:       comment,
+			CreatedByTGID: createdBy,
+		})
+	}
+	return s.store.CreateInviteCodes(ctx, items)
+}
+
+func (s *Service) ListInvites(ctx context.Context, limit, offset int) ([]pgrepo.InviteCodeRecord, error) {
+	if s.store == nil {
+		return []pgrepo.InviteCodeRecord{}, nil
+	}
+	if limit <= 0 || limit > 200 {
+		limit = 50
+	}
+	if offset < 0 {
+		offset = 0
+	}
+	return s.store.ListInviteCodes(ctx, limit, offset)
+}
+
+func (s *Service) DisableInvite(ctx context.Context, code string) (pgrepo.InviteCodeRecord, error) {
+	if s.store == nil {
+		return pgrepo.InviteCodeRecord{}, ErrWaitlistUnavailable
+	}
+	item, err := s.store.DisableInviteCode(ctx, NormalizeInviteCode(code))
+	if errors.Is(err, pgrepo.ErrInviteCodeNotFound) {
+		return pgrepo.InviteCodeRecord{}, ErrInviteCodeNotFound
+	}
+	return item, err
+}
+
+// NotifyAdmitted delivers one batch of admission messages through send and reports how many
+// went out. Failed sends are retried on a later run until the attempt cap.
+func (s *Service) NotifyAdmitted(ctx context.Context, send func(context.Context, Notification) error) (int, error) {
+	if s.store == nil || send == nil {
+		return 0, nil
+	}
+
+	items, err := s.store.AcquireWaitlistNotifications(ctx, notifyBatchSize, notifyMaxAttempts, notifyClaimTimeout)
+	if err != nil {
+		return 0, err
+	}
+
+	sent := 0
+	for _, item := range items {
+		if err := send(ctx, Notification{UserID: item.UserID, TelegramID: item.TelegramID, Position: item.Position}); err != nil {
+			continue
+		}
+		if err := s.store.MarkWaitlistNotified(ctx, item.UserID); err != nil {
+			return sent, err
+		}
+		sent++
+	}
+	return sent, nil
+}
+
+func (s *Service) tryInvite(ctx context.Context, userID int64, inviteCode string, entry pgrepo.WaitlistEntryRecord) (Status, error) {
+	if inviteCode == "" {
+		return statusFromEntry(entry), nil
+	}
+	admitted, err := s.store.RedeemInvite(ctx, userID, inviteCode)
+	if errors.Is(err, pgrepo.ErrInviteCodeUnusable) {
+		out := statusFromEntry(entry)
+		out.InviteRejected = true
+		return out, nil
+	}
+	if err != nil {
+		return Status{}, err
+	}
+	return statusFromEntry(admitted), nil
+}
+
+func (s *Service) registrationOpen(ctx context.Context, userID int64) bool {
+	if s.flags == nil {
+		return true
+	}
+	return s.flags.Bool(ctx, flagssvc.RegistrationEnabled, s.flags.Subject(ctx, userID), true)
+}
+
+func statusFromEntry(entry pgrepo.WaitlistEntryRecord) Status {
+	out := Status{
+		Status:     entry.Status,
+		Position:   entry.Position,
+		AdmittedAt: entry.AdmittedAt,
+	}
+	if entry.Status == StatusWaiting {
+		out.Ahead = entry.AheadInQueue
+	}
+	if entry.AdmittedVia != nil {
+		out.AdmittedVia = *entry.AdmittedVia
+	}
+	if !entry.JoinedAt.IsZero() {
+		joined := entry.JoinedAt
+		out.JoinedAt = &joined
+	}
+	return out
+}
+
+func NormalizeInviteCode(code string) string {
+	code = strings.ToUpper(strings.TrimSpace(code))
+	if len(code) > maxInviteCodeLen {
+		return ""
+	}
+	return code
+}
+
+func NewInviteCode() (string, error) {
+	b := make([]byte, inviteCodeLen)
+	if _, err := rand.Read(b); err != nil {
+		return "", fmt.Errorf("generate invite code: %w", err)
+	}
+	for i := range b {
+		b[i] = inviteAlphabet[int(b[i])%len(inviteAlphabet)]
+	}
+	return string(b), nil
+}
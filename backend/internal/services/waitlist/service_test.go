@@ -0,0 +1,276 @@
+package waitlist
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	pgrepo "github.com/ivankudzin/tgapp/backend/internal/repo/postgres"
+	flagssvc "github.com/ivankudzin/tgapp/backend/internal/services/flags"
+)
+
+type fakeFlagStore struct {
+	registration bool
+}
+
+func (f *fakeFlagStore) ListAppFlags(context.Context) ([]pgrepo.AppFlagRecord, error) {
+	return []pgrepo.AppFlagRecord{{Key: flagssvc.RegistrationEnabled, ValueType: "bool", ValueBool: f.registration}}, nil
+}
+
+func (f *fakeFlagStore) UpsertAppFlag(_ context.Context, in pgrepo.AppFlagRecord) (pgrepo.AppFlagRecord, error) {
+	return in, nil
+}
+
+func (f *fakeFlagStore) DeleteAppFlag(context.Context, string) error {
+	return nil
+}
+
+type fakeStore struct {
+	entries  map[int64]pgrepo.WaitlistEntryRecord
+	profiles map[int64]bool
+	invites  map[string]int
+	nextPos  int64
+	admitIn  []pgrepo.WaitlistAdmitInput
+	notify   []pgrepo.WaitlistNotificationRecord
+	notified []int64
+}
+
+func newFakeStore() *fakeStore {
+	return &fakeStore{
+		entries:  map[int64]pgrepo.WaitlistEntryRecord{},
+		profiles: map[int64]bool{},
+		invites:  map[string]int{},
+	}
+}
+
+func (f *fakeStore) GetWaitlistEntry(_ context.Context, userID int64) (pgrepo.WaitlistEntryRecord, error) {
+	entry, ok := f.entries[userID]
+	if !ok {
+		return pgrepo.WaitlistEntryRecord{}, pgrepo.ErrWaitlistEntryNotFound
+	}
+	for _, other := range f.entries {
+		if other.Status == StatusWaiting && other.Position < entry.Position {
+			entry.AheadInQueue++
+		}
+	}
+	return entry, nil
+}
+
+func (f *fakeStore) UserHasProfile(_ context.Context, userID int64) (bool, error) {
+	return f.profiles[userID], nil
+}
+
+func (f *fakeStore) JoinWaitlist(ctx context.Context, userID int64) (pgrepo.WaitlistEntryRecord, error) {
+	if _, ok := f.entries[userID]; !ok {
+		f.nextPos++
+		f.entries[userID] = pgrepo.WaitlistEntryRecord{UserID: userID, Position: f.nextPos, Status: StatusWaiting, JoinedAt: time.Now()}
+	}
+	return f.GetWaitlistEntry(ctx, userID)
+}
+
+func (f *fakeStore) AdmitWaitlistUser(ctx context.Context, userID int64, via string) (pgrepo.WaitlistEntryRecord, error) {
+	entry := f.entries[userID]
+	entry.Status = StatusAdmitted
+	entry.AdmittedVia = &via
+	f.entries[userID] = entry
+	return f.GetWaitlistEntry(ctx, userID)
+}
+
+func (f *fakeStore) RedeemInvite(ctx context.Context, userID int64, code string) (pgrepo.WaitlistEntryRecord, error) {
+	left, ok := f.invites[code]
+	if !ok || left <= 0 {
+		return pgrepo.WaitlistEntryRecord{}, pgrepo.ErrInviteCodeUnusable
+	}
+	f.invites[code] = left - 1
+	entry, exists := f.entries[userID]
+	if !exists {
+		f.nextPos++
+		entry = pgrepo.WaitlistEntryRecord{UserID: userID, Position: f.nextPos}
+	}
+	via := pgrepo.WaitlistAdmittedViaInvite
+	entry.Status = StatusAdmitted
+	entry.AdmittedVia = &via
+	entry.InviteCode = &code
+	f.entries[userID] = entry
+	return f.GetWaitlistEntry(ctx, userID)
+}
+
+func (f *fakeStore) AdmitWaitlist(_ context.Context, in pgrepo.WaitlistAdmitInput) ([]pgrepo.WaitlistAdmittedRecord, error) {
+	f.admitIn = append(f.admitIn, in)
+	return []pgrepo.WaitlistAdmittedRecord{}, nil
+}
+
+func (f *fakeStore) WaitlistStats(context.Context, int) (pgrepo.WaitlistStatsRecord, error) {
+	return pgrepo.WaitlistStatsRecord{}, nil
+}
+
+func (f *fakeStore) AcquireWaitlistNotifications(context.Context, int, int, time.Duration) ([]pgrepo.WaitlistNotificationRecord, error) {
+	return f.notify, nil
+}
+
+func (f *fakeStore) MarkWaitlistNotified(_ context.Context, userID int64) error {
+	f.notified = append(f.notified, userID)
+	return nil
+}
+
+func (f *fakeStore) CreateInviteCodes(_ context.Context, items []pgrepo.InviteCodeRecord) ([]pgrepo.InviteCodeRecord, error) {
+	return items, nil
+}
+
+func (f *fakeStore) ListInviteCodes(context.Context, int, int) ([]pgrepo.InviteCodeRecord, error) {
+	return nil, nil
+}
+
+func (f *fakeStore) DisableInviteCode(context.Context, string) (pgrepo.InviteCodeRecord, error) {
+	return pgrepo.InviteCodeRecord{}, pgrepo.ErrInviteCodeNotFound
+}
+
+func newClosedService(store *fakeStore) (*Service, *fakeFlagStore) {
+	flagStore := &fakeFlagStore{registration: false}
+	return NewService(store, flagssvc.NewService(flagStore)), flagStore
+}
+
+func TestRegisterQueuesNewUsersWhileRegistrationIsClosed(t *testing.T) {
+	store := newFakeStore()
+	store.profiles[1] = true
+	service, _ := newClosedService(store)
+	ctx := context.Background()
+
+	existing, err := service.Register(ctx, 1, "")
+	if err != nil || existing.Status != StatusOpen {
+		t.Fatalf("expected existing profile to bypass the waitlist, got %+v err=%v", existing, err)
+	}
+
+	first, err := service.Register(ctx, 2, "")
+	if err != nil || first.Status != StatusWaiting || first.Position != 1 || first.Ahead != 0 {
+		t.Fatalf("unexpected first status: %+v err=%v", first, err)
+	}
+	second, err := service.Register(ctx, 3, "")
+	if err != nil || second.Status != StatusWaiting || second.Ahead != 1 {
+		t.Fatalf("unexpected second status: %+v err=%v", second, err)
+	}
+
+	again, err := service.Register(ctx, 3, "")
+	if err != nil || again.Position != second.Position {
+		t.Fatalf("expected repeated login to keep the queue position, got %+v err=%v", again, err)
+	}
+}
+
+func TestRegisterInviteCodeBypassesWaitlist(t *testing.T) {
+	store := newFakeStore()
+	store.invites["ABCD2345"] = 1
+	service, _ := newClosedService(store)
+	ctx := context.Background()
+
+	status, err := service.Register(ctx, 10, " abcd2345 ")
+	if err != nil {
+		t.Fatalf("register: %v", err)
+	}
+	if status.Status != StatusAdmitted || status.AdmittedVia != pgrepo.WaitlistAdmittedViaInvite {
+		t.Fatalf("expected invite admission, got %+v", status)
+	}
+
+	rejected, err := service.Register(ctx, 11, "ABCD2345")
+	if err != nil {
+		t.Fatalf("register with used code: %v", err)
+	}
+	if rejected.Status != StatusWaiting || !rejected.InviteRejected {
+		t.Fatalf("expected used-up code to queue the user, got %+v", rejected)
+	}
+
+	if _, err := service.RedeemInvite(ctx, 11, "NOPE2345"); !errors.Is(err, ErrInviteCodeInvalid) {
+		t.Fatalf("expected ErrInviteCodeInvalid, got %v", err)
+	}
+	store.invites["LATE2345"] = 1
+	late, err := service.RedeemInvite(ctx, 11, "late2345")
+	if err != nil || late.Status != StatusAdmitted {
+		t.Fatalf("expected queued user to be admitted by a fresh code, got %+v err=%v", late, err)
+	}
+}
+
+func TestRegisterAdmitsWaitingUserOnceRegistrationReopens(t *testing.T) {
+	store := newFakeStore()
+	flagStore := &fakeFlagStore{registration: false}
+	flags := flagssvc.NewService(flagStore)
+	service := NewService(store, flags)
+	ctx := context.Background()
+
+	if status, _ := service.Register(ctx, 5, ""); status.Status != StatusWaiting {
+		t.Fatalf("expected waiting, got %+v", status)
+	}
+
+	flagStore.registration = true
+	if _, err := flags.Save(ctx, flagssvc.Flag{Key: flagssvc.RegistrationEnabled, Type: flagssvc.TypeBool, Value: []byte(`true`)}, 0); err != nil {
+		t.Fatalf("save flag: %v", err)
+	}
+
+	status, err := service.Register(ctx, 5, "")
+	if err != nil || status.Status != StatusAdmitted || status.AdmittedVia != pgrepo.WaitlistAdmittedViaOpen {
+		t.Fatalf("expected open admission, got %+v err=%v", status, err)
+	}
+	if fresh, _ := service.Register(ctx, 6, ""); fresh.Status != StatusOpen {
+		t.Fatalf("expected new user to skip the waitlist, got %+v", fresh)
+	}
+}
+
+func TestAdmitValidatesInput(t *testing.T) {
+	store := newFakeStore()
+	service, _ := newClosedService(store)
+	ctx := context.Background()
+
+	for _, in := range []AdmitInput{
+		{Limit: 0},
+		{Limit: MaxAdmitBatch + 1},
+		{Limit: 10, Balance: true, Gender: "male"},
+	} {
+		if _, err := service.Admit(ctx, in); !errors.Is(err, ErrInvalidInput) {
+			t.Fatalf("expected ErrInvalidInput for %+v, got %v", in, err)
+		}
+	}
+
+	if _, err := service.Admit(ctx, AdmitInput{Limit: 20, CityID: " minsk ", Gender: "FEMALE", ActorTGID: 7}); err != nil {
+		t.Fatalf("admit: %v", err)
+	}
+	got := store.admitIn[0]
+	if got.CityID != "minsk" || got.Gender != "female" || got.ActorTGID != 7 || got.Limit != 20 {
+		t.Fatalf("unexpected admit input: %+v", got)
+	}
+}
+
+func TestNotifyAdmittedMarksOnlyDeliveredMessages(t *testing.T) {
+	store := newFakeStore()
+	store.notify = []pgrepo.WaitlistNotificationRecord{
+		{UserID: 1, TelegramID: 101},
+		{UserID: 2, TelegramID: 102},
+	}
+	service, _ := newClosedService(store)
+
+	sent, err := service.NotifyAdmitted(context.Background(), func(_ context.Context, n Notification) error {
+		if n.TelegramID == 102 {
+			return errors.New("blocked by user")
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("notify: %v", err)
+	}
+	if sent != 1 || len(store.notified) != 1 || store.notified[0] != 1 {
+		t.Fatalf("expected only user 1 to be marked, sent=%d notified=%v", sent, store.notified)
+	}
+}
+
+func TestNewInviteCodeUsesUnambiguousAlphabet(t *testing.T) {
+	code, err := NewInviteCode()
+	if err != nil {
+		t.Fatalf("new invite code: %v", err)
+	}
+	if len(code) != inviteCodeLen {
+		t.Fatalf("unexpected code length: %q", code)
+	}
+	for _, r := range code {
+		if r == '0' || r == 'O' || r == '1' || r == 'I' {
+			t.Fatalf("code contains ambiguous rune: %q", code)
+		}
+	}
+}
@@ -0,0 +1,20 @@
+package dto
+
+import "time"
+
+type AdminRoleItem struct {
+	Name          string     `json:"name"`
+	Permissions   []string   `json:"permissions"`
+	IsSystem      bool       `json:"is_system"`
+	UpdatedByTGID *int64     `json:"updated_by_tg_id,omitempty"`
+	UpdatedAt     *time.Time `json:"updated_at,omitempty"`
+}
+
+type AdminRolesResponse struct {
+	Permissions []string        `json:"permissions"`
+	Items       []AdminRoleItem `json:"items"`
+}
+
+type AdminRoleUpsertRequest struct {
+	Permissions []string `json:"permissions"`
+}
@@ -7,33 +7,190 @@ type AdminBotLookupUserResponse struct {
 }
 
 type AdminBotLookupUser struct {
-	UserID           int64      `json:"user_id"`
-	TGID             int64      `json:"tg_id"`
-	Username         string     `json:"username"`
-	CityID           string     `json:"city_id"`
-	Birthdate        *time.Time `json:"birthdate"`
-	Age              int        `json:"age"`
-	Gender           string     `json:"gender"`
-	LookingFor       string     `json:"looking_for"`
-	Goals            []string   `json:"goals"`
-	Languages        []string   `json:"languages"`
-	Occupation       string     `json:"occupation"`
-	Education        string     `json:"education"`
-	ModerationStatus string     `json:"moderation_status"`
-	Approved         bool       `json:"approved"`
-	PhotoKeys        []string   `json:"photo_keys"`
-	CircleKey        string     `json:"circle_key"`
-	PhotoURLs        []string   `json:"photo_urls"`
-	CircleURL        string     `json:"circle_url"`
-	PlusExpiresAt    *time.Time `json:"plus_expires_at"`
-	BoostUntil       *time.Time `json:"boost_until"`
-	SuperlikeCredits int        `json:"superlike_credits"`
-	RevealCredits    int        `json:"reveal_credits"`
-	LikeTokens       int        `json:"like_tokens"`
-	IsBanned         bool       `json:"is_banned"`
-	BanReason        string     `json:"ban_reason"`
+	UserID           int64                  `json:"user_id"`
+	TGID             int64                  `json:"tg_id"`
+	Username         string                 `json:"username"`
+	CityID           string                 `json:"city_id"`
+	Birthdate        *time.Time             `json:"birthdate"`
+	Age              int                    `json:"age"`
+	Gender           string                 `json:"gender"`
+	LookingFor       string                 `json:"looking_for"`
+	Goals            []string               `json:"goals"`
+	Languages        []string               `json:"languages"`
+	Occupation       string                 `json:"occupation"`
+	Education        string                 `json:"education"`
+	ModerationStatus string                 `json:"moderation_status"`
+	Approved         bool                   `json:"approved"`
+	PhotoKeys        []string               `json:"photo_keys"`
+	CircleKey        string                 `json:"circle_key"`
+	PhotoURLs        []string               `json:"photo_urls"`
+	CircleURL        string                 `json:"circle_url"`
+	PlusExpiresAt    *time.Time             `json:"plus_expires_at"`
+	BoostUntil       *time.Time             `json:"boost_until"`
+	SuperlikeCredits int                    `json:"superlike_credits"`
+	RevealCredits    int                    `json:"reveal_credits"`
+	LikeTokens       int                    `json:"like_tokens"`
+	IsBanned         bool                   `json:"is_banned"`
+	BanReason        string                 `json:"ban_reason"`
+	BanReasonCode    string                 `json:"ban_reason_code"`
+	BanExpiresAt     *time.Time             `json:"ban_expires_at"`
+	EvasionMatches   []AdminBotEvasionMatch `json:"evasion_matches"`
 }
 
+type AdminBotEvasionMatch struct {
+	BannedUserID int64    `json:"banned_user_id"`
+	BannedTGID   int64    `json:"banned_tg_id"`
+	Signals      []string `json:"signals"`
+}
+
+// AdminBotBanRequest keeps user_id/updated_by_tg_id for older bot builds; the URL and auth header win.
 type AdminBotBanRequest struct {
-	Reason string `json:"reason"`
+	UserID        int64  `json:"user_id,omitempty"`
+	UpdatedByTGID int64  `json:"updated_by_tg_id,omitempty"`
+	ReasonCode    string `json:"reason_code"`
+	Reason        string `json:"reason"`
+	Duration      string `json:"duration"`
+}
+
+type AdminBotBanState struct {
+	Banned     bool       `json:"banned"`
+	ReasonCode string     `json:"reason_code"`
+	Reason     string     `json:"reason"`
+	BannedAt   *time.Time `json:"banned_at"`
+	ExpiresAt  *time.Time `json:"expires_at"`
+}
+
+type AdminBotBanResponse struct {
+	OK bool `json:"ok"`
+	AdminBotBanState
+	UnmatchedCount  int  `json:"unmatched_count"`
+	SessionsRevoked bool `json:"sessions_revoked"`
+}
+
+// AdminBotDossierResponse carries only the requested sections; the rest are omitted.
+type AdminBotDossierResponse struct {
+	UserID     int64                      `json:"user_id"`
+	Section    string                     `json:"section"`
+	Limit      int                        `json:"limit"`
+	Offset     int                        `json:"offset"`
+	Risk       *AdminBotDossierRisk       `json:"risk,omitempty"`
+	Activity   *AdminBotDossierActivity   `json:"activity,omitempty"`
+	Reports    *AdminBotDossierReports    `json:"reports,omitempty"`
+	Devices    *AdminBotDossierDevices    `json:"devices,omitempty"`
+	Purchases  *AdminBotDossierPurchases  `json:"purchases,omitempty"`
+	Moderation *AdminBotDossierModeration `json:"moderation,omitempty"`
+	Support    *AdminBotDossierSupport    `json:"support,omitempty"`
+}
+
+type AdminBotDossierRisk struct {
+	Available       bool                       `json:"available"`
+	RiskScore       int                        `json:"risk_score"`
+	CooldownUntil   *time.Time                 `json:"cooldown_until"`
+	LastViolationAt *time.Time                 `json:"last_violation_at"`
+	ShadowEnabled   bool                       `json:"shadow_enabled"`
+	Events          []AdminBotDossierRiskEvent `json:"events"`
+	EventsTotal     int                        `json:"events_total"`
+}
+
+type AdminBotDossierRiskEvent struct {
+	Name       string         `json:"name"`
+	OccurredAt time.Time      `json:"occurred_at"`
+	Payload    map[string]any `json:"payload,omitempty"`
+}
+
+type AdminBotDossierActivity struct {
+	Days             int     `json:"days"`
+	Likes            int     `json:"likes"`
+	Superlikes       int     `json:"superlikes"`
+	Dislikes         int     `json:"dislikes"`
+	LikeRatio        float64 `json:"like_ratio"`
+	SegmentLikeRatio float64 `json:"segment_like_ratio"`
+	LikesReceived    int     `json:"likes_received"`
+	Swipes1h         int     `json:"swipes_1h"`
+	Swipes24h        int     `json:"swipes_24h"`
+	TooFastHits      int     `json:"too_fast_hits"`
+}
+
+type AdminBotDossierReports struct {
+	Received      []AdminBotDossierReport `json:"received"`
+	ReceivedTotal int                     `json:"received_total"`
+	Filed         []AdminBotDossierReport `json:"filed"`
+	FiledTotal    int                     `json:"filed_total"`
+}
+
+type AdminBotDossierReport struct {
+	ID                int64     `json:"id"`
+	CounterpartUserID int64     `json:"counterpart_user_id"`
+	Reason            string    `json:"reason"`
+	Details           string    `json:"details"`
+	Status            string    `json:"status"`
+	CreatedAt         time.Time `json:"created_at"`
+}
+
+type AdminBotDossierDevices struct {
+	Devices        []AdminBotDossierDevice        `json:"devices"`
+	Total          int                            `json:"total"`
+	LinkedAccounts []AdminBotDossierLinkedAccount `json:"linked_accounts"`
+}
+
+type AdminBotDossierDevice struct {
+	DeviceID    string    `json:"device_id"`
+	FirstSeenAt time.Time `json:"first_seen_at"`
+	LastSeenAt  time.Time `json:"last_seen_at"`
+	IsBlocked   bool      `json:"is_blocked"`
+	SharedWith  int       `json:"shared_with"`
+}
+
+type AdminBotDossierLinkedAccount struct {
+	UserID  int64    `json:"user_id"`
+	TGID    int64    `json:"tg_id"`
+	Signals []string `json:"signals"`
+	Banned  bool     `json:"banned"`
+}
+
+type AdminBotDossierPurchases struct {
+	Items []AdminBotDossierPurchase `json:"items"`
+	Total int                       `json:"total"`
+}
+
+type AdminBotDossierPurchase struct {
+	ID          int64     `json:"id"`
+	SKU         string    `json:"sku"`
+	Provider    string    `json:"provider"`
+	Status      string    `json:"status"`
+	AmountMinor int64     `json:"amount_minor"`
+	Currency    string    `json:"currency"`
+	CreatedAt   time.Time `json:"created_at"`
+}
+
+type AdminBotDossierModeration struct {
+	Items []AdminBotDossierModerationDecision `json:"items"`
+	Total int                                 `json:"total"`
+}
+
+type AdminBotDossierModerationDecision struct {
+	ModerationItemID int64      `json:"moderation_item_id"`
+	TargetType       string     `json:"target_type"`
+	Status           string     `json:"status"`
+	ReasonCode       string     `json:"reason_code"`
+	ModeratorTGID    *int64     `json:"moderator_tg_id"`
+	CreatedAt        time.Time  `json:"created_at"`
+	DecidedAt        *time.Time `json:"decided_at"`
+	ReviewKind       string     `json:"review_kind"`
+	ReviewStatus     string     `json:"review_status"`
+}
+
+type AdminBotDossierSupport struct {
+	Available bool                                 `json:"available"`
+	Items     []AdminBotDossierSupportConversation `json:"items"`
+	Total     int                                  `json:"total"`
+}
+
+type AdminBotDossierSupportConversation struct {
+	ID            int64     `json:"id"`
+	Status        string    `json:"status"`
+	MessagesCount int       `json:"messages_count"`
+	LastMessage   string    `json:"last_message"`
+	CreatedAt     time.Time `json:"created_at"`
+	UpdatedAt     time.Time `json:"updated_at"`
 }
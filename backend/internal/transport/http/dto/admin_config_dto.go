@@ -0,0 +1,61 @@
+package dto
+
+import (
+	"encoding/json"
+	"time"
+)
+
+type AdminConfigResponse struct {
+	Version     int64           `json:"version"`
+	Checksum    string          `json:"checksum"`
+	ETag        string          `json:"etag"`
+	PublishedAt *time.Time      `json:"published_at"`
+	Document    json.RawMessage `json:"document"`
+}
+
+type AdminConfigVersionItem struct {
+	Version                int64           `json:"version"`
+	Checksum               string          `json:"checksum"`
+	Comment                string          `json:"comment"`
+	RollbackOf             *int64          `json:"rollback_of"`
+	PublishedByAdminUserID *int64          `json:"published_by_admin_user_id"`
+	PublishedByTGID        *int64          `json:"published_by_tg_id"`
+	PublishedAt            *time.Time      `json:"published_at"`
+	Document               json.RawMessage `json:"document,omitempty"`
+}
+
+type AdminConfigVersionListResponse struct {
+	Items  []AdminConfigVersionItem `json:"items"`
+	Offset int                      `json:"offset"`
+	Limit  int                      `json:"limit"`
+}
+
+type AdminConfigChange struct {
+	Path string          `json:"path"`
+	From json.RawMessage `json:"from"`
+	To   json.RawMessage `json:"to"`
+}
+
+type AdminConfigDiffResponse struct {
+	From    int64               `json:"from"`
+	To      int64               `json:"to"`
+	Changes []AdminConfigChange `json:"changes"`
+}
+
+type AdminConfigPublishRequest struct {
+	Document    json.RawMessage `json:"document"`
+	Comment     string          `json:"comment"`
+	BaseVersion *int64          `json:"base_version,omitempty"`
+	DryRun      bool            `json:"dry_run"`
+}
+
+type AdminConfigRollbackRequest struct {
+	Version int64  `json:"version"`
+	Comment string `json:"comment"`
+}
+
+type AdminConfigPublishResponse struct {
+	DryRun  bool                   `json:"dry_run"`
+	Version AdminConfigVersionItem `json:"version"`
+	Changes []AdminConfigChange    `json:"changes"`
+}
@@ -0,0 +1,49 @@
+package dto
+
+import (
+	"encoding/json"
+	"time"
+)
+
+type AdminFlagRule struct {
+	CityIDs    []string        `json:"city_ids,omitempty"`
+	Genders    []string        `json:"genders,omitempty"`
+	Plus       *bool           `json:"plus,omitempty"`
+	UserIDs    []int64         `json:"user_ids,omitempty"`
+	Percentage *int            `json:"percentage,omitempty"`
+	Value      json.RawMessage `json:"value"`
+}
+
+type AdminFlagItem struct {
+	Key           string          `json:"key"`
+	Type          string          `json:"type"`
+	Value         json.RawMessage `json:"value"`
+	Rules         []AdminFlagRule `json:"rules"`
+	Description   string          `json:"description"`
+	ClientVisible bool            `json:"client_visible"`
+	UpdatedByTGID *int64          `json:"updated_by_tg_id"`
+	UpdatedAt     *time.Time      `json:"updated_at"`
+}
+
+type AdminFlagListResponse struct {
+	Items []AdminFlagItem `json:"items"`
+}
+
+type AdminFlagUpsertRequest struct {
+	Type          string          `json:"type"`
+	Value         json.RawMessage `json:"value"`
+	Rules         []AdminFlagRule `json:"rules"`
+	Description   string          `json:"description"`
+	ClientVisible bool            `json:"client_visible"`
+}
+
+type AdminFlagEvaluateResponse struct {
+	Key     string          `json:"key"`
+	UserID  int64           `json:"user_id"`
+	CityID  string          `json:"city_id"`
+	Gender  string          `json:"gender"`
+	IsPlus  bool            `json:"is_plus"`
+	Bucket  int             `json:"bucket"`
+	Value   json.RawMessage `json:"value"`
+	Default bool            `json:"default"`
+}
@@ -1,6 +1,13 @@
 package dto
 
-import "time"
+import (
+	"encoding/json"
+	"time"
+)
+
+type AdminUserPrivateRequest struct {
+	Reason string `json:"reason"`
+}
 
 type AdminUserPrivateResponse struct {
 	UserID    int64      `json:"user_id"`
@@ -43,3 +50,61 @@ type AdminAntiAbuseTopResponse struct {
 	Limit int64                   `json:"limit"`
 	Items []AdminAntiAbuseTopItem `json:"items"`
 }
+
+// AdminBotAuditAppendRequest mirrors the bot's audit entry. The actor, hashes and
+// timestamp are accepted for wire compatibility but ignored: the server derives
+// them from the authenticated bot identity and its own clock.
+type AdminBotAuditAppendRequest struct {
+	ID        string          `json:"id,omitempty"`
+	ActorTGID int64           `json:"actor_tg_id"`
+	Action    string          `json:"action"`
+	Payload   json.RawMessage `json:"payload"`
+	PrevHash  string          `json:"prev_hash,omitempty"`
+	Hash      string          `json:"hash,omitempty"`
+	CreatedAt *time.Time      `json:"created_at,omitempty"`
+}
+
+type AdminAuditItem struct {
+	ID        string          `json:"id"`
+	Seq       int64           `json:"seq"`
+	ActorTGID int64           `json:"actor_tg_id"`
+	Action    string          `json:"action"`
+	Payload   json.RawMessage `json:"payload"`
+	PrevHash  string          `json:"prev_hash"`
+	Hash      string          `json:"hash"`
+	CreatedAt time.Time       `json:"created_at"`
+}
+
+type AdminAuditListResponse struct {
+	Items   []AdminAuditItem `json:"items"`
+	Offset  int              `json:"offset"`
+	Limit   int              `json:"limit"`
+	HasMore bool             `json:"has_more"`
+}
+
+type AdminAuditChainResponse struct {
+	Checked  int    `json:"checked"`
+	OK       bool   `json:"ok"`
+	BrokenID string `json:"broken_id,omitempty"`
+}
+
+type AdminUserSummary struct {
+	UserID           int64     `json:"user_id"`
+	TGID             int64     `json:"tg_id"`
+	Username         string    `json:"username"`
+	DisplayName      string    `json:"display_name"`
+	CityID           string    `json:"city_id"`
+	Gender           string    `json:"gender"`
+	ModerationStatus string    `json:"moderation_status"`
+	Approved         bool      `json:"approved"`
+	IsBanned         bool      `json:"is_banned"`
+	ReportsCount     int       `json:"reports_count"`
+	CreatedAt        time.Time `json:"created_at"`
+}
+
+type AdminUserSearchResponse struct {
+	Items  []AdminUserSummary `json:"items"`
+	Total  int                `json:"total"`
+	Offset int                `json:"offset"`
+	Limit  int                `json:"limit"`
+}
@@ -1,7 +1,8 @@
 package dto
 
 type TelegramAuthRequest struct {
-	InitData string `json:"init_data"`
+	InitData   string `json:"init_data"`
+	InviteCode string `json:"invite_code,omitempty"`
 }
 
 type RefreshRequest struct {
@@ -9,8 +10,9 @@ type RefreshRequest struct {
 }
 
 type AuthMeResponse struct {
-	ID   int64  `json:"id"`
-	Role string `json:"role"`
+	ID           int64                       `json:"id"`
+	Role         string                      `json:"role"`
+	Registration *RegistrationStatusResponse `json:"registration,omitempty"`
 }
 
 type AuthTokensResponse struct {
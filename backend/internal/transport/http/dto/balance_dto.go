@@ -0,0 +1,30 @@
+package dto
+
+import "time"
+
+type AdminBalanceCity struct {
+	CityID               string  `json:"city_id"`
+	ActiveFemale         int     `json:"active_female"`
+	ActiveMale           int     `json:"active_male"`
+	FemaleShare          float64 `json:"female_share"`
+	TargetShare          float64 `json:"target_share"`
+	State                string  `json:"state"`
+	UnderGender          string  `json:"under_gender,omitempty"`
+	OverGender           string  `json:"over_gender,omitempty"`
+	LikesFemale          int     `json:"likes_female"`
+	LikesMale            int     `json:"likes_male"`
+	WaitingFemale        int     `json:"waiting_female"`
+	WaitingMale          int     `json:"waiting_male"`
+	ThrottleAdmissions   bool    `json:"throttle_admissions"`
+	PrioritizeModeration bool    `json:"prioritize_moderation"`
+	OverLikeFactor       float64 `json:"over_like_factor"`
+	UnderLikeFactor      float64 `json:"under_like_factor"`
+}
+
+type AdminBalanceResponse struct {
+	Enabled      bool               `json:"enabled"`
+	ActiveWindow string             `json:"active_window"`
+	Tolerance    float64            `json:"tolerance"`
+	ComputedAt   *time.Time         `json:"computed_at,omitempty"`
+	Cities       []AdminBalanceCity `json:"cities"`
+}
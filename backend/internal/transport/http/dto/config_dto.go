@@ -1,6 +1,7 @@
 package dto
 
 type ConfigResponse struct {
+	Version   int64                 `json:"version"`
 	Limits    ConfigLimitsResponse  `json:"limits"`
 	AntiAbuse ConfigAntiAbuse       `json:"antiabuse"`
 	AdsInject ConfigAdsInject       `json:"ads_inject"`
@@ -22,6 +22,7 @@ type FeedItemResponse struct {
 	CityID          string              `json:"city_id,omitempty"`
 	City            string              `json:"city,omitempty"`
 	DistanceKM      *float64            `json:"distance_km,omitempty"`
+	IsVerified      bool                `json:"is_verified,omitempty"`
 }
 
 type FeedResponse struct {
@@ -1,13 +1,18 @@
 package dto
 
-import "time"
+import (
+	"encoding/json"
+	"time"
+)
 
 type MeResponse struct {
-	User             MeUserPublicResponse    `json:"user"`
-	ModerationStatus string                  `json:"moderation_status"`
-	Entitlements     MeEntitlementsResponse  `json:"entitlements"`
-	Quota            MeQuotaSnapshotResponse `json:"quota"`
-	AntiAbuseState   MeAntiAbuseState        `json:"antiabuse_state"`
+	User             MeUserPublicResponse        `json:"user"`
+	ModerationStatus string                      `json:"moderation_status"`
+	Entitlements     MeEntitlementsResponse      `json:"entitlements"`
+	Quota            MeQuotaSnapshotResponse     `json:"quota"`
+	AntiAbuseState   MeAntiAbuseState            `json:"antiabuse_state"`
+	Flags            map[string]json.RawMessage  `json:"flags"`
+	Registration     *RegistrationStatusResponse `json:"registration,omitempty"`
 }
 
 type MeUserPublicResponse struct {
@@ -7,6 +7,16 @@ type ModerationStatusResponse struct {
 	ReasonText      *string `json:"reason_text,omitempty"`
 	RequiredFixStep *string `json:"required_fix_step,omitempty"`
 	ETABucket       string  `json:"eta_bucket"`
+	AppealStatus    string  `json:"appeal_status,omitempty"`
+}
+
+type ModerationAppealRequest struct {
+	Message string `json:"message"`
+}
+
+type ModerationAppealResponse struct {
+	ReviewID int64  `json:"review_id"`
+	Status   string `json:"status"`
 }
 
 type AdminBotModQueueAcquireResponse struct {
@@ -36,11 +46,34 @@ type AdminBotProfileCard struct {
 	Occupation  string   `json:"occupation"`
 	Education   string   `json:"education"`
 	Birthdate   *string  `json:"birthdate,omitempty"`
+
+	VerificationStatus string `json:"verification_status,omitempty"`
 }
 
 type AdminBotProfileMedia struct {
-	Photos []string `json:"photos"`
-	Circle *string  `json:"circle,omitempty"`
+	Photos  []string                `json:"photos"`
+	Circle  *string                 `json:"circle,omitempty"`
+	Matches []AdminBotPhotoMatchDTO `json:"matches"`
+}
+
+type AdminBotPhotoMatchDTO struct {
+	MediaID        int64  `json:"media_id"`
+	Slot           int    `json:"slot"`
+	Source         string `json:"source"`
+	MatchedUserID  *int64 `json:"matched_user_id,omitempty"`
+	MatchedMediaID *int64 `json:"matched_media_id,omitempty"`
+	BannedImageID  *int64 `json:"banned_image_id,omitempty"`
+	Label          string `json:"label,omitempty"`
+	Distance       int    `json:"distance"`
+}
+
+type AdminBotBanImageRequest struct {
+	MediaID int64  `json:"media_id"`
+	Label   string `json:"label"`
+}
+
+type AdminBotBanImageResponse struct {
+	ID int64 `json:"id"`
 }
 
 type AdminBotModerationRejectRequest struct {
@@ -50,12 +83,238 @@ type AdminBotModerationRejectRequest struct {
 }
 
 type AdminBotModerationRejectReasonItem struct {
-	ReasonCode      string `json:"reason_code"`
-	Label           string `json:"label"`
-	ReasonText      string `json:"reason_text"`
-	RequiredFixStep string `json:"required_fix_step"`
+	ReasonCode      string            `json:"reason_code"`
+	Label           string            `json:"label"`
+	Labels          map[string]string `json:"labels"`
+	ReasonText      string            `json:"reason_text"`
+	RequiredFixStep string            `json:"required_fix_step"`
+	Severity        string            `json:"severity"`
+	IsActive        bool              `json:"is_active"`
+	SortOrder       int               `json:"sort_order"`
+	UpdatedByTGID   *int64            `json:"updated_by_tg_id,omitempty"`
+	UpdatedAt       *time.Time        `json:"updated_at,omitempty"`
+}
+
+type AdminRejectReasonUpsertRequest struct {
+	ActorTGID       int64             `json:"actor_tg_id,omitempty"`
+	Label           string            `json:"label"`
+	Labels          map[string]string `json:"labels"`
+	ReasonText      string            `json:"reason_text"`
+	RequiredFixStep string            `json:"required_fix_step"`
+	Severity        string            `json:"severity"`
+	IsActive        *bool             `json:"is_active"`
+	SortOrder       int               `json:"sort_order"`
 }
 
 type AdminBotModerationRejectReasonsResponse struct {
 	Items []AdminBotModerationRejectReasonItem `json:"items"`
 }
+
+type AdminModerationSLAResponse struct {
+	WindowHours      int                           `json:"window_hours"`
+	GeneratedAt      time.Time                     `json:"generated_at"`
+	Decided          int                           `json:"decided"`
+	Approved         int                           `json:"approved"`
+	Rejected         int                           `json:"rejected"`
+	ApproveRatio     float64                       `json:"approve_ratio"`
+	WaitP50Sec       float64                       `json:"wait_p50_sec"`
+	WaitP90Sec       float64                       `json:"wait_p90_sec"`
+	WaitP99Sec       float64                       `json:"wait_p99_sec"`
+	Pending          int                           `json:"pending"`
+	OldestPendingSec float64                       `json:"oldest_pending_sec"`
+	DecidedLastHour  int                           `json:"decided_last_hour"`
+	ETABucket        string                        `json:"eta_bucket"`
+	LockExpired      int                           `json:"lock_expired"`
+	StaleLocks       int                           `json:"stale_locks"`
+	Moderators       []AdminModerationSLAModerator `json:"moderators"`
+	RejectReasons    []AdminModerationSLAReason    `json:"reject_reasons"`
+}
+
+type AdminModerationSLAModerator struct {
+	ModeratorTGID  int64   `json:"moderator_tg_id"`
+	Decisions      int     `json:"decisions"`
+	Approved       int     `json:"approved"`
+	Rejected       int     `json:"rejected"`
+	AvgDecisionSec float64 `json:"avg_decision_sec"`
+	P90DecisionSec float64 `json:"p90_decision_sec"`
+}
+
+type AdminModerationSLAReason struct {
+	ReasonCode    string  `json:"reason_code"`
+	Count         int     `json:"count"`
+	ShareOfReject float64 `json:"share_of_reject"`
+	ShareOfTotal  float64 `json:"share_of_total"`
+}
+
+type AdminBotReviewAcquireRequest struct {
+	Kind string `json:"kind"`
+}
+
+type AdminBotReviewAcquireResponse struct {
+	Review  AdminBotReviewItem   `json:"review"`
+	Profile AdminBotProfileCard  `json:"profile"`
+	Media   AdminBotProfileMedia `json:"media"`
+}
+
+type AdminBotReviewItem struct {
+	ID                    int64      `json:"id"`
+	ModerationItemID      int64      `json:"moderation_item_id"`
+	UserID                int64      `json:"user_id"`
+	Kind                  string     `json:"kind"`
+	Status                string     `json:"status"`
+	OriginalDecision      string     `json:"original_decision"`
+	OriginalReasonCode    *string    `json:"original_reason_code,omitempty"`
+	OriginalModeratorTGID *int64     `json:"original_moderator_tg_id,omitempty"`
+	ReasonText            *string    `json:"reason_text,omitempty"`
+	RequiredFixStep       *string    `json:"required_fix_step,omitempty"`
+	AppealMessage         *string    `json:"appeal_message,omitempty"`
+	LockedAt              *time.Time `json:"locked_at,omitempty"`
+	CreatedAt             time.Time  `json:"created_at"`
+}
+
+type AdminBotReviewResolveRequest struct {
+	Outcome    string `json:"outcome"`
+	ReasonCode string `json:"reason_code"`
+	Note       string `json:"note"`
+}
+
+type AdminBotReviewResolveResponse struct {
+	ID               int64  `json:"id"`
+	ModerationItemID int64  `json:"moderation_item_id"`
+	UserID           int64  `json:"user_id"`
+	Kind             string `json:"kind"`
+	Status           string `json:"status"`
+}
+
+type AdminBotReviewStatsResponse struct {
+	WindowHours int                       `json:"window_hours"`
+	Kinds       []AdminBotReviewKindStats `json:"kinds"`
+	Moderators  []AdminBotReviewAgreement `json:"moderators"`
+}
+
+type AdminBotReviewKindStats struct {
+	Kind          string  `json:"kind"`
+	Pending       int     `json:"pending"`
+	Decided       int     `json:"decided"`
+	Upheld        int     `json:"upheld"`
+	Overturned    int     `json:"overturned"`
+	AgreementRate float64 `json:"agreement_rate"`
+}
+
+type AdminBotReviewAgreement struct {
+	ModeratorTGID int64   `json:"moderator_tg_id"`
+	Reviewed      int     `json:"reviewed"`
+	Upheld        int     `json:"upheld"`
+	Overturned    int     `json:"overturned"`
+	AgreementRate float64 `json:"agreement_rate"`
+}
+
+type AdminBotModeratorShiftItem struct {
+	ID            int64     `json:"id"`
+	ModeratorTGID int64     `json:"moderator_tg_id"`
+	Weekday       int       `json:"weekday"`
+	StartsAt      string    `json:"starts_at"`
+	EndsAt        string    `json:"ends_at"`
+	CreatedByTGID *int64    `json:"created_by_tg_id,omitempty"`
+	CreatedAt     time.Time `json:"created_at"`
+}
+
+type AdminBotModeratorWorkloadItem struct {
+	ModeratorTGID  int64                        `json:"moderator_tg_id"`
+	MaxActiveItems int                          `json:"max_active_items"`
+	Skills         []string                     `json:"skills"`
+	ActiveItems    int                          `json:"active_items"`
+	OnShift        bool                         `json:"on_shift"`
+	Shifts         []AdminBotModeratorShiftItem `json:"shifts"`
+	UpdatedByTGID  *int64                       `json:"updated_by_tg_id,omitempty"`
+	UpdatedAt      *time.Time                   `json:"updated_at,omitempty"`
+}
+
+type AdminBotModerationWorkloadResponse struct {
+	Pending    int                             `json:"pending"`
+	Skills     []string                        `json:"skills"`
+	Moderators []AdminBotModeratorWorkloadItem `json:"moderators"`
+}
+
+type AdminBotModeratorWorkloadRequest struct {
+	MaxActiveItems int      `json:"max_active_items"`
+	Skills         []string `json:"skills"`
+}
+
+type AdminBotModeratorShiftRequest struct {
+	Weekday  int    `json:"weekday"`
+	StartsAt string `json:"starts_at"`
+	EndsAt   string `json:"ends_at"`
+}
+
+type AdminBotReassignIdleRequest struct {
+	IdleSec int64 `json:"idle_sec"`
+}
+
+type AdminBotLockReassignmentItem struct {
+	ModerationItemID int64   `json:"moderation_item_id"`
+	UserID           int64   `json:"user_id"`
+	Skill            string  `json:"skill"`
+	FromTGID         int64   `json:"from_tg_id"`
+	ToTGID           int64   `json:"to_tg_id"`
+	IdleSec          float64 `json:"idle_sec"`
+}
+
+type AdminBotReassignIdleResponse struct {
+	Items []AdminBotLockReassignmentItem `json:"items"`
+}
+
+type AdminBotShiftThroughputItem struct {
+	ShiftID       int64     `json:"shift_id"`
+	ModeratorTGID int64     `json:"moderator_tg_id"`
+	StartsAt      time.Time `json:"starts_at"`
+	EndsAt        time.Time `json:"ends_at"`
+	Decisions     int       `json:"decisions"`
+	Approved      int       `json:"approved"`
+	Rejected      int       `json:"rejected"`
+}
+
+type AdminBotShiftThroughputResponse struct {
+	From  time.Time                     `json:"from"`
+	To    time.Time                     `json:"to"`
+	Items []AdminBotShiftThroughputItem `json:"items"`
+}
+
+type AdminBotModQueueBatchRequest struct {
+	Limit int `json:"limit"`
+}
+
+type AdminBotModQueueBatchResponse struct {
+	Items []AdminBotModQueueAcquireResponse `json:"items"`
+}
+
+type AdminBotModerationBatchDecision struct {
+	ModerationItemID int64  `json:"moderation_item_id"`
+	Decision         string `json:"decision"`
+	ReasonCode       string `json:"reason_code,omitempty"`
+	ReasonText       string `json:"reason_text,omitempty"`
+	RequiredFixStep  string `json:"required_fix_step,omitempty"`
+}
+
+type AdminBotModerationBatchDecideRequest struct {
+	Decisions []AdminBotModerationBatchDecision `json:"decisions"`
+}
+
+type AdminBotModerationBatchDecidedItem struct {
+	ModerationItemID int64      `json:"moderation_item_id"`
+	UserID           int64      `json:"user_id"`
+	Status           string     `json:"status"`
+	ReviewStartedAt  *time.Time `json:"review_started_at,omitempty"`
+}
+
+type AdminBotModerationBatchDecideResponse struct {
+	Items []AdminBotModerationBatchDecidedItem `json:"items"`
+}
+
+type AdminModerationQueueStatsResponse struct {
+	PendingCount    int        `json:"pending_count"`
+	LockedCount     int        `json:"locked_count"`
+	HeldByMeCount   int        `json:"held_by_me_count"`
+	OldestPendingAt *time.Time `json:"oldest_pending_at,omitempty"`
+	ETABucket       string     `json:"eta_bucket"`
+}
@@ -51,5 +51,6 @@ type CandidateProfileResponse struct {
 }
 
 type CandidateBadgesResponse struct {
-	IsPlus bool `json:"is_plus"`
+	IsPlus   bool `json:"is_plus"`
+	Verified bool `json:"verified"`
 }
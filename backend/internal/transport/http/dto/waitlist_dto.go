@@ -0,0 +1,75 @@
+package dto
+
+import "time"
+
+type RegistrationStatusResponse struct {
+	Status         string     `json:"status"`
+	Position       int64      `json:"position,omitempty"`
+	Ahead          int64      `json:"ahead"`
+	AdmittedVia    string     `json:"admitted_via,omitempty"`
+	JoinedAt       *time.Time `json:"joined_at,omitempty"`
+	AdmittedAt     *time.Time `json:"admitted_at,omitempty"`
+	InviteRejected bool       `json:"invite_rejected,omitempty"`
+}
+
+type WaitlistInviteRequest struct {
+	InviteCode string `json:"invite_code"`
+}
+
+type AdminWaitlistBucket struct {
+	Key   string `json:"key"`
+	Count int64  `json:"count"`
+}
+
+type AdminWaitlistStatsResponse struct {
+	Waiting       int64                 `json:"waiting"`
+	AdmittedToday int64                 `json:"admitted_today"`
+	PendingNotify int64                 `json:"pending_notify"`
+	ByCity        []AdminWaitlistBucket `json:"by_city"`
+	ByGender      []AdminWaitlistBucket `json:"by_gender"`
+}
+
+type AdminWaitlistAdmitRequest struct {
+	Limit         int    `json:"limit"`
+	CityID        string `json:"city_id,omitempty"`
+	Gender        string `json:"gender,omitempty"`
+	Balance       bool   `json:"balance,omitempty"`
+	IgnoreBalance bool   `json:"ignore_balance,omitempty"`
+}
+
+type AdminWaitlistAdmittedItem struct {
+	UserID   int64  `json:"user_id"`
+	Position int64  `json:"position"`
+	CityID   string `json:"city_id"`
+	Gender   string `json:"gender"`
+}
+
+type AdminWaitlistAdmitResponse struct {
+	Admitted int                         `json:"admitted"`
+	Waiting  int64                       `json:"waiting"`
+	Items    []AdminWaitlistAdmittedItem `json:"items"`
+}
+
+type AdminInviteCodesCreateRequest struct {
+	Count     int        `json:"count"`
+	MaxUses   int        `json:"max_uses"`
+	ExpiresAt *time.Time `json:"expires_at,omitempty"`
+	Comment   string     `json:"comment,omitempty"`
+}
+
+type AdminInviteCodeItem struct {
+	Code          string     `json:"code"`
+	MaxUses       int        `json:"max_uses"`
+	UsedCount     int        `json:"used_count"`
+	ExpiresAt     *time.Time `json:"expires_at"`
+	Comment       string     `json:"comment"`
+	CreatedByTGID *int64     `json:"created_by_tg_id"`
+	CreatedAt     *time.Time `json:"created_at"`
+	DisabledAt    *time.Time `json:"disabled_at"`
+}
+
+type AdminInviteCodeListResponse struct {
+	Items  []AdminInviteCodeItem `json:"items"`
+	Limit  int                   `json:"limit"`
+	Offset int                   `json:"offset"`
+}
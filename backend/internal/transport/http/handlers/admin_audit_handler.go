@@ -0,0 +1,306 @@
+package handlers
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	pgrepo "github.com/ivankudzin/tgapp/backend/internal/repo/postgres"
+	auditsvc "github.com/ivankudzin/tgapp/backend/internal/services/audit"
+	authsvc "github.com/ivankudzin/tgapp/backend/internal/services/auth"
+	"github.com/ivankudzin/tgapp/backend/internal/transport/http/dto"
+	httperrors "github.com/ivankudzin/tgapp/backend/internal/transport/http/errors"
+)
+
+type AdminAuditHandler struct {
+	service *auditsvc.Service
+}
+
+func NewAdminAuditHandler(service *auditsvc.Service) *AdminAuditHandler {
+	return &AdminAuditHandler{service: service}
+}
+
+func (h *AdminAuditHandler) BotAppend(w http.ResponseWriter, r *http.Request) {
+	actorTGID, ok := adminBotActorTGID(r)
+	if !ok {
+		writeUnauthorized(w, "UNAUTHORIZED", "admin bot authentication required")
+		return
+	}
+	if h.service == nil {
+		writeInternal(w, "AUDIT_SERVICE_UNAVAILABLE", "audit service is unavailable")
+		return
+	}
+
+	var req dto.AdminBotAuditAppendRequest
+	if err := decodeJSON(r, &req); err != nil {
+		writeBadRequest(w, "VALIDATION_ERROR", "invalid request body")
+		return
+	}
+	record, err := h.service.Append(r.Context(), actorTGID, req.Action, req.Payload)
+	if err != nil {
+		switch {
+		case errors.Is(err, auditsvc.ErrInvalidAction):
+			writeBadRequest(w, "VALIDATION_ERROR", "action is required")
+		case errors.Is(err, auditsvc.ErrInvalidPayload):
+			writeBadRequest(w, "VALIDATION_ERROR", "payload must be a json object")
+		default:
+			writeInternal(w, "INTERNAL_ERROR", "failed to append audit record")
+		}
+		return
+	}
+
+	httperrors.Write(w, http.StatusOK, toAdminAuditItem(record))
+}
+
+func (h *AdminAuditHandler) BotRecent(w http.ResponseWriter, r *http.Request) {
+	if _, ok := adminBotActorTGID(r); !ok {
+		writeUnauthorized(w, "UNAUTHORIZED", "admin bot authentication required")
+		return
+	}
+
+	limit := auditsvc.DefaultPageSize
+	if raw := strings.TrimSpace(r.URL.Query().Get("limit")); raw != "" {
+		parsed, err := strconv.Atoi(raw)
+		if err != nil || parsed <= 0 {
+			writeBadRequest(w, "VALIDATION_ERROR", "limit must be a positive integer")
+			return
+		}
+		limit = parsed
+	}
+	h.writeSearch(w, r, pgrepo.AuditFilter{Limit: limit})
+}
+
+func (h *AdminAuditHandler) BotSearch(w http.ResponseWriter, r *http.Request) {
+	if _, ok := adminBotActorTGID(r); !ok {
+		writeUnauthorized(w, "UNAUTHORIZED", "admin bot authentication required")
+		return
+	}
+	h.search(w, r)
+}
+
+func (h *AdminAuditHandler) BotVerify(w http.ResponseWriter, r *http.Request) {
+	if _, ok := adminBotActorTGID(r); !ok {
+		writeUnauthorized(w, "UNAUTHORIZED", "admin bot authentication required")
+		return
+	}
+	h.verify(w, r)
+}
+
+func (h *AdminAuditHandler) List(w http.ResponseWriter, r *http.Request) {
+	if _, ok := authsvc.IdentityFromContext(r.Context()); !ok {
+		writeUnauthorized(w, "UNAUTHORIZED", "authentication required")
+		return
+	}
+	h.search(w, r)
+}
+
+func (h *AdminAuditHandler) Verify(w http.ResponseWriter, r *http.Request) {
+	if _, ok := authsvc.IdentityFromContext(r.Context()); !ok {
+		writeUnauthorized(w, "UNAUTHORIZED", "authentication required")
+		return
+	}
+	h.verify(w, r)
+}
+
+func (h *AdminAuditHandler) Export(w http.ResponseWriter, r *http.Request) {
+	identity, ok := authsvc.IdentityFromContext(r.Context())
+	if !ok {
+		writeUnauthorized(w, "UNAUTHORIZED", "authentication required")
+		return
+	}
+	if h.service == nil {
+		writeInternal(w, "AUDIT_SERVICE_UNAVAILABLE", "audit service is unavailable")
+		return
+	}
+
+	filter, err := parseAuditFilter(r)
+	if err != nil {
+		writeBadRequest(w, "VALIDATION_ERROR", err.Error())
+		return
+	}
+	format := strings.ToLower(strings.TrimSpace(r.URL.Query().Get("format")))
+	if format == "" {
+		format = auditsvc.FormatCSV
+	}
+
+	// Buffer so a failed query still produces a proper error response instead of a truncated file.
+	var body bytes.Buffer
+	rows, err := h.service.Export(r.Context(), filter, format, &body)
+	if err != nil {
+		if errors.Is(err, auditsvc.ErrUnsupportedFormat) {
+			writeBadRequest(w, "VALIDATION_ERROR", "format must be csv or json")
+			return
+		}
+		writeInternal(w, "INTERNAL_ERROR", "failed to export audit records")
+		return
+	}
+
+	payload, _ := json.Marshal(map[string]any{
+		"source":        "web",
+		"actor_user_id": identity.UserID,
+		"format":        format,
+		"rows":          rows,
+		"query":         r.URL.RawQuery,
+	})
+	_, _ = h.service.Append(r.Context(), 0, "AUDIT_EXPORT", payload)
+
+	contentType := "text/csv; charset=utf-8"
+	if format == auditsvc.FormatJSON {
+		contentType = "application/json"
+	}
+	w.Header().Set("Content-Type", contentType)
+	w.Header().Set("Content-Disposition", fmt.Sprintf(
+		`attachment; filename="audit-%s.%s"`,
+		time.Now().UTC().Format("20060102-150405"),
+		format,
+	))
+	w.WriteHeader(http.StatusOK)
+	_, _ = w.Write(body.Bytes())
+}
+
+func (h *AdminAuditHandler) search(w http.ResponseWriter, r *http.Request) {
+	filter, err := parseAuditFilter(r)
+	if err != nil {
+		writeBadRequest(w, "VALIDATION_ERROR", err.Error())
+		return
+	}
+	h.writeSearch(w, r, filter)
+}
+
+func (h *AdminAuditHandler) writeSearch(w http.ResponseWriter, r *http.Request, filter pgrepo.AuditFilter) {
+	if h.service == nil {
+		writeInternal(w, "AUDIT_SERVICE_UNAVAILABLE", "audit service is unavailable")
+		return
+	}
+
+	page, err := h.service.Search(r.Context(), filter)
+	if err != nil {
+		writeInternal(w, "INTERNAL_ERROR", "failed to load audit records")
+		return
+	}
+
+	items := make([]dto.AdminAuditItem, 0, len(page.Items))
+	for _, record := range page.Items {
+		items = append(items, toAdminAuditItem(record))
+	}
+	httperrors.Write(w, http.StatusOK, dto.AdminAuditListResponse{
+		Items:   items,
+		Offset:  page.Offset,
+		Limit:   page.Limit,
+		HasMore: page.HasMore,
+	})
+}
+
+func (h *AdminAuditHandler) verify(w http.ResponseWriter, r *http.Request) {
+	if h.service == nil {
+		writeInternal(w, "AUDIT_SERVICE_UNAVAILABLE", "audit service is unavailable")
+		return
+	}
+
+	report, err := h.service.VerifyChain(r.Context())
+	if err != nil {
+		writeInternal(w, "INTERNAL_ERROR", "failed to verify audit chain")
+		return
+	}
+	httperrors.Write(w, http.StatusOK, dto.AdminAuditChainResponse{
+		Checked:  report.Checked,
+		OK:       report.OK,
+		BrokenID: report.BrokenID,
+	})
+}
+
+// parseAuditFilter accepts RFC3339 timestamps or YYYY-MM-DD days for from/to; a bare "to" day is inclusive.
+func parseAuditFilter(r *http.Request) (pgrepo.AuditFilter, error) {
+	query := r.URL.Query()
+	filter := pgrepo.AuditFilter{
+		Action: strings.TrimSpace(query.Get("action")),
+	}
+
+	parseID := func(key string) (int64, error) {
+		raw := strings.TrimSpace(query.Get(key))
+		if raw == "" {
+			return 0, nil
+		}
+		value, err := strconv.ParseInt(raw, 10, 64)
+		if err != nil || value <= 0 {
+			return 0, fmt.Errorf("%s must be a positive integer", key)
+		}
+		return value, nil
+	}
+
+	var err error
+	if filter.ActorTGID, err = parseID("actor_tg_id"); err != nil {
+		return pgrepo.AuditFilter{}, err
+	}
+	if filter.TargetUserID, err = parseID("target_user_id"); err != nil {
+		return pgrepo.AuditFilter{}, err
+	}
+	if filter.Limit, err = queryNonNegativeInt(query, "limit"); err != nil {
+		return pgrepo.AuditFilter{}, err
+	}
+	if filter.Offset, err = queryNonNegativeInt(query, "offset"); err != nil {
+		return pgrepo.AuditFilter{}, err
+	}
+	if filter.From, err = queryTimeBound(query, "from", false); err != nil {
+		return pgrepo.AuditFilter{}, err
+	}
+	if filter.To, err = queryTimeBound(query, "to", true); err != nil {
+		return pgrepo.AuditFilter{}, err
+	}
+	if filter.From != nil && filter.To != nil && !filter.From.Before(*filter.To) {
+		return pgrepo.AuditFilter{}, errors.New("from must be before to")
+	}
+	return filter, nil
+}
+
+func queryNonNegativeInt(query url.Values, key string) (int, error) {
+	raw := strings.TrimSpace(query.Get(key))
+	if raw == "" {
+		return 0, nil
+	}
+	value, err := strconv.Atoi(raw)
+	if err != nil || value < 0 {
+		return 0, fmt.Errorf("%s must be a non-negative integer", key)
+	}
+	return value, nil
+}
+
+// queryTimeBound parses an RFC3339 timestamp or a YYYY-MM-DD day; inclusiveDay moves a bare day to its end.
+func queryTimeBound(query url.Values, key string, inclusiveDay bool) (*time.Time, error) {
+	raw := strings.TrimSpace(query.Get(key))
+	if raw == "" {
+		return nil, nil
+	}
+	if ts, err := time.Parse(time.RFC3339, raw); err == nil {
+		ts = ts.UTC()
+		return &ts, nil
+	}
+	day, ok := parseDayDate(raw)
+	if !ok {
+		return nil, fmt.Errorf("%s must be RFC3339 or YYYY-MM-DD", key)
+	}
+	if inclusiveDay {
+		day = day.Add(24 * time.Hour)
+	}
+	return &day, nil
+}
+
+func toAdminAuditItem(record pgrepo.AuditRecord) dto.AdminAuditItem {
+	item := auditsvc.ToExportItem(record)
+	return dto.AdminAuditItem{
+		ID:        item.ID,
+		Seq:       item.Seq,
+		ActorTGID: item.ActorTGID,
+		Action:    item.Action,
+		Payload:   item.Payload,
+		PrevHash:  item.PrevHash,
+		Hash:      item.Hash,
+		CreatedAt: item.CreatedAt,
+	}
+}
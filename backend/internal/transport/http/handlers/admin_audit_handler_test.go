@@ -0,0 +1,130 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	pgrepo "github.com/ivankudzin/tgapp/backend/internal/repo/postgres"
+	auditsvc "github.com/ivankudzin/tgapp/backend/internal/services/audit"
+	authsvc "github.com/ivankudzin/tgapp/backend/internal/services/auth"
+	"github.com/ivankudzin/tgapp/backend/internal/transport/http/dto"
+)
+
+func TestAdminAuditListAppliesFilter(t *testing.T) {
+	repo := &auditRepoStub{records: []pgrepo.AuditRecord{
+		{ID: "a1", Seq: 2, ActorTGID: 7, Action: "BAN_USER", Payload: json.RawMessage(`{"target_user_id":42}`), CreatedAt: time.Date(2026, 1, 2, 10, 0, 0, 0, time.UTC)},
+		{ID: "a0", Seq: 1, ActorTGID: 7, Action: "BAN_USER", Payload: json.RawMessage(`{"target_user_id":42}`), CreatedAt: time.Date(2026, 1, 2, 9, 0, 0, 0, time.UTC)},
+	}}
+	handler := NewAdminAuditHandler(auditsvc.NewService(repo))
+
+	req := httptest.NewRequest(http.MethodGet, "/admin/audit?actor_tg_id=7&target_user_id=42&action=ban_user&from=2026-01-01&to=2026-01-02&limit=1", nil)
+	req = req.WithContext(authsvc.WithIdentity(req.Context(), authsvc.Identity{UserID: 1, SID: "sid-1", Role: "OWNER"}))
+	rr := httptest.NewRecorder()
+	handler.List(rr, req)
+
+	if rr.Code != http.StatusOK {
+		t.Fatalf("unexpected status: got %d want %d", rr.Code, http.StatusOK)
+	}
+	var resp dto.AdminAuditListResponse
+	if err := json.Unmarshal(rr.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("decode response: %v", err)
+	}
+	if len(resp.Items) != 1 || !resp.HasMore {
+		t.Fatalf("unexpected page: %+v", resp)
+	}
+
+	got := repo.lastFilter
+	if got.ActorTGID != 7 || got.TargetUserID != 42 || got.Action != "BAN_USER" {
+		t.Fatalf("unexpected filter: %+v", got)
+	}
+	if got.From == nil || !got.From.Equal(time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)) {
+		t.Fatalf("unexpected from: %v", got.From)
+	}
+	if got.To == nil || !got.To.Equal(time.Date(2026, 1, 3, 0, 0, 0, 0, time.UTC)) {
+		t.Fatalf("unexpected inclusive to: %v", got.To)
+	}
+}
+
+func TestAdminAuditListRejectsInvalidRange(t *testing.T) {
+	handler := NewAdminAuditHandler(auditsvc.NewService(&auditRepoStub{}))
+
+	req := httptest.NewRequest(http.MethodGet, "/admin/audit?from=2026-01-05&to=2026-01-01", nil)
+	req = req.WithContext(authsvc.WithIdentity(req.Context(), authsvc.Identity{UserID: 1, SID: "sid-1", Role: "OWNER"}))
+	rr := httptest.NewRecorder()
+	handler.List(rr, req)
+
+	if rr.Code != http.StatusBadRequest {
+		t.Fatalf("unexpected status: got %d want %d", rr.Code, http.StatusBadRequest)
+	}
+}
+
+func TestAdminAuditExportWritesCSVAndAuditsItself(t *testing.T) {
+	repo := &auditRepoStub{records: []pgrepo.AuditRecord{
+		{ID: "a1", Seq: 1, ActorTGID: 7, Action: "BAN_USER", Payload: json.RawMessage(`{"target_user_id":42}`), Hash: "h1", CreatedAt: time.Date(2026, 1, 2, 10, 0, 0, 0, time.UTC)},
+	}}
+	handler := NewAdminAuditHandler(auditsvc.NewService(repo))
+
+	req := httptest.NewRequest(http.MethodGet, "/admin/audit/export?format=csv", nil)
+	req = req.WithContext(authsvc.WithIdentity(req.Context(), authsvc.Identity{UserID: 5, SID: "sid-5", Role: "OWNER"}))
+	rr := httptest.NewRecorder()
+	handler.Export(rr, req)
+
+	if rr.Code != http.StatusOK {
+		t.Fatalf("unexpected status: got %d want %d", rr.Code, http.StatusOK)
+	}
+	if ct := rr.Header().Get("Content-Type"); !strings.HasPrefix(ct, "text/csv") {
+		t.Fatalf("unexpected content type: %q", ct)
+	}
+	lines := strings.Split(strings.TrimSpace(rr.Body.String()), "\n")
+	if len(lines) != 2 || !strings.HasPrefix(lines[0], "id,seq,created_at") {
+		t.Fatalf("unexpected csv body: %q", rr.Body.String())
+	}
+	if len(repo.appended) != 1 || repo.appended[0].Action != "AUDIT_EXPORT" {
+		t.Fatalf("expected export to be audited, got %+v", repo.appended)
+	}
+}
+
+func TestAdminAuditExportRejectsUnknownFormat(t *testing.T) {
+	handler := NewAdminAuditHandler(auditsvc.NewService(&auditRepoStub{}))
+
+	req := httptest.NewRequest(http.MethodGet, "/admin/audit/export?format=xml", nil)
+	req = req.WithContext(authsvc.WithIdentity(req.Context(), authsvc.Identity{UserID: 5, SID: "sid-5", Role: "OWNER"}))
+	rr := httptest.NewRecorder()
+	handler.Export(rr, req)
+
+	if rr.Code != http.StatusBadRequest {
+		t.Fatalf("unexpected status: got %d want %d", rr.Code, http.StatusBadRequest)
+	}
+}
+
+type auditRepoStub struct {
+	records    []pgrepo.AuditRecord
+	appended   []pgrepo.AuditRecord
+	lastFilter pgrepo.AuditFilter
+	appendErr  error
+}
+
+func (s *auditRepoStub) Append(_ context.Context, in pgrepo.AuditRecord) (pgrepo.AuditRecord, error) {
+	if s.appendErr != nil {
+		return pgrepo.AuditRecord{}, s.appendErr
+	}
+	s.appended = append(s.appended, in)
+	return in, nil
+}
+
+func (s *auditRepoStub) Search(_ context.Context, filter pgrepo.AuditFilter) ([]pgrepo.AuditRecord, error) {
+	s.lastFilter = filter
+	if filter.Limit > 0 && len(s.records) > filter.Limit {
+		return s.records[:filter.Limit], nil
+	}
+	return s.records, nil
+}
+
+func (s *auditRepoStub) VerifyChain(context.Context) (pgrepo.AuditChainReport, error) {
+	return pgrepo.AuditChainReport{Checked: len(s.records), OK: true}, nil
+}
@@ -0,0 +1,114 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	auditsvc "github.com/ivankudzin/tgapp/backend/internal/services/audit"
+	authsvc "github.com/ivankudzin/tgapp/backend/internal/services/auth"
+	balancesvc "github.com/ivankudzin/tgapp/backend/internal/services/balance"
+	"github.com/ivankudzin/tgapp/backend/internal/transport/http/dto"
+	httperrors "github.com/ivankudzin/tgapp/backend/internal/transport/http/errors"
+)
+
+type AdminBalanceHandler struct {
+	balance *balancesvc.Service
+	audit   *auditsvc.Service
+}
+
+func NewAdminBalanceHandler(balance *balancesvc.Service, audit *auditsvc.Service) *AdminBalanceHandler {
+	return &AdminBalanceHandler{balance: balance, audit: audit}
+}
+
+// Report returns the supply ratios per city and what the controller does about them.
+func (h *AdminBalanceHandler) Report(w http.ResponseWriter, r *http.Request) {
+	if _, ok := authsvc.IdentityFromContext(r.Context()); !ok {
+		writeUnauthorized(w, "UNAUTHORIZED", "authentication required")
+		return
+	}
+	if h.balance == nil {
+		writeInternal(w, "BALANCE_UNAVAILABLE", "supply balance service is unavailable")
+		return
+	}
+
+	report, err := h.balance.Report(r.Context())
+	if err != nil {
+		writeInternal(w, "INTERNAL_ERROR", "failed to load supply balance")
+		return
+	}
+	httperrors.Write(w, http.StatusOK, toAdminBalanceResponse(report))
+}
+
+// Recompute refreshes the decisions without waiting for the next tick, e.g. after a config publish.
+func (h *AdminBalanceHandler) Recompute(w http.ResponseWriter, r *http.Request) {
+	identity, ok := authsvc.IdentityFromContext(r.Context())
+	if !ok {
+		writeUnauthorized(w, "UNAUTHORIZED", "authentication required")
+		return
+	}
+	if h.balance == nil {
+		writeInternal(w, "BALANCE_UNAVAILABLE", "supply balance service is unavailable")
+		return
+	}
+
+	report, err := h.balance.Recompute(r.Context())
+	if err != nil {
+		if errors.Is(err, balancesvc.ErrUnavailable) {
+			writeInternal(w, "BALANCE_UNAVAILABLE", "supply balance store is not configured")
+			return
+		}
+		writeInternal(w, "INTERNAL_ERROR", "failed to recompute supply balance")
+		return
+	}
+
+	if h.audit != nil {
+		actorTGID, _ := authsvc.ActorTGIDFromContext(r.Context())
+		throttled := make([]string, 0)
+		for _, city := range report.Cities {
+			if city.ThrottleAdmissions {
+				throttled = append(throttled, city.CityID)
+			}
+		}
+		payload, _ := json.Marshal(map[string]any{
+			"source":           "web",
+			"actor_user_id":    identity.UserID,
+			"enabled":          report.Enabled,
+			"cities":           len(report.Cities),
+			"throttled_cities": throttled,
+		})
+		_, _ = h.audit.Append(r.Context(), actorTGID, "SUPPLY_BALANCE_RECOMPUTE", payload)
+	}
+	httperrors.Write(w, http.StatusOK, toAdminBalanceResponse(report))
+}
+
+func toAdminBalanceResponse(report balancesvc.Report) dto.AdminBalanceResponse {
+	resp := dto.AdminBalanceResponse{
+		Enabled:      report.Enabled,
+		ActiveWindow: report.ActiveWindow.String(),
+		Tolerance:    report.Tolerance,
+		ComputedAt:   report.ComputedAt,
+		Cities:       make([]dto.AdminBalanceCity, 0, len(report.Cities)),
+	}
+	for _, city := range report.Cities {
+		resp.Cities = append(resp.Cities, dto.AdminBalanceCity{
+			CityID:               city.CityID,
+			ActiveFemale:         city.ActiveFemale,
+			ActiveMale:           city.ActiveMale,
+			FemaleShare:          city.FemaleShare,
+			TargetShare:          city.TargetShare,
+			State:                city.State,
+			UnderGender:          city.UnderGender,
+			OverGender:           city.OverGender,
+			LikesFemale:          city.LikesFemale,
+			LikesMale:            city.LikesMale,
+			WaitingFemale:        city.WaitingFemale,
+			WaitingMale:          city.WaitingMale,
+			ThrottleAdmissions:   city.ThrottleAdmissions,
+			PrioritizeModeration: city.PrioritizeModeration,
+			OverLikeFactor:       city.OverLikeFactor,
+			UnderLikeFactor:      city.UnderLikeFactor,
+		})
+	}
+	return resp
+}
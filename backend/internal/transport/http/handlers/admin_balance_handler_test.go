@@ -0,0 +1,81 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/ivankudzin/tgapp/backend/internal/config"
+	pgrepo "github.com/ivankudzin/tgapp/backend/internal/repo/postgres"
+	authsvc "github.com/ivankudzin/tgapp/backend/internal/services/auth"
+	balancesvc "github.com/ivankudzin/tgapp/backend/internal/services/balance"
+	"github.com/ivankudzin/tgapp/backend/internal/transport/http/dto"
+)
+
+// adminBalanceStoreStub implements only what these tests reach; other methods panic through
+// the nil embedded interface.
+type adminBalanceStoreStub struct {
+	balancesvc.Store
+
+	saved []pgrepo.SupplyBalanceRecord
+}
+
+func (s *adminBalanceStoreStub) ListSupplyBalance(context.Context) ([]pgrepo.SupplyBalanceRecord, error) {
+	return s.saved, nil
+}
+
+func TestAdminBalanceReportServesStoredDecisions(t *testing.T) {
+	under, over := balancesvc.GenderFemale, balancesvc.GenderMale
+	store := &adminBalanceStoreStub{saved: []pgrepo.SupplyBalanceRecord{{
+		CityID:             "minsk",
+		ActiveFemale:       30,
+		ActiveMale:         70,
+		FemaleShare:        0.3,
+		TargetShare:        0.5,
+		State:              balancesvc.StateFemaleShort,
+		UnderGender:        &under,
+		OverGender:         &over,
+		ThrottleAdmissions: true,
+		OverLikeFactor:     0.8,
+		UnderLikeFactor:    1,
+		ComputedAt:         time.Date(2026, 10, 19, 9, 0, 0, 0, time.UTC),
+	}}}
+	cfg := config.Default().Remote.Balance
+	cfg.Enabled = true
+	handler := NewAdminBalanceHandler(balancesvc.NewService(store, cfg), nil)
+
+	req := httptest.NewRequest(http.MethodGet, "/admin/balance", nil)
+	req = req.WithContext(authsvc.WithIdentity(req.Context(), authsvc.Identity{UserID: 1, Role: "ADMIN"}))
+	rr := httptest.NewRecorder()
+
+	handler.Report(rr, req)
+
+	if rr.Code != http.StatusOK {
+		t.Fatalf("unexpected status: got=%d body=%s", rr.Code, rr.Body.String())
+	}
+	var resp dto.AdminBalanceResponse
+	if err := json.Unmarshal(rr.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("decode response: %v", err)
+	}
+	if !resp.Enabled || resp.ActiveWindow != "168h0m0s" || resp.ComputedAt == nil || len(resp.Cities) != 1 {
+		t.Fatalf("unexpected response: %+v", resp)
+	}
+	city := resp.Cities[0]
+	if city.State != balancesvc.StateFemaleShort || city.UnderGender != "female" || !city.ThrottleAdmissions || city.OverLikeFactor != 0.8 {
+		t.Fatalf("unexpected city decision: %+v", city)
+	}
+}
+
+func TestAdminBalanceReportRequiresIdentity(t *testing.T) {
+	handler := NewAdminBalanceHandler(balancesvc.NewService(nil, config.BalanceConfig{}), nil)
+	rr := httptest.NewRecorder()
+
+	handler.Report(rr, httptest.NewRequest(http.MethodGet, "/admin/balance", nil))
+
+	if rr.Code != http.StatusUnauthorized {
+		t.Fatalf("expected 401, got %d", rr.Code)
+	}
+}
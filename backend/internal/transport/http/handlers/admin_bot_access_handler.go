@@ -0,0 +1,122 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strings"
+
+	"github.com/go-chi/chi/v5"
+
+	auditsvc "github.com/ivankudzin/tgapp/backend/internal/services/audit"
+	authsvc "github.com/ivankudzin/tgapp/backend/internal/services/auth"
+	permissionssvc "github.com/ivankudzin/tgapp/backend/internal/services/permissions"
+	"github.com/ivankudzin/tgapp/backend/internal/transport/http/dto"
+	httperrors "github.com/ivankudzin/tgapp/backend/internal/transport/http/errors"
+)
+
+type AdminBotAccessHandler struct {
+	service *permissionssvc.Service
+	audit   *auditsvc.Service
+}
+
+func NewAdminBotAccessHandler(service *permissionssvc.Service, audit *auditsvc.Service) *AdminBotAccessHandler {
+	return &AdminBotAccessHandler{service: service, audit: audit}
+}
+
+func (h *AdminBotAccessHandler) ListRoles(w http.ResponseWriter, r *http.Request) {
+	if _, ok := adminBotActorTGID(r); !ok {
+		writeUnauthorized(w, "UNAUTHORIZED", "admin bot authentication required")
+		return
+	}
+	if h.service == nil {
+		writeInternal(w, "PERMISSIONS_UNAVAILABLE", "permissions service is unavailable")
+		return
+	}
+
+	roles, err := h.service.ListRoles(r.Context())
+	if err != nil {
+		writeInternal(w, "INTERNAL_ERROR", "failed to load roles")
+		return
+	}
+
+	resp := dto.AdminRolesResponse{
+		Permissions: permissionssvc.Known(),
+		Items:       make([]dto.AdminRoleItem, 0, len(roles)),
+	}
+	for _, role := range roles {
+		resp.Items = append(resp.Items, toAdminRoleItemDTO(role))
+	}
+	httperrors.Write(w, http.StatusOK, resp)
+}
+
+// UpsertRole creates or replaces a custom role. Only OWNER may define roles, whatever permissions
+// the caller's own role carries.
+func (h *AdminBotAccessHandler) UpsertRole(w http.ResponseWriter, r *http.Request) {
+	actorTGID, ok := adminBotActorTGID(r)
+	if !ok {
+		writeUnauthorized(w, "UNAUTHORIZED", "admin bot authentication required")
+		return
+	}
+	if role, _ := authsvc.ActorRoleFromContext(r.Context()); permissionssvc.NormalizeRoleName(role) != permissionssvc.RoleOwner {
+		httperrors.Write(w, http.StatusForbidden, httperrors.APIError{
+			Code:    "FORBIDDEN",
+			Message: "only OWNER can define roles",
+		})
+		return
+	}
+	if h.service == nil {
+		writeInternal(w, "PERMISSIONS_UNAVAILABLE", "permissions service is unavailable")
+		return
+	}
+
+	name := strings.TrimSpace(chi.URLParam(r, "name"))
+	var req dto.AdminRoleUpsertRequest
+	if err := decodeJSON(r, &req); err != nil {
+		writeBadRequest(w, "VALIDATION_ERROR", "invalid request body")
+		return
+	}
+
+	saved, err := h.service.SaveCustomRole(r.Context(), permissionssvc.Role{Name: name, Permissions: req.Permissions}, actorTGID)
+	if err != nil {
+		switch {
+		case errors.Is(err, permissionssvc.ErrInvalidRole):
+			writeBadRequest(w, "VALIDATION_ERROR", err.Error())
+		case errors.Is(err, permissionssvc.ErrSystemRole):
+			httperrors.Write(w, http.StatusConflict, httperrors.APIError{
+				Code:    "SYSTEM_ROLE",
+				Message: "system roles cannot be changed",
+			})
+		case errors.Is(err, permissionssvc.ErrRolesReadOnly):
+			writeInternal(w, "ROLES_READ_ONLY", "role catalog is not configured")
+		default:
+			writeInternal(w, "INTERNAL_ERROR", "failed to save role")
+		}
+		return
+	}
+
+	if h.audit != nil {
+		payload, _ := json.Marshal(map[string]any{
+			"source":      "bot",
+			"role":        saved.Name,
+			"permissions": saved.Permissions,
+		})
+		_, _ = h.audit.Append(r.Context(), actorTGID, "ADMIN_ROLE_UPDATE", payload)
+	}
+
+	httperrors.Write(w, http.StatusOK, toAdminRoleItemDTO(saved))
+}
+
+func toAdminRoleItemDTO(role permissionssvc.Role) dto.AdminRoleItem {
+	out := dto.AdminRoleItem{
+		Name:          role.Name,
+		Permissions:   append([]string{}, role.Permissions...),
+		IsSystem:      role.IsSystem,
+		UpdatedByTGID: role.UpdatedByTGID,
+	}
+	if !role.UpdatedAt.IsZero() {
+		updatedAt := role.UpdatedAt.UTC()
+		out.UpdatedAt = &updatedAt
+	}
+	return out
+}
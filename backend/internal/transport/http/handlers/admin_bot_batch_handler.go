@@ -0,0 +1,137 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+
+	pgrepo "github.com/ivankudzin/tgapp/backend/internal/repo/postgres"
+	modsvc "github.com/ivankudzin/tgapp/backend/internal/services/moderation"
+	"github.com/ivankudzin/tgapp/backend/internal/transport/http/dto"
+	httperrors "github.com/ivankudzin/tgapp/backend/internal/transport/http/errors"
+)
+
+func (h *AdminBotModerationHandler) QueueAcquireBatch(w http.ResponseWriter, r *http.Request) {
+	actorTGID, ok := adminBotActorTGID(r)
+	if !ok {
+		writeUnauthorized(w, "UNAUTHORIZED", "admin bot authentication required")
+		return
+	}
+	if h.service == nil {
+		writeInternal(w, "MODERATION_SERVICE_UNAVAILABLE", "moderation service is unavailable")
+		return
+	}
+
+	var req dto.AdminBotModQueueBatchRequest
+	if err := decodeJSON(r, &req); err != nil {
+		writeBadRequest(w, "VALIDATION_ERROR", "invalid request body")
+		return
+	}
+
+	items, err := h.service.AcquireQueueBatch(r.Context(), actorTGID, req.Limit)
+	if err != nil {
+		switch {
+		case errors.Is(err, modsvc.ErrQueueEmpty):
+			w.WriteHeader(http.StatusNoContent)
+		case errors.Is(err, modsvc.ErrInvalidBatch):
+			writeBadRequest(w, "VALIDATION_ERROR", err.Error())
+		default:
+			writeInternal(w, "INTERNAL_ERROR", "failed to acquire moderation batch")
+		}
+		return
+	}
+
+	resp := dto.AdminBotModQueueBatchResponse{
+		Items: make([]dto.AdminBotModQueueAcquireResponse, 0, len(items)),
+	}
+	for _, item := range items {
+		resp.Items = append(resp.Items, toAdminBotModQueueAcquireResponse(item))
+	}
+	httperrors.Write(w, http.StatusOK, resp)
+}
+
+func (h *AdminBotModerationHandler) DecideBatch(w http.ResponseWriter, r *http.Request) {
+	actorTGID, ok := adminBotActorTGID(r)
+	if !ok {
+		writeUnauthorized(w, "UNAUTHORIZED", "admin bot authentication required")
+		return
+	}
+	if h.service == nil {
+		writeInternal(w, "MODERATION_SERVICE_UNAVAILABLE", "moderation service is unavailable")
+		return
+	}
+
+	var req dto.AdminBotModerationBatchDecideRequest
+	if err := decodeJSON(r, &req); err != nil {
+		writeBadRequest(w, "VALIDATION_ERROR", "invalid request body")
+		return
+	}
+
+	decisions := make([]modsvc.BatchDecision, 0, len(req.Decisions))
+	for _, item := range req.Decisions {
+		decisions = append(decisions, modsvc.BatchDecision{
+			ItemID:          item.ModerationItemID,
+			Decision:        item.Decision,
+			ReasonCode:      item.ReasonCode,
+			ReasonText:      item.ReasonText,
+			RequiredFixStep: item.RequiredFixStep,
+		})
+	}
+
+	decided, err := h.service.DecideBatch(r.Context(), actorTGID, decisions)
+	if err != nil {
+		switch {
+		case errors.Is(err, modsvc.ErrInvalidBatch):
+			writeBadRequest(w, "VALIDATION_ERROR", err.Error())
+		case errors.Is(err, modsvc.ErrInvalidReasonCode):
+			writeBadRequest(w, "VALIDATION_ERROR", "invalid reason_code")
+		case errors.Is(err, pgrepo.ErrModerationItemNotFound):
+			httperrors.Write(w, http.StatusNotFound, httperrors.APIError{
+				Code:    "NOT_FOUND",
+				Message: err.Error(),
+			})
+		case errors.Is(err, pgrepo.ErrModerationItemNotPending):
+			httperrors.Write(w, http.StatusConflict, httperrors.APIError{
+				Code:    "ITEM_NOT_PENDING",
+				Message: err.Error(),
+			})
+		case errors.Is(err, modsvc.ErrCircleMissing):
+			httperrors.Write(w, http.StatusConflict, httperrors.APIError{
+				Code:    "CIRCLE_MISSING",
+				Message: err.Error(),
+			})
+		default:
+			writeInternal(w, "INTERNAL_ERROR", "failed to apply moderation batch")
+		}
+		return
+	}
+
+	byID := make(map[int64]dto.AdminBotModerationBatchDecision, len(req.Decisions))
+	for _, item := range req.Decisions {
+		byID[item.ModerationItemID] = item
+	}
+	resp := dto.AdminBotModerationBatchDecideResponse{
+		Items: make([]dto.AdminBotModerationBatchDecidedItem, 0, len(decided)),
+	}
+	for _, item := range decided {
+		decision := byID[item.ID]
+		if strings.EqualFold(item.Status, "APPROVED") {
+			h.logModerationAudit(r, "MODERATION_APPROVE", actorTGID, item.ID, map[string]any{
+				"verified": strings.EqualFold(strings.TrimSpace(decision.Decision), pgrepo.ModerationBatchApproveVerified),
+				"batch":    true,
+			})
+		} else {
+			h.logModerationAudit(r, "MODERATION_REJECT", actorTGID, item.ID, map[string]any{
+				"reason_code": strings.ToUpper(strings.TrimSpace(decision.ReasonCode)),
+				"batch":       true,
+			})
+		}
+		resp.Items = append(resp.Items, dto.AdminBotModerationBatchDecidedItem{
+			ModerationItemID: item.ID,
+			UserID:           item.UserID,
+			Status:           item.Status,
+			ReviewStartedAt:  item.LockedAt,
+		})
+	}
+	httperrors.Write(w, http.StatusOK, resp)
+}
@@ -0,0 +1,224 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+
+	userssvc "github.com/ivankudzin/tgapp/backend/internal/services/users"
+	"github.com/ivankudzin/tgapp/backend/internal/transport/http/dto"
+	httperrors "github.com/ivankudzin/tgapp/backend/internal/transport/http/errors"
+)
+
+func (h *AdminBotUsersHandler) Dossier(w http.ResponseWriter, r *http.Request) {
+	actorTGID, ok := adminBotActorTGIDForUsers(r)
+	if !ok {
+		writeUnauthorized(w, "UNAUTHORIZED", "admin bot authentication required")
+		return
+	}
+	if h.users == nil {
+		writeInternal(w, "USERS_SERVICE_UNAVAILABLE", "users service is unavailable")
+		return
+	}
+
+	values := r.URL.Query()
+	userID, err := strconv.ParseInt(strings.TrimSpace(values.Get("user_id")), 10, 64)
+	if err != nil || userID <= 0 {
+		writeBadRequest(w, "VALIDATION_ERROR", "invalid user id")
+		return
+	}
+	limit, ok := optionalQueryInt(values.Get("limit"))
+	if !ok {
+		writeBadRequest(w, "VALIDATION_ERROR", "invalid limit")
+		return
+	}
+	offset, ok := optionalQueryInt(values.Get("offset"))
+	if !ok {
+		writeBadRequest(w, "VALIDATION_ERROR", "invalid offset")
+		return
+	}
+
+	dossier, err := h.users.Dossier(r.Context(), userssvc.DossierQuery{
+		UserID:  userID,
+		Section: values.Get("section"),
+		Limit:   limit,
+		Offset:  offset,
+	})
+	if err != nil {
+		switch {
+		case errors.Is(err, userssvc.ErrValidation):
+			writeBadRequest(w, "VALIDATION_ERROR", err.Error())
+		case errors.Is(err, userssvc.ErrNotFound):
+			httperrors.Write(w, http.StatusNotFound, httperrors.APIError{
+				Code:    "NOT_FOUND",
+				Message: "user not found",
+			})
+		default:
+			writeInternal(w, "INTERNAL_ERROR", "failed to load dossier")
+		}
+		return
+	}
+
+	h.logAuditAction(r, "VIEW_DOSSIER", actorTGID, userID, map[string]any{
+		"section": dossier.Section,
+		"offset":  dossier.Offset,
+	})
+	httperrors.Write(w, http.StatusOK, toDossierDTO(dossier))
+}
+
+func optionalQueryInt(raw string) (int, bool) {
+	raw = strings.TrimSpace(raw)
+	if raw == "" {
+		return 0, true
+	}
+	value, err := strconv.Atoi(raw)
+	if err != nil || value < 0 {
+		return 0, false
+	}
+	return value, true
+}
+
+func toDossierDTO(in userssvc.Dossier) dto.AdminBotDossierResponse {
+	out := dto.AdminBotDossierResponse{
+		UserID:  in.UserID,
+		Section: in.Section,
+		Limit:   in.Limit,
+		Offset:  in.Offset,
+	}
+
+	if in.Risk != nil {
+		events := make([]dto.AdminBotDossierRiskEvent, 0, len(in.Risk.Events))
+		for _, event := range in.Risk.Events {
+			events = append(events, dto.AdminBotDossierRiskEvent{
+				Name:       event.Name,
+				OccurredAt: event.OccurredAt,
+				Payload:    event.Payload,
+			})
+		}
+		out.Risk = &dto.AdminBotDossierRisk{
+			Available:       in.Risk.Available,
+			RiskScore:       in.Risk.RiskScore,
+			CooldownUntil:   in.Risk.CooldownUntil,
+			LastViolationAt: in.Risk.LastViolationAt,
+			ShadowEnabled:   in.Risk.ShadowEnabled,
+			Events:          events,
+			EventsTotal:     in.Risk.EventsTotal,
+		}
+	}
+	if in.Activity != nil {
+		out.Activity = &dto.AdminBotDossierActivity{
+			Days:             in.Activity.Days,
+			Likes:            in.Activity.Likes,
+			Superlikes:       in.Activity.Superlikes,
+			Dislikes:         in.Activity.Dislikes,
+			LikeRatio:        in.Activity.LikeRatio,
+			SegmentLikeRatio: in.Activity.SegmentLikeRatio,
+			LikesReceived:    in.Activity.LikesReceived,
+			Swipes1h:         in.Activity.Swipes1h,
+			Swipes24h:        in.Activity.Swipes24h,
+			TooFastHits:      in.Activity.TooFastHits,
+		}
+	}
+	if in.Reports != nil {
+		out.Reports = &dto.AdminBotDossierReports{
+			Received:      toDossierReportDTOs(in.Reports.Received),
+			ReceivedTotal: in.Reports.ReceivedTotal,
+			Filed:         toDossierReportDTOs(in.Reports.Filed),
+			FiledTotal:    in.Reports.FiledTotal,
+		}
+	}
+	if in.Devices != nil {
+		devices := make([]dto.AdminBotDossierDevice, 0, len(in.Devices.Devices))
+		for _, device := range in.Devices.Devices {
+			devices = append(devices, dto.AdminBotDossierDevice{
+				DeviceID:    device.DeviceID,
+				FirstSeenAt: device.FirstSeenAt,
+				LastSeenAt:  device.LastSeenAt,
+				IsBlocked:   device.IsBlocked,
+				SharedWith:  device.SharedWith,
+			})
+		}
+		linked := make([]dto.AdminBotDossierLinkedAccount, 0, len(in.Devices.LinkedAccounts))
+		for _, account := range in.Devices.LinkedAccounts {
+			linked = append(linked, dto.AdminBotDossierLinkedAccount{
+				UserID:  account.UserID,
+				TGID:    account.TGID,
+				Signals: append([]string(nil), account.Signals...),
+				Banned:  account.Banned,
+			})
+		}
+		out.Devices = &dto.AdminBotDossierDevices{
+			Devices:        devices,
+			Total:          in.Devices.Total,
+			LinkedAccounts: linked,
+		}
+	}
+	if in.Purchases != nil {
+		items := make([]dto.AdminBotDossierPurchase, 0, len(in.Purchases.Items))
+		for _, item := range in.Purchases.Items {
+			items = append(items, dto.AdminBotDossierPurchase{
+				ID:          item.ID,
+				SKU:         item.SKU,
+				Provider:    item.Provider,
+				Status:      item.Status,
+				AmountMinor: item.AmountMinor,
+				Currency:    item.Currency,
+				CreatedAt:   item.CreatedAt,
+			})
+		}
+		out.Purchases = &dto.AdminBotDossierPurchases{Items: items, Total: in.Purchases.Total}
+	}
+	if in.Moderation != nil {
+		items := make([]dto.AdminBotDossierModerationDecision, 0, len(in.Moderation.Items))
+		for _, item := range in.Moderation.Items {
+			items = append(items, dto.AdminBotDossierModerationDecision{
+				ModerationItemID: item.ModerationItemID,
+				TargetType:       item.TargetType,
+				Status:           item.Status,
+				ReasonCode:       item.ReasonCode,
+				ModeratorTGID:    item.ModeratorTGID,
+				CreatedAt:        item.CreatedAt,
+				DecidedAt:        item.DecidedAt,
+				ReviewKind:       item.ReviewKind,
+				ReviewStatus:     item.ReviewStatus,
+			})
+		}
+		out.Moderation = &dto.AdminBotDossierModeration{Items: items, Total: in.Moderation.Total}
+	}
+	if in.Support != nil {
+		items := make([]dto.AdminBotDossierSupportConversation, 0, len(in.Support.Items))
+		for _, item := range in.Support.Items {
+			items = append(items, dto.AdminBotDossierSupportConversation{
+				ID:            item.ID,
+				Status:        item.Status,
+				MessagesCount: item.MessagesCount,
+				LastMessage:   item.LastMessage,
+				CreatedAt:     item.CreatedAt,
+				UpdatedAt:     item.UpdatedAt,
+			})
+		}
+		out.Support = &dto.AdminBotDossierSupport{
+			Available: in.Support.Available,
+			Items:     items,
+			Total:     in.Support.Total,
+		}
+	}
+
+	return out
+}
+
+func toDossierReportDTOs(reports []userssvc.DossierReport) []dto.AdminBotDossierReport {
+	items := make([]dto.AdminBotDossierReport, 0, len(reports))
+	for _, report := range reports {
+		items = append(items, dto.AdminBotDossierReport{
+			ID:                report.ID,
+			CounterpartUserID: report.CounterpartUserID,
+			Reason:            report.Reason,
+			Details:           report.Details,
+			Status:            report.Status,
+			CreatedAt:         report.CreatedAt,
+		})
+	}
+	return items
+}
@@ -12,6 +12,7 @@ import (
 	pgrepo "github.com/ivankudzin/tgapp/backend/internal/repo/postgres"
 	analyticsvc "github.com/ivankudzin/tgapp/backend/internal/services/analytics"
 	authsvc "github.com/ivankudzin/tgapp/backend/internal/services/auth"
+	mediasvc "github.com/ivankudzin/tgapp/backend/internal/services/media"
 	modsvc "github.com/ivankudzin/tgapp/backend/internal/services/moderation"
 	"github.com/ivankudzin/tgapp/backend/internal/transport/http/dto"
 	httperrors "github.com/ivankudzin/tgapp/backend/internal/transport/http/errors"
@@ -51,13 +52,11 @@ func (h *AdminBotModerationHandler) QueueAcquire(w http.ResponseWriter, r *http.
 		return
 	}
 
-	var birthdate *string
-	if item.Profile.Birthdate != nil {
-		v := item.Profile.Birthdate.UTC().Format("2006-01-02")
-		birthdate = &v
-	}
+	httperrors.Write(w, http.StatusOK, toAdminBotModQueueAcquireResponse(item))
+}
 
-	httperrors.Write(w, http.StatusOK, dto.AdminBotModQueueAcquireResponse{
+func toAdminBotModQueueAcquireResponse(item modsvc.QueueItem) dto.AdminBotModQueueAcquireResponse {
+	return dto.AdminBotModQueueAcquireResponse{
 		ModerationItem: dto.AdminBotModerationItem{
 			ID:           item.ItemID,
 			UserID:       item.UserID,
@@ -68,22 +67,97 @@ func (h *AdminBotModerationHandler) QueueAcquire(w http.ResponseWriter, r *http.
 			LockedAt:     item.LockedAt,
 			LockedUntil:  item.LockedUntil,
 		},
-		Profile: dto.AdminBotProfileCard{
-			UserID:      item.Profile.UserID,
-			DisplayName: item.Profile.DisplayName,
-			CityID:      item.Profile.CityID,
-			Gender:      item.Profile.Gender,
-			LookingFor:  item.Profile.LookingFor,
-			Goals:       item.Profile.Goals,
-			Occupation:  item.Profile.Occupation,
-			Education:   item.Profile.Education,
-			Birthdate:   birthdate,
-		},
+		Profile: toAdminBotProfileCard(item.Profile),
 		Media: dto.AdminBotProfileMedia{
-			Photos: append([]string(nil), item.PhotoURLs...),
-			Circle: item.CircleURL,
+			Photos:  append([]string(nil), item.PhotoURLs...),
+			Circle:  item.CircleURL,
+			Matches: toAdminBotPhotoMatches(item.PhotoMatches),
 		},
+	}
+}
+
+func (h *AdminBotModerationHandler) BanImage(w http.ResponseWriter, r *http.Request) {
+	actorTGID, ok := adminBotActorTGID(r)
+	if !ok {
+		writeUnauthorized(w, "UNAUTHORIZED", "admin bot authentication required")
+		return
+	}
+	if h.service == nil {
+		writeInternal(w, "MODERATION_SERVICE_UNAVAILABLE", "moderation service is unavailable")
+		return
+	}
+
+	var req dto.AdminBotBanImageRequest
+	if err := decodeJSON(r, &req); err != nil || req.MediaID <= 0 {
+		writeBadRequest(w, "VALIDATION_ERROR", "invalid request body")
+		return
+	}
+
+	id, err := h.service.BanPhotoHash(r.Context(), req.MediaID, req.Label, actorTGID)
+	if err != nil {
+		switch {
+		case errors.Is(err, pgrepo.ErrMediaNotFound):
+			httperrors.Write(w, http.StatusNotFound, httperrors.APIError{
+				Code:    "NOT_FOUND",
+				Message: "hashed photo not found",
+			})
+		default:
+			writeInternal(w, "INTERNAL_ERROR", "failed to ban image")
+		}
+		return
+	}
+
+	h.logModerationAudit(r, "MODERATION_BAN_IMAGE", actorTGID, 0, map[string]any{
+		"media_id":        req.MediaID,
+		"banned_image_id": id,
 	})
+	httperrors.Write(w, http.StatusOK, dto.AdminBotBanImageResponse{ID: id})
+}
+
+func toAdminBotProfileCard(profile pgrepo.ProfileQueueSummary) dto.AdminBotProfileCard {
+	var birthdate *string
+	if profile.Birthdate != nil {
+		v := profile.Birthdate.UTC().Format("2006-01-02")
+		birthdate = &v
+	}
+
+	return dto.AdminBotProfileCard{
+		UserID:      profile.UserID,
+		DisplayName: profile.DisplayName,
+		CityID:      profile.CityID,
+		Gender:      profile.Gender,
+		LookingFor:  profile.LookingFor,
+		Goals:       profile.Goals,
+		Occupation:  profile.Occupation,
+		Education:   profile.Education,
+		Birthdate:   birthdate,
+
+		VerificationStatus: profile.VerificationStatus,
+	}
+}
+
+func toAdminBotPhotoMatches(matches []mediasvc.PhotoMatch) []dto.AdminBotPhotoMatchDTO {
+	items := make([]dto.AdminBotPhotoMatchDTO, 0, len(matches))
+	for _, match := range matches {
+		items = append(items, dto.AdminBotPhotoMatchDTO{
+			MediaID:        match.MediaID,
+			Slot:           match.Position,
+			Source:         match.Source,
+			MatchedUserID:  positiveInt64Ptr(match.MatchedUserID),
+			MatchedMediaID: positiveInt64Ptr(match.MatchedMediaID),
+			BannedImageID:  positiveInt64Ptr(match.BannedImageID),
+			Label:          match.Label,
+			Distance:       match.Distance,
+		})
+	}
+	return items
+}
+
+func positiveInt64Ptr(value int64) *int64 {
+	if value <= 0 {
+		return nil
+	}
+	return &value
 }
 
 func (h *AdminBotModerationHandler) RejectReasons(w http.ResponseWriter, r *http.Request) {
@@ -96,23 +170,74 @@ func (h *AdminBotModerationHandler) RejectReasons(w http.ResponseWriter, r *http
 		return
 	}
 
-	serviceItems := h.service.ListRejectReasons()
-	resp := dto.AdminBotModerationRejectReasonsResponse{
-		Items: make([]dto.AdminBotModerationRejectReasonItem, 0, len(serviceItems)),
+	writeRejectReasons(w, r, h.service)
+}
+
+func (h *AdminBotModerationHandler) UpsertRejectReason(w http.ResponseWriter, r *http.Request) {
+	actorTGID, ok := adminBotActorTGID(r)
+	if !ok {
+		writeUnauthorized(w, "UNAUTHORIZED", "admin bot authentication required")
+		return
+	}
+	if h.service == nil {
+		writeInternal(w, "MODERATION_SERVICE_UNAVAILABLE", "moderation service is unavailable")
+		return
+	}
+
+	item, bodyActorTGID, ok := decodeRejectReasonUpsert(w, r)
+	if !ok {
+		return
+	}
+	if bodyActorTGID != 0 {
+		actorTGID = bodyActorTGID
+	}
+	saved, ok := upsertRejectReason(w, r, h.service, actorTGID, item)
+	if !ok {
+		return
+	}
+	h.logModerationAudit(r, "REJECT_REASON_UPDATE", actorTGID, 0, map[string]any{
+		"reason_code": saved.ReasonCode,
+		"is_active":   saved.IsActive,
+		"severity":    saved.Severity,
+	})
+
+	httperrors.Write(w, http.StatusOK, toRejectReasonItemDTO(saved))
+}
+
+func (h *AdminBotModerationHandler) SLA(w http.ResponseWriter, r *http.Request) {
+	if _, ok := adminBotActorTGID(r); !ok {
+		writeUnauthorized(w, "UNAUTHORIZED", "admin bot authentication required")
+		return
+	}
+	if h.service == nil {
+		writeInternal(w, "MODERATION_SERVICE_UNAVAILABLE", "moderation service is unavailable")
+		return
+	}
+
+	window, ok := parseSLAWindow(r)
+	if !ok {
+		writeBadRequest(w, "VALIDATION_ERROR", "hours must be an integer between 1 and 720")
+		return
 	}
-	for _, item := range serviceItems {
-		resp.Items = append(resp.Items, dto.AdminBotModerationRejectReasonItem{
-			ReasonCode:      item.ReasonCode,
-			Label:           item.Label,
-			ReasonText:      item.ReasonText,
-			RequiredFixStep: item.RequiredFixStep,
-		})
+
+	report, err := h.service.GetSLA(r.Context(), window)
+	if err != nil {
+		writeInternal(w, "INTERNAL_ERROR", "failed to load moderation sla")
+		return
 	}
 
-	httperrors.Write(w, http.StatusOK, resp)
+	httperrors.Write(w, http.StatusOK, toModerationSLAResponse(report))
 }
 
 func (h *AdminBotModerationHandler) Approve(w http.ResponseWriter, r *http.Request) {
+	h.approve(w, r, false)
+}
+
+func (h *AdminBotModerationHandler) ApproveVerified(w http.ResponseWriter, r *http.Request) {
+	h.approve(w, r, true)
+}
+
+func (h *AdminBotModerationHandler) approve(w http.ResponseWriter, r *http.Request, verified bool) {
 	actorTGID, ok := adminBotActorTGID(r)
 	if !ok {
 		writeUnauthorized(w, "UNAUTHORIZED", "admin bot authentication required")
@@ -129,20 +254,41 @@ func (h *AdminBotModerationHandler) Approve(w http.ResponseWriter, r *http.Reque
 		return
 	}
 
-	if err := h.service.Approve(r.Context(), itemID, actorTGID); err != nil {
+	approve := h.service.Approve
+	if verified {
+		approve = h.service.ApproveAsVerified
+	}
+	if err := approve(r.Context(), itemID, actorTGID); err != nil {
 		switch {
 		case errors.Is(err, pgrepo.ErrModerationItemNotFound):
 			httperrors.Write(w, http.StatusNotFound, httperrors.APIError{
 				Code:    "NOT_FOUND",
 				Message: "moderation item not found",
 			})
+		case errors.Is(err, pgrepo.ErrModerationItemNotPending):
+			httperrors.Write(w, http.StatusConflict, httperrors.APIError{
+				Code:    "ITEM_NOT_PENDING",
+				Message: "moderation item is already decided",
+			})
+		case errors.Is(err, pgrepo.ErrModerationItemLocked):
+			httperrors.Write(w, http.StatusConflict, httperrors.APIError{
+				Code:    "ITEM_LOCKED",
+				Message: "moderation item is being reviewed by another moderator",
+			})
+		case errors.Is(err, modsvc.ErrCircleMissing):
+			httperrors.Write(w, http.StatusConflict, httperrors.APIError{
+				Code:    "CIRCLE_MISSING",
+				Message: "user has no circle to verify",
+			})
 		default:
 			writeInternal(w, "INTERNAL_ERROR", "failed to approve moderation item")
 		}
 		return
 	}
 
-	h.logModerationAudit(r, "MODERATION_APPROVE", actorTGID, itemID, nil)
+	h.logModerationAudit(r, "MODERATION_APPROVE", actorTGID, itemID, map[string]any{
+		"verified": verified,
+	})
 	httperrors.Write(w, http.StatusOK, dto.LogoutResponse{OK: true})
 }
 
@@ -180,16 +326,24 @@ func (h *AdminBotModerationHandler) Reject(w http.ResponseWriter, r *http.Reques
 		switch {
 		case errors.Is(err, modsvc.ErrInvalidReasonCode):
 			writeBadRequest(w, "VALIDATION_ERROR", "invalid reason_code")
+		case errors.Is(err, modsvc.ErrInvalidRejectPayload):
+			writeBadRequest(w, "VALIDATION_ERROR", "invalid reject payload")
 		case errors.Is(err, pgrepo.ErrModerationItemNotFound):
 			httperrors.Write(w, http.StatusNotFound, httperrors.APIError{
 				Code:    "NOT_FOUND",
 				Message: "moderation item not found",
 			})
+		case errors.Is(err, pgrepo.ErrModerationItemNotPending):
+			httperrors.Write(w, http.StatusConflict, httperrors.APIError{
+				Code:    "ITEM_NOT_PENDING",
+				Message: "moderation item is already decided",
+			})
+		case errors.Is(err, pgrepo.ErrModerationItemLocked):
+			httperrors.Write(w, http.StatusConflict, httperrors.APIError{
+				Code:    "ITEM_LOCKED",
+				Message: "moderation item is being reviewed by another moderator",
+			})
 		default:
-			if strings.Contains(strings.ToLower(err.Error()), "required") {
-				writeBadRequest(w, "VALIDATION_ERROR", "invalid reject payload")
-				return
-			}
 			writeInternal(w, "INTERNAL_ERROR", "failed to reject moderation item")
 		}
 		return
@@ -1,11 +1,14 @@
 package handlers
 
 import (
+	"context"
 	"encoding/json"
 	"net/http"
 	"net/http/httptest"
+	"strings"
 	"testing"
 
+	pgrepo "github.com/ivankudzin/tgapp/backend/internal/repo/postgres"
 	authsvc "github.com/ivankudzin/tgapp/backend/internal/services/auth"
 	modsvc "github.com/ivankudzin/tgapp/backend/internal/services/moderation"
 	"github.com/ivankudzin/tgapp/backend/internal/transport/http/dto"
@@ -24,8 +27,22 @@ func TestAdminBotRejectReasonsUnauthorizedWithoutBotContext(t *testing.T) {
 	}
 }
 
+type rejectReasonStoreStub struct {
+	modsvc.RejectReasonStore
+	records []pgrepo.RejectReasonRecord
+}
+
+func (s *rejectReasonStoreStub) ListRejectReasons(context.Context, bool) ([]pgrepo.RejectReasonRecord, error) {
+	return s.records, nil
+}
+
 func TestAdminBotRejectReasonsReturnsItems(t *testing.T) {
-	handler := NewAdminBotModerationHandler(modsvc.NewService(nil, nil, nil, nil), nil)
+	service := modsvc.NewService(nil, nil, nil, nil)
+	service.AttachRejectReasons(&rejectReasonStoreStub{records: []pgrepo.RejectReasonRecord{
+		{Code: "PHOTO_NO_FACE", Labels: map[string]string{"ru": "Фото: не видно лица"}, ReasonText: "text", RequiredFixStep: "step", Severity: "LOW", IsActive: true, SortOrder: 10},
+		{Code: "OTHER", Labels: map[string]string{"ru": "Другое"}, ReasonText: "text", RequiredFixStep: "step", Severity: "MEDIUM", IsActive: true, SortOrder: 1000},
+	}})
+	handler := NewAdminBotModerationHandler(service, nil)
 
 	req := httptest.NewRequest(http.MethodGet, "/admin/bot/mod/reject-reasons", nil)
 	ctx := req.Context()
@@ -52,7 +69,7 @@ func TestAdminBotRejectReasonsReturnsItems(t *testing.T) {
 	for _, item := range response.Items {
 		if item.ReasonCode == "OTHER" {
 			hasOther = true
-			if item.ReasonText == "" || item.RequiredFixStep == "" {
+			if item.ReasonText == "" || item.RequiredFixStep == "" || item.Severity == "" || !item.IsActive {
 				t.Fatalf("OTHER template is incomplete: %+v", item)
 			}
 			break
@@ -62,3 +79,60 @@ func TestAdminBotRejectReasonsReturnsItems(t *testing.T) {
 		t.Fatalf("expected OTHER reason code in response")
 	}
 }
+
+func TestAdminBotUpsertRejectReasonValidatesPayload(t *testing.T) {
+	handler := NewAdminBotModerationHandler(modsvc.NewService(nil, nil, nil, nil), nil)
+
+	body := strings.NewReader(`{"label":"Фото","reason_text":"text","required_fix_step":"step","severity":"CRITICAL"}`)
+	req := httptest.NewRequest(http.MethodPut, "/admin/bot/mod/reject-reasons/PHOTO_BLURRY", body)
+	ctx := req.Context()
+	ctx = authsvc.WithActorIsBot(ctx, true)
+	ctx = authsvc.WithActorTGID(ctx, 777)
+	ctx = withURLParam(ctx, "code", "PHOTO_BLURRY")
+	req = req.WithContext(ctx)
+
+	rr := httptest.NewRecorder()
+	handler.UpsertRejectReason(rr, req)
+
+	if rr.Code != http.StatusBadRequest {
+		t.Fatalf("unexpected status: got=%d want=%d body=%s", rr.Code, http.StatusBadRequest, rr.Body.String())
+	}
+}
+
+func TestAdminBotSaveModeratorWorkloadRejectsUnknownSkill(t *testing.T) {
+	service := modsvc.NewService(nil, nil, nil, nil)
+	service.AttachWorkload(pgrepo.NewModeratorWorkloadRepo(nil))
+	handler := NewAdminBotModerationHandler(service, nil)
+
+	body := strings.NewReader(`{"max_active_items":2,"skills":["photo","video"]}`)
+	req := httptest.NewRequest(http.MethodPut, "/admin/bot/mod/workload/555", body)
+	ctx := req.Context()
+	ctx = authsvc.WithActorIsBot(ctx, true)
+	ctx = authsvc.WithActorTGID(ctx, 777)
+	ctx = withURLParam(ctx, "tg_id", "555")
+	req = req.WithContext(ctx)
+
+	rr := httptest.NewRecorder()
+	handler.SaveModeratorWorkload(rr, req)
+
+	if rr.Code != http.StatusBadRequest {
+		t.Fatalf("unexpected status: got=%d want=%d body=%s", rr.Code, http.StatusBadRequest, rr.Body.String())
+	}
+}
+
+func TestAdminBotWorkloadUnavailableWithoutStore(t *testing.T) {
+	handler := NewAdminBotModerationHandler(modsvc.NewService(nil, nil, nil, nil), nil)
+
+	req := httptest.NewRequest(http.MethodGet, "/admin/bot/mod/workload", nil)
+	ctx := req.Context()
+	ctx = authsvc.WithActorIsBot(ctx, true)
+	ctx = authsvc.WithActorTGID(ctx, 777)
+	req = req.WithContext(ctx)
+
+	rr := httptest.NewRecorder()
+	handler.Workload(rr, req)
+
+	if rr.Code != http.StatusInternalServerError || !strings.Contains(rr.Body.String(), "WORKLOAD_UNAVAILABLE") {
+		t.Fatalf("unexpected response: code=%d body=%s", rr.Code, rr.Body.String())
+	}
+}
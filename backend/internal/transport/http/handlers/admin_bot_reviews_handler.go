@@ -0,0 +1,247 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+	"time"
+
+	pgrepo "github.com/ivankudzin/tgapp/backend/internal/repo/postgres"
+	modsvc "github.com/ivankudzin/tgapp/backend/internal/services/moderation"
+	"github.com/ivankudzin/tgapp/backend/internal/transport/http/dto"
+	httperrors "github.com/ivankudzin/tgapp/backend/internal/transport/http/errors"
+)
+
+func (h *AdminBotModerationHandler) ReviewAcquire(w http.ResponseWriter, r *http.Request) {
+	actorTGID, ok := adminBotActorTGID(r)
+	if !ok {
+		writeUnauthorized(w, "UNAUTHORIZED", "admin bot authentication required")
+		return
+	}
+	if h.service == nil {
+		writeInternal(w, "MODERATION_SERVICE_UNAVAILABLE", "moderation service is unavailable")
+		return
+	}
+
+	var req dto.AdminBotReviewAcquireRequest
+	if err := decodeJSON(r, &req); err != nil {
+		writeBadRequest(w, "VALIDATION_ERROR", "invalid request body")
+		return
+	}
+
+	item, err := h.service.GetNextReview(r.Context(), req.Kind, actorTGID)
+	if err != nil {
+		switch {
+		case errors.Is(err, modsvc.ErrInvalidReviewKind):
+			writeBadRequest(w, "VALIDATION_ERROR", "kind must be APPEAL or QA")
+		case errors.Is(err, modsvc.ErrReviewQueueEmpty):
+			w.WriteHeader(http.StatusNoContent)
+		default:
+			writeInternal(w, "INTERNAL_ERROR", "failed to acquire review")
+		}
+		return
+	}
+
+	review := item.Review
+	httperrors.Write(w, http.StatusOK, dto.AdminBotReviewAcquireResponse{
+		Review: dto.AdminBotReviewItem{
+			ID:                    review.ID,
+			ModerationItemID:      review.ModerationItemID,
+			UserID:                review.UserID,
+			Kind:                  review.Kind,
+			Status:                review.Status,
+			OriginalDecision:      review.OriginalDecision,
+			OriginalReasonCode:    review.OriginalReasonCode,
+			OriginalModeratorTGID: review.OriginalModeratorTGID,
+			ReasonText:            item.ReasonText,
+			RequiredFixStep:       item.RequiredFixStep,
+			AppealMessage:         review.AppealMessage,
+			LockedAt:              review.LockedAt,
+			CreatedAt:             review.CreatedAt,
+		},
+		Profile: toAdminBotProfileCard(item.Profile),
+		Media: dto.AdminBotProfileMedia{
+			Photos:  append([]string(nil), item.PhotoURLs...),
+			Circle:  item.CircleURL,
+			Matches: []dto.AdminBotPhotoMatchDTO{},
+		},
+	})
+}
+
+func (h *AdminBotModerationHandler) ReviewGet(w http.ResponseWriter, r *http.Request) {
+	if _, ok := adminBotActorTGID(r); !ok {
+		writeUnauthorized(w, "UNAUTHORIZED", "admin bot authentication required")
+		return
+	}
+	if h.service == nil {
+		writeInternal(w, "MODERATION_SERVICE_UNAVAILABLE", "moderation service is unavailable")
+		return
+	}
+
+	reviewID, ok := moderationItemIDFromRequest(r)
+	if !ok {
+		writeBadRequest(w, "VALIDATION_ERROR", "invalid review id")
+		return
+	}
+
+	review, err := h.service.GetReview(r.Context(), reviewID)
+	if err != nil {
+		if errors.Is(err, pgrepo.ErrModerationReviewNotFound) {
+			httperrors.Write(w, http.StatusNotFound, httperrors.APIError{
+				Code:    "NOT_FOUND",
+				Message: "review not found",
+			})
+			return
+		}
+		writeInternal(w, "INTERNAL_ERROR", "failed to load review")
+		return
+	}
+
+	httperrors.Write(w, http.StatusOK, dto.AdminBotReviewItem{
+		ID:                    review.ID,
+		ModerationItemID:      review.ModerationItemID,
+		UserID:                review.UserID,
+		Kind:                  review.Kind,
+		Status:                review.Status,
+		OriginalDecision:      review.OriginalDecision,
+		OriginalReasonCode:    review.OriginalReasonCode,
+		OriginalModeratorTGID: review.OriginalModeratorTGID,
+		AppealMessage:         review.AppealMessage,
+		LockedAt:              review.LockedAt,
+		CreatedAt:             review.CreatedAt,
+	})
+}
+
+func (h *AdminBotModerationHandler) ReviewResolve(w http.ResponseWriter, r *http.Request) {
+	actorTGID, ok := adminBotActorTGID(r)
+	if !ok {
+		writeUnauthorized(w, "UNAUTHORIZED", "admin bot authentication required")
+		return
+	}
+	if h.service == nil {
+		writeInternal(w, "MODERATION_SERVICE_UNAVAILABLE", "moderation service is unavailable")
+		return
+	}
+
+	reviewID, ok := moderationItemIDFromRequest(r)
+	if !ok {
+		writeBadRequest(w, "VALIDATION_ERROR", "invalid review id")
+		return
+	}
+
+	var req dto.AdminBotReviewResolveRequest
+	if err := decodeJSON(r, &req); err != nil {
+		writeBadRequest(w, "VALIDATION_ERROR", "invalid request body")
+		return
+	}
+
+	review, err := h.service.ResolveReview(r.Context(), modsvc.ResolveReviewInput{
+		ReviewID:     reviewID,
+		ReviewerTGID: actorTGID,
+		Outcome:      req.Outcome,
+		ReasonCode:   req.ReasonCode,
+		Note:         req.Note,
+	})
+	if err != nil {
+		switch {
+		case errors.Is(err, modsvc.ErrInvalidReviewOutcome):
+			writeBadRequest(w, "VALIDATION_ERROR", "outcome must be UPHOLD or OVERTURN")
+		case errors.Is(err, modsvc.ErrInvalidReasonCode):
+			writeBadRequest(w, "VALIDATION_ERROR", "invalid reason_code")
+		case errors.Is(err, modsvc.ErrSameReviewer):
+			httperrors.Write(w, http.StatusForbidden, httperrors.APIError{
+				Code:    "SAME_REVIEWER",
+				Message: "second review must be done by a different moderator",
+			})
+		case errors.Is(err, pgrepo.ErrModerationReviewNotFound), errors.Is(err, pgrepo.ErrModerationItemNotFound):
+			httperrors.Write(w, http.StatusNotFound, httperrors.APIError{
+				Code:    "NOT_FOUND",
+				Message: "review not found",
+			})
+		case errors.Is(err, pgrepo.ErrModerationReviewNotPending):
+			httperrors.Write(w, http.StatusConflict, httperrors.APIError{
+				Code:    "REVIEW_NOT_PENDING",
+				Message: "review is already resolved",
+			})
+		case errors.Is(err, pgrepo.ErrModerationReviewLockConflict):
+			httperrors.Write(w, http.StatusConflict, httperrors.APIError{
+				Code:    "REVIEW_NOT_LOCKED",
+				Message: "review is not locked by you; take it from the queue again",
+			})
+		default:
+			writeInternal(w, "INTERNAL_ERROR", "failed to resolve review")
+		}
+		return
+	}
+
+	action := "MODERATION_REVIEW_UPHOLD"
+	if review.Status == modsvc.ReviewStatusOverturned {
+		action = "MODERATION_REVIEW_OVERTURN"
+	}
+	h.logModerationAudit(r, action, actorTGID, review.ModerationItemID, map[string]any{
+		"review_id":                review.ID,
+		"review_kind":              review.Kind,
+		"target_user_id":           review.UserID,
+		"original_decision":        review.OriginalDecision,
+		"original_moderator_tg_id": review.OriginalModeratorTGID,
+		"reason_code":              strings.ToUpper(strings.TrimSpace(req.ReasonCode)),
+		"note":                     strings.TrimSpace(req.Note),
+	})
+	httperrors.Write(w, http.StatusOK, dto.AdminBotReviewResolveResponse{
+		ID:               review.ID,
+		ModerationItemID: review.ModerationItemID,
+		UserID:           review.UserID,
+		Kind:             review.Kind,
+		Status:           review.Status,
+	})
+}
+
+func (h *AdminBotModerationHandler) ReviewStats(w http.ResponseWriter, r *http.Request) {
+	if _, ok := adminBotActorTGID(r); !ok {
+		writeUnauthorized(w, "UNAUTHORIZED", "admin bot authentication required")
+		return
+	}
+	if h.service == nil {
+		writeInternal(w, "MODERATION_SERVICE_UNAVAILABLE", "moderation service is unavailable")
+		return
+	}
+
+	window, ok := parseSLAWindow(r)
+	if !ok {
+		writeBadRequest(w, "VALIDATION_ERROR", "hours must be an integer between 1 and 720")
+		return
+	}
+
+	stats, err := h.service.GetReviewStats(r.Context(), window)
+	if err != nil {
+		writeInternal(w, "INTERNAL_ERROR", "failed to load review stats")
+		return
+	}
+
+	resp := dto.AdminBotReviewStatsResponse{
+		WindowHours: int(stats.Window / time.Hour),
+		Kinds:       make([]dto.AdminBotReviewKindStats, 0, len(stats.Kinds)),
+		Moderators:  make([]dto.AdminBotReviewAgreement, 0, len(stats.Moderators)),
+	}
+	for _, item := range stats.Kinds {
+		resp.Kinds = append(resp.Kinds, dto.AdminBotReviewKindStats{
+			Kind:          item.Kind,
+			Pending:       item.Pending,
+			Decided:       item.Decided,
+			Upheld:        item.Upheld,
+			Overturned:    item.Overturned,
+			AgreementRate: item.AgreementRate,
+		})
+	}
+	for _, item := range stats.Moderators {
+		resp.Moderators = append(resp.Moderators, dto.AdminBotReviewAgreement{
+			ModeratorTGID: item.ModeratorTGID,
+			Reviewed:      item.Reviewed,
+			Upheld:        item.Upheld,
+			Overturned:    item.Overturned,
+			AgreementRate: item.AgreementRate,
+		})
+	}
+
+	httperrors.Write(w, http.StatusOK, resp)
+}
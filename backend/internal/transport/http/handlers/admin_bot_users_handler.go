@@ -67,35 +67,7 @@ func (h *AdminBotUsersHandler) LookupUser(w http.ResponseWriter, r *http.Request
 		"target_tg_id": found.TGID,
 	})
 
-	httperrors.Write(w, http.StatusOK, dto.AdminBotLookupUserResponse{
-		User: dto.AdminBotLookupUser{
-			UserID:           found.UserID,
-			TGID:             found.TGID,
-			Username:         found.Username,
-			CityID:           found.CityID,
-			Birthdate:        found.Birthdate,
-			Age:              found.Age,
-			Gender:           found.Gender,
-			LookingFor:       found.LookingFor,
-			Goals:            append([]string(nil), found.Goals...),
-			Languages:        append([]string(nil), found.Languages...),
-			Occupation:       found.Occupation,
-			Education:        found.Education,
-			ModerationStatus: found.ModerationStatus,
-			Approved:         found.Approved,
-			PhotoKeys:        append([]string(nil), found.PhotoKeys...),
-			CircleKey:        found.CircleKey,
-			PhotoURLs:        append([]string(nil), found.PhotoURLs...),
-			CircleURL:        found.CircleURL,
-			PlusExpiresAt:    found.PlusExpiresAt,
-			BoostUntil:       found.BoostUntil,
-			SuperlikeCredits: found.SuperlikeCredits,
-			RevealCredits:    found.RevealCredits,
-			LikeTokens:       found.LikeTokens,
-			IsBanned:         found.IsBanned,
-			BanReason:        found.BanReason,
-		},
-	})
+	httperrors.Write(w, http.StatusOK, dto.AdminBotLookupUserResponse{User: toAdminBotLookupUser(found)})
 }
 
 func (h *AdminBotUsersHandler) BanUser(w http.ResponseWriter, r *http.Request) {
@@ -121,10 +93,17 @@ func (h *AdminBotUsersHandler) BanUser(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	if err := h.users.SetBan(r.Context(), userID, true, req.Reason, actorTGID); err != nil {
+	result, err := h.users.Ban(r.Context(), userssvc.BanInput{
+		UserID:     userID,
+		ReasonCode: req.ReasonCode,
+		Reason:     req.Reason,
+		Duration:   req.Duration,
+		ActorTGID:  actorTGID,
+	})
+	if err != nil {
 		switch {
 		case errors.Is(err, userssvc.ErrValidation):
-			writeBadRequest(w, "VALIDATION_ERROR", "invalid ban request")
+			writeBadRequest(w, "VALIDATION_ERROR", err.Error())
 		case errors.Is(err, userssvc.ErrNotFound):
 			httperrors.Write(w, http.StatusNotFound, httperrors.APIError{
 				Code:    "NOT_FOUND",
@@ -137,9 +116,19 @@ func (h *AdminBotUsersHandler) BanUser(w http.ResponseWriter, r *http.Request) {
 	}
 
 	h.logAuditAction(r, "BAN_USER", actorTGID, userID, map[string]any{
-		"reason": strings.TrimSpace(req.Reason),
+		"reason":           result.Reason,
+		"reason_code":      result.ReasonCode,
+		"duration":         strings.TrimSpace(req.Duration),
+		"expires_at":       result.ExpiresAt,
+		"unmatched_count":  result.UnmatchedCount,
+		"sessions_revoked": result.SessionsRevoked,
+	})
+	httperrors.Write(w, http.StatusOK, dto.AdminBotBanResponse{
+		OK:               true,
+		AdminBotBanState: toBanStateDTO(result.BanState),
+		UnmatchedCount:   result.UnmatchedCount,
+		SessionsRevoked:  result.SessionsRevoked,
 	})
-	httperrors.Write(w, http.StatusOK, dto.LogoutResponse{OK: true})
 }
 
 func (h *AdminBotUsersHandler) UnbanUser(w http.ResponseWriter, r *http.Request) {
@@ -159,7 +148,7 @@ func (h *AdminBotUsersHandler) UnbanUser(w http.ResponseWriter, r *http.Request)
 		return
 	}
 
-	if err := h.users.SetBan(r.Context(), userID, false, "", actorTGID); err != nil {
+	if err := h.users.Unban(r.Context(), userID, actorTGID); err != nil {
 		switch {
 		case errors.Is(err, userssvc.ErrValidation):
 			writeBadRequest(w, "VALIDATION_ERROR", "invalid unban request")
@@ -178,6 +167,30 @@ func (h *AdminBotUsersHandler) UnbanUser(w http.ResponseWriter, r *http.Request)
 	httperrors.Write(w, http.StatusOK, dto.LogoutResponse{OK: true})
 }
 
+func (h *AdminBotUsersHandler) BanState(w http.ResponseWriter, r *http.Request) {
+	if _, ok := adminBotActorTGIDForUsers(r); !ok {
+		writeUnauthorized(w, "UNAUTHORIZED", "admin bot authentication required")
+		return
+	}
+	if h.users == nil {
+		writeInternal(w, "USERS_SERVICE_UNAVAILABLE", "users service is unavailable")
+		return
+	}
+
+	userID, err := strconv.ParseInt(strings.TrimSpace(r.URL.Query().Get("user_id")), 10, 64)
+	if err != nil || userID <= 0 {
+		writeBadRequest(w, "VALIDATION_ERROR", "invalid user id")
+		return
+	}
+
+	state, err := h.users.GetBanState(r.Context(), userID)
+	if err != nil {
+		writeInternal(w, "INTERNAL_ERROR", "failed to load ban state")
+		return
+	}
+	httperrors.Write(w, http.StatusOK, toBanStateDTO(state))
+}
+
 func (h *AdminBotUsersHandler) ForceReview(w http.ResponseWriter, r *http.Request) {
 	actorTGID, ok := adminBotActorTGIDForUsers(r)
 	if !ok {
@@ -274,3 +287,58 @@ func adminBotUserIDFromRequest(r *http.Request) (int64, bool) {
 	}
 	return userID, true
 }
+
+func toAdminBotLookupUser(found userssvc.LookupUser) dto.AdminBotLookupUser {
+	return dto.AdminBotLookupUser{
+		UserID:           found.UserID,
+		TGID:             found.TGID,
+		Username:         found.Username,
+		CityID:           found.CityID,
+		Birthdate:        found.Birthdate,
+		Age:              found.Age,
+		Gender:           found.Gender,
+		LookingFor:       found.LookingFor,
+		Goals:            append([]string(nil), found.Goals...),
+		Languages:        append([]string(nil), found.Languages...),
+		Occupation:       found.Occupation,
+		Education:        found.Education,
+		ModerationStatus: found.ModerationStatus,
+		Approved:         found.Approved,
+		PhotoKeys:        append([]string(nil), found.PhotoKeys...),
+		CircleKey:        found.CircleKey,
+		PhotoURLs:        append([]string(nil), found.PhotoURLs...),
+		CircleURL:        found.CircleURL,
+		PlusExpiresAt:    found.PlusExpiresAt,
+		BoostUntil:       found.BoostUntil,
+		SuperlikeCredits: found.SuperlikeCredits,
+		RevealCredits:    found.RevealCredits,
+		LikeTokens:       found.LikeTokens,
+		IsBanned:         found.IsBanned,
+		BanReason:        found.BanReason,
+		BanReasonCode:    found.BanReasonCode,
+		BanExpiresAt:     found.BanExpiresAt,
+		EvasionMatches:   toEvasionMatchDTOs(found.EvasionMatches),
+	}
+}
+
+func toBanStateDTO(state userssvc.BanState) dto.AdminBotBanState {
+	return dto.AdminBotBanState{
+		Banned:     state.Banned,
+		ReasonCode: state.ReasonCode,
+		Reason:     state.Reason,
+		BannedAt:   state.BannedAt,
+		ExpiresAt:  state.ExpiresAt,
+	}
+}
+
+func toEvasionMatchDTOs(matches []userssvc.EvasionMatch) []dto.AdminBotEvasionMatch {
+	items := make([]dto.AdminBotEvasionMatch, 0, len(matches))
+	for _, match := range matches {
+		items = append(items, dto.AdminBotEvasionMatch{
+			BannedUserID: match.BannedUserID,
+			BannedTGID:   match.BannedTGID,
+			Signals:      append([]string(nil), match.Signals...),
+		})
+	}
+	return items
+}
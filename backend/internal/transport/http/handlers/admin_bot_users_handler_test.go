@@ -0,0 +1,78 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	authsvc "github.com/ivankudzin/tgapp/backend/internal/services/auth"
+	userssvc "github.com/ivankudzin/tgapp/backend/internal/services/users"
+	"github.com/ivankudzin/tgapp/backend/internal/transport/http/dto"
+)
+
+func TestAdminBotBanUserRejectsUnknownDuration(t *testing.T) {
+	handler := NewAdminBotUsersHandler(userssvc.NewService(nil, nil, nil), nil)
+
+	rr := httptest.NewRecorder()
+	handler.BanUser(rr, adminBotBanRequest(`{"reason_code":"SPAM","duration":"1y"}`))
+
+	if rr.Code != http.StatusBadRequest {
+		t.Fatalf("unexpected status: got=%d want=%d body=%s", rr.Code, http.StatusBadRequest, rr.Body.String())
+	}
+}
+
+func TestAdminBotBanUserAcceptsLegacyBotPayload(t *testing.T) {
+	handler := NewAdminBotUsersHandler(userssvc.NewService(nil, nil, nil), nil)
+
+	rr := httptest.NewRecorder()
+	handler.BanUser(rr, adminBotBanRequest(`{"user_id":42,"reason":"spam","updated_by_tg_id":777,"reason_code":"spam","duration":"24h"}`))
+
+	if rr.Code != http.StatusOK {
+		t.Fatalf("unexpected status: got=%d want=%d body=%s", rr.Code, http.StatusOK, rr.Body.String())
+	}
+	var response dto.AdminBotBanResponse
+	if err := json.NewDecoder(rr.Body).Decode(&response); err != nil {
+		t.Fatalf("decode response: %v", err)
+	}
+	if !response.OK || !response.Banned || response.ReasonCode != "SPAM" || response.ExpiresAt == nil {
+		t.Fatalf("unexpected ban response: %+v", response)
+	}
+}
+
+func adminBotBanRequest(body string) *http.Request {
+	req := httptest.NewRequest(http.MethodPost, "/admin/bot/users/42/ban", strings.NewReader(body))
+	ctx := withURLParam(req.Context(), "id", "42")
+	ctx = authsvc.WithActorIsBot(ctx, true)
+	ctx = authsvc.WithActorTGID(ctx, 777)
+	return req.WithContext(ctx)
+}
+
+func TestAdminBotDossierValidatesQuery(t *testing.T) {
+	handler := NewAdminBotUsersHandler(userssvc.NewService(nil, nil, nil), nil)
+
+	cases := map[string]int{
+		"/admin/bot/lookup/dossier":                            http.StatusBadRequest,
+		"/admin/bot/lookup/dossier?user_id=42&limit=x":         http.StatusBadRequest,
+		"/admin/bot/lookup/dossier?user_id=42&section=chats":   http.StatusBadRequest,
+		"/admin/bot/lookup/dossier?user_id=42&section=reports": http.StatusNotFound,
+	}
+	for target, want := range cases {
+		req := httptest.NewRequest(http.MethodGet, target, nil)
+		ctx := authsvc.WithActorIsBot(req.Context(), true)
+		ctx = authsvc.WithActorTGID(ctx, 777)
+
+		rr := httptest.NewRecorder()
+		handler.Dossier(rr, req.WithContext(ctx))
+		if rr.Code != want {
+			t.Fatalf("%s: unexpected status: got=%d want=%d body=%s", target, rr.Code, want, rr.Body.String())
+		}
+	}
+
+	rr := httptest.NewRecorder()
+	handler.Dossier(rr, httptest.NewRequest(http.MethodGet, "/admin/bot/lookup/dossier?user_id=42", nil))
+	if rr.Code != http.StatusUnauthorized {
+		t.Fatalf("unexpected status without bot auth: got=%d", rr.Code)
+	}
+}
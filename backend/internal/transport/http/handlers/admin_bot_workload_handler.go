@@ -0,0 +1,296 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+
+	pgrepo "github.com/ivankudzin/tgapp/backend/internal/repo/postgres"
+	modsvc "github.com/ivankudzin/tgapp/backend/internal/services/moderation"
+	"github.com/ivankudzin/tgapp/backend/internal/transport/http/dto"
+	httperrors "github.com/ivankudzin/tgapp/backend/internal/transport/http/errors"
+)
+
+func (h *AdminBotModerationHandler) Workload(w http.ResponseWriter, r *http.Request) {
+	if _, ok := adminBotActorTGID(r); !ok {
+		writeUnauthorized(w, "UNAUTHORIZED", "admin bot authentication required")
+		return
+	}
+	if h.service == nil {
+		writeInternal(w, "MODERATION_SERVICE_UNAVAILABLE", "moderation service is unavailable")
+		return
+	}
+
+	workload, err := h.service.GetWorkload(r.Context())
+	if err != nil {
+		writeWorkloadError(w, err, "failed to load moderator workload")
+		return
+	}
+
+	resp := dto.AdminBotModerationWorkloadResponse{
+		Pending:    workload.Pending,
+		Skills:     append([]string(nil), pgrepo.ModeratorSkills...),
+		Moderators: make([]dto.AdminBotModeratorWorkloadItem, 0, len(workload.Moderators)),
+	}
+	for _, item := range workload.Moderators {
+		resp.Moderators = append(resp.Moderators, toModeratorWorkloadDTO(item))
+	}
+	httperrors.Write(w, http.StatusOK, resp)
+}
+
+func (h *AdminBotModerationHandler) SaveModeratorWorkload(w http.ResponseWriter, r *http.Request) {
+	actorTGID, ok := adminBotActorTGID(r)
+	if !ok {
+		writeUnauthorized(w, "UNAUTHORIZED", "admin bot authentication required")
+		return
+	}
+	if h.service == nil {
+		writeInternal(w, "MODERATION_SERVICE_UNAVAILABLE", "moderation service is unavailable")
+		return
+	}
+	moderatorTGID, ok := moderatorTGIDFromRequest(r)
+	if !ok {
+		writeBadRequest(w, "VALIDATION_ERROR", "invalid moderator tg id")
+		return
+	}
+
+	var req dto.AdminBotModeratorWorkloadRequest
+	if err := decodeJSON(r, &req); err != nil {
+		writeBadRequest(w, "VALIDATION_ERROR", "invalid request body")
+		return
+	}
+
+	saved, err := h.service.SaveModeratorWorkload(r.Context(), moderatorTGID, req.MaxActiveItems, req.Skills, actorTGID)
+	if err != nil {
+		writeWorkloadError(w, err, "failed to save moderator workload")
+		return
+	}
+
+	h.logModerationAudit(r, "MODERATOR_WORKLOAD_UPDATE", actorTGID, 0, map[string]any{
+		"moderator_tg_id":  saved.ModeratorTGID,
+		"max_active_items": saved.MaxActiveItems,
+		"skills":           saved.Skills,
+	})
+	httperrors.Write(w, http.StatusOK, toModeratorWorkloadDTO(saved))
+}
+
+func (h *AdminBotModerationHandler) AddModeratorShift(w http.ResponseWriter, r *http.Request) {
+	actorTGID, ok := adminBotActorTGID(r)
+	if !ok {
+		writeUnauthorized(w, "UNAUTHORIZED", "admin bot authentication required")
+		return
+	}
+	if h.service == nil {
+		writeInternal(w, "MODERATION_SERVICE_UNAVAILABLE", "moderation service is unavailable")
+		return
+	}
+	moderatorTGID, ok := moderatorTGIDFromRequest(r)
+	if !ok {
+		writeBadRequest(w, "VALIDATION_ERROR", "invalid moderator tg id")
+		return
+	}
+
+	var req dto.AdminBotModeratorShiftRequest
+	if err := decodeJSON(r, &req); err != nil {
+		writeBadRequest(w, "VALIDATION_ERROR", "invalid request body")
+		return
+	}
+
+	shift, err := h.service.AddModeratorShift(r.Context(), moderatorTGID, req.Weekday, req.StartsAt, req.EndsAt, actorTGID)
+	if err != nil {
+		writeWorkloadError(w, err, "failed to add moderator shift")
+		return
+	}
+
+	h.logModerationAudit(r, "MODERATOR_SHIFT_ADD", actorTGID, 0, map[string]any{
+		"shift_id":        shift.ID,
+		"moderator_tg_id": shift.ModeratorTGID,
+		"weekday":         shift.Weekday,
+		"starts_at":       shift.StartsAt,
+		"ends_at":         shift.EndsAt,
+	})
+	httperrors.Write(w, http.StatusOK, toModeratorShiftDTO(shift))
+}
+
+func (h *AdminBotModerationHandler) DeleteModeratorShift(w http.ResponseWriter, r *http.Request) {
+	actorTGID, ok := adminBotActorTGID(r)
+	if !ok {
+		writeUnauthorized(w, "UNAUTHORIZED", "admin bot authentication required")
+		return
+	}
+	if h.service == nil {
+		writeInternal(w, "MODERATION_SERVICE_UNAVAILABLE", "moderation service is unavailable")
+		return
+	}
+	shiftID, ok := moderationItemIDFromRequest(r)
+	if !ok {
+		writeBadRequest(w, "VALIDATION_ERROR", "invalid shift id")
+		return
+	}
+
+	shift, err := h.service.DeleteModeratorShift(r.Context(), shiftID)
+	if err != nil {
+		writeWorkloadError(w, err, "failed to delete moderator shift")
+		return
+	}
+
+	h.logModerationAudit(r, "MODERATOR_SHIFT_DELETE", actorTGID, 0, map[string]any{
+		"shift_id":        shift.ID,
+		"moderator_tg_id": shift.ModeratorTGID,
+	})
+	httperrors.Write(w, http.StatusOK, toModeratorShiftDTO(shift))
+}
+
+func (h *AdminBotModerationHandler) ReassignIdleLocks(w http.ResponseWriter, r *http.Request) {
+	actorTGID, ok := adminBotActorTGID(r)
+	if !ok {
+		writeUnauthorized(w, "UNAUTHORIZED", "admin bot authentication required")
+		return
+	}
+	if h.service == nil {
+		writeInternal(w, "MODERATION_SERVICE_UNAVAILABLE", "moderation service is unavailable")
+		return
+	}
+
+	var req dto.AdminBotReassignIdleRequest
+	if err := decodeJSON(r, &req); err != nil {
+		writeBadRequest(w, "VALIDATION_ERROR", "invalid request body")
+		return
+	}
+
+	moved, err := h.service.ReassignIdleLocks(r.Context(), time.Duration(req.IdleSec)*time.Second)
+	if err != nil {
+		writeWorkloadError(w, err, "failed to reassign idle locks")
+		return
+	}
+
+	resp := dto.AdminBotReassignIdleResponse{Items: make([]dto.AdminBotLockReassignmentItem, 0, len(moved))}
+	for _, item := range moved {
+		resp.Items = append(resp.Items, dto.AdminBotLockReassignmentItem{
+			ModerationItemID: item.ModerationItemID,
+			UserID:           item.UserID,
+			Skill:            item.Skill,
+			FromTGID:         item.FromTGID,
+			ToTGID:           item.ToTGID,
+			IdleSec:          item.IdleSec,
+		})
+		h.logModerationAudit(r, "MODERATION_LOCK_REASSIGN", actorTGID, item.ModerationItemID, map[string]any{
+			"from_tg_id": item.FromTGID,
+			"to_tg_id":   item.ToTGID,
+			"idle_sec":   item.IdleSec,
+		})
+	}
+	httperrors.Write(w, http.StatusOK, resp)
+}
+
+func (h *AdminBotModerationHandler) ShiftThroughput(w http.ResponseWriter, r *http.Request) {
+	if _, ok := adminBotActorTGID(r); !ok {
+		writeUnauthorized(w, "UNAUTHORIZED", "admin bot authentication required")
+		return
+	}
+	if h.service == nil {
+		writeInternal(w, "MODERATION_SERVICE_UNAVAILABLE", "moderation service is unavailable")
+		return
+	}
+
+	to := time.Now().UTC()
+	if raw := strings.TrimSpace(r.URL.Query().Get("to")); raw != "" {
+		parsed, err := time.Parse(time.RFC3339, raw)
+		if err != nil {
+			writeBadRequest(w, "VALIDATION_ERROR", "to must be RFC3339")
+			return
+		}
+		to = parsed.UTC()
+	}
+	from := to.Add(-24 * time.Hour)
+	if raw := strings.TrimSpace(r.URL.Query().Get("from")); raw != "" {
+		parsed, err := time.Parse(time.RFC3339, raw)
+		if err != nil {
+			writeBadRequest(w, "VALIDATION_ERROR", "from must be RFC3339")
+			return
+		}
+		from = parsed.UTC()
+	}
+
+	items, err := h.service.ShiftThroughput(r.Context(), from, to)
+	if err != nil {
+		writeWorkloadError(w, err, "failed to load shift throughput")
+		return
+	}
+
+	resp := dto.AdminBotShiftThroughputResponse{
+		From:  from,
+		To:    to,
+		Items: make([]dto.AdminBotShiftThroughputItem, 0, len(items)),
+	}
+	for _, item := range items {
+		resp.Items = append(resp.Items, dto.AdminBotShiftThroughputItem{
+			ShiftID:       item.ShiftID,
+			ModeratorTGID: item.ModeratorTGID,
+			StartsAt:      item.StartsAt.UTC(),
+			EndsAt:        item.EndsAt.UTC(),
+			Decisions:     item.Decisions,
+			Approved:      item.Approved,
+			Rejected:      item.Rejected,
+		})
+	}
+	httperrors.Write(w, http.StatusOK, resp)
+}
+
+func writeWorkloadError(w http.ResponseWriter, err error, message string) {
+	switch {
+	case errors.Is(err, modsvc.ErrInvalidWorkload):
+		writeBadRequest(w, "VALIDATION_ERROR", err.Error())
+	case errors.Is(err, pgrepo.ErrModeratorShiftNotFound):
+		httperrors.Write(w, http.StatusNotFound, httperrors.APIError{
+			Code:    "SHIFT_NOT_FOUND",
+			Message: "moderator shift not found",
+		})
+	case errors.Is(err, modsvc.ErrWorkloadUnavailable):
+		writeInternal(w, "WORKLOAD_UNAVAILABLE", "moderator workload is not configured")
+	default:
+		writeInternal(w, "INTERNAL_ERROR", message)
+	}
+}
+
+func moderatorTGIDFromRequest(r *http.Request) (int64, bool) {
+	raw := strings.TrimSpace(chi.URLParam(r, "tg_id"))
+	tgID, err := strconv.ParseInt(raw, 10, 64)
+	if err != nil || tgID == 0 {
+		return 0, false
+	}
+	return tgID, true
+}
+
+func toModeratorWorkloadDTO(item pgrepo.ModeratorWorkloadRecord) dto.AdminBotModeratorWorkloadItem {
+	out := dto.AdminBotModeratorWorkloadItem{
+		ModeratorTGID:  item.ModeratorTGID,
+		MaxActiveItems: item.MaxActiveItems,
+		Skills:         append([]string{}, item.Skills...),
+		ActiveItems:    item.ActiveItems,
+		OnShift:        item.OnShift,
+		Shifts:         make([]dto.AdminBotModeratorShiftItem, 0, len(item.Shifts)),
+		UpdatedByTGID:  item.UpdatedByTGID,
+		UpdatedAt:      item.UpdatedAt,
+	}
+	for _, shift := range item.Shifts {
+		out.Shifts = append(out.Shifts, toModeratorShiftDTO(shift))
+	}
+	return out
+}
+
+func toModeratorShiftDTO(shift pgrepo.ModeratorShiftRecord) dto.AdminBotModeratorShiftItem {
+	return dto.AdminBotModeratorShiftItem{
+		ID:            shift.ID,
+		ModeratorTGID: shift.ModeratorTGID,
+		Weekday:       shift.Weekday,
+		StartsAt:      shift.StartsAt,
+		EndsAt:        shift.EndsAt,
+		CreatedByTGID: shift.CreatedByTGID,
+		CreatedAt:     shift.CreatedAt.UTC(),
+	}
+}
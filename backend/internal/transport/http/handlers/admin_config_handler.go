@@ -0,0 +1,319 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+
+	auditsvc "github.com/ivankudzin/tgapp/backend/internal/services/audit"
+	authsvc "github.com/ivankudzin/tgapp/backend/internal/services/auth"
+	remotecfgsvc "github.com/ivankudzin/tgapp/backend/internal/services/remoteconfig"
+	"github.com/ivankudzin/tgapp/backend/internal/transport/http/dto"
+	httperrors "github.com/ivankudzin/tgapp/backend/internal/transport/http/errors"
+)
+
+type AdminConfigHandler struct {
+	remote *remotecfgsvc.Service
+	audit  *auditsvc.Service
+}
+
+func NewAdminConfigHandler(remote *remotecfgsvc.Service, audit *auditsvc.Service) *AdminConfigHandler {
+	return &AdminConfigHandler{remote: remote, audit: audit}
+}
+
+func (h *AdminConfigHandler) Current(w http.ResponseWriter, r *http.Request) {
+	if _, ok := authsvc.IdentityFromContext(r.Context()); !ok {
+		writeUnauthorized(w, "UNAUTHORIZED", "authentication required")
+		return
+	}
+	if h.remote == nil {
+		writeInternal(w, "REMOTE_CONFIG_UNAVAILABLE", "remote config service is unavailable")
+		return
+	}
+
+	snapshot := h.remote.Current()
+	httperrors.Write(w, http.StatusOK, dto.AdminConfigResponse{
+		Version:     snapshot.Version,
+		Checksum:    snapshot.Checksum,
+		ETag:        snapshot.ETag(),
+		PublishedAt: optionalTime(snapshot.PublishedAt),
+		Document:    snapshot.Document,
+	})
+}
+
+func (h *AdminConfigHandler) ListVersions(w http.ResponseWriter, r *http.Request) {
+	if _, ok := authsvc.IdentityFromContext(r.Context()); !ok {
+		writeUnauthorized(w, "UNAUTHORIZED", "authentication required")
+		return
+	}
+	if h.remote == nil {
+		writeInternal(w, "REMOTE_CONFIG_UNAVAILABLE", "remote config service is unavailable")
+		return
+	}
+
+	query := r.URL.Query()
+	limit, err := queryNonNegativeInt(query, "limit")
+	if err != nil {
+		writeBadRequest(w, "VALIDATION_ERROR", err.Error())
+		return
+	}
+	offset, err := queryNonNegativeInt(query, "offset")
+	if err != nil {
+		writeBadRequest(w, "VALIDATION_ERROR", err.Error())
+		return
+	}
+
+	items, err := h.remote.ListVersions(r.Context(), limit, offset)
+	if err != nil {
+		writeRemoteConfigError(w, err, "failed to list remote config versions")
+		return
+	}
+
+	resp := dto.AdminConfigVersionListResponse{
+		Items:  make([]dto.AdminConfigVersionItem, 0, len(items)),
+		Offset: offset,
+		Limit:  limit,
+	}
+	for _, item := range items {
+		resp.Items = append(resp.Items, toAdminConfigVersionItem(item))
+	}
+	httperrors.Write(w, http.StatusOK, resp)
+}
+
+func (h *AdminConfigHandler) GetVersion(w http.ResponseWriter, r *http.Request) {
+	if _, ok := authsvc.IdentityFromContext(r.Context()); !ok {
+		writeUnauthorized(w, "UNAUTHORIZED", "authentication required")
+		return
+	}
+	if h.remote == nil {
+		writeInternal(w, "REMOTE_CONFIG_UNAVAILABLE", "remote config service is unavailable")
+		return
+	}
+
+	version, err := strconv.ParseInt(strings.TrimSpace(chi.URLParam(r, "version")), 10, 64)
+	if err != nil || version <= 0 {
+		writeBadRequest(w, "VALIDATION_ERROR", "invalid version")
+		return
+	}
+
+	item, err := h.remote.GetVersion(r.Context(), version)
+	if err != nil {
+		writeRemoteConfigError(w, err, "failed to load remote config version")
+		return
+	}
+	httperrors.Write(w, http.StatusOK, toAdminConfigVersionItem(item))
+}
+
+// Diff compares ?from= with ?to=; without to the version currently served is used.
+func (h *AdminConfigHandler) Diff(w http.ResponseWriter, r *http.Request) {
+	if _, ok := authsvc.IdentityFromContext(r.Context()); !ok {
+		writeUnauthorized(w, "UNAUTHORIZED", "authentication required")
+		return
+	}
+	if h.remote == nil {
+		writeInternal(w, "REMOTE_CONFIG_UNAVAILABLE", "remote config service is unavailable")
+		return
+	}
+
+	query := r.URL.Query()
+	from, err := strconv.ParseInt(strings.TrimSpace(query.Get("from")), 10, 64)
+	if err != nil || from <= 0 {
+		writeBadRequest(w, "VALIDATION_ERROR", "from must be a positive version")
+		return
+	}
+	var to int64
+	if raw := strings.TrimSpace(query.Get("to")); raw != "" {
+		to, err = strconv.ParseInt(raw, 10, 64)
+		if err != nil || to <= 0 {
+			writeBadRequest(w, "VALIDATION_ERROR", "to must be a positive version")
+			return
+		}
+	}
+
+	changes, err := h.remote.Diff(r.Context(), from, to)
+	if err != nil {
+		writeRemoteConfigError(w, err, "failed to diff remote config versions")
+		return
+	}
+	if to == 0 {
+		to = h.remote.Current().Version
+	}
+	httperrors.Write(w, http.StatusOK, dto.AdminConfigDiffResponse{
+		From:    from,
+		To:      to,
+		Changes: toAdminConfigChanges(changes),
+	})
+}
+
+func (h *AdminConfigHandler) Publish(w http.ResponseWriter, r *http.Request) {
+	identity, ok := authsvc.IdentityFromContext(r.Context())
+	if !ok {
+		writeUnauthorized(w, "UNAUTHORIZED", "authentication required")
+		return
+	}
+	if h.remote == nil {
+		writeInternal(w, "REMOTE_CONFIG_UNAVAILABLE", "remote config service is unavailable")
+		return
+	}
+
+	var req dto.AdminConfigPublishRequest
+	if err := decodeJSON(r, &req); err != nil {
+		writeBadRequest(w, "VALIDATION_ERROR", "invalid json body")
+		return
+	}
+
+	result, err := h.remote.Publish(r.Context(), remotecfgsvc.PublishInput{
+		Document:    req.Document,
+		Comment:     req.Comment,
+		BaseVersion: req.BaseVersion,
+		DryRun:      req.DryRun,
+		By:          adminConfigPublisher(r, identity),
+	})
+	if err != nil {
+		writeRemoteConfigError(w, err, "failed to publish remote config")
+		return
+	}
+
+	if !result.DryRun {
+		h.appendAudit(r, "REMOTE_CONFIG_PUBLISH", identity, result, nil)
+	}
+	httperrors.Write(w, http.StatusOK, toAdminConfigPublishResponse(result))
+}
+
+func (h *AdminConfigHandler) Rollback(w http.ResponseWriter, r *http.Request) {
+	identity, ok := authsvc.IdentityFromContext(r.Context())
+	if !ok {
+		writeUnauthorized(w, "UNAUTHORIZED", "authentication required")
+		return
+	}
+	if h.remote == nil {
+		writeInternal(w, "REMOTE_CONFIG_UNAVAILABLE", "remote config service is unavailable")
+		return
+	}
+
+	var req dto.AdminConfigRollbackRequest
+	if err := decodeJSON(r, &req); err != nil {
+		writeBadRequest(w, "VALIDATION_ERROR", "invalid json body")
+		return
+	}
+	if req.Version <= 0 {
+		writeBadRequest(w, "VALIDATION_ERROR", "version is required")
+		return
+	}
+
+	result, err := h.remote.Rollback(r.Context(), req.Version, req.Comment, adminConfigPublisher(r, identity))
+	if err != nil {
+		writeRemoteConfigError(w, err, "failed to roll back remote config")
+		return
+	}
+
+	h.appendAudit(r, "REMOTE_CONFIG_ROLLBACK", identity, result, map[string]any{
+		"rollback_of": req.Version,
+	})
+	httperrors.Write(w, http.StatusOK, toAdminConfigPublishResponse(result))
+}
+
+func (h *AdminConfigHandler) appendAudit(
+	r *http.Request,
+	action string,
+	identity authsvc.Identity,
+	result remotecfgsvc.PublishResult,
+	extra map[string]any,
+) {
+	if h.audit == nil {
+		return
+	}
+
+	paths := make([]string, 0, len(result.Changes))
+	for _, change := range result.Changes {
+		paths = append(paths, change.Path)
+	}
+	props := map[string]any{
+		"source":        "web",
+		"actor_user_id": identity.UserID,
+		"actor_role":    strings.TrimSpace(identity.Role),
+		"version":       result.Version.Version,
+		"checksum":      result.Version.Checksum,
+		"comment":       result.Version.Comment,
+		"changed_paths": paths,
+	}
+	for key, value := range extra {
+		props[key] = value
+	}
+	payload, _ := json.Marshal(props)
+	actorTGID, _ := authsvc.ActorTGIDFromContext(r.Context())
+	_, _ = h.audit.Append(r.Context(), actorTGID, action, payload)
+}
+
+func adminConfigPublisher(r *http.Request, identity authsvc.Identity) remotecfgsvc.Publisher {
+	actorTGID, _ := authsvc.ActorTGIDFromContext(r.Context())
+	return remotecfgsvc.Publisher{AdminUserID: identity.UserID, TGID: actorTGID}
+}
+
+func writeRemoteConfigError(w http.ResponseWriter, err error, fallback string) {
+	switch {
+	case errors.Is(err, remotecfgsvc.ErrInvalidDocument):
+		writeBadRequest(w, "VALIDATION_ERROR", err.Error())
+	case errors.Is(err, remotecfgsvc.ErrNoChanges):
+		writeBadRequest(w, "NO_CHANGES", "document matches the current remote config")
+	case errors.Is(err, remotecfgsvc.ErrVersionNotFound):
+		httperrors.Write(w, http.StatusNotFound, httperrors.APIError{
+			Code:    "NOT_FOUND",
+			Message: "remote config version not found",
+		})
+	case errors.Is(err, remotecfgsvc.ErrVersionConflict):
+		httperrors.Write(w, http.StatusConflict, httperrors.APIError{
+			Code:    "VERSION_CONFLICT",
+			Message: "remote config was published by someone else; reload and retry",
+		})
+	case errors.Is(err, remotecfgsvc.ErrUnavailable):
+		httperrors.Write(w, http.StatusServiceUnavailable, httperrors.APIError{
+			Code:    "REMOTE_CONFIG_UNAVAILABLE",
+			Message: "remote config store is unavailable",
+		})
+	default:
+		writeInternal(w, "INTERNAL_ERROR", fallback)
+	}
+}
+
+func toAdminConfigVersionItem(item remotecfgsvc.Version) dto.AdminConfigVersionItem {
+	return dto.AdminConfigVersionItem{
+		Version:                item.Version,
+		Checksum:               item.Checksum,
+		Comment:                item.Comment,
+		RollbackOf:             item.RollbackOf,
+		PublishedByAdminUserID: item.PublishedByAdminUserID,
+		PublishedByTGID:        item.PublishedByTGID,
+		PublishedAt:            optionalTime(item.PublishedAt),
+		Document:               item.Document,
+	}
+}
+
+func toAdminConfigChanges(changes []remotecfgsvc.Change) []dto.AdminConfigChange {
+	out := make([]dto.AdminConfigChange, 0, len(changes))
+	for _, change := range changes {
+		out = append(out, dto.AdminConfigChange{Path: change.Path, From: change.From, To: change.To})
+	}
+	return out
+}
+
+func toAdminConfigPublishResponse(result remotecfgsvc.PublishResult) dto.AdminConfigPublishResponse {
+	return dto.AdminConfigPublishResponse{
+		DryRun:  result.DryRun,
+		Version: toAdminConfigVersionItem(result.Version),
+		Changes: toAdminConfigChanges(result.Changes),
+	}
+}
+
+func optionalTime(value time.Time) *time.Time {
+	if value.IsZero() {
+		return nil
+	}
+	out := value.UTC()
+	return &out
+}
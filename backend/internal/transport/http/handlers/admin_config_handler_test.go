@@ -0,0 +1,85 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/ivankudzin/tgapp/backend/internal/config"
+	authsvc "github.com/ivankudzin/tgapp/backend/internal/services/auth"
+	remotecfgsvc "github.com/ivankudzin/tgapp/backend/internal/services/remoteconfig"
+	"github.com/ivankudzin/tgapp/backend/internal/transport/http/dto"
+)
+
+func TestAdminConfigCurrentServesSnapshot(t *testing.T) {
+	handler := NewAdminConfigHandler(remotecfgsvc.NewService(config.Default().Remote), nil)
+
+	req := httptest.NewRequest(http.MethodGet, "/admin/config", nil)
+	req = req.WithContext(authsvc.WithIdentity(req.Context(), authsvc.Identity{UserID: 1, Role: "OWNER"}))
+	rr := httptest.NewRecorder()
+
+	handler.Current(rr, req)
+
+	if rr.Code != http.StatusOK {
+		t.Fatalf("unexpected status: got=%d want=%d", rr.Code, http.StatusOK)
+	}
+	var resp dto.AdminConfigResponse
+	if err := json.Unmarshal(rr.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("decode response: %v", err)
+	}
+	if resp.Version != 0 || resp.Checksum == "" || resp.ETag == "" || len(resp.Document) == 0 {
+		t.Fatalf("unexpected response: %+v", resp)
+	}
+}
+
+func TestAdminConfigPublishValidatesDocument(t *testing.T) {
+	handler := NewAdminConfigHandler(remotecfgsvc.NewService(config.Default().Remote), nil)
+
+	body := strings.NewReader(`{"document":{"limits":{"free_likes":10}},"comment":"typo"}`)
+	req := httptest.NewRequest(http.MethodPost, "/admin/config/publish", body)
+	req = req.WithContext(authsvc.WithIdentity(req.Context(), authsvc.Identity{UserID: 1, Role: "OWNER"}))
+	rr := httptest.NewRecorder()
+
+	handler.Publish(rr, req)
+
+	if rr.Code != http.StatusBadRequest || !strings.Contains(rr.Body.String(), "VALIDATION_ERROR") {
+		t.Fatalf("unexpected response: got=%d body=%s", rr.Code, rr.Body.String())
+	}
+}
+
+func TestAdminConfigPublishWithoutStoreIsUnavailable(t *testing.T) {
+	remote := config.Default().Remote
+	handler := NewAdminConfigHandler(remotecfgsvc.NewService(remote), nil)
+
+	remote.Limits.FreeLikesPerDay = 50
+	document, err := remotecfgsvc.EncodeDocument(remote)
+	if err != nil {
+		t.Fatalf("encode: %v", err)
+	}
+	body, _ := json.Marshal(dto.AdminConfigPublishRequest{Document: document})
+	req := httptest.NewRequest(http.MethodPost, "/admin/config/publish", strings.NewReader(string(body)))
+	req = req.WithContext(authsvc.WithIdentity(req.Context(), authsvc.Identity{UserID: 1, Role: "OWNER"}))
+	rr := httptest.NewRecorder()
+
+	handler.Publish(rr, req)
+
+	if rr.Code != http.StatusServiceUnavailable {
+		t.Fatalf("unexpected status: got=%d want=%d", rr.Code, http.StatusServiceUnavailable)
+	}
+}
+
+func TestAdminConfigDiffRequiresFrom(t *testing.T) {
+	handler := NewAdminConfigHandler(remotecfgsvc.NewService(config.Default().Remote), nil)
+
+	req := httptest.NewRequest(http.MethodGet, "/admin/config/diff?to=2", nil)
+	req = req.WithContext(authsvc.WithIdentity(req.Context(), authsvc.Identity{UserID: 1, Role: "OWNER"}))
+	rr := httptest.NewRecorder()
+
+	handler.Diff(rr, req)
+
+	if rr.Code != http.StatusBadRequest {
+		t.Fatalf("unexpected status: got=%d want=%d", rr.Code, http.StatusBadRequest)
+	}
+}
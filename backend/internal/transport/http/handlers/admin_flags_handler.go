@@ -0,0 +1,287 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/go-chi/chi/v5"
+
+	auditsvc "github.com/ivankudzin/tgapp/backend/internal/services/audit"
+	authsvc "github.com/ivankudzin/tgapp/backend/internal/services/auth"
+	flagssvc "github.com/ivankudzin/tgapp/backend/internal/services/flags"
+	"github.com/ivankudzin/tgapp/backend/internal/transport/http/dto"
+	httperrors "github.com/ivankudzin/tgapp/backend/internal/transport/http/errors"
+)
+
+type AdminFlagsHandler struct {
+	flags *flagssvc.Service
+	audit *auditsvc.Service
+}
+
+func NewAdminFlagsHandler(flags *flagssvc.Service, audit *auditsvc.Service) *AdminFlagsHandler {
+	return &AdminFlagsHandler{flags: flags, audit: audit}
+}
+
+func (h *AdminFlagsHandler) List(w http.ResponseWriter, r *http.Request) {
+	if _, ok := authsvc.IdentityFromContext(r.Context()); !ok {
+		writeUnauthorized(w, "UNAUTHORIZED", "authentication required")
+		return
+	}
+	h.writeList(w, r)
+}
+
+func (h *AdminFlagsHandler) Get(w http.ResponseWriter, r *http.Request) {
+	if _, ok := authsvc.IdentityFromContext(r.Context()); !ok {
+		writeUnauthorized(w, "UNAUTHORIZED", "authentication required")
+		return
+	}
+	if h.flags == nil {
+		writeInternal(w, "FLAGS_UNAVAILABLE", "flag service is unavailable")
+		return
+	}
+
+	flag, err := h.flags.Get(r.Context(), strings.TrimSpace(chi.URLParam(r, "key")))
+	if err != nil {
+		writeFlagError(w, err, "failed to load flag")
+		return
+	}
+	httperrors.Write(w, http.StatusOK, toAdminFlagItem(flag))
+}
+
+func (h *AdminFlagsHandler) Upsert(w http.ResponseWriter, r *http.Request) {
+	identity, ok := authsvc.IdentityFromContext(r.Context())
+	if !ok {
+		writeUnauthorized(w, "UNAUTHORIZED", "authentication required")
+		return
+	}
+	actorTGID, _ := authsvc.ActorTGIDFromContext(r.Context())
+	h.upsert(w, r, actorTGID, map[string]any{
+		"source":        "web",
+		"actor_user_id": identity.UserID,
+	})
+}
+
+func (h *AdminFlagsHandler) Delete(w http.ResponseWriter, r *http.Request) {
+	identity, ok := authsvc.IdentityFromContext(r.Context())
+	if !ok {
+		writeUnauthorized(w, "UNAUTHORIZED", "authentication required")
+		return
+	}
+	actorTGID, _ := authsvc.ActorTGIDFromContext(r.Context())
+	h.delete(w, r, actorTGID, map[string]any{
+		"source":        "web",
+		"actor_user_id": identity.UserID,
+	})
+}
+
+// Evaluate previews what a flag resolves to for ?user_id=, using the same targeting data
+// the API uses for that user.
+func (h *AdminFlagsHandler) Evaluate(w http.ResponseWriter, r *http.Request) {
+	if _, ok := authsvc.IdentityFromContext(r.Context()); !ok {
+		writeUnauthorized(w, "UNAUTHORIZED", "authentication required")
+		return
+	}
+	if h.flags == nil {
+		writeInternal(w, "FLAGS_UNAVAILABLE", "flag service is unavailable")
+		return
+	}
+
+	userID, err := strconv.ParseInt(strings.TrimSpace(r.URL.Query().Get("user_id")), 10, 64)
+	if err != nil || userID <= 0 {
+		writeBadRequest(w, "VALIDATION_ERROR", "user_id must be a positive integer")
+		return
+	}
+
+	flag, err := h.flags.Get(r.Context(), strings.TrimSpace(chi.URLParam(r, "key")))
+	if err != nil {
+		writeFlagError(w, err, "failed to load flag")
+		return
+	}
+
+	subject := h.flags.Subject(r.Context(), userID)
+	value := flag.Evaluate(subject)
+	httperrors.Write(w, http.StatusOK, dto.AdminFlagEvaluateResponse{
+		Key:     flag.Key,
+		UserID:  subject.UserID,
+		CityID:  subject.CityID,
+		Gender:  subject.Gender,
+		IsPlus:  subject.IsPlus,
+		Bucket:  flagssvc.Bucket(flag.Key, subject.UserID),
+		Value:   value,
+		Default: string(value) == string(flag.Value),
+	})
+}
+
+func (h *AdminFlagsHandler) BotList(w http.ResponseWriter, r *http.Request) {
+	if _, ok := adminBotActorTGID(r); !ok {
+		writeUnauthorized(w, "UNAUTHORIZED", "admin bot authentication required")
+		return
+	}
+	h.writeList(w, r)
+}
+
+func (h *AdminFlagsHandler) BotUpsert(w http.ResponseWriter, r *http.Request) {
+	actorTGID, ok := adminBotActorTGID(r)
+	if !ok {
+		writeUnauthorized(w, "UNAUTHORIZED", "admin bot authentication required")
+		return
+	}
+	h.upsert(w, r, actorTGID, map[string]any{"source": "bot"})
+}
+
+func (h *AdminFlagsHandler) BotDelete(w http.ResponseWriter, r *http.Request) {
+	actorTGID, ok := adminBotActorTGID(r)
+	if !ok {
+		writeUnauthorized(w, "UNAUTHORIZED", "admin bot authentication required")
+		return
+	}
+	h.delete(w, r, actorTGID, map[string]any{"source": "bot"})
+}
+
+func (h *AdminFlagsHandler) writeList(w http.ResponseWriter, r *http.Request) {
+	if h.flags == nil {
+		writeInternal(w, "FLAGS_UNAVAILABLE", "flag service is unavailable")
+		return
+	}
+
+	items, err := h.flags.List(r.Context())
+	if err != nil {
+		writeFlagError(w, err, "failed to load flags")
+		return
+	}
+
+	resp := dto.AdminFlagListResponse{Items: make([]dto.AdminFlagItem, 0, len(items))}
+	for _, item := range items {
+		resp.Items = append(resp.Items, toAdminFlagItem(item))
+	}
+	httperrors.Write(w, http.StatusOK, resp)
+}
+
+func (h *AdminFlagsHandler) upsert(w http.ResponseWriter, r *http.Request, actorTGID int64, props map[string]any) {
+	if h.flags == nil {
+		writeInternal(w, "FLAGS_UNAVAILABLE", "flag service is unavailable")
+		return
+	}
+
+	var req dto.AdminFlagUpsertRequest
+	if err := decodeJSON(r, &req); err != nil {
+		writeBadRequest(w, "VALIDATION_ERROR", "invalid json body")
+		return
+	}
+
+	key := strings.TrimSpace(chi.URLParam(r, "key"))
+	previous, err := h.flags.Get(r.Context(), key)
+	hadPrevious := err == nil
+
+	rules := make([]flagssvc.Rule, 0, len(req.Rules))
+	for _, rule := range req.Rules {
+		rules = append(rules, flagssvc.Rule{
+			CityIDs:    rule.CityIDs,
+			Genders:    rule.Genders,
+			Plus:       rule.Plus,
+			UserIDs:    rule.UserIDs,
+			Percentage: rule.Percentage,
+			Value:      rule.Value,
+		})
+	}
+	saved, err := h.flags.Save(r.Context(), flagssvc.Flag{
+		Key:           key,
+		Type:          flagssvc.ValueType(req.Type),
+		Value:         req.Value,
+		Rules:         rules,
+		Description:   req.Description,
+		ClientVisible: req.ClientVisible,
+	}, actorTGID)
+	if err != nil {
+		writeFlagError(w, err, "failed to save flag")
+		return
+	}
+
+	item := toAdminFlagItem(saved)
+	props["key"] = saved.Key
+	props["after"] = item
+	if hadPrevious {
+		props["before"] = toAdminFlagItem(previous)
+	}
+	h.appendAudit(r, actorTGID, "FLAG_UPDATE", props)
+	httperrors.Write(w, http.StatusOK, item)
+}
+
+func (h *AdminFlagsHandler) delete(w http.ResponseWriter, r *http.Request, actorTGID int64, props map[string]any) {
+	if h.flags == nil {
+		writeInternal(w, "FLAGS_UNAVAILABLE", "flag service is unavailable")
+		return
+	}
+
+	key := strings.TrimSpace(chi.URLParam(r, "key"))
+	previous, err := h.flags.Get(r.Context(), key)
+	if err != nil {
+		writeFlagError(w, err, "failed to load flag")
+		return
+	}
+	if err := h.flags.Delete(r.Context(), key); err != nil {
+		writeFlagError(w, err, "failed to delete flag")
+		return
+	}
+
+	props["key"] = key
+	props["before"] = toAdminFlagItem(previous)
+	h.appendAudit(r, actorTGID, "FLAG_DELETE", props)
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (h *AdminFlagsHandler) appendAudit(r *http.Request, actorTGID int64, action string, props map[string]any) {
+	if h.audit == nil {
+		return
+	}
+	payload, _ := json.Marshal(props)
+	_, _ = h.audit.Append(r.Context(), actorTGID, action, payload)
+}
+
+func writeFlagError(w http.ResponseWriter, err error, fallback string) {
+	switch {
+	case errors.Is(err, flagssvc.ErrInvalidFlag):
+		writeBadRequest(w, "VALIDATION_ERROR", err.Error())
+	case errors.Is(err, flagssvc.ErrFlagNotFound):
+		httperrors.Write(w, http.StatusNotFound, httperrors.APIError{
+			Code:    "NOT_FOUND",
+			Message: "flag not found",
+		})
+	case errors.Is(err, flagssvc.ErrBuiltinFlag):
+		httperrors.Write(w, http.StatusConflict, httperrors.APIError{
+			Code:    "BUILTIN_FLAG",
+			Message: "built-in flags cannot be deleted or retyped",
+		})
+	case errors.Is(err, flagssvc.ErrFlagsReadOnly):
+		writeInternal(w, "FLAGS_READ_ONLY", "flag store is not configured")
+	default:
+		writeInternal(w, "INTERNAL_ERROR", fallback)
+	}
+}
+
+func toAdminFlagItem(flag flagssvc.Flag) dto.AdminFlagItem {
+	out := dto.AdminFlagItem{
+		Key:           flag.Key,
+		Type:          string(flag.Type),
+		Value:         flag.Value,
+		Rules:         make([]dto.AdminFlagRule, 0, len(flag.Rules)),
+		Description:   flag.Description,
+		ClientVisible: flag.ClientVisible,
+		UpdatedByTGID: flag.UpdatedByTGID,
+		UpdatedAt:     optionalTime(flag.UpdatedAt),
+	}
+	for _, rule := range flag.Rules {
+		out.Rules = append(out.Rules, dto.AdminFlagRule{
+			CityIDs:    rule.CityIDs,
+			Genders:    rule.Genders,
+			Plus:       rule.Plus,
+			UserIDs:    rule.UserIDs,
+			Percentage: rule.Percentage,
+			Value:      rule.Value,
+		})
+	}
+	return out
+}
@@ -0,0 +1,125 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/ivankudzin/tgapp/backend/internal/config"
+	pgrepo "github.com/ivankudzin/tgapp/backend/internal/repo/postgres"
+	authsvc "github.com/ivankudzin/tgapp/backend/internal/services/auth"
+	flagssvc "github.com/ivankudzin/tgapp/backend/internal/services/flags"
+	"github.com/ivankudzin/tgapp/backend/internal/transport/http/dto"
+)
+
+func TestAdminFlagsUpsertAndEvaluate(t *testing.T) {
+	store := &adminFlagStoreStub{subject: pgrepo.AppFlagSubjectRecord{CityID: "minsk", Gender: "female"}}
+	service := flagssvc.NewService(store)
+	service.AttachSubjects(store)
+	handler := NewAdminFlagsHandler(service, nil)
+
+	body := `{"type":"bool","value":false,"client_visible":true,"rules":[{"city_ids":["Minsk"],"value":true}]}`
+	req := httptest.NewRequest(http.MethodPut, "/admin/flags/travel_mode", strings.NewReader(body))
+	ctx := authsvc.WithIdentity(req.Context(), authsvc.Identity{UserID: 1, Role: "OWNER"})
+	req = req.WithContext(withURLParam(ctx, "key", "travel_mode"))
+	rr := httptest.NewRecorder()
+
+	handler.Upsert(rr, req)
+
+	if rr.Code != http.StatusOK {
+		t.Fatalf("unexpected status: got=%d body=%s", rr.Code, rr.Body.String())
+	}
+	var saved dto.AdminFlagItem
+	if err := json.Unmarshal(rr.Body.Bytes(), &saved); err != nil {
+		t.Fatalf("decode response: %v", err)
+	}
+	if saved.Key != "travel_mode" || len(saved.Rules) != 1 || saved.Rules[0].CityIDs[0] != "minsk" {
+		t.Fatalf("unexpected saved flag: %+v", saved)
+	}
+
+	req = httptest.NewRequest(http.MethodGet, "/admin/flags/travel_mode/evaluate?user_id=42", nil)
+	ctx = authsvc.WithIdentity(req.Context(), authsvc.Identity{UserID: 1, Role: "OWNER"})
+	req = req.WithContext(withURLParam(ctx, "key", "travel_mode"))
+	rr = httptest.NewRecorder()
+
+	handler.Evaluate(rr, req)
+
+	var preview dto.AdminFlagEvaluateResponse
+	if err := json.Unmarshal(rr.Body.Bytes(), &preview); err != nil {
+		t.Fatalf("decode response: %v", err)
+	}
+	if string(preview.Value) != "true" || preview.Default || preview.CityID != "minsk" {
+		t.Fatalf("unexpected preview: %+v", preview)
+	}
+
+	meHandler := NewMeHandler(config.Default().Remote, nil)
+	meHandler.AttachFlags(service)
+	req = httptest.NewRequest(http.MethodGet, "/v1/me", nil)
+	req = req.WithContext(authsvc.WithIdentity(req.Context(), authsvc.Identity{UserID: 42, Role: "USER"}))
+	rr = httptest.NewRecorder()
+
+	meHandler.Handle(rr, req)
+
+	var me dto.MeResponse
+	if err := json.Unmarshal(rr.Body.Bytes(), &me); err != nil {
+		t.Fatalf("decode me: %v", err)
+	}
+	if string(me.Flags["travel_mode"]) != "true" {
+		t.Fatalf("expected travel_mode in /me flags, got %v", me.Flags)
+	}
+}
+
+func TestAdminFlagsRejectsInvalidAndBuiltinChanges(t *testing.T) {
+	handler := NewAdminFlagsHandler(flagssvc.NewService(&adminFlagStoreStub{}), nil)
+
+	req := httptest.NewRequest(http.MethodPut, "/admin/flags/feed_limit", strings.NewReader(`{"type":"int","value":"many"}`))
+	ctx := authsvc.WithIdentity(req.Context(), authsvc.Identity{UserID: 1, Role: "OWNER"})
+	req = req.WithContext(withURLParam(ctx, "key", "feed_limit"))
+	rr := httptest.NewRecorder()
+	handler.Upsert(rr, req)
+	if rr.Code != http.StatusBadRequest {
+		t.Fatalf("unexpected status: got=%d body=%s", rr.Code, rr.Body.String())
+	}
+
+	req = httptest.NewRequest(http.MethodPut, "/admin/bot/flags/registration_enabled", strings.NewReader(`{"type":"string","value":"on"}`))
+	ctx = authsvc.WithActorTGID(authsvc.WithActorIsBot(req.Context(), true), 1001)
+	req = req.WithContext(withURLParam(ctx, "key", "registration_enabled"))
+	rr = httptest.NewRecorder()
+	handler.BotUpsert(rr, req)
+	if rr.Code != http.StatusConflict {
+		t.Fatalf("unexpected status: got=%d body=%s", rr.Code, rr.Body.String())
+	}
+}
+
+type adminFlagStoreStub struct {
+	records []pgrepo.AppFlagRecord
+	subject pgrepo.AppFlagSubjectRecord
+}
+
+func (s *adminFlagStoreStub) ListAppFlags(context.Context) ([]pgrepo.AppFlagRecord, error) {
+	return append([]pgrepo.AppFlagRecord(nil), s.records...), nil
+}
+
+func (s *adminFlagStoreStub) UpsertAppFlag(_ context.Context, in pgrepo.AppFlagRecord) (pgrepo.AppFlagRecord, error) {
+	for i := range s.records {
+		if s.records[i].Key == in.Key {
+			s.records[i] = in
+			return in, nil
+		}
+	}
+	s.records = append(s.records, in)
+	return in, nil
+}
+
+func (s *adminFlagStoreStub) DeleteAppFlag(context.Context, string) error {
+	return pgrepo.ErrAppFlagNotFound
+}
+
+func (s *adminFlagStoreStub) AppFlagSubject(_ context.Context, userID int64) (pgrepo.AppFlagSubjectRecord, error) {
+	out := s.subject
+	out.UserID = userID
+	return out, nil
+}
@@ -2,6 +2,7 @@ package handlers
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
 	"net/http"
 	"strconv"
@@ -13,7 +14,9 @@ import (
 	pgrepo "github.com/ivankudzin/tgapp/backend/internal/repo/postgres"
 	redrepo "github.com/ivankudzin/tgapp/backend/internal/repo/redis"
 	analyticsvc "github.com/ivankudzin/tgapp/backend/internal/services/analytics"
+	auditsvc "github.com/ivankudzin/tgapp/backend/internal/services/audit"
 	authsvc "github.com/ivankudzin/tgapp/backend/internal/services/auth"
+	modsvc "github.com/ivankudzin/tgapp/backend/internal/services/moderation"
 	userssvc "github.com/ivankudzin/tgapp/backend/internal/services/users"
 	"github.com/ivankudzin/tgapp/backend/internal/transport/http/dto"
 	httperrors "github.com/ivankudzin/tgapp/backend/internal/transport/http/errors"
@@ -28,13 +31,22 @@ type AntiAbuseDashboardReader interface {
 	Top(ctx context.Context, kind string, limit int64) ([]redrepo.OffenderItem, error)
 }
 
+type ModerationSLAReader interface {
+	GetSLA(ctx context.Context, window time.Duration) (modsvc.SLAReport, error)
+}
+
 type AdminHandler struct {
 	users     *userssvc.Service
 	telemetry *analyticsvc.Service
 	metrics   DailyMetricsReader
 	antiabuse AntiAbuseDashboardReader
+	sla       ModerationSLAReader
+	audit     *auditsvc.Service
 }
 
+// maxViewPrivateReasonLen bounds the justification stored with each private data view.
+const maxViewPrivateReasonLen = 500
+
 func NewAdminHandler(users *userssvc.Service, telemetry *analyticsvc.Service) *AdminHandler {
 	return &AdminHandler{
 		users:     users,
@@ -50,6 +62,14 @@ func (h *AdminHandler) AttachAntiAbuseDashboard(reader AntiAbuseDashboardReader)
 	h.antiabuse = reader
 }
 
+func (h *AdminHandler) AttachModerationSLA(reader ModerationSLAReader) {
+	h.sla = reader
+}
+
+func (h *AdminHandler) AttachAudit(audit *auditsvc.Service) {
+	h.audit = audit
+}
+
 func (h *AdminHandler) Health(w http.ResponseWriter, r *http.Request) {
 	identity, ok := authsvc.IdentityFromContext(r.Context())
 	if !ok {
@@ -81,7 +101,28 @@ func (h *AdminHandler) UserPrivate(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	h.logViewPrivateAudit(r, identity.UserID, identity.Role, targetUserID)
+	var req dto.AdminUserPrivateRequest
+	if err := decodeJSON(r, &req); err != nil {
+		writeBadRequest(w, "VALIDATION_ERROR", "invalid request body")
+		return
+	}
+	reason := strings.TrimSpace(req.Reason)
+	if reason == "" {
+		writeBadRequest(w, "REASON_REQUIRED", "reason is required to view private data")
+		return
+	}
+	if len([]rune(reason)) > maxViewPrivateReasonLen {
+		writeBadRequest(w, "VALIDATION_ERROR", "reason is too long")
+		return
+	}
+
+	if err := h.logViewPrivateAudit(r, identity, targetUserID, reason); err != nil {
+		httperrors.Write(w, http.StatusServiceUnavailable, httperrors.APIError{
+			Code:    "AUDIT_UNAVAILABLE",
+			Message: "private data is not shown while the view cannot be audited",
+		})
+		return
+	}
 
 	privateData, err := h.users.GetPrivate(r.Context(), targetUserID)
 	if err != nil {
@@ -238,19 +279,110 @@ func (h *AdminHandler) AntiAbuseTop(w http.ResponseWriter, r *http.Request) {
 	})
 }
 
-func (h *AdminHandler) logViewPrivateAudit(r *http.Request, actorUserID int64, actorRole string, targetUserID int64) {
-	if h.telemetry == nil || r == nil {
+func (h *AdminHandler) ModerationSLA(w http.ResponseWriter, r *http.Request) {
+	_, ok := authsvc.IdentityFromContext(r.Context())
+	if !ok {
+		writeUnauthorized(w, "UNAUTHORIZED", "authentication required")
+		return
+	}
+	if h.sla == nil {
+		writeInternal(w, "MODERATION_SLA_UNAVAILABLE", "moderation sla is unavailable")
+		return
+	}
+
+	window, ok := parseSLAWindow(r)
+	if !ok {
+		writeBadRequest(w, "VALIDATION_ERROR", "hours must be an integer between 1 and 720")
+		return
+	}
+
+	report, err := h.sla.GetSLA(r.Context(), window)
+	if err != nil {
+		writeInternal(w, "INTERNAL_ERROR", "failed to load moderation sla")
 		return
 	}
 
+	httperrors.Write(w, http.StatusOK, toModerationSLAResponse(report))
+}
+
+func parseSLAWindow(r *http.Request) (time.Duration, bool) {
+	raw := strings.TrimSpace(r.URL.Query().Get("hours"))
+	if raw == "" {
+		return 24 * time.Hour, true
+	}
+	hours, err := strconv.Atoi(raw)
+	if err != nil || hours < 1 || hours > 720 {
+		return 0, false
+	}
+	return time.Duration(hours) * time.Hour, true
+}
+
+func toModerationSLAResponse(report modsvc.SLAReport) dto.AdminModerationSLAResponse {
+	resp := dto.AdminModerationSLAResponse{
+		WindowHours:      int(report.Window / time.Hour),
+		GeneratedAt:      report.GeneratedAt,
+		Decided:          report.DecidedCount,
+		Approved:         report.ApprovedCount,
+		Rejected:         report.RejectedCount,
+		ApproveRatio:     report.ApproveRatio,
+		WaitP50Sec:       report.WaitP50Sec,
+		WaitP90Sec:       report.WaitP90Sec,
+		WaitP99Sec:       report.WaitP99Sec,
+		Pending:          report.PendingCount,
+		OldestPendingSec: report.OldestPendingSec,
+		DecidedLastHour:  report.DecidedLastHour,
+		ETABucket:        report.ETABucket,
+		LockExpired:      report.LockExpiredCount,
+		StaleLocks:       report.StaleLockCount,
+		Moderators:       make([]dto.AdminModerationSLAModerator, 0, len(report.Moderators)),
+		RejectReasons:    make([]dto.AdminModerationSLAReason, 0, len(report.RejectReasons)),
+	}
+	for _, item := range report.Moderators {
+		resp.Moderators = append(resp.Moderators, dto.AdminModerationSLAModerator{
+			ModeratorTGID:  item.ModeratorTGID,
+			Decisions:      item.Decisions,
+			Approved:       item.Approved,
+			Rejected:       item.Rejected,
+			AvgDecisionSec: item.AvgDecisionSec,
+			P90DecisionSec: item.P90DecisionSec,
+		})
+	}
+	for _, item := range report.RejectReasons {
+		resp.RejectReasons = append(resp.RejectReasons, dto.AdminModerationSLAReason{
+			ReasonCode:    item.ReasonCode,
+			Count:         item.Count,
+			ShareOfReject: item.ShareOfReject,
+			ShareOfTotal:  item.ShareOfTotal,
+		})
+	}
+	return resp
+}
+
+// logViewPrivateAudit records the view in both the telemetry stream and the hash-chained audit log.
+// Only the audit log append is mandatory: without it the caller must not reveal the data.
+func (h *AdminHandler) logViewPrivateAudit(r *http.Request, identity authsvc.Identity, targetUserID int64, reason string) error {
+	if h.audit == nil {
+		return errors.New("audit service is not configured")
+	}
+
 	props := map[string]any{
-		"action":         "VIEW_PRIVATE",
-		"actor_user_id":  actorUserID,
-		"actor_role":     strings.TrimSpace(actorRole),
+		"actor_user_id":  identity.UserID,
+		"actor_role":     strings.TrimSpace(identity.Role),
 		"target_user_id": targetUserID,
+		"reason":         reason,
 	}
 
-	actor := actorUserID
+	payload, _ := json.Marshal(withProp(props, "source", "web"))
+	actorTGID, _ := authsvc.ActorTGIDFromContext(r.Context())
+	if _, err := h.audit.Append(r.Context(), actorTGID, "VIEW_PRIVATE", payload); err != nil {
+		return err
+	}
+	if h.telemetry == nil {
+		return nil
+	}
+	props = withProp(props, "action", "VIEW_PRIVATE")
+
+	actor := identity.UserID
 	_ = h.telemetry.IngestBatch(r.Context(), &actor, []analyticsvc.BatchEvent{
 		{
 			Name:  "audit_log",
@@ -258,6 +390,16 @@ func (h *AdminHandler) logViewPrivateAudit(r *http.Request, actorUserID int64, a
 			Props: props,
 		},
 	})
+	return nil
+}
+
+func withProp(props map[string]any, key string, value any) map[string]any {
+	out := make(map[string]any, len(props)+1)
+	for k, v := range props {
+		out[k] = v
+	}
+	out[key] = value
+	return out
 }
 
 func adminTargetUserIDFromRequest(r *http.Request) (int64, bool) {
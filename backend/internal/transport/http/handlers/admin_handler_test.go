@@ -3,8 +3,10 @@ package handlers
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"net/http"
 	"net/http/httptest"
+	"strings"
 	"testing"
 	"time"
 
@@ -13,7 +15,9 @@ import (
 	pgrepo "github.com/ivankudzin/tgapp/backend/internal/repo/postgres"
 	redrepo "github.com/ivankudzin/tgapp/backend/internal/repo/redis"
 	analyticsvc "github.com/ivankudzin/tgapp/backend/internal/services/analytics"
+	auditsvc "github.com/ivankudzin/tgapp/backend/internal/services/audit"
 	authsvc "github.com/ivankudzin/tgapp/backend/internal/services/auth"
+	modsvc "github.com/ivankudzin/tgapp/backend/internal/services/moderation"
 	userssvc "github.com/ivankudzin/tgapp/backend/internal/services/users"
 	"github.com/ivankudzin/tgapp/backend/internal/transport/http/dto"
 )
@@ -22,8 +26,10 @@ func TestAdminUserPrivateWritesAuditLog(t *testing.T) {
 	store := &auditStoreStub{}
 	telemetry := analyticsvc.NewService(store, analyticsvc.Config{MaxBatchSize: 100})
 	handler := NewAdminHandler(userssvc.NewService(nil, nil, nil), telemetry)
+	auditRepo := &auditRepoStub{}
+	handler.AttachAudit(auditsvc.NewService(auditRepo))
 
-	req := httptest.NewRequest(http.MethodGet, "/admin/users/42/private", nil)
+	req := httptest.NewRequest(http.MethodPost, "/admin/users/42/private", strings.NewReader(`{"reason":"ticket 1234"}`))
 	req = req.WithContext(authsvc.WithIdentity(req.Context(), authsvc.Identity{
 		UserID: 100,
 		SID:    "sid-100",
@@ -37,6 +43,9 @@ func TestAdminUserPrivateWritesAuditLog(t *testing.T) {
 	if rr.Code != http.StatusNotFound {
 		t.Fatalf("unexpected status: got %d want %d", rr.Code, http.StatusNotFound)
 	}
+	if len(auditRepo.appended) != 1 || auditRepo.appended[0].Action != "VIEW_PRIVATE" {
+		t.Fatalf("expected one VIEW_PRIVATE audit record, got %+v", auditRepo.appended)
+	}
 	if len(store.events) != 1 {
 		t.Fatalf("expected one audit event, got %d", len(store.events))
 	}
@@ -52,6 +61,51 @@ func TestAdminUserPrivateWritesAuditLog(t *testing.T) {
 	if target != 42 {
 		t.Fatalf("unexpected target_user_id: %d", target)
 	}
+	reason, _ := store.events[0].Props["reason"].(string)
+	if reason != "ticket 1234" {
+		t.Fatalf("unexpected reason: %q", reason)
+	}
+}
+
+func TestAdminUserPrivateFailsClosedWhenAuditFails(t *testing.T) {
+	store := &auditStoreStub{}
+	telemetry := analyticsvc.NewService(store, analyticsvc.Config{MaxBatchSize: 100})
+	handler := NewAdminHandler(userssvc.NewService(nil, nil, nil), telemetry)
+	handler.AttachAudit(auditsvc.NewService(&auditRepoStub{appendErr: errors.New("audit down")}))
+
+	req := httptest.NewRequest(http.MethodPost, "/admin/users/42/private", strings.NewReader(`{"reason":"ticket 1234"}`))
+	req = req.WithContext(authsvc.WithIdentity(req.Context(), authsvc.Identity{UserID: 100, Role: "OWNER"}))
+	req = req.WithContext(withURLParam(req.Context(), "id", "42"))
+
+	rr := httptest.NewRecorder()
+	handler.UserPrivate(rr, req)
+
+	if rr.Code != http.StatusServiceUnavailable {
+		t.Fatalf("unexpected status: got %d want %d", rr.Code, http.StatusServiceUnavailable)
+	}
+	if len(store.events) != 0 {
+		t.Fatalf("expected no telemetry without an audit record, got %d", len(store.events))
+	}
+}
+
+func TestAdminUserPrivateRequiresReason(t *testing.T) {
+	store := &auditStoreStub{}
+	telemetry := analyticsvc.NewService(store, analyticsvc.Config{MaxBatchSize: 100})
+	handler := NewAdminHandler(userssvc.NewService(nil, nil, nil), telemetry)
+
+	req := httptest.NewRequest(http.MethodPost, "/admin/users/42/private", strings.NewReader(`{"reason":" "}`))
+	req = req.WithContext(authsvc.WithIdentity(req.Context(), authsvc.Identity{UserID: 100, Role: "OWNER"}))
+	req = req.WithContext(withURLParam(req.Context(), "id", "42"))
+
+	rr := httptest.NewRecorder()
+	handler.UserPrivate(rr, req)
+
+	if rr.Code != http.StatusBadRequest {
+		t.Fatalf("unexpected status: got %d want %d", rr.Code, http.StatusBadRequest)
+	}
+	if len(store.events) != 0 {
+		t.Fatalf("expected no audit events without a reason, got %d", len(store.events))
+	}
 }
 
 func TestAdminHealthReturnsIdentity(t *testing.T) {
@@ -180,6 +234,81 @@ func TestAdminAntiAbuseTopReturnsItems(t *testing.T) {
 	}
 }
 
+func TestAdminModerationSLAReturnsReport(t *testing.T) {
+	reader := &moderationSLAReaderStub{
+		report: modsvc.SLAReport{
+			Window:           48 * time.Hour,
+			DecidedCount:     10,
+			ApprovedCount:    7,
+			RejectedCount:    3,
+			ApproveRatio:     0.7,
+			WaitP90Sec:       1200,
+			ETABucket:        "up_to_20",
+			LockExpiredCount: 2,
+			Moderators:       []modsvc.ModeratorSLA{{ModeratorTGID: 55, Decisions: 10, AvgDecisionSec: 40}},
+			RejectReasons:    []modsvc.RejectReasonSLA{{ReasonCode: "PHOTO_NO_FACE", Count: 3, ShareOfReject: 1}},
+		},
+	}
+	handler := NewAdminHandler(nil, nil)
+	handler.AttachModerationSLA(reader)
+
+	req := httptest.NewRequest(http.MethodGet, "/admin/moderation/sla?hours=48", nil)
+	req = req.WithContext(authsvc.WithIdentity(req.Context(), authsvc.Identity{
+		UserID: 1,
+		SID:    "sid-1",
+		Role:   "OWNER",
+	}))
+	rr := httptest.NewRecorder()
+
+	handler.ModerationSLA(rr, req)
+
+	if rr.Code != http.StatusOK {
+		t.Fatalf("unexpected status: got %d want %d", rr.Code, http.StatusOK)
+	}
+	if reader.window != 48*time.Hour {
+		t.Fatalf("unexpected window: %s", reader.window)
+	}
+	var response dto.AdminModerationSLAResponse
+	if err := json.NewDecoder(rr.Body).Decode(&response); err != nil {
+		t.Fatalf("decode response: %v", err)
+	}
+	if response.WindowHours != 48 || response.WaitP90Sec != 1200 || response.LockExpired != 2 {
+		t.Fatalf("unexpected sla payload: %+v", response)
+	}
+	if len(response.Moderators) != 1 || len(response.RejectReasons) != 1 {
+		t.Fatalf("unexpected sla breakdowns: %+v", response)
+	}
+}
+
+func TestAdminModerationSLARejectsInvalidWindow(t *testing.T) {
+	handler := NewAdminHandler(nil, nil)
+	handler.AttachModerationSLA(&moderationSLAReaderStub{})
+
+	req := httptest.NewRequest(http.MethodGet, "/admin/moderation/sla?hours=0", nil)
+	req = req.WithContext(authsvc.WithIdentity(req.Context(), authsvc.Identity{
+		UserID: 1,
+		SID:    "sid-1",
+		Role:   "OWNER",
+	}))
+	rr := httptest.NewRecorder()
+
+	handler.ModerationSLA(rr, req)
+
+	if rr.Code != http.StatusBadRequest {
+		t.Fatalf("unexpected status: got %d want %d", rr.Code, http.StatusBadRequest)
+	}
+}
+
+type moderationSLAReaderStub struct {
+	report modsvc.SLAReport
+	window time.Duration
+}
+
+func (s *moderationSLAReaderStub) GetSLA(_ context.Context, window time.Duration) (modsvc.SLAReport, error) {
+	s.window = window
+	return s.report, nil
+}
+
 type auditStoreStub struct {
 	userIDs []*int64
 	events  []pgrepo.EventWriteRecord
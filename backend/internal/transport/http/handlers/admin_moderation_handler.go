@@ -0,0 +1,252 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strings"
+
+	pgrepo "github.com/ivankudzin/tgapp/backend/internal/repo/postgres"
+	auditsvc "github.com/ivankudzin/tgapp/backend/internal/services/audit"
+	authsvc "github.com/ivankudzin/tgapp/backend/internal/services/auth"
+	modsvc "github.com/ivankudzin/tgapp/backend/internal/services/moderation"
+	"github.com/ivankudzin/tgapp/backend/internal/transport/http/dto"
+	httperrors "github.com/ivankudzin/tgapp/backend/internal/transport/http/errors"
+)
+
+// AdminModerationHandler serves the moderation queue to the web admin panel. Locks are held
+// under the admin's Telegram ID so web and bot moderators never review the same card.
+type AdminModerationHandler struct {
+	service *modsvc.Service
+	audit   *auditsvc.Service
+}
+
+func NewAdminModerationHandler(service *modsvc.Service, audit *auditsvc.Service) *AdminModerationHandler {
+	return &AdminModerationHandler{service: service, audit: audit}
+}
+
+func (h *AdminModerationHandler) QueueAcquire(w http.ResponseWriter, r *http.Request) {
+	moderator, ok := adminWebModerator(r)
+	if !ok {
+		writeUnauthorized(w, "UNAUTHORIZED", "authentication required")
+		return
+	}
+	if h.service == nil {
+		writeInternal(w, "MODERATION_SERVICE_UNAVAILABLE", "moderation service is unavailable")
+		return
+	}
+
+	item, err := h.service.GetNextQueueItem(r.Context(), moderator.TGID)
+	if err != nil {
+		switch {
+		case errors.Is(err, modsvc.ErrQueueEmpty):
+			w.WriteHeader(http.StatusNoContent)
+		default:
+			writeInternal(w, "INTERNAL_ERROR", "failed to acquire moderation item")
+		}
+		return
+	}
+
+	httperrors.Write(w, http.StatusOK, toAdminBotModQueueAcquireResponse(item))
+}
+
+func (h *AdminModerationHandler) Item(w http.ResponseWriter, r *http.Request) {
+	if _, ok := adminWebModerator(r); !ok {
+		writeUnauthorized(w, "UNAUTHORIZED", "authentication required")
+		return
+	}
+	if h.service == nil {
+		writeInternal(w, "MODERATION_SERVICE_UNAVAILABLE", "moderation service is unavailable")
+		return
+	}
+
+	itemID, ok := moderationItemIDFromRequest(r)
+	if !ok {
+		writeBadRequest(w, "VALIDATION_ERROR", "invalid moderation item id")
+		return
+	}
+
+	item, err := h.service.GetQueueItem(r.Context(), itemID)
+	if err != nil {
+		if errors.Is(err, pgrepo.ErrModerationItemNotFound) {
+			httperrors.Write(w, http.StatusNotFound, httperrors.APIError{
+				Code:    "NOT_FOUND",
+				Message: "moderation item not found",
+			})
+			return
+		}
+		writeInternal(w, "INTERNAL_ERROR", "failed to load moderation item")
+		return
+	}
+
+	httperrors.Write(w, http.StatusOK, toAdminBotModQueueAcquireResponse(item))
+}
+
+func (h *AdminModerationHandler) QueueStats(w http.ResponseWriter, r *http.Request) {
+	moderator, ok := adminWebModerator(r)
+	if !ok {
+		writeUnauthorized(w, "UNAUTHORIZED", "authentication required")
+		return
+	}
+	if h.service == nil {
+		writeInternal(w, "MODERATION_SERVICE_UNAVAILABLE", "moderation service is unavailable")
+		return
+	}
+
+	stats, err := h.service.GetQueueStats(r.Context(), moderator.TGID)
+	if err != nil {
+		writeInternal(w, "INTERNAL_ERROR", "failed to load moderation queue stats")
+		return
+	}
+
+	httperrors.Write(w, http.StatusOK, dto.AdminModerationQueueStatsResponse{
+		PendingCount:    stats.PendingCount,
+		LockedCount:     stats.LockedCount,
+		HeldByMeCount:   stats.HeldByActorCount,
+		OldestPendingAt: stats.OldestPendingAt,
+		ETABucket:       stats.ETABucket,
+	})
+}
+
+func (h *AdminModerationHandler) Approve(w http.ResponseWriter, r *http.Request) {
+	h.approve(w, r, false)
+}
+
+func (h *AdminModerationHandler) ApproveVerified(w http.ResponseWriter, r *http.Request) {
+	h.approve(w, r, true)
+}
+
+func (h *AdminModerationHandler) approve(w http.ResponseWriter, r *http.Request, verified bool) {
+	moderator, ok := adminWebModerator(r)
+	if !ok {
+		writeUnauthorized(w, "UNAUTHORIZED", "authentication required")
+		return
+	}
+	if h.service == nil {
+		writeInternal(w, "MODERATION_SERVICE_UNAVAILABLE", "moderation service is unavailable")
+		return
+	}
+
+	itemID, ok := moderationItemIDFromRequest(r)
+	if !ok {
+		writeBadRequest(w, "VALIDATION_ERROR", "invalid moderation item id")
+		return
+	}
+
+	if err := h.service.ApproveBy(r.Context(), itemID, moderator, verified); err != nil {
+		if errors.Is(err, modsvc.ErrCircleMissing) {
+			httperrors.Write(w, http.StatusConflict, httperrors.APIError{
+				Code:    "CIRCLE_MISSING",
+				Message: "user has no circle to verify",
+			})
+			return
+		}
+		writeModerationDecisionError(w, err, "failed to approve moderation item")
+		return
+	}
+
+	h.appendAudit(r, "MODERATION_APPROVE", moderator, itemID, map[string]any{
+		"verified": verified,
+	})
+	httperrors.Write(w, http.StatusOK, dto.LogoutResponse{OK: true})
+}
+
+func (h *AdminModerationHandler) Reject(w http.ResponseWriter, r *http.Request) {
+	moderator, ok := adminWebModerator(r)
+	if !ok {
+		writeUnauthorized(w, "UNAUTHORIZED", "authentication required")
+		return
+	}
+	if h.service == nil {
+		writeInternal(w, "MODERATION_SERVICE_UNAVAILABLE", "moderation service is unavailable")
+		return
+	}
+
+	itemID, ok := moderationItemIDFromRequest(r)
+	if !ok {
+		writeBadRequest(w, "VALIDATION_ERROR", "invalid moderation item id")
+		return
+	}
+
+	var req dto.AdminBotModerationRejectRequest
+	if err := decodeJSON(r, &req); err != nil {
+		writeBadRequest(w, "VALIDATION_ERROR", "invalid request body")
+		return
+	}
+
+	if err := h.service.RejectBy(r.Context(), itemID, moderator, req.ReasonCode, req.ReasonText, req.RequiredFixStep); err != nil {
+		switch {
+		case errors.Is(err, modsvc.ErrInvalidReasonCode):
+			writeBadRequest(w, "VALIDATION_ERROR", "invalid reason_code")
+		case errors.Is(err, modsvc.ErrInvalidRejectPayload):
+			writeBadRequest(w, "VALIDATION_ERROR", "invalid reject payload")
+		default:
+			writeModerationDecisionError(w, err, "failed to reject moderation item")
+		}
+		return
+	}
+
+	h.appendAudit(r, "MODERATION_REJECT", moderator, itemID, map[string]any{
+		"reason_code":       strings.ToUpper(strings.TrimSpace(req.ReasonCode)),
+		"required_fix_step": strings.TrimSpace(req.RequiredFixStep),
+	})
+	httperrors.Write(w, http.StatusOK, dto.LogoutResponse{OK: true})
+}
+
+func (h *AdminModerationHandler) appendAudit(
+	r *http.Request,
+	action string,
+	moderator modsvc.Moderator,
+	itemID int64,
+	extra map[string]any,
+) {
+	if h.audit == nil {
+		return
+	}
+
+	props := map[string]any{
+		"source":             "web",
+		"actor_user_id":      moderator.AdminUserID,
+		"moderation_item_id": itemID,
+	}
+	for key, value := range extra {
+		props[key] = value
+	}
+	payload, _ := json.Marshal(props)
+	_, _ = h.audit.Append(r.Context(), moderator.TGID, action, payload)
+}
+
+func writeModerationDecisionError(w http.ResponseWriter, err error, fallback string) {
+	switch {
+	case errors.Is(err, pgrepo.ErrModerationItemNotFound):
+		httperrors.Write(w, http.StatusNotFound, httperrors.APIError{
+			Code:    "NOT_FOUND",
+			Message: "moderation item not found",
+		})
+	case errors.Is(err, pgrepo.ErrModerationItemNotPending):
+		httperrors.Write(w, http.StatusConflict, httperrors.APIError{
+			Code:    "ITEM_NOT_PENDING",
+			Message: "moderation item is already decided",
+		})
+	case errors.Is(err, pgrepo.ErrModerationItemLocked):
+		httperrors.Write(w, http.StatusConflict, httperrors.APIError{
+			Code:    "ITEM_LOCKED",
+			Message: "moderation item is being reviewed by another moderator",
+		})
+	default:
+		writeInternal(w, "INTERNAL_ERROR", fallback)
+	}
+}
+
+// adminWebModerator requires both the admin user id and the Telegram ID set by the admin web auth middleware.
+func adminWebModerator(r *http.Request) (modsvc.Moderator, bool) {
+	identity, ok := authsvc.IdentityFromContext(r.Context())
+	if !ok || identity.UserID <= 0 {
+		return modsvc.Moderator{}, false
+	}
+	actorTGID, ok := authsvc.ActorTGIDFromContext(r.Context())
+	if !ok || actorTGID == 0 {
+		return modsvc.Moderator{}, false
+	}
+	return modsvc.Moderator{TGID: actorTGID, AdminUserID: identity.UserID, Role: strings.TrimSpace(identity.Role)}, true
+}
@@ -0,0 +1,60 @@
+package handlers
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	authsvc "github.com/ivankudzin/tgapp/backend/internal/services/auth"
+	modsvc "github.com/ivankudzin/tgapp/backend/internal/services/moderation"
+)
+
+func TestAdminModerationQueueAcquireRequiresTelegramID(t *testing.T) {
+	handler := NewAdminModerationHandler(modsvc.NewService(nil, nil, nil, nil), nil)
+
+	req := httptest.NewRequest(http.MethodPost, "/admin/moderation/queue/acquire", nil)
+	req = req.WithContext(authsvc.WithIdentity(req.Context(), authsvc.Identity{UserID: 5, Role: "MODERATOR"}))
+	rr := httptest.NewRecorder()
+
+	handler.QueueAcquire(rr, req)
+
+	if rr.Code != http.StatusUnauthorized {
+		t.Fatalf("unexpected status: got=%d want=%d", rr.Code, http.StatusUnauthorized)
+	}
+}
+
+func TestAdminModerationRejectValidatesItemID(t *testing.T) {
+	handler := NewAdminModerationHandler(modsvc.NewService(nil, nil, nil, nil), nil)
+
+	req := httptest.NewRequest(http.MethodPost, "/admin/moderation/items/abc/reject", strings.NewReader(`{}`))
+	ctx := authsvc.WithIdentity(req.Context(), authsvc.Identity{UserID: 5, Role: "MODERATOR"})
+	ctx = authsvc.WithActorTGID(ctx, 777)
+	ctx = withURLParam(ctx, "id", "abc")
+	req = req.WithContext(ctx)
+	rr := httptest.NewRecorder()
+
+	handler.Reject(rr, req)
+
+	if rr.Code != http.StatusBadRequest {
+		t.Fatalf("unexpected status: got=%d want=%d", rr.Code, http.StatusBadRequest)
+	}
+}
+
+func TestAdminModerationRejectValidatesReasonCode(t *testing.T) {
+	handler := NewAdminModerationHandler(modsvc.NewService(nil, nil, nil, nil), nil)
+
+	body := strings.NewReader(`{"reason_code":"BAD_REASON","reason_text":"text","required_fix_step":"step"}`)
+	req := httptest.NewRequest(http.MethodPost, "/admin/moderation/items/1/reject", body)
+	ctx := authsvc.WithIdentity(req.Context(), authsvc.Identity{UserID: 5, Role: "MODERATOR"})
+	ctx = authsvc.WithActorTGID(ctx, 777)
+	ctx = withURLParam(ctx, "id", "1")
+	req = req.WithContext(ctx)
+	rr := httptest.NewRecorder()
+
+	handler.Reject(rr, req)
+
+	if rr.Code != http.StatusBadRequest {
+		t.Fatalf("unexpected status: got=%d want=%d", rr.Code, http.StatusBadRequest)
+	}
+}
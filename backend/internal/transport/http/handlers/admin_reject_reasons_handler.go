@@ -0,0 +1,165 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/go-chi/chi/v5"
+
+	auditsvc "github.com/ivankudzin/tgapp/backend/internal/services/audit"
+	authsvc "github.com/ivankudzin/tgapp/backend/internal/services/auth"
+	modsvc "github.com/ivankudzin/tgapp/backend/internal/services/moderation"
+	"github.com/ivankudzin/tgapp/backend/internal/transport/http/dto"
+	httperrors "github.com/ivankudzin/tgapp/backend/internal/transport/http/errors"
+)
+
+type AdminRejectReasonsHandler struct {
+	service *modsvc.Service
+	audit   *auditsvc.Service
+}
+
+func NewAdminRejectReasonsHandler(service *modsvc.Service, audit *auditsvc.Service) *AdminRejectReasonsHandler {
+	return &AdminRejectReasonsHandler{service: service, audit: audit}
+}
+
+func (h *AdminRejectReasonsHandler) List(w http.ResponseWriter, r *http.Request) {
+	if _, ok := authsvc.IdentityFromContext(r.Context()); !ok {
+		writeUnauthorized(w, "UNAUTHORIZED", "authentication required")
+		return
+	}
+	if h.service == nil {
+		writeInternal(w, "MODERATION_SERVICE_UNAVAILABLE", "moderation service is unavailable")
+		return
+	}
+
+	writeRejectReasons(w, r, h.service)
+}
+
+func (h *AdminRejectReasonsHandler) Upsert(w http.ResponseWriter, r *http.Request) {
+	identity, ok := authsvc.IdentityFromContext(r.Context())
+	if !ok {
+		writeUnauthorized(w, "UNAUTHORIZED", "authentication required")
+		return
+	}
+	if h.service == nil {
+		writeInternal(w, "MODERATION_SERVICE_UNAVAILABLE", "moderation service is unavailable")
+		return
+	}
+
+	item, _, ok := decodeRejectReasonUpsert(w, r)
+	if !ok {
+		return
+	}
+	saved, ok := upsertRejectReason(w, r, h.service, 0, item)
+	if !ok {
+		return
+	}
+
+	if h.audit != nil {
+		payload, _ := json.Marshal(map[string]any{
+			"source":        "web",
+			"actor_user_id": identity.UserID,
+			"reason_code":   saved.ReasonCode,
+			"is_active":     saved.IsActive,
+			"severity":      saved.Severity,
+		})
+		_, _ = h.audit.Append(r.Context(), 0, "REJECT_REASON_UPDATE", payload)
+	}
+
+	httperrors.Write(w, http.StatusOK, toRejectReasonItemDTO(saved))
+}
+
+func writeRejectReasons(w http.ResponseWriter, r *http.Request, service *modsvc.Service) {
+	includeInactive, _ := strconv.ParseBool(strings.TrimSpace(r.URL.Query().Get("include_inactive")))
+	serviceItems, err := service.ListRejectReasons(r.Context(), includeInactive)
+	if err != nil {
+		writeInternal(w, "INTERNAL_ERROR", "failed to load reject reasons")
+		return
+	}
+
+	resp := dto.AdminBotModerationRejectReasonsResponse{
+		Items: make([]dto.AdminBotModerationRejectReasonItem, 0, len(serviceItems)),
+	}
+	for _, item := range serviceItems {
+		resp.Items = append(resp.Items, toRejectReasonItemDTO(item))
+	}
+
+	httperrors.Write(w, http.StatusOK, resp)
+}
+
+// decodeRejectReasonUpsert reads the {code} URL param and body; is_active defaults to true for new edits.
+func decodeRejectReasonUpsert(w http.ResponseWriter, r *http.Request) (modsvc.RejectReasonItem, int64, bool) {
+	code := strings.TrimSpace(chi.URLParam(r, "code"))
+	if code == "" {
+		writeBadRequest(w, "VALIDATION_ERROR", "reason code is required")
+		return modsvc.RejectReasonItem{}, 0, false
+	}
+
+	var req dto.AdminRejectReasonUpsertRequest
+	if err := decodeJSON(r, &req); err != nil {
+		writeBadRequest(w, "VALIDATION_ERROR", "invalid request body")
+		return modsvc.RejectReasonItem{}, 0, false
+	}
+
+	isActive := true
+	if req.IsActive != nil {
+		isActive = *req.IsActive
+	}
+	return modsvc.RejectReasonItem{
+		ReasonCode:      code,
+		Label:           req.Label,
+		Labels:          req.Labels,
+		ReasonText:      req.ReasonText,
+		RequiredFixStep: req.RequiredFixStep,
+		Severity:        req.Severity,
+		IsActive:        isActive,
+		SortOrder:       req.SortOrder,
+	}, req.ActorTGID, true
+}
+
+func upsertRejectReason(
+	w http.ResponseWriter,
+	r *http.Request,
+	service *modsvc.Service,
+	actorTGID int64,
+	item modsvc.RejectReasonItem,
+) (modsvc.RejectReasonItem, bool) {
+	saved, err := service.UpsertRejectReason(r.Context(), actorTGID, item)
+	if err != nil {
+		switch {
+		case errors.Is(err, modsvc.ErrInvalidRejectReason):
+			writeBadRequest(w, "VALIDATION_ERROR", err.Error())
+		case errors.Is(err, modsvc.ErrRejectReasonsReadOnly):
+			writeInternal(w, "REJECT_REASONS_READ_ONLY", "reject reason catalog is not configured")
+		default:
+			writeInternal(w, "INTERNAL_ERROR", "failed to save reject reason")
+		}
+		return modsvc.RejectReasonItem{}, false
+	}
+	return saved, true
+}
+
+func toRejectReasonItemDTO(item modsvc.RejectReasonItem) dto.AdminBotModerationRejectReasonItem {
+	out := dto.AdminBotModerationRejectReasonItem{
+		ReasonCode:      item.ReasonCode,
+		Label:           item.Label,
+		Labels:          item.Labels,
+		ReasonText:      item.ReasonText,
+		RequiredFixStep: item.RequiredFixStep,
+		Severity:        item.Severity,
+		IsActive:        item.IsActive,
+		SortOrder:       item.SortOrder,
+		UpdatedByTGID:   item.UpdatedByTGID,
+	}
+	if out.Labels == nil {
+		out.Labels = map[string]string{}
+	}
+	if !item.UpdatedAt.IsZero() {
+		updatedAt := item.UpdatedAt.UTC()
+		out.UpdatedAt = &updatedAt
+	}
+	return out
+}
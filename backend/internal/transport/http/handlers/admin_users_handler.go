@@ -0,0 +1,323 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+
+	auditsvc "github.com/ivankudzin/tgapp/backend/internal/services/audit"
+	authsvc "github.com/ivankudzin/tgapp/backend/internal/services/auth"
+	userssvc "github.com/ivankudzin/tgapp/backend/internal/services/users"
+	"github.com/ivankudzin/tgapp/backend/internal/transport/http/dto"
+	httperrors "github.com/ivankudzin/tgapp/backend/internal/transport/http/errors"
+)
+
+// AdminUsersHandler is the web admin counterpart of AdminBotUsersHandler. Actions are
+// attributed to the admin user and written to the audit log with source "web".
+type AdminUsersHandler struct {
+	users *userssvc.Service
+	audit *auditsvc.Service
+}
+
+func NewAdminUsersHandler(users *userssvc.Service, audit *auditsvc.Service) *AdminUsersHandler {
+	return &AdminUsersHandler{users: users, audit: audit}
+}
+
+func (h *AdminUsersHandler) Search(w http.ResponseWriter, r *http.Request) {
+	if _, ok := authsvc.IdentityFromContext(r.Context()); !ok {
+		writeUnauthorized(w, "UNAUTHORIZED", "authentication required")
+		return
+	}
+	if h.users == nil {
+		writeInternal(w, "USERS_SERVICE_UNAVAILABLE", "users service is unavailable")
+		return
+	}
+
+	query, err := parseUserSearchQuery(r)
+	if err != nil {
+		writeBadRequest(w, "VALIDATION_ERROR", err.Error())
+		return
+	}
+
+	result, err := h.users.Search(r.Context(), query)
+	if err != nil {
+		if errors.Is(err, userssvc.ErrValidation) {
+			writeBadRequest(w, "VALIDATION_ERROR", err.Error())
+			return
+		}
+		writeInternal(w, "INTERNAL_ERROR", "failed to search users")
+		return
+	}
+
+	resp := dto.AdminUserSearchResponse{
+		Items:  make([]dto.AdminUserSummary, 0, len(result.Items)),
+		Total:  result.Total,
+		Offset: result.Offset,
+		Limit:  result.Limit,
+	}
+	for _, item := range result.Items {
+		resp.Items = append(resp.Items, dto.AdminUserSummary{
+			UserID:           item.UserID,
+			TGID:             item.TGID,
+			Username:         item.Username,
+			DisplayName:      item.DisplayName,
+			CityID:           item.CityID,
+			Gender:           item.Gender,
+			ModerationStatus: item.ModerationStatus,
+			Approved:         item.Approved,
+			IsBanned:         item.IsBanned,
+			ReportsCount:     item.ReportsCount,
+			CreatedAt:        item.CreatedAt,
+		})
+	}
+	httperrors.Write(w, http.StatusOK, resp)
+}
+
+func (h *AdminUsersHandler) Detail(w http.ResponseWriter, r *http.Request) {
+	identity, ok := authsvc.IdentityFromContext(r.Context())
+	if !ok {
+		writeUnauthorized(w, "UNAUTHORIZED", "authentication required")
+		return
+	}
+	if h.users == nil {
+		writeInternal(w, "USERS_SERVICE_UNAVAILABLE", "users service is unavailable")
+		return
+	}
+
+	userID, ok := adminTargetUserIDFromRequest(r)
+	if !ok {
+		writeBadRequest(w, "VALIDATION_ERROR", "invalid user id")
+		return
+	}
+
+	found, err := h.users.GetUser(r.Context(), userID)
+	if err != nil {
+		switch {
+		case errors.Is(err, userssvc.ErrValidation):
+			writeBadRequest(w, "VALIDATION_ERROR", "invalid user id")
+		case errors.Is(err, userssvc.ErrNotFound):
+			httperrors.Write(w, http.StatusNotFound, httperrors.APIError{
+				Code:    "NOT_FOUND",
+				Message: "user not found",
+			})
+		default:
+			writeInternal(w, "INTERNAL_ERROR", "failed to load user")
+		}
+		return
+	}
+
+	h.appendAudit(r, "VIEW_USER", identity, userID, nil)
+	httperrors.Write(w, http.StatusOK, dto.AdminBotLookupUserResponse{User: toAdminBotLookupUser(found)})
+}
+
+func (h *AdminUsersHandler) Ban(w http.ResponseWriter, r *http.Request) {
+	identity, actorTGID, ok := adminWebActor(r)
+	if !ok {
+		writeUnauthorized(w, "UNAUTHORIZED", "authentication required")
+		return
+	}
+	if h.users == nil {
+		writeInternal(w, "USERS_SERVICE_UNAVAILABLE", "users service is unavailable")
+		return
+	}
+
+	userID, ok := adminTargetUserIDFromRequest(r)
+	if !ok {
+		writeBadRequest(w, "VALIDATION_ERROR", "invalid user id")
+		return
+	}
+
+	var req dto.AdminBotBanRequest
+	if err := decodeJSON(r, &req); err != nil && !errors.Is(err, io.EOF) {
+		writeBadRequest(w, "VALIDATION_ERROR", "invalid request body")
+		return
+	}
+
+	result, err := h.users.Ban(r.Context(), userssvc.BanInput{
+		UserID:     userID,
+		ReasonCode: req.ReasonCode,
+		Reason:     req.Reason,
+		Duration:   req.Duration,
+		ActorTGID:  actorTGID,
+	})
+	if err != nil {
+		switch {
+		case errors.Is(err, userssvc.ErrValidation):
+			writeBadRequest(w, "VALIDATION_ERROR", err.Error())
+		case errors.Is(err, userssvc.ErrNotFound):
+			httperrors.Write(w, http.StatusNotFound, httperrors.APIError{
+				Code:    "NOT_FOUND",
+				Message: "user not found",
+			})
+		default:
+			writeInternal(w, "INTERNAL_ERROR", "failed to ban user")
+		}
+		return
+	}
+
+	h.appendAudit(r, "BAN_USER", identity, userID, map[string]any{
+		"reason":           result.Reason,
+		"reason_code":      result.ReasonCode,
+		"duration":         strings.TrimSpace(req.Duration),
+		"expires_at":       result.ExpiresAt,
+		"unmatched_count":  result.UnmatchedCount,
+		"sessions_revoked": result.SessionsRevoked,
+	})
+	httperrors.Write(w, http.StatusOK, dto.AdminBotBanResponse{
+		OK:               true,
+		AdminBotBanState: toBanStateDTO(result.BanState),
+		UnmatchedCount:   result.UnmatchedCount,
+		SessionsRevoked:  result.SessionsRevoked,
+	})
+}
+
+func (h *AdminUsersHandler) Unban(w http.ResponseWriter, r *http.Request) {
+	identity, actorTGID, ok := adminWebActor(r)
+	if !ok {
+		writeUnauthorized(w, "UNAUTHORIZED", "authentication required")
+		return
+	}
+	if h.users == nil {
+		writeInternal(w, "USERS_SERVICE_UNAVAILABLE", "users service is unavailable")
+		return
+	}
+
+	userID, ok := adminTargetUserIDFromRequest(r)
+	if !ok {
+		writeBadRequest(w, "VALIDATION_ERROR", "invalid user id")
+		return
+	}
+
+	if err := h.users.Unban(r.Context(), userID, actorTGID); err != nil {
+		switch {
+		case errors.Is(err, userssvc.ErrValidation):
+			writeBadRequest(w, "VALIDATION_ERROR", "invalid unban request")
+		case errors.Is(err, userssvc.ErrNotFound):
+			httperrors.Write(w, http.StatusNotFound, httperrors.APIError{
+				Code:    "NOT_FOUND",
+				Message: "user not found",
+			})
+		default:
+			writeInternal(w, "INTERNAL_ERROR", "failed to unban user")
+		}
+		return
+	}
+
+	h.appendAudit(r, "UNBAN_USER", identity, userID, nil)
+	httperrors.Write(w, http.StatusOK, dto.LogoutResponse{OK: true})
+}
+
+func (h *AdminUsersHandler) ForceReview(w http.ResponseWriter, r *http.Request) {
+	identity, ok := authsvc.IdentityFromContext(r.Context())
+	if !ok {
+		writeUnauthorized(w, "UNAUTHORIZED", "authentication required")
+		return
+	}
+	if h.users == nil {
+		writeInternal(w, "USERS_SERVICE_UNAVAILABLE", "users service is unavailable")
+		return
+	}
+
+	userID, ok := adminTargetUserIDFromRequest(r)
+	if !ok {
+		writeBadRequest(w, "VALIDATION_ERROR", "invalid user id")
+		return
+	}
+
+	if err := h.users.ForceReview(r.Context(), userID); err != nil {
+		switch {
+		case errors.Is(err, userssvc.ErrValidation):
+			writeBadRequest(w, "VALIDATION_ERROR", "invalid force-review request")
+		case errors.Is(err, userssvc.ErrNotFound):
+			httperrors.Write(w, http.StatusNotFound, httperrors.APIError{
+				Code:    "NOT_FOUND",
+				Message: "user not found",
+			})
+		default:
+			writeInternal(w, "INTERNAL_ERROR", "failed to force review")
+		}
+		return
+	}
+
+	h.appendAudit(r, "FORCE_REVIEW", identity, userID, nil)
+	httperrors.Write(w, http.StatusOK, dto.LogoutResponse{OK: true})
+}
+
+func (h *AdminUsersHandler) appendAudit(
+	r *http.Request,
+	action string,
+	identity authsvc.Identity,
+	targetUserID int64,
+	extra map[string]any,
+) {
+	if h.audit == nil {
+		return
+	}
+
+	props := map[string]any{
+		"source":         "web",
+		"actor_user_id":  identity.UserID,
+		"actor_role":     strings.TrimSpace(identity.Role),
+		"target_user_id": targetUserID,
+	}
+	for key, value := range extra {
+		props[key] = value
+	}
+	payload, _ := json.Marshal(props)
+	actorTGID, _ := authsvc.ActorTGIDFromContext(r.Context())
+	_, _ = h.audit.Append(r.Context(), actorTGID, action, payload)
+}
+
+// adminWebActor returns the web identity and the Telegram ID that bans are recorded under.
+func adminWebActor(r *http.Request) (authsvc.Identity, int64, bool) {
+	identity, ok := authsvc.IdentityFromContext(r.Context())
+	if !ok {
+		return authsvc.Identity{}, 0, false
+	}
+	actorTGID, ok := authsvc.ActorTGIDFromContext(r.Context())
+	if !ok || actorTGID == 0 {
+		return authsvc.Identity{}, 0, false
+	}
+	return identity, actorTGID, true
+}
+
+// parseUserSearchQuery reads q, city_id, status, banned, created_from/created_to (RFC3339 or
+// YYYY-MM-DD, a bare created_to day is inclusive), min_reports, limit and offset.
+func parseUserSearchQuery(r *http.Request) (userssvc.SearchQuery, error) {
+	query := r.URL.Query()
+	out := userssvc.SearchQuery{
+		Query:  query.Get("q"),
+		CityID: query.Get("city_id"),
+		Status: query.Get("status"),
+	}
+
+	if raw := strings.TrimSpace(query.Get("banned")); raw != "" {
+		banned, err := strconv.ParseBool(raw)
+		if err != nil {
+			return userssvc.SearchQuery{}, fmt.Errorf("banned must be a boolean")
+		}
+		out.Banned = &banned
+	}
+
+	var err error
+	if out.CreatedFrom, err = queryTimeBound(query, "created_from", false); err != nil {
+		return userssvc.SearchQuery{}, err
+	}
+	if out.CreatedTo, err = queryTimeBound(query, "created_to", true); err != nil {
+		return userssvc.SearchQuery{}, err
+	}
+	if out.MinReports, err = queryNonNegativeInt(query, "min_reports"); err != nil {
+		return userssvc.SearchQuery{}, err
+	}
+	if out.Limit, err = queryNonNegativeInt(query, "limit"); err != nil {
+		return userssvc.SearchQuery{}, err
+	}
+	if out.Offset, err = queryNonNegativeInt(query, "offset"); err != nil {
+		return userssvc.SearchQuery{}, err
+	}
+	return out, nil
+}
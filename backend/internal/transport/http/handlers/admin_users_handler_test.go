@@ -0,0 +1,46 @@
+package handlers
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	authsvc "github.com/ivankudzin/tgapp/backend/internal/services/auth"
+	userssvc "github.com/ivankudzin/tgapp/backend/internal/services/users"
+)
+
+func TestParseUserSearchQuery(t *testing.T) {
+	req := httptest.NewRequest(http.MethodGet, "/admin/users?q=@alice&city_id=minsk&banned=true&created_from=2026-03-01&created_to=2026-03-31&min_reports=2&limit=50", nil)
+
+	query, err := parseUserSearchQuery(req)
+	if err != nil {
+		t.Fatalf("parse: %v", err)
+	}
+	if query.Query != "@alice" || query.CityID != "minsk" || query.Banned == nil || !*query.Banned || query.MinReports != 2 || query.Limit != 50 {
+		t.Fatalf("unexpected query: %+v", query)
+	}
+	if query.CreatedTo == nil || query.CreatedTo.Format("2006-01-02") != "2026-04-01" {
+		t.Fatalf("expected inclusive created_to day, got %v", query.CreatedTo)
+	}
+
+	req = httptest.NewRequest(http.MethodGet, "/admin/users?banned=maybe", nil)
+	if _, err := parseUserSearchQuery(req); err == nil {
+		t.Fatalf("expected error for invalid banned filter")
+	}
+}
+
+func TestAdminUsersBanRequiresTelegramID(t *testing.T) {
+	handler := NewAdminUsersHandler(userssvc.NewService(nil, nil, nil), nil)
+
+	req := httptest.NewRequest(http.MethodPost, "/admin/users/42/ban", nil)
+	ctx := authsvc.WithIdentity(req.Context(), authsvc.Identity{UserID: 100, Role: "ADMIN"})
+	ctx = withURLParam(ctx, "id", "42")
+	req = req.WithContext(ctx)
+
+	rr := httptest.NewRecorder()
+	handler.Ban(rr, req)
+
+	if rr.Code != http.StatusUnauthorized {
+		t.Fatalf("unexpected status: got %d want %d", rr.Code, http.StatusUnauthorized)
+	}
+}
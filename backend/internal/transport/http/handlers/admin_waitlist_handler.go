@@ -0,0 +1,278 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+
+	"github.com/go-chi/chi/v5"
+
+	pgrepo "github.com/ivankudzin/tgapp/backend/internal/repo/postgres"
+	auditsvc "github.com/ivankudzin/tgapp/backend/internal/services/audit"
+	authsvc "github.com/ivankudzin/tgapp/backend/internal/services/auth"
+	waitlistsvc "github.com/ivankudzin/tgapp/backend/internal/services/waitlist"
+	"github.com/ivankudzin/tgapp/backend/internal/transport/http/dto"
+	httperrors "github.com/ivankudzin/tgapp/backend/internal/transport/http/errors"
+)
+
+const adminWaitlistTopBuckets = 10
+
+type AdminWaitlistHandler struct {
+	waitlist *waitlistsvc.Service
+	audit    *auditsvc.Service
+}
+
+func NewAdminWaitlistHandler(waitlist *waitlistsvc.Service, audit *auditsvc.Service) *AdminWaitlistHandler {
+	return &AdminWaitlistHandler{waitlist: waitlist, audit: audit}
+}
+
+func (h *AdminWaitlistHandler) Stats(w http.ResponseWriter, r *http.Request) {
+	if _, ok := authsvc.IdentityFromContext(r.Context()); !ok {
+		writeUnauthorized(w, "UNAUTHORIZED", "authentication required")
+		return
+	}
+	h.writeStats(w, r)
+}
+
+func (h *AdminWaitlistHandler) Admit(w http.ResponseWriter, r *http.Request) {
+	identity, ok := authsvc.IdentityFromContext(r.Context())
+	if !ok {
+		writeUnauthorized(w, "UNAUTHORIZED", "authentication required")
+		return
+	}
+	actorTGID, _ := authsvc.ActorTGIDFromContext(r.Context())
+	h.admit(w, r, actorTGID, map[string]any{
+		"source":        "web",
+		"actor_user_id": identity.UserID,
+	})
+}
+
+func (h *AdminWaitlistHandler) ListInvites(w http.ResponseWriter, r *http.Request) {
+	if _, ok := authsvc.IdentityFromContext(r.Context()); !ok {
+		writeUnauthorized(w, "UNAUTHORIZED", "authentication required")
+		return
+	}
+	if h.waitlist == nil {
+		writeInternal(w, "WAITLIST_UNAVAILABLE", "waitlist service is unavailable")
+		return
+	}
+
+	query := r.URL.Query()
+	limit, err := queryNonNegativeInt(query, "limit")
+	if err != nil {
+		writeBadRequest(w, "VALIDATION_ERROR", err.Error())
+		return
+	}
+	offset, err := queryNonNegativeInt(query, "offset")
+	if err != nil {
+		writeBadRequest(w, "VALIDATION_ERROR", err.Error())
+		return
+	}
+
+	items, err := h.waitlist.ListInvites(r.Context(), limit, offset)
+	if err != nil {
+		writeWaitlistError(w, err, "failed to list invite codes")
+		return
+	}
+
+	resp := dto.AdminInviteCodeListResponse{
+		Items:  make([]dto.AdminInviteCodeItem, 0, len(items)),
+		Limit:  limit,
+		Offset: offset,
+	}
+	for _, item := range items {
+		resp.Items = append(resp.Items, toAdminInviteCodeItem(item))
+	}
+	httperrors.Write(w, http.StatusOK, resp)
+}
+
+func (h *AdminWaitlistHandler) CreateInvites(w http.ResponseWriter, r *http.Request) {
+	identity, ok := authsvc.IdentityFromContext(r.Context())
+	if !ok {
+		writeUnauthorized(w, "UNAUTHORIZED", "authentication required")
+		return
+	}
+	if h.waitlist == nil {
+		writeInternal(w, "WAITLIST_UNAVAILABLE", "waitlist service is unavailable")
+		return
+	}
+
+	var req dto.AdminInviteCodesCreateRequest
+	if err := decodeJSON(r, &req); err != nil {
+		writeBadRequest(w, "VALIDATION_ERROR", "invalid json body")
+		return
+	}
+
+	actorTGID, _ := authsvc.ActorTGIDFromContext(r.Context())
+	items, err := h.waitlist.CreateInvites(r.Context(), waitlistsvc.InviteInput{
+		Count:     req.Count,
+		MaxUses:   req.MaxUses,
+		ExpiresAt: req.ExpiresAt,
+		Comment:   req.Comment,
+		ActorTGID: actorTGID,
+	})
+	if err != nil {
+		writeWaitlistError(w, err, "failed to create invite codes")
+		return
+	}
+
+	resp := dto.AdminInviteCodeListResponse{Items: make([]dto.AdminInviteCodeItem, 0, len(items)), Limit: len(items)}
+	codes := make([]string, 0, len(items))
+	for _, item := range items {
+		resp.Items = append(resp.Items, toAdminInviteCodeItem(item))
+		codes = append(codes, item.Code)
+	}
+	h.appendAudit(r, actorTGID, "INVITE_CODES_CREATE", map[string]any{
+		"source":        "web",
+		"actor_user_id": identity.UserID,
+		"codes":         codes,
+		"max_uses":      req.MaxUses,
+		"expires_at":    req.ExpiresAt,
+		"comment":       strings.TrimSpace(req.Comment),
+	})
+	httperrors.Write(w, http.StatusCreated, resp)
+}
+
+func (h *AdminWaitlistHandler) DisableInvite(w http.ResponseWriter, r *http.Request) {
+	identity, ok := authsvc.IdentityFromContext(r.Context())
+	if !ok {
+		writeUnauthorized(w, "UNAUTHORIZED", "authentication required")
+		return
+	}
+	if h.waitlist == nil {
+		writeInternal(w, "WAITLIST_UNAVAILABLE", "waitlist service is unavailable")
+		return
+	}
+
+	item, err := h.waitlist.DisableInvite(r.Context(), chi.URLParam(r, "code"))
+	if err != nil {
+		writeWaitlistError(w, err, "failed to disable invite code")
+		return
+	}
+
+	actorTGID, _ := authsvc.ActorTGIDFromContext(r.Context())
+	h.appendAudit(r, actorTGID, "INVITE_CODE_DISABLE", map[string]any{
+		"source":        "web",
+		"actor_user_id": identity.UserID,
+		"code":          item.Code,
+	})
+	httperrors.Write(w, http.StatusOK, toAdminInviteCodeItem(item))
+}
+
+func (h *AdminWaitlistHandler) BotStats(w http.ResponseWriter, r *http.Request) {
+	if _, ok := adminBotActorTGID(r); !ok {
+		writeUnauthorized(w, "UNAUTHORIZED", "admin bot authentication required")
+		return
+	}
+	h.writeStats(w, r)
+}
+
+func (h *AdminWaitlistHandler) BotAdmit(w http.ResponseWriter, r *http.Request) {
+	actorTGID, ok := adminBotActorTGID(r)
+	if !ok {
+		writeUnauthorized(w, "UNAUTHORIZED", "admin bot authentication required")
+		return
+	}
+	h.admit(w, r, actorTGID, map[string]any{"source": "bot"})
+}
+
+func (h *AdminWaitlistHandler) writeStats(w http.ResponseWriter, r *http.Request) {
+	if h.waitlist == nil {
+		writeInternal(w, "WAITLIST_UNAVAILABLE", "waitlist service is unavailable")
+		return
+	}
+
+	stats, err := h.waitlist.Stats(r.Context(), adminWaitlistTopBuckets)
+	if err != nil {
+		writeWaitlistError(w, err, "failed to load waitlist stats")
+		return
+	}
+	httperrors.Write(w, http.StatusOK, dto.AdminWaitlistStatsResponse{
+		Waiting:       stats.Waiting,
+		AdmittedToday: stats.AdmittedToday,
+		PendingNotify: stats.PendingNotify,
+		ByCity:        toAdminWaitlistBuckets(stats.ByCity),
+		ByGender:      toAdminWaitlistBuckets(stats.ByGender),
+	})
+}
+
+func (h *AdminWaitlistHandler) admit(w http.ResponseWriter, r *http.Request, actorTGID int64, props map[string]any) {
+	if h.waitlist == nil {
+		writeInternal(w, "WAITLIST_UNAVAILABLE", "waitlist service is unavailable")
+		return
+	}
+
+	var req dto.AdminWaitlistAdmitRequest
+	if err := decodeJSON(r, &req); err != nil {
+		writeBadRequest(w, "VALIDATION_ERROR", "invalid json body")
+		return
+	}
+
+	result, err := h.waitlist.Admit(r.Context(), waitlistsvc.AdmitInput{
+		Limit:         req.Limit,
+		CityID:        req.CityID,
+		Gender:        req.Gender,
+		Balance:       req.Balance,
+		IgnoreBalance: req.IgnoreBalance,
+		ActorTGID:     actorTGID,
+	})
+	if err != nil {
+		writeWaitlistError(w, err, "failed to admit waitlisted users")
+		return
+	}
+
+	resp := dto.AdminWaitlistAdmitResponse{
+		Admitted: len(result.Admitted),
+		Waiting:  result.Waiting,
+		Items:    make([]dto.AdminWaitlistAdmittedItem, 0, len(result.Admitted)),
+	}
+	userIDs := make([]int64, 0, len(result.Admitted))
+	for _, item := range result.Admitted {
+		resp.Items = append(resp.Items, dto.AdminWaitlistAdmittedItem{
+			UserID:   item.UserID,
+			Position: item.Position,
+			CityID:   item.CityID,
+			Gender:   item.Gender,
+		})
+		userIDs = append(userIDs, item.UserID)
+	}
+
+	props["limit"] = req.Limit
+	props["city_id"] = strings.TrimSpace(req.CityID)
+	props["gender"] = strings.TrimSpace(req.Gender)
+	props["balance"] = req.Balance
+	props["ignore_balance"] = req.IgnoreBalance
+	props["admitted"] = len(result.Admitted)
+	props["user_ids"] = userIDs
+	h.appendAudit(r, actorTGID, "WAITLIST_ADMIT", props)
+	httperrors.Write(w, http.StatusOK, resp)
+}
+
+func (h *AdminWaitlistHandler) appendAudit(r *http.Request, actorTGID int64, action string, props map[string]any) {
+	if h.audit == nil {
+		return
+	}
+	payload, _ := json.Marshal(props)
+	_, _ = h.audit.Append(r.Context(), actorTGID, action, payload)
+}
+
+func toAdminWaitlistBuckets(items []pgrepo.WaitlistBucketRecord) []dto.AdminWaitlistBucket {
+	out := make([]dto.AdminWaitlistBucket, 0, len(items))
+	for _, item := range items {
+		out = append(out, dto.AdminWaitlistBucket{Key: item.Key, Count: item.Count})
+	}
+	return out
+}
+
+func toAdminInviteCodeItem(item pgrepo.InviteCodeRecord) dto.AdminInviteCodeItem {
+	return dto.AdminInviteCodeItem{
+		Code:          item.Code,
+		MaxUses:       item.MaxUses,
+		UsedCount:     item.UsedCount,
+		ExpiresAt:     item.ExpiresAt,
+		Comment:       item.Comment,
+		CreatedByTGID: item.CreatedByTGID,
+		CreatedAt:     optionalTime(item.CreatedAt),
+		DisabledAt:    item.DisabledAt,
+	}
+}
@@ -0,0 +1,108 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	pgrepo "github.com/ivankudzin/tgapp/backend/internal/repo/postgres"
+	authsvc "github.com/ivankudzin/tgapp/backend/internal/services/auth"
+	waitlistsvc "github.com/ivankudzin/tgapp/backend/internal/services/waitlist"
+	"github.com/ivankudzin/tgapp/backend/internal/transport/http/dto"
+)
+
+// adminWaitlistStoreStub implements only what these tests reach; other methods panic through
+// the nil embedded interface.
+type adminWaitlistStoreStub struct {
+	waitlistsvc.Store
+
+	admitIn pgrepo.WaitlistAdmitInput
+	entry   pgrepo.WaitlistEntryRecord
+}
+
+func (s *adminWaitlistStoreStub) AdmitWaitlist(_ context.Context, in pgrepo.WaitlistAdmitInput) ([]pgrepo.WaitlistAdmittedRecord, error) {
+	s.admitIn = in
+	return []pgrepo.WaitlistAdmittedRecord{
+		{UserID: 7, Position: 1, CityID: "minsk", Gender: "female"},
+		{UserID: 9, Position: 2, CityID: "minsk", Gender: "male"},
+	}, nil
+}
+
+func (s *adminWaitlistStoreStub) WaitlistStats(context.Context, int) (pgrepo.WaitlistStatsRecord, error) {
+	return pgrepo.WaitlistStatsRecord{Waiting: 40}, nil
+}
+
+func (s *adminWaitlistStoreStub) GetWaitlistEntry(context.Context, int64) (pgrepo.WaitlistEntryRecord, error) {
+	return s.entry, nil
+}
+
+func (s *adminWaitlistStoreStub) RedeemInvite(context.Context, int64, string) (pgrepo.WaitlistEntryRecord, error) {
+	return pgrepo.WaitlistEntryRecord{}, pgrepo.ErrInviteCodeUnusable
+}
+
+func TestAdminWaitlistBotAdmitUsesActorAndReportsBatch(t *testing.T) {
+	store := &adminWaitlistStoreStub{}
+	handler := NewAdminWaitlistHandler(waitlistsvc.NewService(store, nil), nil)
+
+	req := httptest.NewRequest(http.MethodPost, "/admin/bot/waitlist/admit", strings.NewReader(`{"limit":2,"city_id":"minsk","balance":true}`))
+	req = req.WithContext(authsvc.WithActorTGID(authsvc.WithActorIsBot(req.Context(), true), 555))
+	rr := httptest.NewRecorder()
+
+	handler.BotAdmit(rr, req)
+
+	if rr.Code != http.StatusOK {
+		t.Fatalf("unexpected status: got=%d body=%s", rr.Code, rr.Body.String())
+	}
+	var resp dto.AdminWaitlistAdmitResponse
+	if err := json.Unmarshal(rr.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("decode response: %v", err)
+	}
+	if resp.Admitted != 2 || resp.Waiting != 40 || len(resp.Items) != 2 {
+		t.Fatalf("unexpected response: %+v", resp)
+	}
+	if store.admitIn.ActorTGID != 555 || !store.admitIn.Balance || store.admitIn.CityID != "minsk" {
+		t.Fatalf("unexpected admit input: %+v", store.admitIn)
+	}
+
+	req = httptest.NewRequest(http.MethodPost, "/admin/bot/waitlist/admit", strings.NewReader(`{"limit":0}`))
+	req = req.WithContext(authsvc.WithActorTGID(authsvc.WithActorIsBot(req.Context(), true), 555))
+	rr = httptest.NewRecorder()
+
+	handler.BotAdmit(rr, req)
+
+	if rr.Code != http.StatusBadRequest {
+		t.Fatalf("expected 400 for empty batch, got=%d body=%s", rr.Code, rr.Body.String())
+	}
+}
+
+func TestWaitlistInviteRejectsUnusableCode(t *testing.T) {
+	store := &adminWaitlistStoreStub{entry: pgrepo.WaitlistEntryRecord{UserID: 3, Position: 12, Status: pgrepo.WaitlistStatusWaiting}}
+	handler := NewWaitlistHandler(waitlistsvc.NewService(store, nil))
+
+	req := httptest.NewRequest(http.MethodPost, "/v1/waitlist/invite", strings.NewReader(`{"invite_code":"used2345"}`))
+	req = req.WithContext(authsvc.WithIdentity(req.Context(), authsvc.Identity{UserID: 3, Role: "USER"}))
+	rr := httptest.NewRecorder()
+
+	handler.Invite(rr, req)
+
+	if rr.Code != http.StatusBadRequest || !strings.Contains(rr.Body.String(), "INVITE_CODE_INVALID") {
+		t.Fatalf("unexpected response: got=%d body=%s", rr.Code, rr.Body.String())
+	}
+
+	req = httptest.NewRequest(http.MethodGet, "/v1/waitlist", nil)
+	req = req.WithContext(authsvc.WithIdentity(req.Context(), authsvc.Identity{UserID: 3, Role: "USER"}))
+	rr = httptest.NewRecorder()
+
+	handler.Status(rr, req)
+
+	var status dto.RegistrationStatusResponse
+	if err := json.Unmarshal(rr.Body.Bytes(), &status); err != nil {
+		t.Fatalf("decode status: %v", err)
+	}
+	if status.Status != waitlistsvc.StatusWaiting || status.Position != 12 {
+		t.Fatalf("unexpected status: %+v", status)
+	}
+}
@@ -7,18 +7,26 @@ import (
 	"time"
 
 	authsvc "github.com/ivankudzin/tgapp/backend/internal/services/auth"
+	waitlistsvc "github.com/ivankudzin/tgapp/backend/internal/services/waitlist"
 	"github.com/ivankudzin/tgapp/backend/internal/transport/http/dto"
 	httperrors "github.com/ivankudzin/tgapp/backend/internal/transport/http/errors"
 )
 
 type AuthHandler struct {
-	service *authsvc.Service
+	service  *authsvc.Service
+	waitlist *waitlistsvc.Service
 }
 
 func NewAuthHandler(service *authsvc.Service) *AuthHandler {
 	return &AuthHandler{service: service}
 }
 
+// AttachWaitlist queues new users on Telegram login while registration is closed and
+// reports their place in the login response.
+func (h *AuthHandler) AttachWaitlist(waitlist *waitlistsvc.Service) {
+	h.waitlist = waitlist
+}
+
 func (h *AuthHandler) Handle(w http.ResponseWriter, r *http.Request) {
 	h.Telegram(w, r)
 }
@@ -46,13 +54,27 @@ func (h *AuthHandler) Telegram(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	// A user who could not be placed on the waitlist would slip past it, so a registration
+	// failure fails the login; the client retries and the next login registers again.
+	var registration *dto.RegistrationStatusResponse
+	if h.waitlist != nil {
+		status, err := h.waitlist.Register(r.Context(), res.Me.ID, req.InviteCode)
+		if err != nil {
+			writeInternal(w, "REGISTRATION_FAILED", "failed to register user")
+			return
+		}
+		out := toRegistrationStatus(status)
+		registration = &out
+	}
+
 	httperrors.Write(w, http.StatusOK, dto.AuthTokensResponse{
 		AccessToken:  res.AccessToken,
 		RefreshToken: res.RefreshToken,
 		ExpiresInSec: maxInt64(0, int64(time.Until(res.AccessExpires).Seconds())),
 		Me: dto.AuthMeResponse{
-			ID:   res.Me.ID,
-			Role: res.Me.Role,
+			ID:           res.Me.ID,
+			Role:         res.Me.Role,
+			Registration: registration,
 		},
 	})
 }
@@ -132,6 +154,8 @@ func handleAuthError(w http.ResponseWriter, err error) {
 		writeBadRequest(w, "INVALID_REQUEST", "request validation failed")
 	case errors.Is(err, authsvc.ErrUnauthorized):
 		writeUnauthorized(w, "UNAUTHORIZED", "authentication failed")
+	case errors.Is(err, authsvc.ErrBanned):
+		httperrors.Write(w, http.StatusForbidden, httperrors.APIError{Code: "USER_BANNED", Message: "user is banned"})
 	default:
 		writeInternal(w, "INTERNAL_ERROR", "internal server error")
 	}
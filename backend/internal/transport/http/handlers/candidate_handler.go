@@ -87,7 +87,8 @@ func (h *CandidateHandler) Profile(w http.ResponseWriter, r *http.Request) {
 		IsTravel:    candidate.IsTravel,
 		TravelCity:  candidate.TravelCity,
 		Badges: dto.CandidateBadgesResponse{
-			IsPlus: candidate.Badges.IsPlus,
+			IsPlus:   candidate.Badges.IsPlus,
+			Verified: candidate.Badges.Verified,
 		},
 	})
 }
@@ -128,7 +129,8 @@ func (h *CandidateHandler) Photos(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	photos, err := h.media.ListPhotos(r.Context(), candidateUserID)
+	// The candidate strip on feed cards only needs the small variant.
+	photos, err := h.media.ListPhotoVariants(r.Context(), candidateUserID, mediasvc.VariantThumb)
 	if err != nil {
 		writeInternal(w, "INTERNAL_ERROR", "failed to load candidate photos")
 		return
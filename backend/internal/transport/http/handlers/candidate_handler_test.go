@@ -50,7 +50,7 @@ type candidateMediaStoreStub struct {
 	photos map[int64][]mediasvc.PhotoRecord
 }
 
-func (s candidateMediaStoreStub) CreatePhoto(context.Context, int64, string) (mediasvc.PhotoRecord, error) {
+func (s candidateMediaStoreStub) CreatePhoto(context.Context, int64, string, mediasvc.ImageHashes) (mediasvc.PhotoRecord, error) {
 	return mediasvc.PhotoRecord{}, fmt.Errorf("not implemented")
 }
 
@@ -3,74 +3,123 @@ package handlers
 import (
 	"fmt"
 	"net/http"
+	"strconv"
+	"strings"
 	"time"
 
 	"github.com/ivankudzin/tgapp/backend/internal/config"
+	remotecfgsvc "github.com/ivankudzin/tgapp/backend/internal/services/remoteconfig"
 	"github.com/ivankudzin/tgapp/backend/internal/transport/http/dto"
 	httperrors "github.com/ivankudzin/tgapp/backend/internal/transport/http/errors"
 )
 
+// RemoteConfigSource is the remote config an instance currently serves.
+type RemoteConfigSource interface {
+	Current() remotecfgsvc.Snapshot
+}
+
+type staticRemoteConfigSource struct {
+	snapshot remotecfgsvc.Snapshot
+}
+
+func (s staticRemoteConfigSource) Current() remotecfgsvc.Snapshot {
+	return s.snapshot
+}
+
+func staticRemoteConfig(remote config.RemoteConfig) RemoteConfigSource {
+	return staticRemoteConfigSource{snapshot: remotecfgsvc.StaticSnapshot(remote)}
+}
+
 type ConfigHandler struct {
-	remote config.RemoteConfig
+	remote RemoteConfigSource
 }
 
 func NewConfigHandler(remote config.RemoteConfig) *ConfigHandler {
-	return &ConfigHandler{remote: remote}
+	return &ConfigHandler{remote: staticRemoteConfig(remote)}
 }
 
-func (h *ConfigHandler) Handle(w http.ResponseWriter, _ *http.Request) {
-	cities := make([]dto.ConfigCityResponse, 0, len(h.remote.Cities))
-	for _, city := range h.remote.Cities {
+func (h *ConfigHandler) AttachRemoteConfig(source RemoteConfigSource) {
+	if source != nil {
+		h.remote = source
+	}
+}
+
+func (h *ConfigHandler) Handle(w http.ResponseWriter, r *http.Request) {
+	snapshot := h.remote.Current()
+	remote := snapshot.Config
+
+	etag := snapshot.ETag()
+	w.Header().Set("ETag", etag)
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("X-Config-Version", strconv.FormatInt(snapshot.Version, 10))
+	if etagMatches(r.Header.Get("If-None-Match"), etag) {
+		w.WriteHeader(http.StatusNotModified)
+		return
+	}
+
+	cities := make([]dto.ConfigCityResponse, 0, len(remote.Cities))
+	for _, city := range remote.Cities {
 		cities = append(cities, dto.ConfigCityResponse{ID: city.ID, Name: city.Name})
 	}
 
 	httperrors.Write(w, http.StatusOK, dto.ConfigResponse{
+		Version: snapshot.Version,
 		Limits: dto.ConfigLimitsResponse{
-			FreeLikesPerDay: h.remote.Limits.FreeLikesPerDay,
+			FreeLikesPerDay: remote.Limits.FreeLikesPerDay,
 			Plus: dto.ConfigPlusLimits{
-				UnlimitedUI: h.remote.Limits.PlusUnlimitedUI,
+				UnlimitedUI: remote.Limits.PlusUnlimitedUI,
 				RateLimits: dto.ConfigPlusRateLimits{
-					PerMinute: h.remote.Limits.PlusRatePerMinute,
-					Per10Sec:  h.remote.Limits.PlusRatePer10Seconds,
+					PerMinute: remote.Limits.PlusRatePerMinute,
+					Per10Sec:  remote.Limits.PlusRatePer10Seconds,
 				},
-				RewindPerDay: h.remote.Limits.PlusRewindsPerDay,
+				RewindPerDay: remote.Limits.PlusRewindsPerDay,
 			},
 		},
 		AntiAbuse: dto.ConfigAntiAbuse{
-			LikeMaxPerSec:        h.remote.AntiAbuse.LikeMaxPerSec,
-			LikeMax10Sec:         h.remote.AntiAbuse.LikeMax10Sec,
-			LikeMaxPerMin:        h.remote.AntiAbuse.LikeMaxPerMin,
-			ReportMaxPer10Min:    h.remote.AntiAbuse.ReportMaxPer10Min,
-			MinCardViewMS:        h.remote.AntiAbuse.MinCardViewMS,
-			RiskDecayHours:       h.remote.AntiAbuse.RiskDecayHours,
-			CooldownStepsSec:     h.remote.AntiAbuse.CooldownStepsSec,
-			ShadowThreshold:      h.remote.AntiAbuse.ShadowThreshold,
-			ShadowRankMultiplier: h.remote.AntiAbuse.ShadowRankMultiplier,
-			SuspectLikeThreshold: h.remote.AntiAbuse.SuspectLikeThreshold,
-			NewDeviceRiskWeight:  h.remote.AntiAbuse.NewDeviceRiskWeight,
+			LikeMaxPerSec:        remote.AntiAbuse.LikeMaxPerSec,
+			LikeMax10Sec:         remote.AntiAbuse.LikeMax10Sec,
+			LikeMaxPerMin:        remote.AntiAbuse.LikeMaxPerMin,
+			ReportMaxPer10Min:    remote.AntiAbuse.ReportMaxPer10Min,
+			MinCardViewMS:        remote.AntiAbuse.MinCardViewMS,
+			RiskDecayHours:       remote.AntiAbuse.RiskDecayHours,
+			CooldownStepsSec:     remote.AntiAbuse.CooldownStepsSec,
+			ShadowThreshold:      remote.AntiAbuse.ShadowThreshold,
+			ShadowRankMultiplier: remote.AntiAbuse.ShadowRankMultiplier,
+			SuspectLikeThreshold: remote.AntiAbuse.SuspectLikeThreshold,
+			NewDeviceRiskWeight:  remote.AntiAbuse.NewDeviceRiskWeight,
 		},
 		AdsInject: dto.ConfigAdsInject{
-			Free: h.remote.AdsInject.FreeEvery,
-			Plus: h.remote.AdsInject.PlusEvery,
+			Free: remote.AdsInject.FreeEvery,
+			Plus: remote.AdsInject.PlusEvery,
 		},
 		Filters: dto.ConfigFiltersResponse{
 			Age: dto.ConfigRange{
-				Min: h.remote.Filters.AgeMin,
-				Max: h.remote.Filters.AgeMax,
+				Min: remote.Filters.AgeMin,
+				Max: remote.Filters.AgeMax,
 			},
 			Radius: dto.ConfigRange{
-				Min: h.remote.Filters.RadiusDefaultKM,
-				Max: h.remote.Filters.RadiusMaxKM,
+				Min: remote.Filters.RadiusDefaultKM,
+				Max: remote.Filters.RadiusMaxKM,
 			},
 		},
-		GoalsMode: h.remote.GoalsMode,
+		GoalsMode: remote.GoalsMode,
 		Boost: dto.ConfigBoostResponse{
-			Duration: formatDuration(h.remote.Boost.Duration),
+			Duration: formatDuration(remote.Boost.Duration),
 		},
 		Cities: cities,
 	})
 }
 
+func etagMatches(header, etag string) bool {
+	for _, candidate := range strings.Split(header, ",") {
+		candidate = strings.TrimPrefix(strings.TrimSpace(candidate), "W/")
+		if candidate == "*" || candidate == etag {
+			return true
+		}
+	}
+	return false
+}
+
 func formatDuration(d time.Duration) string {
 	if d <= 0 {
 		return "0s"
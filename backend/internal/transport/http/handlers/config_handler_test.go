@@ -7,6 +7,8 @@ import (
 	"testing"
 
 	"github.com/ivankudzin/tgapp/backend/internal/config"
+	remotecfgsvc "github.com/ivankudzin/tgapp/backend/internal/services/remoteconfig"
+	"github.com/ivankudzin/tgapp/backend/internal/transport/http/dto"
 )
 
 func TestConfigHandlerResponseShape(t *testing.T) {
@@ -81,3 +83,44 @@ func requireObjectKey(t *testing.T, m map[string]interface{}, key string) {
 		t.Fatalf("missing key %q", key)
 	}
 }
+
+func TestConfigHandlerETagAndLiveSource(t *testing.T) {
+	h := NewConfigHandler(config.Default().Remote)
+
+	rr := httptest.NewRecorder()
+	h.Handle(rr, httptest.NewRequest(http.MethodGet, "/config", nil))
+	etag := rr.Header().Get("ETag")
+	if rr.Code != http.StatusOK || etag == "" {
+		t.Fatalf("expected 200 with ETag, got %d etag=%q", rr.Code, etag)
+	}
+
+	req := httptest.NewRequest(http.MethodGet, "/config", nil)
+	req.Header.Set("If-None-Match", etag)
+	rr = httptest.NewRecorder()
+	h.Handle(rr, req)
+	if rr.Code != http.StatusNotModified || rr.Body.Len() != 0 {
+		t.Fatalf("expected 304 without body, got %d", rr.Code)
+	}
+
+	next := config.Default().Remote
+	next.Limits.FreeLikesPerDay = 50
+	snapshot := remotecfgsvc.StaticSnapshot(next)
+	snapshot.Version = 4
+	h.AttachRemoteConfig(staticRemoteConfigSource{snapshot: snapshot})
+
+	req = httptest.NewRequest(http.MethodGet, "/config", nil)
+	req.Header.Set("If-None-Match", etag)
+	rr = httptest.NewRecorder()
+	h.Handle(rr, req)
+	if rr.Code != http.StatusOK || rr.Header().Get("ETag") == etag || rr.Header().Get("X-Config-Version") != "4" {
+		t.Fatalf("expected fresh config for new version, got %d headers=%v", rr.Code, rr.Header())
+	}
+
+	var resp dto.ConfigResponse
+	if err := json.Unmarshal(rr.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("decode response: %v", err)
+	}
+	if resp.Version != 4 || resp.Limits.FreeLikesPerDay != 50 {
+		t.Fatalf("unexpected response: version=%d free_likes=%d", resp.Version, resp.Limits.FreeLikesPerDay)
+	}
+}
@@ -40,11 +40,20 @@ func (h *FeedHandler) Handle(w http.ResponseWriter, r *http.Request) {
 		limit = 20
 	}
 
-	result, err := h.service.Get(r.Context(), identity.UserID, cursor, limit)
+	filters := feedsvc.Filters{
+		VerifiedOnly: parseBoolQuery(r.URL.Query().Get("verified_only")),
+	}
+
+	result, err := h.service.GetFiltered(r.Context(), identity.UserID, cursor, limit, filters)
 	if err != nil {
 		switch {
 		case errors.Is(err, feedsvc.ErrInvalidCursor):
 			writeBadRequest(w, "VALIDATION_ERROR", "invalid cursor")
+		case errors.Is(err, feedsvc.ErrPlusRequired):
+			httperrors.Write(w, http.StatusForbidden, httperrors.APIError{
+				Code:    "PLUS_REQUIRED",
+				Message: "verified only filter requires plus",
+			})
 		case errors.Is(err, feedsvc.ErrValidation):
 			writeBadRequest(w, "VALIDATION_ERROR", "invalid feed request")
 		default:
@@ -76,6 +85,7 @@ func (h *FeedHandler) Handle(w http.ResponseWriter, r *http.Request) {
 			responseItem.CityID = item.CityID
 			responseItem.City = item.City
 			responseItem.DistanceKM = item.DistanceKM
+			responseItem.IsVerified = item.IsVerified
 		}
 		items = append(items, responseItem)
 	}
@@ -86,6 +96,11 @@ func (h *FeedHandler) Handle(w http.ResponseWriter, r *http.Request) {
 	})
 }
 
+func parseBoolQuery(raw string) bool {
+	value, err := strconv.ParseBool(strings.TrimSpace(raw))
+	return err == nil && value
+}
+
 func parseIntOrDefault(raw string, fallback int) int {
 	value, err := strconv.Atoi(strings.TrimSpace(raw))
 	if err != nil {
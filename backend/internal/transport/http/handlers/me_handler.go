@@ -1,6 +1,7 @@
 package handlers
 
 import (
+	"encoding/json"
 	"fmt"
 	"net/http"
 	"time"
@@ -8,24 +9,46 @@ import (
 	"github.com/ivankudzin/tgapp/backend/internal/config"
 	antiabusesvc "github.com/ivankudzin/tgapp/backend/internal/services/antiabuse"
 	authsvc "github.com/ivankudzin/tgapp/backend/internal/services/auth"
+	flagssvc "github.com/ivankudzin/tgapp/backend/internal/services/flags"
+	waitlistsvc "github.com/ivankudzin/tgapp/backend/internal/services/waitlist"
 	"github.com/ivankudzin/tgapp/backend/internal/transport/http/dto"
 	httperrors "github.com/ivankudzin/tgapp/backend/internal/transport/http/errors"
 )
 
 type MeHandler struct {
-	remote    config.RemoteConfig
+	remote    RemoteConfigSource
 	antiabuse *antiabusesvc.Service
+	flags     *flagssvc.Service
+	waitlist  *waitlistsvc.Service
 	now       func() time.Time
 }
 
 func NewMeHandler(remote config.RemoteConfig, antiabuse *antiabusesvc.Service) *MeHandler {
 	return &MeHandler{
-		remote:    remote,
+		remote:    staticRemoteConfig(remote),
 		antiabuse: antiabuse,
 		now:       time.Now,
 	}
 }
 
+// AttachRemoteConfig switches the handler to a live config source so published versions
+// apply without a restart.
+func (h *MeHandler) AttachRemoteConfig(source RemoteConfigSource) {
+	if source != nil {
+		h.remote = source
+	}
+}
+
+// AttachFlags exposes client-visible feature flags evaluated for the caller.
+func (h *MeHandler) AttachFlags(flags *flagssvc.Service) {
+	h.flags = flags
+}
+
+// AttachWaitlist reports the caller's registration status (OPEN, WAITING or ADMITTED).
+func (h *MeHandler) AttachWaitlist(waitlist *waitlistsvc.Service) {
+	h.waitlist = waitlist
+}
+
 func (h *MeHandler) Handle(w http.ResponseWriter, r *http.Request) {
 	identity, ok := authsvc.IdentityFromContext(r.Context())
 	if !ok {
@@ -33,40 +56,41 @@ func (h *MeHandler) Handle(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	remote := h.remote.Current().Config
 	now := h.now().UTC()
 	loc := time.UTC
-	if tz := h.remote.MeDefaults.Timezone; tz != "" {
+	if tz := remote.MeDefaults.Timezone; tz != "" {
 		if loaded, err := time.LoadLocation(tz); err == nil {
 			loc = loaded
 		}
 	}
 
 	plusUntil := (*time.Time)(nil)
-	if h.remote.MeDefaults.IsPlus && h.remote.MeDefaults.PlusDuration > 0 {
-		v := now.Add(h.remote.MeDefaults.PlusDuration)
+	if remote.MeDefaults.IsPlus && remote.MeDefaults.PlusDuration > 0 {
+		v := now.Add(remote.MeDefaults.PlusDuration)
 		plusUntil = &v
 	}
 
 	incognitoUntil := (*time.Time)(nil)
-	if h.remote.MeDefaults.Entitlements.IncognitoDuration > 0 {
-		v := now.Add(h.remote.MeDefaults.Entitlements.IncognitoDuration)
+	if remote.MeDefaults.Entitlements.IncognitoDuration > 0 {
+		v := now.Add(remote.MeDefaults.Entitlements.IncognitoDuration)
 		incognitoUntil = &v
 	}
 
-	likesLeft := h.remote.Limits.FreeLikesPerDay
-	if h.remote.MeDefaults.IsPlus && h.remote.Limits.PlusUnlimitedUI {
+	likesLeft := remote.Limits.FreeLikesPerDay
+	if remote.MeDefaults.IsPlus && remote.Limits.PlusUnlimitedUI {
 		likesLeft = -1
 	}
 
 	resetAt := nextLocalMidnight(now, loc)
 	tooFast := (*int64)(nil)
-	if h.remote.MeDefaults.TooFastRetryAfterSec > 0 {
-		v := h.remote.MeDefaults.TooFastRetryAfterSec
+	if remote.MeDefaults.TooFastRetryAfterSec > 0 {
+		v := remote.MeDefaults.TooFastRetryAfterSec
 		tooFast = &v
 	}
 
-	username := fmt.Sprintf("%s%d", h.remote.MeDefaults.UsernamePrefix, identity.UserID)
-	if h.remote.MeDefaults.UsernamePrefix == "" {
+	username := fmt.Sprintf("%s%d", remote.MeDefaults.UsernamePrefix, identity.UserID)
+	if remote.MeDefaults.UsernamePrefix == "" {
 		username = fmt.Sprintf("u%d", identity.UserID)
 	}
 
@@ -87,6 +111,19 @@ func (h *MeHandler) Handle(w http.ResponseWriter, r *http.Request) {
 		}
 	}
 
+	flags := map[string]json.RawMessage{}
+	if h.flags != nil {
+		flags = h.flags.ClientValues(r.Context(), h.flags.Subject(r.Context(), identity.UserID))
+	}
+
+	var registration *dto.RegistrationStatusResponse
+	if h.waitlist != nil {
+		if status, err := h.waitlist.Status(r.Context(), identity.UserID); err == nil {
+			out := toRegistrationStatus(status)
+			registration = &out
+		}
+	}
+
 	httperrors.Write(w, http.StatusOK, dto.MeResponse{
 		User: dto.MeUserPublicResponse{
 			ID:        identity.UserID,
@@ -94,16 +131,16 @@ func (h *MeHandler) Handle(w http.ResponseWriter, r *http.Request) {
 			Username:  username,
 			Role:      identity.Role,
 			Zodiac:    nil,
-			IsPlus:    h.remote.MeDefaults.IsPlus,
+			IsPlus:    remote.MeDefaults.IsPlus,
 			PlusUntil: plusUntil,
-			CityID:    h.remote.MeDefaults.CityID,
+			CityID:    remote.MeDefaults.CityID,
 		},
-		ModerationStatus: h.remote.MeDefaults.ModerationStatus,
+		ModerationStatus: remote.MeDefaults.ModerationStatus,
 		Entitlements: dto.MeEntitlementsResponse{
-			SuperLikeCredits:      h.remote.MeDefaults.Entitlements.SuperLikeCredits,
-			BoostCredits:          h.remote.MeDefaults.Entitlements.BoostCredits,
-			RevealCredits:         h.remote.MeDefaults.Entitlements.RevealCredits,
-			MessageWoMatchCredits: h.remote.MeDefaults.Entitlements.MessageWoMatchCredits,
+			SuperLikeCredits:      remote.MeDefaults.Entitlements.SuperLikeCredits,
+			BoostCredits:          remote.MeDefaults.Entitlements.BoostCredits,
+			RevealCredits:         remote.MeDefaults.Entitlements.RevealCredits,
+			MessageWoMatchCredits: remote.MeDefaults.Entitlements.MessageWoMatchCredits,
 			IncognitoUntil:        incognitoUntil,
 		},
 		Quota: dto.MeQuotaSnapshotResponse{
@@ -112,6 +149,8 @@ func (h *MeHandler) Handle(w http.ResponseWriter, r *http.Request) {
 			TooFastRetryAfter: tooFast,
 		},
 		AntiAbuseState: antiAbuseState,
+		Flags:          flags,
+		Registration:   registration,
 	})
 }
 
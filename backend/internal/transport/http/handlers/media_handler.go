@@ -54,12 +54,7 @@ func (h *MediaHandler) PhotoUpload(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	contentType := header.Header.Get("Content-Type")
-	if contentType == "" {
-		contentType = "application/octet-stream"
-	}
-
-	photo, err := h.service.UploadPhoto(r.Context(), identity.UserID, header.Filename, contentType, file, header.Size)
+	photo, err := h.service.UploadPhoto(r.Context(), identity.UserID, file, header.Size)
 	if err != nil {
 		handleMediaError(w, err)
 		return
@@ -105,6 +100,18 @@ func handleMediaError(w http.ResponseWriter, err error) {
 	switch {
 	case errors.Is(err, mediasvc.ErrValidation):
 		writeBadRequest(w, "VALIDATION_ERROR", "invalid media request")
+	case errors.Is(err, mediasvc.ErrUnsupportedImage):
+		httperrors.Write(w, http.StatusUnsupportedMediaType, httperrors.APIError{
+			Code:    "UNSUPPORTED_IMAGE",
+			Message: "only jpeg, png and webp images are supported",
+		})
+	case errors.Is(err, mediasvc.ErrImageTooSmall):
+		writeBadRequest(w, "IMAGE_TOO_SMALL", "image resolution is too small")
+	case errors.Is(err, mediasvc.ErrImageTooLarge):
+		httperrors.Write(w, http.StatusRequestEntityTooLarge, httperrors.APIError{
+			Code:    "IMAGE_TOO_LARGE",
+			Message: "image is too large",
+		})
 	case errors.Is(err, mediasvc.ErrPhotoLimitReached):
 		httperrors.Write(w, http.StatusConflict, httperrors.APIError{
 			Code:    "PHOTO_LIMIT_REACHED",
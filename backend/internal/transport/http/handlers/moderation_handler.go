@@ -48,5 +48,50 @@ func (h *ModerationHandler) Handle(w http.ResponseWriter, r *http.Request) {
 		ReasonText:      status.ReasonText,
 		RequiredFixStep: status.RequiredFixStep,
 		ETABucket:       status.ETABucket,
+		AppealStatus:    status.AppealStatus,
+	})
+}
+
+func (h *ModerationHandler) Appeal(w http.ResponseWriter, r *http.Request) {
+	identity, ok := authsvc.IdentityFromContext(r.Context())
+	if !ok {
+		writeUnauthorized(w, "UNAUTHORIZED", "authentication required")
+		return
+	}
+	if h.service == nil {
+		writeInternal(w, "MODERATION_SERVICE_UNAVAILABLE", "moderation service is unavailable")
+		return
+	}
+
+	var req dto.ModerationAppealRequest
+	if err := decodeJSON(r, &req); err != nil {
+		writeBadRequest(w, "VALIDATION_ERROR", "invalid request body")
+		return
+	}
+
+	review, err := h.service.SubmitAppeal(r.Context(), identity.UserID, req.Message)
+	if err != nil {
+		switch {
+		case errors.Is(err, modsvc.ErrInvalidAppealMessage):
+			writeBadRequest(w, "VALIDATION_ERROR", "message must be 1-500 characters")
+		case errors.Is(err, modsvc.ErrAppealNotAllowed):
+			httperrors.Write(w, http.StatusConflict, httperrors.APIError{
+				Code:    "APPEAL_NOT_ALLOWED",
+				Message: "only a rejected profile can be appealed",
+			})
+		case errors.Is(err, modsvc.ErrAppealExists):
+			httperrors.Write(w, http.StatusConflict, httperrors.APIError{
+				Code:    "APPEAL_EXISTS",
+				Message: "this decision has already been appealed",
+			})
+		default:
+			writeInternal(w, "INTERNAL_ERROR", "failed to submit appeal")
+		}
+		return
+	}
+
+	httperrors.Write(w, http.StatusOK, dto.ModerationAppealResponse{
+		ReviewID: review.ID,
+		Status:   review.Status,
 	})
 }
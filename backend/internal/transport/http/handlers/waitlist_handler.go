@@ -0,0 +1,99 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+
+	authsvc "github.com/ivankudzin/tgapp/backend/internal/services/auth"
+	waitlistsvc "github.com/ivankudzin/tgapp/backend/internal/services/waitlist"
+	"github.com/ivankudzin/tgapp/backend/internal/transport/http/dto"
+	httperrors "github.com/ivankudzin/tgapp/backend/internal/transport/http/errors"
+)
+
+type WaitlistHandler struct {
+	waitlist *waitlistsvc.Service
+}
+
+func NewWaitlistHandler(waitlist *waitlistsvc.Service) *WaitlistHandler {
+	return &WaitlistHandler{waitlist: waitlist}
+}
+
+func (h *WaitlistHandler) Status(w http.ResponseWriter, r *http.Request) {
+	identity, ok := authsvc.IdentityFromContext(r.Context())
+	if !ok {
+		writeUnauthorized(w, "UNAUTHORIZED", "authentication required")
+		return
+	}
+	if h.waitlist == nil {
+		writeInternal(w, "WAITLIST_UNAVAILABLE", "waitlist service is unavailable")
+		return
+	}
+
+	status, err := h.waitlist.Status(r.Context(), identity.UserID)
+	if err != nil {
+		writeInternal(w, "INTERNAL_ERROR", "failed to load waitlist status")
+		return
+	}
+	httperrors.Write(w, http.StatusOK, toRegistrationStatus(status))
+}
+
+// Invite applies an invite code for a user who is already queued.
+func (h *WaitlistHandler) Invite(w http.ResponseWriter, r *http.Request) {
+	identity, ok := authsvc.IdentityFromContext(r.Context())
+	if !ok {
+		writeUnauthorized(w, "UNAUTHORIZED", "authentication required")
+		return
+	}
+	if h.waitlist == nil {
+		writeInternal(w, "WAITLIST_UNAVAILABLE", "waitlist service is unavailable")
+		return
+	}
+
+	var req dto.WaitlistInviteRequest
+	if err := decodeJSON(r, &req); err != nil {
+		writeBadRequest(w, "VALIDATION_ERROR", "invalid json body")
+		return
+	}
+
+	status, err := h.waitlist.RedeemInvite(r.Context(), identity.UserID, req.InviteCode)
+	if err != nil {
+		writeWaitlistError(w, err, "failed to redeem invite code")
+		return
+	}
+	httperrors.Write(w, http.StatusOK, toRegistrationStatus(status))
+}
+
+func writeWaitlistError(w http.ResponseWriter, err error, fallback string) {
+	switch {
+	case errors.Is(err, waitlistsvc.ErrInvalidInput):
+		writeBadRequest(w, "VALIDATION_ERROR", err.Error())
+	case errors.Is(err, waitlistsvc.ErrInviteCodeInvalid):
+		writeBadRequest(w, "INVITE_CODE_INVALID", "invite code is invalid, expired or used up")
+	case errors.Is(err, waitlistsvc.ErrNotOnWaitlist):
+		httperrors.Write(w, http.StatusConflict, httperrors.APIError{
+			Code:    "NOT_ON_WAITLIST",
+			Message: "user is not on the waitlist",
+		})
+	case errors.Is(err, waitlistsvc.ErrInviteCodeNotFound):
+		httperrors.Write(w, http.StatusNotFound, httperrors.APIError{
+			Code:    "NOT_FOUND",
+			Message: "invite code not found",
+		})
+	case errors.Is(err, waitlistsvc.ErrWaitlistUnavailable):
+		writeInternal(w, "WAITLIST_UNAVAILABLE", "waitlist store is not configured")
+	default:
+		writeInternal(w, "INTERNAL_ERROR", fallback)
+	}
+}
+
+func toRegistrationStatus(status waitlistsvc.Status) dto.RegistrationStatusResponse {
+	return dto.RegistrationStatusResponse{
+		Status:         status.Status,
+		Position:       status.Position,
+		Ahead:          status.Ahead,
+		AdmittedVia:    status.AdmittedVia,
+		JoinedAt:       status.JoinedAt,
+		AdmittedAt:     status.AdmittedAt,
+		InviteRejected: status.InviteRejected,
+	}
+}
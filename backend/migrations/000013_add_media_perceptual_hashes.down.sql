@@ -0,0 +1,9 @@
+DROP TABLE IF EXISTS media_hash_matches;
+DROP TABLE IF EXISTS banned_image_hashes;
+
+DROP INDEX IF EXISTS idx_media_phash_bands;
+
+ALTER TABLE media
+    DROP COLUMN IF EXISTS phash_bands,
+    DROP COLUMN IF EXISTS dhash,
+    DROP COLUMN IF EXISTS phash;
@@ -0,0 +1,36 @@
+ALTER TABLE media
+    ADD COLUMN IF NOT EXISTS phash BIGINT NULL,
+    ADD COLUMN IF NOT EXISTS dhash BIGINT NULL,
+    ADD COLUMN IF NOT EXISTS phash_bands INTEGER[] NULL;
+
+CREATE INDEX IF NOT EXISTS idx_media_phash_bands
+    ON media USING GIN (phash_bands)
+    WHERE kind = 'photo' AND phash_bands IS NOT NULL;
+
+CREATE TABLE IF NOT EXISTS banned_image_hashes (
+    id BIGSERIAL PRIMARY KEY,
+    phash BIGINT NOT NULL,
+    dhash BIGINT NULL,
+    phash_bands INTEGER[] NOT NULL,
+    label TEXT NOT NULL DEFAULT '',
+    source_media_id BIGINT NULL REFERENCES media(id) ON DELETE SET NULL,
+    created_by_tg_id BIGINT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_banned_image_hashes_bands
+    ON banned_image_hashes USING GIN (phash_bands);
+
+CREATE TABLE IF NOT EXISTS media_hash_matches (
+    id BIGSERIAL PRIMARY KEY,
+    media_id BIGINT NOT NULL REFERENCES media(id) ON DELETE CASCADE,
+    source TEXT NOT NULL CHECK (source IN ('USER', 'BANNED')),
+    matched_media_id BIGINT NULL REFERENCES media(id) ON DELETE CASCADE,
+    matched_user_id BIGINT NULL,
+    banned_image_id BIGINT NULL REFERENCES banned_image_hashes(id) ON DELETE CASCADE,
+    distance INTEGER NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_media_hash_matches_media
+    ON media_hash_matches (media_id, distance ASC);
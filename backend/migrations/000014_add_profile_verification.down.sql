@@ -0,0 +1,8 @@
+DROP INDEX IF EXISTS idx_profiles_verified;
+
+ALTER TABLE profiles
+    DROP CONSTRAINT IF EXISTS profiles_verification_status_check;
+
+ALTER TABLE profiles
+    DROP COLUMN IF EXISTS verified_at,
+    DROP COLUMN IF EXISTS verification_status;
@@ -0,0 +1,14 @@
+ALTER TABLE profiles
+    ADD COLUMN IF NOT EXISTS verification_status TEXT NOT NULL DEFAULT 'NONE',
+    ADD COLUMN IF NOT EXISTS verified_at TIMESTAMPTZ NULL;
+
+ALTER TABLE profiles
+    DROP CONSTRAINT IF EXISTS profiles_verification_status_check;
+
+ALTER TABLE profiles
+    ADD CONSTRAINT profiles_verification_status_check
+    CHECK (verification_status IN ('NONE', 'PENDING', 'VERIFIED', 'REJECTED', 'REVERIFY_REQUIRED'));
+
+CREATE INDEX IF NOT EXISTS idx_profiles_verified
+    ON profiles (user_id)
+    WHERE verification_status = 'VERIFIED';
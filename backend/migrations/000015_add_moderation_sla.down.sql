@@ -0,0 +1,7 @@
+DROP INDEX IF EXISTS idx_moderation_lock_expiries_expired_at;
+DROP TABLE IF EXISTS moderation_lock_expiries;
+
+DROP INDEX IF EXISTS idx_moderation_items_decided_at;
+
+ALTER TABLE moderation_items
+    DROP COLUMN IF EXISTS review_started_at;
@@ -0,0 +1,18 @@
+ALTER TABLE moderation_items
+    ADD COLUMN IF NOT EXISTS review_started_at TIMESTAMPTZ NULL;
+
+CREATE INDEX IF NOT EXISTS idx_moderation_items_decided_at
+    ON moderation_items (decided_at DESC)
+    WHERE decided_at IS NOT NULL;
+
+CREATE TABLE IF NOT EXISTS moderation_lock_expiries (
+    id BIGSERIAL PRIMARY KEY,
+    moderation_item_id BIGINT NOT NULL REFERENCES moderation_items(id) ON DELETE CASCADE,
+    moderator_tg_id BIGINT NOT NULL,
+    locked_at TIMESTAMPTZ NULL,
+    expired_at TIMESTAMPTZ NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_moderation_lock_expiries_expired_at
+    ON moderation_lock_expiries (expired_at DESC);
@@ -0,0 +1,4 @@
+DROP INDEX IF EXISTS idx_moderation_reviews_decided_at;
+DROP INDEX IF EXISTS idx_moderation_reviews_pending_fifo;
+DROP INDEX IF EXISTS ux_moderation_reviews_item_kind;
+DROP TABLE IF EXISTS moderation_reviews;
@@ -0,0 +1,31 @@
+CREATE TABLE IF NOT EXISTS moderation_reviews (
+    id BIGSERIAL PRIMARY KEY,
+    moderation_item_id BIGINT NOT NULL REFERENCES moderation_items(id) ON DELETE CASCADE,
+    user_id BIGINT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    kind TEXT NOT NULL,
+    status TEXT NOT NULL DEFAULT 'PENDING',
+    original_decision TEXT NOT NULL,
+    original_reason_code TEXT NULL,
+    original_moderator_tg_id BIGINT NULL,
+    appeal_message TEXT NULL,
+    reviewer_tg_id BIGINT NULL,
+    review_note TEXT NULL,
+    locked_by_tg_id BIGINT NULL,
+    locked_at TIMESTAMPTZ NULL,
+    locked_until TIMESTAMPTZ NULL,
+    decided_at TIMESTAMPTZ NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    CONSTRAINT moderation_reviews_kind_check CHECK (kind IN ('APPEAL', 'QA')),
+    CONSTRAINT moderation_reviews_status_check CHECK (status IN ('PENDING', 'UPHELD', 'OVERTURNED'))
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS ux_moderation_reviews_item_kind
+    ON moderation_reviews (moderation_item_id, kind);
+
+CREATE INDEX IF NOT EXISTS idx_moderation_reviews_pending_fifo
+    ON moderation_reviews (kind, status, created_at ASC, id ASC);
+
+CREATE INDEX IF NOT EXISTS idx_moderation_reviews_decided_at
+    ON moderation_reviews (kind, decided_at DESC)
+    WHERE decided_at IS NOT NULL;
@@ -0,0 +1,11 @@
+-- The table itself may be owned by bot_moderator, so only the chain columns are removed.
+DROP INDEX IF EXISTS idx_bot_audit_target_user_created_at;
+DROP INDEX IF EXISTS idx_bot_audit_action_created_at;
+DROP INDEX IF EXISTS idx_bot_audit_actor_created_at;
+DROP INDEX IF EXISTS idx_bot_audit_created_at;
+DROP INDEX IF EXISTS idx_bot_audit_seq;
+
+ALTER TABLE bot_audit
+    DROP COLUMN IF EXISTS hash,
+    DROP COLUMN IF EXISTS prev_hash,
+    DROP COLUMN IF EXISTS seq;
@@ -0,0 +1,26 @@
+CREATE EXTENSION IF NOT EXISTS pgcrypto;
+
+-- bot_audit is shared with tgbots/bot_moderator; the shape must stay compatible with its migrations.
+CREATE TABLE IF NOT EXISTS bot_audit (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    actor_tg_id BIGINT NOT NULL,
+    action TEXT NOT NULL,
+    payload JSONB NOT NULL DEFAULT '{}'::jsonb,
+    created_at TIMESTAMPTZ NOT NULL
+);
+
+ALTER TABLE bot_audit
+    ADD COLUMN IF NOT EXISTS seq BIGSERIAL,
+    ADD COLUMN IF NOT EXISTS prev_hash TEXT NULL,
+    ADD COLUMN IF NOT EXISTS hash TEXT NULL;
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_bot_audit_seq
+    ON bot_audit (seq);
+CREATE INDEX IF NOT EXISTS idx_bot_audit_created_at
+    ON bot_audit (created_at DESC);
+CREATE INDEX IF NOT EXISTS idx_bot_audit_actor_created_at
+    ON bot_audit (actor_tg_id, created_at DESC);
+CREATE INDEX IF NOT EXISTS idx_bot_audit_action_created_at
+    ON bot_audit (action, created_at DESC);
+CREATE INDEX IF NOT EXISTS idx_bot_audit_target_user_created_at
+    ON bot_audit (((payload->>'target_user_id')), created_at DESC);
@@ -0,0 +1,2 @@
+DROP INDEX IF EXISTS idx_moderation_reject_reasons_active_sort;
+DROP TABLE IF EXISTS moderation_reject_reasons;
@@ -0,0 +1,30 @@
+CREATE TABLE IF NOT EXISTS moderation_reject_reasons (
+    code TEXT PRIMARY KEY,
+    labels JSONB NOT NULL DEFAULT '{}'::jsonb,
+    reason_text TEXT NOT NULL,
+    required_fix_step TEXT NOT NULL,
+    severity TEXT NOT NULL DEFAULT 'MEDIUM',
+    is_active BOOLEAN NOT NULL DEFAULT TRUE,
+    sort_order INT NOT NULL DEFAULT 0,
+    updated_by_tg_id BIGINT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    CONSTRAINT moderation_reject_reasons_code_check CHECK (code ~ '^[A-Z0-9_]{2,32}$'),
+    CONSTRAINT moderation_reject_reasons_severity_check CHECK (severity IN ('LOW', 'MEDIUM', 'HIGH'))
+);
+
+CREATE INDEX IF NOT EXISTS idx_moderation_reject_reasons_active_sort
+    ON moderation_reject_reasons (is_active, sort_order, code);
+
+INSERT INTO moderation_reject_reasons (code, labels, reason_text, required_fix_step, severity, sort_order)
+VALUES
+    ('PHOTO_NO_FACE', '{"ru": "Фото: не видно лица", "en": "Photo: face not visible"}', 'На фото не видно лица.', 'Загрузите фото, где лицо хорошо различимо.', 'LOW', 10),
+    ('PHOTO_FAKE_NOT_YOU', '{"ru": "Фото: не вы", "en": "Photo: not you"}', 'Фото не соответствует владельцу анкеты.', 'Загрузите ваши реальные фотографии без чужих изображений.', 'HIGH', 20),
+    ('PHOTO_PROHIBITED', '{"ru": "Фото: запрещенный контент", "en": "Photo: prohibited content"}', 'Обнаружен запрещенный фото-контент.', 'Удалите запрещённый контент и загрузите новые фото.', 'HIGH', 30),
+    ('CIRCLE_MISMATCH', '{"ru": "Кружок: не совпадает", "en": "Circle: mismatch"}', 'Кружок не совпадает с фотографиями анкеты.', 'Перезапишите кружок, чтобы внешность совпадала с фото.', 'MEDIUM', 40),
+    ('CIRCLE_FAILED', '{"ru": "Кружок: не прошел проверку", "en": "Circle: check failed"}', 'Кружок не прошел проверку качества.', 'Перезапишите кружок при хорошем освещении и стабильной связи.', 'LOW', 50),
+    ('PROFILE_INCOMPLETE', '{"ru": "Профиль: не заполнен", "en": "Profile: incomplete"}', 'Профиль заполнен не полностью.', 'Заполните обязательные поля профиля и отправьте на модерацию повторно.', 'LOW', 60),
+    ('SPAM_ADS_LINKS', '{"ru": "Спам/реклама/ссылки", "en": "Spam/ads/links"}', 'Обнаружены признаки спама, рекламы или внешних ссылок.', 'Удалите спам/рекламу/ссылки из профиля и отправьте на модерацию повторно.', 'HIGH', 70),
+    ('BOT_SUSPECT', '{"ru": "Подозрение на бота", "en": "Bot suspect"}', 'Профиль помечен как подозрительный на автоматизацию.', 'Обновите анкету и пройдите повторную модерацию вручную.', 'HIGH', 80),
+    ('OTHER', '{"ru": "Другое", "en": "Other"}', 'Требуется корректировка анкеты.', 'Обновите анкету по замечанию модератора и отправьте на модерацию повторно.', 'MEDIUM', 1000)
+ON CONFLICT (code) DO NOTHING;
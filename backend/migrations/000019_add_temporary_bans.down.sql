@@ -0,0 +1,7 @@
+DROP INDEX IF EXISTS idx_media_hash_matches_matched_user;
+DROP INDEX IF EXISTS idx_user_bans_active_expires_at;
+
+ALTER TABLE user_bans
+    DROP COLUMN IF EXISTS expires_at,
+    DROP COLUMN IF EXISTS banned_at,
+    DROP COLUMN IF EXISTS reason_code;
@@ -0,0 +1,18 @@
+ALTER TABLE user_bans
+    ADD COLUMN IF NOT EXISTS reason_code TEXT NULL,
+    ADD COLUMN IF NOT EXISTS banned_at TIMESTAMPTZ NULL,
+    ADD COLUMN IF NOT EXISTS expires_at TIMESTAMPTZ NULL;
+
+UPDATE user_bans
+SET reason_code = 'OTHER',
+    banned_at = updated_at
+WHERE banned = TRUE
+  AND reason_code IS NULL;
+
+CREATE INDEX IF NOT EXISTS idx_user_bans_active_expires_at
+    ON user_bans (expires_at)
+    WHERE banned = TRUE;
+
+CREATE INDEX IF NOT EXISTS idx_media_hash_matches_matched_user
+    ON media_hash_matches (matched_user_id)
+    WHERE matched_user_id IS NOT NULL;
@@ -0,0 +1,7 @@
+DROP INDEX IF EXISTS idx_moderation_items_locked_by;
+
+ALTER TABLE moderation_items
+    DROP COLUMN IF EXISTS assigned_at;
+
+DROP TABLE IF EXISTS moderator_shifts;
+DROP TABLE IF EXISTS moderator_workload;
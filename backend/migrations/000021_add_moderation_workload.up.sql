@@ -0,0 +1,31 @@
+CREATE TABLE IF NOT EXISTS moderator_workload (
+    moderator_tg_id BIGINT PRIMARY KEY,
+    max_active_items INTEGER NOT NULL DEFAULT 1,
+    skills TEXT[] NOT NULL DEFAULT ARRAY['PHOTO', 'CIRCLE', 'REPORTS']::TEXT[],
+    updated_by_tg_id BIGINT NULL,
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    CONSTRAINT moderator_workload_max_active_items_check CHECK (max_active_items BETWEEN 1 AND 20)
+);
+
+-- Shift times are wall-clock Europe/Minsk; ends_at <= starts_at means the shift crosses midnight.
+CREATE TABLE IF NOT EXISTS moderator_shifts (
+    id BIGSERIAL PRIMARY KEY,
+    moderator_tg_id BIGINT NOT NULL,
+    weekday SMALLINT NOT NULL,
+    starts_at TIME NOT NULL,
+    ends_at TIME NOT NULL,
+    created_by_tg_id BIGINT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    CONSTRAINT moderator_shifts_weekday_check CHECK (weekday BETWEEN 1 AND 7),
+    CONSTRAINT moderator_shifts_span_check CHECK (starts_at <> ends_at)
+);
+
+CREATE INDEX IF NOT EXISTS idx_moderator_shifts_moderator
+    ON moderator_shifts (moderator_tg_id, weekday, starts_at);
+
+ALTER TABLE moderation_items
+    ADD COLUMN IF NOT EXISTS assigned_at TIMESTAMPTZ NULL;
+
+CREATE INDEX IF NOT EXISTS idx_moderation_items_locked_by
+    ON moderation_items (locked_by_tg_id, locked_until)
+    WHERE locked_by_tg_id IS NOT NULL;
@@ -0,0 +1,4 @@
+DROP INDEX IF EXISTS idx_moderation_items_moderator_admin_user;
+
+ALTER TABLE moderation_items
+    DROP COLUMN IF EXISTS moderator_admin_user_id;
@@ -0,0 +1,8 @@
+-- Decisions made from the web admin panel are attributed to the admin user; moderator_tg_id
+-- still carries the Telegram ID the queue lock was held under.
+ALTER TABLE moderation_items
+    ADD COLUMN IF NOT EXISTS moderator_admin_user_id BIGINT NULL REFERENCES admin_users(id) ON DELETE SET NULL;
+
+CREATE INDEX IF NOT EXISTS idx_moderation_items_moderator_admin_user
+    ON moderation_items (moderator_admin_user_id, decided_at DESC)
+    WHERE moderator_admin_user_id IS NOT NULL;
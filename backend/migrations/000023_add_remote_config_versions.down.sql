@@ -0,0 +1 @@
+DROP TABLE IF EXISTS remote_config_versions;
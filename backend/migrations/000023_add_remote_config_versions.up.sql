@@ -0,0 +1,12 @@
+-- Remote config is an append-only list of documents; the highest version is the active one.
+-- A rollback re-publishes an older document as a new version so the history stays linear.
+CREATE TABLE IF NOT EXISTS remote_config_versions (
+    version BIGSERIAL PRIMARY KEY,
+    document JSONB NOT NULL,
+    checksum TEXT NOT NULL,
+    comment TEXT NOT NULL DEFAULT '',
+    rollback_of BIGINT NULL REFERENCES remote_config_versions(version),
+    published_by_admin_user_id BIGINT NULL REFERENCES admin_users(id) ON DELETE SET NULL,
+    published_by_tg_id BIGINT NULL,
+    published_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
@@ -0,0 +1,10 @@
+DELETE FROM app_flags WHERE key = 'travel_mode';
+
+ALTER TABLE app_flags DROP CONSTRAINT IF EXISTS app_flags_value_type_check;
+
+ALTER TABLE app_flags
+    DROP COLUMN IF EXISTS client_visible,
+    DROP COLUMN IF EXISTS description,
+    DROP COLUMN IF EXISTS rules,
+    DROP COLUMN IF EXISTS value,
+    DROP COLUMN IF EXISTS value_type;
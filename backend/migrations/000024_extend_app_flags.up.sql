@@ -0,0 +1,21 @@
+-- app_flags becomes a typed flag store. Boolean flags keep their default in value_bool (the bot's
+-- DB mode toggles it directly); int and string flags keep it in value. Rules are evaluated in
+-- order and the first matching rule's value wins.
+ALTER TABLE app_flags
+    ADD COLUMN IF NOT EXISTS value_type TEXT NOT NULL DEFAULT 'bool',
+    ADD COLUMN IF NOT EXISTS value JSONB NULL,
+    ADD COLUMN IF NOT EXISTS rules JSONB NOT NULL DEFAULT '[]'::jsonb,
+    ADD COLUMN IF NOT EXISTS description TEXT NOT NULL DEFAULT '',
+    ADD COLUMN IF NOT EXISTS client_visible BOOLEAN NOT NULL DEFAULT FALSE;
+
+ALTER TABLE app_flags DROP CONSTRAINT IF EXISTS app_flags_value_type_check;
+ALTER TABLE app_flags
+    ADD CONSTRAINT app_flags_value_type_check CHECK (value_type IN ('bool', 'int', 'string'));
+
+UPDATE app_flags
+SET description = 'Регистрация новых пользователей'
+WHERE key = 'registration_enabled' AND description = '';
+
+INSERT INTO app_flags (key, value_bool, value_type, description, client_visible, updated_at)
+VALUES ('travel_mode', FALSE, 'bool', 'Travel mode (dark launch по городам)', TRUE, NOW())
+ON CONFLICT (key) DO NOTHING;
@@ -0,0 +1,5 @@
+DROP TABLE IF EXISTS invite_codes;
+
+DROP INDEX IF EXISTS idx_registration_waitlist_notify;
+DROP INDEX IF EXISTS idx_registration_waitlist_waiting;
+DROP TABLE IF EXISTS registration_waitlist;
@@ -0,0 +1,40 @@
+-- New Telegram users who log in while registration_enabled is off wait here until an admin admits
+-- them. position is the queue order. The bot notifies batch-admitted users (notified_at); a claim
+-- (notify_attempted_at) keeps bot instances from sending twice and it gives up after a few attempts.
+CREATE TABLE IF NOT EXISTS registration_waitlist (
+    user_id BIGINT PRIMARY KEY REFERENCES users(id) ON DELETE CASCADE,
+    position BIGSERIAL NOT NULL UNIQUE,
+    status TEXT NOT NULL DEFAULT 'WAITING',
+    invite_code TEXT NULL,
+    admitted_via TEXT NULL,
+    joined_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    admitted_at TIMESTAMPTZ NULL,
+    admitted_by_tg_id BIGINT NULL,
+    notified_at TIMESTAMPTZ NULL,
+    notify_attempts INT NOT NULL DEFAULT 0,
+    notify_attempted_at TIMESTAMPTZ NULL,
+    CONSTRAINT registration_waitlist_status_check CHECK (status IN ('WAITING', 'ADMITTED')),
+    CONSTRAINT registration_waitlist_admitted_via_check CHECK (admitted_via IS NULL OR admitted_via IN ('batch', 'invite', 'open'))
+);
+
+CREATE INDEX IF NOT EXISTS idx_registration_waitlist_waiting
+    ON registration_waitlist(position)
+    WHERE status = 'WAITING';
+
+CREATE INDEX IF NOT EXISTS idx_registration_waitlist_notify
+    ON registration_waitlist(admitted_at)
+    WHERE status = 'ADMITTED' AND notified_at IS NULL;
+
+-- Invite codes let a user skip the waitlist; max_uses = 0 means unlimited.
+CREATE TABLE IF NOT EXISTS invite_codes (
+    code TEXT PRIMARY KEY,
+    max_uses INT NOT NULL DEFAULT 1,
+    used_count INT NOT NULL DEFAULT 0,
+    expires_at TIMESTAMPTZ NULL,
+    comment TEXT NOT NULL DEFAULT '',
+    created_by_tg_id BIGINT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    disabled_at TIMESTAMPTZ NULL,
+    CONSTRAINT invite_codes_max_uses_check CHECK (max_uses >= 0),
+    CONSTRAINT invite_codes_used_count_check CHECK (used_count >= 0)
+);
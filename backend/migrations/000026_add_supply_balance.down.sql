@@ -0,0 +1 @@
+DROP TABLE IF EXISTS supply_balance_decisions;
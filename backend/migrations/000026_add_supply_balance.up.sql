@@ -0,0 +1,24 @@
+-- Latest decisions of the supply balance controller, one row per city. The API recomputes the
+-- table from approved profiles seen in the active window; waitlist admission and the moderation
+-- queue read it directly so every instance (and the bot's direct-DB paths) act on the same view.
+-- under_gender / over_gender are NULL while the city is within tolerance or has too few profiles.
+CREATE TABLE IF NOT EXISTS supply_balance_decisions (
+    city_id TEXT PRIMARY KEY,
+    active_female INT NOT NULL DEFAULT 0,
+    active_male INT NOT NULL DEFAULT 0,
+    female_share DOUBLE PRECISION NOT NULL DEFAULT 0,
+    target_share DOUBLE PRECISION NOT NULL DEFAULT 0,
+    state TEXT NOT NULL,
+    under_gender TEXT NULL,
+    over_gender TEXT NULL,
+    likes_female INT NOT NULL DEFAULT 0,
+    likes_male INT NOT NULL DEFAULT 0,
+    waiting_female INT NOT NULL DEFAULT 0,
+    waiting_male INT NOT NULL DEFAULT 0,
+    throttle_admissions BOOLEAN NOT NULL DEFAULT FALSE,
+    prioritize_moderation BOOLEAN NOT NULL DEFAULT FALSE,
+    over_like_factor DOUBLE PRECISION NOT NULL DEFAULT 1,
+    under_like_factor DOUBLE PRECISION NOT NULL DEFAULT 1,
+    computed_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    CONSTRAINT supply_balance_decisions_state_check CHECK (state IN ('BALANCED', 'FEMALE_SHORT', 'MALE_SHORT', 'LOW_SAMPLE'))
+);
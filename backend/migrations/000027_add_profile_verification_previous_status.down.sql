@@ -0,0 +1,2 @@
+ALTER TABLE profiles
+    DROP COLUMN IF EXISTS verification_previous_status;
@@ -0,0 +1,4 @@
+-- Verification status a profile had before its latest circle went to moderation, so a reject
+-- for a non-circle reason can put it back instead of leaving the profile PENDING.
+ALTER TABLE profiles
+    ADD COLUMN IF NOT EXISTS verification_previous_status TEXT NULL;
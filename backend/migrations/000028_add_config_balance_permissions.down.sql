@@ -0,0 +1,4 @@
+UPDATE admin_roles
+SET permissions = array_remove(array_remove(permissions, 'config.publish'), 'balance.recompute'),
+    updated_at = NOW()
+WHERE permissions && ARRAY['config.publish', 'balance.recompute'];
@@ -0,0 +1,6 @@
+-- OWNER holds every permission in code; the stored seed is kept in step so role listings match.
+UPDATE admin_roles
+SET permissions = permissions || ARRAY['config.publish', 'balance.recompute'],
+    updated_at = NOW()
+WHERE name = 'OWNER'
+  AND NOT permissions @> ARRAY['config.publish', 'balance.recompute'];
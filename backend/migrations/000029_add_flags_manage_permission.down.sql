@@ -0,0 +1,4 @@
+UPDATE admin_roles
+SET permissions = array_remove(permissions, 'flags.manage'),
+    updated_at = NOW()
+WHERE 'flags.manage' = ANY(permissions);
@@ -0,0 +1,12 @@
+-- Flag edits move off system.toggle_registration; roles that could edit flags before keep that ability.
+UPDATE admin_roles
+SET permissions = permissions || ARRAY['flags.manage'],
+    updated_at = NOW()
+WHERE 'system.toggle_registration' = ANY(permissions)
+  AND NOT 'flags.manage' = ANY(permissions);
+
+UPDATE admin_roles
+SET permissions = permissions || ARRAY['flags.manage'],
+    updated_at = NOW()
+WHERE name = 'OWNER'
+  AND NOT 'flags.manage' = ANY(permissions);
@@ -0,0 +1,170 @@
+package app
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"bot_moderator/internal/domain/enums"
+	"bot_moderator/internal/domain/model"
+	"bot_moderator/internal/infra/statestore"
+	"bot_moderator/internal/infra/telegram"
+	"bot_moderator/internal/services/access"
+	"bot_moderator/internal/ui"
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+)
+
+// Role editor callbacks carry the selected permissions as a bitmask over enums.Permissions so the
+// draft survives without session state and stays within Telegram's 64-byte callback limit:
+// acc:rm:NAME:MASK re-renders the editor, acc:rs:NAME:MASK saves.
+func (a *App) handleRoleDefinitionCallback(ctx context.Context, chatID int64, actorTGID int64, actorRole enums.Role, parts []string) (string, bool) {
+	if parts[1] == "roles" {
+		a.sendRoleDefinitionsScreen(ctx, chatID, actorRole)
+		return "", false
+	}
+	if actorRole != enums.RoleOwner {
+		return "Роли может настраивать только OWNER", true
+	}
+
+	switch parts[1] {
+	case "rnew":
+		if !a.enterChatState(ctx, chatID, telegram.StateWaitingRoleName, actorTGID, actorRole, nil) {
+			return chatStateBusyText, true
+		}
+		a.sendText(chatID, "Введите название новой роли латиницей, например QA_LEAD")
+		return "", false
+	case "re":
+		if len(parts) < 3 {
+			return "", false
+		}
+		def, ok := a.accessService.RoleDefinition(ctx, enums.NormalizeRole(parts[2]))
+		if !ok {
+			return "Роль не найдена", true
+		}
+		if def.IsSystem {
+			return "Системные роли не редактируются", true
+		}
+		a.sendRoleEditor(chatID, def.Name, permissionMask(def.Permissions))
+		return "", false
+	case "rm", "rs":
+		if len(parts) < 4 {
+			return "", false
+		}
+		name := enums.NormalizeRole(parts[2])
+		mask, err := strconv.Atoi(parts[3])
+		if err != nil || mask < 0 || mask >= 1<<len(enums.Permissions) {
+			return "Некорректный набор прав", true
+		}
+		if parts[1] == "rm" {
+			a.sendRoleEditor(chatID, name, mask)
+			return "", false
+		}
+		return a.saveRoleDefinition(ctx, chatID, actorTGID, actorRole, name, mask)
+	default:
+		return "", false
+	}
+}
+
+func (a *App) saveRoleDefinition(ctx context.Context, chatID int64, actorTGID int64, actorRole enums.Role, name enums.Role, mask int) (string, bool) {
+	saved, err := a.accessService.SaveRoleDefinition(ctx, actorTGID, actorRole, model.RoleDefinition{
+		Name:        name,
+		Permissions: maskPermissions(mask),
+	})
+	switch {
+	case errors.Is(err, access.ErrInvalidRole):
+		return "Выберите хотя бы одно право", true
+	case errors.Is(err, access.ErrSystemRole):
+		return "Системные роли не редактируются", true
+	case errors.Is(err, access.ErrAccessDenied):
+		return "Недостаточно прав", true
+	case err != nil:
+		a.logger.Warn("save role definition", "error", err, "role", name, "tg_id", actorTGID)
+		return "Не удалось сохранить роль", true
+	}
+
+	if err := a.auditService.LogRoleDefinitionSaved(ctx, actorTGID, saved); err != nil {
+		a.logger.Warn("write role definition audit", "error", err, "role", name)
+	}
+	a.sendRoleDefinitionsScreen(ctx, chatID, actorRole)
+	return fmt.Sprintf("Роль %s сохранена", saved.Name), false
+}
+
+func (a *App) handleRoleNameInput(ctx context.Context, message *tgbotapi.Message, session statestore.Session) {
+	name := enums.NormalizeRole(message.Text)
+	switch err := access.ValidateCustomRoleName(name); {
+	case errors.Is(err, access.ErrSystemRole):
+		a.sendText(message.Chat.ID, "Это системная роль, выберите другое название")
+		return
+	case err != nil:
+		a.sendText(message.Chat.ID, "Название: 2–32 символа A-Z, 0-9 и _, начинается с буквы")
+		return
+	}
+
+	a.resetChatState(ctx, message.Chat.ID, session)
+	mask := 0
+	if def, ok := a.accessService.RoleDefinition(ctx, name); ok {
+		mask = permissionMask(def.Permissions)
+	}
+	a.sendRoleEditor(message.Chat.ID, name, mask)
+}
+
+func (a *App) sendRoleDefinitionsScreen(ctx context.Context, chatID int64, actorRole enums.Role) {
+	defs := a.accessService.ListRoleDefinitions(ctx)
+
+	rows := make([][]telegram.InlineButton, 0, len(defs)+2)
+	if actorRole == enums.RoleOwner {
+		for _, def := range defs {
+			if def.IsSystem {
+				continue
+			}
+			rows = append(rows, []telegram.InlineButton{{Text: "✏️ " + string(def.Name), Data: "acc:re:" + string(def.Name)}})
+		}
+		rows = append(rows, []telegram.InlineButton{{Text: "➕ Новая роль", Data: "acc:rnew"}})
+	}
+	rows = append(rows, []telegram.InlineButton{{Text: "⬅️ Back", Data: "acc:root"}})
+	a.sendInline(chatID, ui.RenderRoleDefinitions(defs), rows)
+}
+
+func (a *App) sendRoleEditor(chatID int64, name enums.Role, mask int) {
+	rows := make([][]telegram.InlineButton, 0, len(enums.Permissions)+2)
+	for idx, permission := range enums.Permissions {
+		mark := "▫️"
+		if mask&(1<<idx) != 0 {
+			mark = "✅"
+		}
+		rows = append(rows, []telegram.InlineButton{{
+			Text: mark + " " + permission.Label(),
+			Data: fmt.Sprintf("acc:rm:%s:%d", name, mask^(1<<idx)),
+		}})
+	}
+	rows = append(rows,
+		[]telegram.InlineButton{{Text: "💾 Сохранить", Data: fmt.Sprintf("acc:rs:%s:%d", name, mask)}},
+		[]telegram.InlineButton{{Text: "⬅️ Back", Data: "acc:roles"}},
+	)
+	a.sendInline(chatID, ui.RenderRoleEditor(name, maskPermissions(mask)), rows)
+}
+
+func permissionMask(permissions []enums.Permission) int {
+	mask := 0
+	for idx, permission := range enums.Permissions {
+		for _, granted := range permissions {
+			if strings.EqualFold(string(granted), string(permission)) {
+				mask |= 1 << idx
+				break
+			}
+		}
+	}
+	return mask
+}
+
+func maskPermissions(mask int) []enums.Permission {
+	result := make([]enums.Permission, 0, len(enums.Permissions))
+	for idx, permission := range enums.Permissions {
+		if mask&(1<<idx) != 0 {
+			result = append(result, permission)
+		}
+	}
+	return result
+}
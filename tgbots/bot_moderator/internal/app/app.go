@@ -6,11 +6,11 @@ import (
 	"fmt"
 	"log/slog"
 	"strings"
-	"sync"
 	"time"
 
 	"bot_moderator/internal/config"
 	s3infra "bot_moderator/internal/infra/s3"
+	"bot_moderator/internal/infra/statestore"
 	"bot_moderator/internal/infra/telegram"
 	"bot_moderator/internal/repo/adminhttp"
 	"bot_moderator/internal/repo/dualrepo"
@@ -23,31 +23,31 @@ import (
 	"bot_moderator/internal/services/moderation"
 	statssvc "bot_moderator/internal/services/stats"
 	systemsvc "bot_moderator/internal/services/system"
+	workloadsvc "bot_moderator/internal/services/workload"
+	"github.com/redis/go-redis/v9"
 )
 
 type App struct {
 	cfg    config.Config
 	logger *slog.Logger
 	db     *sql.DB
+	redis  *redis.Client
 	tg     *telegram.Client
 
 	accessService     *access.Service
 	moderationService *moderation.Service
+	reviewService     *moderation.ReviewService
+	rejectReasons     *moderation.RejectReasonCatalog
 	lookupService     *lookup.Service
 	bansService       *bans.Service
 	auditService      *audit.Service
 	exportService     *exportsvc.Service
+	exportWorker      *exportsvc.Worker
 	statsService      *statssvc.Service
 	systemService     *systemsvc.Service
+	workloadService   *workloadsvc.Service
 
-	rejectMu     sync.Mutex
-	rejectByChat map[int64]rejectCommentState
-
-	lookupInputMu     sync.Mutex
-	lookupInputByChat map[int64]lookupInputState
-
-	lookupSessionMu     sync.Mutex
-	lookupSessionByChat map[int64]lookupSessionState
+	stateStore statestore.Store
 }
 
 func New(cfg config.Config, logger *slog.Logger) (*App, error) {
@@ -63,15 +63,26 @@ func New(cfg config.Config, logger *slog.Logger) (*App, error) {
 		db = nil
 	}
 
+	stateStore, redisClient, err := openStateStore(context.Background(), strings.TrimSpace(cfg.RedisAddr), time.Duration(cfg.StateTTLMinutes)*time.Minute)
+	if err != nil {
+		logger.Warn("redis unavailable, keeping conversation state in memory", "error", err)
+	}
+
 	botUsersRepo := postgres.NewBotUsersRepo(db)
 	botRolesRepo := postgres.NewBotRolesRepo(db)
 	moderationRepo := postgres.NewModerationRepo(db)
+	reviewsRepo := postgres.NewReviewsRepo(db, moderationRepo)
 	usersLookupRepo := postgres.NewUsersLookupRepo(db)
 	bansRepo := postgres.NewBansRepo(db)
 	auditRepo := postgres.NewAuditRepo(db)
 	exportsRepo := postgres.NewExportsQueueRepo(db)
 	workStatsRepo := postgres.NewWorkStatsRepo(db)
 	systemRepo := postgres.NewSystemRepo(db)
+	rejectReasonsRepo := postgres.NewRejectReasonsRepo(db)
+	roleDefinitionsRepo := postgres.NewRoleDefinitionsRepo(db)
+	workloadRepo := postgres.NewWorkloadRepo(db)
+	flagsRepo := postgres.NewFlagsRepo(db)
+	waitlistRepo := postgres.NewWaitlistRepo(db)
 
 	useHTTPRepos := adminMode == "http" || adminMode == "dual"
 	dualFallback := adminMode == "dual"
@@ -104,25 +115,37 @@ func New(cfg config.Config, logger *slog.Logger) (*App, error) {
 
 	var accessUsersRepo access.UsersRepo = botUsersRepo
 	var accessRolesRepo access.RolesRepo = botRolesRepo
+	var roleDefinitionsStore access.RoleDefinitionsRepo = roleDefinitionsRepo
 	var moderationServiceRepo moderation.Repo = dualrepo.NewModerationRepo(nil, moderationRepo, adminMode)
+	var reviewServiceRepo moderation.ReviewRepo = reviewsRepo
 	var lookupRepo lookup.Repo = usersLookupRepo
 	var bansServiceRepo bans.Repo = bansRepo
 	var auditServiceRepo audit.Repo = auditRepo
 	var exportServiceRepo exportsvc.Repo = exportsRepo
 	var statsServiceRepo statssvc.Repo = workStatsRepo
 	var systemServiceRepo systemsvc.Repo = systemRepo
+	var rejectReasonStore moderation.RejectReasonStore = rejectReasonsRepo
+	var workloadServiceRepo workloadsvc.Repo = workloadRepo
+	var flagsServiceRepo systemsvc.FlagsRepo = flagsRepo
+	var waitlistServiceRepo systemsvc.WaitlistRepo = waitlistRepo
 
 	if useHTTPRepos {
 		accessUsersRepo = adminhttp.NewAccessUsersRepo(adminHTTPClient, botUsersRepo, dualFallback)
 		accessRolesRepo = adminhttp.NewAccessRolesRepo(adminHTTPClient, botRolesRepo, dualFallback)
+		roleDefinitionsStore = adminhttp.NewRoleDefinitionsRepo(adminHTTPClient, roleDefinitionsRepo, dualFallback)
 		httpModerationRepo := adminhttp.NewModerationRepo(adminHTTPClient, moderationRepo, dualFallback)
 		moderationServiceRepo = dualrepo.NewModerationRepo(httpModerationRepo, moderationRepo, adminMode)
+		reviewServiceRepo = adminhttp.NewReviewsRepo(adminHTTPClient, reviewsRepo, dualFallback)
 		lookupRepo = adminhttp.NewUsersLookupRepo(adminHTTPClient, usersLookupRepo, dualFallback)
 		bansServiceRepo = adminhttp.NewBansRepo(adminHTTPClient, bansRepo, dualFallback)
 		auditServiceRepo = adminhttp.NewAuditRepo(adminHTTPClient, auditRepo, dualFallback)
 		exportServiceRepo = adminhttp.NewExportsQueueRepo(adminHTTPClient, exportsRepo, dualFallback)
 		statsServiceRepo = adminhttp.NewWorkStatsRepo(adminHTTPClient, workStatsRepo, dualFallback)
 		systemServiceRepo = adminhttp.NewSystemRepo(adminHTTPClient, systemRepo, dualFallback)
+		rejectReasonStore = adminhttp.NewRejectReasonsRepo(adminHTTPClient, rejectReasonsRepo, dualFallback)
+		workloadServiceRepo = adminhttp.NewWorkloadRepo(adminHTTPClient, workloadRepo, dualFallback)
+		flagsServiceRepo = adminhttp.NewFlagsRepo(adminHTTPClient, flagsRepo, dualFallback)
+		waitlistServiceRepo = adminhttp.NewWaitlistRepo(adminHTTPClient, waitlistRepo, dualFallback)
 	}
 
 	var signer *s3infra.Signer
@@ -135,36 +158,70 @@ func New(cfg config.Config, logger *slog.Logger) (*App, error) {
 		logger.Warn("s3 signer is disabled: missing S3_ENDPOINT or S3_BUCKET")
 	}
 
+	rejectReasons := moderation.NewRejectReasonCatalog(rejectReasonStore)
 	app := &App{
-		cfg:                 cfg,
-		logger:              logger,
-		db:                  db,
-		accessService:       access.NewService(cfg.OwnerTGID, accessUsersRepo, accessRolesRepo),
-		moderationService:   moderation.NewService(moderationServiceRepo, signer),
-		lookupService:       lookup.NewService(lookupRepo, signer),
-		bansService:         bans.NewService(bansServiceRepo),
-		auditService:        audit.NewService(auditServiceRepo),
-		exportService:       exportsvc.NewService(exportServiceRepo),
-		statsService:        statssvc.NewService(statsServiceRepo),
-		systemService:       systemsvc.NewService(systemServiceRepo),
-		rejectByChat:        make(map[int64]rejectCommentState),
-		lookupInputByChat:   make(map[int64]lookupInputState),
-		lookupSessionByChat: make(map[int64]lookupSessionState),
+		cfg:               cfg,
+		logger:            logger,
+		db:                db,
+		accessService:     access.NewService(cfg.OwnerTGID, accessUsersRepo, accessRolesRepo, roleDefinitionsStore),
+		moderationService: moderation.NewService(moderationServiceRepo, signer),
+		reviewService:     moderation.NewReviewService(reviewServiceRepo, signer),
+		rejectReasons:     rejectReasons,
+		lookupService:     lookup.NewService(lookupRepo, signer),
+		bansService:       bans.NewService(bansServiceRepo),
+		auditService:      audit.NewService(auditServiceRepo),
+		exportService:     exportsvc.NewService(exportServiceRepo),
+		statsService:      statssvc.NewService(statsServiceRepo),
+		systemService:     systemsvc.NewService(systemServiceRepo),
+		workloadService:   workloadsvc.NewService(workloadServiceRepo),
+		redis:             redisClient,
+		stateStore:        stateStore,
 	}
 
+	app.moderationService.AttachRejectReasons(rejectReasons)
+	app.reviewService.AttachRejectReasons(rejectReasons)
+	app.systemService.AttachFlags(flagsServiceRepo)
+	app.systemService.AttachWaitlist(waitlistServiceRepo)
+
 	app.tg, err = telegram.NewClient(cfg.BotToken, cfg.PollTimeoutSeconds, logger, app.routeUpdate)
 	if err != nil {
-		if db != nil {
-			_ = db.Close()
-		}
+		app.close()
 		return nil, fmt.Errorf("create telegram client: %w", err)
 	}
 
+	exportSink, err := app.buildExportSink()
+	if err != nil {
+		logger.Warn("export sink unavailable, export worker disabled", "error", err)
+		exportSink = nil
+	}
+	if exportSink != nil && db == nil {
+		logger.Warn("export worker requires postgres, export worker disabled")
+		exportSink = nil
+	}
+	app.exportWorker = exportsvc.NewWorker(exportsRepo, exportSink, exportsvc.WorkerConfig{
+		BatchSize:   cfg.ExportBatchSize,
+		MaxAttempts: cfg.ExportMaxAttempts,
+	})
+
 	return app, nil
 }
 
 func (a *App) Run(ctx context.Context) error {
 	defer a.close()
+	go a.runSLAAlerts(ctx)
+	go a.runWorkloadBalancer(ctx)
+	go a.runExportWorker(ctx)
+	if a.cfg.IsWebhookEnabled() {
+		if a.redis == nil {
+			a.logger.Warn("webhook mode without redis: update dedup and chat state are local to this replica")
+		}
+		return a.tg.StartWebhook(ctx, telegram.WebhookConfig{
+			ListenAddr: a.cfg.WebhookListenAddr,
+			Path:       a.cfg.WebhookPath,
+			PublicURL:  a.cfg.WebhookURL,
+			Secret:     a.cfg.WebhookSecret,
+		})
+	}
 	return a.tg.Start(ctx)
 }
 
@@ -174,6 +231,11 @@ func (a *App) close() {
 			a.logger.Error("close postgres", "error", err)
 		}
 	}
+	if a.redis != nil {
+		if err := a.redis.Close(); err != nil {
+			a.logger.Error("close redis", "error", err)
+		}
+	}
 }
 
 func normalizeAdminMode(raw string) string {
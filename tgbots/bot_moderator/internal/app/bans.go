@@ -0,0 +1,115 @@
+package app
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"bot_moderator/internal/domain/enums"
+	"bot_moderator/internal/domain/model"
+	"bot_moderator/internal/infra/statestore"
+	"bot_moderator/internal/infra/telegram"
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+)
+
+func (a *App) sendBanReasonPrompt(chatID int64, userID int64) {
+	rows := make([][]telegram.InlineButton, 0, len(enums.BanReasons)+1)
+	for _, reason := range enums.BanReasons {
+		rows = append(rows, []telegram.InlineButton{{
+			Text: reason.Label(),
+			Data: fmt.Sprintf("%s:banr:%d:%s", callbackPrefixLookup, userID, reason),
+		}})
+	}
+	rows = append(rows, []telegram.InlineButton{{
+		Text: "⬅️ Back",
+		Data: fmt.Sprintf("%s:no:%s:%d", callbackPrefixLookup, lookupActionBan, userID),
+	}})
+	a.sendInline(chatID, fmt.Sprintf("Причина блокировки user_id=%d", userID), rows)
+}
+
+func (a *App) sendBanDurationPrompt(chatID int64, userID int64, reason enums.BanReason) {
+	buttons := make([]telegram.InlineButton, 0, len(enums.BanDurations))
+	for _, duration := range enums.BanDurations {
+		buttons = append(buttons, telegram.InlineButton{
+			Text: duration.Label(),
+			Data: fmt.Sprintf("%s:band:%d:%s:%s", callbackPrefixLookup, userID, reason, duration),
+		})
+	}
+	rows := [][]telegram.InlineButton{
+		buttons,
+		{{Text: "⬅️ Back", Data: fmt.Sprintf("%s:yes:%s:%d", callbackPrefixLookup, lookupActionBan, userID)}},
+	}
+	a.sendInline(chatID, fmt.Sprintf("Срок блокировки user_id=%d (%s)", userID, reason.Label()), rows)
+}
+
+// handleBanPickCallback walks reason (find:banr:USER:CODE) -> duration (find:band:USER:CODE:DUR) -> comment.
+func (a *App) handleBanPickCallback(ctx context.Context, chatID int64, actorTGID int64, actorRole enums.Role, parts []string) (string, bool) {
+	if len(parts) < 4 {
+		return "", false
+	}
+	userID, err := parseTGID(parts[2])
+	if err != nil || userID <= 0 {
+		return "Некорректный user id", true
+	}
+	reason, ok := enums.ParseBanReason(parts[3])
+	if !ok {
+		return "Некорректная причина", true
+	}
+
+	if parts[1] == "banr" {
+		a.sendBanDurationPrompt(chatID, userID, reason)
+		return "Выберите срок", false
+	}
+
+	if len(parts) < 5 {
+		return "", false
+	}
+	duration, ok := enums.ParseBanDuration(parts[4])
+	if !ok {
+		return "Некорректный срок", true
+	}
+	if !a.enterChatState(ctx, chatID, telegram.StateWaitingBanReason, actorTGID, actorRole, func(session *statestore.Session) {
+		session.TargetUserID = userID
+		session.BanReasonCode = string(reason)
+		session.BanDuration = string(duration)
+	}) {
+		return chatStateBusyText, true
+	}
+	a.sendText(chatID, fmt.Sprintf("Комментарий к блокировке user_id=%d (%s, %s) или '-' без комментария", userID, reason.Label(), duration.Label()))
+	return "Ожидаю комментарий", false
+}
+
+func (a *App) handleBanReasonInput(ctx context.Context, message *tgbotapi.Message, session statestore.Session) {
+	comment := strings.TrimSpace(message.Text)
+	if comment == "-" {
+		comment = ""
+	}
+	ban := model.BanInput{
+		ReasonCode: session.BanReasonCode,
+		Reason:     comment,
+		Duration:   session.BanDuration,
+	}
+	// Sessions saved before reason codes existed carry only the target user.
+	if ban.ReasonCode == "" {
+		ban.ReasonCode = string(enums.BanReasonOther)
+	}
+	if ban.Duration == "" {
+		ban.Duration = string(enums.BanDurationPermanent)
+	}
+
+	a.resetChatState(ctx, message.Chat.ID, session)
+
+	if err := a.executeLookupAction(ctx, message.Chat.ID, session.ActorTGID, session.ActorRole, lookupActionBan, session.TargetUserID, ban); err != nil {
+		a.logger.Warn("ban with reason", "error", err, "user_id", session.TargetUserID, "tg_id", session.ActorTGID)
+		a.sendText(message.Chat.ID, "Не удалось заблокировать пользователя")
+	}
+}
+
+func renderBanApplied(userID int64, ban model.BanInput, state model.BanState) string {
+	until := "навсегда"
+	if state.ExpiresAt != nil {
+		until = "до " + state.ExpiresAt.UTC().Format(time.RFC3339)
+	}
+	return fmt.Sprintf("Пользователь user_id=%d заблокирован (%s), %s", userID, enums.BanReason(ban.ReasonCode).Label(), until)
+}
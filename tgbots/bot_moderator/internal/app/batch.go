@@ -0,0 +1,417 @@
+package app
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"bot_moderator/internal/domain/enums"
+	"bot_moderator/internal/domain/model"
+	"bot_moderator/internal/infra/statestore"
+	"bot_moderator/internal/infra/telegram"
+	"bot_moderator/internal/repo/postgres"
+	moderationsvc "bot_moderator/internal/services/moderation"
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+)
+
+// Telegram rejects album captions longer than this.
+const batchCaptionMaxRunes = 1024
+
+var batchSizes = []int{5, model.MaxModerationBatchSize}
+
+func (a *App) handleBatchModerationEntry(ctx context.Context, message *tgbotapi.Message) {
+	if message == nil || message.From == nil {
+		return
+	}
+
+	_, role, err := a.resolveActorRole(ctx, message.From)
+	if err != nil {
+		a.logger.Warn("resolve actor role for batch moderation", "error", err, "tg_id", message.From.ID)
+		a.sendText(message.Chat.ID, "Не удалось определить роль")
+		return
+	}
+	if !a.accessService.Can(ctx, role, enums.PermissionModerationDecide) {
+		a.sendText(message.Chat.ID, "Dating App: нет доступа")
+		return
+	}
+
+	a.sendBatchSizePrompt(message.Chat.ID, "Пакетная модерация: сколько анкет взять?")
+}
+
+// Batch callbacks: bat:start:N acquires a batch, bat:a|v|r:ID decide one item, bat:rr:ID:CODE rejects
+// with a catalog reason and bat:all:BATCH → bat:allok:BATCH approves every undecided item of the batch.
+func (a *App) handleBatchCallback(ctx context.Context, chatID int64, query *tgbotapi.CallbackQuery, parts []string) (string, bool) {
+	actorTGID, actorRole, err := a.resolveActorRole(ctx, query.From)
+	if err != nil {
+		return "Не удалось определить роль", true
+	}
+	if !a.accessService.Can(ctx, actorRole, enums.PermissionModerationDecide) {
+		return "Нет доступа", true
+	}
+
+	switch parts[1] {
+	case "start":
+		if len(parts) < 3 {
+			return "", false
+		}
+		size, err := strconv.Atoi(parts[2])
+		if err != nil {
+			return "Некорректный размер пакета", true
+		}
+		return a.startModerationBatch(ctx, chatID, actorTGID, actorRole, size)
+	case "a", "v", "r", "rr", "d":
+		if len(parts) < 3 {
+			return "", false
+		}
+		itemID, err := parseTGID(parts[2])
+		if err != nil {
+			return "Некорректный item id", true
+		}
+		session, ok := a.loadChatState(ctx, chatID)
+		if !ok || session.ActorTGID != actorTGID || !batchHasItem(session, itemID) {
+			return "Анкета уже обработана", true
+		}
+
+		switch parts[1] {
+		case "a", "v":
+			decision := model.ModerationBatchApprove
+			if parts[1] == "v" {
+				decision = model.ModerationBatchApproveVerified
+			}
+			return a.applyBatchDecisions(ctx, chatID, actorTGID, actorRole, session.BatchID, false, []moderationsvc.BatchDecisionInput{{
+				ModerationItemID: itemID,
+				Decision:         decision,
+			}})
+		case "r":
+			rows, ok := a.rejectReasonRows(ctx, func(code string) string {
+				return fmt.Sprintf("%s:rr:%d:%s", callbackPrefixBatch, itemID, code)
+			})
+			if !ok {
+				return "Не удалось загрузить причины отказа", true
+			}
+			rows = append(rows, []telegram.InlineButton{{
+				Text: "⬅️ Back",
+				Data: fmt.Sprintf("%s:d:%d", callbackPrefixBatch, itemID),
+			}})
+			a.sendInline(chatID, fmt.Sprintf("Анкета #%d: выберите причину отклонения", itemID), rows)
+			return "Выберите причину", false
+		case "rr":
+			if len(parts) < 4 {
+				return "", false
+			}
+			reasonCode := normalizeReasonCode(parts[3])
+			if !a.isActiveRejectReason(ctx, reasonCode) {
+				return "Некорректный reason code", true
+			}
+			if reasonCode == enums.RejectReasonOther {
+				if !a.enterChatState(ctx, chatID, telegram.StateWaitingRejectReason, actorTGID, actorRole, func(session *statestore.Session) {
+					session.ItemID = itemID
+				}) {
+					return chatStateBusyText, true
+				}
+				a.sendText(chatID, "Введите комментарий для причины OTHER (или '-' без комментария)")
+				return "Ожидаю комментарий", false
+			}
+			return a.applyBatchDecisions(ctx, chatID, actorTGID, actorRole, session.BatchID, false, []moderationsvc.BatchDecisionInput{{
+				ModerationItemID: itemID,
+				Decision:         model.ModerationBatchReject,
+				ReasonCode:       reasonCode,
+			}})
+		default:
+			a.sendInline(chatID, fmt.Sprintf("Анкета #%d", itemID), batchItemRows(itemID))
+			return "", false
+		}
+	case "all", "allok":
+		if len(parts) < 3 {
+			return "", false
+		}
+		session, ok := a.loadChatState(ctx, chatID)
+		if !ok || session.ActorTGID != actorTGID || session.BatchID != parts[2] || len(session.BatchItemIDs) == 0 {
+			return "Пакет уже обработан", true
+		}
+		if parts[1] == "all" {
+			ids := make([]string, 0, len(session.BatchItemIDs))
+			for _, itemID := range session.BatchItemIDs {
+				ids = append(ids, fmt.Sprintf("#%d", itemID))
+			}
+			a.sendInline(chatID, fmt.Sprintf("Одобрить все анкеты пакета (%d)?\n%s", len(ids), strings.Join(ids, ", ")), [][]telegram.InlineButton{{
+				{Text: "✅ Да, одобрить", Data: fmt.Sprintf("%s:allok:%s", callbackPrefixBatch, session.BatchID)},
+				{Text: "Отмена", Data: fmt.Sprintf("%s:no", callbackPrefixBatch)},
+			}})
+			return "", false
+		}
+
+		decisions := make([]moderationsvc.BatchDecisionInput, 0, len(session.BatchItemIDs))
+		for _, itemID := range session.BatchItemIDs {
+			decisions = append(decisions, moderationsvc.BatchDecisionInput{
+				ModerationItemID: itemID,
+				Decision:         model.ModerationBatchApprove,
+			})
+		}
+		return a.applyBatchDecisions(ctx, chatID, actorTGID, actorRole, session.BatchID, true, decisions)
+	case "no":
+		return "Отменено", false
+	default:
+		return "", false
+	}
+}
+
+func (a *App) handleBatchRejectCommentInput(ctx context.Context, message *tgbotapi.Message, session statestore.Session) {
+	comment := strings.TrimSpace(message.Text)
+	if comment == "-" {
+		comment = ""
+	}
+
+	a.resetChatState(ctx, message.Chat.ID, session)
+
+	text, _ := a.applyBatchDecisions(ctx, message.Chat.ID, session.ActorTGID, session.ActorRole, session.BatchID, false, []moderationsvc.BatchDecisionInput{{
+		ModerationItemID: session.ItemID,
+		Decision:         model.ModerationBatchReject,
+		ReasonCode:       enums.RejectReasonOther,
+		Comment:          comment,
+	}})
+	if text != "" {
+		a.sendText(message.Chat.ID, text)
+	}
+}
+
+func (a *App) startModerationBatch(ctx context.Context, chatID int64, actorTGID int64, actorRole enums.Role, size int) (string, bool) {
+	items, err := a.moderationService.AcquireBatch(ctx, actorTGID, size)
+	if errors.Is(err, moderationsvc.ErrQueueEmpty) {
+		a.sendText(chatID, "Очередь пуста")
+		return "", false
+	}
+	if errors.Is(err, moderationsvc.ErrInvalidBatch) {
+		return "Некорректный размер пакета", true
+	}
+	if err != nil {
+		a.logger.Warn("acquire moderation batch", "error", err, "tg_id", actorTGID, "size", size)
+		return "Не удалось получить пакет анкет", true
+	}
+
+	session, ok := a.loadChatState(ctx, chatID)
+	if !ok || session.ActorTGID != actorTGID {
+		session = statestore.Session{State: telegram.StateIdle, ActorTGID: actorTGID, ActorRole: actorRole}
+	}
+	session.BatchID = strconv.FormatInt(time.Now().UnixNano(), 36)
+	session.BatchItemIDs = make([]int64, 0, len(items))
+	for _, item := range items {
+		session.BatchItemIDs = append(session.BatchItemIDs, item.ModerationItemID)
+	}
+	a.saveChatState(ctx, chatID, session)
+
+	for _, item := range items {
+		a.sendBatchItem(chatID, item)
+	}
+	a.sendInline(chatID, fmt.Sprintf("Пакет: %d анкет. Решайте по каждой или одобрите все сразу.", len(items)), [][]telegram.InlineButton{
+		{{Text: fmt.Sprintf("✅ Одобрить все (%d)", len(items)), Data: fmt.Sprintf("%s:all:%s", callbackPrefixBatch, session.BatchID)}},
+	})
+	return fmt.Sprintf("Взято анкет: %d", len(items)), false
+}
+
+// sendBatchItem sends the profile as one album with the card as its caption, followed by compact decision buttons.
+func (a *App) sendBatchItem(chatID int64, item model.ModerationQueueItem) {
+	caption := renderBatchCaption(item)
+
+	photos := make([]string, 0, len(item.PhotoURLs))
+	for _, mediaURL := range item.PhotoURLs {
+		if url := strings.TrimSpace(mediaURL); url != "" {
+			photos = append(photos, url)
+		}
+	}
+	circleURL := strings.TrimSpace(item.CircleURL)
+
+	var err error
+	switch {
+	case len(photos) == 0 && circleURL == "":
+		a.sendText(chatID, caption+"\nМедиа не найдены")
+	case len(photos) == 0:
+		err = a.sendVideoByURL(chatID, circleURL, caption)
+	case len(photos) == 1 && circleURL == "":
+		// Albums need at least two entries.
+		err = a.sendPhotoByURL(chatID, photos[0], caption)
+	default:
+		media := make([]interface{}, 0, len(photos)+1)
+		for idx, url := range photos {
+			photo := tgbotapi.NewInputMediaPhoto(tgbotapi.FileURL(url))
+			if idx == 0 {
+				photo.Caption = caption
+			}
+			media = append(media, photo)
+		}
+		if circleURL != "" {
+			media = append(media, tgbotapi.NewInputMediaVideo(tgbotapi.FileURL(circleURL)))
+		}
+		err = a.tg.SendMediaGroup(tgbotapi.NewMediaGroup(chatID, media))
+	}
+	if err != nil {
+		a.logger.Warn("send batch album", "error", err, "chat_id", chatID, "item_id", item.ModerationItemID)
+		a.sendText(chatID, caption)
+	}
+
+	a.sendInline(chatID, fmt.Sprintf("Анкета #%d", item.ModerationItemID), batchItemRows(item.ModerationItemID))
+}
+
+// applyBatchDecisions commits decisions in one transaction; if any item was taken or decided elsewhere
+// nothing is applied and the moderator is asked to decide the rest one by one.
+func (a *App) applyBatchDecisions(
+	ctx context.Context,
+	chatID int64,
+	actorTGID int64,
+	actorRole enums.Role,
+	batchID string,
+	bulk bool,
+	decisions []moderationsvc.BatchDecisionInput,
+) (string, bool) {
+	results, err := a.moderationService.DecideBatch(ctx, moderationsvc.DecideBatchInput{
+		ActorTGID: actorTGID,
+		ActorRole: actorRole,
+		BatchID:   batchID,
+		Bulk:      bulk,
+		Decisions: decisions,
+	})
+	if err != nil && len(results) == 0 {
+		switch {
+		case errors.Is(err, moderationsvc.ErrCircleMissing):
+			return "Нет кружка для верификации", true
+		case errors.Is(err, postgres.ErrModerationItemNotPending), errors.Is(err, postgres.ErrModerationItemNotFound):
+			if bulk {
+				return "Часть анкет уже обработана, пакет не применён. Решите по одной.", true
+			}
+			a.dropBatchItems(ctx, chatID, actorTGID, decisions[0].ModerationItemID)
+			return "Анкета уже обработана", true
+		case errors.Is(err, postgres.ErrModerationItemLocked):
+			return itemLockedText, true
+		default:
+			a.logger.Warn("decide moderation batch", "error", err, "batch_id", batchID, "tg_id", actorTGID)
+			return "Не удалось применить решение", true
+		}
+	}
+	if err != nil {
+		a.logger.Warn("record moderation batch actions", "error", err, "batch_id", batchID, "tg_id", actorTGID)
+	}
+
+	decided := make([]int64, 0, len(results))
+	for _, result := range results {
+		decided = append(decided, result.ModerationItemID)
+		if result.Decision == "REJECT" {
+			if err := a.auditService.LogModerationReject(ctx, actorTGID, result.TargetUserID, result.ModerationItemID, result.ReasonCode); err != nil {
+				a.logger.Warn("write moderation reject audit", "error", err)
+			}
+			continue
+		}
+		if err := a.auditService.LogModerationApprove(ctx, actorTGID, result.TargetUserID, result.ModerationItemID, result.Verified); err != nil {
+			a.logger.Warn("write moderation approve audit", "error", err)
+		}
+	}
+
+	ack := fmt.Sprintf("Одобрено анкет: %d", len(results))
+	if !bulk && len(results) == 1 {
+		ack = renderBatchResult(results[0])
+	}
+	if a.dropBatchItems(ctx, chatID, actorTGID, decided...) == 0 {
+		a.sendBatchSizePrompt(chatID, "Пакет обработан. Взять следующий?")
+	}
+	return ack, false
+}
+
+// dropBatchItems removes decided items from the open batch and returns how many are left.
+func (a *App) dropBatchItems(ctx context.Context, chatID int64, actorTGID int64, itemIDs ...int64) int {
+	session, ok := a.loadChatState(ctx, chatID)
+	if !ok || session.ActorTGID != actorTGID {
+		return 0
+	}
+
+	remaining := session.BatchItemIDs[:0]
+	for _, itemID := range session.BatchItemIDs {
+		drop := false
+		for _, decided := range itemIDs {
+			if itemID == decided {
+				drop = true
+				break
+			}
+		}
+		if !drop {
+			remaining = append(remaining, itemID)
+		}
+	}
+	session.BatchItemIDs = remaining
+	if len(remaining) == 0 {
+		session.BatchID = ""
+		session.BatchItemIDs = nil
+	}
+	a.saveChatState(ctx, chatID, session)
+	return len(remaining)
+}
+
+func (a *App) sendBatchSizePrompt(chatID int64, text string) {
+	row := make([]telegram.InlineButton, 0, len(batchSizes))
+	for _, size := range batchSizes {
+		row = append(row, telegram.InlineButton{
+			Text: fmt.Sprintf("%d анкет", size),
+			Data: fmt.Sprintf("%s:start:%d", callbackPrefixBatch, size),
+		})
+	}
+	a.sendInline(chatID, text, [][]telegram.InlineButton{row})
+}
+
+func batchItemRows(moderationItemID int64) [][]telegram.InlineButton {
+	return [][]telegram.InlineButton{{
+		{Text: "✅", Data: fmt.Sprintf("%s:a:%d", callbackPrefixBatch, moderationItemID)},
+		{Text: "🪪", Data: fmt.Sprintf("%s:v:%d", callbackPrefixBatch, moderationItemID)},
+		{Text: "❌", Data: fmt.Sprintf("%s:r:%d", callbackPrefixBatch, moderationItemID)},
+	}}
+}
+
+func batchHasItem(session statestore.Session, moderationItemID int64) bool {
+	if moderationItemID == 0 {
+		return false
+	}
+	for _, itemID := range session.BatchItemIDs {
+		if itemID == moderationItemID {
+			return true
+		}
+	}
+	return false
+}
+
+func renderBatchResult(result moderationsvc.BatchDecisionResult) string {
+	switch {
+	case result.Decision == "REJECT":
+		return fmt.Sprintf("Анкета #%d отклонена (%s)", result.ModerationItemID, result.ReasonCode)
+	case result.Verified:
+		return fmt.Sprintf("Анкета #%d одобрена, верифицирован", result.ModerationItemID)
+	default:
+		return fmt.Sprintf("Анкета #%d одобрена", result.ModerationItemID)
+	}
+}
+
+func renderBatchCaption(item model.ModerationQueueItem) string {
+	profile := item.Profile
+	age := "-"
+	if profile.Age > 0 {
+		age = strconv.Itoa(profile.Age)
+	}
+	goals := "-"
+	if len(profile.Goals) > 0 {
+		goals = strings.Join(profile.Goals, ", ")
+	}
+
+	lines := []string{
+		fmt.Sprintf("#%d · user_id %d · @%s", item.ModerationItemID, profile.UserID, defaultText(profile.Username, "-")),
+		fmt.Sprintf("%s, %s · %s", age, defaultText(profile.Gender, "-"), defaultText(profile.CityID, "-")),
+		fmt.Sprintf("ищет: %s · цели: %s", defaultText(profile.LookingFor, "-"), goals),
+		fmt.Sprintf("%s · %s", defaultText(profile.Occupation, "-"), defaultText(profile.Education, "-")),
+		fmt.Sprintf("verification: %s", defaultText(profile.VerificationStatus, "-")),
+	}
+	lines = append(lines, renderPhotoMatches(item.PhotoMatches)...)
+
+	caption := []rune(strings.Join(lines, "\n"))
+	if len(caption) > batchCaptionMaxRunes {
+		caption = append(caption[:batchCaptionMaxRunes-1], '…')
+	}
+	return string(caption)
+}
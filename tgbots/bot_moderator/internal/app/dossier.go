@@ -0,0 +1,97 @@
+package app
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+
+	"bot_moderator/internal/domain/enums"
+	"bot_moderator/internal/domain/model"
+	"bot_moderator/internal/infra/telegram"
+	"bot_moderator/internal/services/lookup"
+	"bot_moderator/internal/ui"
+)
+
+const lookupActionDossier = "DOSSIER"
+
+// handleDossierCallback serves find:dos:UID:SECTION:OFFSET.
+func (a *App) handleDossierCallback(ctx context.Context, chatID int64, actorTGID int64, actorRole enums.Role, parts []string) (string, bool) {
+	if len(parts) < 5 {
+		return "", false
+	}
+	userID, err := parseTGID(parts[2])
+	if err != nil || userID <= 0 {
+		return "Некорректный user id", true
+	}
+	section, ok := enums.ParseDossierSection(parts[3])
+	if !ok {
+		return "Некорректный раздел", true
+	}
+	offset, err := strconv.Atoi(parts[4])
+	if err != nil || offset < 0 {
+		return "Некорректная страница", true
+	}
+
+	dossier, err := a.lookupService.Dossier(ctx, model.DossierQuery{
+		UserID:  userID,
+		Section: string(section),
+		Limit:   lookup.DossierPageSize,
+		Offset:  offset,
+	})
+	if err != nil {
+		if errors.Is(err, lookup.ErrUserNotFound) {
+			return "Пользователь не найден", true
+		}
+		a.logger.Warn("load dossier", "error", err, "user_id", userID, "section", section, "tg_id", actorTGID)
+		return "Не удалось загрузить досье", true
+	}
+
+	if offset == 0 {
+		if err := a.lookupService.LogAction(ctx, actorTGID, actorRole, strconv.FormatInt(userID, 10), &userID, lookupActionDossier, map[string]interface{}{
+			"target_user_id": userID,
+			"section":        string(section),
+		}); err != nil {
+			a.logger.Warn("log dossier action", "error", err, "user_id", userID, "tg_id", actorTGID)
+		}
+	}
+
+	a.sendInline(chatID, ui.RenderDossier(dossier), dossierKeyboard(dossier, section))
+	return "", false
+}
+
+func dossierKeyboard(dossier model.Dossier, section enums.DossierSection) [][]telegram.InlineButton {
+	data := func(target enums.DossierSection, offset int) string {
+		return fmt.Sprintf("%s:dos:%d:%s:%d", callbackPrefixLookup, dossier.UserID, target, offset)
+	}
+
+	rows := make([][]telegram.InlineButton, 0, 5)
+	nav := make([]telegram.InlineButton, 0, 2)
+	if dossier.Offset > 0 {
+		nav = append(nav, telegram.InlineButton{Text: "◀️ Назад", Data: data(section, max(dossier.Offset-dossier.Limit, 0))})
+	}
+	if dossier.Offset+dossier.Limit < dossier.PageTotal() {
+		nav = append(nav, telegram.InlineButton{Text: "Вперёд ▶️", Data: data(section, dossier.Offset+dossier.Limit)})
+	}
+	if len(nav) > 0 {
+		rows = append(rows, nav)
+	}
+
+	tabs := make([]telegram.InlineButton, 0, len(enums.DossierSections))
+	for _, target := range enums.DossierSections {
+		if target == section {
+			continue
+		}
+		tabs = append(tabs, telegram.InlineButton{Text: target.Label(), Data: data(target, 0)})
+	}
+	for len(tabs) > 0 {
+		n := min(3, len(tabs))
+		rows = append(rows, tabs[:n])
+		tabs = tabs[n:]
+	}
+
+	rows = append(rows, []telegram.InlineButton{
+		{Text: "⬅️ К карточке", Data: fmt.Sprintf("%s:card:%d", callbackPrefixLookup, dossier.UserID)},
+	})
+	return rows
+}
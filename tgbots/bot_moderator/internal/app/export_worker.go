@@ -0,0 +1,67 @@
+package app
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	exportsvc "bot_moderator/internal/services/export"
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+)
+
+func (a *App) buildExportSink() (exportsvc.Sink, error) {
+	switch a.cfg.ExportSink {
+	case "":
+		return nil, nil
+	case "sheets":
+		return exportsvc.NewSheetsSink(
+			a.cfg.SheetsAPIURL,
+			a.cfg.SheetsSpreadsheetID,
+			a.cfg.SheetsRange,
+			a.cfg.SheetsAccessToken,
+			time.Duration(a.cfg.AdminHTTPTimeout)*time.Second,
+		)
+	case "csv":
+		return exportsvc.NewCSVSink(a.cfg.ExportCSVDir)
+	case "telegram":
+		return exportsvc.NewTelegramSink(a, a.cfg.OwnerTGID)
+	default:
+		return nil, fmt.Errorf("unsupported EXPORT_SINK %q", a.cfg.ExportSink)
+	}
+}
+
+// runExportWorker drains the exports queue into the configured sink until ctx is done.
+func (a *App) runExportWorker(ctx context.Context) {
+	if !a.exportWorker.Enabled() {
+		return
+	}
+
+	interval := time.Duration(a.cfg.ExportPollSeconds) * time.Second
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		for {
+			delivered, err := a.exportWorker.ProcessOnce(ctx)
+			if err != nil {
+				a.logger.Warn("deliver export batch", "error", err, "sink", a.exportWorker.SinkName())
+				break
+			}
+			if delivered == 0 || ctx.Err() != nil {
+				break
+			}
+		}
+	}
+}
+
+func (a *App) SendDocument(_ context.Context, chatID int64, fileName string, data []byte, caption string) error {
+	document := tgbotapi.NewDocument(chatID, tgbotapi.FileBytes{Name: fileName, Bytes: data})
+	document.Caption = caption
+	return a.tg.Send(document)
+}
@@ -0,0 +1,311 @@
+package app
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+
+	"bot_moderator/internal/domain/enums"
+	"bot_moderator/internal/domain/model"
+	"bot_moderator/internal/infra/statestore"
+	"bot_moderator/internal/infra/telegram"
+	systemsvc "bot_moderator/internal/services/system"
+	"bot_moderator/internal/ui"
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+)
+
+const (
+	flagFieldNew   = "new"
+	flagFieldValue = "value"
+	flagFieldRules = "rules"
+	flagFieldDesc  = "desc"
+)
+
+const newFlagHelp = "Введите новый флаг одной строкой:\n" +
+	"ключ тип значение [описание]\n" +
+	"Например: travel_mode bool false Travel mode\n" +
+	"Тип: bool, int или string"
+
+const flagRulesHelp = "Введите правила, по одному на строку (первое совпавшее выигрывает):\n" +
+	"city=minsk,brest gender=female plus=true pct=20 users=1,2 => значение\n" +
+	"Все условия необязательны. «-» — удалить все правила."
+
+var flagFieldPrompts = map[string]string{
+	flagFieldValue: "Введите значение по умолчанию",
+	flagFieldRules: flagRulesHelp,
+	flagFieldDesc:  "Введите описание флага («-» — очистить)",
+}
+
+// flagMutations are the flag callbacks that change a flag and need flags.manage;
+// system.toggle_registration alone only lets a role browse the list.
+var flagMutations = map[string]bool{
+	"new":   true,
+	"tog":   true,
+	"vis":   true,
+	"edit":  true,
+	"del":   true,
+	"delok": true,
+}
+
+func (a *App) handleFlagCallback(ctx context.Context, chatID int64, query *tgbotapi.CallbackQuery, parts []string) (string, bool) {
+	actorTGID, actorRole, err := a.resolveActorRole(ctx, query.From)
+	if err != nil {
+		return "Не удалось определить роль", true
+	}
+	canManage := a.accessService.Can(ctx, actorRole, enums.PermissionFlagsManage)
+	if !canManage && !a.accessService.Can(ctx, actorRole, enums.PermissionSystemRegistration) {
+		return "Нет доступа", true
+	}
+	if flagMutations[parts[1]] && !canManage {
+		return "Нет доступа", true
+	}
+
+	switch parts[1] {
+	case "list":
+		a.sendFlagList(ctx, chatID)
+		return "", false
+	case "back":
+		a.clearChatState(ctx, chatID)
+		a.sendSystemScreen(ctx, chatID)
+		return "", false
+	case "new":
+		if !a.enterChatState(ctx, chatID, telegram.StateWaitingFlagInput, actorTGID, actorRole, func(session *statestore.Session) {
+			session.FlagKey = ""
+			session.FlagField = flagFieldNew
+		}) {
+			return chatStateBusyText, true
+		}
+		a.sendText(chatID, newFlagHelp)
+		return "Ожидаю флаг", false
+	}
+
+	if len(parts) < 3 {
+		return "", false
+	}
+	flag, err := a.systemService.GetFlag(ctx, parts[2])
+	if err != nil {
+		if errors.Is(err, systemsvc.ErrFlagNotFound) {
+			return "Флаг не найден", true
+		}
+		a.logger.Warn("load flag", "error", err, "key", parts[2])
+		return "Не удалось загрузить флаг", true
+	}
+
+	switch parts[1] {
+	case "view":
+		a.sendFlagCard(chatID, flag)
+		return "", false
+	case "tog":
+		if flag.Type != model.AppFlagTypeBool {
+			return "Переключать можно только bool", true
+		}
+		if string(flag.Value) == "true" {
+			flag.Value = json.RawMessage(`false`)
+		} else {
+			flag.Value = json.RawMessage(`true`)
+		}
+		return a.saveFlag(ctx, chatID, actorTGID, flag, flagFieldValue)
+	case "vis":
+		flag.ClientVisible = !flag.ClientVisible
+		return a.saveFlag(ctx, chatID, actorTGID, flag, "client_visible")
+	case "edit":
+		if len(parts) < 4 {
+			return "", false
+		}
+		prompt, ok := flagFieldPrompts[parts[3]]
+		if !ok {
+			return "Некорректное поле", true
+		}
+		if !a.enterChatState(ctx, chatID, telegram.StateWaitingFlagInput, actorTGID, actorRole, func(session *statestore.Session) {
+			session.FlagKey = flag.Key
+			session.FlagField = parts[3]
+		}) {
+			return chatStateBusyText, true
+		}
+		a.sendText(chatID, fmt.Sprintf("%s\nТип: %s", prompt, flag.Type))
+		return "Ожидаю значение", false
+	case "del":
+		rows := [][]telegram.InlineButton{{
+			{Text: "Да, удалить", Data: fmt.Sprintf("%s:delok:%s", callbackPrefixFlags, flag.Key)},
+			{Text: "Нет", Data: fmt.Sprintf("%s:view:%s", callbackPrefixFlags, flag.Key)},
+		}}
+		a.sendInline(chatID, fmt.Sprintf("Удалить флаг %s? Сервисы вернутся к значениям по умолчанию из кода.", flag.Key), rows)
+		return "", false
+	case "delok":
+		if err := a.systemService.DeleteFlag(ctx, flag.Key); err != nil {
+			if errors.Is(err, systemsvc.ErrBuiltinFlag) {
+				return "Этот флаг нельзя удалить", true
+			}
+			a.logger.Warn("delete flag", "error", err, "key", flag.Key, "tg_id", actorTGID)
+			return "Не удалось удалить флаг", true
+		}
+		if err := a.auditService.LogFlagDeleted(ctx, actorTGID, flag); err != nil {
+			a.logger.Warn("write flag delete audit", "error", err, "key", flag.Key, "tg_id", actorTGID)
+		}
+		a.sendFlagList(ctx, chatID)
+		return "Удалено", false
+	default:
+		return "", false
+	}
+}
+
+func (a *App) handleFlagInput(ctx context.Context, message *tgbotapi.Message, session statestore.Session) {
+	raw := strings.TrimSpace(message.Text)
+	if raw == "" {
+		a.sendText(message.Chat.ID, "Значение не может быть пустым")
+		return
+	}
+
+	var (
+		flag model.AppFlag
+		err  error
+	)
+	if session.FlagField == flagFieldNew {
+		flag, err = parseNewFlag(raw)
+		if err != nil {
+			a.sendText(message.Chat.ID, fmt.Sprintf("%v\n\n%s", err, newFlagHelp))
+			return
+		}
+		if _, err := a.systemService.GetFlag(ctx, flag.Key); err == nil {
+			a.sendText(message.Chat.ID, "Флаг с таким ключом уже есть, откройте его в списке")
+			return
+		}
+	} else {
+		flag, err = a.systemService.GetFlag(ctx, session.FlagKey)
+		if err != nil {
+			a.resetChatState(ctx, message.Chat.ID, session)
+			a.sendText(message.Chat.ID, "Флаг не найден")
+			return
+		}
+		if err := applyFlagField(&flag, session.FlagField, raw); err != nil {
+			a.sendText(message.Chat.ID, err.Error())
+			return
+		}
+	}
+
+	a.resetChatState(ctx, message.Chat.ID, session)
+	if text, failed := a.saveFlag(ctx, message.Chat.ID, session.ActorTGID, flag, session.FlagField); failed {
+		a.sendText(message.Chat.ID, text)
+	}
+}
+
+func (a *App) saveFlag(ctx context.Context, chatID int64, actorTGID int64, flag model.AppFlag, field string) (string, bool) {
+	saved, err := a.systemService.SaveFlag(ctx, actorTGID, flag)
+	if err != nil {
+		switch {
+		case errors.Is(err, systemsvc.ErrInvalidFlag):
+			return fmt.Sprintf("Некорректный флаг: %v", err), true
+		case errors.Is(err, systemsvc.ErrBuiltinFlag):
+			return "Тип этого флага менять нельзя", true
+		}
+		a.logger.Warn("save flag", "error", err, "key", flag.Key, "tg_id", actorTGID)
+		return "Не удалось сохранить флаг", true
+	}
+	if err := a.auditService.LogFlagUpdated(ctx, actorTGID, saved, field); err != nil {
+		a.logger.Warn("write flag audit", "error", err, "key", saved.Key, "tg_id", actorTGID)
+	}
+
+	a.sendFlagCard(chatID, saved)
+	return "Сохранено", false
+}
+
+func (a *App) sendFlagList(ctx context.Context, chatID int64) {
+	flags, err := a.systemService.ListFlags(ctx)
+	if err != nil {
+		a.logger.Warn("load flags", "error", err)
+		a.sendText(chatID, "Не удалось загрузить флаги")
+		return
+	}
+
+	rows := make([][]telegram.InlineButton, 0, len(flags)+2)
+	for _, flag := range flags {
+		rows = append(rows, []telegram.InlineButton{{
+			Text: fmt.Sprintf("%s = %s", flag.Key, string(flag.Value)),
+			Data: fmt.Sprintf("%s:view:%s", callbackPrefixFlags, flag.Key),
+		}})
+	}
+	rows = append(rows,
+		[]telegram.InlineButton{{Text: "➕ Новый флаг", Data: fmt.Sprintf("%s:new", callbackPrefixFlags)}},
+		[]telegram.InlineButton{{Text: "Back", Data: fmt.Sprintf("%s:back", callbackPrefixFlags)}},
+	)
+	a.sendInline(chatID, ui.RenderFlagList(flags), rows)
+}
+
+func (a *App) sendFlagCard(chatID int64, flag model.AppFlag) {
+	data := func(action string) string {
+		return fmt.Sprintf("%s:%s:%s", callbackPrefixFlags, action, flag.Key)
+	}
+	edit := func(field string) string {
+		return fmt.Sprintf("%s:edit:%s:%s", callbackPrefixFlags, flag.Key, field)
+	}
+
+	visText := "👁 Показать клиенту"
+	if flag.ClientVisible {
+		visText = "🙈 Скрыть от клиента"
+	}
+	first := []telegram.InlineButton{{Text: "Значение", Data: edit(flagFieldValue)}}
+	if flag.Type == model.AppFlagTypeBool {
+		first = []telegram.InlineButton{{Text: "🔁 Переключить", Data: data("tog")}}
+	}
+	first = append(first, telegram.InlineButton{Text: visText, Data: data("vis")})
+
+	rows := [][]telegram.InlineButton{
+		first,
+		{
+			{Text: "Правила", Data: edit(flagFieldRules)},
+			{Text: "Описание", Data: edit(flagFieldDesc)},
+		},
+	}
+	if flag.Key != systemsvc.RegistrationFlagKey {
+		rows = append(rows, []telegram.InlineButton{{Text: "🗑 Удалить", Data: data("del")}})
+	}
+	rows = append(rows, []telegram.InlineButton{{Text: "⬅️ Back", Data: fmt.Sprintf("%s:list", callbackPrefixFlags)}})
+	a.sendInline(chatID, ui.RenderFlag(flag, systemsvc.FormatFlagRules(flag.Rules)), rows)
+}
+
+func applyFlagField(flag *model.AppFlag, field string, raw string) error {
+	switch field {
+	case flagFieldValue:
+		value, err := systemsvc.ParseFlagValue(flag.Type, raw)
+		if err != nil {
+			return fmt.Errorf("Некорректное значение: %v", err)
+		}
+		flag.Value = value
+	case flagFieldRules:
+		rules, err := systemsvc.ParseFlagRules(flag.Type, raw)
+		if err != nil {
+			return fmt.Errorf("Некорректные правила: %v\n\n%s", err, flagRulesHelp)
+		}
+		flag.Rules = rules
+	case flagFieldDesc:
+		if raw == "-" {
+			raw = ""
+		}
+		flag.Description = raw
+	default:
+		return fmt.Errorf("Некорректное поле")
+	}
+	return nil
+}
+
+func parseNewFlag(raw string) (model.AppFlag, error) {
+	fields := strings.Fields(raw)
+	if len(fields) < 3 {
+		return model.AppFlag{}, fmt.Errorf("Нужно минимум 3 части: ключ, тип и значение")
+	}
+
+	flagType := strings.ToLower(fields[1])
+	value, err := systemsvc.ParseFlagValue(flagType, fields[2])
+	if err != nil {
+		return model.AppFlag{}, fmt.Errorf("Некорректное значение: %v", err)
+	}
+	return model.AppFlag{
+		Key:         strings.ToLower(fields[0]),
+		Type:        flagType,
+		Value:       value,
+		Rules:       []model.AppFlagRule{},
+		Description: strings.Join(fields[3:], " "),
+	}, nil
+}
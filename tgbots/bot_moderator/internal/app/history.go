@@ -0,0 +1,234 @@
+package app
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"bot_moderator/internal/domain/enums"
+	"bot_moderator/internal/domain/model"
+	"bot_moderator/internal/infra/statestore"
+	"bot_moderator/internal/infra/telegram"
+	auditsvc "bot_moderator/internal/services/audit"
+	"bot_moderator/internal/ui"
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+)
+
+const auditFilterHelp = "Введите фильтр History, например:\n" +
+	"actor:123456 user:42 action:BAN_USER from:2026-01-01 to:2026-01-31\n" +
+	"Все части необязательны, '-' — без фильтра"
+
+func (a *App) handleHistoryEntry(ctx context.Context, message *tgbotapi.Message) {
+	if message == nil || message.From == nil {
+		return
+	}
+
+	_, role, err := a.resolveActorRole(ctx, message.From)
+	if err != nil {
+		a.logger.Warn("resolve actor role for history", "error", err, "tg_id", message.From.ID)
+		a.sendText(message.Chat.ID, "Не удалось открыть History")
+		return
+	}
+	if !a.accessService.Can(ctx, role, enums.PermissionAuditView) {
+		a.sendText(message.Chat.ID, "Dating App: нет доступа")
+		return
+	}
+
+	filter := a.loadAuditFilter(ctx, message.Chat.ID)
+	a.sendAuditPage(ctx, message.Chat.ID, filter, 0)
+}
+
+func (a *App) handleAuditFilterInput(ctx context.Context, message *tgbotapi.Message, session statestore.Session) {
+	raw := strings.TrimSpace(message.Text)
+	if raw == "-" {
+		raw = ""
+	}
+
+	filter, err := auditsvc.ParseFilter(raw)
+	if err != nil {
+		a.sendText(message.Chat.ID, fmt.Sprintf("Некорректный фильтр: %v\n\n%s", err, auditFilterHelp))
+		return
+	}
+
+	session.AuditFilter = auditsvc.FormatFilter(filter)
+	a.resetChatState(ctx, message.Chat.ID, session)
+	a.sendAuditPage(ctx, message.Chat.ID, filter, 0)
+}
+
+func (a *App) handleAuditCallback(ctx context.Context, chatID int64, query *tgbotapi.CallbackQuery, parts []string) (string, bool) {
+	actorTGID, actorRole, err := a.resolveActorRole(ctx, query.From)
+	if err != nil {
+		return "Не удалось определить роль", true
+	}
+	if !a.accessService.Can(ctx, actorRole, enums.PermissionAuditView) {
+		return "Нет доступа", true
+	}
+
+	filter := a.loadAuditFilter(ctx, chatID)
+
+	switch parts[1] {
+	case "page":
+		if len(parts) < 3 {
+			return "", false
+		}
+		offset, err := strconv.Atoi(parts[2])
+		if err != nil || offset < 0 {
+			return "Некорректная страница", true
+		}
+		a.sendAuditPage(ctx, chatID, filter, offset)
+		return "", false
+	case "filter":
+		if !a.enterChatState(ctx, chatID, telegram.StateWaitingAuditFilter, actorTGID, actorRole, nil) {
+			return chatStateBusyText, true
+		}
+		a.sendText(chatID, auditFilterHelp)
+		return "Ожидаю фильтр", false
+	case "actions":
+		a.sendAuditActionPicker(chatID)
+		return "", false
+	case "act":
+		if len(parts) < 3 {
+			return "", false
+		}
+		action, ok := enums.ParseAuditAction(parts[2])
+		if !ok {
+			return "Неизвестное действие", true
+		}
+		filter.Action = action
+		a.saveAuditFilter(ctx, chatID, actorTGID, actorRole, filter)
+		a.sendAuditPage(ctx, chatID, filter, 0)
+		return "", false
+	case "reset":
+		filter = model.AuditFilter{}
+		a.saveAuditFilter(ctx, chatID, actorTGID, actorRole, filter)
+		a.sendAuditPage(ctx, chatID, filter, 0)
+		return "Фильтр сброшен", false
+	case "export":
+		if len(parts) < 3 {
+			return "", false
+		}
+		return a.sendAuditExport(ctx, chatID, actorTGID, filter, parts[2])
+	case "verify":
+		report, err := a.auditService.VerifyChain(ctx)
+		if err != nil {
+			a.logger.Warn("verify audit chain", "error", err)
+			return "Не удалось проверить цепочку", true
+		}
+		a.sendText(chatID, ui.RenderAuditChainReport(report))
+		return "", false
+	default:
+		return "", false
+	}
+}
+
+func (a *App) sendAuditPage(ctx context.Context, chatID int64, filter model.AuditFilter, offset int) {
+	filter.Offset = offset
+	filter.Limit = auditsvc.DefaultPageSize
+	page, err := a.auditService.Search(ctx, filter)
+	if err != nil {
+		a.logger.Warn("search audit", "error", err)
+		a.sendText(chatID, "Не удалось загрузить History")
+		return
+	}
+
+	text := ui.RenderAuditPage(page, auditsvc.FormatFilter(filter))
+	a.sendInline(chatID, text, auditPageKeyboard(page))
+}
+
+func auditPageKeyboard(page model.AuditPage) [][]telegram.InlineButton {
+	rows := make([][]telegram.InlineButton, 0, 4)
+
+	nav := make([]telegram.InlineButton, 0, 2)
+	if page.Offset > 0 {
+		prev := page.Offset - page.Limit
+		if prev < 0 {
+			prev = 0
+		}
+		nav = append(nav, telegram.InlineButton{Text: "◀️ Назад", Data: fmt.Sprintf("%s:page:%d", callbackPrefixAudit, prev)})
+	}
+	if page.HasMore {
+		nav = append(nav, telegram.InlineButton{Text: "Вперёд ▶️", Data: fmt.Sprintf("%s:page:%d", callbackPrefixAudit, page.Offset+page.Limit)})
+	}
+	if len(nav) > 0 {
+		rows = append(rows, nav)
+	}
+
+	rows = append(rows,
+		[]telegram.InlineButton{
+			{Text: "Фильтр", Data: fmt.Sprintf("%s:filter", callbackPrefixAudit)},
+			{Text: "Действие", Data: fmt.Sprintf("%s:actions", callbackPrefixAudit)},
+			{Text: "Сброс", Data: fmt.Sprintf("%s:reset", callbackPrefixAudit)},
+		},
+		[]telegram.InlineButton{
+			{Text: "CSV", Data: fmt.Sprintf("%s:export:%s", callbackPrefixAudit, auditsvc.ExportFormatCSV)},
+			{Text: "JSON", Data: fmt.Sprintf("%s:export:%s", callbackPrefixAudit, auditsvc.ExportFormatJSON)},
+			{Text: "Проверить цепочку", Data: fmt.Sprintf("%s:verify", callbackPrefixAudit)},
+		},
+	)
+	return rows
+}
+
+func (a *App) sendAuditActionPicker(chatID int64) {
+	actions := enums.AuditActions()
+	rows := make([][]telegram.InlineButton, 0, (len(actions)+1)/2)
+	for i := 0; i < len(actions); i += 2 {
+		row := []telegram.InlineButton{{Text: string(actions[i]), Data: fmt.Sprintf("%s:act:%s", callbackPrefixAudit, actions[i])}}
+		if i+1 < len(actions) {
+			row = append(row, telegram.InlineButton{Text: string(actions[i+1]), Data: fmt.Sprintf("%s:act:%s", callbackPrefixAudit, actions[i+1])})
+		}
+		rows = append(rows, row)
+	}
+	a.sendInline(chatID, "Выберите действие", rows)
+}
+
+func (a *App) sendAuditExport(ctx context.Context, chatID int64, actorTGID int64, filter model.AuditFilter, format string) (string, bool) {
+	data, count, err := a.auditService.Export(ctx, filter, format)
+	if errors.Is(err, auditsvc.ErrUnsupportedExportFormat) {
+		return "Неподдерживаемый формат", true
+	}
+	if err != nil {
+		a.logger.Warn("export audit", "error", err, "format", format)
+		return "Не удалось выгрузить History", true
+	}
+
+	fileName := fmt.Sprintf("audit-%s.%s", time.Now().UTC().Format("20060102-150405"), format)
+	caption := fmt.Sprintf("History: %d записей", count)
+	if count >= auditsvc.ExportLimit {
+		caption += fmt.Sprintf(" (лимит %d, сузьте фильтр)", auditsvc.ExportLimit)
+	}
+	if err := a.SendDocument(ctx, chatID, fileName, data, caption); err != nil {
+		a.logger.Warn("send audit export", "error", err)
+		return "Не удалось отправить файл", true
+	}
+
+	if err := a.auditService.LogAuditExport(ctx, actorTGID, filter, format, count); err != nil {
+		a.logger.Warn("write audit export audit", "error", err)
+	}
+	return "Готово", false
+}
+
+func (a *App) loadAuditFilter(ctx context.Context, chatID int64) model.AuditFilter {
+	session, ok := a.loadChatState(ctx, chatID)
+	if !ok || session.AuditFilter == "" {
+		return model.AuditFilter{}
+	}
+	filter, err := auditsvc.ParseFilter(session.AuditFilter)
+	if err != nil {
+		return model.AuditFilter{}
+	}
+	return filter
+}
+
+func (a *App) saveAuditFilter(ctx context.Context, chatID int64, actorTGID int64, actorRole enums.Role, filter model.AuditFilter) {
+	session, ok := a.loadChatState(ctx, chatID)
+	if !ok || session.ActorTGID != actorTGID {
+		session = statestore.Session{State: telegram.StateIdle}
+	}
+	session.ActorTGID = actorTGID
+	session.ActorRole = actorRole
+	session.AuditFilter = auditsvc.FormatFilter(filter)
+	a.saveChatState(ctx, chatID, session)
+}
@@ -0,0 +1,329 @@
+package app
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"bot_moderator/internal/domain/enums"
+	"bot_moderator/internal/domain/model"
+	"bot_moderator/internal/infra/statestore"
+	"bot_moderator/internal/infra/telegram"
+	moderationsvc "bot_moderator/internal/services/moderation"
+	"bot_moderator/internal/ui"
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+)
+
+const (
+	reasonFieldNew   = "new"
+	reasonFieldLabel = "label"
+	reasonFieldEN    = "en"
+	reasonFieldText  = "text"
+	reasonFieldFix   = "fix"
+	reasonFieldOrder = "order"
+)
+
+const newRejectReasonHelp = "Введите новую причину одной строкой:\n" +
+	"CODE | Название | Текст пользователю | Что исправить\n" +
+	"Код: латиница A-Z, цифры и _, до 32 символов"
+
+var reasonFieldPrompts = map[string]string{
+	reasonFieldLabel: "Введите название причины (ru)",
+	reasonFieldEN:    "Введите название причины (en)",
+	reasonFieldText:  "Введите текст причины для пользователя",
+	reasonFieldFix:   "Введите шаг исправления для пользователя",
+	reasonFieldOrder: "Введите порядок сортировки (целое число)",
+}
+
+var rejectReasonSeverityCycle = map[string]enums.RejectReasonSeverity{
+	string(enums.RejectReasonSeverityLow):    enums.RejectReasonSeverityMedium,
+	string(enums.RejectReasonSeverityMedium): enums.RejectReasonSeverityHigh,
+	string(enums.RejectReasonSeverityHigh):   enums.RejectReasonSeverityLow,
+}
+
+// rejectReasonRows builds one button per active catalog reason; the caller appends its own Back row.
+func (a *App) rejectReasonRows(ctx context.Context, data func(code string) string) ([][]telegram.InlineButton, bool) {
+	reasons, err := a.rejectReasons.List(ctx, false)
+	if err != nil {
+		a.logger.Warn("load reject reasons", "error", err)
+		return nil, false
+	}
+	if len(reasons) == 0 {
+		return nil, false
+	}
+
+	rows := make([][]telegram.InlineButton, 0, len(reasons)+1)
+	for _, reason := range reasons {
+		rows = append(rows, []telegram.InlineButton{{
+			Text: reason.LabelFor(model.RejectReasonDefaultLanguage),
+			Data: data(reason.Code),
+		}})
+	}
+	return rows, true
+}
+
+func (a *App) isActiveRejectReason(ctx context.Context, code string) bool {
+	_, err := a.rejectReasons.Lookup(ctx, code)
+	return err == nil
+}
+
+func (a *App) handleRejectReasonsEntry(ctx context.Context, message *tgbotapi.Message) {
+	if message == nil || message.From == nil {
+		return
+	}
+
+	_, role, err := a.resolveActorRole(ctx, message.From)
+	if err != nil {
+		a.logger.Warn("resolve actor role for reject reasons", "error", err, "tg_id", message.From.ID)
+		a.sendText(message.Chat.ID, "Не удалось открыть причины отказа")
+		return
+	}
+	if !a.accessService.Can(ctx, role, enums.PermissionModerationReasons) {
+		a.sendText(message.Chat.ID, "Dating App: нет доступа")
+		return
+	}
+
+	a.sendRejectReasonCatalog(ctx, message.Chat.ID)
+}
+
+func (a *App) handleRejectReasonCallback(ctx context.Context, chatID int64, query *tgbotapi.CallbackQuery, parts []string) (string, bool) {
+	actorTGID, actorRole, err := a.resolveActorRole(ctx, query.From)
+	if err != nil {
+		return "Не удалось определить роль", true
+	}
+	if !a.accessService.Can(ctx, actorRole, enums.PermissionModerationReasons) {
+		return "Нет доступа", true
+	}
+
+	switch parts[1] {
+	case "list":
+		a.sendRejectReasonCatalog(ctx, chatID)
+		return "", false
+	case "back":
+		a.clearChatState(ctx, chatID)
+		a.sendMainMenu(ctx, chatID, actorRole)
+		return "", false
+	case "new":
+		if !a.enterChatState(ctx, chatID, telegram.StateWaitingReasonEdit, actorTGID, actorRole, func(session *statestore.Session) {
+			session.ReasonCode = ""
+			session.ReasonField = reasonFieldNew
+		}) {
+			return chatStateBusyText, true
+		}
+		a.sendText(chatID, newRejectReasonHelp)
+		return "Ожидаю причину", false
+	}
+
+	if len(parts) < 3 {
+		return "", false
+	}
+	reason, err := a.rejectReasons.Get(ctx, parts[2])
+	if err != nil {
+		if errors.Is(err, moderationsvc.ErrUnknownRejectReason) {
+			return "Причина не найдена", true
+		}
+		a.logger.Warn("load reject reason", "error", err, "reason_code", parts[2])
+		return "Не удалось загрузить причину", true
+	}
+
+	switch parts[1] {
+	case "view":
+		a.sendRejectReasonCard(chatID, reason)
+		return "", false
+	case "toggle":
+		reason.IsActive = !reason.IsActive
+		return a.saveRejectReason(ctx, chatID, actorTGID, reason, "is_active")
+	case "sev":
+		reason.Severity = string(rejectReasonSeverityCycle[reason.Severity])
+		if reason.Severity == "" {
+			reason.Severity = string(enums.RejectReasonSeverityMedium)
+		}
+		return a.saveRejectReason(ctx, chatID, actorTGID, reason, "severity")
+	case "edit":
+		if len(parts) < 4 {
+			return "", false
+		}
+		prompt, ok := reasonFieldPrompts[parts[3]]
+		if !ok {
+			return "Некорректное поле", true
+		}
+		if !a.enterChatState(ctx, chatID, telegram.StateWaitingReasonEdit, actorTGID, actorRole, func(session *statestore.Session) {
+			session.ReasonCode = reason.Code
+			session.ReasonField = parts[3]
+		}) {
+			return chatStateBusyText, true
+		}
+		a.sendText(chatID, prompt)
+		return "Ожидаю значение", false
+	default:
+		return "", false
+	}
+}
+
+func (a *App) handleRejectReasonEditInput(ctx context.Context, message *tgbotapi.Message, session statestore.Session) {
+	raw := strings.TrimSpace(message.Text)
+	if raw == "" {
+		a.sendText(message.Chat.ID, "Значение не может быть пустым")
+		return
+	}
+
+	var (
+		reason model.RejectReason
+		err    error
+	)
+	field := session.ReasonField
+	if field == reasonFieldNew {
+		reason, err = parseNewRejectReason(raw)
+		if err != nil {
+			a.sendText(message.Chat.ID, fmt.Sprintf("%v\n\n%s", err, newRejectReasonHelp))
+			return
+		}
+		if _, err := a.rejectReasons.Get(ctx, reason.Code); err == nil {
+			a.sendText(message.Chat.ID, "Причина с таким кодом уже есть, откройте её в списке")
+			return
+		}
+	} else {
+		reason, err = a.rejectReasons.Get(ctx, session.ReasonCode)
+		if err != nil {
+			a.resetChatState(ctx, message.Chat.ID, session)
+			a.sendText(message.Chat.ID, "Причина не найдена")
+			return
+		}
+		if err := applyRejectReasonField(&reason, field, raw); err != nil {
+			a.sendText(message.Chat.ID, err.Error())
+			return
+		}
+	}
+
+	a.resetChatState(ctx, message.Chat.ID, session)
+	if text, failed := a.saveRejectReason(ctx, message.Chat.ID, session.ActorTGID, reason, field); failed {
+		a.sendText(message.Chat.ID, text)
+	}
+}
+
+func (a *App) saveRejectReason(ctx context.Context, chatID int64, actorTGID int64, reason model.RejectReason, field string) (string, bool) {
+	saved, err := a.rejectReasons.Save(ctx, actorTGID, reason)
+	if err != nil {
+		if errors.Is(err, moderationsvc.ErrInvalidRejectReason) {
+			return fmt.Sprintf("Некорректная причина: %v", err), true
+		}
+		a.logger.Warn("save reject reason", "error", err, "reason_code", reason.Code, "tg_id", actorTGID)
+		return "Не удалось сохранить причину", true
+	}
+	if err := a.auditService.LogRejectReasonUpdate(ctx, actorTGID, saved, field); err != nil {
+		a.logger.Warn("write reject reason audit", "error", err, "reason_code", saved.Code, "tg_id", actorTGID)
+	}
+
+	a.sendRejectReasonCard(chatID, saved)
+	return "Сохранено", false
+}
+
+func (a *App) sendRejectReasonCatalog(ctx context.Context, chatID int64) {
+	reasons, err := a.rejectReasons.List(ctx, true)
+	if err != nil {
+		a.logger.Warn("load reject reason catalog", "error", err)
+		a.sendText(chatID, "Не удалось загрузить причины отказа")
+		return
+	}
+
+	rows := make([][]telegram.InlineButton, 0, len(reasons)+2)
+	for _, reason := range reasons {
+		mark := "✅"
+		if !reason.IsActive {
+			mark = "⛔️"
+		}
+		rows = append(rows, []telegram.InlineButton{{
+			Text: fmt.Sprintf("%s %s", mark, reason.LabelFor(model.RejectReasonDefaultLanguage)),
+			Data: fmt.Sprintf("%s:view:%s", callbackPrefixReasons, reason.Code),
+		}})
+	}
+	rows = append(rows,
+		[]telegram.InlineButton{{Text: "➕ Новая причина", Data: fmt.Sprintf("%s:new", callbackPrefixReasons)}},
+		[]telegram.InlineButton{{Text: "Back", Data: fmt.Sprintf("%s:back", callbackPrefixReasons)}},
+	)
+	a.sendInline(chatID, ui.RenderRejectReasonCatalog(reasons), rows)
+}
+
+func (a *App) sendRejectReasonCard(chatID int64, reason model.RejectReason) {
+	toggleText := "⛔️ Отключить"
+	if !reason.IsActive {
+		toggleText = "✅ Включить"
+	}
+
+	data := func(action string) string {
+		return fmt.Sprintf("%s:%s:%s", callbackPrefixReasons, action, reason.Code)
+	}
+	edit := func(field string) string {
+		return fmt.Sprintf("%s:edit:%s:%s", callbackPrefixReasons, reason.Code, field)
+	}
+	rows := [][]telegram.InlineButton{
+		{
+			{Text: toggleText, Data: data("toggle")},
+			{Text: "Серьёзность: " + reason.Severity, Data: data("sev")},
+		},
+		{
+			{Text: "Название ru", Data: edit(reasonFieldLabel)},
+			{Text: "Название en", Data: edit(reasonFieldEN)},
+		},
+		{
+			{Text: "Текст", Data: edit(reasonFieldText)},
+			{Text: "Что исправить", Data: edit(reasonFieldFix)},
+			{Text: "Порядок", Data: edit(reasonFieldOrder)},
+		},
+		{
+			{Text: "⬅️ Back", Data: fmt.Sprintf("%s:list", callbackPrefixReasons)},
+		},
+	}
+	a.sendInline(chatID, ui.RenderRejectReason(reason), rows)
+}
+
+func applyRejectReasonField(reason *model.RejectReason, field string, value string) error {
+	labels := make(map[string]string, len(reason.Labels)+1)
+	for lang, label := range reason.Labels {
+		labels[lang] = label
+	}
+
+	switch field {
+	case reasonFieldLabel:
+		labels[model.RejectReasonDefaultLanguage] = value
+		reason.Label = value
+	case reasonFieldEN:
+		labels["en"] = value
+	case reasonFieldText:
+		reason.ReasonText = value
+	case reasonFieldFix:
+		reason.RequiredFixStep = value
+	case reasonFieldOrder:
+		order, err := strconv.Atoi(value)
+		if err != nil {
+			return fmt.Errorf("Порядок должен быть целым числом")
+		}
+		reason.SortOrder = order
+	default:
+		return fmt.Errorf("Некорректное поле")
+	}
+	reason.Labels = labels
+	return nil
+}
+
+func parseNewRejectReason(raw string) (model.RejectReason, error) {
+	parts := strings.Split(raw, "|")
+	if len(parts) != 4 {
+		return model.RejectReason{}, fmt.Errorf("Нужно 4 части через |")
+	}
+	for i := range parts {
+		parts[i] = strings.TrimSpace(parts[i])
+	}
+
+	return model.RejectReason{
+		Code:            strings.ToUpper(parts[0]),
+		Label:           parts[1],
+		Labels:          map[string]string{model.RejectReasonDefaultLanguage: parts[1]},
+		ReasonText:      parts[2],
+		RequiredFixStep: parts[3],
+		Severity:        string(enums.RejectReasonSeverityMedium),
+		IsActive:        true,
+	}, nil
+}
@@ -0,0 +1,222 @@
+package app
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"bot_moderator/internal/domain/enums"
+	"bot_moderator/internal/domain/model"
+	"bot_moderator/internal/infra/telegram"
+	moderationsvc "bot_moderator/internal/services/moderation"
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+)
+
+func (a *App) canReview(ctx context.Context, role enums.Role, kind string) bool {
+	switch kind {
+	case model.ModerationReviewKindAppeal:
+		return a.accessService.Can(ctx, role, enums.PermissionModerationDecide)
+	case model.ModerationReviewKindQA:
+		return a.accessService.Can(ctx, role, enums.PermissionModerationQA)
+	default:
+		return false
+	}
+}
+
+func (a *App) handleReviewEntry(ctx context.Context, message *tgbotapi.Message, kind string) {
+	if message == nil || message.From == nil {
+		return
+	}
+
+	actorTGID, role, err := a.resolveActorRole(ctx, message.From)
+	if err != nil {
+		a.logger.Warn("resolve actor role for review", "error", err, "tg_id", message.From.ID)
+		a.sendText(message.Chat.ID, "Не удалось определить роль")
+		return
+	}
+	if !a.canReview(ctx, role, kind) {
+		a.sendText(message.Chat.ID, "Dating App: нет доступа")
+		return
+	}
+
+	a.acquireAndSendNextReview(ctx, message.Chat.ID, actorTGID, kind)
+}
+
+func (a *App) acquireAndSendNextReview(ctx context.Context, chatID int64, actorTGID int64, kind string) {
+	item, err := a.reviewService.AcquireNext(ctx, kind, actorTGID)
+	if errors.Is(err, moderationsvc.ErrReviewQueueEmpty) {
+		a.sendText(chatID, "Очередь проверок пуста")
+		return
+	}
+	if err != nil {
+		a.logger.Warn("acquire next review", "error", err, "kind", kind, "tg_id", actorTGID)
+		a.sendText(chatID, "Не удалось получить следующую проверку")
+		return
+	}
+
+	a.sendText(chatID, renderReviewQueueItem(item))
+	a.sendModerationMedia(chatID, model.ModerationQueueItem{
+		ModerationItemID: item.Review.ModerationItemID,
+		PhotoURLs:        item.PhotoURLs,
+		CircleURL:        item.CircleURL,
+	})
+	a.sendReviewDecisionPrompt(chatID, item.Review)
+}
+
+func (a *App) sendReviewDecisionPrompt(chatID int64, review model.ModerationReview) {
+	overturnText := "↩️ Одобрить анкету"
+	if review.Kind == model.ModerationReviewKindQA {
+		overturnText = "↩️ Отклонить анкету"
+	}
+	rows := [][]telegram.InlineButton{{
+		{Text: "✅ Оставить решение", Data: fmt.Sprintf("%s:up:%d", callbackPrefixReview, review.ID)},
+		{Text: overturnText, Data: fmt.Sprintf("%s:over:%d", callbackPrefixReview, review.ID)},
+	}}
+	a.sendInline(chatID, "Решение по повторной проверке", rows)
+}
+
+func (a *App) sendReviewReasonPrompt(ctx context.Context, chatID int64, reviewID int64) bool {
+	rows, ok := a.rejectReasonRows(ctx, func(code string) string {
+		return fmt.Sprintf("%s:reason:%d:%s", callbackPrefixReview, reviewID, code)
+	})
+	if !ok {
+		return false
+	}
+	rows = append(rows, []telegram.InlineButton{{
+		Text: "⬅️ Back",
+		Data: fmt.Sprintf("%s:back:%d", callbackPrefixReview, reviewID),
+	}})
+	a.sendInline(chatID, "Выберите причину отклонения", rows)
+	return true
+}
+
+// handleReviewCallback serves rev:up:<id>, rev:over:<id>, rev:back:<id> and
+// rev:reason:<id>:<code>. The review kind always comes from the stored review.
+func (a *App) handleReviewCallback(ctx context.Context, chatID int64, query *tgbotapi.CallbackQuery, parts []string) (string, bool) {
+	actorTGID, actorRole, err := a.resolveActorRole(ctx, query.From)
+	if err != nil {
+		return "Не удалось определить роль", true
+	}
+	if len(parts) < 3 {
+		return "", false
+	}
+
+	reviewID, err := parseTGID(parts[2])
+	if err != nil || reviewID <= 0 {
+		return "Некорректный review id", true
+	}
+
+	outcome := model.ModerationReviewOutcomeOverturn
+	reasonCode := ""
+	switch parts[1] {
+	case "back", "over":
+		review, err := a.reviewService.Get(ctx, reviewID)
+		if errors.Is(err, moderationsvc.ErrReviewNotFound) {
+			return "Проверка не найдена", true
+		}
+		if err != nil {
+			a.logger.Warn("load review", "error", err, "review_id", reviewID, "tg_id", actorTGID)
+			return "Не удалось загрузить проверку", true
+		}
+		if !a.canReview(ctx, actorRole, review.Kind) {
+			return "Нет доступа", true
+		}
+		if parts[1] == "back" {
+			a.sendReviewDecisionPrompt(chatID, review)
+			return "", false
+		}
+		if review.Kind == model.ModerationReviewKindQA {
+			if !a.sendReviewReasonPrompt(ctx, chatID, reviewID) {
+				return "Не удалось загрузить причины отказа", true
+			}
+			return "Выберите причину", false
+		}
+	case "up":
+		outcome = model.ModerationReviewOutcomeUphold
+	case "reason":
+		if len(parts) < 4 {
+			return "", false
+		}
+		reasonCode = normalizeReasonCode(parts[3])
+		if !a.isActiveRejectReason(ctx, reasonCode) {
+			return "Некорректный reason code", true
+		}
+	default:
+		return "", false
+	}
+
+	review, err := a.reviewService.Resolve(ctx, moderationsvc.ResolveReviewInput{
+		ReviewID:     reviewID,
+		ReviewerTGID: actorTGID,
+		Outcome:      outcome,
+		ReasonCode:   reasonCode,
+	}, func(kind string) bool {
+		return a.canReview(ctx, actorRole, kind)
+	})
+	switch {
+	case errors.Is(err, moderationsvc.ErrReviewForbidden):
+		return "Нет доступа", true
+	case errors.Is(err, moderationsvc.ErrReviewNotFound):
+		return "Проверка не найдена", true
+	case errors.Is(err, moderationsvc.ErrReviewReasonRequired):
+		return "Выберите причину отказа", true
+	case errors.Is(err, moderationsvc.ErrSameReviewer):
+		return "Нельзя пересматривать собственное решение", true
+	case errors.Is(err, moderationsvc.ErrReviewNotPending):
+		return "Проверка уже завершена", true
+	case errors.Is(err, moderationsvc.ErrReviewNotLocked):
+		return "Проверка не закреплена за вами, возьмите её из очереди снова", true
+	case err != nil:
+		a.logger.Warn("resolve review", "error", err, "review_id", reviewID, "tg_id", actorTGID)
+		return "Не удалось сохранить решение", true
+	}
+
+	if err := a.auditService.LogModerationReview(ctx, actorTGID, review, reasonCode, ""); err != nil {
+		a.logger.Warn("write moderation review audit", "error", err)
+	}
+
+	if review.Status == model.ModerationReviewStatusOverturned {
+		a.sendText(chatID, fmt.Sprintf("Проверка #%d: решение по анкете #%d отменено", review.ID, review.ModerationItemID))
+	} else {
+		a.sendText(chatID, fmt.Sprintf("Проверка #%d: решение по анкете #%d оставлено", review.ID, review.ModerationItemID))
+	}
+	a.acquireAndSendNextReview(ctx, chatID, actorTGID, review.Kind)
+	return "Сохранено", false
+}
+
+func renderReviewQueueItem(item model.ModerationReviewQueueItem) string {
+	review := item.Review
+	profile := item.Profile
+
+	title := "Апелляция"
+	if review.Kind == model.ModerationReviewKindQA {
+		title = "QA проверка"
+	}
+	originalModerator := "-"
+	if review.OriginalModeratorTGID != nil {
+		originalModerator = fmt.Sprintf("%d", *review.OriginalModeratorTGID)
+	}
+
+	lines := []string{
+		fmt.Sprintf("%s #%d (анкета #%d)", title, review.ID, review.ModerationItemID),
+		fmt.Sprintf("user_id: %d", profile.UserID),
+		fmt.Sprintf("tg_id: %d", profile.TGID),
+		fmt.Sprintf("username: %s", defaultText(profile.Username, "-")),
+		fmt.Sprintf("display_name: %s", defaultText(profile.DisplayName, "-")),
+		fmt.Sprintf("verification: %s", defaultText(profile.VerificationStatus, "-")),
+		fmt.Sprintf("Исходное решение: %s", defaultText(review.OriginalDecision, "-")),
+		fmt.Sprintf("Причина: %s", defaultText(review.OriginalReasonCode, "-")),
+		fmt.Sprintf("Модератор: %s", originalModerator),
+		fmt.Sprintf("created_at: %s", review.CreatedAt.UTC().Format(time.RFC3339)),
+	}
+	if review.ReasonText != "" {
+		lines = append(lines, fmt.Sprintf("Текст причины: %s", review.ReasonText))
+	}
+	if review.AppealMessage != "" {
+		lines = append(lines, fmt.Sprintf("Сообщение пользователя: %s", review.AppealMessage))
+	}
+
+	return strings.Join(lines, "\n")
+}
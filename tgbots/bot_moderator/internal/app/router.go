@@ -11,8 +11,10 @@ import (
 
 	"bot_moderator/internal/domain/enums"
 	"bot_moderator/internal/domain/model"
+	"bot_moderator/internal/infra/statestore"
 	"bot_moderator/internal/infra/telegram"
 	"bot_moderator/internal/repo/adminhttp"
+	"bot_moderator/internal/repo/postgres"
 	"bot_moderator/internal/services/access"
 	lookupsvc "bot_moderator/internal/services/lookup"
 	moderationsvc "bot_moderator/internal/services/moderation"
@@ -26,8 +28,17 @@ const (
 	callbackPrefixLookup     = "find"
 	callbackPrefixSystem     = "sys"
 	callbackPrefixWorkStats  = "wst"
+	callbackPrefixReview     = "rev"
+	callbackPrefixAudit      = "aud"
+	callbackPrefixReasons    = "rr"
+	callbackPrefixWorkload   = "wl"
+	callbackPrefixBatch      = "bat"
+	callbackPrefixFlags      = "flg"
+	callbackPrefixWaitlist   = "wtl"
 )
 
+const itemLockedText = "Анкету сейчас проверяет другой модератор"
+
 const (
 	lookupActionLookup      = "LOOKUP"
 	lookupActionBan         = "BAN"
@@ -35,54 +46,18 @@ const (
 	lookupActionForceReview = "FORCE_REVIEW"
 )
 
-var rejectReasonCodes = []string{
-	"PHOTO_NO_FACE",
-	"PHOTO_FAKE_NOT_YOU",
-	"PHOTO_PROHIBITED",
-	"CIRCLE_MISMATCH",
-	"CIRCLE_FAILED",
-	"PROFILE_INCOMPLETE",
-	"SPAM_ADS_LINKS",
-	"BOT_SUSPECT",
-	"OTHER",
-}
-
-type rejectReasonOption struct {
-	Code  string
-	Label string
-}
-
-var rejectReasonOptions = []rejectReasonOption{
-	{Code: "PHOTO_NO_FACE", Label: "PHOTO_NO_FACE"},
-	{Code: "PHOTO_FAKE_NOT_YOU", Label: "PHOTO_FAKE_NOT_YOU"},
-	{Code: "PHOTO_PROHIBITED", Label: "PHOTO_PROHIBITED"},
-	{Code: "CIRCLE_MISMATCH", Label: "CIRCLE_MISMATCH"},
-	{Code: "CIRCLE_FAILED", Label: "CIRCLE_FAILED"},
-	{Code: "PROFILE_INCOMPLETE", Label: "PROFILE_INCOMPLETE"},
-	{Code: "SPAM_ADS_LINKS", Label: "SPAM_ADS_LINKS"},
-	{Code: "BOT_SUSPECT", Label: "BOT_SUSPECT"},
-	{Code: "OTHER", Label: "Другая причина"},
-}
-
-type rejectCommentState struct {
-	ActorTGID int64
-	ActorRole enums.Role
-	ItemID    int64
-}
-
-type lookupInputState struct {
-	ActorTGID int64
-	ActorRole enums.Role
-}
-
-type lookupSessionState struct {
-	ActorTGID   int64
-	ActorRole   enums.Role
-	Query       string
-	FoundUserID int64
-}
+const (
+	updateDedupTTL      = 24 * time.Hour
+	callbackInFlightTTL = 30 * time.Second
+)
 
 func (a *App) routeUpdate(ctx context.Context, update tgbotapi.Update) {
+	// Telegram redelivers webhook updates on timeouts and replicas may race on the same update.
+	if update.UpdateID > 0 && !a.claim(ctx, "update:"+strconv.Itoa(update.UpdateID), updateDedupTTL) {
+		a.logger.Debug("skip duplicate update", "update_id", update.UpdateID)
+		return
+	}
+
 	if update.Message != nil {
 		a.routeMessage(ctx, update.Message)
 	}
@@ -97,7 +72,7 @@ func (a *App) routeMessage(ctx context.Context, message *tgbotapi.Message) {
 		return
 	}
 	if message.From != nil {
-		ctx = adminhttp.WithActorTGID(ctx, message.From.ID)
+		ctx = a.withActor(ctx, message.From)
 	}
 
 	if message.IsCommand() {
@@ -110,11 +85,7 @@ func (a *App) routeMessage(ctx context.Context, message *tgbotapi.Message) {
 		return
 	}
 
-	if a.handleRejectCommentIfNeeded(ctx, message) {
-		return
-	}
-
-	if a.handleLookupInputIfNeeded(ctx, message) {
+	if a.handleStatefulInput(ctx, message) {
 		return
 	}
 
@@ -137,8 +108,16 @@ func (a *App) handleMenuMessage(ctx context.Context, message *tgbotapi.Message)
 		a.handleHistoryEntry(ctx, message)
 	case "System":
 		a.handleSystemEntry(ctx, message)
+	case "Причины отказа":
+		a.handleRejectReasonsEntry(ctx, message)
 	case "Приступить к модерации":
 		a.handleAcquireModerationItem(ctx, message)
+	case "Пакетная модерация":
+		a.handleBatchModerationEntry(ctx, message)
+	case "Апелляции":
+		a.handleReviewEntry(ctx, message, model.ModerationReviewKindAppeal)
+	case "QA проверка":
+		a.handleReviewEntry(ctx, message, model.ModerationReviewKindQA)
 	}
 }
 
@@ -153,7 +132,7 @@ func (a *App) handleAcquireModerationItem(ctx context.Context, message *tgbotapi
 		a.sendText(message.Chat.ID, "Не удалось определить роль")
 		return
 	}
-	if role != enums.RoleOwner && role != enums.RoleAdmin && role != enums.RoleModerator {
+	if !a.accessService.Can(ctx, role, enums.PermissionModerationDecide) {
 		a.sendText(message.Chat.ID, "Dating App: нет доступа")
 		return
 	}
@@ -192,102 +171,106 @@ func (a *App) sendModerationDecisionPrompt(chatID int64, moderationItemID int64)
 	rows := [][]telegram.InlineButton{{
 		{Text: "✅ Approve", Data: fmt.Sprintf("%s:approve:%d", callbackPrefixModeration, moderationItemID)},
 		{Text: "❌ Reject", Data: fmt.Sprintf("%s:reject:%d", callbackPrefixModeration, moderationItemID)},
+	}, {
+		{Text: "🪪 Approve + Verified", Data: fmt.Sprintf("%s:verify:%d", callbackPrefixModeration, moderationItemID)},
 	}}
 	a.sendInline(chatID, "Решение по анкете", rows)
 }
 
-func (a *App) handleRejectCommentIfNeeded(ctx context.Context, message *tgbotapi.Message) bool {
+func (a *App) handleStatefulInput(ctx context.Context, message *tgbotapi.Message) bool {
 	if message == nil || message.From == nil {
 		return false
 	}
 
-	a.rejectMu.Lock()
-	state, ok := a.rejectByChat[message.Chat.ID]
-	a.rejectMu.Unlock()
-	if !ok {
+	session, ok := a.loadChatState(ctx, message.Chat.ID)
+	if !ok || session.ActorTGID != message.From.ID {
 		return false
 	}
 
-	if state.ActorTGID != message.From.ID {
+	switch session.State {
+	case telegram.StateWaitingRejectReason:
+		if batchHasItem(session, session.ItemID) {
+			a.handleBatchRejectCommentInput(ctx, message, session)
+			break
+		}
+		a.handleRejectCommentInput(ctx, message, session)
+	case telegram.StateWaitingLookupQuery:
+		a.handleLookupQueryInput(ctx, message, session)
+	case telegram.StateWaitingBanReason:
+		a.handleBanReasonInput(ctx, message, session)
+	case telegram.StateWaitingAuditFilter:
+		a.handleAuditFilterInput(ctx, message, session)
+	case telegram.StateWaitingReasonEdit:
+		a.handleRejectReasonEditInput(ctx, message, session)
+	case telegram.StateWaitingRoleName:
+		a.handleRoleNameInput(ctx, message, session)
+	case telegram.StateWaitingShiftInput:
+		a.handleShiftInput(ctx, message, session)
+	case telegram.StateWaitingFlagInput:
+		a.handleFlagInput(ctx, message, session)
+	default:
 		return false
 	}
+	return true
+}
 
+func (a *App) handleRejectCommentInput(ctx context.Context, message *tgbotapi.Message, session statestore.Session) {
 	comment := strings.TrimSpace(message.Text)
 	if comment == "-" {
 		comment = ""
 	}
 
-	a.rejectMu.Lock()
-	delete(a.rejectByChat, message.Chat.ID)
-	a.rejectMu.Unlock()
+	a.resetChatState(ctx, message.Chat.ID, session)
 
-	if _, err := a.rejectAndContinue(ctx, message.Chat.ID, state.ActorTGID, state.ActorRole, state.ItemID, "OTHER", comment); err != nil {
-		a.logger.Warn("reject with comment", "error", err, "item_id", state.ItemID, "tg_id", state.ActorTGID)
+	if _, err := a.rejectAndContinue(ctx, message.Chat.ID, session.ActorTGID, session.ActorRole, session.ItemID, enums.RejectReasonOther, comment); err != nil {
+		if errors.Is(err, postgres.ErrModerationItemNotPending) {
+			a.sendText(message.Chat.ID, "Анкета уже обработана")
+			return
+		}
+		if errors.Is(err, postgres.ErrModerationItemLocked) {
+			a.sendText(message.Chat.ID, itemLockedText)
+			return
+		}
+		a.logger.Warn("reject with comment", "error", err, "item_id", session.ItemID, "tg_id", session.ActorTGID)
 		a.sendText(message.Chat.ID, "Не удалось отклонить анкету")
 	}
-
-	return true
 }
 
-func (a *App) handleLookupInputIfNeeded(ctx context.Context, message *tgbotapi.Message) bool {
-	if message == nil || message.From == nil {
-		return false
-	}
-
-	a.lookupInputMu.Lock()
-	state, ok := a.lookupInputByChat[message.Chat.ID]
-	a.lookupInputMu.Unlock()
-	if !ok {
-		return false
-	}
-
-	if state.ActorTGID != message.From.ID {
-		return false
-	}
-
+func (a *App) handleLookupQueryInput(ctx context.Context, message *tgbotapi.Message, session statestore.Session) {
 	query := strings.TrimSpace(message.Text)
 	if query == "" {
 		a.sendText(message.Chat.ID, "Введите @username или tg_id")
-		return true
+		return
 	}
 
-	deleteLookupInputState(a, message.Chat.ID)
-
 	found, err := a.lookupService.FindUser(ctx, query)
 	if errors.Is(err, lookupsvc.ErrUserNotFound) {
 		a.sendText(message.Chat.ID, "Пользователь не найден. Введите @username или tg_id")
-		setLookupInputState(a, message.Chat.ID, state)
-		return true
+		return
 	}
 	if err != nil {
-		a.logger.Warn("find user", "error", err, "query", query, "tg_id", state.ActorTGID)
+		a.logger.Warn("find user", "error", err, "query", query, "tg_id", session.ActorTGID)
 		a.sendText(message.Chat.ID, "Не удалось выполнить поиск")
-		setLookupInputState(a, message.Chat.ID, state)
-		return true
+		return
 	}
 
-	session := lookupSessionState{
-		ActorTGID:   state.ActorTGID,
-		ActorRole:   state.ActorRole,
-		Query:       query,
-		FoundUserID: found.UserID,
-	}
-	setLookupSessionState(a, message.Chat.ID, session)
+	session.LookupQuery = query
+	session.LookupFoundUserID = found.UserID
+	a.resetChatState(ctx, message.Chat.ID, session)
 
 	foundUserID := found.UserID
-	if err := a.lookupService.LogAction(ctx, state.ActorTGID, state.ActorRole, query, &foundUserID, lookupActionLookup, map[string]interface{}{
+	if err := a.lookupService.LogAction(ctx, session.ActorTGID, session.ActorRole, query, &foundUserID, lookupActionLookup, map[string]interface{}{
 		"target_user_id": found.UserID,
 		"target_tg_id":   found.TGID,
 		"query":          query,
 	}); err != nil {
-		a.logger.Warn("log lookup action", "error", err, "query", query, "tg_id", state.ActorTGID)
+		a.logger.Warn("log lookup action", "error", err, "query", query, "tg_id", session.ActorTGID)
 	}
-	if err := a.auditService.LogLookup(ctx, state.ActorTGID, query, found.UserID); err != nil {
-		a.logger.Warn("log lookup audit", "error", err, "query", query, "tg_id", state.ActorTGID)
+	if err := a.auditService.LogLookup(ctx, session.ActorTGID, query, found.UserID); err != nil {
+		a.logger.Warn("log lookup audit", "error", err, "query", query, "tg_id", session.ActorTGID)
 	}
 
 	a.sendLookupUserCard(message.Chat.ID, found)
-	return true
 }
 
 func (a *App) sendPhotoByURL(chatID int64, mediaURL string, caption string) error {
@@ -325,7 +308,13 @@ func (a *App) handleStart(ctx context.Context, message *tgbotapi.Message) {
 		}
 	}
 
-	if role == enums.RoleNone {
+	// /start abandons a pending input so the chat can always get back to IDLE.
+	if session, ok := a.loadChatState(ctx, message.Chat.ID); ok && session.State != telegram.StateIdle {
+		a.resetChatState(ctx, message.Chat.ID, session)
+	}
+
+	def, known := a.accessService.RoleDefinition(ctx, role)
+	if role == enums.RoleNone || !known || len(def.Permissions) == 0 {
 		response := tgbotapi.NewMessage(message.Chat.ID, "У вас нет доступа к этому боту")
 		response.ReplyMarkup = tgbotapi.NewRemoveKeyboard(true)
 		if err := a.tg.Send(response); err != nil {
@@ -338,7 +327,7 @@ func (a *App) handleStart(ctx context.Context, message *tgbotapi.Message) {
 		return
 	}
 
-	text, menu := ui.RenderStart(role)
+	text, menu := ui.RenderStart(role, def)
 	response := tgbotapi.NewMessage(message.Chat.ID, text)
 	response.ReplyMarkup = telegram.BuildReplyKeyboard(menu)
 
@@ -363,12 +352,12 @@ func (a *App) handleAccessEntry(ctx context.Context, message *tgbotapi.Message)
 		return
 	}
 
-	if !a.accessService.CanOpenAccess(role) {
+	if !a.accessService.CanOpenAccess(ctx, role) {
 		a.sendText(message.Chat.ID, "Dating App: нет доступа")
 		return
 	}
 
-	a.sendAccessScreen(ctx, message.Chat.ID)
+	a.sendAccessScreen(ctx, message.Chat.ID, role)
 }
 
 func (a *App) handleFindUserEntry(ctx context.Context, message *tgbotapi.Message) {
@@ -383,42 +372,16 @@ func (a *App) handleFindUserEntry(ctx context.Context, message *tgbotapi.Message
 		return
 	}
 
-	if role != enums.RoleOwner && role != enums.RoleAdmin {
+	if !a.accessService.Can(ctx, role, enums.PermissionUsersViewPrivate) {
 		a.sendText(message.Chat.ID, "Dating App: нет доступа")
 		return
 	}
 
-	setLookupInputState(a, message.Chat.ID, lookupInputState{
-		ActorTGID: actorTGID,
-		ActorRole: role,
-	})
-	a.sendText(message.Chat.ID, "Введите @username или tg_id")
-}
-
-func (a *App) handleHistoryEntry(ctx context.Context, message *tgbotapi.Message) {
-	if message == nil || message.From == nil {
+	if !a.enterChatState(ctx, message.Chat.ID, telegram.StateWaitingLookupQuery, actorTGID, role, nil) {
+		a.sendText(message.Chat.ID, chatStateBusyText)
 		return
 	}
-
-	_, role, err := a.resolveActorRole(ctx, message.From)
-	if err != nil {
-		a.logger.Warn("resolve actor role for history", "error", err, "tg_id", message.From.ID)
-		a.sendText(message.Chat.ID, "Не удалось открыть History")
-		return
-	}
-	if role != enums.RoleOwner {
-		a.sendText(message.Chat.ID, "Dating App: нет доступа")
-		return
-	}
-
-	entries, err := a.auditService.ListRecent(ctx, 50)
-	if err != nil {
-		a.logger.Warn("list recent audit", "error", err)
-		a.sendText(message.Chat.ID, "Не удалось загрузить History")
-		return
-	}
-
-	a.sendAuditHistory(message.Chat.ID, entries)
+	a.sendText(message.Chat.ID, "Введите @username или tg_id")
 }
 
 func (a *App) handleWorkStatsEntry(ctx context.Context, message *tgbotapi.Message) {
@@ -432,7 +395,7 @@ func (a *App) handleWorkStatsEntry(ctx context.Context, message *tgbotapi.Messag
 		a.sendText(message.Chat.ID, "Не удалось открыть Work Stats")
 		return
 	}
-	if role != enums.RoleOwner {
+	if !a.accessService.Can(ctx, role, enums.PermissionStatsView) {
 		a.sendText(message.Chat.ID, "Dating App: нет доступа")
 		return
 	}
@@ -462,7 +425,7 @@ func (a *App) handleSystemEntry(ctx context.Context, message *tgbotapi.Message)
 		a.sendText(message.Chat.ID, "Не удалось открыть System")
 		return
 	}
-	if role != enums.RoleOwner {
+	if !a.accessService.Can(ctx, role, enums.PermissionSystemRegistration) {
 		a.sendText(message.Chat.ID, "Dating App: нет доступа")
 		return
 	}
@@ -474,7 +437,7 @@ func (a *App) handleCallback(ctx context.Context, query *tgbotapi.CallbackQuery)
 	if query == nil || query.From == nil {
 		return
 	}
-	ctx = adminhttp.WithActorTGID(ctx, query.From.ID)
+	ctx = a.withActor(ctx, query.From)
 
 	chatID, ok := callbackChatID(query)
 	if !ok {
@@ -482,6 +445,14 @@ func (a *App) handleCallback(ctx context.Context, query *tgbotapi.CallbackQuery)
 		return
 	}
 
+	// Double taps and concurrent deliveries of the same button run at most once at a time.
+	inFlightKey := fmt.Sprintf("cb:%d:%s", chatID, query.Data)
+	if !a.claim(ctx, inFlightKey, callbackInFlightTTL) {
+		a.answerCallback(query.ID, "Уже обрабатывается", false)
+		return
+	}
+	defer a.releaseClaim(ctx, inFlightKey)
+
 	ackText := ""
 	ackAlert := false
 	defer a.answerCallback(query.ID, ackText, ackAlert)
@@ -502,6 +473,20 @@ func (a *App) handleCallback(ctx context.Context, query *tgbotapi.CallbackQuery)
 		ackText, ackAlert = a.handleSystemCallback(ctx, chatID, query, parts)
 	case callbackPrefixWorkStats:
 		ackText, ackAlert = a.handleWorkStatsCallback(ctx, chatID, query, parts)
+	case callbackPrefixReview:
+		ackText, ackAlert = a.handleReviewCallback(ctx, chatID, query, parts)
+	case callbackPrefixAudit:
+		ackText, ackAlert = a.handleAuditCallback(ctx, chatID, query, parts)
+	case callbackPrefixReasons:
+		ackText, ackAlert = a.handleRejectReasonCallback(ctx, chatID, query, parts)
+	case callbackPrefixWorkload:
+		ackText, ackAlert = a.handleWorkloadCallback(ctx, chatID, query, parts)
+	case callbackPrefixBatch:
+		ackText, ackAlert = a.handleBatchCallback(ctx, chatID, query, parts)
+	case callbackPrefixFlags:
+		ackText, ackAlert = a.handleFlagCallback(ctx, chatID, query, parts)
+	case callbackPrefixWaitlist:
+		ackText, ackAlert = a.handleWaitlistCallback(ctx, chatID, query, parts)
 	}
 }
 
@@ -510,24 +495,21 @@ func (a *App) handleAccessCallback(ctx context.Context, chatID int64, query *tgb
 	if err != nil {
 		return "Не удалось определить роль", true
 	}
-	if !a.accessService.CanOpenAccess(actorRole) {
+	if !a.accessService.CanOpenAccess(ctx, actorRole) {
 		return "Нет доступа", true
 	}
 
 	switch parts[1] {
 	case "root":
-		a.sendAccessScreen(ctx, chatID)
+		a.sendAccessScreen(ctx, chatID, actorRole)
 	case "back":
-		a.sendMainMenu(chatID, actorRole)
+		a.sendMainMenu(ctx, chatID, actorRole)
 	case "add":
 		if len(parts) < 3 {
 			return "", false
 		}
-		targetRole, valid := parseAssignableRole(parts[2])
-		if !valid {
-			return "", false
-		}
-		if !a.accessService.CanGrantRole(actorRole, targetRole) {
+		targetRole := enums.NormalizeRole(parts[2])
+		if !a.accessService.CanManageRole(ctx, actorRole, targetRole) {
 			return "Недостаточно прав", true
 		}
 		a.sendRecentUsersScreen(ctx, chatID, targetRole)
@@ -535,15 +517,12 @@ func (a *App) handleAccessCallback(ctx context.Context, chatID int64, query *tgb
 		if len(parts) < 4 {
 			return "", false
 		}
-		targetRole, valid := parseAssignableRole(parts[2])
-		if !valid {
-			return "", false
-		}
+		targetRole := enums.NormalizeRole(parts[2])
 		targetTGID, err := parseTGID(parts[3])
 		if err != nil {
 			return "Некорректный пользователь", true
 		}
-		if !a.accessService.CanGrantRole(actorRole, targetRole) {
+		if !a.accessService.CanManageRole(ctx, actorRole, targetRole) {
 			return "Недостаточно прав", true
 		}
 
@@ -553,10 +532,7 @@ func (a *App) handleAccessCallback(ctx context.Context, chatID int64, query *tgb
 		if len(parts) < 4 {
 			return "", false
 		}
-		targetRole, valid := parseAssignableRole(parts[2])
-		if !valid {
-			return "", false
-		}
+		targetRole := enums.NormalizeRole(parts[2])
 		targetTGID, err := parseTGID(parts[3])
 		if err != nil {
 			return "Некорректный пользователь", true
@@ -577,7 +553,7 @@ func (a *App) handleAccessCallback(ctx context.Context, chatID int64, query *tgb
 		}
 
 		a.sendText(chatID, fmt.Sprintf("Роль %s назначена: %s", targetRole, renderUserLabel(targetUser.TgID, targetUser.Username)))
-		a.sendAccessScreen(ctx, chatID)
+		a.sendAccessScreen(ctx, chatID, actorRole)
 	case "edit":
 		a.sendEditListScreen(ctx, chatID)
 	case "editsel":
@@ -595,10 +571,10 @@ func (a *App) handleAccessCallback(ctx context.Context, chatID int64, query *tgb
 			return "Не удалось загрузить роль", true
 		}
 		if targetRole == enums.RoleNone {
-			a.sendText(chatID, "У пользователя нет активной роли ADMIN/MODERATOR")
+			a.sendText(chatID, "У пользователя нет активной роли")
 			return "", false
 		}
-		if !a.accessService.CanRevokeRole(actorRole, targetRole) {
+		if !a.accessService.CanRevokeRole(ctx, actorRole, targetRole) {
 			return "Недостаточно прав", true
 		}
 
@@ -632,7 +608,9 @@ func (a *App) handleAccessCallback(ctx context.Context, chatID int64, query *tgb
 		}
 
 		a.sendText(chatID, fmt.Sprintf("Роль %s снята: %s", targetRole, renderUserLabel(targetUser.TgID, targetUser.Username)))
-		a.sendAccessScreen(ctx, chatID)
+		a.sendAccessScreen(ctx, chatID, actorRole)
+	case "roles", "re", "rm", "rs", "rnew":
+		return a.handleRoleDefinitionCallback(ctx, chatID, actorTGID, actorRole, parts)
 	}
 
 	return "", false
@@ -643,7 +621,7 @@ func (a *App) handleModerationCallback(ctx context.Context, chatID int64, query
 	if err != nil {
 		return "Не удалось определить роль", true
 	}
-	if actorRole != enums.RoleOwner && actorRole != enums.RoleAdmin && actorRole != enums.RoleModerator {
+	if !a.accessService.Can(ctx, actorRole, enums.PermissionModerationDecide) {
 		return "Нет доступа", true
 	}
 
@@ -666,11 +644,39 @@ func (a *App) handleModerationCallback(ctx context.Context, chatID int64, query
 		if err != nil {
 			return "Некорректный item id", true
 		}
-		if _, err := a.approveAndContinue(ctx, chatID, actorTGID, actorRole, itemID); err != nil {
+		if _, err := a.approveAndContinue(ctx, chatID, actorTGID, actorRole, itemID, false); err != nil {
+			if errors.Is(err, postgres.ErrModerationItemNotPending) {
+				return "Анкета уже обработана", true
+			}
+			if errors.Is(err, postgres.ErrModerationItemLocked) {
+				return itemLockedText, true
+			}
 			a.logger.Warn("approve moderation item", "error", err, "item_id", itemID, "tg_id", actorTGID)
 			return "Не удалось одобрить анкету", true
 		}
 		return "Одобрено", false
+	case "verify":
+		if len(parts) < 3 {
+			return "", false
+		}
+		itemID, err := parseTGID(parts[2])
+		if err != nil {
+			return "Некорректный item id", true
+		}
+		if _, err := a.approveAndContinue(ctx, chatID, actorTGID, actorRole, itemID, true); err != nil {
+			if errors.Is(err, moderationsvc.ErrCircleMissing) {
+				return "Нет кружка для верификации", true
+			}
+			if errors.Is(err, postgres.ErrModerationItemNotPending) {
+				return "Анкета уже обработана", true
+			}
+			if errors.Is(err, postgres.ErrModerationItemLocked) {
+				return itemLockedText, true
+			}
+			a.logger.Warn("approve moderation item as verified", "error", err, "item_id", itemID, "tg_id", actorTGID)
+			return "Не удалось одобрить анкету", true
+		}
+		return "Одобрено, верифицирован", false
 	case "reject":
 		if len(parts) < 3 {
 			return "", false
@@ -679,7 +685,9 @@ func (a *App) handleModerationCallback(ctx context.Context, chatID int64, query
 		if err != nil {
 			return "Некорректный item id", true
 		}
-		a.sendRejectReasonPrompt(chatID, itemID)
+		if !a.sendRejectReasonPrompt(ctx, chatID, itemID) {
+			return "Не удалось загрузить причины отказа", true
+		}
 		return "Выберите причину", false
 	case "reason":
 		if len(parts) < 4 {
@@ -690,23 +698,27 @@ func (a *App) handleModerationCallback(ctx context.Context, chatID int64, query
 			return "Некорректный item id", true
 		}
 		reasonCode := normalizeReasonCode(parts[3])
-		if !isValidRejectReason(reasonCode) {
+		if !a.isActiveRejectReason(ctx, reasonCode) {
 			return "Некорректный reason code", true
 		}
 
-		if reasonCode == "OTHER" {
-			a.rejectMu.Lock()
-			a.rejectByChat[chatID] = rejectCommentState{
-				ActorTGID: actorTGID,
-				ActorRole: actorRole,
-				ItemID:    itemID,
+		if reasonCode == enums.RejectReasonOther {
+			if !a.enterChatState(ctx, chatID, telegram.StateWaitingRejectReason, actorTGID, actorRole, func(session *statestore.Session) {
+				session.ItemID = itemID
+			}) {
+				return chatStateBusyText, true
 			}
-			a.rejectMu.Unlock()
 			a.sendText(chatID, "Введите комментарий для причины OTHER (или '-' без комментария)")
 			return "Ожидаю комментарий", false
 		}
 
 		if _, err := a.rejectAndContinue(ctx, chatID, actorTGID, actorRole, itemID, reasonCode, ""); err != nil {
+			if errors.Is(err, postgres.ErrModerationItemNotPending) {
+				return "Анкета уже обработана", true
+			}
+			if errors.Is(err, postgres.ErrModerationItemLocked) {
+				return itemLockedText, true
+			}
 			a.logger.Warn("reject moderation item", "error", err, "item_id", itemID, "reason_code", reasonCode)
 			return "Не удалось отклонить анкету", true
 		}
@@ -721,15 +733,14 @@ func (a *App) handleLookupCallback(ctx context.Context, chatID int64, query *tgb
 	if err != nil {
 		return "Не удалось определить роль", true
 	}
-	if actorRole != enums.RoleOwner && actorRole != enums.RoleAdmin {
+	if !a.accessService.Can(ctx, actorRole, enums.PermissionUsersViewPrivate) {
 		return "Нет доступа", true
 	}
 
 	switch parts[1] {
 	case "back":
-		deleteLookupInputState(a, chatID)
-		deleteLookupSessionState(a, chatID)
-		a.sendMainMenu(chatID, actorRole)
+		a.clearChatState(ctx, chatID)
+		a.sendMainMenu(ctx, chatID, actorRole)
 		return "", false
 	case "act":
 		if len(parts) < 4 {
@@ -739,6 +750,9 @@ func (a *App) handleLookupCallback(ctx context.Context, chatID int64, query *tgb
 		if !isValidLookupAction(action) {
 			return "Некорректное действие", true
 		}
+		if !a.accessService.Can(ctx, actorRole, lookupActionPermission(action)) {
+			return "Недостаточно прав", true
+		}
 
 		userID, err := parseTGID(parts[3])
 		if err != nil || userID <= 0 {
@@ -761,7 +775,12 @@ func (a *App) handleLookupCallback(ctx context.Context, chatID int64, query *tgb
 			return "Некорректный user id", true
 		}
 
-		if err := a.executeLookupAction(ctx, chatID, actorTGID, actorRole, action, userID); err != nil {
+		if action == lookupActionBan {
+			a.sendBanReasonPrompt(chatID, userID)
+			return "Выберите причину", false
+		}
+
+		if err := a.executeLookupAction(ctx, chatID, actorTGID, actorRole, action, userID, model.BanInput{}); err != nil {
 			a.logger.Warn("execute lookup action", "error", err, "action", action, "user_id", userID, "tg_id", actorTGID)
 			return "Не удалось выполнить действие", true
 		}
@@ -782,6 +801,25 @@ func (a *App) handleLookupCallback(ctx context.Context, chatID int64, query *tgb
 		}
 		a.sendLookupUserCard(chatID, user)
 		return "Отменено", false
+	case "banr", "band":
+		return a.handleBanPickCallback(ctx, chatID, actorTGID, actorRole, parts)
+	case "dos":
+		return a.handleDossierCallback(ctx, chatID, actorTGID, actorRole, parts)
+	case "card":
+		if len(parts) < 3 {
+			return "", false
+		}
+		userID, err := parseTGID(parts[2])
+		if err != nil || userID <= 0 {
+			return "Некорректный user id", true
+		}
+		user, err := a.lookupService.GetByUserID(ctx, userID)
+		if err != nil {
+			a.logger.Warn("reload lookup user card", "error", err, "user_id", userID)
+			return "Не удалось обновить карточку", true
+		}
+		a.sendLookupUserCard(chatID, user)
+		return "", false
 	default:
 		return "", false
 	}
@@ -792,7 +830,7 @@ func (a *App) handleSystemCallback(ctx context.Context, chatID int64, query *tgb
 	if err != nil {
 		return "Не удалось определить роль", true
 	}
-	if actorRole != enums.RoleOwner {
+	if !a.accessService.Can(ctx, actorRole, enums.PermissionSystemRegistration) {
 		return "Нет доступа", true
 	}
 
@@ -822,8 +860,19 @@ func (a *App) handleSystemCallback(ctx context.Context, chatID int64, query *tgb
 		}
 		a.sendUsersCountScreen(chatID, count.Total, count.Approved)
 		return "", false
+	case "exports":
+		a.sendExportQueueScreen(ctx, chatID)
+		return "", false
+	case "exportsretry":
+		requeued, err := a.exportWorker.RequeueFailed(ctx)
+		if err != nil {
+			a.logger.Warn("requeue failed exports", "error", err, "tg_id", actorTGID)
+			return "Не удалось перезапустить экспорт", true
+		}
+		a.sendExportQueueScreen(ctx, chatID)
+		return fmt.Sprintf("Возвращено в очередь: %d", requeued), false
 	case "back":
-		a.sendMainMenu(chatID, actorRole)
+		a.sendMainMenu(ctx, chatID, actorRole)
 		return "", false
 	default:
 		return "", false
@@ -835,7 +884,7 @@ func (a *App) handleWorkStatsCallback(ctx context.Context, chatID int64, query *
 	if err != nil {
 		return "Не удалось определить роль", true
 	}
-	if actorRole != enums.RoleOwner {
+	if !a.accessService.Can(ctx, actorRole, enums.PermissionStatsView) {
 		return "Нет доступа", true
 	}
 
@@ -843,27 +892,61 @@ func (a *App) handleWorkStatsCallback(ctx context.Context, chatID int64, query *
 		return "", false
 	}
 	switch parts[1] {
+	case "sla":
+		report, err := a.statsService.BuildSLAReport(ctx)
+		if err != nil {
+			a.logger.Warn("build moderation sla report", "error", err, "tg_id", query.From.ID)
+			return "Не удалось загрузить SLA", true
+		}
+		a.sendModerationSLAScreen(chatID, report)
+		return "", false
+	case "reviews":
+		stats, err := a.reviewService.Stats(ctx)
+		if err != nil {
+			a.logger.Warn("build review stats", "error", err, "tg_id", query.From.ID)
+			return "Не удалось загрузить статистику проверок", true
+		}
+		a.sendReviewStatsScreen(chatID, stats)
+		return "", false
+	case "shifts":
+		items, err := a.workloadService.ShiftThroughput(ctx)
+		if err != nil {
+			a.logger.Warn("build shift throughput", "error", err, "tg_id", query.From.ID)
+			return "Не удалось загрузить статистику смен", true
+		}
+		a.sendShiftThroughputScreen(chatID, items)
+		return "", false
+	case "batch":
+		stats, err := a.statsService.BuildBatchReport(ctx)
+		if err != nil {
+			a.logger.Warn("build batch throughput", "error", err, "tg_id", query.From.ID)
+			return "Не удалось загрузить статистику пакетов", true
+		}
+		a.sendInline(chatID, ui.RenderModerationBatchStats(stats), [][]telegram.InlineButton{
+			{{Text: "Back", Data: fmt.Sprintf("%s:back", callbackPrefixWorkStats)}},
+		})
+		return "", false
 	case "back":
-		a.sendMainMenu(chatID, actorRole)
+		a.sendMainMenu(ctx, chatID, actorRole)
 		return "", false
 	default:
 		return "", false
 	}
 }
 
-func (a *App) sendRejectReasonPrompt(chatID int64, moderationItemID int64) {
-	rows := make([][]telegram.InlineButton, 0, len(rejectReasonOptions)+1)
-	for _, option := range rejectReasonOptions {
-		rows = append(rows, []telegram.InlineButton{{
-			Text: option.Label,
-			Data: fmt.Sprintf("%s:reason:%d:%s", callbackPrefixModeration, moderationItemID, option.Code),
-		}})
+func (a *App) sendRejectReasonPrompt(ctx context.Context, chatID int64, moderationItemID int64) bool {
+	rows, ok := a.rejectReasonRows(ctx, func(code string) string {
+		return fmt.Sprintf("%s:reason:%d:%s", callbackPrefixModeration, moderationItemID, code)
+	})
+	if !ok {
+		return false
 	}
 	rows = append(rows, []telegram.InlineButton{{
 		Text: "⬅️ Back",
 		Data: fmt.Sprintf("%s:decision:%d", callbackPrefixModeration, moderationItemID),
 	}})
 	a.sendInline(chatID, "Выберите причину отклонения", rows)
+	return true
 }
 
 func (a *App) rejectAndContinue(
@@ -901,21 +984,27 @@ func (a *App) approveAndContinue(
 	actorTGID int64,
 	actorRole enums.Role,
 	moderationItemID int64,
+	verified bool,
 ) (moderationsvc.ApproveResult, error) {
 	result, err := a.moderationService.Approve(ctx, moderationsvc.ApproveInput{
 		ActorTGID:        actorTGID,
 		ActorRole:        actorRole,
 		ModerationItemID: moderationItemID,
+		Verified:         verified,
 	})
 	if err != nil {
 		return moderationsvc.ApproveResult{}, err
 	}
 
-	if err := a.auditService.LogModerationApprove(ctx, actorTGID, result.TargetUserID, result.ModerationItemID); err != nil {
+	if err := a.auditService.LogModerationApprove(ctx, actorTGID, result.TargetUserID, result.ModerationItemID, result.Verified); err != nil {
 		a.logger.Warn("write moderation approve audit", "error", err)
 	}
 
-	a.sendText(chatID, fmt.Sprintf("Анкета #%d одобрена", result.ModerationItemID))
+	if result.Verified {
+		a.sendText(chatID, fmt.Sprintf("Анкета #%d одобрена, пользователь верифицирован", result.ModerationItemID))
+	} else {
+		a.sendText(chatID, fmt.Sprintf("Анкета #%d одобрена", result.ModerationItemID))
+	}
 	a.acquireAndSendNextModerationItem(ctx, chatID, actorTGID)
 	return result, nil
 }
@@ -959,37 +1048,45 @@ func (a *App) sendLookupActionConfirm(chatID int64, action string, userID int64)
 	a.sendInline(chatID, text, rows)
 }
 
-func (a *App) executeLookupAction(ctx context.Context, chatID int64, actorTGID int64, actorRole enums.Role, action string, userID int64) error {
+func (a *App) executeLookupAction(ctx context.Context, chatID int64, actorTGID int64, actorRole enums.Role, action string, userID int64, ban model.BanInput) error {
+	if !a.accessService.Can(ctx, actorRole, lookupActionPermission(action)) {
+		return access.ErrAccessDenied
+	}
+
 	target, err := a.lookupService.GetByUserID(ctx, userID)
 	if err != nil {
 		return err
 	}
 
-	session, hasSession := getLookupSessionState(a, chatID)
+	session, hasSession := a.loadChatState(ctx, chatID)
 	queryText := strconv.FormatInt(userID, 10)
 	if hasSession && session.ActorTGID == actorTGID {
-		if strings.TrimSpace(session.Query) != "" {
-			queryText = strings.TrimSpace(session.Query)
+		if strings.TrimSpace(session.LookupQuery) != "" {
+			queryText = strings.TrimSpace(session.LookupQuery)
 		}
 	}
 
 	switch action {
 	case lookupActionBan:
-		reason := "BANNED_BY_ADMIN"
-		if err := a.bansService.Ban(ctx, userID, reason, actorTGID); err != nil {
+		ban.UserID = userID
+		ban.ActorTGID = actorTGID
+		state, err := a.bansService.Ban(ctx, ban)
+		if err != nil {
 			return err
 		}
 		if err := a.lookupService.LogAction(ctx, actorTGID, actorRole, queryText, &userID, lookupActionBan, map[string]interface{}{
 			"target_user_id": target.UserID,
 			"target_tg_id":   target.TGID,
-			"reason":         reason,
+			"reason_code":    ban.ReasonCode,
+			"reason":         ban.Reason,
+			"duration":       ban.Duration,
 		}); err != nil {
 			a.logger.Warn("log ban action", "error", err, "user_id", userID)
 		}
-		if err := a.auditService.LogBan(ctx, actorTGID, target.UserID, reason); err != nil {
+		if err := a.auditService.LogBan(ctx, actorTGID, target.UserID, ban, state.ExpiresAt); err != nil {
 			a.logger.Warn("log ban audit", "error", err, "user_id", userID)
 		}
-		a.sendText(chatID, fmt.Sprintf("Пользователь user_id=%d заблокирован", userID))
+		a.sendText(chatID, renderBanApplied(userID, ban, state))
 	case lookupActionUnban:
 		if err := a.bansService.Unban(ctx, userID, actorTGID); err != nil {
 			return err
@@ -1062,6 +1159,7 @@ func (a *App) sendLookupUserCard(chatID int64, user model.LookupUser) {
 		},
 		{
 			{Text: "Force Review", Data: fmt.Sprintf("%s:act:%s:%d", callbackPrefixLookup, lookupActionForceReview, user.UserID)},
+			{Text: "📂 Досье", Data: fmt.Sprintf("%s:dos:%d:%s:0", callbackPrefixLookup, user.UserID, enums.DossierSectionOverview)},
 		},
 		{
 			{Text: "Back", Data: fmt.Sprintf("%s:back", callbackPrefixLookup)},
@@ -1070,34 +1168,6 @@ func (a *App) sendLookupUserCard(chatID int64, user model.LookupUser) {
 	a.sendInline(chatID, "Действия", rows)
 }
 
-func (a *App) sendAuditHistory(chatID int64, entries []model.Audit) {
-	if len(entries) == 0 {
-		a.sendText(chatID, "History пуста")
-		return
-	}
-
-	lines := make([]string, 0, len(entries)+1)
-	lines = append(lines, "History (последние 50):")
-	for _, entry := range entries {
-		payload := strings.TrimSpace(string(entry.Payload))
-		if payload == "" {
-			payload = "{}"
-		}
-		line := fmt.Sprintf(
-			"%s | %s | actor=%d | %s",
-			entry.CreatedAt.UTC().Format("2006-01-02 15:04:05"),
-			entry.Action,
-			entry.ActorTGID,
-			payload,
-		)
-		lines = append(lines, line)
-	}
-
-	for _, chunk := range splitByLength(lines, 3600) {
-		a.sendText(chatID, chunk)
-	}
-}
-
 func (a *App) sendSystemScreen(ctx context.Context, chatID int64) {
 	enabled, err := a.systemService.GetRegistrationEnabled(ctx)
 	if err != nil {
@@ -1111,9 +1181,17 @@ func (a *App) sendSystemScreen(ctx context.Context, chatID int64) {
 		regText = "Registration: ON"
 	}
 
+	waitlistText := "⏳ Лист ожидания"
+	if stats, err := a.systemService.WaitlistStats(ctx); err == nil {
+		waitlistText = fmt.Sprintf("⏳ Лист ожидания: %d", stats.Waiting)
+	}
+
 	rows := [][]telegram.InlineButton{
 		{{Text: regText, Data: fmt.Sprintf("%s:toggle", callbackPrefixSystem)}},
+		{{Text: waitlistText, Data: fmt.Sprintf("%s:view", callbackPrefixWaitlist)}},
 		{{Text: "Users count", Data: fmt.Sprintf("%s:users", callbackPrefixSystem)}},
+		{{Text: "Exports", Data: fmt.Sprintf("%s:exports", callbackPrefixSystem)}},
+		{{Text: "🚩 Флаги", Data: fmt.Sprintf("%s:list", callbackPrefixFlags)}},
 		{{Text: "Back", Data: fmt.Sprintf("%s:back", callbackPrefixSystem)}},
 	}
 	a.sendInline(chatID, "System", rows)
@@ -1127,6 +1205,22 @@ func (a *App) sendUsersCountScreen(chatID int64, total int64, approved int64) {
 	a.sendInline(chatID, text, rows)
 }
 
+func (a *App) sendExportQueueScreen(ctx context.Context, chatID int64) {
+	stats, err := a.exportWorker.Stats(ctx)
+	if err != nil {
+		a.logger.Warn("load export queue stats", "error", err)
+		a.sendText(chatID, "Не удалось загрузить Exports")
+		return
+	}
+
+	rows := [][]telegram.InlineButton{}
+	if stats.Failed > 0 {
+		rows = append(rows, []telegram.InlineButton{{Text: "Retry failed", Data: fmt.Sprintf("%s:exportsretry", callbackPrefixSystem)}})
+	}
+	rows = append(rows, []telegram.InlineButton{{Text: "Back", Data: fmt.Sprintf("%s:root", callbackPrefixSystem)}})
+	a.sendInline(chatID, ui.RenderExportQueue(stats, a.exportWorker.SinkName(), time.Now().UTC()), rows)
+}
+
 func (a *App) sendWorkStatsScreen(chatID int64, report model.WorkStatsReport) {
 	text := ui.RenderWorkStats(report)
 	chunks := splitByLength(strings.Split(text, "\n"), 3600)
@@ -1139,12 +1233,39 @@ func (a *App) sendWorkStatsScreen(chatID int64, report model.WorkStatsReport) {
 
 	text = chunks[len(chunks)-1]
 	rows := [][]telegram.InlineButton{
+		{{Text: "SLA", Data: fmt.Sprintf("%s:sla", callbackPrefixWorkStats)}},
+		{{Text: "Second review", Data: fmt.Sprintf("%s:reviews", callbackPrefixWorkStats)}},
+		{{Text: "Смены", Data: fmt.Sprintf("%s:shifts", callbackPrefixWorkStats)}},
+		{{Text: "Пакеты", Data: fmt.Sprintf("%s:batch", callbackPrefixWorkStats)}},
 		{{Text: "Back", Data: fmt.Sprintf("%s:back", callbackPrefixWorkStats)}},
 	}
 	a.sendInline(chatID, text, rows)
 }
 
-func (a *App) sendAccessScreen(ctx context.Context, chatID int64) {
+func (a *App) sendModerationSLAScreen(chatID int64, report model.ModerationSLAReport) {
+	text := ui.RenderModerationSLA(report)
+	chunks := splitByLength(strings.Split(text, "\n"), 3600)
+	if len(chunks) == 0 {
+		chunks = []string{"SLA"}
+	}
+	for i := 0; i < len(chunks)-1; i++ {
+		a.sendText(chatID, chunks[i])
+	}
+
+	rows := [][]telegram.InlineButton{
+		{{Text: "Back", Data: fmt.Sprintf("%s:back", callbackPrefixWorkStats)}},
+	}
+	a.sendInline(chatID, chunks[len(chunks)-1], rows)
+}
+
+func (a *App) sendReviewStatsScreen(chatID int64, stats model.ModerationReviewStats) {
+	rows := [][]telegram.InlineButton{
+		{{Text: "Back", Data: fmt.Sprintf("%s:back", callbackPrefixWorkStats)}},
+	}
+	a.sendInline(chatID, ui.RenderReviewStats(stats), rows)
+}
+
+func (a *App) sendAccessScreen(ctx context.Context, chatID int64, actorRole enums.Role) {
 	text, err := a.buildAccessSummary(ctx)
 	if err != nil {
 		a.logger.Warn("build access summary", "error", err)
@@ -1152,16 +1273,28 @@ func (a *App) sendAccessScreen(ctx context.Context, chatID int64) {
 		return
 	}
 
-	rows := [][]telegram.InlineButton{
-		{
-			{Text: "➕ Add Moderator", Data: "acc:add:MODERATOR"},
-			{Text: "➕ Add Admin", Data: "acc:add:ADMIN"},
-		},
-		{
+	rows := make([][]telegram.InlineButton, 0, 4)
+	row := make([]telegram.InlineButton, 0, 2)
+	for _, def := range a.accessService.AssignableRoles(ctx, actorRole) {
+		row = append(row, telegram.InlineButton{Text: "➕ " + string(def.Name), Data: "acc:add:" + string(def.Name)})
+		if len(row) == 2 {
+			rows = append(rows, row)
+			row = make([]telegram.InlineButton, 0, 2)
+		}
+	}
+	if len(row) > 0 {
+		rows = append(rows, row)
+	}
+	rows = append(rows,
+		[]telegram.InlineButton{
 			{Text: "✏️ Edit", Data: "acc:edit"},
-			{Text: "⬅️ Back", Data: "acc:back"},
+			{Text: "🧩 Роли", Data: "acc:roles"},
 		},
+	)
+	if a.accessService.Can(ctx, actorRole, enums.PermissionAccessManage) {
+		rows = append(rows, []telegram.InlineButton{{Text: "🗓 Нагрузка и смены", Data: callbackPrefixWorkload + ":list"}})
 	}
+	rows = append(rows, []telegram.InlineButton{{Text: "⬅️ Back", Data: "acc:back"}})
 	a.sendInline(chatID, text, rows)
 }
 
@@ -1215,9 +1348,9 @@ func (a *App) sendEditListScreen(ctx context.Context, chatID int64) {
 	}
 	rows = append(rows, []telegram.InlineButton{{Text: "⬅️ Back", Data: "acc:root"}})
 
-	text := "Выберите Admin/Moderator для изменения роли"
+	text := "Выберите пользователя для изменения роли"
 	if len(assignments) == 0 {
-		text = "Нет активных ролей Admin/Moderator"
+		text = "Нет активных ролей"
 	}
 	a.sendInline(chatID, text, rows)
 }
@@ -1237,30 +1370,32 @@ func (a *App) buildAccessSummary(ctx context.Context) (string, error) {
 		return "", err
 	}
 
-	admins := make([]string, 0)
-	moderators := make([]string, 0)
+	byRole := make(map[enums.Role][]string)
+	order := make([]enums.Role, 0, 8)
+	for _, def := range a.accessService.ListRoleDefinitions(ctx) {
+		if def.Name == enums.RoleOwner {
+			continue
+		}
+		byRole[def.Name] = []string{}
+		order = append(order, def.Name)
+	}
 	for _, assignment := range assignments {
-		entry := "- " + renderUserLabel(assignment.TgID, assignment.Username)
-		switch assignment.Role {
-		case enums.RoleAdmin:
-			admins = append(admins, entry)
-		case enums.RoleModerator:
-			moderators = append(moderators, entry)
+		if _, ok := byRole[assignment.Role]; !ok {
+			order = append(order, assignment.Role)
 		}
+		byRole[assignment.Role] = append(byRole[assignment.Role], "- "+renderUserLabel(assignment.TgID, assignment.Username))
 	}
 
-	if len(admins) == 0 {
-		admins = append(admins, "- —")
-	}
-	if len(moderators) == 0 {
-		moderators = append(moderators, "- —")
+	var b strings.Builder
+	b.WriteString("Access")
+	for _, role := range order {
+		entries := byRole[role]
+		if len(entries) == 0 {
+			entries = []string{"- —"}
+		}
+		b.WriteString(fmt.Sprintf("\n\n%s:\n%s", role, strings.Join(entries, "\n")))
 	}
-
-	return fmt.Sprintf(
-		"Access\n\nAdmins:\n%s\n\nModerators:\n%s",
-		strings.Join(admins, "\n"),
-		strings.Join(moderators, "\n"),
-	), nil
+	return b.String(), nil
 }
 
 func (a *App) safeGetUser(ctx context.Context, tgID int64) model.BotUser {
@@ -1274,10 +1409,24 @@ func (a *App) safeGetUser(ctx context.Context, tgID int64) model.BotUser {
 	return user
 }
 
+// withActor tags admin API calls with the actor and their role so the backend checks the actor's permissions.
+func (a *App) withActor(ctx context.Context, user *tgbotapi.User) context.Context {
+	ctx = adminhttp.WithActorTGID(ctx, user.ID)
+	role, err := a.accessService.ResolveRole(ctx, user.ID)
+	if err != nil {
+		a.logger.Warn("resolve actor role", "error", err, "tg_id", user.ID)
+		return ctx
+	}
+	return adminhttp.WithActorRole(ctx, role)
+}
+
 func (a *App) resolveActorRole(ctx context.Context, user *tgbotapi.User) (int64, enums.Role, error) {
 	if user == nil {
 		return 0, enums.RoleNone, nil
 	}
+	if role := adminhttp.ActorRoleFromContext(ctx); role != "" && adminhttp.ActorTGIDFromContext(ctx) == user.ID {
+		return user.ID, role, nil
+	}
 	role, err := a.accessService.ResolveRole(ctx, user.ID)
 	if err != nil {
 		return user.ID, enums.RoleNone, err
@@ -1285,8 +1434,9 @@ func (a *App) resolveActorRole(ctx context.Context, user *tgbotapi.User) (int64,
 	return user.ID, role, nil
 }
 
-func (a *App) sendMainMenu(chatID int64, role enums.Role) {
-	text, menu := ui.RenderStart(role)
+func (a *App) sendMainMenu(ctx context.Context, chatID int64, role enums.Role) {
+	def, _ := a.accessService.RoleDefinition(ctx, role)
+	text, menu := ui.RenderStart(role, def)
 	response := tgbotapi.NewMessage(chatID, text)
 	response.ReplyMarkup = telegram.BuildReplyKeyboard(menu)
 	if err := a.tg.Send(response); err != nil {
@@ -1325,17 +1475,6 @@ func callbackChatID(query *tgbotapi.CallbackQuery) (int64, bool) {
 	return query.Message.Chat.ID, true
 }
 
-func parseAssignableRole(raw string) (enums.Role, bool) {
-	switch strings.ToUpper(strings.TrimSpace(raw)) {
-	case string(enums.RoleAdmin):
-		return enums.RoleAdmin, true
-	case string(enums.RoleModerator):
-		return enums.RoleModerator, true
-	default:
-		return enums.RoleNone, false
-	}
-}
-
 func parseTGID(raw string) (int64, error) {
 	return strconv.ParseInt(strings.TrimSpace(raw), 10, 64)
 }
@@ -1344,6 +1483,17 @@ func normalizeLookupAction(raw string) string {
 	return strings.ToUpper(strings.TrimSpace(raw))
 }
 
+func lookupActionPermission(action string) enums.Permission {
+	switch action {
+	case lookupActionBan, lookupActionUnban:
+		return enums.PermissionUsersBan
+	case lookupActionForceReview:
+		return enums.PermissionModerationDecide
+	default:
+		return enums.PermissionUsersViewPrivate
+	}
+}
+
 func isValidLookupAction(action string) bool {
 	switch action {
 	case lookupActionBan, lookupActionUnban, lookupActionForceReview:
@@ -1356,20 +1506,11 @@ func isValidLookupAction(action string) bool {
 func normalizeReasonCode(raw string) string {
 	trimmed := strings.ToUpper(strings.TrimSpace(raw))
 	if trimmed == "" {
-		return "OTHER"
+		return enums.RejectReasonOther
 	}
 	return trimmed
 }
 
-func isValidRejectReason(code string) bool {
-	for _, option := range rejectReasonOptions {
-		if code == option.Code {
-			return true
-		}
-	}
-	return false
-}
-
 func renderUserLabel(tgID int64, username string) string {
 	name := strings.TrimSpace(username)
 	if name != "" {
@@ -1407,8 +1548,15 @@ func renderLookupUser(user model.LookupUser) string {
 	if user.IsBanned {
 		banned = "true"
 	}
+	banExpires := "-"
+	if user.IsBanned {
+		banExpires = "permanent"
+		if user.BanExpiresAt != nil {
+			banExpires = user.BanExpiresAt.UTC().Format(time.RFC3339)
+		}
+	}
 
-	return strings.Join([]string{
+	lines := []string{
 		"Find user:",
 		fmt.Sprintf("user_id: %d", user.UserID),
 		fmt.Sprintf("tg_id: %d", user.TGID),
@@ -1425,13 +1573,22 @@ func renderLookupUser(user model.LookupUser) string {
 		fmt.Sprintf("moderation_status: %s", defaultText(user.ModerationStatus, "-")),
 		fmt.Sprintf("approved: %t", user.Approved),
 		fmt.Sprintf("banned: %s", banned),
+		fmt.Sprintf("ban_reason_code: %s", defaultText(user.BanReasonCode, "-")),
 		fmt.Sprintf("ban_reason: %s", defaultText(user.BanReason, "-")),
+		fmt.Sprintf("ban_expires_at: %s", banExpires),
 		fmt.Sprintf("plus_expires_at: %s", plus),
 		fmt.Sprintf("boost_until: %s", boost),
 		fmt.Sprintf("superlike_credits: %d", user.SuperlikeCredits),
 		fmt.Sprintf("reveal_credits: %d", user.RevealCredits),
 		fmt.Sprintf("like_tokens: %d", user.LikeTokens),
-	}, "\n")
+	}
+	if len(user.EvasionMatches) > 0 {
+		lines = append(lines, "⚠️ Возможный обход блокировки:")
+		for _, match := range user.EvasionMatches {
+			lines = append(lines, fmt.Sprintf("- user_id=%d tg_id=%d: %s", match.BannedUserID, match.BannedTGID, strings.Join(match.Signals, ", ")))
+		}
+	}
+	return strings.Join(lines, "\n")
 }
 
 func splitByLength(lines []string, maxLen int) []string {
@@ -1466,37 +1623,6 @@ func splitByLength(lines []string, maxLen int) []string {
 	return chunks
 }
 
-func setLookupInputState(a *App, chatID int64, state lookupInputState) {
-	a.lookupInputMu.Lock()
-	defer a.lookupInputMu.Unlock()
-	a.lookupInputByChat[chatID] = state
-}
-
-func deleteLookupInputState(a *App, chatID int64) {
-	a.lookupInputMu.Lock()
-	defer a.lookupInputMu.Unlock()
-	delete(a.lookupInputByChat, chatID)
-}
-
-func setLookupSessionState(a *App, chatID int64, state lookupSessionState) {
-	a.lookupSessionMu.Lock()
-	defer a.lookupSessionMu.Unlock()
-	a.lookupSessionByChat[chatID] = state
-}
-
-func getLookupSessionState(a *App, chatID int64) (lookupSessionState, bool) {
-	a.lookupSessionMu.Lock()
-	defer a.lookupSessionMu.Unlock()
-	state, ok := a.lookupSessionByChat[chatID]
-	return state, ok
-}
-
-func deleteLookupSessionState(a *App, chatID int64) {
-	a.lookupSessionMu.Lock()
-	defer a.lookupSessionMu.Unlock()
-	delete(a.lookupSessionByChat, chatID)
-}
-
 func renderModerationQueueItem(item model.ModerationQueueItem) string {
 	profile := item.Profile
 	goals := "-"
@@ -1530,14 +1656,34 @@ func renderModerationQueueItem(item model.ModerationQueueItem) string {
 		fmt.Sprintf("languages: %s", languages),
 		fmt.Sprintf("occupation: %s", defaultText(profile.Occupation, "-")),
 		fmt.Sprintf("education: %s", defaultText(profile.Education, "-")),
+		fmt.Sprintf("verification: %s", defaultText(profile.VerificationStatus, "-")),
 		fmt.Sprintf("eta_bucket: %s", defaultText(item.ETABucket, "-")),
 		fmt.Sprintf("created_at: %s", item.CreatedAt.UTC().Format(time.RFC3339)),
 		fmt.Sprintf("locked_at: %s", item.LockedAt.UTC().Format(time.RFC3339)),
 	}
+	lines = append(lines, renderPhotoMatches(item.PhotoMatches)...)
 
 	return strings.Join(lines, "\n")
 }
 
+func renderPhotoMatches(matches []model.PhotoMatch) []string {
+	if len(matches) == 0 {
+		return nil
+	}
+
+	lines := []string{"⚠️ Совпадения фото:"}
+	for _, match := range matches {
+		switch match.Source {
+		case "BANNED":
+			label := defaultText(match.Label, fmt.Sprintf("#%d", match.BannedImageID))
+			lines = append(lines, fmt.Sprintf("Фото %d: в списке запрещённых (%s), distance %d", match.Slot, label, match.Distance))
+		default:
+			lines = append(lines, fmt.Sprintf("Фото %d: также используется у user_id=%d, distance %d", match.Slot, match.MatchedUserID, match.Distance))
+		}
+	}
+	return lines
+}
+
 func defaultText(value string, fallback string) string {
 	trimmed := strings.TrimSpace(value)
 	if trimmed == "" {
@@ -0,0 +1,60 @@
+package app
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"bot_moderator/internal/domain/enums"
+	"bot_moderator/internal/repo/adminhttp"
+)
+
+const (
+	slaAlertCheckInterval = 5 * time.Minute
+	slaAlertCooldown      = time.Hour
+)
+
+// runSLAAlerts pings the owner when the moderation queue wait breaches the configured p90.
+func (a *App) runSLAAlerts(ctx context.Context) {
+	if a.cfg.OwnerTGID == 0 || a.cfg.SLAAlertP90Minutes <= 0 {
+		return
+	}
+
+	threshold := time.Duration(a.cfg.SLAAlertP90Minutes) * time.Minute
+	ctx = adminhttp.WithActorTGID(ctx, a.cfg.OwnerTGID)
+	ctx = adminhttp.WithActorRole(ctx, enums.RoleOwner)
+	ticker := time.NewTicker(slaAlertCheckInterval)
+	defer ticker.Stop()
+
+	var lastAlertAt time.Time
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		report, breached, err := a.statsService.CheckSLA(ctx, threshold)
+		if err != nil {
+			a.logger.Warn("check moderation sla", "error", err)
+			continue
+		}
+		if !breached || time.Since(lastAlertAt) < slaAlertCooldown {
+			continue
+		}
+
+		lastAlertAt = time.Now()
+		a.sendText(a.cfg.OwnerTGID, fmt.Sprintf(
+			"⚠️ SLA модерации нарушен: p90 ожидания %s (порог %d мин)\nВ очереди: %d, старейшая: %s, решений за час: %d",
+			formatSLASeconds(report.WaitP90Sec),
+			a.cfg.SLAAlertP90Minutes,
+			report.Pending,
+			formatSLASeconds(report.OldestPendingSec),
+			report.Decided,
+		))
+	}
+}
+
+func formatSLASeconds(seconds float64) string {
+	return (time.Duration(seconds) * time.Second).Round(time.Second).String()
+}
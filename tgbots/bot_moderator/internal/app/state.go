@@ -0,0 +1,113 @@
+package app
+
+import (
+	"context"
+	"time"
+
+	"bot_moderator/internal/domain/enums"
+	"bot_moderator/internal/infra/statestore"
+	"bot_moderator/internal/infra/telegram"
+	"github.com/redis/go-redis/v9"
+)
+
+func (a *App) loadChatState(ctx context.Context, chatID int64) (statestore.Session, bool) {
+	session, ok, err := a.stateStore.Get(ctx, chatID)
+	if err != nil {
+		a.logger.Warn("load chat state", "error", err, "chat_id", chatID)
+		return statestore.Session{}, false
+	}
+	if !ok {
+		return statestore.Session{}, false
+	}
+	session.State = session.State.Normalize()
+	return session, true
+}
+
+// chatStateBusyText answers an attempt to start an input flow while another one is waiting.
+const chatStateBusyText = "Сначала завершите текущий ввод или отправьте /start"
+
+// enterChatState moves the chat into next. It refuses transitions the state table does not
+// allow, so a pending prompt is never silently dropped; /start resets the chat to IDLE.
+func (a *App) enterChatState(
+	ctx context.Context,
+	chatID int64,
+	next telegram.State,
+	actorTGID int64,
+	actorRole enums.Role,
+	update func(*statestore.Session),
+) bool {
+	session, ok := a.loadChatState(ctx, chatID)
+	if !ok || session.ActorTGID != actorTGID {
+		session = statestore.Session{State: telegram.StateIdle}
+	}
+	if !session.State.CanTransition(next) {
+		return false
+	}
+
+	session.State = next
+	session.ActorTGID = actorTGID
+	session.ActorRole = actorRole
+	if update != nil {
+		update(&session)
+	}
+	a.saveChatState(ctx, chatID, session)
+	return true
+}
+
+// resetChatState returns the chat to IDLE while keeping the lookup context.
+func (a *App) resetChatState(ctx context.Context, chatID int64, session statestore.Session) {
+	session.State = telegram.StateIdle
+	session.ItemID = 0
+	session.TargetUserID = 0
+	session.BanReasonCode = ""
+	session.BanDuration = ""
+	session.ReasonCode = ""
+	session.ReasonField = ""
+	session.ShiftModeratorTGID = 0
+	session.FlagKey = ""
+	session.FlagField = ""
+	a.saveChatState(ctx, chatID, session)
+}
+
+func (a *App) clearChatState(ctx context.Context, chatID int64) {
+	if err := a.stateStore.Delete(ctx, chatID); err != nil {
+		a.logger.Warn("clear chat state", "error", err, "chat_id", chatID)
+	}
+}
+
+func (a *App) saveChatState(ctx context.Context, chatID int64, session statestore.Session) {
+	if err := a.stateStore.Set(ctx, chatID, session); err != nil {
+		a.logger.Warn("save chat state", "error", err, "chat_id", chatID, "state", session.State)
+	}
+}
+
+// claim is fail-open: a broken store must not stop the bot from handling updates.
+func (a *App) claim(ctx context.Context, key string, ttl time.Duration) bool {
+	ok, err := a.stateStore.Claim(ctx, key, ttl)
+	if err != nil {
+		a.logger.Warn("claim key", "error", err, "key", key)
+		return true
+	}
+	return ok
+}
+
+func (a *App) releaseClaim(ctx context.Context, key string) {
+	if err := a.stateStore.Release(ctx, key); err != nil {
+		a.logger.Warn("release key", "error", err, "key", key)
+	}
+}
+
+func openStateStore(ctx context.Context, addr string, ttl time.Duration) (statestore.Store, *redis.Client, error) {
+	if addr == "" {
+		return statestore.NewMemoryStore(ttl), nil, nil
+	}
+
+	client := redis.NewClient(&redis.Options{Addr: addr})
+	pingCtx, cancel := context.WithTimeout(ctx, 3*time.Second)
+	defer cancel()
+	if err := client.Ping(pingCtx).Err(); err != nil {
+		_ = client.Close()
+		return statestore.NewMemoryStore(ttl), nil, err
+	}
+	return statestore.NewRedisStore(client, ttl), client, nil
+}
@@ -0,0 +1,156 @@
+package app
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"bot_moderator/internal/domain/enums"
+	"bot_moderator/internal/domain/model"
+	"bot_moderator/internal/infra/telegram"
+	systemsvc "bot_moderator/internal/services/system"
+	"bot_moderator/internal/ui"
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+)
+
+// Admission modes in callback data: queue order, gender-balanced, or a single city.
+const (
+	waitlistModeQueue   = "q"
+	waitlistModeBalance = "b"
+	waitlistModeCity    = "c"
+)
+
+var waitlistBatchSizes = []int{10, 50, 100}
+
+const waitlistCityButtons = 3
+
+func (a *App) handleWaitlistCallback(ctx context.Context, chatID int64, query *tgbotapi.CallbackQuery, parts []string) (string, bool) {
+	actorTGID, actorRole, err := a.resolveActorRole(ctx, query.From)
+	if err != nil {
+		return "Не удалось определить роль", true
+	}
+	if !a.accessService.Can(ctx, actorRole, enums.PermissionSystemRegistration) {
+		return "Нет доступа", true
+	}
+
+	switch parts[1] {
+	case "view":
+		a.sendWaitlistScreen(ctx, chatID)
+		return "", false
+	case "back":
+		a.sendSystemScreen(ctx, chatID)
+		return "", false
+	case "ask", "ok":
+		req, ok := parseWaitlistAdmit(parts[2:])
+		if !ok {
+			return "Некорректная команда", true
+		}
+		if parts[1] == "ask" {
+			rows := [][]telegram.InlineButton{{
+				{Text: "Да, допустить", Data: fmt.Sprintf("%s:ok:%s", callbackPrefixWaitlist, strings.Join(parts[2:], ":"))},
+				{Text: "Нет", Data: fmt.Sprintf("%s:view", callbackPrefixWaitlist)},
+			}}
+			a.sendInline(chatID, fmt.Sprintf("Допустить %s? Пользователи получат сообщение от бота.", describeWaitlistAdmit(req)), rows)
+			return "", false
+		}
+
+		result, err := a.systemService.AdmitWaitlist(ctx, actorTGID, req)
+		if err != nil {
+			if errors.Is(err, systemsvc.ErrInvalidWaitlistAdmit) {
+				return "Некорректный размер пачки", true
+			}
+			a.logger.Warn("admit waitlist", "error", err, "tg_id", actorTGID)
+			return "Не удалось допустить пользователей", true
+		}
+		if err := a.auditService.LogWaitlistAdmitted(ctx, actorTGID, req, result); err != nil {
+			a.logger.Warn("write waitlist admit audit", "error", err, "tg_id", actorTGID)
+		}
+		a.sendText(chatID, fmt.Sprintf("Допущено: %d. В очереди осталось: %d", result.Admitted, result.Waiting))
+		a.sendWaitlistScreen(ctx, chatID)
+		return "Готово", false
+	default:
+		return "", false
+	}
+}
+
+func (a *App) sendWaitlistScreen(ctx context.Context, chatID int64) {
+	stats, err := a.systemService.WaitlistStats(ctx)
+	if err != nil {
+		a.logger.Warn("load waitlist stats", "error", err)
+		a.sendText(chatID, "Не удалось загрузить лист ожидания")
+		return
+	}
+	enabled, err := a.systemService.GetRegistrationEnabled(ctx)
+	if err != nil {
+		a.logger.Warn("get registration flag", "error", err)
+	}
+
+	admit := func(limit int, mode string, extra ...string) string {
+		data := fmt.Sprintf("%s:ask:%d:%s", callbackPrefixWaitlist, limit, mode)
+		if len(extra) > 0 {
+			data += ":" + strings.Join(extra, ":")
+		}
+		return data
+	}
+
+	queueRow := make([]telegram.InlineButton, 0, len(waitlistBatchSizes))
+	balanceRow := make([]telegram.InlineButton, 0, len(waitlistBatchSizes))
+	for _, size := range waitlistBatchSizes {
+		queueRow = append(queueRow, telegram.InlineButton{Text: fmt.Sprintf("+%d", size), Data: admit(size, waitlistModeQueue)})
+		balanceRow = append(balanceRow, telegram.InlineButton{Text: fmt.Sprintf("⚖️ +%d", size), Data: admit(size, waitlistModeBalance)})
+	}
+	rows := [][]telegram.InlineButton{queueRow, balanceRow}
+	for i, bucket := range stats.ByCity {
+		if i >= waitlistCityButtons {
+			break
+		}
+		if bucket.Key == "unknown" {
+			continue
+		}
+		rows = append(rows, []telegram.InlineButton{{
+			Text: fmt.Sprintf("🏙 %s (%d): +%d", bucket.Key, bucket.Count, waitlistBatchSizes[1]),
+			Data: admit(waitlistBatchSizes[1], waitlistModeCity, bucket.Key),
+		}})
+	}
+	rows = append(rows, []telegram.InlineButton{{Text: "Back", Data: fmt.Sprintf("%s:back", callbackPrefixWaitlist)}})
+	a.sendInline(chatID, ui.RenderWaitlistStats(stats, enabled), rows)
+}
+
+// parseWaitlistAdmit decodes "<limit>:<mode>[:<city>]" from callback data.
+func parseWaitlistAdmit(parts []string) (model.WaitlistAdmitRequest, bool) {
+	if len(parts) < 2 {
+		return model.WaitlistAdmitRequest{}, false
+	}
+	limit, err := strconv.Atoi(parts[0])
+	if err != nil || limit <= 0 {
+		return model.WaitlistAdmitRequest{}, false
+	}
+
+	req := model.WaitlistAdmitRequest{Limit: limit}
+	switch parts[1] {
+	case waitlistModeQueue:
+	case waitlistModeBalance:
+		req.Balance = true
+	case waitlistModeCity:
+		if len(parts) < 3 || strings.TrimSpace(parts[2]) == "" {
+			return model.WaitlistAdmitRequest{}, false
+		}
+		req.CityID = strings.Join(parts[2:], ":")
+	default:
+		return model.WaitlistAdmitRequest{}, false
+	}
+	return req, true
+}
+
+func describeWaitlistAdmit(req model.WaitlistAdmitRequest) string {
+	switch {
+	case req.CityID != "":
+		return fmt.Sprintf("%d из города %s", req.Limit, req.CityID)
+	case req.Balance:
+		return fmt.Sprintf("%d поровну по полу", req.Limit)
+	default:
+		return fmt.Sprintf("%d по порядку очереди", req.Limit)
+	}
+}
@@ -0,0 +1,313 @@
+package app
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"bot_moderator/internal/domain/enums"
+	"bot_moderator/internal/domain/model"
+	"bot_moderator/internal/infra/statestore"
+	"bot_moderator/internal/infra/telegram"
+	"bot_moderator/internal/repo/postgres"
+	workloadsvc "bot_moderator/internal/services/workload"
+	"bot_moderator/internal/ui"
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+)
+
+// Workload editor callbacks carry the draft cap and a skill bitmask over enums.ModeratorSkills,
+// like the role editor: wl:e:TG:CAP:MASK re-renders the editor, wl:s:TG:CAP:MASK saves.
+func (a *App) handleWorkloadCallback(ctx context.Context, chatID int64, query *tgbotapi.CallbackQuery, parts []string) (string, bool) {
+	actorTGID, actorRole, err := a.resolveActorRole(ctx, query.From)
+	if err != nil {
+		return "Не удалось определить роль", true
+	}
+	if !a.accessService.Can(ctx, actorRole, enums.PermissionAccessManage) {
+		return "Нет доступа", true
+	}
+
+	switch parts[1] {
+	case "list":
+		a.sendWorkloadScreen(ctx, chatID)
+		return "", false
+	case "m":
+		if len(parts) < 3 {
+			return "", false
+		}
+		tgID, err := parseTGID(parts[2])
+		if err != nil {
+			return "Некорректный пользователь", true
+		}
+		overview, ok := a.loadWorkload(ctx, chatID)
+		if !ok {
+			return "", false
+		}
+		item, _ := overview.Moderator(tgID)
+		a.sendModeratorWorkloadEditor(ctx, chatID, item, item.MaxActiveItems, moderatorSkillMask(item.Skills))
+		return "", false
+	case "e", "s":
+		if len(parts) < 5 {
+			return "", false
+		}
+		tgID, err := parseTGID(parts[2])
+		if err != nil {
+			return "Некорректный пользователь", true
+		}
+		maxActive, errCap := strconv.Atoi(parts[3])
+		mask, errMask := strconv.Atoi(parts[4])
+		if errCap != nil || errMask != nil || mask < 0 || mask >= 1<<len(enums.ModeratorSkills) {
+			return "Некорректные параметры", true
+		}
+		maxActive = clampModeratorCap(maxActive)
+		if parts[1] == "e" {
+			overview, ok := a.loadWorkload(ctx, chatID)
+			if !ok {
+				return "", false
+			}
+			item, _ := overview.Moderator(tgID)
+			a.sendModeratorWorkloadEditor(ctx, chatID, item, maxActive, mask)
+			return "", false
+		}
+		return a.saveModeratorWorkload(ctx, chatID, actorTGID, tgID, maxActive, mask)
+	case "sh":
+		if len(parts) < 3 {
+			return "", false
+		}
+		tgID, err := parseTGID(parts[2])
+		if err != nil {
+			return "Некорректный пользователь", true
+		}
+		if !a.enterChatState(ctx, chatID, telegram.StateWaitingShiftInput, actorTGID, actorRole, func(session *statestore.Session) {
+			session.ShiftModeratorTGID = tgID
+		}) {
+			return chatStateBusyText, true
+		}
+		a.sendText(chatID, "Введите смену (время Минска): дни и часы, например «Пн-Пт 09:00-18:00», «Сб,Вс 22:00-06:00» или «* 10:00-14:00»")
+		return "", false
+	case "sd":
+		if len(parts) < 4 {
+			return "", false
+		}
+		tgID, errTG := parseTGID(parts[2])
+		shiftID, errShift := parseTGID(parts[3])
+		if errTG != nil || errShift != nil {
+			return "Некорректная смена", true
+		}
+		shift, err := a.workloadService.DeleteShift(ctx, shiftID)
+		if errors.Is(err, postgres.ErrModeratorShiftNotFound) {
+			return "Смена уже удалена", true
+		}
+		if err != nil {
+			a.logger.Warn("delete moderator shift", "error", err, "shift_id", shiftID, "tg_id", actorTGID)
+			return "Не удалось удалить смену", true
+		}
+		if err := a.auditService.LogModeratorShiftDeleted(ctx, actorTGID, shift); err != nil {
+			a.logger.Warn("write shift delete audit", "error", err, "shift_id", shiftID)
+		}
+		a.sendModeratorWorkloadScreen(ctx, chatID, tgID)
+		return "Смена удалена", false
+	default:
+		return "", false
+	}
+}
+
+func (a *App) saveModeratorWorkload(ctx context.Context, chatID int64, actorTGID int64, tgID int64, maxActive int, mask int) (string, bool) {
+	saved, err := a.workloadService.SaveModerator(ctx, tgID, maxActive, maskModeratorSkills(mask), actorTGID)
+	if errors.Is(err, workloadsvc.ErrInvalidWorkload) {
+		return "Выберите хотя бы один навык", true
+	}
+	if err != nil {
+		a.logger.Warn("save moderator workload", "error", err, "moderator_tg_id", tgID, "tg_id", actorTGID)
+		return "Не удалось сохранить нагрузку", true
+	}
+
+	if err := a.auditService.LogModeratorWorkloadUpdated(ctx, actorTGID, saved); err != nil {
+		a.logger.Warn("write workload audit", "error", err, "moderator_tg_id", tgID)
+	}
+	a.sendModeratorWorkloadScreen(ctx, chatID, tgID)
+	return "Сохранено", false
+}
+
+func (a *App) handleShiftInput(ctx context.Context, message *tgbotapi.Message, session statestore.Session) {
+	tgID := session.ShiftModeratorTGID
+	shifts, err := a.workloadService.AddShifts(ctx, tgID, message.Text, session.ActorTGID)
+	if errors.Is(err, workloadsvc.ErrInvalidShiftSpec) {
+		a.sendText(message.Chat.ID, "Формат: «Пн-Пт 09:00-18:00». Дни: Пн Вт Ср Чт Пт Сб Вс, диапазон через «-», список через «,», «*» — каждый день")
+		return
+	}
+
+	a.resetChatState(ctx, message.Chat.ID, session)
+	for _, shift := range shifts {
+		if err := a.auditService.LogModeratorShiftAdded(ctx, session.ActorTGID, shift); err != nil {
+			a.logger.Warn("write shift add audit", "error", err, "shift_id", shift.ID)
+		}
+	}
+	if err != nil {
+		a.logger.Warn("add moderator shift", "error", err, "moderator_tg_id", tgID, "tg_id", session.ActorTGID)
+		a.sendText(message.Chat.ID, "Не удалось сохранить смену")
+	}
+	a.sendModeratorWorkloadScreen(ctx, message.Chat.ID, tgID)
+}
+
+func (a *App) loadWorkload(ctx context.Context, chatID int64) (model.ModerationWorkload, bool) {
+	overview, err := a.workloadService.Overview(ctx)
+	if err != nil {
+		a.logger.Warn("load moderation workload", "error", err)
+		a.sendText(chatID, "Не удалось загрузить нагрузку модераторов")
+		return model.ModerationWorkload{}, false
+	}
+	return overview, true
+}
+
+func (a *App) sendWorkloadScreen(ctx context.Context, chatID int64) {
+	overview, ok := a.loadWorkload(ctx, chatID)
+	if !ok {
+		return
+	}
+	moderators, labels := a.workloadModerators(ctx, overview)
+
+	rows := make([][]telegram.InlineButton, 0, len(moderators)+1)
+	for _, tgID := range moderators {
+		rows = append(rows, []telegram.InlineButton{{
+			Text: labels[tgID],
+			Data: fmt.Sprintf("%s:m:%d", callbackPrefixWorkload, tgID),
+		}})
+	}
+	rows = append(rows, []telegram.InlineButton{{Text: "⬅️ Back", Data: "acc:root"}})
+	a.sendInline(chatID, ui.RenderModerationWorkload(overview, moderators, labels), rows)
+}
+
+func (a *App) sendModeratorWorkloadScreen(ctx context.Context, chatID int64, tgID int64) {
+	overview, ok := a.loadWorkload(ctx, chatID)
+	if !ok {
+		return
+	}
+	item, _ := overview.Moderator(tgID)
+	a.sendModeratorWorkloadEditor(ctx, chatID, item, item.MaxActiveItems, moderatorSkillMask(item.Skills))
+}
+
+func (a *App) sendModeratorWorkloadEditor(ctx context.Context, chatID int64, item model.ModeratorWorkload, maxActive int, mask int) {
+	tgID := item.ModeratorTGID
+	draft := func(nextCap, nextMask int) string {
+		return fmt.Sprintf("%s:e:%d:%d:%d", callbackPrefixWorkload, tgID, clampModeratorCap(nextCap), nextMask)
+	}
+
+	rows := make([][]telegram.InlineButton, 0, len(enums.ModeratorSkills)+len(item.Shifts)+4)
+	rows = append(rows, []telegram.InlineButton{
+		{Text: "➖", Data: draft(maxActive-1, mask)},
+		{Text: fmt.Sprintf("Лимит: %d", maxActive), Data: draft(maxActive, mask)},
+		{Text: "➕", Data: draft(maxActive+1, mask)},
+	})
+	for idx, skill := range enums.ModeratorSkills {
+		mark := "▫️"
+		if mask&(1<<idx) != 0 {
+			mark = "✅"
+		}
+		rows = append(rows, []telegram.InlineButton{{
+			Text: mark + " " + skill.Label(),
+			Data: draft(maxActive, mask^(1<<idx)),
+		}})
+	}
+	rows = append(rows, []telegram.InlineButton{{
+		Text: "💾 Сохранить",
+		Data: fmt.Sprintf("%s:s:%d:%d:%d", callbackPrefixWorkload, tgID, maxActive, mask),
+	}})
+	for _, shift := range item.Shifts {
+		rows = append(rows, []telegram.InlineButton{{
+			Text: "🗑 " + ui.RenderModeratorShift(shift),
+			Data: fmt.Sprintf("%s:sd:%d:%d", callbackPrefixWorkload, tgID, shift.ID),
+		}})
+	}
+	rows = append(rows,
+		[]telegram.InlineButton{{Text: "➕ Смена", Data: fmt.Sprintf("%s:sh:%d", callbackPrefixWorkload, tgID)}},
+		[]telegram.InlineButton{{Text: "⬅️ Back", Data: callbackPrefixWorkload + ":list"}},
+	)
+
+	label := renderUserLabel(tgID, a.safeGetUser(ctx, tgID).Username)
+	a.sendInline(chatID, ui.RenderModeratorWorkload(label, item, maxActive, maskModeratorSkills(mask)), rows)
+}
+
+// workloadModerators merges staff who can decide moderation items with anyone who already has
+// a workload row, shift or lock, ordered by label.
+func (a *App) workloadModerators(ctx context.Context, overview model.ModerationWorkload) ([]int64, map[int64]string) {
+	labels := make(map[int64]string, len(overview.Moderators))
+	assignments, err := a.accessService.ListActiveAssignments(ctx)
+	if err != nil {
+		a.logger.Warn("list active assignments for workload", "error", err)
+	}
+	for _, assignment := range assignments {
+		if a.accessService.Can(ctx, assignment.Role, enums.PermissionModerationDecide) {
+			labels[assignment.TgID] = renderUserLabel(assignment.TgID, assignment.Username)
+		}
+	}
+	for _, item := range overview.Moderators {
+		if _, ok := labels[item.ModeratorTGID]; !ok {
+			labels[item.ModeratorTGID] = renderUserLabel(item.ModeratorTGID, "")
+		}
+	}
+
+	moderators := make([]int64, 0, len(labels))
+	for tgID := range labels {
+		moderators = append(moderators, tgID)
+	}
+	sort.Slice(moderators, func(i, j int) bool {
+		left, right := strings.ToLower(labels[moderators[i]]), strings.ToLower(labels[moderators[j]])
+		if left != right {
+			return left < right
+		}
+		return moderators[i] < moderators[j]
+	})
+	return moderators, labels
+}
+
+func (a *App) sendShiftThroughputScreen(chatID int64, items []model.ShiftThroughput) {
+	text := ui.RenderShiftThroughput(items)
+	chunks := splitByLength(strings.Split(text, "\n"), 3600)
+	if len(chunks) == 0 {
+		chunks = []string{"Смены"}
+	}
+	for i := 0; i < len(chunks)-1; i++ {
+		a.sendText(chatID, chunks[i])
+	}
+
+	rows := [][]telegram.InlineButton{
+		{{Text: "Back", Data: fmt.Sprintf("%s:back", callbackPrefixWorkStats)}},
+	}
+	a.sendInline(chatID, chunks[len(chunks)-1], rows)
+}
+
+func clampModeratorCap(value int) int {
+	if value < 1 {
+		return 1
+	}
+	if value > workloadsvc.MaxModeratorActiveItems {
+		return workloadsvc.MaxModeratorActiveItems
+	}
+	return value
+}
+
+func moderatorSkillMask(skills []enums.ModeratorSkill) int {
+	mask := 0
+	for idx, skill := range enums.ModeratorSkills {
+		for _, selected := range skills {
+			if selected == skill {
+				mask |= 1 << idx
+				break
+			}
+		}
+	}
+	return mask
+}
+
+func maskModeratorSkills(mask int) []enums.ModeratorSkill {
+	result := make([]enums.ModeratorSkill, 0, len(enums.ModeratorSkills))
+	for idx, skill := range enums.ModeratorSkills {
+		if mask&(1<<idx) != 0 {
+			result = append(result, skill)
+		}
+	}
+	return result
+}
@@ -0,0 +1,93 @@
+package app
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"bot_moderator/internal/domain/enums"
+	"bot_moderator/internal/repo/adminhttp"
+)
+
+const (
+	workloadCheckInterval = time.Minute
+	queuePushCooldown     = 30 * time.Minute
+)
+
+// runWorkloadBalancer hands idle locks to free on-shift moderators and pings the shift
+// when the pending queue grows past the configured threshold.
+func (a *App) runWorkloadBalancer(ctx context.Context) {
+	if a.cfg.OwnerTGID == 0 || (a.cfg.ModerationIdleLockMinutes <= 0 && a.cfg.ModerationQueuePushThreshold <= 0) {
+		return
+	}
+
+	ctx = adminhttp.WithActorTGID(ctx, a.cfg.OwnerTGID)
+	ctx = adminhttp.WithActorRole(ctx, enums.RoleOwner)
+	ticker := time.NewTicker(workloadCheckInterval)
+	defer ticker.Stop()
+
+	var lastPushAt time.Time
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		a.reassignIdleLocks(ctx)
+		if time.Since(lastPushAt) >= queuePushCooldown && a.pushQueueBacklog(ctx) {
+			lastPushAt = time.Now()
+		}
+	}
+}
+
+func (a *App) reassignIdleLocks(ctx context.Context) {
+	if a.cfg.ModerationIdleLockMinutes <= 0 {
+		return
+	}
+
+	idle := time.Duration(a.cfg.ModerationIdleLockMinutes) * time.Minute
+	moved, err := a.workloadService.ReassignIdle(ctx, idle)
+	if err != nil {
+		a.logger.Warn("reassign idle moderation locks", "error", err)
+		return
+	}
+
+	for _, item := range moved {
+		if err := a.auditService.LogLockReassigned(ctx, a.cfg.OwnerTGID, item); err != nil {
+			a.logger.Warn("audit lock reassignment failed", "moderation_item_id", item.ModerationItemID, "error", err)
+		}
+		a.sendText(item.ToTGID, fmt.Sprintf(
+			"📌 Вам назначена анкета #%d (%s). Нажмите «Приступить к модерации».",
+			item.ModerationItemID,
+			item.Skill.Label(),
+		))
+		a.sendText(item.FromTGID, fmt.Sprintf(
+			"⌛ Анкета #%d передана другому модератору: нет активности %s.",
+			item.ModerationItemID,
+			formatSLASeconds(item.IdleSec),
+		))
+	}
+}
+
+func (a *App) pushQueueBacklog(ctx context.Context) bool {
+	threshold := a.cfg.ModerationQueuePushThreshold
+	pending, targets, err := a.workloadService.QueuePushTargets(ctx, threshold)
+	if err != nil {
+		a.logger.Warn("check moderation queue backlog", "error", err)
+		return false
+	}
+	if pending <= threshold || threshold <= 0 {
+		return false
+	}
+
+	text := fmt.Sprintf("📥 В очереди модерации %d анкет (порог %d). Нажмите «Приступить к модерации».", pending, threshold)
+	if len(targets) == 0 {
+		a.sendText(a.cfg.OwnerTGID, text+"\nСвободных модераторов на смене нет.")
+		return true
+	}
+	for _, tgID := range targets {
+		a.sendText(tgID, text)
+	}
+	return true
+}
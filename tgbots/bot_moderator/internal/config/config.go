@@ -23,6 +23,26 @@ type Config struct {
 	S3SecretKey        string
 	S3UseSSL           bool
 	S3Bucket           string
+	SLAAlertP90Minutes int
+	StateTTLMinutes    int
+
+	ModerationQueuePushThreshold int
+	ModerationIdleLockMinutes    int
+
+	WebhookURL        string
+	WebhookListenAddr string
+	WebhookPath       string
+	WebhookSecret     string
+
+	ExportSink          string
+	ExportPollSeconds   int
+	ExportBatchSize     int
+	ExportMaxAttempts   int
+	ExportCSVDir        string
+	SheetsAPIURL        string
+	SheetsSpreadsheetID string
+	SheetsRange         string
+	SheetsAccessToken   string
 }
 
 func Load() (Config, error) {
@@ -46,6 +66,41 @@ func Load() (Config, error) {
 		return Config{}, err
 	}
 
+	slaAlertP90Minutes, err := getInt([]string{"SLA_ALERT_P90_MINUTES"}, 30)
+	if err != nil {
+		return Config{}, err
+	}
+
+	stateTTLMinutes, err := getInt([]string{"STATE_TTL_MINUTES"}, 30)
+	if err != nil {
+		return Config{}, err
+	}
+
+	queuePushThreshold, err := getInt([]string{"MODERATION_QUEUE_PUSH_THRESHOLD"}, 50)
+	if err != nil {
+		return Config{}, err
+	}
+
+	idleLockMinutes, err := getInt([]string{"MODERATION_IDLE_LOCK_MINUTES"}, 5)
+	if err != nil {
+		return Config{}, err
+	}
+
+	exportPollSeconds, err := getInt([]string{"EXPORT_POLL_SECONDS"}, 30)
+	if err != nil {
+		return Config{}, err
+	}
+
+	exportBatchSize, err := getInt([]string{"EXPORT_BATCH_SIZE"}, 50)
+	if err != nil {
+		return Config{}, err
+	}
+
+	exportMaxAttempts, err := getInt([]string{"EXPORT_MAX_ATTEMPTS"}, 8)
+	if err != nil {
+		return Config{}, err
+	}
+
 	adminMode := normalizeAdminMode(getString("ADMIN_MODE", "dual"))
 
 	cfg := Config{
@@ -64,6 +119,26 @@ func Load() (Config, error) {
 		S3SecretKey:        getString("S3_SECRET_KEY", ""),
 		S3UseSSL:           s3UseSSL,
 		S3Bucket:           getString("S3_BUCKET", ""),
+		SLAAlertP90Minutes: slaAlertP90Minutes,
+		StateTTLMinutes:    stateTTLMinutes,
+
+		ModerationQueuePushThreshold: queuePushThreshold,
+		ModerationIdleLockMinutes:    idleLockMinutes,
+
+		WebhookURL:        getString("WEBHOOK_URL", ""),
+		WebhookListenAddr: getString("WEBHOOK_LISTEN_ADDR", ":8090"),
+		WebhookPath:       getString("WEBHOOK_PATH", "/telegram/webhook"),
+		WebhookSecret:     getString("WEBHOOK_SECRET", ""),
+
+		ExportSink:          strings.ToLower(getString("EXPORT_SINK", "")),
+		ExportPollSeconds:   exportPollSeconds,
+		ExportBatchSize:     exportBatchSize,
+		ExportMaxAttempts:   exportMaxAttempts,
+		ExportCSVDir:        getString("EXPORT_CSV_DIR", "./exports"),
+		SheetsAPIURL:        getString("SHEETS_API_URL", "https://sheets.googleapis.com"),
+		SheetsSpreadsheetID: getString("SHEETS_SPREADSHEET_ID", ""),
+		SheetsRange:         getString("SHEETS_RANGE", "Sheet1!A1"),
+		SheetsAccessToken:   getString("SHEETS_ACCESS_TOKEN", ""),
 	}
 
 	if cfg.PollTimeoutSeconds <= 0 {
@@ -72,6 +147,9 @@ func Load() (Config, error) {
 	if cfg.AdminHTTPTimeout <= 0 {
 		cfg.AdminHTTPTimeout = 8
 	}
+	if cfg.ExportPollSeconds <= 0 {
+		cfg.ExportPollSeconds = 30
+	}
 
 	return cfg, nil
 }
@@ -80,6 +158,10 @@ func (c Config) IsHTTPEnabled() bool {
 	return strings.TrimSpace(c.AdminAPIURL) != "" && strings.TrimSpace(c.AdminBotToken) != ""
 }
 
+func (c Config) IsWebhookEnabled() bool {
+	return strings.TrimSpace(c.WebhookURL) != ""
+}
+
 func (c Config) IsDualMode() bool {
 	return normalizeAdminMode(c.AdminMode) == "dual"
 }
@@ -3,16 +3,68 @@ package enums
 type AuditAction string
 
 const (
-	AuditActionBotStart          AuditAction = "BOT_START"
-	AuditActionRoleGranted       AuditAction = "ROLE_GRANTED"
-	AuditActionRoleRevoked       AuditAction = "ROLE_REVOKED"
-	AuditActionModerationApprove AuditAction = "MODERATION_APPROVE"
-	AuditActionModerationReject  AuditAction = "MODERATION_REJECT"
-	AuditActionLookupUser        AuditAction = "LOOKUP_USER"
-	AuditActionBanUser           AuditAction = "BAN_USER"
-	AuditActionUnbanUser         AuditAction = "UNBAN_USER"
-	AuditActionForceReview       AuditAction = "FORCE_REVIEW"
-	AuditActionSystemToggleReg   AuditAction = "SYSTEM_TOGGLE_REGISTRATION"
-	AuditActionSystemViewUsers   AuditAction = "SYSTEM_VIEW_USERS_COUNT"
-	AuditActionSystemViewWork    AuditAction = "SYSTEM_VIEW_WORK_STATS"
+	AuditActionBotStart           AuditAction = "BOT_START"
+	AuditActionRoleGranted        AuditAction = "ROLE_GRANTED"
+	AuditActionRoleRevoked        AuditAction = "ROLE_REVOKED"
+	AuditActionModerationApprove  AuditAction = "MODERATION_APPROVE"
+	AuditActionModerationReject   AuditAction = "MODERATION_REJECT"
+	AuditActionReviewUphold       AuditAction = "MODERATION_REVIEW_UPHOLD"
+	AuditActionReviewOverturn     AuditAction = "MODERATION_REVIEW_OVERTURN"
+	AuditActionLookupUser         AuditAction = "LOOKUP_USER"
+	AuditActionBanUser            AuditAction = "BAN_USER"
+	AuditActionUnbanUser          AuditAction = "UNBAN_USER"
+	AuditActionForceReview        AuditAction = "FORCE_REVIEW"
+	AuditActionSystemToggleReg    AuditAction = "SYSTEM_TOGGLE_REGISTRATION"
+	AuditActionSystemViewUsers    AuditAction = "SYSTEM_VIEW_USERS_COUNT"
+	AuditActionSystemViewWork     AuditAction = "SYSTEM_VIEW_WORK_STATS"
+	AuditActionAuditExport        AuditAction = "AUDIT_EXPORT"
+	AuditActionRejectReasonEdit   AuditAction = "REJECT_REASON_UPDATE"
+	AuditActionRoleDefinitionSave AuditAction = "ROLE_DEFINITION_SAVED"
+	AuditActionWorkloadUpdate     AuditAction = "MODERATOR_WORKLOAD_UPDATED"
+	AuditActionShiftAdd           AuditAction = "MODERATOR_SHIFT_ADDED"
+	AuditActionShiftDelete        AuditAction = "MODERATOR_SHIFT_DELETED"
+	AuditActionLockReassign       AuditAction = "MODERATION_LOCK_REASSIGNED"
+	AuditActionFlagUpdate         AuditAction = "FLAG_UPDATE"
+	AuditActionFlagDelete         AuditAction = "FLAG_DELETE"
+	AuditActionWaitlistAdmit      AuditAction = "WAITLIST_ADMIT"
 )
+
+var auditActions = []AuditAction{
+	AuditActionBotStart,
+	AuditActionRoleGranted,
+	AuditActionRoleRevoked,
+	AuditActionModerationApprove,
+	AuditActionModerationReject,
+	AuditActionReviewUphold,
+	AuditActionReviewOverturn,
+	AuditActionLookupUser,
+	AuditActionBanUser,
+	AuditActionUnbanUser,
+	AuditActionForceReview,
+	AuditActionSystemToggleReg,
+	AuditActionSystemViewUsers,
+	AuditActionSystemViewWork,
+	AuditActionAuditExport,
+	AuditActionRejectReasonEdit,
+	AuditActionRoleDefinitionSave,
+	AuditActionWorkloadUpdate,
+	AuditActionShiftAdd,
+	AuditActionShiftDelete,
+	AuditActionLockReassign,
+	AuditActionFlagUpdate,
+	AuditActionFlagDelete,
+	AuditActionWaitlistAdmit,
+}
+
+func AuditActions() []AuditAction {
+	return append([]AuditAction(nil), auditActions...)
+}
+
+func ParseAuditAction(raw string) (AuditAction, bool) {
+	for _, action := range auditActions {
+		if string(action) == raw {
+			return action, true
+		}
+	}
+	return "", false
+}
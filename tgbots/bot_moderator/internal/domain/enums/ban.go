@@ -0,0 +1,91 @@
+package enums
+
+import "time"
+
+// BanReason codes mirror the backend users service; unknown codes are rejected there.
+type BanReason string
+
+const (
+	BanReasonSpam        BanReason = "SPAM"
+	BanReasonScam        BanReason = "SCAM"
+	BanReasonHarassment  BanReason = "HARASSMENT"
+	BanReasonFakeProfile BanReason = "FAKE_PROFILE"
+	BanReasonUnderage    BanReason = "UNDERAGE"
+	BanReasonExplicit    BanReason = "EXPLICIT_CONTENT"
+	BanReasonBanEvasion  BanReason = "BAN_EVASION"
+	BanReasonOther       BanReason = "OTHER"
+)
+
+var BanReasons = []BanReason{
+	BanReasonSpam,
+	BanReasonScam,
+	BanReasonHarassment,
+	BanReasonFakeProfile,
+	BanReasonUnderage,
+	BanReasonExplicit,
+	BanReasonBanEvasion,
+	BanReasonOther,
+}
+
+var banReasonLabels = map[BanReason]string{
+	BanReasonSpam:        "Спам",
+	BanReasonScam:        "Мошенничество",
+	BanReasonHarassment:  "Оскорбления",
+	BanReasonFakeProfile: "Фейковый профиль",
+	BanReasonUnderage:    "Несовершеннолетний",
+	BanReasonExplicit:    "Откровенный контент",
+	BanReasonBanEvasion:  "Обход блокировки",
+	BanReasonOther:       "Другое",
+}
+
+func (r BanReason) Label() string {
+	if label, ok := banReasonLabels[r]; ok {
+		return label
+	}
+	return string(r)
+}
+
+func ParseBanReason(raw string) (BanReason, bool) {
+	_, ok := banReasonLabels[BanReason(raw)]
+	return BanReason(raw), ok
+}
+
+type BanDuration string
+
+const (
+	BanDuration24h       BanDuration = "24h"
+	BanDuration7d        BanDuration = "7d"
+	BanDuration30d       BanDuration = "30d"
+	BanDurationPermanent BanDuration = "permanent"
+)
+
+var BanDurations = []BanDuration{
+	BanDuration24h,
+	BanDuration7d,
+	BanDuration30d,
+	BanDurationPermanent,
+}
+
+var banDurationTTLs = map[BanDuration]time.Duration{
+	BanDuration24h:       24 * time.Hour,
+	BanDuration7d:        7 * 24 * time.Hour,
+	BanDuration30d:       30 * 24 * time.Hour,
+	BanDurationPermanent: 0,
+}
+
+func ParseBanDuration(raw string) (BanDuration, bool) {
+	_, ok := banDurationTTLs[BanDuration(raw)]
+	return BanDuration(raw), ok
+}
+
+// TTL is zero for a permanent ban.
+func (d BanDuration) TTL() time.Duration {
+	return banDurationTTLs[d]
+}
+
+func (d BanDuration) Label() string {
+	if d == BanDurationPermanent {
+		return "Навсегда"
+	}
+	return string(d)
+}
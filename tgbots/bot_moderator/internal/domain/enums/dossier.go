@@ -0,0 +1,46 @@
+package enums
+
+// DossierSection names match the backend /admin/bot/lookup/dossier sections.
+type DossierSection string
+
+const (
+	DossierSectionOverview   DossierSection = "overview"
+	DossierSectionReports    DossierSection = "reports"
+	DossierSectionDevices    DossierSection = "devices"
+	DossierSectionPurchases  DossierSection = "purchases"
+	DossierSectionModeration DossierSection = "moderation"
+	DossierSectionRisk       DossierSection = "risk"
+	DossierSectionSupport    DossierSection = "support"
+)
+
+var DossierSections = []DossierSection{
+	DossierSectionOverview,
+	DossierSectionReports,
+	DossierSectionRisk,
+	DossierSectionDevices,
+	DossierSectionPurchases,
+	DossierSectionModeration,
+	DossierSectionSupport,
+}
+
+var dossierSectionLabels = map[DossierSection]string{
+	DossierSectionOverview:   "Сводка",
+	DossierSectionReports:    "Жалобы",
+	DossierSectionDevices:    "Устройства",
+	DossierSectionPurchases:  "Покупки",
+	DossierSectionModeration: "Модерация",
+	DossierSectionRisk:       "Риск",
+	DossierSectionSupport:    "Поддержка",
+}
+
+func (s DossierSection) Label() string {
+	if label, ok := dossierSectionLabels[s]; ok {
+		return label
+	}
+	return string(s)
+}
+
+func ParseDossierSection(raw string) (DossierSection, bool) {
+	_, ok := dossierSectionLabels[DossierSection(raw)]
+	return DossierSection(raw), ok
+}
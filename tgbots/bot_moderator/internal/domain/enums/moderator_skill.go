@@ -0,0 +1,32 @@
+package enums
+
+// ModeratorSkill tags the kind of queue item a moderator is trusted with.
+type ModeratorSkill string
+
+const (
+	ModeratorSkillPhoto   ModeratorSkill = "PHOTO"
+	ModeratorSkillCircle  ModeratorSkill = "CIRCLE"
+	ModeratorSkillReports ModeratorSkill = "REPORTS"
+)
+
+// ModeratorSkills is ordered; the workload editor keeps the selection as a bitmask over it.
+var ModeratorSkills = []ModeratorSkill{ModeratorSkillPhoto, ModeratorSkillCircle, ModeratorSkillReports}
+
+var moderatorSkillLabels = map[ModeratorSkill]string{
+	ModeratorSkillPhoto:   "Фото",
+	ModeratorSkillCircle:  "Кружки",
+	ModeratorSkillReports: "Жалобы",
+}
+
+func (s ModeratorSkill) Label() string {
+	if label, ok := moderatorSkillLabels[s]; ok {
+		return label
+	}
+	return string(s)
+}
+
+func ParseModeratorSkill(raw string) (ModeratorSkill, bool) {
+	skill := ModeratorSkill(raw)
+	_, ok := moderatorSkillLabels[skill]
+	return skill, ok
+}
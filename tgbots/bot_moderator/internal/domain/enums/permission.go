@@ -0,0 +1,64 @@
+package enums
+
+// Permission names mirror the backend permissions service; roles are stored as sets of them.
+type Permission string
+
+const (
+	PermissionModerationDecide   Permission = "moderation.decide"
+	PermissionModerationQA       Permission = "moderation.qa"
+	PermissionModerationReasons  Permission = "moderation.reasons"
+	PermissionUsersBan           Permission = "users.ban"
+	PermissionUsersViewPrivate   Permission = "users.view_private"
+	PermissionSystemRegistration Permission = "system.toggle_registration"
+	PermissionStatsView          Permission = "stats.view"
+	PermissionAuditView          Permission = "audit.view"
+	PermissionPaymentsRefund     Permission = "payments.refund"
+	PermissionAccessManage       Permission = "access.manage"
+	PermissionConfigPublish      Permission = "config.publish"
+	PermissionBalanceRecompute   Permission = "balance.recompute"
+	PermissionFlagsManage        Permission = "flags.manage"
+)
+
+var Permissions = []Permission{
+	PermissionModerationDecide,
+	PermissionModerationQA,
+	PermissionModerationReasons,
+	PermissionUsersBan,
+	PermissionUsersViewPrivate,
+	PermissionSystemRegistration,
+	PermissionStatsView,
+	PermissionAuditView,
+	PermissionPaymentsRefund,
+	PermissionAccessManage,
+	PermissionConfigPublish,
+	PermissionBalanceRecompute,
+	PermissionFlagsManage,
+}
+
+var permissionLabels = map[Permission]string{
+	PermissionModerationDecide:   "Модерация анкет",
+	PermissionModerationQA:       "QA проверка",
+	PermissionModerationReasons:  "Причины отказа",
+	PermissionUsersBan:           "Бан пользователей",
+	PermissionUsersViewPrivate:   "Поиск и досье",
+	PermissionSystemRegistration: "System: регистрация, просмотр флагов, лист ожидания",
+	PermissionStatsView:          "Статистика",
+	PermissionAuditView:          "History (аудит)",
+	PermissionPaymentsRefund:     "Возвраты платежей",
+	PermissionAccessManage:       "Управление доступом",
+	PermissionConfigPublish:      "Публикация remote config",
+	PermissionBalanceRecompute:   "Пересчёт баланса",
+	PermissionFlagsManage:        "Редактирование флагов",
+}
+
+func (p Permission) Label() string {
+	if label, ok := permissionLabels[p]; ok {
+		return label
+	}
+	return string(p)
+}
+
+func ParsePermission(raw string) (Permission, bool) {
+	_, ok := permissionLabels[Permission(raw)]
+	return Permission(raw), ok
+}
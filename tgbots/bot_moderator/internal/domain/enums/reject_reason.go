@@ -1,9 +1,21 @@
 package enums
 
-type RejectReason string
+// RejectReasonOther is the catch-all catalog code that asks the moderator for a free-form comment.
+const RejectReasonOther = "OTHER"
+
+type RejectReasonSeverity string
 
 const (
-	RejectReasonFakePhoto       RejectReason = "FAKE_PHOTO"
-	RejectReasonLowQuality      RejectReason = "LOW_QUALITY"
-	RejectReasonPolicyViolation RejectReason = "POLICY_VIOLATION"
+	RejectReasonSeverityLow    RejectReasonSeverity = "LOW"
+	RejectReasonSeverityMedium RejectReasonSeverity = "MEDIUM"
+	RejectReasonSeverityHigh   RejectReasonSeverity = "HIGH"
 )
+
+func ParseRejectReasonSeverity(raw string) (RejectReasonSeverity, bool) {
+	switch RejectReasonSeverity(raw) {
+	case RejectReasonSeverityLow, RejectReasonSeverityMedium, RejectReasonSeverityHigh:
+		return RejectReasonSeverity(raw), true
+	default:
+		return "", false
+	}
+}
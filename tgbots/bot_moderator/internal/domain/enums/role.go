@@ -1,10 +1,23 @@
 package enums
 
+import "strings"
+
+// Role is the name of a role definition. OWNER, ADMIN, SUPPORT and MODERATOR are seeded as system
+// roles; the owner can define more, so any other upper-case name may be valid too.
 type Role string
 
 const (
 	RoleOwner     Role = "OWNER"
 	RoleAdmin     Role = "ADMIN"
+	RoleSupport   Role = "SUPPORT"
 	RoleModerator Role = "MODERATOR"
 	RoleNone      Role = "NONE"
 )
+
+func NormalizeRole(raw string) Role {
+	value := strings.ToUpper(strings.TrimSpace(raw))
+	if value == "" {
+		return RoleNone
+	}
+	return Role(value)
+}
@@ -0,0 +1,34 @@
+package model
+
+import (
+	"encoding/json"
+	"time"
+)
+
+const (
+	AppFlagTypeBool   = "bool"
+	AppFlagTypeInt    = "int"
+	AppFlagTypeString = "string"
+)
+
+// AppFlag matches the backend /admin/bot/flags item. Rules are checked in order and the
+// first match wins; without a match the default Value applies.
+type AppFlag struct {
+	Key           string          `json:"key"`
+	Type          string          `json:"type"`
+	Value         json.RawMessage `json:"value"`
+	Rules         []AppFlagRule   `json:"rules"`
+	Description   string          `json:"description"`
+	ClientVisible bool            `json:"client_visible"`
+	UpdatedByTGID *int64          `json:"updated_by_tg_id,omitempty"`
+	UpdatedAt     *time.Time      `json:"updated_at,omitempty"`
+}
+
+type AppFlagRule struct {
+	CityIDs    []string        `json:"city_ids,omitempty"`
+	Genders    []string        `json:"genders,omitempty"`
+	Plus       *bool           `json:"plus,omitempty"`
+	UserIDs    []int64         `json:"user_ids,omitempty"`
+	Percentage *int            `json:"percentage,omitempty"`
+	Value      json.RawMessage `json:"value"`
+}
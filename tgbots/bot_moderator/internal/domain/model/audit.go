@@ -1,16 +1,82 @@
 package model
 
 import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
 	"encoding/json"
+	"strconv"
+	"strings"
 	"time"
 
 	"bot_moderator/internal/domain/enums"
 )
 
 type Audit struct {
-	ID        string
-	ActorTGID int64
-	Action    enums.AuditAction
-	Payload   json.RawMessage
-	CreatedAt time.Time
+	ID        string            `json:"id"`
+	ActorTGID int64             `json:"actor_tg_id"`
+	Action    enums.AuditAction `json:"action"`
+	Payload   json.RawMessage   `json:"payload"`
+	PrevHash  string            `json:"prev_hash,omitempty"`
+	Hash      string            `json:"hash,omitempty"`
+	CreatedAt time.Time         `json:"created_at"`
+}
+
+// AuditFilter narrows History; zero values mean "any".
+type AuditFilter struct {
+	ActorTGID    int64
+	TargetUserID int64
+	Action       enums.AuditAction
+	From         *time.Time
+	To           *time.Time
+	Limit        int
+	Offset       int
+}
+
+type AuditPage struct {
+	Items   []Audit
+	Offset  int
+	Limit   int
+	HasMore bool
+}
+
+type AuditChainReport struct {
+	Checked  int    `json:"checked"`
+	OK       bool   `json:"ok"`
+	BrokenID string `json:"broken_id,omitempty"`
+}
+
+// AuditChainHash links an entry to its predecessor. The payload is canonicalized because
+// jsonb does not preserve key order or whitespace; the backend computes the same value.
+func AuditChainHash(prevHash string, entry Audit) string {
+	var b strings.Builder
+	b.WriteString(prevHash)
+	b.WriteByte('|')
+	b.WriteString(strconv.FormatInt(entry.ActorTGID, 10))
+	b.WriteByte('|')
+	b.WriteString(string(entry.Action))
+	b.WriteByte('|')
+	b.Write(canonicalAuditPayload(entry.Payload))
+	b.WriteByte('|')
+	b.WriteString(entry.CreatedAt.UTC().Truncate(time.Microsecond).Format(time.RFC3339Nano))
+
+	sum := sha256.Sum256([]byte(b.String()))
+	return hex.EncodeToString(sum[:])
+}
+
+func canonicalAuditPayload(payload json.RawMessage) []byte {
+	if len(bytes.TrimSpace(payload)) == 0 {
+		return []byte(`{}`)
+	}
+	decoder := json.NewDecoder(bytes.NewReader(payload))
+	decoder.UseNumber()
+	var value interface{}
+	if err := decoder.Decode(&value); err != nil {
+		return bytes.TrimSpace(payload)
+	}
+	canonical, err := json.Marshal(value)
+	if err != nil {
+		return bytes.TrimSpace(payload)
+	}
+	return canonical
 }
@@ -10,5 +10,8 @@ type BotModerationAction struct {
 	Decision         string
 	ReasonCode       string
 	DurationSec      *int
-	CreatedAt        time.Time
+	// BatchID groups decisions taken in batch mode; Bulk marks those applied by "approve all".
+	BatchID   string
+	Bulk      bool
+	CreatedAt time.Time
 }
@@ -0,0 +1,168 @@
+package model
+
+import "time"
+
+// DossierQuery pages one dossier section; an empty Section loads the overview plus every list.
+type DossierQuery struct {
+	UserID  int64
+	Section string
+	Limit   int
+	Offset  int
+}
+
+type Dossier struct {
+	UserID     int64              `json:"user_id"`
+	Section    string             `json:"section"`
+	Limit      int                `json:"limit"`
+	Offset     int                `json:"offset"`
+	Risk       *DossierRisk       `json:"risk,omitempty"`
+	Activity   *DossierActivity   `json:"activity,omitempty"`
+	Reports    *DossierReports    `json:"reports,omitempty"`
+	Devices    *DossierDevices    `json:"devices,omitempty"`
+	Purchases  *DossierPurchases  `json:"purchases,omitempty"`
+	Moderation *DossierModeration `json:"moderation,omitempty"`
+	Support    *DossierSupport    `json:"support,omitempty"`
+}
+
+type DossierRisk struct {
+	Available       bool               `json:"available"`
+	RiskScore       int                `json:"risk_score"`
+	CooldownUntil   *time.Time         `json:"cooldown_until"`
+	LastViolationAt *time.Time         `json:"last_violation_at"`
+	ShadowEnabled   bool               `json:"shadow_enabled"`
+	Events          []DossierRiskEvent `json:"events"`
+	EventsTotal     int                `json:"events_total"`
+}
+
+type DossierRiskEvent struct {
+	Name       string         `json:"name"`
+	OccurredAt time.Time      `json:"occurred_at"`
+	Payload    map[string]any `json:"payload,omitempty"`
+}
+
+type DossierActivity struct {
+	Days             int     `json:"days"`
+	Likes            int     `json:"likes"`
+	Superlikes       int     `json:"superlikes"`
+	Dislikes         int     `json:"dislikes"`
+	LikeRatio        float64 `json:"like_ratio"`
+	SegmentLikeRatio float64 `json:"segment_like_ratio"`
+	LikesReceived    int     `json:"likes_received"`
+	Swipes1h         int     `json:"swipes_1h"`
+	Swipes24h        int     `json:"swipes_24h"`
+	TooFastHits      int     `json:"too_fast_hits"`
+}
+
+type DossierReports struct {
+	Received      []DossierReport `json:"received"`
+	ReceivedTotal int             `json:"received_total"`
+	Filed         []DossierReport `json:"filed"`
+	FiledTotal    int             `json:"filed_total"`
+}
+
+type DossierReport struct {
+	ID                int64     `json:"id"`
+	CounterpartUserID int64     `json:"counterpart_user_id"`
+	Reason            string    `json:"reason"`
+	Details           string    `json:"details"`
+	Status            string    `json:"status"`
+	CreatedAt         time.Time `json:"created_at"`
+}
+
+type DossierDevices struct {
+	Devices        []DossierDevice        `json:"devices"`
+	Total          int                    `json:"total"`
+	LinkedAccounts []DossierLinkedAccount `json:"linked_accounts"`
+}
+
+type DossierDevice struct {
+	DeviceID    string    `json:"device_id"`
+	FirstSeenAt time.Time `json:"first_seen_at"`
+	LastSeenAt  time.Time `json:"last_seen_at"`
+	IsBlocked   bool      `json:"is_blocked"`
+	SharedWith  int       `json:"shared_with"`
+}
+
+type DossierLinkedAccount struct {
+	UserID  int64    `json:"user_id"`
+	TGID    int64    `json:"tg_id"`
+	Signals []string `json:"signals"`
+	Banned  bool     `json:"banned"`
+}
+
+type DossierPurchases struct {
+	Items []DossierPurchase `json:"items"`
+	Total int               `json:"total"`
+}
+
+type DossierPurchase struct {
+	ID          int64     `json:"id"`
+	SKU         string    `json:"sku"`
+	Provider    string    `json:"provider"`
+	Status      string    `json:"status"`
+	AmountMinor int64     `json:"amount_minor"`
+	Currency    string    `json:"currency"`
+	CreatedAt   time.Time `json:"created_at"`
+}
+
+type DossierModeration struct {
+	Items []DossierModerationDecision `json:"items"`
+	Total int                         `json:"total"`
+}
+
+type DossierModerationDecision struct {
+	ModerationItemID int64      `json:"moderation_item_id"`
+	TargetType       string     `json:"target_type"`
+	Status           string     `json:"status"`
+	ReasonCode       string     `json:"reason_code"`
+	ModeratorTGID    *int64     `json:"moderator_tg_id"`
+	CreatedAt        time.Time  `json:"created_at"`
+	DecidedAt        *time.Time `json:"decided_at"`
+	ReviewKind       string     `json:"review_kind"`
+	ReviewStatus     string     `json:"review_status"`
+}
+
+type DossierSupport struct {
+	Available bool                         `json:"available"`
+	Items     []DossierSupportConversation `json:"items"`
+	Total     int                          `json:"total"`
+}
+
+type DossierSupportConversation struct {
+	ID            int64     `json:"id"`
+	Status        string    `json:"status"`
+	MessagesCount int       `json:"messages_count"`
+	LastMessage   string    `json:"last_message"`
+	CreatedAt     time.Time `json:"created_at"`
+	UpdatedAt     time.Time `json:"updated_at"`
+}
+
+// PageTotal is the largest list total in the loaded section; paging continues while Offset+Limit is below it.
+func (d Dossier) PageTotal() int {
+	total := 0
+	keep := func(value int) {
+		if value > total {
+			total = value
+		}
+	}
+	if d.Risk != nil {
+		keep(d.Risk.EventsTotal)
+	}
+	if d.Reports != nil {
+		keep(d.Reports.ReceivedTotal)
+		keep(d.Reports.FiledTotal)
+	}
+	if d.Devices != nil {
+		keep(d.Devices.Total)
+	}
+	if d.Purchases != nil {
+		keep(d.Purchases.Total)
+	}
+	if d.Moderation != nil {
+		keep(d.Moderation.Total)
+	}
+	if d.Support != nil {
+		keep(d.Support.Total)
+	}
+	return total
+}
@@ -10,5 +10,15 @@ type ExportQueueItem struct {
 	Kind      string
 	Payload   json.RawMessage
 	Status    string
+	Attempts  int
+	LastError string
 	CreatedAt time.Time
 }
+
+type ExportQueueStats struct {
+	Pending         int
+	Failed          int
+	DoneLast24h     int
+	OldestPendingAt *time.Time
+	RecentFailures  []ExportQueueItem
+}
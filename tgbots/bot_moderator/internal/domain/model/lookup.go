@@ -31,6 +31,32 @@ type LookupUser struct {
 	LikeTokens       int
 	IsBanned         bool
 	BanReason        string
+	BanReasonCode    string
+	BanExpiresAt     *time.Time
+	EvasionMatches   []EvasionMatch
+}
+
+// EvasionMatch is an older, currently banned account linked to the looked-up user.
+type EvasionMatch struct {
+	BannedUserID int64    `json:"banned_user_id"`
+	BannedTGID   int64    `json:"banned_tg_id"`
+	Signals      []string `json:"signals"`
+}
+
+type BanInput struct {
+	UserID     int64
+	ReasonCode string
+	Reason     string
+	Duration   string
+	ActorTGID  int64
+}
+
+type BanState struct {
+	Banned     bool       `json:"banned"`
+	ReasonCode string     `json:"reason_code"`
+	Reason     string     `json:"reason"`
+	BannedAt   *time.Time `json:"banned_at"`
+	ExpiresAt  *time.Time `json:"expires_at"`
 }
 
 type BotLookupAction struct {
@@ -40,6 +40,8 @@ type ModerationProfile struct {
 	Languages   []string
 	Occupation  string
 	Education   string
+
+	VerificationStatus string
 }
 
 type ModerationQueueItem struct {
@@ -51,4 +53,16 @@ type ModerationQueueItem struct {
 	Profile          ModerationProfile
 	PhotoURLs        []string
 	CircleURL        string
+	PhotoMatches     []PhotoMatch
+}
+
+type PhotoMatch struct {
+	MediaID        int64
+	Slot           int
+	Source         string
+	MatchedUserID  int64
+	MatchedMediaID int64
+	BannedImageID  int64
+	Label          string
+	Distance       int
 }
@@ -0,0 +1,48 @@
+package model
+
+const MaxModerationBatchSize = 10
+
+const (
+	ModerationBatchApprove         = "APPROVE"
+	ModerationBatchApproveVerified = "APPROVE_VERIFIED"
+	ModerationBatchReject          = "REJECT"
+)
+
+type ModerationBatchDecision struct {
+	ModerationItemID int64
+	Decision         string
+	ReasonCode       string
+	ReasonText       string
+	RequiredFixStep  string
+}
+
+// ModerationBatchStats compares batch-mode throughput with one-by-one moderation per moderator.
+type ModerationBatchStats struct {
+	WindowHours int
+	Moderators  []ModerationBatchModerator
+}
+
+type ModerationBatchModerator struct {
+	ModeratorTGID int64
+	Batches       int
+	Decisions     int
+	BulkApproved  int
+	// ActiveSec spans from the first lock to the last decision of each batch.
+	ActiveSec float64
+	// SingleAvgDecisionSec is the average card time outside batch mode over the same window.
+	SingleAvgDecisionSec float64
+}
+
+func (m ModerationBatchModerator) ItemsPerMinute() float64 {
+	if m.ActiveSec <= 0 {
+		return 0
+	}
+	return float64(m.Decisions) * 60 / m.ActiveSec
+}
+
+func (m ModerationBatchModerator) SingleItemsPerMinute() float64 {
+	if m.SingleAvgDecisionSec <= 0 {
+		return 0
+	}
+	return 60 / m.SingleAvgDecisionSec
+}
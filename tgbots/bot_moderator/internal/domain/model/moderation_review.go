@@ -0,0 +1,77 @@
+package model
+
+import "time"
+
+const (
+	ModerationReviewKindAppeal = "APPEAL"
+	ModerationReviewKindQA     = "QA"
+
+	ModerationReviewStatusPending    = "PENDING"
+	ModerationReviewStatusUpheld     = "UPHELD"
+	ModerationReviewStatusOverturned = "OVERTURNED"
+
+	ModerationReviewOutcomeUphold   = "UPHOLD"
+	ModerationReviewOutcomeOverturn = "OVERTURN"
+)
+
+type ModerationReview struct {
+	ID                    int64
+	ModerationItemID      int64
+	UserID                int64
+	Kind                  string
+	Status                string
+	OriginalDecision      string
+	OriginalReasonCode    string
+	OriginalModeratorTGID *int64
+	ReasonText            string
+	AppealMessage         string
+	LockedAt              *time.Time
+	CreatedAt             time.Time
+}
+
+type ModerationReviewItem struct {
+	Review    ModerationReview
+	Profile   ModerationProfile
+	PhotoKeys []string
+	CircleKey string
+}
+
+type ModerationReviewQueueItem struct {
+	Review    ModerationReview
+	Profile   ModerationProfile
+	PhotoURLs []string
+	CircleURL string
+}
+
+type ModerationReviewDecision struct {
+	ReviewID        int64
+	ReviewerTGID    int64
+	Outcome         string
+	ReasonCode      string
+	ReasonText      string
+	RequiredFixStep string
+	Note            string
+}
+
+type ModerationReviewKindStats struct {
+	Kind          string  `json:"kind"`
+	Pending       int     `json:"pending"`
+	Decided       int     `json:"decided"`
+	Upheld        int     `json:"upheld"`
+	Overturned    int     `json:"overturned"`
+	AgreementRate float64 `json:"agreement_rate"`
+}
+
+type ModerationReviewAgreement struct {
+	ModeratorTGID int64   `json:"moderator_tg_id"`
+	Reviewed      int     `json:"reviewed"`
+	Upheld        int     `json:"upheld"`
+	Overturned    int     `json:"overturned"`
+	AgreementRate float64 `json:"agreement_rate"`
+}
+
+type ModerationReviewStats struct {
+	WindowHours int                         `json:"window_hours"`
+	Kinds       []ModerationReviewKindStats `json:"kinds"`
+	Moderators  []ModerationReviewAgreement `json:"moderators"`
+}
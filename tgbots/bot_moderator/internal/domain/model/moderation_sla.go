@@ -0,0 +1,35 @@
+package model
+
+type ModerationSLAModerator struct {
+	ModeratorTGID  int64   `json:"moderator_tg_id"`
+	Decisions      int     `json:"decisions"`
+	Approved       int     `json:"approved"`
+	Rejected       int     `json:"rejected"`
+	AvgDecisionSec float64 `json:"avg_decision_sec"`
+	P90DecisionSec float64 `json:"p90_decision_sec"`
+}
+
+type ModerationSLAReason struct {
+	ReasonCode    string  `json:"reason_code"`
+	Count         int     `json:"count"`
+	ShareOfReject float64 `json:"share_of_reject"`
+	ShareOfTotal  float64 `json:"share_of_total"`
+}
+
+type ModerationSLAReport struct {
+	WindowHours      int                      `json:"window_hours"`
+	Decided          int                      `json:"decided"`
+	Approved         int                      `json:"approved"`
+	Rejected         int                      `json:"rejected"`
+	ApproveRatio     float64                  `json:"approve_ratio"`
+	WaitP50Sec       float64                  `json:"wait_p50_sec"`
+	WaitP90Sec       float64                  `json:"wait_p90_sec"`
+	WaitP99Sec       float64                  `json:"wait_p99_sec"`
+	Pending          int                      `json:"pending"`
+	OldestPendingSec float64                  `json:"oldest_pending_sec"`
+	DecidedLastHour  int                      `json:"decided_last_hour"`
+	LockExpired      int                      `json:"lock_expired"`
+	StaleLocks       int                      `json:"stale_locks"`
+	Moderators       []ModerationSLAModerator `json:"moderators"`
+	RejectReasons    []ModerationSLAReason    `json:"reject_reasons"`
+}
@@ -0,0 +1,35 @@
+package model
+
+import (
+	"strings"
+	"time"
+)
+
+const RejectReasonDefaultLanguage = "ru"
+
+type RejectReason struct {
+	Code            string            `json:"reason_code"`
+	Label           string            `json:"label"`
+	Labels          map[string]string `json:"labels"`
+	ReasonText      string            `json:"reason_text"`
+	RequiredFixStep string            `json:"required_fix_step"`
+	Severity        string            `json:"severity"`
+	IsActive        bool              `json:"is_active"`
+	SortOrder       int               `json:"sort_order"`
+	UpdatedByTGID   *int64            `json:"updated_by_tg_id,omitempty"`
+	UpdatedAt       *time.Time        `json:"updated_at,omitempty"`
+}
+
+// LabelFor falls back to the default language, then to the flat label and finally to the code.
+func (r RejectReason) LabelFor(lang string) string {
+	if label := strings.TrimSpace(r.Labels[strings.ToLower(strings.TrimSpace(lang))]); label != "" {
+		return label
+	}
+	if label := strings.TrimSpace(r.Labels[RejectReasonDefaultLanguage]); label != "" {
+		return label
+	}
+	if label := strings.TrimSpace(r.Label); label != "" {
+		return label
+	}
+	return r.Code
+}
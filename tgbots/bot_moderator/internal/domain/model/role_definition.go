@@ -0,0 +1,35 @@
+package model
+
+import (
+	"time"
+
+	"bot_moderator/internal/domain/enums"
+)
+
+// RoleDefinition matches the backend /admin/bot/access/roles item.
+type RoleDefinition struct {
+	Name          enums.Role         `json:"name"`
+	Permissions   []enums.Permission `json:"permissions"`
+	IsSystem      bool               `json:"is_system"`
+	UpdatedByTGID *int64             `json:"updated_by_tg_id,omitempty"`
+	UpdatedAt     *time.Time         `json:"updated_at,omitempty"`
+}
+
+func (d RoleDefinition) Has(permission enums.Permission) bool {
+	for _, granted := range d.Permissions {
+		if granted == permission {
+			return true
+		}
+	}
+	return false
+}
+
+// Covers reports whether every permission of other is also held by d.
+func (d RoleDefinition) Covers(other RoleDefinition) bool {
+	for _, permission := range other.Permissions {
+		if !d.Has(permission) {
+			return false
+		}
+	}
+	return true
+}
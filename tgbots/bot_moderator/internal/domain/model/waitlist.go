@@ -0,0 +1,26 @@
+package model
+
+type WaitlistBucket struct {
+	Key   string `json:"key"`
+	Count int64  `json:"count"`
+}
+
+// WaitlistStats matches the backend /admin/bot/waitlist response.
+type WaitlistStats struct {
+	Waiting       int64            `json:"waiting"`
+	AdmittedToday int64            `json:"admitted_today"`
+	PendingNotify int64            `json:"pending_notify"`
+	ByCity        []WaitlistBucket `json:"by_city"`
+	ByGender      []WaitlistBucket `json:"by_gender"`
+}
+
+type WaitlistAdmitRequest struct {
+	Limit   int    `json:"limit"`
+	CityID  string `json:"city_id,omitempty"`
+	Balance bool   `json:"balance,omitempty"`
+}
+
+type WaitlistAdmitResult struct {
+	Admitted int   `json:"admitted"`
+	Waiting  int64 `json:"waiting"`
+}
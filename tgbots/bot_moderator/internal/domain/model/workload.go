@@ -0,0 +1,82 @@
+package model
+
+import (
+	"time"
+
+	"bot_moderator/internal/domain/enums"
+)
+
+const DefaultModeratorMaxActiveItems = 1
+
+type ModeratorShift struct {
+	ID            int64     `json:"id"`
+	ModeratorTGID int64     `json:"moderator_tg_id"`
+	Weekday       int       `json:"weekday"`
+	StartsAt      string    `json:"starts_at"`
+	EndsAt        string    `json:"ends_at"`
+	CreatedByTGID *int64    `json:"created_by_tg_id,omitempty"`
+	CreatedAt     time.Time `json:"created_at"`
+}
+
+type ModeratorWorkload struct {
+	ModeratorTGID  int64                  `json:"moderator_tg_id"`
+	MaxActiveItems int                    `json:"max_active_items"`
+	Skills         []enums.ModeratorSkill `json:"skills"`
+	ActiveItems    int                    `json:"active_items"`
+	OnShift        bool                   `json:"on_shift"`
+	Shifts         []ModeratorShift       `json:"shifts"`
+	UpdatedByTGID  *int64                 `json:"updated_by_tg_id,omitempty"`
+	UpdatedAt      *time.Time             `json:"updated_at,omitempty"`
+}
+
+// DefaultModeratorWorkload is what an unconfigured moderator gets: one card at a time, every skill.
+func DefaultModeratorWorkload(tgID int64) ModeratorWorkload {
+	return ModeratorWorkload{
+		ModeratorTGID:  tgID,
+		MaxActiveItems: DefaultModeratorMaxActiveItems,
+		Skills:         append([]enums.ModeratorSkill(nil), enums.ModeratorSkills...),
+		Shifts:         []ModeratorShift{},
+	}
+}
+
+func (w ModeratorWorkload) HasSkill(skill enums.ModeratorSkill) bool {
+	for _, item := range w.Skills {
+		if item == skill {
+			return true
+		}
+	}
+	return false
+}
+
+type ModerationWorkload struct {
+	Pending    int                 `json:"pending"`
+	Moderators []ModeratorWorkload `json:"moderators"`
+}
+
+func (w ModerationWorkload) Moderator(tgID int64) (ModeratorWorkload, bool) {
+	for _, item := range w.Moderators {
+		if item.ModeratorTGID == tgID {
+			return item, true
+		}
+	}
+	return DefaultModeratorWorkload(tgID), false
+}
+
+type LockReassignment struct {
+	ModerationItemID int64                `json:"moderation_item_id"`
+	UserID           int64                `json:"user_id"`
+	Skill            enums.ModeratorSkill `json:"skill"`
+	FromTGID         int64                `json:"from_tg_id"`
+	ToTGID           int64                `json:"to_tg_id"`
+	IdleSec          float64              `json:"idle_sec"`
+}
+
+type ShiftThroughput struct {
+	ShiftID       int64     `json:"shift_id"`
+	ModeratorTGID int64     `json:"moderator_tg_id"`
+	StartsAt      time.Time `json:"starts_at"`
+	EndsAt        time.Time `json:"ends_at"`
+	Decisions     int       `json:"decisions"`
+	Approved      int       `json:"approved"`
+	Rejected      int       `json:"rejected"`
+}
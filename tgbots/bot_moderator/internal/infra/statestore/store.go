@@ -0,0 +1,248 @@
+package statestore
+
+import (
+	"container/heap"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"sync"
+	"time"
+
+	"bot_moderator/internal/domain/enums"
+	"bot_moderator/internal/infra/telegram"
+	"github.com/redis/go-redis/v9"
+)
+
+const (
+	DefaultTTL          = 30 * time.Minute
+	redisKeyPrefix      = "bot_moderator:state:"
+	redisClaimKeyPrefix = "bot_moderator:claim:"
+)
+
+// Session is the per-chat conversation state shared between replicas.
+type Session struct {
+	State     telegram.State `json:"state"`
+	ActorTGID int64          `json:"actor_tg_id"`
+	ActorRole enums.Role     `json:"actor_role"`
+
+	// ItemID is the moderation item awaiting a reject comment.
+	ItemID int64 `json:"item_id,omitempty"`
+	// TargetUserID is the user awaiting a ban comment; BanReasonCode and BanDuration were picked via buttons.
+	TargetUserID  int64  `json:"target_user_id,omitempty"`
+	BanReasonCode string `json:"ban_reason_code,omitempty"`
+	BanDuration   string `json:"ban_duration,omitempty"`
+
+	LookupQuery       string `json:"lookup_query,omitempty"`
+	LookupFoundUserID int64  `json:"lookup_found_user_id,omitempty"`
+
+	// AuditFilter is the History filter in ParseFilter syntax; it survives other flows.
+	AuditFilter string `json:"audit_filter,omitempty"`
+
+	// ReasonCode and ReasonField identify the reject reason catalog entry being edited.
+	ReasonCode  string `json:"reason_code,omitempty"`
+	ReasonField string `json:"reason_field,omitempty"`
+
+	// ShiftModeratorTGID is the moderator whose weekly shift is being typed in.
+	ShiftModeratorTGID int64 `json:"shift_moderator_tg_id,omitempty"`
+
+	// FlagKey and FlagField identify the feature flag being edited; FlagKey is empty for a new flag.
+	FlagKey   string `json:"flag_key,omitempty"`
+	FlagField string `json:"flag_field,omitempty"`
+
+	// BatchID and BatchItemIDs are the undecided items of the open moderation batch; they survive other flows.
+	BatchID      string  `json:"batch_id,omitempty"`
+	BatchItemIDs []int64 `json:"batch_item_ids,omitempty"`
+
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+type Store interface {
+	Get(ctx context.Context, chatID int64) (Session, bool, error)
+	Set(ctx context.Context, chatID int64, session Session) error
+	Delete(ctx context.Context, chatID int64) error
+
+	// Claim marks key as taken for ttl and reports whether this caller got it first.
+	Claim(ctx context.Context, key string, ttl time.Duration) (bool, error)
+	Release(ctx context.Context, key string) error
+}
+
+type MemoryStore struct {
+	ttl time.Duration
+	now func() time.Time
+
+	mu       sync.Mutex
+	sessions map[int64]memoryEntry
+	claims   map[string]time.Time
+	// expiries orders claims by deadline so Claim only touches the ones that ran out.
+	expiries claimHeap
+}
+
+type memoryEntry struct {
+	session   Session
+	expiresAt time.Time
+}
+
+type claimExpiry struct {
+	key       string
+	expiresAt time.Time
+}
+
+// claimHeap is a min-heap of claim deadlines. Entries left behind by Release or a re-claim
+// are skipped when popped because the map no longer holds their deadline.
+type claimHeap []claimExpiry
+
+func (h claimHeap) Len() int           { return len(h) }
+func (h claimHeap) Less(i, j int) bool { return h[i].expiresAt.Before(h[j].expiresAt) }
+func (h claimHeap) Swap(i, j int)      { h[i], h[j] = h[j], h[i] }
+func (h *claimHeap) Push(x any)        { *h = append(*h, x.(claimExpiry)) }
+func (h *claimHeap) Pop() any {
+	old := *h
+	last := old[len(old)-1]
+	*h = old[:len(old)-1]
+	return last
+}
+
+func NewMemoryStore(ttl time.Duration) *MemoryStore {
+	if ttl <= 0 {
+		ttl = DefaultTTL
+	}
+	return &MemoryStore{
+		ttl:      ttl,
+		now:      time.Now,
+		sessions: make(map[int64]memoryEntry),
+		claims:   make(map[string]time.Time),
+	}
+}
+
+func (s *MemoryStore) Get(_ context.Context, chatID int64) (Session, bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	entry, ok := s.sessions[chatID]
+	if !ok {
+		return Session{}, false, nil
+	}
+	if !s.now().Before(entry.expiresAt) {
+		delete(s.sessions, chatID)
+		return Session{}, false, nil
+	}
+	return entry.session, true, nil
+}
+
+func (s *MemoryStore) Set(_ context.Context, chatID int64, session Session) error {
+	now := s.now()
+	session.UpdatedAt = now.UTC()
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.sessions[chatID] = memoryEntry{session: session, expiresAt: now.Add(s.ttl)}
+	return nil
+}
+
+func (s *MemoryStore) Delete(_ context.Context, chatID int64) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.sessions, chatID)
+	return nil
+}
+
+func (s *MemoryStore) Claim(_ context.Context, key string, ttl time.Duration) (bool, error) {
+	now := s.now()
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.expireClaims(now)
+	if _, ok := s.claims[key]; ok {
+		return false, nil
+	}
+	expiresAt := now.Add(ttl)
+	s.claims[key] = expiresAt
+	heap.Push(&s.expiries, claimExpiry{key: key, expiresAt: expiresAt})
+	return true, nil
+}
+
+// expireClaims drops claims whose deadline passed; the caller holds s.mu.
+func (s *MemoryStore) expireClaims(now time.Time) {
+	for s.expiries.Len() > 0 && !now.Before(s.expiries[0].expiresAt) {
+		expired := heap.Pop(&s.expiries).(claimExpiry)
+		if expiresAt, ok := s.claims[expired.key]; ok && expiresAt.Equal(expired.expiresAt) {
+			delete(s.claims, expired.key)
+		}
+	}
+}
+
+func (s *MemoryStore) Release(_ context.Context, key string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.claims, key)
+	return nil
+}
+
+type RedisStore struct {
+	client redis.UniversalClient
+	ttl    time.Duration
+}
+
+func NewRedisStore(client redis.UniversalClient, ttl time.Duration) *RedisStore {
+	if ttl <= 0 {
+		ttl = DefaultTTL
+	}
+	return &RedisStore{client: client, ttl: ttl}
+}
+
+func (s *RedisStore) Get(ctx context.Context, chatID int64) (Session, bool, error) {
+	raw, err := s.client.Get(ctx, redisKey(chatID)).Bytes()
+	if errors.Is(err, redis.Nil) {
+		return Session{}, false, nil
+	}
+	if err != nil {
+		return Session{}, false, fmt.Errorf("get chat state: %w", err)
+	}
+
+	session := Session{}
+	if err := json.Unmarshal(raw, &session); err != nil {
+		return Session{}, false, fmt.Errorf("decode chat state: %w", err)
+	}
+	return session, true, nil
+}
+
+func (s *RedisStore) Set(ctx context.Context, chatID int64, session Session) error {
+	session.UpdatedAt = time.Now().UTC()
+	raw, err := json.Marshal(session)
+	if err != nil {
+		return fmt.Errorf("encode chat state: %w", err)
+	}
+	if err := s.client.Set(ctx, redisKey(chatID), raw, s.ttl).Err(); err != nil {
+		return fmt.Errorf("set chat state: %w", err)
+	}
+	return nil
+}
+
+func (s *RedisStore) Delete(ctx context.Context, chatID int64) error {
+	if err := s.client.Del(ctx, redisKey(chatID)).Err(); err != nil {
+		return fmt.Errorf("delete chat state: %w", err)
+	}
+	return nil
+}
+
+func (s *RedisStore) Claim(ctx context.Context, key string, ttl time.Duration) (bool, error) {
+	ok, err := s.client.SetNX(ctx, redisClaimKeyPrefix+key, 1, ttl).Result()
+	if err != nil {
+		return false, fmt.Errorf("claim %s: %w", key, err)
+	}
+	return ok, nil
+}
+
+func (s *RedisStore) Release(ctx context.Context, key string) error {
+	if err := s.client.Del(ctx, redisClaimKeyPrefix+key).Err(); err != nil {
+		return fmt.Errorf("release %s: %w", key, err)
+	}
+	return nil
+}
+
+func redisKey(chatID int64) string {
+	return redisKeyPrefix + strconv.FormatInt(chatID, 10)
+}
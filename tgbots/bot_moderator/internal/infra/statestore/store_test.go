@@ -0,0 +1,144 @@
+package statestore
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"bot_moderator/internal/domain/enums"
+	"bot_moderator/internal/infra/telegram"
+	"github.com/alicebob/miniredis/v2"
+	"github.com/redis/go-redis/v9"
+)
+
+func TestMemoryStoreExpiresAbandonedSessions(t *testing.T) {
+	store := NewMemoryStore(time.Minute)
+	now := time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+	store.now = func() time.Time { return now }
+
+	ctx := context.Background()
+	if err := store.Set(ctx, 10, Session{State: telegram.StateWaitingRejectReason, ActorTGID: 1, ItemID: 5}); err != nil {
+		t.Fatalf("set: %v", err)
+	}
+
+	session, ok, err := store.Get(ctx, 10)
+	if err != nil || !ok || session.ItemID != 5 {
+		t.Fatalf("expected stored session, got %+v ok=%v err=%v", session, ok, err)
+	}
+
+	now = now.Add(time.Minute)
+	if _, ok, _ := store.Get(ctx, 10); ok {
+		t.Fatal("expected session to expire after ttl")
+	}
+}
+
+func TestRedisStoreRoundTripAndTTL(t *testing.T) {
+	server := miniredis.RunT(t)
+	client := redis.NewClient(&redis.Options{Addr: server.Addr()})
+	defer client.Close()
+
+	store := NewRedisStore(client, 2*time.Minute)
+	ctx := context.Background()
+
+	input := Session{
+		State:             telegram.StateWaitingBanReason,
+		ActorTGID:         7,
+		ActorRole:         enums.RoleAdmin,
+		TargetUserID:      42,
+		LookupQuery:       "@user",
+		LookupFoundUserID: 42,
+	}
+	if err := store.Set(ctx, 99, input); err != nil {
+		t.Fatalf("set: %v", err)
+	}
+
+	session, ok, err := store.Get(ctx, 99)
+	if err != nil || !ok {
+		t.Fatalf("expected session, ok=%v err=%v", ok, err)
+	}
+	if session.State != telegram.StateWaitingBanReason || session.TargetUserID != 42 || session.ActorRole != enums.RoleAdmin || session.LookupQuery != "@user" {
+		t.Fatalf("unexpected session %+v", session)
+	}
+
+	server.FastForward(2 * time.Minute)
+	if _, ok, err := store.Get(ctx, 99); err != nil || ok {
+		t.Fatalf("expected session to expire, ok=%v err=%v", ok, err)
+	}
+
+	if err := store.Set(ctx, 99, input); err != nil {
+		t.Fatalf("set: %v", err)
+	}
+	if err := store.Delete(ctx, 99); err != nil {
+		t.Fatalf("delete: %v", err)
+	}
+	if _, ok, _ := store.Get(ctx, 99); ok {
+		t.Fatal("expected session to be deleted")
+	}
+}
+
+func TestClaimIsExclusiveUntilExpiryOrRelease(t *testing.T) {
+	server := miniredis.RunT(t)
+	client := redis.NewClient(&redis.Options{Addr: server.Addr()})
+	defer client.Close()
+
+	memory := NewMemoryStore(time.Minute)
+	now := time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+	memory.now = func() time.Time { return now }
+
+	stores := map[string]struct {
+		store   Store
+		advance func(time.Duration)
+	}{
+		"memory": {store: memory, advance: func(d time.Duration) { now = now.Add(d) }},
+		"redis":  {store: NewRedisStore(client, time.Minute), advance: server.FastForward},
+	}
+
+	ctx := context.Background()
+	for name, tc := range stores {
+		if ok, err := tc.store.Claim(ctx, "update:1", time.Minute); err != nil || !ok {
+			t.Fatalf("%s: expected first claim, ok=%v err=%v", name, ok, err)
+		}
+		if ok, err := tc.store.Claim(ctx, "update:1", time.Minute); err != nil || ok {
+			t.Fatalf("%s: expected duplicate claim to be rejected, ok=%v err=%v", name, ok, err)
+		}
+
+		tc.advance(time.Minute)
+		if ok, _ := tc.store.Claim(ctx, "update:1", time.Minute); !ok {
+			t.Fatalf("%s: expected claim after expiry", name)
+		}
+
+		if err := tc.store.Release(ctx, "update:1"); err != nil {
+			t.Fatalf("%s: release: %v", name, err)
+		}
+		if ok, _ := tc.store.Claim(ctx, "update:1", time.Minute); !ok {
+			t.Fatalf("%s: expected claim after release", name)
+		}
+	}
+}
+
+func TestMemoryClaimExpiryKeepsReclaimedKeys(t *testing.T) {
+	store := NewMemoryStore(time.Minute)
+	now := time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+	store.now = func() time.Time { return now }
+	ctx := context.Background()
+
+	_, _ = store.Claim(ctx, "update:1", time.Minute)
+	_ = store.Release(ctx, "update:1")
+	now = now.Add(30 * time.Second)
+	_, _ = store.Claim(ctx, "update:1", time.Minute)
+	_, _ = store.Claim(ctx, "update:2", time.Minute)
+
+	// The first claim's deadline passes; its stale heap entry must not drop the re-claim.
+	now = now.Add(31 * time.Second)
+	if ok, _ := store.Claim(ctx, "update:1", time.Minute); ok {
+		t.Fatal("expected the re-claimed key to stay taken")
+	}
+
+	now = now.Add(time.Hour)
+	if ok, _ := store.Claim(ctx, "update:3", time.Minute); !ok {
+		t.Fatal("expected a fresh claim")
+	}
+	if len(store.claims) != 1 || store.expiries.Len() != 1 {
+		t.Fatalf("expected expired claims to be dropped, claims=%d expiries=%d", len(store.claims), store.expiries.Len())
+	}
+}
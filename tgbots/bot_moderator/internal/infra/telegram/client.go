@@ -61,6 +61,11 @@ func (c *Client) Start(ctx context.Context) error {
 		timeout = 30
 	}
 
+	// getUpdates is rejected while a webhook from a previous webhook-mode run is still registered.
+	if _, err := c.api.Request(tgbotapi.DeleteWebhookConfig{}); err != nil {
+		c.logger.Warn("delete telegram webhook", "error", err)
+	}
+
 	updateConfig := tgbotapi.NewUpdate(0)
 	updateConfig.Timeout = timeout
 	updates := c.api.GetUpdatesChan(updateConfig)
@@ -86,3 +91,11 @@ func (c *Client) Send(msg tgbotapi.Chattable) error {
 	_, err := c.api.Send(msg)
 	return err
 }
+
+func (c *Client) SendMediaGroup(cfg tgbotapi.MediaGroupConfig) error {
+	if c.dryRun {
+		return nil
+	}
+	_, err := c.api.SendMediaGroup(cfg)
+	return err
+}
@@ -6,4 +6,40 @@ const (
 	StateIdle                State = "IDLE"
 	StateWaitingRejectReason State = "WAITING_REJECT_REASON"
 	StateWaitingBanReason    State = "WAITING_BAN_REASON"
+	StateWaitingLookupQuery  State = "WAITING_LOOKUP_QUERY"
+	StateWaitingAuditFilter  State = "WAITING_AUDIT_FILTER"
+	StateWaitingReasonEdit   State = "WAITING_REJECT_REASON_EDIT"
+	StateWaitingRoleName     State = "WAITING_ROLE_NAME"
+	StateWaitingShiftInput   State = "WAITING_SHIFT_INPUT"
+	StateWaitingFlagInput    State = "WAITING_FLAG_INPUT"
 )
+
+// Input flows always start from IDLE (or restart themselves) and return to IDLE;
+// switching between two input flows goes through IDLE so abandoned prompts don't leak.
+var stateTransitions = map[State][]State{
+	StateIdle:                {StateIdle, StateWaitingRejectReason, StateWaitingBanReason, StateWaitingLookupQuery, StateWaitingAuditFilter, StateWaitingReasonEdit, StateWaitingRoleName, StateWaitingShiftInput, StateWaitingFlagInput},
+	StateWaitingRejectReason: {StateIdle, StateWaitingRejectReason},
+	StateWaitingBanReason:    {StateIdle, StateWaitingBanReason},
+	StateWaitingLookupQuery:  {StateIdle, StateWaitingLookupQuery},
+	StateWaitingAuditFilter:  {StateIdle, StateWaitingAuditFilter},
+	StateWaitingReasonEdit:   {StateIdle, StateWaitingReasonEdit},
+	StateWaitingRoleName:     {StateIdle, StateWaitingRoleName},
+	StateWaitingShiftInput:   {StateIdle, StateWaitingShiftInput},
+	StateWaitingFlagInput:    {StateIdle, StateWaitingFlagInput},
+}
+
+func (s State) Normalize() State {
+	if _, ok := stateTransitions[s]; ok {
+		return s
+	}
+	return StateIdle
+}
+
+func (s State) CanTransition(next State) bool {
+	for _, allowed := range stateTransitions[s.Normalize()] {
+		if allowed == next {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,28 @@
+package telegram
+
+import "testing"
+
+func TestStateTransitions(t *testing.T) {
+	cases := []struct {
+		from, to State
+		allowed  bool
+	}{
+		{StateIdle, StateWaitingRejectReason, true},
+		{StateWaitingRejectReason, StateIdle, true},
+		{StateWaitingRejectReason, StateWaitingBanReason, false},
+		{StateWaitingLookupQuery, StateWaitingRejectReason, false},
+		{State(""), StateWaitingLookupQuery, true},
+		{StateWaitingAuditFilter, StateWaitingLookupQuery, false},
+		{StateIdle, StateWaitingReasonEdit, true},
+		{StateWaitingReasonEdit, StateWaitingRejectReason, false},
+		{StateIdle, StateWaitingRoleName, true},
+		{StateWaitingRoleName, StateWaitingLookupQuery, false},
+		{StateIdle, StateWaitingShiftInput, true},
+		{StateWaitingShiftInput, StateWaitingRoleName, false},
+	}
+	for _, tc := range cases {
+		if got := tc.from.CanTransition(tc.to); got != tc.allowed {
+			t.Fatalf("%s -> %s: expected %v, got %v", tc.from, tc.to, tc.allowed, got)
+		}
+	}
+}
@@ -0,0 +1,116 @@
+package telegram
+
+import (
+	"context"
+	"crypto/subtle"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+)
+
+const (
+	SecretTokenHeader  = "X-Telegram-Bot-Api-Secret-Token"
+	maxWebhookBodySize = 1 << 20
+)
+
+type WebhookConfig struct {
+	ListenAddr string
+	Path       string
+	PublicURL  string
+	Secret     string
+}
+
+// WebhookHandler validates the secret header and hands the update to the router synchronously,
+// so Telegram only gets 200 once the update has been processed.
+func (c *Client) WebhookHandler(secret string) http.Handler {
+	expected := []byte(secret)
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			w.WriteHeader(http.StatusMethodNotAllowed)
+			return
+		}
+		provided := []byte(r.Header.Get(SecretTokenHeader))
+		if len(expected) == 0 || subtle.ConstantTimeCompare(provided, expected) != 1 {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+
+		var update tgbotapi.Update
+		if err := json.NewDecoder(io.LimitReader(r.Body, maxWebhookBodySize)).Decode(&update); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+
+		c.handler(context.WithoutCancel(r.Context()), update)
+		w.WriteHeader(http.StatusOK)
+	})
+}
+
+// StartWebhook registers the webhook with Telegram and serves updates until ctx is done.
+func (c *Client) StartWebhook(ctx context.Context, cfg WebhookConfig) error {
+	if strings.TrimSpace(cfg.Secret) == "" {
+		return errors.New("webhook secret is required")
+	}
+	path := cfg.Path
+	if path == "" {
+		path = "/telegram/webhook"
+	}
+
+	if !c.dryRun {
+		if err := c.setWebhook(cfg.PublicURL, cfg.Secret); err != nil {
+			return fmt.Errorf("set telegram webhook: %w", err)
+		}
+	}
+
+	mux := http.NewServeMux()
+	mux.Handle(path, c.WebhookHandler(cfg.Secret))
+	mux.HandleFunc("/healthz", func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	})
+
+	server := &http.Server{
+		Addr:              cfg.ListenAddr,
+		Handler:           mux,
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+
+	errCh := make(chan error, 1)
+	go func() {
+		errCh <- server.ListenAndServe()
+	}()
+	c.logger.Info("telegram webhook listening", "addr", cfg.ListenAddr, "path", path)
+
+	select {
+	case <-ctx.Done():
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+		return server.Shutdown(shutdownCtx)
+	case err := <-errCh:
+		if errors.Is(err, http.ErrServerClosed) {
+			return nil
+		}
+		return err
+	}
+}
+
+func (c *Client) setWebhook(publicURL string, secret string) error {
+	if strings.TrimSpace(publicURL) == "" {
+		return errors.New("webhook public url is required")
+	}
+
+	params := tgbotapi.Params{
+		"url":          strings.TrimSpace(publicURL),
+		"secret_token": secret,
+	}
+	if err := params.AddInterface("allowed_updates", []string{"message", "callback_query"}); err != nil {
+		return err
+	}
+	_, err := c.api.MakeRequest("setWebhook", params)
+	return err
+}
@@ -0,0 +1,50 @@
+package telegram
+
+import (
+	"context"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+)
+
+func TestWebhookHandlerValidatesSecret(t *testing.T) {
+	var received []int
+	client, err := NewClient("", 0, slog.Default(), func(_ context.Context, update tgbotapi.Update) {
+		received = append(received, update.UpdateID)
+	})
+	if err != nil {
+		t.Fatalf("new client: %v", err)
+	}
+	handler := client.WebhookHandler("s3cret")
+
+	cases := []struct {
+		name   string
+		secret string
+		body   string
+		status int
+	}{
+		{name: "missing secret", body: `{"update_id":1}`, status: http.StatusUnauthorized},
+		{name: "wrong secret", secret: "nope", body: `{"update_id":2}`, status: http.StatusUnauthorized},
+		{name: "bad body", secret: "s3cret", body: `{`, status: http.StatusBadRequest},
+		{name: "ok", secret: "s3cret", body: `{"update_id":3}`, status: http.StatusOK},
+	}
+	for _, tc := range cases {
+		req := httptest.NewRequest(http.MethodPost, "/telegram/webhook", strings.NewReader(tc.body))
+		if tc.secret != "" {
+			req.Header.Set(SecretTokenHeader, tc.secret)
+		}
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, req)
+		if rec.Code != tc.status {
+			t.Fatalf("%s: expected %d, got %d", tc.name, tc.status, rec.Code)
+		}
+	}
+
+	if len(received) != 1 || received[0] != 3 {
+		t.Fatalf("expected only update 3 to be routed, got %v", received)
+	}
+}
@@ -83,16 +83,7 @@ func (r *AccessRolesRepo) GetActiveRole(ctx context.Context, tgID int64) (enums.
 		return enums.RoleNone, err
 	}
 
-	switch response.Role {
-	case string(enums.RoleOwner):
-		return enums.RoleOwner, nil
-	case string(enums.RoleAdmin):
-		return enums.RoleAdmin, nil
-	case string(enums.RoleModerator):
-		return enums.RoleModerator, nil
-	default:
-		return enums.RoleNone, nil
-	}
+	return enums.NormalizeRole(response.Role), nil
 }
 
 func (r *AccessRolesRepo) ListActive(ctx context.Context) ([]model.BotRoleAssignment, error) {
@@ -2,6 +2,9 @@ package adminhttp
 
 import (
 	"context"
+	"net/url"
+	"strconv"
+	"time"
 
 	"bot_moderator/internal/domain/model"
 	"bot_moderator/internal/repo/postgres"
@@ -42,3 +45,48 @@ func (r *AuditRepo) ListRecent(ctx context.Context, limit int) ([]model.Audit, e
 	}
 	return response.Items, nil
 }
+
+func (r *AuditRepo) Search(ctx context.Context, filter model.AuditFilter) ([]model.Audit, error) {
+	query := url.Values{}
+	if filter.ActorTGID != 0 {
+		query.Set("actor_tg_id", strconv.FormatInt(filter.ActorTGID, 10))
+	}
+	if filter.TargetUserID != 0 {
+		query.Set("target_user_id", strconv.FormatInt(filter.TargetUserID, 10))
+	}
+	if filter.Action != "" {
+		query.Set("action", string(filter.Action))
+	}
+	if filter.From != nil {
+		query.Set("from", filter.From.UTC().Format(time.RFC3339))
+	}
+	if filter.To != nil {
+		query.Set("to", filter.To.UTC().Format(time.RFC3339))
+	}
+	query.Set("limit", intToString(filter.Limit))
+	query.Set("offset", intToString(filter.Offset))
+
+	response := struct {
+		Items []model.Audit `json:"items"`
+	}{}
+	err := r.client.DoJSON(ctx, "GET", "/admin/bot/audit?"+query.Encode(), nil, &response)
+	if shouldFallbackWithNotFound(r.dual, err) && r.db != nil {
+		return r.db.Search(ctx, filter)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return response.Items, nil
+}
+
+func (r *AuditRepo) VerifyChain(ctx context.Context) (model.AuditChainReport, error) {
+	response := model.AuditChainReport{}
+	err := r.client.DoJSON(ctx, "GET", "/admin/bot/audit/verify", nil, &response)
+	if shouldFallbackWithNotFound(r.dual, err) && r.db != nil {
+		return r.db.VerifyChain(ctx)
+	}
+	if err != nil {
+		return model.AuditChainReport{}, err
+	}
+	return response, nil
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"net/http"
 
+	"bot_moderator/internal/domain/model"
 	"bot_moderator/internal/repo/postgres"
 )
 
@@ -21,17 +22,21 @@ func NewBansRepo(client *Client, db *postgres.BansRepo, dual bool) *BansRepo {
 	}
 }
 
-func (r *BansRepo) Ban(ctx context.Context, userID int64, reason string, updatedByTGID int64) error {
+// Ban never falls back to the database: only the backend can revoke the user's app sessions.
+func (r *BansRepo) Ban(ctx context.Context, in model.BanInput) (model.BanState, error) {
 	request := map[string]interface{}{
-		"user_id":          userID,
-		"reason":           reason,
-		"updated_by_tg_id": updatedByTGID,
+		"user_id":          in.UserID,
+		"reason_code":      in.ReasonCode,
+		"reason":           in.Reason,
+		"duration":         in.Duration,
+		"updated_by_tg_id": in.ActorTGID,
 	}
-	err := r.client.DoJSON(ctx, http.MethodPost, "/admin/bot/users/"+int64ToString(userID)+"/ban", request, nil)
-	if shouldFallbackWithNotFound(r.dual, err) && r.db != nil {
-		return r.db.Ban(ctx, userID, reason, updatedByTGID)
+	response := model.BanState{}
+	err := r.client.DoJSON(ctx, http.MethodPost, "/admin/bot/users/"+int64ToString(in.UserID)+"/ban", request, &response)
+	if err != nil {
+		return model.BanState{}, err
 	}
-	return err
+	return response, nil
 }
 
 func (r *BansRepo) Unban(ctx context.Context, userID int64, updatedByTGID int64) error {
@@ -46,17 +51,14 @@ func (r *BansRepo) Unban(ctx context.Context, userID int64, updatedByTGID int64)
 	return err
 }
 
-func (r *BansRepo) GetBanState(ctx context.Context, userID int64) (bool, string, error) {
-	response := struct {
-		Banned bool   `json:"banned"`
-		Reason string `json:"reason"`
-	}{}
+func (r *BansRepo) GetBanState(ctx context.Context, userID int64) (model.BanState, error) {
+	response := model.BanState{}
 	err := r.client.DoJSON(ctx, http.MethodGet, "/admin/bot/bans/state?user_id="+int64ToString(userID), nil, &response)
 	if shouldFallback(r.dual, err) && r.db != nil {
 		return r.db.GetBanState(ctx, userID)
 	}
 	if err != nil {
-		return false, "", err
+		return model.BanState{}, err
 	}
-	return response.Banned, response.Reason, nil
+	return response, nil
 }
@@ -14,6 +14,8 @@ import (
 	"strconv"
 	"strings"
 	"time"
+
+	"bot_moderator/internal/domain/enums"
 )
 
 type Client struct {
@@ -33,6 +35,10 @@ type actorTGIDContextKeyType struct{}
 
 var actorTGIDContextKey actorTGIDContextKeyType
 
+type actorRoleContextKeyType struct{}
+
+var actorRoleContextKey actorRoleContextKeyType
+
 func (e *RequestError) Error() string {
 	if e == nil {
 		return ""
@@ -127,6 +133,22 @@ func ActorTGIDFromContext(ctx context.Context) int64 {
 	return value
 }
 
+// WithActorRole lets the backend check the actor's own permissions instead of the bot-wide role.
+func WithActorRole(ctx context.Context, role enums.Role) context.Context {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	return context.WithValue(ctx, actorRoleContextKey, role)
+}
+
+func ActorRoleFromContext(ctx context.Context) enums.Role {
+	if ctx == nil {
+		return ""
+	}
+	value, _ := ctx.Value(actorRoleContextKey).(enums.Role)
+	return value
+}
+
 func (c *Client) DoJSON(ctx context.Context, method string, path string, requestBody interface{}, responseBody interface{}) error {
 	if c == nil || c.httpClient == nil {
 		return &RequestError{
@@ -206,6 +228,9 @@ func (c *Client) do(ctx context.Context, method string, path string, body []byte
 	}
 	req.Header.Set("X-Admin-Bot-Token", c.botToken)
 	req.Header.Set("X-Actor-Tg-Id", strconv.FormatInt(actorTGID, 10))
+	if role := ActorRoleFromContext(ctx); role != "" && role != enums.RoleNone {
+		req.Header.Set("X-Actor-Role", string(role))
+	}
 	req.Header.Set("Content-Type", "application/json")
 
 	resp, err := c.httpClient.Do(req)
@@ -8,6 +8,8 @@ import (
 	"strings"
 	"testing"
 	"time"
+
+	"bot_moderator/internal/domain/enums"
 )
 
 func TestClientDoSetsRequiredHeaders(t *testing.T) {
@@ -25,6 +27,9 @@ func TestClientDoSetsRequiredHeaders(t *testing.T) {
 		if got := r.Header.Get("X-Actor-Tg-Id"); got != "777001" {
 			t.Fatalf("unexpected X-Actor-Tg-Id: %q", got)
 		}
+		if got := r.Header.Get("X-Actor-Role"); got != "SUPPORT" {
+			t.Fatalf("unexpected X-Actor-Role: %q", got)
+		}
 		if got := r.Header.Get("Content-Type"); got != "application/json" {
 			t.Fatalf("unexpected Content-Type: %q", got)
 		}
@@ -38,7 +43,8 @@ func TestClientDoSetsRequiredHeaders(t *testing.T) {
 		t.Fatalf("new client: %v", err)
 	}
 
-	status, response, err := client.do(context.Background(), http.MethodPost, "/admin/test", []byte(`{"x":1}`), actorID)
+	ctx := WithActorRole(context.Background(), enums.RoleSupport)
+	status, response, err := client.do(ctx, http.MethodPost, "/admin/test", []byte(`{"x":1}`), actorID)
 	if err != nil {
 		t.Fatalf("do request: %v", err)
 	}
@@ -0,0 +1,72 @@
+package adminhttp
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/url"
+
+	"bot_moderator/internal/domain/model"
+	"bot_moderator/internal/repo/postgres"
+	"bot_moderator/internal/services/system"
+)
+
+type FlagsRepo struct {
+	client *Client
+	db     *postgres.FlagsRepo
+	dual   bool
+}
+
+func NewFlagsRepo(client *Client, db *postgres.FlagsRepo, dual bool) *FlagsRepo {
+	return &FlagsRepo{
+		client: client,
+		db:     db,
+		dual:   dual,
+	}
+}
+
+func (r *FlagsRepo) ListFlags(ctx context.Context) ([]model.AppFlag, error) {
+	response := struct {
+		Items []model.AppFlag `json:"items"`
+	}{}
+	err := r.client.DoJSON(ctx, http.MethodGet, "/admin/bot/flags", nil, &response)
+	if shouldFallbackWithNotFound(r.dual, err) && r.db != nil {
+		return r.db.ListFlags(ctx)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return response.Items, nil
+}
+
+func (r *FlagsRepo) UpsertFlag(ctx context.Context, flag model.AppFlag, updatedByTGID int64) (model.AppFlag, error) {
+	request := map[string]interface{}{
+		"type":           flag.Type,
+		"value":          flag.Value,
+		"rules":          flag.Rules,
+		"description":    flag.Description,
+		"client_visible": flag.ClientVisible,
+	}
+
+	response := model.AppFlag{}
+	err := r.client.DoJSON(ctx, http.MethodPut, "/admin/bot/flags/"+url.PathEscape(flag.Key), request, &response)
+	if shouldFallbackWithNotFound(r.dual, err) && r.db != nil {
+		return r.db.UpsertFlag(ctx, flag, updatedByTGID)
+	}
+	if err != nil {
+		return model.AppFlag{}, err
+	}
+	return response, nil
+}
+
+func (r *FlagsRepo) DeleteFlag(ctx context.Context, key string) error {
+	err := r.client.DoJSON(ctx, http.MethodDelete, "/admin/bot/flags/"+url.PathEscape(key), nil, nil)
+	if shouldFallbackWithNotFound(r.dual, err) && r.db != nil {
+		return r.db.DeleteFlag(ctx, key)
+	}
+	var reqErr *RequestError
+	if errors.As(err, &reqErr) && reqErr.StatusCode == http.StatusNotFound {
+		return system.ErrFlagNotFound
+	}
+	return err
+}
@@ -3,6 +3,7 @@ package adminhttp
 import (
 	"context"
 	"net/http"
+	"net/url"
 	"strings"
 	"time"
 
@@ -59,6 +60,26 @@ func (r *UsersLookupRepo) FindByUserID(ctx context.Context, userID int64) (model
 	return user, nil
 }
 
+func (r *UsersLookupRepo) Dossier(ctx context.Context, query model.DossierQuery) (model.Dossier, error) {
+	values := url.Values{}
+	values.Set("user_id", int64ToString(query.UserID))
+	if query.Section != "" {
+		values.Set("section", query.Section)
+	}
+	values.Set("limit", intToString(query.Limit))
+	values.Set("offset", intToString(query.Offset))
+
+	response := model.Dossier{}
+	err := r.client.DoJSON(ctx, http.MethodGet, "/admin/bot/lookup/dossier?"+values.Encode(), nil, &response)
+	if shouldFallbackWithNotFound(r.dual, err) && r.db != nil {
+		return r.db.Dossier(ctx, query)
+	}
+	if err != nil {
+		return model.Dossier{}, err
+	}
+	return response, nil
+}
+
 func (r *UsersLookupRepo) InsertAction(ctx context.Context, action model.BotLookupAction) error {
 	err := r.client.DoJSON(ctx, http.MethodPost, "/admin/bot/lookup/action", action, nil)
 	if shouldFallback(r.dual, err) && r.db != nil {
@@ -82,31 +103,34 @@ type lookupUserEnvelope struct {
 	User lookupUserDTO `json:"user"`
 	Item lookupUserDTO `json:"item"`
 
-	UserID           int64      `json:"user_id"`
-	TGID             int64      `json:"tg_id"`
-	Username         string     `json:"username"`
-	CityID           string     `json:"city_id"`
-	Birthdate        *time.Time `json:"birthdate"`
-	Age              int        `json:"age"`
-	Gender           string     `json:"gender"`
-	LookingFor       string     `json:"looking_for"`
-	Goals            []string   `json:"goals"`
-	Languages        []string   `json:"languages"`
-	Occupation       string     `json:"occupation"`
-	Education        string     `json:"education"`
-	ModerationStatus string     `json:"moderation_status"`
-	Approved         bool       `json:"approved"`
-	PhotoKeys        []string   `json:"photo_keys"`
-	CircleKey        string     `json:"circle_key"`
-	PhotoURLs        []string   `json:"photo_urls"`
-	CircleURL        string     `json:"circle_url"`
-	PlusExpiresAt    *time.Time `json:"plus_expires_at"`
-	BoostUntil       *time.Time `json:"boost_until"`
-	SuperlikeCredits int        `json:"superlike_credits"`
-	RevealCredits    int        `json:"reveal_credits"`
-	LikeTokens       int        `json:"like_tokens"`
-	IsBanned         bool       `json:"is_banned"`
-	BanReason        string     `json:"ban_reason"`
+	UserID           int64                `json:"user_id"`
+	TGID             int64                `json:"tg_id"`
+	Username         string               `json:"username"`
+	CityID           string               `json:"city_id"`
+	Birthdate        *time.Time           `json:"birthdate"`
+	Age              int                  `json:"age"`
+	Gender           string               `json:"gender"`
+	LookingFor       string               `json:"looking_for"`
+	Goals            []string             `json:"goals"`
+	Languages        []string             `json:"languages"`
+	Occupation       string               `json:"occupation"`
+	Education        string               `json:"education"`
+	ModerationStatus string               `json:"moderation_status"`
+	Approved         bool                 `json:"approved"`
+	PhotoKeys        []string             `json:"photo_keys"`
+	CircleKey        string               `json:"circle_key"`
+	PhotoURLs        []string             `json:"photo_urls"`
+	CircleURL        string               `json:"circle_url"`
+	PlusExpiresAt    *time.Time           `json:"plus_expires_at"`
+	BoostUntil       *time.Time           `json:"boost_until"`
+	SuperlikeCredits int                  `json:"superlike_credits"`
+	RevealCredits    int                  `json:"reveal_credits"`
+	LikeTokens       int                  `json:"like_tokens"`
+	IsBanned         bool                 `json:"is_banned"`
+	BanReason        string               `json:"ban_reason"`
+	BanReasonCode    string               `json:"ban_reason_code"`
+	BanExpiresAt     *time.Time           `json:"ban_expires_at"`
+	EvasionMatches   []model.EvasionMatch `json:"evasion_matches"`
 }
 
 func (e lookupUserEnvelope) toModel() model.LookupUser {
@@ -142,6 +166,9 @@ func (e lookupUserEnvelope) toModel() model.LookupUser {
 		LikeTokens:       e.LikeTokens,
 		IsBanned:         e.IsBanned,
 		BanReason:        strings.TrimSpace(e.BanReason),
+		BanReasonCode:    strings.TrimSpace(e.BanReasonCode),
+		BanExpiresAt:     e.BanExpiresAt,
+		EvasionMatches:   e.EvasionMatches,
 	}
 }
 
@@ -156,31 +183,34 @@ func (e lookupUserEnvelope) pickDTO() lookupUserDTO {
 }
 
 type lookupUserDTO struct {
-	UserID           int64      `json:"user_id"`
-	TGID             int64      `json:"tg_id"`
-	Username         string     `json:"username"`
-	CityID           string     `json:"city_id"`
-	Birthdate        *time.Time `json:"birthdate"`
-	Age              int        `json:"age"`
-	Gender           string     `json:"gender"`
-	LookingFor       string     `json:"looking_for"`
-	Goals            []string   `json:"goals"`
-	Languages        []string   `json:"languages"`
-	Occupation       string     `json:"occupation"`
-	Education        string     `json:"education"`
-	ModerationStatus string     `json:"moderation_status"`
-	Approved         bool       `json:"approved"`
-	PhotoKeys        []string   `json:"photo_keys"`
-	CircleKey        string     `json:"circle_key"`
-	PhotoURLs        []string   `json:"photo_urls"`
-	CircleURL        string     `json:"circle_url"`
-	PlusExpiresAt    *time.Time `json:"plus_expires_at"`
-	BoostUntil       *time.Time `json:"boost_until"`
-	SuperlikeCredits int        `json:"superlike_credits"`
-	RevealCredits    int        `json:"reveal_credits"`
-	LikeTokens       int        `json:"like_tokens"`
-	IsBanned         bool       `json:"is_banned"`
-	BanReason        string     `json:"ban_reason"`
+	UserID           int64                `json:"user_id"`
+	TGID             int64                `json:"tg_id"`
+	Username         string               `json:"username"`
+	CityID           string               `json:"city_id"`
+	Birthdate        *time.Time           `json:"birthdate"`
+	Age              int                  `json:"age"`
+	Gender           string               `json:"gender"`
+	LookingFor       string               `json:"looking_for"`
+	Goals            []string             `json:"goals"`
+	Languages        []string             `json:"languages"`
+	Occupation       string               `json:"occupation"`
+	Education        string               `json:"education"`
+	ModerationStatus string               `json:"moderation_status"`
+	Approved         bool                 `json:"approved"`
+	PhotoKeys        []string             `json:"photo_keys"`
+	CircleKey        string               `json:"circle_key"`
+	PhotoURLs        []string             `json:"photo_urls"`
+	CircleURL        string               `json:"circle_url"`
+	PlusExpiresAt    *time.Time           `json:"plus_expires_at"`
+	BoostUntil       *time.Time           `json:"boost_until"`
+	SuperlikeCredits int                  `json:"superlike_credits"`
+	RevealCredits    int                  `json:"reveal_credits"`
+	LikeTokens       int                  `json:"like_tokens"`
+	IsBanned         bool                 `json:"is_banned"`
+	BanReason        string               `json:"ban_reason"`
+	BanReasonCode    string               `json:"ban_reason_code"`
+	BanExpiresAt     *time.Time           `json:"ban_expires_at"`
+	EvasionMatches   []model.EvasionMatch `json:"evasion_matches"`
 }
 
 func (dto lookupUserDTO) toModel() model.LookupUser {
@@ -210,5 +240,8 @@ func (dto lookupUserDTO) toModel() model.LookupUser {
 		LikeTokens:       dto.LikeTokens,
 		IsBanned:         dto.IsBanned,
 		BanReason:        strings.TrimSpace(dto.BanReason),
+		BanReasonCode:    strings.TrimSpace(dto.BanReasonCode),
+		BanExpiresAt:     dto.BanExpiresAt,
+		EvasionMatches:   dto.EvasionMatches,
 	}
 }
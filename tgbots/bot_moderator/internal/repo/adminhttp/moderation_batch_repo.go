@@ -0,0 +1,103 @@
+package adminhttp
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"time"
+
+	"bot_moderator/internal/domain/model"
+	"bot_moderator/internal/repo/postgres"
+)
+
+func (r *ModerationRepo) AcquirePendingBatch(ctx context.Context, actorTGID int64, limit int, lockDuration time.Duration) ([]model.ModerationItem, error) {
+	request := map[string]interface{}{
+		"actor_tg_id": actorTGID,
+		"limit":       limit,
+	}
+
+	response := moderationBatchAcquireResponseDTO{}
+	err := r.client.DoJSON(ctx, http.MethodPost, "/admin/bot/mod/queue/acquire-batch", request, &response)
+	if shouldFallbackModeration(r.dual, err) && r.db != nil {
+		return r.db.AcquirePendingBatch(ctx, actorTGID, limit, lockDuration)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	items := make([]model.ModerationItem, 0, len(response.Items))
+	for _, raw := range response.Items {
+		entry := raw.toCacheEntry()
+		if entry.Item.ID == 0 || entry.Item.UserID == 0 {
+			continue
+		}
+		if entry.Item.LockedAt == nil {
+			now := time.Now().UTC()
+			entry.Item.LockedAt = &now
+		}
+		if entry.Item.LockedByTGID == nil && actorTGID != 0 {
+			lockedBy := actorTGID
+			entry.Item.LockedByTGID = &lockedBy
+		}
+		if entry.Profile.UserID == 0 {
+			entry.Profile.UserID = entry.Item.UserID
+		}
+		r.putCache(entry)
+		items = append(items, entry.Item)
+	}
+	if len(items) == 0 {
+		return nil, postgres.ErrModerationQueueEmpty
+	}
+	return items, nil
+}
+
+func (r *ModerationRepo) DecideBatch(ctx context.Context, actorTGID int64, decisions []model.ModerationBatchDecision) ([]model.ModerationItem, error) {
+	payload := make([]map[string]interface{}, 0, len(decisions))
+	for _, decision := range decisions {
+		payload = append(payload, map[string]interface{}{
+			"moderation_item_id": decision.ModerationItemID,
+			"decision":           decision.Decision,
+			"reason_code":        strings.TrimSpace(decision.ReasonCode),
+			"reason_text":        strings.TrimSpace(decision.ReasonText),
+			"required_fix_step":  strings.TrimSpace(decision.RequiredFixStep),
+		})
+	}
+	request := map[string]interface{}{
+		"actor_tg_id": actorTGID,
+		"decisions":   payload,
+	}
+
+	response := moderationBatchDecideResponseDTO{}
+	err := r.client.DoJSON(ctx, http.MethodPost, "/admin/bot/mod/batch/decide", request, &response)
+	if shouldFallbackModeration(r.dual, err) && r.db != nil {
+		return r.db.DecideBatch(ctx, actorTGID, decisions)
+	}
+	if err != nil {
+		return nil, mapModerationDecisionError(err)
+	}
+
+	items := make([]model.ModerationItem, 0, len(response.Items))
+	for _, item := range response.Items {
+		r.dropCacheByItemID(item.ModerationItemID)
+		items = append(items, model.ModerationItem{
+			ID:       item.ModerationItemID,
+			UserID:   item.UserID,
+			Status:   model.ModerationStatus(strings.ToUpper(strings.TrimSpace(item.Status))),
+			LockedAt: item.ReviewStartedAt,
+		})
+	}
+	return items, nil
+}
+
+type moderationBatchAcquireResponseDTO struct {
+	Items []moderationAcquireResponseDTO `json:"items"`
+}
+
+type moderationBatchDecideResponseDTO struct {
+	Items []struct {
+		ModerationItemID int64      `json:"moderation_item_id"`
+		UserID           int64      `json:"user_id"`
+		Status           string     `json:"status"`
+		ReviewStartedAt  *time.Time `json:"review_started_at"`
+	} `json:"items"`
+}
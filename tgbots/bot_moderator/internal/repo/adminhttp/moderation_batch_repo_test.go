@@ -0,0 +1,78 @@
+package adminhttp
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"bot_moderator/internal/domain/model"
+	"bot_moderator/internal/repo/postgres"
+)
+
+func TestModerationRepoAcquireBatchCachesEveryItem(t *testing.T) {
+	t.Parallel()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/admin/bot/mod/queue/acquire-batch" {
+			t.Fatalf("unexpected path: %s", r.URL.Path)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"items": [
+			{"moderation_item": {"id": 11, "user_id": 501}, "profile": {"display_name": "A"}, "media": {"photo_urls": ["https://cdn.example.com/a.jpg"]}},
+			{"moderation_item": {"id": 12, "user_id": 502}, "profile": {"display_name": "B"}, "media": {"circle_url": "https://cdn.example.com/b.mp4"}}
+		]}`))
+	}))
+	defer server.Close()
+
+	client, err := NewClient(server.URL, "bot-token", 2*time.Second)
+	if err != nil {
+		t.Fatalf("new client: %v", err)
+	}
+	repo := NewModerationRepo(client, nil, false)
+	ctx := context.Background()
+
+	items, err := repo.AcquirePendingBatch(ctx, 700001, 5, 10*time.Minute)
+	if err != nil {
+		t.Fatalf("acquire batch: %v", err)
+	}
+	if len(items) != 2 || items[1].ID != 12 || items[0].LockedByTGID == nil || *items[0].LockedByTGID != 700001 {
+		t.Fatalf("unexpected batch: %+v", items)
+	}
+
+	profile, err := repo.GetProfile(ctx, 501)
+	if err != nil || profile.DisplayName != "A" {
+		t.Fatalf("expected cached profile, got %+v %v", profile, err)
+	}
+	circle, err := repo.GetLatestCircleKey(ctx, 502)
+	if err != nil || circle != "https://cdn.example.com/b.mp4" {
+		t.Fatalf("expected cached circle, got %q %v", circle, err)
+	}
+}
+
+func TestModerationRepoDecideBatchMapsAlreadyDecided(t *testing.T) {
+	t.Parallel()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusConflict)
+		_, _ = w.Write([]byte(`{"code":"ITEM_NOT_PENDING","message":"moderation item 12: moderation item is not pending"}`))
+	}))
+	defer server.Close()
+
+	client, err := NewClient(server.URL, "bot-token", 2*time.Second)
+	if err != nil {
+		t.Fatalf("new client: %v", err)
+	}
+	repo := NewModerationRepo(client, nil, false)
+
+	_, err = repo.DecideBatch(context.Background(), 700001, []model.ModerationBatchDecision{
+		{ModerationItemID: 11, Decision: model.ModerationBatchApprove},
+		{ModerationItemID: 12, Decision: model.ModerationBatchApprove},
+	})
+	if !errors.Is(err, postgres.ErrModerationItemNotPending) {
+		t.Fatalf("expected not pending error, got %v", err)
+	}
+}
@@ -17,6 +17,7 @@ type moderationCacheEntry struct {
 	Profile  model.ModerationProfile
 	PhotoRef []string
 	Circle   string
+	Matches  []model.PhotoMatch
 }
 
 type ModerationRepo struct {
@@ -130,6 +131,17 @@ func (r *ModerationRepo) GetLatestCircleKey(ctx context.Context, userID int64) (
 	return "", nil
 }
 
+func (r *ModerationRepo) ListPhotoMatches(ctx context.Context, userID int64) ([]model.PhotoMatch, error) {
+	if cached, ok := r.getCacheByUserID(userID); ok {
+		return append([]model.PhotoMatch{}, cached.Matches...), nil
+	}
+
+	if r.dual && r.db != nil {
+		return r.db.ListPhotoMatches(ctx, userID)
+	}
+	return []model.PhotoMatch{}, nil
+}
+
 func (r *ModerationRepo) GetByID(ctx context.Context, moderationItemID int64) (model.ModerationItem, error) {
 	if cached, ok := r.getCacheByItemID(moderationItemID); ok {
 		return cached.Item, nil
@@ -161,40 +173,39 @@ func (r *ModerationRepo) GetByID(ctx context.Context, moderationItemID int64) (m
 	return entry.Item, nil
 }
 
-func (r *ModerationRepo) MarkApproved(ctx context.Context, moderationItemID int64) error {
+func (r *ModerationRepo) MarkApproved(ctx context.Context, moderationItemID int64, actorTGID int64, verified bool) error {
 	request := map[string]interface{}{
 		"moderation_item_id": moderationItemID,
 	}
-	if actorTGID := ActorTGIDFromContext(ctx); actorTGID != 0 {
+	if actorTGID != 0 {
 		request["actor_tg_id"] = actorTGID
 	}
 
-	err := r.client.DoJSON(
-		ctx,
-		http.MethodPost,
-		"/admin/bot/mod/items/"+int64ToString(moderationItemID)+"/approve",
-		request,
-		nil,
-	)
+	path := "/admin/bot/mod/items/" + int64ToString(moderationItemID) + "/approve"
+	if verified {
+		path += "-verified"
+	}
+
+	err := r.client.DoJSON(ctx, http.MethodPost, path, request, nil)
 	if shouldFallbackModeration(r.dual, err) && r.db != nil {
-		return r.db.MarkApproved(ctx, moderationItemID)
+		return r.db.MarkApproved(ctx, moderationItemID, actorTGID, verified)
 	}
 	if err != nil {
-		return err
+		return mapModerationDecisionError(err)
 	}
 
 	r.dropCacheByItemID(moderationItemID)
 	return nil
 }
 
-func (r *ModerationRepo) MarkRejected(ctx context.Context, moderationItemID int64, reasonCode string, reasonText string, requiredFixStep string) error {
+func (r *ModerationRepo) MarkRejected(ctx context.Context, moderationItemID int64, actorTGID int64, reasonCode string, reasonText string, requiredFixStep string) error {
 	request := map[string]interface{}{
 		"moderation_item_id": moderationItemID,
 		"reason_code":        strings.TrimSpace(reasonCode),
 		"reason_text":        strings.TrimSpace(reasonText),
 		"required_fix_step":  strings.TrimSpace(requiredFixStep),
 	}
-	if actorTGID := ActorTGIDFromContext(ctx); actorTGID != 0 {
+	if actorTGID != 0 {
 		request["actor_tg_id"] = actorTGID
 	}
 
@@ -206,10 +217,10 @@ func (r *ModerationRepo) MarkRejected(ctx context.Context, moderationItemID int6
 		nil,
 	)
 	if shouldFallbackModeration(r.dual, err) && r.db != nil {
-		return r.db.MarkRejected(ctx, moderationItemID, reasonCode, reasonText, requiredFixStep)
+		return r.db.MarkRejected(ctx, moderationItemID, actorTGID, reasonCode, reasonText, requiredFixStep)
 	}
 	if err != nil {
-		return err
+		return mapModerationDecisionError(err)
 	}
 
 	r.dropCacheByItemID(moderationItemID)
@@ -247,6 +258,7 @@ func (r *ModerationRepo) putCache(entry moderationCacheEntry) {
 		Profile:  entry.Profile,
 		PhotoRef: cloneStrings(entry.PhotoRef),
 		Circle:   entry.Circle,
+		Matches:  append([]model.PhotoMatch{}, entry.Matches...),
 	}
 	r.lastItemByUser[entry.Item.UserID] = entry.Item.ID
 }
@@ -359,6 +371,7 @@ func (dto moderationAcquireResponseDTO) toCacheEntry() moderationCacheEntry {
 		Profile:  profile,
 		PhotoRef: media.pickPhotoRefs(),
 		Circle:   media.pickCircleRef(),
+		Matches:  media.toPhotoMatches(),
 	}
 }
 
@@ -431,6 +444,8 @@ type moderationProfileDTO struct {
 	Languages   []string   `json:"languages"`
 	Occupation  string     `json:"occupation"`
 	Education   string     `json:"education"`
+
+	VerificationStatus string `json:"verification_status"`
 }
 
 func (dto moderationProfileDTO) toModel() model.ModerationProfile {
@@ -448,18 +463,49 @@ func (dto moderationProfileDTO) toModel() model.ModerationProfile {
 		Languages:   cloneStrings(dto.Languages),
 		Occupation:  strings.TrimSpace(dto.Occupation),
 		Education:   strings.TrimSpace(dto.Education),
+
+		VerificationStatus: strings.ToUpper(strings.TrimSpace(dto.VerificationStatus)),
 	}
 }
 
 type moderationMediaDTO struct {
-	Photos             []moderationMediaRefDTO `json:"photos"`
-	PhotoKeys          []string                `json:"photo_keys"`
-	PhotoURLs          []string                `json:"photo_urls"`
-	PresignedPhotoURLs []string                `json:"presigned_photo_urls"`
-	Circle             moderationMediaRefDTO   `json:"circle"`
-	CircleKey          string                  `json:"circle_key"`
-	CircleURL          string                  `json:"circle_url"`
-	PresignedCircleURL string                  `json:"presigned_circle_url"`
+	Photos             []moderationMediaRefDTO   `json:"photos"`
+	PhotoKeys          []string                  `json:"photo_keys"`
+	PhotoURLs          []string                  `json:"photo_urls"`
+	PresignedPhotoURLs []string                  `json:"presigned_photo_urls"`
+	Circle             moderationMediaRefDTO     `json:"circle"`
+	CircleKey          string                    `json:"circle_key"`
+	CircleURL          string                    `json:"circle_url"`
+	PresignedCircleURL string                    `json:"presigned_circle_url"`
+	Matches            []moderationPhotoMatchDTO `json:"matches"`
+}
+
+type moderationPhotoMatchDTO struct {
+	MediaID        int64  `json:"media_id"`
+	Slot           int    `json:"slot"`
+	Source         string `json:"source"`
+	MatchedUserID  int64  `json:"matched_user_id"`
+	MatchedMediaID int64  `json:"matched_media_id"`
+	BannedImageID  int64  `json:"banned_image_id"`
+	Label          string `json:"label"`
+	Distance       int    `json:"distance"`
+}
+
+func (dto moderationMediaDTO) toPhotoMatches() []model.PhotoMatch {
+	matches := make([]model.PhotoMatch, 0, len(dto.Matches))
+	for _, match := range dto.Matches {
+		matches = append(matches, model.PhotoMatch{
+			MediaID:        match.MediaID,
+			Slot:           match.Slot,
+			Source:         strings.ToUpper(strings.TrimSpace(match.Source)),
+			MatchedUserID:  match.MatchedUserID,
+			MatchedMediaID: match.MatchedMediaID,
+			BannedImageID:  match.BannedImageID,
+			Label:          strings.TrimSpace(match.Label),
+			Distance:       match.Distance,
+		})
+	}
+	return matches
 }
 
 type moderationMediaRefDTO struct {
@@ -531,3 +577,21 @@ func firstNonEmpty(values ...string) string {
 	}
 	return ""
 }
+
+// mapModerationDecisionError lets a concurrent duplicate decision surface as the same error in http and db modes.
+func mapModerationDecisionError(err error) error {
+	var reqErr *RequestError
+	if !errors.As(err, &reqErr) {
+		return err
+	}
+	switch {
+	case reqErr.StatusCode == http.StatusNotFound:
+		return postgres.ErrModerationItemNotFound
+	case reqErr.StatusCode == http.StatusConflict && reqErr.Err != nil && strings.Contains(reqErr.Err.Error(), "ITEM_NOT_PENDING"):
+		return postgres.ErrModerationItemNotPending
+	case reqErr.StatusCode == http.StatusConflict && reqErr.Err != nil && strings.Contains(reqErr.Err.Error(), "ITEM_LOCKED"):
+		return postgres.ErrModerationItemLocked
+	default:
+		return err
+	}
+}
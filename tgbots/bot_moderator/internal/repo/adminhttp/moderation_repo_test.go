@@ -2,11 +2,14 @@ package adminhttp
 
 import (
 	"context"
+	"errors"
 	"net/http"
 	"net/http/httptest"
 	"strings"
 	"testing"
 	"time"
+
+	"bot_moderator/internal/repo/postgres"
 )
 
 func TestModerationRepoAcquireCachesProfileAndMedia(t *testing.T) {
@@ -54,7 +57,10 @@ func TestModerationRepoAcquireCachesProfileAndMedia(t *testing.T) {
 					{"url": "https://cdn.example.com/photo2.jpg"},
 					{"presigned_url": "https://signed.example.com/photo3.jpg"}
 				],
-				"circle": {"presigned_url": "https://signed.example.com/circle.mp4"}
+				"circle": {"presigned_url": "https://signed.example.com/circle.mp4"},
+				"matches": [
+					{"media_id": 7001, "slot": 2, "source": "USER", "matched_user_id": 777, "matched_media_id": 8001, "distance": 3}
+				]
 			}
 		}`))
 	}))
@@ -108,6 +114,90 @@ func TestModerationRepoAcquireCachesProfileAndMedia(t *testing.T) {
 	if circle != "https://signed.example.com/circle.mp4" {
 		t.Fatalf("unexpected circle ref: %q", circle)
 	}
+
+	matches, err := repo.ListPhotoMatches(ctx, item.UserID)
+	if err != nil {
+		t.Fatalf("list photo matches: %v", err)
+	}
+	if len(matches) != 1 || matches[0].Slot != 2 || matches[0].MatchedUserID != 777 || matches[0].Source != "USER" {
+		t.Fatalf("unexpected photo matches: %+v", matches)
+	}
+}
+
+func TestModerationRepoMarkApprovedVerifiedUsesVerifiedEndpoint(t *testing.T) {
+	t.Parallel()
+
+	paths := make(chan string, 2)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		paths <- r.URL.Path
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"ok":true}`))
+	}))
+	defer server.Close()
+
+	client, err := NewClient(server.URL, "bot-token", 2*time.Second)
+	if err != nil {
+		t.Fatalf("new client: %v", err)
+	}
+	repo := NewModerationRepo(client, nil, false)
+
+	if err := repo.MarkApproved(context.Background(), 91, 700001, false); err != nil {
+		t.Fatalf("mark approved: %v", err)
+	}
+	if err := repo.MarkApproved(context.Background(), 92, 700001, true); err != nil {
+		t.Fatalf("mark approved verified: %v", err)
+	}
+
+	if got := <-paths; got != "/admin/bot/mod/items/91/approve" {
+		t.Fatalf("unexpected approve path: %s", got)
+	}
+	if got := <-paths; got != "/admin/bot/mod/items/92/approve-verified" {
+		t.Fatalf("unexpected verified approve path: %s", got)
+	}
+}
+
+func TestModerationRepoMarkRejectedMapsAlreadyDecided(t *testing.T) {
+	t.Parallel()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusConflict)
+		_, _ = w.Write([]byte(`{"code":"ITEM_NOT_PENDING","message":"moderation item is already decided"}`))
+	}))
+	defer server.Close()
+
+	client, err := NewClient(server.URL, "bot-token", 2*time.Second)
+	if err != nil {
+		t.Fatalf("new client: %v", err)
+	}
+	repo := NewModerationRepo(client, nil, false)
+
+	err = repo.MarkRejected(context.Background(), 91, 700001, "OTHER", "text", "fix")
+	if !errors.Is(err, postgres.ErrModerationItemNotPending) {
+		t.Fatalf("expected not pending error, got %v", err)
+	}
+}
+
+func TestModerationRepoMarkApprovedMapsLocked(t *testing.T) {
+	t.Parallel()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusConflict)
+		_, _ = w.Write([]byte(`{"code":"ITEM_LOCKED","message":"moderation item is locked by another moderator"}`))
+	}))
+	defer server.Close()
+
+	client, err := NewClient(server.URL, "bot-token", 2*time.Second)
+	if err != nil {
+		t.Fatalf("new client: %v", err)
+	}
+	repo := NewModerationRepo(client, nil, false)
+
+	err = repo.MarkApproved(context.Background(), 91, 700001, false)
+	if !errors.Is(err, postgres.ErrModerationItemLocked) {
+		t.Fatalf("expected locked error, got %v", err)
+	}
 }
 
 func TestShouldFallbackModeration(t *testing.T) {
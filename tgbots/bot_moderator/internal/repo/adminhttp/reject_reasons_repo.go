@@ -0,0 +1,71 @@
+package adminhttp
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"bot_moderator/internal/domain/model"
+	"bot_moderator/internal/repo/postgres"
+)
+
+type RejectReasonsRepo struct {
+	client *Client
+	db     *postgres.RejectReasonsRepo
+	dual   bool
+}
+
+func NewRejectReasonsRepo(client *Client, db *postgres.RejectReasonsRepo, dual bool) *RejectReasonsRepo {
+	return &RejectReasonsRepo{
+		client: client,
+		db:     db,
+		dual:   dual,
+	}
+}
+
+func (r *RejectReasonsRepo) ListRejectReasons(ctx context.Context, includeInactive bool) ([]model.RejectReason, error) {
+	path := "/admin/bot/mod/reject-reasons"
+	if includeInactive {
+		path += "?include_inactive=true"
+	}
+
+	response := struct {
+		Items []model.RejectReason `json:"items"`
+	}{}
+	err := r.client.DoJSON(ctx, http.MethodGet, path, nil, &response)
+	if shouldFallback(r.dual, err) && r.db != nil {
+		return r.db.ListRejectReasons(ctx, includeInactive)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return response.Items, nil
+}
+
+func (r *RejectReasonsRepo) UpsertRejectReason(ctx context.Context, reason model.RejectReason) (model.RejectReason, error) {
+	isActive := reason.IsActive
+	request := map[string]interface{}{
+		"label":             strings.TrimSpace(reason.Label),
+		"labels":            reason.Labels,
+		"reason_text":       strings.TrimSpace(reason.ReasonText),
+		"required_fix_step": strings.TrimSpace(reason.RequiredFixStep),
+		"severity":          strings.TrimSpace(reason.Severity),
+		"is_active":         &isActive,
+		"sort_order":        reason.SortOrder,
+	}
+	if reason.UpdatedByTGID != nil {
+		request["actor_tg_id"] = *reason.UpdatedByTGID
+	}
+
+	response := model.RejectReason{}
+	path := "/admin/bot/mod/reject-reasons/" + url.PathEscape(strings.ToUpper(strings.TrimSpace(reason.Code)))
+	err := r.client.DoJSON(ctx, http.MethodPut, path, request, &response)
+	if shouldFallback(r.dual, err) && r.db != nil {
+		return r.db.UpsertRejectReason(ctx, reason)
+	}
+	if err != nil {
+		return model.RejectReason{}, err
+	}
+	return response, nil
+}
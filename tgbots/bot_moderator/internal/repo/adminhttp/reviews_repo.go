@@ -0,0 +1,193 @@
+package adminhttp
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strings"
+	"time"
+
+	"bot_moderator/internal/domain/model"
+	"bot_moderator/internal/repo/postgres"
+)
+
+type ReviewsRepo struct {
+	client *Client
+	db     *postgres.ReviewsRepo
+	dual   bool
+}
+
+func NewReviewsRepo(client *Client, db *postgres.ReviewsRepo, dual bool) *ReviewsRepo {
+	return &ReviewsRepo{
+		client: client,
+		db:     db,
+		dual:   dual,
+	}
+}
+
+func (r *ReviewsRepo) AcquireNextReview(ctx context.Context, kind string, reviewerTGID int64, lockDuration time.Duration) (model.ModerationReviewItem, error) {
+	request := map[string]interface{}{
+		"kind": strings.ToUpper(strings.TrimSpace(kind)),
+	}
+
+	response := reviewAcquireResponseDTO{}
+	err := r.client.DoJSON(ctx, http.MethodPost, "/admin/bot/mod/reviews/acquire", request, &response)
+	if shouldFallbackWithNotFound(r.dual, err) && r.db != nil {
+		return r.db.AcquireNextReview(ctx, kind, reviewerTGID, lockDuration)
+	}
+	if err != nil {
+		return model.ModerationReviewItem{}, err
+	}
+	// 204 No Content leaves the response empty.
+	if response.Review.ID == 0 {
+		return model.ModerationReviewItem{}, postgres.ErrReviewQueueEmpty
+	}
+
+	profile := response.Profile.toModel()
+	if profile.UserID == 0 {
+		profile.UserID = response.Review.UserID
+	}
+	circle := ""
+	if response.Media.Circle != nil {
+		circle = strings.TrimSpace(*response.Media.Circle)
+	}
+
+	return model.ModerationReviewItem{
+		Review:    response.Review.toModel(),
+		Profile:   profile,
+		PhotoKeys: cloneStrings(response.Media.Photos),
+		CircleKey: circle,
+	}, nil
+}
+
+func (r *ReviewsRepo) GetReview(ctx context.Context, reviewID int64) (model.ModerationReview, error) {
+	response := reviewItemDTO{}
+	err := r.client.DoJSON(ctx, http.MethodGet, "/admin/bot/mod/reviews/"+int64ToString(reviewID), nil, &response)
+	if shouldFallbackWithNotFound(r.dual, err) && r.db != nil {
+		return r.db.GetReview(ctx, reviewID)
+	}
+	if err != nil {
+		var reqErr *RequestError
+		if errors.As(err, &reqErr) && reqErr.StatusCode == http.StatusNotFound {
+			return model.ModerationReview{}, postgres.ErrReviewNotFound
+		}
+		return model.ModerationReview{}, err
+	}
+	return response.toModel(), nil
+}
+
+func (r *ReviewsRepo) ResolveReview(ctx context.Context, decision model.ModerationReviewDecision) (model.ModerationReview, error) {
+	request := map[string]interface{}{
+		"outcome":     decision.Outcome,
+		"reason_code": strings.TrimSpace(decision.ReasonCode),
+		"note":        strings.TrimSpace(decision.Note),
+	}
+
+	response := struct {
+		ID               int64  `json:"id"`
+		ModerationItemID int64  `json:"moderation_item_id"`
+		UserID           int64  `json:"user_id"`
+		Kind             string `json:"kind"`
+		Status           string `json:"status"`
+	}{}
+	err := r.client.DoJSON(
+		ctx,
+		http.MethodPost,
+		"/admin/bot/mod/reviews/"+int64ToString(decision.ReviewID)+"/resolve",
+		request,
+		&response,
+	)
+	if shouldFallback(r.dual, err) && r.db != nil {
+		return r.db.ResolveReview(ctx, decision)
+	}
+	if err != nil {
+		var reqErr *RequestError
+		if errors.As(err, &reqErr) {
+			switch reqErr.StatusCode {
+			case http.StatusForbidden:
+				return model.ModerationReview{}, postgres.ErrReviewSameModerator
+			case http.StatusNotFound:
+				return model.ModerationReview{}, postgres.ErrReviewNotFound
+			case http.StatusConflict:
+				if reqErr.Err != nil && strings.Contains(reqErr.Err.Error(), "REVIEW_NOT_LOCKED") {
+					return model.ModerationReview{}, postgres.ErrReviewNotLocked
+				}
+				return model.ModerationReview{}, postgres.ErrReviewNotPending
+			}
+		}
+		return model.ModerationReview{}, err
+	}
+
+	return model.ModerationReview{
+		ID:               response.ID,
+		ModerationItemID: response.ModerationItemID,
+		UserID:           response.UserID,
+		Kind:             strings.ToUpper(strings.TrimSpace(response.Kind)),
+		Status:           strings.ToUpper(strings.TrimSpace(response.Status)),
+	}, nil
+}
+
+func (r *ReviewsRepo) ReviewStats(ctx context.Context, window time.Duration) (model.ModerationReviewStats, error) {
+	hours := int(window / time.Hour)
+	if hours <= 0 {
+		hours = 24
+	}
+
+	response := model.ModerationReviewStats{}
+	err := r.client.DoJSON(ctx, http.MethodGet, "/admin/bot/mod/reviews/stats?hours="+intToString(hours), nil, &response)
+	if shouldFallbackWithNotFound(r.dual, err) && r.db != nil {
+		return r.db.ReviewStats(ctx, window)
+	}
+	if err != nil {
+		return model.ModerationReviewStats{}, err
+	}
+	return response, nil
+}
+
+type reviewAcquireResponseDTO struct {
+	Review  reviewItemDTO        `json:"review"`
+	Profile moderationProfileDTO `json:"profile"`
+	Media   struct {
+		Photos []string `json:"photos"`
+		Circle *string  `json:"circle"`
+	} `json:"media"`
+}
+
+type reviewItemDTO struct {
+	ID                    int64      `json:"id"`
+	ModerationItemID      int64      `json:"moderation_item_id"`
+	UserID                int64      `json:"user_id"`
+	Kind                  string     `json:"kind"`
+	Status                string     `json:"status"`
+	OriginalDecision      string     `json:"original_decision"`
+	OriginalReasonCode    *string    `json:"original_reason_code"`
+	OriginalModeratorTGID *int64     `json:"original_moderator_tg_id"`
+	ReasonText            *string    `json:"reason_text"`
+	AppealMessage         *string    `json:"appeal_message"`
+	LockedAt              *time.Time `json:"locked_at"`
+	CreatedAt             time.Time  `json:"created_at"`
+}
+
+func (dto reviewItemDTO) toModel() model.ModerationReview {
+	return model.ModerationReview{
+		ID:                    dto.ID,
+		ModerationItemID:      dto.ModerationItemID,
+		UserID:                dto.UserID,
+		Kind:                  strings.ToUpper(strings.TrimSpace(dto.Kind)),
+		Status:                strings.ToUpper(strings.TrimSpace(dto.Status)),
+		OriginalDecision:      strings.ToUpper(strings.TrimSpace(dto.OriginalDecision)),
+		OriginalReasonCode:    derefString(dto.OriginalReasonCode),
+		OriginalModeratorTGID: dto.OriginalModeratorTGID,
+		ReasonText:            derefString(dto.ReasonText),
+		AppealMessage:         derefString(dto.AppealMessage),
+		LockedAt:              dto.LockedAt,
+		CreatedAt:             dto.CreatedAt,
+	}
+}
+
+func derefString(value *string) string {
+	if value == nil {
+		return ""
+	}
+	return strings.TrimSpace(*value)
+}
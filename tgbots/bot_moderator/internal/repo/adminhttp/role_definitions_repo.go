@@ -0,0 +1,54 @@
+package adminhttp
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+
+	"bot_moderator/internal/domain/model"
+	"bot_moderator/internal/repo/postgres"
+)
+
+type RoleDefinitionsRepo struct {
+	client *Client
+	db     *postgres.RoleDefinitionsRepo
+	dual   bool
+}
+
+func NewRoleDefinitionsRepo(client *Client, db *postgres.RoleDefinitionsRepo, dual bool) *RoleDefinitionsRepo {
+	return &RoleDefinitionsRepo{
+		client: client,
+		db:     db,
+		dual:   dual,
+	}
+}
+
+func (r *RoleDefinitionsRepo) ListRoleDefinitions(ctx context.Context) ([]model.RoleDefinition, error) {
+	response := struct {
+		Items []model.RoleDefinition `json:"items"`
+	}{}
+	err := r.client.DoJSON(ctx, http.MethodGet, "/admin/bot/access/roles", nil, &response)
+	if shouldFallbackWithNotFound(r.dual, err) && r.db != nil {
+		return r.db.ListRoleDefinitions(ctx)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return response.Items, nil
+}
+
+func (r *RoleDefinitionsRepo) UpsertRoleDefinition(ctx context.Context, def model.RoleDefinition) (model.RoleDefinition, bool, error) {
+	request := map[string]interface{}{
+		"permissions": def.Permissions,
+	}
+
+	response := model.RoleDefinition{}
+	err := r.client.DoJSON(ctx, http.MethodPut, "/admin/bot/access/roles/"+url.PathEscape(string(def.Name)), request, &response)
+	if shouldFallbackWithNotFound(r.dual, err) && r.db != nil {
+		return r.db.UpsertRoleDefinition(ctx, def)
+	}
+	if err != nil {
+		return model.RoleDefinition{}, false, err
+	}
+	return response, true, nil
+}
@@ -2,6 +2,7 @@ package adminhttp
 
 import (
 	"context"
+	"time"
 
 	"bot_moderator/internal/domain/model"
 	"bot_moderator/internal/repo/postgres"
@@ -42,3 +43,28 @@ func (r *WorkStatsRepo) Aggregate(ctx context.Context, bounds statssvc.PeriodBou
 	}
 	return response, nil
 }
+
+// BatchThroughput has no backend endpoint: batch actions live in the bot-owned tables.
+func (r *WorkStatsRepo) BatchThroughput(ctx context.Context, window time.Duration) (model.ModerationBatchStats, error) {
+	if r.dual && r.db != nil {
+		return r.db.BatchThroughput(ctx, window)
+	}
+	return model.ModerationBatchStats{WindowHours: int(window / time.Hour), Moderators: []model.ModerationBatchModerator{}}, nil
+}
+
+func (r *WorkStatsRepo) SLA(ctx context.Context, window time.Duration) (model.ModerationSLAReport, error) {
+	hours := int(window / time.Hour)
+	if hours <= 0 {
+		hours = 24
+	}
+
+	response := model.ModerationSLAReport{}
+	err := r.client.DoJSON(ctx, "GET", "/admin/bot/mod/sla?hours="+intToString(hours), nil, &response)
+	if shouldFallback(r.dual, err) && r.db != nil {
+		return r.db.SLA(ctx, window)
+	}
+	if err != nil {
+		return model.ModerationSLAReport{}, err
+	}
+	return response, nil
+}
@@ -0,0 +1,47 @@
+package adminhttp
+
+import (
+	"context"
+	"net/http"
+
+	"bot_moderator/internal/domain/model"
+	"bot_moderator/internal/repo/postgres"
+)
+
+type WaitlistRepo struct {
+	client *Client
+	db     *postgres.WaitlistRepo
+	dual   bool
+}
+
+func NewWaitlistRepo(client *Client, db *postgres.WaitlistRepo, dual bool) *WaitlistRepo {
+	return &WaitlistRepo{
+		client: client,
+		db:     db,
+		dual:   dual,
+	}
+}
+
+func (r *WaitlistRepo) WaitlistStats(ctx context.Context) (model.WaitlistStats, error) {
+	response := model.WaitlistStats{}
+	err := r.client.DoJSON(ctx, http.MethodGet, "/admin/bot/waitlist", nil, &response)
+	if shouldFallbackWithNotFound(r.dual, err) && r.db != nil {
+		return r.db.WaitlistStats(ctx)
+	}
+	if err != nil {
+		return model.WaitlistStats{}, err
+	}
+	return response, nil
+}
+
+func (r *WaitlistRepo) AdmitWaitlist(ctx context.Context, req model.WaitlistAdmitRequest, actorTGID int64) (model.WaitlistAdmitResult, error) {
+	response := model.WaitlistAdmitResult{}
+	err := r.client.DoJSON(ctx, http.MethodPost, "/admin/bot/waitlist/admit", req, &response)
+	if shouldFallbackWithNotFound(r.dual, err) && r.db != nil {
+		return r.db.AdmitWaitlist(ctx, req, actorTGID)
+	}
+	if err != nil {
+		return model.WaitlistAdmitResult{}, err
+	}
+	return response, nil
+}
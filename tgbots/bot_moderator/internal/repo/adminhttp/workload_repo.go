@@ -0,0 +1,130 @@
+package adminhttp
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+
+	"bot_moderator/internal/domain/model"
+	"bot_moderator/internal/repo/postgres"
+)
+
+type WorkloadRepo struct {
+	client *Client
+	db     *postgres.WorkloadRepo
+	dual   bool
+}
+
+func NewWorkloadRepo(client *Client, db *postgres.WorkloadRepo, dual bool) *WorkloadRepo {
+	return &WorkloadRepo{
+		client: client,
+		db:     db,
+		dual:   dual,
+	}
+}
+
+func (r *WorkloadRepo) Workload(ctx context.Context) (model.ModerationWorkload, error) {
+	response := model.ModerationWorkload{}
+	err := r.client.DoJSON(ctx, http.MethodGet, "/admin/bot/mod/workload", nil, &response)
+	if shouldFallbackWithNotFound(r.dual, err) && r.db != nil {
+		return r.db.Workload(ctx)
+	}
+	if err != nil {
+		return model.ModerationWorkload{}, err
+	}
+	if response.Moderators == nil {
+		response.Moderators = []model.ModeratorWorkload{}
+	}
+	return response, nil
+}
+
+func (r *WorkloadRepo) SaveModerator(ctx context.Context, in model.ModeratorWorkload) (model.ModeratorWorkload, error) {
+	request := map[string]interface{}{
+		"max_active_items": in.MaxActiveItems,
+		"skills":           in.Skills,
+	}
+
+	response := model.ModeratorWorkload{}
+	err := r.client.DoJSON(ctx, http.MethodPut, "/admin/bot/mod/workload/"+strconv.FormatInt(in.ModeratorTGID, 10), request, &response)
+	if shouldFallbackWithNotFound(r.dual, err) && r.db != nil {
+		return r.db.SaveModerator(ctx, in)
+	}
+	if err != nil {
+		return model.ModeratorWorkload{}, err
+	}
+	return response, nil
+}
+
+func (r *WorkloadRepo) AddShift(ctx context.Context, in model.ModeratorShift) (model.ModeratorShift, error) {
+	request := map[string]interface{}{
+		"weekday":   in.Weekday,
+		"starts_at": in.StartsAt,
+		"ends_at":   in.EndsAt,
+	}
+
+	response := model.ModeratorShift{}
+	err := r.client.DoJSON(ctx, http.MethodPost, "/admin/bot/mod/workload/"+strconv.FormatInt(in.ModeratorTGID, 10)+"/shifts", request, &response)
+	if shouldFallbackWithNotFound(r.dual, err) && r.db != nil {
+		return r.db.AddShift(ctx, in)
+	}
+	if err != nil {
+		return model.ModeratorShift{}, err
+	}
+	return response, nil
+}
+
+func (r *WorkloadRepo) DeleteShift(ctx context.Context, shiftID int64) (model.ModeratorShift, error) {
+	response := model.ModeratorShift{}
+	err := r.client.DoJSON(ctx, http.MethodDelete, "/admin/bot/mod/shifts/"+strconv.FormatInt(shiftID, 10), nil, &response)
+	if shouldFallbackWithNotFound(r.dual, err) && r.db != nil {
+		return r.db.DeleteShift(ctx, shiftID)
+	}
+	var reqErr *RequestError
+	if errors.As(err, &reqErr) && reqErr.StatusCode == http.StatusNotFound {
+		return model.ModeratorShift{}, postgres.ErrModeratorShiftNotFound
+	}
+	if err != nil {
+		return model.ModeratorShift{}, err
+	}
+	return response, nil
+}
+
+// ReassignIdleLocks leaves the lock TTL to the backend, which applies its own queue lock.
+func (r *WorkloadRepo) ReassignIdleLocks(ctx context.Context, idle, lockDuration time.Duration) ([]model.LockReassignment, error) {
+	request := map[string]interface{}{
+		"idle_sec": int64(idle / time.Second),
+	}
+
+	response := struct {
+		Items []model.LockReassignment `json:"items"`
+	}{}
+	err := r.client.DoJSON(ctx, http.MethodPost, "/admin/bot/mod/workload/reassign-idle", request, &response)
+	if shouldFallbackWithNotFound(r.dual, err) && r.db != nil {
+		return r.db.ReassignIdleLocks(ctx, idle, lockDuration)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return response.Items, nil
+}
+
+func (r *WorkloadRepo) ShiftThroughput(ctx context.Context, from, to time.Time) ([]model.ShiftThroughput, error) {
+	values := url.Values{}
+	values.Set("from", from.UTC().Format(time.RFC3339))
+	values.Set("to", to.UTC().Format(time.RFC3339))
+
+	response := struct {
+		Items []model.ShiftThroughput `json:"items"`
+	}{}
+	err := r.client.DoJSON(ctx, http.MethodGet, "/admin/bot/mod/shifts/throughput?"+values.Encode(), nil, &response)
+	if shouldFallbackWithNotFound(r.dual, err) && r.db != nil {
+		return r.db.ShiftThroughput(ctx, from, to)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return response.Items, nil
+}
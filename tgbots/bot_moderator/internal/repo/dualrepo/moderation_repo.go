@@ -22,10 +22,13 @@ type ModerationRepo interface {
 	GetProfile(context.Context, int64) (model.ModerationProfile, error)
 	ListPhotoKeys(context.Context, int64, int) ([]string, error)
 	GetLatestCircleKey(context.Context, int64) (string, error)
+	ListPhotoMatches(context.Context, int64) ([]model.PhotoMatch, error)
 	GetByID(context.Context, int64) (model.ModerationItem, error)
-	MarkApproved(context.Context, int64) error
-	MarkRejected(context.Context, int64, string, string, string) error
+	MarkApproved(context.Context, int64, int64, bool) error
+	MarkRejected(context.Context, int64, int64, string, string, string) error
 	InsertModerationAction(context.Context, model.BotModerationAction) error
+	AcquirePendingBatch(context.Context, int64, int, time.Duration) ([]model.ModerationItem, error)
+	DecideBatch(context.Context, int64, []model.ModerationBatchDecision) ([]model.ModerationItem, error)
 }
 
 type DualRepo struct {
@@ -96,6 +99,18 @@ func (r *DualRepo) GetLatestCircleKey(ctx context.Context, userID int64) (string
 	)
 }
 
+func (r *DualRepo) ListPhotoMatches(ctx context.Context, userID int64) ([]model.PhotoMatch, error) {
+	return callWithFallback(
+		r,
+		func(repo ModerationRepo) ([]model.PhotoMatch, error) {
+			return repo.ListPhotoMatches(ctx, userID)
+		},
+		func(repo ModerationRepo) ([]model.PhotoMatch, error) {
+			return repo.ListPhotoMatches(ctx, userID)
+		},
+	)
+}
+
 func (r *DualRepo) GetByID(ctx context.Context, moderationItemID int64) (model.ModerationItem, error) {
 	return callWithFallback(
 		r,
@@ -108,26 +123,26 @@ func (r *DualRepo) GetByID(ctx context.Context, moderationItemID int64) (model.M
 	)
 }
 
-func (r *DualRepo) MarkApproved(ctx context.Context, moderationItemID int64) error {
+func (r *DualRepo) MarkApproved(ctx context.Context, moderationItemID int64, actorTGID int64, verified bool) error {
 	return callWithFallbackErr(
 		r,
 		func(repo ModerationRepo) error {
-			return repo.MarkApproved(ctx, moderationItemID)
+			return repo.MarkApproved(ctx, moderationItemID, actorTGID, verified)
 		},
 		func(repo ModerationRepo) error {
-			return repo.MarkApproved(ctx, moderationItemID)
+			return repo.MarkApproved(ctx, moderationItemID, actorTGID, verified)
 		},
 	)
 }
 
-func (r *DualRepo) MarkRejected(ctx context.Context, moderationItemID int64, reasonCode string, reasonText string, requiredFixStep string) error {
+func (r *DualRepo) MarkRejected(ctx context.Context, moderationItemID int64, actorTGID int64, reasonCode string, reasonText string, requiredFixStep string) error {
 	return callWithFallbackErr(
 		r,
 		func(repo ModerationRepo) error {
-			return repo.MarkRejected(ctx, moderationItemID, reasonCode, reasonText, requiredFixStep)
+			return repo.MarkRejected(ctx, moderationItemID, actorTGID, reasonCode, reasonText, requiredFixStep)
 		},
 		func(repo ModerationRepo) error {
-			return repo.MarkRejected(ctx, moderationItemID, reasonCode, reasonText, requiredFixStep)
+			return repo.MarkRejected(ctx, moderationItemID, actorTGID, reasonCode, reasonText, requiredFixStep)
 		},
 	)
 }
@@ -144,6 +159,30 @@ func (r *DualRepo) InsertModerationAction(ctx context.Context, action model.BotM
 	)
 }
 
+func (r *DualRepo) AcquirePendingBatch(ctx context.Context, actorTGID int64, limit int, lockDuration time.Duration) ([]model.ModerationItem, error) {
+	return callWithFallback(
+		r,
+		func(repo ModerationRepo) ([]model.ModerationItem, error) {
+			return repo.AcquirePendingBatch(ctx, actorTGID, limit, lockDuration)
+		},
+		func(repo ModerationRepo) ([]model.ModerationItem, error) {
+			return repo.AcquirePendingBatch(ctx, actorTGID, limit, lockDuration)
+		},
+	)
+}
+
+func (r *DualRepo) DecideBatch(ctx context.Context, actorTGID int64, decisions []model.ModerationBatchDecision) ([]model.ModerationItem, error) {
+	return callWithFallback(
+		r,
+		func(repo ModerationRepo) ([]model.ModerationItem, error) {
+			return repo.DecideBatch(ctx, actorTGID, decisions)
+		},
+		func(repo ModerationRepo) ([]model.ModerationItem, error) {
+			return repo.DecideBatch(ctx, actorTGID, decisions)
+		},
+	)
+}
+
 func callWithFallback[T any](
 	dualRepo *DualRepo,
 	httpCall func(ModerationRepo) (T, error),
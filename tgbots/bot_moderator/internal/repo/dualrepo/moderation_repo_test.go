@@ -35,15 +35,19 @@ func (s *stubModerationRepo) GetLatestCircleKey(context.Context, int64) (string,
 	return "", nil
 }
 
+func (s *stubModerationRepo) ListPhotoMatches(context.Context, int64) ([]model.PhotoMatch, error) {
+	return []model.PhotoMatch{}, nil
+}
+
 func (s *stubModerationRepo) GetByID(context.Context, int64) (model.ModerationItem, error) {
 	return model.ModerationItem{}, nil
 }
 
-func (s *stubModerationRepo) MarkApproved(context.Context, int64) error {
+func (s *stubModerationRepo) MarkApproved(context.Context, int64, int64, bool) error {
 	return nil
 }
 
-func (s *stubModerationRepo) MarkRejected(context.Context, int64, string, string, string) error {
+func (s *stubModerationRepo) MarkRejected(context.Context, int64, int64, string, string, string) error {
 	return nil
 }
 
@@ -51,6 +55,14 @@ func (s *stubModerationRepo) InsertModerationAction(context.Context, model.BotMo
 	return nil
 }
 
+func (s *stubModerationRepo) AcquirePendingBatch(context.Context, int64, int, time.Duration) ([]model.ModerationItem, error) {
+	return []model.ModerationItem{}, nil
+}
+
+func (s *stubModerationRepo) DecideBatch(context.Context, int64, []model.ModerationBatchDecision) ([]model.ModerationItem, error) {
+	return []model.ModerationItem{}, nil
+}
+
 func TestDualRepoHTTPOkDoesNotCallDB(t *testing.T) {
 	t.Parallel()
 
@@ -4,12 +4,18 @@ import (
 	"context"
 	"database/sql"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"strings"
+	"time"
 
 	"bot_moderator/internal/domain/enums"
 	"bot_moderator/internal/domain/model"
 )
 
+// auditChainLockKey serializes chain appends; the backend takes the same advisory lock.
+const auditChainLockKey = "bot_audit_chain"
+
 type AuditRepo struct {
 	db *sql.DB
 }
@@ -27,43 +33,114 @@ func (r *AuditRepo) Save(ctx context.Context, entry model.Audit) error {
 	if len(payload) == 0 {
 		payload = json.RawMessage(`{}`)
 	}
+	entry.Payload = payload
+	// Postgres rounds to microseconds; truncate first so the stored value hashes the same.
+	entry.CreatedAt = entry.CreatedAt.UTC().Truncate(time.Microsecond)
+
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("begin transaction for bot audit: %w", err)
+	}
+	defer func() {
+		_ = tx.Rollback()
+	}()
 
-	_, err := r.db.ExecContext(ctx, `
-		INSERT INTO bot_audit (actor_tg_id, action, payload, created_at)
-		VALUES ($1, $2, $3, $4)
-	`, entry.ActorTGID, string(entry.Action), string(payload), entry.CreatedAt)
-	return err
+	if _, err := tx.ExecContext(ctx, `SELECT pg_advisory_xact_lock(hashtext($1))`, auditChainLockKey); err != nil {
+		return fmt.Errorf("lock bot audit chain: %w", err)
+	}
+
+	var prevHash string
+	err = tx.QueryRowContext(ctx, `
+		SELECT hash
+		FROM bot_audit
+		WHERE hash IS NOT NULL
+		ORDER BY seq DESC
+		LIMIT 1
+	`).Scan(&prevHash)
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		return fmt.Errorf("load bot audit chain head: %w", err)
+	}
+
+	hash := model.AuditChainHash(prevHash, entry)
+	if _, err := tx.ExecContext(ctx, `
+		INSERT INTO bot_audit (actor_tg_id, action, payload, created_at, prev_hash, hash)
+		VALUES ($1, $2, $3, $4, $5, $6)
+	`, entry.ActorTGID, string(entry.Action), string(payload), entry.CreatedAt, prevHash, hash); err != nil {
+		return fmt.Errorf("insert bot audit: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("commit bot audit: %w", err)
+	}
+	return nil
 }
 
 func (r *AuditRepo) ListRecent(ctx context.Context, limit int) ([]model.Audit, error) {
+	if limit <= 0 {
+		limit = 50
+	}
+	return r.Search(ctx, model.AuditFilter{Limit: limit})
+}
+
+func (r *AuditRepo) Search(ctx context.Context, filter model.AuditFilter) ([]model.Audit, error) {
 	if r.db == nil {
 		return []model.Audit{}, nil
 	}
-	if limit <= 0 {
-		limit = 50
+	if filter.Limit <= 0 {
+		filter.Limit = 50
+	}
+	if filter.Offset < 0 {
+		filter.Offset = 0
 	}
 
-	rows, err := r.db.QueryContext(ctx, `
-		SELECT id::text, actor_tg_id, action, payload, created_at
+	conditions := make([]string, 0, 5)
+	args := make([]interface{}, 0, 7)
+	if filter.ActorTGID != 0 {
+		args = append(args, filter.ActorTGID)
+		conditions = append(conditions, fmt.Sprintf("actor_tg_id = $%d", len(args)))
+	}
+	if filter.TargetUserID != 0 {
+		args = append(args, fmt.Sprintf("%d", filter.TargetUserID))
+		conditions = append(conditions, fmt.Sprintf("payload->>'target_user_id' = $%d", len(args)))
+	}
+	if filter.Action != "" {
+		args = append(args, string(filter.Action))
+		conditions = append(conditions, fmt.Sprintf("action = $%d", len(args)))
+	}
+	if filter.From != nil {
+		args = append(args, filter.From.UTC())
+		conditions = append(conditions, fmt.Sprintf("created_at >= $%d", len(args)))
+	}
+	if filter.To != nil {
+		args = append(args, filter.To.UTC())
+		conditions = append(conditions, fmt.Sprintf("created_at < $%d", len(args)))
+	}
+
+	where := ""
+	if len(conditions) > 0 {
+		where = "WHERE " + strings.Join(conditions, " AND ")
+	}
+	args = append(args, filter.Limit, filter.Offset)
+
+	query := fmt.Sprintf(`
+		SELECT id::text, actor_tg_id, action, payload, COALESCE(prev_hash, ''), COALESCE(hash, ''), created_at
 		FROM bot_audit
-		ORDER BY created_at DESC
-		LIMIT $1
-	`, limit)
+		%s
+		ORDER BY created_at DESC, seq DESC
+		LIMIT $%d OFFSET $%d
+	`, where, len(args)-1, len(args))
+	rows, err := r.db.QueryContext(ctx, query, args...)
 	if err != nil {
-		return nil, fmt.Errorf("list recent bot audit: %w", err)
+		return nil, fmt.Errorf("search bot audit: %w", err)
 	}
 	defer rows.Close()
 
-	result := make([]model.Audit, 0, limit)
+	result := make([]model.Audit, 0, filter.Limit)
 	for rows.Next() {
-		var entry model.Audit
-		var action string
-		var payload []byte
-		if err := rows.Scan(&entry.ID, &entry.ActorTGID, &action, &payload, &entry.CreatedAt); err != nil {
-			return nil, fmt.Errorf("scan bot audit row: %w", err)
+		entry, err := scanAudit(rows)
+		if err != nil {
+			return nil, err
 		}
-		entry.Action = enums.AuditAction(action)
-		entry.Payload = json.RawMessage(payload)
 		result = append(result, entry)
 	}
 	if err := rows.Err(); err != nil {
@@ -72,3 +149,55 @@ func (r *AuditRepo) ListRecent(ctx context.Context, limit int) ([]model.Audit, e
 
 	return result, nil
 }
+
+// VerifyChain walks the chain from its first hashed entry; rows written before the chain existed are skipped.
+func (r *AuditRepo) VerifyChain(ctx context.Context) (model.AuditChainReport, error) {
+	report := model.AuditChainReport{OK: true}
+	if r.db == nil {
+		return report, nil
+	}
+
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT id::text, actor_tg_id, action, payload, COALESCE(prev_hash, ''), COALESCE(hash, ''), created_at
+		FROM bot_audit
+		WHERE seq >= (SELECT MIN(seq) FROM bot_audit WHERE hash IS NOT NULL)
+		ORDER BY seq ASC
+	`)
+	if err != nil {
+		return model.AuditChainReport{}, fmt.Errorf("load bot audit chain: %w", err)
+	}
+	defer rows.Close()
+
+	prevHash := ""
+	for rows.Next() {
+		entry, err := scanAudit(rows)
+		if err != nil {
+			return model.AuditChainReport{}, err
+		}
+		report.Checked++
+		if entry.Hash == "" || entry.PrevHash != prevHash || model.AuditChainHash(prevHash, entry) != entry.Hash {
+			report.OK = false
+			report.BrokenID = entry.ID
+			return report, nil
+		}
+		prevHash = entry.Hash
+	}
+	if err := rows.Err(); err != nil {
+		return model.AuditChainReport{}, fmt.Errorf("iterate bot audit chain: %w", err)
+	}
+
+	return report, nil
+}
+
+func scanAudit(rows *sql.Rows) (model.Audit, error) {
+	var entry model.Audit
+	var action string
+	var payload []byte
+	if err := rows.Scan(&entry.ID, &entry.ActorTGID, &action, &payload, &entry.PrevHash, &entry.Hash, &entry.CreatedAt); err != nil {
+		return model.Audit{}, fmt.Errorf("scan bot audit row: %w", err)
+	}
+	entry.Action = enums.AuditAction(action)
+	entry.Payload = json.RawMessage(payload)
+	entry.CreatedAt = entry.CreatedAt.UTC()
+	return entry, nil
+}
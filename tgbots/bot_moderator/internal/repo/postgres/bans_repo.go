@@ -5,17 +5,91 @@ import (
 	"database/sql"
 	"fmt"
 	"strings"
+	"time"
+
+	"bot_moderator/internal/domain/enums"
+	"bot_moderator/internal/domain/model"
 )
 
+const banUnmatchedStatus = "unmatched_ban"
+
 type BansRepo struct {
-	db *sql.DB
+	db  *sql.DB
+	now func() time.Time
 }
 
 func NewBansRepo(db *sql.DB) *BansRepo {
-	return &BansRepo{db: db}
+	return &BansRepo{db: db, now: time.Now}
 }
 
-func (r *BansRepo) SetBan(ctx context.Context, userID int64, banned bool, reason string, updatedByTGID int64) error {
+// Ban writes the ban and ends active matches. It is only used when the bot runs without the
+// admin API: app sessions live in the backend's Redis and cannot be revoked from here, but the
+// backend refuses login and token refresh for banned users, so they expire with the access token.
+func (r *BansRepo) Ban(ctx context.Context, in model.BanInput) (model.BanState, error) {
+	if r.db == nil {
+		return model.BanState{}, nil
+	}
+	if in.UserID <= 0 {
+		return model.BanState{}, fmt.Errorf("invalid user id")
+	}
+	if in.ActorTGID == 0 {
+		return model.BanState{}, fmt.Errorf("invalid updated_by_tg_id")
+	}
+
+	now := r.now().UTC()
+	state := model.BanState{
+		Banned:     true,
+		ReasonCode: in.ReasonCode,
+		Reason:     strings.TrimSpace(in.Reason),
+		BannedAt:   &now,
+	}
+	if ttl := enums.BanDuration(in.Duration).TTL(); ttl > 0 {
+		expiresAt := now.Add(ttl)
+		state.ExpiresAt = &expiresAt
+	}
+
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return model.BanState{}, fmt.Errorf("begin ban transaction: %w", err)
+	}
+	defer func() {
+		_ = tx.Rollback()
+	}()
+
+	_, err = tx.ExecContext(ctx, `
+		INSERT INTO user_bans (user_id, banned, reason_code, reason, banned_at, expires_at, updated_by_tg_id, updated_at)
+		VALUES ($1::uuid, TRUE, $2, NULLIF($3, ''), $4, $5, $6, $4)
+		ON CONFLICT (user_id)
+		DO UPDATE SET
+			banned = TRUE,
+			reason_code = EXCLUDED.reason_code,
+			reason = EXCLUDED.reason,
+			banned_at = EXCLUDED.banned_at,
+			expires_at = EXCLUDED.expires_at,
+			updated_by_tg_id = EXCLUDED.updated_by_tg_id,
+			updated_at = EXCLUDED.updated_at
+	`, pseudoUUID(in.UserID), state.ReasonCode, state.Reason, now, state.ExpiresAt, in.ActorTGID)
+	if err != nil {
+		return model.BanState{}, fmt.Errorf("upsert user ban: %w", err)
+	}
+
+	_, err = tx.ExecContext(ctx, `
+		UPDATE matches
+		SET status = $2
+		WHERE (user_a_id = $1 OR user_b_id = $1)
+		  AND COALESCE(status, 'active') = 'active'
+	`, in.UserID, banUnmatchedStatus)
+	if err != nil {
+		return model.BanState{}, fmt.Errorf("unmatch banned user: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return model.BanState{}, fmt.Errorf("commit ban transaction: %w", err)
+	}
+	return state, nil
+}
+
+func (r *BansRepo) Unban(ctx context.Context, userID int64, updatedByTGID int64) error {
 	if r.db == nil {
 		return nil
 	}
@@ -26,53 +100,68 @@ func (r *BansRepo) SetBan(ctx context.Context, userID int64, banned bool, reason
 		return fmt.Errorf("invalid updated_by_tg_id")
 	}
 
-	uuid := pseudoUUID(userID)
 	_, err := r.db.ExecContext(ctx, `
-		INSERT INTO user_bans (user_id, banned, reason, updated_by_tg_id, updated_at)
-		VALUES ($1::uuid, $2, NULLIF($3, ''), $4, NOW())
+		INSERT INTO user_bans (user_id, banned, reason_code, reason, banned_at, expires_at, updated_by_tg_id, updated_at)
+		VALUES ($1::uuid, FALSE, NULL, NULL, NULL, NULL, $2, NOW())
 		ON CONFLICT (user_id)
 		DO UPDATE SET
-			banned = EXCLUDED.banned,
-			reason = EXCLUDED.reason,
+			banned = FALSE,
+			reason_code = NULL,
+			reason = NULL,
+			banned_at = NULL,
+			expires_at = NULL,
 			updated_by_tg_id = EXCLUDED.updated_by_tg_id,
 			updated_at = EXCLUDED.updated_at
-	`, uuid, banned, strings.TrimSpace(reason), updatedByTGID)
+	`, pseudoUUID(userID), updatedByTGID)
 	if err != nil {
-		return fmt.Errorf("upsert user ban: %w", err)
+		return fmt.Errorf("clear user ban: %w", err)
 	}
 	return nil
 }
 
-func (r *BansRepo) GetBanState(ctx context.Context, userID int64) (bool, string, error) {
+// GetBanState reports an expired temporary ban as not banned.
+func (r *BansRepo) GetBanState(ctx context.Context, userID int64) (model.BanState, error) {
 	if r.db == nil {
-		return false, "", nil
+		return model.BanState{}, nil
 	}
 	if userID <= 0 {
-		return false, "", fmt.Errorf("invalid user id")
+		return model.BanState{}, fmt.Errorf("invalid user id")
 	}
+	return queryBanState(ctx, r.db, userID)
+}
 
-	var banned bool
-	var reason sql.NullString
-	err := r.db.QueryRowContext(ctx, `
-		SELECT banned, reason
+func queryBanState(ctx context.Context, db *sql.DB, userID int64) (model.BanState, error) {
+	var (
+		state      model.BanState
+		reasonCode sql.NullString
+		reason     sql.NullString
+		bannedAt   sql.NullTime
+		expiresAt  sql.NullTime
+	)
+	err := db.QueryRowContext(ctx, `
+		SELECT banned, reason_code, reason, banned_at, expires_at
 		FROM user_bans
 		WHERE user_id = $1::uuid
+		  AND banned = TRUE
+		  AND (expires_at IS NULL OR expires_at > NOW())
 		LIMIT 1
-	`, pseudoUUID(userID)).Scan(&banned, &reason)
+	`, pseudoUUID(userID)).Scan(&state.Banned, &reasonCode, &reason, &bannedAt, &expiresAt)
 	if err != nil {
 		if err == sql.ErrNoRows {
-			return false, "", nil
+			return model.BanState{}, nil
 		}
-		return false, "", fmt.Errorf("get user ban state: %w", err)
+		return model.BanState{}, fmt.Errorf("get user ban state: %w", err)
 	}
 
-	return banned, strings.TrimSpace(reason.String), nil
-}
-
-func (r *BansRepo) Ban(ctx context.Context, userID int64, reason string, updatedByTGID int64) error {
-	return r.SetBan(ctx, userID, true, reason, updatedByTGID)
-}
-
-func (r *BansRepo) Unban(ctx context.Context, userID int64, updatedByTGID int64) error {
-	return r.SetBan(ctx, userID, false, "", updatedByTGID)
+	state.ReasonCode = strings.TrimSpace(reasonCode.String)
+	state.Reason = strings.TrimSpace(reason.String)
+	if bannedAt.Valid {
+		value := bannedAt.Time.UTC()
+		state.BannedAt = &value
+	}
+	if expiresAt.Valid {
+		value := expiresAt.Time.UTC()
+		state.ExpiresAt = &value
+	}
+	return state, nil
 }
@@ -4,7 +4,6 @@ import (
 	"context"
 	"database/sql"
 	"errors"
-	"strings"
 	"time"
 
 	"bot_moderator/internal/domain/enums"
@@ -38,7 +37,7 @@ func (r *BotRolesRepo) GetActiveRole(ctx context.Context, tgID int64) (enums.Rol
 		return enums.RoleNone, err
 	}
 
-	return normalizeRole(role), nil
+	return enums.NormalizeRole(role), nil
 }
 
 func (r *BotRolesRepo) ListActive(ctx context.Context) ([]model.BotRoleAssignment, error) {
@@ -55,9 +54,9 @@ func (r *BotRolesRepo) ListActive(ctx context.Context) ([]model.BotRoleAssignmen
 		FROM bot_roles br
 		LEFT JOIN bot_users bu ON bu.tg_id = br.tg_id
 		WHERE br.revoked_at IS NULL
-		  AND br.role IN ($1, $2)
+		  AND br.role <> $1
 		ORDER BY br.role ASC, bu.username ASC NULLS LAST, br.tg_id ASC
-	`, string(enums.RoleAdmin), string(enums.RoleModerator))
+	`, string(enums.RoleOwner))
 	if err != nil {
 		return nil, err
 	}
@@ -76,7 +75,7 @@ func (r *BotRolesRepo) ListActive(ctx context.Context) ([]model.BotRoleAssignmen
 		); err != nil {
 			return nil, err
 		}
-		assignment.Role = normalizeRole(role)
+		assignment.Role = enums.NormalizeRole(role)
 		result = append(result, assignment)
 	}
 	if err := rows.Err(); err != nil {
@@ -131,16 +130,3 @@ func (r *BotRolesRepo) RevokeRole(ctx context.Context, tgID int64, revokedAt tim
 	}
 	return affected > 0, nil
 }
-
-func normalizeRole(role string) enums.Role {
-	switch strings.ToUpper(strings.TrimSpace(role)) {
-	case string(enums.RoleOwner):
-		return enums.RoleOwner
-	case string(enums.RoleAdmin):
-		return enums.RoleAdmin
-	case string(enums.RoleModerator):
-		return enums.RoleModerator
-	default:
-		return enums.RoleNone
-	}
-}
@@ -0,0 +1,420 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"bot_moderator/internal/domain/enums"
+	"bot_moderator/internal/domain/model"
+	"github.com/lib/pq"
+)
+
+const dossierActivityDays = 7
+
+var dossierRiskEventNames = []string{
+	"antiabuse_cooldown_applied",
+	"antiabuse_too_fast",
+	"antiabuse_shadow_enabled",
+	"antiabuse_suspect_like",
+	"antiabuse_new_device",
+	"antiabuse_low_card_view",
+}
+
+// Dossier is the DB-only fallback: the live anti-abuse state lives in the backend Redis and
+// support history behind the backend API, so both are reported as unavailable here.
+func (r *UsersLookupRepo) Dossier(ctx context.Context, query model.DossierQuery) (model.Dossier, error) {
+	if r.db == nil {
+		return model.Dossier{}, ErrLookupUserNotFound
+	}
+
+	var exists bool
+	if err := r.db.QueryRowContext(ctx, `SELECT EXISTS (SELECT 1 FROM users WHERE id = $1)`, query.UserID).Scan(&exists); err != nil {
+		return model.Dossier{}, fmt.Errorf("check dossier user: %w", err)
+	}
+	if !exists {
+		return model.Dossier{}, ErrLookupUserNotFound
+	}
+
+	out := model.Dossier{
+		UserID:  query.UserID,
+		Section: query.Section,
+		Limit:   query.Limit,
+		Offset:  query.Offset,
+	}
+	want := func(section enums.DossierSection) bool {
+		return query.Section == "" || query.Section == string(section)
+	}
+
+	var err error
+	if want(enums.DossierSectionOverview) || want(enums.DossierSectionRisk) {
+		risk := model.DossierRisk{}
+		if want(enums.DossierSectionRisk) {
+			if risk.Events, risk.EventsTotal, err = r.dossierRiskEvents(ctx, query); err != nil {
+				return model.Dossier{}, err
+			}
+		}
+		out.Risk = &risk
+	}
+	if want(enums.DossierSectionOverview) {
+		activity, err := r.dossierActivity(ctx, query.UserID)
+		if err != nil {
+			return model.Dossier{}, err
+		}
+		out.Activity = &activity
+	}
+	if want(enums.DossierSectionReports) {
+		reports := model.DossierReports{}
+		if reports.Received, reports.ReceivedTotal, err = r.dossierReports(ctx, query, "target_user_id", "reporter_user_id"); err != nil {
+			return model.Dossier{}, err
+		}
+		if reports.Filed, reports.FiledTotal, err = r.dossierReports(ctx, query, "reporter_user_id", "target_user_id"); err != nil {
+			return model.Dossier{}, err
+		}
+		out.Reports = &reports
+	}
+	if want(enums.DossierSectionDevices) {
+		devices, err := r.dossierDevices(ctx, query)
+		if err != nil {
+			return model.Dossier{}, err
+		}
+		out.Devices = &devices
+	}
+	if want(enums.DossierSectionPurchases) {
+		purchases, err := r.dossierPurchases(ctx, query)
+		if err != nil {
+			return model.Dossier{}, err
+		}
+		out.Purchases = &purchases
+	}
+	if want(enums.DossierSectionModeration) {
+		moderation, err := r.dossierModeration(ctx, query)
+		if err != nil {
+			return model.Dossier{}, err
+		}
+		out.Moderation = &moderation
+	}
+	if want(enums.DossierSectionSupport) {
+		out.Support = &model.DossierSupport{}
+	}
+	return out, nil
+}
+
+func (r *UsersLookupRepo) dossierRiskEvents(ctx context.Context, query model.DossierQuery) ([]model.DossierRiskEvent, int, error) {
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT name, occurred_at, payload, COUNT(*) OVER ()
+		FROM events
+		WHERE user_id = $1
+		  AND name = ANY($2::text[])
+		ORDER BY occurred_at DESC, id DESC
+		LIMIT $3 OFFSET $4
+	`, query.UserID, pq.Array(dossierRiskEventNames), query.Limit, query.Offset)
+	if err != nil {
+		return nil, 0, fmt.Errorf("list dossier risk events: %w", err)
+	}
+	defer rows.Close()
+
+	items := make([]model.DossierRiskEvent, 0, query.Limit)
+	total := 0
+	for rows.Next() {
+		var (
+			item    model.DossierRiskEvent
+			payload []byte
+		)
+		if err := rows.Scan(&item.Name, &item.OccurredAt, &payload, &total); err != nil {
+			return nil, 0, fmt.Errorf("scan dossier risk event: %w", err)
+		}
+		item.OccurredAt = item.OccurredAt.UTC()
+		if len(payload) > 0 {
+			_ = json.Unmarshal(payload, &item.Payload)
+		}
+		items = append(items, item)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, 0, fmt.Errorf("iterate dossier risk events: %w", err)
+	}
+	return items, total, nil
+}
+
+func (r *UsersLookupRepo) dossierActivity(ctx context.Context, userID int64) (model.DossierActivity, error) {
+	now := time.Now().UTC()
+	since := now.AddDate(0, 0, -dossierActivityDays)
+	activity := model.DossierActivity{Days: dossierActivityDays}
+
+	err := r.db.QueryRowContext(ctx, `
+		SELECT
+			COUNT(*) FILTER (WHERE s.actor_user_id = $1 AND s.action = 'LIKE' AND s.created_at >= $2),
+			COUNT(*) FILTER (WHERE s.actor_user_id = $1 AND s.action = 'SUPERLIKE' AND s.created_at >= $2),
+			COUNT(*) FILTER (WHERE s.actor_user_id = $1 AND s.action = 'DISLIKE' AND s.created_at >= $2),
+			COUNT(*) FILTER (WHERE s.target_user_id = $1 AND s.action IN ('LIKE', 'SUPERLIKE') AND s.created_at >= $2),
+			COUNT(*) FILTER (WHERE s.actor_user_id = $1 AND s.created_at >= $3),
+			COUNT(*) FILTER (WHERE s.actor_user_id = $1 AND s.created_at >= $4)
+		FROM swipes s
+		WHERE (s.actor_user_id = $1 OR s.target_user_id = $1)
+		  AND s.created_at >= $2
+	`, userID, since, now.Add(-time.Hour), now.Add(-24*time.Hour)).Scan(
+		&activity.Likes,
+		&activity.Superlikes,
+		&activity.Dislikes,
+		&activity.LikesReceived,
+		&activity.Swipes1h,
+		&activity.Swipes24h,
+	)
+	if err != nil {
+		return model.DossierActivity{}, fmt.Errorf("load dossier swipe activity: %w", err)
+	}
+	activity.LikeRatio = dossierLikeRatio(int64(activity.Likes+activity.Superlikes), int64(activity.Dislikes))
+
+	if err := r.db.QueryRowContext(ctx, `
+		SELECT COALESCE(SUM(too_fast_hits), 0)
+		FROM quotas_daily
+		WHERE user_id = $1
+		  AND day_key >= $2::date
+	`, userID, since).Scan(&activity.TooFastHits); err != nil {
+		return model.DossierActivity{}, fmt.Errorf("load dossier too-fast hits: %w", err)
+	}
+
+	var segmentLikes, segmentDislikes int64
+	err = r.db.QueryRowContext(ctx, `
+		SELECT COALESCE(SUM(dm.likes + dm.superlikes), 0), COALESCE(SUM(dm.dislikes), 0)
+		FROM profiles p
+		JOIN daily_metrics dm
+		  ON dm.city_id = COALESCE(NULLIF(TRIM(p.city_id), ''), 'unknown')
+		 AND dm.gender = COALESCE(NULLIF(LOWER(TRIM(p.gender)), ''), 'unknown')
+		 AND dm.looking_for = COALESCE(NULLIF(LOWER(TRIM(p.looking_for)), ''), 'unknown')
+		WHERE p.user_id = $1
+		  AND dm.day_key >= $2::date
+	`, userID, since).Scan(&segmentLikes, &segmentDislikes)
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		return model.DossierActivity{}, fmt.Errorf("load dossier segment metrics: %w", err)
+	}
+	activity.SegmentLikeRatio = dossierLikeRatio(segmentLikes, segmentDislikes)
+
+	return activity, nil
+}
+
+func dossierLikeRatio(likes, dislikes int64) float64 {
+	if likes+dislikes <= 0 {
+		return 0
+	}
+	return float64(likes) / float64(likes+dislikes)
+}
+
+func (r *UsersLookupRepo) dossierReports(ctx context.Context, query model.DossierQuery, ownColumn, counterpartColumn string) ([]model.DossierReport, int, error) {
+	rows, err := r.db.QueryContext(ctx, fmt.Sprintf(`
+		SELECT id, %s, reason, COALESCE(details, ''), status, created_at, COUNT(*) OVER ()
+		FROM reports
+		WHERE %s = $1
+		ORDER BY created_at DESC, id DESC
+		LIMIT $2 OFFSET $3
+	`, counterpartColumn, ownColumn), query.UserID, query.Limit, query.Offset)
+	if err != nil {
+		return nil, 0, fmt.Errorf("list dossier reports: %w", err)
+	}
+	defer rows.Close()
+
+	items := make([]model.DossierReport, 0, query.Limit)
+	total := 0
+	for rows.Next() {
+		var item model.DossierReport
+		if err := rows.Scan(&item.ID, &item.CounterpartUserID, &item.Reason, &item.Details, &item.Status, &item.CreatedAt, &total); err != nil {
+			return nil, 0, fmt.Errorf("scan dossier report: %w", err)
+		}
+		item.CreatedAt = item.CreatedAt.UTC()
+		items = append(items, item)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, 0, fmt.Errorf("iterate dossier reports: %w", err)
+	}
+	return items, total, nil
+}
+
+func (r *UsersLookupRepo) dossierDevices(ctx context.Context, query model.DossierQuery) (model.DossierDevices, error) {
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT
+			d.device_id,
+			d.first_seen_at,
+			d.last_seen_at,
+			d.is_blocked,
+			(SELECT COUNT(*) FROM user_devices o WHERE o.device_id = d.device_id AND o.user_id <> d.user_id),
+			COUNT(*) OVER ()
+		FROM user_devices d
+		WHERE d.user_id = $1
+		ORDER BY d.last_seen_at DESC, d.device_id ASC
+		LIMIT $2 OFFSET $3
+	`, query.UserID, query.Limit, query.Offset)
+	if err != nil {
+		return model.DossierDevices{}, fmt.Errorf("list dossier devices: %w", err)
+	}
+	defer rows.Close()
+
+	out := model.DossierDevices{Devices: make([]model.DossierDevice, 0, query.Limit)}
+	for rows.Next() {
+		var item model.DossierDevice
+		if err := rows.Scan(&item.DeviceID, &item.FirstSeenAt, &item.LastSeenAt, &item.IsBlocked, &item.SharedWith, &out.Total); err != nil {
+			return model.DossierDevices{}, fmt.Errorf("scan dossier device: %w", err)
+		}
+		item.FirstSeenAt = item.FirstSeenAt.UTC()
+		item.LastSeenAt = item.LastSeenAt.UTC()
+		out.Devices = append(out.Devices, item)
+	}
+	if err := rows.Err(); err != nil {
+		return model.DossierDevices{}, fmt.Errorf("iterate dossier devices: %w", err)
+	}
+
+	out.LinkedAccounts, err = r.linkedAccounts(ctx, query.UserID)
+	if err != nil {
+		return model.DossierDevices{}, err
+	}
+	return out, nil
+}
+
+func (r *UsersLookupRepo) linkedAccounts(ctx context.Context, userID int64) ([]model.DossierLinkedAccount, error) {
+	rows, err := r.db.QueryContext(ctx, linkedAccountsCTE+`
+		SELECT
+			l.linked_user_id,
+			u.telegram_id,
+			l.signal,
+			EXISTS (
+				SELECT 1
+				FROM user_bans ub
+				WHERE ub.user_id = (
+					'00000000-0000-0000-0000-' ||
+					LPAD(TO_HEX((l.linked_user_id & 281474976710655)::bigint), 12, '0')
+				)::uuid
+				  AND ub.banned = TRUE
+				  AND (ub.expires_at IS NULL OR ub.expires_at > NOW())
+			)
+		FROM linked l
+		JOIN users u ON u.id = l.linked_user_id
+		ORDER BY l.linked_user_id ASC, l.signal ASC
+	`, userID)
+	if err != nil {
+		return nil, fmt.Errorf("list linked accounts: %w", err)
+	}
+	defer rows.Close()
+
+	items := make([]model.DossierLinkedAccount, 0)
+	index := make(map[int64]int)
+	for rows.Next() {
+		var (
+			linkedUserID int64
+			linkedTGID   int64
+			signal       string
+			banned       bool
+		)
+		if err := rows.Scan(&linkedUserID, &linkedTGID, &signal, &banned); err != nil {
+			return nil, fmt.Errorf("scan linked account: %w", err)
+		}
+		pos, ok := index[linkedUserID]
+		if !ok {
+			pos = len(items)
+			index[linkedUserID] = pos
+			items = append(items, model.DossierLinkedAccount{UserID: linkedUserID, TGID: linkedTGID, Banned: banned})
+		}
+		items[pos].Signals = append(items[pos].Signals, signal)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterate linked accounts: %w", err)
+	}
+	return items, nil
+}
+
+func (r *UsersLookupRepo) dossierPurchases(ctx context.Context, query model.DossierQuery) (model.DossierPurchases, error) {
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT id, sku, provider, status, COALESCE(amount_minor, 0), COALESCE(currency, ''), created_at, COUNT(*) OVER ()
+		FROM purchases
+		WHERE user_id = $1
+		ORDER BY created_at DESC, id DESC
+		LIMIT $2 OFFSET $3
+	`, query.UserID, query.Limit, query.Offset)
+	if err != nil {
+		return model.DossierPurchases{}, fmt.Errorf("list dossier purchases: %w", err)
+	}
+	defer rows.Close()
+
+	out := model.DossierPurchases{Items: make([]model.DossierPurchase, 0, query.Limit)}
+	for rows.Next() {
+		var item model.DossierPurchase
+		if err := rows.Scan(&item.ID, &item.SKU, &item.Provider, &item.Status, &item.AmountMinor, &item.Currency, &item.CreatedAt, &out.Total); err != nil {
+			return model.DossierPurchases{}, fmt.Errorf("scan dossier purchase: %w", err)
+		}
+		item.CreatedAt = item.CreatedAt.UTC()
+		out.Items = append(out.Items, item)
+	}
+	if err := rows.Err(); err != nil {
+		return model.DossierPurchases{}, fmt.Errorf("iterate dossier purchases: %w", err)
+	}
+	return out, nil
+}
+
+func (r *UsersLookupRepo) dossierModeration(ctx context.Context, query model.DossierQuery) (model.DossierModeration, error) {
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT
+			mi.id,
+			mi.target_type,
+			mi.status,
+			COALESCE(mi.reason_code, ''),
+			mi.moderator_tg_id,
+			mi.created_at,
+			mi.decided_at,
+			COALESCE(mr.kind, ''),
+			COALESCE(mr.status, ''),
+			COUNT(*) OVER ()
+		FROM moderation_items mi
+		LEFT JOIN LATERAL (
+			SELECT r.kind, r.status
+			FROM moderation_reviews r
+			WHERE r.moderation_item_id = mi.id
+			ORDER BY r.created_at DESC, r.id DESC
+			LIMIT 1
+		) mr ON TRUE
+		WHERE mi.user_id = $1
+		ORDER BY mi.created_at DESC, mi.id DESC
+		LIMIT $2 OFFSET $3
+	`, query.UserID, query.Limit, query.Offset)
+	if err != nil {
+		return model.DossierModeration{}, fmt.Errorf("list dossier moderation decisions: %w", err)
+	}
+	defer rows.Close()
+
+	out := model.DossierModeration{Items: make([]model.DossierModerationDecision, 0, query.Limit)}
+	for rows.Next() {
+		var (
+			item          model.DossierModerationDecision
+			moderatorTGID sql.NullInt64
+			decidedAt     sql.NullTime
+		)
+		if err := rows.Scan(
+			&item.ModerationItemID,
+			&item.TargetType,
+			&item.Status,
+			&item.ReasonCode,
+			&moderatorTGID,
+			&item.CreatedAt,
+			&decidedAt,
+			&item.ReviewKind,
+			&item.ReviewStatus,
+			&out.Total,
+		); err != nil {
+			return model.DossierModeration{}, fmt.Errorf("scan dossier moderation decision: %w", err)
+		}
+		item.CreatedAt = item.CreatedAt.UTC()
+		if moderatorTGID.Valid {
+			value := moderatorTGID.Int64
+			item.ModeratorTGID = &value
+		}
+		if decidedAt.Valid {
+			value := decidedAt.Time.UTC()
+			item.DecidedAt = &value
+		}
+		out.Items = append(out.Items, item)
+	}
+	if err := rows.Err(); err != nil {
+		return model.DossierModeration{}, fmt.Errorf("iterate dossier moderation decisions: %w", err)
+	}
+	return out, nil
+}
@@ -5,8 +5,12 @@ import (
 	"database/sql"
 	"encoding/json"
 	"fmt"
+	"sort"
 	"strings"
 	"time"
+
+	"bot_moderator/internal/domain/model"
+	"github.com/lib/pq"
 )
 
 type ExportsQueueRepo struct {
@@ -46,3 +50,199 @@ func (r *ExportsQueueRepo) Enqueue(ctx context.Context, kind string, payload jso
 	}
 	return nil
 }
+
+// Lease claims up to limit pending rows whose next attempt is due and bumps their attempt counter.
+func (r *ExportsQueueRepo) Lease(ctx context.Context, kind string, limit int, lease time.Duration) ([]model.ExportQueueItem, error) {
+	if r.db == nil {
+		return []model.ExportQueueItem{}, nil
+	}
+	if limit <= 0 {
+		limit = 50
+	}
+	if lease <= 0 {
+		lease = 2 * time.Minute
+	}
+
+	rows, err := r.db.QueryContext(ctx, `
+		WITH candidate AS (
+			SELECT id
+			FROM bot_exports_queue
+			WHERE status = 'PENDING'
+			  AND kind = $1
+			  AND next_attempt_at <= NOW()
+			  AND (locked_until IS NULL OR locked_until < NOW())
+			ORDER BY created_at ASC, id ASC
+			FOR UPDATE SKIP LOCKED
+			LIMIT $2
+		)
+		UPDATE bot_exports_queue q
+		SET locked_until = NOW() + make_interval(secs => $3),
+		    attempts = q.attempts + 1,
+		    updated_at = NOW()
+		FROM candidate
+		WHERE q.id = candidate.id
+		RETURNING q.id::text, q.kind, q.payload::text, q.status, q.attempts, COALESCE(q.last_error, ''), q.created_at
+	`, strings.TrimSpace(kind), limit, int64(lease/time.Second))
+	if err != nil {
+		return nil, fmt.Errorf("lease export items: %w", err)
+	}
+	defer rows.Close()
+
+	items := make([]model.ExportQueueItem, 0, limit)
+	for rows.Next() {
+		item, err := scanExportQueueItem(rows)
+		if err != nil {
+			return nil, fmt.Errorf("scan leased export item: %w", err)
+		}
+		items = append(items, item)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterate leased export items: %w", err)
+	}
+
+	sort.SliceStable(items, func(i, j int) bool {
+		return items[i].CreatedAt.Before(items[j].CreatedAt)
+	})
+	return items, nil
+}
+
+func (r *ExportsQueueRepo) MarkDone(ctx context.Context, ids []string) error {
+	if r.db == nil || len(ids) == 0 {
+		return nil
+	}
+
+	_, err := r.db.ExecContext(ctx, `
+		UPDATE bot_exports_queue
+		SET status = 'DONE',
+		    last_error = NULL,
+		    locked_until = NULL,
+		    processed_at = NOW(),
+		    updated_at = NOW()
+		WHERE id = ANY($1::uuid[])
+	`, pq.Array(ids))
+	if err != nil {
+		return fmt.Errorf("mark export items done: %w", err)
+	}
+	return nil
+}
+
+func (r *ExportsQueueRepo) MarkRetry(ctx context.Context, id string, lastError string, nextAttemptAt time.Time) error {
+	if r.db == nil {
+		return nil
+	}
+
+	_, err := r.db.ExecContext(ctx, `
+		UPDATE bot_exports_queue
+		SET last_error = $2,
+		    next_attempt_at = $3,
+		    locked_until = NULL,
+		    updated_at = NOW()
+		WHERE id = $1::uuid
+	`, id, strings.TrimSpace(lastError), nextAttemptAt.UTC())
+	if err != nil {
+		return fmt.Errorf("mark export item for retry: %w", err)
+	}
+	return nil
+}
+
+func (r *ExportsQueueRepo) MarkFailed(ctx context.Context, id string, lastError string) error {
+	if r.db == nil {
+		return nil
+	}
+
+	_, err := r.db.ExecContext(ctx, `
+		UPDATE bot_exports_queue
+		SET status = 'FAILED',
+		    last_error = $2,
+		    locked_until = NULL,
+		    processed_at = NOW(),
+		    updated_at = NOW()
+		WHERE id = $1::uuid
+	`, id, strings.TrimSpace(lastError))
+	if err != nil {
+		return fmt.Errorf("mark export item failed: %w", err)
+	}
+	return nil
+}
+
+func (r *ExportsQueueRepo) RequeueFailed(ctx context.Context) (int64, error) {
+	if r.db == nil {
+		return 0, nil
+	}
+
+	result, err := r.db.ExecContext(ctx, `
+		UPDATE bot_exports_queue
+		SET status = 'PENDING',
+		    attempts = 0,
+		    next_attempt_at = NOW(),
+		    processed_at = NULL,
+		    updated_at = NOW()
+		WHERE status = 'FAILED'
+	`)
+	if err != nil {
+		return 0, fmt.Errorf("requeue failed export items: %w", err)
+	}
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return 0, fmt.Errorf("rows affected for requeue export items: %w", err)
+	}
+	return affected, nil
+}
+
+func (r *ExportsQueueRepo) Stats(ctx context.Context) (model.ExportQueueStats, error) {
+	stats := model.ExportQueueStats{RecentFailures: []model.ExportQueueItem{}}
+	if r.db == nil {
+		return stats, nil
+	}
+
+	var oldest sql.NullTime
+	err := r.db.QueryRowContext(ctx, `
+		SELECT COUNT(*) FILTER (WHERE status = 'PENDING'),
+		       COUNT(*) FILTER (WHERE status = 'FAILED'),
+		       COUNT(*) FILTER (WHERE status = 'DONE' AND processed_at >= NOW() - INTERVAL '24 hours'),
+		       MIN(created_at) FILTER (WHERE status = 'PENDING')
+		FROM bot_exports_queue
+	`).Scan(&stats.Pending, &stats.Failed, &stats.DoneLast24h, &oldest)
+	if err != nil {
+		return model.ExportQueueStats{}, fmt.Errorf("query export queue stats: %w", err)
+	}
+	if oldest.Valid {
+		value := oldest.Time.UTC()
+		stats.OldestPendingAt = &value
+	}
+
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT id::text, kind, payload::text, status, attempts, COALESCE(last_error, ''), created_at
+		FROM bot_exports_queue
+		WHERE last_error IS NOT NULL
+		  AND status IN ('PENDING', 'FAILED')
+		ORDER BY updated_at DESC
+		LIMIT 5
+	`)
+	if err != nil {
+		return model.ExportQueueStats{}, fmt.Errorf("query export queue failures: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		item, err := scanExportQueueItem(rows)
+		if err != nil {
+			return model.ExportQueueStats{}, fmt.Errorf("scan export queue failure: %w", err)
+		}
+		stats.RecentFailures = append(stats.RecentFailures, item)
+	}
+	if err := rows.Err(); err != nil {
+		return model.ExportQueueStats{}, fmt.Errorf("iterate export queue failures: %w", err)
+	}
+	return stats, nil
+}
+
+func scanExportQueueItem(rows *sql.Rows) (model.ExportQueueItem, error) {
+	item := model.ExportQueueItem{}
+	var payload string
+	if err := rows.Scan(&item.ID, &item.Kind, &payload, &item.Status, &item.Attempts, &item.LastError, &item.CreatedAt); err != nil {
+		return model.ExportQueueItem{}, err
+	}
+	item.Payload = json.RawMessage(payload)
+	return item, nil
+}
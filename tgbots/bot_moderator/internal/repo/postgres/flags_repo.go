@@ -0,0 +1,160 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+
+	"bot_moderator/internal/domain/model"
+	"bot_moderator/internal/services/system"
+)
+
+var ErrFlagsUnavailable = errors.New("app flags are unavailable")
+
+// FlagsRepo reads the backend-owned app_flags table directly in db mode. Boolean flags keep
+// their default in value_bool, the column the Registration toggle flips.
+type FlagsRepo struct {
+	db *sql.DB
+}
+
+func NewFlagsRepo(db *sql.DB) *FlagsRepo {
+	return &FlagsRepo{db: db}
+}
+
+func (r *FlagsRepo) ListFlags(ctx context.Context) ([]model.AppFlag, error) {
+	if r.db == nil {
+		return nil, ErrFlagsUnavailable
+	}
+
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT key, value_type, value_bool, value, rules, description, client_visible, updated_by_tg_id, updated_at
+		FROM app_flags
+		ORDER BY key ASC
+	`)
+	if err != nil {
+		return nil, fmt.Errorf("list app flags: %w", err)
+	}
+	defer rows.Close()
+
+	items := make([]model.AppFlag, 0, 8)
+	for rows.Next() {
+		item, err := scanAppFlag(rows)
+		if err != nil {
+			return nil, err
+		}
+		items = append(items, item)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterate app flags: %w", err)
+	}
+	return items, nil
+}
+
+func (r *FlagsRepo) UpsertFlag(ctx context.Context, flag model.AppFlag, updatedByTGID int64) (model.AppFlag, error) {
+	if r.db == nil {
+		return model.AppFlag{}, ErrFlagsUnavailable
+	}
+
+	var (
+		valueBool bool
+		value     interface{}
+	)
+	if flag.Type == model.AppFlagTypeBool {
+		valueBool, _ = strconv.ParseBool(string(flag.Value))
+	} else {
+		value = string(flag.Value)
+	}
+	rules := flag.Rules
+	if rules == nil {
+		rules = []model.AppFlagRule{}
+	}
+	rawRules, err := json.Marshal(rules)
+	if err != nil {
+		return model.AppFlag{}, fmt.Errorf("encode app flag rules: %w", err)
+	}
+	var updatedBy interface{}
+	if updatedByTGID != 0 {
+		updatedBy = updatedByTGID
+	}
+
+	rows, err := r.db.QueryContext(ctx, `
+		INSERT INTO app_flags (key, value_type, value_bool, value, rules, description, client_visible, updated_by_tg_id, updated_at)
+		VALUES ($1, $2, $3, $4::jsonb, $5::jsonb, $6, $7, $8, NOW())
+		ON CONFLICT (key) DO UPDATE SET
+			value_type = EXCLUDED.value_type,
+			value_bool = EXCLUDED.value_bool,
+			value = EXCLUDED.value,
+			rules = EXCLUDED.rules,
+			description = EXCLUDED.description,
+			client_visible = EXCLUDED.client_visible,
+			updated_by_tg_id = EXCLUDED.updated_by_tg_id,
+			updated_at = NOW()
+		RETURNING key, value_type, value_bool, value, rules, description, client_visible, updated_by_tg_id, updated_at
+	`, flag.Key, flag.Type, valueBool, value, string(rawRules), flag.Description, flag.ClientVisible, updatedBy)
+	if err != nil {
+		return model.AppFlag{}, fmt.Errorf("upsert app flag: %w", err)
+	}
+	defer rows.Close()
+
+	if !rows.Next() {
+		if err := rows.Err(); err != nil {
+			return model.AppFlag{}, fmt.Errorf("upsert app flag: %w", err)
+		}
+		return model.AppFlag{}, fmt.Errorf("upsert app flag: no row returned")
+	}
+	return scanAppFlag(rows)
+}
+
+func (r *FlagsRepo) DeleteFlag(ctx context.Context, key string) error {
+	if r.db == nil {
+		return ErrFlagsUnavailable
+	}
+
+	result, err := r.db.ExecContext(ctx, `DELETE FROM app_flags WHERE key = $1`, key)
+	if err != nil {
+		return fmt.Errorf("delete app flag: %w", err)
+	}
+	if affected, err := result.RowsAffected(); err == nil && affected == 0 {
+		return system.ErrFlagNotFound
+	}
+	return nil
+}
+
+func scanAppFlag(rows *sql.Rows) (model.AppFlag, error) {
+	var (
+		item      model.AppFlag
+		valueBool bool
+		value     []byte
+		rules     []byte
+		updatedBy sql.NullInt64
+		updatedAt time.Time
+	)
+	if err := rows.Scan(&item.Key, &item.Type, &valueBool, &value, &rules, &item.Description, &item.ClientVisible, &updatedBy, &updatedAt); err != nil {
+		return model.AppFlag{}, fmt.Errorf("scan app flag: %w", err)
+	}
+
+	if item.Type == model.AppFlagTypeBool {
+		item.Value = json.RawMessage(strconv.FormatBool(valueBool))
+	} else {
+		item.Value = json.RawMessage(value)
+	}
+	item.Rules = []model.AppFlagRule{}
+	if len(rules) > 0 {
+		if err := json.Unmarshal(rules, &item.Rules); err != nil {
+			return model.AppFlag{}, fmt.Errorf("decode app flag %s rules: %w", item.Key, err)
+		}
+	}
+	if updatedBy.Valid {
+		v := updatedBy.Int64
+		item.UpdatedByTGID = &v
+	}
+	if !updatedAt.IsZero() {
+		v := updatedAt.UTC()
+		item.UpdatedAt = &v
+	}
+	return item, nil
+}
@@ -0,0 +1,9 @@
+DROP INDEX IF EXISTS idx_bot_exports_queue_status_next_attempt;
+
+ALTER TABLE bot_exports_queue
+    DROP COLUMN IF EXISTS updated_at,
+    DROP COLUMN IF EXISTS processed_at,
+    DROP COLUMN IF EXISTS locked_until,
+    DROP COLUMN IF EXISTS next_attempt_at,
+    DROP COLUMN IF EXISTS last_error,
+    DROP COLUMN IF EXISTS attempts;
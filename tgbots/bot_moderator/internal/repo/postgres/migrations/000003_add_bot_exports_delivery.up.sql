@@ -0,0 +1,10 @@
+ALTER TABLE bot_exports_queue
+    ADD COLUMN IF NOT EXISTS attempts INT NOT NULL DEFAULT 0,
+    ADD COLUMN IF NOT EXISTS last_error TEXT NULL,
+    ADD COLUMN IF NOT EXISTS next_attempt_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    ADD COLUMN IF NOT EXISTS locked_until TIMESTAMPTZ NULL,
+    ADD COLUMN IF NOT EXISTS processed_at TIMESTAMPTZ NULL,
+    ADD COLUMN IF NOT EXISTS updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW();
+
+CREATE INDEX IF NOT EXISTS idx_bot_exports_queue_status_next_attempt
+    ON bot_exports_queue (status, next_attempt_at ASC);
@@ -0,0 +1,8 @@
+DROP INDEX IF EXISTS idx_bot_audit_target_user_created_at;
+DROP INDEX IF EXISTS idx_bot_audit_created_at;
+DROP INDEX IF EXISTS idx_bot_audit_seq;
+
+ALTER TABLE bot_audit
+    DROP COLUMN IF EXISTS hash,
+    DROP COLUMN IF EXISTS prev_hash,
+    DROP COLUMN IF EXISTS seq;
@@ -0,0 +1,11 @@
+ALTER TABLE bot_audit
+    ADD COLUMN IF NOT EXISTS seq BIGSERIAL,
+    ADD COLUMN IF NOT EXISTS prev_hash TEXT NULL,
+    ADD COLUMN IF NOT EXISTS hash TEXT NULL;
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_bot_audit_seq
+    ON bot_audit (seq);
+CREATE INDEX IF NOT EXISTS idx_bot_audit_created_at
+    ON bot_audit (created_at DESC);
+CREATE INDEX IF NOT EXISTS idx_bot_audit_target_user_created_at
+    ON bot_audit (((payload->>'target_user_id')), created_at DESC);
@@ -0,0 +1,5 @@
+DROP INDEX IF EXISTS idx_bot_moderation_actions_batch_created_at;
+
+ALTER TABLE bot_moderation_actions
+    DROP COLUMN IF EXISTS bulk,
+    DROP COLUMN IF EXISTS batch_id;
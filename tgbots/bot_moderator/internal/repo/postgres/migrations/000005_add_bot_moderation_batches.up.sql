@@ -0,0 +1,7 @@
+ALTER TABLE bot_moderation_actions
+    ADD COLUMN IF NOT EXISTS batch_id TEXT NULL,
+    ADD COLUMN IF NOT EXISTS bulk BOOLEAN NOT NULL DEFAULT FALSE;
+
+CREATE INDEX IF NOT EXISTS idx_bot_moderation_actions_batch_created_at
+    ON bot_moderation_actions (actor_tg_id, batch_id, created_at DESC)
+    WHERE batch_id IS NOT NULL;
@@ -0,0 +1,288 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"bot_moderator/internal/domain/enums"
+	"bot_moderator/internal/domain/model"
+	"github.com/lib/pq"
+)
+
+// AcquirePendingBatch locks up to limit pending items for the actor under one lock deadline.
+// Live locks the actor already holds count towards the batch and are extended with it.
+func (r *ModerationRepo) AcquirePendingBatch(ctx context.Context, actorTGID int64, limit int, lockDuration time.Duration) ([]model.ModerationItem, error) {
+	if r.db == nil {
+		return nil, ErrModerationQueueEmpty
+	}
+	if actorTGID == 0 {
+		return nil, fmt.Errorf("invalid actor tg id")
+	}
+	if limit <= 0 || limit > model.MaxModerationBatchSize {
+		return nil, fmt.Errorf("invalid moderation batch size")
+	}
+	if lockDuration <= 0 {
+		lockDuration = 10 * time.Minute
+	}
+	intervalSeconds := int64(lockDuration / time.Second)
+
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("begin transaction: %w", err)
+	}
+	defer func() {
+		_ = tx.Rollback()
+	}()
+
+	skills := moderatorSkillStrings(enums.ModeratorSkills)
+	err = tx.QueryRowContext(ctx, `
+		SELECT skills
+		FROM moderator_workload
+		WHERE moderator_tg_id = $1
+	`, actorTGID).Scan(pq.Array(&skills))
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		return nil, fmt.Errorf("load moderator workload: %w", err)
+	}
+
+	rows, err := tx.QueryContext(ctx, `
+		UPDATE moderation_items
+		SET locked_at = CASE WHEN assigned_at IS NOT NULL THEN NOW() ELSE locked_at END,
+		    locked_until = NOW() + make_interval(secs => $2),
+		    assigned_at = NULL,
+		    updated_at = NOW()
+		WHERE id IN (
+			SELECT id
+			FROM moderation_items
+			WHERE locked_by_tg_id = $1
+			  AND locked_until > NOW()
+			  AND UPPER(status) = 'PENDING'
+			ORDER BY locked_at ASC, id ASC
+			LIMIT $3
+			FOR UPDATE
+		)
+		RETURNING `+moderationItemReturningColumns,
+		actorTGID, intervalSeconds, limit)
+	if err != nil {
+		return nil, fmt.Errorf("extend held moderation locks: %w", err)
+	}
+	items, err := scanModerationItems(rows)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(items) < limit {
+		rows, err = tx.QueryContext(ctx, `
+			WITH candidate AS (`+moderationQueueCandidatesSQL+`), expired AS (
+				INSERT INTO moderation_lock_expiries (moderation_item_id, moderator_tg_id, locked_at, expired_at)
+				SELECT id, locked_by_tg_id, locked_at, locked_until
+				FROM candidate
+				WHERE locked_by_tg_id IS NOT NULL
+				  AND locked_until IS NOT NULL
+			)
+			UPDATE moderation_items mi
+			SET locked_by_tg_id = $1,
+			    locked_at = NOW(),
+			    locked_until = NOW() + make_interval(secs => $2),
+			    assigned_at = NULL,
+			    updated_at = NOW()
+			FROM candidate
+			WHERE mi.id = candidate.id
+			RETURNING `+prefixedModerationItemReturningColumns,
+			actorTGID, intervalSeconds, pq.Array(skills), int64(moderationQueueAgingThreshold/time.Second), limit-len(items))
+		if err != nil {
+			return nil, fmt.Errorf("acquire pending moderation batch: %w", err)
+		}
+		acquired, err := scanModerationItems(rows)
+		if err != nil {
+			return nil, err
+		}
+		items = append(items, acquired...)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("commit transaction: %w", err)
+	}
+
+	if len(items) == 0 {
+		return nil, ErrModerationQueueEmpty
+	}
+	sort.SliceStable(items, func(i, j int) bool {
+		if !items[i].CreatedAt.Equal(items[j].CreatedAt) {
+			return items[i].CreatedAt.Before(items[j].CreatedAt)
+		}
+		return items[i].ID < items[j].ID
+	})
+	return items, nil
+}
+
+// DecideBatch applies every decision in one transaction: if any item is no longer pending or is
+// held by another moderator, nothing is written. LockedAt of the returned items is the review start.
+func (r *ModerationRepo) DecideBatch(ctx context.Context, actorTGID int64, decisions []model.ModerationBatchDecision) ([]model.ModerationItem, error) {
+	if r.db == nil {
+		return nil, ErrModerationItemNotFound
+	}
+	if actorTGID == 0 {
+		return nil, fmt.Errorf("invalid actor tg id")
+	}
+	if len(decisions) == 0 || len(decisions) > model.MaxModerationBatchSize {
+		return nil, fmt.Errorf("invalid moderation batch size")
+	}
+
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("begin transaction for moderation batch: %w", err)
+	}
+	defer func() {
+		_ = tx.Rollback()
+	}()
+
+	out := make([]model.ModerationItem, 0, len(decisions))
+	for _, decision := range decisions {
+		status := "APPROVED"
+		if decision.Decision == model.ModerationBatchReject {
+			status = "REJECTED"
+		}
+
+		item := model.ModerationItem{}
+		var itemStatus string
+		err := tx.QueryRowContext(ctx, `
+			UPDATE moderation_items
+			SET status = $3,
+			    reason_code = NULLIF($4, ''),
+			    reason_text = NULLIF($5, ''),
+			    required_fix_step = NULLIF($6, ''),
+			    decided_at = NOW(),
+			    review_started_at = locked_at,
+			    moderator_tg_id = $2,
+			    locked_by_tg_id = NULL,
+			    locked_until = NOW(),
+			    updated_at = NOW()
+			WHERE id = $1
+			  AND UPPER(status) = 'PENDING'
+			  AND (locked_by_tg_id IS NULL OR locked_by_tg_id = $2 OR locked_until < NOW())
+			RETURNING id, user_id, status, review_started_at, created_at, updated_at
+		`,
+			decision.ModerationItemID,
+			actorTGID,
+			status,
+			strings.TrimSpace(decision.ReasonCode),
+			strings.TrimSpace(decision.ReasonText),
+			strings.TrimSpace(decision.RequiredFixStep),
+		).Scan(&item.ID, &item.UserID, &itemStatus, &item.LockedAt, &item.CreatedAt, &item.UpdatedAt)
+		if err != nil {
+			if errors.Is(err, sql.ErrNoRows) {
+				var exists bool
+				if existsErr := tx.QueryRowContext(ctx, `
+					SELECT EXISTS(SELECT 1 FROM moderation_items WHERE id = $1)
+				`, decision.ModerationItemID).Scan(&exists); existsErr != nil {
+					return nil, fmt.Errorf("check moderation item existence: %w", existsErr)
+				}
+				if !exists {
+					return nil, fmt.Errorf("moderation item %d: %w", decision.ModerationItemID, ErrModerationItemNotFound)
+				}
+				return nil, fmt.Errorf("moderation item %d: %w", decision.ModerationItemID, ErrModerationItemNotPending)
+			}
+			return nil, fmt.Errorf("apply batch moderation decision: %w", err)
+		}
+		item.Status = model.ModerationStatus(strings.ToUpper(strings.TrimSpace(itemStatus)))
+
+		_, err = tx.ExecContext(ctx, `
+			INSERT INTO profiles (user_id, display_name, moderation_status, approved, updated_at)
+			VALUES ($1, '', $2, $3, NOW())
+			ON CONFLICT (user_id) DO UPDATE SET
+				moderation_status = EXCLUDED.moderation_status,
+				approved = EXCLUDED.approved,
+				updated_at = NOW()
+		`, item.UserID, status, status == "APPROVED")
+		if err != nil {
+			return nil, fmt.Errorf("update profile moderation status for batch: %w", err)
+		}
+
+		verification := ""
+		switch {
+		case decision.Decision == model.ModerationBatchApproveVerified:
+			verification = "VERIFIED"
+		case decision.Decision == model.ModerationBatchReject && isCircleRejectReason(decision.ReasonCode):
+			verification = "REJECTED"
+		}
+		if verification != "" {
+			_, err = tx.ExecContext(ctx, `
+				UPDATE profiles
+				SET verification_status = $2,
+				    verified_at = CASE WHEN $2 = 'VERIFIED' THEN NOW() ELSE NULL END,
+				    updated_at = NOW()
+				WHERE user_id = $1
+			`, item.UserID, verification)
+			if err != nil {
+				return nil, fmt.Errorf("update profile verification for batch: %w", err)
+			}
+		} else if decision.Decision == model.ModerationBatchReject {
+			if _, err := tx.ExecContext(ctx, restorePendingVerificationQuery, item.UserID); err != nil {
+				return nil, fmt.Errorf("restore profile verification for batch: %w", err)
+			}
+		}
+		out = append(out, item)
+	}
+
+	// Deciding part of a batch counts as activity on the rest, so the idle reassigner leaves it alone.
+	if _, err := tx.ExecContext(ctx, `
+		UPDATE moderation_items
+		SET locked_at = NOW(), updated_at = NOW()
+		WHERE locked_by_tg_id = $1
+		  AND locked_until > NOW()
+		  AND UPPER(status) = 'PENDING'
+	`, actorTGID); err != nil {
+		return nil, fmt.Errorf("touch held moderation locks: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("commit transaction for moderation batch: %w", err)
+	}
+	return out, nil
+}
+
+func isCircleRejectReason(reasonCode string) bool {
+	switch strings.ToUpper(strings.TrimSpace(reasonCode)) {
+	case "CIRCLE_MISMATCH", "CIRCLE_FAILED":
+		return true
+	default:
+		return false
+	}
+}
+
+func scanModerationItems(rows *sql.Rows) ([]model.ModerationItem, error) {
+	defer rows.Close()
+
+	items := make([]model.ModerationItem, 0, model.MaxModerationBatchSize)
+	for rows.Next() {
+		item := model.ModerationItem{}
+		var status string
+		if err := rows.Scan(
+			&item.ID,
+			&item.UserID,
+			&status,
+			&item.ETABucket,
+			&item.CreatedAt,
+			&item.LockedAt,
+			&item.LockedUntil,
+			&item.UpdatedAt,
+			&item.TargetType,
+			&item.TargetID,
+			&item.ModeratorTGID,
+			&item.LockedByTGID,
+		); err != nil {
+			return nil, fmt.Errorf("scan moderation item: %w", err)
+		}
+		item.Status = model.ModerationStatus(strings.ToUpper(strings.TrimSpace(status)))
+		items = append(items, item)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterate moderation items: %w", err)
+	}
+	return items, nil
+}
@@ -8,6 +8,7 @@ import (
 	"strings"
 	"time"
 
+	"bot_moderator/internal/domain/enums"
 	"bot_moderator/internal/domain/model"
 	"github.com/lib/pq"
 )
@@ -15,6 +16,7 @@ import (
 var ErrModerationQueueEmpty = errors.New("moderation queue is empty")
 var ErrModerationItemNotFound = errors.New("moderation item not found")
 var ErrModerationItemNotPending = errors.New("moderation item is not pending")
+var ErrModerationItemLocked = errors.New("moderation item is locked by another moderator")
 
 type ModerationRepo struct {
 	db *sql.DB
@@ -24,6 +26,9 @@ func NewModerationRepo(db *sql.DB) *ModerationRepo {
 	return &ModerationRepo{db: db}
 }
 
+// AcquireNextPending hands the actor their next item. A card auto-assigned to the actor, or
+// one they already hold once they are at their cap, is resumed; otherwise the highest
+// priority unlocked item matching the actor's skills is locked.
 func (r *ModerationRepo) AcquireNextPending(ctx context.Context, actorTGID int64, lockDuration time.Duration) (model.ModerationItem, error) {
 	if r.db == nil {
 		return model.ModerationItem{}, ErrModerationQueueEmpty
@@ -43,40 +48,75 @@ func (r *ModerationRepo) AcquireNextPending(ctx context.Context, actorTGID int64
 		_ = tx.Rollback()
 	}()
 
-	item := model.ModerationItem{}
-	var status string
 	var intervalSeconds int64 = int64(lockDuration / time.Second)
 
+	maxActive := model.DefaultModeratorMaxActiveItems
+	skills := moderatorSkillStrings(enums.ModeratorSkills)
 	err = tx.QueryRowContext(ctx, `
-		WITH candidate AS (
-			SELECT id
+		SELECT max_active_items, skills
+		FROM moderator_workload
+		WHERE moderator_tg_id = $1
+	`, actorTGID).Scan(&maxActive, pq.Array(&skills))
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		return model.ModerationItem{}, fmt.Errorf("load moderator workload: %w", err)
+	}
+
+	var heldID int64
+	var heldAssigned bool
+	var heldCount int
+	err = tx.QueryRowContext(ctx, `
+		WITH held AS (
+			SELECT id, assigned_at, locked_at
 			FROM moderation_items
-			WHERE UPPER(status) = 'PENDING'
-			  AND (locked_until IS NULL OR locked_until < NOW())
-			ORDER BY created_at ASC, id ASC
-			FOR UPDATE SKIP LOCKED
-			LIMIT 1
+			WHERE locked_by_tg_id = $1
+			  AND locked_until > NOW()
+			  AND UPPER(status) = 'PENDING'
+			FOR UPDATE
 		)
-		UPDATE moderation_items mi
-		SET locked_by_tg_id = $1,
-		    locked_at = NOW(),
-		    locked_until = NOW() + make_interval(secs => $2),
-		    updated_at = NOW()
-		FROM candidate
-		WHERE mi.id = candidate.id
-		RETURNING mi.id,
-		          mi.user_id,
-		          mi.status,
-		          mi.eta_bucket,
-		          mi.created_at,
-		          mi.locked_at,
-		          mi.locked_until,
-		          mi.updated_at,
-		          mi.target_type,
-		          mi.target_id,
-		          mi.moderator_tg_id,
-		          mi.locked_by_tg_id
-	`, actorTGID, intervalSeconds).Scan(
+		SELECT id, assigned_at IS NOT NULL, (SELECT COUNT(*) FROM held)
+		FROM held
+		ORDER BY assigned_at IS NOT NULL DESC, locked_at ASC, id ASC
+		LIMIT 1
+	`, actorTGID).Scan(&heldID, &heldAssigned, &heldCount)
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		return model.ModerationItem{}, fmt.Errorf("load held moderation locks: %w", err)
+	}
+
+	var row *sql.Row
+	if heldID > 0 && (heldAssigned || heldCount >= maxActive) {
+		row = tx.QueryRowContext(ctx, `
+			UPDATE moderation_items
+			SET locked_at = CASE WHEN assigned_at IS NOT NULL THEN NOW() ELSE locked_at END,
+			    locked_until = NOW() + make_interval(secs => $2),
+			    assigned_at = NULL,
+			    updated_at = NOW()
+			WHERE id = $1
+			RETURNING `+moderationItemReturningColumns,
+			heldID, intervalSeconds)
+	} else {
+		row = tx.QueryRowContext(ctx, `
+			WITH candidate AS (`+moderationQueueCandidatesSQL+`), expired AS (
+				INSERT INTO moderation_lock_expiries (moderation_item_id, moderator_tg_id, locked_at, expired_at)
+				SELECT id, locked_by_tg_id, locked_at, locked_until
+				FROM candidate
+				WHERE locked_by_tg_id IS NOT NULL
+				  AND locked_until IS NOT NULL
+			)
+			UPDATE moderation_items mi
+			SET locked_by_tg_id = $1,
+			    locked_at = NOW(),
+			    locked_until = NOW() + make_interval(secs => $2),
+			    assigned_at = NULL,
+			    updated_at = NOW()
+			FROM candidate
+			WHERE mi.id = candidate.id
+			RETURNING `+prefixedModerationItemReturningColumns,
+			actorTGID, intervalSeconds, pq.Array(skills), int64(moderationQueueAgingThreshold/time.Second), 1)
+	}
+
+	item := model.ModerationItem{}
+	var status string
+	err = row.Scan(
 		&item.ID,
 		&item.UserID,
 		&status,
@@ -106,6 +146,10 @@ func (r *ModerationRepo) AcquireNextPending(ctx context.Context, actorTGID int64
 	return item, nil
 }
 
+const moderationItemReturningColumns = `id, user_id, status, eta_bucket, created_at, locked_at, locked_until, updated_at, target_type, target_id, moderator_tg_id, locked_by_tg_id`
+
+const prefixedModerationItemReturningColumns = `mi.id, mi.user_id, mi.status, mi.eta_bucket, mi.created_at, mi.locked_at, mi.locked_until, mi.updated_at, mi.target_type, mi.target_id, mi.moderator_tg_id, mi.locked_by_tg_id`
+
 func (r *ModerationRepo) GetByID(ctx context.Context, moderationItemID int64) (model.ModerationItem, error) {
 	if r.db == nil {
 		return model.ModerationItem{}, ErrModerationItemNotFound
@@ -160,6 +204,7 @@ func (r *ModerationRepo) GetByID(ctx context.Context, moderationItemID int64) (m
 func (r *ModerationRepo) MarkRejected(
 	ctx context.Context,
 	moderationItemID int64,
+	actorTGID int64,
 	reasonCode string,
 	reasonText string,
 	requiredFixStep string,
@@ -171,35 +216,85 @@ func (r *ModerationRepo) MarkRejected(
 		return fmt.Errorf("invalid moderation item id")
 	}
 
-	result, err := r.db.ExecContext(ctx, `
+	var userID int64
+	err := r.db.QueryRowContext(ctx, `
 		UPDATE moderation_items
 		SET status = 'REJECTED',
 		    reason_code = $2,
 		    reason_text = $3,
 		    required_fix_step = $4,
 		    decided_at = NOW(),
+		    review_started_at = locked_at,
+		    moderator_tg_id = $5,
 		    locked_by_tg_id = NULL,
 		    locked_until = NOW(),
 		    updated_at = NOW()
 		WHERE id = $1
 		  AND UPPER(status) = 'PENDING'
-	`, moderationItemID, strings.TrimSpace(reasonCode), strings.TrimSpace(reasonText), strings.TrimSpace(requiredFixStep))
+		  AND (locked_by_tg_id IS NULL OR locked_by_tg_id = $5 OR locked_until < NOW())
+		RETURNING user_id
+	`, moderationItemID, strings.TrimSpace(reasonCode), strings.TrimSpace(reasonText), strings.TrimSpace(requiredFixStep), actorTGID).Scan(&userID)
 	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return decisionConflict(ctx, r.db, moderationItemID)
+		}
 		return fmt.Errorf("mark moderation item rejected: %w", err)
 	}
 
-	affected, err := result.RowsAffected()
-	if err != nil {
-		return fmt.Errorf("rows affected for reject moderation item: %w", err)
-	}
-	if affected == 0 {
-		return ErrModerationItemNotPending
+	if isCircleRejectReason(reasonCode) {
+		if _, err := r.db.ExecContext(ctx, `
+			UPDATE profiles
+			SET verification_status = 'REJECTED',
+			    verified_at = NULL,
+			    updated_at = NOW()
+			WHERE user_id = $1
+		`, userID); err != nil {
+			return fmt.Errorf("update profile verification on reject: %w", err)
+		}
+	} else if _, err := r.db.ExecContext(ctx, restorePendingVerificationQuery, userID); err != nil {
+		return fmt.Errorf("restore profile verification on reject: %w", err)
 	}
 
 	return nil
 }
 
-func (r *ModerationRepo) MarkApproved(ctx context.Context, moderationItemID int64) error {
+// decisionConflict explains why a guarded decision update matched no row.
+func decisionConflict(ctx context.Context, q interface {
+	QueryRowContext(context.Context, string, ...any) *sql.Row
+}, moderationItemID int64) error {
+	var pending, locked bool
+	err := q.QueryRowContext(ctx, `
+		SELECT
+			UPPER(status) = 'PENDING',
+			locked_by_tg_id IS NOT NULL AND locked_until >= NOW()
+		FROM moderation_items
+		WHERE id = $1
+	`, moderationItemID).Scan(&pending, &locked)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return ErrModerationItemNotFound
+		}
+		return fmt.Errorf("check moderation item: %w", err)
+	}
+	if pending && locked {
+		return ErrModerationItemLocked
+	}
+	return ErrModerationItemNotPending
+}
+
+// restorePendingVerificationQuery puts a PENDING profile back to the status it had before its
+// circle went to review. It runs on rejects for reasons that say nothing about the circle.
+const restorePendingVerificationQuery = `
+	UPDATE profiles
+	SET verification_status = COALESCE(verification_previous_status, 'NONE'),
+	    verified_at = CASE WHEN verification_previous_status = 'VERIFIED' THEN verified_at ELSE NULL END,
+	    verification_previous_status = NULL,
+	    updated_at = NOW()
+	WHERE user_id = $1
+	  AND verification_status = 'PENDING'
+`
+
+func (r *ModerationRepo) MarkApproved(ctx context.Context, moderationItemID int64, actorTGID int64, verified bool) error {
 	if r.db == nil {
 		return ErrModerationItemNotFound
 	}
@@ -223,25 +318,19 @@ func (r *ModerationRepo) MarkApproved(ctx context.Context, moderationItemID int6
 		    reason_text = NULL,
 		    required_fix_step = NULL,
 		    decided_at = NOW(),
+		    review_started_at = locked_at,
+		    moderator_tg_id = $2,
 		    locked_by_tg_id = NULL,
 		    locked_until = NOW(),
 		    updated_at = NOW()
 		WHERE id = $1
 		  AND UPPER(status) = 'PENDING'
+		  AND (locked_by_tg_id IS NULL OR locked_by_tg_id = $2 OR locked_until < NOW())
 		RETURNING user_id
-	`, moderationItemID).Scan(&userID)
+	`, moderationItemID, actorTGID).Scan(&userID)
 	if err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
-			var exists bool
-			if existsErr := tx.QueryRowContext(ctx, `
-				SELECT EXISTS(SELECT 1 FROM moderation_items WHERE id = $1)
-			`, moderationItemID).Scan(&exists); existsErr != nil {
-				return fmt.Errorf("check moderation item existence: %w", existsErr)
-			}
-			if !exists {
-				return ErrModerationItemNotFound
-			}
-			return ErrModerationItemNotPending
+			return decisionConflict(ctx, tx, moderationItemID)
 		}
 		return fmt.Errorf("mark moderation item approved: %w", err)
 	}
@@ -258,6 +347,19 @@ func (r *ModerationRepo) MarkApproved(ctx context.Context, moderationItemID int6
 		return fmt.Errorf("update profile moderation status on approve: %w", err)
 	}
 
+	if verified {
+		_, err = tx.ExecContext(ctx, `
+			UPDATE profiles
+			SET verification_status = 'VERIFIED',
+			    verified_at = NOW(),
+			    updated_at = NOW()
+			WHERE user_id = $1
+		`, userID)
+		if err != nil {
+			return fmt.Errorf("update profile verification on approve: %w", err)
+		}
+	}
+
 	if err := tx.Commit(); err != nil {
 		return fmt.Errorf("commit transaction for approve moderation item: %w", err)
 	}
@@ -290,6 +392,11 @@ func (r *ModerationRepo) InsertModerationAction(ctx context.Context, action mode
 		createdAt = time.Now().UTC()
 	}
 
+	var batchID interface{}
+	if trimmed := strings.TrimSpace(action.BatchID); trimmed != "" {
+		batchID = trimmed
+	}
+
 	_, err := r.db.ExecContext(ctx, `
 		INSERT INTO bot_moderation_actions (
 			actor_tg_id,
@@ -299,8 +406,10 @@ func (r *ModerationRepo) InsertModerationAction(ctx context.Context, action mode
 			decision,
 			reason_code,
 			duration_sec,
+			batch_id,
+			bulk,
 			created_at
-		) VALUES ($1, $2, $3::uuid, $4::uuid, $5, $6, $7, $8)
+		) VALUES ($1, $2, $3::uuid, $4::uuid, $5, $6, $7, $8, $9, $10)
 	`,
 		action.ActorTGID,
 		strings.TrimSpace(action.ActorRole),
@@ -309,6 +418,8 @@ func (r *ModerationRepo) InsertModerationAction(ctx context.Context, action mode
 		strings.TrimSpace(action.Decision),
 		reasonCode,
 		duration,
+		batchID,
+		action.Bulk,
 		createdAt,
 	)
 	if err != nil {
@@ -338,7 +449,8 @@ func (r *ModerationRepo) GetProfile(ctx context.Context, userID int64) (model.Mo
 		       COALESCE(p.goals, '{}'::text[]),
 		       COALESCE(p.languages, '{}'::text[]),
 		       COALESCE(p.occupation, ''),
-		       COALESCE(p.education, '')
+		       COALESCE(p.education, ''),
+		       COALESCE(p.verification_status, 'NONE')
 		FROM users u
 		LEFT JOIN profiles p ON p.user_id = u.id
 		WHERE u.id = $1
@@ -356,6 +468,7 @@ func (r *ModerationRepo) GetProfile(ctx context.Context, userID int64) (model.Mo
 		&languages,
 		&profile.Occupation,
 		&profile.Education,
+		&profile.VerificationStatus,
 	)
 	if err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
@@ -413,6 +526,57 @@ func (r *ModerationRepo) ListPhotoKeys(ctx context.Context, userID int64, limit
 	return keys, nil
 }
 
+func (r *ModerationRepo) ListPhotoMatches(ctx context.Context, userID int64) ([]model.PhotoMatch, error) {
+	if r.db == nil {
+		return []model.PhotoMatch{}, nil
+	}
+
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT hm.media_id,
+		       m.position,
+		       hm.source,
+		       COALESCE(hm.matched_user_id, 0),
+		       COALESCE(hm.matched_media_id, 0),
+		       COALESCE(hm.banned_image_id, 0),
+		       COALESCE(b.label, ''),
+		       hm.distance
+		FROM media_hash_matches hm
+		JOIN media m ON m.id = hm.media_id
+		LEFT JOIN banned_image_hashes b ON b.id = hm.banned_image_id
+		WHERE m.user_id = $1
+		  AND m.kind = 'photo'
+		  AND m.status = 'active'
+		ORDER BY m.position ASC, hm.distance ASC, hm.id ASC
+	`, userID)
+	if err != nil {
+		return nil, fmt.Errorf("list photo matches: %w", err)
+	}
+	defer rows.Close()
+
+	matches := make([]model.PhotoMatch, 0)
+	for rows.Next() {
+		var match model.PhotoMatch
+		if err := rows.Scan(
+			&match.MediaID,
+			&match.Slot,
+			&match.Source,
+			&match.MatchedUserID,
+			&match.MatchedMediaID,
+			&match.BannedImageID,
+			&match.Label,
+			&match.Distance,
+		); err != nil {
+			return nil, fmt.Errorf("scan photo match: %w", err)
+		}
+		matches = append(matches, match)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterate photo matches: %w", err)
+	}
+
+	return matches, nil
+}
+
 func (r *ModerationRepo) GetLatestCircleKey(ctx context.Context, userID int64) (string, error) {
 	if r.db == nil {
 		return "", nil
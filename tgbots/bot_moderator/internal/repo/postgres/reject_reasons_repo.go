@@ -0,0 +1,140 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"bot_moderator/internal/domain/model"
+)
+
+var ErrRejectReasonsUnavailable = errors.New("reject reason catalog is unavailable")
+
+// RejectReasonsRepo reads the backend-owned moderation_reject_reasons catalog directly in db mode.
+type RejectReasonsRepo struct {
+	db *sql.DB
+}
+
+func NewRejectReasonsRepo(db *sql.DB) *RejectReasonsRepo {
+	return &RejectReasonsRepo{db: db}
+}
+
+func (r *RejectReasonsRepo) ListRejectReasons(ctx context.Context, includeInactive bool) ([]model.RejectReason, error) {
+	if r.db == nil {
+		return nil, ErrRejectReasonsUnavailable
+	}
+
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT code, labels, reason_text, required_fix_step, severity, is_active, sort_order, updated_by_tg_id, updated_at
+		FROM moderation_reject_reasons
+		WHERE $1 OR is_active
+		ORDER BY sort_order ASC, code ASC
+	`, includeInactive)
+	if err != nil {
+		return nil, fmt.Errorf("list reject reasons: %w", err)
+	}
+	defer rows.Close()
+
+	items := make([]model.RejectReason, 0, 16)
+	for rows.Next() {
+		item, err := scanRejectReason(rows)
+		if err != nil {
+			return nil, err
+		}
+		items = append(items, item)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterate reject reasons: %w", err)
+	}
+	return items, nil
+}
+
+func (r *RejectReasonsRepo) UpsertRejectReason(ctx context.Context, reason model.RejectReason) (model.RejectReason, error) {
+	if r.db == nil {
+		return model.RejectReason{}, ErrRejectReasonsUnavailable
+	}
+
+	labels, err := json.Marshal(reason.Labels)
+	if err != nil {
+		return model.RejectReason{}, fmt.Errorf("marshal reject reason labels: %w", err)
+	}
+
+	rows, err := r.db.QueryContext(ctx, `
+		INSERT INTO moderation_reject_reasons (
+			code, labels, reason_text, required_fix_step, severity, is_active, sort_order, updated_by_tg_id, updated_at
+		)
+		VALUES ($1, $2::jsonb, $3, $4, $5, $6, $7, $8, NOW())
+		ON CONFLICT (code) DO UPDATE SET
+			labels = EXCLUDED.labels,
+			reason_text = EXCLUDED.reason_text,
+			required_fix_step = EXCLUDED.required_fix_step,
+			severity = EXCLUDED.severity,
+			is_active = EXCLUDED.is_active,
+			sort_order = EXCLUDED.sort_order,
+			updated_by_tg_id = EXCLUDED.updated_by_tg_id,
+			updated_at = NOW()
+		RETURNING code, labels, reason_text, required_fix_step, severity, is_active, sort_order, updated_by_tg_id, updated_at
+	`,
+		strings.ToUpper(strings.TrimSpace(reason.Code)),
+		string(labels),
+		strings.TrimSpace(reason.ReasonText),
+		strings.TrimSpace(reason.RequiredFixStep),
+		strings.ToUpper(strings.TrimSpace(reason.Severity)),
+		reason.IsActive,
+		reason.SortOrder,
+		reason.UpdatedByTGID,
+	)
+	if err != nil {
+		return model.RejectReason{}, fmt.Errorf("upsert reject reason: %w", err)
+	}
+	defer rows.Close()
+
+	if !rows.Next() {
+		if err := rows.Err(); err != nil {
+			return model.RejectReason{}, fmt.Errorf("upsert reject reason: %w", err)
+		}
+		return model.RejectReason{}, fmt.Errorf("upsert reject reason: no row returned")
+	}
+	return scanRejectReason(rows)
+}
+
+func scanRejectReason(rows *sql.Rows) (model.RejectReason, error) {
+	var (
+		item          model.RejectReason
+		labels        []byte
+		updatedByTGID sql.NullInt64
+		updatedAt     time.Time
+	)
+	if err := rows.Scan(
+		&item.Code,
+		&labels,
+		&item.ReasonText,
+		&item.RequiredFixStep,
+		&item.Severity,
+		&item.IsActive,
+		&item.SortOrder,
+		&updatedByTGID,
+		&updatedAt,
+	); err != nil {
+		return model.RejectReason{}, fmt.Errorf("scan reject reason: %w", err)
+	}
+
+	item.Labels = map[string]string{}
+	if len(labels) > 0 {
+		if err := json.Unmarshal(labels, &item.Labels); err != nil {
+			return model.RejectReason{}, fmt.Errorf("decode reject reason labels: %w", err)
+		}
+	}
+	item.Label = item.LabelFor(model.RejectReasonDefaultLanguage)
+	if updatedByTGID.Valid {
+		value := updatedByTGID.Int64
+		item.UpdatedByTGID = &value
+	}
+	updatedAt = updatedAt.UTC()
+	item.UpdatedAt = &updatedAt
+	return item, nil
+}
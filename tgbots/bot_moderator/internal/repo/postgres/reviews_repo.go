@@ -0,0 +1,416 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"bot_moderator/internal/domain/model"
+)
+
+var ErrReviewQueueEmpty = errors.New("review queue is empty")
+var ErrReviewNotFound = errors.New("moderation review not found")
+var ErrReviewNotPending = errors.New("moderation review is not pending")
+var ErrReviewSameModerator = errors.New("review must be done by a different moderator")
+var ErrReviewNotLocked = errors.New("moderation review is not locked by the reviewer")
+
+// botActionItemUUID mirrors pseudoUUID for joining bot_moderation_actions on BIGINT ids.
+const botActionItemUUID = `('00000000-0000-0000-0000-' || lpad(to_hex(mr.moderation_item_id), 12, '0'))::uuid`
+
+type ReviewsRepo struct {
+	db         *sql.DB
+	moderation *ModerationRepo
+}
+
+func NewReviewsRepo(db *sql.DB, moderation *ModerationRepo) *ReviewsRepo {
+	return &ReviewsRepo{db: db, moderation: moderation}
+}
+
+func (r *ReviewsRepo) AcquireNextReview(ctx context.Context, kind string, reviewerTGID int64, lockDuration time.Duration) (model.ModerationReviewItem, error) {
+	if r.db == nil {
+		return model.ModerationReviewItem{}, ErrReviewQueueEmpty
+	}
+	if reviewerTGID == 0 {
+		return model.ModerationReviewItem{}, fmt.Errorf("invalid reviewer tg id")
+	}
+	if lockDuration <= 0 {
+		lockDuration = 10 * time.Minute
+	}
+
+	review, err := scanReview(r.db.QueryRowContext(ctx, `
+		WITH candidate AS (
+			SELECT mr.id
+			FROM moderation_reviews mr
+			JOIN moderation_items mi ON mi.id = mr.moderation_item_id
+			WHERE mr.kind = $1
+			  AND mr.status = 'PENDING'
+			  AND (mr.locked_until IS NULL OR mr.locked_until < NOW())
+			  AND COALESCE(mr.original_moderator_tg_id, 0) <> $2
+			  AND COALESCE(mi.moderator_tg_id, 0) <> $2
+			  AND NOT EXISTS (
+				SELECT 1
+				FROM bot_moderation_actions bma
+				WHERE bma.moderation_item_id = `+botActionItemUUID+`
+				  AND bma.actor_tg_id = $2
+			  )
+			ORDER BY mr.created_at ASC, mr.id ASC
+			FOR UPDATE OF mr SKIP LOCKED
+			LIMIT 1
+		)
+		UPDATE moderation_reviews mr
+		SET locked_by_tg_id = $2,
+		    locked_at = NOW(),
+		    locked_until = NOW() + make_interval(secs => $3),
+		    updated_at = NOW()
+		FROM candidate, moderation_items mi
+		WHERE mr.id = candidate.id
+		  AND mi.id = mr.moderation_item_id
+		RETURNING mr.id,
+		          mr.moderation_item_id,
+		          mr.user_id,
+		          mr.kind,
+		          mr.status,
+		          mr.original_decision,
+		          COALESCE(mr.original_reason_code, ''),
+		          mr.original_moderator_tg_id,
+		          COALESCE(mi.reason_text, ''),
+		          COALESCE(mr.appeal_message, ''),
+		          mr.locked_at,
+		          mr.created_at
+	`, strings.ToUpper(strings.TrimSpace(kind)), reviewerTGID, int64(lockDuration/time.Second)))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return model.ModerationReviewItem{}, ErrReviewQueueEmpty
+		}
+		return model.ModerationReviewItem{}, fmt.Errorf("acquire next moderation review: %w", err)
+	}
+
+	item := model.ModerationReviewItem{Review: review}
+	if r.moderation == nil {
+		item.Profile = model.ModerationProfile{UserID: review.UserID}
+		return item, nil
+	}
+
+	item.Profile, err = r.moderation.GetProfile(ctx, review.UserID)
+	if err != nil {
+		return model.ModerationReviewItem{}, err
+	}
+	item.PhotoKeys, err = r.moderation.ListPhotoKeys(ctx, review.UserID, 3)
+	if err != nil {
+		return model.ModerationReviewItem{}, err
+	}
+	item.CircleKey, err = r.moderation.GetLatestCircleKey(ctx, review.UserID)
+	if err != nil {
+		return model.ModerationReviewItem{}, err
+	}
+	return item, nil
+}
+
+func (r *ReviewsRepo) GetReview(ctx context.Context, reviewID int64) (model.ModerationReview, error) {
+	if r.db == nil {
+		return model.ModerationReview{}, ErrReviewNotFound
+	}
+	if reviewID <= 0 {
+		return model.ModerationReview{}, fmt.Errorf("invalid review id")
+	}
+
+	review, err := scanReview(r.db.QueryRowContext(ctx, `
+		SELECT `+reviewColumns+`
+		FROM moderation_reviews mr
+		JOIN moderation_items mi ON mi.id = mr.moderation_item_id
+		WHERE mr.id = $1
+	`, reviewID))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return model.ModerationReview{}, ErrReviewNotFound
+		}
+		return model.ModerationReview{}, fmt.Errorf("get moderation review: %w", err)
+	}
+	return review, nil
+}
+
+func (r *ReviewsRepo) ResolveReview(ctx context.Context, decision model.ModerationReviewDecision) (model.ModerationReview, error) {
+	if r.db == nil {
+		return model.ModerationReview{}, ErrReviewNotFound
+	}
+	if decision.ReviewID <= 0 || decision.ReviewerTGID == 0 {
+		return model.ModerationReview{}, fmt.Errorf("invalid moderation review decision")
+	}
+
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return model.ModerationReview{}, fmt.Errorf("begin transaction for resolve review: %w", err)
+	}
+	defer func() {
+		_ = tx.Rollback()
+	}()
+
+	var sameModerator bool
+	review, err := scanReview(tx.QueryRowContext(ctx, `
+		SELECT `+reviewColumns+`
+		FROM moderation_reviews mr
+		JOIN moderation_items mi ON mi.id = mr.moderation_item_id
+		WHERE mr.id = $1
+		FOR UPDATE OF mr, mi
+	`, decision.ReviewID))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return model.ModerationReview{}, ErrReviewNotFound
+		}
+		return model.ModerationReview{}, fmt.Errorf("load moderation review: %w", err)
+	}
+	if review.Status != model.ModerationReviewStatusPending {
+		return model.ModerationReview{}, ErrReviewNotPending
+	}
+
+	err = tx.QueryRowContext(ctx, `
+		SELECT COALESCE(mr.original_moderator_tg_id, 0) = $2
+		    OR COALESCE(mi.moderator_tg_id, 0) = $2
+		    OR EXISTS (
+				SELECT 1
+				FROM bot_moderation_actions bma
+				WHERE bma.moderation_item_id = `+botActionItemUUID+`
+				  AND bma.actor_tg_id = $2
+		    )
+		FROM moderation_reviews mr
+		JOIN moderation_items mi ON mi.id = mr.moderation_item_id
+		WHERE mr.id = $1
+	`, decision.ReviewID, decision.ReviewerTGID).Scan(&sameModerator)
+	if err != nil {
+		return model.ModerationReview{}, fmt.Errorf("check review moderator history: %w", err)
+	}
+	if sameModerator {
+		return model.ModerationReview{}, ErrReviewSameModerator
+	}
+
+	status := model.ModerationReviewStatusUpheld
+	if decision.Outcome == model.ModerationReviewOutcomeOverturn {
+		status = model.ModerationReviewStatusOverturned
+	}
+
+	result, err := tx.ExecContext(ctx, `
+		UPDATE moderation_reviews
+		SET status = $2,
+		    reviewer_tg_id = $3,
+		    review_note = NULLIF($4, ''),
+		    locked_by_tg_id = NULL,
+		    locked_at = NULL,
+		    locked_until = NULL,
+		    decided_at = NOW(),
+		    updated_at = NOW()
+		WHERE id = $1
+		  AND locked_by_tg_id = $3
+		  AND locked_until > NOW()
+	`, review.ID, status, decision.ReviewerTGID, strings.TrimSpace(decision.Note))
+	if err != nil {
+		return model.ModerationReview{}, fmt.Errorf("resolve moderation review: %w", err)
+	}
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return model.ModerationReview{}, fmt.Errorf("rows affected for resolve review: %w", err)
+	}
+	if affected == 0 {
+		return model.ModerationReview{}, ErrReviewNotLocked
+	}
+
+	if status == model.ModerationReviewStatusOverturned {
+		if err := overrideModerationDecision(ctx, tx, review, decision); err != nil {
+			return model.ModerationReview{}, err
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return model.ModerationReview{}, fmt.Errorf("commit transaction for resolve review: %w", err)
+	}
+
+	review.Status = status
+	return review, nil
+}
+
+// overrideModerationDecision rewrites the outcome of the reviewed item. The profile follows
+// only while the item is the user's latest, so a late override cannot undo a newer decision.
+func overrideModerationDecision(ctx context.Context, tx *sql.Tx, review model.ModerationReview, decision model.ModerationReviewDecision) error {
+	var (
+		status       string
+		reasonCode   string
+		rejectCircle bool
+	)
+	switch review.Kind {
+	case model.ModerationReviewKindAppeal:
+		status = "APPROVED"
+	case model.ModerationReviewKindQA:
+		status = "REJECTED"
+		reasonCode = strings.ToUpper(strings.TrimSpace(decision.ReasonCode))
+		rejectCircle = isCircleRejectReason(reasonCode)
+	default:
+		return fmt.Errorf("unsupported review kind %q", review.Kind)
+	}
+
+	result, err := tx.ExecContext(ctx, `
+		UPDATE moderation_items
+		SET status = $3,
+		    reason_code = NULLIF($4, ''),
+		    reason_text = NULLIF($5, ''),
+		    required_fix_step = NULLIF($6, ''),
+		    moderator_tg_id = $2,
+		    updated_at = NOW()
+		WHERE id = $1
+		  AND UPPER(status) IN ('APPROVED', 'REJECTED')
+	`, review.ModerationItemID, decision.ReviewerTGID, status, reasonCode,
+		strings.TrimSpace(decision.ReasonText),
+		strings.TrimSpace(decision.RequiredFixStep),
+	)
+	if err != nil {
+		return fmt.Errorf("override moderation decision: %w", err)
+	}
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("rows affected for override moderation decision: %w", err)
+	}
+	if affected == 0 {
+		return ErrReviewNotPending
+	}
+
+	var superseded bool
+	if err := tx.QueryRowContext(ctx, `
+		SELECT EXISTS (
+			SELECT 1
+			FROM moderation_items
+			WHERE user_id = $1
+			  AND id > $2
+		)
+	`, review.UserID, review.ModerationItemID).Scan(&superseded); err != nil {
+		return fmt.Errorf("check newer moderation items: %w", err)
+	}
+	if superseded {
+		return nil
+	}
+
+	verificationUpdate := ""
+	if rejectCircle {
+		verificationUpdate = `, verification_status = 'REJECTED', verified_at = NULL`
+	}
+	if _, err := tx.ExecContext(ctx, `
+		UPDATE profiles
+		SET moderation_status = $2,
+		    approved = $3,
+		    updated_at = NOW()`+verificationUpdate+`
+		WHERE user_id = $1
+	`, review.UserID, status, status == "APPROVED"); err != nil {
+		return fmt.Errorf("update profile on review overturn: %w", err)
+	}
+	return nil
+}
+
+func (r *ReviewsRepo) ReviewStats(ctx context.Context, window time.Duration) (model.ModerationReviewStats, error) {
+	if window <= 0 {
+		window = 24 * time.Hour
+	}
+	stats := model.ModerationReviewStats{
+		WindowHours: int(window / time.Hour),
+		Kinds:       []model.ModerationReviewKindStats{},
+		Moderators:  []model.ModerationReviewAgreement{},
+	}
+	if r.db == nil {
+		return stats, nil
+	}
+	since := time.Now().UTC().Add(-window)
+
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT kind,
+		       COUNT(*) FILTER (WHERE status = 'PENDING'),
+		       COUNT(*) FILTER (WHERE status <> 'PENDING' AND decided_at >= $1),
+		       COUNT(*) FILTER (WHERE status = 'UPHELD' AND decided_at >= $1),
+		       COUNT(*) FILTER (WHERE status = 'OVERTURNED' AND decided_at >= $1)
+		FROM moderation_reviews
+		GROUP BY kind
+		ORDER BY kind ASC
+	`, since)
+	if err != nil {
+		return model.ModerationReviewStats{}, fmt.Errorf("query review kind stats: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		item := model.ModerationReviewKindStats{}
+		if err := rows.Scan(&item.Kind, &item.Pending, &item.Decided, &item.Upheld, &item.Overturned); err != nil {
+			return model.ModerationReviewStats{}, fmt.Errorf("scan review kind stats: %w", err)
+		}
+		item.AgreementRate = shareOf(item.Upheld, item.Decided)
+		stats.Kinds = append(stats.Kinds, item)
+	}
+	if err := rows.Err(); err != nil {
+		return model.ModerationReviewStats{}, fmt.Errorf("iterate review kind stats: %w", err)
+	}
+
+	moderatorRows, err := r.db.QueryContext(ctx, `
+		SELECT original_moderator_tg_id,
+		       COUNT(*),
+		       COUNT(*) FILTER (WHERE status = 'UPHELD'),
+		       COUNT(*) FILTER (WHERE status = 'OVERTURNED')
+		FROM moderation_reviews
+		WHERE status <> 'PENDING'
+		  AND decided_at >= $1
+		  AND original_moderator_tg_id IS NOT NULL
+		GROUP BY original_moderator_tg_id
+		ORDER BY COUNT(*) DESC, original_moderator_tg_id ASC
+	`, since)
+	if err != nil {
+		return model.ModerationReviewStats{}, fmt.Errorf("query review moderator stats: %w", err)
+	}
+	defer moderatorRows.Close()
+
+	for moderatorRows.Next() {
+		item := model.ModerationReviewAgreement{}
+		if err := moderatorRows.Scan(&item.ModeratorTGID, &item.Reviewed, &item.Upheld, &item.Overturned); err != nil {
+			return model.ModerationReviewStats{}, fmt.Errorf("scan review moderator stats: %w", err)
+		}
+		item.AgreementRate = shareOf(item.Upheld, item.Reviewed)
+		stats.Moderators = append(stats.Moderators, item)
+	}
+	if err := moderatorRows.Err(); err != nil {
+		return model.ModerationReviewStats{}, fmt.Errorf("iterate review moderator stats: %w", err)
+	}
+
+	return stats, nil
+}
+
+const reviewColumns = `mr.id,
+		       mr.moderation_item_id,
+		       mr.user_id,
+		       mr.kind,
+		       mr.status,
+		       mr.original_decision,
+		       COALESCE(mr.original_reason_code, ''),
+		       mr.original_moderator_tg_id,
+		       COALESCE(mi.reason_text, ''),
+		       COALESCE(mr.appeal_message, ''),
+		       mr.locked_at,
+		       mr.created_at`
+
+func scanReview(row *sql.Row) (model.ModerationReview, error) {
+	review := model.ModerationReview{}
+	err := row.Scan(
+		&review.ID,
+		&review.ModerationItemID,
+		&review.UserID,
+		&review.Kind,
+		&review.Status,
+		&review.OriginalDecision,
+		&review.OriginalReasonCode,
+		&review.OriginalModeratorTGID,
+		&review.ReasonText,
+		&review.AppealMessage,
+		&review.LockedAt,
+		&review.CreatedAt,
+	)
+	if err != nil {
+		return model.ModerationReview{}, err
+	}
+	review.Kind = strings.ToUpper(strings.TrimSpace(review.Kind))
+	review.Status = strings.ToUpper(strings.TrimSpace(review.Status))
+	return review, nil
+}
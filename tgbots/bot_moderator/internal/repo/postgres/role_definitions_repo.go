@@ -0,0 +1,123 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/lib/pq"
+
+	"bot_moderator/internal/domain/enums"
+	"bot_moderator/internal/domain/model"
+)
+
+var ErrRoleDefinitionsUnavailable = errors.New("role definitions are unavailable")
+
+// RoleDefinitionsRepo reads the backend-owned admin_roles table directly in db mode.
+type RoleDefinitionsRepo struct {
+	db *sql.DB
+}
+
+func NewRoleDefinitionsRepo(db *sql.DB) *RoleDefinitionsRepo {
+	return &RoleDefinitionsRepo{db: db}
+}
+
+func (r *RoleDefinitionsRepo) ListRoleDefinitions(ctx context.Context) ([]model.RoleDefinition, error) {
+	if r.db == nil {
+		return nil, ErrRoleDefinitionsUnavailable
+	}
+
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT name, permissions, is_system, updated_by_tg_id, updated_at
+		FROM admin_roles
+		ORDER BY is_system DESC, name ASC
+	`)
+	if err != nil {
+		return nil, fmt.Errorf("list role definitions: %w", err)
+	}
+	defer rows.Close()
+
+	items := make([]model.RoleDefinition, 0, 8)
+	for rows.Next() {
+		item, err := scanRoleDefinition(rows)
+		if err != nil {
+			return nil, err
+		}
+		items = append(items, item)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterate role definitions: %w", err)
+	}
+	return items, nil
+}
+
+// UpsertRoleDefinition leaves system roles untouched and reports saved=false for them.
+func (r *RoleDefinitionsRepo) UpsertRoleDefinition(ctx context.Context, def model.RoleDefinition) (model.RoleDefinition, bool, error) {
+	if r.db == nil {
+		return model.RoleDefinition{}, false, ErrRoleDefinitionsUnavailable
+	}
+
+	permissions := make([]string, 0, len(def.Permissions))
+	for _, permission := range def.Permissions {
+		permissions = append(permissions, string(permission))
+	}
+
+	rows, err := r.db.QueryContext(ctx, `
+		INSERT INTO admin_roles (name, permissions, is_system, updated_by_tg_id, updated_at)
+		VALUES ($1, $2::text[], FALSE, $3, NOW())
+		ON CONFLICT (name) DO UPDATE SET
+			permissions = EXCLUDED.permissions,
+			updated_by_tg_id = EXCLUDED.updated_by_tg_id,
+			updated_at = NOW()
+		WHERE admin_roles.is_system = FALSE
+		RETURNING name, permissions, is_system, updated_by_tg_id, updated_at
+	`, string(def.Name), pq.Array(permissions), def.UpdatedByTGID)
+	if err != nil {
+		return model.RoleDefinition{}, false, fmt.Errorf("upsert role definition: %w", err)
+	}
+	defer rows.Close()
+
+	if !rows.Next() {
+		if err := rows.Err(); err != nil {
+			return model.RoleDefinition{}, false, fmt.Errorf("upsert role definition: %w", err)
+		}
+		return model.RoleDefinition{}, false, nil
+	}
+	item, err := scanRoleDefinition(rows)
+	if err != nil {
+		return model.RoleDefinition{}, false, err
+	}
+	return item, true, nil
+}
+
+func scanRoleDefinition(rows *sql.Rows) (model.RoleDefinition, error) {
+	var (
+		name        string
+		permissions pq.StringArray
+		updatedBy   sql.NullInt64
+		updatedAt   time.Time
+		item        model.RoleDefinition
+	)
+	if err := rows.Scan(&name, &permissions, &item.IsSystem, &updatedBy, &updatedAt); err != nil {
+		return model.RoleDefinition{}, fmt.Errorf("scan role definition: %w", err)
+	}
+
+	item.Name = enums.NormalizeRole(name)
+	item.Permissions = make([]enums.Permission, 0, len(permissions))
+	for _, raw := range permissions {
+		if permission, ok := enums.ParsePermission(raw); ok {
+			item.Permissions = append(item.Permissions, permission)
+		}
+	}
+	if updatedBy.Valid {
+		value := updatedBy.Int64
+		item.UpdatedByTGID = &value
+	}
+	if !updatedAt.IsZero() {
+		value := updatedAt.UTC()
+		item.UpdatedAt = &value
+	}
+	return item, nil
+}
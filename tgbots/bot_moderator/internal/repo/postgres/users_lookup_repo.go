@@ -16,6 +16,37 @@ import (
 
 var ErrLookupUserNotFound = errors.New("lookup user not found")
 
+// linkedAccountsCTE yields (linked_user_id, signal) for accounts sharing a device, phone or photo with $1.
+const linkedAccountsCTE = `
+		WITH linked AS (
+			SELECT other.user_id AS linked_user_id, 'DEVICE' AS signal
+			FROM user_devices mine
+			JOIN user_devices other ON other.device_id = mine.device_id AND other.user_id <> mine.user_id
+			WHERE mine.user_id = $1
+			UNION
+			SELECT other.user_id, 'PHONE'
+			FROM user_private mine
+			JOIN user_private other ON other.phone_hash = mine.phone_hash AND other.user_id <> mine.user_id
+			WHERE mine.user_id = $1
+			  AND mine.phone_hash <> ''
+			UNION
+			SELECT hm.matched_user_id, 'PHOTO'
+			FROM media_hash_matches hm
+			JOIN media m ON m.id = hm.media_id
+			WHERE m.user_id = $1
+			  AND hm.source = 'USER'
+			  AND hm.matched_user_id IS NOT NULL
+			  AND hm.matched_user_id <> $1
+			UNION
+			SELECT m.user_id, 'PHOTO'
+			FROM media_hash_matches hm
+			JOIN media m ON m.id = hm.media_id
+			WHERE hm.matched_user_id = $1
+			  AND hm.source = 'USER'
+			  AND m.user_id <> $1
+		)
+`
+
 type UsersLookupRepo struct {
 	db *sql.DB
 }
@@ -328,12 +359,19 @@ func (r *UsersLookupRepo) findUserBase(ctx context.Context, query string, args .
 	}
 	user.CircleKey = circleKey
 
-	banned, reason, err := r.getBanState(ctx, user.UserID)
+	ban, err := queryBanState(ctx, r.db, user.UserID)
+	if err != nil {
+		return model.LookupUser{}, err
+	}
+	user.IsBanned = ban.Banned
+	user.BanReason = ban.Reason
+	user.BanReasonCode = ban.ReasonCode
+	user.BanExpiresAt = ban.ExpiresAt
+
+	user.EvasionMatches, err = r.findBanEvasion(ctx, user.UserID)
 	if err != nil {
 		return model.LookupUser{}, err
 	}
-	user.IsBanned = banned
-	user.BanReason = reason
 
 	return user, nil
 }
@@ -388,20 +426,49 @@ func (r *UsersLookupRepo) getLatestCircleKey(ctx context.Context, userID int64)
 	return key, nil
 }
 
-func (r *UsersLookupRepo) getBanState(ctx context.Context, userID int64) (bool, string, error) {
-	var banned bool
-	var reason sql.NullString
-	err := r.db.QueryRowContext(ctx, `
-		SELECT banned, reason
-		FROM user_bans
-		WHERE user_id = $1::uuid
-		LIMIT 1
-	`, pseudoUUID(userID)).Scan(&banned, &reason)
+// findBanEvasion mirrors the backend users service: older, currently banned accounts that
+// share a device, phone hash or near-duplicate photo with userID.
+func (r *UsersLookupRepo) findBanEvasion(ctx context.Context, userID int64) ([]model.EvasionMatch, error) {
+	rows, err := r.db.QueryContext(ctx, linkedAccountsCTE+`
+		SELECT l.linked_user_id, banned_user.telegram_id, l.signal
+		FROM linked l
+		JOIN users banned_user ON banned_user.id = l.linked_user_id
+		JOIN users suspect ON suspect.id = $1
+		JOIN user_bans ub ON ub.user_id = (
+			'00000000-0000-0000-0000-' ||
+			LPAD(TO_HEX((l.linked_user_id & 281474976710655)::bigint), 12, '0')
+		)::uuid
+		WHERE banned_user.created_at < suspect.created_at
+		  AND ub.banned = TRUE
+		  AND (ub.expires_at IS NULL OR ub.expires_at > NOW())
+		ORDER BY l.linked_user_id ASC, l.signal ASC
+	`, userID)
 	if err != nil {
-		if errors.Is(err, sql.ErrNoRows) {
-			return false, "", nil
+		return nil, fmt.Errorf("find ban evasion links: %w", err)
+	}
+	defer rows.Close()
+
+	items := make([]model.EvasionMatch, 0)
+	index := make(map[int64]int)
+	for rows.Next() {
+		var (
+			bannedUserID int64
+			bannedTGID   int64
+			signal       string
+		)
+		if err := rows.Scan(&bannedUserID, &bannedTGID, &signal); err != nil {
+			return nil, fmt.Errorf("scan ban evasion link: %w", err)
+		}
+		pos, ok := index[bannedUserID]
+		if !ok {
+			pos = len(items)
+			index[bannedUserID] = pos
+			items = append(items, model.EvasionMatch{BannedUserID: bannedUserID, BannedTGID: bannedTGID})
 		}
-		return false, "", fmt.Errorf("lookup ban state: %w", err)
+		items[pos].Signals = append(items[pos].Signals, signal)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterate ban evasion links: %w", err)
 	}
-	return banned, strings.TrimSpace(reason.String), nil
+	return items, nil
 }
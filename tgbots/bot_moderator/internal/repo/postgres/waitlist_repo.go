@@ -0,0 +1,140 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"bot_moderator/internal/domain/model"
+)
+
+var ErrWaitlistUnavailable = errors.New("registration waitlist is unavailable")
+
+const waitlistTopBuckets = 10
+
+// WaitlistRepo reads and admits from the backend-owned registration_waitlist table in db mode.
+// Admission mirrors the backend query so both modes pick the same users.
+type WaitlistRepo struct {
+	db *sql.DB
+}
+
+func NewWaitlistRepo(db *sql.DB) *WaitlistRepo {
+	return &WaitlistRepo{db: db}
+}
+
+func (r *WaitlistRepo) WaitlistStats(ctx context.Context) (model.WaitlistStats, error) {
+	if r.db == nil {
+		return model.WaitlistStats{}, ErrWaitlistUnavailable
+	}
+
+	var out model.WaitlistStats
+	if err := r.db.QueryRowContext(ctx, `
+		SELECT
+			COUNT(*) FILTER (WHERE status = 'WAITING'),
+			COUNT(*) FILTER (WHERE status = 'ADMITTED' AND admitted_at >= date_trunc('day', NOW())),
+			COUNT(*) FILTER (WHERE status = 'ADMITTED' AND notified_at IS NULL)
+		FROM registration_waitlist
+	`).Scan(&out.Waiting, &out.AdmittedToday, &out.PendingNotify); err != nil {
+		return model.WaitlistStats{}, fmt.Errorf("load waitlist stats: %w", err)
+	}
+
+	var err error
+	out.ByCity, err = r.waitingBuckets(ctx, `COALESCE(NULLIF(p.city_id, ''), 'unknown')`)
+	if err != nil {
+		return model.WaitlistStats{}, err
+	}
+	out.ByGender, err = r.waitingBuckets(ctx, `COALESCE(NULLIF(p.gender, ''), 'unknown')`)
+	if err != nil {
+		return model.WaitlistStats{}, err
+	}
+	return out, nil
+}
+
+func (r *WaitlistRepo) AdmitWaitlist(ctx context.Context, req model.WaitlistAdmitRequest, actorTGID int64) (model.WaitlistAdmitResult, error) {
+	if r.db == nil {
+		return model.WaitlistAdmitResult{}, ErrWaitlistUnavailable
+	}
+
+	var actor interface{}
+	if actorTGID != 0 {
+		actor = actorTGID
+	}
+
+	var out model.WaitlistAdmitResult
+	if err := r.db.QueryRowContext(ctx, `
+		WITH candidates AS (
+			SELECT
+				w.user_id,
+				w.position,
+				ROW_NUMBER() OVER (PARTITION BY COALESCE(p.gender, 'unknown') ORDER BY w.position) AS rn
+			FROM registration_waitlist w
+			LEFT JOIN profiles p ON p.user_id = w.user_id
+			WHERE w.status = 'WAITING'
+			  AND ($2 = '' OR LOWER(COALESCE(p.city_id, '')) = LOWER($2))
+			  AND NOT EXISTS (
+				SELECT 1
+				FROM supply_balance_decisions sb
+				WHERE sb.throttle_admissions
+				  AND sb.city_id = TRIM(p.city_id)
+				  AND sb.over_gender = LOWER(TRIM(p.gender))
+			  )
+		),
+		picked AS (
+			SELECT user_id
+			FROM candidates
+			ORDER BY CASE WHEN $3::boolean THEN rn ELSE 0 END ASC, position ASC
+			LIMIT $1
+		),
+		admitted AS (
+			UPDATE registration_waitlist w
+			SET status = 'ADMITTED',
+				admitted_via = 'batch',
+				admitted_at = NOW(),
+				admitted_by_tg_id = $4
+			FROM picked
+			WHERE w.user_id = picked.user_id
+			  AND w.status = 'WAITING'
+			RETURNING w.user_id
+		)
+		SELECT COUNT(*) FROM admitted
+	`, req.Limit, req.CityID, req.Balance, actor).Scan(&out.Admitted); err != nil {
+		return model.WaitlistAdmitResult{}, fmt.Errorf("admit waitlist: %w", err)
+	}
+
+	if err := r.db.QueryRowContext(ctx, `
+		SELECT COUNT(*) FROM registration_waitlist WHERE status = 'WAITING'
+	`).Scan(&out.Waiting); err != nil {
+		return model.WaitlistAdmitResult{}, fmt.Errorf("count waitlist: %w", err)
+	}
+	return out, nil
+}
+
+func (r *WaitlistRepo) waitingBuckets(ctx context.Context, keyExpr string) ([]model.WaitlistBucket, error) {
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT `+keyExpr+` AS bucket, COUNT(*)
+		FROM registration_waitlist w
+		LEFT JOIN profiles p ON p.user_id = w.user_id
+		WHERE w.status = 'WAITING'
+		GROUP BY bucket
+		ORDER BY COUNT(*) DESC, bucket ASC
+		LIMIT $1
+	`, waitlistTopBuckets)
+	if err != nil {
+		return nil, fmt.Errorf("load waitlist buckets: %w", err)
+	}
+	defer rows.Close()
+
+	items := make([]model.WaitlistBucket, 0, waitlistTopBuckets)
+	for rows.Next() {
+		var item model.WaitlistBucket
+		if err := rows.Scan(&item.Key, &item.Count); err != nil {
+			return nil, fmt.Errorf("scan waitlist bucket: %w", err)
+		}
+		items = append(items, item)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterate waitlist buckets: %w", err)
+	}
+	return items, nil
+}
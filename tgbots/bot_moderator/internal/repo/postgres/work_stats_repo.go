@@ -4,6 +4,7 @@ import (
 	"context"
 	"database/sql"
 	"fmt"
+	"time"
 
 	"bot_moderator/internal/domain/model"
 	statssvc "bot_moderator/internal/services/stats"
@@ -100,3 +101,199 @@ func (r *WorkStatsRepo) Aggregate(ctx context.Context, bounds statssvc.PeriodBou
 	report.Actors = actors
 	return report, nil
 }
+
+func (r *WorkStatsRepo) SLA(ctx context.Context, window time.Duration) (model.ModerationSLAReport, error) {
+	report := model.ModerationSLAReport{
+		WindowHours:   int(window / time.Hour),
+		Moderators:    []model.ModerationSLAModerator{},
+		RejectReasons: []model.ModerationSLAReason{},
+	}
+	if r.db == nil {
+		return report, nil
+	}
+
+	since := time.Now().UTC().Add(-window)
+
+	err := r.db.QueryRowContext(ctx, `
+		SELECT
+			COUNT(*),
+			COUNT(*) FILTER (WHERE UPPER(status) = 'APPROVED'),
+			COUNT(*) FILTER (WHERE UPPER(status) = 'REJECTED'),
+			COALESCE(percentile_cont(0.5) WITHIN GROUP (ORDER BY EXTRACT(EPOCH FROM decided_at - created_at)), 0),
+			COALESCE(percentile_cont(0.9) WITHIN GROUP (ORDER BY EXTRACT(EPOCH FROM decided_at - created_at)), 0),
+			COALESCE(percentile_cont(0.99) WITHIN GROUP (ORDER BY EXTRACT(EPOCH FROM decided_at - created_at)), 0),
+			COUNT(*) FILTER (WHERE decided_at >= NOW() - INTERVAL '1 hour')
+		FROM moderation_items
+		WHERE decided_at >= $1
+	`, since).Scan(
+		&report.Decided,
+		&report.Approved,
+		&report.Rejected,
+		&report.WaitP50Sec,
+		&report.WaitP90Sec,
+		&report.WaitP99Sec,
+		&report.DecidedLastHour,
+	)
+	if err != nil {
+		return model.ModerationSLAReport{}, fmt.Errorf("aggregate moderation wait times: %w", err)
+	}
+
+	err = r.db.QueryRowContext(ctx, `
+		SELECT
+			COUNT(*),
+			COALESCE(MAX(EXTRACT(EPOCH FROM NOW() - created_at)), 0),
+			COUNT(*) FILTER (WHERE locked_by_tg_id IS NOT NULL AND locked_until < NOW())
+		FROM moderation_items
+		WHERE UPPER(status) = 'PENDING'
+	`).Scan(&report.Pending, &report.OldestPendingSec, &report.StaleLocks)
+	if err != nil {
+		return model.ModerationSLAReport{}, fmt.Errorf("aggregate pending moderation items: %w", err)
+	}
+
+	err = r.db.QueryRowContext(ctx, `
+		SELECT COUNT(*)
+		FROM moderation_lock_expiries
+		WHERE expired_at >= $1
+	`, since).Scan(&report.LockExpired)
+	if err != nil {
+		return model.ModerationSLAReport{}, fmt.Errorf("count moderation lock expiries: %w", err)
+	}
+
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT
+			actor_tg_id,
+			COUNT(*),
+			COUNT(*) FILTER (WHERE decision = 'APPROVE'),
+			COUNT(*) FILTER (WHERE decision = 'REJECT'),
+			COALESCE(AVG(duration_sec), 0),
+			COALESCE(percentile_cont(0.9) WITHIN GROUP (ORDER BY duration_sec), 0)
+		FROM bot_moderation_actions
+		WHERE created_at >= $1
+		GROUP BY actor_tg_id
+		ORDER BY COUNT(*) DESC, actor_tg_id ASC
+	`, since)
+	if err != nil {
+		return model.ModerationSLAReport{}, fmt.Errorf("aggregate moderator latency: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var item model.ModerationSLAModerator
+		if err := rows.Scan(
+			&item.ModeratorTGID,
+			&item.Decisions,
+			&item.Approved,
+			&item.Rejected,
+			&item.AvgDecisionSec,
+			&item.P90DecisionSec,
+		); err != nil {
+			return model.ModerationSLAReport{}, fmt.Errorf("scan moderator latency: %w", err)
+		}
+		report.Moderators = append(report.Moderators, item)
+	}
+	if err := rows.Err(); err != nil {
+		return model.ModerationSLAReport{}, fmt.Errorf("iterate moderator latency: %w", err)
+	}
+
+	reasonRows, err := r.db.QueryContext(ctx, `
+		SELECT COALESCE(NULLIF(UPPER(reason_code), ''), 'OTHER') AS code, COUNT(*)
+		FROM moderation_items
+		WHERE decided_at >= $1
+		  AND UPPER(status) = 'REJECTED'
+		GROUP BY code
+		ORDER BY COUNT(*) DESC, code ASC
+	`, since)
+	if err != nil {
+		return model.ModerationSLAReport{}, fmt.Errorf("aggregate reject reasons: %w", err)
+	}
+	defer reasonRows.Close()
+
+	for reasonRows.Next() {
+		var item model.ModerationSLAReason
+		if err := reasonRows.Scan(&item.ReasonCode, &item.Count); err != nil {
+			return model.ModerationSLAReport{}, fmt.Errorf("scan reject reason count: %w", err)
+		}
+		item.ShareOfReject = shareOf(item.Count, report.Rejected)
+		item.ShareOfTotal = shareOf(item.Count, report.Decided)
+		report.RejectReasons = append(report.RejectReasons, item)
+	}
+	if err := reasonRows.Err(); err != nil {
+		return model.ModerationSLAReport{}, fmt.Errorf("iterate reject reason counts: %w", err)
+	}
+
+	report.ApproveRatio = shareOf(report.Approved, report.Decided)
+	return report, nil
+}
+
+func shareOf(part, total int) float64 {
+	if total <= 0 {
+		return 0
+	}
+	return float64(part) / float64(total)
+}
+
+func (r *WorkStatsRepo) BatchThroughput(ctx context.Context, window time.Duration) (model.ModerationBatchStats, error) {
+	report := model.ModerationBatchStats{
+		WindowHours: int(window / time.Hour),
+		Moderators:  []model.ModerationBatchModerator{},
+	}
+	if r.db == nil {
+		return report, nil
+	}
+
+	since := time.Now().UTC().Add(-window)
+	rows, err := r.db.QueryContext(ctx, `
+		WITH batches AS (
+			SELECT
+				actor_tg_id,
+				batch_id,
+				COUNT(*) AS decisions,
+				COUNT(*) FILTER (WHERE bulk) AS bulk_approved,
+				EXTRACT(EPOCH FROM MAX(created_at) - MIN(created_at - make_interval(secs => COALESCE(duration_sec, 0)))) AS active_sec
+			FROM bot_moderation_actions
+			WHERE batch_id IS NOT NULL
+			  AND created_at >= $1
+			GROUP BY actor_tg_id, batch_id
+		), single AS (
+			SELECT actor_tg_id, AVG(duration_sec) AS avg_sec
+			FROM bot_moderation_actions
+			WHERE batch_id IS NULL
+			  AND created_at >= $1
+			GROUP BY actor_tg_id
+		)
+		SELECT
+			b.actor_tg_id,
+			COUNT(*),
+			SUM(b.decisions),
+			SUM(b.bulk_approved),
+			COALESCE(SUM(b.active_sec), 0),
+			COALESCE(MAX(s.avg_sec), 0)
+		FROM batches b
+		LEFT JOIN single s ON s.actor_tg_id = b.actor_tg_id
+		GROUP BY b.actor_tg_id
+		ORDER BY SUM(b.decisions) DESC, b.actor_tg_id ASC
+	`, since)
+	if err != nil {
+		return model.ModerationBatchStats{}, fmt.Errorf("aggregate batch throughput: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var item model.ModerationBatchModerator
+		if err := rows.Scan(
+			&item.ModeratorTGID,
+			&item.Batches,
+			&item.Decisions,
+			&item.BulkApproved,
+			&item.ActiveSec,
+			&item.SingleAvgDecisionSec,
+		); err != nil {
+			return model.ModerationBatchStats{}, fmt.Errorf("scan batch throughput: %w", err)
+		}
+		report.Moderators = append(report.Moderators, item)
+	}
+	if err := rows.Err(); err != nil {
+		return model.ModerationBatchStats{}, fmt.Errorf("iterate batch throughput: %w", err)
+	}
+	return report, nil
+}
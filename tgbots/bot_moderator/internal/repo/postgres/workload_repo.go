@@ -0,0 +1,532 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/lib/pq"
+
+	"bot_moderator/internal/domain/enums"
+	"bot_moderator/internal/domain/model"
+)
+
+var ErrWorkloadUnavailable = errors.New("moderator workload is unavailable")
+var ErrModeratorShiftNotFound = errors.New("moderator shift not found")
+
+// moderationQueueAgingThreshold lifts items that waited this long above every priority tier,
+// so a steady stream of re-reviews and reports cannot starve ordinary submissions.
+const moderationQueueAgingThreshold = 2 * time.Hour
+
+// moderationItemSkillSQL classifies a queue item (aliased mi, with media m and the lateral
+// rep.reported flag joined) into the skill needed to review it.
+const moderationItemSkillSQL = `CASE WHEN rep.reported THEN 'REPORTS' WHEN m.kind = 'circle' THEN 'CIRCLE' ELSE 'PHOTO' END`
+
+const moderationItemReportedSQL = `CROSS JOIN LATERAL (
+	SELECT EXISTS (
+		SELECT 1 FROM reports rp
+		WHERE rp.target_user_id = mi.user_id
+		  AND rp.created_at > NOW() - INTERVAL '7 days'
+	) AS reported
+) rep`
+
+// moderationQueueCandidatesSQL selects up to $5 unlocked pending items for skills $3 in queue
+// priority order: items older than $4 seconds, re-reviews, reported users, segments the supply
+// balance controller marks as under-represented, Plus users, then FIFO.
+const moderationQueueCandidatesSQL = `
+	SELECT mi.id, mi.locked_by_tg_id, mi.locked_at, mi.locked_until
+	FROM moderation_items mi
+	LEFT JOIN media m ON mi.target_type = 'media' AND m.id = mi.target_id
+	LEFT JOIN user_entitlements ue ON ue.user_id = mi.user_id
+	` + moderationItemReportedSQL + `
+	WHERE UPPER(mi.status) = 'PENDING'
+	  AND (mi.locked_until IS NULL OR mi.locked_until < NOW())
+	  AND (` + moderationItemSkillSQL + `) = ANY($3::TEXT[])
+	ORDER BY
+		mi.created_at < NOW() - make_interval(secs => $4) DESC,
+		(mi.target_type = 'profile' OR EXISTS (
+			SELECT 1 FROM moderation_items prev
+			WHERE prev.user_id = mi.user_id
+			  AND prev.id <> mi.id
+			  AND UPPER(prev.status) = 'REJECTED'
+		)) DESC,
+		rep.reported DESC,
+		EXISTS (
+			SELECT 1
+			FROM profiles bp
+			JOIN supply_balance_decisions sb ON sb.city_id = TRIM(bp.city_id)
+			WHERE bp.user_id = mi.user_id
+			  AND sb.prioritize_moderation
+			  AND sb.under_gender = LOWER(TRIM(bp.gender))
+		) DESC,
+		COALESCE(ue.plus_active_until > NOW(), FALSE) DESC,
+		mi.created_at ASC,
+		mi.id ASC
+	FOR UPDATE OF mi SKIP LOCKED
+	LIMIT $5
+`
+
+// moderatorShiftActiveSQL matches shifts (aliased s) that cover the current Minsk wall-clock
+// time, including the tail of yesterday's overnight shift.
+const moderatorShiftActiveSQL = `(
+	(s.weekday = EXTRACT(ISODOW FROM (NOW() AT TIME ZONE 'Europe/Minsk'))
+	 AND (NOW() AT TIME ZONE 'Europe/Minsk')::time >= s.starts_at
+	 AND ((NOW() AT TIME ZONE 'Europe/Minsk')::time < s.ends_at OR s.ends_at <= s.starts_at))
+	OR
+	(s.ends_at <= s.starts_at
+	 AND s.weekday = EXTRACT(ISODOW FROM (NOW() AT TIME ZONE 'Europe/Minsk') - INTERVAL '1 day')
+	 AND (NOW() AT TIME ZONE 'Europe/Minsk')::time < s.ends_at)
+)`
+
+const activeLocksByModeratorSQL = `(
+	SELECT COUNT(*)
+	FROM moderation_items al
+	WHERE al.locked_by_tg_id = %s
+	  AND al.locked_until > NOW()
+	  AND UPPER(al.status) = 'PENDING'
+)`
+
+const moderatorShiftColumns = `id, moderator_tg_id, weekday, to_char(starts_at, 'HH24:MI'), to_char(ends_at, 'HH24:MI'), created_by_tg_id, created_at`
+
+// WorkloadRepo reads the backend-owned moderator_workload and moderator_shifts tables in db mode.
+type WorkloadRepo struct {
+	db *sql.DB
+}
+
+func NewWorkloadRepo(db *sql.DB) *WorkloadRepo {
+	return &WorkloadRepo{db: db}
+}
+
+func (r *WorkloadRepo) Workload(ctx context.Context) (model.ModerationWorkload, error) {
+	if r.db == nil {
+		return model.ModerationWorkload{}, ErrWorkloadUnavailable
+	}
+
+	out := model.ModerationWorkload{Moderators: []model.ModeratorWorkload{}}
+	if err := r.db.QueryRowContext(ctx, `
+		SELECT COUNT(*)
+		FROM moderation_items
+		WHERE UPPER(status) = 'PENDING'
+	`).Scan(&out.Pending); err != nil {
+		return model.ModerationWorkload{}, fmt.Errorf("count pending moderation items: %w", err)
+	}
+
+	rows, err := r.db.QueryContext(ctx, `
+		WITH staff AS (
+			SELECT moderator_tg_id FROM moderator_workload
+			UNION
+			SELECT moderator_tg_id FROM moderator_shifts
+			UNION
+			SELECT locked_by_tg_id FROM moderation_items
+			WHERE locked_by_tg_id IS NOT NULL
+			  AND locked_until > NOW()
+			  AND UPPER(status) = 'PENDING'
+		)
+		SELECT
+			st.moderator_tg_id,
+			COALESCE(w.max_active_items, $1),
+			COALESCE(w.skills, $2::TEXT[]),
+			w.updated_by_tg_id,
+			w.updated_at,
+			`+fmt.Sprintf(activeLocksByModeratorSQL, "st.moderator_tg_id")+`,
+			EXISTS (
+				SELECT 1 FROM moderator_shifts s
+				WHERE s.moderator_tg_id = st.moderator_tg_id
+				  AND `+moderatorShiftActiveSQL+`
+			)
+		FROM staff st
+		LEFT JOIN moderator_workload w ON w.moderator_tg_id = st.moderator_tg_id
+		ORDER BY st.moderator_tg_id ASC
+	`, model.DefaultModeratorMaxActiveItems, pq.Array(moderatorSkillStrings(enums.ModeratorSkills)))
+	if err != nil {
+		return model.ModerationWorkload{}, fmt.Errorf("list moderator workload: %w", err)
+	}
+	defer rows.Close()
+
+	index := make(map[int64]int)
+	for rows.Next() {
+		var item model.ModeratorWorkload
+		var skills []string
+		var updatedBy sql.NullInt64
+		var updatedAt sql.NullTime
+		if err := rows.Scan(
+			&item.ModeratorTGID,
+			&item.MaxActiveItems,
+			pq.Array(&skills),
+			&updatedBy,
+			&updatedAt,
+			&item.ActiveItems,
+			&item.OnShift,
+		); err != nil {
+			return model.ModerationWorkload{}, fmt.Errorf("scan moderator workload: %w", err)
+		}
+		item.Skills = parseModeratorSkills(skills)
+		item.Shifts = []model.ModeratorShift{}
+		if updatedBy.Valid {
+			value := updatedBy.Int64
+			item.UpdatedByTGID = &value
+		}
+		if updatedAt.Valid {
+			value := updatedAt.Time.UTC()
+			item.UpdatedAt = &value
+		}
+		index[item.ModeratorTGID] = len(out.Moderators)
+		out.Moderators = append(out.Moderators, item)
+	}
+	if err := rows.Err(); err != nil {
+		return model.ModerationWorkload{}, fmt.Errorf("iterate moderator workload: %w", err)
+	}
+
+	shiftRows, err := r.db.QueryContext(ctx, `
+		SELECT `+moderatorShiftColumns+`
+		FROM moderator_shifts
+		ORDER BY moderator_tg_id ASC, weekday ASC, starts_at ASC, id ASC
+	`)
+	if err != nil {
+		return model.ModerationWorkload{}, fmt.Errorf("list moderator shifts: %w", err)
+	}
+	defer shiftRows.Close()
+
+	for shiftRows.Next() {
+		shift, err := scanModeratorShift(shiftRows)
+		if err != nil {
+			return model.ModerationWorkload{}, fmt.Errorf("scan moderator shift: %w", err)
+		}
+		if i, ok := index[shift.ModeratorTGID]; ok {
+			out.Moderators[i].Shifts = append(out.Moderators[i].Shifts, shift)
+		}
+	}
+	if err := shiftRows.Err(); err != nil {
+		return model.ModerationWorkload{}, fmt.Errorf("iterate moderator shifts: %w", err)
+	}
+	return out, nil
+}
+
+func (r *WorkloadRepo) SaveModerator(ctx context.Context, in model.ModeratorWorkload) (model.ModeratorWorkload, error) {
+	if r.db == nil {
+		return model.ModeratorWorkload{}, ErrWorkloadUnavailable
+	}
+
+	out := model.ModeratorWorkload{Shifts: []model.ModeratorShift{}}
+	var skills []string
+	var updatedBy sql.NullInt64
+	var updatedAt time.Time
+	err := r.db.QueryRowContext(ctx, `
+		INSERT INTO moderator_workload (moderator_tg_id, max_active_items, skills, updated_by_tg_id, updated_at)
+		VALUES ($1, $2, $3::TEXT[], $4, NOW())
+		ON CONFLICT (moderator_tg_id) DO UPDATE SET
+			max_active_items = EXCLUDED.max_active_items,
+			skills = EXCLUDED.skills,
+			updated_by_tg_id = EXCLUDED.updated_by_tg_id,
+			updated_at = NOW()
+		RETURNING moderator_tg_id, max_active_items, skills, updated_by_tg_id, updated_at
+	`, in.ModeratorTGID, in.MaxActiveItems, pq.Array(moderatorSkillStrings(in.Skills)), in.UpdatedByTGID).Scan(
+		&out.ModeratorTGID,
+		&out.MaxActiveItems,
+		pq.Array(&skills),
+		&updatedBy,
+		&updatedAt,
+	)
+	if err != nil {
+		return model.ModeratorWorkload{}, fmt.Errorf("upsert moderator workload: %w", err)
+	}
+	out.Skills = parseModeratorSkills(skills)
+	if updatedBy.Valid {
+		value := updatedBy.Int64
+		out.UpdatedByTGID = &value
+	}
+	updatedAt = updatedAt.UTC()
+	out.UpdatedAt = &updatedAt
+	return out, nil
+}
+
+func (r *WorkloadRepo) AddShift(ctx context.Context, in model.ModeratorShift) (model.ModeratorShift, error) {
+	if r.db == nil {
+		return model.ModeratorShift{}, ErrWorkloadUnavailable
+	}
+
+	out, err := scanModeratorShift(r.db.QueryRowContext(ctx, `
+		INSERT INTO moderator_shifts (moderator_tg_id, weekday, starts_at, ends_at, created_by_tg_id, created_at)
+		VALUES ($1, $2, $3::TIME, $4::TIME, $5, NOW())
+		RETURNING `+moderatorShiftColumns,
+		in.ModeratorTGID, in.Weekday, in.StartsAt, in.EndsAt, in.CreatedByTGID))
+	if err != nil {
+		return model.ModeratorShift{}, fmt.Errorf("insert moderator shift: %w", err)
+	}
+	return out, nil
+}
+
+func (r *WorkloadRepo) DeleteShift(ctx context.Context, shiftID int64) (model.ModeratorShift, error) {
+	if r.db == nil {
+		return model.ModeratorShift{}, ErrWorkloadUnavailable
+	}
+
+	out, err := scanModeratorShift(r.db.QueryRowContext(ctx, `
+		DELETE FROM moderator_shifts
+		WHERE id = $1
+		RETURNING `+moderatorShiftColumns,
+		shiftID))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return model.ModeratorShift{}, ErrModeratorShiftNotFound
+		}
+		return model.ModeratorShift{}, fmt.Errorf("delete moderator shift: %w", err)
+	}
+	return out, nil
+}
+
+type reassignCandidate struct {
+	tgID      int64
+	maxActive int
+	active    int
+	skills    []enums.ModeratorSkill
+}
+
+// ReassignIdleLocks moves cards whose holder sat on them longer than idle to the least loaded
+// on-shift moderator with spare capacity and the matching skill. Cards nobody can take keep
+// their lock until it expires normally.
+func (r *WorkloadRepo) ReassignIdleLocks(ctx context.Context, idle, lockDuration time.Duration) ([]model.LockReassignment, error) {
+	if r.db == nil {
+		return nil, ErrWorkloadUnavailable
+	}
+	if idle <= 0 || lockDuration <= 0 {
+		return nil, fmt.Errorf("invalid idle lock window")
+	}
+
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("begin transaction: %w", err)
+	}
+	defer func() {
+		_ = tx.Rollback()
+	}()
+
+	rows, err := tx.QueryContext(ctx, `
+		SELECT mi.id, mi.user_id, mi.locked_by_tg_id, EXTRACT(EPOCH FROM NOW() - mi.locked_at)::FLOAT8, `+moderationItemSkillSQL+`
+		FROM moderation_items mi
+		LEFT JOIN media m ON mi.target_type = 'media' AND m.id = mi.target_id
+		`+moderationItemReportedSQL+`
+		WHERE UPPER(mi.status) = 'PENDING'
+		  AND mi.locked_by_tg_id IS NOT NULL
+		  AND mi.locked_until > NOW()
+		  AND mi.locked_at < NOW() - make_interval(secs => $1)
+		ORDER BY mi.locked_at ASC, mi.id ASC
+		LIMIT 50
+		FOR UPDATE OF mi SKIP LOCKED
+	`, int64(idle/time.Second))
+	if err != nil {
+		return nil, fmt.Errorf("list idle moderation locks: %w", err)
+	}
+	idleItems := make([]model.LockReassignment, 0, 8)
+	for rows.Next() {
+		var item model.LockReassignment
+		var skill string
+		if err := rows.Scan(&item.ModerationItemID, &item.UserID, &item.FromTGID, &item.IdleSec, &skill); err != nil {
+			_ = rows.Close()
+			return nil, fmt.Errorf("scan idle moderation lock: %w", err)
+		}
+		item.Skill = enums.ModeratorSkill(skill)
+		idleItems = append(idleItems, item)
+	}
+	if err := rows.Err(); err != nil {
+		_ = rows.Close()
+		return nil, fmt.Errorf("iterate idle moderation locks: %w", err)
+	}
+	_ = rows.Close()
+	if len(idleItems) == 0 {
+		return []model.LockReassignment{}, nil
+	}
+
+	rows, err = tx.QueryContext(ctx, `
+		SELECT DISTINCT
+			s.moderator_tg_id,
+			COALESCE(w.max_active_items, $1),
+			COALESCE(w.skills, $2::TEXT[]),
+			`+fmt.Sprintf(activeLocksByModeratorSQL, "s.moderator_tg_id")+`
+		FROM moderator_shifts s
+		LEFT JOIN moderator_workload w ON w.moderator_tg_id = s.moderator_tg_id
+		WHERE `+moderatorShiftActiveSQL+`
+	`, model.DefaultModeratorMaxActiveItems, pq.Array(moderatorSkillStrings(enums.ModeratorSkills)))
+	if err != nil {
+		return nil, fmt.Errorf("list on-shift moderators: %w", err)
+	}
+	candidates := make([]*reassignCandidate, 0, 8)
+	for rows.Next() {
+		candidate := &reassignCandidate{}
+		var skills []string
+		if err := rows.Scan(&candidate.tgID, &candidate.maxActive, pq.Array(&skills), &candidate.active); err != nil {
+			_ = rows.Close()
+			return nil, fmt.Errorf("scan on-shift moderator: %w", err)
+		}
+		candidate.skills = parseModeratorSkills(skills)
+		candidates = append(candidates, candidate)
+	}
+	if err := rows.Err(); err != nil {
+		_ = rows.Close()
+		return nil, fmt.Errorf("iterate on-shift moderators: %w", err)
+	}
+	_ = rows.Close()
+
+	seconds := int64(lockDuration / time.Second)
+	moved := make([]model.LockReassignment, 0, len(idleItems))
+	for _, item := range idleItems {
+		target := pickReassignCandidate(candidates, item.FromTGID, item.Skill)
+		if target == nil {
+			continue
+		}
+
+		if _, err := tx.ExecContext(ctx, `
+			WITH expired AS (
+				INSERT INTO moderation_lock_expiries (moderation_item_id, moderator_tg_id, locked_at, expired_at)
+				SELECT id, locked_by_tg_id, locked_at, NOW()
+				FROM moderation_items
+				WHERE id = $1
+			)
+			UPDATE moderation_items
+			SET locked_by_tg_id = $2,
+			    locked_at = NOW(),
+			    locked_until = NOW() + make_interval(secs => $3),
+			    assigned_at = NOW(),
+			    updated_at = NOW()
+			WHERE id = $1
+		`, item.ModerationItemID, target.tgID, seconds); err != nil {
+			return nil, fmt.Errorf("reassign moderation item %d: %w", item.ModerationItemID, err)
+		}
+
+		target.active++
+		for _, candidate := range candidates {
+			if candidate.tgID == item.FromTGID && candidate.active > 0 {
+				candidate.active--
+			}
+		}
+		item.ToTGID = target.tgID
+		moved = append(moved, item)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("commit transaction: %w", err)
+	}
+	return moved, nil
+}
+
+func pickReassignCandidate(candidates []*reassignCandidate, holderTGID int64, skill enums.ModeratorSkill) *reassignCandidate {
+	var best *reassignCandidate
+	for _, candidate := range candidates {
+		if candidate.tgID == holderTGID || candidate.active >= candidate.maxActive || !hasModeratorSkill(candidate.skills, skill) {
+			continue
+		}
+		if best == nil || candidate.active < best.active || (candidate.active == best.active && candidate.tgID < best.tgID) {
+			best = candidate
+		}
+	}
+	return best
+}
+
+// ShiftThroughput counts decisions made inside every scheduled shift occurrence that overlaps [from, to).
+func (r *WorkloadRepo) ShiftThroughput(ctx context.Context, from, to time.Time) ([]model.ShiftThroughput, error) {
+	if r.db == nil {
+		return nil, ErrWorkloadUnavailable
+	}
+
+	rows, err := r.db.QueryContext(ctx, `
+		WITH days AS (
+			SELECT d::DATE AS day
+			FROM generate_series(
+				(($1::TIMESTAMPTZ AT TIME ZONE 'Europe/Minsk')::DATE - 1)::TIMESTAMP,
+				($2::TIMESTAMPTZ AT TIME ZONE 'Europe/Minsk')::DATE::TIMESTAMP,
+				INTERVAL '1 day'
+			) d
+		), occurrences AS (
+			SELECT
+				s.id,
+				s.moderator_tg_id,
+				(days.day + s.starts_at) AT TIME ZONE 'Europe/Minsk' AS starts_at,
+				(days.day + s.ends_at + CASE WHEN s.ends_at <= s.starts_at THEN INTERVAL '1 day' ELSE INTERVAL '0' END) AT TIME ZONE 'Europe/Minsk' AS ends_at
+			FROM moderator_shifts s
+			JOIN days ON EXTRACT(ISODOW FROM days.day) = s.weekday
+		)
+		SELECT
+			o.id,
+			o.moderator_tg_id,
+			o.starts_at,
+			o.ends_at,
+			COUNT(mi.id),
+			COUNT(mi.id) FILTER (WHERE UPPER(mi.status) = 'APPROVED'),
+			COUNT(mi.id) FILTER (WHERE UPPER(mi.status) = 'REJECTED')
+		FROM occurrences o
+		LEFT JOIN moderation_items mi
+			ON mi.moderator_tg_id = o.moderator_tg_id
+			AND mi.decided_at >= o.starts_at
+			AND mi.decided_at < o.ends_at
+		WHERE o.starts_at < $2
+		  AND o.ends_at > $1
+		GROUP BY o.id, o.moderator_tg_id, o.starts_at, o.ends_at
+		ORDER BY o.starts_at ASC, o.moderator_tg_id ASC
+	`, from.UTC(), to.UTC())
+	if err != nil {
+		return nil, fmt.Errorf("query shift throughput: %w", err)
+	}
+	defer rows.Close()
+
+	items := make([]model.ShiftThroughput, 0, 16)
+	for rows.Next() {
+		var item model.ShiftThroughput
+		if err := rows.Scan(&item.ShiftID, &item.ModeratorTGID, &item.StartsAt, &item.EndsAt, &item.Decisions, &item.Approved, &item.Rejected); err != nil {
+			return nil, fmt.Errorf("scan shift throughput: %w", err)
+		}
+		item.StartsAt = item.StartsAt.UTC()
+		item.EndsAt = item.EndsAt.UTC()
+		items = append(items, item)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterate shift throughput: %w", err)
+	}
+	return items, nil
+}
+
+type rowScanner interface {
+	Scan(dest ...any) error
+}
+
+func scanModeratorShift(row rowScanner) (model.ModeratorShift, error) {
+	var item model.ModeratorShift
+	var createdBy sql.NullInt64
+	if err := row.Scan(&item.ID, &item.ModeratorTGID, &item.Weekday, &item.StartsAt, &item.EndsAt, &createdBy, &item.CreatedAt); err != nil {
+		return model.ModeratorShift{}, err
+	}
+	if createdBy.Valid {
+		value := createdBy.Int64
+		item.CreatedByTGID = &value
+	}
+	item.CreatedAt = item.CreatedAt.UTC()
+	return item, nil
+}
+
+func moderatorSkillStrings(skills []enums.ModeratorSkill) []string {
+	out := make([]string, 0, len(skills))
+	for _, skill := range skills {
+		out = append(out, string(skill))
+	}
+	return out
+}
+
+func parseModeratorSkills(raw []string) []enums.ModeratorSkill {
+	out := make([]enums.ModeratorSkill, 0, len(raw))
+	for _, item := range raw {
+		if skill, ok := enums.ParseModeratorSkill(item); ok {
+			out = append(out, skill)
+		}
+	}
+	return out
+}
+
+func hasModeratorSkill(skills []enums.ModeratorSkill, skill enums.ModeratorSkill) bool {
+	for _, item := range skills {
+		if item == skill {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,167 @@
+package access
+
+import (
+	"context"
+	"errors"
+	"regexp"
+	"sort"
+	"sync"
+	"time"
+
+	"bot_moderator/internal/domain/enums"
+	"bot_moderator/internal/domain/model"
+)
+
+var (
+	ErrInvalidRole      = errors.New("invalid role definition")
+	ErrSystemRole       = errors.New("system roles are read-only")
+	ErrRolesUnavailable = errors.New("role definitions are not configured")
+)
+
+const roleDefinitionsCacheTTL = 30 * time.Second
+
+var roleNamePattern = regexp.MustCompile(`^[A-Z][A-Z0-9_]{1,31}$`)
+
+// defaultRoleDefinitions mirror the backend admin_roles seed and are used until the catalog loads.
+var defaultRoleDefinitions = []model.RoleDefinition{
+	{Name: enums.RoleOwner, Permissions: append([]enums.Permission(nil), enums.Permissions...), IsSystem: true},
+	{Name: enums.RoleAdmin, Permissions: []enums.Permission{
+		enums.PermissionModerationDecide,
+		enums.PermissionModerationQA,
+		enums.PermissionModerationReasons,
+		enums.PermissionUsersBan,
+		enums.PermissionUsersViewPrivate,
+		enums.PermissionAccessManage,
+	}, IsSystem: true},
+	{Name: enums.RoleSupport, Permissions: []enums.Permission{
+		enums.PermissionUsersViewPrivate,
+		enums.PermissionStatsView,
+		enums.PermissionPaymentsRefund,
+	}, IsSystem: true},
+	{Name: enums.RoleModerator, Permissions: []enums.Permission{enums.PermissionModerationDecide}, IsSystem: true},
+}
+
+type RoleDefinitionsRepo interface {
+	ListRoleDefinitions(context.Context) ([]model.RoleDefinition, error)
+	UpsertRoleDefinition(context.Context, model.RoleDefinition) (model.RoleDefinition, bool, error)
+}
+
+// roleCatalog caches role definitions briefly so roles edited in the admin panel apply without a restart.
+type roleCatalog struct {
+	repo RoleDefinitionsRepo
+
+	mu       sync.Mutex
+	items    []model.RoleDefinition
+	loadedAt time.Time
+	now      func() time.Time
+}
+
+func (c *roleCatalog) list(ctx context.Context) []model.RoleDefinition {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.items != nil && c.now().Sub(c.loadedAt) < roleDefinitionsCacheTTL {
+		return c.items
+	}
+	if c.repo == nil {
+		c.items = defaultRoleDefinitions
+		c.loadedAt = c.now()
+		return c.items
+	}
+
+	items, err := c.repo.ListRoleDefinitions(ctx)
+	if err != nil || len(items) == 0 {
+		// Keep serving the last known catalog; before the first load the seed is the safest guess.
+		if c.items == nil {
+			return defaultRoleDefinitions
+		}
+		return c.items
+	}
+	for i := range items {
+		items[i].Name = enums.NormalizeRole(string(items[i].Name))
+	}
+	sort.SliceStable(items, func(i, j int) bool {
+		if items[i].IsSystem != items[j].IsSystem {
+			return items[i].IsSystem
+		}
+		return items[i].Name < items[j].Name
+	})
+	c.items = items
+	c.loadedAt = c.now()
+	return items
+}
+
+func (c *roleCatalog) get(ctx context.Context, role enums.Role) (model.RoleDefinition, bool) {
+	// OWNER always holds every permission so a bad catalog edit can't lock the owner out.
+	if role == enums.RoleOwner {
+		return defaultRoleDefinitions[0], true
+	}
+	if role == enums.RoleNone {
+		return model.RoleDefinition{}, false
+	}
+	for _, def := range c.list(ctx) {
+		if def.Name == role {
+			return def, true
+		}
+	}
+	return model.RoleDefinition{}, false
+}
+
+func (c *roleCatalog) save(ctx context.Context, def model.RoleDefinition) (model.RoleDefinition, error) {
+	if c.repo == nil {
+		return model.RoleDefinition{}, ErrRolesUnavailable
+	}
+	saved, ok, err := c.repo.UpsertRoleDefinition(ctx, def)
+	if err != nil {
+		return model.RoleDefinition{}, err
+	}
+	if !ok {
+		return model.RoleDefinition{}, ErrSystemRole
+	}
+
+	c.mu.Lock()
+	c.items = nil
+	c.loadedAt = time.Time{}
+	c.mu.Unlock()
+	return saved, nil
+}
+
+// ValidateCustomRoleName checks a name the owner may use for a new role.
+func ValidateCustomRoleName(role enums.Role) error {
+	if !roleNamePattern.MatchString(string(role)) || role == enums.RoleNone {
+		return ErrInvalidRole
+	}
+	if isSystemRole(role) {
+		return ErrSystemRole
+	}
+	return nil
+}
+
+func isSystemRole(role enums.Role) bool {
+	for _, def := range defaultRoleDefinitions {
+		if def.Name == role {
+			return true
+		}
+	}
+	return false
+}
+
+func normalizePermissions(raw []enums.Permission) ([]enums.Permission, error) {
+	seen := make(map[enums.Permission]struct{}, len(raw))
+	result := make([]enums.Permission, 0, len(raw))
+	for _, permission := range raw {
+		if _, ok := enums.ParsePermission(string(permission)); !ok {
+			return nil, ErrInvalidRole
+		}
+		if _, ok := seen[permission]; ok {
+			continue
+		}
+		seen[permission] = struct{}{}
+		result = append(result, permission)
+	}
+	if len(result) == 0 {
+		return nil, ErrInvalidRole
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i] < result[j] })
+	return result, nil
+}
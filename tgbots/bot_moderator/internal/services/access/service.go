@@ -26,15 +26,17 @@ type Service struct {
 	ownerTGID int64
 	usersRepo UsersRepo
 	rolesRepo RolesRepo
+	roles     *roleCatalog
 }
 
 var ErrAccessDenied = errors.New("access denied")
 
-func NewService(ownerTGID int64, usersRepo UsersRepo, rolesRepo RolesRepo) *Service {
+func NewService(ownerTGID int64, usersRepo UsersRepo, rolesRepo RolesRepo, definitionsRepo RoleDefinitionsRepo) *Service {
 	return &Service{
 		ownerTGID: ownerTGID,
 		usersRepo: usersRepo,
 		rolesRepo: rolesRepo,
+		roles:     &roleCatalog{repo: definitionsRepo, now: time.Now},
 	}
 }
 
@@ -64,30 +66,81 @@ func (s *Service) ResolveRole(ctx context.Context, tgID int64) (enums.Role, erro
 	return role, nil
 }
 
-func (s *Service) CanOpenAccess(role enums.Role) bool {
-	return role == enums.RoleOwner || role == enums.RoleAdmin
+func (s *Service) RoleDefinition(ctx context.Context, role enums.Role) (model.RoleDefinition, bool) {
+	return s.roles.get(ctx, role)
 }
 
-func (s *Service) CanGrantRole(actorRole, targetRole enums.Role) bool {
-	switch actorRole {
-	case enums.RoleOwner:
-		return targetRole == enums.RoleAdmin || targetRole == enums.RoleModerator
-	case enums.RoleAdmin:
-		return targetRole == enums.RoleModerator
-	default:
+func (s *Service) ListRoleDefinitions(ctx context.Context) []model.RoleDefinition {
+	return append([]model.RoleDefinition(nil), s.roles.list(ctx)...)
+}
+
+func (s *Service) Can(ctx context.Context, role enums.Role, permission enums.Permission) bool {
+	def, ok := s.roles.get(ctx, role)
+	return ok && def.Has(permission)
+}
+
+func (s *Service) CanOpenAccess(ctx context.Context, role enums.Role) bool {
+	return s.Can(ctx, role, enums.PermissionAccessManage)
+}
+
+// CanManageRole reports whether actorRole may grant or revoke targetRole. Besides OWNER, only holders
+// of access.manage may do it, and only for roles with strictly fewer permissions than their own.
+func (s *Service) CanManageRole(ctx context.Context, actorRole, targetRole enums.Role) bool {
+	if targetRole == enums.RoleOwner || targetRole == enums.RoleNone {
+		return false
+	}
+	target, ok := s.roles.get(ctx, targetRole)
+	if !ok {
+		return false
+	}
+	if actorRole == enums.RoleOwner {
+		return true
+	}
+	actor, ok := s.roles.get(ctx, actorRole)
+	if !ok || !actor.Has(enums.PermissionAccessManage) {
 		return false
 	}
+	return actor.Covers(target) && !target.Covers(actor)
 }
 
-func (s *Service) CanRevokeRole(actorRole, targetRole enums.Role) bool {
-	switch actorRole {
-	case enums.RoleOwner:
-		return targetRole == enums.RoleAdmin || targetRole == enums.RoleModerator
-	case enums.RoleAdmin:
-		return targetRole == enums.RoleModerator
-	default:
-		return false
+// CanRevokeRole also lets OWNER take away roles whose definition no longer exists.
+func (s *Service) CanRevokeRole(ctx context.Context, actorRole, targetRole enums.Role) bool {
+	if actorRole == enums.RoleOwner {
+		return targetRole != enums.RoleOwner && targetRole != enums.RoleNone
+	}
+	return s.CanManageRole(ctx, actorRole, targetRole)
+}
+
+// AssignableRoles lists the role definitions actorRole may hand out.
+func (s *Service) AssignableRoles(ctx context.Context, actorRole enums.Role) []model.RoleDefinition {
+	result := make([]model.RoleDefinition, 0, 4)
+	for _, def := range s.roles.list(ctx) {
+		if s.CanManageRole(ctx, actorRole, def.Name) {
+			result = append(result, def)
+		}
+	}
+	return result
+}
+
+// SaveRoleDefinition creates or replaces a custom role; only OWNER may define roles.
+func (s *Service) SaveRoleDefinition(ctx context.Context, actorTGID int64, actorRole enums.Role, def model.RoleDefinition) (model.RoleDefinition, error) {
+	if actorRole != enums.RoleOwner {
+		return model.RoleDefinition{}, ErrAccessDenied
+	}
+	def.Name = enums.NormalizeRole(string(def.Name))
+	if err := ValidateCustomRoleName(def.Name); err != nil {
+		return model.RoleDefinition{}, err
+	}
+	permissions, err := normalizePermissions(def.Permissions)
+	if err != nil {
+		return model.RoleDefinition{}, err
+	}
+	def.Permissions = permissions
+	def.IsSystem = false
+	if actorTGID != 0 {
+		def.UpdatedByTGID = &actorTGID
 	}
+	return s.roles.save(ctx, def)
 }
 
 func (s *Service) ListRecentUsers(ctx context.Context, limit int) ([]model.BotUser, error) {
@@ -119,7 +172,7 @@ func (s *Service) GetActiveManagedRole(ctx context.Context, tgID int64) (enums.R
 	if err != nil {
 		return enums.RoleNone, err
 	}
-	if role != enums.RoleAdmin && role != enums.RoleModerator {
+	if role == enums.RoleOwner {
 		return enums.RoleNone, nil
 	}
 	return role, nil
@@ -129,7 +182,7 @@ func (s *Service) GrantRole(ctx context.Context, actorTGID int64, actorRole enum
 	if targetTGID == s.ownerTGID {
 		return ErrAccessDenied
 	}
-	if !s.CanGrantRole(actorRole, targetRole) {
+	if !s.CanManageRole(ctx, actorRole, targetRole) {
 		return ErrAccessDenied
 	}
 	if s.rolesRepo == nil {
@@ -153,7 +206,7 @@ func (s *Service) RevokeRole(ctx context.Context, actorRole enums.Role, targetTG
 	if targetRole == enums.RoleNone {
 		return enums.RoleNone, false, nil
 	}
-	if !s.CanRevokeRole(actorRole, targetRole) {
+	if !s.CanRevokeRole(ctx, actorRole, targetRole) {
 		return targetRole, false, ErrAccessDenied
 	}
 
@@ -1,17 +1,36 @@
 package audit
 
 import (
+	"bytes"
 	"context"
+	"encoding/csv"
 	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
 	"time"
 
 	"bot_moderator/internal/domain/enums"
 	"bot_moderator/internal/domain/model"
 )
 
+var ErrInvalidFilter = errors.New("invalid audit filter")
+var ErrUnsupportedExportFormat = errors.New("unsupported audit export format")
+
+const (
+	DefaultPageSize = 10
+	ExportLimit     = 5000
+
+	ExportFormatCSV  = "csv"
+	ExportFormatJSON = "json"
+)
+
 type Repo interface {
 	Save(context.Context, model.Audit) error
 	ListRecent(context.Context, int) ([]model.Audit, error)
+	Search(context.Context, model.AuditFilter) ([]model.Audit, error)
+	VerifyChain(context.Context) (model.AuditChainReport, error)
 }
 
 type Service struct {
@@ -98,7 +117,7 @@ func (s *Service) LogModerationReject(ctx context.Context, actorTGID int64, targ
 	return s.repo.Save(ctx, entry)
 }
 
-func (s *Service) LogModerationApprove(ctx context.Context, actorTGID int64, targetUserID int64, moderationItemID int64) error {
+func (s *Service) LogModerationApprove(ctx context.Context, actorTGID int64, targetUserID int64, moderationItemID int64, verified bool) error {
 	if s.repo == nil {
 		return nil
 	}
@@ -106,6 +125,7 @@ func (s *Service) LogModerationApprove(ctx context.Context, actorTGID int64, tar
 	payload, err := json.Marshal(map[string]interface{}{
 		"target_user_id":     targetUserID,
 		"moderation_item_id": moderationItemID,
+		"verified":           verified,
 	})
 	if err != nil {
 		payload = json.RawMessage(`{}`)
@@ -120,6 +140,29 @@ func (s *Service) LogModerationApprove(ctx context.Context, actorTGID int64, tar
 	return s.repo.Save(ctx, entry)
 }
 
+func (s *Service) LogModerationReview(ctx context.Context, actorTGID int64, review model.ModerationReview, reasonCode string, note string) error {
+	action := enums.AuditActionReviewUphold
+	if review.Status == model.ModerationReviewStatusOverturned {
+		action = enums.AuditActionReviewOverturn
+	}
+	data := map[string]interface{}{
+		"review_id":          review.ID,
+		"review_kind":        review.Kind,
+		"moderation_item_id": review.ModerationItemID,
+		"target_user_id":     review.UserID,
+	}
+	if review.OriginalModeratorTGID != nil {
+		data["original_moderator_tg_id"] = *review.OriginalModeratorTGID
+	}
+	if reasonCode != "" {
+		data["reason_code"] = reasonCode
+	}
+	if note != "" {
+		data["note"] = note
+	}
+	return s.logWithPayload(ctx, action, actorTGID, data)
+}
+
 func (s *Service) LogLookup(ctx context.Context, actorTGID int64, query string, targetUserID int64) error {
 	return s.logWithPayload(ctx, enums.AuditActionLookupUser, actorTGID, map[string]interface{}{
 		"query":          query,
@@ -127,11 +170,17 @@ func (s *Service) LogLookup(ctx context.Context, actorTGID int64, query string,
 	})
 }
 
-func (s *Service) LogBan(ctx context.Context, actorTGID int64, targetUserID int64, reason string) error {
-	return s.logWithPayload(ctx, enums.AuditActionBanUser, actorTGID, map[string]interface{}{
+func (s *Service) LogBan(ctx context.Context, actorTGID int64, targetUserID int64, ban model.BanInput, expiresAt *time.Time) error {
+	payload := map[string]interface{}{
 		"target_user_id": targetUserID,
-		"reason":         reason,
-	})
+		"reason_code":    ban.ReasonCode,
+		"reason":         ban.Reason,
+		"duration":       ban.Duration,
+	}
+	if expiresAt != nil {
+		payload["expires_at"] = expiresAt.UTC().Format(time.RFC3339)
+	}
+	return s.logWithPayload(ctx, enums.AuditActionBanUser, actorTGID, payload)
 }
 
 func (s *Service) LogUnban(ctx context.Context, actorTGID int64, targetUserID int64) error {
@@ -163,6 +212,87 @@ func (s *Service) LogSystemViewWorkStats(ctx context.Context, actorTGID int64) e
 	return s.logWithPayload(ctx, enums.AuditActionSystemViewWork, actorTGID, map[string]interface{}{})
 }
 
+func (s *Service) LogRejectReasonUpdate(ctx context.Context, actorTGID int64, reason model.RejectReason, field string) error {
+	return s.logWithPayload(ctx, enums.AuditActionRejectReasonEdit, actorTGID, map[string]interface{}{
+		"reason_code": reason.Code,
+		"field":       field,
+		"is_active":   reason.IsActive,
+		"severity":    reason.Severity,
+	})
+}
+
+func (s *Service) LogFlagUpdated(ctx context.Context, actorTGID int64, flag model.AppFlag, field string) error {
+	return s.logWithPayload(ctx, enums.AuditActionFlagUpdate, actorTGID, map[string]interface{}{
+		"key":            flag.Key,
+		"field":          field,
+		"type":           flag.Type,
+		"value":          flag.Value,
+		"rules":          flag.Rules,
+		"client_visible": flag.ClientVisible,
+	})
+}
+
+func (s *Service) LogFlagDeleted(ctx context.Context, actorTGID int64, flag model.AppFlag) error {
+	return s.logWithPayload(ctx, enums.AuditActionFlagDelete, actorTGID, map[string]interface{}{
+		"key":   flag.Key,
+		"type":  flag.Type,
+		"value": flag.Value,
+		"rules": flag.Rules,
+	})
+}
+
+func (s *Service) LogWaitlistAdmitted(ctx context.Context, actorTGID int64, req model.WaitlistAdmitRequest, result model.WaitlistAdmitResult) error {
+	return s.logWithPayload(ctx, enums.AuditActionWaitlistAdmit, actorTGID, map[string]interface{}{
+		"limit":    req.Limit,
+		"city_id":  req.CityID,
+		"balance":  req.Balance,
+		"admitted": result.Admitted,
+		"waiting":  result.Waiting,
+	})
+}
+
+func (s *Service) LogRoleDefinitionSaved(ctx context.Context, actorTGID int64, def model.RoleDefinition) error {
+	return s.logWithPayload(ctx, enums.AuditActionRoleDefinitionSave, actorTGID, map[string]interface{}{
+		"role":        string(def.Name),
+		"permissions": def.Permissions,
+	})
+}
+
+func (s *Service) LogModeratorWorkloadUpdated(ctx context.Context, actorTGID int64, workload model.ModeratorWorkload) error {
+	return s.logWithPayload(ctx, enums.AuditActionWorkloadUpdate, actorTGID, map[string]interface{}{
+		"target_tg_id":     workload.ModeratorTGID,
+		"max_active_items": workload.MaxActiveItems,
+		"skills":           workload.Skills,
+	})
+}
+
+func (s *Service) LogModeratorShiftAdded(ctx context.Context, actorTGID int64, shift model.ModeratorShift) error {
+	return s.logWithPayload(ctx, enums.AuditActionShiftAdd, actorTGID, map[string]interface{}{
+		"shift_id":     shift.ID,
+		"target_tg_id": shift.ModeratorTGID,
+		"weekday":      shift.Weekday,
+		"starts_at":    shift.StartsAt,
+		"ends_at":      shift.EndsAt,
+	})
+}
+
+func (s *Service) LogModeratorShiftDeleted(ctx context.Context, actorTGID int64, shift model.ModeratorShift) error {
+	return s.logWithPayload(ctx, enums.AuditActionShiftDelete, actorTGID, map[string]interface{}{
+		"shift_id":     shift.ID,
+		"target_tg_id": shift.ModeratorTGID,
+	})
+}
+
+func (s *Service) LogLockReassigned(ctx context.Context, actorTGID int64, item model.LockReassignment) error {
+	return s.logWithPayload(ctx, enums.AuditActionLockReassign, actorTGID, map[string]interface{}{
+		"moderation_item_id": item.ModerationItemID,
+		"target_user_id":     item.UserID,
+		"from_tg_id":         item.FromTGID,
+		"to_tg_id":           item.ToTGID,
+		"idle_sec":           item.IdleSec,
+	})
+}
+
 func (s *Service) ListRecent(ctx context.Context, limit int) ([]model.Audit, error) {
 	if s.repo == nil {
 		return []model.Audit{}, nil
@@ -173,6 +303,179 @@ func (s *Service) ListRecent(ctx context.Context, limit int) ([]model.Audit, err
 	return s.repo.ListRecent(ctx, limit)
 }
 
+func (s *Service) LogAuditExport(ctx context.Context, actorTGID int64, filter model.AuditFilter, format string, rows int) error {
+	return s.logWithPayload(ctx, enums.AuditActionAuditExport, actorTGID, map[string]interface{}{
+		"filter": FormatFilter(filter),
+		"format": format,
+		"rows":   rows,
+	})
+}
+
+// Search returns one page of History; an extra row is fetched to know whether a next page exists.
+func (s *Service) Search(ctx context.Context, filter model.AuditFilter) (model.AuditPage, error) {
+	if filter.Limit <= 0 {
+		filter.Limit = DefaultPageSize
+	}
+	if filter.Offset < 0 {
+		filter.Offset = 0
+	}
+	page := model.AuditPage{Items: []model.Audit{}, Offset: filter.Offset, Limit: filter.Limit}
+	if s.repo == nil {
+		return page, nil
+	}
+
+	query := filter
+	query.Limit = filter.Limit + 1
+	items, err := s.repo.Search(ctx, query)
+	if err != nil {
+		return model.AuditPage{}, err
+	}
+	if len(items) > filter.Limit {
+		page.HasMore = true
+		items = items[:filter.Limit]
+	}
+	page.Items = items
+	return page, nil
+}
+
+func (s *Service) Export(ctx context.Context, filter model.AuditFilter, format string) ([]byte, int, error) {
+	format = strings.ToLower(strings.TrimSpace(format))
+	if format != ExportFormatCSV && format != ExportFormatJSON {
+		return nil, 0, ErrUnsupportedExportFormat
+	}
+
+	items := []model.Audit{}
+	if s.repo != nil {
+		filter.Limit = ExportLimit
+		filter.Offset = 0
+		found, err := s.repo.Search(ctx, filter)
+		if err != nil {
+			return nil, 0, err
+		}
+		items = found
+	}
+
+	data, err := encodeExport(items, format)
+	if err != nil {
+		return nil, 0, err
+	}
+	return data, len(items), nil
+}
+
+func (s *Service) VerifyChain(ctx context.Context) (model.AuditChainReport, error) {
+	if s.repo == nil {
+		return model.AuditChainReport{OK: true}, nil
+	}
+	return s.repo.VerifyChain(ctx)
+}
+
+func encodeExport(items []model.Audit, format string) ([]byte, error) {
+	if format == ExportFormatJSON {
+		return json.MarshalIndent(items, "", "  ")
+	}
+
+	var buf bytes.Buffer
+	writer := csv.NewWriter(&buf)
+	if err := writer.Write([]string{"id", "created_at", "actor_tg_id", "action", "payload", "prev_hash", "hash"}); err != nil {
+		return nil, err
+	}
+	for _, item := range items {
+		payload := strings.TrimSpace(string(item.Payload))
+		if payload == "" {
+			payload = "{}"
+		}
+		if err := writer.Write([]string{
+			item.ID,
+			item.CreatedAt.UTC().Format(time.RFC3339),
+			strconv.FormatInt(item.ActorTGID, 10),
+			string(item.Action),
+			payload,
+			item.PrevHash,
+			item.Hash,
+		}); err != nil {
+			return nil, err
+		}
+	}
+	writer.Flush()
+	if err := writer.Error(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// ParseFilter reads "actor:<tg_id> user:<user_id> action:<ACTION> from:<YYYY-MM-DD> to:<YYYY-MM-DD>";
+// every term is optional and the "to" day is inclusive.
+func ParseFilter(raw string) (model.AuditFilter, error) {
+	filter := model.AuditFilter{}
+	for _, term := range strings.Fields(raw) {
+		key, value, ok := strings.Cut(term, ":")
+		if !ok || strings.TrimSpace(value) == "" {
+			return model.AuditFilter{}, fmt.Errorf("%w: %q", ErrInvalidFilter, term)
+		}
+
+		switch strings.ToLower(key) {
+		case "actor":
+			id, err := strconv.ParseInt(value, 10, 64)
+			if err != nil || id <= 0 {
+				return model.AuditFilter{}, fmt.Errorf("%w: actor must be a tg id", ErrInvalidFilter)
+			}
+			filter.ActorTGID = id
+		case "user":
+			id, err := strconv.ParseInt(value, 10, 64)
+			if err != nil || id <= 0 {
+				return model.AuditFilter{}, fmt.Errorf("%w: user must be a user id", ErrInvalidFilter)
+			}
+			filter.TargetUserID = id
+		case "action":
+			action, ok := enums.ParseAuditAction(strings.ToUpper(value))
+			if !ok {
+				return model.AuditFilter{}, fmt.Errorf("%w: unknown action %s", ErrInvalidFilter, value)
+			}
+			filter.Action = action
+		case "from":
+			day, err := time.Parse("2006-01-02", value)
+			if err != nil {
+				return model.AuditFilter{}, fmt.Errorf("%w: from must be YYYY-MM-DD", ErrInvalidFilter)
+			}
+			filter.From = &day
+		case "to":
+			day, err := time.Parse("2006-01-02", value)
+			if err != nil {
+				return model.AuditFilter{}, fmt.Errorf("%w: to must be YYYY-MM-DD", ErrInvalidFilter)
+			}
+			end := day.Add(24 * time.Hour)
+			filter.To = &end
+		default:
+			return model.AuditFilter{}, fmt.Errorf("%w: unknown key %s", ErrInvalidFilter, key)
+		}
+	}
+	if filter.From != nil && filter.To != nil && !filter.From.Before(*filter.To) {
+		return model.AuditFilter{}, fmt.Errorf("%w: from must be before to", ErrInvalidFilter)
+	}
+	return filter, nil
+}
+
+// FormatFilter is the inverse of ParseFilter and is what the bot keeps in the chat session.
+func FormatFilter(filter model.AuditFilter) string {
+	terms := make([]string, 0, 5)
+	if filter.ActorTGID != 0 {
+		terms = append(terms, "actor:"+strconv.FormatInt(filter.ActorTGID, 10))
+	}
+	if filter.TargetUserID != 0 {
+		terms = append(terms, "user:"+strconv.FormatInt(filter.TargetUserID, 10))
+	}
+	if filter.Action != "" {
+		terms = append(terms, "action:"+string(filter.Action))
+	}
+	if filter.From != nil {
+		terms = append(terms, "from:"+filter.From.UTC().Format("2006-01-02"))
+	}
+	if filter.To != nil {
+		terms = append(terms, "to:"+filter.To.UTC().Add(-24*time.Hour).Format("2006-01-02"))
+	}
+	return strings.Join(terms, " ")
+}
+
 func (s *Service) logWithPayload(ctx context.Context, action enums.AuditAction, actorTGID int64, data map[string]interface{}) error {
 	if s.repo == nil {
 		return nil
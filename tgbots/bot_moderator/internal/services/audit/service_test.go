@@ -0,0 +1,151 @@
+package audit
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"bot_moderator/internal/domain/enums"
+	"bot_moderator/internal/domain/model"
+)
+
+type auditRepoStub struct {
+	items   []model.Audit
+	filters []model.AuditFilter
+}
+
+func (s *auditRepoStub) Save(context.Context, model.Audit) error { return nil }
+
+func (s *auditRepoStub) ListRecent(context.Context, int) ([]model.Audit, error) { return s.items, nil }
+
+func (s *auditRepoStub) Search(_ context.Context, filter model.AuditFilter) ([]model.Audit, error) {
+	s.filters = append(s.filters, filter)
+	end := filter.Offset + filter.Limit
+	if end > len(s.items) {
+		end = len(s.items)
+	}
+	if filter.Offset >= end {
+		return []model.Audit{}, nil
+	}
+	return s.items[filter.Offset:end], nil
+}
+
+func (s *auditRepoStub) VerifyChain(context.Context) (model.AuditChainReport, error) {
+	return model.AuditChainReport{OK: true}, nil
+}
+
+func TestParseFilterRoundTrip(t *testing.T) {
+	filter, err := ParseFilter("actor:42 user:7 action:ban_user from:2026-01-01 to:2026-01-31")
+	if err != nil {
+		t.Fatalf("parse filter: %v", err)
+	}
+	if filter.ActorTGID != 42 || filter.TargetUserID != 7 || filter.Action != enums.AuditActionBanUser {
+		t.Fatalf("unexpected filter %+v", filter)
+	}
+	if got := filter.To.Format(time.RFC3339); got != "2026-02-01T00:00:00Z" {
+		t.Fatalf("expected inclusive to day, got %s", got)
+	}
+	if got := FormatFilter(filter); got != "actor:42 user:7 action:BAN_USER from:2026-01-01 to:2026-01-31" {
+		t.Fatalf("unexpected formatted filter %q", got)
+	}
+
+	for _, raw := range []string{"actor:x", "action:NOPE", "from:2026-02-01 to:2026-01-01", "color:red", "user"} {
+		if _, err := ParseFilter(raw); !errors.Is(err, ErrInvalidFilter) {
+			t.Fatalf("%q: expected invalid filter error, got %v", raw, err)
+		}
+	}
+}
+
+func TestSearchReportsNextPage(t *testing.T) {
+	repo := &auditRepoStub{}
+	for i := 0; i < 12; i++ {
+		repo.items = append(repo.items, model.Audit{ID: fmt.Sprint(i)})
+	}
+	svc := NewService(repo)
+
+	first, err := svc.Search(context.Background(), model.AuditFilter{})
+	if err != nil {
+		t.Fatalf("search: %v", err)
+	}
+	if len(first.Items) != DefaultPageSize || !first.HasMore {
+		t.Fatalf("expected full first page with more, got %d items has_more=%v", len(first.Items), first.HasMore)
+	}
+
+	second, err := svc.Search(context.Background(), model.AuditFilter{Offset: DefaultPageSize})
+	if err != nil {
+		t.Fatalf("search: %v", err)
+	}
+	if len(second.Items) != 2 || second.HasMore {
+		t.Fatalf("expected last page of 2, got %d items has_more=%v", len(second.Items), second.HasMore)
+	}
+}
+
+func TestExportCSV(t *testing.T) {
+	repo := &auditRepoStub{items: []model.Audit{{
+		ID:        "a1",
+		ActorTGID: 5,
+		Action:    enums.AuditActionUnbanUser,
+		Payload:   json.RawMessage(`{"target_user_id": 9}`),
+		Hash:      "h1",
+		CreatedAt: time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC),
+	}}}
+	svc := NewService(repo)
+
+	data, rows, err := svc.Export(context.Background(), model.AuditFilter{Action: enums.AuditActionUnbanUser}, "CSV")
+	if err != nil {
+		t.Fatalf("export: %v", err)
+	}
+	if rows != 1 || repo.filters[0].Limit != ExportLimit {
+		t.Fatalf("unexpected export rows=%d filter=%+v", rows, repo.filters[0])
+	}
+	lines := strings.Split(strings.TrimSpace(string(data)), "\n")
+	if len(lines) != 2 || lines[1] != `a1,2026-01-02T03:04:05Z,5,UNBAN_USER,"{""target_user_id"": 9}",,h1` {
+		t.Fatalf("unexpected csv:\n%s", data)
+	}
+
+	if _, _, err := svc.Export(context.Background(), model.AuditFilter{}, "xml"); !errors.Is(err, ErrUnsupportedExportFormat) {
+		t.Fatalf("expected unsupported format error, got %v", err)
+	}
+}
+
+func TestAuditChainHashIgnoresJSONBFormatting(t *testing.T) {
+	createdAt := time.Date(2026, 1, 2, 3, 4, 5, 123456789, time.UTC)
+	written := model.Audit{
+		ActorTGID: 1,
+		Action:    enums.AuditActionBanUser,
+		Payload:   json.RawMessage(`{"target_user_id":9,"reason":"SPAM"}`),
+		CreatedAt: createdAt,
+	}
+	readBack := written
+	readBack.Payload = json.RawMessage(`{"reason": "SPAM", "target_user_id": 9}`)
+	readBack.CreatedAt = createdAt.Truncate(time.Microsecond).In(time.FixedZone("MSK", 3*3600))
+
+	if model.AuditChainHash("prev", written) != model.AuditChainHash("prev", readBack) {
+		t.Fatal("expected hash to survive jsonb normalization")
+	}
+	tampered := readBack
+	tampered.ActorTGID = 2
+	if model.AuditChainHash("prev", written) == model.AuditChainHash("prev", tampered) {
+		t.Fatal("expected tampering to change the hash")
+	}
+	if model.AuditChainHash("prev", written) == model.AuditChainHash("other", written) {
+		t.Fatal("expected hash to depend on the previous link")
+	}
+}
+
+// The backend pins the same value in its audit service tests; both sides must agree to share one chain.
+func TestAuditChainHashMatchesBackend(t *testing.T) {
+	entry := model.Audit{
+		ActorTGID: 700001,
+		Action:    enums.AuditActionBanUser,
+		Payload:   json.RawMessage(`{"target_user_id": 42, "reason": "SPAM"}`),
+		CreatedAt: time.Date(2026, 1, 2, 3, 4, 5, 678901000, time.UTC),
+	}
+	if got := model.AuditChainHash("", entry); got != "dd85f6f100a82ea2b1d7fee61b084733c0609faca6a3ad39eeb48eac02131b01" {
+		t.Fatalf("unexpected genesis hash %s", got)
+	}
+}
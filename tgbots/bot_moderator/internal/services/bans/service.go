@@ -2,12 +2,22 @@ package bans
 
 import (
 	"context"
+	"errors"
+	"strings"
+
+	"bot_moderator/internal/domain/enums"
+	"bot_moderator/internal/domain/model"
+)
+
+var (
+	ErrInvalidBanReason   = errors.New("invalid ban reason code")
+	ErrInvalidBanDuration = errors.New("invalid ban duration")
 )
 
 type Repo interface {
-	Ban(context.Context, int64, string, int64) error
+	Ban(context.Context, model.BanInput) (model.BanState, error)
 	Unban(context.Context, int64, int64) error
-	GetBanState(context.Context, int64) (bool, string, error)
+	GetBanState(context.Context, int64) (model.BanState, error)
 }
 
 type Service struct {
@@ -18,11 +28,21 @@ func NewService(repo Repo) *Service {
 	return &Service{repo: repo}
 }
 
-func (s *Service) Ban(ctx context.Context, userID int64, reason string, updatedByTGID int64) error {
+func (s *Service) Ban(ctx context.Context, in model.BanInput) (model.BanState, error) {
+	in.ReasonCode = strings.ToUpper(strings.TrimSpace(in.ReasonCode))
+	if _, ok := enums.ParseBanReason(in.ReasonCode); !ok {
+		return model.BanState{}, ErrInvalidBanReason
+	}
+	in.Duration = strings.ToLower(strings.TrimSpace(in.Duration))
+	if _, ok := enums.ParseBanDuration(in.Duration); !ok {
+		return model.BanState{}, ErrInvalidBanDuration
+	}
+	in.Reason = strings.TrimSpace(in.Reason)
+
 	if s.repo == nil {
-		return nil
+		return model.BanState{}, nil
 	}
-	return s.repo.Ban(ctx, userID, reason, updatedByTGID)
+	return s.repo.Ban(ctx, in)
 }
 
 func (s *Service) Unban(ctx context.Context, userID int64, updatedByTGID int64) error {
@@ -32,9 +52,9 @@ func (s *Service) Unban(ctx context.Context, userID int64, updatedByTGID int64)
 	return s.repo.Unban(ctx, userID, updatedByTGID)
 }
 
-func (s *Service) GetBanState(ctx context.Context, userID int64) (bool, string, error) {
+func (s *Service) GetBanState(ctx context.Context, userID int64) (model.BanState, error) {
 	if s.repo == nil {
-		return false, "", nil
+		return model.BanState{}, nil
 	}
 	return s.repo.GetBanState(ctx, userID)
 }
@@ -0,0 +1,57 @@
+package bans
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"bot_moderator/internal/domain/model"
+)
+
+type fakeRepo struct {
+	banned model.BanInput
+}
+
+func (r *fakeRepo) Ban(_ context.Context, in model.BanInput) (model.BanState, error) {
+	r.banned = in
+	return model.BanState{Banned: true, ReasonCode: in.ReasonCode, Reason: in.Reason}, nil
+}
+
+func (r *fakeRepo) Unban(_ context.Context, _ int64, _ int64) error {
+	return nil
+}
+
+func (r *fakeRepo) GetBanState(_ context.Context, _ int64) (model.BanState, error) {
+	return model.BanState{}, nil
+}
+
+func TestBanNormalizesCatalogCodeAndDuration(t *testing.T) {
+	repo := &fakeRepo{}
+	svc := NewService(repo)
+
+	state, err := svc.Ban(context.Background(), model.BanInput{
+		UserID:     42,
+		ReasonCode: " spam ",
+		Reason:     "  bulk invites ",
+		Duration:   "7D",
+		ActorTGID:  777,
+	})
+	if err != nil {
+		t.Fatalf("ban: %v", err)
+	}
+	if !state.Banned || repo.banned.ReasonCode != "SPAM" || repo.banned.Duration != "7d" || repo.banned.Reason != "bulk invites" {
+		t.Fatalf("unexpected ban request: %+v", repo.banned)
+	}
+}
+
+func TestBanRejectsUnknownReasonAndDuration(t *testing.T) {
+	svc := NewService(&fakeRepo{})
+	ctx := context.Background()
+
+	if _, err := svc.Ban(ctx, model.BanInput{UserID: 1, ReasonCode: "RUDE", Duration: "24h", ActorTGID: 1}); !errors.Is(err, ErrInvalidBanReason) {
+		t.Fatalf("expected ErrInvalidBanReason, got %v", err)
+	}
+	if _, err := svc.Ban(ctx, model.BanInput{UserID: 1, ReasonCode: "SPAM", Duration: "1y", ActorTGID: 1}); !errors.Is(err, ErrInvalidBanDuration) {
+		t.Fatalf("expected ErrInvalidBanDuration, got %v", err)
+	}
+}
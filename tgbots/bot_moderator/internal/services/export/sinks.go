@@ -0,0 +1,259 @@
+package export
+
+import (
+	"bytes"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"bot_moderator/internal/domain/model"
+)
+
+// Sink delivers a leased batch; an error makes the whole batch retry.
+type Sink interface {
+	Name() string
+	Deliver(context.Context, []model.ExportQueueItem) error
+}
+
+// exportRow flattens a queue item into created_at, kind, id followed by payload values in key order.
+func exportRow(item model.ExportQueueItem) []string {
+	row := []string{item.CreatedAt.UTC().Format(time.RFC3339), item.Kind, item.ID}
+
+	payload := map[string]interface{}{}
+	if len(item.Payload) > 0 {
+		if err := json.Unmarshal(item.Payload, &payload); err != nil {
+			return append(row, string(item.Payload))
+		}
+	}
+
+	keys := make([]string, 0, len(payload))
+	for key := range payload {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		row = append(row, exportCell(payload[key]))
+	}
+	return row
+}
+
+func exportCell(value interface{}) string {
+	switch typed := value.(type) {
+	case nil:
+		return ""
+	case string:
+		return typed
+	case float64, bool:
+		return fmt.Sprint(typed)
+	default:
+		raw, err := json.Marshal(typed)
+		if err != nil {
+			return fmt.Sprint(typed)
+		}
+		return string(raw)
+	}
+}
+
+func encodeCSV(items []model.ExportQueueItem) ([]byte, error) {
+	var buf bytes.Buffer
+	writer := csv.NewWriter(&buf)
+	for _, item := range items {
+		if err := writer.Write(exportRow(item)); err != nil {
+			return nil, err
+		}
+	}
+	writer.Flush()
+	if err := writer.Error(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// SheetsSink appends rows through the Google Sheets values:append API shape.
+type SheetsSink struct {
+	baseURL       string
+	spreadsheetID string
+	sheetRange    string
+	accessToken   string
+	httpClient    *http.Client
+}
+
+func NewSheetsSink(baseURL, spreadsheetID, sheetRange, accessToken string, timeout time.Duration) (*SheetsSink, error) {
+	baseURL = strings.TrimRight(strings.TrimSpace(baseURL), "/")
+	if baseURL == "" {
+		baseURL = "https://sheets.googleapis.com"
+	}
+	if strings.TrimSpace(spreadsheetID) == "" {
+		return nil, fmt.Errorf("sheets spreadsheet id is required")
+	}
+	if strings.TrimSpace(sheetRange) == "" {
+		sheetRange = "Sheet1!A1"
+	}
+	if timeout <= 0 {
+		timeout = 10 * time.Second
+	}
+
+	return &SheetsSink{
+		baseURL:       baseURL,
+		spreadsheetID: strings.TrimSpace(spreadsheetID),
+		sheetRange:    strings.TrimSpace(sheetRange),
+		accessToken:   strings.TrimSpace(accessToken),
+		httpClient:    &http.Client{Timeout: timeout},
+	}, nil
+}
+
+func (s *SheetsSink) Name() string {
+	return "sheets"
+}
+
+func (s *SheetsSink) Deliver(ctx context.Context, items []model.ExportQueueItem) error {
+	if len(items) == 0 {
+		return nil
+	}
+
+	values := make([][]string, 0, len(items))
+	for _, item := range items {
+		values = append(values, exportRow(item))
+	}
+	body, err := json.Marshal(map[string]interface{}{
+		"majorDimension": "ROWS",
+		"values":         values,
+	})
+	if err != nil {
+		return fmt.Errorf("marshal sheets append body: %w", err)
+	}
+
+	endpoint := fmt.Sprintf(
+		"%s/v4/spreadsheets/%s/values/%s:append?valueInputOption=RAW&insertDataOption=INSERT_ROWS",
+		s.baseURL,
+		url.PathEscape(s.spreadsheetID),
+		url.PathEscape(s.sheetRange),
+	)
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("build sheets append request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if s.accessToken != "" {
+		req.Header.Set("Authorization", "Bearer "+s.accessToken)
+	}
+
+	resp, err := s.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("sheets append request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		snippet, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return fmt.Errorf("sheets append status %d: %s", resp.StatusCode, strings.TrimSpace(string(snippet)))
+	}
+	_, _ = io.Copy(io.Discard, resp.Body)
+	return nil
+}
+
+// CSVSink appends rows to one file per UTC day inside dir.
+type CSVSink struct {
+	dir string
+	now func() time.Time
+
+	mu sync.Mutex
+}
+
+func NewCSVSink(dir string) (*CSVSink, error) {
+	dir = strings.TrimSpace(dir)
+	if dir == "" {
+		return nil, fmt.Errorf("csv export dir is required")
+	}
+	if err := os.MkdirAll(dir, 0o750); err != nil {
+		return nil, fmt.Errorf("create csv export dir: %w", err)
+	}
+	return &CSVSink{dir: dir, now: time.Now}, nil
+}
+
+func (s *CSVSink) Name() string {
+	return "csv"
+}
+
+func (s *CSVSink) Path(day time.Time) string {
+	return filepath.Join(s.dir, "exports-"+day.UTC().Format("2006-01-02")+".csv")
+}
+
+func (s *CSVSink) Deliver(_ context.Context, items []model.ExportQueueItem) error {
+	if len(items) == 0 {
+		return nil
+	}
+
+	payload, err := encodeCSV(items)
+	if err != nil {
+		return fmt.Errorf("encode csv export: %w", err)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	file, err := os.OpenFile(s.Path(s.now()), os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0o640)
+	if err != nil {
+		return fmt.Errorf("open csv export file: %w", err)
+	}
+	if _, err := file.Write(payload); err != nil {
+		_ = file.Close()
+		return fmt.Errorf("write csv export file: %w", err)
+	}
+	if err := file.Close(); err != nil {
+		return fmt.Errorf("close csv export file: %w", err)
+	}
+	return nil
+}
+
+type DocumentSender interface {
+	SendDocument(ctx context.Context, chatID int64, fileName string, data []byte, caption string) error
+}
+
+// TelegramSink sends each batch to the owner as a CSV document.
+type TelegramSink struct {
+	sender DocumentSender
+	chatID int64
+	now    func() time.Time
+}
+
+func NewTelegramSink(sender DocumentSender, chatID int64) (*TelegramSink, error) {
+	if sender == nil {
+		return nil, fmt.Errorf("telegram document sender is required")
+	}
+	if chatID == 0 {
+		return nil, fmt.Errorf("telegram export chat id is required")
+	}
+	return &TelegramSink{sender: sender, chatID: chatID, now: time.Now}, nil
+}
+
+func (s *TelegramSink) Name() string {
+	return "telegram"
+}
+
+func (s *TelegramSink) Deliver(ctx context.Context, items []model.ExportQueueItem) error {
+	if len(items) == 0 {
+		return nil
+	}
+
+	payload, err := encodeCSV(items)
+	if err != nil {
+		return fmt.Errorf("encode telegram export: %w", err)
+	}
+
+	now := s.now().UTC()
+	fileName := "exports-" + now.Format("20060102-150405") + ".csv"
+	caption := fmt.Sprintf("Экспорт: %d строк", len(items))
+	return s.sender.SendDocument(ctx, s.chatID, fileName, payload, caption)
+}
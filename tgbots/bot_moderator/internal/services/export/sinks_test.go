@@ -0,0 +1,100 @@
+package export
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"bot_moderator/internal/domain/model"
+)
+
+func exportItem(id string, payload string) model.ExportQueueItem {
+	return model.ExportQueueItem{
+		ID:        id,
+		Kind:      "SHEETS",
+		Payload:   json.RawMessage(payload),
+		CreatedAt: time.Date(2026, 3, 4, 5, 6, 7, 0, time.UTC),
+	}
+}
+
+func TestSheetsSinkAppendsRows(t *testing.T) {
+	var gotPath, gotAuth string
+	var gotBody struct {
+		Values [][]string `json:"values"`
+	}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotPath = r.URL.EscapedPath()
+		gotAuth = r.Header.Get("Authorization")
+		if err := json.NewDecoder(r.Body).Decode(&gotBody); err != nil {
+			t.Errorf("decode body: %v", err)
+		}
+		_, _ = w.Write([]byte(`{"updates":{"updatedRows":1}}`))
+	}))
+	defer server.Close()
+
+	sink, err := NewSheetsSink(server.URL, "sheet-1", "Exports!A1", "token", time.Second)
+	if err != nil {
+		t.Fatalf("new sink: %v", err)
+	}
+	if err := sink.Deliver(context.Background(), []model.ExportQueueItem{
+		exportItem("a", `{"user_id":42,"decision":"APPROVE"}`),
+	}); err != nil {
+		t.Fatalf("deliver: %v", err)
+	}
+
+	if gotPath != "/v4/spreadsheets/sheet-1/values/Exports%21A1:append" {
+		t.Fatalf("unexpected path %q", gotPath)
+	}
+	if gotAuth != "Bearer token" {
+		t.Fatalf("unexpected auth header %q", gotAuth)
+	}
+	expected := []string{"2026-03-04T05:06:07Z", "SHEETS", "a", "APPROVE", "42"}
+	if len(gotBody.Values) != 1 || strings.Join(gotBody.Values[0], "|") != strings.Join(expected, "|") {
+		t.Fatalf("unexpected values %v", gotBody.Values)
+	}
+}
+
+func TestSheetsSinkReturnsErrorOnFailureStatus(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		http.Error(w, "quota", http.StatusTooManyRequests)
+	}))
+	defer server.Close()
+
+	sink, err := NewSheetsSink(server.URL, "sheet-1", "", "", time.Second)
+	if err != nil {
+		t.Fatalf("new sink: %v", err)
+	}
+	err = sink.Deliver(context.Background(), []model.ExportQueueItem{exportItem("a", `{}`)})
+	if err == nil || !strings.Contains(err.Error(), "429") {
+		t.Fatalf("expected 429 error, got %v", err)
+	}
+}
+
+func TestCSVSinkAppendsToDailyFile(t *testing.T) {
+	sink, err := NewCSVSink(t.TempDir())
+	if err != nil {
+		t.Fatalf("new sink: %v", err)
+	}
+	day := time.Date(2026, 3, 4, 23, 0, 0, 0, time.UTC)
+	sink.now = func() time.Time { return day }
+
+	for _, id := range []string{"a", "b"} {
+		if err := sink.Deliver(context.Background(), []model.ExportQueueItem{exportItem(id, `{"note":"x, y"}`)}); err != nil {
+			t.Fatalf("deliver: %v", err)
+		}
+	}
+
+	raw, err := os.ReadFile(sink.Path(day))
+	if err != nil {
+		t.Fatalf("read csv: %v", err)
+	}
+	lines := strings.Split(strings.TrimSpace(string(raw)), "\n")
+	if len(lines) != 2 || lines[1] != `2026-03-04T05:06:07Z,SHEETS,b,"x, y"` {
+		t.Fatalf("unexpected csv content:\n%s", raw)
+	}
+}
@@ -0,0 +1,133 @@
+package export
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"bot_moderator/internal/domain/model"
+)
+
+const (
+	defaultBatchSize   = 50
+	defaultMaxAttempts = 8
+	defaultLease       = 2 * time.Minute
+	retryBaseDelay     = 30 * time.Second
+	retryMaxDelay      = time.Hour
+)
+
+type QueueRepo interface {
+	Lease(context.Context, string, int, time.Duration) ([]model.ExportQueueItem, error)
+	MarkDone(context.Context, []string) error
+	MarkRetry(context.Context, string, string, time.Time) error
+	MarkFailed(context.Context, string, string) error
+	RequeueFailed(context.Context) (int64, error)
+	Stats(context.Context) (model.ExportQueueStats, error)
+}
+
+type WorkerConfig struct {
+	BatchSize   int
+	MaxAttempts int
+	Lease       time.Duration
+}
+
+type Worker struct {
+	repo QueueRepo
+	sink Sink
+	cfg  WorkerConfig
+	now  func() time.Time
+}
+
+func NewWorker(repo QueueRepo, sink Sink, cfg WorkerConfig) *Worker {
+	if cfg.BatchSize <= 0 {
+		cfg.BatchSize = defaultBatchSize
+	}
+	if cfg.MaxAttempts <= 0 {
+		cfg.MaxAttempts = defaultMaxAttempts
+	}
+	if cfg.Lease <= 0 {
+		cfg.Lease = defaultLease
+	}
+	return &Worker{repo: repo, sink: sink, cfg: cfg, now: time.Now}
+}
+
+func (w *Worker) Enabled() bool {
+	return w != nil && w.repo != nil && w.sink != nil
+}
+
+func (w *Worker) SinkName() string {
+	if w == nil || w.sink == nil {
+		return "off"
+	}
+	return w.sink.Name()
+}
+
+// ProcessOnce leases one batch and delivers it; it returns how many rows were delivered.
+func (w *Worker) ProcessOnce(ctx context.Context) (int, error) {
+	if !w.Enabled() {
+		return 0, nil
+	}
+
+	items, err := w.repo.Lease(ctx, exportKindSheets, w.cfg.BatchSize, w.cfg.Lease)
+	if err != nil {
+		return 0, err
+	}
+	if len(items) == 0 {
+		return 0, nil
+	}
+
+	deliverErr := w.sink.Deliver(ctx, items)
+	if deliverErr == nil {
+		ids := make([]string, 0, len(items))
+		for _, item := range items {
+			ids = append(ids, item.ID)
+		}
+		if err := w.repo.MarkDone(ctx, ids); err != nil {
+			return 0, err
+		}
+		return len(items), nil
+	}
+
+	lastError := fmt.Sprintf("%s: %v", w.sink.Name(), deliverErr)
+	for _, item := range items {
+		if item.Attempts >= w.cfg.MaxAttempts {
+			if err := w.repo.MarkFailed(ctx, item.ID, lastError); err != nil {
+				return 0, err
+			}
+			continue
+		}
+		if err := w.repo.MarkRetry(ctx, item.ID, lastError, w.now().UTC().Add(RetryDelay(item.Attempts))); err != nil {
+			return 0, err
+		}
+	}
+	return 0, deliverErr
+}
+
+func (w *Worker) Stats(ctx context.Context) (model.ExportQueueStats, error) {
+	if w == nil || w.repo == nil {
+		return model.ExportQueueStats{}, nil
+	}
+	return w.repo.Stats(ctx)
+}
+
+func (w *Worker) RequeueFailed(ctx context.Context) (int64, error) {
+	if w == nil || w.repo == nil {
+		return 0, nil
+	}
+	return w.repo.RequeueFailed(ctx)
+}
+
+// RetryDelay doubles from 30s per attempt and caps at one hour.
+func RetryDelay(attempts int) time.Duration {
+	if attempts < 1 {
+		attempts = 1
+	}
+	delay := retryBaseDelay
+	for i := 1; i < attempts; i++ {
+		delay *= 2
+		if delay >= retryMaxDelay {
+			return retryMaxDelay
+		}
+	}
+	return delay
+}
@@ -0,0 +1,113 @@
+package export
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"bot_moderator/internal/domain/model"
+)
+
+type queueRepoStub struct {
+	items   []model.ExportQueueItem
+	done    []string
+	retried map[string]time.Time
+	failed  map[string]string
+}
+
+func newQueueRepoStub(items ...model.ExportQueueItem) *queueRepoStub {
+	return &queueRepoStub{items: items, retried: map[string]time.Time{}, failed: map[string]string{}}
+}
+
+func (s *queueRepoStub) Lease(context.Context, string, int, time.Duration) ([]model.ExportQueueItem, error) {
+	items := s.items
+	s.items = nil
+	return items, nil
+}
+
+func (s *queueRepoStub) MarkDone(_ context.Context, ids []string) error {
+	s.done = append(s.done, ids...)
+	return nil
+}
+
+func (s *queueRepoStub) MarkRetry(_ context.Context, id string, _ string, next time.Time) error {
+	s.retried[id] = next
+	return nil
+}
+
+func (s *queueRepoStub) MarkFailed(_ context.Context, id string, lastError string) error {
+	s.failed[id] = lastError
+	return nil
+}
+
+func (s *queueRepoStub) RequeueFailed(context.Context) (int64, error) {
+	return 0, nil
+}
+
+func (s *queueRepoStub) Stats(context.Context) (model.ExportQueueStats, error) {
+	return model.ExportQueueStats{}, nil
+}
+
+type sinkStub struct {
+	err       error
+	delivered int
+}
+
+func (s *sinkStub) Name() string {
+	return "stub"
+}
+
+func (s *sinkStub) Deliver(_ context.Context, items []model.ExportQueueItem) error {
+	if s.err != nil {
+		return s.err
+	}
+	s.delivered += len(items)
+	return nil
+}
+
+func TestWorkerMarksDeliveredRowsDone(t *testing.T) {
+	repo := newQueueRepoStub(model.ExportQueueItem{ID: "a", Attempts: 1}, model.ExportQueueItem{ID: "b", Attempts: 1})
+	sink := &sinkStub{}
+	worker := NewWorker(repo, sink, WorkerConfig{})
+
+	delivered, err := worker.ProcessOnce(context.Background())
+	if err != nil {
+		t.Fatalf("process: %v", err)
+	}
+	if delivered != 2 || sink.delivered != 2 || len(repo.done) != 2 {
+		t.Fatalf("expected 2 delivered rows, got delivered=%d sink=%d done=%v", delivered, sink.delivered, repo.done)
+	}
+}
+
+func TestWorkerRetriesWithBackoffAndFailsAfterMaxAttempts(t *testing.T) {
+	repo := newQueueRepoStub(model.ExportQueueItem{ID: "retry", Attempts: 2}, model.ExportQueueItem{ID: "dead", Attempts: 3})
+	worker := NewWorker(repo, &sinkStub{err: errors.New("boom")}, WorkerConfig{MaxAttempts: 3})
+	now := time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+	worker.now = func() time.Time { return now }
+
+	if _, err := worker.ProcessOnce(context.Background()); err == nil {
+		t.Fatal("expected delivery error")
+	}
+	if next, ok := repo.retried["retry"]; !ok || !next.Equal(now.Add(time.Minute)) {
+		t.Fatalf("expected retry in 1m, got %v (ok=%v)", next, ok)
+	}
+	if lastError, ok := repo.failed["dead"]; !ok || lastError != "stub: boom" {
+		t.Fatalf("expected dead row failed with sink error, got %q (ok=%v)", lastError, ok)
+	}
+}
+
+func TestRetryDelay(t *testing.T) {
+	cases := map[int]time.Duration{
+		0:  30 * time.Second,
+		1:  30 * time.Second,
+		2:  time.Minute,
+		4:  4 * time.Minute,
+		12: time.Hour,
+	}
+	for attempts, expected := range cases {
+		if got := RetryDelay(attempts); got != expected {
+			t.Fatalf("RetryDelay(%d) = %s, expected %s", attempts, got, expected)
+		}
+	}
+}
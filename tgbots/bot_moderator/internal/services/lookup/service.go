@@ -14,13 +14,22 @@ import (
 
 const signedURLTTL = 5 * time.Minute
 
-var ErrUserNotFound = errors.New("user not found")
+const (
+	DossierPageSize    = 5
+	maxDossierPageSize = 50
+)
+
+var (
+	ErrUserNotFound          = errors.New("user not found")
+	ErrInvalidDossierSection = errors.New("invalid dossier section")
+)
 
 type Repo interface {
 	FindUser(context.Context, string) (model.LookupUser, error)
 	FindByUserID(context.Context, int64) (model.LookupUser, error)
 	InsertAction(context.Context, model.BotLookupAction) error
 	ForceReview(context.Context, int64) error
+	Dossier(context.Context, model.DossierQuery) (model.Dossier, error)
 }
 
 type URLSigner interface {
@@ -76,6 +85,35 @@ func (s *Service) GetByUserID(ctx context.Context, userID int64) (model.LookupUs
 	return signedUser, nil
 }
 
+func (s *Service) Dossier(ctx context.Context, query model.DossierQuery) (model.Dossier, error) {
+	if s.repo == nil || query.UserID <= 0 {
+		return model.Dossier{}, ErrUserNotFound
+	}
+	if query.Section != "" {
+		if _, ok := enums.ParseDossierSection(query.Section); !ok {
+			return model.Dossier{}, ErrInvalidDossierSection
+		}
+	}
+	if query.Limit <= 0 {
+		query.Limit = DossierPageSize
+	}
+	if query.Limit > maxDossierPageSize {
+		query.Limit = maxDossierPageSize
+	}
+	if query.Offset < 0 {
+		query.Offset = 0
+	}
+
+	dossier, err := s.repo.Dossier(ctx, query)
+	if err != nil {
+		if errors.Is(err, pgrepo.ErrLookupUserNotFound) {
+			return model.Dossier{}, ErrUserNotFound
+		}
+		return model.Dossier{}, err
+	}
+	return dossier, nil
+}
+
 func (s *Service) ForceReview(ctx context.Context, userID int64) error {
 	if s.repo == nil {
 		return nil
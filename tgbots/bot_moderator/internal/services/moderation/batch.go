@@ -0,0 +1,173 @@
+package moderation
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"bot_moderator/internal/domain/enums"
+	"bot_moderator/internal/domain/model"
+	pgrepo "bot_moderator/internal/repo/postgres"
+)
+
+var ErrInvalidBatch = errors.New("invalid moderation batch")
+
+// AcquireBatch locks up to size queue items for the actor under one lock and loads their cards.
+func (s *Service) AcquireBatch(ctx context.Context, actorTGID int64, size int) ([]model.ModerationQueueItem, error) {
+	if size < 1 || size > model.MaxModerationBatchSize {
+		return nil, fmt.Errorf("%w: size must be between 1 and %d", ErrInvalidBatch, model.MaxModerationBatchSize)
+	}
+	if s.repo == nil {
+		return nil, ErrQueueEmpty
+	}
+
+	items, err := s.repo.AcquirePendingBatch(ctx, actorTGID, size, 10*time.Minute)
+	if err != nil {
+		if errors.Is(err, pgrepo.ErrModerationQueueEmpty) {
+			return nil, ErrQueueEmpty
+		}
+		return nil, err
+	}
+
+	out := make([]model.ModerationQueueItem, 0, len(items))
+	for _, item := range items {
+		queueItem, err := s.queueItem(ctx, item)
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, queueItem)
+	}
+	return out, nil
+}
+
+type BatchDecisionInput struct {
+	ModerationItemID int64
+	Decision         string
+	ReasonCode       string
+	Comment          string
+}
+
+type DecideBatchInput struct {
+	ActorTGID int64
+	ActorRole enums.Role
+	BatchID   string
+	// Bulk marks decisions applied with a single "approve all" confirmation.
+	Bulk      bool
+	Decisions []BatchDecisionInput
+}
+
+type BatchDecisionResult struct {
+	TargetUserID     int64
+	ModerationItemID int64
+	Decision         string
+	ReasonCode       string
+	Verified         bool
+	DurationSec      *int
+}
+
+// DecideBatch commits all decisions in one transaction and records them for Work Stats.
+// Results are returned even when recording the Work Stats actions fails afterwards.
+func (s *Service) DecideBatch(ctx context.Context, input DecideBatchInput) ([]BatchDecisionResult, error) {
+	if s.repo == nil {
+		return nil, fmt.Errorf("moderation repo is not configured")
+	}
+	if input.ActorTGID == 0 {
+		return nil, fmt.Errorf("invalid actor tg id")
+	}
+	if strings.TrimSpace(input.BatchID) == "" {
+		return nil, fmt.Errorf("%w: batch id is required", ErrInvalidBatch)
+	}
+	if len(input.Decisions) == 0 || len(input.Decisions) > model.MaxModerationBatchSize {
+		return nil, fmt.Errorf("%w: size must be between 1 and %d", ErrInvalidBatch, model.MaxModerationBatchSize)
+	}
+
+	decisions := make([]model.ModerationBatchDecision, 0, len(input.Decisions))
+	seen := make(map[int64]struct{}, len(input.Decisions))
+	for _, in := range input.Decisions {
+		if in.ModerationItemID <= 0 {
+			return nil, fmt.Errorf("%w: invalid moderation item id", ErrInvalidBatch)
+		}
+		if _, ok := seen[in.ModerationItemID]; ok {
+			return nil, fmt.Errorf("%w: duplicate moderation item %d", ErrInvalidBatch, in.ModerationItemID)
+		}
+		seen[in.ModerationItemID] = struct{}{}
+
+		decision := model.ModerationBatchDecision{
+			ModerationItemID: in.ModerationItemID,
+			Decision:         strings.ToUpper(strings.TrimSpace(in.Decision)),
+		}
+		switch decision.Decision {
+		case model.ModerationBatchApprove:
+		case model.ModerationBatchApproveVerified:
+			item, err := s.repo.GetByID(ctx, in.ModerationItemID)
+			if err != nil {
+				return nil, err
+			}
+			circleKey, err := s.repo.GetLatestCircleKey(ctx, item.UserID)
+			if err != nil {
+				return nil, err
+			}
+			if strings.TrimSpace(circleKey) == "" {
+				return nil, fmt.Errorf("moderation item %d: %w", in.ModerationItemID, ErrCircleMissing)
+			}
+		case model.ModerationBatchReject:
+			reasonCode := normalizeReasonCode(in.ReasonCode)
+			tpl, err := s.rejectReasons.Lookup(ctx, reasonCode)
+			if err != nil {
+				return nil, err
+			}
+			decision.ReasonCode = reasonCode
+			decision.ReasonText, decision.RequiredFixStep = rejectTexts(tpl, in.Comment)
+		default:
+			return nil, fmt.Errorf("%w: unknown decision %q", ErrInvalidBatch, in.Decision)
+		}
+		decisions = append(decisions, decision)
+	}
+
+	decided, err := s.repo.DecideBatch(ctx, input.ActorTGID, decisions)
+	if err != nil {
+		return nil, err
+	}
+
+	byID := make(map[int64]model.ModerationBatchDecision, len(decisions))
+	for _, decision := range decisions {
+		byID[decision.ModerationItemID] = decision
+	}
+
+	now := time.Now().UTC()
+	results := make([]BatchDecisionResult, 0, len(decided))
+	var actionErrs []error
+	for _, item := range decided {
+		decision := byID[item.ID]
+		result := BatchDecisionResult{
+			TargetUserID:     item.UserID,
+			ModerationItemID: item.ID,
+			Decision:         "APPROVE",
+			ReasonCode:       decision.ReasonCode,
+			Verified:         decision.Decision == model.ModerationBatchApproveVerified,
+			DurationSec:      calculateDurationSec(item.LockedAt),
+		}
+		if decision.Decision == model.ModerationBatchReject {
+			result.Decision = "REJECT"
+		}
+		results = append(results, result)
+
+		if err := s.repo.InsertModerationAction(ctx, model.BotModerationAction{
+			ActorTGID:        input.ActorTGID,
+			ActorRole:        string(input.ActorRole),
+			TargetUserID:     item.UserID,
+			ModerationItemID: item.ID,
+			Decision:         result.Decision,
+			ReasonCode:       result.ReasonCode,
+			DurationSec:      result.DurationSec,
+			BatchID:          input.BatchID,
+			Bulk:             input.Bulk,
+			CreatedAt:        now,
+		}); err != nil {
+			actionErrs = append(actionErrs, fmt.Errorf("record moderation item %d: %w", item.ID, err))
+		}
+	}
+	return results, errors.Join(actionErrs...)
+}
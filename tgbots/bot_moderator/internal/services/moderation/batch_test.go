@@ -0,0 +1,113 @@
+package moderation
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"bot_moderator/internal/domain/enums"
+	"bot_moderator/internal/domain/model"
+)
+
+type batchRepoStub struct {
+	Repo
+
+	circles   map[int64]string
+	decisions []model.ModerationBatchDecision
+	actions   []model.BotModerationAction
+}
+
+func (r *batchRepoStub) GetByID(_ context.Context, itemID int64) (model.ModerationItem, error) {
+	return model.ModerationItem{ID: itemID, UserID: itemID * 10}, nil
+}
+
+func (r *batchRepoStub) GetLatestCircleKey(_ context.Context, userID int64) (string, error) {
+	return r.circles[userID], nil
+}
+
+func (r *batchRepoStub) DecideBatch(_ context.Context, _ int64, decisions []model.ModerationBatchDecision) ([]model.ModerationItem, error) {
+	r.decisions = decisions
+	lockedAt := time.Now().UTC().Add(-time.Minute)
+	items := make([]model.ModerationItem, 0, len(decisions))
+	for _, decision := range decisions {
+		items = append(items, model.ModerationItem{ID: decision.ModerationItemID, UserID: decision.ModerationItemID * 10, LockedAt: &lockedAt})
+	}
+	return items, nil
+}
+
+func (r *batchRepoStub) InsertModerationAction(_ context.Context, action model.BotModerationAction) error {
+	r.actions = append(r.actions, action)
+	return nil
+}
+
+func newBatchTestService(repo *batchRepoStub) *Service {
+	svc := NewService(repo, nil)
+	svc.AttachRejectReasons(NewRejectReasonCatalog(&rejectReasonStoreStub{items: []model.RejectReason{
+		{Code: "OTHER", ReasonText: "Нужно исправить.", RequiredFixStep: "Исправьте.", IsActive: true},
+		{Code: "NSFW", ReasonText: "NSFW.", RequiredFixStep: "Замените фото.", IsActive: false},
+	}}))
+	return svc
+}
+
+func TestDecideBatchRecordsBatchActions(t *testing.T) {
+	repo := &batchRepoStub{circles: map[int64]string{20: "circle.mp4"}}
+	svc := newBatchTestService(repo)
+
+	results, err := svc.DecideBatch(context.Background(), DecideBatchInput{
+		ActorTGID: 7,
+		ActorRole: enums.RoleModerator,
+		BatchID:   "b1",
+		Decisions: []BatchDecisionInput{
+			{ModerationItemID: 1, Decision: "approve"},
+			{ModerationItemID: 2, Decision: model.ModerationBatchApproveVerified},
+			{ModerationItemID: 3, Decision: model.ModerationBatchReject, ReasonCode: "other", Comment: "без лица"},
+		},
+	})
+	if err != nil {
+		t.Fatalf("decide batch: %v", err)
+	}
+	if len(results) != 3 || !results[1].Verified || results[2].Decision != "REJECT" || results[2].DurationSec == nil {
+		t.Fatalf("unexpected results: %+v", results)
+	}
+	if got := repo.decisions[2]; got.ReasonCode != "OTHER" || got.ReasonText != "Нужно исправить. Комментарий модератора: без лица" {
+		t.Fatalf("unexpected reject decision: %+v", got)
+	}
+	if len(repo.actions) != 3 || repo.actions[0].BatchID != "b1" || repo.actions[0].Bulk || repo.actions[1].Decision != "APPROVE" {
+		t.Fatalf("unexpected recorded actions: %+v", repo.actions)
+	}
+}
+
+func TestDecideBatchValidatesBeforeCommit(t *testing.T) {
+	repo := &batchRepoStub{}
+	svc := newBatchTestService(repo)
+	ctx := context.Background()
+
+	cases := []struct {
+		decisions []BatchDecisionInput
+		want      error
+	}{
+		{[]BatchDecisionInput{{ModerationItemID: 1, Decision: "SKIP"}}, ErrInvalidBatch},
+		{[]BatchDecisionInput{{ModerationItemID: 1, Decision: "APPROVE"}, {ModerationItemID: 1, Decision: "APPROVE"}}, ErrInvalidBatch},
+		{[]BatchDecisionInput{{ModerationItemID: 2, Decision: model.ModerationBatchApproveVerified}}, ErrCircleMissing},
+		{[]BatchDecisionInput{{ModerationItemID: 3, Decision: model.ModerationBatchReject, ReasonCode: "NSFW"}}, ErrUnknownRejectReason},
+	}
+	for _, tc := range cases {
+		_, err := svc.DecideBatch(ctx, DecideBatchInput{ActorTGID: 7, BatchID: "b1", Decisions: tc.decisions})
+		if !errors.Is(err, tc.want) {
+			t.Fatalf("expected %v for %+v, got %v", tc.want, tc.decisions, err)
+		}
+	}
+	if repo.decisions != nil {
+		t.Fatalf("invalid batches must not reach the repo: %+v", repo.decisions)
+	}
+}
+
+func TestAcquireBatchRejectsInvalidSize(t *testing.T) {
+	svc := newBatchTestService(&batchRepoStub{})
+	for _, size := range []int{0, model.MaxModerationBatchSize + 1} {
+		if _, err := svc.AcquireBatch(context.Background(), 7, size); !errors.Is(err, ErrInvalidBatch) {
+			t.Fatalf("expected ErrInvalidBatch for size %d, got %v", size, err)
+		}
+	}
+}
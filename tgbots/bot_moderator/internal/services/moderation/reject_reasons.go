@@ -0,0 +1,175 @@
+package moderation
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+
+	"bot_moderator/internal/domain/enums"
+	"bot_moderator/internal/domain/model"
+)
+
+var ErrUnknownRejectReason = errors.New("unsupported reason code")
+var ErrInvalidRejectReason = errors.New("invalid reject reason")
+var ErrRejectReasonsUnavailable = errors.New("reject reason catalog is not configured")
+
+const rejectReasonCacheTTL = 30 * time.Second
+
+var rejectReasonCodePattern = regexp.MustCompile(`^[A-Z0-9_]{2,32}$`)
+
+type RejectReasonStore interface {
+	ListRejectReasons(context.Context, bool) ([]model.RejectReason, error)
+	UpsertRejectReason(context.Context, model.RejectReason) (model.RejectReason, error)
+}
+
+// RejectReasonCatalog caches the backend catalog briefly so edits from the admin panel reach the bot without a restart.
+type RejectReasonCatalog struct {
+	store RejectReasonStore
+
+	mu       sync.Mutex
+	items    []model.RejectReason
+	loadedAt time.Time
+	now      func() time.Time
+}
+
+func NewRejectReasonCatalog(store RejectReasonStore) *RejectReasonCatalog {
+	return &RejectReasonCatalog{store: store, now: time.Now}
+}
+
+func (c *RejectReasonCatalog) List(ctx context.Context, includeInactive bool) ([]model.RejectReason, error) {
+	all, err := c.load(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	items := make([]model.RejectReason, 0, len(all))
+	for _, item := range all {
+		if includeInactive || item.IsActive {
+			items = append(items, item)
+		}
+	}
+	return items, nil
+}
+
+func (c *RejectReasonCatalog) Get(ctx context.Context, code string) (model.RejectReason, error) {
+	all, err := c.load(ctx)
+	if err != nil {
+		return model.RejectReason{}, err
+	}
+	code = normalizeReasonCode(code)
+	for _, item := range all {
+		if item.Code == code {
+			return item, nil
+		}
+	}
+	return model.RejectReason{}, ErrUnknownRejectReason
+}
+
+// Lookup only resolves active reasons; disabled codes are treated as unknown.
+func (c *RejectReasonCatalog) Lookup(ctx context.Context, code string) (model.RejectReason, error) {
+	item, err := c.Get(ctx, code)
+	if err != nil {
+		return model.RejectReason{}, err
+	}
+	if !item.IsActive {
+		return model.RejectReason{}, ErrUnknownRejectReason
+	}
+	return item, nil
+}
+
+func (c *RejectReasonCatalog) Save(ctx context.Context, actorTGID int64, reason model.RejectReason) (model.RejectReason, error) {
+	if c == nil || c.store == nil {
+		return model.RejectReason{}, ErrRejectReasonsUnavailable
+	}
+	normalized, err := normalizeRejectReason(reason)
+	if err != nil {
+		return model.RejectReason{}, err
+	}
+	if actorTGID != 0 {
+		normalized.UpdatedByTGID = &actorTGID
+	}
+
+	saved, err := c.store.UpsertRejectReason(ctx, normalized)
+	if err != nil {
+		return model.RejectReason{}, err
+	}
+	c.invalidate()
+	return saved, nil
+}
+
+func (c *RejectReasonCatalog) invalidate() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.items = nil
+	c.loadedAt = time.Time{}
+}
+
+func (c *RejectReasonCatalog) load(ctx context.Context) ([]model.RejectReason, error) {
+	if c == nil || c.store == nil {
+		return nil, ErrRejectReasonsUnavailable
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.items != nil && c.now().Sub(c.loadedAt) < rejectReasonCacheTTL {
+		return c.items, nil
+	}
+
+	items, err := c.store.ListRejectReasons(ctx, true)
+	if err != nil {
+		if c.items != nil {
+			return c.items, nil
+		}
+		return nil, err
+	}
+	for i := range items {
+		items[i].Code = normalizeReasonCode(items[i].Code)
+		items[i].Label = items[i].LabelFor(model.RejectReasonDefaultLanguage)
+	}
+	c.items = items
+	c.loadedAt = c.now()
+	return items, nil
+}
+
+func normalizeRejectReason(reason model.RejectReason) (model.RejectReason, error) {
+	reason.Code = strings.ToUpper(strings.TrimSpace(reason.Code))
+	if !rejectReasonCodePattern.MatchString(reason.Code) {
+		return model.RejectReason{}, fmt.Errorf("%w: code must match [A-Z0-9_]{2,32}", ErrInvalidRejectReason)
+	}
+
+	labels := make(map[string]string, len(reason.Labels)+1)
+	for lang, label := range reason.Labels {
+		lang = strings.ToLower(strings.TrimSpace(lang))
+		label = strings.TrimSpace(label)
+		if lang != "" && label != "" {
+			labels[lang] = label
+		}
+	}
+	if label := strings.TrimSpace(reason.Label); label != "" && labels[model.RejectReasonDefaultLanguage] == "" {
+		labels[model.RejectReasonDefaultLanguage] = label
+	}
+	if labels[model.RejectReasonDefaultLanguage] == "" {
+		return model.RejectReason{}, fmt.Errorf("%w: %s label is required", ErrInvalidRejectReason, model.RejectReasonDefaultLanguage)
+	}
+	reason.Labels = labels
+	reason.Label = labels[model.RejectReasonDefaultLanguage]
+
+	reason.ReasonText = strings.TrimSpace(reason.ReasonText)
+	reason.RequiredFixStep = strings.TrimSpace(reason.RequiredFixStep)
+	if reason.ReasonText == "" || reason.RequiredFixStep == "" {
+		return model.RejectReason{}, fmt.Errorf("%w: reason text and fix step are required", ErrInvalidRejectReason)
+	}
+
+	reason.Severity = strings.ToUpper(strings.TrimSpace(reason.Severity))
+	if reason.Severity == "" {
+		reason.Severity = string(enums.RejectReasonSeverityMedium)
+	}
+	if _, ok := enums.ParseRejectReasonSeverity(reason.Severity); !ok {
+		return model.RejectReason{}, fmt.Errorf("%w: severity must be LOW, MEDIUM or HIGH", ErrInvalidRejectReason)
+	}
+	return reason, nil
+}
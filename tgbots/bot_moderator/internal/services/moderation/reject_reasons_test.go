@@ -0,0 +1,119 @@
+package moderation
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"bot_moderator/internal/domain/model"
+)
+
+type rejectReasonStoreStub struct {
+	items []model.RejectReason
+	lists int
+	saved model.RejectReason
+	err   error
+}
+
+func (s *rejectReasonStoreStub) ListRejectReasons(_ context.Context, includeInactive bool) ([]model.RejectReason, error) {
+	s.lists++
+	if s.err != nil {
+		return nil, s.err
+	}
+	items := make([]model.RejectReason, 0, len(s.items))
+	for _, item := range s.items {
+		if includeInactive || item.IsActive {
+			items = append(items, item)
+		}
+	}
+	return items, nil
+}
+
+func (s *rejectReasonStoreStub) UpsertRejectReason(_ context.Context, reason model.RejectReason) (model.RejectReason, error) {
+	s.saved = reason
+	for i, item := range s.items {
+		if item.Code == reason.Code {
+			s.items[i] = reason
+			return reason, nil
+		}
+	}
+	s.items = append(s.items, reason)
+	return reason, nil
+}
+
+func testRejectReasonCatalog() (*RejectReasonCatalog, *rejectReasonStoreStub) {
+	store := &rejectReasonStoreStub{items: []model.RejectReason{
+		{
+			Code:            "PHOTO_NO_FACE",
+			Labels:          map[string]string{"ru": "Фото: не видно лица"},
+			ReasonText:      "На фото не видно лица.",
+			RequiredFixStep: "Загрузите фото, где лицо хорошо различимо.",
+			Severity:        "LOW",
+			IsActive:        true,
+		},
+		{
+			Code:            "LEGACY",
+			Labels:          map[string]string{"en": "Legacy"},
+			ReasonText:      "text",
+			RequiredFixStep: "step",
+			Severity:        "LOW",
+		},
+	}}
+	return NewRejectReasonCatalog(store), store
+}
+
+func TestRejectReasonCatalogCachesAndSkipsInactive(t *testing.T) {
+	catalog, store := testRejectReasonCatalog()
+	now := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	catalog.now = func() time.Time { return now }
+	ctx := context.Background()
+
+	active, err := catalog.List(ctx, false)
+	if err != nil {
+		t.Fatalf("list: %v", err)
+	}
+	if len(active) != 1 || active[0].Label != "Фото: не видно лица" {
+		t.Fatalf("unexpected active reasons: %+v", active)
+	}
+	if _, err := catalog.Lookup(ctx, "legacy"); !errors.Is(err, ErrUnknownRejectReason) {
+		t.Fatalf("expected inactive reason to be unknown, got %v", err)
+	}
+	if store.lists != 1 {
+		t.Fatalf("expected cached catalog, store listed %d times", store.lists)
+	}
+
+	now = now.Add(rejectReasonCacheTTL)
+	store.err = errors.New("backend down")
+	if _, err := catalog.Lookup(ctx, "PHOTO_NO_FACE"); err != nil {
+		t.Fatalf("expected stale catalog on refresh failure, got %v", err)
+	}
+}
+
+func TestRejectReasonCatalogSaveValidatesAndInvalidates(t *testing.T) {
+	catalog, store := testRejectReasonCatalog()
+	ctx := context.Background()
+
+	if _, err := catalog.Save(ctx, 1, model.RejectReason{Code: "LEGACY", ReasonText: "t", RequiredFixStep: "s"}); !errors.Is(err, ErrInvalidRejectReason) {
+		t.Fatalf("expected missing ru label to be rejected, got %v", err)
+	}
+
+	if _, err := catalog.Lookup(ctx, "LEGACY"); !errors.Is(err, ErrUnknownRejectReason) {
+		t.Fatalf("expected inactive reason, got %v", err)
+	}
+	legacy, err := catalog.Get(ctx, "LEGACY")
+	if err != nil {
+		t.Fatalf("get: %v", err)
+	}
+	legacy.IsActive = true
+	legacy.Label = "Устарело"
+	if _, err := catalog.Save(ctx, 700, legacy); err != nil {
+		t.Fatalf("save: %v", err)
+	}
+	if store.saved.UpdatedByTGID == nil || *store.saved.UpdatedByTGID != 700 || store.saved.Labels["ru"] != "Устарело" {
+		t.Fatalf("unexpected saved reason: %+v", store.saved)
+	}
+	if _, err := catalog.Lookup(ctx, "LEGACY"); err != nil {
+		t.Fatalf("expected reactivated reason after save, got %v", err)
+	}
+}
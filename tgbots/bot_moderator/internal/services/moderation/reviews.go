@@ -0,0 +1,190 @@
+package moderation
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"bot_moderator/internal/domain/model"
+	pgrepo "bot_moderator/internal/repo/postgres"
+)
+
+var ErrReviewQueueEmpty = errors.New("review queue is empty")
+var ErrSameReviewer = errors.New("review must be done by a different moderator")
+var ErrReviewNotPending = errors.New("review is already resolved")
+var ErrInvalidReviewKind = errors.New("invalid review kind")
+var ErrInvalidReviewOutcome = errors.New("invalid review outcome")
+var ErrReviewReasonRequired = errors.New("qa overturn requires a reject reason")
+var ErrReviewNotFound = errors.New("review not found")
+var ErrReviewNotLocked = errors.New("review is not locked by the reviewer")
+var ErrReviewForbidden = errors.New("not allowed to resolve this review kind")
+
+const (
+	reviewLockTTL     = 10 * time.Minute
+	reviewStatsWindow = 7 * 24 * time.Hour
+)
+
+type ReviewRepo interface {
+	AcquireNextReview(context.Context, string, int64, time.Duration) (model.ModerationReviewItem, error)
+	GetReview(context.Context, int64) (model.ModerationReview, error)
+	ResolveReview(context.Context, model.ModerationReviewDecision) (model.ModerationReview, error)
+	ReviewStats(context.Context, time.Duration) (model.ModerationReviewStats, error)
+}
+
+type ReviewService struct {
+	repo          ReviewRepo
+	signer        URLSigner
+	rejectReasons *RejectReasonCatalog
+}
+
+func NewReviewService(repo ReviewRepo, signer URLSigner) *ReviewService {
+	return &ReviewService{repo: repo, signer: signer}
+}
+
+func (s *ReviewService) AttachRejectReasons(catalog *RejectReasonCatalog) {
+	s.rejectReasons = catalog
+}
+
+func (s *ReviewService) AcquireNext(ctx context.Context, kind string, reviewerTGID int64) (model.ModerationReviewQueueItem, error) {
+	kind, ok := normalizeReviewKind(kind)
+	if !ok {
+		return model.ModerationReviewQueueItem{}, ErrInvalidReviewKind
+	}
+	if s.repo == nil {
+		return model.ModerationReviewQueueItem{}, ErrReviewQueueEmpty
+	}
+
+	item, err := s.repo.AcquireNextReview(ctx, kind, reviewerTGID, reviewLockTTL)
+	if err != nil {
+		if errors.Is(err, pgrepo.ErrReviewQueueEmpty) {
+			return model.ModerationReviewQueueItem{}, ErrReviewQueueEmpty
+		}
+		return model.ModerationReviewQueueItem{}, err
+	}
+
+	photoURLs := make([]string, 0, len(item.PhotoKeys))
+	for _, key := range item.PhotoKeys {
+		url, signErr := signMediaKey(ctx, s.signer, key)
+		if signErr != nil {
+			return model.ModerationReviewQueueItem{}, signErr
+		}
+		if strings.TrimSpace(url) != "" {
+			photoURLs = append(photoURLs, url)
+		}
+	}
+	circleURL, err := signMediaKey(ctx, s.signer, item.CircleKey)
+	if err != nil {
+		return model.ModerationReviewQueueItem{}, err
+	}
+
+	return model.ModerationReviewQueueItem{
+		Review:    item.Review,
+		Profile:   item.Profile,
+		PhotoURLs: photoURLs,
+		CircleURL: circleURL,
+	}, nil
+}
+
+type ResolveReviewInput struct {
+	ReviewID     int64
+	ReviewerTGID int64
+	Outcome      string
+	ReasonCode   string
+	Note         string
+}
+
+// Get loads a review by id; the kind it reports is the stored one, not anything a caller sent.
+func (s *ReviewService) Get(ctx context.Context, reviewID int64) (model.ModerationReview, error) {
+	if s.repo == nil {
+		return model.ModerationReview{}, fmt.Errorf("review repo is not configured")
+	}
+	if reviewID <= 0 {
+		return model.ModerationReview{}, fmt.Errorf("invalid review id")
+	}
+
+	review, err := s.repo.GetReview(ctx, reviewID)
+	if errors.Is(err, pgrepo.ErrReviewNotFound) {
+		return model.ModerationReview{}, ErrReviewNotFound
+	}
+	return review, err
+}
+
+// Resolve loads the review, checks canResolve and the reason requirement against its stored
+// kind, and records the decision.
+func (s *ReviewService) Resolve(ctx context.Context, input ResolveReviewInput, canResolve func(kind string) bool) (model.ModerationReview, error) {
+	if input.ReviewerTGID == 0 {
+		return model.ModerationReview{}, fmt.Errorf("invalid reviewer tg id")
+	}
+
+	decision := model.ModerationReviewDecision{
+		ReviewID:     input.ReviewID,
+		ReviewerTGID: input.ReviewerTGID,
+		Outcome:      strings.ToUpper(strings.TrimSpace(input.Outcome)),
+		Note:         strings.TrimSpace(input.Note),
+	}
+	switch decision.Outcome {
+	case model.ModerationReviewOutcomeUphold, model.ModerationReviewOutcomeOverturn:
+	default:
+		return model.ModerationReview{}, ErrInvalidReviewOutcome
+	}
+
+	review, err := s.Get(ctx, input.ReviewID)
+	if err != nil {
+		return model.ModerationReview{}, err
+	}
+	if canResolve == nil || !canResolve(review.Kind) {
+		return model.ModerationReview{}, ErrReviewForbidden
+	}
+	if review.Status != model.ModerationReviewStatusPending {
+		return model.ModerationReview{}, ErrReviewNotPending
+	}
+
+	if decision.Outcome == model.ModerationReviewOutcomeOverturn && review.Kind == model.ModerationReviewKindQA {
+		reasonCode := strings.TrimSpace(input.ReasonCode)
+		if reasonCode == "" {
+			return model.ModerationReview{}, ErrReviewReasonRequired
+		}
+		code := normalizeReasonCode(reasonCode)
+		tpl, err := s.rejectReasons.Lookup(ctx, code)
+		if err != nil {
+			return model.ModerationReview{}, err
+		}
+		decision.ReasonCode = code
+		decision.ReasonText = tpl.ReasonText
+		decision.RequiredFixStep = tpl.RequiredFixStep
+	}
+
+	resolved, err := s.repo.ResolveReview(ctx, decision)
+	switch {
+	case errors.Is(err, pgrepo.ErrReviewSameModerator):
+		return model.ModerationReview{}, ErrSameReviewer
+	case errors.Is(err, pgrepo.ErrReviewNotPending):
+		return model.ModerationReview{}, ErrReviewNotPending
+	case errors.Is(err, pgrepo.ErrReviewNotLocked):
+		return model.ModerationReview{}, ErrReviewNotLocked
+	case errors.Is(err, pgrepo.ErrReviewNotFound):
+		return model.ModerationReview{}, ErrReviewNotFound
+	case err != nil:
+		return model.ModerationReview{}, err
+	}
+	return resolved, nil
+}
+
+func (s *ReviewService) Stats(ctx context.Context) (model.ModerationReviewStats, error) {
+	if s.repo == nil {
+		return model.ModerationReviewStats{WindowHours: int(reviewStatsWindow / time.Hour)}, nil
+	}
+	return s.repo.ReviewStats(ctx, reviewStatsWindow)
+}
+
+func normalizeReviewKind(raw string) (string, bool) {
+	kind := strings.ToUpper(strings.TrimSpace(raw))
+	switch kind {
+	case model.ModerationReviewKindAppeal, model.ModerationReviewKindQA:
+		return kind, true
+	default:
+		return "", false
+	}
+}
@@ -0,0 +1,135 @@
+package moderation
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"bot_moderator/internal/domain/model"
+	pgrepo "bot_moderator/internal/repo/postgres"
+)
+
+type reviewRepoStub struct {
+	review   model.ModerationReview
+	decision model.ModerationReviewDecision
+	resolved bool
+	err      error
+}
+
+func (s *reviewRepoStub) GetReview(_ context.Context, reviewID int64) (model.ModerationReview, error) {
+	if s.review.ID != reviewID {
+		return model.ModerationReview{}, pgrepo.ErrReviewNotFound
+	}
+	return s.review, nil
+}
+
+func (s *reviewRepoStub) AcquireNextReview(context.Context, string, int64, time.Duration) (model.ModerationReviewItem, error) {
+	return model.ModerationReviewItem{}, pgrepo.ErrReviewQueueEmpty
+}
+
+func (s *reviewRepoStub) ResolveReview(_ context.Context, decision model.ModerationReviewDecision) (model.ModerationReview, error) {
+	s.decision = decision
+	s.resolved = true
+	if s.err != nil {
+		return model.ModerationReview{}, s.err
+	}
+	return model.ModerationReview{ID: decision.ReviewID, Status: model.ModerationReviewStatusOverturned}, nil
+}
+
+func (s *reviewRepoStub) ReviewStats(context.Context, time.Duration) (model.ModerationReviewStats, error) {
+	return model.ModerationReviewStats{}, nil
+}
+
+func pendingReview(kind string) model.ModerationReview {
+	return model.ModerationReview{ID: 1, Kind: kind, Status: model.ModerationReviewStatusPending}
+}
+
+func allowAll(string) bool { return true }
+
+func TestReviewResolveQAOverturnRequiresReason(t *testing.T) {
+	svc := NewReviewService(&reviewRepoStub{review: pendingReview(model.ModerationReviewKindQA)}, nil)
+
+	_, err := svc.Resolve(context.Background(), ResolveReviewInput{
+		ReviewID:     1,
+		ReviewerTGID: 100,
+		Outcome:      model.ModerationReviewOutcomeOverturn,
+	}, allowAll)
+	if !errors.Is(err, ErrReviewReasonRequired) {
+		t.Fatalf("expected ErrReviewReasonRequired, got %v", err)
+	}
+}
+
+func TestReviewResolveFillsRejectTemplate(t *testing.T) {
+	repo := &reviewRepoStub{review: pendingReview(model.ModerationReviewKindQA)}
+	svc := NewReviewService(repo, nil)
+	catalog, _ := testRejectReasonCatalog()
+	svc.AttachRejectReasons(catalog)
+
+	if _, err := svc.Resolve(context.Background(), ResolveReviewInput{
+		ReviewID:     1,
+		ReviewerTGID: 100,
+		Outcome:      "overturn",
+		ReasonCode:   "photo_no_face",
+	}, allowAll); err != nil {
+		t.Fatalf("resolve: %v", err)
+	}
+	if repo.decision.ReasonCode != "PHOTO_NO_FACE" || repo.decision.ReasonText == "" || repo.decision.RequiredFixStep == "" {
+		t.Fatalf("expected reject template in decision, got %+v", repo.decision)
+	}
+}
+
+func TestReviewResolveAuthorizesStoredKind(t *testing.T) {
+	repo := &reviewRepoStub{review: pendingReview(model.ModerationReviewKindQA)}
+	svc := NewReviewService(repo, nil)
+
+	var checked []string
+	appealOnly := func(kind string) bool {
+		checked = append(checked, kind)
+		return kind == model.ModerationReviewKindAppeal
+	}
+	_, err := svc.Resolve(context.Background(), ResolveReviewInput{
+		ReviewID:     1,
+		ReviewerTGID: 100,
+		Outcome:      model.ModerationReviewOutcomeUphold,
+	}, appealOnly)
+	if !errors.Is(err, ErrReviewForbidden) {
+		t.Fatalf("expected ErrReviewForbidden, got %v", err)
+	}
+	if len(checked) != 1 || checked[0] != model.ModerationReviewKindQA {
+		t.Fatalf("expected the stored QA kind to be checked, got %v", checked)
+	}
+	if repo.resolved {
+		t.Fatalf("a forbidden review must not be resolved")
+	}
+
+	if _, err := svc.Resolve(context.Background(), ResolveReviewInput{
+		ReviewID:     2,
+		ReviewerTGID: 100,
+		Outcome:      model.ModerationReviewOutcomeUphold,
+	}, allowAll); !errors.Is(err, ErrReviewNotFound) {
+		t.Fatalf("expected ErrReviewNotFound, got %v", err)
+	}
+}
+
+func TestReviewResolveMapsRepoErrors(t *testing.T) {
+	for _, tc := range []struct {
+		repoErr error
+		want    error
+	}{
+		{repoErr: pgrepo.ErrReviewSameModerator, want: ErrSameReviewer},
+		{repoErr: pgrepo.ErrReviewNotLocked, want: ErrReviewNotLocked},
+		{repoErr: pgrepo.ErrReviewNotPending, want: ErrReviewNotPending},
+	} {
+		svc := NewReviewService(&reviewRepoStub{review: pendingReview(model.ModerationReviewKindAppeal), err: tc.repoErr}, nil)
+
+		_, err := svc.Resolve(context.Background(), ResolveReviewInput{
+			ReviewID:     1,
+			ReviewerTGID: 100,
+			Outcome:      model.ModerationReviewOutcomeUphold,
+		}, allowAll)
+		if !errors.Is(err, tc.want) {
+			t.Fatalf("expected %v for %v, got %v", tc.want, tc.repoErr, err)
+		}
+	}
+}
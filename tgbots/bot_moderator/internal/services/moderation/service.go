@@ -13,6 +13,7 @@ import (
 )
 
 var ErrQueueEmpty = errors.New("moderation queue is empty")
+var ErrCircleMissing = errors.New("verification circle is missing")
 
 const signedURLTTL = 5 * time.Minute
 
@@ -21,10 +22,13 @@ type Repo interface {
 	GetProfile(context.Context, int64) (model.ModerationProfile, error)
 	ListPhotoKeys(context.Context, int64, int) ([]string, error)
 	GetLatestCircleKey(context.Context, int64) (string, error)
+	ListPhotoMatches(context.Context, int64) ([]model.PhotoMatch, error)
 	GetByID(context.Context, int64) (model.ModerationItem, error)
-	MarkApproved(context.Context, int64) error
-	MarkRejected(context.Context, int64, string, string, string) error
+	MarkApproved(context.Context, int64, int64, bool) error
+	MarkRejected(context.Context, int64, int64, string, string, string) error
 	InsertModerationAction(context.Context, model.BotModerationAction) error
+	AcquirePendingBatch(context.Context, int64, int, time.Duration) ([]model.ModerationItem, error)
+	DecideBatch(context.Context, int64, []model.ModerationBatchDecision) ([]model.ModerationItem, error)
 }
 
 type URLSigner interface {
@@ -32,14 +36,23 @@ type URLSigner interface {
 }
 
 type Service struct {
-	repo   Repo
-	signer URLSigner
+	repo          Repo
+	signer        URLSigner
+	rejectReasons *RejectReasonCatalog
 }
 
 func NewService(repo Repo, signer URLSigner) *Service {
 	return &Service{repo: repo, signer: signer}
 }
 
+func (s *Service) AttachRejectReasons(catalog *RejectReasonCatalog) {
+	s.rejectReasons = catalog
+}
+
+func (s *Service) RejectReasons() *RejectReasonCatalog {
+	return s.rejectReasons
+}
+
 func (s *Service) AcquireNextPending(ctx context.Context, actorTGID int64) (model.ModerationQueueItem, error) {
 	if s.repo == nil {
 		return model.ModerationQueueItem{}, ErrQueueEmpty
@@ -52,7 +65,10 @@ func (s *Service) AcquireNextPending(ctx context.Context, actorTGID int64) (mode
 		}
 		return model.ModerationQueueItem{}, err
 	}
+	return s.queueItem(ctx, item)
+}
 
+func (s *Service) queueItem(ctx context.Context, item model.ModerationItem) (model.ModerationQueueItem, error) {
 	profile, err := s.repo.GetProfile(ctx, item.UserID)
 	if err != nil {
 		return model.ModerationQueueItem{}, err
@@ -68,6 +84,11 @@ func (s *Service) AcquireNextPending(ctx context.Context, actorTGID int64) (mode
 		return model.ModerationQueueItem{}, err
 	}
 
+	photoMatches, err := s.repo.ListPhotoMatches(ctx, item.UserID)
+	if err != nil {
+		return model.ModerationQueueItem{}, err
+	}
+
 	lockedAt := time.Now().UTC()
 	if item.LockedAt != nil {
 		lockedAt = item.LockedAt.UTC()
@@ -98,10 +119,15 @@ func (s *Service) AcquireNextPending(ctx context.Context, actorTGID int64) (mode
 		Profile:          profile,
 		PhotoURLs:        photoURLs,
 		CircleURL:        circleURL,
+		PhotoMatches:     photoMatches,
 	}, nil
 }
 
 func (s *Service) signKey(ctx context.Context, key string) (string, error) {
+	return signMediaKey(ctx, s.signer, key)
+}
+
+func signMediaKey(ctx context.Context, signer URLSigner, key string) (string, error) {
 	trimmed := strings.TrimSpace(key)
 	if trimmed == "" {
 		return "", nil
@@ -109,21 +135,23 @@ func (s *Service) signKey(ctx context.Context, key string) (string, error) {
 	if strings.HasPrefix(trimmed, "http://") || strings.HasPrefix(trimmed, "https://") {
 		return trimmed, nil
 	}
-	if s.signer == nil {
+	if signer == nil {
 		return "", fmt.Errorf("moderation url signer is not configured")
 	}
-	return s.signer.PresignGet(ctx, trimmed, signedURLTTL)
+	return signer.PresignGet(ctx, trimmed, signedURLTTL)
 }
 
 type ApproveInput struct {
 	ActorTGID        int64
 	ActorRole        enums.Role
 	ModerationItemID int64
+	Verified         bool
 }
 
 type ApproveResult struct {
 	TargetUserID     int64
 	ModerationItemID int64
+	Verified         bool
 	DurationSec      *int
 }
 
@@ -142,8 +170,17 @@ func (s *Service) Approve(ctx context.Context, input ApproveInput) (ApproveResul
 	if err != nil {
 		return ApproveResult{}, err
 	}
+	if input.Verified {
+		circleKey, err := s.repo.GetLatestCircleKey(ctx, item.UserID)
+		if err != nil {
+			return ApproveResult{}, err
+		}
+		if strings.TrimSpace(circleKey) == "" {
+			return ApproveResult{}, ErrCircleMissing
+		}
+	}
 
-	if err := s.repo.MarkApproved(ctx, input.ModerationItemID); err != nil {
+	if err := s.repo.MarkApproved(ctx, input.ModerationItemID, input.ActorTGID, input.Verified); err != nil {
 		return ApproveResult{}, err
 	}
 
@@ -164,6 +201,7 @@ func (s *Service) Approve(ctx context.Context, input ApproveInput) (ApproveResul
 	return ApproveResult{
 		TargetUserID:     item.UserID,
 		ModerationItemID: input.ModerationItemID,
+		Verified:         input.Verified,
 		DurationSec:      durationSec,
 	}, nil
 }
@@ -183,50 +221,6 @@ type RejectResult struct {
 	DurationSec      *int
 }
 
-type rejectTemplate struct {
-	reasonText      string
-	requiredFixStep string
-}
-
-var rejectTemplates = map[string]rejectTemplate{
-	"PHOTO_NO_FACE": {
-		reasonText:      "На фото не видно лица.",
-		requiredFixStep: "Загрузите фото, где лицо хорошо различимо.",
-	},
-	"PHOTO_FAKE_NOT_YOU": {
-		reasonText:      "Фото не соответствует владельцу анкеты.",
-		requiredFixStep: "Загрузите ваши реальные фотографии без чужих изображений.",
-	},
-	"PHOTO_PROHIBITED": {
-		reasonText:      "Обнаружен запрещенный фото-контент.",
-		requiredFixStep: "Удалите запрещённый контент и загрузите новые фото.",
-	},
-	"CIRCLE_MISMATCH": {
-		reasonText:      "Кружок не совпадает с фотографиями анкеты.",
-		requiredFixStep: "Перезапишите кружок, чтобы внешность совпадала с фото.",
-	},
-	"CIRCLE_FAILED": {
-		reasonText:      "Кружок не прошел проверку качества.",
-		requiredFixStep: "Перезапишите кружок при хорошем освещении и стабильной связи.",
-	},
-	"PROFILE_INCOMPLETE": {
-		reasonText:      "Профиль заполнен не полностью.",
-		requiredFixStep: "Заполните обязательные поля профиля и отправьте на модерацию повторно.",
-	},
-	"SPAM_ADS_LINKS": {
-		reasonText:      "Обнаружены признаки спама, рекламы или внешних ссылок.",
-		requiredFixStep: "Удалите спам/рекламу/ссылки из профиля и отправьте на модерацию повторно.",
-	},
-	"BOT_SUSPECT": {
-		reasonText:      "Профиль помечен как подозрительный на автоматизацию.",
-		requiredFixStep: "Обновите анкету и пройдите повторную модерацию вручную.",
-	},
-	"OTHER": {
-		reasonText:      "Требуется корректировка анкеты.",
-		requiredFixStep: "Обновите анкету по замечанию модератора и отправьте на модерацию повторно.",
-	},
-}
-
 func (s *Service) Reject(ctx context.Context, input RejectInput) (RejectResult, error) {
 	if s.repo == nil {
 		return RejectResult{}, fmt.Errorf("moderation repo is not configured")
@@ -239,9 +233,9 @@ func (s *Service) Reject(ctx context.Context, input RejectInput) (RejectResult,
 	}
 
 	reasonCode := normalizeReasonCode(input.ReasonCode)
-	tpl, ok := rejectTemplates[reasonCode]
-	if !ok {
-		return RejectResult{}, fmt.Errorf("unsupported reason code")
+	tpl, err := s.rejectReasons.Lookup(ctx, reasonCode)
+	if err != nil {
+		return RejectResult{}, err
 	}
 
 	item, err := s.repo.GetByID(ctx, input.ModerationItemID)
@@ -249,15 +243,9 @@ func (s *Service) Reject(ctx context.Context, input RejectInput) (RejectResult,
 		return RejectResult{}, err
 	}
 
-	reasonText := tpl.reasonText
-	requiredFixStep := tpl.requiredFixStep
-	comment := strings.TrimSpace(input.Comment)
-	if comment != "" {
-		reasonText = fmt.Sprintf("%s Комментарий модератора: %s", reasonText, comment)
-		requiredFixStep = fmt.Sprintf("%s Комментарий модератора: %s", requiredFixStep, comment)
-	}
+	reasonText, requiredFixStep := rejectTexts(tpl, input.Comment)
 
-	if err := s.repo.MarkRejected(ctx, input.ModerationItemID, reasonCode, reasonText, requiredFixStep); err != nil {
+	if err := s.repo.MarkRejected(ctx, input.ModerationItemID, input.ActorTGID, reasonCode, reasonText, requiredFixStep); err != nil {
 		return RejectResult{}, err
 	}
 
@@ -285,10 +273,20 @@ func (s *Service) Reject(ctx context.Context, input RejectInput) (RejectResult,
 	}, nil
 }
 
+func rejectTexts(tpl model.RejectReason, comment string) (string, string) {
+	reasonText := tpl.ReasonText
+	requiredFixStep := tpl.RequiredFixStep
+	if comment = strings.TrimSpace(comment); comment != "" {
+		reasonText = fmt.Sprintf("%s Комментарий модератора: %s", reasonText, comment)
+		requiredFixStep = fmt.Sprintf("%s Комментарий модератора: %s", requiredFixStep, comment)
+	}
+	return reasonText, requiredFixStep
+}
+
 func normalizeReasonCode(raw string) string {
 	code := strings.ToUpper(strings.TrimSpace(raw))
 	if code == "" {
-		return "OTHER"
+		return enums.RejectReasonOther
 	}
 	return code
 }
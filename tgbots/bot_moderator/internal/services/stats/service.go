@@ -9,6 +9,13 @@ import (
 
 const minskLocationName = "Europe/Minsk"
 
+const (
+	slaReportWindow = 24 * time.Hour
+	slaAlertWindow  = time.Hour
+
+	batchReportWindow = 7 * 24 * time.Hour
+)
+
 type PeriodBounds struct {
 	DayStart   time.Time
 	DayEnd     time.Time
@@ -20,6 +27,8 @@ type PeriodBounds struct {
 
 type Repo interface {
 	Aggregate(context.Context, PeriodBounds) (model.WorkStatsReport, error)
+	SLA(context.Context, time.Duration) (model.ModerationSLAReport, error)
+	BatchThroughput(context.Context, time.Duration) (model.ModerationBatchStats, error)
 }
 
 type Service struct {
@@ -55,6 +64,39 @@ func (s *Service) BuildReport(ctx context.Context) (model.WorkStatsReport, error
 	return s.repo.Aggregate(ctx, bounds)
 }
 
+func (s *Service) BuildSLAReport(ctx context.Context) (model.ModerationSLAReport, error) {
+	if s.repo == nil {
+		return model.ModerationSLAReport{}, nil
+	}
+	return s.repo.SLA(ctx, slaReportWindow)
+}
+
+func (s *Service) BuildBatchReport(ctx context.Context) (model.ModerationBatchStats, error) {
+	if s.repo == nil {
+		return model.ModerationBatchStats{WindowHours: int(batchReportWindow / time.Hour)}, nil
+	}
+	return s.repo.BatchThroughput(ctx, batchReportWindow)
+}
+
+// CheckSLA reports whether the p90 wait over the last hour exceeds threshold. A queue
+// with no decisions in that hour is judged by its oldest pending item instead.
+func (s *Service) CheckSLA(ctx context.Context, threshold time.Duration) (model.ModerationSLAReport, bool, error) {
+	if s.repo == nil || threshold <= 0 {
+		return model.ModerationSLAReport{}, false, nil
+	}
+
+	report, err := s.repo.SLA(ctx, slaAlertWindow)
+	if err != nil {
+		return model.ModerationSLAReport{}, false, err
+	}
+
+	limit := threshold.Seconds()
+	if report.Decided == 0 {
+		return report, report.OldestPendingSec > limit, nil
+	}
+	return report, report.WaitP90Sec > limit, nil
+}
+
 func computePeriodBounds(now time.Time, loc *time.Location) PeriodBounds {
 	localNow := now.In(loc)
 	year, month, day := localNow.Date()
@@ -20,6 +20,18 @@ type fixtureAction struct {
 type fakeRepo struct {
 	actions []fixtureAction
 	bounds  PeriodBounds
+	sla     model.ModerationSLAReport
+	window  time.Duration
+}
+
+func (r *fakeRepo) SLA(_ context.Context, window time.Duration) (model.ModerationSLAReport, error) {
+	r.window = window
+	return r.sla, nil
+}
+
+func (r *fakeRepo) BatchThroughput(_ context.Context, window time.Duration) (model.ModerationBatchStats, error) {
+	r.window = window
+	return model.ModerationBatchStats{WindowHours: int(window / time.Hour)}, nil
 }
 
 func (r *fakeRepo) Aggregate(_ context.Context, bounds PeriodBounds) (model.WorkStatsReport, error) {
@@ -163,3 +175,34 @@ func TestBuildReportEuropeMinskBoundaries(t *testing.T) {
 		t.Fatalf("unexpected actorTwo stats: %+v", actorTwo)
 	}
 }
+
+func TestCheckSLA(t *testing.T) {
+	tests := []struct {
+		name string
+		sla  model.ModerationSLAReport
+		want bool
+	}{
+		{name: "p90 under threshold", sla: model.ModerationSLAReport{Decided: 5, WaitP90Sec: 600}, want: false},
+		{name: "p90 over threshold", sla: model.ModerationSLAReport{Decided: 5, WaitP90Sec: 2400}, want: true},
+		{name: "stalled queue uses oldest pending", sla: model.ModerationSLAReport{Pending: 3, OldestPendingSec: 3600}, want: true},
+		{name: "empty queue", sla: model.ModerationSLAReport{}, want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := &fakeRepo{sla: tt.sla}
+			svc := newService(repo, time.Now, time.UTC)
+
+			_, breached, err := svc.CheckSLA(context.Background(), 30*time.Minute)
+			if err != nil {
+				t.Fatalf("check sla: %v", err)
+			}
+			if breached != tt.want {
+				t.Fatalf("unexpected breach: got %v want %v", breached, tt.want)
+			}
+			if repo.window != time.Hour {
+				t.Fatalf("unexpected sla window: %s", repo.window)
+			}
+		})
+	}
+}
@@ -0,0 +1,317 @@
+package system
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"bot_moderator/internal/domain/model"
+)
+
+// RegistrationFlagKey is also toggled by the Registration button; it can't be deleted or retyped.
+const RegistrationFlagKey = "registration_enabled"
+
+const (
+	maxFlagRules       = 20
+	maxFlagRuleUserIDs = 1000
+	maxFlagStringLen   = 256
+	maxFlagDescription = 200
+)
+
+var (
+	ErrInvalidFlag      = errors.New("invalid flag")
+	ErrBuiltinFlag      = errors.New("built-in flag cannot be deleted or retyped")
+	ErrFlagNotFound     = errors.New("flag not found")
+	ErrFlagsUnavailable = errors.New("flags are unavailable")
+)
+
+var flagKeyPattern = regexp.MustCompile(`^[a-z][a-z0-9_]{1,63}$`)
+
+type FlagsRepo interface {
+	ListFlags(context.Context) ([]model.AppFlag, error)
+	UpsertFlag(context.Context, model.AppFlag, int64) (model.AppFlag, error)
+	DeleteFlag(context.Context, string) error
+}
+
+func (s *Service) AttachFlags(repo FlagsRepo) {
+	s.flags = repo
+}
+
+func (s *Service) ListFlags(ctx context.Context) ([]model.AppFlag, error) {
+	if s.flags == nil {
+		return nil, ErrFlagsUnavailable
+	}
+	return s.flags.ListFlags(ctx)
+}
+
+func (s *Service) GetFlag(ctx context.Context, key string) (model.AppFlag, error) {
+	items, err := s.ListFlags(ctx)
+	if err != nil {
+		return model.AppFlag{}, err
+	}
+	for _, item := range items {
+		if item.Key == key {
+			return item, nil
+		}
+	}
+	return model.AppFlag{}, ErrFlagNotFound
+}
+
+// SaveFlag validates the flag here as well, since in db mode nothing else stands between the
+// bot and app_flags.
+func (s *Service) SaveFlag(ctx context.Context, actorTGID int64, flag model.AppFlag) (model.AppFlag, error) {
+	if s.flags == nil {
+		return model.AppFlag{}, ErrFlagsUnavailable
+	}
+	normalized, err := NormalizeFlag(flag)
+	if err != nil {
+		return model.AppFlag{}, err
+	}
+	if normalized.Key == RegistrationFlagKey && normalized.Type != model.AppFlagTypeBool {
+		return model.AppFlag{}, ErrBuiltinFlag
+	}
+	return s.flags.UpsertFlag(ctx, normalized, actorTGID)
+}
+
+func (s *Service) DeleteFlag(ctx context.Context, key string) error {
+	if s.flags == nil {
+		return ErrFlagsUnavailable
+	}
+	if key == RegistrationFlagKey {
+		return ErrBuiltinFlag
+	}
+	return s.flags.DeleteFlag(ctx, key)
+}
+
+func NormalizeFlag(flag model.AppFlag) (model.AppFlag, error) {
+	flag.Key = strings.ToLower(strings.TrimSpace(flag.Key))
+	if !flagKeyPattern.MatchString(flag.Key) {
+		return model.AppFlag{}, fmt.Errorf("%w: ключ — латиница a-z, цифры и _, от 2 до 64 символов", ErrInvalidFlag)
+	}
+	flag.Type = strings.ToLower(strings.TrimSpace(flag.Type))
+	if flag.Type == "" {
+		flag.Type = model.AppFlagTypeBool
+	}
+	if flag.Type != model.AppFlagTypeBool && flag.Type != model.AppFlagTypeInt && flag.Type != model.AppFlagTypeString {
+		return model.AppFlag{}, fmt.Errorf("%w: тип должен быть bool, int или string", ErrInvalidFlag)
+	}
+
+	value, err := normalizeFlagJSON(flag.Type, flag.Value)
+	if err != nil {
+		return model.AppFlag{}, fmt.Errorf("%w: значение: %v", ErrInvalidFlag, err)
+	}
+	flag.Value = value
+
+	flag.Description = strings.TrimSpace(flag.Description)
+	if len([]rune(flag.Description)) > maxFlagDescription {
+		return model.AppFlag{}, fmt.Errorf("%w: описание длиннее %d символов", ErrInvalidFlag, maxFlagDescription)
+	}
+
+	if len(flag.Rules) > maxFlagRules {
+		return model.AppFlag{}, fmt.Errorf("%w: не больше %d правил", ErrInvalidFlag, maxFlagRules)
+	}
+	rules := make([]model.AppFlagRule, 0, len(flag.Rules))
+	for i, rule := range flag.Rules {
+		if len(rule.UserIDs) > maxFlagRuleUserIDs {
+			return model.AppFlag{}, fmt.Errorf("%w: правило %d: не больше %d user id", ErrInvalidFlag, i+1, maxFlagRuleUserIDs)
+		}
+		for _, id := range rule.UserIDs {
+			if id <= 0 {
+				return model.AppFlag{}, fmt.Errorf("%w: правило %d: user id должны быть положительными", ErrInvalidFlag, i+1)
+			}
+		}
+		if rule.Percentage != nil && (*rule.Percentage < 0 || *rule.Percentage > 100) {
+			return model.AppFlag{}, fmt.Errorf("%w: правило %d: pct от 0 до 100", ErrInvalidFlag, i+1)
+		}
+		value, err := normalizeFlagJSON(flag.Type, rule.Value)
+		if err != nil {
+			return model.AppFlag{}, fmt.Errorf("%w: правило %d: значение: %v", ErrInvalidFlag, i+1, err)
+		}
+		rule.Value = value
+		rule.CityIDs = normalizeFlagTokens(rule.CityIDs)
+		rule.Genders = normalizeFlagTokens(rule.Genders)
+		rules = append(rules, rule)
+	}
+	flag.Rules = rules
+	return flag, nil
+}
+
+// ParseFlagValue turns what a moderator types into the JSON value of a flag of the given type.
+// Strings may be typed bare or quoted.
+func ParseFlagValue(flagType string, raw string) (json.RawMessage, error) {
+	raw = strings.TrimSpace(raw)
+	switch flagType {
+	case model.AppFlagTypeBool:
+		switch strings.ToLower(raw) {
+		case "true", "on", "1":
+			return json.RawMessage(`true`), nil
+		case "false", "off", "0":
+			return json.RawMessage(`false`), nil
+		}
+		return nil, fmt.Errorf("ожидается true или false")
+	case model.AppFlagTypeInt:
+		value, err := strconv.ParseInt(raw, 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("ожидается целое число")
+		}
+		return json.RawMessage(strconv.FormatInt(value, 10)), nil
+	case model.AppFlagTypeString:
+		if unquoted, err := strconv.Unquote(raw); err == nil && strings.HasPrefix(raw, `"`) {
+			raw = unquoted
+		}
+		if raw == "" {
+			return nil, fmt.Errorf("пустая строка")
+		}
+		if len([]rune(raw)) > maxFlagStringLen {
+			return nil, fmt.Errorf("строка длиннее %d символов", maxFlagStringLen)
+		}
+		encoded, _ := json.Marshal(raw)
+		return encoded, nil
+	default:
+		return nil, fmt.Errorf("неизвестный тип %q", flagType)
+	}
+}
+
+// ParseFlagRules reads one rule per line:
+//
+//	city=minsk,brest gender=female plus=true pct=20 users=1,2 => true
+//
+// Every condition is optional; "-" clears the rules.
+func ParseFlagRules(flagType string, text string) ([]model.AppFlagRule, error) {
+	text = strings.TrimSpace(text)
+	if text == "-" || text == "" {
+		return []model.AppFlagRule{}, nil
+	}
+
+	rules := make([]model.AppFlagRule, 0, 4)
+	for lineNo, line := range strings.Split(text, "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" {
+			continue
+		}
+		conditions, rawValue, ok := strings.Cut(line, "=>")
+		if !ok {
+			return nil, fmt.Errorf("строка %d: нет «=> значение»", lineNo+1)
+		}
+
+		var rule model.AppFlagRule
+		value, err := ParseFlagValue(flagType, rawValue)
+		if err != nil {
+			return nil, fmt.Errorf("строка %d: %v", lineNo+1, err)
+		}
+		rule.Value = value
+
+		for _, token := range strings.Fields(conditions) {
+			name, raw, ok := strings.Cut(token, "=")
+			if !ok || raw == "" {
+				return nil, fmt.Errorf("строка %d: условие %q должно быть вида ключ=значение", lineNo+1, token)
+			}
+			switch strings.ToLower(name) {
+			case "city":
+				rule.CityIDs = append(rule.CityIDs, strings.Split(raw, ",")...)
+			case "gender":
+				rule.Genders = append(rule.Genders, strings.Split(raw, ",")...)
+			case "plus":
+				plus, err := strconv.ParseBool(raw)
+				if err != nil {
+					return nil, fmt.Errorf("строка %d: plus=true или plus=false", lineNo+1)
+				}
+				rule.Plus = &plus
+			case "pct":
+				pct, err := strconv.Atoi(strings.TrimSuffix(raw, "%"))
+				if err != nil || pct < 0 || pct > 100 {
+					return nil, fmt.Errorf("строка %d: pct от 0 до 100", lineNo+1)
+				}
+				rule.Percentage = &pct
+			case "users":
+				for _, part := range strings.Split(raw, ",") {
+					id, err := strconv.ParseInt(strings.TrimSpace(part), 10, 64)
+					if err != nil || id <= 0 {
+						return nil, fmt.Errorf("строка %d: некорректный user id %q", lineNo+1, part)
+					}
+					rule.UserIDs = append(rule.UserIDs, id)
+				}
+			default:
+				return nil, fmt.Errorf("строка %d: неизвестное условие %q", lineNo+1, name)
+			}
+		}
+		rules = append(rules, rule)
+	}
+	return rules, nil
+}
+
+// FormatFlagRules renders rules back into the ParseFlagRules syntax.
+func FormatFlagRules(rules []model.AppFlagRule) string {
+	lines := make([]string, 0, len(rules))
+	for _, rule := range rules {
+		parts := make([]string, 0, 6)
+		if len(rule.CityIDs) > 0 {
+			parts = append(parts, "city="+strings.Join(rule.CityIDs, ","))
+		}
+		if len(rule.Genders) > 0 {
+			parts = append(parts, "gender="+strings.Join(rule.Genders, ","))
+		}
+		if rule.Plus != nil {
+			parts = append(parts, "plus="+strconv.FormatBool(*rule.Plus))
+		}
+		if rule.Percentage != nil {
+			parts = append(parts, "pct="+strconv.Itoa(*rule.Percentage))
+		}
+		if len(rule.UserIDs) > 0 {
+			ids := make([]string, 0, len(rule.UserIDs))
+			for _, id := range rule.UserIDs {
+				ids = append(ids, strconv.FormatInt(id, 10))
+			}
+			parts = append(parts, "users="+strings.Join(ids, ","))
+		}
+		parts = append(parts, "=>", string(rule.Value))
+		lines = append(lines, strings.Join(parts, " "))
+	}
+	return strings.Join(lines, "\n")
+}
+
+func normalizeFlagJSON(flagType string, raw json.RawMessage) (json.RawMessage, error) {
+	var decoded any
+	if err := json.Unmarshal(raw, &decoded); err != nil || decoded == nil {
+		return nil, fmt.Errorf("не задано")
+	}
+	switch value := decoded.(type) {
+	case bool:
+		if flagType == model.AppFlagTypeBool {
+			return ParseFlagValue(flagType, strconv.FormatBool(value))
+		}
+	case float64:
+		if flagType == model.AppFlagTypeInt {
+			return ParseFlagValue(flagType, strings.TrimSpace(string(raw)))
+		}
+	case string:
+		if flagType == model.AppFlagTypeString {
+			return ParseFlagValue(flagType, strconv.Quote(value))
+		}
+	}
+	return nil, fmt.Errorf("не соответствует типу %s", flagType)
+}
+
+func normalizeFlagTokens(values []string) []string {
+	if len(values) == 0 {
+		return nil
+	}
+	out := make([]string, 0, len(values))
+	seen := make(map[string]struct{}, len(values))
+	for _, value := range values {
+		value = strings.ToLower(strings.TrimSpace(value))
+		if value == "" {
+			continue
+		}
+		if _, ok := seen[value]; ok {
+			continue
+		}
+		seen[value] = struct{}{}
+		out = append(out, value)
+	}
+	return out
+}
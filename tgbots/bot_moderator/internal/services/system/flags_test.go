@@ -0,0 +1,114 @@
+package system
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"bot_moderator/internal/domain/model"
+)
+
+type fakeFlagsRepo struct {
+	saved []model.AppFlag
+}
+
+func (r *fakeFlagsRepo) ListFlags(context.Context) ([]model.AppFlag, error) {
+	return r.saved, nil
+}
+
+func (r *fakeFlagsRepo) UpsertFlag(_ context.Context, flag model.AppFlag, _ int64) (model.AppFlag, error) {
+	r.saved = append(r.saved, flag)
+	return flag, nil
+}
+
+func (r *fakeFlagsRepo) DeleteFlag(context.Context, string) error {
+	return nil
+}
+
+func TestParseFlagRulesRoundTrip(t *testing.T) {
+	text := "city=Minsk,brest gender=female plus=true pct=20 => true\nusers=101,102 => true"
+	rules, err := ParseFlagRules(model.AppFlagTypeBool, text)
+	if err != nil {
+		t.Fatalf("parse rules: %v", err)
+	}
+	if len(rules) != 2 {
+		t.Fatalf("expected 2 rules, got %d", len(rules))
+	}
+	first := rules[0]
+	if len(first.CityIDs) != 2 || first.Plus == nil || !*first.Plus || first.Percentage == nil || *first.Percentage != 20 {
+		t.Fatalf("unexpected first rule: %+v", first)
+	}
+	if len(rules[1].UserIDs) != 2 || string(rules[1].Value) != "true" {
+		t.Fatalf("unexpected second rule: %+v", rules[1])
+	}
+
+	flag, err := NormalizeFlag(model.AppFlag{Key: "travel_mode", Type: "bool", Value: json.RawMessage(`false`), Rules: rules})
+	if err != nil {
+		t.Fatalf("normalize: %v", err)
+	}
+	want := "city=minsk,brest gender=female plus=true pct=20 => true\nusers=101,102 => true"
+	if got := FormatFlagRules(flag.Rules); got != want {
+		t.Fatalf("unexpected formatted rules:\n%s\nwant:\n%s", got, want)
+	}
+
+	cleared, err := ParseFlagRules(model.AppFlagTypeBool, "-")
+	if err != nil || len(cleared) != 0 {
+		t.Fatalf("expected cleared rules, got %v %v", cleared, err)
+	}
+}
+
+func TestParseFlagRulesRejectsBadInput(t *testing.T) {
+	cases := []struct {
+		flagType string
+		text     string
+	}{
+		{model.AppFlagTypeBool, "city=minsk true"},
+		{model.AppFlagTypeBool, "pct=120 => true"},
+		{model.AppFlagTypeBool, "country=by => true"},
+		{model.AppFlagTypeInt, "city=minsk => many"},
+		{model.AppFlagTypeBool, "users=1,x => true"},
+	}
+	for _, tc := range cases {
+		if _, err := ParseFlagRules(tc.flagType, tc.text); err == nil {
+			t.Fatalf("expected error for %q", tc.text)
+		}
+	}
+}
+
+func TestParseFlagValueByType(t *testing.T) {
+	if v, err := ParseFlagValue(model.AppFlagTypeString, `"beta feed"`); err != nil || string(v) != `"beta feed"` {
+		t.Fatalf("unexpected quoted string: %s %v", v, err)
+	}
+	if v, err := ParseFlagValue(model.AppFlagTypeString, `beta`); err != nil || string(v) != `"beta"` {
+		t.Fatalf("unexpected bare string: %s %v", v, err)
+	}
+	if v, err := ParseFlagValue(model.AppFlagTypeInt, " 42 "); err != nil || string(v) != "42" {
+		t.Fatalf("unexpected int: %s %v", v, err)
+	}
+	if _, err := ParseFlagValue(model.AppFlagTypeBool, "yes please"); err == nil {
+		t.Fatalf("expected bool parse error")
+	}
+}
+
+func TestSaveFlagProtectsRegistrationFlag(t *testing.T) {
+	repo := &fakeFlagsRepo{}
+	svc := NewService(nil)
+	svc.AttachFlags(repo)
+
+	_, err := svc.SaveFlag(context.Background(), 1, model.AppFlag{Key: RegistrationFlagKey, Type: "int", Value: json.RawMessage(`1`)})
+	if !errors.Is(err, ErrBuiltinFlag) {
+		t.Fatalf("expected ErrBuiltinFlag, got %v", err)
+	}
+	if err := svc.DeleteFlag(context.Background(), RegistrationFlagKey); !errors.Is(err, ErrBuiltinFlag) {
+		t.Fatalf("expected ErrBuiltinFlag on delete, got %v", err)
+	}
+
+	_, err = svc.SaveFlag(context.Background(), 1, model.AppFlag{Key: "feed_limit", Type: "int", Value: json.RawMessage(`"ten"`)})
+	if !errors.Is(err, ErrInvalidFlag) {
+		t.Fatalf("expected ErrInvalidFlag, got %v", err)
+	}
+	if len(repo.saved) != 0 {
+		t.Fatalf("invalid flags must not reach the repo: %+v", repo.saved)
+	}
+}
@@ -14,7 +14,9 @@ type UsersCount struct {
 }
 
 type Service struct {
-	repo Repo
+	repo     Repo
+	flags    FlagsRepo
+	waitlist WaitlistRepo
 }
 
 func NewService(repo Repo) *Service {
@@ -0,0 +1,44 @@
+package system
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"bot_moderator/internal/domain/model"
+)
+
+const MaxWaitlistAdmit = 500
+
+var (
+	ErrInvalidWaitlistAdmit = errors.New("invalid waitlist admission")
+	ErrWaitlistUnavailable  = errors.New("waitlist is unavailable")
+)
+
+type WaitlistRepo interface {
+	WaitlistStats(context.Context) (model.WaitlistStats, error)
+	AdmitWaitlist(context.Context, model.WaitlistAdmitRequest, int64) (model.WaitlistAdmitResult, error)
+}
+
+func (s *Service) AttachWaitlist(repo WaitlistRepo) {
+	s.waitlist = repo
+}
+
+func (s *Service) WaitlistStats(ctx context.Context) (model.WaitlistStats, error) {
+	if s.waitlist == nil {
+		return model.WaitlistStats{}, ErrWaitlistUnavailable
+	}
+	return s.waitlist.WaitlistStats(ctx)
+}
+
+func (s *Service) AdmitWaitlist(ctx context.Context, actorTGID int64, req model.WaitlistAdmitRequest) (model.WaitlistAdmitResult, error) {
+	if s.waitlist == nil {
+		return model.WaitlistAdmitResult{}, ErrWaitlistUnavailable
+	}
+	if req.Limit <= 0 || req.Limit > MaxWaitlistAdmit {
+		return model.WaitlistAdmitResult{}, fmt.Errorf("%w: limit must be between 1 and %d", ErrInvalidWaitlistAdmit, MaxWaitlistAdmit)
+	}
+	req.CityID = strings.TrimSpace(req.CityID)
+	return s.waitlist.AdmitWaitlist(ctx, req, actorTGID)
+}
@@ -0,0 +1,47 @@
+package system
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"bot_moderator/internal/domain/model"
+)
+
+type fakeWaitlistRepo struct {
+	req   model.WaitlistAdmitRequest
+	actor int64
+}
+
+func (r *fakeWaitlistRepo) WaitlistStats(context.Context) (model.WaitlistStats, error) {
+	return model.WaitlistStats{Waiting: 12}, nil
+}
+
+func (r *fakeWaitlistRepo) AdmitWaitlist(_ context.Context, req model.WaitlistAdmitRequest, actorTGID int64) (model.WaitlistAdmitResult, error) {
+	r.req = req
+	r.actor = actorTGID
+	return model.WaitlistAdmitResult{Admitted: req.Limit, Waiting: 2}, nil
+}
+
+func TestAdmitWaitlistValidatesLimitAndPassesActor(t *testing.T) {
+	svc := NewService(&fakeRepo{})
+	if _, err := svc.AdmitWaitlist(context.Background(), 1, model.WaitlistAdmitRequest{Limit: 10}); !errors.Is(err, ErrWaitlistUnavailable) {
+		t.Fatalf("expected ErrWaitlistUnavailable without repo, got %v", err)
+	}
+
+	repo := &fakeWaitlistRepo{}
+	svc.AttachWaitlist(repo)
+	for _, limit := range []int{0, MaxWaitlistAdmit + 1} {
+		if _, err := svc.AdmitWaitlist(context.Background(), 1, model.WaitlistAdmitRequest{Limit: limit}); !errors.Is(err, ErrInvalidWaitlistAdmit) {
+			t.Fatalf("expected ErrInvalidWaitlistAdmit for limit=%d, got %v", limit, err)
+		}
+	}
+
+	result, err := svc.AdmitWaitlist(context.Background(), 777, model.WaitlistAdmitRequest{Limit: 10, CityID: " minsk "})
+	if err != nil {
+		t.Fatalf("admit waitlist: %v", err)
+	}
+	if result.Admitted != 10 || repo.actor != 777 || repo.req.CityID != "minsk" {
+		t.Fatalf("unexpected admit: result=%+v req=%+v actor=%d", result, repo.req, repo.actor)
+	}
+}
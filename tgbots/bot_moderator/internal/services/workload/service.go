@@ -0,0 +1,238 @@
+package workload
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"bot_moderator/internal/domain/enums"
+	"bot_moderator/internal/domain/model"
+)
+
+var (
+	ErrInvalidWorkload  = errors.New("invalid moderator workload")
+	ErrInvalidShiftSpec = errors.New("invalid shift spec")
+)
+
+const (
+	MaxModeratorActiveItems = 20
+
+	queueLockTTL     = 10 * time.Minute
+	throughputWindow = 24 * time.Hour
+)
+
+type Repo interface {
+	Workload(context.Context) (model.ModerationWorkload, error)
+	SaveModerator(context.Context, model.ModeratorWorkload) (model.ModeratorWorkload, error)
+	AddShift(context.Context, model.ModeratorShift) (model.ModeratorShift, error)
+	DeleteShift(context.Context, int64) (model.ModeratorShift, error)
+	ReassignIdleLocks(context.Context, time.Duration, time.Duration) ([]model.LockReassignment, error)
+	ShiftThroughput(context.Context, time.Time, time.Time) ([]model.ShiftThroughput, error)
+}
+
+type Service struct {
+	repo  Repo
+	nowFn func() time.Time
+}
+
+func NewService(repo Repo) *Service {
+	return &Service{repo: repo, nowFn: time.Now}
+}
+
+func (s *Service) Overview(ctx context.Context) (model.ModerationWorkload, error) {
+	if s.repo == nil {
+		return model.ModerationWorkload{Moderators: []model.ModeratorWorkload{}}, nil
+	}
+	return s.repo.Workload(ctx)
+}
+
+func (s *Service) SaveModerator(ctx context.Context, tgID int64, maxActiveItems int, skills []enums.ModeratorSkill, actorTGID int64) (model.ModeratorWorkload, error) {
+	if tgID == 0 {
+		return model.ModeratorWorkload{}, fmt.Errorf("%w: moderator tg id is required", ErrInvalidWorkload)
+	}
+	if maxActiveItems < 1 || maxActiveItems > MaxModeratorActiveItems {
+		return model.ModeratorWorkload{}, fmt.Errorf("%w: cap must be between 1 and %d", ErrInvalidWorkload, MaxModeratorActiveItems)
+	}
+	normalized := make([]enums.ModeratorSkill, 0, len(skills))
+	for _, skill := range enums.ModeratorSkills {
+		for _, selected := range skills {
+			if selected == skill {
+				normalized = append(normalized, skill)
+				break
+			}
+		}
+	}
+	if len(normalized) == 0 {
+		return model.ModeratorWorkload{}, fmt.Errorf("%w: at least one skill is required", ErrInvalidWorkload)
+	}
+
+	in := model.ModeratorWorkload{
+		ModeratorTGID:  tgID,
+		MaxActiveItems: maxActiveItems,
+		Skills:         normalized,
+	}
+	if actorTGID != 0 {
+		in.UpdatedByTGID = &actorTGID
+	}
+	if s.repo == nil {
+		return in, nil
+	}
+	return s.repo.SaveModerator(ctx, in)
+}
+
+// AddShifts stores one weekly shift per weekday named in spec, e.g. "Пн-Пт 09:00-18:00".
+func (s *Service) AddShifts(ctx context.Context, tgID int64, spec string, actorTGID int64) ([]model.ModeratorShift, error) {
+	if tgID == 0 {
+		return nil, fmt.Errorf("%w: moderator tg id is required", ErrInvalidWorkload)
+	}
+	weekdays, startsAt, endsAt, err := ParseShiftSpec(spec)
+	if err != nil {
+		return nil, err
+	}
+
+	out := make([]model.ModeratorShift, 0, len(weekdays))
+	for _, weekday := range weekdays {
+		in := model.ModeratorShift{
+			ModeratorTGID: tgID,
+			Weekday:       weekday,
+			StartsAt:      startsAt,
+			EndsAt:        endsAt,
+		}
+		if actorTGID != 0 {
+			in.CreatedByTGID = &actorTGID
+		}
+		if s.repo == nil {
+			out = append(out, in)
+			continue
+		}
+		saved, err := s.repo.AddShift(ctx, in)
+		if err != nil {
+			return out, err
+		}
+		out = append(out, saved)
+	}
+	return out, nil
+}
+
+func (s *Service) DeleteShift(ctx context.Context, shiftID int64) (model.ModeratorShift, error) {
+	if shiftID <= 0 {
+		return model.ModeratorShift{}, fmt.Errorf("%w: invalid shift id", ErrInvalidWorkload)
+	}
+	if s.repo == nil {
+		return model.ModeratorShift{}, nil
+	}
+	return s.repo.DeleteShift(ctx, shiftID)
+}
+
+// ReassignIdle moves cards held longer than idle to the least loaded on-shift moderator.
+func (s *Service) ReassignIdle(ctx context.Context, idle time.Duration) ([]model.LockReassignment, error) {
+	if s.repo == nil || idle <= 0 {
+		return nil, nil
+	}
+	if idle >= queueLockTTL {
+		return nil, fmt.Errorf("%w: idle window must be below the %s queue lock", ErrInvalidWorkload, queueLockTTL)
+	}
+	return s.repo.ReassignIdleLocks(ctx, idle, queueLockTTL)
+}
+
+func (s *Service) ShiftThroughput(ctx context.Context) ([]model.ShiftThroughput, error) {
+	if s.repo == nil {
+		return []model.ShiftThroughput{}, nil
+	}
+	to := s.nowFn().UTC()
+	return s.repo.ShiftThroughput(ctx, to.Add(-throughputWindow), to)
+}
+
+// QueuePushTargets returns the on-shift moderators to ping once the pending queue exceeds threshold.
+func (s *Service) QueuePushTargets(ctx context.Context, threshold int) (int, []int64, error) {
+	if s.repo == nil || threshold <= 0 {
+		return 0, nil, nil
+	}
+	overview, err := s.repo.Workload(ctx)
+	if err != nil {
+		return 0, nil, err
+	}
+	if overview.Pending <= threshold {
+		return overview.Pending, nil, nil
+	}
+
+	targets := make([]int64, 0, len(overview.Moderators))
+	for _, item := range overview.Moderators {
+		if item.OnShift && item.ActiveItems < item.MaxActiveItems {
+			targets = append(targets, item.ModeratorTGID)
+		}
+	}
+	return overview.Pending, targets, nil
+}
+
+var shiftWeekdays = map[string]int{
+	"пн": 1, "вт": 2, "ср": 3, "чт": 4, "пт": 5, "сб": 6, "вс": 7,
+	"mon": 1, "tue": 2, "wed": 3, "thu": 4, "fri": 5, "sat": 6, "sun": 7,
+}
+
+// ParseShiftSpec reads "<days> HH:MM-HH:MM" where days is a weekday, a range ("Пн-Пт"),
+// a comma list ("Сб,Вс") or "*" for every day. An end at or before the start is an overnight shift.
+func ParseShiftSpec(spec string) ([]int, string, string, error) {
+	fields := strings.Fields(strings.TrimSpace(spec))
+	if len(fields) != 2 {
+		return nil, "", "", ErrInvalidShiftSpec
+	}
+
+	weekdays, ok := parseShiftWeekdays(strings.ToLower(fields[0]))
+	if !ok {
+		return nil, "", "", ErrInvalidShiftSpec
+	}
+
+	clock := strings.SplitN(fields[1], "-", 2)
+	if len(clock) != 2 {
+		return nil, "", "", ErrInvalidShiftSpec
+	}
+	startsAt, okStart := normalizeClock(clock[0])
+	endsAt, okEnd := normalizeClock(clock[1])
+	if !okStart || !okEnd || startsAt == endsAt {
+		return nil, "", "", ErrInvalidShiftSpec
+	}
+	return weekdays, startsAt, endsAt, nil
+}
+
+func parseShiftWeekdays(raw string) ([]int, bool) {
+	if raw == "*" {
+		return []int{1, 2, 3, 4, 5, 6, 7}, true
+	}
+
+	seen := make(map[int]struct{}, 7)
+	for _, part := range strings.Split(raw, ",") {
+		bounds := strings.SplitN(part, "-", 2)
+		from, ok := shiftWeekdays[bounds[0]]
+		if !ok {
+			return nil, false
+		}
+		to := from
+		if len(bounds) == 2 {
+			if to, ok = shiftWeekdays[bounds[1]]; !ok || to < from {
+				return nil, false
+			}
+		}
+		for day := from; day <= to; day++ {
+			seen[day] = struct{}{}
+		}
+	}
+
+	out := make([]int, 0, len(seen))
+	for day := 1; day <= 7; day++ {
+		if _, ok := seen[day]; ok {
+			out = append(out, day)
+		}
+	}
+	return out, len(out) > 0
+}
+
+func normalizeClock(raw string) (string, bool) {
+	parsed, err := time.Parse("15:04", strings.TrimSpace(raw))
+	if err != nil {
+		return "", false
+	}
+	return parsed.Format("15:04"), true
+}
@@ -0,0 +1,134 @@
+package workload
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+
+	"bot_moderator/internal/domain/enums"
+	"bot_moderator/internal/domain/model"
+)
+
+type fakeRepo struct {
+	overview model.ModerationWorkload
+	saved    model.ModeratorWorkload
+	shifts   []model.ModeratorShift
+}
+
+func (r *fakeRepo) Workload(context.Context) (model.ModerationWorkload, error) {
+	return r.overview, nil
+}
+
+func (r *fakeRepo) SaveModerator(_ context.Context, in model.ModeratorWorkload) (model.ModeratorWorkload, error) {
+	r.saved = in
+	return in, nil
+}
+
+func (r *fakeRepo) AddShift(_ context.Context, in model.ModeratorShift) (model.ModeratorShift, error) {
+	in.ID = int64(len(r.shifts) + 1)
+	r.shifts = append(r.shifts, in)
+	return in, nil
+}
+
+func (r *fakeRepo) DeleteShift(context.Context, int64) (model.ModeratorShift, error) {
+	return model.ModeratorShift{}, nil
+}
+
+func (r *fakeRepo) ReassignIdleLocks(context.Context, time.Duration, time.Duration) ([]model.LockReassignment, error) {
+	return nil, nil
+}
+
+func (r *fakeRepo) ShiftThroughput(context.Context, time.Time, time.Time) ([]model.ShiftThroughput, error) {
+	return nil, nil
+}
+
+func TestParseShiftSpec(t *testing.T) {
+	weekdays, startsAt, endsAt, err := ParseShiftSpec(" Пн-Пт  9:00-18:00 ")
+	if err != nil {
+		t.Fatalf("parse: %v", err)
+	}
+	if !reflect.DeepEqual(weekdays, []int{1, 2, 3, 4, 5}) || startsAt != "09:00" || endsAt != "18:00" {
+		t.Fatalf("unexpected shift: %v %s-%s", weekdays, startsAt, endsAt)
+	}
+
+	weekdays, startsAt, endsAt, err = ParseShiftSpec("Вс,Сб 22:00-06:00")
+	if err != nil {
+		t.Fatalf("parse overnight: %v", err)
+	}
+	if !reflect.DeepEqual(weekdays, []int{6, 7}) || startsAt != "22:00" || endsAt != "06:00" {
+		t.Fatalf("unexpected overnight shift: %v %s-%s", weekdays, startsAt, endsAt)
+	}
+
+	for _, spec := range []string{"", "Пн", "Пт-Пн 09:00-18:00", "Пн 09:00-09:00", "Xx 09:00-18:00", "Пн 25:00-26:00"} {
+		if _, _, _, err := ParseShiftSpec(spec); !errors.Is(err, ErrInvalidShiftSpec) {
+			t.Fatalf("expected ErrInvalidShiftSpec for %q, got %v", spec, err)
+		}
+	}
+}
+
+func TestAddShiftsStoresOneShiftPerWeekday(t *testing.T) {
+	repo := &fakeRepo{}
+	svc := NewService(repo)
+
+	shifts, err := svc.AddShifts(context.Background(), 42, "* 10:00-14:00", 1)
+	if err != nil {
+		t.Fatalf("add shifts: %v", err)
+	}
+	if len(shifts) != 7 || len(repo.shifts) != 7 || repo.shifts[6].Weekday != 7 || *repo.shifts[0].CreatedByTGID != 1 {
+		t.Fatalf("unexpected shifts: %+v", repo.shifts)
+	}
+}
+
+func TestSaveModeratorValidatesCapAndSkills(t *testing.T) {
+	repo := &fakeRepo{}
+	svc := NewService(repo)
+	ctx := context.Background()
+
+	if _, err := svc.SaveModerator(ctx, 42, 0, enums.ModeratorSkills, 1); !errors.Is(err, ErrInvalidWorkload) {
+		t.Fatalf("expected cap error, got %v", err)
+	}
+	if _, err := svc.SaveModerator(ctx, 42, 2, nil, 1); !errors.Is(err, ErrInvalidWorkload) {
+		t.Fatalf("expected skills error, got %v", err)
+	}
+
+	skills := []enums.ModeratorSkill{enums.ModeratorSkillReports, enums.ModeratorSkillPhoto, enums.ModeratorSkillReports}
+	if _, err := svc.SaveModerator(ctx, 42, 3, skills, 1); err != nil {
+		t.Fatalf("save: %v", err)
+	}
+	want := []enums.ModeratorSkill{enums.ModeratorSkillPhoto, enums.ModeratorSkillReports}
+	if repo.saved.MaxActiveItems != 3 || !reflect.DeepEqual(repo.saved.Skills, want) {
+		t.Fatalf("unexpected saved workload: %+v", repo.saved)
+	}
+}
+
+func TestQueuePushTargetsOnlyAboveThreshold(t *testing.T) {
+	repo := &fakeRepo{overview: model.ModerationWorkload{
+		Pending: 10,
+		Moderators: []model.ModeratorWorkload{
+			{ModeratorTGID: 1, MaxActiveItems: 1, OnShift: true},
+			{ModeratorTGID: 2, MaxActiveItems: 1, ActiveItems: 1, OnShift: true},
+			{ModeratorTGID: 3, MaxActiveItems: 2},
+		},
+	}}
+	svc := NewService(repo)
+
+	if _, targets, err := svc.QueuePushTargets(context.Background(), 10); err != nil || len(targets) != 0 {
+		t.Fatalf("expected no push at threshold, got %v %v", targets, err)
+	}
+	pending, targets, err := svc.QueuePushTargets(context.Background(), 5)
+	if err != nil {
+		t.Fatalf("push targets: %v", err)
+	}
+	if pending != 10 || !reflect.DeepEqual(targets, []int64{1}) {
+		t.Fatalf("unexpected push targets: %d %v", pending, targets)
+	}
+}
+
+func TestReassignIdleRejectsWindowAboveLockTTL(t *testing.T) {
+	svc := NewService(&fakeRepo{})
+	if _, err := svc.ReassignIdle(context.Background(), queueLockTTL); !errors.Is(err, ErrInvalidWorkload) {
+		t.Fatalf("expected ErrInvalidWorkload, got %v", err)
+	}
+}
@@ -0,0 +1,36 @@
+package ui
+
+import (
+	"fmt"
+	"strings"
+
+	"bot_moderator/internal/domain/enums"
+	"bot_moderator/internal/domain/model"
+)
+
+func RenderRoleDefinitions(defs []model.RoleDefinition) string {
+	var b strings.Builder
+	b.WriteString("Роли и права")
+	for _, def := range defs {
+		kind := "своя"
+		if def.IsSystem {
+			kind = "системная"
+		}
+		b.WriteString(fmt.Sprintf("\n\n%s (%s)", def.Name, kind))
+		if len(def.Permissions) == 0 {
+			b.WriteString("\n- —")
+			continue
+		}
+		for _, permission := range def.Permissions {
+			b.WriteString("\n- " + permission.Label())
+		}
+	}
+	return b.String()
+}
+
+func RenderRoleEditor(name enums.Role, selected []enums.Permission) string {
+	return fmt.Sprintf(
+		"Роль %s\nВыбрано прав: %d из %d\nНажмите на право, чтобы включить или выключить его, затем сохраните.",
+		name, len(selected), len(enums.Permissions),
+	)
+}
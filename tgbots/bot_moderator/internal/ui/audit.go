@@ -0,0 +1,45 @@
+package ui
+
+import (
+	"fmt"
+	"strings"
+
+	"bot_moderator/internal/domain/model"
+)
+
+func RenderAuditPage(page model.AuditPage, filterText string) string {
+	if filterText == "" {
+		filterText = "нет"
+	}
+
+	lines := []string{
+		fmt.Sprintf("History — фильтр: %s", filterText),
+	}
+	if len(page.Items) == 0 {
+		lines = append(lines, "Записей не найдено")
+		return strings.Join(lines, "\n")
+	}
+
+	lines = append(lines, fmt.Sprintf("Записи %d–%d:", page.Offset+1, page.Offset+len(page.Items)))
+	for _, entry := range page.Items {
+		payload := strings.TrimSpace(string(entry.Payload))
+		if payload == "" {
+			payload = "{}"
+		}
+		lines = append(lines, fmt.Sprintf(
+			"%s | %s | actor=%d | %s",
+			entry.CreatedAt.UTC().Format("2006-01-02 15:04:05"),
+			entry.Action,
+			entry.ActorTGID,
+			payload,
+		))
+	}
+	return strings.Join(lines, "\n")
+}
+
+func RenderAuditChainReport(report model.AuditChainReport) string {
+	if report.OK {
+		return fmt.Sprintf("Цепочка аудита цела: проверено записей %d", report.Checked)
+	}
+	return fmt.Sprintf("⚠️ Цепочка аудита нарушена на записи %s (проверено %d)", report.BrokenID, report.Checked)
+}
@@ -0,0 +1,203 @@
+package ui
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"bot_moderator/internal/domain/enums"
+	"bot_moderator/internal/domain/model"
+)
+
+const dossierTimeLayout = "2006-01-02 15:04"
+
+func RenderDossier(d model.Dossier) string {
+	section := enums.DossierSection(d.Section)
+	if section == "" {
+		section = enums.DossierSectionOverview
+	}
+
+	lines := []string{fmt.Sprintf("📂 Досье user_id=%d — %s", d.UserID, section.Label())}
+	switch section {
+	case enums.DossierSectionOverview:
+		lines = append(lines, renderDossierOverview(d)...)
+	case enums.DossierSectionReports:
+		lines = append(lines, renderDossierReports(d.Reports)...)
+	case enums.DossierSectionRisk:
+		lines = append(lines, renderDossierRisk(d.Risk)...)
+	case enums.DossierSectionDevices:
+		lines = append(lines, renderDossierDevices(d.Devices)...)
+	case enums.DossierSectionPurchases:
+		lines = append(lines, renderDossierPurchases(d.Purchases)...)
+	case enums.DossierSectionModeration:
+		lines = append(lines, renderDossierModeration(d.Moderation)...)
+	case enums.DossierSectionSupport:
+		lines = append(lines, renderDossierSupport(d.Support)...)
+	}
+	if section != enums.DossierSectionOverview && d.PageTotal() > 0 {
+		lines = append(lines, fmt.Sprintf("Страница: %d–%d из %d", d.Offset+1, min(d.Offset+d.Limit, d.PageTotal()), d.PageTotal()))
+	}
+	return strings.Join(lines, "\n")
+}
+
+func renderDossierOverview(d model.Dossier) []string {
+	lines := make([]string, 0, 8)
+	lines = append(lines, renderDossierRiskState(d.Risk)...)
+	if a := d.Activity; a != nil {
+		lines = append(lines,
+			fmt.Sprintf("Свайпы за %d дн.: like=%d superlike=%d dislike=%d", a.Days, a.Likes, a.Superlikes, a.Dislikes),
+			fmt.Sprintf("Доля лайков: %s (сегмент %s)", renderPercent(a.LikeRatio), renderPercent(a.SegmentLikeRatio)),
+			fmt.Sprintf("Скорость: %d/час, %d/сутки, too_fast=%d", a.Swipes1h, a.Swipes24h, a.TooFastHits),
+			fmt.Sprintf("Получено лайков: %d", a.LikesReceived),
+		)
+	}
+	return lines
+}
+
+func renderDossierRiskState(risk *model.DossierRisk) []string {
+	if risk == nil || !risk.Available {
+		return []string{"Риск: нет данных"}
+	}
+	lines := []string{fmt.Sprintf("Риск: %d, shadow=%s", risk.RiskScore, yesNo(risk.ShadowEnabled))}
+	if risk.CooldownUntil != nil && risk.CooldownUntil.After(time.Now()) {
+		lines = append(lines, "Cooldown до: "+risk.CooldownUntil.UTC().Format(dossierTimeLayout))
+	}
+	if risk.LastViolationAt != nil {
+		lines = append(lines, "Последнее нарушение: "+risk.LastViolationAt.UTC().Format(dossierTimeLayout))
+	}
+	return lines
+}
+
+func renderDossierRisk(risk *model.DossierRisk) []string {
+	lines := renderDossierRiskState(risk)
+	if risk == nil || len(risk.Events) == 0 {
+		return append(lines, "История anti-abuse пуста")
+	}
+	lines = append(lines, fmt.Sprintf("История anti-abuse (%d):", risk.EventsTotal))
+	for _, event := range risk.Events {
+		lines = append(lines, fmt.Sprintf("%s | %s", event.OccurredAt.UTC().Format(dossierTimeLayout), strings.TrimPrefix(event.Name, "antiabuse_")))
+	}
+	return lines
+}
+
+func renderDossierReports(reports *model.DossierReports) []string {
+	if reports == nil {
+		return []string{"Нет данных"}
+	}
+	lines := []string{fmt.Sprintf("Получено: %d", reports.ReceivedTotal)}
+	for _, report := range reports.Received {
+		lines = append(lines, renderDossierReport(report, "от"))
+	}
+	lines = append(lines, fmt.Sprintf("Отправлено: %d", reports.FiledTotal))
+	for _, report := range reports.Filed {
+		lines = append(lines, renderDossierReport(report, "на"))
+	}
+	return lines
+}
+
+func renderDossierReport(report model.DossierReport, direction string) string {
+	line := fmt.Sprintf("%s | %s %d | %s [%s]", report.CreatedAt.UTC().Format(dossierTimeLayout), direction, report.CounterpartUserID, report.Reason, report.Status)
+	if details := strings.TrimSpace(report.Details); details != "" {
+		line += " — " + details
+	}
+	return line
+}
+
+func renderDossierDevices(devices *model.DossierDevices) []string {
+	if devices == nil {
+		return []string{"Нет данных"}
+	}
+	lines := []string{fmt.Sprintf("Устройства: %d", devices.Total)}
+	for _, device := range devices.Devices {
+		line := fmt.Sprintf("%s | последний вход %s", device.DeviceID, device.LastSeenAt.UTC().Format(dossierTimeLayout))
+		if device.SharedWith > 0 {
+			line += fmt.Sprintf(" | ещё аккаунтов: %d", device.SharedWith)
+		}
+		if device.IsBlocked {
+			line += " | ⛔️"
+		}
+		lines = append(lines, line)
+	}
+	if len(devices.LinkedAccounts) == 0 {
+		return append(lines, "Связанные аккаунты: нет")
+	}
+	lines = append(lines, "Связанные аккаунты:")
+	for _, account := range devices.LinkedAccounts {
+		line := fmt.Sprintf("user_id=%d tg_id=%d (%s)", account.UserID, account.TGID, strings.Join(account.Signals, ", "))
+		if account.Banned {
+			line += " ⛔️"
+		}
+		lines = append(lines, line)
+	}
+	return lines
+}
+
+func renderDossierPurchases(purchases *model.DossierPurchases) []string {
+	if purchases == nil || len(purchases.Items) == 0 {
+		return []string{"Покупок нет"}
+	}
+	lines := []string{fmt.Sprintf("Покупки: %d", purchases.Total)}
+	for _, item := range purchases.Items {
+		lines = append(lines, fmt.Sprintf(
+			"%s | %s | %s | %d.%02d %s | %s",
+			item.CreatedAt.UTC().Format(dossierTimeLayout),
+			item.SKU,
+			item.Provider,
+			item.AmountMinor/100,
+			item.AmountMinor%100,
+			item.Currency,
+			item.Status,
+		))
+	}
+	return lines
+}
+
+func renderDossierModeration(moderation *model.DossierModeration) []string {
+	if moderation == nil || len(moderation.Items) == 0 {
+		return []string{"Решений модерации нет"}
+	}
+	lines := []string{fmt.Sprintf("Решения: %d", moderation.Total)}
+	for _, item := range moderation.Items {
+		at := item.CreatedAt
+		if item.DecidedAt != nil {
+			at = *item.DecidedAt
+		}
+		line := fmt.Sprintf("%s | #%d %s %s", at.UTC().Format(dossierTimeLayout), item.ModerationItemID, item.TargetType, item.Status)
+		if item.ReasonCode != "" {
+			line += " " + item.ReasonCode
+		}
+		if item.ModeratorTGID != nil {
+			line += fmt.Sprintf(" | mod=%d", *item.ModeratorTGID)
+		}
+		if item.ReviewKind != "" {
+			line += fmt.Sprintf(" | %s: %s", item.ReviewKind, item.ReviewStatus)
+		}
+		lines = append(lines, line)
+	}
+	return lines
+}
+
+func renderDossierSupport(support *model.DossierSupport) []string {
+	if support == nil || !support.Available {
+		return []string{"История поддержки недоступна"}
+	}
+	if len(support.Items) == 0 {
+		return []string{"Обращений нет"}
+	}
+	lines := []string{fmt.Sprintf("Обращения: %d", support.Total)}
+	for _, item := range support.Items {
+		lines = append(lines, fmt.Sprintf("%s | #%d %s | сообщений: %d | %s", item.UpdatedAt.UTC().Format(dossierTimeLayout), item.ID, item.Status, item.MessagesCount, item.LastMessage))
+	}
+	return lines
+}
+
+func renderPercent(ratio float64) string {
+	return fmt.Sprintf("%.0f%%", ratio*100)
+}
+
+func yesNo(value bool) string {
+	if value {
+		return "да"
+	}
+	return "нет"
+}
@@ -0,0 +1,45 @@
+package ui
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"bot_moderator/internal/domain/model"
+)
+
+func RenderExportQueue(stats model.ExportQueueStats, sinkName string, now time.Time) string {
+	oldest := "—"
+	if stats.OldestPendingAt != nil {
+		oldest = renderSLADuration(now.Sub(*stats.OldestPendingAt).Seconds())
+	}
+
+	lines := []string{
+		fmt.Sprintf("Exports (sink: %s)", sinkName),
+		fmt.Sprintf("В очереди: %d, старейшая: %s", stats.Pending, oldest),
+		fmt.Sprintf("Доставлено за 24 ч: %d", stats.DoneLast24h),
+		fmt.Sprintf("Ошибки (FAILED): %d", stats.Failed),
+	}
+
+	if len(stats.RecentFailures) > 0 {
+		lines = append(lines, "Последние ошибки:")
+		for _, item := range stats.RecentFailures {
+			lines = append(lines, fmt.Sprintf(
+				"%s %s — попыток:%d | %s",
+				item.Status,
+				shortExportID(item.ID),
+				item.Attempts,
+				item.LastError,
+			))
+		}
+	}
+
+	return strings.Join(lines, "\n")
+}
+
+func shortExportID(id string) string {
+	if len(id) > 8 {
+		return id[:8]
+	}
+	return id
+}
@@ -0,0 +1,54 @@
+package ui
+
+import (
+	"fmt"
+	"strings"
+
+	"bot_moderator/internal/domain/model"
+)
+
+func RenderFlagList(items []model.AppFlag) string {
+	if len(items) == 0 {
+		return "Флаги: пусто"
+	}
+
+	lines := []string{"Флаги:"}
+	for _, item := range items {
+		line := fmt.Sprintf("%s = %s", item.Key, string(item.Value))
+		if len(item.Rules) > 0 {
+			line += fmt.Sprintf(" (+%d правил)", len(item.Rules))
+		}
+		lines = append(lines, line)
+	}
+	return strings.Join(lines, "\n")
+}
+
+// RenderFlag shows a flag card; rules are passed pre-formatted in the syntax the editor accepts.
+func RenderFlag(item model.AppFlag, rules string) string {
+	visibility := "только бэкенд"
+	if item.ClientVisible {
+		visibility = "отдаётся клиенту в /me"
+	}
+
+	lines := []string{
+		fmt.Sprintf("🚩 %s (%s)", item.Key, item.Type),
+		fmt.Sprintf("По умолчанию: %s", string(item.Value)),
+		fmt.Sprintf("Видимость: %s", visibility),
+	}
+	if item.Description != "" {
+		lines = append(lines, fmt.Sprintf("Описание: %s", item.Description))
+	}
+	if rules == "" {
+		lines = append(lines, "Правила: нет")
+	} else {
+		lines = append(lines, "Правила (первое совпавшее):", rules)
+	}
+	if item.UpdatedAt != nil {
+		updated := fmt.Sprintf("Изменено: %s", item.UpdatedAt.UTC().Format("2006-01-02 15:04"))
+		if item.UpdatedByTGID != nil {
+			updated += fmt.Sprintf(" (%d)", *item.UpdatedByTGID)
+		}
+		lines = append(lines, updated)
+	}
+	return strings.Join(lines, "\n")
+}
@@ -1,29 +1,57 @@
 package ui
 
-import "bot_moderator/internal/domain/enums"
+import (
+	"bot_moderator/internal/domain/enums"
+	"bot_moderator/internal/domain/model"
+)
 
-func MenuByRole(role enums.Role) [][]string {
-	switch role {
-	case enums.RoleOwner:
-		return [][]string{
-			{"Dating App"},
-			{"Приступить к модерации"},
-			{"Access", "Find user"},
-			{"History", "System"},
-			{"Work Stats"},
-		}
-	case enums.RoleAdmin:
-		return [][]string{
-			{"Dating App"},
-			{"Приступить к модерации"},
-			{"Access", "Find user"},
+// menuEntries lists the paired menu buttons in display order with the permission each one needs.
+var menuEntries = []struct {
+	label      string
+	permission enums.Permission
+}{
+	{label: "Access", permission: enums.PermissionAccessManage},
+	{label: "Find user", permission: enums.PermissionUsersViewPrivate},
+	{label: "History", permission: enums.PermissionAuditView},
+	{label: "System", permission: enums.PermissionSystemRegistration},
+	{label: "Work Stats", permission: enums.PermissionStatsView},
+	{label: "Причины отказа", permission: enums.PermissionModerationReasons},
+}
+
+func MenuFor(def model.RoleDefinition) [][]string {
+	if len(def.Permissions) == 0 {
+		return [][]string{}
+	}
+
+	menu := [][]string{{"Dating App"}}
+	if def.Has(enums.PermissionModerationDecide) {
+		menu = append(menu, []string{"Приступить к модерации", "Пакетная модерация"})
+	}
+
+	reviews := make([]string, 0, 2)
+	if def.Has(enums.PermissionModerationDecide) {
+		reviews = append(reviews, "Апелляции")
+	}
+	if def.Has(enums.PermissionModerationQA) {
+		reviews = append(reviews, "QA проверка")
+	}
+	if len(reviews) > 0 {
+		menu = append(menu, reviews)
+	}
+
+	row := make([]string, 0, 2)
+	for _, entry := range menuEntries {
+		if !def.Has(entry.permission) {
+			continue
 		}
-	case enums.RoleModerator:
-		return [][]string{
-			{"Dating App"},
-			{"Приступить к модерации"},
+		row = append(row, entry.label)
+		if len(row) == 2 {
+			menu = append(menu, row)
+			row = make([]string, 0, 2)
 		}
-	default:
-		return [][]string{}
 	}
+	if len(row) > 0 {
+		menu = append(menu, row)
+	}
+	return menu
 }
@@ -0,0 +1,64 @@
+package ui
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"bot_moderator/internal/domain/model"
+)
+
+func RenderRejectReasonCatalog(items []model.RejectReason) string {
+	if len(items) == 0 {
+		return "Причины отказа: каталог пуст"
+	}
+
+	lines := []string{"Причины отказа:"}
+	for _, item := range items {
+		lines = append(lines, fmt.Sprintf("%s %s — %s [%s]", rejectReasonMark(item), item.Code, item.LabelFor(model.RejectReasonDefaultLanguage), item.Severity))
+	}
+	return strings.Join(lines, "\n")
+}
+
+func RenderRejectReason(item model.RejectReason) string {
+	status := "активна"
+	if !item.IsActive {
+		status = "отключена"
+	}
+
+	lines := []string{
+		fmt.Sprintf("%s %s", rejectReasonMark(item), item.Code),
+		fmt.Sprintf("Статус: %s", status),
+		fmt.Sprintf("Серьёзность: %s", item.Severity),
+		fmt.Sprintf("Порядок: %d", item.SortOrder),
+	}
+
+	langs := make([]string, 0, len(item.Labels))
+	for lang := range item.Labels {
+		langs = append(langs, lang)
+	}
+	sort.Strings(langs)
+	for _, lang := range langs {
+		lines = append(lines, fmt.Sprintf("Название [%s]: %s", lang, item.Labels[lang]))
+	}
+
+	lines = append(lines,
+		fmt.Sprintf("Текст пользователю: %s", item.ReasonText),
+		fmt.Sprintf("Что исправить: %s", item.RequiredFixStep),
+	)
+	if item.UpdatedAt != nil {
+		updated := fmt.Sprintf("Изменено: %s", item.UpdatedAt.UTC().Format("2006-01-02 15:04"))
+		if item.UpdatedByTGID != nil {
+			updated += fmt.Sprintf(" (%d)", *item.UpdatedByTGID)
+		}
+		lines = append(lines, updated)
+	}
+	return strings.Join(lines, "\n")
+}
+
+func rejectReasonMark(item model.RejectReason) string {
+	if item.IsActive {
+		return "✅"
+	}
+	return "⛔️"
+}
@@ -1,7 +1,10 @@
 package ui
 
-import "bot_moderator/internal/domain/enums"
+import (
+	"bot_moderator/internal/domain/enums"
+	"bot_moderator/internal/domain/model"
+)
 
-func RenderStart(role enums.Role) (string, [][]string) {
-	return StartMessage(role), MenuByRole(role)
+func RenderStart(role enums.Role, def model.RoleDefinition) (string, [][]string) {
+	return StartMessage(role), MenuFor(def)
 }
@@ -0,0 +1,38 @@
+package ui
+
+import (
+	"fmt"
+	"strings"
+
+	"bot_moderator/internal/domain/model"
+)
+
+func RenderWaitlistStats(stats model.WaitlistStats, registrationEnabled bool) string {
+	registration := "закрыта"
+	if registrationEnabled {
+		registration = "открыта"
+	}
+
+	lines := []string{
+		"⏳ Лист ожидания",
+		fmt.Sprintf("Регистрация: %s", registration),
+		fmt.Sprintf("В очереди: %d", stats.Waiting),
+		fmt.Sprintf("Допущено сегодня: %d", stats.AdmittedToday),
+		fmt.Sprintf("Ждут уведомления: %d", stats.PendingNotify),
+	}
+	if len(stats.ByGender) > 0 {
+		lines = append(lines, "", "По полу: "+renderWaitlistBuckets(stats.ByGender))
+	}
+	if len(stats.ByCity) > 0 {
+		lines = append(lines, "По городам: "+renderWaitlistBuckets(stats.ByCity))
+	}
+	return strings.Join(lines, "\n")
+}
+
+func renderWaitlistBuckets(items []model.WaitlistBucket) string {
+	parts := make([]string, 0, len(items))
+	for _, item := range items {
+		parts = append(parts, fmt.Sprintf("%s %d", item.Key, item.Count))
+	}
+	return strings.Join(parts, ", ")
+}
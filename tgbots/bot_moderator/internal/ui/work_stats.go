@@ -44,3 +44,131 @@ func renderWorkStatsUserLabel(tgID int64, username string) string {
 	}
 	return strconv.FormatInt(tgID, 10)
 }
+
+func RenderModerationSLA(report model.ModerationSLAReport) string {
+	lines := []string{
+		fmt.Sprintf("SLA (последние %d ч)", report.WindowHours),
+		fmt.Sprintf("Ожидание p50/p90/p99: %s / %s / %s",
+			renderSLADuration(report.WaitP50Sec),
+			renderSLADuration(report.WaitP90Sec),
+			renderSLADuration(report.WaitP99Sec),
+		),
+		fmt.Sprintf("В очереди: %d, старейшая: %s", report.Pending, renderSLADuration(report.OldestPendingSec)),
+		fmt.Sprintf("Решений за час: %d", report.DecidedLastHour),
+		fmt.Sprintf("Approve/Reject: %d / %d (approve %.0f%%)", report.Approved, report.Rejected, report.ApproveRatio*100),
+		fmt.Sprintf("Истекшие блокировки: %d (сейчас брошено: %d)", report.LockExpired, report.StaleLocks),
+	}
+
+	if len(report.Moderators) == 0 {
+		lines = append(lines, "Модераторы: —")
+	} else {
+		lines = append(lines, "Модераторы:")
+		for _, item := range report.Moderators {
+			lines = append(lines, fmt.Sprintf(
+				"%d — решений:%d (A:%d R:%d) | avg:%s | p90:%s",
+				item.ModeratorTGID,
+				item.Decisions,
+				item.Approved,
+				item.Rejected,
+				renderSLADuration(item.AvgDecisionSec),
+				renderSLADuration(item.P90DecisionSec),
+			))
+		}
+	}
+
+	if len(report.RejectReasons) == 0 {
+		lines = append(lines, "Причины отказа: —")
+	} else {
+		lines = append(lines, "Причины отказа:")
+		for _, item := range report.RejectReasons {
+			lines = append(lines, fmt.Sprintf(
+				"%s — %d (%.0f%% отказов, %.0f%% решений)",
+				item.ReasonCode,
+				item.Count,
+				item.ShareOfReject*100,
+				item.ShareOfTotal*100,
+			))
+		}
+	}
+
+	return strings.Join(lines, "\n")
+}
+
+func RenderReviewStats(stats model.ModerationReviewStats) string {
+	lines := []string{fmt.Sprintf("Second review (последние %d ч)", stats.WindowHours)}
+
+	if len(stats.Kinds) == 0 {
+		lines = append(lines, "Проверок: —")
+	}
+	for _, item := range stats.Kinds {
+		lines = append(lines, fmt.Sprintf(
+			"%s — в очереди:%d | решено:%d (оставлено:%d, отменено:%d) | согласие:%.0f%%",
+			item.Kind,
+			item.Pending,
+			item.Decided,
+			item.Upheld,
+			item.Overturned,
+			item.AgreementRate*100,
+		))
+	}
+
+	if len(stats.Moderators) == 0 {
+		lines = append(lines, "Согласие по модераторам: —")
+	} else {
+		lines = append(lines, "Согласие по модераторам:")
+		for _, item := range stats.Moderators {
+			lines = append(lines, fmt.Sprintf(
+				"%d — проверено:%d (оставлено:%d, отменено:%d) | %.0f%%",
+				item.ModeratorTGID,
+				item.Reviewed,
+				item.Upheld,
+				item.Overturned,
+				item.AgreementRate*100,
+			))
+		}
+	}
+
+	return strings.Join(lines, "\n")
+}
+
+func RenderModerationBatchStats(stats model.ModerationBatchStats) string {
+	lines := []string{fmt.Sprintf("Пакетная модерация (последние %d ч)", stats.WindowHours)}
+	if len(stats.Moderators) == 0 {
+		lines = append(lines, "Пакетов: —")
+		return strings.Join(lines, "\n")
+	}
+
+	for _, item := range stats.Moderators {
+		single := "—"
+		if perMinute := item.SingleItemsPerMinute(); perMinute > 0 {
+			single = fmt.Sprintf("%.1f/мин", perMinute)
+		}
+		lines = append(lines, fmt.Sprintf(
+			"%d — пакетов:%d | решений:%d (массово:%d) | пакетно:%.1f/мин, по одной:%s",
+			item.ModeratorTGID,
+			item.Batches,
+			item.Decisions,
+			item.BulkApproved,
+			item.ItemsPerMinute(),
+			single,
+		))
+	}
+	return strings.Join(lines, "\n")
+}
+
+func renderSLADuration(seconds float64) string {
+	total := int64(seconds + 0.5)
+	if total <= 0 {
+		return "0s"
+	}
+	hours := total / 3600
+	minutes := (total % 3600) / 60
+	secs := total % 60
+	if hours > 0 {
+		return fmt.Sprintf("%dh%02dm", hours, minutes)
+	}
+	if minutes > 0 {
+		return fmt.Sprintf("%dm%02ds", minutes, secs)
+	}
+	return fmt.Sprintf("%ds", secs)
+}
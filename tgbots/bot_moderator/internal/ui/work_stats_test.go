@@ -1,9 +1,12 @@
 package ui
 
 import (
+	"encoding/json"
 	"strings"
 	"testing"
+	"time"
 
+	"bot_moderator/internal/domain/enums"
 	"bot_moderator/internal/domain/model"
 )
 
@@ -54,3 +57,260 @@ func TestRenderWorkStats(t *testing.T) {
 		}
 	}
 }
+
+func TestRenderModerationSLA(t *testing.T) {
+	report := model.ModerationSLAReport{
+		WindowHours:      24,
+		Decided:          10,
+		Approved:         8,
+		Rejected:         2,
+		ApproveRatio:     0.8,
+		WaitP50Sec:       300,
+		WaitP90Sec:       1500,
+		WaitP99Sec:       4000,
+		Pending:          4,
+		OldestPendingSec: 95,
+		DecidedLastHour:  6,
+		LockExpired:      3,
+		StaleLocks:       1,
+		Moderators: []model.ModerationSLAModerator{
+			{ModeratorTGID: 1001, Decisions: 10, Approved: 8, Rejected: 2, AvgDecisionSec: 42, P90DecisionSec: 80},
+		},
+		RejectReasons: []model.ModerationSLAReason{
+			{ReasonCode: "PHOTO_NO_FACE", Count: 2, ShareOfReject: 1, ShareOfTotal: 0.2},
+		},
+	}
+
+	text := RenderModerationSLA(report)
+
+	required := []string{
+		"SLA (последние 24 ч)",
+		"Ожидание p50/p90/p99: 5m00s / 25m00s / 1h06m",
+		"В очереди: 4, старейшая: 1m35s",
+		"Approve/Reject: 8 / 2 (approve 80%)",
+		"Истекшие блокировки: 3 (сейчас брошено: 1)",
+		"1001 — решений:10 (A:8 R:2) | avg:42s | p90:1m20s",
+		"PHOTO_NO_FACE — 2 (100% отказов, 20% решений)",
+	}
+	for _, token := range required {
+		if !strings.Contains(text, token) {
+			t.Fatalf("expected sla text to contain %q; got:\n%s", token, text)
+		}
+	}
+}
+
+func TestRenderReviewStats(t *testing.T) {
+	stats := model.ModerationReviewStats{
+		WindowHours: 168,
+		Kinds: []model.ModerationReviewKindStats{
+			{Kind: "APPEAL", Pending: 2, Decided: 4, Upheld: 3, Overturned: 1, AgreementRate: 0.75},
+			{Kind: "QA", Pending: 0, Decided: 10, Upheld: 9, Overturned: 1, AgreementRate: 0.9},
+		},
+		Moderators: []model.ModerationReviewAgreement{
+			{ModeratorTGID: 1001, Reviewed: 5, Upheld: 4, Overturned: 1, AgreementRate: 0.8},
+		},
+	}
+
+	text := RenderReviewStats(stats)
+
+	required := []string{
+		"Second review (последние 168 ч)",
+		"APPEAL — в очереди:2 | решено:4 (оставлено:3, отменено:1) | согласие:75%",
+		"QA — в очереди:0 | решено:10 (оставлено:9, отменено:1) | согласие:90%",
+		"1001 — проверено:5 (оставлено:4, отменено:1) | 80%",
+	}
+	for _, token := range required {
+		if !strings.Contains(text, token) {
+			t.Fatalf("expected review stats text to contain %q; got:\n%s", token, text)
+		}
+	}
+}
+
+func TestRenderExportQueue(t *testing.T) {
+	now := time.Date(2026, 3, 4, 12, 0, 0, 0, time.UTC)
+	oldest := now.Add(-90 * time.Second)
+	stats := model.ExportQueueStats{
+		Pending:         3,
+		Failed:          1,
+		DoneLast24h:     12,
+		OldestPendingAt: &oldest,
+		RecentFailures: []model.ExportQueueItem{
+			{ID: "0123456789abcdef", Status: "FAILED", Attempts: 8, LastError: "sheets: status 403"},
+		},
+	}
+
+	text := RenderExportQueue(stats, "sheets", now)
+
+	required := []string{
+		"Exports (sink: sheets)",
+		"В очереди: 3, старейшая: 1m30s",
+		"Доставлено за 24 ч: 12",
+		"Ошибки (FAILED): 1",
+		"FAILED 01234567 — попыток:8 | sheets: status 403",
+	}
+	for _, token := range required {
+		if !strings.Contains(text, token) {
+			t.Fatalf("expected export queue text to contain %q; got:\n%s", token, text)
+		}
+	}
+}
+
+func TestRenderAuditPage(t *testing.T) {
+	page := model.AuditPage{
+		Offset: 10,
+		Limit:  10,
+		Items: []model.Audit{{
+			ActorTGID: 42,
+			Action:    enums.AuditActionBanUser,
+			Payload:   json.RawMessage(`{"target_user_id":7}`),
+			CreatedAt: time.Date(2026, 3, 4, 12, 0, 0, 0, time.UTC),
+		}},
+	}
+
+	text := RenderAuditPage(page, "action:BAN_USER")
+
+	required := []string{
+		"History — фильтр: action:BAN_USER",
+		"Записи 11–11:",
+		`2026-03-04 12:00:00 | BAN_USER | actor=42 | {"target_user_id":7}`,
+	}
+	for _, token := range required {
+		if !strings.Contains(text, token) {
+			t.Fatalf("expected audit page text to contain %q; got:\n%s", token, text)
+		}
+	}
+
+	if text := RenderAuditPage(model.AuditPage{}, ""); !strings.Contains(text, "фильтр: нет") || !strings.Contains(text, "Записей не найдено") {
+		t.Fatalf("unexpected empty audit page text:\n%s", text)
+	}
+}
+
+func TestRenderRejectReason(t *testing.T) {
+	updatedBy := int64(700001)
+	updatedAt := time.Date(2026, 1, 2, 3, 4, 0, 0, time.UTC)
+	item := model.RejectReason{
+		Code:            "PHOTO_NO_FACE",
+		Labels:          map[string]string{"ru": "Фото: не видно лица", "en": "Photo: face not visible"},
+		ReasonText:      "На фото не видно лица.",
+		RequiredFixStep: "Загрузите фото, где лицо хорошо различимо.",
+		Severity:        "LOW",
+		SortOrder:       10,
+		UpdatedByTGID:   &updatedBy,
+		UpdatedAt:       &updatedAt,
+	}
+
+	text := RenderRejectReason(item)
+	for _, token := range []string{
+		"⛔️ PHOTO_NO_FACE",
+		"Статус: отключена",
+		"Название [en]: Photo: face not visible",
+		"Название [ru]: Фото: не видно лица",
+		"Изменено: 2026-01-02 03:04 (700001)",
+	} {
+		if !strings.Contains(text, token) {
+			t.Fatalf("expected reason text to contain %q; got:\n%s", token, text)
+		}
+	}
+
+	item.IsActive = true
+	catalog := RenderRejectReasonCatalog([]model.RejectReason{item})
+	if !strings.Contains(catalog, "✅ PHOTO_NO_FACE — Фото: не видно лица [LOW]") {
+		t.Fatalf("unexpected catalog text:\n%s", catalog)
+	}
+}
+
+func TestRenderDossier(t *testing.T) {
+	decidedAt := time.Date(2026, 2, 3, 4, 5, 0, 0, time.UTC)
+	moderator := int64(900)
+	dossier := model.Dossier{
+		UserID:  42,
+		Section: "moderation",
+		Limit:   5,
+		Offset:  5,
+		Moderation: &model.DossierModeration{
+			Total: 7,
+			Items: []model.DossierModerationDecision{{
+				ModerationItemID: 11,
+				TargetType:       "PROFILE",
+				Status:           "REJECTED",
+				ReasonCode:       "PHOTO_NO_FACE",
+				ModeratorTGID:    &moderator,
+				DecidedAt:        &decidedAt,
+				ReviewKind:       "APPEAL",
+				ReviewStatus:     "UPHELD",
+			}},
+		},
+	}
+
+	text := RenderDossier(dossier)
+	for _, token := range []string{
+		"📂 Досье user_id=42 — Модерация",
+		"2026-02-03 04:05 | #11 PROFILE REJECTED PHOTO_NO_FACE | mod=900 | APPEAL: UPHELD",
+		"Страница: 6–7 из 7",
+	} {
+		if !strings.Contains(text, token) {
+			t.Fatalf("expected dossier text to contain %q; got:\n%s", token, text)
+		}
+	}
+
+	overview := RenderDossier(model.Dossier{
+		UserID:   42,
+		Risk:     &model.DossierRisk{},
+		Activity: &model.DossierActivity{Days: 7, Likes: 30, Dislikes: 10, LikeRatio: 0.75, SegmentLikeRatio: 0.4, Swipes1h: 120},
+	})
+	for _, token := range []string{"Риск: нет данных", "Доля лайков: 75% (сегмент 40%)", "Скорость: 120/час"} {
+		if !strings.Contains(overview, token) {
+			t.Fatalf("expected overview to contain %q; got:\n%s", token, overview)
+		}
+	}
+
+	support := RenderDossier(model.Dossier{UserID: 42, Section: "support", Support: &model.DossierSupport{}})
+	if !strings.Contains(support, "История поддержки недоступна") {
+		t.Fatalf("unexpected support text:\n%s", support)
+	}
+}
+
+func TestRenderModerationBatchStats(t *testing.T) {
+	text := RenderModerationBatchStats(model.ModerationBatchStats{
+		WindowHours: 168,
+		Moderators: []model.ModerationBatchModerator{
+			{ModeratorTGID: 1001, Batches: 2, Decisions: 20, BulkApproved: 12, ActiveSec: 600, SingleAvgDecisionSec: 30},
+			{ModeratorTGID: 1002, Batches: 1, Decisions: 5, ActiveSec: 150},
+		},
+	})
+	for _, want := range []string{
+		"Пакетная модерация (последние 168 ч)",
+		"1001 — пакетов:2 | решений:20 (массово:12) | пакетно:2.0/мин, по одной:2.0/мин",
+		"1002 — пакетов:1 | решений:5 (массово:0) | пакетно:2.0/мин, по одной:—",
+	} {
+		if !strings.Contains(text, want) {
+			t.Fatalf("expected %q in batch stats:\n%s", want, text)
+		}
+	}
+
+	if empty := RenderModerationBatchStats(model.ModerationBatchStats{WindowHours: 168}); !strings.Contains(empty, "Пакетов: —") {
+		t.Fatalf("unexpected empty batch stats:\n%s", empty)
+	}
+}
+
+func TestRenderShiftThroughput(t *testing.T) {
+	// Monday 2026-02-02 09:00–13:00 in Minsk.
+	startsAt := time.Date(2026, 2, 2, 6, 0, 0, 0, time.UTC)
+	text := RenderShiftThroughput([]model.ShiftThroughput{{
+		ShiftID:       3,
+		ModeratorTGID: 1001,
+		StartsAt:      startsAt,
+		EndsAt:        startsAt.Add(4 * time.Hour),
+		Decisions:     10,
+		Approved:      8,
+		Rejected:      2,
+	}})
+	if !strings.Contains(text, "Пн 09:00–13:00 · 1001 — решений:10 (одобрено:8, отклонено:2) | 2.5/ч") {
+		t.Fatalf("unexpected shift throughput text:\n%s", text)
+	}
+
+	overnight := RenderModeratorShift(model.ModeratorShift{Weekday: 7, StartsAt: "22:00", EndsAt: "06:00"})
+	if overnight != "Вс 22:00–06:00 (ночь)" {
+		t.Fatalf("unexpected shift label %q", overnight)
+	}
+}
@@ -0,0 +1,149 @@
+package ui
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"bot_moderator/internal/domain/enums"
+	"bot_moderator/internal/domain/model"
+)
+
+var shiftWeekdayLabels = [...]string{"", "Пн", "Вт", "Ср", "Чт", "Пт", "Сб", "Вс"}
+
+// Shifts are scheduled in Minsk wall-clock time.
+var shiftLocation = loadShiftLocation()
+
+func loadShiftLocation() *time.Location {
+	loc, err := time.LoadLocation("Europe/Minsk")
+	if err != nil {
+		return time.FixedZone("Europe/Minsk", 3*3600)
+	}
+	return loc
+}
+
+// RenderModerationWorkload lists moderators with their load; labels maps tg id to a display name.
+func RenderModerationWorkload(overview model.ModerationWorkload, moderators []int64, labels map[int64]string) string {
+	lines := []string{
+		"Нагрузка и смены",
+		fmt.Sprintf("В очереди: %d", overview.Pending),
+	}
+	if len(moderators) == 0 {
+		lines = append(lines, "", "Модераторов нет")
+		return strings.Join(lines, "\n")
+	}
+
+	lines = append(lines, "")
+	for _, tgID := range moderators {
+		item, _ := overview.Moderator(tgID)
+		mark := "⚪️"
+		if item.OnShift {
+			mark = "🟢"
+		}
+		lines = append(lines, fmt.Sprintf(
+			"%s %s — в работе %d/%d | %s | смен: %d",
+			mark,
+			workloadLabel(tgID, labels),
+			item.ActiveItems,
+			item.MaxActiveItems,
+			renderModeratorSkills(item.Skills),
+			len(item.Shifts),
+		))
+	}
+	lines = append(lines, "", "🟢 — на смене сейчас")
+	return strings.Join(lines, "\n")
+}
+
+// RenderModeratorWorkload shows the editor draft (cap and skills) next to the stored shifts.
+func RenderModeratorWorkload(label string, item model.ModeratorWorkload, maxActiveItems int, skills []enums.ModeratorSkill) string {
+	status := "не на смене"
+	if item.OnShift {
+		status = "на смене"
+	}
+
+	lines := []string{
+		fmt.Sprintf("Модератор %s (%s)", label, status),
+		fmt.Sprintf("В работе: %d", item.ActiveItems),
+		fmt.Sprintf("Лимит карточек: %d", maxActiveItems),
+		fmt.Sprintf("Навыки: %s", renderModeratorSkills(skills)),
+		"",
+	}
+	if len(item.Shifts) == 0 {
+		lines = append(lines, "Смены: —")
+	} else {
+		lines = append(lines, "Смены (время Минска):")
+		for _, shift := range item.Shifts {
+			lines = append(lines, "- "+RenderModeratorShift(shift))
+		}
+	}
+	return strings.Join(lines, "\n")
+}
+
+func RenderModeratorShift(shift model.ModeratorShift) string {
+	text := fmt.Sprintf("%s %s–%s", shiftWeekdayLabel(shift.Weekday), shift.StartsAt, shift.EndsAt)
+	if shift.EndsAt <= shift.StartsAt {
+		text += " (ночь)"
+	}
+	return text
+}
+
+func RenderShiftThroughput(items []model.ShiftThroughput) string {
+	lines := []string{"Смены за последние 24 ч"}
+	if len(items) == 0 {
+		lines = append(lines, "Смен: —")
+		return strings.Join(lines, "\n")
+	}
+
+	for _, item := range items {
+		startsAt := item.StartsAt.In(shiftLocation)
+		endsAt := item.EndsAt.In(shiftLocation)
+		perHour := 0.0
+		if hours := item.EndsAt.Sub(item.StartsAt).Hours(); hours > 0 {
+			perHour = float64(item.Decisions) / hours
+		}
+		lines = append(lines, fmt.Sprintf(
+			"%s %s–%s · %d — решений:%d (одобрено:%d, отклонено:%d) | %.1f/ч",
+			shiftWeekdayLabel(isoWeekday(startsAt)),
+			startsAt.Format("15:04"),
+			endsAt.Format("15:04"),
+			item.ModeratorTGID,
+			item.Decisions,
+			item.Approved,
+			item.Rejected,
+			perHour,
+		))
+	}
+	return strings.Join(lines, "\n")
+}
+
+func renderModeratorSkills(skills []enums.ModeratorSkill) string {
+	if len(skills) == 0 {
+		return "—"
+	}
+	labels := make([]string, 0, len(skills))
+	for _, skill := range skills {
+		labels = append(labels, skill.Label())
+	}
+	return strings.Join(labels, ", ")
+}
+
+func shiftWeekdayLabel(weekday int) string {
+	if weekday < 1 || weekday >= len(shiftWeekdayLabels) {
+		return "?"
+	}
+	return shiftWeekdayLabels[weekday]
+}
+
+func isoWeekday(t time.Time) int {
+	if t.Weekday() == time.Sunday {
+		return 7
+	}
+	return int(t.Weekday())
+}
+
+func workloadLabel(tgID int64, labels map[int64]string) string {
+	if label, ok := labels[tgID]; ok && label != "" {
+		return label
+	}
+	return fmt.Sprintf("%d", tgID)
+}
@@ -54,7 +54,7 @@ func (s *stubRolesRepo) RevokeRole(_ context.Context, _ int64, _ time.Time) (boo
 }
 
 func TestAccessResolveRole(t *testing.T) {
-	svc := access.NewService(999, nil, &stubRolesRepo{role: enums.RoleModerator})
+	svc := access.NewService(999, nil, &stubRolesRepo{role: enums.RoleModerator}, nil)
 
 	ownerRole, err := svc.ResolveRole(context.Background(), 999)
 	if err != nil {
@@ -74,7 +74,7 @@ func TestAccessResolveRole(t *testing.T) {
 }
 
 func TestAccessResolveRoleRepoError(t *testing.T) {
-	svc := access.NewService(0, nil, &stubRolesRepo{err: errors.New("db error")})
+	svc := access.NewService(0, nil, &stubRolesRepo{err: errors.New("db error")}, nil)
 
 	_, err := svc.ResolveRole(context.Background(), 1000)
 	if err == nil {
@@ -84,7 +84,7 @@ func TestAccessResolveRoleRepoError(t *testing.T) {
 
 func TestAccessTouchUser(t *testing.T) {
 	usersRepo := &stubUsersRepo{}
-	svc := access.NewService(0, usersRepo, nil)
+	svc := access.NewService(0, usersRepo, nil, nil)
 
 	err := svc.TouchUser(context.Background(), model.BotUser{TgID: 1000})
 	if err != nil {
@@ -116,7 +116,7 @@ func TestStartRenderingForAllRoles(t *testing.T) {
 		{
 			name:        "moderator",
 			role:        enums.RoleModerator,
-			mustHave:    []string{"Dating App", "Приступить к модерации"},
+			mustHave:    []string{"Dating App", "Приступить к модерации", "Пакетная модерация"},
 			mustNotHave: []string{"Access", "Find user", "System", "Work Stats"},
 		},
 		{
@@ -125,6 +125,17 @@ func TestStartRenderingForAllRoles(t *testing.T) {
 			mustHave:    []string{"Dating App", "Приступить к модерации", "Access", "Find user"},
 			mustNotHave: []string{"System", "Work Stats"},
 		},
+		{
+			name:        "support",
+			role:        enums.RoleSupport,
+			mustHave:    []string{"Dating App", "Find user", "Work Stats"},
+			mustNotHave: []string{"Приступить к модерации", "Пакетная модерация", "Access", "System", "History"},
+		},
+		{
+			name:        "unknown custom role",
+			role:        enums.Role("GHOST"),
+			expectEmpty: true,
+		},
 		{
 			name:     "owner",
 			role:     enums.RoleOwner,
@@ -132,9 +143,11 @@ func TestStartRenderingForAllRoles(t *testing.T) {
 		},
 	}
 
+	svc := access.NewService(0, nil, nil, nil)
 	for _, tc := range testCases {
 		t.Run(tc.name, func(t *testing.T) {
-			text, menu := ui.RenderStart(tc.role)
+			def, _ := svc.RoleDefinition(context.Background(), tc.role)
+			text, menu := ui.RenderStart(tc.role, def)
 			if text == "" {
 				t.Fatalf("role %s: empty text", tc.role)
 			}
@@ -168,6 +181,90 @@ func TestStartRenderingForAllRoles(t *testing.T) {
 	}
 }
 
+func TestAccessRoleManagementRequiresStrictSubset(t *testing.T) {
+	defs := &stubRoleDefinitionsRepo{items: []model.RoleDefinition{
+		{Name: enums.RoleAdmin, Permissions: []enums.Permission{enums.PermissionModerationDecide, enums.PermissionUsersBan, enums.PermissionAccessManage}, IsSystem: true},
+		{Name: enums.RoleSupport, Permissions: []enums.Permission{enums.PermissionStatsView}, IsSystem: true},
+		{Name: enums.RoleModerator, Permissions: []enums.Permission{enums.PermissionModerationDecide}, IsSystem: true},
+		{Name: "BAN_DESK", Permissions: []enums.Permission{enums.PermissionModerationDecide, enums.PermissionUsersBan}},
+	}}
+	svc := access.NewService(999, nil, nil, defs)
+	ctx := context.Background()
+
+	cases := []struct {
+		actor, target enums.Role
+		allowed       bool
+	}{
+		{enums.RoleOwner, enums.RoleSupport, true},
+		{enums.RoleOwner, enums.RoleOwner, false},
+		{enums.RoleAdmin, enums.RoleModerator, true},
+		{enums.RoleAdmin, "BAN_DESK", true},
+		{enums.RoleAdmin, enums.RoleAdmin, false},
+		{enums.RoleAdmin, enums.RoleSupport, false},
+		{"BAN_DESK", enums.RoleModerator, false},
+		{enums.RoleModerator, enums.RoleModerator, false},
+		{enums.RoleOwner, "GHOST", false},
+	}
+	for _, tc := range cases {
+		if got := svc.CanManageRole(ctx, tc.actor, tc.target); got != tc.allowed {
+			t.Fatalf("%s -> %s: expected %v, got %v", tc.actor, tc.target, tc.allowed, got)
+		}
+	}
+	if !svc.CanRevokeRole(ctx, enums.RoleOwner, "GHOST") {
+		t.Fatal("owner must be able to revoke roles whose definition was removed")
+	}
+}
+
+func TestAccessSaveRoleDefinitionOwnerOnly(t *testing.T) {
+	defs := &stubRoleDefinitionsRepo{}
+	svc := access.NewService(999, nil, nil, defs)
+	ctx := context.Background()
+	def := model.RoleDefinition{Name: "qa_lead", Permissions: []enums.Permission{enums.PermissionModerationQA, enums.PermissionModerationQA}}
+
+	if _, err := svc.SaveRoleDefinition(ctx, 1, enums.RoleAdmin, def); !errors.Is(err, access.ErrAccessDenied) {
+		t.Fatalf("expected access denied for admin, got %v", err)
+	}
+	if _, err := svc.SaveRoleDefinition(ctx, 999, enums.RoleOwner, model.RoleDefinition{Name: "moderator", Permissions: def.Permissions}); !errors.Is(err, access.ErrSystemRole) {
+		t.Fatalf("expected system role error, got %v", err)
+	}
+	if _, err := svc.SaveRoleDefinition(ctx, 999, enums.RoleOwner, model.RoleDefinition{Name: "QA_LEAD"}); !errors.Is(err, access.ErrInvalidRole) {
+		t.Fatalf("expected invalid role without permissions, got %v", err)
+	}
+
+	saved, err := svc.SaveRoleDefinition(ctx, 999, enums.RoleOwner, def)
+	if err != nil {
+		t.Fatalf("save role definition: %v", err)
+	}
+	if saved.Name != "QA_LEAD" || len(saved.Permissions) != 1 || saved.UpdatedByTGID == nil {
+		t.Fatalf("unexpected saved role: %+v", saved)
+	}
+	if !svc.Can(ctx, "QA_LEAD", enums.PermissionModerationQA) || svc.Can(ctx, "QA_LEAD", enums.PermissionModerationDecide) {
+		t.Fatal("saved role permissions are not applied")
+	}
+}
+
+type stubRoleDefinitionsRepo struct {
+	items []model.RoleDefinition
+}
+
+func (s *stubRoleDefinitionsRepo) ListRoleDefinitions(_ context.Context) ([]model.RoleDefinition, error) {
+	return append([]model.RoleDefinition(nil), s.items...), nil
+}
+
+func (s *stubRoleDefinitionsRepo) UpsertRoleDefinition(_ context.Context, def model.RoleDefinition) (model.RoleDefinition, bool, error) {
+	for i, item := range s.items {
+		if item.Name == def.Name {
+			if item.IsSystem {
+				return model.RoleDefinition{}, false, nil
+			}
+			s.items[i] = def
+			return def, true, nil
+		}
+	}
+	s.items = append(s.items, def)
+	return def, true, nil
+}
+
 func menuContains(menu [][]string, expected string) bool {
 	for _, row := range menu {
 		for _, item := range row {